@@ -33,8 +33,15 @@ Corefile: |-
           fallthrough
       }
       prometheus :9153
+      {{- if .Values.networking.advanced.coreDNS.plugins }}
+{{ .Values.networking.advanced.coreDNS.plugins | indent 6 }}
+      {{- end }}
       {{- if .Values.networking.advanced.fallbackHostCluster }}
       forward . {{`{{.HOST_CLUSTER_DNS}}`}}
+      {{- else if .Values.networking.advanced.coreDNS.upstream }}
+      forward . {{ join " " .Values.networking.advanced.coreDNS.upstream }}
+      {{- else if .Values.networking.advanced.coreDNS.nodeLocalDNS.enabled }}
+      forward . {{ .Values.networking.advanced.coreDNS.nodeLocalDNS.ip }}
       {{- else if .Values.policies.networkPolicy.enabled }}
       forward . /etc/resolv.conf {{ .Values.policies.networkPolicy.fallbackDns }} {
           policy sequential
@@ -46,7 +53,50 @@ Corefile: |-
       loop
       loadbalance
   }
+  {{- range .Values.networking.advanced.coreDNS.stubDomains }}
+
+  {{ trimSuffix "." .domain }}:1053 {
+      errors
+      cache 30
+      forward . {{ join " " .resolvers }}
+  }
+  {{- end }}
+  {{- if .Values.networking.advanced.platformDNS.enabled }}
+
+  {{ trimSuffix "." .Values.networking.advanced.platformDNS.domain }}:1053 {
+      errors
+      hosts /etc/coredns/PlatformHosts {
+          ttl 30
+          reload 15s
+      }
+  }
+  {{- end }}
+  {{- if .Values.networking.advanced.coreDNS.serverBlocks }}
+
+{{ .Values.networking.advanced.coreDNS.serverBlocks | indent 2 }}
+  {{- end }}
 
   import /etc/coredns/custom/*.server
   {{- end }}
 {{- end -}}
+
+{{/*
+  Name of the secret and service of the virtual cluster within the shared dns namespace, needs to match the key of the
+  shared dns tenant of the syncer
+*/}}
+{{- define "vcluster.sharedDNS.tenantKey" -}}
+{{- $name := printf "vc-dns-%s-x-%s" .Release.Name .Release.Namespace -}}
+{{- if gt (len $name) 63 -}}
+{{- printf "%s-%s" (substr 0 52 $name) (substr 0 10 (sha256sum $name)) | replace ".-" "-" -}}
+{{- else -}}
+{{- $name -}}
+{{- end -}}
+{{- end -}}
+
+{{/*
+  Name of the registration of the virtual cluster within the platform dns namespace, needs to match the registration
+  name of the syncer, which is built the same way as the shared dns tenant key
+*/}}
+{{- define "vcluster.platformDNS.registrationName" -}}
+{{- include "vcluster.sharedDNS.tenantKey" . -}}
+{{- end -}}
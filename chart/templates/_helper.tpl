@@ -8,10 +8,27 @@
 
 {{- define "vcluster.image" -}}
 {{- if .defaultImageRegistry -}}
-{{ .defaultImageRegistry }}/{{ .repository }}:{{ .tag }}
+{{ .defaultImageRegistry | trimSuffix "/" }}/{{ .repository }}:{{ .tag }}
 {{- else if .registry -}}
 {{ .registry }}/{{ .repository }}:{{ .tag }}
 {{- else -}}
 {{ .repository }}:{{ .tag }}
 {{- end -}}
 {{- end -}}
+
+{{/*
+  the pod security standard can be set to a level or to the pod security admission configuration
+*/}}
+{{- define "vcluster.podSecurityStandard.level" -}}
+{{- if kindIs "map" .Values.policies.podSecurityStandard -}}
+{{ .Values.policies.podSecurityStandard.level }}
+{{- else -}}
+{{ .Values.policies.podSecurityStandard }}
+{{- end -}}
+{{- end -}}
+
+{{- define "vcluster.podSecurityStandard.admission.enabled" -}}
+{{- if and (kindIs "map" .Values.policies.podSecurityStandard) .Values.policies.podSecurityStandard.enabled -}}
+{{- true -}}
+{{- end -}}
+{{- end -}}
@@ -227,11 +227,11 @@
 */}}
 {{- define "vcluster.plugins.initContainers" -}}
 {{- range $key, $container := .Values.plugins }}
-{{- if not $container.image }}
+{{- if or (not $container.image) $container.address }}
 {{- continue }}
 {{- end }}
 - {{- if $.Values.controlPlane.advanced.defaultImageRegistry }}
-  image: {{ $.Values.controlPlane.advanced.defaultImageRegistry }}/{{ $container.image }}
+  image: {{ $.Values.controlPlane.advanced.defaultImageRegistry | trimSuffix "/" }}/{{ $container.image }}
   {{- else }}
   image: {{ $container.image }}
   {{- end }}
@@ -288,7 +288,7 @@
 {{- continue -}}
 {{- end -}}
 - {{- if $.Values.controlPlane.advanced.defaultImageRegistry }}
-  image: {{ $.Values.controlPlane.advanced.defaultImageRegistry }}/{{ $container.image }}
+  image: {{ $.Values.controlPlane.advanced.defaultImageRegistry | trimSuffix "/" }}/{{ $container.image }}
   {{- else }}
   image: {{ $container.image }}
   {{- end }}
@@ -61,7 +61,7 @@
 {{- end }}
 {{- $counter = add1 $counter }}
 - {{- if $.Values.controlPlane.advanced.defaultImageRegistry }}
-  image: {{ $.Values.controlPlane.advanced.defaultImageRegistry }}/{{ $container.image }}
+  image: {{ $.Values.controlPlane.advanced.defaultImageRegistry | trimSuffix "/" }}/{{ $container.image }}
   {{- else }}
   image: {{ $container.image }}
   {{- end }}
@@ -124,3 +124,60 @@
   {{- end }}
   {{- end }}
 {{- end }}
+
+{{/*
+  Sidecar container definition for plugins that run in their own container
+*/}}
+{{- define "vcluster.plugins.containers" -}}
+{{- range $key, $container := .Values.plugins }}
+{{- if or (not $container.image) (not $container.address) }}
+{{- continue }}
+{{- end }}
+- {{- if $.Values.controlPlane.advanced.defaultImageRegistry }}
+  image: {{ $.Values.controlPlane.advanced.defaultImageRegistry | trimSuffix "/" }}/{{ $container.image }}
+  {{- else }}
+  image: {{ $container.image }}
+  {{- end }}
+  {{- if $container.name }}
+  name: {{ $container.name | quote }}
+  {{- else }}
+  name: {{ $key | quote }}
+  {{- end }}
+  {{- if $container.imagePullPolicy }}
+  imagePullPolicy: {{ $container.imagePullPolicy }}
+  {{- end }}
+  {{- if $container.command }}
+  command:
+    {{- range $commandIndex, $command := $container.command }}
+    - {{ $command | quote }}
+    {{- end }}
+  {{- end }}
+  {{- if $container.args }}
+  args:
+    {{- range $argIndex, $arg := $container.args }}
+    - {{ $arg | quote }}
+    {{- end }}
+  {{- end }}
+  env:
+    - name: VCLUSTER_PLUGIN_ADDRESS
+      value: {{ $container.address | quote }}
+    - name: VCLUSTER_PLUGIN_NAME
+      value: {{ $key | quote }}
+    {{- if $container.config }}
+    - name: PLUGIN_CONFIG
+      value: {{ toYaml $container.config | quote }}
+    {{- end }}
+  {{- if $container.securityContext }}
+  securityContext:
+{{ toYaml $container.securityContext | indent 4 }}
+  {{- end }}
+  {{- if $container.volumeMounts }}
+  volumeMounts:
+{{ toYaml $container.volumeMounts | indent 4 }}
+  {{- end }}
+  {{- if $container.resources }}
+  resources:
+{{ toYaml $container.resources | indent 4 }}
+  {{- end }}
+{{- end }}
+{{- end -}}
@@ -27,18 +27,35 @@
     .Values.sync.toHost.volumeSnapshots.enabled
     .Values.controlPlane.advanced.virtualScheduler.enabled
     .Values.sync.fromHost.ingressClasses.enabled
+    .Values.sync.fromHost.runtimeClasses.enabled
     (eq (toString .Values.sync.fromHost.storageClasses.enabled) "true")
     (eq (toString .Values.sync.fromHost.csiNodes.enabled) "true")
     (eq (toString .Values.sync.fromHost.csiDrivers.enabled) "true")
     (eq (toString .Values.sync.fromHost.csiStorageCapacities.enabled) "true")
     .Values.sync.fromHost.nodes.enabled
+    .Values.sync.fromHost.nodes.fakeNodeExtendedResources
+    .Values.integrations.istio.enabled
+    .Values.integrations.certManager.enabled
+    .Values.integrations.externalSecrets.enabled
     .Values.observability.metrics.proxy.nodes
+    (not (empty (include "vcluster.imagePullSecretSources" . )))
     .Values.experimental.multiNamespaceMode.enabled -}}
 {{- true -}}
 {{- end -}}
 {{- end -}}
 {{- end -}}
 
+{{/*
+  Host secrets the image pull secrets of synced pods are copied from
+*/}}
+{{- define "vcluster.imagePullSecretSources" -}}
+{{- range $imagePullSecret := .Values.sync.toHost.pods.imagePullSecrets }}
+{{- if and $imagePullSecret.fromSecret $imagePullSecret.fromSecret.name }}
+- {{ $imagePullSecret.fromSecret.name | quote }}
+{{- end }}
+{{- end }}
+{{- end -}}
+
 {{/*
   Role rules defined on global level
 */}}
@@ -98,6 +98,26 @@ func ExecuteStart(ctx context.Context, options *StartOptions) error {
 		return fmt.Errorf("create controller context: %w", err)
 	}
 
+	// apply config changes at runtime
+	reloader, err := config.NewReloader(vConfig, options.Config, options.SetValues)
+	if err != nil {
+		return fmt.Errorf("create config reloader: %w", err)
+	}
+	reloader.Start(controllerCtx.Context)
+
+	// satellites only run the syncers for their host cluster
+	if vConfig.Experimental.MultiCluster.Satellite.Enabled {
+		err = StartLeaderElection(controllerCtx, func() error {
+			return setup.StartSatellite(controllerCtx)
+		})
+		if err != nil {
+			return fmt.Errorf("start satellite: %w", err)
+		}
+
+		<-controllerCtx.StopChan
+		return nil
+	}
+
 	// start proxy
 	err = setup.StartProxy(controllerCtx)
 	if err != nil {
@@ -127,6 +147,17 @@ func ExecuteStart(ctx context.Context, options *StartOptions) error {
 		return fmt.Errorf("connect to platform: %w", err)
 	}
 
+	// with sharding all replicas sync objects, otherwise only the leader
+	if vConfig.Experimental.SyncSettings.Sharding.Enabled {
+		err = setup.StartShardedControllers(controllerCtx)
+		if err != nil {
+			return fmt.Errorf("start sharded controllers: %w", err)
+		}
+
+		<-controllerCtx.StopChan
+		return nil
+	}
+
 	// start leader election + controllers
 	err = StartLeaderElection(controllerCtx, func() error {
 		return setup.StartControllers(controllerCtx)
@@ -0,0 +1,23 @@
+package certs
+
+import (
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/spf13/cobra"
+)
+
+func NewCertsCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
+	certsCmd := &cobra.Command{
+		Use:   "certs",
+		Short: "Manage the certificates and keys of a virtual cluster",
+		Long: `#######################################################
+#################### vcluster certs ###################
+#######################################################
+Manage the certificates and keys of a virtual cluster
+#######################################################
+	`,
+		Args: cobra.NoArgs,
+	}
+
+	certsCmd.AddCommand(newRotateCmd(globalFlags))
+	return certsCmd
+}
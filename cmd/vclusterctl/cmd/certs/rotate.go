@@ -0,0 +1,60 @@
+package certs
+
+import (
+	"time"
+
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/vcluster/pkg/cli"
+	"github.com/loft-sh/vcluster/pkg/cli/completion"
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/loft-sh/vcluster/pkg/cli/util"
+	"github.com/spf13/cobra"
+)
+
+type rotateCmd struct {
+	*flags.GlobalFlags
+	cli.CertsRotateOptions
+
+	log log.Logger
+}
+
+func newRotateCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
+	cmd := &rotateCmd{
+		GlobalFlags: globalFlags,
+		log:         log.GetInstance(),
+	}
+
+	cobraCmd := &cobra.Command{
+		Use:   "rotate" + util.VClusterNameOnlyUseLine,
+		Short: "Rotates the keys of a virtual cluster",
+		Long: `#######################################################
+################ vcluster certs rotate ################
+#######################################################
+Rotates the service account signing key of a virtual
+cluster with the k8s or eks distro and restarts it. The
+public key of the previous signing key stays published
+in the jwks endpoint of the virtual cluster, so tokens
+signed before the rotation stay valid until the next
+rotation.
+
+Use --interval to keep rotating the keys, e.g. in a long
+running pod. Make sure the interval is longer than the
+lifetime of the issued tokens.
+
+Example:
+vcluster certs rotate my-vcluster --namespace vcluster-my-vcluster --sa-keys
+vcluster certs rotate my-vcluster --namespace vcluster-my-vcluster --sa-keys --interval 2160h
+#######################################################
+	`,
+		Args:              util.VClusterNameOnlyValidator,
+		ValidArgsFunction: completion.NewValidVClusterNameFunc(globalFlags),
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			return cli.CertsRotate(cobraCmd.Context(), &cmd.CertsRotateOptions, cmd.GlobalFlags, args[0], cmd.log)
+		},
+	}
+
+	cobraCmd.Flags().BoolVar(&cmd.SAKeys, "sa-keys", false, "Rotate the service account signing keys")
+	cobraCmd.Flags().DurationVar(&cmd.Interval, "interval", 0, "If set, rotates the keys in this interval until the command is stopped")
+	cobraCmd.Flags().DurationVar(&cmd.Timeout, "timeout", 5*time.Minute, "The time to wait for the virtual cluster to become ready with the new keys")
+	return cobraCmd
+}
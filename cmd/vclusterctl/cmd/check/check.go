@@ -0,0 +1,24 @@
+package check
+
+import (
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/spf13/cobra"
+)
+
+func NewCheckCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
+	checkCmd := &cobra.Command{
+		Use:   "check",
+		Short: "Runtime checks against a virtual cluster",
+		Long: `#######################################################
+################### vcluster check ####################
+#######################################################
+Runs checks within the current virtual cluster to verify
+that the host cluster supports its features.
+#######################################################
+	`,
+		Args: cobra.NoArgs,
+	}
+
+	checkCmd.AddCommand(netpol(globalFlags))
+	return checkCmd
+}
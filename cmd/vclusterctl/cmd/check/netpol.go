@@ -0,0 +1,55 @@
+package check
+
+import (
+	"time"
+
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/vcluster/pkg/cli"
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/spf13/cobra"
+)
+
+type NetpolCmd struct {
+	*flags.GlobalFlags
+	cli.CheckNetworkPolicyOptions
+
+	log log.Logger
+}
+
+func netpol(globalFlags *flags.GlobalFlags) *cobra.Command {
+	cmd := &NetpolCmd{
+		GlobalFlags: globalFlags,
+		log:         log.GetInstance(),
+	}
+
+	cobraCmd := &cobra.Command{
+		Use:   "netpol",
+		Short: "Checks that network policies of the virtual cluster are enforced",
+		Long: `#######################################################
+################ vcluster check netpol ################
+#######################################################
+Creates a server and a client pod in two temporary
+namespaces of the current virtual cluster and checks
+that ingress and egress network policies, including
+namespace selectors, are enforced by the CNI of the host
+cluster. The namespaces are deleted afterwards.
+
+Requires sync.toHost.networkPolicies.enabled.
+
+Example:
+vcluster connect my-vcluster -n my-namespace -- vcluster check netpol
+vcluster check netpol --output json
+#######################################################
+	`,
+		Args: cobra.NoArgs,
+		RunE: func(cobraCmd *cobra.Command, _ []string) error {
+			return cli.CheckNetworkPolicies(cobraCmd.Context(), &cmd.CheckNetworkPolicyOptions, cmd.GlobalFlags, cmd.log)
+		},
+	}
+
+	cobraCmd.Flags().StringVar(&cmd.Output, "output", "table", "Choose the format of the output. [table|json]")
+	cobraCmd.Flags().DurationVar(&cmd.Timeout, "timeout", time.Minute, "How long to wait for a network policy to take effect")
+	cobraCmd.Flags().StringVar(&cmd.ServerImage, "server-image", "nginxinc/nginx-unprivileged", "The image of the server pod, needs to serve http on port 8080")
+	cobraCmd.Flags().StringVar(&cmd.ClientImage, "client-image", "busybox", "The image of the client pods, needs to provide wget")
+	return cobraCmd
+}
@@ -0,0 +1,27 @@
+package config
+
+import (
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/spf13/cobra"
+)
+
+func NewConfigCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
+	configCmd := &cobra.Command{
+		Use:   "config",
+		Short: "Virtual cluster config subcommands",
+		Long: `#######################################################
+################### vcluster config ###################
+#######################################################
+Helps working with the vcluster.yaml config of virtual
+clusters.
+#######################################################
+	`,
+		Args: cobra.NoArgs,
+	}
+
+	configCmd.AddCommand(schema(globalFlags))
+	configCmd.AddCommand(explain(globalFlags))
+	configCmd.AddCommand(history(globalFlags))
+	configCmd.AddCommand(rollback(globalFlags))
+	return configCmd
+}
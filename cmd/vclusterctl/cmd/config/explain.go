@@ -0,0 +1,106 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/loft-sh/log"
+	vclusterconfig "github.com/loft-sh/vcluster/config"
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+)
+
+type ExplainCmd struct {
+	*flags.GlobalFlags
+	vclusterconfig.SchemaOptions
+
+	log log.Logger
+}
+
+func explain(globalFlags *flags.GlobalFlags) *cobra.Command {
+	cmd := &ExplainCmd{
+		GlobalFlags: globalFlags,
+		log:         log.GetInstance(),
+	}
+
+	cobraCmd := &cobra.Command{
+		Use:   "explain [path]",
+		Short: "Describes a field of the vcluster.yaml",
+		Long: `#######################################################
+############### vcluster config explain ###############
+#######################################################
+Prints the description, type, default value and
+constraints of a vcluster.yaml field as well as its
+direct child fields.
+
+Example:
+vcluster config explain sync.toHost.pods
+vcluster config explain controlPlane.distro.k3s --distro k3s
+#######################################################
+	`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			path := ""
+			if len(args) > 0 {
+				path = args[0]
+			}
+
+			return cmd.Run(path)
+		},
+	}
+
+	cobraCmd.Flags().StringVar(&cmd.Distro, "distro", "", fmt.Sprintf("Show the defaults of the given distro. Allowed distros: %s", strings.Join([]string{"k8s", "k3s", "k0s", "eks"}, ", ")))
+	cobraCmd.Flags().StringVar(&cmd.KubernetesVersion, "k8s-version", "", "Show the default images of the given Kubernetes version, e.g. v1.29")
+	return cobraCmd
+}
+
+func (cmd *ExplainCmd) Run(path string) error {
+	explanation, err := vclusterconfig.Explain(strings.Trim(path, "."), &cmd.SchemaOptions)
+	if err != nil {
+		return err
+	}
+
+	out := &strings.Builder{}
+	fmt.Fprintf(out, "PATH:    %s\n", explanation.Path)
+	fmt.Fprintf(out, "TYPE:    %s\n", explanation.Type)
+	if explanation.Description != "" {
+		fmt.Fprintf(out, "\nDESCRIPTION:\n%s\n", indent(explanation.Description, "    "))
+	}
+	if explanation.Default != nil {
+		defaultValue, err := yaml.Marshal(explanation.Default)
+		if err != nil {
+			return fmt.Errorf("marshal default value: %w", err)
+		}
+
+		fmt.Fprintf(out, "\nDEFAULT:\n%s\n", indent(strings.TrimSpace(string(defaultValue)), "    "))
+	}
+	if len(explanation.Constraints) > 0 {
+		fmt.Fprintf(out, "\nCONSTRAINTS:\n")
+		for _, constraint := range explanation.Constraints {
+			fmt.Fprintf(out, "    - %s\n", constraint)
+		}
+	}
+	if len(explanation.Fields) > 0 {
+		fmt.Fprintf(out, "\nFIELDS:\n")
+		for _, field := range explanation.Fields {
+			fmt.Fprintf(out, "    %s <%s>\n", field.Name, field.Type)
+			if field.Description != "" {
+				fmt.Fprintf(out, "%s\n\n", indent(field.Description, "        "))
+			}
+		}
+	}
+
+	cmd.log.WriteString(logrus.InfoLevel, strings.TrimRight(out.String(), "\n")+"\n")
+	return nil
+}
+
+func indent(text, prefix string) string {
+	lines := strings.Split(text, "\n")
+	for i := range lines {
+		lines[i] = prefix + lines[i]
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,49 @@
+package config
+
+import (
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/vcluster/pkg/cli"
+	"github.com/loft-sh/vcluster/pkg/cli/completion"
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/loft-sh/vcluster/pkg/cli/util"
+	"github.com/spf13/cobra"
+)
+
+type HistoryCmd struct {
+	*flags.GlobalFlags
+	cli.ConfigHistoryOptions
+
+	log log.Logger
+}
+
+func history(globalFlags *flags.GlobalFlags) *cobra.Command {
+	cmd := &HistoryCmd{
+		GlobalFlags: globalFlags,
+		log:         log.GetInstance(),
+	}
+
+	cobraCmd := &cobra.Command{
+		Use:   "history" + util.VClusterNameOnlyUseLine,
+		Short: "Lists the config revisions of a virtual cluster",
+		Long: `#######################################################
+############### vcluster config history ###############
+#######################################################
+Lists the revisions of the helm release of the virtual
+cluster with the time they were deployed and the
+checksum of their values. Helm keeps the last 10
+revisions by default.
+
+Example:
+vcluster config history my-vcluster -n my-namespace
+#######################################################
+	`,
+		Args:              util.VClusterNameOnlyValidator,
+		ValidArgsFunction: completion.NewValidVClusterNameFunc(globalFlags),
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			return cli.ConfigHistory(cobraCmd.Context(), cmd.GlobalFlags, args[0], &cmd.ConfigHistoryOptions, cmd.log)
+		},
+	}
+
+	cobraCmd.Flags().StringVarP(&cmd.Output, "output", "o", "table", "Choose the format of the output. [table|json]")
+	return cobraCmd
+}
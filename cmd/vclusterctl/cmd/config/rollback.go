@@ -0,0 +1,53 @@
+package config
+
+import (
+	"time"
+
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/vcluster/pkg/cli"
+	"github.com/loft-sh/vcluster/pkg/cli/completion"
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/loft-sh/vcluster/pkg/cli/util"
+	"github.com/loft-sh/vcluster/pkg/constants"
+	"github.com/spf13/cobra"
+)
+
+type RollbackCmd struct {
+	*flags.GlobalFlags
+	cli.ConfigRollbackOptions
+
+	log log.Logger
+}
+
+func rollback(globalFlags *flags.GlobalFlags) *cobra.Command {
+	cmd := &RollbackCmd{
+		GlobalFlags: globalFlags,
+		log:         log.GetInstance(),
+	}
+
+	cobraCmd := &cobra.Command{
+		Use:   "rollback" + util.VClusterNameOnlyUseLine,
+		Short: "Rolls back the config of a virtual cluster",
+		Long: `#######################################################
+############### vcluster config rollback ##############
+#######################################################
+Upgrades the virtual cluster with the values of a
+previous revision listed by 'vcluster config history'.
+The chart version of the virtual cluster is kept.
+
+Example:
+vcluster config rollback my-vcluster -n my-namespace --revision 3
+#######################################################
+	`,
+		Args:              util.VClusterNameOnlyValidator,
+		ValidArgsFunction: completion.NewValidVClusterNameFunc(globalFlags),
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			return cli.ConfigRollback(cobraCmd.Context(), cmd.GlobalFlags, args[0], &cmd.ConfigRollbackOptions, cmd.log)
+		},
+	}
+
+	cobraCmd.Flags().IntVar(&cmd.Revision, "revision", 0, "The revision whose values should be deployed again")
+	cobraCmd.Flags().StringVar(&cmd.ChartRepo, "chart-repo", constants.LoftChartRepo, "The virtual cluster chart repo the virtual cluster was deployed from")
+	cobraCmd.Flags().DurationVar(&cmd.RollbackTimeout, "rollback-timeout", 5*time.Minute, "Roll back to the current revision if the virtual cluster does not become ready within this duration. 0 disables the rollback")
+	return cobraCmd
+}
@@ -0,0 +1,62 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/loft-sh/log"
+	vclusterconfig "github.com/loft-sh/vcluster/config"
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+type SchemaCmd struct {
+	*flags.GlobalFlags
+	vclusterconfig.SchemaOptions
+
+	log log.Logger
+}
+
+func schema(globalFlags *flags.GlobalFlags) *cobra.Command {
+	cmd := &SchemaCmd{
+		GlobalFlags: globalFlags,
+		log:         log.GetInstance(),
+	}
+
+	cobraCmd := &cobra.Command{
+		Use:   "schema",
+		Short: "Prints the JSON schema of the vcluster.yaml",
+		Long: `#######################################################
+################ vcluster config schema ###############
+#######################################################
+Prints the JSON schema of the vcluster.yaml including
+the descriptions and default values of this vCluster
+version. Editors and CI validators can use the schema
+to validate the vcluster.yaml.
+
+Example:
+vcluster config schema > vcluster.schema.json
+vcluster config schema --distro k3s --k8s-version v1.29
+#######################################################
+	`,
+		Args: cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return cmd.Run()
+		},
+	}
+
+	cobraCmd.Flags().StringVar(&cmd.Distro, "distro", "", fmt.Sprintf("Only include the config of the given distro. Allowed distros: %s", strings.Join([]string{"k8s", "k3s", "k0s", "eks"}, ", ")))
+	cobraCmd.Flags().StringVar(&cmd.KubernetesVersion, "k8s-version", "", "Use the default images of the given Kubernetes version, e.g. v1.29")
+	return cobraCmd
+}
+
+func (cmd *SchemaCmd) Run() error {
+	out, err := vclusterconfig.NewSchema(&cmd.SchemaOptions)
+	if err != nil {
+		return fmt.Errorf("generate schema: %w", err)
+	}
+
+	cmd.log.WriteString(logrus.InfoLevel, string(out)+"\n")
+	return nil
+}
@@ -45,6 +45,8 @@ vcluster connect test --namespace test
 # Open a new bash with the vcluster KUBECONFIG defined
 vcluster connect test -n test -- bash
 vcluster connect test -n test -- kubectl get ns
+# Connect through the ingress or load balancer of the vcluster
+vcluster connect test -n test --via-gateway
 #######################################################
 	`,
 		Args:              nameValidator,
@@ -1,14 +1,18 @@
 package convert
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/loft-sh/log"
-	"github.com/loft-sh/vcluster/config/legacyconfig"
+	"github.com/loft-sh/vcluster/pkg/cli"
 	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/loft-sh/vcluster/pkg/constants"
+	"github.com/loft-sh/vcluster/pkg/upgrade"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"k8s.io/apimachinery/pkg/util/yaml"
@@ -16,16 +20,30 @@ import (
 
 type configCmd struct {
 	*flags.GlobalFlags
-	log      log.Logger
-	distro   string
-	filePath string
-	format   string
+	log        log.Logger
+	distro     string
+	filePath   string
+	format     string
+	setValues  []string
+	release    string
+	outputFile string
+	apply      bool
+
+	// createOptions configure the upgrade of --apply
+	createOptions cli.CreateOptions
 }
 
 func convertValues(globalFlags *flags.GlobalFlags) *cobra.Command {
 	c := &configCmd{
 		GlobalFlags: globalFlags,
 		log:         log.GetInstance(),
+		createOptions: cli.CreateOptions{
+			ChartName:       "vcluster",
+			CreateNamespace: true,
+			ExposeLocal:     true,
+			Add:             true,
+			Output:          "text",
+		},
 	}
 
 	cobraCmd := &cobra.Command{
@@ -36,49 +54,92 @@ func convertValues(globalFlags *flags.GlobalFlags) *cobra.Command {
 ##############################################################
 Converts virtual cluster config config to the v0.20 format
 
+The values can also be read directly from a deployed
+pre-v0.20 virtual cluster via --release, in which case
+the distro is inferred from the chart. With --apply the
+virtual cluster is upgraded with the converted config.
+
 Examples:
 vcluster convert config --distro k8s -f /my/k8s/values.yaml
 vcluster convert config --distro k3s < /my/k3s/values.yaml
 cat /my/k0s/values.yaml | vcluster convert config --distro k0s
+vcluster convert config --distro k3s -f values.yaml --set syncer.replicas=2
+vcluster convert config --release my-vcluster -n my-namespace --output-file vcluster.yaml
+vcluster convert config --release my-vcluster -n my-namespace --apply
+vcluster convert config --release my-vcluster -n my-namespace --apply --chart-repo https://charts.example.com/vcluster --values overrides.yaml
 ##############################################################
 	`,
-		RunE: func(_ *cobra.Command, _ []string) error {
-			return c.Run()
+		RunE: func(cobraCmd *cobra.Command, _ []string) error {
+			return c.Run(cobraCmd.Context())
 		}}
 
 	cobraCmd.Flags().StringVarP(&c.filePath, "file", "f", "", "Path to the input file")
 	cobraCmd.Flags().StringVar(&c.distro, "distro", "", fmt.Sprintf("Kubernetes distro of the config. Allowed distros: %s", strings.Join([]string{"k8s", "k3s", "k0s", "eks"}, ", ")))
 	cobraCmd.Flags().StringVarP(&c.format, "output", "o", "yaml", "Prints the output in the specified format. Allowed values: yaml, json")
+	cobraCmd.Flags().StringArrayVar(&c.setValues, "set", []string{}, "Set legacy values before converting them. E.g. --set 'syncer.replicas=2'")
+	cobraCmd.Flags().StringVar(&c.release, "release", "", "Name of a deployed pre-v0.20 virtual cluster to read the values from")
+	cobraCmd.Flags().StringVar(&c.outputFile, "output-file", "", "Path to write the converted config to instead of printing it")
+	cobraCmd.Flags().BoolVar(&c.apply, "apply", false, "Upgrade the virtual cluster given via --release with the converted config")
+	cobraCmd.Flags().StringArrayVar(&c.createOptions.Values, "values", []string{}, "Path where to load extra helm values from for --apply, they take precedence over the converted config")
+	cobraCmd.Flags().StringVar(&c.createOptions.ChartVersion, "chart-version", upgrade.GetVersion(), "The virtual cluster chart version to upgrade to with --apply")
+	cobraCmd.Flags().StringVar(&c.createOptions.ChartRepo, "chart-repo", constants.LoftChartRepo, "The virtual cluster chart repo to upgrade from with --apply, either a helm repository url or an oci:// registry")
+	cobraCmd.Flags().StringVar(&c.createOptions.ChartRepoUsername, "repo-username", "", "The username to authenticate against the --chart-repo")
+	cobraCmd.Flags().StringVar(&c.createOptions.ChartRepoPassword, "repo-password", "", "The password to authenticate against the --chart-repo")
+	cobraCmd.Flags().StringVar(&c.createOptions.ChartRepoCAFile, "repo-ca-file", "", "Verify the certificate of the --chart-repo with this certificate authority bundle")
+	cobraCmd.Flags().DurationVar(&c.createOptions.RollbackTimeout, "rollback-timeout", 5*time.Minute, "If the virtual cluster is not ready within this duration after --apply, the release and its config are rolled back to the previous revision. Set to 0 to disable the automatic rollback")
+	cobraCmd.Flags().BoolVar(&c.createOptions.Connect, "connect", false, "If true will run vcluster connect directly after --apply upgraded the virtual cluster")
 
 	return cobraCmd
 }
 
-func (cmd *configCmd) Run() error {
-	var (
-		convertedConfig string
-		err             error
-	)
-
-	if cmd.distro == "" {
-		return fmt.Errorf("no distro given: please set \"--distro\" (IMPORTANT: distro must match the given config values)")
+func (cmd *configCmd) Run(ctx context.Context) error {
+	if cmd.apply && cmd.release == "" {
+		return fmt.Errorf("--apply requires --release")
+	} else if cmd.release != "" && cmd.filePath != "" {
+		return fmt.Errorf("please specify either --release or --file")
 	}
 
-	if cmd.filePath != "" {
-		file, err := os.Open(cmd.filePath)
+	legacyValues := ""
+	if cmd.release != "" {
+		values, distro, err := cli.LegacyReleaseValues(ctx, cmd.GlobalFlags, cmd.release, cmd.log)
 		if err != nil {
 			return err
 		}
-		convertedConfig, err = convert(cmd.distro, file)
-		if err != nil {
-			return fmt.Errorf("unable to convert config values: %w", err)
+		if cmd.distro != "" && cmd.distro != distro {
+			cmd.log.Warnf("Using distro %s although vcluster %s was deployed with the %s chart", cmd.distro, cmd.release, distro)
+		} else {
+			cmd.distro = distro
 		}
-		defer file.Close()
+
+		legacyValues = values
 	} else {
+		if cmd.distro == "" {
+			return fmt.Errorf("no distro given: please set \"--distro\" (IMPORTANT: distro must match the given config values)")
+		}
+
 		// If no files provided, read from stdin
-		convertedConfig, err = convert(cmd.distro, os.Stdin)
+		var in io.Reader = os.Stdin
+		if cmd.filePath != "" {
+			file, err := os.Open(cmd.filePath)
+			if err != nil {
+				return err
+			}
+			defer file.Close()
+
+			in = file
+		}
+
+		content, err := io.ReadAll(in)
 		if err != nil {
-			return fmt.Errorf("unable to convert config values: %w", err)
+			return err
 		}
+
+		legacyValues = string(content)
+	}
+
+	convertedConfig, err := cli.ConvertConfig(cmd.distro, legacyValues, cmd.setValues)
+	if err != nil {
+		return fmt.Errorf("unable to convert config values: %w", err)
 	}
 
 	var out string
@@ -96,16 +157,20 @@ func (cmd *configCmd) Run() error {
 		out = convertedConfig
 	}
 
-	cmd.log.WriteString(logrus.InfoLevel, out)
+	if cmd.outputFile != "" {
+		err = os.WriteFile(cmd.outputFile, []byte(out), 0600)
+		if err != nil {
+			return fmt.Errorf("write converted config: %w", err)
+		}
 
-	return nil
-}
+		cmd.log.Donef("Wrote converted config to %s", cmd.outputFile)
+	} else if !cmd.apply {
+		cmd.log.WriteString(logrus.InfoLevel, out)
+	}
 
-func convert(distro string, r io.Reader) (string, error) {
-	content, err := io.ReadAll(r)
-	if err != nil {
-		return "", err
+	if cmd.apply {
+		return cli.ApplyConvertedConfig(ctx, cmd.GlobalFlags, cmd.release, convertedConfig, &cmd.createOptions, cmd.log)
 	}
 
-	return legacyconfig.MigrateLegacyConfig(distro, string(content))
+	return nil
 }
@@ -41,6 +41,34 @@ Creates a new virtual cluster
 
 Example:
 vcluster create test --namespace test
+# Deploy only the workload resources of an isolated control plane
+vcluster create test --namespace test --headless
+# Register the virtual cluster in the Argo CD instance of the argocd namespace
+vcluster create test --namespace test --register-argocd argocd
+# Install flux within the virtual cluster and sync it with a git repository
+vcluster create test --namespace test --flux-bootstrap https://github.com/my-org/fleet --flux-path ./clusters/test
+# Start from the curated values for a highly available virtual cluster
+vcluster create test --namespace test --preset ha -f vcluster.yaml
+# Reuse the same vcluster.yaml across environments, e.g. with host: ${CLUSTER_DOMAIN} in the values
+CLUSTER_DOMAIN=dev.example.com vcluster create test --namespace test -f vcluster.yaml --expand-env
+# Read the values from stdin
+cat vcluster.yaml | vcluster create test --namespace test -f -
+# Download the values and verify their checksum
+vcluster create test --namespace test -f "https://example.com/vcluster.yaml?checksum=sha256:<hex>"
+# Pull the values from an OCI registry, e.g. pushed via oras push ghcr.io/my-org/values:v1 vcluster.yaml
+vcluster create test --namespace test -f oci://ghcr.io/my-org/values:v1
+# Validate the values against the policy of the platform team
+vcluster create test --namespace test -f vcluster.yaml --policy configmap://platform/vcluster-policy
+# Pull all vCluster images from a registry mirror in an air-gapped environment
+vcluster create test --namespace test --image-registry registry.example.com/mirror
+# Deploy the chart from an authenticated mirror of the chart repository
+vcluster create test --namespace test --chart-repo https://charts.example.com/vcluster --repo-username ci --repo-password "$REPO_TOKEN" --repo-ca-file ca.crt
+# Deploy the chart from an OCI registry with the credentials of docker login
+vcluster create test --namespace test --chart-repo oci://ghcr.io/my-org/charts --chart-version 0.20.0
+# Print a machine-readable summary of the deployment
+vcluster create test --namespace test --upgrade --connect=false --output json
+# Print the rendered template of the platform without creating the virtual cluster
+vcluster create test --driver platform --template my-template --set-param replicas=3 --preview
 #######################################################
 	`,
 		Args: util.VClusterNameOnlyValidator,
@@ -0,0 +1,23 @@
+package debug
+
+import (
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/spf13/cobra"
+)
+
+func NewDebugCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
+	debugCmd := &cobra.Command{
+		Use:   "debug",
+		Short: "Debug helpers for virtual clusters",
+		Long: `#######################################################
+#################### vcluster debug ###################
+#######################################################
+Helpers to find and resolve problems of virtual clusters.
+#######################################################
+	`,
+		Args: cobra.NoArgs,
+	}
+
+	debugCmd.AddCommand(stuckDeletions(globalFlags))
+	return debugCmd
+}
@@ -0,0 +1,57 @@
+package debug
+
+import (
+	"time"
+
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/vcluster/pkg/cli"
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/spf13/cobra"
+)
+
+type StuckDeletionsCmd struct {
+	*flags.GlobalFlags
+	cli.StuckDeletionsOptions
+
+	log log.Logger
+}
+
+func stuckDeletions(globalFlags *flags.GlobalFlags) *cobra.Command {
+	cmd := &StuckDeletionsCmd{
+		GlobalFlags: globalFlags,
+		log:         log.GetInstance(),
+	}
+
+	cobraCmd := &cobra.Command{
+		Use:   "stuck-deletions",
+		Short: "Lists objects that are stuck in deletion",
+		Long: `#######################################################
+########### vcluster debug stuck-deletions ############
+#######################################################
+Lists the objects of the current cluster that are being
+deleted for longer than --min-age and still have
+finalizers. Run it against the virtual cluster to find
+virtual objects waiting for their host object or against
+the host cluster with -n to find imported host objects
+waiting for their virtual object.
+
+With --force the finalizers vcluster.loft.sh/host-cleanup
+and vcluster.loft.sh/virtual-cleanup are removed from
+these objects, other finalizers are kept.
+
+Example:
+vcluster connect my-vcluster -n my-namespace -- vcluster debug stuck-deletions
+vcluster debug stuck-deletions -n my-namespace --min-age 10m --force
+#######################################################
+	`,
+		Args: cobra.NoArgs,
+		RunE: func(cobraCmd *cobra.Command, _ []string) error {
+			return cli.StuckDeletions(cobraCmd.Context(), &cmd.StuckDeletionsOptions, cmd.GlobalFlags, cmd.log)
+		},
+	}
+
+	cobraCmd.Flags().StringVar(&cmd.Output, "output", "table", "Choose the format of the output. [table|json]")
+	cobraCmd.Flags().DurationVar(&cmd.MinAge, "min-age", 5*time.Minute, "How long an object has to be deleting to be listed")
+	cobraCmd.Flags().BoolVar(&cmd.Force, "force", false, "Remove the vCluster finalizers of the listed objects")
+	return cobraCmd
+}
@@ -41,6 +41,8 @@ Deletes a virtual cluster
 
 Example:
 vcluster delete test --namespace test
+# delete all host objects of the vcluster even if the helm release is gone
+vcluster delete test --namespace test --force-cleanup
 #######################################################
 	`,
 		Args:              util.VClusterNameOnlyValidator,
@@ -0,0 +1,25 @@
+package density
+
+import (
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/spf13/cobra"
+)
+
+func NewDensityCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
+	densityCmd := &cobra.Command{
+		Use:   "density",
+		Short: "Virtual cluster density subcommands",
+		Long: `#######################################################
+################## vcluster density ###################
+#######################################################
+Helps running many virtual clusters on the same host cluster.
+Use controlPlane.advanced.density.enabled to tune the
+virtual cluster control plane for dense hosts.
+#######################################################
+	`,
+		Args: cobra.NoArgs,
+	}
+
+	densityCmd.AddCommand(report(globalFlags))
+	return densityCmd
+}
@@ -0,0 +1,47 @@
+package density
+
+import (
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/vcluster/pkg/cli"
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/spf13/cobra"
+)
+
+type ReportCmd struct {
+	*flags.GlobalFlags
+	cli.DensityReportOptions
+
+	log log.Logger
+}
+
+func report(globalFlags *flags.GlobalFlags) *cobra.Command {
+	cmd := &ReportCmd{
+		GlobalFlags: globalFlags,
+		log:         log.GetInstance(),
+	}
+
+	cobraCmd := &cobra.Command{
+		Use:   "report",
+		Short: "Estimates how many more virtual clusters fit into the host cluster",
+		Long: `#######################################################
+############### vcluster density report ###############
+#######################################################
+Estimates how many more virtual clusters fit into the
+current host cluster. The estimate is based on the
+average resource requests of the existing virtual
+clusters and the free allocatable capacity of the nodes.
+
+Example:
+vcluster density report
+vcluster density report --output json
+#######################################################
+	`,
+		Args: cobra.NoArgs,
+		RunE: func(cobraCmd *cobra.Command, _ []string) error {
+			return cli.DensityReportHelm(cobraCmd.Context(), &cmd.DensityReportOptions, cmd.GlobalFlags, cmd.log)
+		},
+	}
+
+	cobraCmd.Flags().StringVar(&cmd.Output, "output", "table", "Choose the format of the output. [table|json]")
+	return cobraCmd
+}
@@ -0,0 +1,23 @@
+package dev
+
+import (
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/spf13/cobra"
+)
+
+func NewDevCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
+	devCmd := &cobra.Command{
+		Use:   "dev",
+		Short: "Tools for developing vcluster",
+		Long: `#######################################################
+##################### vcluster dev ####################
+#######################################################
+Tools for developing and testing vcluster locally
+#######################################################
+	`,
+		Args: cobra.NoArgs,
+	}
+
+	devCmd.AddCommand(newPlatformMockCmd(globalFlags))
+	return devCmd
+}
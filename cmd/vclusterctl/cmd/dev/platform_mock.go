@@ -0,0 +1,53 @@
+package dev
+
+import (
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/loft-sh/vcluster/pkg/platform/mock"
+	"github.com/spf13/cobra"
+)
+
+type PlatformMockCmd struct {
+	*flags.GlobalFlags
+	mock.Options
+
+	log log.Logger
+}
+
+func newPlatformMockCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
+	cmd := &PlatformMockCmd{
+		GlobalFlags: globalFlags,
+		log:         log.GetInstance(),
+	}
+
+	cobraCmd := &cobra.Command{
+		Use:   "platform-mock",
+		Short: "Serves an in-memory fake of the platform api",
+		Long: `#######################################################
+############## vcluster dev platform-mock #############
+#######################################################
+Serves an in-memory fake of the platform management api
+with a self-signed certificate. It supports projects,
+virtual cluster instances and access keys, so the
+platform driver of the cli can be tried without a
+platform license. All data is lost on exit.
+
+Example:
+vcluster dev platform-mock --access-key my-key
+vcluster login https://localhost:8443 --access-key my-key --insecure
+vcluster list --driver platform
+#######################################################
+	`,
+		Args: cobra.NoArgs,
+		RunE: func(cobraCmd *cobra.Command, _ []string) error {
+			return mock.Start(cobraCmd.Context(), &cmd.Options, cmd.log)
+		},
+	}
+
+	cobraCmd.Flags().StringVar(&cmd.Address, "address", "localhost:8443", "The address the mock platform binds to")
+	cobraCmd.Flags().StringVar(&cmd.AccessKey, "access-key", "", "The access key clients need to log in with")
+	cobraCmd.Flags().StringVar(&cmd.Project, "project", "default", "The project that is created on start")
+	cobraCmd.Flags().StringVar(&cmd.Cluster, "cluster", "loft-cluster", "The cluster the project is allowed to use")
+	_ = cobraCmd.MarkFlagRequired("access-key")
+	return cobraCmd
+}
@@ -0,0 +1,29 @@
+package failover
+
+import (
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/spf13/cobra"
+)
+
+func NewFailoverCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
+	failoverCmd := &cobra.Command{
+		Use:   "failover",
+		Short: "Manage standby virtual clusters in other clusters",
+		Long: `#######################################################
+################## vcluster failover ##################
+#######################################################
+Deploy a cold standby of a virtual cluster into another
+cluster and activate it if the source cluster fails.
+
+The standby shares the data of the source only if the
+virtual cluster uses an external database as backing
+store that is reachable from both clusters.
+#######################################################
+	`,
+		Args: cobra.NoArgs,
+	}
+
+	failoverCmd.AddCommand(newStandbyCmd(globalFlags))
+	failoverCmd.AddCommand(newPromoteCmd(globalFlags))
+	return failoverCmd
+}
@@ -0,0 +1,53 @@
+package failover
+
+import (
+	"time"
+
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/vcluster/pkg/cli"
+	"github.com/loft-sh/vcluster/pkg/cli/completion"
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/loft-sh/vcluster/pkg/cli/util"
+	"github.com/spf13/cobra"
+)
+
+type promoteCmd struct {
+	*flags.GlobalFlags
+	cli.FailoverPromoteOptions
+
+	log log.Logger
+}
+
+func newPromoteCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
+	cmd := &promoteCmd{
+		GlobalFlags: globalFlags,
+		log:         log.GetInstance(),
+	}
+
+	cobraCmd := &cobra.Command{
+		Use:   "promote" + util.VClusterNameOnlyUseLine,
+		Short: "Activates a standby virtual cluster",
+		Long: `#######################################################
+############## vcluster failover promote ##############
+#######################################################
+Scales a standby created by 'vcluster failover standby'
+up to the replicas of its source virtual cluster and
+restores the last replicated snapshot of the source.
+Make sure the source virtual cluster is no longer
+running.
+
+Example:
+vcluster failover promote my-vcluster -n my-namespace --context eu-west
+#######################################################
+	`,
+		Args:              util.VClusterNameOnlyValidator,
+		ValidArgsFunction: completion.NewValidVClusterNameFunc(globalFlags),
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			return cli.FailoverPromote(cobraCmd.Context(), cmd.GlobalFlags, args[0], &cmd.FailoverPromoteOptions, cmd.log)
+		},
+	}
+
+	cobraCmd.Flags().StringVar(&cmd.ChartRepo, "chart-repo", "", "The virtual cluster chart repo, defaults to the repo the standby was deployed from")
+	cobraCmd.Flags().DurationVar(&cmd.RollbackTimeout, "rollback-timeout", 5*time.Minute, "Roll back to the standby if the promoted virtual cluster does not become ready within this duration. 0 disables the rollback")
+	return cobraCmd
+}
@@ -0,0 +1,63 @@
+package failover
+
+import (
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/vcluster/pkg/cli"
+	"github.com/loft-sh/vcluster/pkg/cli/completion"
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/loft-sh/vcluster/pkg/cli/util"
+	"github.com/loft-sh/vcluster/pkg/constants"
+	"github.com/spf13/cobra"
+)
+
+type standbyCmd struct {
+	*flags.GlobalFlags
+	cli.FailoverStandbyOptions
+
+	log log.Logger
+}
+
+func newStandbyCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
+	cmd := &standbyCmd{
+		GlobalFlags: globalFlags,
+		log:         log.GetInstance(),
+	}
+
+	cobraCmd := &cobra.Command{
+		Use:   "standby" + util.VClusterNameOnlyUseLine,
+		Short: "Deploys a standby of a virtual cluster into another cluster",
+		Long: `#######################################################
+############## vcluster failover standby ##############
+#######################################################
+Deploys the virtual cluster with the same chart version
+and config into the cluster of --target-context, scaled
+to zero. Run it again, e.g. from a cron job, to keep the
+standby in sync with config changes of the source.
+
+Unless the virtual cluster uses an external database
+that is shared with the standby, a snapshot of its
+objects is replicated to the standby and restored on
+promote. Use --snapshot-interval to keep replicating
+snapshots. Objects managed by controllers are recreated
+on promote, the data of persistent volumes is not
+replicated.
+
+Example:
+vcluster failover standby my-vcluster -n my-namespace --target-context eu-west
+vcluster failover standby my-vcluster -n my-namespace --target-context eu-west --snapshot-interval 15m
+#######################################################
+	`,
+		Args:              util.VClusterNameOnlyValidator,
+		ValidArgsFunction: completion.NewValidVClusterNameFunc(globalFlags),
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			return cli.FailoverStandby(cobraCmd.Context(), cmd.GlobalFlags, args[0], &cmd.FailoverStandbyOptions, cmd.log)
+		},
+	}
+
+	cobraCmd.Flags().StringVar(&cmd.TargetContext, "target-context", "", "The kube context of the cluster to deploy the standby to")
+	cobraCmd.Flags().StringVar(&cmd.TargetNamespace, "target-namespace", "", "The namespace to deploy the standby to, defaults to the namespace of the virtual cluster")
+	cobraCmd.Flags().StringVar(&cmd.ChartRepo, "chart-repo", constants.LoftChartRepo, "The virtual cluster chart repo the virtual cluster was deployed from")
+	cobraCmd.Flags().StringVar(&cmd.SnapshotLocation, "snapshot-location", "", "A local path, file:// or presigned http(s):// url to replicate the snapshots to, defaults to a secret in the namespace of the standby")
+	cobraCmd.Flags().DurationVar(&cmd.SnapshotInterval, "snapshot-interval", 0, "If set, keeps replicating a snapshot of the virtual cluster in this interval until the command is stopped")
+	return cobraCmd
+}
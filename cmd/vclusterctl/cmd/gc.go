@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/vcluster/pkg/cli"
+	"github.com/loft-sh/vcluster/pkg/cli/completion"
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/loft-sh/vcluster/pkg/cli/util"
+	"github.com/spf13/cobra"
+)
+
+// GCCmd holds the gc cmd flags
+type GCCmd struct {
+	*flags.GlobalFlags
+	cli.GCOptions
+
+	log log.Logger
+}
+
+// NewGCCmd creates a new command
+func NewGCCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
+	cmd := &GCCmd{
+		GlobalFlags: globalFlags,
+		log:         log.GetInstance(),
+	}
+
+	cobraCmd := &cobra.Command{
+		Use:   "gc" + util.VClusterNameOnlyUseLine,
+		Short: "Deletes orphaned host objects of a virtual cluster",
+		Long: `#######################################################
+##################### vcluster gc #####################
+#######################################################
+Scans the namespace of the virtual cluster for host
+objects synced by it whose virtual object no longer
+exists, e.g. after crashes or forced deletions, and
+deletes them.
+
+Virtual pods, services and persistent volume claims
+without a host object are reported as well. These are
+not deleted, as the syncer creates their host objects.
+
+Example:
+vcluster gc my-vcluster -n my-namespace --dry-run
+vcluster gc my-vcluster -n my-namespace
+#######################################################
+	`,
+		Args:              util.VClusterNameOnlyValidator,
+		ValidArgsFunction: completion.NewValidVClusterNameFunc(globalFlags),
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			return cli.GC(cobraCmd.Context(), &cmd.GCOptions, cmd.GlobalFlags, args[0], cmd.log)
+		},
+	}
+
+	cobraCmd.Flags().StringVarP(&cmd.Output, "output", "o", "table", "Choose the format of the output. [table|json]")
+	cobraCmd.Flags().BoolVar(&cmd.DryRun, "dry-run", false, "Only report the orphaned objects without deleting them")
+	return cobraCmd
+}
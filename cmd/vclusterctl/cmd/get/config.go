@@ -0,0 +1,52 @@
+package get
+
+import (
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/vcluster/pkg/cli"
+	"github.com/loft-sh/vcluster/pkg/cli/completion"
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/loft-sh/vcluster/pkg/cli/util"
+	"github.com/spf13/cobra"
+)
+
+type configCmd struct {
+	*flags.GlobalFlags
+	cli.GetConfigOptions
+
+	log log.Logger
+}
+
+func newConfigCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
+	cmd := &configCmd{
+		GlobalFlags: globalFlags,
+		log:         log.GetInstance(),
+	}
+
+	cobraCmd := &cobra.Command{
+		Use:   "config" + util.VClusterNameOnlyUseLine,
+		Short: "Prints the vcluster.yaml of a virtual cluster",
+		Long: `#######################################################
+################# vcluster get config #################
+#######################################################
+Prints the values the virtual cluster was deployed with,
+which can be used to create the same virtual cluster
+elsewhere. With --resolved the config including all
+defaults as used by the virtual cluster is printed.
+
+Example:
+vcluster get config my-vcluster -n my-namespace > vcluster.yaml
+vcluster create my-vcluster -n other-namespace -f vcluster.yaml
+vcluster get config my-vcluster -n my-namespace --resolved -o json
+#######################################################
+	`,
+		Args:              util.VClusterNameOnlyValidator,
+		ValidArgsFunction: completion.NewValidVClusterNameFunc(globalFlags),
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			return cli.GetConfig(cobraCmd.Context(), cmd.GlobalFlags, args[0], &cmd.GetConfigOptions, cmd.log)
+		},
+	}
+
+	cobraCmd.Flags().StringVarP(&cmd.Output, "output", "o", "yaml", "Choose the format of the output. [yaml|json]")
+	cobraCmd.Flags().BoolVar(&cmd.Resolved, "resolved", false, "Print the config including all defaults from the config secret of the virtual cluster")
+	return cobraCmd
+}
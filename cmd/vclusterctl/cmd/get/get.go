@@ -0,0 +1,23 @@
+package get
+
+import (
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/spf13/cobra"
+)
+
+func NewGetCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
+	getCmd := &cobra.Command{
+		Use:   "get",
+		Short: "Retrieves information about a virtual cluster",
+		Long: `#######################################################
+##################### vcluster get ####################
+#######################################################
+Retrieves information about virtual clusters.
+#######################################################
+	`,
+		Args: cobra.NoArgs,
+	}
+
+	getCmd.AddCommand(newConfigCmd(globalFlags))
+	return getCmd
+}
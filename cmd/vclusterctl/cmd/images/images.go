@@ -0,0 +1,25 @@
+package images
+
+import (
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/spf13/cobra"
+)
+
+func NewImagesCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
+	imagesCmd := &cobra.Command{
+		Use:   "images",
+		Short: "Image helpers for virtual clusters",
+		Long: `#######################################################
+################### vcluster images ###################
+#######################################################
+Helpers to inspect the images a virtual cluster deploys,
+e.g. to mirror them for air-gapped environments or to
+scan them.
+#######################################################
+	`,
+		Args: cobra.NoArgs,
+	}
+
+	imagesCmd.AddCommand(list(globalFlags))
+	return imagesCmd
+}
@@ -0,0 +1,70 @@
+package images
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/vcluster/pkg/cli"
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/loft-sh/vcluster/pkg/constants"
+	"github.com/loft-sh/vcluster/pkg/upgrade"
+	"github.com/spf13/cobra"
+)
+
+type ListCmd struct {
+	*flags.GlobalFlags
+	cli.ImagesListOptions
+
+	log log.Logger
+}
+
+func list(globalFlags *flags.GlobalFlags) *cobra.Command {
+	cmd := &ListCmd{
+		GlobalFlags: globalFlags,
+		log:         log.GetInstance(),
+	}
+
+	cobraCmd := &cobra.Command{
+		Use:   "list",
+		Short: "Lists the images of a virtual cluster",
+		Long: `#######################################################
+################ vcluster images list #################
+#######################################################
+Renders the vCluster chart with the given values and
+lists every container image it deploys together with
+the digest of the image in its registry. The images the
+virtual cluster deploys itself, such as coredns, are
+included. The host cluster is not accessed.
+
+With --output cyclonedx the images are printed as
+CycloneDX SBOM for scanning pipelines.
+
+Example:
+vcluster images list
+vcluster images list --chart-version v0.20.0 --distro k3s -f vcluster.yaml
+vcluster images list -f vcluster.yaml --output cyclonedx > sbom.json
+vcluster images list --skip-digests --output json
+#######################################################
+	`,
+		Args: cobra.NoArgs,
+		RunE: func(cobraCmd *cobra.Command, _ []string) error {
+			return cli.ImagesList(cobraCmd.Context(), &cmd.ImagesListOptions, cmd.log)
+		},
+	}
+
+	cobraCmd.Flags().StringVar(&cmd.ChartVersion, "chart-version", upgrade.GetVersion(), "The virtual cluster chart version to use (e.g. v0.9.1)")
+	cobraCmd.Flags().StringVar(&cmd.ChartName, "chart-name", "vcluster", "The virtual cluster chart name to use")
+	cobraCmd.Flags().StringVar(&cmd.ChartRepo, "chart-repo", constants.LoftChartRepo, "The virtual cluster chart repo to use")
+	cobraCmd.Flags().StringVar(&cmd.LocalChartDir, "local-chart-dir", "", "The virtual cluster local chart dir to use")
+	cobraCmd.Flags().StringVar(&cmd.Distro, "distro", "k8s", fmt.Sprintf("Kubernetes distro to list the images of. Allowed distros: %s", strings.Join(cli.AllowedDistros, ", ")))
+	cobraCmd.Flags().StringVar(&cmd.KubernetesVersion, "kubernetes-version", "", "The kubernetes version to list the images of (e.g. v1.29). Patch versions are not supported")
+	cobraCmd.Flags().StringArrayVarP(&cmd.Values, "values", "f", []string{}, "Path where to load extra helm values from, use - to read them from stdin or pass an https:// or oci:// reference to download them")
+	cobraCmd.Flags().StringArrayVar(&cmd.SetValues, "set", []string{}, "Set values for helm. E.g. --set 'persistence.enabled=true'")
+	cobraCmd.Flags().BoolVar(&cmd.ExpandEnv, "expand-env", false, "Substitute ${VAR} and ${VAR:-default} environment variables and execute [[ ]] templates in the values files")
+	cobraCmd.Flags().StringVarP(&cmd.Output, "output", "o", "table", "Choose the format of the output. [table|json|cyclonedx]")
+	cobraCmd.Flags().BoolVar(&cmd.SkipDigests, "skip-digests", false, "Only list the images without resolving their digests, e.g. if the registries are not reachable")
+
+	_ = cobraCmd.Flags().MarkHidden("local-chart-dir")
+	return cobraCmd
+}
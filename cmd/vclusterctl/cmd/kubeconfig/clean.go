@@ -0,0 +1,54 @@
+package kubeconfig
+
+import (
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/vcluster/pkg/cli"
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/spf13/cobra"
+)
+
+type CleanCmd struct {
+	*flags.GlobalFlags
+	cli.KubeConfigCleanOptions
+
+	log log.Logger
+}
+
+func clean(globalFlags *flags.GlobalFlags) *cobra.Command {
+	cmd := &CleanCmd{
+		GlobalFlags: globalFlags,
+		log:         log.GetInstance(),
+	}
+
+	cobraCmd := &cobra.Command{
+		Use:   "clean",
+		Short: "Removes the kube contexts of virtual clusters that don't exist anymore",
+		Long: `#######################################################
+############## vcluster kubeconfig clean ##############
+#######################################################
+Removes the kube contexts of virtual clusters that don't
+exist anymore, e.g. because they were deleted with
+kubectl or the host cluster was torn down.
+
+Helm virtual clusters are looked up in the host context
+they were connected from. Their contexts are kept if the
+host cluster can't be reached, unless
+--include-unreachable is used. Platform virtual clusters
+are looked up in the platform you are logged into.
+
+Example:
+vcluster kubeconfig clean --dry-run
+vcluster kubeconfig clean
+vcluster kubeconfig clean --include-unreachable
+#######################################################
+	`,
+		Args: cobra.NoArgs,
+		RunE: func(cobraCmd *cobra.Command, _ []string) error {
+			return cli.CleanKubeConfig(cobraCmd.Context(), &cmd.KubeConfigCleanOptions, cmd.GlobalFlags, cmd.log)
+		},
+	}
+
+	cobraCmd.Flags().BoolVar(&cmd.DryRun, "dry-run", false, "Print the stale kube contexts without removing them")
+	cobraCmd.Flags().BoolVar(&cmd.IncludeUnreachable, "include-unreachable", false, "Also remove the contexts of virtual clusters whose host cluster can't be reached")
+	return cobraCmd
+}
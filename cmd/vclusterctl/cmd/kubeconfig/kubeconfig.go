@@ -0,0 +1,24 @@
+package kubeconfig
+
+import (
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/spf13/cobra"
+)
+
+func NewKubeConfigCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
+	kubeConfigCmd := &cobra.Command{
+		Use:   "kubeconfig",
+		Short: "Manages the vCluster contexts in your kube config",
+		Long: `#######################################################
+################# vcluster kubeconfig #################
+#######################################################
+Manages the kube contexts vcluster connect and vcluster
+create added to your kube config.
+#######################################################
+	`,
+		Args: cobra.NoArgs,
+	}
+
+	kubeConfigCmd.AddCommand(clean(globalFlags))
+	return kubeConfigCmd
+}
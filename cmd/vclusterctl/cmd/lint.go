@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/vcluster/pkg/cli"
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/spf13/cobra"
+)
+
+// LintCmd holds the lint cmd flags
+type LintCmd struct {
+	*flags.GlobalFlags
+	cli.LintOptions
+
+	log log.Logger
+}
+
+// NewLintCmd creates a new command
+func NewLintCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
+	cmd := &LintCmd{
+		GlobalFlags: globalFlags,
+		log:         log.GetInstance(),
+	}
+
+	cobraCmd := &cobra.Command{
+		Use:   "lint",
+		Short: "Lints a vcluster.yaml for errors and risky settings",
+		Long: `#######################################################
+#################### vcluster lint ####################
+#######################################################
+Lints a vcluster.yaml merged with the default values of
+this vCluster version. Errors are settings the virtual
+cluster will not start or not work with, warnings are
+settings that are valid but risky or likely unintended.
+
+The command exits with a non-zero exit code if errors
+were found, or warnings with --strict.
+
+Example:
+vcluster lint -f vcluster.yaml
+vcluster lint -f vcluster.yaml --strict --output json
+vcluster lint -f vcluster.yaml --policy policy.yaml
+#######################################################
+	`,
+		Args: cobra.NoArgs,
+		RunE: func(cobraCmd *cobra.Command, _ []string) error {
+			return cli.Lint(cobraCmd.Context(), &cmd.LintOptions, cmd.log)
+		},
+	}
+
+	cobraCmd.Flags().StringArrayVarP(&cmd.Values, "values", "f", []string{}, "Path where to load the virtual cluster helm values from, use - to read them from stdin or pass an https:// or oci:// reference to download them")
+	cobraCmd.Flags().StringArrayVar(&cmd.SetValues, "set", []string{}, "Set values for the virtual cluster. E.g. --set 'persistence.enabled=true'")
+	cobraCmd.Flags().BoolVar(&cmd.ExpandEnv, "expand-env", false, "Substitute ${VAR} and ${VAR:-default} environment variables and execute [[ ]] templates in the values files")
+	cobraCmd.Flags().BoolVar(&cmd.Strict, "strict", false, "If enabled, warnings fail the command as well")
+	cobraCmd.Flags().StringVar(&cmd.Policy, "policy", "", fmt.Sprintf("Policy the values are validated against, either a local file or an https:// or oci:// reference. Defaults to $%s", cli.PolicyEnv))
+	cobraCmd.Flags().StringSliceVar(&cmd.PolicyOverrideTokens, "policy-override-token", []string{}, "Tokens that exempt the values from policy rules")
+	cobraCmd.Flags().StringVarP(&cmd.Output, "output", "o", "text", "Choose the format of the output. [text|json]")
+	return cobraCmd
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/loft-sh/vcluster/pkg/cli"
 	"github.com/loft-sh/vcluster/pkg/cli/config"
 	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	flagslist "github.com/loft-sh/vcluster/pkg/cli/flags/list"
 	"github.com/spf13/cobra"
 )
 
@@ -38,6 +39,7 @@ Example:
 vcluster list
 vcluster list --output json
 vcluster list --namespace test
+vcluster list --status Paused --sort-by age
 #######################################################
 	`,
 		Args:    cobra.NoArgs,
@@ -48,7 +50,8 @@ vcluster list --namespace test
 	}
 
 	cobraCmd.Flags().StringVar(&cmd.Driver, "driver", "", "The driver to use for managing the virtual cluster, can be either helm or platform.")
-	cobraCmd.Flags().StringVar(&cmd.Output, "output", "table", "Choose the format of the output. [table|json]")
+	flagslist.AddCommonFlags(cobraCmd, &cmd.ListOptions)
+	flagslist.AddPlatformFlags(cobraCmd, &cmd.ListOptions, "[PLATFORM] ")
 
 	return cobraCmd
 }
@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"time"
+
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/vcluster/pkg/cli"
+	"github.com/loft-sh/vcluster/pkg/cli/completion"
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/loft-sh/vcluster/pkg/cli/util"
+	"github.com/spf13/cobra"
+)
+
+// LoadTestCmd holds the loadtest cmd flags
+type LoadTestCmd struct {
+	*flags.GlobalFlags
+	cli.LoadTestOptions
+
+	log log.Logger
+}
+
+// NewLoadTestCmd creates a new command
+func NewLoadTestCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
+	cmd := &LoadTestCmd{
+		GlobalFlags: globalFlags,
+		log:         log.GetInstance(),
+	}
+
+	cobraCmd := &cobra.Command{
+		Use:   "loadtest" + util.VClusterNameOnlyUseLine,
+		Short: "Measures the sync latency of a virtual cluster under load",
+		Long: `#######################################################
+################## vcluster loadtest ##################
+#######################################################
+Creates namespaces with pods, services and secrets in
+the virtual cluster and reports:
+- the sync latency percentiles per resource, measured
+  from the creation of the virtual object until its
+  host object was observed
+- the requests the host api server handled during the
+  test, if its metrics are accessible. These include
+  the requests of all other clients of the host cluster
+
+The namespaces are deleted afterwards. Use a dedicated
+virtual cluster, the pods are scheduled on host nodes.
+
+Example:
+vcluster loadtest my-vcluster -n my-namespace
+vcluster loadtest my-vcluster -n my-namespace --namespaces 50 --objects 20 --output json
+#######################################################
+	`,
+		Args:              util.VClusterNameOnlyValidator,
+		ValidArgsFunction: completion.NewValidVClusterNameFunc(globalFlags),
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			return cli.LoadTest(cobraCmd.Context(), &cmd.LoadTestOptions, cmd.GlobalFlags, args[0], cmd.log)
+		},
+	}
+
+	cobraCmd.Flags().StringVarP(&cmd.Output, "output", "o", "table", "Choose the format of the output. [table|json]")
+	cobraCmd.Flags().IntVar(&cmd.Namespaces, "namespaces", 10, "The number of namespaces to create")
+	cobraCmd.Flags().IntVar(&cmd.Objects, "objects", 10, "The number of pods, services and secrets to create in each namespace")
+	cobraCmd.Flags().IntVar(&cmd.Concurrency, "concurrency", 5, "The number of namespaces to fill in parallel")
+	cobraCmd.Flags().Float32Var(&cmd.QPS, "qps", 50, "The maximum queries per second against the virtual cluster")
+	cobraCmd.Flags().IntVar(&cmd.Burst, "burst", 100, "The maximum burst of queries against the virtual cluster")
+	cobraCmd.Flags().StringVar(&cmd.Image, "image", cli.DefaultLoadTestImage, "The image of the created pods")
+	cobraCmd.Flags().DurationVar(&cmd.Timeout, "timeout", 5*time.Minute, "How long to wait for all objects to be synced")
+	cobraCmd.Flags().BoolVar(&cmd.SkipCleanup, "skip-cleanup", false, "If enabled, the created namespaces are kept after the test")
+	return cobraCmd
+}
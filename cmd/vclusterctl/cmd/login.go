@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 
@@ -35,10 +36,13 @@ type LoginCmd struct {
 
 	Log log.Logger
 
-	Driver      string
-	AccessKey   string
-	Insecure    bool
-	DockerLogin bool
+	Driver        string
+	AccessKey     string
+	AccessKeyFile string
+	Insecure      bool
+	DockerLogin   bool
+
+	OIDC config.OIDCLogin
 }
 
 func NewLoginCmd(globalFlags *flags.GlobalFlags) (*cobra.Command, error) {
@@ -55,6 +59,14 @@ Login into vCluster platform
 Example:
 vcluster login https://my-vcluster-platform.com
 vcluster login https://my-vcluster-platform.com --access-key myaccesskey
+
+# Log in without a browser or prompt, e.g. in CI
+vcluster login https://my-vcluster-platform.com --access-key-file /var/run/secrets/platform/access-key
+echo "$ACCESS_KEY" | vcluster login https://my-vcluster-platform.com --access-key-file -
+
+# Log in with the client credentials of an OIDC client that is connected to the platform.
+# The login is refreshed before the token expires as long as the client secret is available.
+VCLUSTER_OIDC_CLIENT_SECRET=secret vcluster login https://my-vcluster-platform.com --oidc-issuer https://sso.example.com --oidc-client-id ci
 ########################################################
 	`
 
@@ -73,6 +85,11 @@ vcluster login https://my-vcluster-platform.com --access-key myaccesskey
 
 	loginCmd.Flags().StringVar(&cmd.Driver, "use-driver", "", "Switch vCluster driver between platform and helm")
 	loginCmd.Flags().StringVar(&cmd.AccessKey, "access-key", "", "The access key to use")
+	loginCmd.Flags().StringVar(&cmd.AccessKeyFile, "access-key-file", "", "The file to read the access key from, - reads it from stdin")
+	loginCmd.Flags().StringVar(&cmd.OIDC.Issuer, "oidc-issuer", "", "The OIDC provider to request a token for the client credentials from")
+	loginCmd.Flags().StringVar(&cmd.OIDC.ClientID, "oidc-client-id", "", "The OIDC client to log in with")
+	loginCmd.Flags().StringVar(&cmd.OIDC.ClientSecretFile, "oidc-client-secret-file", "", "The file to read the OIDC client secret from. If empty, the secret is read from "+platform.OIDCClientSecretEnv)
+	loginCmd.Flags().StringSliceVar(&cmd.OIDC.Scopes, "oidc-scopes", []string{}, "Additional scopes to request from the OIDC provider")
 	loginCmd.Flags().BoolVar(&cmd.Insecure, "insecure", true, product.Replace("Allow login into an insecure Loft instance"))
 	loginCmd.Flags().BoolVar(&cmd.DockerLogin, "docker-login", true, "If true, will log into the docker image registries the user has image pull secrets for")
 
@@ -117,9 +134,14 @@ func (cmd *LoginCmd) Run(ctx context.Context, args []string) error {
 	// log into platform
 	loginClient := platform.NewLoginClientFromConfig(cfg)
 	url = strings.TrimSuffix(url, "/")
-	var err error
-	if cmd.AccessKey != "" {
-		err = loginClient.LoginWithAccessKey(url, cmd.AccessKey, cmd.Insecure)
+	accessKey, err := cmd.accessKey()
+	if err != nil {
+		return err
+	}
+	if accessKey != "" {
+		err = loginClient.LoginWithAccessKey(url, accessKey, cmd.Insecure)
+	} else if cmd.OIDC.Issuer != "" || cmd.OIDC.ClientID != "" {
+		err = loginClient.LoginWithOIDC(ctx, url, cmd.OIDC, cmd.Insecure)
 	} else {
 		err = loginClient.Login(url, cmd.Insecure, cmd.Log)
 	}
@@ -149,6 +171,38 @@ func (cmd *LoginCmd) Run(ctx context.Context, args []string) error {
 	return nil
 }
 
+// accessKey returns the access key of the --access-key or --access-key-file flag
+func (cmd *LoginCmd) accessKey() (string, error) {
+	loginMethods := 0
+	for _, set := range []bool{cmd.AccessKey != "", cmd.AccessKeyFile != "", cmd.OIDC.Issuer != "" || cmd.OIDC.ClientID != ""} {
+		if set {
+			loginMethods++
+		}
+	}
+	if loginMethods > 1 {
+		return "", errors.New("only one of --access-key, --access-key-file or --oidc-issuer can be used")
+	} else if cmd.AccessKeyFile == "" {
+		return cmd.AccessKey, nil
+	}
+
+	var out []byte
+	var err error
+	if cmd.AccessKeyFile == "-" {
+		out, err = io.ReadAll(os.Stdin)
+	} else {
+		out, err = os.ReadFile(cmd.AccessKeyFile)
+	}
+	if err != nil {
+		return "", fmt.Errorf("read access key: %w", err)
+	}
+
+	accessKey := strings.TrimSpace(string(out))
+	if accessKey == "" {
+		return "", fmt.Errorf("access key file %s is empty", cmd.AccessKeyFile)
+	}
+	return accessKey, nil
+}
+
 func (cmd *LoginCmd) printLoginDetails(ctx context.Context) error {
 	cfg := cmd.LoadedConfig(cmd.Log)
 	platformClient := platform.NewClientFromConfig(cfg)
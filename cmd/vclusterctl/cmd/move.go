@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"time"
+
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/vcluster/pkg/cli"
+	"github.com/loft-sh/vcluster/pkg/cli/completion"
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/loft-sh/vcluster/pkg/cli/util"
+	"github.com/loft-sh/vcluster/pkg/constants"
+	"github.com/spf13/cobra"
+)
+
+// MoveCmd holds the move cmd flags
+type MoveCmd struct {
+	*flags.GlobalFlags
+	cli.MoveOptions
+
+	log log.Logger
+}
+
+// NewMoveCmd creates a new command
+func NewMoveCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
+	cmd := &MoveCmd{
+		GlobalFlags: globalFlags,
+		log:         log.GetInstance(),
+	}
+
+	cobraCmd := &cobra.Command{
+		Use:   "move" + util.VClusterNameOnlyUseLine,
+		Short: "Moves a virtual cluster to another namespace or cluster",
+		Long: `#######################################################
+#################### vcluster move ####################
+#######################################################
+Moves a virtual cluster into another namespace or the
+cluster of --target-context. The virtual cluster is
+deployed with the same chart version and config into
+the target.
+
+Virtual clusters with an external database are paused
+before the target is started with the same data. For
+all other backing stores, the objects of the virtual
+cluster are snapshotted right before the source is
+paused and restored into the target. Objects managed by
+controllers are recreated by the target.
+
+The data of persistent volumes is not moved. If the
+virtual cluster has persistent volume claims, the source
+is kept so the data can be copied into the volumes of
+the target, unless --force-delete-source is set.
+
+Load balancer services and ingresses get new addresses
+in the target, DNS records pointing to the addresses of
+the source have to be updated afterwards.
+
+Finally the kube context of the source is replaced by a
+context of the target and the source is deleted.
+
+Example:
+vcluster move my-vcluster -n my-namespace --target-namespace other-namespace
+vcluster move my-vcluster -n my-namespace --target-context eu-west --keep-source
+#######################################################
+	`,
+		Args:              util.VClusterNameOnlyValidator,
+		ValidArgsFunction: completion.NewValidVClusterNameFunc(globalFlags),
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			return cli.Move(cobraCmd.Context(), cmd.GlobalFlags, args[0], &cmd.MoveOptions, cmd.log)
+		},
+	}
+
+	cobraCmd.Flags().StringVar(&cmd.TargetContext, "target-context", "", "The kube context of the cluster to move the virtual cluster to, defaults to the current context")
+	cobraCmd.Flags().StringVar(&cmd.TargetNamespace, "target-namespace", "", "The namespace to move the virtual cluster to, defaults to the namespace of the virtual cluster")
+	cobraCmd.Flags().BoolVar(&cmd.KeepSource, "keep-source", false, "If enabled, the paused source virtual cluster is kept instead of deleted")
+	cobraCmd.Flags().BoolVar(&cmd.ForceDeleteSource, "force-delete-source", false, "If enabled, the source virtual cluster is deleted even if the data of its persistent volumes is not moved")
+	cobraCmd.Flags().StringVar(&cmd.SnapshotLocation, "snapshot-location", "", "A local path, file:// or presigned http(s):// url to save the snapshot of the source to, only used for virtual clusters without an external database")
+	cobraCmd.Flags().StringVar(&cmd.ChartRepo, "chart-repo", constants.LoftChartRepo, "The virtual cluster chart repo the virtual cluster was deployed from")
+	cobraCmd.Flags().DurationVar(&cmd.RollbackTimeout, "rollback-timeout", 5*time.Minute, "Roll back the target and resume the source if the target does not become ready within this duration. 0 disables the rollback")
+	return cobraCmd
+}
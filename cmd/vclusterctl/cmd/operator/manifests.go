@@ -0,0 +1,69 @@
+package operator
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/loft-sh/vcluster/pkg/operator"
+	"github.com/loft-sh/vcluster/pkg/upgrade"
+	"github.com/spf13/cobra"
+)
+
+type ManifestsCmd struct {
+	*flags.GlobalFlags
+	operator.ManifestOptions
+}
+
+func manifests(globalFlags *flags.GlobalFlags) *cobra.Command {
+	cmd := &ManifestsCmd{
+		GlobalFlags: globalFlags,
+	}
+
+	cobraCmd := &cobra.Command{
+		Use:   "manifests",
+		Short: "Prints the manifests that install the vcluster operator",
+		Long: `#######################################################
+############# vcluster operator manifests #############
+#######################################################
+Prints the namespace, service account, cluster role
+binding and deployment that run the vcluster operator in
+the host cluster. The operator deploys the vcluster chart,
+which creates roles and cluster roles of its own, so its
+service account is bound to cluster-admin.
+
+Example:
+vcluster operator manifests | kubectl apply -f -
+vcluster operator manifests --replicas 2 --cluster-api | kubectl apply -f -
+#######################################################
+	`,
+		Args: cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			cmd.ManifestOptions.WatchNamespace = cmd.GlobalFlags.Namespace
+			return cmd.Run()
+		},
+	}
+
+	cobraCmd.Flags().StringVar(&cmd.ManifestOptions.Namespace, "operator-namespace", operator.Name, "The namespace to install the operator into")
+	cobraCmd.Flags().StringVar(&cmd.Image, "image", "ghcr.io/loft-sh/vcluster-cli:"+upgrade.GetVersion(), "The vcluster cli image the operator runs with")
+	cobraCmd.Flags().Int32Var(&cmd.Replicas, "replicas", 1, "The number of operator replicas, leader election is enabled for more than one replica")
+	cobraCmd.Flags().BoolVar(&cmd.ClusterAPI, "cluster-api", false, "If enabled, VCluster objects act as ClusterAPI infrastructure and control plane provider")
+	return cobraCmd
+}
+
+func (cmd *ManifestsCmd) Run() error {
+	documents := []string{}
+	for _, obj := range operator.Manifests(&cmd.ManifestOptions) {
+		out, err := yaml.Marshal(obj)
+		if err != nil {
+			return fmt.Errorf("marshal manifest: %w", err)
+		}
+
+		documents = append(documents, string(out))
+	}
+
+	_, err := fmt.Fprint(os.Stdout, strings.Join(documents, "---\n"))
+	return err
+}
@@ -0,0 +1,26 @@
+package operator
+
+import (
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/spf13/cobra"
+)
+
+func NewOperatorCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
+	operatorCmd := &cobra.Command{
+		Use:   "operator",
+		Short: "Virtual cluster operator subcommands",
+		Long: `#######################################################
+################## vcluster operator ##################
+#######################################################
+Manages virtual clusters declaratively through VCluster
+objects (operator.vcluster.loft.sh/v1alpha1) in the host
+cluster, e.g. with GitOps tools like Argo CD.
+#######################################################
+	`,
+		Args: cobra.NoArgs,
+	}
+
+	operatorCmd.AddCommand(start(globalFlags))
+	operatorCmd.AddCommand(manifests(globalFlags))
+	return operatorCmd
+}
@@ -0,0 +1,68 @@
+package operator
+
+import (
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/loft-sh/vcluster/pkg/operator"
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+type StartCmd struct {
+	*flags.GlobalFlags
+	operator.Options
+
+	log log.Logger
+}
+
+func start(globalFlags *flags.GlobalFlags) *cobra.Command {
+	cmd := &StartCmd{
+		GlobalFlags: globalFlags,
+		log:         log.GetInstance(),
+	}
+
+	cobraCmd := &cobra.Command{
+		Use:   "start",
+		Short: "Starts the vcluster operator",
+		Long: `#######################################################
+############### vcluster operator start ###############
+#######################################################
+Installs the VCluster custom resource definition and
+reconciles VCluster objects into helm releases. Every
+VCluster object is deployed as a helm release with the
+same name and namespace, changes to the spec upgrade the
+release and deleting the object deletes the release.
+
+With --cluster-api VCluster objects act as ClusterAPI
+infrastructure and control plane provider, so ClusterAPI
+Cluster objects can reference them.
+
+Example:
+vcluster operator start
+vcluster operator start --namespace team-a
+vcluster operator start --cluster-api
+
+Use vcluster operator manifests to run the operator in
+the host cluster.
+#######################################################
+	`,
+		Args: cobra.NoArgs,
+		RunE: func(cobraCmd *cobra.Command, _ []string) error {
+			restConfig, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(clientcmd.NewDefaultClientConfigLoadingRules(), &clientcmd.ConfigOverrides{
+				CurrentContext: cmd.Context,
+			}).ClientConfig()
+			if err != nil {
+				return err
+			}
+
+			cmd.Options.Namespace = cmd.GlobalFlags.Namespace
+			return operator.Start(cobraCmd.Context(), restConfig, &cmd.Options, cmd.log)
+		},
+	}
+
+	cobraCmd.Flags().StringVar(&cmd.MetricsBindAddress, "metrics-bind-address", "0", "The address the metrics endpoint binds to, 0 disables it")
+	cobraCmd.Flags().BoolVar(&cmd.LeaderElection, "leader-elect", false, "If enabled, only one of multiple operator replicas is active at a time")
+	cobraCmd.Flags().StringVar(&cmd.LeaderElectionNamespace, "leader-election-namespace", "", "The namespace of the leader election lease, defaults to --namespace or the namespace of the operator pod")
+	cobraCmd.Flags().BoolVar(&cmd.ClusterAPI, "cluster-api", false, "If enabled, VCluster objects act as ClusterAPI infrastructure and control plane provider")
+	return cobraCmd
+}
@@ -0,0 +1,61 @@
+package platform
+
+import (
+	"context"
+
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/loft-sh/vcluster/pkg/cli/start"
+	"github.com/spf13/cobra"
+)
+
+type ActivateCmd struct {
+	start.Options
+}
+
+func NewActivateCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
+	cmd := &ActivateCmd{
+		Options: start.Options{
+			GlobalFlags: globalFlags,
+			Log:         log.GetInstance(),
+		},
+	}
+
+	activateCmd := &cobra.Command{
+		Use:   "activate",
+		Short: "Activate an offline license for a vCluster platform instance",
+		Long: `########################################################
+############ vcluster platform activate ################
+########################################################
+Activates an offline license for the vCluster platform
+instance in the current kube context. The license is
+stored in a secret and passed to vCluster platform, so
+air-gapped instances don't need to reach the license
+server.
+
+Use vcluster platform start --license-file to install
+vCluster platform with an offline license.
+
+Example:
+vcluster platform activate --license-file license.jwt
+vcluster platform activate --license-file license.jwt --namespace vcluster-platform
+########################################################
+	`,
+		Args: cobra.NoArgs,
+		RunE: func(cobraCmd *cobra.Command, _ []string) error {
+			return cmd.Run(cobraCmd.Context())
+		},
+	}
+
+	activateCmd.Flags().StringVar(&cmd.LicenseFile, "license-file", "", "The path to the offline license key")
+	activateCmd.Flags().StringVar(&cmd.Context, "context", "", "The kube context vCluster platform is installed in")
+	activateCmd.Flags().StringVar(&cmd.Namespace, "namespace", "vcluster-platform", "The namespace vCluster platform is installed in")
+	activateCmd.Flags().BoolVar(&cmd.NoWait, "no-wait", false, "If true, vCluster platform will not wait until it was restarted with the license")
+	_ = activateCmd.MarkFlagRequired("license-file")
+
+	return activateCmd
+}
+
+func (cmd *ActivateCmd) Run(ctx context.Context) error {
+	return start.NewLoftStarter(cmd.Options).Activate(ctx)
+}
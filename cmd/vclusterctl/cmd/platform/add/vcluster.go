@@ -2,9 +2,11 @@ package add
 
 import (
 	"context"
+	"errors"
 
 	"github.com/loft-sh/log"
 	"github.com/loft-sh/vcluster/pkg/cli"
+	"github.com/loft-sh/vcluster/pkg/cli/completion"
 	"github.com/loft-sh/vcluster/pkg/cli/flags"
 	"github.com/spf13/cobra"
 )
@@ -27,16 +29,33 @@ func NewVClusterCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
 ###############################################
 Adds a vCluster to the vCluster platform.
 
+Use --all to add all vClusters of the current context,
+optionally limited to namespaces matching --namespace-selector,
+or --manifest to add the vClusters listed in a file:
+
+vclusters:
+- name: my-vcluster
+  namespace: vcluster-my-vcluster
+  context: my-context
+  project: my-project
+  importName: my-vcluster
+
+The vClusters are added concurrently and the status of
+each vCluster is reported.
+
 Example:
 vcluster platform add vcluster my-vcluster --namespace vcluster-my-vcluster --project my-project --import-name my-vcluster
+vcluster platform add vcluster --all --namespace-selector team=a --project my-project
+vcluster platform add vcluster --manifest vclusters.yaml
 ###############################################
 	`
 
 	addCmd := &cobra.Command{
-		Use:   "vcluster",
-		Short: "Adds an existing vCluster to the vCluster platform",
-		Long:  description,
-		Args:  cobra.ExactArgs(1),
+		Use:               "vcluster",
+		Short:             "Adds an existing vCluster to the vCluster platform",
+		Long:              description,
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: completion.NewValidVClusterNameFunc(globalFlags),
 		RunE: func(cobraCmd *cobra.Command, args []string) error {
 			return cmd.Run(cobraCmd.Context(), args)
 		},
@@ -48,11 +67,29 @@ vcluster platform add vcluster my-vcluster --namespace vcluster-my-vcluster --pr
 	addCmd.Flags().StringVar(&cmd.AccessKey, "access-key", "", "The access key for the vCluster to connect to the platform. If empty, the CLI will generate one")
 	addCmd.Flags().StringVar(&cmd.Host, "host", "", "The host where to reach the platform")
 	addCmd.Flags().BoolVar(&cmd.Insecure, "insecure", false, "If the platform host is insecure")
+	addCmd.Flags().BoolVar(&cmd.All, "all", false, "Add all vClusters of the current context")
+	addCmd.Flags().StringVar(&cmd.NamespaceSelector, "namespace-selector", "", "Only add vClusters in namespaces matching this label selector, requires --all")
+	addCmd.Flags().StringVar(&cmd.Manifest, "manifest", "", "Path to a file listing the vClusters to add")
+	addCmd.Flags().IntVar(&cmd.Concurrency, "concurrency", 4, "The number of vClusters to add at the same time with --all or --manifest")
 
 	return addCmd
 }
 
 // Run executes the functionality
 func (cmd *VClusterCmd) Run(ctx context.Context, args []string) error {
+	if cmd.All && cmd.Manifest != "" {
+		return errors.New("--all and --manifest cannot be used together")
+	} else if cmd.NamespaceSelector != "" && !cmd.All {
+		return errors.New("--namespace-selector requires --all")
+	} else if cmd.All || cmd.Manifest != "" {
+		if len(args) > 0 {
+			return errors.New("a vCluster name cannot be used together with --all or --manifest")
+		}
+
+		return cli.AddVClustersHelm(ctx, &cmd.AddVClusterOptions, cmd.GlobalFlags, cmd.Log)
+	} else if len(args) == 0 {
+		return errors.New("please specify a vCluster name, --all or --manifest")
+	}
+
 	return cli.AddVClusterHelm(ctx, &cmd.AddVClusterOptions, cmd.GlobalFlags, args[0], cmd.Log)
 }
@@ -18,5 +18,6 @@ func NewBackupCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
 	}
 
 	addCmd.AddCommand(newManagementCmd(globalFlags))
+	addCmd.AddCommand(newListCmd(globalFlags))
 	return addCmd
 }
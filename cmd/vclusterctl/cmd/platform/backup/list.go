@@ -0,0 +1,92 @@
+package backup
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/loft-sh/api/v4/pkg/product"
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/log/table"
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/loft-sh/vcluster/pkg/platform/backup"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/duration"
+)
+
+// ListCmd holds the cmd flags
+type ListCmd struct {
+	*flags.GlobalFlags
+	Log      log.Logger
+	Selector string
+}
+
+// newListCmd creates a new command for listing backup archives
+func newListCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
+	cmd := &ListCmd{
+		GlobalFlags: globalFlags,
+		Log:         log.GetInstance(),
+	}
+
+	description := product.ReplaceWithHeader("backup list", `
+List the backup archives in a local directory
+
+Lists the archives written by
+vcluster platform backup management --destination
+together with their labels. Use --selector to only list
+the archives with matching labels.
+
+Example:
+vcluster platform backup list backups
+vcluster platform backup list backups --selector purpose=pre-upgrade
+########################################################
+	`)
+
+	c := &cobra.Command{
+		Use:   "list [directory]",
+		Short: "Lists the backup archives in a local directory",
+		Long:  description,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			return cmd.run(args[0])
+		},
+	}
+
+	c.Flags().StringVarP(&cmd.Selector, "selector", "l", "", "Only list archives with labels that match the selector. E.g. --selector purpose=pre-upgrade")
+	return c
+}
+
+// run executes the functionality
+func (cmd *ListCmd) run(location string) error {
+	selector, err := labels.Parse(cmd.Selector)
+	if err != nil {
+		return fmt.Errorf("parse selector: %w", err)
+	}
+
+	entries, err := backup.List(location, selector)
+	if err != nil {
+		return err
+	}
+
+	header := []string{
+		"Location",
+		"Labels",
+		"Age",
+	}
+	values := [][]string{}
+	for _, entry := range entries {
+		age := ""
+		if !entry.Created.IsZero() {
+			age = duration.HumanDuration(time.Since(entry.Created))
+		}
+
+		values = append(values, []string{
+			entry.Location,
+			backup.FormatLabels(entry.Labels),
+			age,
+		})
+	}
+
+	table.PrintTable(cmd.Log, header, values)
+	return nil
+}
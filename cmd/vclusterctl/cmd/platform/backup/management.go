@@ -3,6 +3,8 @@ package backup
 import (
 	"fmt"
 	"os"
+	"slices"
+	"strings"
 
 	storagev1 "github.com/loft-sh/api/v4/pkg/apis/storage/v1"
 	"github.com/loft-sh/api/v4/pkg/product"
@@ -33,7 +35,11 @@ type ManagementCmd struct {
 	Log       log.Logger
 	Namespace string
 	Filename  string
-	Skip      []string
+	// Destination is the location the backup is uploaded to as a compressed archive instead of writing it to Filename
+	Destination string
+	// Labels are stored in the metadata of the archive written to Destination
+	Labels []string
+	Skip   []string
 }
 
 // newManagementCmd creates a new command for backing up the management plane
@@ -46,8 +52,20 @@ func newManagementCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
 	description := product.ReplaceWithHeader("backup management", `
 Backup creates a backup for the vCluster platform management plane
 
+The backup contains the projects, templates, virtual cluster
+instances, users, teams and their secrets as well as the
+settings of vCluster platform. Use --destination to upload the
+backup as a compressed archive to an object store via a
+presigned url and restore it with
+vcluster platform restore management. Archives can be labeled
+with --label and listed by their labels with
+vcluster platform backup list.
+
 Example:
 vcluster platform backup management
+vcluster platform backup management --destination backup.tar.gz
+vcluster platform backup management --destination backups/pre-upgrade.tar.gz --label purpose=pre-upgrade --label git=abc123
+vcluster platform backup management --destination "https://my-bucket.s3.amazonaws.com/backup.tar.gz?X-Amz-Signature=..."
 ########################################################
 	`)
 
@@ -67,14 +85,23 @@ vcluster platform backup management
 		},
 	}
 
-	c.Flags().StringSliceVar(&cmd.Skip, "skip", []string{}, "What resources the backup should skip. Valid options are: users, teams, accesskeys, sharedsecrets, clusters and clusteraccounttemplates")
+	c.Flags().StringSliceVar(&cmd.Skip, "skip", []string{}, "What resources the backup should skip. Valid options are: users, teams, accesskeys, sharedsecrets, clusters, clusteraccounttemplates and settings")
 	c.Flags().StringVar(&cmd.Namespace, "namespace", "loft", product.Replace("The namespace vCluster platform was installed into"))
 	c.Flags().StringVar(&cmd.Filename, "filename", "backup.yaml", "The filename to write the backup to")
+	c.Flags().StringVar(&cmd.Destination, "destination", "", "Write the backup as a compressed archive to a local path, a file:// url or upload it to a presigned http(s):// object store url instead of --filename")
+	c.Flags().StringArrayVar(&cmd.Labels, "label", []string{}, "Label to store in the metadata of the archive written to --destination. E.g. --label purpose=pre-upgrade")
 	return c
 }
 
 // run executes the functionality
 func (cmd *ManagementCmd) run(cobraCmd *cobra.Command) error {
+	archiveLabels, err := backup.ParseLabels(cmd.Labels)
+	if err != nil {
+		return err
+	} else if len(archiveLabels) > 0 && cmd.Destination == "" {
+		return fmt.Errorf("--label can only be used with --destination, as only archives have metadata")
+	}
+
 	// first load the kube config
 	kubeClientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(clientcmd.NewDefaultClientConfigLoadingRules(), &clientcmd.ConfigOverrides{})
 
@@ -112,9 +139,35 @@ func (cmd *ManagementCmd) run(cobraCmd *cobra.Command) error {
 	objects, errors := backup.All(ctx, client, cmd.Skip, func(msg string) {
 		cmd.Log.Info(msg)
 	})
+	if !slices.Contains(cmd.Skip, "settings") {
+		cmd.Log.Info("Backing up settings...")
+		settings, err := backup.Settings(ctx, client, cmd.Namespace)
+		if err != nil {
+			errors = append(errors, fmt.Errorf("backup settings: %w", err))
+		} else {
+			objects = append(objects, settings...)
+		}
+	}
 	for _, err := range errors {
 		cmd.Log.Warn(err)
 	}
+
+	if cmd.Destination != "" {
+		archive, err := backup.ToArchive(objects, archiveLabels)
+		if err != nil {
+			return err
+		}
+
+		cmd.Log.Infof("Uploading backup of %d objects...", len(objects))
+		err = backup.Upload(ctx, cmd.Destination, archive)
+		if err != nil {
+			return err
+		}
+
+		cmd.Log.Donef("Wrote backup of %d objects to %s", len(objects), backupLocation(cmd.Destination))
+		return nil
+	}
+
 	backupBytes, err := backup.ToYAML(objects)
 	if err != nil {
 		return err
@@ -130,3 +183,8 @@ func (cmd *ManagementCmd) run(cobraCmd *cobra.Command) error {
 	cmd.Log.Donef("Wrote backup to %s", cmd.Filename)
 	return nil
 }
+
+// backupLocation returns the location without the query, which contains the signature of presigned urls
+func backupLocation(location string) string {
+	return strings.SplitN(location, "?", 2)[0]
+}
@@ -46,7 +46,7 @@ vcluster platform connect vcluster test -n test -- kubectl get ns
 #########################################################################
 	`,
 		Args:              nameValidator,
-		ValidArgsFunction: completion.NewValidVClusterNameFunc(globalFlags),
+		ValidArgsFunction: completion.NewValidPlatformVClusterNameFunc(globalFlags),
 		RunE: func(cobraCmd *cobra.Command, args []string) error {
 			// Check for newer version
 			upgrade.PrintNewerVersionWarning()
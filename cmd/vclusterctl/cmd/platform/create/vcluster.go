@@ -37,6 +37,7 @@ Creates a new virtual cluster
 
 Example:
 vcluster platform create vcluster test --namespace test
+vcluster platform create vcluster test --template my-template --params params.yaml --preview
 #########################################################################
 	`,
 		Args: util.VClusterNameOnlyValidator,
@@ -40,7 +40,7 @@ vcluster platform delete vcluster --namespace test
 #########################################################################
 	`,
 		Args:              util.VClusterNameOnlyValidator,
-		ValidArgsFunction: completion.NewValidVClusterNameFunc(globalFlags),
+		ValidArgsFunction: completion.NewValidPlatformVClusterNameFunc(globalFlags),
 		RunE: func(cobraCmd *cobra.Command, args []string) error {
 			return cmd.Run(cobraCmd.Context(), args)
 		},
@@ -20,6 +20,7 @@ func NewGetCmd(globalFlags *flags.GlobalFlags, defaults *defaults.Defaults) *cob
 
 	cmd.AddCommand(newClusterCmd(globalFlags))
 	cmd.AddCommand(newClusterAccessKeyCmd(globalFlags))
+	cmd.AddCommand(newQuotaCmd(globalFlags, defaults))
 	cmd.AddCommand(newSecretCmd(globalFlags, defaults))
 	cmd.AddCommand(newUserCmd(globalFlags))
 	return cmd
@@ -0,0 +1,56 @@
+package get
+
+import (
+	"context"
+
+	"github.com/loft-sh/api/v4/pkg/product"
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/vcluster/pkg/cli"
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	pdefaults "github.com/loft-sh/vcluster/pkg/platform/defaults"
+	"github.com/spf13/cobra"
+)
+
+type quotaCmd struct {
+	*flags.GlobalFlags
+	cli.QuotaOptions
+
+	log log.Logger
+}
+
+func newQuotaCmd(globalFlags *flags.GlobalFlags, defaults *pdefaults.Defaults) *cobra.Command {
+	cmd := &quotaCmd{
+		GlobalFlags: globalFlags,
+		log:         log.GetInstance(),
+	}
+	description := product.ReplaceWithHeader("get quota", `
+Prints the quotas of a project and how much of them is used.
+Project quotas limit the whole project, per user quotas limit
+every user and team in the project.
+
+Example:
+vcluster platform get quota --project my-project
+vcluster platform get quota --project my-project --output yaml
+########################################################
+	`)
+	c := &cobra.Command{
+		Use:   "quota",
+		Short: "Prints the quotas of a project",
+		Long:  description,
+		Args:  cobra.NoArgs,
+		RunE: func(cobraCmd *cobra.Command, _ []string) error {
+			return cmd.Run(cobraCmd.Context())
+		},
+	}
+
+	p, _ := defaults.Get(pdefaults.KeyProject, "")
+	c.Flags().StringVarP(&cmd.Project, "project", "p", p, "The project to print the quotas of")
+	c.Flags().StringVarP(&cmd.Output, "output", "o", "table", "Output format. One of: (table, json, yaml)")
+
+	return c
+}
+
+// Run executes the command logic
+func (cmd *quotaCmd) Run(ctx context.Context) error {
+	return cli.GetQuotaPlatform(ctx, &cmd.QuotaOptions, cmd.GlobalFlags, cmd.log)
+}
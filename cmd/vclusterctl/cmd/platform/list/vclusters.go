@@ -6,6 +6,7 @@ import (
 	"github.com/loft-sh/log"
 	"github.com/loft-sh/vcluster/pkg/cli"
 	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	flagslist "github.com/loft-sh/vcluster/pkg/cli/flags/list"
 	"github.com/spf13/cobra"
 )
 
@@ -34,6 +35,9 @@ Lists all virtual clusters that are connected to the current platform
 
 Example:
 vcluster platform list vclusters
+vcluster platform list vclusters --project my-project --cluster loft-cluster
+vcluster platform list vclusters --sleeping --template my-template --sort-by age
+vcluster platform list vclusters --status Ready --output json
 ##########################################################################
 	`,
 		Args: cobra.NoArgs,
@@ -42,7 +46,8 @@ vcluster platform list vclusters
 		},
 	}
 
-	cobraCmd.Flags().StringVar(&cmd.Output, "output", "table", "Choose the format of the output. [table|json]")
+	flagslist.AddCommonFlags(cobraCmd, &cmd.ListOptions)
+	flagslist.AddPlatformFlags(cobraCmd, &cmd.ListOptions)
 
 	return cobraCmd
 }
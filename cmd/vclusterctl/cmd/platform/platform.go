@@ -12,6 +12,7 @@ import (
 	cmddelete "github.com/loft-sh/vcluster/cmd/vclusterctl/cmd/platform/delete"
 	"github.com/loft-sh/vcluster/cmd/vclusterctl/cmd/platform/get"
 	"github.com/loft-sh/vcluster/cmd/vclusterctl/cmd/platform/list"
+	"github.com/loft-sh/vcluster/cmd/vclusterctl/cmd/platform/restore"
 	"github.com/loft-sh/vcluster/cmd/vclusterctl/cmd/platform/set"
 	"github.com/loft-sh/vcluster/cmd/vclusterctl/cmd/platform/share"
 	"github.com/loft-sh/vcluster/cmd/vclusterctl/cmd/platform/sleep"
@@ -50,14 +51,18 @@ func NewPlatformCmd(globalFlags *flags.GlobalFlags) (*cobra.Command, error) {
 	startCmd := NewStartCmd(globalFlags)
 
 	platformCmd.AddCommand(startCmd)
+	platformCmd.AddCommand(NewActivateCmd(globalFlags))
+	platformCmd.AddCommand(NewUpgradeCmd(globalFlags))
 	platformCmd.AddCommand(NewResetCmd(globalFlags))
 	platformCmd.AddCommand(add.NewAddCmd(globalFlags))
 	platformCmd.AddCommand(NewAccessKeyCmd(globalFlags))
+	platformCmd.AddCommand(NewRotateKeyCmd(globalFlags))
 	platformCmd.AddCommand(get.NewGetCmd(globalFlags, defaults))
 	platformCmd.AddCommand(connect.NewConnectCmd(globalFlags, defaults))
 	platformCmd.AddCommand(list.NewListCmd(globalFlags))
 	platformCmd.AddCommand(set.NewSetCmd(globalFlags, defaults))
 	platformCmd.AddCommand(backup.NewBackupCmd(globalFlags))
+	platformCmd.AddCommand(restore.NewRestoreCmd(globalFlags))
 	platformCmd.AddCommand(wakeup.NewWakeupCmd(globalFlags, defaults))
 	platformCmd.AddCommand(sleep.NewSleepCmd(globalFlags, defaults))
 	platformCmd.AddCommand(share.NewShareCmd(globalFlags, defaults))
@@ -0,0 +1,131 @@
+package restore
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/loft-sh/api/v4/pkg/product"
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/log/survey"
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/loft-sh/vcluster/pkg/platform/backup"
+	"github.com/loft-sh/vcluster/pkg/platform/clihelper"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/kubernetes"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/clientcmd"
+	clientpkg "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ManagementCmd holds the cmd flags
+type ManagementCmd struct {
+	*flags.GlobalFlags
+	Log       log.Logger
+	Namespace string
+	Source    string
+	Overwrite bool
+}
+
+// newManagementCmd creates a new command for restoring the management plane
+func newManagementCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
+	cmd := &ManagementCmd{
+		GlobalFlags: globalFlags,
+		Log:         log.GetInstance(),
+	}
+
+	description := product.ReplaceWithHeader("restore management", `
+Restore restores a backup of the vCluster platform management
+plane created by vcluster platform backup management. Objects
+that already exist are skipped unless --overwrite is set.
+
+Example:
+vcluster platform restore management --source backup.tar.gz
+vcluster platform restore management --source "https://my-bucket.s3.amazonaws.com/backup.tar.gz?X-Amz-Signature=..."
+vcluster platform restore management --source backup.yaml --overwrite
+########################################################
+	`)
+
+	c := &cobra.Command{
+		Use:   "management",
+		Short: product.Replace("Restore a vCluster platform management plane backup"),
+		Long:  description,
+		Args:  cobra.NoArgs,
+		RunE: func(cobraCmd *cobra.Command, _ []string) error {
+			return cmd.run(cobraCmd)
+		},
+	}
+
+	c.Flags().StringVar(&cmd.Namespace, "namespace", "loft", product.Replace("The namespace vCluster platform was installed into"))
+	c.Flags().StringVar(&cmd.Source, "source", "", "The backup to restore: a local path, a file:// url or a presigned http(s):// object store url")
+	c.Flags().BoolVar(&cmd.Overwrite, "overwrite", false, "If true, existing objects are overwritten with the objects of the backup")
+	_ = c.MarkFlagRequired("source")
+	return c
+}
+
+// run executes the functionality
+func (cmd *ManagementCmd) run(cobraCmd *cobra.Command) error {
+	ctx := cobraCmd.Context()
+	data, err := backup.Download(ctx, cmd.Source)
+	if err != nil {
+		return fmt.Errorf("read backup: %w", err)
+	}
+	objects, err := backup.FromArchive(data)
+	if err != nil {
+		return err
+	}
+
+	// first load the kube config
+	kubeClientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(clientcmd.NewDefaultClientConfigLoadingRules(), &clientcmd.ConfigOverrides{})
+
+	// load the raw config
+	kubeConfig, err := kubeClientConfig.ClientConfig()
+	if err != nil {
+		return fmt.Errorf("there is an error loading your current kube config (%w), please make sure you have access to a kubernetes cluster and the command `kubectl get namespaces` is working", err)
+	}
+
+	kubeClient, err := kubernetes.NewForConfig(kubeConfig)
+	if err != nil {
+		return fmt.Errorf("there is an error loading your current kube config (%w), please make sure you have access to a kubernetes cluster and the command `kubectl get namespaces` is working", err)
+	}
+
+	// the custom resources of the backup only exist once vCluster platform is installed
+	isInstalled, err := clihelper.IsLoftAlreadyInstalled(ctx, kubeClient, cmd.Namespace)
+	if err != nil {
+		return err
+	} else if !isInstalled {
+		answer, err := cmd.Log.Question(&survey.QuestionOptions{
+			Question:     fmt.Sprintf(product.Replace("Seems like vCluster platform was not installed into namespace %q, do you want to continue?"), cmd.Namespace),
+			DefaultValue: "Yes",
+			Options:      []string{"Yes", "No"},
+		})
+		if err != nil || answer != "Yes" {
+			return err
+		}
+	}
+
+	client, err := clientpkg.New(kubeConfig, clientpkg.Options{Scheme: clientgoscheme.Scheme})
+	if err != nil {
+		return err
+	}
+
+	cmd.Log.Infof("Restoring %d objects...", len(objects))
+	result, errors := backup.Restore(ctx, client, objects, cmd.Overwrite, func(msg string) {
+		cmd.Log.Debug(msg)
+	})
+	for _, err := range errors {
+		cmd.Log.Warn(err)
+	}
+
+	cmd.Log.Donef("Restored backup: %d created, %d updated, %d skipped, %d failed", result.Created, result.Updated, result.Skipped, len(errors))
+	if cmd.Overwrite && slices.ContainsFunc(objects, func(obj *unstructured.Unstructured) bool {
+		return obj.GetKind() == "Secret" && obj.GetName() == backup.ManagerConfigSecret
+	}) {
+		cmd.Log.Info(product.Replace("Restart vCluster platform to apply the restored settings"))
+	}
+	if len(errors) > 0 {
+		return fmt.Errorf("failed to restore %d objects", len(errors))
+	}
+
+	return nil
+}
@@ -0,0 +1,22 @@
+package restore
+
+import (
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/spf13/cobra"
+)
+
+// NewRestoreCmd creates a new command
+func NewRestoreCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
+	restoreCmd := &cobra.Command{
+		Use:   "restore",
+		Short: "Restore subcommands",
+		Long: `#######################################################
+############ vcluster platform restore ################
+#######################################################
+		`,
+		Args: cobra.NoArgs,
+	}
+
+	restoreCmd.AddCommand(newManagementCmd(globalFlags))
+	return restoreCmd
+}
@@ -0,0 +1,59 @@
+package platform
+
+import (
+	"time"
+
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/vcluster/pkg/cli"
+	"github.com/loft-sh/vcluster/pkg/cli/completion"
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/loft-sh/vcluster/pkg/cli/util"
+	"github.com/spf13/cobra"
+)
+
+type RotateKeyCmd struct {
+	*flags.GlobalFlags
+	cli.RotateKeyOptions
+
+	Log log.Logger
+}
+
+func NewRotateKeyCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
+	cmd := &RotateKeyCmd{
+		GlobalFlags: globalFlags,
+		Log:         log.GetInstance(),
+	}
+
+	description := `########################################################
+############# vcluster platform rotate-key #############
+########################################################
+Creates a new platform access key for a virtual cluster,
+stores it in the platform secret of the virtual cluster
+and restarts it. After the virtual cluster is ready
+again, the access key of the previous rotation is
+revoked.
+
+Use --interval to keep rotating the access key, e.g. in
+a long running pod.
+
+Example:
+vcluster platform rotate-key my-vcluster --namespace vcluster-my-vcluster
+vcluster platform rotate-key my-vcluster --namespace vcluster-my-vcluster --interval 720h
+########################################################
+	`
+
+	rotateKeyCmd := &cobra.Command{
+		Use:               "rotate-key" + util.VClusterNameOnlyUseLine,
+		Short:             "Rotates the platform access key of a virtual cluster",
+		Long:              description,
+		Args:              util.VClusterNameOnlyValidator,
+		ValidArgsFunction: completion.NewValidVClusterNameFunc(globalFlags),
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			return cli.RotateKey(cobraCmd.Context(), &cmd.RotateKeyOptions, cmd.GlobalFlags, args[0], cmd.Log)
+		},
+	}
+
+	rotateKeyCmd.Flags().DurationVar(&cmd.Interval, "interval", 0, "If set, rotates the access key in this interval until the command is stopped")
+	rotateKeyCmd.Flags().DurationVar(&cmd.Timeout, "timeout", 5*time.Minute, "The time to wait for the virtual cluster to become ready with the new access key")
+	return rotateKeyCmd
+}
@@ -0,0 +1,62 @@
+package set
+
+import (
+	"context"
+
+	"github.com/loft-sh/api/v4/pkg/product"
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/vcluster/pkg/cli"
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	pdefaults "github.com/loft-sh/vcluster/pkg/platform/defaults"
+	"github.com/spf13/cobra"
+)
+
+// QuotaCmd holds the flags
+type QuotaCmd struct {
+	*flags.GlobalFlags
+	cli.QuotaOptions
+
+	log log.Logger
+}
+
+// NewQuotaCmd creates a new command
+func NewQuotaCmd(globalFlags *flags.GlobalFlags, defaults *pdefaults.Defaults) *cobra.Command {
+	cmd := &QuotaCmd{
+		GlobalFlags: globalFlags,
+		log:         log.GetInstance(),
+	}
+	description := product.ReplaceWithHeader("set quota", `
+Sets or removes quotas of a project. Quotas are given as
+RESOURCE=VALUE, an empty value or RESOURCE- removes the quota.
+With --per-user the quotas limit every user and team in the
+project instead of the whole project.
+
+Example:
+vcluster platform set quota --project my-project --virtual-clusters 10 --cpu 20 --memory 64Gi
+vcluster platform set quota --project my-project limits.cpu=40 requests.memory-
+vcluster platform set quota --project my-project --per-user count/virtualclusterinstances=2
+#######################################################
+	`)
+	c := &cobra.Command{
+		Use:   "quota [RESOURCE=VALUE...]",
+		Short: "Sets the quotas of a project",
+		Long:  description,
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			return cmd.Run(cobraCmd.Context(), args)
+		},
+	}
+
+	p, _ := defaults.Get(pdefaults.KeyProject, "")
+	c.Flags().StringVarP(&cmd.Project, "project", "p", p, "The project to set the quotas of")
+	c.Flags().BoolVar(&cmd.PerUser, "per-user", false, "Set the quotas of every user and team in the project instead of the quotas of the whole project")
+	c.Flags().StringVar(&cmd.VirtualClusters, "virtual-clusters", "", "The maximum number of virtual clusters, same as "+cli.QuotaVirtualClusters+"=VALUE")
+	c.Flags().StringVar(&cmd.CPU, "cpu", "", "The maximum requested cpu, same as "+cli.QuotaCPU+"=VALUE")
+	c.Flags().StringVar(&cmd.Memory, "memory", "", "The maximum requested memory, same as "+cli.QuotaMemory+"=VALUE")
+
+	return c
+}
+
+// Run executes the command logic
+func (cmd *QuotaCmd) Run(ctx context.Context, args []string) error {
+	return cli.SetQuotaPlatform(ctx, &cmd.QuotaOptions, cmd.GlobalFlags, args, cmd.log)
+}
@@ -18,5 +18,6 @@ func NewSetCmd(globalFlags *flags.GlobalFlags, defaults *pdefaults.Defaults) *co
 	}
 
 	c.AddCommand(NewSecretCmd(globalFlags, defaults))
+	c.AddCommand(NewQuotaCmd(globalFlags, defaults))
 	return c
 }
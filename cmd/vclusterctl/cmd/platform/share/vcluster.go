@@ -7,6 +7,7 @@ import (
 	storagev1 "github.com/loft-sh/api/v4/pkg/apis/storage/v1"
 	"github.com/loft-sh/api/v4/pkg/product"
 	"github.com/loft-sh/log"
+	"github.com/loft-sh/vcluster/pkg/cli/completion"
 	"github.com/loft-sh/vcluster/pkg/cli/flags"
 	"github.com/loft-sh/vcluster/pkg/cli/util"
 	"github.com/loft-sh/vcluster/pkg/platform"
@@ -50,7 +51,8 @@ vcluster platform share vcluster myvcluster --project myproject
 vcluster platform share vcluster myvcluster --project myproject --user admin
 ##########################################################################
 	`,
-		Args: util.VClusterNameOnlyValidator,
+		Args:              util.VClusterNameOnlyValidator,
+		ValidArgsFunction: completion.NewValidPlatformVClusterNameFunc(globalFlags),
 		RunE: func(cobraCmd *cobra.Command, args []string) error {
 			return cmd.Run(cobraCmd.Context(), args)
 		},
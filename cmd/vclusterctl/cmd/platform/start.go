@@ -56,16 +56,19 @@ before running this command:
 	startCmd.Flags().StringVar(&cmd.Namespace, "namespace", "vcluster-platform", "The namespace to install vCluster platform into")
 	startCmd.Flags().StringVar(&cmd.LocalPort, "local-port", "", "The local port to bind to if using port-forwarding")
 	startCmd.Flags().StringVar(&cmd.Host, "host", "", "Provide a hostname to enable ingress and configure its hostname")
+	startCmd.Flags().BoolVar(&cmd.Route, "route", false, "If true, vCluster platform will be exposed via an OpenShift route with the hostname of --host instead of an ingress")
 	startCmd.Flags().StringVar(&cmd.Password, "password", "", "The password to use for the admin account. (If empty this will be the namespace UID)")
 	startCmd.Flags().StringVar(&cmd.Version, "version", "latest", "The vCluster platform version to install")
-	startCmd.Flags().StringVar(&cmd.Values, "values", "", "Path to a file for extra vCluster platform helm chart values")
+	startCmd.Flags().StringArrayVar(&cmd.Values, "values", []string{}, "Path to a file for extra vCluster platform helm chart values, can be specified multiple times and is merged in order")
+	startCmd.Flags().StringArrayVar(&cmd.SetValues, "set", []string{}, "Set values for the vCluster platform helm chart on top of the values files. E.g. --set 'replicaCount=2'")
 	startCmd.Flags().BoolVar(&cmd.ReuseValues, "reuse-values", true, "Reuse previous vCluster platform helm values on upgrade")
-	startCmd.Flags().BoolVar(&cmd.Upgrade, "upgrade", false, "If true, vCluster platform will try to upgrade the release")
+	startCmd.Flags().BoolVar(&cmd.Upgrade, "upgrade", false, "If true, vCluster platform will try to upgrade the release. Use vcluster platform upgrade to review the changes first and roll back failed upgrades")
 	startCmd.Flags().StringVar(&cmd.Email, "email", "", "The email to use for the installation")
 	startCmd.Flags().BoolVar(&cmd.Reset, "reset", false, "If true, an existing vCluster platform instance will be deleted before installing vCluster platform")
 	startCmd.Flags().BoolVar(&cmd.NoWait, "no-wait", false, "If true, vCluster platform will not wait after installing it")
 	startCmd.Flags().BoolVar(&cmd.NoPortForwarding, "no-port-forwarding", false, "If true, vCluster platform will not do port forwarding after installing it")
 	startCmd.Flags().BoolVar(&cmd.NoTunnel, "no-tunnel", false, "If true, vCluster platform will not create a loft.host tunnel for this installation")
+	startCmd.Flags().StringVar(&cmd.LicenseFile, "license-file", "", "The path to an offline license key to activate on install, implies --no-tunnel")
 	startCmd.Flags().BoolVar(&cmd.NoLogin, "no-login", false, "If true, vCluster platform will not login to a vCluster platform instance on start")
 	startCmd.Flags().StringVar(&cmd.ChartPath, "chart-path", "", "The vCluster platform chart path to deploy vCluster platform")
 	startCmd.Flags().StringVar(&cmd.ChartRepo, "chart-repo", "https://charts.loft.sh/", "The chart repo to deploy vCluster platform")
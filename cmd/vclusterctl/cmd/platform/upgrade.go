@@ -0,0 +1,68 @@
+package platform
+
+import (
+	"context"
+	"time"
+
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/loft-sh/vcluster/pkg/cli/start"
+	"github.com/spf13/cobra"
+)
+
+type UpgradeCmd struct {
+	start.Options
+}
+
+func NewUpgradeCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
+	cmd := &UpgradeCmd{
+		Options: start.Options{
+			GlobalFlags: globalFlags,
+			Log:         log.GetInstance(),
+		},
+	}
+
+	upgradeCmd := &cobra.Command{
+		Use:   "upgrade",
+		Short: "Upgrade a vCluster platform instance",
+		Long: `########################################################
+############ vcluster platform upgrade #################
+########################################################
+Upgrades the vCluster platform instance in the current kube
+context. Prints the current and target version and the
+changed helm values first, then upgrades the release and
+rolls it back to the previous revision if vCluster platform
+doesn't become ready.
+
+Upgrades across major versions and downgrades are rejected.
+
+Example:
+vcluster platform upgrade
+vcluster platform upgrade --version v4.1.0 --dry-run
+vcluster platform upgrade --values values.yaml --set replicaCount=2
+########################################################
+	`,
+		Args: cobra.NoArgs,
+		RunE: func(cobraCmd *cobra.Command, _ []string) error {
+			return cmd.Run(cobraCmd.Context())
+		},
+	}
+
+	upgradeCmd.Flags().StringVar(&cmd.Context, "context", "", "The kube context vCluster platform is installed in")
+	upgradeCmd.Flags().StringVar(&cmd.Namespace, "namespace", "vcluster-platform", "The namespace vCluster platform is installed in")
+	upgradeCmd.Flags().StringVar(&cmd.Version, "version", "latest", "The vCluster platform version to upgrade to")
+	upgradeCmd.Flags().StringArrayVar(&cmd.Values, "values", []string{}, "Path to a file for extra vCluster platform helm chart values, can be specified multiple times and is merged in order")
+	upgradeCmd.Flags().StringArrayVar(&cmd.SetValues, "set", []string{}, "Set values for the vCluster platform helm chart on top of the values files. E.g. --set 'replicaCount=2'")
+	upgradeCmd.Flags().BoolVar(&cmd.ReuseValues, "reuse-values", true, "Reuse the current vCluster platform helm values, if false the values are reset to the chart defaults")
+	upgradeCmd.Flags().BoolVar(&cmd.DryRun, "dry-run", false, "If true, only print the upgrade plan")
+	upgradeCmd.Flags().DurationVar(&cmd.UpgradeTimeout, "timeout", 10*time.Minute, "The time to wait for the upgraded vCluster platform to become ready before it is rolled back")
+	upgradeCmd.Flags().StringVar(&cmd.ChartPath, "chart-path", "", "The vCluster platform chart path to deploy vCluster platform")
+	upgradeCmd.Flags().StringVar(&cmd.ChartRepo, "chart-repo", "https://charts.loft.sh/", "The chart repo to deploy vCluster platform")
+	upgradeCmd.Flags().StringVar(&cmd.ChartName, "chart-name", "vcluster-platform", "The chart name to deploy vCluster platform")
+
+	return upgradeCmd
+}
+
+func (cmd *UpgradeCmd) Run(ctx context.Context) error {
+	return start.NewLoftStarter(cmd.Options).RunUpgrade(ctx)
+}
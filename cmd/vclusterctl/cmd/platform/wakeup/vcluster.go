@@ -5,6 +5,7 @@ import (
 
 	"github.com/loft-sh/log"
 	"github.com/loft-sh/vcluster/pkg/cli"
+	"github.com/loft-sh/vcluster/pkg/cli/completion"
 	"github.com/loft-sh/vcluster/pkg/cli/flags"
 	"github.com/loft-sh/vcluster/pkg/cli/util"
 	"github.com/spf13/cobra"
@@ -39,7 +40,8 @@ Example:
 vcluster platform wakeup vcluster test --namespace test
 #########################################################################
 	`,
-		Args: util.VClusterNameOnlyValidator,
+		Args:              util.VClusterNameOnlyValidator,
+		ValidArgsFunction: completion.NewValidPlatformVClusterNameFunc(globalFlags),
 		RunE: func(cobraCmd *cobra.Command, args []string) error {
 			return cmd.Run(cobraCmd.Context(), args)
 		},
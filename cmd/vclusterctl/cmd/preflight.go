@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/vcluster/pkg/cli"
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/loft-sh/vcluster/pkg/cli/util"
+	"github.com/spf13/cobra"
+)
+
+// PreflightCmd holds the preflight cmd flags
+type PreflightCmd struct {
+	*flags.GlobalFlags
+	cli.PreflightOptions
+
+	log log.Logger
+}
+
+// NewPreflightCmd creates a new command
+func NewPreflightCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
+	cmd := &PreflightCmd{
+		GlobalFlags: globalFlags,
+		log:         log.GetInstance(),
+	}
+
+	useLine, nameValidator := util.NamedPositionalArgsValidator(false, true, "VCLUSTER_NAME")
+
+	cobraCmd := &cobra.Command{
+		Use:   "preflight" + useLine,
+		Short: "Checks if the host cluster is ready for a virtual cluster",
+		Long: `#######################################################
+################## vcluster preflight #################
+#######################################################
+Checks the host cluster before a virtual cluster with the
+given values is created:
+- the RBAC permissions of the current user
+- the pod security restrictions of the namespace
+- the storage class of the backing store
+- the available node resources for the control plane
+- the API groups the enabled features need
+- network policies restricting the control plane egress
+
+The namespace is chosen like vcluster create does. The
+command exits with a non-zero exit code if a check
+failed, warnings don't fail the command.
+
+Example:
+vcluster preflight
+vcluster preflight my-vcluster -n my-namespace -f vcluster.yaml
+vcluster preflight my-vcluster --output json
+#######################################################
+	`,
+		Args: nameValidator,
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			vClusterName := "vcluster"
+			if len(args) > 0 {
+				vClusterName = args[0]
+			}
+
+			return cli.Preflight(cobraCmd.Context(), vClusterName, &cmd.PreflightOptions, cmd.GlobalFlags, cmd.log)
+		},
+	}
+
+	cobraCmd.Flags().StringArrayVarP(&cmd.Values, "values", "f", []string{}, "Path where to load the virtual cluster helm values from, use - to read them from stdin or pass an https:// or oci:// reference to download them")
+	cobraCmd.Flags().StringArrayVar(&cmd.SetValues, "set", []string{}, "Set values for the virtual cluster. E.g. --set 'persistence.enabled=true'")
+	cobraCmd.Flags().BoolVar(&cmd.ExpandEnv, "expand-env", false, "Substitute ${VAR} and ${VAR:-default} environment variables and execute [[ ]] templates in the values files")
+	cobraCmd.Flags().StringVarP(&cmd.Output, "output", "o", "table", "Choose the format of the output. [table|json]")
+	return cobraCmd
+}
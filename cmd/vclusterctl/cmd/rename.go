@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/vcluster/pkg/cli"
+	"github.com/loft-sh/vcluster/pkg/cli/completion"
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/loft-sh/vcluster/pkg/constants"
+	"github.com/spf13/cobra"
+)
+
+// RenameCmd holds the rename cmd flags
+type RenameCmd struct {
+	*flags.GlobalFlags
+	cli.RenameOptions
+
+	log log.Logger
+}
+
+// NewRenameCmd creates a new command
+func NewRenameCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
+	cmd := &RenameCmd{
+		GlobalFlags: globalFlags,
+		log:         log.GetInstance(),
+	}
+
+	cobraCmd := &cobra.Command{
+		Use:   "rename VCLUSTER_NAME NEW_NAME",
+		Short: "Renames a virtual cluster",
+		Long: `#######################################################
+################### vcluster rename ###################
+#######################################################
+Renames a virtual cluster within its namespace. The
+virtual cluster is paused, its persistent volumes and
+the volumes of synced persistent volume claims are bound
+to claims with the new name and the virtual cluster is
+deployed under the new name with the same config. The
+host objects are recreated with the new name and the
+kube context is renamed. If the virtual cluster can't be
+deployed under the new name, the volumes are bound to
+their original claims again and the virtual cluster is
+resumed under its old name.
+
+Virtual clusters with an etcd backing store or in multi
+namespace mode cannot be renamed.
+
+Example:
+vcluster rename my-vcluster new-vcluster -n my-namespace
+#######################################################
+	`,
+		Args:              cobra.ExactArgs(2),
+		ValidArgsFunction: completion.NewValidVClusterNameFunc(globalFlags),
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			return cli.Rename(cobraCmd.Context(), cmd.GlobalFlags, args[0], args[1], &cmd.RenameOptions, cmd.log)
+		},
+	}
+
+	cobraCmd.Flags().StringVar(&cmd.ChartRepo, "chart-repo", constants.LoftChartRepo, "The virtual cluster chart repo the virtual cluster was deployed from")
+	return cobraCmd
+}
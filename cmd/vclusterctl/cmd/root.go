@@ -10,12 +10,30 @@ import (
 	"github.com/mitchellh/go-homedir"
 
 	"github.com/loft-sh/log"
+	cmdcerts "github.com/loft-sh/vcluster/cmd/vclusterctl/cmd/certs"
+	"github.com/loft-sh/vcluster/cmd/vclusterctl/cmd/check"
+	cmdconfig "github.com/loft-sh/vcluster/cmd/vclusterctl/cmd/config"
 	"github.com/loft-sh/vcluster/cmd/vclusterctl/cmd/convert"
 	"github.com/loft-sh/vcluster/cmd/vclusterctl/cmd/credits"
+	"github.com/loft-sh/vcluster/cmd/vclusterctl/cmd/debug"
+	"github.com/loft-sh/vcluster/cmd/vclusterctl/cmd/density"
+	"github.com/loft-sh/vcluster/cmd/vclusterctl/cmd/dev"
+	"github.com/loft-sh/vcluster/cmd/vclusterctl/cmd/failover"
+	"github.com/loft-sh/vcluster/cmd/vclusterctl/cmd/get"
+	"github.com/loft-sh/vcluster/cmd/vclusterctl/cmd/images"
+	cmdkubeconfig "github.com/loft-sh/vcluster/cmd/vclusterctl/cmd/kubeconfig"
+	cmdoperator "github.com/loft-sh/vcluster/cmd/vclusterctl/cmd/operator"
 	cmdplatform "github.com/loft-sh/vcluster/cmd/vclusterctl/cmd/platform"
 	"github.com/loft-sh/vcluster/cmd/vclusterctl/cmd/platform/set"
+	"github.com/loft-sh/vcluster/cmd/vclusterctl/cmd/serve"
+	"github.com/loft-sh/vcluster/cmd/vclusterctl/cmd/shareddns"
+	"github.com/loft-sh/vcluster/cmd/vclusterctl/cmd/snapshot"
+	cmdsync "github.com/loft-sh/vcluster/cmd/vclusterctl/cmd/sync"
 	cmdtelemetry "github.com/loft-sh/vcluster/cmd/vclusterctl/cmd/telemetry"
 	"github.com/loft-sh/vcluster/cmd/vclusterctl/cmd/use"
+	"github.com/loft-sh/vcluster/cmd/vclusterctl/cmd/volumes"
+	"github.com/loft-sh/vcluster/pkg/cli/audit"
+	"github.com/loft-sh/vcluster/pkg/cli/cleanup"
 	"github.com/loft-sh/vcluster/pkg/cli/completion"
 	"github.com/loft-sh/vcluster/pkg/cli/config"
 	"github.com/loft-sh/vcluster/pkg/cli/flags"
@@ -74,8 +92,12 @@ func Execute() {
 		log.Fatalf("error building root: %+v\n", err)
 	}
 
-	// Execute command
-	err = rootCmd.ExecuteContext(context.Background())
+	// Execute command, temporary artifacts are cleaned up on exit or interrupt
+	stopSignalHandling := cleanup.HandleSignals(log)
+	executedCmd, err := rootCmd.ExecuteContextC(context.Background())
+	stopSignalHandling()
+	cleanup.Run(log)
+	recordAudit(executedCmd, err, log)
 	recordAndFlush(err, log)
 	if err != nil {
 		if globalFlags.Debug {
@@ -112,12 +134,37 @@ func BuildRoot(log log.Logger) (*cobra.Command, error) {
 	rootCmd.AddCommand(NewPauseCmd(globalFlags))
 	rootCmd.AddCommand(NewResumeCmd(globalFlags))
 	rootCmd.AddCommand(NewDisconnectCmd(globalFlags))
+	rootCmd.AddCommand(cmdkubeconfig.NewKubeConfigCmd(globalFlags))
+	rootCmd.AddCommand(NewStatusCmd(globalFlags))
 	rootCmd.AddCommand(NewUpgradeCmd())
 	rootCmd.AddCommand(use.NewUseCmd(globalFlags))
 	rootCmd.AddCommand(convert.NewConvertCmd(globalFlags))
+	rootCmd.AddCommand(failover.NewFailoverCmd(globalFlags))
+	rootCmd.AddCommand(cmdcerts.NewCertsCmd(globalFlags))
+	rootCmd.AddCommand(cmdsync.NewSyncCmd(globalFlags))
+	rootCmd.AddCommand(get.NewGetCmd(globalFlags))
+	rootCmd.AddCommand(cmdconfig.NewConfigCmd(globalFlags))
 	rootCmd.AddCommand(cmdtelemetry.NewTelemetryCmd(globalFlags))
 	rootCmd.AddCommand(versionCmd)
 	rootCmd.AddCommand(NewInfoCmd(globalFlags))
+	rootCmd.AddCommand(NewValidateCmd(globalFlags))
+	rootCmd.AddCommand(NewLintCmd(globalFlags))
+	rootCmd.AddCommand(NewPreflightCmd(globalFlags))
+	rootCmd.AddCommand(NewVerifyCmd(globalFlags))
+	rootCmd.AddCommand(NewGCCmd(globalFlags))
+	rootCmd.AddCommand(NewLoadTestCmd(globalFlags))
+	rootCmd.AddCommand(NewMoveCmd(globalFlags))
+	rootCmd.AddCommand(NewRenameCmd(globalFlags))
+	rootCmd.AddCommand(snapshot.NewSnapshotCmd(globalFlags))
+	rootCmd.AddCommand(check.NewCheckCmd(globalFlags))
+	rootCmd.AddCommand(debug.NewDebugCmd(globalFlags))
+	rootCmd.AddCommand(density.NewDensityCmd(globalFlags))
+	rootCmd.AddCommand(images.NewImagesCmd(globalFlags))
+	rootCmd.AddCommand(volumes.NewVolumesCmd(globalFlags))
+	rootCmd.AddCommand(cmdoperator.NewOperatorCmd(globalFlags))
+	rootCmd.AddCommand(shareddns.NewSharedDNSCmd(globalFlags))
+	rootCmd.AddCommand(serve.NewServeCmd(globalFlags))
+	rootCmd.AddCommand(dev.NewDevCmd(globalFlags))
 	rootCmd.AddCommand(set.NewSetCmd(globalFlags, defaults))
 
 	// add platform commands
@@ -147,14 +194,31 @@ func BuildRoot(log log.Logger) (*cobra.Command, error) {
 	rootCmd.AddCommand(credits.NewCreditsCmd())
 
 	// add completion command
-	err = rootCmd.RegisterFlagCompletionFunc("namespace", completion.NewNamespaceCompletionFunc(rootCmd.Context()))
+	err = rootCmd.RegisterFlagCompletionFunc("namespace", completion.NewNamespaceCompletionFunc(globalFlags))
 	if err != nil {
 		return rootCmd, fmt.Errorf("failed to register completion for namespace: %w", err)
 	}
+	err = completion.RegisterPlatformFlagCompletions(rootCmd, globalFlags)
+	if err != nil {
+		return rootCmd, fmt.Errorf("failed to register completion for platform flags: %w", err)
+	}
 
 	return rootCmd, nil
 }
 
+// recordAudit records mutating platform commands in the audit log
+func recordAudit(executedCmd *cobra.Command, err error, log log.Logger) {
+	cliConfig := globalFlags.LoadedConfig(log)
+	if executedCmd == nil || cliConfig.Audit.Disabled || !audit.IsMutating(executedCmd, cliConfig.Driver.Type) {
+		return
+	}
+
+	auditErr := audit.Record(context.Background(), cliConfig, audit.NewEvent(executedCmd, cliConfig, platform.Self, err))
+	if auditErr != nil {
+		log.Warnf("Error recording audit event: %v", auditErr)
+	}
+}
+
 func recordAndFlush(err error, log log.Logger) {
 	telemetry.CollectorCLI.RecordCLI(globalFlags.LoadedConfig(log), platform.Self, err)
 	telemetry.CollectorCLI.Flush()
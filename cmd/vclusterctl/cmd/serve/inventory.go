@@ -0,0 +1,68 @@
+package serve
+
+import (
+	"context"
+	"os"
+
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/vcluster/pkg/cli/find"
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/loft-sh/vcluster/pkg/inventory"
+	"github.com/spf13/cobra"
+)
+
+// TokenEnv is read if no token is passed via --token
+const TokenEnv = "VCLUSTER_INVENTORY_TOKEN"
+
+type InventoryCmd struct {
+	*flags.GlobalFlags
+	inventory.Options
+
+	log log.Logger
+}
+
+func newInventoryCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
+	cmd := &InventoryCmd{
+		GlobalFlags: globalFlags,
+		log:         log.GetInstance(),
+	}
+
+	cobraCmd := &cobra.Command{
+		Use:   "inventory",
+		Short: "Serves a read-only json api of all virtual clusters",
+		Long: `#######################################################
+############### vcluster serve inventory ##############
+#######################################################
+Serves a read-only json api of all virtual clusters that
+are visible within the current kube context. Clients
+authenticate with the token as bearer token, the token
+is read from the ` + TokenEnv + `
+environment variable if --token is not set.
+
+Endpoints:
+GET /api/v1/vclusters
+GET /api/v1/vclusters/<namespace>/<name>
+GET /healthz
+
+Example:
+vcluster serve inventory --token my-token
+vcluster serve inventory --namespace team-a --cache-ttl 1m
+#######################################################
+	`,
+		Args: cobra.NoArgs,
+		RunE: func(cobraCmd *cobra.Command, _ []string) error {
+			if cmd.Token == "" {
+				cmd.Token = os.Getenv(TokenEnv)
+			}
+
+			return inventory.Start(cobraCmd.Context(), func(ctx context.Context) ([]find.VCluster, error) {
+				return find.ListOSSVClusters(ctx, cmd.Context, "", cmd.Namespace)
+			}, &cmd.Options, cmd.log)
+		},
+	}
+
+	cobraCmd.Flags().StringVar(&cmd.Address, "address", ":8080", "The address the inventory api binds to")
+	cobraCmd.Flags().StringVar(&cmd.Token, "token", "", "The bearer token clients need to send")
+	cobraCmd.Flags().DurationVar(&cmd.CacheTTL, "cache-ttl", inventory.DefaultCacheTTL, "The time the list of virtual clusters is cached for")
+	return cobraCmd
+}
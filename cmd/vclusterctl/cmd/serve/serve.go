@@ -0,0 +1,24 @@
+package serve
+
+import (
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/spf13/cobra"
+)
+
+func NewServeCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
+	serveCmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Serves apis about virtual clusters",
+		Long: `#######################################################
+#################### vcluster serve ###################
+#######################################################
+Serves apis about the virtual clusters of the current
+kube context, e.g. for internal developer portals.
+#######################################################
+	`,
+		Args: cobra.NoArgs,
+	}
+
+	serveCmd.AddCommand(newInventoryCmd(globalFlags))
+	return serveCmd
+}
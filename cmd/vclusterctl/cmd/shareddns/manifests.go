@@ -0,0 +1,70 @@
+package shareddns
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/loft-sh/vcluster/pkg/coredns"
+	"github.com/loft-sh/vcluster/pkg/upgrade"
+	"github.com/spf13/cobra"
+)
+
+type ManifestsCmd struct {
+	*flags.GlobalFlags
+	coredns.SharedDNSManifestOptions
+}
+
+func manifests(globalFlags *flags.GlobalFlags) *cobra.Command {
+	cmd := &ManifestsCmd{
+		GlobalFlags: globalFlags,
+	}
+
+	cobraCmd := &cobra.Command{
+		Use:   "manifests",
+		Short: "Prints the manifests that install the shared CoreDNS",
+		Long: `#######################################################
+############ vcluster shared-dns manifests ############
+#######################################################
+Prints the service account, role, ports config map and
+deployment of the shared CoreDNS. The namespace needs to
+exist already and must match
+networking.advanced.sharedDNS.namespace of the virtual
+clusters.
+
+Virtual clusters register themselves with a secret that
+only the shared CoreDNS can read. It holds a token that
+can only read the services, endpoints and namespaces of
+the virtual cluster.
+
+Example:
+vcluster shared-dns manifests | kubectl apply -f -
+vcluster shared-dns manifests -n dns --replicas 3 | kubectl apply -f -
+#######################################################
+	`,
+		Args: cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			cmd.SharedDNSManifestOptions.Namespace = cmd.GlobalFlags.Namespace
+			if cmd.SharedDNSManifestOptions.Namespace == "" {
+				cmd.SharedDNSManifestOptions.Namespace = coredns.SharedDNSName
+			}
+
+			return cmd.Run()
+		},
+	}
+
+	cobraCmd.Flags().StringVar(&cmd.Image, "image", coredns.DefaultImage, "The CoreDNS image")
+	cobraCmd.Flags().StringVar(&cmd.CLIImage, "cli-image", "ghcr.io/loft-sh/vcluster-cli:"+upgrade.GetVersion(), "The vcluster cli image that writes the tenants into the Corefile")
+	cobraCmd.Flags().IntVar(&cmd.Replicas, "replicas", 2, "The number of shared CoreDNS replicas")
+	return cobraCmd
+}
+
+func (cmd *ManifestsCmd) Run() error {
+	out, err := coredns.SharedDNSManifests(&cmd.SharedDNSManifestOptions)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprint(os.Stdout, string(out))
+	return err
+}
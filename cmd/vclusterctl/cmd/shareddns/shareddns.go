@@ -0,0 +1,25 @@
+package shareddns
+
+import (
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/spf13/cobra"
+)
+
+func NewSharedDNSCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
+	sharedDNSCmd := &cobra.Command{
+		Use:   "shared-dns",
+		Short: "Shared CoreDNS subcommands",
+		Long: `#######################################################
+################# vcluster shared-dns #################
+#######################################################
+Manages the CoreDNS that is shared between the virtual
+clusters with networking.advanced.sharedDNS.enabled.
+#######################################################
+	`,
+		Args: cobra.NoArgs,
+	}
+
+	sharedDNSCmd.AddCommand(start(globalFlags))
+	sharedDNSCmd.AddCommand(manifests(globalFlags))
+	return sharedDNSCmd
+}
@@ -0,0 +1,72 @@
+package shareddns
+
+import (
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/loft-sh/vcluster/pkg/coredns"
+	"github.com/loft-sh/vcluster/pkg/util/clienthelper"
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+type StartCmd struct {
+	*flags.GlobalFlags
+
+	ConfigDir string
+	Once      bool
+
+	log log.Logger
+}
+
+func start(globalFlags *flags.GlobalFlags) *cobra.Command {
+	cmd := &StartCmd{
+		GlobalFlags: globalFlags,
+		log:         log.GetInstance(),
+	}
+
+	cobraCmd := &cobra.Command{
+		Use:   "start",
+		Short: "Writes the tenants of the shared CoreDNS into its Corefile",
+		Long: `#######################################################
+############## vcluster shared-dns start ##############
+#######################################################
+Watches the tenant secrets within the namespace of the
+shared CoreDNS and writes the Corefile and the kube
+configs of the tenants into the config directory. This
+runs next to CoreDNS in the shared CoreDNS pods.
+
+Use vcluster shared-dns manifests to install the shared
+CoreDNS.
+#######################################################
+	`,
+		Args: cobra.NoArgs,
+		RunE: func(cobraCmd *cobra.Command, _ []string) error {
+			restConfig, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(clientcmd.NewDefaultClientConfigLoadingRules(), &clientcmd.ConfigOverrides{
+				CurrentContext: cmd.Context,
+			}).ClientConfig()
+			if err != nil {
+				return err
+			}
+
+			kubeClient, err := kubernetes.NewForConfig(restConfig)
+			if err != nil {
+				return err
+			}
+
+			namespace := cmd.Namespace
+			if namespace == "" {
+				namespace, err = clienthelper.CurrentNamespace()
+				if err != nil {
+					return err
+				}
+			}
+
+			return coredns.RunSharedDNSTenants(cobraCmd.Context(), kubeClient, namespace, cmd.ConfigDir, cmd.Once, cmd.log)
+		},
+	}
+
+	cobraCmd.Flags().StringVar(&cmd.ConfigDir, "config-dir", coredns.SharedDNSConfigPath, "The directory the Corefile and the kube configs of the tenants are written to")
+	cobraCmd.Flags().BoolVar(&cmd.Once, "once", false, "If enabled, the Corefile is written once instead of watching the tenants")
+	return cobraCmd
+}
@@ -0,0 +1,89 @@
+package snapshot
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/log/table"
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/loft-sh/vcluster/pkg/platform/backup"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/duration"
+)
+
+// ListCmd holds the cmd flags
+type ListCmd struct {
+	*flags.GlobalFlags
+	Log      log.Logger
+	Selector string
+}
+
+// newListCmd creates a new command for listing snapshots
+func newListCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
+	cmd := &ListCmd{
+		GlobalFlags: globalFlags,
+		Log:         log.GetInstance(),
+	}
+
+	c := &cobra.Command{
+		Use:   "list [directory]",
+		Short: "Lists the snapshots in a local directory",
+		Long: `#######################################################
+################ vcluster snapshot list ###############
+#######################################################
+Lists the snapshots written by vcluster snapshot in a
+local directory together with their labels, the oldest
+snapshot first. Use --selector to only list the
+snapshots with matching labels.
+
+Example:
+vcluster snapshot list snapshots
+vcluster snapshot list snapshots --selector purpose=pre-upgrade
+#######################################################
+	`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			return cmd.run(args[0])
+		},
+	}
+
+	c.Flags().StringVarP(&cmd.Selector, "selector", "l", "", "Only list snapshots with labels that match the selector. E.g. --selector purpose=pre-upgrade")
+	return c
+}
+
+// run executes the functionality
+func (cmd *ListCmd) run(location string) error {
+	selector, err := labels.Parse(cmd.Selector)
+	if err != nil {
+		return fmt.Errorf("parse selector: %w", err)
+	}
+
+	entries, err := backup.List(location, selector)
+	if err != nil {
+		return err
+	}
+
+	header := []string{
+		"Location",
+		"Labels",
+		"Age",
+	}
+	values := [][]string{}
+	for _, entry := range entries {
+		age := ""
+		if !entry.Created.IsZero() {
+			age = duration.HumanDuration(time.Since(entry.Created))
+		}
+
+		values = append(values, []string{
+			entry.Location,
+			backup.FormatLabels(entry.Labels),
+			age,
+		})
+	}
+
+	table.PrintTable(cmd.Log, header, values)
+	return nil
+}
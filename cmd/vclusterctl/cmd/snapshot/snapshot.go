@@ -0,0 +1,70 @@
+package snapshot
+
+import (
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/vcluster/pkg/cli"
+	"github.com/loft-sh/vcluster/pkg/cli/completion"
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/loft-sh/vcluster/pkg/cli/util"
+	"github.com/loft-sh/vcluster/pkg/platform/backup"
+	"github.com/spf13/cobra"
+)
+
+// SnapshotCmd holds the snapshot cmd flags
+type SnapshotCmd struct {
+	*flags.GlobalFlags
+	cli.SnapshotOptions
+
+	Labels []string
+
+	log log.Logger
+}
+
+// NewSnapshotCmd creates a new command
+func NewSnapshotCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
+	cmd := &SnapshotCmd{
+		GlobalFlags: globalFlags,
+		log:         log.GetInstance(),
+	}
+
+	useLine, validator := util.NamedPositionalArgsValidator(true, true, "VCLUSTER_NAME", "DESTINATION")
+	cobraCmd := &cobra.Command{
+		Use:   "snapshot" + useLine,
+		Short: "Takes a snapshot of a virtual cluster",
+		Long: `#######################################################
+################### vcluster snapshot #################
+#######################################################
+Takes a snapshot of the objects of a virtual cluster and
+writes it as compressed archive to a local path, a
+file:// url or uploads it to a presigned http(s):// object
+store url. Objects managed by controllers are left out,
+the data of persistent volumes is not part of the
+snapshot.
+
+Snapshots can be labeled with --label, e.g. with their
+purpose or the git ref they were taken at, and the
+snapshots in a local directory are listed by their
+labels with vcluster snapshot list.
+
+Example:
+vcluster snapshot my-vcluster snapshots/pre-upgrade.tar.gz --label purpose=pre-upgrade --label git=abc123
+vcluster snapshot my-vcluster "https://my-bucket.s3.amazonaws.com/snapshot.tar.gz?X-Amz-Signature=..."
+#######################################################
+	`,
+		Args:              validator,
+		ValidArgsFunction: completion.NewValidVClusterNameFunc(globalFlags),
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			labels, err := backup.ParseLabels(cmd.Labels)
+			if err != nil {
+				return err
+			}
+			cmd.SnapshotOptions.Labels = labels
+
+			return cli.Snapshot(cobraCmd.Context(), cmd.GlobalFlags, args[0], args[1], &cmd.SnapshotOptions, cmd.log)
+		},
+	}
+
+	cobraCmd.Flags().StringArrayVar(&cmd.Labels, "label", []string{}, "Label to store in the metadata of the snapshot. E.g. --label purpose=pre-upgrade")
+	cobraCmd.AddCommand(newListCmd(globalFlags))
+	return cobraCmd
+}
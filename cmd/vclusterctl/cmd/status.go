@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"os"
+	"time"
+
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/vcluster/pkg/cli"
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/spf13/cobra"
+)
+
+// StatusCmd holds the status cmd flags
+type StatusCmd struct {
+	*flags.GlobalFlags
+	cli.StatusOptions
+
+	log log.Logger
+}
+
+// NewStatusCmd creates a new command
+func NewStatusCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
+	cmd := &StatusCmd{
+		GlobalFlags: globalFlags,
+		log:         log.GetInstance(),
+	}
+
+	cobraCmd := &cobra.Command{
+		Use:   "status",
+		Short: "Shows the virtual cluster of the current kube context and whether it is sleeping",
+		Long: `#######################################################
+################### vcluster status ###################
+#######################################################
+Shows the virtual cluster of the current kube context and
+whether it is awake or sleeping.
+
+The state is cached by kube context for --cache-ttl, so
+vcluster status --short can be used in a shell prompt
+without calling the cluster on every render. It prints
+nothing if the current context doesn't belong to a
+virtual cluster.
+
+Example:
+vcluster status
+vcluster status --short
+vcluster status --short --watch
+# bash prompt
+PS1='$(vcluster status --short --silent) \$ '
+#######################################################
+	`,
+		Args: cobra.NoArgs,
+		RunE: func(cobraCmd *cobra.Command, _ []string) error {
+			return cli.Status(cobraCmd.Context(), &cmd.StatusOptions, cmd.GlobalFlags, os.Stdout, cmd.log)
+		},
+	}
+
+	cobraCmd.Flags().BoolVar(&cmd.Short, "short", false, "Print only the name and state of the virtual cluster, e.g. for a shell prompt")
+	cobraCmd.Flags().BoolVar(&cmd.Watch, "watch", false, "Print the status again whenever it changes")
+	cobraCmd.Flags().DurationVar(&cmd.Interval, "interval", 5*time.Second, "How often to check the status in watch mode")
+	cobraCmd.Flags().DurationVar(&cmd.CacheTTL, "cache-ttl", 30*time.Second, "How long a cached state is used before it is checked again")
+	cobraCmd.Flags().DurationVar(&cmd.Timeout, "timeout", 2*time.Second, "How long to wait for the state before the cached or an unknown state is printed")
+	return cobraCmd
+}
@@ -0,0 +1,49 @@
+package sync
+
+import (
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/vcluster/pkg/cli"
+	"github.com/loft-sh/vcluster/pkg/cli/completion"
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/loft-sh/vcluster/pkg/cli/util"
+	"github.com/spf13/cobra"
+)
+
+type pauseCmd struct {
+	*flags.GlobalFlags
+	cli.SyncPauseOptions
+
+	log log.Logger
+}
+
+func newPauseCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
+	cmd := &pauseCmd{
+		GlobalFlags: globalFlags,
+		log:         log.GetInstance(),
+	}
+
+	cobraCmd := &cobra.Command{
+		Use:   "pause" + util.VClusterNameOnlyUseLine,
+		Short: "Pauses the syncing of resources",
+		Long: `#######################################################
+################# vcluster sync pause #################
+#######################################################
+Pauses the syncers of the given resources while the
+virtual cluster keeps running. Changes to paused
+resources are synced once they are resumed with
+'vcluster sync resume'.
+
+Example:
+vcluster sync pause my-vcluster -n my-namespace --resource pods,services
+#######################################################
+	`,
+		Args:              util.VClusterNameOnlyValidator,
+		ValidArgsFunction: completion.NewValidVClusterNameFunc(globalFlags),
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			return cli.SyncPause(cobraCmd.Context(), cmd.GlobalFlags, args[0], &cmd.SyncPauseOptions, cmd.log)
+		},
+	}
+
+	cobraCmd.Flags().StringSliceVar(&cmd.Resources, "resource", []string{}, "The resources to pause, e.g. pods,services")
+	return cobraCmd
+}
@@ -0,0 +1,48 @@
+package sync
+
+import (
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/vcluster/pkg/cli"
+	"github.com/loft-sh/vcluster/pkg/cli/completion"
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/loft-sh/vcluster/pkg/cli/util"
+	"github.com/spf13/cobra"
+)
+
+type resumeCmd struct {
+	*flags.GlobalFlags
+	cli.SyncPauseOptions
+
+	log log.Logger
+}
+
+func newResumeCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
+	cmd := &resumeCmd{
+		GlobalFlags: globalFlags,
+		log:         log.GetInstance(),
+	}
+
+	cobraCmd := &cobra.Command{
+		Use:   "resume" + util.VClusterNameOnlyUseLine,
+		Short: "Resumes the syncing of paused resources",
+		Long: `#######################################################
+################ vcluster sync resume #################
+#######################################################
+Resumes the syncers of the given resources that were
+paused with 'vcluster sync pause'. Without --resource
+all paused resources are resumed.
+
+Example:
+vcluster sync resume my-vcluster -n my-namespace --resource pods
+#######################################################
+	`,
+		Args:              util.VClusterNameOnlyValidator,
+		ValidArgsFunction: completion.NewValidVClusterNameFunc(globalFlags),
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			return cli.SyncResume(cobraCmd.Context(), cmd.GlobalFlags, args[0], &cmd.SyncPauseOptions, cmd.log)
+		},
+	}
+
+	cobraCmd.Flags().StringSliceVar(&cmd.Resources, "resource", []string{}, "The resources to resume, defaults to all paused resources")
+	return cobraCmd
+}
@@ -0,0 +1,25 @@
+package sync
+
+import (
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/spf13/cobra"
+)
+
+func NewSyncCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
+	syncCmd := &cobra.Command{
+		Use:   "sync",
+		Short: "Manage the syncer of a virtual cluster",
+		Long: `#######################################################
+#################### vcluster sync ####################
+#######################################################
+Pause and resume the syncing of single resources at
+runtime without scaling down the virtual cluster.
+#######################################################
+	`,
+		Args: cobra.NoArgs,
+	}
+
+	syncCmd.AddCommand(newPauseCmd(globalFlags))
+	syncCmd.AddCommand(newResumeCmd(globalFlags))
+	return syncCmd
+}
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/vcluster/pkg/cli"
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/spf13/cobra"
+)
+
+// ValidateCmd holds the validate cmd flags
+type ValidateCmd struct {
+	*flags.GlobalFlags
+	cli.ValidateOptions
+
+	log log.Logger
+}
+
+// NewValidateCmd creates a new command
+func NewValidateCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
+	cmd := &ValidateCmd{
+		GlobalFlags: globalFlags,
+		log:         log.GetInstance(),
+	}
+
+	cobraCmd := &cobra.Command{
+		Use:   "validate",
+		Short: "Validates a vcluster.yaml against the config schema",
+		Long: `#######################################################
+################## vcluster validate ##################
+#######################################################
+Validates a vcluster.yaml against the config schema of
+the given chart version without contacting any cluster.
+Unknown fields and values of the wrong type are
+reported with their path.
+
+The schema of this vCluster version is embedded, the
+schemas of other chart versions are downloaded.
+
+Example:
+vcluster validate -f vcluster.yaml
+vcluster validate -f vcluster.yaml --chart-version v0.20.0
+#######################################################
+	`,
+		Args: cobra.NoArgs,
+		RunE: func(cobraCmd *cobra.Command, _ []string) error {
+			return cli.Validate(cobraCmd.Context(), &cmd.ValidateOptions, cmd.log)
+		},
+	}
+
+	cobraCmd.Flags().StringArrayVarP(&cmd.Values, "values", "f", []string{}, "Path where to load the virtual cluster helm values from, use - to read them from stdin or pass an https:// or oci:// reference to download them")
+	cobraCmd.Flags().StringArrayVar(&cmd.SetValues, "set", []string{}, "Set values for the virtual cluster. E.g. --set 'persistence.enabled=true'")
+	cobraCmd.Flags().BoolVar(&cmd.ExpandEnv, "expand-env", false, "Substitute ${VAR} and ${VAR:-default} environment variables and execute [[ ]] templates in the values files")
+	cobraCmd.Flags().StringVar(&cmd.ChartVersion, "chart-version", "", "The virtual cluster chart version to validate against. Defaults to the version of this binary")
+	return cobraCmd
+}
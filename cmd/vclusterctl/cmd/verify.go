@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"time"
+
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/vcluster/pkg/cli"
+	"github.com/loft-sh/vcluster/pkg/cli/completion"
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/loft-sh/vcluster/pkg/cli/util"
+	"github.com/spf13/cobra"
+)
+
+// VerifyCmd holds the verify cmd flags
+type VerifyCmd struct {
+	*flags.GlobalFlags
+	cli.VerifyOptions
+
+	log log.Logger
+}
+
+// NewVerifyCmd creates a new command
+func NewVerifyCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
+	cmd := &VerifyCmd{
+		GlobalFlags: globalFlags,
+		log:         log.GetInstance(),
+	}
+
+	cobraCmd := &cobra.Command{
+		Use:   "verify" + util.VClusterNameOnlyUseLine,
+		Short: "Runs a smoke test against a virtual cluster",
+		Long: `#######################################################
+################### vcluster verify ###################
+#######################################################
+Deploys a probe pod and service into a temporary
+namespace of the virtual cluster and checks:
+- the API server responds
+- the pod is scheduled on a host node
+- DNS resolves the service name
+- the service is reachable from another pod
+
+The namespace is deleted afterwards. vcluster create runs
+these checks after creating a virtual cluster if --verify
+is set.
+
+Example:
+vcluster verify my-vcluster -n my-namespace
+vcluster verify my-vcluster -n my-namespace --output json
+#######################################################
+	`,
+		Args:              util.VClusterNameOnlyValidator,
+		ValidArgsFunction: completion.NewValidVClusterNameFunc(globalFlags),
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			return cli.Verify(cobraCmd.Context(), &cmd.VerifyOptions, cmd.GlobalFlags, args[0], cmd.log)
+		},
+	}
+
+	cobraCmd.Flags().StringVarP(&cmd.Output, "output", "o", "table", "Choose the format of the output. [table|json]")
+	cobraCmd.Flags().DurationVar(&cmd.Timeout, "timeout", 2*time.Minute, "How long a single check may take")
+	cobraCmd.Flags().StringVar(&cmd.ServerImage, "server-image", cli.DefaultVerifyServerImage, "The image of the probe server pod, needs to serve http on port 8080")
+	cobraCmd.Flags().StringVar(&cmd.ClientImage, "client-image", cli.DefaultVerifyClientImage, "The image of the probe client pods, needs to provide nslookup and wget")
+	return cobraCmd
+}
@@ -0,0 +1,58 @@
+package volumes
+
+import (
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/vcluster/pkg/cli"
+	"github.com/loft-sh/vcluster/pkg/cli/completion"
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/loft-sh/vcluster/pkg/cli/util"
+	"github.com/spf13/cobra"
+)
+
+type exportCmd struct {
+	*flags.GlobalFlags
+	cli.VolumesExportOptions
+
+	log log.Logger
+}
+
+func newExportCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
+	cmd := &exportCmd{
+		GlobalFlags: globalFlags,
+		log:         log.GetInstance(),
+	}
+
+	cobraCmd := &cobra.Command{
+		Use:   "export" + util.VClusterNameOnlyUseLine,
+		Short: "Exports the host volumes of a virtual cluster",
+		Long: `#######################################################
+############### vcluster volumes export ###############
+#######################################################
+Writes the host persistent volumes that are bound to the
+persistent volume claims of the virtual cluster to a
+file. Export the volumes together with a backup of the
+virtual cluster and use vcluster volumes import after
+restoring it to another host cluster.
+
+The reclaim policy of the exported volumes is changed to
+Retain, so deleting the virtual cluster doesn't delete
+their data before they were imported. Stop the workloads
+of the virtual cluster before importing the volumes, as
+the same volume must not be attached by both host
+clusters at the same time.
+
+Example:
+vcluster volumes export my-vcluster --namespace vcluster-my-vcluster -f volumes.yaml
+#######################################################
+	`,
+		Args:              util.VClusterNameOnlyValidator,
+		ValidArgsFunction: completion.NewValidVClusterNameFunc(globalFlags),
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			return cli.VolumesExport(cobraCmd.Context(), &cmd.VolumesExportOptions, cmd.GlobalFlags, args[0], cmd.log)
+		},
+	}
+
+	cobraCmd.Flags().StringVarP(&cmd.File, "file", "f", "", "The file to write the volumes to, prints them if empty")
+	cobraCmd.Flags().BoolVar(&cmd.Retain, "retain", true, "If true, changes the reclaim policy of the exported volumes to Retain. Otherwise volumes with another reclaim policy are only reported")
+	return cobraCmd
+}
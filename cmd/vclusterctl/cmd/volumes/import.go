@@ -0,0 +1,60 @@
+package volumes
+
+import (
+	"fmt"
+
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/vcluster/pkg/cli"
+	"github.com/loft-sh/vcluster/pkg/cli/completion"
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/loft-sh/vcluster/pkg/cli/util"
+	"github.com/spf13/cobra"
+)
+
+type importCmd struct {
+	*flags.GlobalFlags
+	cli.VolumesImportOptions
+
+	log log.Logger
+}
+
+func newImportCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
+	cmd := &importCmd{
+		GlobalFlags: globalFlags,
+		log:         log.GetInstance(),
+	}
+
+	cobraCmd := &cobra.Command{
+		Use:   "import" + util.VClusterNameOnlyUseLine,
+		Short: "Recreates exported host volumes for a restored virtual cluster",
+		Long: `#######################################################
+############### vcluster volumes import ###############
+#######################################################
+Recreates the host persistent volumes written by vcluster
+volumes export as static volumes for the persistent
+volume claims of a restored virtual cluster, so stateful
+workloads reconnect to their data. Each volume is
+reserved for the host claim of its virtual claim and
+created with the reclaim policy Retain. The underlying
+storage, e.g. the disk of a csi volume, must be reachable
+from the new host cluster.
+
+Example:
+vcluster volumes import my-vcluster --namespace vcluster-my-vcluster -f volumes.yaml
+#######################################################
+	`,
+		Args:              util.VClusterNameOnlyValidator,
+		ValidArgsFunction: completion.NewValidVClusterNameFunc(globalFlags),
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			if cmd.File == "" {
+				return fmt.Errorf("please specify the exported volumes with --file")
+			}
+
+			return cli.VolumesImport(cobraCmd.Context(), &cmd.VolumesImportOptions, cmd.GlobalFlags, args[0], cmd.log)
+		},
+	}
+
+	cobraCmd.Flags().StringVarP(&cmd.File, "file", "f", "", "The file written by vcluster volumes export")
+	cobraCmd.Flags().BoolVar(&cmd.DryRun, "dry-run", false, "Only print the host persistent volumes that would be created")
+	return cobraCmd
+}
@@ -0,0 +1,66 @@
+package volumes
+
+import (
+	"time"
+
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/vcluster/pkg/cli"
+	"github.com/loft-sh/vcluster/pkg/cli/completion"
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/loft-sh/vcluster/pkg/cli/util"
+	"github.com/spf13/cobra"
+)
+
+type migrateCmd struct {
+	*flags.GlobalFlags
+	cli.VolumesMigrateOptions
+
+	log log.Logger
+}
+
+func newMigrateCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
+	cmd := &migrateCmd{
+		GlobalFlags: globalFlags,
+		log:         log.GetInstance(),
+	}
+
+	cobraCmd := &cobra.Command{
+		Use:   "migrate" + util.VClusterNameOnlyUseLine,
+		Short: "Migrates persistent volume claims to another storage class",
+		Long: `#######################################################
+############### vcluster volumes migrate ##############
+#######################################################
+Moves the data of the persistent volume claims of a
+storage class within the virtual cluster to new volumes
+of another storage class, e.g. when the host cluster
+deprecates a storage class.
+
+The data is copied by a pod or restored from a csi volume
+snapshot (--method snapshot) into a temporary claim.
+Afterwards the claim is recreated with the same name and
+bound to the new volume and the old volume is deleted
+according to its reclaim policy. The claims must not be
+used by pods during the migration.
+
+Example:
+vcluster volumes migrate my-vcluster --namespace vcluster-my-vcluster --from-class gp2 --to-class gp3
+vcluster volumes migrate my-vcluster --namespace vcluster-my-vcluster --from-class gp2 --to-class gp3 --pvc default/data-postgres-0 --method snapshot
+#######################################################
+	`,
+		Args:              util.VClusterNameOnlyValidator,
+		ValidArgsFunction: completion.NewValidVClusterNameFunc(globalFlags),
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			return cli.VolumesMigrate(cobraCmd.Context(), &cmd.VolumesMigrateOptions, cmd.GlobalFlags, args[0], cmd.log)
+		},
+	}
+
+	cobraCmd.Flags().StringVar(&cmd.FromClass, "from-class", "", "The storage class of the persistent volume claims to migrate")
+	cobraCmd.Flags().StringVar(&cmd.ToClass, "to-class", "", "The storage class to migrate the persistent volume claims to")
+	cobraCmd.Flags().StringSliceVar(&cmd.PersistentVolumeClaims, "pvc", []string{}, "Only migrate these persistent volume claims in the format namespace/name")
+	cobraCmd.Flags().StringVar(&cmd.Method, "method", cli.VolumesMigrateMethodCopy, "How to move the data. [copy|snapshot]")
+	cobraCmd.Flags().StringVar(&cmd.SnapshotClass, "snapshot-class", "", "The volume snapshot class to use with --method snapshot")
+	cobraCmd.Flags().StringVar(&cmd.Image, "image", cli.DefaultVolumesMigrateImage, "The image of the migration pod, needs to provide cp")
+	cobraCmd.Flags().DurationVar(&cmd.Timeout, "timeout", 10*time.Minute, "How long a single step of the migration of a claim may take")
+	cobraCmd.Flags().BoolVar(&cmd.DryRun, "dry-run", false, "Only print the persistent volume claims that would be migrated")
+	return cobraCmd
+}
@@ -0,0 +1,25 @@
+package volumes
+
+import (
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/spf13/cobra"
+)
+
+func NewVolumesCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
+	volumesCmd := &cobra.Command{
+		Use:   "volumes",
+		Short: "Manage the volumes of a virtual cluster",
+		Long: `#######################################################
+################### vcluster volumes ##################
+#######################################################
+Manage the volumes of a virtual cluster
+#######################################################
+	`,
+		Args: cobra.NoArgs,
+	}
+
+	volumesCmd.AddCommand(newMigrateCmd(globalFlags))
+	volumesCmd.AddCommand(newExportCmd(globalFlags))
+	volumesCmd.AddCommand(newImportCmd(globalFlags))
+	return volumesCmd
+}
@@ -103,6 +103,28 @@ controlPlane:
 	}
 }
 
+func TestPodSecurityStandard_UnmarshalJSON(t *testing.T) {
+	c := &Config{}
+	err := c.UnmarshalYAMLStrict([]byte(`
+policies:
+  podSecurityStandard: baseline
+`))
+	assert.NilError(t, err)
+	assert.DeepEqual(t, c.Policies.PodSecurityStandard, PodSecurityStandard{Level: "baseline"})
+
+	c = &Config{}
+	err = c.UnmarshalYAMLStrict([]byte(`
+policies:
+  podSecurityStandard:
+    level: baseline
+    enabled: true
+    defaults:
+      warn: restricted
+`))
+	assert.NilError(t, err)
+	assert.DeepEqual(t, c.Policies.PodSecurityStandard, PodSecurityStandard{Level: "baseline", Enabled: true, Defaults: PodSecurityStandardDefaults{Warn: "restricted"}})
+}
+
 func TestConfig_IsProFeatureEnabled(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -329,3 +351,70 @@ func TestConfig_IsProFeatureEnabled(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateIsolatedControlPlane(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  ExperimentalIsolatedControlPlane
+		wantErr bool
+	}{
+		{
+			name:   "Disabled",
+			config: ExperimentalIsolatedControlPlane{},
+		},
+		{
+			name:   "Headless",
+			config: ExperimentalIsolatedControlPlane{Headless: true},
+		},
+		{
+			name:   "Control plane",
+			config: ExperimentalIsolatedControlPlane{Enabled: true, KubeConfig: "/data/workload/kubeconfig.yaml", Namespace: "workloads"},
+		},
+		{
+			name:    "Headless and control plane",
+			config:  ExperimentalIsolatedControlPlane{Enabled: true, Headless: true, KubeConfig: "/data/workload/kubeconfig.yaml"},
+			wantErr: true,
+		},
+		{
+			name:    "Control plane without kube config",
+			config:  ExperimentalIsolatedControlPlane{Enabled: true},
+			wantErr: true,
+		},
+		{
+			name:    "Kube config without control plane",
+			config:  ExperimentalIsolatedControlPlane{KubeConfig: "/data/workload/kubeconfig.yaml"},
+			wantErr: true,
+		},
+		{
+			name:   "Konnectivity for control plane",
+			config: ExperimentalIsolatedControlPlane{Enabled: true, KubeConfig: "/data/workload/kubeconfig.yaml", Konnectivity: IsolatedControlPlaneKonnectivity{Enabled: true}},
+		},
+		{
+			name:   "Konnectivity for headless",
+			config: ExperimentalIsolatedControlPlane{Headless: true, Konnectivity: IsolatedControlPlaneKonnectivity{Enabled: true, Agent: KonnectivityAgent{ServerAddress: "vcluster.example.com"}}},
+		},
+		{
+			name:    "Konnectivity for headless without server address",
+			config:  ExperimentalIsolatedControlPlane{Headless: true, Konnectivity: IsolatedControlPlaneKonnectivity{Enabled: true}},
+			wantErr: true,
+		},
+		{
+			name:    "Konnectivity without isolated control plane",
+			config:  ExperimentalIsolatedControlPlane{Konnectivity: IsolatedControlPlaneKonnectivity{Enabled: true}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateIsolatedControlPlane(tt.config)
+			assert.Equal(t, err != nil, tt.wantErr)
+		})
+	}
+}
+
+func TestWithDefaultImageRegistry(t *testing.T) {
+	assert.Equal(t, WithDefaultImageRegistry("", "coredns/coredns:1.11.0"), "coredns/coredns:1.11.0")
+	assert.Equal(t, WithDefaultImageRegistry("registry.example.com", "coredns/coredns:1.11.0"), "registry.example.com/coredns/coredns:1.11.0")
+	assert.Equal(t, WithDefaultImageRegistry("registry.example.com/mirror/", "library/alpine:3.20"), "registry.example.com/mirror/library/alpine:3.20")
+}
@@ -103,6 +103,7 @@ type ExtraValuesOptions struct {
 	Expose            bool
 	NodePort          bool
 	SyncNodes         bool
+	Headless          bool
 	KubernetesVersion KubernetesVersion
 
 	DisableTelemetry    bool
@@ -371,6 +372,10 @@ func addCommonReleaseValues(config *Config, options *ExtraValuesOptions) {
 		config.Sync.FromHost.Nodes.Enabled = true
 	}
 
+	if options.Headless {
+		config.Experimental.IsolatedControlPlane.Headless = true
+	}
+
 	if options.DisableTelemetry {
 		config.Telemetry.Enabled = false
 	} else if options.InstanceCreatorType != "" {
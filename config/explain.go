@@ -0,0 +1,212 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Explanation describes a single path of the vcluster.yaml
+type Explanation struct {
+	Path        string
+	Type        string
+	Description string
+	Default     interface{}
+	Constraints []string
+	Fields      []ExplanationField
+}
+
+// ExplanationField is a direct child field of an explained path
+type ExplanationField struct {
+	Name        string
+	Type        string
+	Description string
+}
+
+// Explain returns the description, type, default and constraints of the given dot separated config path,
+// e.g. sync.toHost.pods. An empty path explains the root of the vcluster.yaml.
+func Explain(path string, options *SchemaOptions) (*Explanation, error) {
+	rawSchema, err := NewSchema(options)
+	if err != nil {
+		return nil, err
+	}
+
+	schema := map[string]interface{}{}
+	err = json.Unmarshal(rawSchema, &schema)
+	if err != nil {
+		return nil, fmt.Errorf("parse schema: %w", err)
+	}
+	definitions, _ := schema["$defs"].(map[string]interface{})
+
+	current := schema
+	segments := []string{}
+	if path != "" {
+		segments = strings.Split(path, ".")
+	}
+	for i, segment := range segments {
+		next := childSchema(resolveSchema(current, definitions), segment, definitions)
+		if next == nil {
+			return nil, fmt.Errorf("field %q does not exist in %s", segment, explainPath(segments[:i]))
+		}
+
+		current = next
+	}
+
+	resolved := resolveSchema(current, definitions)
+	explanation := &Explanation{
+		Path:        explainPath(segments),
+		Type:        schemaType(resolved, definitions),
+		Description: schemaDescription(current, resolved),
+		Default:     current["default"],
+		Constraints: schemaConstraints(resolved),
+	}
+	if explanation.Default == nil {
+		explanation.Default = resolved["default"]
+	}
+
+	properties, _ := resolved["properties"].(map[string]interface{})
+	for name, rawProperty := range properties {
+		property, _ := rawProperty.(map[string]interface{})
+		resolvedProperty := resolveSchema(property, definitions)
+		explanation.Fields = append(explanation.Fields, ExplanationField{
+			Name:        name,
+			Type:        schemaType(resolvedProperty, definitions),
+			Description: schemaDescription(property, resolvedProperty),
+		})
+	}
+	slices.SortFunc(explanation.Fields, func(a, b ExplanationField) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return explanation, nil
+}
+
+func explainPath(segments []string) string {
+	if len(segments) == 0 {
+		return "<root>"
+	}
+
+	return strings.Join(segments, ".")
+}
+
+// childSchema returns the schema of the given field. Array items and map values are stepped into
+// transparently, so sync.toHost.pods.translateImage.<key> or experimental.deploy.vcluster.helm.chart work.
+func childSchema(schema map[string]interface{}, name string, definitions map[string]interface{}) map[string]interface{} {
+	if items, ok := schema["items"].(map[string]interface{}); ok {
+		return childSchema(resolveSchema(items, definitions), name, definitions)
+	}
+
+	if properties, ok := schema["properties"].(map[string]interface{}); ok {
+		if property, ok := properties[name].(map[string]interface{}); ok {
+			return property
+		}
+	}
+	if additionalProperties, ok := schema["additionalProperties"].(map[string]interface{}); ok {
+		return additionalProperties
+	}
+	for _, key := range []string{"anyOf", "oneOf"} {
+		choices, _ := schema[key].([]interface{})
+		for _, rawChoice := range choices {
+			choice, _ := rawChoice.(map[string]interface{})
+			if child := childSchema(resolveSchema(choice, definitions), name, definitions); child != nil {
+				return child
+			}
+		}
+	}
+
+	return nil
+}
+
+// resolveSchema follows the $ref of the schema, the definitions are keyed by the name after #/$defs/
+func resolveSchema(schema map[string]interface{}, definitions map[string]interface{}) map[string]interface{} {
+	ref, ok := schema["$ref"].(string)
+	if !ok {
+		return schema
+	}
+
+	definition, ok := definitions[strings.TrimPrefix(ref, "#/$defs/")].(map[string]interface{})
+	if !ok {
+		return schema
+	}
+
+	return resolveSchema(definition, definitions)
+}
+
+func schemaType(schema map[string]interface{}, definitions map[string]interface{}) string {
+	for _, key := range []string{"anyOf", "oneOf"} {
+		choices, ok := schema[key].([]interface{})
+		if !ok {
+			continue
+		}
+
+		types := []string{}
+		for _, rawChoice := range choices {
+			choice, _ := rawChoice.(map[string]interface{})
+			types = append(types, schemaType(resolveSchema(choice, definitions), definitions))
+		}
+		return strings.Join(types, " | ")
+	}
+
+	typeName, _ := schema["type"].(string)
+	switch typeName {
+	case "array":
+		items, _ := schema["items"].(map[string]interface{})
+		return "[]" + schemaType(resolveSchema(items, definitions), definitions)
+	case "object":
+		if additionalProperties, ok := schema["additionalProperties"].(map[string]interface{}); ok {
+			return "map[string]" + schemaType(resolveSchema(additionalProperties, definitions), definitions)
+		}
+	case "":
+		if _, ok := schema["properties"]; ok {
+			return "object"
+		}
+
+		return "any"
+	}
+
+	return typeName
+}
+
+func schemaDescription(property, resolved map[string]interface{}) string {
+	if description, ok := property["description"].(string); ok {
+		return description
+	}
+
+	description, _ := resolved["description"].(string)
+	return description
+}
+
+func schemaConstraints(schema map[string]interface{}) []string {
+	constraints := []string{}
+	if enum, ok := schema["enum"].([]interface{}); ok {
+		values := []string{}
+		for _, value := range enum {
+			values = append(values, fmt.Sprintf("%v", value))
+		}
+		constraints = append(constraints, "must be one of: "+strings.Join(values, ", "))
+	}
+	if pattern, ok := schema["pattern"].(string); ok {
+		constraints = append(constraints, "must match pattern "+pattern)
+	}
+	if minimum, ok := schema["minimum"]; ok {
+		constraints = append(constraints, fmt.Sprintf("minimum %v", minimum))
+	}
+	if maximum, ok := schema["maximum"]; ok {
+		constraints = append(constraints, fmt.Sprintf("maximum %v", maximum))
+	}
+	if required, ok := schema["required"].([]interface{}); ok && len(required) > 0 {
+		fields := []string{}
+		for _, field := range required {
+			fields = append(fields, fmt.Sprintf("%v", field))
+		}
+		constraints = append(constraints, "required fields: "+strings.Join(fields, ", "))
+	}
+	if additionalProperties, ok := schema["additionalProperties"].(bool); ok && !additionalProperties {
+		if _, ok := schema["properties"]; ok {
+			constraints = append(constraints, "no fields other than the listed ones are allowed")
+		}
+	}
+
+	return constraints
+}
@@ -0,0 +1,41 @@
+package config
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func TestExplain(t *testing.T) {
+	explanation, err := Explain("sync.toHost.pods", &SchemaOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, explanation.Path, "sync.toHost.pods")
+	assert.Equal(t, explanation.Type, "object")
+	assert.Assert(t, explanation.Description != "")
+	assert.DeepEqual(t, explanation.Constraints, []string{"no fields other than the listed ones are allowed"})
+
+	fields := map[string]string{}
+	for _, field := range explanation.Fields {
+		fields[field.Name] = field.Type
+	}
+	assert.Equal(t, fields["enabled"], "boolean")
+	assert.Equal(t, fields["translateImage"], "map[string]string")
+	assert.Equal(t, fields["enforceTolerations"], "[]string")
+
+	explanation, err = Explain("controlPlane.proxy.port", &SchemaOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, explanation.Type, "integer")
+	assert.Equal(t, explanation.Default, float64(8443))
+
+	explanation, err = Explain("sync.fromHost.csiDrivers.enabled", &SchemaOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, explanation.Type, "string | boolean")
+	assert.Equal(t, explanation.Default, "auto")
+
+	explanation, err = Explain("experimental.multiCluster.satellites.nodeSelector", &SchemaOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, explanation.Type, "map[string]string")
+
+	_, err = Explain("sync.toHost.unknown", &SchemaOptions{})
+	assert.Error(t, err, `field "unknown" does not exist in sync.toHost`)
+}
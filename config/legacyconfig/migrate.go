@@ -401,9 +401,9 @@ func convertBaseValues(oldConfig BaseHelm, newConfig *config.Config) error {
 			newConfig.Policies.LimitRange.Enabled = true
 		}
 		if oldConfig.Isolation.PodSecurityStandard == "" {
-			newConfig.Policies.PodSecurityStandard = "baseline"
+			newConfig.Policies.PodSecurityStandard.Level = "baseline"
 		} else {
-			newConfig.Policies.PodSecurityStandard = oldConfig.Isolation.PodSecurityStandard
+			newConfig.Policies.PodSecurityStandard.Level = oldConfig.Isolation.PodSecurityStandard
 		}
 
 		if oldConfig.Isolation.NetworkPolicy.OutgoingConnections.IPBlock.CIDR != "" {
@@ -273,7 +273,8 @@ pro: true`,
 policies:
   limitRange:
     enabled: true
-  podSecurityStandard: baseline
+  podSecurityStandard:
+    level: baseline
   resourceQuota:
     enabled: true`,
 		},
@@ -0,0 +1,263 @@
+package config
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+//go:embed values.schema.json
+var Schema string
+
+// SchemaOptions define the variant of the schema to generate
+type SchemaOptions struct {
+	// Distro removes the configuration of other distros and enables the given one by default
+	Distro string
+
+	// KubernetesVersion sets the default images of the distros to the given Kubernetes version
+	KubernetesVersion string
+}
+
+// NewSchema returns the vcluster.yaml JSON schema including the default values of this version.
+func NewSchema(options *SchemaOptions) ([]byte, error) {
+	extraValuesOptions := &ExtraValuesOptions{}
+	if options.Distro != "" {
+		if !slices.Contains([]string{K3SDistro, K0SDistro, K8SDistro, EKSDistro}, options.Distro) {
+			return nil, fmt.Errorf("unsupported distro %s, please use one of: %s, %s, %s, %s", options.Distro, K8SDistro, K3SDistro, K0SDistro, EKSDistro)
+		}
+
+		extraValuesOptions.Distro = options.Distro
+	}
+	if options.KubernetesVersion != "" {
+		kubernetesVersion, err := ParseKubernetesVersionInfo(options.KubernetesVersion)
+		if err != nil {
+			return nil, err
+		}
+
+		extraValuesOptions.KubernetesVersion = *kubernetesVersion
+	}
+
+	defaultConfig, err := getExtraValues(extraValuesOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	defaults, err := schemaDefaults(defaultConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	schema := map[string]interface{}{}
+	err = json.Unmarshal([]byte(Schema), &schema)
+	if err != nil {
+		return nil, fmt.Errorf("parse schema: %w", err)
+	}
+
+	definitions, _ := schema["$defs"].(map[string]interface{})
+	applySchemaDefaults(schema, definitions, defaults)
+	if options.Distro != "" {
+		removeOtherDistros(schema, options.Distro)
+	}
+
+	return json.MarshalIndent(schema, "", "  ")
+}
+
+// applySchemaDefaults sets the default value of each property. Referenced definitions are inlined where they
+// have nested defaults, as the same definition is used for different paths with different defaults.
+func applySchemaDefaults(schema map[string]interface{}, definitions map[string]interface{}, defaults map[string]interface{}) {
+	properties, ok := schema["properties"].(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	for key, rawProperty := range properties {
+		property, ok := rawProperty.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		value, ok := defaults[key]
+		if !ok {
+			continue
+		}
+
+		nestedDefaults, isObject := value.(map[string]interface{})
+		if isObject {
+			if ref, ok := property["$ref"].(string); ok {
+				definition, ok := definitions[strings.TrimPrefix(ref, "#/$defs/")].(map[string]interface{})
+				if ok {
+					property = inlineDefinition(property, definition)
+					properties[key] = property
+				}
+			}
+
+			if _, ok := property["properties"]; ok {
+				applySchemaDefaults(property, definitions, nestedDefaults)
+				continue
+			}
+		}
+
+		property["default"] = value
+	}
+}
+
+func inlineDefinition(property, definition map[string]interface{}) map[string]interface{} {
+	inlined := map[string]interface{}{}
+	for k, v := range deepCopyJSON(definition).(map[string]interface{}) {
+		inlined[k] = v
+	}
+	for k, v := range property {
+		if k != "$ref" {
+			inlined[k] = v
+		}
+	}
+
+	return inlined
+}
+
+func removeOtherDistros(schema map[string]interface{}, distro string) {
+	distroSchema := nestedProperty(schema, "controlPlane", "distro")
+	if distroSchema == nil {
+		return
+	}
+
+	properties, ok := distroSchema["properties"].(map[string]interface{})
+	if !ok {
+		return
+	}
+	for key := range properties {
+		if key != distro {
+			delete(properties, key)
+		}
+	}
+}
+
+func nestedProperty(schema map[string]interface{}, path ...string) map[string]interface{} {
+	for _, key := range path {
+		properties, ok := schema["properties"].(map[string]interface{})
+		if !ok {
+			return nil
+		}
+
+		schema, ok = properties[key].(map[string]interface{})
+		if !ok {
+			return nil
+		}
+	}
+
+	return schema
+}
+
+func deepCopyJSON(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		copied := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			copied[key] = deepCopyJSON(item)
+		}
+		return copied
+	case []interface{}:
+		copied := make([]interface{}, len(v))
+		for i, item := range v {
+			copied[i] = deepCopyJSON(item)
+		}
+		return copied
+	default:
+		return v
+	}
+}
+
+// schemaDefaults merges the given config into the values.yaml, as the config omits empty values such as false
+func schemaDefaults(config *Config) (map[string]interface{}, error) {
+	defaults := map[string]interface{}{}
+	err := yaml.Unmarshal([]byte(Values), &defaults)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+
+	configMap := map[string]interface{}{}
+	err = json.Unmarshal(raw, &configMap)
+	if err != nil {
+		return nil, err
+	}
+
+	mergeJSON(defaults, configMap)
+	return defaults, nil
+}
+
+func mergeJSON(into, from map[string]interface{}) {
+	for key, value := range from {
+		fromMap, fromIsMap := value.(map[string]interface{})
+		intoMap, intoIsMap := into[key].(map[string]interface{})
+		if fromIsMap && intoIsMap {
+			mergeJSON(intoMap, fromMap)
+			continue
+		}
+
+		into[key] = value
+	}
+}
+
+// SensitivePaths returns the dotted paths of all values the schema marks as writeOnly, such as
+// controlPlane.distro.k3s.token. Items of arrays are referenced as path[]. These values contain secrets and should be redacted before they're logged.
+func SensitivePaths() ([]string, error) {
+	schema := map[string]interface{}{}
+	err := json.Unmarshal([]byte(Schema), &schema)
+	if err != nil {
+		return nil, fmt.Errorf("parse schema: %w", err)
+	}
+
+	definitions, _ := schema["$defs"].(map[string]interface{})
+	paths := []string{}
+	collectSensitivePaths(schema, definitions, "", map[string]bool{}, &paths)
+	slices.Sort(paths)
+	return paths, nil
+}
+
+func collectSensitivePaths(schema map[string]interface{}, definitions map[string]interface{}, prefix string, visited map[string]bool, paths *[]string) {
+	if ref, ok := schema["$ref"].(string); ok {
+		name := strings.TrimPrefix(ref, "#/$defs/")
+		definition, ok := definitions[name].(map[string]interface{})
+		if !ok || visited[name] {
+			return
+		}
+
+		visited[name] = true
+		collectSensitivePaths(definition, definitions, prefix, visited, paths)
+		delete(visited, name)
+		return
+	}
+
+	if writeOnly, _ := schema["writeOnly"].(bool); writeOnly && prefix != "" {
+		*paths = append(*paths, prefix)
+		return
+	}
+
+	// items of arrays are referenced as path[]
+	if items, ok := schema["items"].(map[string]interface{}); ok {
+		collectSensitivePaths(items, definitions, prefix+"[]", visited, paths)
+	}
+
+	properties, _ := schema["properties"].(map[string]interface{})
+	for key, rawProperty := range properties {
+		property, ok := rawProperty.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+		collectSensitivePaths(property, definitions, path, visited, paths)
+	}
+}
@@ -0,0 +1,49 @@
+package config
+
+import (
+	"encoding/json"
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func TestNewSchema(t *testing.T) {
+	raw, err := NewSchema(&SchemaOptions{})
+	assert.NilError(t, err)
+
+	schema := map[string]interface{}{}
+	assert.NilError(t, json.Unmarshal(raw, &schema))
+	assert.Equal(t, nestedProperty(schema, "controlPlane", "proxy", "port")["default"], float64(8443))
+	assert.Equal(t, nestedProperty(schema, "controlPlane", "distro", "k3s", "enabled")["default"], false)
+	assert.Assert(t, nestedProperty(schema, "controlPlane", "distro", "k8s") != nil)
+
+	raw, err = NewSchema(&SchemaOptions{Distro: K8SDistro, KubernetesVersion: "v1.28"})
+	assert.NilError(t, err)
+
+	schema = map[string]interface{}{}
+	assert.NilError(t, json.Unmarshal(raw, &schema))
+	assert.Equal(t, nestedProperty(schema, "controlPlane", "distro", "k8s", "apiServer", "image", "tag")["default"], "v1.28.4")
+	assert.Assert(t, nestedProperty(schema, "controlPlane", "distro", "k3s") == nil)
+
+	raw, err = NewSchema(&SchemaOptions{Distro: K3SDistro})
+	assert.NilError(t, err)
+
+	schema = map[string]interface{}{}
+	assert.NilError(t, json.Unmarshal(raw, &schema))
+	assert.Equal(t, nestedProperty(schema, "controlPlane", "distro", "k3s", "enabled")["default"], true)
+	assert.Assert(t, nestedProperty(schema, "controlPlane", "distro", "k8s") == nil)
+
+	_, err = NewSchema(&SchemaOptions{Distro: "unknown"})
+	assert.ErrorContains(t, err, "unsupported distro")
+}
+
+func TestSensitivePaths(t *testing.T) {
+	paths, err := SensitivePaths()
+	assert.NilError(t, err)
+	assert.DeepEqual(t, paths, []string{
+		"controlPlane.backingStore.database.embedded.dataSource",
+		"controlPlane.backingStore.database.external.dataSource",
+		"controlPlane.distro.k3s.token",
+		"experimental.deploy.vcluster.helm[].chart.password",
+	})
+}
@@ -92,6 +92,8 @@ module.exports = {
         },
         "networking/ingress_traffic",
         "networking/network_policies",
+        "networking/istio",
+        "networking/external_dns",
       ],
     },
     {
@@ -115,6 +117,8 @@ module.exports = {
             "syncer/other_resources/generic_sync",
             "syncer/other_resources/config_syntax",
             "syncer/other_resources/multi_namespace_mode",
+            "syncer/other_resources/cert_manager",
+            "syncer/other_resources/external_secrets",
           ],
         },
         {
@@ -190,6 +194,7 @@ module.exports = {
           items: [
             "o11y/metrics/metrics_server_proxy",
             "o11y/metrics/metrics_server",
+            "o11y/metrics/keda",
             "o11y/metrics/monitoring_vcluster",
           ],
         },
@@ -14,6 +14,7 @@ import (
 )
 
 const OutFile = "chart/values.schema.json"
+const ConfigOutFile = "config/values.schema.json"
 const ValuesOutFile = "chart/values.yaml"
 
 var SkipProperties = map[string]string{
@@ -45,6 +46,12 @@ func main() {
 		panic(err)
 	}
 
+	// the cli embeds the schema to export it for editors
+	err = writeSchema(generatedSchema, ConfigOutFile)
+	if err != nil {
+		panic(err)
+	}
+
 	err = writeValues(generatedSchema)
 	if err != nil {
 		panic(err)
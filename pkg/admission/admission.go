@@ -0,0 +1,123 @@
+package admission
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	vclusterconfig "github.com/loft-sh/vcluster/config"
+	"sigs.k8s.io/yaml"
+)
+
+// ConfigPath is the path of the admission configuration that is passed to the api server
+const ConfigPath = "/run/vcluster/admission-configuration.yaml"
+
+type admissionConfiguration struct {
+	APIVersion string            `json:"apiVersion"`
+	Kind       string            `json:"kind"`
+	Plugins    []admissionPlugin `json:"plugins"`
+}
+
+type admissionPlugin struct {
+	Name          string                   `json:"name"`
+	Configuration podSecurityConfiguration `json:"configuration"`
+}
+
+type podSecurityConfiguration struct {
+	APIVersion string                         `json:"apiVersion"`
+	Kind       string                         `json:"kind"`
+	Defaults   podSecurityDefaults            `json:"defaults"`
+	Exemptions podSecurityConfigurationExempt `json:"exemptions"`
+}
+
+type podSecurityDefaults struct {
+	Enforce        string `json:"enforce"`
+	EnforceVersion string `json:"enforce-version"`
+	Audit          string `json:"audit"`
+	AuditVersion   string `json:"audit-version"`
+	Warn           string `json:"warn"`
+	WarnVersion    string `json:"warn-version"`
+}
+
+type podSecurityConfigurationExempt struct {
+	Usernames      []string `json:"usernames,omitempty"`
+	RuntimeClasses []string `json:"runtimeClasses,omitempty"`
+	Namespaces     []string `json:"namespaces,omitempty"`
+}
+
+// IsEnabled returns true if the api server should be started with the admission configuration
+func IsEnabled(policies vclusterconfig.Policies) bool {
+	return policies.PodSecurityStandard.Enabled
+}
+
+// WriteConfig writes the admission configuration with the pod security admission defaults and exemptions for the api server
+func WriteConfig(policies vclusterconfig.Policies) error {
+	out, err := BuildConfig(policies)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(ConfigPath), 0755)
+	if err != nil {
+		return fmt.Errorf("create admission config dir: %w", err)
+	}
+
+	return os.WriteFile(ConfigPath, out, 0640)
+}
+
+// BuildConfig builds the admission configuration for the given policies
+func BuildConfig(policies vclusterconfig.Policies) ([]byte, error) {
+	podSecurity := policies.PodSecurityStandard
+	enforce := podSecurity.Defaults.Enforce
+	if enforce == "" {
+		enforce = podSecurity.Level
+	}
+
+	out, err := yaml.Marshal(&admissionConfiguration{
+		APIVersion: "apiserver.config.k8s.io/v1",
+		Kind:       "AdmissionConfiguration",
+		Plugins: []admissionPlugin{
+			{
+				Name: "PodSecurity",
+				Configuration: podSecurityConfiguration{
+					APIVersion: "pod-security.admission.config.k8s.io/v1",
+					Kind:       "PodSecurityConfiguration",
+					Defaults: podSecurityDefaults{
+						Enforce:        levelOrDefault(enforce),
+						EnforceVersion: versionOrDefault(podSecurity.Defaults.EnforceVersion),
+						Audit:          levelOrDefault(podSecurity.Defaults.Audit),
+						AuditVersion:   versionOrDefault(podSecurity.Defaults.AuditVersion),
+						Warn:           levelOrDefault(podSecurity.Defaults.Warn),
+						WarnVersion:    versionOrDefault(podSecurity.Defaults.WarnVersion),
+					},
+					Exemptions: podSecurityConfigurationExempt{
+						Usernames:      podSecurity.Exemptions.Usernames,
+						RuntimeClasses: podSecurity.Exemptions.RuntimeClasses,
+						Namespaces:     podSecurity.Exemptions.Namespaces,
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("marshal admission config: %w", err)
+	}
+
+	return out, nil
+}
+
+func levelOrDefault(level string) string {
+	if level == "" {
+		return "privileged"
+	}
+
+	return level
+}
+
+func versionOrDefault(version string) string {
+	if version == "" {
+		return "latest"
+	}
+
+	return version
+}
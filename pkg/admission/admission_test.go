@@ -0,0 +1,45 @@
+package admission
+
+import (
+	"testing"
+
+	vclusterconfig "github.com/loft-sh/vcluster/config"
+	"gotest.tools/v3/assert"
+)
+
+func TestBuildConfig(t *testing.T) {
+	policies := vclusterconfig.Policies{
+		PodSecurityStandard: vclusterconfig.PodSecurityStandard{
+			Level:   "baseline",
+			Enabled: true,
+			Defaults: vclusterconfig.PodSecurityStandardDefaults{
+				Warn:        "restricted",
+				WarnVersion: "v1.30",
+			},
+			Exemptions: vclusterconfig.PodSecurityStandardExemptions{
+				Namespaces: []string{"kube-system"},
+			},
+		},
+	}
+
+	out, err := BuildConfig(policies)
+	assert.NilError(t, err)
+	assert.Equal(t, string(out), `apiVersion: apiserver.config.k8s.io/v1
+kind: AdmissionConfiguration
+plugins:
+- configuration:
+    apiVersion: pod-security.admission.config.k8s.io/v1
+    defaults:
+      audit: privileged
+      audit-version: latest
+      enforce: baseline
+      enforce-version: latest
+      warn: restricted
+      warn-version: v1.30
+    exemptions:
+      namespaces:
+      - kube-system
+    kind: PodSecurityConfiguration
+  name: PodSecurity
+`)
+}
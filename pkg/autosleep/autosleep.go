@@ -0,0 +1,352 @@
+package autosleep
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// SleepingReplicasAnnotation holds the replicas of a workload before it was put to sleep
+	SleepingReplicasAnnotation = "vcluster.loft.sh/sleeping-replicas"
+
+	// SleepingSinceAnnotation holds the unix timestamp when the workload was put to sleep
+	SleepingSinceAnnotation = "vcluster.loft.sh/sleeping-since"
+
+	checkInterval = 30 * time.Second
+
+	// WakeUpTimeout is how long waking up waits for the workloads to become ready
+	WakeUpTimeout = 2 * time.Minute
+)
+
+// Controller puts the workloads of the virtual cluster to sleep after a period of inactivity and
+// wakes them up again as soon as new activity was recorded by the tracker. The tracker is marked as
+// awake once the woken up workloads are ready, so requests waiting for them can continue. Workloads
+// scaled by a HorizontalPodAutoscaler are not put to sleep, as the autoscaler owns their replicas.
+type Controller struct {
+	virtualClient client.Client
+	tracker       *ActivityTracker
+
+	// shared stores the activity and sleeping state for the other replicas, nil with a single replica
+	shared *SharedActivity
+
+	afterInactivity   time.Duration
+	excludeNamespaces []string
+}
+
+// NewController creates a new auto sleep controller, shared is nil if the virtual cluster has a single replica
+func NewController(virtualClient client.Client, tracker *ActivityTracker, shared *SharedActivity, afterInactivity time.Duration, excludeNamespaces []string) *Controller {
+	return &Controller{
+		virtualClient:     virtualClient,
+		tracker:           tracker,
+		shared:            shared,
+		afterInactivity:   afterInactivity,
+		excludeNamespaces: append([]string{"kube-system"}, excludeNamespaces...),
+	}
+}
+
+// Start runs the controller until the context is done
+func (c *Controller) Start(ctx context.Context) {
+	// check if we were sleeping before a restart
+	sleeping, err := c.isSleeping(ctx)
+	if err != nil {
+		klog.Errorf("Error checking auto sleep state: %v", err)
+	}
+	c.setSleeping(ctx, sleeping)
+
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-c.tracker.WakeUpRequests():
+			c.wake(ctx)
+		case <-ticker.C:
+			c.check(ctx, time.Now())
+		}
+	}
+}
+
+// wake wakes up the workloads if they are sleeping and marks them as awake once they are ready
+func (c *Controller) wake(ctx context.Context) {
+	if !c.tracker.Sleeping() {
+		return
+	}
+
+	klog.Infof("Activity detected, waking up virtual cluster workloads")
+	err := c.WakeUp(ctx)
+	if err != nil {
+		klog.Errorf("Error waking up virtual cluster: %v", err)
+		return
+	}
+
+	// waiting requests continue after the timeout as well, as some workloads might never become ready
+	err = wait.PollUntilContextTimeout(ctx, time.Second, WakeUpTimeout, true, c.ready)
+	if err != nil {
+		klog.Errorf("Error waiting for virtual cluster workloads to become ready: %v", err)
+	}
+	c.setSleeping(ctx, false)
+}
+
+// check puts the workloads to sleep if the virtual cluster was inactive long enough
+func (c *Controller) check(ctx context.Context, now time.Time) {
+	err := c.updateRunningPods(ctx)
+	if err != nil {
+		klog.Errorf("Error counting running pods: %v", err)
+		return
+	}
+	if c.tracker.Sleeping() || c.tracker.InactiveFor(now) < c.afterInactivity {
+		return
+	}
+
+	klog.Infof("Virtual cluster was inactive for %s, putting workloads to sleep", c.afterInactivity.String())
+	err = c.Sleep(ctx)
+	if err != nil {
+		klog.Errorf("Error putting virtual cluster to sleep: %v", err)
+		return
+	}
+	c.setSleeping(ctx, true)
+}
+
+// setSleeping marks the tracker as sleeping or awake and stores the state for the other replicas
+func (c *Controller) setSleeping(ctx context.Context, sleeping bool) {
+	if c.shared == nil {
+		c.tracker.SetSleeping(sleeping)
+		return
+	}
+
+	err := c.shared.SetSleeping(ctx, sleeping)
+	if err != nil {
+		klog.Errorf("Error storing auto sleep state: %v", err)
+	}
+}
+
+// Sleep scales down all deployments and statefulsets within the virtual cluster that are not scaled by a
+// HorizontalPodAutoscaler
+func (c *Controller) Sleep(ctx context.Context) error {
+	autoscaled, err := c.autoscaledTargets(ctx)
+	if err != nil {
+		return err
+	}
+
+	deployments := &appsv1.DeploymentList{}
+	err = c.virtualClient.List(ctx, deployments)
+	if err != nil {
+		return fmt.Errorf("list deployments: %w", err)
+	}
+	for i := range deployments.Items {
+		deployment := &deployments.Items[i]
+		if c.excluded(deployment) || autoscaled[autoscaledTarget{kind: "Deployment", namespace: deployment.Namespace, name: deployment.Name}] || ptr.Deref(deployment.Spec.Replicas, 1) == 0 {
+			continue
+		}
+
+		err = c.scaleDown(ctx, deployment, &deployment.Spec.Replicas)
+		if err != nil {
+			return fmt.Errorf("scale down deployment %s/%s: %w", deployment.Namespace, deployment.Name, err)
+		}
+	}
+
+	statefulSets := &appsv1.StatefulSetList{}
+	err = c.virtualClient.List(ctx, statefulSets)
+	if err != nil {
+		return fmt.Errorf("list statefulsets: %w", err)
+	}
+	for i := range statefulSets.Items {
+		statefulSet := &statefulSets.Items[i]
+		if c.excluded(statefulSet) || autoscaled[autoscaledTarget{kind: "StatefulSet", namespace: statefulSet.Namespace, name: statefulSet.Name}] || ptr.Deref(statefulSet.Spec.Replicas, 1) == 0 {
+			continue
+		}
+
+		err = c.scaleDown(ctx, statefulSet, &statefulSet.Spec.Replicas)
+		if err != nil {
+			return fmt.Errorf("scale down statefulset %s/%s: %w", statefulSet.Namespace, statefulSet.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// WakeUp restores the replicas of all workloads that were put to sleep
+func (c *Controller) WakeUp(ctx context.Context) error {
+	deployments := &appsv1.DeploymentList{}
+	err := c.virtualClient.List(ctx, deployments)
+	if err != nil {
+		return fmt.Errorf("list deployments: %w", err)
+	}
+	for i := range deployments.Items {
+		deployment := &deployments.Items[i]
+		err = c.scaleUp(ctx, deployment, &deployment.Spec.Replicas)
+		if err != nil {
+			return fmt.Errorf("scale up deployment %s/%s: %w", deployment.Namespace, deployment.Name, err)
+		}
+	}
+
+	statefulSets := &appsv1.StatefulSetList{}
+	err = c.virtualClient.List(ctx, statefulSets)
+	if err != nil {
+		return fmt.Errorf("list statefulsets: %w", err)
+	}
+	for i := range statefulSets.Items {
+		statefulSet := &statefulSets.Items[i]
+		err = c.scaleUp(ctx, statefulSet, &statefulSet.Spec.Replicas)
+		if err != nil {
+			return fmt.Errorf("scale up statefulset %s/%s: %w", statefulSet.Namespace, statefulSet.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func (c *Controller) scaleDown(ctx context.Context, obj client.Object, replicas **int32) error {
+	patch := client.MergeFrom(obj.DeepCopyObject().(client.Object))
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[SleepingReplicasAnnotation] = strconv.Itoa(int(ptr.Deref(*replicas, 1)))
+	annotations[SleepingSinceAnnotation] = strconv.FormatInt(time.Now().Unix(), 10)
+	obj.SetAnnotations(annotations)
+	*replicas = ptr.To(int32(0))
+	return c.virtualClient.Patch(ctx, obj, patch)
+}
+
+func (c *Controller) scaleUp(ctx context.Context, obj client.Object, replicas **int32) error {
+	annotations := obj.GetAnnotations()
+	if annotations == nil || annotations[SleepingReplicasAnnotation] == "" {
+		return nil
+	}
+
+	sleepingReplicas, err := strconv.Atoi(annotations[SleepingReplicasAnnotation])
+	if err != nil {
+		sleepingReplicas = 1
+	}
+
+	patch := client.MergeFrom(obj.DeepCopyObject().(client.Object))
+	delete(annotations, SleepingReplicasAnnotation)
+	delete(annotations, SleepingSinceAnnotation)
+	obj.SetAnnotations(annotations)
+	*replicas = ptr.To(int32(sleepingReplicas))
+	return c.virtualClient.Patch(ctx, obj, patch)
+}
+
+// updateRunningPods counts the running pods that are not managed by a workload we can scale down,
+// such as jobs or bare pods, as interrupting them would lose work.
+func (c *Controller) updateRunningPods(ctx context.Context) error {
+	pods := &corev1.PodList{}
+	err := c.virtualClient.List(ctx, pods)
+	if err != nil {
+		return err
+	}
+
+	runningPods := 0
+	for _, pod := range pods.Items {
+		if pod.Status.Phase != corev1.PodRunning || slices.Contains(c.excludeNamespaces, pod.Namespace) {
+			continue
+		}
+
+		scalable := false
+		for _, owner := range pod.OwnerReferences {
+			if owner.Kind == "ReplicaSet" || owner.Kind == "StatefulSet" || owner.Kind == "DaemonSet" {
+				scalable = true
+				break
+			}
+		}
+		if !scalable {
+			runningPods++
+		}
+	}
+
+	c.tracker.SetRunningPods(runningPods)
+	return nil
+}
+
+// ready returns true if all deployments and statefulsets outside the excluded namespaces have their replicas ready
+func (c *Controller) ready(ctx context.Context) (bool, error) {
+	deployments := &appsv1.DeploymentList{}
+	err := c.virtualClient.List(ctx, deployments)
+	if err != nil {
+		return false, err
+	}
+	for _, deployment := range deployments.Items {
+		if !c.excluded(&deployment) && deployment.Status.ReadyReplicas < ptr.Deref(deployment.Spec.Replicas, 1) {
+			return false, nil
+		}
+	}
+
+	statefulSets := &appsv1.StatefulSetList{}
+	err = c.virtualClient.List(ctx, statefulSets)
+	if err != nil {
+		return false, err
+	}
+	for _, statefulSet := range statefulSets.Items {
+		if !c.excluded(&statefulSet) && statefulSet.Status.ReadyReplicas < ptr.Deref(statefulSet.Spec.Replicas, 1) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+func (c *Controller) isSleeping(ctx context.Context) (bool, error) {
+	deployments := &appsv1.DeploymentList{}
+	err := c.virtualClient.List(ctx, deployments)
+	if err != nil {
+		return false, err
+	}
+	for _, deployment := range deployments.Items {
+		if deployment.Annotations[SleepingReplicasAnnotation] != "" {
+			return true, nil
+		}
+	}
+
+	statefulSets := &appsv1.StatefulSetList{}
+	err = c.virtualClient.List(ctx, statefulSets)
+	if err != nil {
+		return false, err
+	}
+	for _, statefulSet := range statefulSets.Items {
+		if statefulSet.Annotations[SleepingReplicasAnnotation] != "" {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+type autoscaledTarget struct {
+	kind      string
+	namespace string
+	name      string
+}
+
+// autoscaledTargets returns the workloads that are scaled by a HorizontalPodAutoscaler
+func (c *Controller) autoscaledTargets(ctx context.Context) (map[autoscaledTarget]bool, error) {
+	autoscalers := &autoscalingv2.HorizontalPodAutoscalerList{}
+	err := c.virtualClient.List(ctx, autoscalers)
+	if err != nil {
+		return nil, fmt.Errorf("list horizontal pod autoscalers: %w", err)
+	}
+
+	targets := map[autoscaledTarget]bool{}
+	for _, autoscaler := range autoscalers.Items {
+		targets[autoscaledTarget{kind: autoscaler.Spec.ScaleTargetRef.Kind, namespace: autoscaler.Namespace, name: autoscaler.Spec.ScaleTargetRef.Name}] = true
+	}
+
+	return targets, nil
+}
+
+func (c *Controller) excluded(obj metav1.Object) bool {
+	return slices.Contains(c.excludeNamespaces, obj.GetNamespace())
+}
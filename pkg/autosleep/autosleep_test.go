@@ -0,0 +1,103 @@
+package autosleep
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestSleepWakeUp(t *testing.T) {
+	ctx := context.Background()
+	virtualClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+			Spec:       appsv1.DeploymentSpec{Replicas: ptr.To(int32(2))},
+			Status:     appsv1.DeploymentStatus{ReadyReplicas: 2},
+		},
+		&appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "default"},
+			Spec:       appsv1.StatefulSetSpec{Replicas: ptr.To(int32(1))},
+			Status:     appsv1.StatefulSetStatus{ReadyReplicas: 1},
+		},
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "coredns", Namespace: "kube-system"},
+			Spec:       appsv1.DeploymentSpec{Replicas: ptr.To(int32(1))},
+		},
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "default"},
+			Spec:       appsv1.DeploymentSpec{Replicas: ptr.To(int32(3))},
+			Status:     appsv1.DeploymentStatus{ReadyReplicas: 3},
+		},
+		&autoscalingv2.HorizontalPodAutoscaler{
+			ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "default"},
+			Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+				ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{APIVersion: "apps/v1", Kind: "Deployment", Name: "api"},
+				MaxReplicas:    5,
+			},
+		},
+	).Build()
+	tracker := NewActivityTracker()
+	controller := NewController(virtualClient, tracker, nil, time.Hour, nil)
+	now := time.Now()
+
+	// an active virtual cluster is not put to sleep
+	controller.check(ctx, now)
+	assert.Assert(t, !tracker.Sleeping())
+	assert.Equal(t, replicas(t, virtualClient, &appsv1.Deployment{}, "default", "web"), int32(2))
+
+	// running pods that cannot be scaled down keep the virtual cluster awake
+	job := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "job", Namespace: "default"}, Status: corev1.PodStatus{Phase: corev1.PodRunning}}
+	assert.NilError(t, virtualClient.Create(ctx, job))
+	controller.check(ctx, now.Add(2*time.Hour))
+	assert.Assert(t, !tracker.Sleeping())
+	assert.NilError(t, virtualClient.Delete(ctx, job))
+
+	// an inactive virtual cluster is put to sleep, excluded namespaces and autoscaled workloads keep running
+	controller.check(ctx, now.Add(2*time.Hour))
+	assert.Assert(t, tracker.Sleeping())
+	assert.Equal(t, replicas(t, virtualClient, &appsv1.Deployment{}, "default", "web"), int32(0))
+	assert.Equal(t, replicas(t, virtualClient, &appsv1.StatefulSet{}, "default", "db"), int32(0))
+	assert.Equal(t, replicas(t, virtualClient, &appsv1.Deployment{}, "kube-system", "coredns"), int32(1))
+	assert.Equal(t, replicas(t, virtualClient, &appsv1.Deployment{}, "default", "api"), int32(3))
+	sleeping, err := controller.isSleeping(ctx)
+	assert.NilError(t, err)
+	assert.Assert(t, sleeping)
+
+	// activity wakes the workloads up with their previous replicas
+	tracker.RecordActivity()
+	<-tracker.WakeUpRequests()
+	controller.wake(ctx)
+	assert.Assert(t, !tracker.Sleeping())
+	assert.Equal(t, replicas(t, virtualClient, &appsv1.Deployment{}, "default", "web"), int32(2))
+	assert.Equal(t, replicas(t, virtualClient, &appsv1.StatefulSet{}, "default", "db"), int32(1))
+	sleeping, err = controller.isSleeping(ctx)
+	assert.NilError(t, err)
+	assert.Assert(t, !sleeping)
+
+	// waking up again does nothing
+	controller.wake(ctx)
+	assert.Equal(t, replicas(t, virtualClient, &appsv1.Deployment{}, "default", "web"), int32(2))
+}
+
+func replicas(t *testing.T, virtualClient client.Client, obj client.Object, namespace, name string) int32 {
+	assert.NilError(t, virtualClient.Get(context.Background(), client.ObjectKey{Namespace: namespace, Name: name}, obj))
+	switch workload := obj.(type) {
+	case *appsv1.Deployment:
+		return ptr.Deref(workload.Spec.Replicas, 1)
+	case *appsv1.StatefulSet:
+		return ptr.Deref(workload.Spec.Replicas, 1)
+	}
+
+	t.Fatalf("unexpected workload %T", obj)
+	return 0
+}
@@ -0,0 +1,134 @@
+package autosleep
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// LastActivityAnnotation holds the unix timestamp of the last activity any replica of the virtual cluster recorded
+	LastActivityAnnotation = "vcluster.loft.sh/last-activity"
+
+	// SleepingAnnotation is set to true while the workloads of the virtual cluster are sleeping
+	SleepingAnnotation = "vcluster.loft.sh/sleeping"
+
+	sharedSyncInterval = 5 * time.Second
+)
+
+// DefaultSharedActivity shares the activity of DefaultTracker with the other replicas, it is nil if there is only a
+// single replica
+var DefaultSharedActivity *SharedActivity
+
+// SharedActivity keeps the activity of all replicas of the virtual cluster in a lease. Each replica only proxies part
+// of the requests, but only the leader runs the auto sleep controller, so the replicas publish their activity to the
+// lease and adopt the activity of the others. The controller stores the sleeping state in the lease as well, so
+// requests to other replicas wait until the workloads are awake.
+type SharedActivity struct {
+	kubeClient kubernetes.Interface
+	namespace  string
+	name       string
+
+	tracker *ActivityTracker
+}
+
+// NewSharedActivity creates a new shared activity that syncs the tracker with the lease of the given name
+func NewSharedActivity(kubeClient kubernetes.Interface, namespace, name string, tracker *ActivityTracker) *SharedActivity {
+	return &SharedActivity{
+		kubeClient: kubeClient,
+		namespace:  namespace,
+		name:       name,
+		tracker:    tracker,
+	}
+}
+
+// Start syncs the tracker with the lease until the context is done
+func (s *SharedActivity) Start(ctx context.Context) {
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		err := s.Sync(ctx)
+		if err != nil {
+			klog.Errorf("Error syncing auto sleep activity: %v", err)
+		}
+	}, sharedSyncInterval)
+}
+
+// Sync publishes the activity of this replica to the lease and adopts newer activity and the sleeping state of the
+// lease. Newer activity of other replicas is treated like activity of this replica, so it wakes up the workloads.
+func (s *SharedActivity) Sync(ctx context.Context) error {
+	lease, err := s.kubeClient.CoordinationV1().Leases(s.namespace).Get(ctx, s.name, metav1.GetOptions{})
+	if kerrors.IsNotFound(err) {
+		_, err = s.kubeClient.CoordinationV1().Leases(s.namespace).Create(ctx, s.newLease(s.tracker.Sleeping()), metav1.CreateOptions{})
+		if err != nil && !kerrors.IsAlreadyExists(err) {
+			return fmt.Errorf("create lease: %w", err)
+		}
+
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("get lease: %w", err)
+	}
+
+	sharedActivity, _ := strconv.ParseInt(lease.Annotations[LastActivityAnnotation], 10, 64)
+	localActivity := s.tracker.LastActivity().Unix()
+	if localActivity > sharedActivity {
+		if lease.Annotations == nil {
+			lease.Annotations = map[string]string{}
+		}
+		lease.Annotations[LastActivityAnnotation] = strconv.FormatInt(localActivity, 10)
+
+		// on conflicts another replica published its activity in the meantime, so we try again during the next sync
+		_, err = s.kubeClient.CoordinationV1().Leases(s.namespace).Update(ctx, lease, metav1.UpdateOptions{})
+		if err != nil && !kerrors.IsConflict(err) {
+			return fmt.Errorf("update lease: %w", err)
+		}
+	} else if sharedActivity > localActivity {
+		s.tracker.recordActivityAt(time.Unix(sharedActivity, 0))
+	}
+
+	s.tracker.SetSleeping(lease.Annotations[SleepingAnnotation] == "true")
+	return nil
+}
+
+// SetSleeping stores the sleeping state in the lease before it marks the tracker as sleeping or awake
+func (s *SharedActivity) SetSleeping(ctx context.Context, sleeping bool) error {
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		lease, err := s.kubeClient.CoordinationV1().Leases(s.namespace).Get(ctx, s.name, metav1.GetOptions{})
+		if kerrors.IsNotFound(err) {
+			_, err = s.kubeClient.CoordinationV1().Leases(s.namespace).Create(ctx, s.newLease(sleeping), metav1.CreateOptions{})
+			return err
+		} else if err != nil {
+			return err
+		}
+
+		if lease.Annotations == nil {
+			lease.Annotations = map[string]string{}
+		}
+		lease.Annotations[SleepingAnnotation] = strconv.FormatBool(sleeping)
+		_, err = s.kubeClient.CoordinationV1().Leases(s.namespace).Update(ctx, lease, metav1.UpdateOptions{})
+		return err
+	})
+
+	s.tracker.SetSleeping(sleeping)
+	return err
+}
+
+func (s *SharedActivity) newLease(sleeping bool) *coordinationv1.Lease {
+	return &coordinationv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      s.name,
+			Namespace: s.namespace,
+			Annotations: map[string]string{
+				LastActivityAnnotation: strconv.FormatInt(s.tracker.LastActivity().Unix(), 10),
+				SleepingAnnotation:     strconv.FormatBool(sleeping),
+			},
+		},
+	}
+}
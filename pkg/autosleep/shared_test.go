@@ -0,0 +1,52 @@
+package autosleep
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestSharedActivity(t *testing.T) {
+	ctx := context.Background()
+	kubeClient := fake.NewSimpleClientset()
+	now := time.Now()
+
+	leaderTracker := NewActivityTracker()
+	leaderTracker.lastActivity = now.Add(-time.Hour)
+	leader := NewSharedActivity(kubeClient, "vcluster", "vcluster-activity", leaderTracker)
+	replicaTracker := NewActivityTracker()
+	replicaTracker.lastActivity = now.Add(-time.Hour)
+	replica := NewSharedActivity(kubeClient, "vcluster", "vcluster-activity", replicaTracker)
+
+	// the first sync creates the lease
+	assert.NilError(t, leader.Sync(ctx))
+	_, err := kubeClient.CoordinationV1().Leases("vcluster").Get(ctx, "vcluster-activity", metav1.GetOptions{})
+	assert.NilError(t, err)
+
+	// the controller of the leader stores the sleeping state for the other replicas
+	assert.NilError(t, leader.SetSleeping(ctx, true))
+	assert.Assert(t, leaderTracker.Sleeping())
+	assert.NilError(t, replica.Sync(ctx))
+	assert.Assert(t, replicaTracker.Sleeping())
+
+	// activity of another replica is published and wakes up the leader
+	replicaTracker.RecordActivity()
+	<-replicaTracker.WakeUpRequests()
+	assert.NilError(t, replica.Sync(ctx))
+	assert.NilError(t, leader.Sync(ctx))
+	assert.Assert(t, leaderTracker.InactiveFor(now) <= time.Second)
+	select {
+	case <-leaderTracker.WakeUpRequests():
+	default:
+		t.Fatal("expected wake up request")
+	}
+
+	// requests to other replicas wait until the leader woke up the workloads
+	assert.NilError(t, leader.SetSleeping(ctx, false))
+	assert.NilError(t, replica.Sync(ctx))
+	assert.Assert(t, !replicaTracker.Sleeping())
+}
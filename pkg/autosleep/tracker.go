@@ -0,0 +1,147 @@
+package autosleep
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DefaultTracker is the activity tracker used by the vCluster proxy and the auto sleep controller
+var DefaultTracker = NewActivityTracker()
+
+// ActivityTracker records when the virtual cluster was last used
+type ActivityTracker struct {
+	m sync.Mutex
+
+	lastActivity time.Time
+	runningPods  int
+
+	wakeUp chan struct{}
+
+	// awake is closed while the workloads are not sleeping
+	awake chan struct{}
+}
+
+// NewActivityTracker creates a new activity tracker that considers the virtual cluster active right now
+func NewActivityTracker() *ActivityTracker {
+	awake := make(chan struct{})
+	close(awake)
+	return &ActivityTracker{
+		lastActivity: time.Now(),
+		wakeUp:       make(chan struct{}, 1),
+		awake:        awake,
+	}
+}
+
+// RecordActivity marks the virtual cluster as active and signals a wake up request
+func (a *ActivityTracker) RecordActivity() {
+	a.recordActivityAt(time.Now())
+}
+
+// recordActivityAt records activity at the given time, which might come from another replica, and signals a wake up
+// request
+func (a *ActivityTracker) recordActivityAt(activity time.Time) {
+	a.m.Lock()
+	if activity.After(a.lastActivity) {
+		a.lastActivity = activity
+	}
+	a.m.Unlock()
+
+	// non-blocking as a single pending wake up request is enough
+	select {
+	case a.wakeUp <- struct{}{}:
+	default:
+	}
+}
+
+// LastActivity returns the time of the last recorded activity
+func (a *ActivityTracker) LastActivity() time.Time {
+	a.m.Lock()
+	defer a.m.Unlock()
+
+	return a.lastActivity
+}
+
+// SetRunningPods sets the amount of running pods that cannot be put to sleep
+func (a *ActivityTracker) SetRunningPods(runningPods int) {
+	a.m.Lock()
+	defer a.m.Unlock()
+
+	a.runningPods = runningPods
+}
+
+// RunningPods returns the amount of running pods that cannot be put to sleep
+func (a *ActivityTracker) RunningPods() int {
+	a.m.Lock()
+	defer a.m.Unlock()
+
+	return a.runningPods
+}
+
+// InactiveFor returns how long the virtual cluster was inactive. A virtual cluster with running
+// pods that cannot be scaled down is always considered active.
+func (a *ActivityTracker) InactiveFor(now time.Time) time.Duration {
+	a.m.Lock()
+	defer a.m.Unlock()
+
+	if a.runningPods > 0 {
+		return 0
+	}
+
+	return now.Sub(a.lastActivity)
+}
+
+// WakeUpRequests returns a channel that receives a value whenever new activity was recorded
+func (a *ActivityTracker) WakeUpRequests() <-chan struct{} {
+	return a.wakeUp
+}
+
+// SetSleeping marks the workloads of the virtual cluster as sleeping or awake. Requests waiting in WaitAwake continue
+// once the workloads are awake again.
+func (a *ActivityTracker) SetSleeping(sleeping bool) {
+	a.m.Lock()
+	defer a.m.Unlock()
+
+	if sleeping == a.sleeping() {
+		return
+	} else if sleeping {
+		a.awake = make(chan struct{})
+	} else {
+		close(a.awake)
+	}
+}
+
+// Sleeping returns true if the workloads of the virtual cluster are sleeping
+func (a *ActivityTracker) Sleeping() bool {
+	a.m.Lock()
+	defer a.m.Unlock()
+
+	return a.sleeping()
+}
+
+// WaitAwake records activity and waits until the workloads are awake, the context is done or the timeout passed
+func (a *ActivityTracker) WaitAwake(ctx context.Context, timeout time.Duration) error {
+	a.m.Lock()
+	awake := a.awake
+	a.m.Unlock()
+
+	a.RecordActivity()
+	select {
+	case <-awake:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(timeout):
+		return fmt.Errorf("virtual cluster workloads didn't wake up within %s", timeout.String())
+	}
+}
+
+func (a *ActivityTracker) sleeping() bool {
+	select {
+	case <-a.awake:
+		return false
+	default:
+		return true
+	}
+}
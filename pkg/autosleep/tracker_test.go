@@ -0,0 +1,58 @@
+package autosleep
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestActivityTracker(t *testing.T) {
+	tracker := NewActivityTracker()
+	now := time.Now()
+	tracker.lastActivity = now.Add(-time.Hour)
+	assert.Assert(t, tracker.InactiveFor(now) >= time.Hour)
+
+	// running pods that cannot be scaled down keep the virtual cluster active
+	tracker.SetRunningPods(1)
+	assert.Equal(t, tracker.InactiveFor(now), time.Duration(0))
+	tracker.SetRunningPods(0)
+
+	// activity resets the inactivity and signals a single wake up request
+	tracker.RecordActivity()
+	tracker.RecordActivity()
+	assert.Assert(t, tracker.InactiveFor(now) <= 0)
+	select {
+	case <-tracker.WakeUpRequests():
+	default:
+		t.Fatal("expected wake up request")
+	}
+	select {
+	case <-tracker.WakeUpRequests():
+		t.Fatal("expected only a single pending wake up request")
+	default:
+	}
+}
+
+func TestWaitAwake(t *testing.T) {
+	ctx := context.Background()
+	tracker := NewActivityTracker()
+	assert.Assert(t, !tracker.Sleeping())
+	assert.NilError(t, tracker.WaitAwake(ctx, time.Second))
+
+	// waiting requests continue once the workloads are awake
+	tracker.SetSleeping(true)
+	assert.Assert(t, tracker.Sleeping())
+	done := make(chan error)
+	go func() {
+		done <- tracker.WaitAwake(ctx, time.Minute)
+	}()
+	<-tracker.WakeUpRequests()
+	tracker.SetSleeping(false)
+	assert.NilError(t, <-done)
+
+	tracker.SetSleeping(true)
+	err := tracker.WaitAwake(ctx, 10*time.Millisecond)
+	assert.ErrorContains(t, err, "didn't wake up")
+}
@@ -0,0 +1,60 @@
+package certs
+
+import (
+	"crypto/ecdsa"
+	"crypto/x509"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/util/keyutil"
+)
+
+const (
+	// ServiceAccountPreviousPublicKeyName defines the public key of the previous SA signing key
+	ServiceAccountPreviousPublicKeyName = "sa-previous.pub"
+
+	// ServiceAccountKeyRotatedAnnotation defines the annotation on the certs secret with the time of the last SA key rotation
+	ServiceAccountKeyRotatedAnnotation = "vcluster.loft.sh/sa-key-rotated"
+)
+
+// RotateServiceAccountKeys replaces the service account signing key within the certs secret with a newly generated
+// key of the same type. The public key of the replaced key is kept as sa-previous.pub, so tokens signed before the
+// rotation stay valid and are still published in the jwks endpoint until the next rotation.
+func RotateServiceAccountKeys(secret *corev1.Secret) error {
+	currentPublicKey := secret.Data[certMap[ServiceAccountPublicKeyName]]
+	if len(currentPublicKey) == 0 {
+		return fmt.Errorf("secret %s/%s is missing %s", secret.Namespace, secret.Name, ServiceAccountPublicKeyName)
+	}
+
+	currentKey, err := keyutil.ParsePrivateKeyPEM(secret.Data[certMap[ServiceAccountPrivateKeyName]])
+	if err != nil {
+		return fmt.Errorf("parse %s: %w", ServiceAccountPrivateKeyName, err)
+	}
+	keyType := x509.RSA
+	if _, ok := currentKey.(*ecdsa.PrivateKey); ok {
+		keyType = x509.ECDSA
+	}
+
+	key, err := NewPrivateKey(keyType)
+	if err != nil {
+		return fmt.Errorf("generate %s key: %w", ServiceAccountKeyBaseName, err)
+	}
+	encodedKey, err := keyutil.MarshalPrivateKeyToPEM(key)
+	if err != nil {
+		return fmt.Errorf("marshal %s: %w", ServiceAccountPrivateKeyName, err)
+	}
+	encodedPublicKey, err := EncodePublicKeyPEM(key.Public())
+	if err != nil {
+		return fmt.Errorf("marshal %s: %w", ServiceAccountPublicKeyName, err)
+	}
+
+	secret.Data[ServiceAccountPreviousPublicKeyName] = currentPublicKey
+	secret.Data[certMap[ServiceAccountPrivateKeyName]] = encodedKey
+	secret.Data[certMap[ServiceAccountPublicKeyName]] = encodedPublicKey
+	if secret.Annotations == nil {
+		secret.Annotations = map[string]string{}
+	}
+	secret.Annotations[ServiceAccountKeyRotatedAnnotation] = time.Now().UTC().Format(time.RFC3339)
+	return nil
+}
@@ -0,0 +1,256 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/log/table"
+	"github.com/loft-sh/vcluster/pkg/cli/find"
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/loft-sh/vcluster/pkg/platform"
+	"golang.org/x/sync/errgroup"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/yaml"
+)
+
+// AddVClusterManifest lists the virtual clusters to add to the platform
+type AddVClusterManifest struct {
+	VClusters []AddVClusterTarget `json:"vclusters,omitempty"`
+}
+
+// AddVClusterTarget is a virtual cluster to add to the platform
+type AddVClusterTarget struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+	// Context is the kube context of the virtual cluster, defaults to the current context
+	Context string `json:"context,omitempty"`
+	// Project defaults to the --project flag
+	Project string `json:"project,omitempty"`
+	// ImportName is the name of the virtual cluster in the project, defaults to the name
+	ImportName string `json:"importName,omitempty"`
+}
+
+type addVClusterResult struct {
+	changed bool
+	err     error
+}
+
+// AddVClustersHelm adds the virtual clusters of the manifest or all virtual clusters of the current context to the
+// platform. The virtual clusters are added concurrently and the status of each virtual cluster is reported.
+func AddVClustersHelm(ctx context.Context, options *AddVClusterOptions, globalFlags *flags.GlobalFlags, log log.Logger) error {
+	targets, err := addVClusterTargets(ctx, options, globalFlags, log)
+	if err != nil {
+		return err
+	} else if len(targets) == 0 {
+		log.Info("No virtual clusters found to add")
+		return nil
+	}
+	err = validateAddVClusterTargets(targets)
+	if err != nil {
+		return err
+	}
+
+	// all virtual clusters share the access key of the CLI, so it is created once before adding them
+	if options.AccessKey == "" || options.Host == "" {
+		err = platform.EnsureVirtualClusterAccessKey(ctx, globalFlags.LoadedConfig(log))
+		if err != nil {
+			return err
+		}
+	}
+
+	concurrency := options.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	log.Infof("Adding %d virtual clusters to the platform...", len(targets))
+	results := make([]addVClusterResult, len(targets))
+	finished := 0
+	lock := sync.Mutex{}
+	group := errgroup.Group{}
+	group.SetLimit(concurrency)
+	for i, target := range targets {
+		group.Go(func() error {
+			changed, err := addVClusterTarget(ctx, options, globalFlags, target)
+
+			lock.Lock()
+			defer lock.Unlock()
+			results[i] = addVClusterResult{changed: changed, err: err}
+			finished++
+			if err != nil {
+				log.Warnf("[%d/%d] Failed to add vcluster %s/%s: %v", finished, len(targets), target.Namespace, target.Name, err)
+			} else {
+				log.Donef("[%d/%d] %s vcluster %s/%s", finished, len(targets), addVClusterStatus(results[i]), target.Namespace, target.Name)
+			}
+			return nil
+		})
+	}
+	_ = group.Wait()
+
+	failed := 0
+	rows := [][]string{}
+	for i, target := range targets {
+		if results[i].err != nil {
+			failed++
+		}
+		rows = append(rows, []string{target.Namespace, target.Name, target.Project, addVClusterStatus(results[i])})
+	}
+	table.PrintTable(log, []string{"NAMESPACE", "NAME", "PROJECT", "STATUS"}, rows)
+	if failed > 0 {
+		return fmt.Errorf("failed to add %d of %d virtual clusters", failed, len(targets))
+	}
+
+	return nil
+}
+
+// addVClusterTarget adds a virtual cluster of the batch. The output of the virtual clusters would interleave, so it
+// is discarded and only their status is reported.
+func addVClusterTarget(ctx context.Context, options *AddVClusterOptions, globalFlags *flags.GlobalFlags, target AddVClusterTarget) (bool, error) {
+	targetFlags := *globalFlags
+	targetFlags.Context = target.Context
+	targetFlags.Namespace = target.Namespace
+	targetOptions := *options
+	targetOptions.Project = target.Project
+	targetOptions.ImportName = target.ImportName
+	return addVClusterHelm(ctx, &targetOptions, &targetFlags, target.Name, log.Discard)
+}
+
+func addVClusterStatus(result addVClusterResult) string {
+	switch {
+	case result.err != nil:
+		return "Failed"
+	case result.changed:
+		return "Added"
+	default:
+		return "Already added"
+	}
+}
+
+// addVClusterTargets returns the virtual clusters of the manifest or the virtual clusters found in the current context
+func addVClusterTargets(ctx context.Context, options *AddVClusterOptions, globalFlags *flags.GlobalFlags, log log.Logger) ([]AddVClusterTarget, error) {
+	if options.Manifest != "" {
+		out, err := os.ReadFile(options.Manifest)
+		if err != nil {
+			return nil, fmt.Errorf("read manifest: %w", err)
+		}
+
+		return parseAddVClusterManifest(out, options.Project, globalFlags)
+	}
+
+	selector, err := labels.Parse(options.NamespaceSelector)
+	if err != nil {
+		return nil, fmt.Errorf("parse namespace selector: %w", err)
+	}
+	vClusters, err := find.ListVClusters(ctx, globalFlags.Context, "", globalFlags.Namespace, log)
+	if err != nil {
+		return nil, err
+	}
+
+	// namespaces matching the selector by kube context
+	selectedNamespaces := map[string]map[string]bool{}
+	targets := []AddVClusterTarget{}
+	for _, vCluster := range vClusters {
+		if options.NamespaceSelector != "" {
+			namespaces, ok := selectedNamespaces[vCluster.Context]
+			if !ok {
+				namespaces, err = listSelectedNamespaces(ctx, &vCluster, selector)
+				if err != nil {
+					return nil, err
+				}
+				selectedNamespaces[vCluster.Context] = namespaces
+			}
+			if !namespaces[vCluster.Namespace] {
+				continue
+			}
+		}
+
+		targets = append(targets, AddVClusterTarget{
+			Name:      vCluster.Name,
+			Namespace: vCluster.Namespace,
+			Context:   vCluster.Context,
+			Project:   options.Project,
+		})
+	}
+
+	return targets, nil
+}
+
+func parseAddVClusterManifest(out []byte, project string, globalFlags *flags.GlobalFlags) ([]AddVClusterTarget, error) {
+	manifest := &AddVClusterManifest{}
+	err := yaml.UnmarshalStrict(out, manifest)
+	if err != nil {
+		return nil, fmt.Errorf("parse manifest: %w", err)
+	}
+
+	for i := range manifest.VClusters {
+		if manifest.VClusters[i].Name == "" {
+			return nil, fmt.Errorf("vclusters[%d].name is required", i)
+		}
+		if manifest.VClusters[i].Context == "" {
+			manifest.VClusters[i].Context = globalFlags.Context
+		}
+		if manifest.VClusters[i].Namespace == "" {
+			manifest.VClusters[i].Namespace = globalFlags.Namespace
+		}
+		if manifest.VClusters[i].Project == "" {
+			manifest.VClusters[i].Project = project
+		}
+	}
+
+	return manifest.VClusters, nil
+}
+
+func listSelectedNamespaces(ctx context.Context, vCluster *find.VCluster, selector labels.Selector) (map[string]bool, error) {
+	restConfig, err := vCluster.ClientFactory.ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+	kubeClient, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	namespaceList, err := kubeClient.CoreV1().Namespaces().List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, fmt.Errorf("list namespaces: %w", err)
+	}
+
+	namespaces := map[string]bool{}
+	for _, namespace := range namespaceList.Items {
+		namespaces[namespace.Name] = true
+	}
+	return namespaces, nil
+}
+
+// validateAddVClusterTargets makes sure no two virtual clusters would be added with the same name to a project
+func validateAddVClusterTargets(targets []AddVClusterTarget) error {
+	names := map[string][]string{}
+	for _, target := range targets {
+		name := target.ImportName
+		if name == "" {
+			name = target.Name
+		}
+
+		key := target.Project + "/" + name
+		names[key] = append(names[key], target.Namespace+"/"+target.Name)
+	}
+
+	conflicts := []string{}
+	for key, vClusters := range names {
+		if len(vClusters) > 1 {
+			conflicts = append(conflicts, fmt.Sprintf("%s (%s)", key, strings.Join(vClusters, ", ")))
+		}
+	}
+	if len(conflicts) > 0 {
+		sort.Strings(conflicts)
+		return fmt.Errorf("multiple virtual clusters would be added with the same name to a project: %s. Please use a manifest with an importName for each of them", strings.Join(conflicts, "; "))
+	}
+
+	return nil
+}
@@ -0,0 +1,48 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"gotest.tools/v3/assert"
+)
+
+func TestParseAddVClusterManifest(t *testing.T) {
+	globalFlags := &flags.GlobalFlags{Context: "kind", Namespace: "vcluster"}
+	manifest := []byte(`vclusters:
+- name: a
+- name: b
+  namespace: team-b
+  context: prod
+  project: team-b
+  importName: b-prod
+`)
+
+	targets, err := parseAddVClusterManifest(manifest, "default", globalFlags)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, targets, []AddVClusterTarget{
+		{Name: "a", Namespace: "vcluster", Context: "kind", Project: "default"},
+		{Name: "b", Namespace: "team-b", Context: "prod", Project: "team-b", ImportName: "b-prod"},
+	})
+
+	_, err = parseAddVClusterManifest([]byte("vclusters:\n- namespace: team-a\n"), "default", globalFlags)
+	assert.ErrorContains(t, err, "vclusters[0].name is required")
+
+	_, err = parseAddVClusterManifest([]byte("vclusters:\n- name: a\n  unknown: true\n"), "default", globalFlags)
+	assert.ErrorContains(t, err, "parse manifest")
+}
+
+func TestValidateAddVClusterTargets(t *testing.T) {
+	err := validateAddVClusterTargets([]AddVClusterTarget{
+		{Name: "a", Namespace: "team-a", Project: "default"},
+		{Name: "a", Namespace: "team-b", Project: "other"},
+		{Name: "a", Namespace: "team-c", Project: "default", ImportName: "a-c"},
+	})
+	assert.NilError(t, err)
+
+	err = validateAddVClusterTargets([]AddVClusterTarget{
+		{Name: "a", Namespace: "team-a", Project: "default"},
+		{Name: "a", Namespace: "team-b", Project: "default"},
+	})
+	assert.ErrorContains(t, err, "default/a (team-a/a, team-b/a)")
+}
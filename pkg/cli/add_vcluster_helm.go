@@ -19,6 +19,15 @@ type AddVClusterOptions struct {
 	Insecure   bool
 	AccessKey  string
 	Host       string
+
+	// All adds all virtual clusters of the current context, or of the namespace if one is given
+	All bool
+	// NamespaceSelector limits All to the virtual clusters in namespaces matching the label selector
+	NamespaceSelector string
+	// Manifest is the path to a file listing the virtual clusters to add
+	Manifest string
+	// Concurrency is the number of virtual clusters that are added in parallel
+	Concurrency int
 }
 
 func AddVClusterHelm(
@@ -28,24 +37,36 @@ func AddVClusterHelm(
 	vClusterName string,
 	log log.Logger,
 ) error {
+	_, err := addVClusterHelm(ctx, options, globalFlags, vClusterName, log)
+	return err
+}
+
+// addVClusterHelm adds the virtual cluster to the platform and returns false if it was already added
+func addVClusterHelm(
+	ctx context.Context,
+	options *AddVClusterOptions,
+	globalFlags *flags.GlobalFlags,
+	vClusterName string,
+	log log.Logger,
+) (bool, error) {
 	// check if vCluster exists
 	vCluster, err := find.GetVCluster(ctx, globalFlags.Context, vClusterName, globalFlags.Namespace, log)
 	if err != nil {
-		return err
+		return false, err
 	}
 
 	// create kube client
 	restConfig, err := vCluster.ClientFactory.ClientConfig()
 	if err != nil {
-		return err
+		return false, err
 	}
 	kubeClient, err := kubernetes.NewForConfig(restConfig)
 	if err != nil {
-		return err
+		return false, err
 	}
 
 	// apply platform secret
-	err = platform.ApplyPlatformSecret(
+	changed, err := platform.ApplyPlatformSecret(
 		ctx,
 		globalFlags.LoadedConfig(log),
 		kubeClient,
@@ -57,17 +78,22 @@ func AddVClusterHelm(
 		options.Insecure,
 	)
 	if err != nil {
-		return err
+		return false, err
+	}
+
+	if !changed {
+		log.Donef("vCluster %s/%s is already added", vCluster.Namespace, vCluster.Name)
+		return false, nil
 	}
 
 	// restart vCluster
 	if options.Restart {
 		err = lifecycle.DeletePods(ctx, kubeClient, "app=vcluster,release="+vCluster.Name, vCluster.Namespace, log)
 		if err != nil {
-			return fmt.Errorf("delete vcluster workloads: %w", err)
+			return false, fmt.Errorf("delete vcluster workloads: %w", err)
 		}
 	}
 
 	log.Donef("Successfully added vCluster %s/%s", vCluster.Namespace, vCluster.Name)
-	return nil
+	return true, nil
 }
@@ -0,0 +1,173 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/user"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	managementv1 "github.com/loft-sh/api/v4/pkg/apis/management/v1"
+	"github.com/loft-sh/vcluster/pkg/cli/config"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// webhookTimeout is the time to wait for the audit webhook to accept an event
+var webhookTimeout = 5 * time.Second
+
+// platformVerbs are the subcommands of vcluster platform that change the platform or grant access to it
+var platformVerbs = []string{"activate", "add", "connect", "create", "delete", "reset", "restore", "rotate-key", "set", "share", "sleep", "start", "upgrade", "wakeup"}
+
+// driverVerbs are the top level commands that change the platform if they use the platform driver
+var driverVerbs = []string{"connect", "create", "delete", "pause", "resume"}
+
+// sensitiveFlags are fragments of flag names or values that refer to secrets, their values are not recorded
+var sensitiveFlags = []string{"access-key", "key", "password", "secret", "token"}
+
+// Event is a mutating platform command recorded in the audit log
+type Event struct {
+	Time time.Time `json:"time"`
+	// User is the platform user that ran the command
+	User string `json:"user,omitempty"`
+	// LocalUser is the user of the workstation that ran the command
+	LocalUser string `json:"localUser,omitempty"`
+	// Host is the platform the command ran against
+	Host    string `json:"host,omitempty"`
+	Command string `json:"command"`
+	// Target is the name of the object the command changed
+	Target string `json:"target,omitempty"`
+	// Flags are the flags that were set, values of sensitive flags are redacted
+	Flags map[string]string `json:"flags,omitempty"`
+	Error string            `json:"error,omitempty"`
+}
+
+// IsMutating returns true if the command changes the platform or grants access to it
+func IsMutating(cobraCmd *cobra.Command, driver config.DriverType) bool {
+	path := strings.Fields(cobraCmd.CommandPath())
+	if len(path) < 2 {
+		return false
+	} else if path[1] == "platform" {
+		return len(path) > 2 && slices.Contains(platformVerbs, path[2])
+	}
+
+	if flag := cobraCmd.Flags().Lookup("driver"); flag != nil && flag.Changed {
+		driver = config.DriverType(flag.Value.String())
+	}
+	return len(path) == 2 && driver == config.PlatformDriver && slices.Contains(driverVerbs, path[1])
+}
+
+// NewEvent returns the audit event of the executed command
+func NewEvent(cobraCmd *cobra.Command, cliConfig *config.CLI, self *managementv1.Self, err error) Event {
+	event := Event{
+		Time:    time.Now().UTC(),
+		Host:    cliConfig.Platform.Host,
+		Command: strings.Join(strings.Fields(cobraCmd.CommandPath())[1:], " "),
+		Flags:   map[string]string{},
+	}
+	if self != nil {
+		event.User = self.Status.Subject
+		if self.Status.User != nil && self.Status.User.Email != "" {
+			event.User = self.Status.User.Email
+		}
+	}
+	if localUser, err := user.Current(); err == nil {
+		event.LocalUser = localUser.Username
+	}
+
+	// only the first argument is recorded, further arguments might be secret values
+	if args := cobraCmd.Flags().Args(); len(args) > 0 {
+		event.Target = args[0]
+	}
+	cobraCmd.Flags().Visit(func(flag *pflag.Flag) {
+		event.Flags[flag.Name] = flagValue(flag)
+	})
+	if err != nil {
+		event.Error = err.Error()
+	}
+
+	return event
+}
+
+// Record appends the event to the audit log and posts it to the audit webhook if one is configured
+func Record(ctx context.Context, cliConfig *config.CLI, event Event) error {
+	out, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	path, err := cliConfig.AuditPath()
+	if err != nil {
+		return err
+	}
+	err = appendLine(path, out)
+	if err != nil {
+		return fmt.Errorf("write audit log: %w", err)
+	}
+
+	if cliConfig.Audit.Webhook != "" {
+		err = postEvent(ctx, cliConfig.Audit.Webhook, out)
+		if err != nil {
+			return fmt.Errorf("post audit event: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func appendLine(path string, line []byte) error {
+	err := os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = file.Write(append(line, '\n'))
+	return err
+}
+
+func postEvent(ctx context.Context, webhook string, event []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, webhookTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook, bytes.NewReader(event))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// flagValue returns the value of the flag or a placeholder if the flag or its value, e.g. --set admin.password=,
+// refer to a secret
+func flagValue(flag *pflag.Flag) string {
+	value := flag.Value.String()
+	for _, sensitive := range sensitiveFlags {
+		if strings.Contains(flag.Name, sensitive) || strings.Contains(strings.ToLower(value), sensitive) {
+			return "***"
+		}
+	}
+
+	return value
+}
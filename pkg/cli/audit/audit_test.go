@@ -0,0 +1,87 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	managementv1 "github.com/loft-sh/api/v4/pkg/apis/management/v1"
+	"github.com/loft-sh/vcluster/pkg/cli/config"
+	"github.com/spf13/cobra"
+	"gotest.tools/v3/assert"
+)
+
+func newTestCommand(t *testing.T, args ...string) *cobra.Command {
+	var executed *cobra.Command
+	run := func(cobraCmd *cobra.Command, _ []string) { executed = cobraCmd }
+
+	root := &cobra.Command{Use: "vcluster"}
+	createCmd := &cobra.Command{Use: "create", Run: run}
+	createCmd.Flags().String("driver", "", "")
+	createCmd.Flags().StringArray("set", nil, "")
+	platformCmd := &cobra.Command{Use: "platform"}
+	platformCreateCmd := &cobra.Command{Use: "create"}
+	platformCreateVClusterCmd := &cobra.Command{Use: "vcluster", Run: run}
+	platformCreateVClusterCmd.Flags().String("project", "", "")
+	platformCreateVClusterCmd.Flags().String("access-key", "", "")
+	platformListCmd := &cobra.Command{Use: "list", Run: run}
+	platformCreateCmd.AddCommand(platformCreateVClusterCmd)
+	platformCmd.AddCommand(platformCreateCmd, platformListCmd)
+	root.AddCommand(createCmd, platformCmd)
+
+	root.SetArgs(args)
+	assert.NilError(t, root.Execute())
+	return executed
+}
+
+func TestIsMutating(t *testing.T) {
+	assert.Assert(t, IsMutating(newTestCommand(t, "platform", "create", "vcluster", "my-vcluster"), config.HelmDriver))
+	assert.Assert(t, !IsMutating(newTestCommand(t, "platform", "list"), config.PlatformDriver))
+	assert.Assert(t, IsMutating(newTestCommand(t, "create", "my-vcluster"), config.PlatformDriver))
+	assert.Assert(t, !IsMutating(newTestCommand(t, "create", "my-vcluster"), config.HelmDriver))
+	assert.Assert(t, IsMutating(newTestCommand(t, "create", "my-vcluster", "--driver", "platform"), config.HelmDriver))
+}
+
+func TestRecord(t *testing.T) {
+	received := []Event{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		event := Event{}
+		assert.NilError(t, json.NewDecoder(r.Body).Decode(&event))
+		received = append(received, event)
+	}))
+	defer server.Close()
+
+	cliConfig := config.New()
+	cliConfig.Platform.Host = "https://platform.example.com"
+	cliConfig.Audit.Path = filepath.Join(t.TempDir(), "audit.log")
+	cliConfig.Audit.Webhook = server.URL
+	self := &managementv1.Self{Status: managementv1.SelfStatus{Subject: "admin"}}
+
+	cobraCmd := newTestCommand(t, "platform", "create", "vcluster", "my-vcluster", "--project", "default", "--access-key", "abc")
+	for i := 0; i < 2; i++ {
+		assert.NilError(t, Record(context.Background(), cliConfig, NewEvent(cobraCmd, cliConfig, self, nil)))
+	}
+
+	out, err := os.ReadFile(cliConfig.Audit.Path)
+	assert.NilError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	assert.Equal(t, len(lines), 2)
+
+	event := Event{}
+	assert.NilError(t, json.Unmarshal([]byte(lines[0]), &event))
+	assert.Equal(t, event.Command, "platform create vcluster")
+	assert.Equal(t, event.Target, "my-vcluster")
+	assert.Equal(t, event.User, "admin")
+	assert.Equal(t, event.Host, "https://platform.example.com")
+	assert.DeepEqual(t, event.Flags, map[string]string{"project": "default", "access-key": "***"})
+	assert.Equal(t, len(received), 2)
+
+	cobraCmd = newTestCommand(t, "create", "my-vcluster", "--set", "admin.password=secret")
+	event = NewEvent(cobraCmd, cliConfig, nil, nil)
+	assert.Equal(t, event.Flags["set"], "***")
+}
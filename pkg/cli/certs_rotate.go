@@ -0,0 +1,120 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/vcluster/pkg/certs"
+	"github.com/loft-sh/vcluster/pkg/cli/find"
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/loft-sh/vcluster/pkg/lifecycle"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+)
+
+type CertsRotateOptions struct {
+	// SAKeys rotates the service account signing keys
+	SAKeys bool
+
+	// Interval rotates the keys repeatedly in this interval until the command is stopped
+	Interval time.Duration
+
+	// Timeout is the time to wait for the virtual cluster to become ready with the new keys
+	Timeout time.Duration
+}
+
+// CertsRotate rotates the keys of the virtual cluster selected by the options and restarts the virtual cluster,
+// so the new keys are picked up.
+func CertsRotate(ctx context.Context, options *CertsRotateOptions, globalFlags *flags.GlobalFlags, vClusterName string, log log.Logger) error {
+	if !options.SAKeys {
+		return fmt.Errorf("nothing to rotate, please specify --sa-keys")
+	}
+
+	for {
+		err := certsRotate(ctx, options, globalFlags, vClusterName, log)
+		if err != nil || options.Interval <= 0 {
+			return err
+		}
+
+		log.Infof("Next rotation of the service account keys of vcluster %s in %s", vClusterName, options.Interval.String())
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(options.Interval):
+		}
+	}
+}
+
+func certsRotate(ctx context.Context, options *CertsRotateOptions, globalFlags *flags.GlobalFlags, vClusterName string, log log.Logger) error {
+	vCluster, err := find.GetVCluster(ctx, globalFlags.Context, vClusterName, globalFlags.Namespace, log)
+	if err != nil {
+		return err
+	}
+	restConfig, err := vCluster.ClientFactory.ClientConfig()
+	if err != nil {
+		return err
+	}
+	kubeClient, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+
+	err = rotateServiceAccountKeys(ctx, kubeClient, vCluster.Name, vCluster.Namespace)
+	if err != nil {
+		return err
+	}
+	log.Donef("Stored new service account signing key in certs secret of vCluster %s/%s", vCluster.Namespace, vCluster.Name)
+
+	// the certs secret is only read on startup
+	restarted := time.Now()
+	labelSelector := "app=vcluster,release=" + vCluster.Name
+	err = lifecycle.DeletePods(ctx, kubeClient, labelSelector, vCluster.Namespace, log)
+	if err != nil {
+		return fmt.Errorf("delete vcluster workloads: %w", err)
+	}
+
+	log.Infof("Waiting for vCluster %s/%s to restart with the new service account signing key...", vCluster.Namespace, vCluster.Name)
+	err = wait.PollUntilContextTimeout(ctx, 2*time.Second, options.Timeout, true, func(ctx context.Context) (bool, error) {
+		pods, err := kubeClient.CoreV1().Pods(vCluster.Namespace).List(ctx, metav1.ListOptions{LabelSelector: labelSelector})
+		if err != nil {
+			return false, err
+		}
+
+		return restartedPodsReady(pods.Items, restarted), nil
+	})
+	if err != nil {
+		return fmt.Errorf("vCluster %s/%s didn't become ready with the new service account signing key: %w", vCluster.Namespace, vCluster.Name, err)
+	}
+
+	log.Donef("Successfully rotated service account keys of vCluster %s/%s, tokens signed with the previous key stay valid until the next rotation", vCluster.Namespace, vCluster.Name)
+	return nil
+}
+
+// rotateServiceAccountKeys replaces the service account signing key in the certs secret of the virtual cluster
+func rotateServiceAccountKeys(ctx context.Context, kubeClient kubernetes.Interface, vClusterName, namespace string) error {
+	secretName := vClusterName + "-certs"
+	secret, err := kubeClient.CoreV1().Secrets(namespace).Get(ctx, secretName, metav1.GetOptions{})
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return fmt.Errorf("couldn't find certs secret %s/%s, service account key rotation is only supported for the k8s and eks distros", namespace, secretName)
+		}
+
+		return fmt.Errorf("get certs secret: %w", err)
+	}
+
+	err = certs.RotateServiceAccountKeys(secret)
+	if err != nil {
+		return err
+	}
+
+	_, err = kubeClient.CoreV1().Secrets(namespace).Update(ctx, secret, metav1.UpdateOptions{})
+	if err != nil {
+		return fmt.Errorf("update certs secret: %w", err)
+	}
+
+	return nil
+}
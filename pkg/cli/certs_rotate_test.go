@@ -0,0 +1,54 @@
+package cli
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/x509"
+	"testing"
+
+	"github.com/loft-sh/vcluster/pkg/certs"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/util/keyutil"
+)
+
+func TestRotateServiceAccountKeys(t *testing.T) {
+	key, err := certs.NewPrivateKey(x509.ECDSA)
+	assert.NilError(t, err)
+	encodedKey, err := keyutil.MarshalPrivateKeyToPEM(key)
+	assert.NilError(t, err)
+	encodedPublicKey, err := certs.EncodePublicKeyPEM(key.Public())
+	assert.NilError(t, err)
+
+	kubeClient := fake.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-vcluster-certs", Namespace: "vcluster-ns"},
+		Data: map[string][]byte{
+			certs.ServiceAccountPrivateKeyName: encodedKey,
+			certs.ServiceAccountPublicKeyName:  encodedPublicKey,
+		},
+	})
+
+	err = rotateServiceAccountKeys(context.Background(), kubeClient, "my-vcluster", "vcluster-ns")
+	assert.NilError(t, err)
+
+	secret, err := kubeClient.CoreV1().Secrets("vcluster-ns").Get(context.Background(), "my-vcluster-certs", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Assert(t, secret.Annotations[certs.ServiceAccountKeyRotatedAnnotation] != "")
+	assert.DeepEqual(t, secret.Data[certs.ServiceAccountPreviousPublicKeyName], encodedPublicKey)
+	assert.Assert(t, string(secret.Data[certs.ServiceAccountPublicKeyName]) != string(encodedPublicKey))
+
+	// the new key keeps the key type and matches the new public key
+	newKey, err := keyutil.ParsePrivateKeyPEM(secret.Data[certs.ServiceAccountPrivateKeyName])
+	assert.NilError(t, err)
+	ecdsaKey, ok := newKey.(*ecdsa.PrivateKey)
+	assert.Assert(t, ok, "expected ecdsa key")
+	newPublicKey, err := certs.EncodePublicKeyPEM(ecdsaKey.Public())
+	assert.NilError(t, err)
+	assert.DeepEqual(t, secret.Data[certs.ServiceAccountPublicKeyName], newPublicKey)
+
+	// virtual clusters without certs secret are rejected
+	err = rotateServiceAccountKeys(context.Background(), kubeClient, "other", "vcluster-ns")
+	assert.ErrorContains(t, err, "couldn't find certs secret")
+}
@@ -0,0 +1,85 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/vcluster/pkg/cli/cleanup"
+)
+
+const ociChartMediaType = "application/vnd.cncf.helm.chart.content.v1.tar+gzip"
+
+// pullOCIChart downloads the chart of an oci:// chart repository into a temp file, so helm installs the chart from
+// disk and doesn't need a helm registry login. The credentials are taken from the docker config if none are given.
+func pullOCIChart(ctx context.Context, chartName, chartVersion, chartRepo string, credentials registryCredentials, log log.Logger) (string, *cleanup.Handle, error) {
+	// helm replaces the + of semver build metadata, as it's not allowed in oci tags
+	tag := strings.ReplaceAll(strings.TrimPrefix(chartVersion, "v"), "+", "_")
+	reference := strings.TrimSuffix(strings.TrimPrefix(chartRepo, "oci://"), "/") + "/" + chartName + ":" + tag
+
+	rawChart, err := fetchOCIChart(ctx, reference, http.DefaultClient, credentials)
+	if err != nil {
+		return "", nil, fmt.Errorf("pull chart oci://%s: %w", reference, err)
+	}
+
+	temp, tempCleanup, err := cleanup.TempFile(fmt.Sprintf("%s-%s.tgz-", chartName, tag))
+	if err != nil {
+		return "", nil, fmt.Errorf("create temp chart file: %w", err)
+	}
+	_, err = temp.Write(rawChart)
+	_ = temp.Close()
+	if err != nil {
+		_ = tempCleanup.Run()
+		return "", nil, fmt.Errorf("write temp chart file: %w", err)
+	}
+
+	log.Debugf("Using chart pulled from oci://%s", reference)
+	return temp.Name(), tempCleanup, nil
+}
+
+// fetchOCIChart downloads the chart layer of the helm chart artifact with the given reference
+func fetchOCIChart(ctx context.Context, reference string, httpClient *http.Client, credentials registryCredentials) ([]byte, error) {
+	registry, repository, tagOrDigest, err := parseOCIReference(reference)
+	if err != nil {
+		return nil, err
+	}
+
+	client := &ociClient{
+		httpClient:  httpClient,
+		registry:    registry,
+		credentials: credentials,
+	}
+
+	rawManifest, err := client.get(ctx, fmt.Sprintf("/v2/%s/manifests/%s", repository, tagOrDigest), strings.Join(ociManifestMediaTypes, ", "))
+	if err != nil {
+		return nil, fmt.Errorf("get manifest: %w", err)
+	}
+
+	manifest := &ociManifest{}
+	err = json.Unmarshal(rawManifest, manifest)
+	if err != nil {
+		return nil, fmt.Errorf("parse manifest: %w", err)
+	}
+
+	for _, layer := range manifest.Layers {
+		if layer.MediaType != ociChartMediaType {
+			continue
+		}
+
+		rawChart, err := client.get(ctx, fmt.Sprintf("/v2/%s/blobs/%s", repository, layer.Digest), "")
+		if err != nil {
+			return nil, fmt.Errorf("get layer %s: %w", layer.Digest, err)
+		}
+		err = verifyDigest(rawChart, layer.Digest)
+		if err != nil {
+			return nil, fmt.Errorf("verify layer: %w", err)
+		}
+
+		return rawChart, nil
+	}
+
+	return nil, fmt.Errorf("artifact %s is not a helm chart, found no layer with media type %s", reference, ociChartMediaType)
+}
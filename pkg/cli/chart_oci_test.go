@@ -0,0 +1,56 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestFetchOCIChart(t *testing.T) {
+	chart := []byte("chart-tarball")
+	chartDigest := sha256Digest(chart)
+	manifests := map[string][]byte{}
+	for tag, mediaType := range map[string]string{"0.20.0_build.1": ociChartMediaType, "values": "application/vnd.oci.image.layer.v1.tar"} {
+		manifest, err := json.Marshal(map[string]interface{}{
+			"schemaVersion": 2,
+			"layers":        []map[string]interface{}{{"mediaType": mediaType, "digest": chartDigest}},
+		})
+		assert.NilError(t, err)
+		manifests["/v2/charts/vcluster/manifests/"+tag] = manifest
+	}
+
+	registry := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username, password, ok := r.BasicAuth()
+		if !ok || username != "user" || password != "secret" {
+			w.Header().Set("WWW-Authenticate", `Basic realm="test"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		if manifest, ok := manifests[r.URL.Path]; ok {
+			_, _ = w.Write(manifest)
+		} else if r.URL.Path == "/v2/charts/vcluster/blobs/"+chartDigest {
+			_, _ = w.Write(chart)
+		} else {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer registry.Close()
+	registryHost := strings.TrimPrefix(registry.URL, "https://")
+	credentials := func(string) (string, string) { return "user", "secret" }
+
+	rawChart, err := fetchOCIChart(context.Background(), registryHost+"/charts/vcluster:0.20.0_build.1", registry.Client(), credentials)
+	assert.NilError(t, err)
+	assert.Equal(t, string(rawChart), string(chart))
+
+	_, err = fetchOCIChart(context.Background(), registryHost+"/charts/vcluster:values", registry.Client(), credentials)
+	assert.ErrorContains(t, err, "is not a helm chart")
+
+	_, err = fetchOCIChart(context.Background(), registryHost+"/charts/vcluster:0.20.0_build.1", registry.Client(), nil)
+	assert.ErrorContains(t, err, "requires credentials")
+}
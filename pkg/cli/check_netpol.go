@@ -0,0 +1,398 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/log/table"
+	"github.com/loft-sh/vcluster/pkg/cli/cleanup"
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/loft-sh/vcluster/pkg/util/random"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+const (
+	netpolCheckLabel      = "vcluster.loft.sh/netpol-check"
+	netpolCheckRoleLabel  = "vcluster.loft.sh/netpol-check-role"
+	netpolCheckServerPort = 8080
+)
+
+type CheckNetworkPolicyOptions struct {
+	Output string
+
+	// Timeout is the time a single check may take until the expected result is observed
+	Timeout time.Duration
+
+	ServerImage string
+	ClientImage string
+}
+
+// NetworkPolicyCheckResult is the result of a single network policy check
+type NetworkPolicyCheckResult struct {
+	Name     string `json:"name"`
+	Expected string `json:"expected"`
+	Actual   string `json:"actual"`
+	Passed   bool   `json:"passed"`
+	Error    string `json:"error,omitempty"`
+}
+
+type networkPolicyCase struct {
+	name    string
+	allowed bool
+
+	// policies returns the network policies of the case for the given server and client namespace
+	policies func(server, client *corev1.Namespace) []*networkingv1.NetworkPolicy
+}
+
+// CheckNetworkPolicies creates a server and client in two namespaces of the current (virtual) cluster and checks if
+// the host CNI enforces the network policies created within the virtual cluster like a native cluster would.
+func CheckNetworkPolicies(ctx context.Context, options *CheckNetworkPolicyOptions, globalFlags *flags.GlobalFlags, log log.Logger) error {
+	restConfig, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(clientcmd.NewDefaultClientConfigLoadingRules(), &clientcmd.ConfigOverrides{
+		CurrentContext: globalFlags.Context,
+	}).ClientConfig()
+	if err != nil {
+		return err
+	}
+	kubeClient, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+
+	checkID := random.String(6)
+	namespaces := []*corev1.Namespace{}
+	namespacesCleanup := cleanup.Add("delete network policy check namespaces", func() error {
+		for _, namespace := range namespaces {
+			err := kubeClient.CoreV1().Namespaces().Delete(context.Background(), namespace.Name, metav1.DeleteOptions{})
+			if err != nil && !kerrors.IsNotFound(err) {
+				return err
+			}
+		}
+
+		return nil
+	})
+	defer func() {
+		_ = namespacesCleanup.Run()
+	}()
+
+	for _, role := range []string{"server", "client"} {
+		namespace, err := kubeClient.CoreV1().Namespaces().Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name:   "vcluster-netpol-check-" + role + "-" + checkID,
+			Labels: map[string]string{netpolCheckLabel: checkID, netpolCheckRoleLabel: role},
+		}}, metav1.CreateOptions{})
+		if err != nil {
+			return fmt.Errorf("create namespace: %w", err)
+		}
+
+		namespaces = append(namespaces, namespace)
+	}
+	server, client := namespaces[0], namespaces[1]
+
+	log.Infof("Starting network policy check server in namespace %s...", server.Name)
+	serverAddress, err := startNetpolCheckServer(ctx, kubeClient, server.Name, options)
+	if err != nil {
+		return err
+	}
+
+	probes := 0
+	probe := func(ctx context.Context) (bool, error) {
+		probes++
+		return probeNetpolCheckServer(ctx, kubeClient, client.Name, fmt.Sprintf("probe-%d", probes), serverAddress, options)
+	}
+	apply := func(ctx context.Context, c networkPolicyCase) error {
+		return applyNetworkPolicyCase(ctx, kubeClient, server, client, c)
+	}
+
+	results := runNetworkPolicyChecks(ctx, networkPolicyCases(), apply, probe, options.Timeout, log)
+	if options.Output == "json" {
+		out, err := json.MarshalIndent(results, "", "    ")
+		if err != nil {
+			return fmt.Errorf("json marshal network policy check: %w", err)
+		}
+
+		log.WriteString(logrus.InfoLevel, string(out)+"\n")
+	} else {
+		values := [][]string{}
+		for _, result := range results {
+			status := "PASSED"
+			if !result.Passed {
+				status = "FAILED"
+			}
+
+			values = append(values, []string{result.Name, result.Expected, result.Actual, status})
+		}
+		table.PrintTable(log, []string{"CHECK", "EXPECTED", "ACTUAL", "RESULT"}, values)
+	}
+
+	failed := 0
+	for _, result := range results {
+		if !result.Passed {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d network policy checks failed, make sure sync.toHost.networkPolicies is enabled and the CNI of the host cluster enforces network policies", failed, len(results))
+	}
+
+	return nil
+}
+
+// runNetworkPolicyChecks applies the policies of each case and probes the server until the expected result is
+// observed or the timeout is reached, as CNIs apply network policies asynchronously
+func runNetworkPolicyChecks(ctx context.Context, cases []networkPolicyCase, apply func(context.Context, networkPolicyCase) error, probe func(context.Context) (bool, error), timeout time.Duration, log log.Logger) []NetworkPolicyCheckResult {
+	results := make([]NetworkPolicyCheckResult, 0, len(cases))
+	for _, c := range cases {
+		log.Infof("Checking %s...", c.name)
+		result := NetworkPolicyCheckResult{
+			Name:     c.name,
+			Expected: reachability(c.allowed),
+		}
+
+		err := apply(ctx, c)
+		if err != nil {
+			result.Actual = "error"
+			result.Error = err.Error()
+			results = append(results, result)
+			continue
+		}
+
+		var lastErr error
+		_ = wait.PollUntilContextTimeout(ctx, time.Second, timeout, true, func(ctx context.Context) (bool, error) {
+			allowed, err := probe(ctx)
+			if err != nil {
+				lastErr = err
+				return false, nil
+			}
+
+			lastErr = nil
+			result.Actual = reachability(allowed)
+			return allowed == c.allowed, nil
+		})
+		if lastErr != nil && result.Actual == "" {
+			result.Actual = "error"
+			result.Error = lastErr.Error()
+		}
+
+		result.Passed = result.Actual == result.Expected
+		results = append(results, result)
+	}
+
+	return results
+}
+
+func reachability(allowed bool) string {
+	if allowed {
+		return "allowed"
+	}
+
+	return "denied"
+}
+
+func networkPolicyCases() []networkPolicyCase {
+	denyIngress := func(server *corev1.Namespace) *networkingv1.NetworkPolicy {
+		return &networkingv1.NetworkPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "deny-ingress", Namespace: server.Name},
+			Spec:       networkingv1.NetworkPolicySpec{PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress}},
+		}
+	}
+	denyEgress := func(client *corev1.Namespace) *networkingv1.NetworkPolicy {
+		return &networkingv1.NetworkPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "deny-egress", Namespace: client.Name},
+			Spec:       networkingv1.NetworkPolicySpec{PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeEgress}},
+		}
+	}
+	namespacePeer := func(namespace *corev1.Namespace, podLabels map[string]string) networkingv1.NetworkPolicyPeer {
+		peer := networkingv1.NetworkPolicyPeer{NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{
+			netpolCheckLabel:     namespace.Labels[netpolCheckLabel],
+			netpolCheckRoleLabel: namespace.Labels[netpolCheckRoleLabel],
+		}}}
+		if podLabels != nil {
+			peer.PodSelector = &metav1.LabelSelector{MatchLabels: podLabels}
+		}
+
+		return peer
+	}
+	port := func(port int) []networkingv1.NetworkPolicyPort {
+		return []networkingv1.NetworkPolicyPort{{Port: &intstr.IntOrString{Type: intstr.Int, IntVal: int32(port)}}}
+	}
+
+	return []networkPolicyCase{
+		{
+			name:    "no network policy",
+			allowed: true,
+			policies: func(_, _ *corev1.Namespace) []*networkingv1.NetworkPolicy {
+				return nil
+			},
+		},
+		{
+			name:    "ingress denied",
+			allowed: false,
+			policies: func(server, _ *corev1.Namespace) []*networkingv1.NetworkPolicy {
+				return []*networkingv1.NetworkPolicy{denyIngress(server)}
+			},
+		},
+		{
+			name:    "ingress allowed by namespace selector",
+			allowed: true,
+			policies: func(server, client *corev1.Namespace) []*networkingv1.NetworkPolicy {
+				allow := denyIngress(server)
+				allow.Name = "allow-ingress"
+				allow.Spec.Ingress = []networkingv1.NetworkPolicyIngressRule{{From: []networkingv1.NetworkPolicyPeer{namespacePeer(client, nil)}, Ports: port(netpolCheckServerPort)}}
+				return []*networkingv1.NetworkPolicy{denyIngress(server), allow}
+			},
+		},
+		{
+			name:    "ingress denied by pod selector",
+			allowed: false,
+			policies: func(server, client *corev1.Namespace) []*networkingv1.NetworkPolicy {
+				allow := denyIngress(server)
+				allow.Name = "allow-ingress"
+				allow.Spec.Ingress = []networkingv1.NetworkPolicyIngressRule{{From: []networkingv1.NetworkPolicyPeer{namespacePeer(client, map[string]string{"wrong": "label"})}, Ports: port(netpolCheckServerPort)}}
+				return []*networkingv1.NetworkPolicy{denyIngress(server), allow}
+			},
+		},
+		{
+			name:    "egress denied",
+			allowed: false,
+			policies: func(_, client *corev1.Namespace) []*networkingv1.NetworkPolicy {
+				return []*networkingv1.NetworkPolicy{denyEgress(client)}
+			},
+		},
+		{
+			name:    "egress allowed by namespace selector",
+			allowed: true,
+			policies: func(server, client *corev1.Namespace) []*networkingv1.NetworkPolicy {
+				allow := denyEgress(client)
+				allow.Name = "allow-egress"
+				allow.Spec.Egress = []networkingv1.NetworkPolicyEgressRule{{To: []networkingv1.NetworkPolicyPeer{namespacePeer(server, nil)}, Ports: port(netpolCheckServerPort)}}
+				return []*networkingv1.NetworkPolicy{denyEgress(client), allow}
+			},
+		},
+		{
+			name:    "egress denied by port",
+			allowed: false,
+			policies: func(server, client *corev1.Namespace) []*networkingv1.NetworkPolicy {
+				allow := denyEgress(client)
+				allow.Name = "allow-egress"
+				allow.Spec.Egress = []networkingv1.NetworkPolicyEgressRule{{To: []networkingv1.NetworkPolicyPeer{namespacePeer(server, nil)}, Ports: port(netpolCheckServerPort + 1)}}
+				return []*networkingv1.NetworkPolicy{denyEgress(client), allow}
+			},
+		},
+	}
+}
+
+// applyNetworkPolicyCase replaces the network policies of the previous case with the ones of the given case
+func applyNetworkPolicyCase(ctx context.Context, kubeClient kubernetes.Interface, server, client *corev1.Namespace, c networkPolicyCase) error {
+	for _, namespace := range []string{server.Name, client.Name} {
+		policies, err := kubeClient.NetworkingV1().NetworkPolicies(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return fmt.Errorf("list network policies: %w", err)
+		}
+
+		for _, policy := range policies.Items {
+			err = kubeClient.NetworkingV1().NetworkPolicies(namespace).Delete(ctx, policy.Name, metav1.DeleteOptions{})
+			if err != nil && !kerrors.IsNotFound(err) {
+				return fmt.Errorf("delete network policy %s/%s: %w", namespace, policy.Name, err)
+			}
+		}
+	}
+
+	for _, policy := range c.policies(server, client) {
+		_, err := kubeClient.NetworkingV1().NetworkPolicies(policy.Namespace).Create(ctx, policy, metav1.CreateOptions{})
+		if err != nil {
+			return fmt.Errorf("create network policy %s/%s: %w", policy.Namespace, policy.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// startNetpolCheckServer creates the server pod and service and returns the address of the service once the pod is
+// running. The cluster ip is used instead of the service name, so the egress checks do not depend on DNS.
+func startNetpolCheckServer(ctx context.Context, kubeClient kubernetes.Interface, namespace string, options *CheckNetworkPolicyOptions) (string, error) {
+	labels := map[string]string{netpolCheckRoleLabel: "server"}
+	pod, err := kubeClient.CoreV1().Pods(namespace).Create(ctx, &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "server", Namespace: namespace, Labels: labels},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{
+				Name:  "server",
+				Image: options.ServerImage,
+				Ports: []corev1.ContainerPort{{ContainerPort: netpolCheckServerPort}},
+			}},
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return "", fmt.Errorf("create server pod: %w", err)
+	}
+
+	service, err := kubeClient.CoreV1().Services(namespace).Create(ctx, &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "server", Namespace: namespace},
+		Spec: corev1.ServiceSpec{
+			Selector: labels,
+			Ports:    []corev1.ServicePort{{Port: netpolCheckServerPort, TargetPort: intstr.FromInt32(netpolCheckServerPort)}},
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return "", fmt.Errorf("create server service: %w", err)
+	}
+
+	err = wait.PollUntilContextTimeout(ctx, time.Second, 2*time.Minute, true, func(ctx context.Context) (bool, error) {
+		pod, err = kubeClient.CoreV1().Pods(namespace).Get(ctx, pod.Name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+
+		return pod.Status.Phase == corev1.PodRunning, nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("wait for server pod to be running: %w", err)
+	}
+
+	return service.Spec.ClusterIP + ":" + strconv.Itoa(netpolCheckServerPort), nil
+}
+
+// probeNetpolCheckServer runs a client pod that connects to the server and returns if the connection succeeded
+func probeNetpolCheckServer(ctx context.Context, kubeClient kubernetes.Interface, namespace, name, address string, options *CheckNetworkPolicyOptions) (bool, error) {
+	pod, err := kubeClient.CoreV1().Pods(namespace).Create(ctx, &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: map[string]string{netpolCheckRoleLabel: "client"}},
+		Spec: corev1.PodSpec{
+			RestartPolicy: corev1.RestartPolicyNever,
+			Containers: []corev1.Container{{
+				Name:    "client",
+				Image:   options.ClientImage,
+				Command: []string{"wget", "-q", "-T", "3", "-O", "/dev/null", "http://" + address},
+			}},
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return false, fmt.Errorf("create client pod: %w", err)
+	}
+	defer func() {
+		_ = kubeClient.CoreV1().Pods(namespace).Delete(context.Background(), pod.Name, metav1.DeleteOptions{})
+	}()
+
+	err = wait.PollUntilContextTimeout(ctx, time.Second, time.Minute, true, func(ctx context.Context) (bool, error) {
+		pod, err = kubeClient.CoreV1().Pods(namespace).Get(ctx, pod.Name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+
+		return pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed, nil
+	})
+	if err != nil {
+		return false, fmt.Errorf("wait for client pod %s to complete: %w", name, err)
+	}
+
+	return pod.Status.Phase == corev1.PodSucceeded, nil
+}
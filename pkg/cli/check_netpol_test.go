@@ -0,0 +1,67 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/loft-sh/log"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestRunNetworkPolicyChecks(t *testing.T) {
+	cases := []networkPolicyCase{{name: "allowed", allowed: true}, {name: "denied", allowed: false}, {name: "not enforced", allowed: false}, {name: "broken", allowed: true}}
+
+	current := ""
+	probes := map[string]int{}
+	apply := func(_ context.Context, c networkPolicyCase) error {
+		current = c.name
+		return nil
+	}
+	probe := func(_ context.Context) (bool, error) {
+		probes[current]++
+		switch current {
+		case "denied":
+			// the policy takes effect with the second probe
+			return probes[current] == 1, nil
+		case "not enforced":
+			return true, nil
+		case "broken":
+			return false, fmt.Errorf("image pull error")
+		}
+
+		return true, nil
+	}
+
+	results := runNetworkPolicyChecks(context.Background(), cases, apply, probe, 2*time.Second, log.Discard)
+	assert.DeepEqual(t, results, []NetworkPolicyCheckResult{
+		{Name: "allowed", Expected: "allowed", Actual: "allowed", Passed: true},
+		{Name: "denied", Expected: "denied", Actual: "denied", Passed: true},
+		{Name: "not enforced", Expected: "denied", Actual: "allowed", Passed: false},
+		{Name: "broken", Expected: "allowed", Actual: "error", Passed: false, Error: "image pull error"},
+	})
+	assert.Equal(t, probes["allowed"], 1)
+	assert.Equal(t, probes["denied"], 2)
+}
+
+func TestApplyNetworkPolicyCase(t *testing.T) {
+	server := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "server", Labels: map[string]string{netpolCheckLabel: "abc", netpolCheckRoleLabel: "server"}}}
+	client := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "client", Labels: map[string]string{netpolCheckLabel: "abc", netpolCheckRoleLabel: "client"}}}
+	kubeClient := fake.NewSimpleClientset(server, client)
+
+	for _, c := range networkPolicyCases() {
+		assert.NilError(t, applyNetworkPolicyCase(context.Background(), kubeClient, server, client, c))
+
+		count := 0
+		for _, namespace := range []string{server.Name, client.Name} {
+			policies, err := kubeClient.NetworkingV1().NetworkPolicies(namespace).List(context.Background(), metav1.ListOptions{})
+			assert.NilError(t, err)
+			count += len(policies.Items)
+		}
+		assert.Equal(t, count, len(c.policies(server, client)), c.name)
+	}
+}
@@ -0,0 +1,166 @@
+package cleanup
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	"github.com/loft-sh/log"
+)
+
+var defaultRegistry = New()
+
+// Registry keeps track of temporary artifacts a command created, such as temp files or partially installed releases,
+// and makes sure they are removed if the command fails or the process gets interrupted.
+type Registry struct {
+	m sync.Mutex
+
+	nextID  int
+	entries []*entry
+}
+
+type entry struct {
+	id   int
+	name string
+	fn   func() error
+}
+
+// Handle references a single registered cleanup function
+type Handle struct {
+	registry *Registry
+	id       int
+}
+
+// New creates a new empty registry
+func New() *Registry {
+	return &Registry{}
+}
+
+// Add registers the given cleanup function, the name is used for logging if the cleanup fails
+func Add(name string, fn func() error) *Handle {
+	return defaultRegistry.Add(name, fn)
+}
+
+// TempFile creates a temp file that is removed when the handle or the registry runs
+func TempFile(pattern string) (*os.File, *Handle, error) {
+	return defaultRegistry.TempFile(pattern)
+}
+
+// Run executes all pending cleanup functions of the default registry
+func Run(log log.Logger) {
+	defaultRegistry.Run(log)
+}
+
+// HandleSignals runs the cleanup of the default registry and exits if the process receives an interrupt or termination
+// signal. The returned function stops the signal handling.
+func HandleSignals(log log.Logger) func() {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	stop := defaultRegistry.handleSignals(signals, log, os.Exit)
+	return func() {
+		signal.Stop(signals)
+		stop()
+	}
+}
+
+// Add registers the given cleanup function, the name is used for logging if the cleanup fails
+func (r *Registry) Add(name string, fn func() error) *Handle {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	r.nextID++
+	r.entries = append(r.entries, &entry{id: r.nextID, name: name, fn: fn})
+	return &Handle{registry: r, id: r.nextID}
+}
+
+// TempFile creates a temp file that is removed when the handle or the registry runs
+func (r *Registry) TempFile(pattern string) (*os.File, *Handle, error) {
+	tempFile, err := os.CreateTemp("", pattern)
+	if err != nil {
+		return nil, nil, fmt.Errorf("create temp file: %w", err)
+	}
+
+	name := tempFile.Name()
+	return tempFile, r.Add("remove temp file "+name, func() error {
+		_ = tempFile.Close()
+		err := os.Remove(name)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+
+		return nil
+	}), nil
+}
+
+// Run executes all pending cleanup functions in reverse order of registration
+func (r *Registry) Run(log log.Logger) {
+	for {
+		r.m.Lock()
+		if len(r.entries) == 0 {
+			r.m.Unlock()
+			return
+		}
+
+		last := r.entries[len(r.entries)-1]
+		r.entries = r.entries[:len(r.entries)-1]
+		r.m.Unlock()
+
+		err := last.fn()
+		if err != nil {
+			log.Warnf("Error during cleanup (%s): %v", last.name, err)
+		}
+	}
+}
+
+func (r *Registry) handleSignals(signals <-chan os.Signal, log log.Logger, exit func(int)) func() {
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+
+		select {
+		case <-done:
+			return
+		case <-signals:
+			log.Infof("Interrupted, cleaning up...")
+			r.Run(log)
+			exit(1)
+		}
+	}()
+
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
+
+func (r *Registry) remove(id int) *entry {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	for i, entry := range r.entries {
+		if entry.id == id {
+			r.entries = append(r.entries[:i], r.entries[i+1:]...)
+			return entry
+		}
+	}
+
+	return nil
+}
+
+// Run executes the cleanup function now and removes it from the registry. It does nothing if it was already run or discarded.
+func (h *Handle) Run() error {
+	entry := h.registry.remove(h.id)
+	if entry == nil {
+		return nil
+	}
+
+	return entry.fn()
+}
+
+// Discard removes the cleanup function from the registry without running it, e.g. because the artifact should be kept
+func (h *Handle) Discard() {
+	h.registry.remove(h.id)
+}
@@ -0,0 +1,98 @@
+package cleanup
+
+import (
+	"errors"
+	"os"
+	"syscall"
+	"testing"
+
+	"github.com/loft-sh/log"
+	"gotest.tools/v3/assert"
+)
+
+func TestRunOrder(t *testing.T) {
+	registry := New()
+	order := []string{}
+	registry.Add("first", func() error {
+		order = append(order, "first")
+		return nil
+	})
+	failing := registry.Add("failing", func() error {
+		order = append(order, "failing")
+		return errors.New("failed")
+	})
+	discarded := registry.Add("discarded", func() error {
+		order = append(order, "discarded")
+		return nil
+	})
+	registry.Add("last", func() error {
+		order = append(order, "last")
+		return nil
+	})
+
+	discarded.Discard()
+	registry.Run(log.Discard)
+	assert.DeepEqual(t, order, []string{"last", "failing", "first"})
+
+	// already executed functions are not run again
+	assert.NilError(t, failing.Run())
+	registry.Run(log.Discard)
+	assert.Equal(t, len(order), 3)
+}
+
+func TestTempFile(t *testing.T) {
+	registry := New()
+	tempFile, handle, err := registry.TempFile("cleanup-test-")
+	assert.NilError(t, err)
+	_, err = os.Stat(tempFile.Name())
+	assert.NilError(t, err)
+
+	assert.NilError(t, handle.Run())
+	_, err = os.Stat(tempFile.Name())
+	assert.Assert(t, os.IsNotExist(err))
+
+	// removing an already removed file is fine
+	assert.NilError(t, handle.Run())
+}
+
+func TestHandleSignals(t *testing.T) {
+	registry := New()
+	tempFile, _, err := registry.TempFile("cleanup-test-")
+	assert.NilError(t, err)
+	cleanedUp := false
+	registry.Add("release", func() error {
+		cleanedUp = true
+		return nil
+	})
+
+	signals := make(chan os.Signal, 1)
+	exitCodes := make(chan int, 1)
+	stop := registry.handleSignals(signals, log.Discard, func(code int) {
+		exitCodes <- code
+	})
+	defer stop()
+
+	signals <- syscall.SIGTERM
+	assert.Equal(t, <-exitCodes, 1)
+	assert.Assert(t, cleanedUp)
+	_, err = os.Stat(tempFile.Name())
+	assert.Assert(t, os.IsNotExist(err))
+}
+
+func TestStopSignalHandling(t *testing.T) {
+	registry := New()
+	cleanedUp := false
+	registry.Add("release", func() error {
+		cleanedUp = true
+		return nil
+	})
+
+	signals := make(chan os.Signal, 1)
+	stop := registry.handleSignals(signals, log.Discard, func(int) {
+		t.Fatal("unexpected exit")
+	})
+	stop()
+
+	signals <- os.Interrupt
+	assert.Assert(t, !cleanedUp)
+}
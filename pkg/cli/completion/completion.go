@@ -1,16 +1,21 @@
 package completion
 
 import (
+	"cmp"
 	"context"
+	"strings"
 	"time"
 
 	"github.com/loft-sh/log"
+	"github.com/loft-sh/vcluster/pkg/cli/config"
 	"github.com/loft-sh/vcluster/pkg/cli/find"
 	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/loft-sh/vcluster/pkg/platform"
+	"github.com/loft-sh/vcluster/pkg/platform/kube"
 	"github.com/spf13/cobra"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
-	"sigs.k8s.io/controller-runtime/pkg/client/config"
+	"k8s.io/client-go/tools/clientcmd"
 )
 
 const completionTimeout = time.Second * 3
@@ -25,9 +30,16 @@ type completionResult struct {
 }
 
 // NewValidVClusterNameFunc returns a function that handles shell completion when the argument is vcluster_name
-// It takes into account the namespace if specified by the --namespace flag.
+// It takes into account the namespace if specified by the --namespace flag. If the platform driver is used, the
+// virtual clusters of the platform are completed instead.
 func NewValidVClusterNameFunc(globalFlags *flags.GlobalFlags) Func {
-	fn := func(cmd *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+	fn := func(cmd *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return []string{}, cobra.ShellCompDirectiveNoFileComp
+		} else if driverType(cmd, globalFlags) == config.PlatformDriver {
+			return platformVClusterNames(cmd, globalFlags)
+		}
+
 		vclusters, err := find.ListVClusters(cmd.Context(), globalFlags.Context, "", globalFlags.Namespace, log.Default.ErrorStreamOnly())
 		if err != nil {
 			return []string{}, cobra.ShellCompDirectiveError | cobra.ShellCompDirectiveNoFileComp
@@ -41,10 +53,25 @@ func NewValidVClusterNameFunc(globalFlags *flags.GlobalFlags) Func {
 	return wrapCompletionFuncWithTimeout(cobra.ShellCompDirectiveNoFileComp, fn)
 }
 
+// NewValidPlatformVClusterNameFunc returns a function that handles shell completion when the argument is the name
+// of a platform virtual cluster. It takes into account the project if specified by the --project flag.
+func NewValidPlatformVClusterNameFunc(globalFlags *flags.GlobalFlags) Func {
+	fn := func(cmd *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return []string{}, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		return platformVClusterNames(cmd, globalFlags)
+	}
+	return wrapCompletionFuncWithTimeout(cobra.ShellCompDirectiveNoFileComp, fn)
+}
+
 // NewNamespaceCompletionFunc handles shell completions for the namespace flag
-func NewNamespaceCompletionFunc(ctx context.Context) Func {
-	fn := func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
-		restConfig, err := config.GetConfig()
+func NewNamespaceCompletionFunc(globalFlags *flags.GlobalFlags) Func {
+	fn := func(cmd *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+		restConfig, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(clientcmd.NewDefaultClientConfigLoadingRules(), &clientcmd.ConfigOverrides{
+			CurrentContext: globalFlags.Context,
+		}).ClientConfig()
 		if err != nil {
 			return []string{}, cobra.ShellCompDirectiveError | cobra.ShellCompDirectiveNoFileComp
 		}
@@ -53,15 +80,15 @@ func NewNamespaceCompletionFunc(ctx context.Context) Func {
 			return []string{}, cobra.ShellCompDirectiveError | cobra.ShellCompDirectiveNoFileComp
 		}
 
-		namespaces, err := kubeClient.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+		namespaces, err := kubeClient.CoreV1().Namespaces().List(cmd.Context(), metav1.ListOptions{})
 		if err != nil {
 			return []string{}, cobra.ShellCompDirectiveError | cobra.ShellCompDirectiveNoFileComp
 		}
-		names := make([]string, len(namespaces.Items))
+		names := make([]string, 0, len(namespaces.Items))
 		for i := range namespaces.Items {
 			ns := namespaces.Items[i].Name
 			if ns != metav1.NamespaceSystem {
-				names[i] = ns
+				names = append(names, ns)
 			}
 		}
 		return names, cobra.ShellCompDirectiveNoFileComp
@@ -69,6 +96,189 @@ func NewNamespaceCompletionFunc(ctx context.Context) Func {
 	return wrapCompletionFuncWithTimeout(cobra.ShellCompDirectiveNoFileComp, fn)
 }
 
+// NewProjectCompletionFunc handles shell completions for the project flag
+func NewProjectCompletionFunc(globalFlags *flags.GlobalFlags) Func {
+	fn := func(cmd *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+		managementClient, err := managementClient(cmd.Context(), globalFlags)
+		if err != nil {
+			return []string{}, cobra.ShellCompDirectiveError | cobra.ShellCompDirectiveNoFileComp
+		}
+
+		projects, err := managementClient.Loft().ManagementV1().Projects().List(cmd.Context(), metav1.ListOptions{})
+		if err != nil {
+			return []string{}, cobra.ShellCompDirectiveError | cobra.ShellCompDirectiveNoFileComp
+		}
+		names := make([]string, len(projects.Items))
+		for i := range projects.Items {
+			names[i] = projects.Items[i].Name
+		}
+		return names, cobra.ShellCompDirectiveNoFileComp
+	}
+	return wrapCompletionFuncWithTimeout(cobra.ShellCompDirectiveNoFileComp, fn)
+}
+
+// NewClusterCompletionFunc handles shell completions for the cluster flag. It only completes the clusters of the
+// project if specified by the --project flag.
+func NewClusterCompletionFunc(globalFlags *flags.GlobalFlags) Func {
+	fn := func(cmd *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+		managementClient, err := managementClient(cmd.Context(), globalFlags)
+		if err != nil {
+			return []string{}, cobra.ShellCompDirectiveError | cobra.ShellCompDirectiveNoFileComp
+		}
+
+		names := []string{}
+		if project := stringFlag(cmd, "project"); project != "" {
+			projectClusters, err := managementClient.Loft().ManagementV1().Projects().ListClusters(cmd.Context(), project, metav1.GetOptions{})
+			if err != nil {
+				return []string{}, cobra.ShellCompDirectiveError | cobra.ShellCompDirectiveNoFileComp
+			}
+			for _, cluster := range projectClusters.Clusters {
+				names = append(names, cluster.Name)
+			}
+		} else {
+			clusters, err := managementClient.Loft().ManagementV1().Clusters().List(cmd.Context(), metav1.ListOptions{})
+			if err != nil {
+				return []string{}, cobra.ShellCompDirectiveError | cobra.ShellCompDirectiveNoFileComp
+			}
+			for _, cluster := range clusters.Items {
+				names = append(names, cluster.Name)
+			}
+		}
+		return names, cobra.ShellCompDirectiveNoFileComp
+	}
+	return wrapCompletionFuncWithTimeout(cobra.ShellCompDirectiveNoFileComp, fn)
+}
+
+// NewTemplateCompletionFunc handles shell completions for the template flag. It completes virtual cluster templates
+// or space templates if space is set and only the templates of the project if specified by the --project flag.
+func NewTemplateCompletionFunc(globalFlags *flags.GlobalFlags, space bool) Func {
+	fn := func(cmd *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+		managementClient, err := managementClient(cmd.Context(), globalFlags)
+		if err != nil {
+			return []string{}, cobra.ShellCompDirectiveError | cobra.ShellCompDirectiveNoFileComp
+		}
+
+		names := []string{}
+		if project := stringFlag(cmd, "project"); project != "" {
+			projectTemplates, err := managementClient.Loft().ManagementV1().Projects().ListTemplates(cmd.Context(), project, metav1.GetOptions{})
+			if err != nil {
+				return []string{}, cobra.ShellCompDirectiveError | cobra.ShellCompDirectiveNoFileComp
+			}
+			if space {
+				for _, template := range projectTemplates.SpaceTemplates {
+					names = append(names, template.Name)
+				}
+			} else {
+				for _, template := range projectTemplates.VirtualClusterTemplates {
+					names = append(names, template.Name)
+				}
+			}
+		} else if space {
+			templates, err := managementClient.Loft().ManagementV1().SpaceTemplates().List(cmd.Context(), metav1.ListOptions{})
+			if err != nil {
+				return []string{}, cobra.ShellCompDirectiveError | cobra.ShellCompDirectiveNoFileComp
+			}
+			for _, template := range templates.Items {
+				names = append(names, template.Name)
+			}
+		} else {
+			templates, err := managementClient.Loft().ManagementV1().VirtualClusterTemplates().List(cmd.Context(), metav1.ListOptions{})
+			if err != nil {
+				return []string{}, cobra.ShellCompDirectiveError | cobra.ShellCompDirectiveNoFileComp
+			}
+			for _, template := range templates.Items {
+				names = append(names, template.Name)
+			}
+		}
+		return names, cobra.ShellCompDirectiveNoFileComp
+	}
+	return wrapCompletionFuncWithTimeout(cobra.ShellCompDirectiveNoFileComp, fn)
+}
+
+// RegisterPlatformFlagCompletions registers the completions of the --project, --cluster and --template flags of the
+// command and all of its subcommands that have them, unless a completion is already registered. The flags are
+// added by the shared flag helpers without access to the global flags, so they are registered once for the tree.
+func RegisterPlatformFlagCompletions(cmd *cobra.Command, globalFlags *flags.GlobalFlags) error {
+	completions := map[string]Func{
+		"project":  NewProjectCompletionFunc(globalFlags),
+		"cluster":  NewClusterCompletionFunc(globalFlags),
+		"template": NewTemplateCompletionFunc(globalFlags, isSpaceCommand(cmd)),
+	}
+	for flagName, completion := range completions {
+		if cmd.Flags().Lookup(flagName) == nil {
+			continue
+		} else if _, ok := cmd.GetFlagCompletionFunc(flagName); ok {
+			continue
+		}
+
+		err := cmd.RegisterFlagCompletionFunc(flagName, completion)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, subCmd := range cmd.Commands() {
+		err := RegisterPlatformFlagCompletions(subCmd, globalFlags)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// isSpaceCommand returns true for the vcluster platform commands of spaces, e.g. vcluster platform create namespace
+func isSpaceCommand(cmd *cobra.Command) bool {
+	path := strings.Fields(cmd.CommandPath())
+	return len(path) == 4 && path[1] == "platform" && path[3] == "namespace"
+}
+
+// driverType returns the driver of the --driver flag or of the config, commands without a --driver flag only
+// support helm
+func driverType(cmd *cobra.Command, globalFlags *flags.GlobalFlags) config.DriverType {
+	if cmd.Flags().Lookup("driver") == nil {
+		return config.HelmDriver
+	}
+
+	driver := cmp.Or(stringFlag(cmd, "driver"), string(globalFlags.LoadedConfig(log.Discard).Driver.Type))
+	return config.DriverType(driver)
+}
+
+func stringFlag(cmd *cobra.Command, flagName string) string {
+	flag := cmd.Flags().Lookup(flagName)
+	if flag == nil {
+		return ""
+	}
+
+	return flag.Value.String()
+}
+
+func platformVClusterNames(cmd *cobra.Command, globalFlags *flags.GlobalFlags) ([]string, cobra.ShellCompDirective) {
+	platformClient, err := platform.InitClientFromConfig(cmd.Context(), globalFlags.LoadedConfig(log.Discard))
+	if err != nil {
+		return []string{}, cobra.ShellCompDirectiveError | cobra.ShellCompDirectiveNoFileComp
+	}
+
+	virtualClusters, err := platform.ListVClusters(cmd.Context(), platformClient, "", stringFlag(cmd, "project"))
+	if err != nil {
+		return []string{}, cobra.ShellCompDirectiveError | cobra.ShellCompDirectiveNoFileComp
+	}
+	names := make([]string, len(virtualClusters))
+	for i := range virtualClusters {
+		names[i] = virtualClusters[i].VirtualCluster.Name
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+func managementClient(ctx context.Context, globalFlags *flags.GlobalFlags) (kube.Interface, error) {
+	platformClient, err := platform.InitClientFromConfig(ctx, globalFlags.LoadedConfig(log.Discard))
+	if err != nil {
+		return nil, err
+	}
+
+	return platformClient.Management()
+}
+
 // wrapper to add a timeout to completionFuncs
 func wrapCompletionFuncWithTimeout(defaultDirective cobra.ShellCompDirective, compFunc Func) Func {
 	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
@@ -0,0 +1,49 @@
+package completion
+
+import (
+	"testing"
+
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/spf13/cobra"
+	"gotest.tools/v3/assert"
+)
+
+func TestRegisterPlatformFlagCompletions(t *testing.T) {
+	rootCmd := &cobra.Command{Use: "vcluster"}
+	createCmd := &cobra.Command{Use: "create"}
+	createCmd.Flags().String("project", "", "")
+	createCmd.Flags().String("template", "", "")
+	platformCmd := &cobra.Command{Use: "platform"}
+	platformCreateCmd := &cobra.Command{Use: "create"}
+	spaceCmd := &cobra.Command{Use: "namespace"}
+	spaceCmd.Flags().String("cluster", "", "")
+	spaceCmd.Flags().String("template", "", "")
+	rootCmd.AddCommand(createCmd, platformCmd)
+	platformCmd.AddCommand(platformCreateCmd)
+	platformCreateCmd.AddCommand(spaceCmd)
+
+	// completions that are registered already are kept
+	registered := false
+	assert.NilError(t, createCmd.RegisterFlagCompletionFunc("template", func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+		registered = true
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}))
+
+	assert.NilError(t, RegisterPlatformFlagCompletions(rootCmd, &flags.GlobalFlags{}))
+	for _, c := range []struct {
+		cmd      *cobra.Command
+		flagName string
+	}{{createCmd, "project"}, {createCmd, "template"}, {spaceCmd, "cluster"}, {spaceCmd, "template"}} {
+		_, ok := c.cmd.GetFlagCompletionFunc(c.flagName)
+		assert.Assert(t, ok, "%s --%s", c.cmd.CommandPath(), c.flagName)
+	}
+	_, ok := createCmd.GetFlagCompletionFunc("cluster")
+	assert.Assert(t, !ok)
+
+	templateCompletion, _ := createCmd.GetFlagCompletionFunc("template")
+	templateCompletion(createCmd, nil, "")
+	assert.Assert(t, registered)
+
+	assert.Assert(t, isSpaceCommand(spaceCmd))
+	assert.Assert(t, !isSpaceCommand(createCmd))
+}
@@ -47,6 +47,57 @@ func (c *CLI) Save() error {
 	return Write(c.path, c)
 }
 
+// RegisterContext records a kube context of a virtual cluster, the config needs to be saved afterwards
+func (c *CLI) RegisterContext(name string, kubeContext KubeContext) {
+	if c.Contexts == nil {
+		c.Contexts = map[string]KubeContext{}
+	}
+	if kubeContext.Created.IsZero() {
+		kubeContext.Created = metav1.Now()
+	}
+
+	c.Contexts[name] = kubeContext
+}
+
+// UnregisterContext removes the record of a kube context and returns true if there was one, the config needs to be
+// saved afterwards
+func (c *CLI) UnregisterContext(name string) bool {
+	if _, ok := c.Contexts[name]; !ok {
+		return false
+	}
+
+	delete(c.Contexts, name)
+	return true
+}
+
+// AuditPath returns the path of the audit log, by default it is next to the config file
+func (c *CLI) AuditPath() (string, error) {
+	if c.Audit.Path != "" {
+		return c.Audit.Path, nil
+	}
+
+	return c.pathNextToConfig("audit.log")
+}
+
+// StatusCachePath returns the path of the cached states of the virtual clusters of the kube contexts, it is next to
+// the config file
+func (c *CLI) StatusCachePath() (string, error) {
+	return c.pathNextToConfig("status-cache.json")
+}
+
+func (c *CLI) pathNextToConfig(fileName string) (string, error) {
+	path := c.path
+	if path == "" {
+		var err error
+		path, err = DefaultFilePath()
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return filepath.Join(filepath.Dir(path), fileName), nil
+}
+
 // Read returns the current config by trying to read it from the given config path.
 // It returns a new default config if there have been any errors during the read.
 func Read(path string, log log.Logger) *CLI {
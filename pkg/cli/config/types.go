@@ -1,6 +1,7 @@
 package config
 
 import (
+	"strings"
 	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -11,7 +12,66 @@ type CLI struct {
 	PreviousContext   string   `json:"previousContext,omitempty"`
 	path              string   `json:"-"`
 	Platform          Platform `json:"platform,omitempty"`
+	Helm              Helm     `json:"helm,omitempty"`
+	Audit             Audit    `json:"audit,omitempty"`
 	TelemetryDisabled bool     `json:"telemetryDisabled,omitempty"`
+	// Contexts are the kube contexts created by connect and create, by context name. They are removed again by
+	// delete and vcluster kubeconfig clean.
+	Contexts map[string]KubeContext `json:"contexts,omitempty"`
+}
+
+// KubeContext is a kube context of a virtual cluster that was added to the kube config
+type KubeContext struct {
+	// Driver is the driver the virtual cluster was connected with
+	Driver DriverType `json:"driver,omitempty"`
+	// Name of the virtual cluster
+	Name string `json:"name,omitempty"`
+	// Namespace of the virtual cluster if it is deployed via helm
+	Namespace string `json:"namespace,omitempty"`
+	// Project of the virtual cluster if it is deployed via the platform
+	Project string `json:"project,omitempty"`
+	// Host is the platform the virtual cluster belongs to if it is deployed via the platform
+	Host string `json:"host,omitempty"`
+	// ParentContext is the kube context the virtual cluster was connected from, for helm this is the host cluster
+	ParentContext string `json:"parentContext,omitempty"`
+	// Created is the time the context was added
+	Created metav1.Time `json:"created,omitempty"`
+}
+
+type Audit struct {
+	// Disabled stops recording the mutating platform commands in the audit log
+	Disabled bool `json:"disabled,omitempty"`
+	// Path of the audit log, defaults to audit.log next to the config file
+	Path string `json:"path,omitempty"`
+	// Webhook is an url the audit events are posted to as json in addition to the audit log
+	Webhook string `json:"webhook,omitempty"`
+}
+
+type Helm struct {
+	// ChartRepos holds the credentials of private chart repositories, e.g. a mirror of the vCluster chart
+	ChartRepos []ChartRepo `json:"chartRepos,omitempty"`
+}
+
+type ChartRepo struct {
+	// URL of the chart repository, e.g. https://charts.example.com/vcluster
+	URL string `json:"url,omitempty"`
+	// Username to authenticate against the chart repository
+	Username string `json:"username,omitempty"`
+	// Password to authenticate against the chart repository
+	Password string `json:"password,omitempty"`
+	// CAFile is the path of the certificate authority that signed the certificate of the chart repository
+	CAFile string `json:"caFile,omitempty"`
+}
+
+// ChartRepo returns the configured credentials of the chart repository with the given url or nil
+func (h Helm) ChartRepo(url string) *ChartRepo {
+	for i := range h.ChartRepos {
+		if strings.TrimSuffix(h.ChartRepos[i].URL, "/") == strings.TrimSuffix(url, "/") {
+			return &h.ChartRepos[i]
+		}
+	}
+
+	return nil
 }
 
 type Driver struct {
@@ -36,6 +96,23 @@ type Platform struct {
 	VirtualClusterAccessKey string `json:"virtualClusterAccessKey,omitempty"`
 	// Insecure specifies if the loft instance is insecure
 	Insecure bool `json:"insecure,omitempty"`
+	// OIDC is set if the access key was issued for an OIDC client credentials token, the login is refreshed before
+	// the token expires
+	OIDC *OIDCLogin `json:"oidc,omitempty"`
+}
+
+type OIDCLogin struct {
+	// Issuer is the url of the OIDC provider
+	Issuer string `json:"issuer,omitempty"`
+	// ClientID is the client the token is requested for
+	ClientID string `json:"clientId,omitempty"`
+	// ClientSecretFile is the file the client secret is read from on refresh, the secret itself is never stored.
+	// The secret is read from VCLUSTER_OIDC_CLIENT_SECRET if no file is set.
+	ClientSecretFile string `json:"clientSecretFile,omitempty"`
+	// Scopes are the scopes requested in addition to openid
+	Scopes []string `json:"scopes,omitempty"`
+	// Expiry is the time the token expires
+	Expiry *metav1.Time `json:"expiry,omitempty"`
 }
 
 type VirtualClusterCertificatesEntry struct {
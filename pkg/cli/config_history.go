@@ -0,0 +1,177 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/log/table"
+	"github.com/loft-sh/vcluster/pkg/cli/find"
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/loft-sh/vcluster/pkg/helm"
+	"github.com/sirupsen/logrus"
+	kblabels "k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+)
+
+type ConfigHistoryOptions struct {
+	Output string
+}
+
+type ConfigRollbackOptions struct {
+	// Revision is the helm release revision whose values are deployed again
+	Revision int
+
+	// ChartRepo is the repo of the vcluster chart the current chart version is deployed from
+	ChartRepo string
+
+	RollbackTimeout time.Duration
+}
+
+// ConfigRevision is a config of the virtual cluster that was applied by a helm release revision
+type ConfigRevision struct {
+	Revision     int       `json:"revision"`
+	Deployed     time.Time `json:"deployed"`
+	Status       string    `json:"status"`
+	ChartVersion string    `json:"chartVersion"`
+	Checksum     string    `json:"checksum"`
+	Description  string    `json:"description,omitempty"`
+}
+
+// ConfigHistory prints the config revisions of the virtual cluster. The revisions are read from the helm release
+// history, which keeps the values of the last revisions of the virtual cluster.
+func ConfigHistory(ctx context.Context, globalFlags *flags.GlobalFlags, vClusterName string, options *ConfigHistoryOptions, log log.Logger) error {
+	_, releases, err := releaseHistory(ctx, globalFlags, vClusterName, log)
+	if err != nil {
+		return err
+	}
+	revisions, err := configRevisions(releases)
+	if err != nil {
+		return err
+	}
+
+	if options.Output == "json" {
+		out, err := json.MarshalIndent(revisions, "", "    ")
+		if err != nil {
+			return err
+		}
+
+		log.WriteString(logrus.InfoLevel, string(out)+"\n")
+		return nil
+	}
+
+	rows := [][]string{}
+	for _, revision := range revisions {
+		rows = append(rows, []string{strconv.Itoa(revision.Revision), revision.Deployed.Format(time.RFC3339), revision.Status, revision.ChartVersion, revision.Checksum[:12], revision.Description})
+	}
+	table.PrintTable(log, []string{"REVISION", "DEPLOYED", "STATUS", "CHART", "CHECKSUM", "DESCRIPTION"}, rows)
+	return nil
+}
+
+// ConfigRollback upgrades the virtual cluster with the values of the given revision. The current chart version is
+// kept, so only the config is rolled back.
+func ConfigRollback(ctx context.Context, globalFlags *flags.GlobalFlags, vClusterName string, options *ConfigRollbackOptions, log log.Logger) error {
+	if options.Revision < 1 {
+		return fmt.Errorf("please specify the revision to roll back to via --revision")
+	}
+
+	vCluster, releases, err := releaseHistory(ctx, globalFlags, vClusterName, log)
+	if err != nil {
+		return err
+	}
+	current, target, err := rollbackReleases(releases, options.Revision)
+	if err != nil {
+		return err
+	}
+
+	values := target.Config
+	if values == nil {
+		values = map[string]interface{}{}
+	}
+	log.Infof("Rolling back config of vcluster %s to revision %d...", vClusterName, options.Revision)
+	rollbackFlags := *globalFlags
+	rollbackFlags.Context = vCluster.Context
+	rollbackFlags.Namespace = vCluster.Namespace
+	err = deployReleaseValues(ctx, &rollbackFlags, vClusterName, options.ChartRepo, current.Chart.Metadata.Version, values, options.RollbackTimeout, log)
+	if err != nil {
+		return fmt.Errorf("roll back config: %w", err)
+	}
+
+	log.Donef("Successfully rolled back config of vcluster %s to revision %d", vClusterName, options.Revision)
+	return nil
+}
+
+func releaseHistory(ctx context.Context, globalFlags *flags.GlobalFlags, vClusterName string, log log.Logger) (*find.VCluster, []*helm.Release, error) {
+	vCluster, err := find.GetVCluster(ctx, globalFlags.Context, vClusterName, globalFlags.Namespace, log)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	restConfig, err := vCluster.ClientFactory.ClientConfig()
+	if err != nil {
+		return nil, nil, err
+	}
+	kubeClient, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	releases, err := helm.NewSecrets(kubeClient).ListUnfiltered(ctx, kblabels.SelectorFromSet(kblabels.Set{"name": vClusterName}), vCluster.Namespace)
+	if err != nil {
+		return nil, nil, fmt.Errorf("list helm releases of vcluster %s: %w", vClusterName, err)
+	} else if len(releases) == 0 {
+		return nil, nil, fmt.Errorf("couldn't find a helm release of vcluster %s", vClusterName)
+	}
+
+	return vCluster, releases, nil
+}
+
+// configRevisions returns the config revisions of the given releases sorted by revision
+func configRevisions(releases []*helm.Release) ([]ConfigRevision, error) {
+	revisions := []ConfigRevision{}
+	for _, release := range releases {
+		checksum, err := valuesChecksum(release)
+		if err != nil {
+			return nil, err
+		}
+
+		revisions = append(revisions, ConfigRevision{
+			Revision:     release.Version,
+			Deployed:     release.Info.LastDeployed.Time,
+			Status:       release.Info.Status,
+			ChartVersion: release.Chart.Metadata.Version,
+			Checksum:     checksum,
+			Description:  release.Info.Description,
+		})
+	}
+
+	// helm secrets are listed in name order, which puts revision 10 before revision 2
+	sort.Slice(revisions, func(i, j int) bool {
+		return revisions[i].Revision < revisions[j].Revision
+	})
+	return revisions, nil
+}
+
+// rollbackReleases returns the current release and the release of the revision to roll back to
+func rollbackReleases(releases []*helm.Release, revision int) (*helm.Release, *helm.Release, error) {
+	var current, target *helm.Release
+	for _, release := range releases {
+		if current == nil || release.Version > current.Version {
+			current = release
+		}
+		if release.Version == revision {
+			target = release
+		}
+	}
+	if target == nil {
+		return nil, nil, fmt.Errorf("revision %d not found, run `vcluster config history` to list the available revisions", revision)
+	} else if target == current {
+		return nil, nil, fmt.Errorf("revision %d is the current revision", revision)
+	}
+
+	return current, target, nil
+}
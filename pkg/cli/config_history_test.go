@@ -0,0 +1,65 @@
+package cli
+
+import (
+	"testing"
+	"time"
+
+	"github.com/loft-sh/vcluster/pkg/helm"
+	"gotest.tools/v3/assert"
+)
+
+func TestConfigRevisions(t *testing.T) {
+	deployed := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	releases := []*helm.Release{
+		newHistoryRelease(1, "superseded", deployed, map[string]interface{}{"sync": map[string]interface{}{"toHost": map[string]interface{}{"ingresses": map[string]interface{}{"enabled": true}}}}),
+		newHistoryRelease(2, "deployed", deployed.Add(time.Hour), nil),
+	}
+
+	revisions, err := configRevisions(releases)
+	assert.NilError(t, err)
+	assert.Equal(t, len(revisions), 2)
+	assert.Equal(t, revisions[0].Revision, 1)
+	assert.Equal(t, revisions[0].Status, "superseded")
+	assert.Equal(t, revisions[0].Deployed, deployed)
+	assert.Equal(t, revisions[1].ChartVersion, "0.21.0")
+	assert.Assert(t, revisions[0].Checksum != revisions[1].Checksum)
+
+	current, target, err := rollbackReleases(releases, 1)
+	assert.NilError(t, err)
+	assert.Equal(t, current.Version, 2)
+	assert.Equal(t, target.Version, 1)
+
+	_, _, err = rollbackReleases(releases, 2)
+	assert.ErrorContains(t, err, "is the current revision")
+	_, _, err = rollbackReleases(releases, 3)
+	assert.ErrorContains(t, err, "revision 3 not found")
+}
+
+func TestConfigRevisionsSorted(t *testing.T) {
+	deployed := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	releases := []*helm.Release{}
+	for _, revision := range []int{1, 10, 11, 2, 3, 4, 5, 6, 7, 8, 9} {
+		releases = append(releases, newHistoryRelease(revision, "superseded", deployed.Add(time.Duration(revision)*time.Hour), nil))
+	}
+
+	revisions, err := configRevisions(releases)
+	assert.NilError(t, err)
+	assert.Equal(t, len(revisions), 11)
+	for i, revision := range revisions {
+		assert.Equal(t, revision.Revision, i+1)
+	}
+
+	current, _, err := rollbackReleases(releases, 9)
+	assert.NilError(t, err)
+	assert.Equal(t, current.Version, 11)
+}
+
+func newHistoryRelease(revision int, status string, deployed time.Time, values map[string]interface{}) *helm.Release {
+	return &helm.Release{
+		Name:    "my-vcluster",
+		Version: revision,
+		Info:    &helm.Info{Status: status, LastDeployed: helm.Time{Time: deployed}},
+		Chart:   &helm.Chart{Metadata: &helm.Metadata{Version: "0.21.0"}},
+		Config:  values,
+	}
+}
@@ -6,14 +6,14 @@ import (
 	"io"
 	"os"
 	"os/exec"
-	"os/signal"
 	"sort"
 	"strconv"
 	"strings"
-	"syscall"
 	"time"
 
 	"github.com/loft-sh/log"
+	"github.com/loft-sh/vcluster/pkg/cli/cleanup"
+	"github.com/loft-sh/vcluster/pkg/cli/config"
 	"github.com/loft-sh/vcluster/pkg/cli/find"
 	"github.com/loft-sh/vcluster/pkg/cli/flags"
 	"github.com/loft-sh/vcluster/pkg/cli/localkubernetes"
@@ -52,6 +52,7 @@ type ConnectOptions struct {
 	UpdateCurrent             bool
 	BackgroundProxy           bool
 	Insecure                  bool
+	ViaGateway                bool
 
 	Project string
 }
@@ -102,6 +103,10 @@ func (cmd *connectHelm) connect(ctx context.Context, vCluster *find.VCluster, co
 
 	// check if we should execute command
 	if len(command) > 0 {
+		// the gateway is directly reachable, so no port-forwarding is needed
+		if cmd.ViaGateway {
+			return executeCommand(*kubeConfig, command, nil, cmd.Log)
+		}
 		if !cmd.portForwarding {
 			return fmt.Errorf("command is specified, but port-forwarding isn't started")
 		}
@@ -118,7 +123,12 @@ func (cmd *connectHelm) connect(ctx context.Context, vCluster *find.VCluster, co
 	}
 
 	// write kube config
-	err = writeKubeConfig(kubeConfig, vCluster.Name, cmd.ConnectOptions, cmd.GlobalFlags, cmd.portForwarding, cmd.Log)
+	err = writeKubeConfig(kubeConfig, config.KubeContext{
+		Driver:        config.HelmDriver,
+		Name:          vCluster.Name,
+		Namespace:     vCluster.Namespace,
+		ParentContext: vCluster.Context,
+	}, cmd.ConnectOptions, cmd.GlobalFlags, cmd.portForwarding, cmd.Log)
 	if err != nil {
 		return err
 	}
@@ -136,7 +146,7 @@ func (cmd *connectHelm) connect(ctx context.Context, vCluster *find.VCluster, co
 	return nil
 }
 
-func writeKubeConfig(kubeConfig *clientcmdapi.Config, vClusterName string, options *ConnectOptions, globalFlags *flags.GlobalFlags, portForwarding bool, log log.Logger) error {
+func writeKubeConfig(kubeConfig *clientcmdapi.Config, kubeContext config.KubeContext, options *ConnectOptions, globalFlags *flags.GlobalFlags, portForwarding bool, log log.Logger) error {
 	// write kube config to buffer
 	out, err := clientcmd.Write(*kubeConfig)
 	if err != nil {
@@ -165,28 +175,25 @@ func writeKubeConfig(kubeConfig *clientcmdapi.Config, vClusterName string, optio
 			return err
 		}
 
+		registerContext(globalFlags.LoadedConfig(log), options.KubeConfigContextName, kubeContext, log)
 		log.Donef("Switched active kube context to %s", options.KubeConfigContextName)
 		if !options.BackgroundProxy && portForwarding {
 			log.Warnf("Since you are using port-forwarding to connect, you will need to leave this terminal open")
-			c := make(chan os.Signal, 1)
-			signal.Notify(c, os.Interrupt, syscall.SIGTERM)
-			go func() {
-				<-c
+			// the context is only usable as long as the port-forwarding runs, so it is removed again when the command exits
+			cleanup.Add("switch back to kube context "+globalFlags.Context, func() error {
 				kubeConfig, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(clientcmd.NewDefaultClientConfigLoadingRules(), &clientcmd.ConfigOverrides{}).RawConfig()
 				if err == nil && kubeConfig.CurrentContext == options.KubeConfigContextName {
 					err = deleteContext(&kubeConfig, options.KubeConfigContextName, globalFlags.Context)
 					if err != nil {
-						log.Errorf("Error deleting context: %v", err)
-					} else {
-						log.Infof("Switched back to context %v", globalFlags.Context)
+						return fmt.Errorf("delete context: %w", err)
 					}
+					unregisterContext(globalFlags.LoadedConfig(log), options.KubeConfigContextName, log)
+
+					log.Infof("Switched back to context %v", globalFlags.Context)
 				}
-				os.Exit(1)
-			}()
 
-			defer func() {
-				signal.Stop(c)
-			}()
+				return nil
+			})
 			log.WriteString(logrus.InfoLevel, "- Use CTRL+C to return to your previous kube context\n")
 			log.WriteString(logrus.InfoLevel, "- Use `kubectl get namespaces` in another terminal to access the vcluster\n")
 		} else {
@@ -201,7 +208,7 @@ func writeKubeConfig(kubeConfig *clientcmdapi.Config, vClusterName string, optio
 
 		log.Donef("Virtual cluster kube config written to: %s", options.KubeConfig)
 		if options.Server == "" {
-			log.WriteString(logrus.InfoLevel, fmt.Sprintf("- Use `vcluster connect %s -n %s -- kubectl get ns` to execute a command directly within this terminal\n", vClusterName, globalFlags.Namespace))
+			log.WriteString(logrus.InfoLevel, fmt.Sprintf("- Use `vcluster connect %s -n %s -- kubectl get ns` to execute a command directly within this terminal\n", kubeContext.Name, globalFlags.Namespace))
 		}
 		log.WriteString(logrus.InfoLevel, fmt.Sprintf("- Use `kubectl --kubeconfig %s get namespaces` to access the vcluster\n", options.KubeConfig))
 	}
@@ -263,7 +270,7 @@ func (cmd *connectHelm) prepare(ctx context.Context, vCluster *find.VCluster) er
 func (cmd *connectHelm) getVClusterKubeConfig(ctx context.Context, vclusterName string, command []string) (*clientcmdapi.Config, error) {
 	var err error
 	podName := cmd.PodName
-	if podName == "" {
+	if podName == "" && !cmd.ViaGateway {
 		waitErr := wait.PollUntilContextTimeout(ctx, time.Second, time.Second*30, true, func(ctx context.Context) (bool, error) {
 			// get vcluster pod name
 			var pods *corev1.PodList
@@ -311,6 +318,17 @@ func (cmd *connectHelm) getVClusterKubeConfig(ctx context.Context, vclusterName
 		return nil, err
 	}
 
+	// use the gateway of the virtual cluster instead of the control plane pod
+	if cmd.ViaGateway {
+		err = cmd.setServerFromGateway(ctx, vclusterName)
+		if err != nil {
+			return nil, err
+		}
+
+		// make sure local clients use the gateway as well
+		cmd.LocalPort = 0
+	}
+
 	// check if the vcluster is exposed and set server
 	if vclusterName != "" && cmd.Server == "" && len(command) == 0 {
 		err = cmd.setServerIfExposed(ctx, vclusterName, kubeConfig)
@@ -361,7 +379,7 @@ func (cmd *connectHelm) getVClusterKubeConfig(ctx context.Context, vclusterName
 	}
 
 	// start port forwarding
-	if cmd.ServiceAccount != "" || cmd.Server == "" || len(command) > 0 {
+	if !cmd.ViaGateway && (cmd.ServiceAccount != "" || cmd.Server == "" || len(command) > 0) {
 		cmd.portForwarding = true
 		cmd.interruptChan = make(chan struct{})
 		cmd.errorChan = make(chan error)
@@ -399,6 +417,45 @@ func (cmd *connectHelm) getVClusterKubeConfig(ctx context.Context, vclusterName
 	return kubeConfig, nil
 }
 
+// setServerFromGateway sets the server to the gateway of the virtual cluster, which is either the ingress host
+// or the load balancer address of the virtual cluster service. This allows connecting to virtual clusters whose
+// control plane runs in another cluster than the workloads.
+func (cmd *connectHelm) setServerFromGateway(ctx context.Context, vClusterName string) error {
+	if cmd.Server != "" {
+		return nil
+	}
+
+	ingress, err := cmd.kubeClient.NetworkingV1().Ingresses(cmd.Namespace).Get(ctx, vClusterName, metav1.GetOptions{})
+	if err != nil && !kerrors.IsNotFound(err) {
+		return fmt.Errorf("get vcluster ingress: %w", err)
+	} else if err == nil {
+		for _, rule := range ingress.Spec.Rules {
+			if rule.Host != "" {
+				cmd.Server = rule.Host
+				cmd.Log.Infof("Using vcluster %s ingress gateway: %s", vClusterName, cmd.Server)
+				return nil
+			}
+		}
+	}
+
+	service, err := cmd.kubeClient.CoreV1().Services(cmd.Namespace).Get(ctx, vClusterName, metav1.GetOptions{})
+	if err != nil && !kerrors.IsNotFound(err) {
+		return fmt.Errorf("get vcluster service: %w", err)
+	} else if err == nil && service.Spec.Type == corev1.ServiceTypeLoadBalancer && len(service.Status.LoadBalancer.Ingress) > 0 {
+		if service.Status.LoadBalancer.Ingress[0].Hostname != "" {
+			cmd.Server = service.Status.LoadBalancer.Ingress[0].Hostname
+		} else {
+			cmd.Server = service.Status.LoadBalancer.Ingress[0].IP
+		}
+		if cmd.Server != "" {
+			cmd.Log.Infof("Using vcluster %s load balancer gateway: %s", vClusterName, cmd.Server)
+			return nil
+		}
+	}
+
+	return fmt.Errorf("couldn't find a gateway for vcluster %s in namespace %s, please enable controlPlane.ingress or expose the vcluster service via a load balancer", vClusterName, cmd.Namespace)
+}
+
 func (cmd *connectHelm) setServerIfExposed(ctx context.Context, vClusterName string, vClusterConfig *clientcmdapi.Config) error {
 	printedWaiting := false
 	err := wait.PollUntilContextTimeout(ctx, time.Second*2, time.Minute*5, true, func(ctx context.Context) (done bool, err error) {
@@ -521,13 +578,13 @@ func executeCommand(vKubeConfig clientcmdapi.Config, command []string, errorChan
 	}
 
 	// write a temporary kube file
-	tempFile, err := os.CreateTemp("", "")
+	tempFile, tempFileCleanup, err := cleanup.TempFile("")
 	if err != nil {
-		return fmt.Errorf("create temp file: %w", err)
+		return err
 	}
-	defer func(name string) {
-		_ = os.Remove(name)
-	}(tempFile.Name())
+	defer func() {
+		_ = tempFileCleanup.Run()
+	}()
 
 	_, err = tempFile.Write(out)
 	if err != nil {
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/loft-sh/log"
+	"github.com/loft-sh/vcluster/pkg/cli/config"
 	"github.com/loft-sh/vcluster/pkg/cli/find"
 	"github.com/loft-sh/vcluster/pkg/cli/flags"
 	"github.com/loft-sh/vcluster/pkg/platform"
@@ -69,7 +70,14 @@ func ConnectPlatform(ctx context.Context, options *ConnectOptions, globalFlags *
 		return executeCommand(*kubeConfig, command, nil, cmd.log)
 	}
 
-	return writeKubeConfig(kubeConfig, vCluster.VirtualCluster.Name, options, globalFlags, false, log)
+	_, _, parentContext := find.VClusterPlatformFromContext(options.KubeConfigContextName)
+	return writeKubeConfig(kubeConfig, config.KubeContext{
+		Driver:        config.PlatformDriver,
+		Name:          vCluster.VirtualCluster.Name,
+		Project:       vCluster.Project.Name,
+		Host:          platformClient.Config().Platform.Host,
+		ParentContext: parentContext,
+	}, options, globalFlags, false, log)
 }
 
 func (cmd *connectPlatform) validateProFlags() error {
@@ -0,0 +1,105 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/vcluster/config"
+	"github.com/loft-sh/vcluster/config/legacyconfig"
+	"github.com/loft-sh/vcluster/pkg/cli/cleanup"
+	"github.com/loft-sh/vcluster/pkg/cli/find"
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/loft-sh/vcluster/pkg/helm"
+	"k8s.io/client-go/kubernetes"
+)
+
+// ConvertConfig converts the given pre-v0.20 values of the distro to the v0.20 format. The set values are applied to
+// the legacy values before they are converted.
+func ConvertConfig(distro, legacyValues string, setValues []string) (string, error) {
+	if len(setValues) > 0 {
+		var err error
+		legacyValues, err = mergeAllValues(setValues, nil, legacyValues)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return legacyconfig.MigrateLegacyConfig(distro, legacyValues)
+}
+
+// LegacyReleaseValues returns the user supplied values and the distro of a deployed pre-v0.20 virtual cluster
+func LegacyReleaseValues(ctx context.Context, globalFlags *flags.GlobalFlags, vClusterName string, log log.Logger) (string, string, error) {
+	vCluster, err := find.GetVCluster(ctx, globalFlags.Context, vClusterName, globalFlags.Namespace, log)
+	if err != nil {
+		return "", "", err
+	}
+
+	restConfig, err := vCluster.ClientFactory.ClientConfig()
+	if err != nil {
+		return "", "", err
+	}
+	kubeClient, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return "", "", err
+	}
+
+	release, err := helm.NewSecrets(kubeClient).Get(ctx, vClusterName, vCluster.Namespace)
+	if err != nil {
+		return "", "", fmt.Errorf("get helm release of vcluster %s: %w", vClusterName, err)
+	} else if release.Chart == nil || release.Chart.Metadata == nil {
+		return "", "", fmt.Errorf("helm release of vcluster %s has no chart metadata", vClusterName)
+	} else if !isLegacyVCluster(release.Chart.Metadata.Version) {
+		return "", "", fmt.Errorf("vcluster %s is already deployed with chart version %s, which uses the v0.20 config format", vClusterName, release.Chart.Metadata.Version)
+	}
+
+	values, err := helmExtraValuesYAML(release)
+	if err != nil {
+		return "", "", err
+	}
+
+	return values, legacyDistro(release), nil
+}
+
+// ApplyConvertedConfig upgrades the deployed pre-v0.20 virtual cluster with the converted config. The options configure
+// the chart and the deployment like for vcluster create, the converted config is used as first values file.
+func ApplyConvertedConfig(ctx context.Context, globalFlags *flags.GlobalFlags, vClusterName, convertedConfig string, options *CreateOptions, log log.Logger) error {
+	tempFile, tempFileCleanup, err := cleanup.TempFile("vcluster-converted-*.yaml")
+	if err != nil {
+		return fmt.Errorf("create temp values file: %w", err)
+	}
+	defer func() {
+		_ = tempFileCleanup.Run()
+	}()
+
+	_, err = tempFile.WriteString(convertedConfig)
+	if err != nil {
+		_ = tempFile.Close()
+		return fmt.Errorf("write converted config to temp values file: %w", err)
+	}
+	err = tempFile.Close()
+	if err != nil {
+		return fmt.Errorf("close temp values file: %w", err)
+	}
+
+	createOptions := *options
+	createOptions.Values = append([]string{tempFile.Name()}, options.Values...)
+	createOptions.Upgrade = true
+	if createOptions.Distro == "" {
+		createOptions.Distro = config.K8SDistro
+	}
+
+	return CreateHelm(ctx, &createOptions, globalFlags, vClusterName, log)
+}
+
+// legacyDistro infers the distro of a pre-v0.20 virtual cluster from its chart name
+func legacyDistro(release *helm.Release) string {
+	distro := strings.TrimPrefix(release.Chart.Metadata.Name, "vcluster-")
+	// the old k3s chart is the one without a prefix
+	if distro == "vcluster" {
+		distro = config.K3SDistro
+	}
+
+	return distro
+}
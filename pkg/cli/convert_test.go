@@ -0,0 +1,25 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/loft-sh/vcluster/pkg/helm"
+	"gotest.tools/v3/assert"
+)
+
+func TestConvertConfig(t *testing.T) {
+	converted, err := ConvertConfig("k3s", "syncer:\n  replicas: 1\n", []string{"syncer.replicas=3"})
+	assert.NilError(t, err)
+	assert.Assert(t, strings.Contains(converted, "replicas: 3"), converted)
+
+	_, err = ConvertConfig("k3s", "", []string{"syncer.replicas[=3"})
+	assert.ErrorContains(t, err, "apply --set")
+}
+
+func TestLegacyDistro(t *testing.T) {
+	for chart, distro := range map[string]string{"vcluster": "k3s", "vcluster-k8s": "k8s", "vcluster-k0s": "k0s", "vcluster-eks": "eks"} {
+		release := &helm.Release{Chart: &helm.Chart{Metadata: &helm.Metadata{Name: chart}}}
+		assert.Equal(t, legacyDistro(release), distro)
+	}
+}
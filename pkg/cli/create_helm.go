@@ -18,9 +18,11 @@ import (
 	"github.com/loft-sh/log/terminal"
 	"github.com/loft-sh/vcluster/config"
 	"github.com/loft-sh/vcluster/config/legacyconfig"
+	"github.com/loft-sh/vcluster/pkg/cli/cleanup"
 	"github.com/loft-sh/vcluster/pkg/cli/find"
 	"github.com/loft-sh/vcluster/pkg/cli/flags"
 	"github.com/loft-sh/vcluster/pkg/cli/localkubernetes"
+	pkgconfig "github.com/loft-sh/vcluster/pkg/config"
 	"github.com/loft-sh/vcluster/pkg/constants"
 	"github.com/loft-sh/vcluster/pkg/embed"
 	"github.com/loft-sh/vcluster/pkg/helm"
@@ -49,11 +51,20 @@ type CreateOptions struct {
 	ChartVersion          string
 	ChartName             string
 	ChartRepo             string
+	ChartRepoUsername     string
+	ChartRepoPassword     string
+	ChartRepoCAFile       string
 	LocalChartDir         string
 	Distro                string
 	Values                []string
 	SetValues             []string
+	Presets               []string
+	ImageRegistry         string
+	ExpandEnv             bool
 	Print                 bool
+	Policy                string
+	PolicyOverrideTokens  []string
+	Output                string
 
 	KubernetesVersion string
 
@@ -67,6 +78,20 @@ type CreateOptions struct {
 	ExposeLocal     bool
 	Connect         bool
 	Upgrade         bool
+	Headless        bool
+	Verify          bool
+
+	VerifyServerImage string
+	VerifyClientImage string
+
+	RollbackTimeout time.Duration
+	Canary          bool
+	CanaryDuration  time.Duration
+	AdoptRelease    bool
+	RegisterArgoCD  string
+	FluxBootstrap   string
+	FluxBranch      string
+	FluxPath        string
 
 	// Platform
 	Project         string
@@ -85,6 +110,8 @@ type CreateOptions struct {
 	UseExisting     bool
 	Recreate        bool
 	SkipWait        bool
+	// Preview prints the rendered template instead of creating the virtual cluster
+	Preview bool
 }
 
 var CreatedByVClusterAnnotation = "vcluster.loft.sh/created"
@@ -100,6 +127,10 @@ type createHelm struct {
 	kubeClientConfig clientcmd.ClientConfig
 	kubeClient       *kubernetes.Clientset
 	localCluster     bool
+
+	// outputLog prints the json output, all other messages are logged to stderr then
+	outputLog log.Logger
+	actions   []string
 }
 
 func CreateHelm(ctx context.Context, options *CreateOptions, globalFlags *flags.GlobalFlags, vClusterName string, log log.Logger) error {
@@ -110,6 +141,42 @@ func CreateHelm(ctx context.Context, options *CreateOptions, globalFlags *flags.
 		log: log,
 	}
 
+	switch options.Output {
+	case "", "text":
+	case "json":
+		if options.Print {
+			return fmt.Errorf("--print cannot be used together with --output json")
+		}
+
+		cmd.outputLog = log
+		cmd.log = log.ErrorStreamOnly()
+	default:
+		return fmt.Errorf("unsupported output format %s, please select one of: text, json", options.Output)
+	}
+
+	values, cleanupValues, err := resolveValuesFiles(ctx, options.Values, options.ExpandEnv)
+	if err != nil {
+		return err
+	}
+	defer cleanupValues()
+	options.Values = values
+
+	// presets are layered under the values of the user
+	values, presetCleanup, err := presetValuesFiles(options.Presets, options.Values)
+	if err != nil {
+		return err
+	} else if presetCleanup != nil {
+		defer func() {
+			_ = presetCleanup.Run()
+		}()
+	}
+	options.Values = values
+
+	// the image registry is a shortcut for controlPlane.advanced.defaultImageRegistry and takes precedence over the values
+	if options.ImageRegistry != "" {
+		options.SetValues = append(options.SetValues, "controlPlane.advanced.defaultImageRegistry="+strings.TrimSuffix(options.ImageRegistry, "/"))
+	}
+
 	// make sure we deploy the correct version
 	if options.ChartVersion == upgrade.DevelopmentVersion {
 		options.ChartVersion = ""
@@ -158,30 +225,22 @@ func CreateHelm(ctx context.Context, options *CreateOptions, globalFlags *flags.
 	}
 
 	currentVClusterConfig := &config.Config{}
+	currentValues := ""
 	if isVClusterDeployed(release) {
-		currentValues, err := helmExtraValuesYAML(release)
+		currentValues, err = helmExtraValuesYAML(release)
 		if err != nil {
 			return err
 		}
 		// TODO Delete after vCluster 0.19.x resp. the old config format is out of support.
 		if isLegacyVCluster(release.Chart.Metadata.Version) {
 			// If we have a < v0.20 virtual cluster running we have to infer the distro from the current chart name.
-			currentDistro := strings.TrimPrefix(release.Chart.Metadata.Name, "vcluster-")
-			// If we are upgrading a vCluster < v0.20 the old k3s chart is the one without a prefix.
-			if currentDistro == "vcluster" {
-				currentDistro = config.K3SDistro
-			}
+			currentDistro := legacyDistro(release)
 			// Early abort if a user runs a virtual cluster < v0.20 without providing any values files during an upgrade.
 			// We do this because we don't want to "automagically" convert the old config implicitly, without the user
 			// realizing that the virtual cluster is running with the old config format.
 			if len(cmd.Values) == 0 {
-				helmCommand := fmt.Sprintf("helm -n %s get values %s -o yaml", cmd.Namespace, vClusterName)
-				if currentValues == "" {
-					helmCommand = fmt.Sprintf("%s -a", helmCommand)
-				}
-
-				command := fmt.Sprintf("%s | vcluster convert config --distro %s", helmCommand, currentDistro)
-				return fmt.Errorf("it appears you are using a vCluster configuration using pre-v0.20 formatting. Please run the following to convert the values to the latest format:\n%s", command)
+				command := fmt.Sprintf("vcluster convert config --release %s -n %s --apply", vClusterName, cmd.Namespace)
+				return fmt.Errorf("it appears you are using a vCluster configuration using pre-v0.20 formatting. Please run the following to convert the values to the latest format and upgrade the virtual cluster:\n%s", command)
 			}
 
 			// At this point the user did pass a config file.
@@ -217,14 +276,14 @@ func CreateHelm(ctx context.Context, options *CreateOptions, globalFlags *flags.
 		}
 
 		// write a temporary values file
-		tempFile, err := os.CreateTemp("", "")
-		tempValuesFile := tempFile.Name()
+		tempFile, tempFileCleanup, err := cleanup.TempFile("")
 		if err != nil {
 			return fmt.Errorf("create temp values file: %w", err)
 		}
-		defer func(name string) {
-			_ = os.Remove(name)
-		}(tempValuesFile)
+		defer func() {
+			_ = tempFileCleanup.Run()
+		}()
+		tempValuesFile := tempFile.Name()
 
 		_, err = tempFile.Write([]byte(decodedString))
 		if err != nil {
@@ -242,6 +301,18 @@ func CreateHelm(ctx context.Context, options *CreateOptions, globalFlags *flags.
 	// resetting this as the base64 encoded strings should be removed and only valid file names should be kept.
 	cmd.Values = newExtraValues
 
+	// detect changes of the release values that weren't done by the CLI
+	if isVClusterDeployed(release) {
+		adoptedValuesCleanup, err := cmd.checkValuesChecksum(ctx, release, vClusterName, currentValues)
+		if err != nil {
+			return err
+		} else if adoptedValuesCleanup != nil {
+			defer func() {
+				_ = adoptedValuesCleanup.Run()
+			}()
+		}
+	}
+
 	// find out kubernetes version
 	kubernetesVersion, err := cmd.getKubernetesVersion()
 	if err != nil {
@@ -263,8 +334,38 @@ func CreateHelm(ctx context.Context, options *CreateOptions, globalFlags *flags.
 	if err != nil {
 		return err
 	}
+	for _, result := range pkgconfig.Lint(vClusterConfig) {
+		cmd.log.Warnf("vcluster.yaml lint %s: %s (run `vcluster lint` for details)", result.Severity, result)
+	}
+
+	// enforce the organization policy on the merged values
+	finalValues, err := mergeAllValues(cmd.SetValues, cmd.Values, chartValues)
+	if err != nil {
+		return fmt.Errorf("merge values: %w", err)
+	}
+	err = enforcePolicy(ctx, cmd.Policy, cmd.PolicyOverrideTokens, finalValues, cmd.kubeClient, cmd.log)
+	if err != nil {
+		return err
+	}
 
+	// install flux within the vcluster once it's up
+	if cmd.FluxBootstrap != "" {
+		fluxValuesCleanup, err := cmd.addFluxBootstrap(vClusterConfig)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = fluxValuesCleanup.Run()
+		}()
+	}
+
+	// validate isolated control plane
+	err = config.ValidateIsolatedControlPlane(vClusterConfig.Experimental.IsolatedControlPlane)
+	if err != nil {
+		return err
+	}
 	if vClusterConfig.Experimental.IsolatedControlPlane.Headless {
+		cmd.log.Infof("Deploying virtual cluster %s in headless mode, only the workload resources will be created. Run the control plane in another cluster with experimental.isolatedControlPlane.enabled", vClusterName)
 		cmd.Connect = false
 	}
 
@@ -284,11 +385,53 @@ func CreateHelm(ctx context.Context, options *CreateOptions, globalFlags *flags.
 	}
 
 	// we have to upgrade / install the chart
-	err = cmd.deployChart(ctx, vClusterName, chartValues, helmBinaryPath)
+	err = cmd.deployChart(ctx, vClusterName, chartValues, helmBinaryPath, release)
 	if err != nil {
 		return err
 	}
 
+	// remember the deployed values to detect out-of-band helm changes on the next upgrade
+	err = storeValuesChecksum(ctx, cmd.kubeClient, vClusterName, cmd.Namespace)
+	if err != nil {
+		cmd.log.Warnf("Error storing values checksum of vcluster %s: %v", vClusterName, err)
+	}
+
+	// make the vcluster a deploy target of argo cd
+	if cmd.RegisterArgoCD != "" {
+		err = cmd.registerArgoCD(ctx, vClusterName)
+		if err != nil {
+			return fmt.Errorf("register vcluster in argo cd: %w", err)
+		}
+	}
+
+	// smoke test a newly created virtual cluster, headless virtual clusters have no control plane to test
+	if cmd.Verify && !isVClusterDeployed(release) && !vClusterConfig.Experimental.IsolatedControlPlane.Headless {
+		serverImage := cmd.VerifyServerImage
+		if serverImage == "" {
+			serverImage = config.WithDefaultImageRegistry(vClusterConfig.ControlPlane.Advanced.DefaultImageRegistry, DefaultVerifyServerImage)
+		}
+		clientImage := cmd.VerifyClientImage
+		if clientImage == "" {
+			clientImage = config.WithDefaultImageRegistry(vClusterConfig.ControlPlane.Advanced.DefaultImageRegistry, DefaultVerifyClientImage)
+		}
+
+		err = Verify(ctx, &VerifyOptions{
+			Timeout:     2 * time.Minute,
+			ServerImage: serverImage,
+			ClientImage: clientImage,
+		}, cmd.GlobalFlags, vClusterName, cmd.log)
+		if err != nil {
+			cmd.log.Warnf("Verifying virtual cluster %s failed: %v. Run 'vcluster verify %s --namespace %s' to repeat the checks", vClusterName, err, vClusterName, cmd.Namespace)
+		}
+	}
+
+	if cmd.outputLog != nil {
+		err = cmd.printOutput(ctx, vClusterName, release)
+		if err != nil {
+			return err
+		}
+	}
+
 	// check if we should connect to the vcluster or print the kubeconfig
 	if cmd.Connect || cmd.Print {
 		cmd.log.Donef("Successfully created virtual cluster %s in namespace %s", vClusterName, cmd.Namespace)
@@ -357,11 +500,13 @@ func (cmd *createHelm) addVCluster(ctx context.Context, vClusterConfig *config.C
 		return nil
 	}
 
-	err = platform.ApplyPlatformSecret(ctx, cmd.LoadedConfig(cmd.log), cmd.kubeClient, "", cmd.Namespace, cmd.Project, "", "", false)
+	_, err = platform.ApplyPlatformSecret(ctx, cmd.LoadedConfig(cmd.log), cmd.kubeClient, "", cmd.Namespace, cmd.Project, "", "", false)
 	if err != nil {
 		return fmt.Errorf("apply platform secret: %w", err)
 	}
 
+	cmd.recordAction(CreateActionAddPlatform)
+
 	return nil
 }
 
@@ -420,7 +565,7 @@ func getBase64DecodedString(values string) (string, error) {
 	return string(strDecoded), nil
 }
 
-func (cmd *createHelm) deployChart(ctx context.Context, vClusterName, chartValues, helmExecutablePath string) error {
+func (cmd *createHelm) deployChart(ctx context.Context, vClusterName, chartValues, helmExecutablePath string, release *helm.Release) error {
 	// check if there is a vcluster directory already
 	workDir, err := os.Getwd()
 	if err != nil {
@@ -431,39 +576,13 @@ func (cmd *createHelm) deployChart(ctx context.Context, vClusterName, chartValue
 	}
 
 	if cmd.LocalChartDir == "" {
-		chartEmbedded := false
-		if cmd.ChartVersion == upgrade.GetVersion() { // use embedded chart if default version
-			embeddedChartName := fmt.Sprintf("%s-%s.tgz", cmd.ChartName, upgrade.GetVersion())
-			// not using filepath.Join because the embed.FS separator is not OS specific
-			embeddedChartPath := fmt.Sprintf("chart/%s", embeddedChartName)
-			embeddedChartFile, err := embed.Charts.ReadFile(embeddedChartPath)
-			if err != nil && errors.Is(err, fs.ErrNotExist) {
-				cmd.log.Infof("Chart not embedded: %q, pulling from helm repository.", err)
-			} else if err != nil {
-				cmd.log.Errorf("Unexpected error while accessing embedded file: %q", err)
-			} else {
-				temp, err := os.CreateTemp("", fmt.Sprintf("%s%s", embeddedChartName, "-"))
-				if err != nil {
-					cmd.log.Errorf("Error creating temp file: %v", err)
-				} else {
-					defer temp.Close()
-					defer os.Remove(temp.Name())
-					_, err = temp.Write(embeddedChartFile)
-					if err != nil {
-						cmd.log.Errorf("Error writing package file to temp: %v", err)
-					}
-					cmd.LocalChartDir = temp.Name()
-					chartEmbedded = true
-					cmd.log.Debugf("Using embedded chart: %q", embeddedChartName)
-				}
-			}
-		}
-
-		// rewrite chart location, this is an optimization to avoid
-		// downloading the whole index.yaml and parsing it
-		if !chartEmbedded && cmd.ChartRepo == constants.LoftChartRepo && cmd.ChartVersion != "" { // specify versioned path to repo url
-			cmd.LocalChartDir = constants.LoftChartRepo + "/charts/" + cmd.ChartName + "-" + strings.TrimPrefix(cmd.ChartVersion, "v") + ".tgz"
+		chartPath, chartCleanup := localChartPath(ctx, cmd.ChartName, cmd.ChartVersion, cmd.ChartRepo, cmd.chartRegistryCredentials, cmd.log)
+		if chartCleanup != nil {
+			defer func() {
+				_ = chartCleanup.Run()
+			}()
 		}
+		cmd.LocalChartDir = chartPath
 	}
 
 	if cmd.Upgrade {
@@ -472,8 +591,38 @@ func (cmd *createHelm) deployChart(ctx context.Context, vClusterName, chartValue
 		cmd.log.Infof("Create vcluster %s...", vClusterName)
 	}
 
+	// remember the deployed revision, so we can roll back if the upgraded vcluster doesn't come up
+	var snapshot *upgradeSnapshot
+	if isVClusterDeployed(release) && cmd.RollbackTimeout > 0 {
+		snapshot, err = snapshotRelease(ctx, cmd.kubeClient, release, vClusterName, cmd.Namespace)
+		if err != nil {
+			return err
+		}
+	}
+
+	// hold back all other replicas until the canary replica proved to be healthy
+	canary := int32(-1)
+	if isVClusterDeployed(release) && cmd.Canary {
+		canary, err = cmd.startCanary(ctx, vClusterName)
+		if err != nil {
+			return err
+		}
+	}
+
 	// we have to upgrade / install the chart
-	err = helm.NewClient(&cmd.rawConfig, cmd.log, helmExecutablePath).Upgrade(ctx, vClusterName, cmd.Namespace, helm.UpgradeOptions{
+	username, password, caFile := cmd.chartRepoCredentials()
+	helmClient, err := cmd.newHelmClient(helmExecutablePath)
+	if err != nil {
+		return err
+	}
+	var partialRelease *cleanup.Handle
+	if release == nil {
+		// a new release shouldn't be left behind half installed if the installation fails or is interrupted
+		partialRelease = cleanup.Add("delete partially installed release "+vClusterName, func() error {
+			return helmClient.Delete(vClusterName, cmd.Namespace)
+		})
+	}
+	err = helmClient.Upgrade(ctx, vClusterName, cmd.Namespace, helm.UpgradeOptions{
 		CreateNamespace: cmd.CreateNamespace,
 		Chart:           cmd.ChartName,
 		Repo:            cmd.ChartRepo,
@@ -482,15 +631,88 @@ func (cmd *createHelm) deployChart(ctx context.Context, vClusterName, chartValue
 		Values:          chartValues,
 		ValuesFiles:     cmd.Values,
 		SetValues:       cmd.SetValues,
+		Username:        username,
+		Password:        password,
+		CaFile:          caFile,
 		Debug:           cmd.Debug,
 	})
+	if partialRelease != nil {
+		if err != nil {
+			cmd.log.Infof("Removing partially installed vcluster %s...", vClusterName)
+			if cleanupErr := partialRelease.Run(); cleanupErr != nil {
+				cmd.log.Warnf("Error removing partially installed vcluster %s: %v", vClusterName, cleanupErr)
+			}
+		} else {
+			partialRelease.Discard()
+		}
+	}
+	if err == nil && canary >= 0 {
+		err = cmd.verifyCanary(ctx, vClusterName, canary)
+		if err == nil {
+			cmd.recordAction(CreateActionCanary)
+
+			// the canary is healthy, so continue the rollout with the remaining replicas
+			err = setStatefulSetPartition(ctx, cmd.kubeClient, vClusterName, cmd.Namespace, 0)
+			if err == nil {
+				canary = -1
+			}
+		}
+	}
+	if snapshot == nil {
+		if err != nil && canary >= 0 {
+			cmd.log.Warnf("Only the canary replica of vcluster %s was upgraded, fix the config and upgrade again or run `helm rollback %s -n %s`", vClusterName, vClusterName, cmd.Namespace)
+		}
+	} else if err != nil {
+		// the partition is kept while rolling back, so only the canary replica is rolled back
+		return cmd.rollbackUpgrade(ctx, helmClient, snapshot, vClusterName, canary, err)
+	} else {
+		err = waitForVClusterReady(ctx, cmd.kubeClient, vClusterName, cmd.Namespace, cmd.RollbackTimeout, cmd.log)
+		if err != nil {
+			return cmd.rollbackUpgrade(ctx, helmClient, snapshot, vClusterName, -1, err)
+		}
+	}
 	if err != nil {
 		return err
 	}
 
+	if isVClusterDeployed(release) {
+		cmd.recordAction(CreateActionUpgrade)
+	} else {
+		cmd.recordAction(CreateActionInstall)
+	}
+
 	return nil
 }
 
+// chartRepoCredentials returns the credentials of the chart repository, the flags take precedence over the credentials
+// of the repository in the cli config
+func (cmd *createHelm) chartRepoCredentials() (string, string, string) {
+	username, password, caFile := cmd.ChartRepoUsername, cmd.ChartRepoPassword, cmd.ChartRepoCAFile
+	chartRepo := cmd.LoadedConfig(cmd.log).Helm.ChartRepo(cmd.ChartRepo)
+	if chartRepo == nil {
+		return username, password, caFile
+	}
+
+	if username == "" {
+		username, password = chartRepo.Username, chartRepo.Password
+	}
+	if caFile == "" {
+		caFile = chartRepo.CAFile
+	}
+
+	return username, password, caFile
+}
+
+// chartRegistryCredentials returns the credentials of an oci:// chart repo, which default to the docker config
+func (cmd *createHelm) chartRegistryCredentials(registry string) (string, string) {
+	username, password, _ := cmd.chartRepoCredentials()
+	if username != "" {
+		return username, password
+	}
+
+	return dockerCredentials(registry)
+}
+
 func (cmd *createHelm) ToChartOptions(kubernetesVersion *version.Info, log log.Logger) (*config.ExtraValuesOptions, error) {
 	if !util.Contains(cmd.Distro, AllowedDistros) {
 		return nil, fmt.Errorf("unsupported distro %s, please select one of: %s", cmd.Distro, strings.Join(AllowedDistros, ", "))
@@ -509,6 +731,7 @@ func (cmd *createHelm) ToChartOptions(kubernetesVersion *version.Info, log log.L
 		Expose:    cmd.Expose,
 		SyncNodes: cmd.localCluster,
 		NodePort:  cmd.localCluster,
+		Headless:  cmd.Headless,
 		KubernetesVersion: config.KubernetesVersion{
 			Major: kubernetesVersion.Major,
 			Minor: kubernetesVersion.Minor,
@@ -650,6 +873,8 @@ func (cmd *createHelm) createNamespace(ctx context.Context) error {
 	if err != nil {
 		return fmt.Errorf("create namespace: %w", err)
 	}
+
+	cmd.recordAction(CreateActionCreateNamespace)
 	return nil
 }
 
@@ -693,3 +918,69 @@ func (cmd *createHelm) getKubernetesVersion() (*version.Info, error) {
 
 	return kubernetesVersion, nil
 }
+
+// newHelmClient records all helm invocations in a debug log with redacted secrets if --debug is set
+func (cmd *createHelm) newHelmClient(helmExecutablePath string) (helm.Client, error) {
+	if !cmd.Debug {
+		return helm.NewClient(&cmd.rawConfig, cmd.log, helmExecutablePath), nil
+	}
+
+	sensitivePaths, err := config.SensitivePaths()
+	if err != nil {
+		return nil, err
+	}
+	debugLog, err := helm.NewDebugLog(sensitivePaths)
+	if err != nil {
+		return nil, err
+	}
+
+	cmd.log.Debugf("Recording helm invocations to %s", debugLog.Path)
+	return helm.NewDebugClient(&cmd.rawConfig, cmd.log, helmExecutablePath, debugLog), nil
+}
+
+// localChartPath returns the embedded chart if the version matches the cli version, the chart pulled from an oci:// repo
+// or the versioned path of the chart within the loft repo. It returns an empty path if the chart has to be pulled from
+// the repo by name.
+func localChartPath(ctx context.Context, chartName, chartVersion, chartRepo string, credentials registryCredentials, log log.Logger) (string, *cleanup.Handle) {
+	if chartVersion == upgrade.GetVersion() { // use embedded chart if default version
+		embeddedChartName := fmt.Sprintf("%s-%s.tgz", chartName, upgrade.GetVersion())
+		// not using filepath.Join because the embed.FS separator is not OS specific
+		embeddedChartPath := fmt.Sprintf("chart/%s", embeddedChartName)
+		embeddedChartFile, err := embed.Charts.ReadFile(embeddedChartPath)
+		if err != nil && errors.Is(err, fs.ErrNotExist) {
+			log.Infof("Chart not embedded: %q, pulling from helm repository.", err)
+		} else if err != nil {
+			log.Errorf("Unexpected error while accessing embedded file: %q", err)
+		} else {
+			temp, tempCleanup, err := cleanup.TempFile(fmt.Sprintf("%s%s", embeddedChartName, "-"))
+			if err != nil {
+				log.Errorf("Error creating temp file: %v", err)
+			} else {
+				_, err = temp.Write(embeddedChartFile)
+				if err != nil {
+					log.Errorf("Error writing package file to temp: %v", err)
+				}
+				log.Debugf("Using embedded chart: %q", embeddedChartName)
+				return temp.Name(), tempCleanup
+			}
+		}
+	}
+
+	// pull the chart artifact directly with the docker credentials, helm would need a separate registry login
+	if strings.HasPrefix(chartRepo, "oci://") && chartVersion != "" {
+		chartPath, chartCleanup, err := pullOCIChart(ctx, chartName, chartVersion, chartRepo, credentials, log)
+		if err == nil {
+			return chartPath, chartCleanup
+		}
+
+		log.Warnf("Error pulling chart, will fallback to helm: %v", err)
+	}
+
+	// rewrite chart location, this is an optimization to avoid
+	// downloading the whole index.yaml and parsing it
+	if chartRepo == constants.LoftChartRepo && chartVersion != "" { // specify versioned path to repo url
+		return constants.LoftChartRepo + "/charts/" + chartName + "-" + strings.TrimPrefix(chartVersion, "v") + ".tgz", nil
+	}
+
+	return "", nil
+}
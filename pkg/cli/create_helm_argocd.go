@@ -0,0 +1,194 @@
+package cli
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"github.com/loft-sh/vcluster/pkg/cli/find"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+const (
+	// ArgoCDSecretTypeLabel marks secrets Argo CD reads its clusters from
+	ArgoCDSecretTypeLabel = "argocd.argoproj.io/secret-type"
+
+	// ArgoCDVClusterNameLabel and ArgoCDVClusterNamespaceLabel reference the virtual cluster of an Argo CD cluster secret
+	ArgoCDVClusterNameLabel      = "vcluster.loft.sh/argocd-vcluster-name"
+	ArgoCDVClusterNamespaceLabel = "vcluster.loft.sh/argocd-vcluster-namespace"
+
+	// ArgoCDNamespaceAnnotation on the service of the virtual cluster holds the Argo CD namespace it was registered in,
+	// so delete only cleans up the cluster secret of registered virtual clusters
+	ArgoCDNamespaceAnnotation = "vcluster.loft.sh/argocd-namespace"
+
+	// argoCDServiceAccount is the service account within the virtual cluster Argo CD authenticates as
+	argoCDServiceAccount = "kube-system/argocd-manager"
+)
+
+// argoCDClusterConfig is the connection config of an Argo CD cluster secret
+type argoCDClusterConfig struct {
+	BearerToken     string                `json:"bearerToken,omitempty"`
+	TLSClientConfig argoCDTLSClientConfig `json:"tlsClientConfig"`
+}
+
+type argoCDTLSClientConfig struct {
+	Insecure bool   `json:"insecure,omitempty"`
+	CAData   string `json:"caData,omitempty"`
+}
+
+// registerArgoCD creates the Argo CD cluster secret of the virtual cluster in the given namespace of the host cluster,
+// so the virtual cluster shows up as deploy target. Argo CD runs in the host cluster and accesses the virtual cluster
+// through its service with the token of a dedicated service account.
+func (cmd *createHelm) registerArgoCD(ctx context.Context, vClusterName string) error {
+	vCluster, err := find.GetVCluster(ctx, cmd.Context, vClusterName, cmd.Namespace, cmd.log)
+	if err != nil {
+		return err
+	}
+
+	cmd.log.Infof("Registering vcluster %s in Argo CD namespace %s...", vClusterName, cmd.RegisterArgoCD)
+	connectCmd := &connectHelm{
+		GlobalFlags: cmd.GlobalFlags,
+		ConnectOptions: &ConnectOptions{
+			Server:                    fmt.Sprintf("https://%s.%s.svc:443", vClusterName, cmd.Namespace),
+			ServiceAccount:            argoCDServiceAccount,
+			ServiceAccountClusterRole: "cluster-admin",
+			// silences the port-forwarding that is used to create the service account token
+			BackgroundProxy: true,
+		},
+		Log: cmd.log,
+	}
+	err = connectCmd.prepare(ctx, vCluster)
+	if err != nil {
+		return err
+	}
+
+	kubeConfig, err := connectCmd.getVClusterKubeConfig(ctx, vClusterName, nil)
+	if connectCmd.portForwarding {
+		close(connectCmd.interruptChan)
+		<-connectCmd.errorChan
+	}
+	if err != nil {
+		return err
+	}
+
+	secret, err := argoCDClusterSecret(vClusterName, cmd.Namespace, cmd.RegisterArgoCD, kubeConfig)
+	if err != nil {
+		return err
+	}
+
+	err = applyArgoCDClusterSecret(ctx, cmd.kubeClient, secret)
+	if err != nil {
+		return err
+	}
+
+	patch := fmt.Sprintf(`{"metadata":{"annotations":{%q:%q}}}`, ArgoCDNamespaceAnnotation, cmd.RegisterArgoCD)
+	_, err = cmd.kubeClient.CoreV1().Services(cmd.Namespace).Patch(ctx, vClusterName, types.MergePatchType, []byte(patch), metav1.PatchOptions{})
+	if err != nil {
+		return fmt.Errorf("annotate vcluster service with argo cd namespace: %w", err)
+	}
+
+	cmd.recordAction(CreateActionRegisterArgoCD)
+	cmd.log.Donef("Registered vcluster %s as Argo CD cluster %s", vClusterName, secret.Name)
+	return nil
+}
+
+func argoCDClusterSecret(vClusterName, vClusterNamespace, argoCDNamespace string, kubeConfig *clientcmdapi.Config) (*corev1.Secret, error) {
+	clusterConfig := argoCDClusterConfig{}
+	server := ""
+	for _, cluster := range kubeConfig.Clusters {
+		server = cluster.Server
+		clusterConfig.TLSClientConfig.Insecure = cluster.InsecureSkipTLSVerify
+		if len(cluster.CertificateAuthorityData) > 0 {
+			clusterConfig.TLSClientConfig.CAData = base64.StdEncoding.EncodeToString(cluster.CertificateAuthorityData)
+		}
+	}
+	for _, authInfo := range kubeConfig.AuthInfos {
+		clusterConfig.BearerToken = authInfo.Token
+	}
+	if server == "" || clusterConfig.BearerToken == "" {
+		return nil, fmt.Errorf("vcluster kube config has no server or service account token")
+	}
+
+	rawClusterConfig, err := json.Marshal(clusterConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	name := translate.SafeConcatName("vcluster", vClusterName, vClusterNamespace)
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: argoCDNamespace,
+			Labels: map[string]string{
+				ArgoCDSecretTypeLabel:        "cluster",
+				ArgoCDVClusterNameLabel:      vClusterName,
+				ArgoCDVClusterNamespaceLabel: vClusterNamespace,
+			},
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{
+			"name":   []byte(name),
+			"server": []byte(server),
+			"config": rawClusterConfig,
+		},
+	}, nil
+}
+
+func applyArgoCDClusterSecret(ctx context.Context, kubeClient kubernetes.Interface, secret *corev1.Secret) error {
+	existing, err := kubeClient.CoreV1().Secrets(secret.Namespace).Get(ctx, secret.Name, metav1.GetOptions{})
+	if err != nil {
+		if !kerrors.IsNotFound(err) {
+			return fmt.Errorf("get argo cd cluster secret: %w", err)
+		}
+
+		_, err = kubeClient.CoreV1().Secrets(secret.Namespace).Create(ctx, secret, metav1.CreateOptions{})
+		if err != nil {
+			return fmt.Errorf("create argo cd cluster secret: %w", err)
+		}
+
+		return nil
+	}
+
+	if existing.Labels == nil {
+		existing.Labels = map[string]string{}
+	}
+	for key, value := range secret.Labels {
+		existing.Labels[key] = value
+	}
+	existing.Data = secret.Data
+	_, err = kubeClient.CoreV1().Secrets(secret.Namespace).Update(ctx, existing, metav1.UpdateOptions{})
+	if err != nil {
+		return fmt.Errorf("update argo cd cluster secret: %w", err)
+	}
+
+	return nil
+}
+
+// deleteArgoCDClusterSecrets removes the Argo CD cluster secrets of the virtual cluster from the Argo CD namespace
+func deleteArgoCDClusterSecrets(ctx context.Context, kubeClient kubernetes.Interface, vClusterName, vClusterNamespace, argoCDNamespace string) ([]string, error) {
+	secrets, err := kubeClient.CoreV1().Secrets(argoCDNamespace).List(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=cluster,%s=%s,%s=%s", ArgoCDSecretTypeLabel, ArgoCDVClusterNameLabel, vClusterName, ArgoCDVClusterNamespaceLabel, vClusterNamespace),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list argo cd cluster secrets: %w", err)
+	}
+
+	deleted := []string{}
+	for _, secret := range secrets.Items {
+		err = kubeClient.CoreV1().Secrets(secret.Namespace).Delete(ctx, secret.Name, metav1.DeleteOptions{})
+		if err != nil && !kerrors.IsNotFound(err) {
+			return deleted, fmt.Errorf("delete argo cd cluster secret %s/%s: %w", secret.Namespace, secret.Name, err)
+		}
+
+		deleted = append(deleted, secret.Namespace+"/"+secret.Name)
+	}
+
+	return deleted, nil
+}
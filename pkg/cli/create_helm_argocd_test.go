@@ -0,0 +1,58 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+func TestArgoCDClusterSecret(t *testing.T) {
+	ctx := context.Background()
+	kubeConfig := &clientcmdapi.Config{
+		Clusters:  map[string]*clientcmdapi.Cluster{"my-vcluster": {Server: "https://my-vcluster.team-a.svc:443", CertificateAuthorityData: []byte("ca")}},
+		AuthInfos: map[string]*clientcmdapi.AuthInfo{"my-vcluster": {Token: "token"}},
+	}
+
+	secret, err := argoCDClusterSecret("my-vcluster", "team-a", "argocd", kubeConfig)
+	assert.NilError(t, err)
+	assert.Equal(t, secret.Namespace, "argocd")
+	assert.Equal(t, secret.Labels[ArgoCDSecretTypeLabel], "cluster")
+	assert.Equal(t, string(secret.Data["server"]), "https://my-vcluster.team-a.svc:443")
+
+	clusterConfig := &argoCDClusterConfig{}
+	assert.NilError(t, json.Unmarshal(secret.Data["config"], clusterConfig))
+	assert.Equal(t, clusterConfig.BearerToken, "token")
+	assert.Equal(t, clusterConfig.TLSClientConfig.CAData, "Y2E=")
+
+	// registering twice updates the secret
+	kubeClient := fake.NewSimpleClientset()
+	assert.NilError(t, applyArgoCDClusterSecret(ctx, kubeClient, secret))
+	kubeConfig.AuthInfos["my-vcluster"].Token = "other-token"
+	secret, err = argoCDClusterSecret("my-vcluster", "team-a", "argocd", kubeConfig)
+	assert.NilError(t, err)
+	assert.NilError(t, applyArgoCDClusterSecret(ctx, kubeClient, secret))
+	current, err := kubeClient.CoreV1().Secrets("argocd").Get(ctx, secret.Name, metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Assert(t, json.Unmarshal(current.Data["config"], clusterConfig) == nil && clusterConfig.BearerToken == "other-token")
+
+	// secrets of other virtual clusters are kept
+	other, err := argoCDClusterSecret("my-vcluster", "team-b", "argocd", kubeConfig)
+	assert.NilError(t, err)
+	assert.NilError(t, applyArgoCDClusterSecret(ctx, kubeClient, other))
+	deleted, err := deleteArgoCDClusterSecrets(ctx, kubeClient, "my-vcluster", "team-a", "other-argocd")
+	assert.NilError(t, err)
+	assert.Equal(t, len(deleted), 0)
+	deleted, err = deleteArgoCDClusterSecrets(ctx, kubeClient, "my-vcluster", "team-a", "argocd")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, deleted, []string{"argocd/" + secret.Name})
+	_, err = kubeClient.CoreV1().Secrets("argocd").Get(ctx, other.Name, metav1.GetOptions{})
+	assert.NilError(t, err)
+
+	_, err = argoCDClusterSecret("my-vcluster", "team-a", "argocd", &clientcmdapi.Config{})
+	assert.ErrorContains(t, err, "no server or service account token")
+}
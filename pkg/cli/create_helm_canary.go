@@ -0,0 +1,130 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/loft-sh/vcluster/pkg/cli/find"
+	appsv1 "k8s.io/api/apps/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+)
+
+// startCanary holds back the rollout of all but the highest replica of the vcluster statefulset. It returns
+// the ordinal of the canary replica or -1 if a canary upgrade isn't possible for this vcluster.
+func (cmd *createHelm) startCanary(ctx context.Context, vClusterName string) (int32, error) {
+	statefulSet, err := cmd.kubeClient.AppsV1().StatefulSets(cmd.Namespace).Get(ctx, vClusterName, metav1.GetOptions{})
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			cmd.log.Warnf("Canary upgrades are only supported for vclusters deployed as statefulset, upgrading all replicas at once")
+			return -1, nil
+		}
+
+		return -1, fmt.Errorf("get vcluster statefulset: %w", err)
+	}
+
+	replicas := int32(1)
+	if statefulSet.Spec.Replicas != nil {
+		replicas = *statefulSet.Spec.Replicas
+	}
+	if replicas < 2 {
+		cmd.log.Warnf("Canary upgrades require at least 2 replicas (controlPlane.statefulSet.highAvailability.replicas), upgrading all replicas at once")
+		return -1, nil
+	}
+
+	canary := replicas - 1
+	err = setStatefulSetPartition(ctx, cmd.kubeClient, vClusterName, cmd.Namespace, canary)
+	if err != nil {
+		return -1, err
+	}
+
+	cmd.log.Infof("Upgrading canary replica %s-%d first", vClusterName, canary)
+	return canary, nil
+}
+
+// verifyCanary waits until the canary replica runs the new revision and makes sure it stays ready for the canary duration
+func (cmd *createHelm) verifyCanary(ctx context.Context, vClusterName string, canary int32) error {
+	podName := fmt.Sprintf("%s-%d", vClusterName, canary)
+	timeout := cmd.RollbackTimeout
+	if timeout <= 0 {
+		timeout = 5 * time.Minute
+	}
+
+	cmd.log.Infof("Waiting for canary replica %s to become ready...", podName)
+	var lastErr error
+	var restarts int32
+	err := wait.PollUntilContextTimeout(ctx, time.Second*2, timeout, true, func(ctx context.Context) (bool, error) {
+		restarts, lastErr = canaryReady(ctx, cmd.kubeClient, vClusterName, cmd.Namespace, podName)
+		return lastErr == nil, nil
+	})
+	if err != nil {
+		if lastErr == nil {
+			return err
+		}
+
+		return fmt.Errorf("canary replica %s is not ready after %s: %w", podName, timeout.String(), lastErr)
+	}
+
+	// the canary has to stay healthy, a crashing syncer would restart or turn unready in the meantime
+	cmd.log.Infof("Canary replica %s is ready, verifying it for %s...", podName, cmd.CanaryDuration.String())
+	verifyCtx, cancel := context.WithTimeout(ctx, cmd.CanaryDuration)
+	defer cancel()
+	err = wait.PollUntilContextCancel(verifyCtx, time.Second*5, false, func(ctx context.Context) (bool, error) {
+		currentRestarts, err := canaryReady(ctx, cmd.kubeClient, vClusterName, cmd.Namespace, podName)
+		if err != nil {
+			return false, err
+		} else if currentRestarts > restarts {
+			return false, fmt.Errorf("pod %s restarted during verification", podName)
+		}
+
+		return false, nil
+	})
+	if err != nil && !wait.Interrupted(err) {
+		return fmt.Errorf("canary replica %s failed verification: %w", podName, err)
+	} else if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	cmd.log.Donef("Canary replica %s is healthy, upgrading the remaining replicas", podName)
+	return nil
+}
+
+// canaryReady returns the restart count of the canary pod or an error if it isn't ready on the update revision
+func canaryReady(ctx context.Context, kubeClient kubernetes.Interface, vClusterName, namespace, podName string) (int32, error) {
+	statefulSet, err := kubeClient.AppsV1().StatefulSets(namespace).Get(ctx, vClusterName, metav1.GetOptions{})
+	if err != nil {
+		return 0, fmt.Errorf("get vcluster statefulset: %w", err)
+	} else if statefulSet.Status.ObservedGeneration < statefulSet.Generation {
+		return 0, fmt.Errorf("statefulset %s/%s was not observed yet", namespace, vClusterName)
+	}
+
+	pod, err := kubeClient.CoreV1().Pods(namespace).Get(ctx, podName, metav1.GetOptions{})
+	if err != nil {
+		return 0, fmt.Errorf("get pod %s: %w", podName, err)
+	} else if pod.Labels[appsv1.ControllerRevisionHashLabelKey] != statefulSet.Status.UpdateRevision {
+		return 0, fmt.Errorf("pod %s is not updated yet", podName)
+	} else if !isPodReady(pod) {
+		return 0, fmt.Errorf("pod %s is %s", podName, find.GetPodStatus(pod))
+	}
+
+	restarts := int32(0)
+	for _, containerStatus := range pod.Status.ContainerStatuses {
+		restarts += containerStatus.RestartCount
+	}
+
+	return restarts, nil
+}
+
+func setStatefulSetPartition(ctx context.Context, kubeClient kubernetes.Interface, vClusterName, namespace string, partition int32) error {
+	patch := fmt.Sprintf(`{"spec":{"updateStrategy":{"type":%q,"rollingUpdate":{"partition":%d}}}}`, appsv1.RollingUpdateStatefulSetStrategyType, partition)
+	_, err := kubeClient.AppsV1().StatefulSets(namespace).Patch(ctx, vClusterName, types.StrategicMergePatchType, []byte(patch), metav1.PatchOptions{})
+	if err != nil {
+		return fmt.Errorf("set partition of statefulset %s/%s: %w", namespace, vClusterName, err)
+	}
+
+	return nil
+}
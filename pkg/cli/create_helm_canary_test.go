@@ -0,0 +1,49 @@
+package cli
+
+import (
+	"context"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/ptr"
+)
+
+func TestCanaryReady(t *testing.T) {
+	statefulSet := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "vc", Namespace: "test", Generation: 3},
+		Spec:       appsv1.StatefulSetSpec{Replicas: ptr.To(int32(3))},
+		Status:     appsv1.StatefulSetStatus{ObservedGeneration: 3, CurrentRevision: "vc-1", UpdateRevision: "vc-2"},
+	}
+	newPod := func(revision string, ready corev1.ConditionStatus) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "vc-2", Namespace: "test", Labels: map[string]string{appsv1.ControllerRevisionHashLabelKey: revision}},
+			Status: corev1.PodStatus{
+				Phase:             corev1.PodRunning,
+				Conditions:        []corev1.PodCondition{{Type: corev1.PodReady, Status: ready}},
+				ContainerStatuses: []corev1.ContainerStatus{{Name: "syncer", RestartCount: 1}},
+			},
+		}
+	}
+
+	kubeClient := fake.NewSimpleClientset(statefulSet, newPod("vc-1", corev1.ConditionTrue))
+	_, err := canaryReady(context.Background(), kubeClient, "vc", "test", "vc-2")
+	assert.Error(t, err, "pod vc-2 is not updated yet")
+
+	kubeClient = fake.NewSimpleClientset(statefulSet, newPod("vc-2", corev1.ConditionFalse))
+	_, err = canaryReady(context.Background(), kubeClient, "vc", "test", "vc-2")
+	assert.Error(t, err, "pod vc-2 is Running")
+
+	kubeClient = fake.NewSimpleClientset(statefulSet, newPod("vc-2", corev1.ConditionTrue))
+	restarts, err := canaryReady(context.Background(), kubeClient, "vc", "test", "vc-2")
+	assert.NilError(t, err)
+	assert.Equal(t, restarts, int32(1))
+
+	assert.NilError(t, setStatefulSetPartition(context.Background(), kubeClient, "vc", "test", 2))
+	updated, err := kubeClient.AppsV1().StatefulSets("test").Get(context.Background(), "vc", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, *updated.Spec.UpdateStrategy.RollingUpdate.Partition, int32(2))
+}
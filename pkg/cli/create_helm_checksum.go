@@ -0,0 +1,105 @@
+package cli
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/loft-sh/vcluster/pkg/cli/cleanup"
+	"github.com/loft-sh/vcluster/pkg/constants"
+	"github.com/loft-sh/vcluster/pkg/helm"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+// valuesChecksum returns the checksum of the user supplied values of the helm release
+func valuesChecksum(release *helm.Release) (string, error) {
+	values := release.Config
+	if values == nil {
+		values = map[string]interface{}{}
+	}
+
+	// json marshals maps with sorted keys, so the checksum is stable
+	out, err := json.Marshal(values)
+	if err != nil {
+		return "", fmt.Errorf("marshal release values: %w", err)
+	}
+
+	hash := sha256.Sum256(out)
+	return hex.EncodeToString(hash[:]), nil
+}
+
+// checkValuesChecksum warns if the values of the deployed release were changed outside of the vcluster CLI. If the
+// release should be adopted, the current release values are used as base for the upgrade, so those changes are kept.
+// It returns the cleanup of the temporary values file or nil.
+func (cmd *createHelm) checkValuesChecksum(ctx context.Context, release *helm.Release, vClusterName, currentValues string) (*cleanup.Handle, error) {
+	secret, err := cmd.kubeClient.CoreV1().Secrets(cmd.Namespace).Get(ctx, "vc-config-"+vClusterName, metav1.GetOptions{})
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("get vcluster config secret: %w", err)
+	}
+
+	// the vcluster was deployed by an older CLI or directly via helm
+	deployedChecksum := secret.Annotations[constants.ValuesChecksumAnnotation]
+	if deployedChecksum == "" {
+		return nil, nil
+	}
+
+	checksum, err := valuesChecksum(release)
+	if err != nil {
+		return nil, err
+	} else if checksum == deployedChecksum {
+		return nil, nil
+	}
+
+	if !cmd.AdoptRelease {
+		cmd.log.Warnf("The values of helm release %s in namespace %s were changed outside of the vcluster CLI (e.g. by running helm directly) and will be replaced by this upgrade. Use --adopt-release to keep the current release values", vClusterName, cmd.Namespace)
+		return nil, nil
+	}
+
+	cmd.log.Infof("Adopting the current values of helm release %s", vClusterName)
+	cmd.recordAction(CreateActionAdoptRelease)
+	tempFile, tempFileCleanup, err := cleanup.TempFile("")
+	if err != nil {
+		return nil, fmt.Errorf("create temp values file: %w", err)
+	}
+	defer tempFile.Close()
+
+	_, err = tempFile.Write([]byte(currentValues))
+	if err != nil {
+		_ = tempFileCleanup.Run()
+		return nil, fmt.Errorf("write release values to temp values file: %w", err)
+	}
+
+	// the release values are the base, the passed values still take precedence
+	cmd.Values = append([]string{tempFile.Name()}, cmd.Values...)
+	return tempFileCleanup, nil
+}
+
+// storeValuesChecksum records the checksum of the deployed release values in the vcluster config secret
+func storeValuesChecksum(ctx context.Context, kubeClient kubernetes.Interface, vClusterName, namespace string) error {
+	release, err := helm.NewSecrets(kubeClient).Get(ctx, vClusterName, namespace)
+	if err != nil {
+		return fmt.Errorf("get helm release: %w", err)
+	}
+
+	checksum, err := valuesChecksum(release)
+	if err != nil {
+		return err
+	}
+
+	patch := fmt.Sprintf(`{"metadata":{"annotations":{%q:%q}}}`, constants.ValuesChecksumAnnotation, checksum)
+	_, err = kubeClient.CoreV1().Secrets(namespace).Patch(ctx, "vc-config-"+vClusterName, types.MergePatchType, []byte(patch), metav1.PatchOptions{})
+	if err != nil {
+		return fmt.Errorf("store values checksum: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,35 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/loft-sh/vcluster/pkg/helm"
+	"gotest.tools/v3/assert"
+)
+
+func TestValuesChecksum(t *testing.T) {
+	checksum, err := valuesChecksum(&helm.Release{Config: map[string]interface{}{
+		"sync":         map[string]interface{}{"toHost": map[string]interface{}{"ingresses": map[string]interface{}{"enabled": true}}},
+		"controlPlane": map[string]interface{}{"proxy": map[string]interface{}{"port": 8443}},
+	}})
+	assert.NilError(t, err)
+
+	sameChecksum, err := valuesChecksum(&helm.Release{Config: map[string]interface{}{
+		"controlPlane": map[string]interface{}{"proxy": map[string]interface{}{"port": 8443}},
+		"sync":         map[string]interface{}{"toHost": map[string]interface{}{"ingresses": map[string]interface{}{"enabled": true}}},
+	}})
+	assert.NilError(t, err)
+	assert.Equal(t, checksum, sameChecksum)
+
+	changedChecksum, err := valuesChecksum(&helm.Release{Config: map[string]interface{}{
+		"controlPlane": map[string]interface{}{"proxy": map[string]interface{}{"port": 8444}},
+	}})
+	assert.NilError(t, err)
+	assert.Assert(t, checksum != changedChecksum)
+
+	emptyChecksum, err := valuesChecksum(&helm.Release{})
+	assert.NilError(t, err)
+	otherEmptyChecksum, err := valuesChecksum(&helm.Release{Config: map[string]interface{}{}})
+	assert.NilError(t, err)
+	assert.Equal(t, emptyChecksum, otherEmptyChecksum)
+}
@@ -0,0 +1,116 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/loft-sh/vcluster/config"
+	"github.com/loft-sh/vcluster/pkg/cli/cleanup"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	fluxChartRepo   = "https://fluxcd-community.github.io/helm-charts"
+	fluxNamespace   = "flux-system"
+	fluxRelease     = "flux2"
+	fluxSyncRelease = "flux2-sync"
+)
+
+// addFluxBootstrap deploys flux into the virtual cluster through experimental.deploy.vcluster.helm, so the syncer
+// installs it as soon as the virtual cluster is up and flux starts reconciling the given repository. The charts are
+// appended to the configured ones as values file with the highest precedence, as helm replaces lists while merging.
+func (cmd *createHelm) addFluxBootstrap(vClusterConfig *config.Config) (*cleanup.Handle, error) {
+	charts := []config.ExperimentalDeployHelm{}
+	for _, chart := range vClusterConfig.Experimental.Deploy.VCluster.Helm {
+		if chart.Release.Namespace == fluxNamespace && (chart.Release.Name == fluxRelease || chart.Release.Name == fluxSyncRelease) {
+			continue
+		}
+
+		charts = append(charts, chart)
+	}
+
+	fluxCharts, err := fluxBootstrapCharts(cmd.FluxBootstrap, cmd.FluxBranch, cmd.FluxPath)
+	if err != nil {
+		return nil, err
+	}
+	charts = append(charts, fluxCharts...)
+	vClusterConfig.Experimental.Deploy.VCluster.Helm = charts
+
+	values, err := yaml.Marshal(map[string]interface{}{
+		"experimental": map[string]interface{}{
+			"deploy": map[string]interface{}{
+				"vcluster": map[string]interface{}{
+					"helm": charts,
+				},
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	tempFile, tempFileCleanup, err := cleanup.TempFile("")
+	if err != nil {
+		return nil, fmt.Errorf("create temp values file: %w", err)
+	}
+	defer tempFile.Close()
+
+	_, err = tempFile.Write(values)
+	if err != nil {
+		_ = tempFileCleanup.Run()
+		return nil, fmt.Errorf("write flux values to temp values file: %w", err)
+	}
+
+	cmd.log.Infof("Bootstrapping flux with repository %s", cmd.FluxBootstrap)
+	cmd.Values = append(cmd.Values, tempFile.Name())
+	return tempFileCleanup, nil
+}
+
+// fluxBootstrapCharts returns the flux2 chart and the flux2-sync chart which creates the GitRepository and the
+// Kustomization that reconciles the path of the repository
+func fluxBootstrapCharts(gitURL, branch, path string) ([]config.ExperimentalDeployHelm, error) {
+	syncValues, err := yaml.Marshal(map[string]interface{}{
+		"gitRepository": map[string]interface{}{
+			"spec": map[string]interface{}{
+				"url":      gitURL,
+				"interval": "1m",
+				"ref": map[string]interface{}{
+					"branch": branch,
+				},
+			},
+		},
+		"kustomization": map[string]interface{}{
+			"spec": map[string]interface{}{
+				"path":     path,
+				"prune":    true,
+				"interval": "10m",
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return []config.ExperimentalDeployHelm{
+		{
+			Chart: config.ExperimentalDeployHelmChart{
+				Name: "flux2",
+				Repo: fluxChartRepo,
+			},
+			Release: config.ExperimentalDeployHelmRelease{
+				Name:      fluxRelease,
+				Namespace: fluxNamespace,
+			},
+		},
+		{
+			Chart: config.ExperimentalDeployHelmChart{
+				Name: "flux2-sync",
+				Repo: fluxChartRepo,
+			},
+			Release: config.ExperimentalDeployHelmRelease{
+				Name:      fluxSyncRelease,
+				Namespace: fluxNamespace,
+			},
+			Values: string(syncValues),
+		},
+	}, nil
+}
@@ -0,0 +1,56 @@
+package cli
+
+import (
+	"os"
+	"testing"
+
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/vcluster/config"
+	"gotest.tools/v3/assert"
+	"sigs.k8s.io/yaml"
+)
+
+func TestAddFluxBootstrap(t *testing.T) {
+	cmd := &createHelm{
+		CreateOptions: &CreateOptions{
+			FluxBootstrap: "https://github.com/my-org/fleet",
+			FluxBranch:    "main",
+			FluxPath:      "./clusters/test",
+		},
+		log: log.Discard,
+	}
+
+	// the configured charts are kept, flux charts of a previous bootstrap are replaced
+	vClusterConfig := &config.Config{}
+	vClusterConfig.Experimental.Deploy.VCluster.Helm = []config.ExperimentalDeployHelm{
+		{Chart: config.ExperimentalDeployHelmChart{Name: "my-chart"}},
+		{Chart: config.ExperimentalDeployHelmChart{Name: "flux2"}, Release: config.ExperimentalDeployHelmRelease{Name: fluxRelease, Namespace: fluxNamespace}},
+	}
+	fluxValuesCleanup, err := cmd.addFluxBootstrap(vClusterConfig)
+	assert.NilError(t, err)
+	defer func() {
+		_ = fluxValuesCleanup.Run()
+	}()
+
+	assert.Equal(t, len(cmd.Values), 1)
+	raw, err := os.ReadFile(cmd.Values[0])
+	assert.NilError(t, err)
+	fluxConfig := &config.Config{}
+	assert.NilError(t, yaml.Unmarshal(raw, fluxConfig))
+	assert.DeepEqual(t, fluxConfig.Experimental.Deploy.VCluster.Helm, vClusterConfig.Experimental.Deploy.VCluster.Helm)
+
+	charts := fluxConfig.Experimental.Deploy.VCluster.Helm
+	assert.Equal(t, len(charts), 3)
+	assert.Equal(t, charts[0].Chart.Name, "my-chart")
+	assert.Equal(t, charts[1].Release.Name, fluxRelease)
+	assert.Equal(t, charts[2].Release.Name, fluxSyncRelease)
+
+	syncValues := map[string]interface{}{}
+	assert.NilError(t, yaml.Unmarshal([]byte(charts[2].Values), &syncValues))
+	assert.DeepEqual(t, syncValues["gitRepository"], map[string]interface{}{"spec": map[string]interface{}{
+		"url":      "https://github.com/my-org/fleet",
+		"interval": "1m",
+		"ref":      map[string]interface{}{"branch": "main"},
+	}})
+	assert.Equal(t, syncValues["kustomization"].(map[string]interface{})["spec"].(map[string]interface{})["path"], "./clusters/test")
+}
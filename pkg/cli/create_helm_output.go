@@ -0,0 +1,100 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/loft-sh/vcluster/pkg/helm"
+	"github.com/sirupsen/logrus"
+)
+
+// The actions listed in the json output of create
+const (
+	CreateActionCreateNamespace = "CreateNamespace"
+	CreateActionAddPlatform     = "AddPlatform"
+	CreateActionAdoptRelease    = "AdoptRelease"
+	CreateActionInstall         = "Install"
+	CreateActionUpgrade         = "Upgrade"
+	CreateActionCanary          = "Canary"
+	CreateActionRegisterArgoCD  = "RegisterArgoCD"
+)
+
+// CreateOutput is the machine-readable summary create prints with --output json, so tools wrapping the CLI such as
+// Terraform or Pulumi providers can detect changes without parsing the log lines.
+type CreateOutput struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+
+	Chart        string `json:"chart"`
+	ChartVersion string `json:"chartVersion"`
+	Revision     int    `json:"revision"`
+
+	// ValuesHash is the checksum of the values the release was deployed with
+	ValuesHash string `json:"valuesHash"`
+
+	// Changed is true if the chart version or the values differ from the previously deployed release
+	Changed bool `json:"changed"`
+
+	// Actions are the actions that were performed, e.g. Install or Upgrade
+	Actions []string `json:"actions"`
+}
+
+func (cmd *createHelm) recordAction(action string) {
+	cmd.actions = append(cmd.actions, action)
+}
+
+// printOutput prints the json summary of the deployed release
+func (cmd *createHelm) printOutput(ctx context.Context, vClusterName string, previous *helm.Release) error {
+	current, err := helm.NewSecrets(cmd.kubeClient).Get(ctx, vClusterName, cmd.Namespace)
+	if err != nil {
+		return fmt.Errorf("get deployed helm release: %w", err)
+	}
+
+	output, err := newCreateOutput(previous, current, cmd.actions)
+	if err != nil {
+		return err
+	}
+	output.Name = vClusterName
+	output.Namespace = cmd.Namespace
+
+	out, err := json.MarshalIndent(output, "", "    ")
+	if err != nil {
+		return fmt.Errorf("json marshal create output: %w", err)
+	}
+
+	cmd.outputLog.WriteString(logrus.InfoLevel, string(out)+"\n")
+	return nil
+}
+
+func newCreateOutput(previous, current *helm.Release, actions []string) (*CreateOutput, error) {
+	valuesHash, err := valuesChecksum(current)
+	if err != nil {
+		return nil, err
+	}
+
+	output := &CreateOutput{
+		Revision:   current.Version,
+		ValuesHash: valuesHash,
+		Changed:    true,
+		Actions:    actions,
+	}
+	if output.Actions == nil {
+		output.Actions = []string{}
+	}
+	if current.Chart != nil && current.Chart.Metadata != nil {
+		output.Chart = current.Chart.Metadata.Name
+		output.ChartVersion = current.Chart.Metadata.Version
+	}
+
+	if isVClusterDeployed(previous) {
+		previousHash, err := valuesChecksum(previous)
+		if err != nil {
+			return nil, err
+		}
+
+		output.Changed = previousHash != valuesHash || previous.Chart.Metadata.Version != output.ChartVersion
+	}
+
+	return output, nil
+}
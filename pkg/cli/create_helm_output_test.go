@@ -0,0 +1,49 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/loft-sh/vcluster/pkg/helm"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newTestRelease(version string, revision int, values map[string]interface{}) *helm.Release {
+	return &helm.Release{
+		Chart:   &helm.Chart{Metadata: &helm.Metadata{Name: "vcluster", Version: version}},
+		Config:  values,
+		Version: revision,
+		Secret:  &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"status": "deployed"}}},
+	}
+}
+
+func TestNewCreateOutput(t *testing.T) {
+	values := map[string]interface{}{"sync": map[string]interface{}{"toHost": map[string]interface{}{"ingresses": map[string]interface{}{"enabled": true}}}}
+
+	// fresh install
+	output, err := newCreateOutput(nil, newTestRelease("0.21.0", 1, values), nil)
+	assert.NilError(t, err)
+	assert.Equal(t, output.Chart, "vcluster")
+	assert.Equal(t, output.ChartVersion, "0.21.0")
+	assert.Equal(t, output.Revision, 1)
+	assert.Assert(t, output.Changed)
+	assert.DeepEqual(t, output.Actions, []string{})
+
+	// upgrade without changes
+	unchanged, err := newCreateOutput(newTestRelease("0.21.0", 1, values), newTestRelease("0.21.0", 2, values), []string{CreateActionUpgrade})
+	assert.NilError(t, err)
+	assert.Assert(t, !unchanged.Changed)
+	assert.Equal(t, unchanged.ValuesHash, output.ValuesHash)
+
+	// upgrade to another chart version
+	upgraded, err := newCreateOutput(newTestRelease("0.21.0", 1, values), newTestRelease("0.21.1", 2, values), []string{CreateActionUpgrade})
+	assert.NilError(t, err)
+	assert.Assert(t, upgraded.Changed)
+
+	// upgrade with other values
+	changed, err := newCreateOutput(newTestRelease("0.21.0", 1, values), newTestRelease("0.21.0", 2, nil), []string{CreateActionUpgrade})
+	assert.NilError(t, err)
+	assert.Assert(t, changed.Changed)
+	assert.Assert(t, changed.ValuesHash != output.ValuesHash)
+}
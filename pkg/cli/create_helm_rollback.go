@@ -0,0 +1,209 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/vcluster/pkg/cli/find"
+	"github.com/loft-sh/vcluster/pkg/helm"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+)
+
+// upgradeSnapshot holds the state of a deployed virtual cluster before it was upgraded
+type upgradeSnapshot struct {
+	revision     int
+	configSecret *corev1.Secret
+}
+
+// snapshotRelease records the current revision and the vcluster config secret, so a failed upgrade can be reverted
+func snapshotRelease(ctx context.Context, kubeClient kubernetes.Interface, release *helm.Release, vClusterName, namespace string) (*upgradeSnapshot, error) {
+	snapshot := &upgradeSnapshot{
+		revision: release.Version,
+	}
+
+	secret, err := kubeClient.CoreV1().Secrets(namespace).Get(ctx, "vc-config-"+vClusterName, metav1.GetOptions{})
+	if err != nil && !kerrors.IsNotFound(err) {
+		return nil, fmt.Errorf("get vcluster config secret: %w", err)
+	} else if err == nil {
+		snapshot.configSecret = secret
+	}
+
+	return snapshot, nil
+}
+
+// rollbackUpgrade rolls the release back to the recorded revision and restores the previous vcluster config secret. If
+// a canary upgrade failed, the partition still holds back the other replicas during the rollback and is removed
+// afterwards, as all replicas run the previous revision again.
+func (cmd *createHelm) rollbackUpgrade(ctx context.Context, helmClient helm.Client, snapshot *upgradeSnapshot, vClusterName string, canary int32, cause error) error {
+	cmd.log.Errorf("Upgrade of vcluster %s failed: %v", vClusterName, cause)
+	cmd.log.Infof("Rolling back vcluster %s to revision %d...", vClusterName, snapshot.revision)
+	err := helmClient.Rollback(ctx, vClusterName, cmd.Namespace, snapshot.revision)
+	if err != nil {
+		return fmt.Errorf("upgrade failed: %w, rollback to revision %d failed as well: %w", cause, snapshot.revision, err)
+	}
+
+	err = restoreConfigSecret(ctx, cmd.kubeClient, snapshot.configSecret)
+	if err != nil {
+		return fmt.Errorf("upgrade failed: %w, restore vcluster config secret: %w", cause, err)
+	}
+
+	if canary >= 0 {
+		err = setStatefulSetPartition(ctx, cmd.kubeClient, vClusterName, cmd.Namespace, 0)
+		if err != nil {
+			return fmt.Errorf("upgrade failed: %w, remove canary partition after rollback: %w", cause, err)
+		}
+	}
+
+	return fmt.Errorf("upgrade failed and vcluster %s was rolled back to revision %d: %w", vClusterName, snapshot.revision, cause)
+}
+
+func restoreConfigSecret(ctx context.Context, kubeClient kubernetes.Interface, previous *corev1.Secret) error {
+	if previous == nil {
+		return nil
+	}
+
+	secret, err := kubeClient.CoreV1().Secrets(previous.Namespace).Get(ctx, previous.Name, metav1.GetOptions{})
+	if err != nil {
+		if !kerrors.IsNotFound(err) {
+			return err
+		}
+
+		secret = previous.DeepCopy()
+		secret.ResourceVersion = ""
+		secret.UID = ""
+		_, err = kubeClient.CoreV1().Secrets(previous.Namespace).Create(ctx, secret, metav1.CreateOptions{})
+		return err
+	}
+
+	secret.Data = previous.Data
+	_, err = kubeClient.CoreV1().Secrets(previous.Namespace).Update(ctx, secret, metav1.UpdateOptions{})
+	return err
+}
+
+// waitForVClusterReady waits until the vcluster workload was rolled out completely and all of its pods are ready.
+// Headless releases only deploy the workload part of an isolated control plane without a statefulset, so there is
+// nothing to wait for.
+func waitForVClusterReady(ctx context.Context, kubeClient kubernetes.Interface, vClusterName, namespace string, timeout time.Duration, log log.Logger) error {
+	release, err := helm.NewSecrets(kubeClient).Get(ctx, vClusterName, namespace)
+	if err != nil && !kerrors.IsNotFound(err) {
+		return fmt.Errorf("get helm release: %w", err)
+	} else if err == nil && isHeadlessRelease(release) {
+		log.Infof("Skip waiting for vcluster %s, because it is deployed headless and has no control plane in this cluster", vClusterName)
+		return nil
+	}
+
+	log.Infof("Waiting for vcluster %s to become ready...", vClusterName)
+	var lastErr error
+	err = wait.PollUntilContextTimeout(ctx, time.Second*2, timeout, true, func(ctx context.Context) (bool, error) {
+		lastErr = vClusterRolledOut(ctx, kubeClient, vClusterName, namespace)
+		return lastErr == nil, nil
+	})
+	if err != nil {
+		if lastErr == nil {
+			return err
+		}
+
+		return fmt.Errorf("vcluster %s is not ready after %s: %w", vClusterName, timeout.String(), lastErr)
+	}
+
+	return nil
+}
+
+// isHeadlessRelease returns true if the release was deployed with experimental.isolatedControlPlane.headless
+func isHeadlessRelease(release *helm.Release) bool {
+	experimental, _ := release.Config["experimental"].(map[string]interface{})
+	isolatedControlPlane, _ := experimental["isolatedControlPlane"].(map[string]interface{})
+	headless, _ := isolatedControlPlane["headless"].(bool)
+	return headless
+}
+
+// vClusterRolledOut returns an error describing why the vcluster statefulset or deployment is not ready yet
+func vClusterRolledOut(ctx context.Context, kubeClient kubernetes.Interface, vClusterName, namespace string) error {
+	statefulSet, err := kubeClient.AppsV1().StatefulSets(namespace).Get(ctx, vClusterName, metav1.GetOptions{})
+	if err == nil {
+		if !statefulSetRolledOut(statefulSet) {
+			return notReadyPods(ctx, kubeClient, vClusterName, namespace)
+		}
+
+		return nil
+	} else if !kerrors.IsNotFound(err) {
+		return err
+	}
+
+	deployment, err := kubeClient.AppsV1().Deployments(namespace).Get(ctx, vClusterName, metav1.GetOptions{})
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return fmt.Errorf("neither statefulset nor deployment %s/%s was found", namespace, vClusterName)
+		}
+
+		return err
+	}
+	if !deploymentRolledOut(deployment) {
+		return notReadyPods(ctx, kubeClient, vClusterName, namespace)
+	}
+
+	return nil
+}
+
+func statefulSetRolledOut(statefulSet *appsv1.StatefulSet) bool {
+	replicas := int32(1)
+	if statefulSet.Spec.Replicas != nil {
+		replicas = *statefulSet.Spec.Replicas
+	}
+
+	return statefulSet.Status.ObservedGeneration >= statefulSet.Generation &&
+		statefulSet.Status.UpdatedReplicas == replicas &&
+		statefulSet.Status.ReadyReplicas == replicas &&
+		(statefulSet.Status.UpdateRevision == "" || statefulSet.Status.UpdateRevision == statefulSet.Status.CurrentRevision)
+}
+
+func deploymentRolledOut(deployment *appsv1.Deployment) bool {
+	replicas := int32(1)
+	if deployment.Spec.Replicas != nil {
+		replicas = *deployment.Spec.Replicas
+	}
+
+	return deployment.Status.ObservedGeneration >= deployment.Generation &&
+		deployment.Status.UpdatedReplicas == replicas &&
+		deployment.Status.AvailableReplicas == replicas &&
+		deployment.Status.Replicas == replicas
+}
+
+// notReadyPods collects the status of all vcluster pods that are not ready
+func notReadyPods(ctx context.Context, kubeClient kubernetes.Interface, vClusterName, namespace string) error {
+	pods, err := kubeClient.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: "app=vcluster,release=" + vClusterName})
+	if err != nil {
+		return fmt.Errorf("list vcluster pods: %w", err)
+	}
+
+	messages := []string{}
+	for i := range pods.Items {
+		if !isPodReady(&pods.Items[i]) {
+			messages = append(messages, fmt.Sprintf("pod %s is %s", pods.Items[i].Name, find.GetPodStatus(&pods.Items[i])))
+		}
+	}
+	if len(messages) == 0 {
+		return errors.New("rollout is still in progress")
+	}
+
+	return errors.New(strings.Join(messages, ", "))
+}
+
+func isPodReady(pod *corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+
+	return false
+}
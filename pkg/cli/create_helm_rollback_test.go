@@ -0,0 +1,135 @@
+package cli
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/vcluster/pkg/helm"
+	"gotest.tools/v3/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/ptr"
+)
+
+func TestVClusterRolledOut(t *testing.T) {
+	newStatefulSet := func(readyReplicas int32) *appsv1.StatefulSet {
+		return &appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "vc", Namespace: "test", Generation: 2},
+			Spec:       appsv1.StatefulSetSpec{Replicas: ptr.To(int32(1))},
+			Status: appsv1.StatefulSetStatus{
+				ObservedGeneration: 2,
+				UpdatedReplicas:    1,
+				ReadyReplicas:      readyReplicas,
+				CurrentRevision:    "vc-2",
+				UpdateRevision:     "vc-2",
+			},
+		}
+	}
+	crashingPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "vc-0", Namespace: "test", Labels: map[string]string{"app": "vcluster", "release": "vc"}},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+			ContainerStatuses: []corev1.ContainerStatus{{
+				Name:  "syncer",
+				State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+			}},
+		},
+	}
+
+	testCases := []struct {
+		name    string
+		objects []runtime.Object
+		err     string
+	}{
+		{
+			name:    "ready",
+			objects: []runtime.Object{newStatefulSet(1)},
+		},
+		{
+			name:    "crashing pod",
+			objects: []runtime.Object{newStatefulSet(0), crashingPod},
+			err:     "pod vc-0 is CrashLoopBackOff",
+		},
+		{
+			name:    "rollout in progress",
+			objects: []runtime.Object{newStatefulSet(0)},
+			err:     "rollout is still in progress",
+		},
+		{
+			name: "deployment ready",
+			objects: []runtime.Object{&appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Name: "vc", Namespace: "test"},
+				Status:     appsv1.DeploymentStatus{Replicas: 1, UpdatedReplicas: 1, AvailableReplicas: 1},
+			}},
+		},
+		{
+			name: "missing",
+			err:  "neither statefulset nor deployment test/vc was found",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			kubeClient := fake.NewSimpleClientset(testCase.objects...)
+			err := vClusterRolledOut(context.Background(), kubeClient, "vc", "test")
+			if testCase.err == "" {
+				assert.NilError(t, err)
+			} else {
+				assert.Error(t, err, testCase.err)
+			}
+		})
+	}
+}
+
+func TestWaitForVClusterReadyHeadless(t *testing.T) {
+	newReleaseSecret := func(values map[string]interface{}) *corev1.Secret {
+		rawRelease, err := json.Marshal(&helm.Release{
+			Name:      "vc",
+			Namespace: "test",
+			Version:   2,
+			Info:      &helm.Info{Status: "deployed"},
+			Chart:     &helm.Chart{Metadata: &helm.Metadata{Version: "0.21.0"}},
+			Config:    values,
+		})
+		assert.NilError(t, err)
+
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "sh.helm.release.v1.vc.v2", Namespace: "test", Labels: map[string]string{"name": "vc", "owner": "helm"}},
+			Data:       map[string][]byte{"release": []byte(base64.StdEncoding.EncodeToString(rawRelease))},
+		}
+	}
+
+	// headless releases have no statefulset, so there is nothing to wait for
+	kubeClient := fake.NewSimpleClientset(newReleaseSecret(map[string]interface{}{
+		"experimental": map[string]interface{}{"isolatedControlPlane": map[string]interface{}{"enabled": true, "headless": true}},
+	}))
+	assert.NilError(t, waitForVClusterReady(context.Background(), kubeClient, "vc", "test", time.Second, log.Discard))
+
+	// other releases without statefulset are not ready
+	kubeClient = fake.NewSimpleClientset(newReleaseSecret(nil))
+	err := waitForVClusterReady(context.Background(), kubeClient, "vc", "test", time.Second, log.Discard)
+	assert.ErrorContains(t, err, "neither statefulset nor deployment test/vc was found")
+}
+
+func TestRestoreConfigSecret(t *testing.T) {
+	previous := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "vc-config-vc", Namespace: "test"},
+		Data:       map[string][]byte{"config.yaml": []byte("old")},
+	}
+	kubeClient := fake.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "vc-config-vc", Namespace: "test"},
+		Data:       map[string][]byte{"config.yaml": []byte("new")},
+	})
+
+	assert.NilError(t, restoreConfigSecret(context.Background(), kubeClient, previous))
+	secret, err := kubeClient.CoreV1().Secrets("test").Get(context.Background(), "vc-config-vc", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, string(secret.Data["config.yaml"]), "old")
+}
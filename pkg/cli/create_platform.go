@@ -14,6 +14,7 @@ import (
 	storagev1 "github.com/loft-sh/api/v4/pkg/apis/storage/v1"
 	"github.com/loft-sh/log"
 	vclusterconfig "github.com/loft-sh/vcluster/config"
+	"github.com/loft-sh/vcluster/pkg/cli/config"
 	"github.com/loft-sh/vcluster/pkg/cli/flags"
 	"github.com/loft-sh/vcluster/pkg/constants"
 	"github.com/loft-sh/vcluster/pkg/kube"
@@ -34,6 +35,24 @@ import (
 )
 
 func CreatePlatform(ctx context.Context, options *CreateOptions, globalFlags *flags.GlobalFlags, virtualClusterName string, log log.Logger) error {
+	values, cleanupValues, err := resolveValuesFiles(ctx, options.Values, options.ExpandEnv)
+	if err != nil {
+		return err
+	}
+	defer cleanupValues()
+	options.Values = values
+
+	// presets are layered under the values of the user
+	values, presetCleanup, err := presetValuesFiles(options.Presets, options.Values)
+	if err != nil {
+		return err
+	} else if presetCleanup != nil {
+		defer func() {
+			_ = presetCleanup.Run()
+		}()
+	}
+	options.Values = values
+
 	cfg := globalFlags.LoadedConfig(log)
 	platformClient, err := platform.InitClientFromConfig(ctx, cfg)
 	if err != nil {
@@ -46,6 +65,11 @@ func CreatePlatform(ctx context.Context, options *CreateOptions, globalFlags *fl
 		return err
 	}
 
+	// the template is rendered without creating the virtual cluster
+	if options.Preview {
+		return previewWithTemplate(ctx, platformClient, options, virtualClusterName, globalFlags.Namespace, os.Stdout, log)
+	}
+
 	virtualClusterNamespace := projectutil.ProjectNamespace((options.Project))
 	managementClient, err := platformClient.Management()
 	if err != nil {
@@ -157,6 +181,12 @@ func CreatePlatform(ctx context.Context, options *CreateOptions, globalFlags *fl
 		if err != nil {
 			return err
 		}
+		registerContext(cfg, contextOptions.Name, config.KubeContext{
+			Driver:  config.PlatformDriver,
+			Name:    virtualClusterName,
+			Project: options.Project,
+			Host:    cfg.Platform.Host,
+		}, log)
 
 		log.Donef("Successfully updated kube context to use virtual cluster %s in project %s", ansi.Color(virtualClusterName, "white+b"), ansi.Color(options.Project, "white+b"))
 	}
@@ -401,11 +431,35 @@ func shouldCreateWithTemplate(ctx context.Context, platformClient platform.Clien
 }
 
 func createWithTemplate(ctx context.Context, platformClient platform.Client, options *CreateOptions, virtualClusterName string, targetNamespace string, log log.Logger) (*managementv1.VirtualClusterInstance, error) {
-	err := validateTemplateOptions(options)
+	virtualClusterInstance, virtualClusterTemplate, err := newTemplateVirtualClusterInstance(ctx, platformClient, options, virtualClusterName, targetNamespace, log)
+	if err != nil {
+		return nil, err
+	}
+
+	// get management client
+	managementClient, err := platformClient.Management()
 	if err != nil {
 		return nil, err
 	}
 
+	// create virtual cluster instance
+	log.Infof("Creating virtual cluster %s in project %s with template %s...", virtualClusterName, options.Project, virtualClusterTemplate.Name)
+	virtualClusterInstance, err = managementClient.Loft().ManagementV1().VirtualClusterInstances(virtualClusterInstance.Namespace).Create(ctx, virtualClusterInstance, metav1.CreateOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("create virtual cluster: %w", err)
+	}
+
+	return virtualClusterInstance, nil
+}
+
+// newTemplateVirtualClusterInstance resolves the template and its parameters and returns the virtual cluster instance
+// that would be created with it
+func newTemplateVirtualClusterInstance(ctx context.Context, platformClient platform.Client, options *CreateOptions, virtualClusterName string, targetNamespace string, log log.Logger) (*managementv1.VirtualClusterInstance, *managementv1.VirtualClusterTemplate, error) {
+	err := validateTemplateOptions(options)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	// resolve template
 	virtualClusterTemplate, resolvedParameters, err := platform.ResolveVirtualClusterTemplate(
 		ctx,
@@ -418,7 +472,7 @@ func createWithTemplate(ctx context.Context, platformClient platform.Client, opt
 		log,
 	)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	// create virtual cluster instance
@@ -460,29 +514,16 @@ func createWithTemplate(ctx context.Context, platformClient platform.Client, opt
 	// set labels
 	_, err = kube.UpdateLabels(virtualClusterInstance, options.Labels)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	// set annotations
 	_, err = kube.UpdateAnnotations(virtualClusterInstance, options.Annotations)
 	if err != nil {
-		return nil, err
-	}
-
-	// get management client
-	managementClient, err := platformClient.Management()
-	if err != nil {
-		return nil, err
-	}
-
-	// create virtual cluster instance
-	log.Infof("Creating virtual cluster %s in project %s with template %s...", virtualClusterName, options.Project, virtualClusterTemplate.Name)
-	virtualClusterInstance, err = managementClient.Loft().ManagementV1().VirtualClusterInstances(virtualClusterInstance.Namespace).Create(ctx, virtualClusterInstance, metav1.CreateOptions{})
-	if err != nil {
-		return nil, fmt.Errorf("create virtual cluster: %w", err)
+		return nil, nil, err
 	}
 
-	return virtualClusterInstance, nil
+	return virtualClusterInstance, virtualClusterTemplate, nil
 }
 
 func upgradeWithTemplate(ctx context.Context, platformClient platform.Client, options *CreateOptions, virtualClusterInstance *managementv1.VirtualClusterInstance, log log.Logger) (*managementv1.VirtualClusterInstance, error) {
@@ -0,0 +1,114 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+
+	"github.com/ghodss/yaml"
+	managementv1 "github.com/loft-sh/api/v4/pkg/apis/management/v1"
+	storagev1 "github.com/loft-sh/api/v4/pkg/apis/storage/v1"
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/vcluster/pkg/platform"
+)
+
+// previewWithTemplate prints the vcluster.yaml and the virtual cluster instance that would be created with the
+// template and its parameters, without creating the virtual cluster
+func previewWithTemplate(ctx context.Context, platformClient platform.Client, options *CreateOptions, virtualClusterName, targetNamespace string, out io.Writer, log log.Logger) error {
+	if options.Template == "" {
+		return errors.New("--preview requires --template")
+	}
+
+	virtualClusterInstance, virtualClusterTemplate, err := newTemplateVirtualClusterInstance(ctx, platformClient, options, virtualClusterName, targetNamespace, log)
+	if err != nil {
+		return err
+	}
+	definition, err := templateDefinition(virtualClusterTemplate, options.TemplateVersion)
+	if err != nil {
+		return err
+	}
+
+	values, err := renderTemplateValues(definition.HelmRelease.Values, options.Project, virtualClusterInstance)
+	if err != nil {
+		// the platform has more template functions and variables than the preview, so its raw values are shown instead
+		log.Warnf("Couldn't render the values of template %s, showing them unrendered: %v", virtualClusterTemplate.Name, err)
+		values = definition.HelmRelease.Values
+	}
+
+	return printPreview(out, values, virtualClusterInstance)
+}
+
+// templateDefinition returns the definition of the template version that matches the version pattern, or of the
+// latest version if no pattern is given
+func templateDefinition(virtualClusterTemplate *managementv1.VirtualClusterTemplate, templateVersion string) (*storagev1.VirtualClusterTemplateDefinition, error) {
+	if len(virtualClusterTemplate.Spec.Versions) == 0 {
+		return &virtualClusterTemplate.Spec.Template, nil
+	}
+
+	if templateVersion == "" {
+		latestVersion := platform.GetLatestVersion(virtualClusterTemplate)
+		if latestVersion == nil {
+			return nil, fmt.Errorf("couldn't find any version in template")
+		}
+
+		return &latestVersion.(*storagev1.VirtualClusterTemplateVersion).Template, nil
+	}
+
+	_, latestMatched, err := platform.GetLatestMatchedVersion(virtualClusterTemplate, templateVersion)
+	if err != nil {
+		return nil, err
+	} else if latestMatched == nil {
+		return nil, fmt.Errorf("couldn't find any matching version to %s", templateVersion)
+	}
+
+	return &latestMatched.(*storagev1.VirtualClusterTemplateVersion).Template, nil
+}
+
+// renderTemplateValues renders the values of the template with the resolved parameters as .Values and the
+// virtual cluster instance as .Loft, like the platform does when it creates the virtual cluster
+func renderTemplateValues(rawValues, project string, virtualClusterInstance *managementv1.VirtualClusterInstance) (string, error) {
+	parameters := map[string]interface{}{}
+	err := yaml.Unmarshal([]byte(virtualClusterInstance.Spec.Parameters), &parameters)
+	if err != nil {
+		return "", fmt.Errorf("parse parameters: %w", err)
+	}
+
+	valuesTemplate, err := template.New("vcluster.yaml").Option("missingkey=error").Parse(rawValues)
+	if err != nil {
+		return "", err
+	}
+
+	buffer := &bytes.Buffer{}
+	err = valuesTemplate.Execute(buffer, map[string]interface{}{
+		"Values": parameters,
+		"Loft": map[string]interface{}{
+			"Name":             virtualClusterInstance.Name,
+			"Namespace":        virtualClusterInstance.Namespace,
+			"Project":          project,
+			"Cluster":          virtualClusterInstance.Spec.ClusterRef.Cluster,
+			"ClusterNamespace": virtualClusterInstance.Spec.ClusterRef.Namespace,
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return buffer.String(), nil
+}
+
+func printPreview(out io.Writer, values string, virtualClusterInstance *managementv1.VirtualClusterInstance) error {
+	virtualClusterInstance = virtualClusterInstance.DeepCopy()
+	virtualClusterInstance.APIVersion = managementv1.SchemeGroupVersion.String()
+	virtualClusterInstance.Kind = "VirtualClusterInstance"
+	instance, err := yaml.Marshal(virtualClusterInstance)
+	if err != nil {
+		return fmt.Errorf("marshal virtual cluster instance: %w", err)
+	}
+
+	_, err = fmt.Fprintf(out, "# vcluster.yaml\n%s\n---\n# VirtualClusterInstance\n%s", strings.TrimSpace(values), instance)
+	return err
+}
@@ -0,0 +1,83 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	managementv1 "github.com/loft-sh/api/v4/pkg/apis/management/v1"
+	storagev1 "github.com/loft-sh/api/v4/pkg/apis/storage/v1"
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newPreviewInstance(parameters string) *managementv1.VirtualClusterInstance {
+	return &managementv1.VirtualClusterInstance{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "p-default"},
+		Spec: managementv1.VirtualClusterInstanceSpec{
+			VirtualClusterInstanceSpec: storagev1.VirtualClusterInstanceSpec{
+				TemplateRef: &storagev1.TemplateRef{Name: "small"},
+				ClusterRef: storagev1.VirtualClusterClusterRef{
+					ClusterRef: storagev1.ClusterRef{Cluster: "loft-cluster"},
+				},
+				Parameters: parameters,
+			},
+		},
+	}
+}
+
+func TestRenderTemplateValues(t *testing.T) {
+	instance := newPreviewInstance("replicas: 3\nsync:\n  ingresses: true\n")
+
+	values, err := renderTemplateValues(`controlPlane:
+  statefulSet:
+    highAvailability:
+      replicas: {{ .Values.replicas }}
+sync:
+  toHost:
+    ingresses:
+      enabled: {{ .Values.sync.ingresses }}
+# {{ .Loft.Name }} in {{ .Loft.Project }} on {{ .Loft.Cluster }}
+`, "default", instance)
+	assert.NilError(t, err)
+	assert.Assert(t, strings.Contains(values, "replicas: 3"), values)
+	assert.Assert(t, strings.Contains(values, "enabled: true"), values)
+	assert.Assert(t, strings.Contains(values, "# test in default on loft-cluster"), values)
+
+	_, err = renderTemplateValues("replicas: {{ .Values.unknown }}", "default", instance)
+	assert.ErrorContains(t, err, "unknown")
+}
+
+func TestTemplateDefinition(t *testing.T) {
+	virtualClusterTemplate := &managementv1.VirtualClusterTemplate{
+		Spec: managementv1.VirtualClusterTemplateSpec{
+			VirtualClusterTemplateSpec: storagev1.VirtualClusterTemplateSpec{
+				Versions: []storagev1.VirtualClusterTemplateVersion{
+					{Version: "1.0.0", Template: storagev1.VirtualClusterTemplateDefinition{VirtualClusterCommonSpec: storagev1.VirtualClusterCommonSpec{HelmRelease: storagev1.VirtualClusterHelmRelease{Values: "v1"}}}},
+					{Version: "1.1.0", Template: storagev1.VirtualClusterTemplateDefinition{VirtualClusterCommonSpec: storagev1.VirtualClusterCommonSpec{HelmRelease: storagev1.VirtualClusterHelmRelease{Values: "v1.1"}}}},
+					{Version: "2.0.0", Template: storagev1.VirtualClusterTemplateDefinition{VirtualClusterCommonSpec: storagev1.VirtualClusterCommonSpec{HelmRelease: storagev1.VirtualClusterHelmRelease{Values: "v2"}}}},
+				},
+			},
+		},
+	}
+
+	definition, err := templateDefinition(virtualClusterTemplate, "")
+	assert.NilError(t, err)
+	assert.Equal(t, definition.HelmRelease.Values, "v2")
+
+	definition, err = templateDefinition(virtualClusterTemplate, "1.x.x")
+	assert.NilError(t, err)
+	assert.Equal(t, definition.HelmRelease.Values, "v1.1")
+
+	_, err = templateDefinition(virtualClusterTemplate, "3.x.x")
+	assert.ErrorContains(t, err, "couldn't find any matching version")
+}
+
+func TestPrintPreview(t *testing.T) {
+	out := &bytes.Buffer{}
+	err := printPreview(out, "sync: {}\n", newPreviewInstance("replicas: 3\n"))
+	assert.NilError(t, err)
+	assert.Assert(t, strings.HasPrefix(out.String(), "# vcluster.yaml\nsync: {}\n---\n# VirtualClusterInstance\n"), out.String())
+	assert.Assert(t, strings.Contains(out.String(), "kind: VirtualClusterInstance"), out.String())
+	assert.Assert(t, strings.Contains(out.String(), "parameters: |"), out.String())
+}
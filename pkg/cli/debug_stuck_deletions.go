@@ -0,0 +1,236 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/log/table"
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/loft-sh/vcluster/pkg/constants"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+type StuckDeletionsOptions struct {
+	Output string
+
+	// MinAge is the time an object has to be deleting to count as stuck
+	MinAge time.Duration
+
+	// Force removes the vCluster finalizers of the stuck objects
+	Force bool
+}
+
+// StuckDeletion is an object that is being deleted for longer than expected
+type StuckDeletion struct {
+	Resource   string    `json:"resource"`
+	Namespace  string    `json:"namespace,omitempty"`
+	Name       string    `json:"name"`
+	Deleting   time.Time `json:"deleting"`
+	Finalizers []string  `json:"finalizers"`
+
+	gvr schema.GroupVersionResource
+}
+
+// StuckDeletions lists the objects of the current (virtual or host) cluster that are being deleted for longer than
+// MinAge and still have finalizers. With Force the vCluster finalizers of these objects are removed.
+func StuckDeletions(ctx context.Context, options *StuckDeletionsOptions, globalFlags *flags.GlobalFlags, log log.Logger) error {
+	restConfig, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(clientcmd.NewDefaultClientConfigLoadingRules(), &clientcmd.ConfigOverrides{
+		CurrentContext: globalFlags.Context,
+	}).ClientConfig()
+	if err != nil {
+		return err
+	}
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+	dynamicClient, err := dynamic.NewForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+
+	resources, err := deletableResources(discoveryClient, globalFlags.Namespace != "")
+	if err != nil {
+		return err
+	}
+
+	stuck, err := findStuckDeletions(ctx, dynamicClient, resources, globalFlags.Namespace, options.MinAge, time.Now())
+	if err != nil {
+		return err
+	}
+	if options.Force {
+		for _, obj := range stuck {
+			removed, err := removeSyncFinalizers(ctx, dynamicClient, obj)
+			if err != nil {
+				return err
+			} else if len(removed) > 0 {
+				log.Donef("Removed finalizers %s from %s %s", strings.Join(removed, ", "), obj.Resource, obj.objectName())
+			}
+		}
+	}
+
+	if options.Output == "json" {
+		out, err := json.MarshalIndent(stuck, "", "    ")
+		if err != nil {
+			return err
+		}
+
+		log.WriteString(log.GetLevel(), string(out)+"\n")
+		return nil
+	}
+
+	if len(stuck) == 0 {
+		log.Infof("No objects are being deleted for longer than %s", options.MinAge)
+		return nil
+	}
+
+	header := []string{"RESOURCE", "NAMESPACE", "NAME", "DELETING SINCE", "FINALIZERS"}
+	rows := [][]string{}
+	for _, obj := range stuck {
+		rows = append(rows, []string{obj.Resource, obj.Namespace, obj.Name, duration.HumanDuration(time.Since(obj.Deleting)), strings.Join(obj.Finalizers, ",")})
+	}
+	table.PrintTable(log, header, rows)
+	if !options.Force && slices.ContainsFunc(stuck, hasSyncFinalizer) {
+		log.Infof("Run with --force to remove the finalizers %s of these objects", strings.Join(constants.SyncFinalizers, ", "))
+	}
+
+	return nil
+}
+
+// deletableResources returns the preferred versions of all resources that can be listed and patched. If
+// namespacedOnly is true, cluster scoped resources are skipped.
+func deletableResources(discoveryClient discovery.DiscoveryInterface, namespacedOnly bool) ([]metav1.APIResource, error) {
+	resourceLists, err := discoveryClient.ServerPreferredResources()
+	if err != nil && !discovery.IsGroupDiscoveryFailedError(err) {
+		return nil, fmt.Errorf("discover resources: %w", err)
+	}
+
+	resources := []metav1.APIResource{}
+	for _, resourceList := range resourceLists {
+		gv, err := schema.ParseGroupVersion(resourceList.GroupVersion)
+		if err != nil {
+			continue
+		}
+
+		for _, resource := range resourceList.APIResources {
+			if strings.Contains(resource.Name, "/") || (namespacedOnly && !resource.Namespaced) {
+				continue
+			} else if !slices.Contains(resource.Verbs, "list") || !slices.Contains(resource.Verbs, "patch") {
+				continue
+			}
+
+			resource.Group = gv.Group
+			resource.Version = gv.Version
+			resources = append(resources, resource)
+		}
+	}
+
+	return resources, nil
+}
+
+// findStuckDeletions returns the objects of the given resources that are being deleted for longer than minAge
+func findStuckDeletions(ctx context.Context, dynamicClient dynamic.Interface, resources []metav1.APIResource, namespace string, minAge time.Duration, now time.Time) ([]StuckDeletion, error) {
+	stuck := []StuckDeletion{}
+	for _, resource := range resources {
+		gvr := schema.GroupVersionResource{Group: resource.Group, Version: resource.Version, Resource: resource.Name}
+		var resourceClient dynamic.ResourceInterface = dynamicClient.Resource(gvr)
+		if resource.Namespaced {
+			resourceClient = dynamicClient.Resource(gvr).Namespace(namespace)
+		}
+
+		list, err := resourceClient.List(ctx, metav1.ListOptions{})
+		if err != nil {
+			// skip resources that are gone or that we are not allowed to list
+			if kerrors.IsNotFound(err) || kerrors.IsForbidden(err) || kerrors.IsMethodNotSupported(err) {
+				continue
+			}
+
+			return nil, fmt.Errorf("list %s: %w", gvr.GroupResource().String(), err)
+		}
+
+		for _, obj := range list.Items {
+			deletionTimestamp := obj.GetDeletionTimestamp()
+			if deletionTimestamp == nil || len(obj.GetFinalizers()) == 0 || now.Sub(deletionTimestamp.Time) < minAge {
+				continue
+			}
+
+			stuck = append(stuck, StuckDeletion{
+				Resource:   gvr.GroupResource().String(),
+				Namespace:  obj.GetNamespace(),
+				Name:       obj.GetName(),
+				Deleting:   deletionTimestamp.Time,
+				Finalizers: obj.GetFinalizers(),
+				gvr:        gvr,
+			})
+		}
+	}
+
+	sort.SliceStable(stuck, func(i, j int) bool {
+		return stuck[i].Deleting.Before(stuck[j].Deleting)
+	})
+	return stuck, nil
+}
+
+// removeSyncFinalizers removes the vCluster finalizers of the given object and returns the removed finalizers. Other
+// finalizers are kept, as the controllers that added them might still need to clean up.
+func removeSyncFinalizers(ctx context.Context, dynamicClient dynamic.Interface, obj StuckDeletion) ([]string, error) {
+	removed := []string{}
+	finalizers := []string{}
+	for _, finalizer := range obj.Finalizers {
+		if slices.Contains(constants.SyncFinalizers, finalizer) {
+			removed = append(removed, finalizer)
+		} else {
+			finalizers = append(finalizers, finalizer)
+		}
+	}
+	if len(removed) == 0 {
+		return nil, nil
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"finalizers": finalizers,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var resourceClient dynamic.ResourceInterface = dynamicClient.Resource(obj.gvr)
+	if obj.Namespace != "" {
+		resourceClient = dynamicClient.Resource(obj.gvr).Namespace(obj.Namespace)
+	}
+	_, err = resourceClient.Patch(ctx, obj.Name, types.MergePatchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("remove finalizers of %s %s: %w", obj.Resource, obj.objectName(), err)
+	}
+
+	return removed, nil
+}
+
+func hasSyncFinalizer(obj StuckDeletion) bool {
+	return slices.ContainsFunc(obj.Finalizers, func(finalizer string) bool {
+		return slices.Contains(constants.SyncFinalizers, finalizer)
+	})
+}
+
+func (s StuckDeletion) objectName() string {
+	if s.Namespace == "" {
+		return s.Name
+	}
+
+	return s.Namespace + "/" + s.Name
+}
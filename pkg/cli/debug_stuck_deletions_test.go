@@ -0,0 +1,54 @@
+package cli
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/loft-sh/vcluster/pkg/constants"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+func TestStuckDeletions(t *testing.T) {
+	now := time.Now()
+	configMap := func(name string, deleting time.Duration, finalizers ...string) runtime.Object {
+		configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "test", Finalizers: finalizers}}
+		if deleting > 0 {
+			configMap.DeletionTimestamp = &metav1.Time{Time: now.Add(-deleting)}
+		}
+
+		return configMap
+	}
+
+	dynamicClient := dynamicfake.NewSimpleDynamicClient(scheme.Scheme,
+		configMap("stuck", time.Hour, constants.HostCleanupFinalizer, "example.com/finalizer"),
+		configMap("stuck-longer", 2*time.Hour, constants.HostCleanupFinalizer),
+		configMap("recent", time.Minute, constants.HostCleanupFinalizer),
+		configMap("live", 0, constants.HostCleanupFinalizer),
+	)
+	resources := []metav1.APIResource{{Name: "configmaps", Version: "v1", Namespaced: true}}
+
+	stuck, err := findStuckDeletions(context.Background(), dynamicClient, resources, "test", 5*time.Minute, now)
+	assert.NilError(t, err)
+	assert.Equal(t, len(stuck), 2)
+	assert.Equal(t, stuck[0].Name, "stuck-longer")
+	assert.Equal(t, stuck[1].Name, "stuck")
+	assert.Equal(t, stuck[1].Resource, "configmaps")
+
+	removed, err := removeSyncFinalizers(context.Background(), dynamicClient, stuck[1])
+	assert.NilError(t, err)
+	assert.DeepEqual(t, removed, []string{constants.HostCleanupFinalizer})
+
+	obj, err := dynamicClient.Resource(stuck[1].gvr).Namespace("test").Get(context.Background(), "stuck", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, obj.GetFinalizers(), []string{"example.com/finalizer"})
+
+	removed, err = removeSyncFinalizers(context.Background(), dynamicClient, StuckDeletion{Name: "other", Finalizers: []string{"example.com/finalizer"}})
+	assert.NilError(t, err)
+	assert.Equal(t, len(removed), 0)
+}
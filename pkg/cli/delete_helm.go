@@ -5,12 +5,14 @@ import (
 	"errors"
 	"fmt"
 	"os/exec"
-	"time"
 
 	"github.com/loft-sh/log"
+	"github.com/loft-sh/vcluster/config"
 	"github.com/loft-sh/vcluster/pkg/cli/find"
 	"github.com/loft-sh/vcluster/pkg/cli/flags"
 	"github.com/loft-sh/vcluster/pkg/cli/localkubernetes"
+	platformdns "github.com/loft-sh/vcluster/pkg/controllers/coredns"
+	"github.com/loft-sh/vcluster/pkg/coredns"
 	"github.com/loft-sh/vcluster/pkg/helm"
 	"github.com/loft-sh/vcluster/pkg/platform"
 	"github.com/loft-sh/vcluster/pkg/util/clihelper"
@@ -23,6 +25,7 @@ import (
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 const VirtualClusterServiceUIDLabel = "vcluster.loft.sh/service-uid"
@@ -38,6 +41,14 @@ type DeleteOptions struct {
 	AutoDeleteNamespace bool
 	IgnoreNotFound      bool
 
+	// DeleteSharedNamespace deletes the namespace without asking, even if it was not created by vCluster
+	DeleteSharedNamespace bool
+
+	// ForceCleanup deletes the host objects of the vCluster even if the helm release is gone or can't be uninstalled
+	ForceCleanup bool
+	// CleanupPVCs also deletes the persistent volume claims synced by the vCluster with ForceCleanup
+	CleanupPVCs bool
+
 	Project string
 }
 
@@ -62,11 +73,13 @@ func DeleteHelm(ctx context.Context, options *DeleteOptions, globalFlags *flags.
 	// find vcluster
 	vCluster, err := find.GetVCluster(ctx, cmd.Context, vClusterName, cmd.Namespace, cmd.log)
 	if err != nil {
+		var errorNotFound *find.VClusterNotFoundError
+		if cmd.ForceCleanup && errors.As(err, &errorNotFound) {
+			return cmd.cleanupWithoutRelease(ctx, vClusterName)
+		}
 		if !cmd.IgnoreNotFound {
 			return err
 		}
-
-		var errorNotFound *find.VClusterNotFoundError
 		if !errors.As(err, &errorNotFound) {
 			return err
 		}
@@ -106,19 +119,43 @@ func DeleteHelm(ctx context.Context, options *DeleteOptions, globalFlags *flags.
 		}
 	}
 
+	// protect namespaces that are shared with other workloads
+	if cmd.DeleteNamespace {
+		cmd.DeleteNamespace, err = cmd.confirmNamespaceDeletion(ctx, vClusterName)
+		if err != nil {
+			return err
+		}
+	}
+
 	// get service uid
 	vClusterService, err := cmd.kubeClient.CoreV1().Services(cmd.Namespace).Get(ctx, vClusterName, metav1.GetOptions{})
 	if err != nil && !kerrors.IsNotFound(err) {
 		return fmt.Errorf("error retrieving vcluster service: %w", err)
 	}
 
+	// read the config of the release before it's uninstalled, it decides which host objects outside of the release
+	// have to be cleaned up
+	var releaseConfig *config.Config
+	release, err := helm.NewSecrets(cmd.kubeClient).Get(ctx, vClusterName, cmd.Namespace)
+	if err == nil {
+		releaseConfig, err = releaseVClusterConfig(release.Config)
+	}
+	if err != nil {
+		cmd.log.Debugf("Error reading config of vcluster release: %v", err)
+	}
+
 	// we have to delete the chart
 	cmd.log.Infof("Delete vcluster %s...", vClusterName)
 	err = helm.NewClient(cmd.rawConfig, cmd.log, helmBinaryPath).Delete(vClusterName, cmd.Namespace)
 	if err != nil {
-		return err
+		if !cmd.ForceCleanup {
+			return err
+		}
+
+		cmd.log.Warnf("Error uninstalling helm release of vcluster %s, continue with force cleanup: %v", vClusterName, err)
+	} else {
+		cmd.log.Donef("Successfully deleted virtual cluster %s in namespace %s", vClusterName, cmd.Namespace)
 	}
-	cmd.log.Donef("Successfully deleted virtual cluster %s in namespace %s", vClusterName, cmd.Namespace)
 
 	// try to delete the vCluster in the platform
 	if vClusterService != nil {
@@ -128,6 +165,36 @@ func DeleteHelm(ctx context.Context, options *DeleteOptions, globalFlags *flags.
 		}
 	}
 
+	// remove the vCluster from the shared CoreDNS
+	if releaseConfig != nil && releaseConfig.Networking.Advanced.SharedDNS.Enabled {
+		err = cmd.unregisterSharedDNS(ctx, vClusterName, releaseConfig.Networking.Advanced.SharedDNS.Namespace)
+		if err != nil {
+			cmd.log.Warnf("Error removing vcluster %s from the shared CoreDNS: %v", vClusterName, err)
+		}
+	}
+
+	// remove the registration of the vCluster from the platform dns
+	if releaseConfig != nil && releaseConfig.Networking.Advanced.PlatformDNS.Enabled {
+		registryNamespace := releaseConfig.Networking.Advanced.PlatformDNS.Namespace
+		err = cmd.kubeClient.CoreV1().ConfigMaps(registryNamespace).Delete(ctx, platformdns.PlatformDNSRegistrationName(vClusterName, cmd.Namespace), metav1.DeleteOptions{})
+		if err != nil && !kerrors.IsNotFound(err) {
+			cmd.log.Warnf("Error removing vcluster %s from the platform dns: %v", vClusterName, err)
+		} else if err == nil {
+			cmd.log.Donef("Successfully removed virtual cluster %s from the platform dns in namespace %s", vClusterName, registryNamespace)
+		}
+	}
+
+	// delete the argo cd cluster secret if the vCluster was registered with --register-argocd
+	if vClusterService != nil && vClusterService.Annotations[ArgoCDNamespaceAnnotation] != "" {
+		argoCDSecrets, err := deleteArgoCDClusterSecrets(ctx, cmd.kubeClient, vClusterName, cmd.Namespace, vClusterService.Annotations[ArgoCDNamespaceAnnotation])
+		if err != nil {
+			cmd.log.Warnf("Error deleting argo cd cluster secrets: %v", err)
+		}
+		for _, secret := range argoCDSecrets {
+			cmd.log.Donef("Successfully deleted Argo CD cluster secret %s", secret)
+		}
+	}
+
 	// try to delete the pvc
 	if !cmd.KeepPVC && !cmd.DeleteNamespace {
 		pvcName := fmt.Sprintf("data-%s-0", vClusterName)
@@ -153,6 +220,14 @@ func DeleteHelm(ctx context.Context, options *DeleteOptions, globalFlags *flags.
 		}
 	}
 
+	// delete everything the helm uninstall left behind
+	if cmd.ForceCleanup {
+		err = forceCleanup(ctx, cmd.restConfig, vClusterName, cmd.Namespace, cmd.CleanupPVCs, cmd.log)
+		if err != nil {
+			return fmt.Errorf("force cleanup: %w", err)
+		}
+	}
+
 	// try to delete the ConfigMap
 	if cmd.DeleteConfigMap {
 		// Attempt to delete the ConfigMap
@@ -177,6 +252,25 @@ func DeleteHelm(ctx context.Context, options *DeleteOptions, globalFlags *flags.
 		cmd.DeleteNamespace = false
 	}
 
+	// namespaces of the multi namespace mode
+	multiNamespaces := []string{}
+	namespaces, err := cmd.kubeClient.CoreV1().Namespaces().List(ctx, metav1.ListOptions{
+		LabelSelector: translate.MarkerLabel + "=" + translate.SafeConcatName(cmd.Namespace, "x", vClusterName),
+	})
+	if err != nil && !kerrors.IsForbidden(err) {
+		return fmt.Errorf("list namespaces: %w", err)
+	} else if namespaces != nil {
+		for _, namespace := range namespaces.Items {
+			multiNamespaces = append(multiNamespaces, namespace.Name)
+		}
+	}
+
+	// the syncer is gone, so remove the finalizers it would remove
+	err = cmd.removeStuckFinalizers(ctx, vClusterName, multiNamespaces)
+	if err != nil {
+		cmd.log.Warnf("Error removing vcluster finalizers from host objects: %v", err)
+	}
+
 	// try to delete the namespace
 	if cmd.DeleteNamespace {
 		// delete namespace
@@ -189,40 +283,62 @@ func DeleteHelm(ctx context.Context, options *DeleteOptions, globalFlags *flags.
 			cmd.log.Donef("Successfully deleted virtual cluster namespace %s", cmd.Namespace)
 		}
 
-		// delete multi namespace mode namespaces
-		namespaces, err := cmd.kubeClient.CoreV1().Namespaces().List(ctx, metav1.ListOptions{
-			LabelSelector: translate.MarkerLabel + "=" + translate.SafeConcatName(cmd.Namespace, "x", vClusterName),
-		})
-		if err != nil && !kerrors.IsForbidden(err) {
-			return fmt.Errorf("list namespaces: %w", err)
-		}
-
-		// delete all namespaces
-		if namespaces != nil && len(namespaces.Items) > 0 {
-			for _, namespace := range namespaces.Items {
-				err = cmd.kubeClient.CoreV1().Namespaces().Delete(ctx, namespace.Name, metav1.DeleteOptions{})
-				if err != nil {
-					if !kerrors.IsNotFound(err) {
-						return fmt.Errorf("delete namespace: %w", err)
-					}
-				} else {
-					cmd.log.Donef("Successfully deleted virtual cluster namespace %s", namespace.Name)
+		// delete all multi namespace mode namespaces
+		for _, namespace := range multiNamespaces {
+			err = cmd.kubeClient.CoreV1().Namespaces().Delete(ctx, namespace, metav1.DeleteOptions{})
+			if err != nil {
+				if !kerrors.IsNotFound(err) {
+					return fmt.Errorf("delete namespace: %w", err)
 				}
+			} else {
+				cmd.log.Donef("Successfully deleted virtual cluster namespace %s", namespace)
 			}
 		}
+	}
 
-		// wait for vcluster deletion
-		if cmd.Wait {
-			cmd.log.Info("Waiting for virtual cluster to be deleted...")
-			for {
-				_, err = cmd.kubeClient.CoreV1().Namespaces().Get(ctx, cmd.Namespace, metav1.GetOptions{})
-				if err != nil {
-					break
-				}
+	// wait for vcluster deletion
+	if cmd.Wait {
+		waitForNamespaces := []string{}
+		if cmd.DeleteNamespace {
+			waitForNamespaces = append([]string{cmd.Namespace}, multiNamespaces...)
+		}
 
-				time.Sleep(time.Second)
+		return cmd.waitForCleanup(ctx, vClusterName, waitForNamespaces)
+	}
+
+	return nil
+}
+
+// cleanupWithoutRelease cleans up the host objects of a vCluster whose helm release is already gone
+func (cmd *deleteHelm) cleanupWithoutRelease(ctx context.Context, vClusterName string) error {
+	if cmd.Namespace == "" {
+		return fmt.Errorf("couldn't find vcluster %s, please specify its namespace via --namespace to clean it up", vClusterName)
+	}
+
+	restConfig, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(clientcmd.NewDefaultClientConfigLoadingRules(), &clientcmd.ConfigOverrides{
+		CurrentContext: cmd.Context,
+	}).ClientConfig()
+	if err != nil {
+		return err
+	}
+	kubeClient, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+
+	cmd.log.Infof("Couldn't find vcluster %s in namespace %s, cleaning up its host objects", vClusterName, cmd.Namespace)
+	err = forceCleanup(ctx, restConfig, vClusterName, cmd.Namespace, cmd.CleanupPVCs, cmd.log)
+	if err != nil {
+		return fmt.Errorf("force cleanup: %w", err)
+	}
+	if !cmd.KeepPVC {
+		for _, pvcName := range []string{fmt.Sprintf("data-%s-0", vClusterName), fmt.Sprintf("data-%s-etcd-0", vClusterName)} {
+			err = kubeClient.CoreV1().PersistentVolumeClaims(cmd.Namespace).Delete(ctx, pvcName, metav1.DeleteOptions{})
+			if err != nil && !kerrors.IsNotFound(err) {
+				return fmt.Errorf("delete pvc: %w", err)
+			} else if err == nil {
+				cmd.log.Donef("Successfully deleted virtual cluster pvc %s in namespace %s", pvcName, cmd.Namespace)
 			}
-			cmd.log.Done("Virtual Cluster is deleted")
 		}
 	}
 
@@ -261,16 +377,33 @@ func (cmd *deleteHelm) deleteVClusterInPlatform(ctx context.Context, vClusterSer
 	return nil
 }
 
+func (cmd *deleteHelm) unregisterSharedDNS(ctx context.Context, vClusterName, sharedNamespace string) error {
+	hostClient, err := client.New(cmd.restConfig, client.Options{})
+	if err != nil {
+		return err
+	}
+
+	err = coredns.UnregisterSharedDNSTenant(ctx, hostClient, sharedNamespace, coredns.SharedDNSTenant{Name: vClusterName, Namespace: cmd.Namespace})
+	if err != nil {
+		return err
+	}
+
+	cmd.log.Donef("Successfully removed virtual cluster %s from the shared CoreDNS in namespace %s", vClusterName, sharedNamespace)
+	return nil
+}
+
 func (cmd *deleteHelm) prepare(vCluster *find.VCluster) error {
 	// load the raw config
 	rawConfig, err := vCluster.ClientFactory.RawConfig()
 	if err != nil {
 		return fmt.Errorf("there is an error loading your current kube config (%w), please make sure you have access to a kubernetes cluster and the command `kubectl get namespaces` is working", err)
 	}
-	err = deleteContext(&rawConfig, find.VClusterContextName(vCluster.Name, vCluster.Namespace, vCluster.Context), vCluster.Context)
+	contextName := find.VClusterContextName(vCluster.Name, vCluster.Namespace, vCluster.Context)
+	err = deleteContext(&rawConfig, contextName, vCluster.Context)
 	if err != nil {
 		return fmt.Errorf("delete kube context: %w", err)
 	}
+	unregisterContext(cmd.LoadedConfig(cmd.log), contextName, cmd.log)
 
 	rawConfig.CurrentContext = vCluster.Context
 	restConfig, err := vCluster.ClientFactory.ClientConfig()
@@ -0,0 +1,180 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+)
+
+var (
+	persistentVolumeClaimsResource = schema.GroupVersionResource{Version: "v1", Resource: "persistentvolumeclaims"}
+	secretsResource                = schema.GroupVersionResource{Version: "v1", Resource: "secrets"}
+	namespacesResource             = schema.GroupVersionResource{Version: "v1", Resource: "namespaces"}
+	clusterRolesResource           = schema.GroupVersionResource{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "clusterroles"}
+	clusterRoleBindingsResource    = schema.GroupVersionResource{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "clusterrolebindings"}
+)
+
+// forceCleanup deletes everything the virtual cluster left in the host cluster, also if the helm release is gone: the
+// objects of the chart and its helm release, the objects synced to the host and the secrets created by vCluster. The
+// vCluster finalizers of these objects are removed, so the deletion doesn't wait for a syncer that is no longer running.
+// Synced persistent volume claims are only deleted with cleanupPVCs, the data pvc of vCluster is deleted separately.
+func forceCleanup(ctx context.Context, restConfig *rest.Config, vClusterName, namespace string, cleanupPVCs bool, log log.Logger) error {
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+	dynamicClient, err := dynamic.NewForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+	resources, err := deletableResources(discoveryClient, true)
+	if err != nil {
+		return err
+	}
+
+	log.Infof("Clean up host objects of vcluster %s in namespace %s...", vClusterName, namespace)
+	deleted := 0
+	for _, selector := range []struct {
+		labelSelector string
+		skipPVCs      bool
+	}{
+		// objects synced by vCluster
+		{labelSelector: translate.MarkerLabel + "=" + vClusterName, skipPVCs: !cleanupPVCs},
+		// objects of the chart, the data pvc is deleted according to --keep-pvc
+		{labelSelector: "app=vcluster,release=" + vClusterName, skipPVCs: true},
+		// secrets of the helm release
+		{labelSelector: "owner=helm,name=" + vClusterName},
+	} {
+		count, err := deleteLabeledObjects(ctx, dynamicClient, resources, namespace, selector.labelSelector, selector.skipPVCs, log)
+		if err != nil {
+			return err
+		}
+		deleted += count
+	}
+
+	// secrets vCluster creates at runtime
+	for _, name := range []string{"vc-config-" + vClusterName, "vc-" + vClusterName, vClusterName + "-certs", "vc-k3s-" + vClusterName} {
+		ok, err := forceDeleteObject(ctx, dynamicClient, secretsResource, namespace, name)
+		if err != nil {
+			return err
+		} else if ok {
+			deleted++
+		}
+	}
+
+	// cluster scoped rbac of the chart
+	clusterRoleName := strings.TrimSuffix(truncate(fmt.Sprintf("vc-%s-v-%s", vClusterName, namespace), 63), "-")
+	for _, gvr := range []schema.GroupVersionResource{clusterRolesResource, clusterRoleBindingsResource} {
+		ok, err := forceDeleteObject(ctx, dynamicClient, gvr, "", clusterRoleName)
+		if err != nil && !kerrors.IsForbidden(err) {
+			return err
+		} else if ok {
+			deleted++
+		}
+	}
+
+	// namespaces of the multi namespace mode
+	namespaces, err := dynamicClient.Resource(namespacesResource).List(ctx, metav1.ListOptions{
+		LabelSelector: translate.MarkerLabel + "=" + translate.SafeConcatName(namespace, "x", vClusterName),
+	})
+	if err != nil && !kerrors.IsForbidden(err) {
+		return fmt.Errorf("list namespaces: %w", err)
+	} else if namespaces != nil {
+		for _, ns := range namespaces.Items {
+			ok, err := forceDeleteObject(ctx, dynamicClient, namespacesResource, "", ns.GetName())
+			if err != nil {
+				return err
+			} else if ok {
+				log.Donef("Successfully deleted virtual cluster namespace %s", ns.GetName())
+			}
+		}
+	}
+
+	log.Donef("Successfully cleaned up %d host objects of vcluster %s in namespace %s", deleted, vClusterName, namespace)
+	return nil
+}
+
+// deleteLabeledObjects deletes the objects of the given resources and namespace that match the label selector
+func deleteLabeledObjects(ctx context.Context, dynamicClient dynamic.Interface, resources []metav1.APIResource, namespace, labelSelector string, skipPVCs bool, log log.Logger) (int, error) {
+	deleted := 0
+	for _, resource := range resources {
+		gvr := schema.GroupVersionResource{Group: resource.Group, Version: resource.Version, Resource: resource.Name}
+		if !slices.Contains(resource.Verbs, "delete") || (skipPVCs && gvr == persistentVolumeClaimsResource) {
+			continue
+		}
+
+		list, err := dynamicClient.Resource(gvr).Namespace(namespace).List(ctx, metav1.ListOptions{LabelSelector: labelSelector})
+		if err != nil {
+			// skip resources that are gone or that we are not allowed to list
+			if kerrors.IsNotFound(err) || kerrors.IsForbidden(err) || kerrors.IsMethodNotSupported(err) {
+				continue
+			}
+
+			return deleted, fmt.Errorf("list %s: %w", gvr.GroupResource().String(), err)
+		}
+
+		for _, obj := range list.Items {
+			ok, err := forceDeleteObject(ctx, dynamicClient, gvr, namespace, obj.GetName())
+			if err != nil {
+				return deleted, err
+			} else if ok {
+				log.Debugf("Deleted %s %s/%s", gvr.GroupResource().String(), namespace, obj.GetName())
+				deleted++
+			}
+		}
+	}
+
+	return deleted, nil
+}
+
+// forceDeleteObject removes the vCluster finalizers of the object and deletes it. Returns false if it didn't exist.
+func forceDeleteObject(ctx context.Context, dynamicClient dynamic.Interface, gvr schema.GroupVersionResource, namespace, name string) (bool, error) {
+	var resourceClient dynamic.ResourceInterface = dynamicClient.Resource(gvr)
+	if namespace != "" {
+		resourceClient = dynamicClient.Resource(gvr).Namespace(namespace)
+	}
+
+	obj, err := resourceClient.Get(ctx, name, metav1.GetOptions{})
+	if kerrors.IsNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, fmt.Errorf("get %s %s: %w", gvr.GroupResource().String(), name, err)
+	}
+
+	_, err = removeSyncFinalizers(ctx, dynamicClient, StuckDeletion{
+		Resource:   gvr.GroupResource().String(),
+		Namespace:  namespace,
+		Name:       name,
+		Finalizers: obj.GetFinalizers(),
+		gvr:        gvr,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	propagation := metav1.DeletePropagationBackground
+	err = resourceClient.Delete(ctx, name, metav1.DeleteOptions{PropagationPolicy: &propagation})
+	if err != nil && !kerrors.IsNotFound(err) {
+		return false, fmt.Errorf("delete %s %s: %w", gvr.GroupResource().String(), name, err)
+	}
+
+	return true, nil
+}
+
+func truncate(s string, length int) string {
+	if len(s) > length {
+		return s[:length]
+	}
+
+	return s
+}
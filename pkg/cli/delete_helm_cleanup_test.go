@@ -0,0 +1,55 @@
+package cli
+
+import (
+	"context"
+	"testing"
+
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/vcluster/pkg/constants"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+func TestDeleteLabeledObjects(t *testing.T) {
+	ctx := context.Background()
+	configMapsResource := schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+	labels := map[string]string{translate.MarkerLabel: "my-vcluster"}
+	dynamicClient := dynamicfake.NewSimpleDynamicClient(scheme.Scheme,
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "synced", Namespace: "vcluster", Labels: labels, Finalizers: []string{constants.HostCleanupFinalizer}}},
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "vcluster", Labels: map[string]string{translate.MarkerLabel: "other"}}},
+		&corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: "synced", Namespace: "vcluster", Labels: labels}},
+	)
+	resources := []metav1.APIResource{
+		{Name: "configmaps", Version: "v1", Namespaced: true, Verbs: []string{"list", "patch", "delete"}},
+		{Name: "persistentvolumeclaims", Version: "v1", Namespaced: true, Verbs: []string{"list", "patch", "delete"}},
+	}
+
+	// pvcs are kept
+	deleted, err := deleteLabeledObjects(ctx, dynamicClient, resources, "vcluster", translate.MarkerLabel+"=my-vcluster", true, log.Discard)
+	assert.NilError(t, err)
+	assert.Equal(t, deleted, 1)
+	_, err = dynamicClient.Resource(configMapsResource).Namespace("vcluster").Get(ctx, "synced", metav1.GetOptions{})
+	assert.Assert(t, kerrors.IsNotFound(err))
+	_, err = dynamicClient.Resource(configMapsResource).Namespace("vcluster").Get(ctx, "other", metav1.GetOptions{})
+	assert.NilError(t, err)
+	_, err = dynamicClient.Resource(persistentVolumeClaimsResource).Namespace("vcluster").Get(ctx, "synced", metav1.GetOptions{})
+	assert.NilError(t, err)
+
+	// pvcs are deleted
+	deleted, err = deleteLabeledObjects(ctx, dynamicClient, resources, "vcluster", translate.MarkerLabel+"=my-vcluster", false, log.Discard)
+	assert.NilError(t, err)
+	assert.Equal(t, deleted, 1)
+	_, err = dynamicClient.Resource(persistentVolumeClaimsResource).Namespace("vcluster").Get(ctx, "synced", metav1.GetOptions{})
+	assert.Assert(t, kerrors.IsNotFound(err))
+
+	// missing objects are skipped
+	ok, err := forceDeleteObject(ctx, dynamicClient, secretsResource, "vcluster", "vc-config-my-vcluster")
+	assert.NilError(t, err)
+	assert.Assert(t, !ok)
+}
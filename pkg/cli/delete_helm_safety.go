@@ -0,0 +1,205 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/loft-sh/log/survey"
+	"github.com/loft-sh/log/terminal"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+)
+
+// deleteProgressInterval is the interval the remaining objects are reported while waiting for the cleanup
+var deleteProgressInterval = 5 * time.Second
+
+// confirmNamespaceDeletion returns false if the namespace of the vCluster was not created by vCluster and the user
+// didn't confirm to delete it. Namespaces that were not created by vCluster are usually shared with other workloads.
+func (cmd *deleteHelm) confirmNamespaceDeletion(ctx context.Context, vClusterName string) (bool, error) {
+	namespace, err := cmd.kubeClient.CoreV1().Namespaces().Get(ctx, cmd.Namespace, metav1.GetOptions{})
+	if kerrors.IsNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, fmt.Errorf("get namespace %s: %w", cmd.Namespace, err)
+	} else if isCreatedByVCluster(namespace) || cmd.DeleteSharedNamespace {
+		return true, nil
+	}
+
+	pods, err := cmd.kubeClient.CoreV1().Pods(cmd.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return false, fmt.Errorf("list pods: %w", err)
+	}
+	otherPods := []string{}
+	for _, pod := range pods.Items {
+		if !isVClusterPod(&pod, vClusterName) {
+			otherPods = append(otherPods, pod.Name)
+		}
+	}
+
+	question := fmt.Sprintf("Namespace %s was not created by vcluster", cmd.Namespace)
+	if len(otherPods) > 0 {
+		question += fmt.Sprintf(" and contains %d pods of other workloads", len(otherPods))
+	}
+	if !terminal.IsTerminalIn {
+		cmd.log.Warnf("%s, keeping it. Use --delete-shared-namespace to delete it anyway", question)
+		return false, nil
+	}
+
+	keepOption := "No, keep the namespace"
+	answer, err := cmd.log.Question(&survey.QuestionOptions{
+		Question:     question + ", do you want to delete it?",
+		DefaultValue: keepOption,
+		Options:      []string{keepOption, "Yes"},
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return answer != keepOption, nil
+}
+
+// removeStuckFinalizers removes the vCluster finalizers from the host objects of the vCluster. The syncer that would
+// remove them is gone after the uninstall, so they would block the deletion of these objects and their namespace.
+func (cmd *deleteHelm) removeStuckFinalizers(ctx context.Context, vClusterName string, namespaces []string) error {
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(cmd.restConfig)
+	if err != nil {
+		return err
+	}
+	dynamicClient, err := dynamic.NewForConfig(cmd.restConfig)
+	if err != nil {
+		return err
+	}
+	resources, err := deletableResources(discoveryClient, true)
+	if err != nil {
+		return err
+	}
+
+	// the namespace of the vCluster might be shared with other vClusters, so only the objects of this vCluster are
+	// touched. Namespaces of the multi namespace mode only contain objects of this vCluster.
+	removed, err := removeSyncFinalizersInNamespace(ctx, dynamicClient, resources, cmd.Namespace, translate.MarkerLabel+"="+vClusterName)
+	if err != nil {
+		return err
+	}
+	for _, namespace := range namespaces {
+		count, err := removeSyncFinalizersInNamespace(ctx, dynamicClient, resources, namespace, "")
+		if err != nil {
+			return err
+		}
+		removed += count
+	}
+	if removed > 0 {
+		cmd.log.Donef("Removed vcluster finalizers from %d host objects", removed)
+	}
+
+	return nil
+}
+
+func removeSyncFinalizersInNamespace(ctx context.Context, dynamicClient dynamic.Interface, resources []metav1.APIResource, namespace, labelSelector string) (int, error) {
+	removed := 0
+	for _, resource := range resources {
+		gvr := schema.GroupVersionResource{Group: resource.Group, Version: resource.Version, Resource: resource.Name}
+		list, err := dynamicClient.Resource(gvr).Namespace(namespace).List(ctx, metav1.ListOptions{LabelSelector: labelSelector})
+		if err != nil {
+			if kerrors.IsNotFound(err) || kerrors.IsForbidden(err) || kerrors.IsMethodNotSupported(err) {
+				continue
+			}
+
+			return removed, fmt.Errorf("list %s: %w", gvr.GroupResource().String(), err)
+		}
+
+		for _, obj := range list.Items {
+			finalizers, err := removeSyncFinalizers(ctx, dynamicClient, StuckDeletion{
+				Resource:   gvr.GroupResource().String(),
+				Namespace:  namespace,
+				Name:       obj.GetName(),
+				Finalizers: obj.GetFinalizers(),
+				gvr:        gvr,
+			})
+			if err != nil {
+				return removed, err
+			} else if len(finalizers) > 0 {
+				removed++
+			}
+		}
+	}
+
+	return removed, nil
+}
+
+// waitForCleanup waits until the given namespaces are deleted and the pods of the vCluster are gone and reports the
+// remaining objects in between
+func (cmd *deleteHelm) waitForCleanup(ctx context.Context, vClusterName string, namespaces []string) error {
+	cmd.log.Info("Waiting for virtual cluster to be deleted...")
+	lastProgress := ""
+	for {
+		remaining := map[string]int{}
+		for _, namespace := range namespaces {
+			_, err := cmd.kubeClient.CoreV1().Namespaces().Get(ctx, namespace, metav1.GetOptions{})
+			if kerrors.IsNotFound(err) {
+				continue
+			} else if err != nil {
+				return fmt.Errorf("get namespace %s: %w", namespace, err)
+			}
+
+			remaining["namespaces"]++
+			pods, err := cmd.kubeClient.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return fmt.Errorf("list pods: %w", err)
+			}
+			remaining["pods"] += len(pods.Items)
+		}
+		if len(namespaces) == 0 {
+			pods, err := cmd.kubeClient.CoreV1().Pods(cmd.Namespace).List(ctx, metav1.ListOptions{LabelSelector: "app=vcluster,release=" + vClusterName})
+			if err != nil {
+				return fmt.Errorf("list pods: %w", err)
+			}
+			remaining["pods"] += len(pods.Items)
+		}
+
+		progress := formatRemaining(remaining)
+		if progress == "" {
+			break
+		} else if progress != lastProgress {
+			cmd.log.Infof("Waiting for %s to be deleted", progress)
+			lastProgress = progress
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(deleteProgressInterval):
+		}
+	}
+
+	cmd.log.Done("Virtual Cluster is deleted")
+	return nil
+}
+
+func formatRemaining(remaining map[string]int) string {
+	parts := []string{}
+	for resource, count := range remaining {
+		if count > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", count, resource))
+		}
+	}
+
+	sort.Strings(parts)
+	return strings.Join(parts, ", ")
+}
+
+func isCreatedByVCluster(namespace *corev1.Namespace) bool {
+	return namespace.Annotations != nil && namespace.Annotations[CreatedByVClusterAnnotation] == "true"
+}
+
+// isVClusterPod returns true if the pod belongs to the control plane of the vCluster or was synced by it
+func isVClusterPod(pod *corev1.Pod, vClusterName string) bool {
+	return pod.Labels[translate.MarkerLabel] == vClusterName || (pod.Labels["app"] == "vcluster" && pod.Labels["release"] == vClusterName)
+}
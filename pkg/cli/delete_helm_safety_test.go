@@ -0,0 +1,50 @@
+package cli
+
+import (
+	"context"
+	"testing"
+
+	"github.com/loft-sh/vcluster/pkg/constants"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+func TestRemoveSyncFinalizersInNamespace(t *testing.T) {
+	ctx := context.Background()
+	dynamicClient := dynamicfake.NewSimpleDynamicClient(scheme.Scheme,
+		&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "synced", Namespace: "vcluster", Labels: map[string]string{translate.MarkerLabel: "my-vcluster"}, Finalizers: []string{constants.VirtualCleanupFinalizer, "example.com/finalizer"}}},
+		&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "vcluster", Labels: map[string]string{translate.MarkerLabel: "other"}, Finalizers: []string{constants.VirtualCleanupFinalizer}}},
+	)
+	resources := []metav1.APIResource{{Name: "services", Version: "v1", Namespaced: true}}
+
+	removed, err := removeSyncFinalizersInNamespace(ctx, dynamicClient, resources, "vcluster", translate.MarkerLabel+"=my-vcluster")
+	assert.NilError(t, err)
+	assert.Equal(t, removed, 1)
+
+	servicesResource := schema.GroupVersionResource{Version: "v1", Resource: "services"}
+	synced, err := dynamicClient.Resource(servicesResource).Namespace("vcluster").Get(ctx, "synced", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, synced.GetFinalizers(), []string{"example.com/finalizer"})
+
+	// objects of other virtual clusters keep their finalizers
+	other, err := dynamicClient.Resource(servicesResource).Namespace("vcluster").Get(ctx, "other", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, other.GetFinalizers(), []string{constants.VirtualCleanupFinalizer})
+}
+
+func TestFormatRemaining(t *testing.T) {
+	assert.Equal(t, formatRemaining(map[string]int{"pods": 3, "namespaces": 1}), "1 namespaces, 3 pods")
+	assert.Equal(t, formatRemaining(map[string]int{"pods": 0}), "")
+}
+
+func TestIsVClusterPod(t *testing.T) {
+	assert.Assert(t, isVClusterPod(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "vcluster", "release": "my-vcluster"}}}, "my-vcluster"))
+	assert.Assert(t, isVClusterPod(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{translate.MarkerLabel: "my-vcluster"}}}, "my-vcluster"))
+	assert.Assert(t, !isVClusterPod(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "vcluster", "release": "other"}}}, "my-vcluster"))
+	assert.Assert(t, !isVClusterPod(&corev1.Pod{}, "my-vcluster"))
+}
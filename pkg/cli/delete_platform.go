@@ -43,7 +43,7 @@ func DeletePlatform(ctx context.Context, options *DeleteOptions, config *config.
 
 	// update kube config
 	if options.DeleteContext {
-		err = deletePlatformContext(vCluster.VirtualCluster.Name, vCluster.Project.Name)
+		err = deletePlatformContext(config, vCluster.VirtualCluster.Name, vCluster.Project.Name, log)
 		if err != nil {
 			return fmt.Errorf("delete kube context: %w", err)
 		}
@@ -66,16 +66,22 @@ func isVirtualClusterInstanceStillThere(ctx context.Context, managementClient ku
 	return err == nil
 }
 
-func deletePlatformContext(vClusterName, projectName string) error {
+func deletePlatformContext(cfg *config.CLI, vClusterName, projectName string, log log.Logger) error {
 	kubeClientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(clientcmd.NewDefaultClientConfigLoadingRules(), &clientcmd.ConfigOverrides{})
 	kubeConfig, err := kubeClientConfig.RawConfig()
 	if err != nil {
 		return fmt.Errorf("load kube config: %w", err)
 	}
 
-	// remove matching contexts
+	// remove matching contexts, including the recorded contexts that were created with vcluster create
 	for contextName := range kubeConfig.Contexts {
 		name, project, previousContext := find.VClusterPlatformFromContext(contextName)
+		if kubeContext, ok := cfg.Contexts[contextName]; ok && kubeContext.Driver == config.PlatformDriver {
+			name, project = kubeContext.Name, kubeContext.Project
+			if previousContext == "" {
+				previousContext = cfg.PreviousContext
+			}
+		}
 		if vClusterName != name || projectName != project {
 			continue
 		}
@@ -84,6 +90,7 @@ func deletePlatformContext(vClusterName, projectName string) error {
 		if err != nil {
 			return err
 		}
+		unregisterContext(cfg, contextName, log)
 	}
 
 	return nil
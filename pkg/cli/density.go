@@ -0,0 +1,183 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/log/table"
+	"github.com/loft-sh/vcluster/pkg/cli/find"
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+type DensityReportOptions struct {
+	Output string
+}
+
+// DensityReport holds the estimated capacity of the host cluster for additional virtual clusters
+type DensityReport struct {
+	VirtualClusters int `json:"virtualClusters"`
+
+	AllocatableCPU    string `json:"allocatableCPU"`
+	AllocatableMemory string `json:"allocatableMemory"`
+	RequestedCPU      string `json:"requestedCPU"`
+	RequestedMemory   string `json:"requestedMemory"`
+
+	AverageCPU    string `json:"averageCPUPerVirtualCluster"`
+	AverageMemory string `json:"averageMemoryPerVirtualCluster"`
+
+	// Remaining is the estimated amount of additional virtual clusters that fit into the host cluster
+	Remaining int `json:"remaining"`
+}
+
+// DensityReportHelm estimates how many more virtual clusters fit into the current host cluster based on
+// the resource requests of the existing virtual clusters and the free allocatable capacity of the nodes.
+func DensityReportHelm(ctx context.Context, options *DensityReportOptions, globalFlags *flags.GlobalFlags, log log.Logger) error {
+	restConfig, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(clientcmd.NewDefaultClientConfigLoadingRules(), &clientcmd.ConfigOverrides{
+		CurrentContext: globalFlags.Context,
+	}).ClientConfig()
+	if err != nil {
+		return err
+	}
+	kubeClient, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+
+	vClusters, err := find.ListVClusters(ctx, globalFlags.Context, "", metav1.NamespaceAll, log.ErrorStreamOnly())
+	if err != nil {
+		return err
+	}
+
+	nodes, err := kubeClient.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("list nodes: %w", err)
+	}
+	pods, err := kubeClient.CoreV1().Pods(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("list pods: %w", err)
+	}
+
+	report := calculateDensityReport(vClusters, nodes.Items, pods.Items)
+	if options.Output == "json" {
+		out, err := json.MarshalIndent(report, "", "    ")
+		if err != nil {
+			return fmt.Errorf("json marshal density report: %w", err)
+		}
+
+		log.WriteString(logrus.InfoLevel, string(out)+"\n")
+		return nil
+	}
+
+	header := []string{"VCLUSTERS", "CPU (REQUESTED/ALLOCATABLE)", "MEMORY (REQUESTED/ALLOCATABLE)", "AVG CPU", "AVG MEMORY", "ESTIMATED REMAINING"}
+	table.PrintTable(log, header, [][]string{{
+		fmt.Sprintf("%d", report.VirtualClusters),
+		report.RequestedCPU + "/" + report.AllocatableCPU,
+		report.RequestedMemory + "/" + report.AllocatableMemory,
+		report.AverageCPU,
+		report.AverageMemory,
+		fmt.Sprintf("%d", report.Remaining),
+	}})
+	if report.VirtualClusters == 0 {
+		log.Info("No virtual clusters found, the estimate requires at least one running virtual cluster")
+	}
+
+	return nil
+}
+
+func calculateDensityReport(vClusters []find.VCluster, nodes []corev1.Node, pods []corev1.Pod) *DensityReport {
+	allocatableCPU, allocatableMemory := resource.Quantity{}, resource.Quantity{}
+	for _, node := range nodes {
+		if node.Spec.Unschedulable {
+			continue
+		}
+
+		allocatableCPU.Add(node.Status.Allocatable[corev1.ResourceCPU])
+		allocatableMemory.Add(node.Status.Allocatable[corev1.ResourceMemory])
+	}
+
+	// index the virtual clusters by namespace and name
+	vClusterMap := map[string]bool{}
+	for _, vCluster := range vClusters {
+		vClusterMap[vCluster.Namespace+"/"+vCluster.Name] = true
+	}
+
+	requestedCPU, requestedMemory := resource.Quantity{}, resource.Quantity{}
+	vClusterCPU, vClusterMemory := resource.Quantity{}, resource.Quantity{}
+	for _, pod := range pods {
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+
+		cpu, memory := podRequests(&pod)
+		requestedCPU.Add(cpu)
+		requestedMemory.Add(memory)
+
+		// control plane pods and workloads synced by the virtual cluster
+		if vClusterMap[pod.Namespace+"/"+pod.Labels["release"]] || vClusterMap[pod.Namespace+"/"+pod.Labels[translate.MarkerLabel]] {
+			vClusterCPU.Add(cpu)
+			vClusterMemory.Add(memory)
+		}
+	}
+
+	report := &DensityReport{
+		VirtualClusters:   len(vClusters),
+		AllocatableCPU:    allocatableCPU.String(),
+		AllocatableMemory: allocatableMemory.String(),
+		RequestedCPU:      requestedCPU.String(),
+		RequestedMemory:   requestedMemory.String(),
+	}
+	if len(vClusters) == 0 {
+		return report
+	}
+
+	averageCPU := vClusterCPU.MilliValue() / int64(len(vClusters))
+	averageMemory := vClusterMemory.Value() / int64(len(vClusters))
+	report.AverageCPU = resource.NewMilliQuantity(averageCPU, resource.DecimalSI).String()
+	report.AverageMemory = resource.NewQuantity(averageMemory, resource.BinarySI).String()
+
+	// the estimate is bound by the scarcer resource
+	remaining := math.MaxInt64
+	if averageCPU > 0 {
+		remaining = min(remaining, int(max(allocatableCPU.MilliValue()-requestedCPU.MilliValue(), 0)/averageCPU))
+	}
+	if averageMemory > 0 {
+		remaining = min(remaining, int(max(allocatableMemory.Value()-requestedMemory.Value(), 0)/averageMemory))
+	}
+	if remaining == math.MaxInt64 {
+		remaining = 0
+	}
+	report.Remaining = remaining
+
+	return report
+}
+
+// podRequests returns the effective cpu and memory requests of the pod
+func podRequests(pod *corev1.Pod) (resource.Quantity, resource.Quantity) {
+	cpu, memory := resource.Quantity{}, resource.Quantity{}
+	for _, container := range pod.Spec.Containers {
+		cpu.Add(container.Resources.Requests[corev1.ResourceCPU])
+		memory.Add(container.Resources.Requests[corev1.ResourceMemory])
+	}
+
+	// init containers run before the other containers, so only the largest request counts
+	for _, container := range pod.Spec.InitContainers {
+		if initCPU := container.Resources.Requests[corev1.ResourceCPU]; initCPU.Cmp(cpu) > 0 {
+			cpu = initCPU.DeepCopy()
+		}
+		if initMemory := container.Resources.Requests[corev1.ResourceMemory]; initMemory.Cmp(memory) > 0 {
+			memory = initMemory.DeepCopy()
+		}
+	}
+
+	return cpu, memory
+}
@@ -0,0 +1,60 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/loft-sh/vcluster/pkg/cli/find"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCalculateDensityReport(t *testing.T) {
+	newPod := func(namespace string, labels map[string]string, cpu, memory string) corev1.Pod {
+		return corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Labels: labels},
+			Spec: corev1.PodSpec{Containers: []corev1.Container{{
+				Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse(cpu),
+					corev1.ResourceMemory: resource.MustParse(memory),
+				}},
+			}}},
+		}
+	}
+
+	nodes := []corev1.Node{
+		{Status: corev1.NodeStatus{Allocatable: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("4"),
+			corev1.ResourceMemory: resource.MustParse("8Gi"),
+		}}},
+		{
+			Spec: corev1.NodeSpec{Unschedulable: true},
+			Status: corev1.NodeStatus{Allocatable: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("4"),
+				corev1.ResourceMemory: resource.MustParse("8Gi"),
+			}},
+		},
+	}
+	pods := []corev1.Pod{
+		newPod("vc-1", map[string]string{"release": "vc-1"}, "200m", "256Mi"),
+		newPod("vc-1", map[string]string{"vcluster.loft.sh/managed-by": "vc-1"}, "100m", "256Mi"),
+		newPod("vc-2", map[string]string{"release": "vc-2"}, "300m", "512Mi"),
+		newPod("other", nil, "1", "1Gi"),
+	}
+	vClusters := []find.VCluster{{Name: "vc-1", Namespace: "vc-1"}, {Name: "vc-2", Namespace: "vc-2"}}
+
+	report := calculateDensityReport(vClusters, nodes, pods)
+	assert.Equal(t, report.VirtualClusters, 2)
+	assert.Equal(t, report.AllocatableCPU, "4")
+	assert.Equal(t, report.RequestedCPU, "1600m")
+	assert.Equal(t, report.AverageCPU, "300m")
+	assert.Equal(t, report.AverageMemory, "512Mi")
+
+	// 2400m free cpu / 300m = 8, 6Gi free memory / 512Mi = 12
+	assert.Equal(t, report.Remaining, 8)
+
+	// no virtual clusters means no estimate
+	report = calculateDensityReport(nil, nodes, pods)
+	assert.Equal(t, report.Remaining, 0)
+}
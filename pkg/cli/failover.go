@@ -0,0 +1,463 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/vcluster/config"
+	"github.com/loft-sh/vcluster/pkg/cli/find"
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/loft-sh/vcluster/pkg/constants"
+	"github.com/loft-sh/vcluster/pkg/helm"
+	"github.com/loft-sh/vcluster/pkg/platform/backup"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// AnnotationStandbyOf marks the control plane of a standby virtual cluster with the context, namespace and name of
+	// the virtual cluster it is a standby of
+	AnnotationStandbyOf = "vcluster.loft.sh/standby-of"
+
+	// AnnotationStandbyReplicas holds the control plane replicas the standby virtual cluster is scaled to on promote
+	AnnotationStandbyReplicas = "vcluster.loft.sh/standby-replicas"
+
+	// AnnotationStandbyChartRepo holds the chart repo the standby was deployed from, so promote uses the same repo
+	AnnotationStandbyChartRepo = "vcluster.loft.sh/standby-chart-repo"
+
+	// AnnotationStandbySnapshot is set if snapshots of the source are replicated to the standby. It holds the location of
+	// the snapshot or is empty if the snapshot is stored in a secret next to the standby.
+	AnnotationStandbySnapshot = "vcluster.loft.sh/standby-snapshot"
+
+	// standbySnapshotKey is the key of the snapshot in the standby snapshot secret
+	standbySnapshotKey = "snapshot.tar.gz"
+
+	// maxStandbySnapshotSecretSize is the size of the largest snapshot that fits into a secret
+	maxStandbySnapshotSecretSize = 1000 * 1024
+)
+
+type FailoverStandbyOptions struct {
+	// TargetContext is the kube context of the cluster the standby is deployed to
+	TargetContext string
+
+	// TargetNamespace is the namespace of the standby, defaults to the namespace of the source virtual cluster
+	TargetNamespace string
+
+	// ChartRepo is the repo of the vcluster chart the standby is deployed from
+	ChartRepo string
+
+	// SnapshotLocation is a local path or presigned url the snapshots of the source are replicated to, defaults to a
+	// secret in the namespace of the standby. Snapshots are only taken if the source doesn't use an external database.
+	SnapshotLocation string
+
+	// SnapshotInterval keeps replicating snapshots of the source in this interval until the command is stopped
+	SnapshotInterval time.Duration
+}
+
+type FailoverPromoteOptions struct {
+	// ChartRepo is the repo of the vcluster chart, defaults to the repo the standby was deployed from
+	ChartRepo string
+
+	RollbackTimeout time.Duration
+}
+
+// standbyInfo is recorded on the standby by FailoverStandby and read back on promote
+type standbyInfo struct {
+	// SourceOf is the context, namespace and name of the source virtual cluster
+	SourceOf string
+
+	ChartRepo string
+
+	// Snapshot is true if snapshots of the source are replicated to SnapshotLocation
+	Snapshot         bool
+	SnapshotLocation string
+}
+
+// FailoverStandby deploys the helm release of the given virtual cluster with the same chart version and config into
+// the target context, scaled to zero. Running it again updates the standby to the current config of the source. If
+// the source doesn't use an external database that is shared with the standby, a snapshot of its objects is
+// replicated to the standby and restored on promote.
+func FailoverStandby(ctx context.Context, globalFlags *flags.GlobalFlags, vClusterName string, options *FailoverStandbyOptions, log log.Logger) error {
+	if options.TargetContext == "" {
+		return fmt.Errorf("please specify the kube context of the standby via --target-context")
+	}
+
+	source, err := find.GetVCluster(ctx, globalFlags.Context, vClusterName, globalFlags.Namespace, log)
+	if err != nil {
+		return err
+	}
+	release, err := vClusterRelease(ctx, source, vClusterName)
+	if err != nil {
+		return err
+	}
+	if isLegacyVCluster(release.Chart.Metadata.Version) {
+		return fmt.Errorf("vcluster %s uses chart version %s, please upgrade it to v0.20 or newer before creating a standby", vClusterName, release.Chart.Metadata.Version)
+	}
+
+	targetNamespace := options.TargetNamespace
+	if targetNamespace == "" {
+		targetNamespace = source.Namespace
+	}
+	if options.TargetContext == source.Context && targetNamespace == source.Namespace {
+		return fmt.Errorf("the standby cannot be deployed into the namespace of the source vcluster, please specify a different --target-context or --target-namespace")
+	}
+
+	values := release.Config
+	if values == nil {
+		values = map[string]interface{}{}
+	}
+	vConfig, err := releaseVClusterConfig(values)
+	if err != nil {
+		return err
+	}
+	chartRepo := options.ChartRepo
+	if chartRepo == "" {
+		chartRepo = constants.LoftChartRepo
+	}
+	replicateSnapshots := !sharedBackingStore(vConfig.BackingStoreType())
+	if options.SnapshotInterval > 0 && !replicateSnapshots {
+		return fmt.Errorf("vcluster %s uses an external database that is shared with the standby, so no snapshots are replicated and --snapshot-interval cannot be used", vClusterName)
+	}
+
+	values, err = standbyValues(values, vConfig, strings.Join([]string{source.Context, source.Namespace, vClusterName}, "/"))
+	if err != nil {
+		return err
+	}
+	annotations := map[string]string{AnnotationStandbyChartRepo: chartRepo}
+	if replicateSnapshots {
+		annotations[AnnotationStandbySnapshot] = options.SnapshotLocation
+	}
+	values, err = withStatefulSetAnnotations(values, annotations)
+	if err != nil {
+		return err
+	}
+
+	targetFlags := *globalFlags
+	targetFlags.Context = options.TargetContext
+	targetFlags.Namespace = targetNamespace
+	err = deployReleaseValues(ctx, &targetFlags, vClusterName, chartRepo, release.Chart.Metadata.Version, values, 0, log)
+	if err != nil {
+		return fmt.Errorf("deploy standby: %w", err)
+	}
+
+	sourceFlags := *globalFlags
+	sourceFlags.Context = source.Context
+	sourceFlags.Namespace = source.Namespace
+	if replicateSnapshots {
+		err = replicateSnapshot(ctx, &sourceFlags, &targetFlags, source, options.SnapshotLocation, log)
+		if err != nil {
+			return err
+		}
+	}
+
+	log.Donef("Successfully deployed standby of vcluster %s into namespace %s of context %s, run `vcluster failover promote %s --context %s -n %s` to activate it", vClusterName, targetNamespace, options.TargetContext, vClusterName, options.TargetContext, targetNamespace)
+
+	for replicateSnapshots && options.SnapshotInterval > 0 {
+		log.Infof("Replicating the next snapshot in %s", options.SnapshotInterval)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(options.SnapshotInterval):
+		}
+
+		// the standby still has the last snapshot, so a failed replication is retried in the next interval
+		err = replicateSnapshot(ctx, &sourceFlags, &targetFlags, source, options.SnapshotLocation, log)
+		if err != nil {
+			log.Errorf("Error replicating snapshot: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// FailoverPromote scales a standby virtual cluster created by FailoverStandby up to the replicas of its source and
+// restores the last replicated snapshot of the source
+func FailoverPromote(ctx context.Context, globalFlags *flags.GlobalFlags, vClusterName string, options *FailoverPromoteOptions, log log.Logger) error {
+	standby, err := find.GetVCluster(ctx, globalFlags.Context, vClusterName, globalFlags.Namespace, log)
+	if err != nil {
+		return err
+	}
+	release, err := vClusterRelease(ctx, standby, vClusterName)
+	if err != nil {
+		return err
+	}
+
+	values, info, err := promotedValues(release.Config)
+	if err != nil {
+		return fmt.Errorf("vcluster %s in namespace %s: %w", vClusterName, standby.Namespace, err)
+	}
+	if info.SourceOf != "" && !info.Snapshot {
+		log.Warnf("Make sure the source vcluster %s is no longer running, otherwise both virtual clusters write to the same backing store", info.SourceOf)
+	}
+	chartRepo := options.ChartRepo
+	if chartRepo == "" {
+		chartRepo = info.ChartRepo
+	}
+	if chartRepo == "" {
+		chartRepo = constants.LoftChartRepo
+	}
+
+	var snapshot []byte
+	if info.Snapshot {
+		kubeClient, err := vClusterKubeClient(standby)
+		if err != nil {
+			return err
+		}
+		snapshot, err = loadStandbySnapshot(ctx, kubeClient, standby.Namespace, vClusterName, info.SnapshotLocation)
+		if err != nil {
+			return fmt.Errorf("load snapshot: %w", err)
+		} else if snapshot == nil {
+			log.Warnf("No snapshot of the source vcluster %s was replicated yet, the promoted vcluster starts without its data", info.SourceOf)
+		}
+	}
+
+	promoteFlags := *globalFlags
+	promoteFlags.Context = standby.Context
+	promoteFlags.Namespace = standby.Namespace
+	err = deployReleaseValues(ctx, &promoteFlags, vClusterName, chartRepo, release.Chart.Metadata.Version, values, options.RollbackTimeout, log)
+	if err != nil {
+		return fmt.Errorf("promote standby: %w", err)
+	}
+
+	if snapshot != nil {
+		log.Infof("Restore the last snapshot of the source vcluster %s...", info.SourceOf)
+		err = restoreVClusterSnapshotWithConnect(ctx, &promoteFlags, vClusterName, snapshot, log)
+		if err != nil {
+			return fmt.Errorf("restore snapshot: %w", err)
+		}
+	}
+
+	log.Donef("Successfully promoted vcluster %s in namespace %s", vClusterName, standby.Namespace)
+	return nil
+}
+
+// standbyValues scales the control plane of the given values to zero and records the replicas and the source of the
+// standby, so promote can restore them
+func standbyValues(values map[string]interface{}, vConfig *config.Config, sourceOf string) (map[string]interface{}, error) {
+	values = runtime.DeepCopyJSON(values)
+	replicas := vConfig.ControlPlane.StatefulSet.HighAvailability.Replicas
+	if replicas < 1 {
+		replicas = 1
+	}
+
+	annotations := map[string]interface{}{}
+	existing, _, _ := unstructured.NestedStringMap(values, "controlPlane", "statefulSet", "annotations")
+	for k, v := range existing {
+		annotations[k] = v
+	}
+	annotations[AnnotationStandbyOf] = sourceOf
+	annotations[AnnotationStandbyReplicas] = strconv.Itoa(int(replicas))
+	err := unstructured.SetNestedMap(values, annotations, "controlPlane", "statefulSet", "annotations")
+	if err != nil {
+		return nil, err
+	}
+	err = unstructured.SetNestedField(values, int64(0), "controlPlane", "statefulSet", "highAvailability", "replicas")
+	if err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
+
+// promotedValues reverts standbyValues and returns what was recorded on the standby
+func promotedValues(values map[string]interface{}) (map[string]interface{}, standbyInfo, error) {
+	sourceReplicas, ok, _ := unstructured.NestedString(values, "controlPlane", "statefulSet", "annotations", AnnotationStandbyReplicas)
+	if !ok {
+		return nil, standbyInfo{}, fmt.Errorf("not a standby")
+	}
+	replicas, err := strconv.ParseInt(sourceReplicas, 10, 64)
+	if err != nil {
+		return nil, standbyInfo{}, fmt.Errorf("parse annotation %s: %w", AnnotationStandbyReplicas, err)
+	}
+	info := standbyInfo{}
+	info.SourceOf, _, _ = unstructured.NestedString(values, "controlPlane", "statefulSet", "annotations", AnnotationStandbyOf)
+	info.ChartRepo, _, _ = unstructured.NestedString(values, "controlPlane", "statefulSet", "annotations", AnnotationStandbyChartRepo)
+	info.SnapshotLocation, info.Snapshot, _ = unstructured.NestedString(values, "controlPlane", "statefulSet", "annotations", AnnotationStandbySnapshot)
+
+	values = runtime.DeepCopyJSON(values)
+	for _, annotation := range []string{AnnotationStandbyOf, AnnotationStandbyReplicas, AnnotationStandbyChartRepo, AnnotationStandbySnapshot} {
+		unstructured.RemoveNestedField(values, "controlPlane", "statefulSet", "annotations", annotation)
+	}
+	if annotations, _, _ := unstructured.NestedMap(values, "controlPlane", "statefulSet", "annotations"); len(annotations) == 0 {
+		unstructured.RemoveNestedField(values, "controlPlane", "statefulSet", "annotations")
+	}
+	err = unstructured.SetNestedField(values, replicas, "controlPlane", "statefulSet", "highAvailability", "replicas")
+	if err != nil {
+		return nil, standbyInfo{}, err
+	}
+
+	return values, info, nil
+}
+
+// withStatefulSetAnnotations adds the annotations to the control plane of the given values
+func withStatefulSetAnnotations(values map[string]interface{}, annotations map[string]string) (map[string]interface{}, error) {
+	values = runtime.DeepCopyJSON(values)
+	for k, v := range annotations {
+		err := unstructured.SetNestedField(values, v, "controlPlane", "statefulSet", "annotations", k)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return values, nil
+}
+
+// replicateSnapshot takes a snapshot of the source and stores it for the standby
+func replicateSnapshot(ctx context.Context, sourceFlags, standbyFlags *flags.GlobalFlags, source *find.VCluster, location string, log log.Logger) error {
+	log.Infof("Snapshot vcluster %s in namespace %s of context %s...", source.Name, source.Namespace, source.Context)
+	snapshot, err := snapshotVClusterWithConnect(ctx, sourceFlags, source, nil, log)
+	if err != nil {
+		return fmt.Errorf("snapshot source: %w", err)
+	}
+
+	standby, err := find.GetVCluster(ctx, standbyFlags.Context, source.Name, standbyFlags.Namespace, log)
+	if err != nil {
+		return err
+	}
+	kubeClient, err := vClusterKubeClient(standby)
+	if err != nil {
+		return err
+	}
+	err = saveStandbySnapshot(ctx, kubeClient, standby.Namespace, source.Name, location, snapshot)
+	if err != nil {
+		return fmt.Errorf("save snapshot: %w", err)
+	}
+
+	log.Donef("Replicated snapshot of vcluster %s to the standby in namespace %s of context %s", source.Name, standby.Namespace, standby.Context)
+	return nil
+}
+
+func standbySnapshotSecretName(vClusterName string) string {
+	return translate.SafeConcatName("vc", "standby", "snapshot", vClusterName)
+}
+
+// saveStandbySnapshot uploads the snapshot to the location or writes it to the standby snapshot secret
+func saveStandbySnapshot(ctx context.Context, kubeClient kubernetes.Interface, namespace, vClusterName, location string, snapshot []byte) error {
+	if location != "" {
+		return backup.Upload(ctx, location, snapshot)
+	} else if len(snapshot) > maxStandbySnapshotSecretSize {
+		return fmt.Errorf("snapshot has %d bytes, which doesn't fit into a secret, please use --snapshot-location", len(snapshot))
+	}
+
+	secret, err := kubeClient.CoreV1().Secrets(namespace).Get(ctx, standbySnapshotSecretName(vClusterName), metav1.GetOptions{})
+	if kerrors.IsNotFound(err) {
+		_, err = kubeClient.CoreV1().Secrets(namespace).Create(ctx, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: standbySnapshotSecretName(vClusterName), Namespace: namespace},
+			Data:       map[string][]byte{standbySnapshotKey: snapshot},
+		}, metav1.CreateOptions{})
+		return err
+	} else if err != nil {
+		return err
+	}
+
+	secret.Data = map[string][]byte{standbySnapshotKey: snapshot}
+	_, err = kubeClient.CoreV1().Secrets(namespace).Update(ctx, secret, metav1.UpdateOptions{})
+	return err
+}
+
+// loadStandbySnapshot returns the snapshot saved by saveStandbySnapshot or nil if there is none yet
+func loadStandbySnapshot(ctx context.Context, kubeClient kubernetes.Interface, namespace, vClusterName, location string) ([]byte, error) {
+	if location != "" {
+		return backup.Download(ctx, location)
+	}
+
+	secret, err := kubeClient.CoreV1().Secrets(namespace).Get(ctx, standbySnapshotSecretName(vClusterName), metav1.GetOptions{})
+	if kerrors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	return secret.Data[standbySnapshotKey], nil
+}
+
+func vClusterKubeClient(vCluster *find.VCluster) (kubernetes.Interface, error) {
+	restConfig, err := vCluster.ClientFactory.ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	return kubernetes.NewForConfig(restConfig)
+}
+
+func vClusterRelease(ctx context.Context, vCluster *find.VCluster, vClusterName string) (*helm.Release, error) {
+	kubeClient, err := vClusterKubeClient(vCluster)
+	if err != nil {
+		return nil, err
+	}
+
+	release, err := helm.NewSecrets(kubeClient).Get(ctx, vClusterName, vCluster.Namespace)
+	if err != nil {
+		return nil, fmt.Errorf("get helm release of vcluster %s: %w", vClusterName, err)
+	} else if release.Chart == nil || release.Chart.Metadata == nil {
+		return nil, fmt.Errorf("helm release of vcluster %s has no chart metadata", vClusterName)
+	}
+
+	return release, nil
+}
+
+// releaseVClusterConfig returns the config of the given release values merged into the default config
+func releaseVClusterConfig(values map[string]interface{}) (*config.Config, error) {
+	raw, err := yaml.Marshal(values)
+	if err != nil {
+		return nil, err
+	}
+
+	vConfig, err := config.NewDefaultConfig()
+	if err != nil {
+		return nil, err
+	}
+	err = yaml.Unmarshal(raw, vConfig)
+	if err != nil {
+		return nil, fmt.Errorf("parse release values: %w", err)
+	}
+
+	return vConfig, nil
+}
+
+// deployReleaseValues installs or upgrades the virtual cluster with exactly the given values and chart version
+func deployReleaseValues(ctx context.Context, globalFlags *flags.GlobalFlags, vClusterName, chartRepo, chartVersion string, values map[string]interface{}, rollbackTimeout time.Duration, log log.Logger) error {
+	raw, err := yaml.Marshal(values)
+	if err != nil {
+		return err
+	}
+
+	valuesFile, err := os.CreateTemp("", "vcluster-failover-*.yaml")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(valuesFile.Name())
+
+	_, err = valuesFile.Write(raw)
+	if err != nil {
+		_ = valuesFile.Close()
+		return err
+	}
+	err = valuesFile.Close()
+	if err != nil {
+		return err
+	}
+
+	return CreateHelm(ctx, &CreateOptions{
+		ChartName:       "vcluster",
+		ChartRepo:       chartRepo,
+		ChartVersion:    chartVersion,
+		Distro:          config.K8SDistro,
+		Values:          []string{valuesFile.Name()},
+		Upgrade:         true,
+		CreateNamespace: true,
+		RollbackTimeout: rollbackTimeout,
+		Output:          "text",
+	}, globalFlags, vClusterName, log)
+}
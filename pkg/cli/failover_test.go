@@ -0,0 +1,85 @@
+package cli
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/loft-sh/vcluster/config"
+	"gotest.tools/v3/assert"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestStandbyValues(t *testing.T) {
+	values := map[string]interface{}{
+		"controlPlane": map[string]interface{}{
+			"statefulSet": map[string]interface{}{
+				"highAvailability": map[string]interface{}{"replicas": float64(3)},
+			},
+		},
+	}
+	vConfig, err := releaseVClusterConfig(values)
+	assert.NilError(t, err)
+
+	standby, err := standbyValues(values, vConfig, "source/vcluster-ns/my-vcluster")
+	assert.NilError(t, err)
+	standbyConfig, err := releaseVClusterConfig(standby)
+	assert.NilError(t, err)
+	assert.Equal(t, standbyConfig.ControlPlane.StatefulSet.HighAvailability.Replicas, int32(0))
+	assert.Equal(t, standbyConfig.ControlPlane.StatefulSet.Annotations[AnnotationStandbyReplicas], "3")
+	assert.Equal(t, standbyConfig.ControlPlane.StatefulSet.Annotations[AnnotationStandbyOf], "source/vcluster-ns/my-vcluster")
+
+	// the source values are not modified
+	assert.Equal(t, vConfig.ControlPlane.StatefulSet.HighAvailability.Replicas, int32(3))
+
+	standby, err = withStatefulSetAnnotations(standby, map[string]string{AnnotationStandbyChartRepo: "oci://ghcr.io/my-org/charts", AnnotationStandbySnapshot: ""})
+	assert.NilError(t, err)
+	promoted, info, err := promotedValues(standby)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, info, standbyInfo{SourceOf: "source/vcluster-ns/my-vcluster", ChartRepo: "oci://ghcr.io/my-org/charts", Snapshot: true})
+	promotedConfig, err := releaseVClusterConfig(promoted)
+	assert.NilError(t, err)
+	assert.Equal(t, promotedConfig.ControlPlane.StatefulSet.HighAvailability.Replicas, int32(3))
+	assert.Equal(t, len(promotedConfig.ControlPlane.StatefulSet.Annotations), 0)
+
+	_, _, err = promotedValues(values)
+	assert.ErrorContains(t, err, "not a standby")
+}
+
+func TestStandbyValuesDefaultReplicas(t *testing.T) {
+	standby, err := standbyValues(map[string]interface{}{}, &config.Config{}, "source/default/my-vcluster")
+	assert.NilError(t, err)
+
+	promoted, _, err := promotedValues(standby)
+	assert.NilError(t, err)
+	promotedConfig, err := releaseVClusterConfig(promoted)
+	assert.NilError(t, err)
+	assert.Equal(t, promotedConfig.ControlPlane.StatefulSet.HighAvailability.Replicas, int32(1))
+}
+
+func TestStandbySnapshot(t *testing.T) {
+	ctx := context.Background()
+	kubeClient := fake.NewSimpleClientset()
+
+	// nothing was replicated yet
+	snapshot, err := loadStandbySnapshot(ctx, kubeClient, "standby-ns", "my-vcluster", "")
+	assert.NilError(t, err)
+	assert.Assert(t, snapshot == nil)
+
+	// the secret is updated with every replication
+	assert.NilError(t, saveStandbySnapshot(ctx, kubeClient, "standby-ns", "my-vcluster", "", []byte("first")))
+	assert.NilError(t, saveStandbySnapshot(ctx, kubeClient, "standby-ns", "my-vcluster", "", []byte("second")))
+	snapshot, err = loadStandbySnapshot(ctx, kubeClient, "standby-ns", "my-vcluster", "")
+	assert.NilError(t, err)
+	assert.Equal(t, string(snapshot), "second")
+
+	err = saveStandbySnapshot(ctx, kubeClient, "standby-ns", "my-vcluster", "", make([]byte, maxStandbySnapshotSecretSize+1))
+	assert.ErrorContains(t, err, "please use --snapshot-location")
+
+	// large snapshots are stored at the location instead
+	location := filepath.Join(t.TempDir(), "snapshot.tar.gz")
+	assert.NilError(t, saveStandbySnapshot(ctx, kubeClient, "standby-ns", "my-vcluster", location, make([]byte, maxStandbySnapshotSecretSize+1)))
+	snapshot, err = loadStandbySnapshot(ctx, kubeClient, "standby-ns", "my-vcluster", location)
+	assert.NilError(t, err)
+	assert.Equal(t, len(snapshot), maxStandbySnapshotSecretSize+1)
+}
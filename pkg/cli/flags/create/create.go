@@ -3,8 +3,10 @@ package create
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/loft-sh/vcluster/pkg/cli"
+	"github.com/loft-sh/vcluster/pkg/cli/presets"
 	"github.com/loft-sh/vcluster/pkg/constants"
 	"github.com/loft-sh/vcluster/pkg/upgrade"
 	"github.com/spf13/cobra"
@@ -14,10 +16,12 @@ func AddCommonFlags(cmd *cobra.Command, options *cli.CreateOptions) {
 	cmd.Flags().StringVar(&options.KubeConfigContextName, "kube-config-context-name", "", "If set, will override the context name of the generated virtual cluster kube config with this name")
 	cmd.Flags().StringVar(&options.ChartVersion, "chart-version", upgrade.GetVersion(), "The virtual cluster chart version to use (e.g. v0.9.1)")
 	cmd.Flags().StringVar(&options.ChartName, "chart-name", "vcluster", "The virtual cluster chart name to use")
-	cmd.Flags().StringVar(&options.ChartRepo, "chart-repo", constants.LoftChartRepo, "The virtual cluster chart repo to use")
+	cmd.Flags().StringVar(&options.ChartRepo, "chart-repo", constants.LoftChartRepo, "The virtual cluster chart repo to use, either a helm repository url or an oci:// registry such as oci://ghcr.io/my-org/charts that is accessed with the docker credentials")
 	cmd.Flags().StringVar(&options.KubernetesVersion, "kubernetes-version", "", "The kubernetes version to use (e.g. v1.20). Patch versions are not supported")
-	cmd.Flags().StringArrayVarP(&options.Values, "values", "f", []string{}, "Path where to load extra helm values from")
+	cmd.Flags().StringArrayVarP(&options.Values, "values", "f", []string{}, "Path where to load extra helm values from, use - to read them from stdin or pass an https:// or oci:// reference to download them")
 	cmd.Flags().StringArrayVar(&options.SetValues, "set", []string{}, "Set values for helm. E.g. --set 'persistence.enabled=true'")
+	cmd.Flags().BoolVar(&options.ExpandEnv, "expand-env", false, "Substitute ${VAR} and ${VAR:-default} environment variables and execute [[ ]] templates in the values files")
+	cmd.Flags().StringSliceVar(&options.Presets, "preset", []string{}, fmt.Sprintf("Curated values to start from, the values of -f and --set take precedence. Can be combined, e.g. --preset ha,isolated. Allowed presets: %s", strings.Join(presets.Names(), ", ")))
 	cmd.Flags().BoolVar(&options.Print, "print", false, "If enabled, prints the context to the console")
 	cmd.Flags().BoolVar(&options.UpdateCurrent, "update-current", true, "If true updates the current kube config")
 	cmd.Flags().BoolVar(&options.CreateContext, "create-context", true, "If the CLI should create a kube context for the space")
@@ -33,10 +37,29 @@ func AddCommonFlags(cmd *cobra.Command, options *cli.CreateOptions) {
 
 func AddHelmFlags(cmd *cobra.Command, options *cli.CreateOptions) {
 	cmd.Flags().BoolVar(&options.CreateNamespace, "create-namespace", true, "If true the namespace will be created if it does not exist")
+	cmd.Flags().StringVar(&options.ImageRegistry, "image-registry", "", "Pull all images deployed by vCluster, such as the syncer, distro, etcd, CoreDNS and init container images, from this registry mirror instead. Shortcut for --set controlPlane.advanced.defaultImageRegistry")
+	cmd.Flags().StringVar(&options.ChartRepoUsername, "repo-username", "", "The username to authenticate against the --chart-repo, defaults to the credentials of the repository in helm.chartRepos of the vcluster cli config")
+	cmd.Flags().StringVar(&options.ChartRepoPassword, "repo-password", "", "The password to authenticate against the --chart-repo")
+	cmd.Flags().StringVar(&options.ChartRepoCAFile, "repo-ca-file", "", "Verify the certificate of the --chart-repo with this certificate authority bundle")
 	cmd.Flags().StringVar(&options.LocalChartDir, "local-chart-dir", "", "The virtual cluster local chart dir to use")
 	cmd.Flags().BoolVar(&options.ExposeLocal, "expose-local", true, "If true and a local Kubernetes distro is detected, will deploy vcluster with a NodePort service. Will be set to false and the passed value will be ignored if --expose is set to true.")
 	cmd.Flags().BoolVar(&options.BackgroundProxy, "background-proxy", true, "Try to use a background-proxy to access the vCluster. Only works if docker is installed and reachable")
 	cmd.Flags().BoolVar(&options.Add, "add", true, "Adds the virtual cluster automatically to the current vCluster platform when using helm driver")
+	cmd.Flags().DurationVar(&options.RollbackTimeout, "rollback-timeout", 0, "If set and upgrading, the command waits for the virtual cluster to become ready within this duration and rolls the release and its config back to the previous revision otherwise. Disabled by default")
+	cmd.Flags().BoolVar(&options.Canary, "canary", false, "If upgrading a virtual cluster with multiple replicas, upgrade a single replica first and only continue with the others if it stays healthy")
+	cmd.Flags().DurationVar(&options.CanaryDuration, "canary-duration", 2*time.Minute, "How long the canary replica has to stay healthy before the remaining replicas are upgraded")
+	cmd.Flags().BoolVar(&options.AdoptRelease, "adopt-release", false, "If upgrading and the helm release values were changed outside of the vcluster CLI, use the current release values as base instead of replacing them")
+	cmd.Flags().StringVar(&options.RegisterArgoCD, "register-argocd", "", "If set, registers the virtual cluster as cluster in the Argo CD instance of the given host cluster namespace, e.g. argocd")
+	cmd.Flags().StringVar(&options.FluxBootstrap, "flux-bootstrap", "", "If set, installs flux within the virtual cluster and syncs it with the given git repository url")
+	cmd.Flags().StringVar(&options.FluxBranch, "flux-branch", "main", "The branch of the --flux-bootstrap repository flux should sync")
+	cmd.Flags().StringVar(&options.FluxPath, "flux-path", "./", "The path within the --flux-bootstrap repository flux should sync")
+	cmd.Flags().StringVar(&options.Output, "output", "text", "Choose the format of the output. [text|json]. With json a summary of the deployed release is printed to stdout and all other messages are written to stderr")
+	cmd.Flags().BoolVar(&options.Headless, "headless", false, "If true will only deploy the workload resources of an isolated control plane into the current cluster, the control plane itself needs to run in another cluster")
+	cmd.Flags().BoolVar(&options.Verify, "verify", false, "If true will run a smoke test after the virtual cluster was created, which checks the api server, pod scheduling, DNS and service connectivity with temporary probe pods. Failed checks are only reported as warnings and upgrades are not verified")
+	cmd.Flags().StringVar(&options.VerifyServerImage, "verify-server-image", "", "The image of the probe server pod started by --verify, defaults to "+cli.DefaultVerifyServerImage+" prefixed with the default image registry of the vcluster")
+	cmd.Flags().StringVar(&options.VerifyClientImage, "verify-client-image", "", "The image of the probe client pod started by --verify, defaults to "+cli.DefaultVerifyClientImage+" prefixed with the default image registry of the vcluster")
+	cmd.Flags().StringVar(&options.Policy, "policy", "", fmt.Sprintf("Policy the merged values are validated against before deploying, either a local file, an https:// or oci:// reference or configmap://<namespace>/<name> in the host cluster. Defaults to $%s", cli.PolicyEnv))
+	cmd.Flags().StringSliceVar(&options.PolicyOverrideTokens, "policy-override-token", []string{}, "Tokens that exempt the values from policy rules, the policy lists the sha256 checksum of each token together with the rules it exempts")
 
 	_ = cmd.Flags().MarkHidden("local-chart-dir")
 	_ = cmd.Flags().MarkHidden("expose-local")
@@ -63,4 +86,5 @@ func AddPlatformFlags(cmd *cobra.Command, options *cli.CreateOptions, prefixes .
 	cmd.Flags().BoolVar(&options.UseExisting, "use", false, fmt.Sprintf("%sIf the platform should use the virtual cluster if its already there", prefix))
 	cmd.Flags().BoolVar(&options.Recreate, "recreate", false, fmt.Sprintf("%sIf enabled and there already exists a virtual cluster with this name, it will be deleted first", prefix))
 	cmd.Flags().BoolVar(&options.SkipWait, "skip-wait", false, fmt.Sprintf("%sIf true, will not wait until the virtual cluster is running", prefix))
+	cmd.Flags().BoolVar(&options.Preview, "preview", false, fmt.Sprintf("%sIf a template is used, print the rendered vcluster.yaml and virtual cluster instance without creating it", prefix))
 }
@@ -17,8 +17,11 @@ func AddHelmFlags(cmd *cobra.Command, options *cli.DeleteOptions) {
 	cmd.Flags().BoolVar(&options.DeleteConfigMap, "delete-configmap", false, "If enabled, vCluster will delete the ConfigMap of the vCluster")
 	cmd.Flags().BoolVar(&options.KeepPVC, "keep-pvc", false, "If enabled, vcluster will not delete the persistent volume claim of the vcluster")
 	cmd.Flags().BoolVar(&options.DeleteNamespace, "delete-namespace", false, "If enabled, vcluster will delete the namespace of the vcluster. In the case of multi-namespace mode, will also delete all other namespaces created by vcluster")
+	cmd.Flags().BoolVar(&options.DeleteSharedNamespace, "delete-shared-namespace", false, "If enabled together with --delete-namespace, vcluster will delete the namespace of the vcluster without asking, even if it was not created by vcluster")
 	cmd.Flags().BoolVar(&options.AutoDeleteNamespace, "auto-delete-namespace", true, "If enabled, vcluster will delete the namespace of the vcluster if it was created by vclusterctl. In the case of multi-namespace mode, will also delete all other namespaces created by vcluster")
 	cmd.Flags().BoolVar(&options.IgnoreNotFound, "ignore-not-found", false, "If enabled, vcluster will not error out in case the target vcluster does not exist")
+	cmd.Flags().BoolVar(&options.ForceCleanup, "force-cleanup", false, "If enabled, vcluster will delete all host objects of the vcluster and remove their finalizers, even if the helm uninstall fails or the release is already gone")
+	cmd.Flags().BoolVar(&options.CleanupPVCs, "cleanup-pvcs", false, "If enabled together with --force-cleanup, vcluster will also delete the persistent volume claims synced by the vcluster")
 }
 
 func AddPlatformFlags(cmd *cobra.Command, options *cli.DeleteOptions, prefixes ...string) {
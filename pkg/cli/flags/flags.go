@@ -30,7 +30,7 @@ func SetGlobalFlags(flags *flag.FlagSet, log log.Logger) *GlobalFlags {
 		log.Fatalf("failed to get vcluster configuration file path: %w", err)
 	}
 
-	flags.BoolVar(&globalFlags.Debug, "debug", false, "Prints the stack trace if an error occurs")
+	flags.BoolVar(&globalFlags.Debug, "debug", false, "Prints the stack trace if an error occurs and records the helm invocations of create in a debug file with redacted secrets")
 	flags.StringVar(&globalFlags.Config, "config", defaultConfigPath, "The vcluster CLI config to use (will be created if it does not exist)")
 	flags.StringVar(&globalFlags.Context, "context", "", "The kubernetes config context to use")
 	flags.StringVarP(&globalFlags.Namespace, "namespace", "n", "", "The kubernetes namespace to use")
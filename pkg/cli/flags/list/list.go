@@ -0,0 +1,25 @@
+package list
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/loft-sh/vcluster/pkg/cli"
+	"github.com/spf13/cobra"
+)
+
+func AddCommonFlags(cmd *cobra.Command, options *cli.ListOptions) {
+	cmd.Flags().StringVar(&options.Output, "output", "table", "Choose the format of the output. [table|json]")
+	cmd.Flags().StringSliceVar(&options.Status, "status", nil, "Only list virtual clusters with one of these statuses, e.g. Running or Paused")
+	cmd.Flags().BoolVar(&options.Sleeping, "sleeping", false, "Only list virtual clusters that are sleeping or paused")
+	cmd.Flags().BoolVar(&options.Awake, "awake", false, "Only list virtual clusters that are neither sleeping nor paused")
+	cmd.Flags().StringVar(&options.SortBy, "sort-by", "", fmt.Sprintf("Sort the virtual clusters by this column. [%s]", strings.Join(cli.ListSortColumns, "|")))
+}
+
+func AddPlatformFlags(cmd *cobra.Command, options *cli.ListOptions, prefixes ...string) {
+	prefix := strings.Join(prefixes, "")
+
+	cmd.Flags().StringVar(&options.Project, "project", "", fmt.Sprintf("%sOnly list virtual clusters in this vCluster platform project", prefix))
+	cmd.Flags().StringVar(&options.Cluster, "cluster", "", fmt.Sprintf("%sOnly list virtual clusters running in this vCluster platform cluster", prefix))
+	cmd.Flags().StringVar(&options.Template, "template", "", fmt.Sprintf("%sOnly list virtual clusters created from this vCluster platform template", prefix))
+}
@@ -0,0 +1,275 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/log/table"
+	"github.com/loft-sh/vcluster/pkg/cli/find"
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	"github.com/sirupsen/logrus"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+const (
+	OrphanClusterHost    = "host"
+	OrphanClusterVirtual = "virtual"
+)
+
+// virtual objects of these resources are always synced, so a missing host object means the syncer failed
+var gcVirtualResources = []schema.GroupVersionResource{
+	{Version: "v1", Resource: "pods"},
+	{Version: "v1", Resource: "services"},
+	{Version: "v1", Resource: "persistentvolumeclaims"},
+}
+
+// virtual objects that were created recently might not be synced yet
+const gcMinVirtualAge = time.Minute
+
+type GCOptions struct {
+	Output string
+
+	// DryRun only reports the orphaned objects
+	DryRun bool
+}
+
+// Orphan is an object of the host or virtual cluster whose counterpart in the other cluster is missing
+type Orphan struct {
+	Cluster   string `json:"cluster"`
+	Resource  string `json:"resource"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Reason    string `json:"reason"`
+
+	gvr schema.GroupVersionResource
+}
+
+// GC finds the host objects of the virtual cluster whose virtual object no longer exists and deletes them. Virtual
+// pods, services and persistent volume claims without a host object are reported, the syncer recreates those.
+func GC(ctx context.Context, options *GCOptions, globalFlags *flags.GlobalFlags, vClusterName string, log log.Logger) error {
+	vCluster, err := find.GetVCluster(ctx, globalFlags.Context, vClusterName, globalFlags.Namespace, log)
+	if err != nil {
+		return err
+	}
+
+	connectCmd := &connectHelm{
+		GlobalFlags:    globalFlags,
+		ConnectOptions: &ConnectOptions{},
+		Log:            log,
+	}
+	err = connectCmd.prepare(ctx, vCluster)
+	if err != nil {
+		return err
+	}
+
+	kubeConfig, err := connectCmd.getVClusterKubeConfig(ctx, vCluster.Name, []string{"vcluster", "gc"})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		close(connectCmd.interruptChan)
+		<-connectCmd.errorChan
+	}()
+	err = connectCmd.waitForVCluster(ctx, *kubeConfig, connectCmd.errorChan)
+	if err != nil {
+		return err
+	}
+
+	vRestConfig, err := clientcmd.NewDefaultClientConfig(getLocalVClusterConfig(*kubeConfig, connectCmd.ConnectOptions), &clientcmd.ConfigOverrides{}).ClientConfig()
+	if err != nil {
+		return fmt.Errorf("create virtual rest config: %w", err)
+	}
+	vDiscoveryClient, err := discovery.NewDiscoveryClientForConfig(vRestConfig)
+	if err != nil {
+		return err
+	}
+	vDynamicClient, err := dynamic.NewForConfig(vRestConfig)
+	if err != nil {
+		return err
+	}
+	hostDynamicClient, err := dynamic.NewForConfig(connectCmd.restConfig)
+	if err != nil {
+		return err
+	}
+
+	resources, err := gcResources(connectCmd.kubeClient.Discovery(), vDiscoveryClient)
+	if err != nil {
+		return err
+	}
+
+	orphans, err := findOrphans(ctx, hostDynamicClient, vDynamicClient, resources, vCluster.Namespace, vCluster.Name, time.Now())
+	if err != nil {
+		return err
+	}
+
+	deleted := 0
+	if !options.DryRun {
+		for _, orphan := range orphans {
+			if orphan.Cluster != OrphanClusterHost {
+				continue
+			}
+
+			propagation := metav1.DeletePropagationBackground
+			err = hostDynamicClient.Resource(orphan.gvr).Namespace(orphan.Namespace).Delete(ctx, orphan.Name, metav1.DeleteOptions{PropagationPolicy: &propagation})
+			if err != nil && !kerrors.IsNotFound(err) {
+				return fmt.Errorf("delete %s %s/%s: %w", orphan.Resource, orphan.Namespace, orphan.Name, err)
+			}
+
+			log.Debugf("Deleted orphaned host %s %s/%s", orphan.Resource, orphan.Namespace, orphan.Name)
+			deleted++
+		}
+	}
+
+	if options.Output == "json" {
+		out, err := json.MarshalIndent(orphans, "", "    ")
+		if err != nil {
+			return err
+		}
+
+		log.WriteString(logrus.InfoLevel, string(out)+"\n")
+		return nil
+	}
+
+	if len(orphans) == 0 {
+		log.Infof("No orphaned objects found for vcluster %s", vCluster.Name)
+		return nil
+	}
+
+	rows := [][]string{}
+	for _, orphan := range orphans {
+		rows = append(rows, []string{orphan.Cluster, orphan.Resource, orphan.Namespace, orphan.Name, orphan.Reason})
+	}
+	table.PrintTable(log, []string{"CLUSTER", "RESOURCE", "NAMESPACE", "NAME", "REASON"}, rows)
+	if options.DryRun && slices.ContainsFunc(orphans, isHostOrphan) {
+		log.Infof("Run without --dry-run to delete the orphaned host objects")
+	} else if deleted > 0 {
+		log.Donef("Deleted %d orphaned host objects of vcluster %s", deleted, vCluster.Name)
+	}
+
+	return nil
+}
+
+// gcResources returns the namespaced resources that can be listed and deleted in the host cluster and that exist in
+// the virtual cluster as well
+func gcResources(hostDiscovery, virtualDiscovery discovery.DiscoveryInterface) ([]metav1.APIResource, error) {
+	hostResources, err := deletableResources(hostDiscovery, true)
+	if err != nil {
+		return nil, err
+	}
+	virtualResources, err := deletableResources(virtualDiscovery, true)
+	if err != nil {
+		return nil, err
+	}
+
+	resources := []metav1.APIResource{}
+	for _, resource := range hostResources {
+		if !slices.Contains(resource.Verbs, "delete") {
+			continue
+		}
+
+		if slices.ContainsFunc(virtualResources, func(virtualResource metav1.APIResource) bool {
+			return virtualResource.Group == resource.Group && virtualResource.Version == resource.Version && virtualResource.Name == resource.Name
+		}) {
+			resources = append(resources, resource)
+		}
+	}
+
+	return resources, nil
+}
+
+// findOrphans compares the host objects labeled as managed by the virtual cluster with the virtual objects they were
+// synced from. Host objects are listed before the virtual objects, so objects synced in between are not reported.
+func findOrphans(ctx context.Context, hostClient, virtualClient dynamic.Interface, resources []metav1.APIResource, hostNamespace, vClusterName string, now time.Time) ([]Orphan, error) {
+	orphans := []Orphan{}
+	for _, resource := range resources {
+		gvr := schema.GroupVersionResource{Group: resource.Group, Version: resource.Version, Resource: resource.Name}
+		hostList, err := hostClient.Resource(gvr).Namespace(hostNamespace).List(ctx, metav1.ListOptions{LabelSelector: translate.MarkerLabel + "=" + vClusterName})
+		if err != nil {
+			// skip resources that are gone or that we are not allowed to list
+			if kerrors.IsNotFound(err) || kerrors.IsForbidden(err) || kerrors.IsMethodNotSupported(err) {
+				continue
+			}
+
+			return nil, fmt.Errorf("list host %s: %w", gvr.GroupResource().String(), err)
+		}
+		virtualList, err := virtualClient.Resource(gvr).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			if kerrors.IsNotFound(err) || kerrors.IsForbidden(err) || kerrors.IsMethodNotSupported(err) {
+				continue
+			}
+
+			return nil, fmt.Errorf("list virtual %s: %w", gvr.GroupResource().String(), err)
+		}
+
+		virtualUIDs := map[string]string{}
+		for _, vObj := range virtualList.Items {
+			virtualUIDs[vObj.GetNamespace()+"/"+vObj.GetName()] = string(vObj.GetUID())
+		}
+
+		// host objects without a virtual object
+		synced := map[string]bool{}
+		for _, pObj := range hostList.Items {
+			annotations := pObj.GetAnnotations()
+			if annotations[translate.NameAnnotation] == "" || pObj.GetDeletionTimestamp() != nil {
+				continue
+			}
+
+			key := annotations[translate.NamespaceAnnotation] + "/" + annotations[translate.NameAnnotation]
+			synced[key] = true
+			uid, ok := virtualUIDs[key]
+			if !ok {
+				orphans = append(orphans, newOrphan(OrphanClusterHost, gvr, &pObj, fmt.Sprintf("virtual object %s does not exist", key)))
+			} else if annotations[translate.UIDAnnotation] != "" && annotations[translate.UIDAnnotation] != uid {
+				orphans = append(orphans, newOrphan(OrphanClusterHost, gvr, &pObj, fmt.Sprintf("virtual object %s was recreated", key)))
+			}
+		}
+
+		// virtual objects without a host object
+		if !slices.Contains(gcVirtualResources, gvr) {
+			continue
+		}
+		for _, vObj := range virtualList.Items {
+			if synced[vObj.GetNamespace()+"/"+vObj.GetName()] || vObj.GetDeletionTimestamp() != nil || now.Sub(vObj.GetCreationTimestamp().Time) < gcMinVirtualAge {
+				continue
+			} else if gvr.Resource == "services" && vObj.GetNamespace() == "default" && vObj.GetName() == "kubernetes" {
+				// the kubernetes service points to the vcluster service and is never synced
+				continue
+			}
+
+			orphans = append(orphans, newOrphan(OrphanClusterVirtual, gvr, &vObj, "host object does not exist"))
+		}
+	}
+
+	sort.SliceStable(orphans, func(i, j int) bool {
+		return strings.Join([]string{orphans[i].Cluster, orphans[i].Resource, orphans[i].Namespace, orphans[i].Name}, "/") < strings.Join([]string{orphans[j].Cluster, orphans[j].Resource, orphans[j].Namespace, orphans[j].Name}, "/")
+	})
+	return orphans, nil
+}
+
+func newOrphan(cluster string, gvr schema.GroupVersionResource, obj *unstructured.Unstructured, reason string) Orphan {
+	return Orphan{
+		Cluster:   cluster,
+		Resource:  gvr.GroupResource().String(),
+		Namespace: obj.GetNamespace(),
+		Name:      obj.GetName(),
+		Reason:    reason,
+		gvr:       gvr,
+	}
+}
+
+func isHostOrphan(orphan Orphan) bool {
+	return orphan.Cluster == OrphanClusterHost
+}
@@ -0,0 +1,59 @@
+package cli
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+func TestFindOrphans(t *testing.T) {
+	now := time.Now()
+	hostService := func(name, vName, vUID string) *corev1.Service {
+		return &corev1.Service{ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   "vcluster",
+			Labels:      map[string]string{translate.MarkerLabel: "my-vcluster"},
+			Annotations: map[string]string{translate.NameAnnotation: vName, translate.NamespaceAnnotation: "default", translate.UIDAnnotation: vUID},
+		}}
+	}
+	virtualService := func(name, uid string, age time.Duration) *corev1.Service {
+		return &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", UID: types.UID(uid), CreationTimestamp: metav1.NewTime(now.Add(-age))}}
+	}
+
+	hostClient := dynamicfake.NewSimpleDynamicClient(scheme.Scheme,
+		hostService("synced-x-default-x-my-vcluster", "synced", "1"),
+		hostService("deleted-x-default-x-my-vcluster", "deleted", "2"),
+		hostService("recreated-x-default-x-my-vcluster", "recreated", "3"),
+		// objects of other virtual clusters and objects that were not synced are ignored
+		&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "vcluster", Labels: map[string]string{translate.MarkerLabel: "other"}, Annotations: map[string]string{translate.NameAnnotation: "other"}}},
+		&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "my-vcluster", Namespace: "vcluster", Labels: map[string]string{translate.MarkerLabel: "my-vcluster"}}},
+	)
+	virtualClient := dynamicfake.NewSimpleDynamicClient(scheme.Scheme,
+		virtualService("synced", "1", time.Hour),
+		virtualService("recreated", "4", time.Hour),
+		virtualService("unsynced", "5", time.Hour),
+		virtualService("new", "6", time.Second),
+		virtualService("kubernetes", "7", time.Hour),
+	)
+	resources := []metav1.APIResource{{Name: "services", Version: "v1", Namespaced: true}}
+
+	orphans, err := findOrphans(context.Background(), hostClient, virtualClient, resources, "vcluster", "my-vcluster", now)
+	assert.NilError(t, err)
+	assert.Equal(t, len(orphans), 3)
+	assert.Equal(t, orphans[0].Cluster, OrphanClusterHost)
+	assert.Equal(t, orphans[0].Name, "deleted-x-default-x-my-vcluster")
+	assert.Equal(t, orphans[0].Reason, "virtual object default/deleted does not exist")
+	assert.Equal(t, orphans[1].Name, "recreated-x-default-x-my-vcluster")
+	assert.Equal(t, orphans[1].Reason, "virtual object default/recreated was recreated")
+	assert.Equal(t, orphans[2].Cluster, OrphanClusterVirtual)
+	assert.Equal(t, orphans[2].Name, "unsynced")
+	assert.Equal(t, orphans[2].Resource, "services")
+}
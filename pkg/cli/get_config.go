@@ -0,0 +1,102 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/vcluster/pkg/cli/find"
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/yaml"
+)
+
+type GetConfigOptions struct {
+	Output string
+
+	// Resolved prints the config including all defaults as used by the virtual cluster instead of the user values
+	Resolved bool
+}
+
+// GetConfig prints the values the virtual cluster was deployed with from its helm release or, with Resolved, the
+// config from its vc-config secret that includes the chart defaults
+func GetConfig(ctx context.Context, globalFlags *flags.GlobalFlags, vClusterName string, options *GetConfigOptions, log log.Logger) error {
+	if options.Output != "yaml" && options.Output != "json" {
+		return fmt.Errorf("unsupported output %s, please use yaml or json", options.Output)
+	}
+
+	vCluster, err := find.GetVCluster(ctx, globalFlags.Context, vClusterName, globalFlags.Namespace, log)
+	if err != nil {
+		return err
+	}
+
+	var values map[string]interface{}
+	if options.Resolved {
+		values, err = resolvedVClusterConfig(ctx, vCluster)
+		if err != nil {
+			return err
+		}
+	} else {
+		release, err := vClusterRelease(ctx, vCluster, vClusterName)
+		if err != nil {
+			return err
+		}
+
+		values = release.Config
+	}
+
+	out, err := formatConfig(values, options.Output)
+	if err != nil {
+		return err
+	}
+
+	log.WriteString(logrus.InfoLevel, string(out))
+	return nil
+}
+
+func resolvedVClusterConfig(ctx context.Context, vCluster *find.VCluster) (map[string]interface{}, error) {
+	restConfig, err := vCluster.ClientFactory.ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+	kubeClient, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := kubeClient.CoreV1().Secrets(vCluster.Namespace).Get(ctx, "vc-config-"+vCluster.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("get config secret of vcluster %s: %w", vCluster.Name, err)
+	}
+	raw, ok := secret.Data["config.yaml"]
+	if !ok {
+		return nil, fmt.Errorf("config secret of vcluster %s has no config.yaml", vCluster.Name)
+	}
+
+	values := map[string]interface{}{}
+	err = yaml.Unmarshal(raw, &values)
+	if err != nil {
+		return nil, fmt.Errorf("parse config of vcluster %s: %w", vCluster.Name, err)
+	}
+
+	return values, nil
+}
+
+func formatConfig(values map[string]interface{}, output string) ([]byte, error) {
+	if values == nil {
+		values = map[string]interface{}{}
+	}
+	if output == "json" {
+		out, err := json.MarshalIndent(values, "", "    ")
+		if err != nil {
+			return nil, err
+		}
+
+		return append(out, '\n'), nil
+	}
+
+	return yaml.Marshal(values)
+}
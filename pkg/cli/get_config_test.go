@@ -0,0 +1,43 @@
+package cli
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestFormatConfig(t *testing.T) {
+	values := map[string]interface{}{
+		"sync": map[string]interface{}{
+			"toHost": map[string]interface{}{
+				"ingresses": map[string]interface{}{"enabled": true},
+			},
+		},
+	}
+
+	out, err := formatConfig(values, "yaml")
+	assert.NilError(t, err)
+	assert.Equal(t, string(out), `sync:
+  toHost:
+    ingresses:
+      enabled: true
+`)
+
+	out, err = formatConfig(values, "json")
+	assert.NilError(t, err)
+	assert.Equal(t, string(out), `{
+    "sync": {
+        "toHost": {
+            "ingresses": {
+                "enabled": true
+            }
+        }
+    }
+}
+`)
+
+	// a release without user values is printed as an empty config
+	out, err = formatConfig(nil, "yaml")
+	assert.NilError(t, err)
+	assert.Equal(t, string(out), "{}\n")
+}
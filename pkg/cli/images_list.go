@@ -0,0 +1,395 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/log/table"
+	"github.com/loft-sh/vcluster/config"
+	"github.com/loft-sh/vcluster/pkg/cli/cleanup"
+	"github.com/loft-sh/vcluster/pkg/constants"
+	"github.com/loft-sh/vcluster/pkg/coredns"
+	"github.com/loft-sh/vcluster/pkg/helm"
+	"github.com/loft-sh/vcluster/pkg/util"
+	"github.com/loft-sh/vcluster/pkg/util/helmdownloader"
+	"github.com/sirupsen/logrus"
+	kyaml "k8s.io/apimachinery/pkg/util/yaml"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// imageManifestMediaTypes are accepted when resolving the digest of an image, multi arch images resolve to the digest
+// of their index
+var imageManifestMediaTypes = []string{
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+}
+
+// containerKeys are the fields of a pod spec that hold containers with images
+var containerKeys = []string{"containers", "initContainers", "ephemeralContainers"}
+
+type ImagesListOptions struct {
+	ChartVersion      string
+	ChartName         string
+	ChartRepo         string
+	LocalChartDir     string
+	Distro            string
+	KubernetesVersion string
+	Values            []string
+	SetValues         []string
+	ExpandEnv         bool
+
+	// Output is the output format, either table, json or cyclonedx
+	Output string
+
+	// SkipDigests only lists the images without resolving their digests in the registries
+	SkipDigests bool
+}
+
+// Image is a container image the virtual cluster deploys
+type Image struct {
+	Image   string   `json:"image"`
+	Digest  string   `json:"digest,omitempty"`
+	Sources []string `json:"sources"`
+}
+
+// ImagesList renders the chart with the given values and prints every image it deploys, including the images the
+// virtual cluster deploys itself such as coredns
+func ImagesList(ctx context.Context, options *ImagesListOptions, log log.Logger) error {
+	if options.Output != "table" && options.Output != "json" && options.Output != "cyclonedx" {
+		return fmt.Errorf("unsupported output format %s, please use table, json or cyclonedx", options.Output)
+	} else if !util.Contains(options.Distro, AllowedDistros) {
+		return fmt.Errorf("unsupported distro %s, please select one of: %s", options.Distro, strings.Join(AllowedDistros, ", "))
+	}
+
+	values, cleanupValues, err := resolveValuesFiles(ctx, options.Values, options.ExpandEnv)
+	if err != nil {
+		return err
+	}
+	defer cleanupValues()
+	options.Values = values
+
+	manifests, vClusterConfig, err := renderChart(ctx, options, log)
+	if err != nil {
+		return err
+	}
+
+	images, err := imagesFromManifests(manifests, coreDNSImage(vClusterConfig, options.KubernetesVersion))
+	if err != nil {
+		return err
+	}
+
+	if !options.SkipDigests {
+		for i := range images {
+			images[i].Digest, err = resolveDigest(ctx, images[i].Image, http.DefaultClient, dockerCredentials)
+			if err != nil {
+				log.Warnf("Error resolving digest of image %s: %v", images[i].Image, err)
+			}
+		}
+	}
+
+	switch options.Output {
+	case "json":
+		out, err := json.MarshalIndent(images, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		log.WriteString(logrus.InfoLevel, string(out)+"\n")
+	case "cyclonedx":
+		out, err := json.MarshalIndent(cycloneDXBOM(images, options.ChartName, options.ChartVersion), "", "  ")
+		if err != nil {
+			return err
+		}
+
+		log.WriteString(logrus.InfoLevel, string(out)+"\n")
+	default:
+		rows := make([][]string, 0, len(images))
+		for _, image := range images {
+			rows = append(rows, []string{image.Image, image.Digest, strings.Join(image.Sources, ", ")})
+		}
+
+		table.PrintTable(log, []string{"IMAGE", "DIGEST", "SOURCE"}, rows)
+	}
+
+	return nil
+}
+
+// renderChart renders the chart via helm template and returns the manifests as well as the merged config
+func renderChart(ctx context.Context, options *ImagesListOptions, log log.Logger) ([]byte, *config.Config, error) {
+	extraValuesOptions := &config.ExtraValuesOptions{Distro: options.Distro}
+	kubeVersion := ""
+	if options.KubernetesVersion != "" {
+		parsedVersion, err := config.ParseKubernetesVersionInfo(options.KubernetesVersion)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		extraValuesOptions.KubernetesVersion = *parsedVersion
+		kubeVersion = fmt.Sprintf("v%s.%s.0", parsedVersion.Major, parsedVersion.Minor)
+	}
+	chartValues, err := config.GetExtraValues(extraValuesOptions)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	finalValues, err := mergeAllValues(options.SetValues, options.Values, chartValues)
+	if err != nil {
+		return nil, nil, fmt.Errorf("merge values: %w", err)
+	}
+	vClusterConfig := &config.Config{}
+	err = vClusterConfig.UnmarshalYAMLStrict([]byte(finalValues))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	chartValuesFile, chartValuesCleanup, err := cleanup.TempFile("vcluster-chart-values-*.yaml")
+	if err != nil {
+		return nil, nil, err
+	}
+	defer func() {
+		_ = chartValuesCleanup.Run()
+	}()
+	_, err = chartValuesFile.WriteString(chartValues)
+	if err != nil {
+		return nil, nil, err
+	}
+	err = chartValuesFile.Close()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	chartPath := options.LocalChartDir
+	if chartPath == "" {
+		localPath, chartCleanup := localChartPath(ctx, options.ChartName, options.ChartVersion, options.ChartRepo, dockerCredentials, log)
+		if chartCleanup != nil {
+			defer func() {
+				_ = chartCleanup.Run()
+			}()
+		}
+		chartPath = localPath
+	}
+
+	helmBinaryPath, err := helmdownloader.GetHelmBinaryPath(ctx, log)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	manifests, err := helm.NewClient(&clientcmdapi.Config{}, log, helmBinaryPath).Template(ctx, "vcluster", "vcluster", helm.UpgradeOptions{
+		Chart:       options.ChartName,
+		Repo:        options.ChartRepo,
+		Version:     options.ChartVersion,
+		Path:        chartPath,
+		KubeVersion: kubeVersion,
+		ValuesFiles: append([]string{chartValuesFile.Name()}, options.Values...),
+		SetValues:   options.SetValues,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return manifests, vClusterConfig, nil
+}
+
+// coreDNSImage returns the image the virtual cluster deploys coredns with if the chart doesn't specify one
+func coreDNSImage(vClusterConfig *config.Config, kubernetesVersion string) string {
+	image := coredns.DefaultImage
+	if kubernetesVersion != "" {
+		parsedVersion, err := config.ParseKubernetesVersionInfo(kubernetesVersion)
+		if err == nil && constants.CoreDNSVersionMap[parsedVersion.Major+"."+parsedVersion.Minor] != "" {
+			image = constants.CoreDNSVersionMap[parsedVersion.Major+"."+parsedVersion.Minor]
+		}
+	}
+	return config.WithDefaultImageRegistry(vClusterConfig.ControlPlane.Advanced.DefaultImageRegistry, image)
+}
+
+// imagesFromManifests returns the sorted images of all containers within the manifests. The coredns manifests stored
+// within a configmap are included, their image placeholder is replaced with the given coredns image.
+func imagesFromManifests(manifests []byte, defaultCoreDNSImage string) ([]Image, error) {
+	sources := map[string][]string{}
+	err := collectManifestImages(manifests, "", defaultCoreDNSImage, sources)
+	if err != nil {
+		return nil, err
+	}
+
+	images := make([]Image, 0, len(sources))
+	for image, imageSources := range sources {
+		images = append(images, Image{Image: image, Sources: imageSources})
+	}
+	sort.Slice(images, func(i, j int) bool {
+		return images[i].Image < images[j].Image
+	})
+	return images, nil
+}
+
+func collectManifestImages(manifests []byte, parent, defaultCoreDNSImage string, sources map[string][]string) error {
+	decoder := kyaml.NewYAMLOrJSONDecoder(bytes.NewReader(manifests), 4096)
+	for {
+		obj := map[string]interface{}{}
+		err := decoder.Decode(&obj)
+		if errors.Is(err, io.EOF) {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("parse manifests: %w", err)
+		} else if len(obj) == 0 {
+			continue
+		}
+
+		kind, _ := obj["kind"].(string)
+		metadata, _ := obj["metadata"].(map[string]interface{})
+		name, _ := metadata["name"].(string)
+		source := kind + "/" + name
+		if parent != "" {
+			source = parent + " " + source
+		}
+
+		collectContainerImages(obj, source, sources)
+
+		// the virtual cluster applies these manifests itself
+		data, _ := obj["data"].(map[string]interface{})
+		if coreDNSManifests, ok := data["coredns.yaml"].(string); ok && kind == "ConfigMap" {
+			coreDNSManifests = strings.ReplaceAll(coreDNSManifests, "{{.IMAGE}}", defaultCoreDNSImage)
+			err = collectManifestImages([]byte(coreDNSManifests), source, defaultCoreDNSImage, sources)
+			if err != nil {
+				return fmt.Errorf("%s: %w", source, err)
+			}
+		}
+	}
+}
+
+func collectContainerImages(value interface{}, source string, sources map[string][]string) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			containers, ok := item.([]interface{})
+			if !ok || !slices.Contains(containerKeys, key) {
+				collectContainerImages(item, source, sources)
+				continue
+			}
+
+			for _, container := range containers {
+				containerMap, _ := container.(map[string]interface{})
+				image, _ := containerMap["image"].(string)
+				if image != "" && !slices.Contains(sources[image], source) {
+					sources[image] = append(sources[image], source)
+				}
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			collectContainerImages(item, source, sources)
+		}
+	}
+}
+
+// resolveDigest returns the digest of the manifest or index of the image in its registry
+func resolveDigest(ctx context.Context, image string, httpClient *http.Client, credentials registryCredentials) (string, error) {
+	if _, digest, found := strings.Cut(image, "@"); found {
+		return digest, nil
+	}
+
+	registry, repository, tag, err := parseOCIReference(normalizeImage(image))
+	if err != nil {
+		return "", err
+	}
+
+	client := &ociClient{
+		httpClient:  httpClient,
+		registry:    registry,
+		credentials: credentials,
+	}
+	rawManifest, err := client.get(ctx, fmt.Sprintf("/v2/%s/manifests/%s", repository, tag), strings.Join(imageManifestMediaTypes, ", "))
+	if err != nil {
+		return "", fmt.Errorf("get manifest: %w", err)
+	}
+
+	sum := sha256.Sum256(rawManifest)
+	return "sha256:" + hex.EncodeToString(sum[:]), nil
+}
+
+// normalizeImage prefixes images without registry with docker.io, e.g. coredns/coredns:1.11.0
+func normalizeImage(image string) string {
+	registry, _, found := strings.Cut(image, "/")
+	if !found || (!strings.ContainsAny(registry, ".:") && registry != "localhost") {
+		return "docker.io/" + image
+	}
+
+	return image
+}
+
+type cycloneDXDocument struct {
+	BOMFormat   string               `json:"bomFormat"`
+	SpecVersion string               `json:"specVersion"`
+	Version     int                  `json:"version"`
+	Metadata    cycloneDXMetadata    `json:"metadata"`
+	Components  []cycloneDXComponent `json:"components"`
+}
+
+type cycloneDXMetadata struct {
+	Component cycloneDXComponent `json:"component"`
+}
+
+type cycloneDXComponent struct {
+	Type    string          `json:"type"`
+	Name    string          `json:"name"`
+	Version string          `json:"version,omitempty"`
+	PURL    string          `json:"purl,omitempty"`
+	Hashes  []cycloneDXHash `json:"hashes,omitempty"`
+}
+
+type cycloneDXHash struct {
+	Algorithm string `json:"alg"`
+	Content   string `json:"content"`
+}
+
+// cycloneDXBOM lists the images as container components of the chart, so scanners can pick them up
+func cycloneDXBOM(images []Image, chartName, chartVersion string) *cycloneDXDocument {
+	bom := &cycloneDXDocument{
+		BOMFormat:   "CycloneDX",
+		SpecVersion: "1.5",
+		Version:     1,
+		Metadata: cycloneDXMetadata{
+			Component: cycloneDXComponent{Type: "application", Name: chartName, Version: chartVersion},
+		},
+		Components: []cycloneDXComponent{},
+	}
+
+	for _, image := range images {
+		name, tag := image.Image, ""
+		if before, _, found := strings.Cut(name, "@"); found {
+			name = before
+		}
+		if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+			name, tag = name[:i], name[i+1:]
+		}
+
+		component := cycloneDXComponent{Type: "container", Name: name, Version: tag}
+		if algorithm, hash, found := strings.Cut(image.Digest, ":"); found {
+			component.Hashes = []cycloneDXHash{{Algorithm: strings.ToUpper(strings.Replace(algorithm, "sha", "SHA-", 1)), Content: hash}}
+
+			// pkg:oci/<name>@<digest>?repository_url=<registry>/<repository>&tag=<tag>
+			query := url.Values{"repository_url": []string{name}}
+			if tag != "" {
+				query.Set("tag", tag)
+			}
+			component.PURL = fmt.Sprintf("pkg:oci/%s@%s?%s", name[strings.LastIndex(name, "/")+1:], url.QueryEscape(image.Digest), query.Encode())
+		}
+
+		bom.Components = append(bom.Components, component)
+	}
+
+	return bom
+}
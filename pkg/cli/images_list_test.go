@@ -0,0 +1,112 @@
+package cli
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/loft-sh/vcluster/config"
+	"gotest.tools/v3/assert"
+)
+
+const testManifests = `---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: vc-coredns-vcluster
+data:
+  coredns.yaml: |-
+    apiVersion: apps/v1
+    kind: Deployment
+    metadata:
+      name: coredns
+    spec:
+      template:
+        spec:
+          containers:
+            - name: coredns
+              image: {{.IMAGE}}
+---
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: vcluster
+spec:
+  template:
+    spec:
+      initContainers:
+        - name: kube-apiserver
+          image: registry.k8s.io/kube-apiserver:v1.29.0
+      containers:
+        - name: syncer
+          image: ghcr.io/loft-sh/vcluster-pro:0.21.0
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: other
+spec:
+  template:
+    spec:
+      containers:
+        - name: syncer
+          image: ghcr.io/loft-sh/vcluster-pro:0.21.0
+`
+
+func TestImagesFromManifests(t *testing.T) {
+	vClusterConfig := &config.Config{}
+	assert.Equal(t, coreDNSImage(vClusterConfig, ""), "coredns/coredns:1.11.0")
+	assert.Equal(t, coreDNSImage(vClusterConfig, "v1.26"), "coredns/coredns:1.9.3")
+	vClusterConfig.ControlPlane.Advanced.DefaultImageRegistry = "registry.example.com/"
+	assert.Equal(t, coreDNSImage(vClusterConfig, ""), "registry.example.com/coredns/coredns:1.11.0")
+
+	images, err := imagesFromManifests([]byte(testManifests), "coredns/coredns:1.11.0")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, images, []Image{
+		{Image: "coredns/coredns:1.11.0", Sources: []string{"ConfigMap/vc-coredns-vcluster Deployment/coredns"}},
+		{Image: "ghcr.io/loft-sh/vcluster-pro:0.21.0", Sources: []string{"StatefulSet/vcluster", "Deployment/other"}},
+		{Image: "registry.k8s.io/kube-apiserver:v1.29.0", Sources: []string{"StatefulSet/vcluster"}},
+	})
+}
+
+func TestResolveDigest(t *testing.T) {
+	registry, manifestDigest := newTestRegistry(t)
+	defer registry.Close()
+	registryHost := strings.TrimPrefix(registry.URL, "https://")
+
+	digest, err := resolveDigest(context.Background(), registryHost+"/my-org/values:v1", registry.Client(), nil)
+	assert.NilError(t, err)
+	assert.Equal(t, digest, manifestDigest)
+
+	digest, err = resolveDigest(context.Background(), "my-org/values@sha256:abc", registry.Client(), nil)
+	assert.NilError(t, err)
+	assert.Equal(t, digest, "sha256:abc")
+
+	_, err = resolveDigest(context.Background(), registryHost+"/my-org/other:v1", registry.Client(), nil)
+	assert.ErrorContains(t, err, "unexpected status code 404")
+
+	assert.Equal(t, normalizeImage("coredns/coredns:1.11.0"), "docker.io/coredns/coredns:1.11.0")
+	assert.Equal(t, normalizeImage("nginx"), "docker.io/nginx")
+	assert.Equal(t, normalizeImage("localhost/nginx"), "localhost/nginx")
+	assert.Equal(t, normalizeImage("localhost:5000/nginx"), "localhost:5000/nginx")
+	assert.Equal(t, normalizeImage("ghcr.io/loft-sh/vcluster-pro:0.21.0"), "ghcr.io/loft-sh/vcluster-pro:0.21.0")
+}
+
+func TestCycloneDXBOM(t *testing.T) {
+	bom := cycloneDXBOM([]Image{
+		{Image: "ghcr.io/loft-sh/vcluster-pro:0.21.0", Digest: "sha256:abc"},
+		{Image: "registry.k8s.io/kube-apiserver:v1.29.0"},
+	}, "vcluster", "0.21.0")
+
+	assert.Equal(t, bom.Metadata.Component.Name, "vcluster")
+	assert.DeepEqual(t, bom.Components, []cycloneDXComponent{
+		{
+			Type:    "container",
+			Name:    "ghcr.io/loft-sh/vcluster-pro",
+			Version: "0.21.0",
+			PURL:    "pkg:oci/vcluster-pro@sha256%3Aabc?repository_url=ghcr.io%2Floft-sh%2Fvcluster-pro&tag=0.21.0",
+			Hashes:  []cycloneDXHash{{Algorithm: "SHA-256", Content: "abc"}},
+		},
+		{Type: "container", Name: "registry.k8s.io/kube-apiserver", Version: "v1.29.0"},
+	})
+}
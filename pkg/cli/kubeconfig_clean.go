@@ -0,0 +1,295 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/log/table"
+	"github.com/loft-sh/vcluster/pkg/cli/config"
+	"github.com/loft-sh/vcluster/pkg/cli/find"
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/loft-sh/vcluster/pkg/platform"
+	"github.com/loft-sh/vcluster/pkg/platform/kubeconfig"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+type KubeConfigCleanOptions struct {
+	DryRun bool
+	// IncludeUnreachable also removes the contexts of helm virtual clusters whose host cluster can't be reached
+	IncludeUnreachable bool
+}
+
+// vClusterContext is a kube context that belongs to a virtual cluster
+type vClusterContext struct {
+	config.KubeContext
+
+	Context string
+	Reason  string
+}
+
+// CleanKubeConfig removes the kube contexts of virtual clusters that don't exist anymore. The contexts are taken from
+// the contexts recorded by connect and create and from the context names the CLI uses.
+func CleanKubeConfig(ctx context.Context, options *KubeConfigCleanOptions, globalFlags *flags.GlobalFlags, log log.Logger) error {
+	rawConfig, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(clientcmd.NewDefaultClientConfigLoadingRules(), &clientcmd.ConfigOverrides{}).RawConfig()
+	if err != nil {
+		return fmt.Errorf("load kube config: %w", err)
+	}
+	cfg := globalFlags.LoadedConfig(log)
+
+	// records of contexts that were removed from the kube config by other tools are dropped
+	changed := pruneContexts(cfg, &rawConfig)
+
+	helmContexts, platformContexts := []vClusterContext{}, []vClusterContext{}
+	for _, vClusterContext := range vClusterContexts(&rawConfig, cfg.Contexts) {
+		if vClusterContext.Driver == config.PlatformDriver {
+			platformContexts = append(platformContexts, vClusterContext)
+		} else {
+			helmContexts = append(helmContexts, vClusterContext)
+		}
+	}
+	stale := append(staleHelmContexts(ctx, &rawConfig, helmContexts, options.IncludeUnreachable, log), stalePlatformContexts(ctx, &rawConfig, cfg, platformContexts, log)...)
+	sort.Slice(stale, func(i, j int) bool {
+		return stale[i].Context < stale[j].Context
+	})
+
+	if len(stale) == 0 {
+		log.Done("No stale kube contexts found")
+		return saveContexts(cfg, changed)
+	}
+
+	rows := [][]string{}
+	for _, vClusterContext := range stale {
+		rows = append(rows, []string{vClusterContext.Context, vClusterContext.vClusterName(), vClusterContext.Reason})
+	}
+	table.PrintTable(log, []string{"CONTEXT", "VCLUSTER", "REASON"}, rows)
+	if options.DryRun {
+		log.Infof("Would remove %d stale kube contexts, run without --dry-run to remove them", len(stale))
+		return saveContexts(cfg, changed)
+	}
+
+	for _, vClusterContext := range stale {
+		// switch to the context the virtual cluster was connected from if the removed context is the current one
+		otherContext := vClusterContext.ParentContext
+		if _, ok := rawConfig.Contexts[otherContext]; !ok {
+			otherContext = ""
+			if _, ok := rawConfig.Contexts[cfg.PreviousContext]; ok {
+				otherContext = cfg.PreviousContext
+			}
+		}
+
+		err = deleteContext(&rawConfig, vClusterContext.Context, otherContext)
+		if err != nil {
+			return fmt.Errorf("delete kube context %s: %w", vClusterContext.Context, err)
+		}
+		cfg.UnregisterContext(vClusterContext.Context)
+	}
+
+	log.Donef("Removed %d stale kube contexts", len(stale))
+	return saveContexts(cfg, true)
+}
+
+// staleHelmContexts returns the contexts of helm virtual clusters whose host context is gone or that aren't found
+// in their host cluster anymore. Host clusters that can't be reached are skipped unless includeUnreachable is set.
+func staleHelmContexts(ctx context.Context, rawConfig *clientcmdapi.Config, vClusterContexts []vClusterContext, includeUnreachable bool, log log.Logger) []vClusterContext {
+	stale := []vClusterContext{}
+
+	// virtual clusters by host context, nil if the host cluster is unreachable
+	vClustersByParent := map[string]map[string]bool{}
+	for _, vClusterContext := range vClusterContexts {
+		if _, ok := rawConfig.Contexts[vClusterContext.ParentContext]; !ok {
+			vClusterContext.Reason = fmt.Sprintf("host context %s doesn't exist anymore", vClusterContext.ParentContext)
+			stale = append(stale, vClusterContext)
+			continue
+		}
+
+		vClusters, ok := vClustersByParent[vClusterContext.ParentContext]
+		if !ok {
+			vClusters = listHelmVClusters(ctx, vClusterContext.ParentContext, log)
+			vClustersByParent[vClusterContext.ParentContext] = vClusters
+		}
+
+		if vClusters == nil {
+			if !includeUnreachable {
+				log.Warnf("Skipping kube context %s, because host context %s is unreachable", vClusterContext.Context, vClusterContext.ParentContext)
+				continue
+			}
+
+			vClusterContext.Reason = fmt.Sprintf("host context %s is unreachable", vClusterContext.ParentContext)
+			stale = append(stale, vClusterContext)
+		} else if !vClusters[vClusterContext.Namespace+"/"+vClusterContext.Name] {
+			vClusterContext.Reason = fmt.Sprintf("vcluster doesn't exist in host context %s anymore", vClusterContext.ParentContext)
+			stale = append(stale, vClusterContext)
+		}
+	}
+
+	return stale
+}
+
+// listHelmVClusters returns the namespace/name of the virtual clusters in the host context or nil if the host
+// cluster can't be reached
+func listHelmVClusters(ctx context.Context, parentContext string, log log.Logger) map[string]bool {
+	vClusters, err := find.ListOSSVClusters(ctx, parentContext, "", "")
+	if err != nil {
+		log.Debugf("list virtual clusters in context %s: %v", parentContext, err)
+		return nil
+	}
+
+	names := map[string]bool{}
+	for _, vCluster := range vClusters {
+		names[vCluster.Namespace+"/"+vCluster.Name] = true
+	}
+	return names
+}
+
+// stalePlatformContexts returns the contexts of platform virtual clusters that don't exist in the platform
+// anymore. Only contexts of the platform the CLI is logged into are checked.
+func stalePlatformContexts(ctx context.Context, rawConfig *clientcmdapi.Config, cfg *config.CLI, vClusterContexts []vClusterContext, log log.Logger) []vClusterContext {
+	stale := []vClusterContext{}
+	if len(vClusterContexts) == 0 {
+		return stale
+	}
+
+	platformClient, err := platform.InitClientFromConfig(ctx, cfg)
+	if err != nil {
+		log.Warnf("Skipping the kube contexts of platform virtual clusters, because you are not logged into the platform: %v", err)
+		return stale
+	}
+	virtualClusters, err := platform.ListVClusters(ctx, platformClient, "", "")
+	if err != nil {
+		log.Warnf("Skipping the kube contexts of platform virtual clusters, because they couldn't be listed: %v", err)
+		return stale
+	}
+
+	names := map[string]bool{}
+	for _, virtualCluster := range virtualClusters {
+		names[virtualCluster.Project.Name+"/"+virtualCluster.VirtualCluster.Name] = true
+	}
+	for _, vClusterContext := range vClusterContexts {
+		if !isContextOfPlatform(rawConfig, vClusterContext, cfg.Platform.Host) {
+			log.Debugf("Skipping kube context %s, because it belongs to another platform", vClusterContext.Context)
+			continue
+		} else if names[vClusterContext.Project+"/"+vClusterContext.Name] {
+			continue
+		}
+
+		vClusterContext.Reason = fmt.Sprintf("vcluster doesn't exist in project %s anymore", vClusterContext.Project)
+		stale = append(stale, vClusterContext)
+	}
+
+	return stale
+}
+
+// isContextOfPlatform returns true if the context was created for the platform at host. Contexts that weren't
+// recorded are matched by their server.
+func isContextOfPlatform(rawConfig *clientcmdapi.Config, vClusterContext vClusterContext, host string) bool {
+	if host == "" {
+		return false
+	} else if vClusterContext.Host != "" {
+		return vClusterContext.Host == host
+	}
+
+	kubeContext := rawConfig.Contexts[vClusterContext.Context]
+	if kubeContext == nil || rawConfig.Clusters[kubeContext.Cluster] == nil {
+		return false
+	}
+
+	return strings.HasPrefix(rawConfig.Clusters[kubeContext.Cluster].Server, strings.TrimSuffix(host, "/")+"/")
+}
+
+// vClusterContexts returns the contexts of the kube config that belong to a virtual cluster, sorted by name
+func vClusterContexts(rawConfig *clientcmdapi.Config, registered map[string]config.KubeContext) []vClusterContext {
+	vClusterContexts := []vClusterContext{}
+	for contextName := range rawConfig.Contexts {
+		if vClusterContext, ok := vClusterContextOf(contextName, registered); ok {
+			vClusterContexts = append(vClusterContexts, vClusterContext)
+		}
+	}
+
+	sort.Slice(vClusterContexts, func(i, j int) bool {
+		return vClusterContexts[i].Context < vClusterContexts[j].Context
+	})
+	return vClusterContexts
+}
+
+// vClusterContextOf returns the virtual cluster of the context from the recorded contexts or from the context name
+func vClusterContextOf(contextName string, registered map[string]config.KubeContext) (vClusterContext, bool) {
+	if kubeContext, ok := registered[contextName]; ok {
+		return vClusterContext{KubeContext: kubeContext, Context: contextName}, true
+	} else if name, namespace, parentContext := find.VClusterFromContext(contextName); namespace != "" {
+		return vClusterContext{
+			KubeContext: config.KubeContext{Driver: config.HelmDriver, Name: name, Namespace: namespace, ParentContext: parentContext},
+			Context:     contextName,
+		}, true
+	} else if name, project, parentContext := find.VClusterPlatformFromContext(contextName); project != "" {
+		return vClusterContext{
+			KubeContext: config.KubeContext{Driver: config.PlatformDriver, Name: name, Project: project, ParentContext: parentContext},
+			Context:     contextName,
+		}, true
+	} else if project, name := kubeconfig.VirtualClusterInstanceFromContextName(contextName); project != "" {
+		return vClusterContext{
+			KubeContext: config.KubeContext{Driver: config.PlatformDriver, Name: name, Project: project},
+			Context:     contextName,
+		}, true
+	}
+
+	return vClusterContext{}, false
+}
+
+// pruneContexts drops the recorded contexts that don't exist in the kube config anymore and returns true if any
+// were dropped
+func pruneContexts(cfg *config.CLI, rawConfig *clientcmdapi.Config) bool {
+	pruned := false
+	for contextName := range cfg.Contexts {
+		if _, ok := rawConfig.Contexts[contextName]; !ok {
+			pruned = cfg.UnregisterContext(contextName) || pruned
+		}
+	}
+
+	return pruned
+}
+
+func (c vClusterContext) vClusterName() string {
+	if c.Driver == config.PlatformDriver {
+		return c.Project + "/" + c.Name
+	}
+
+	return c.Namespace + "/" + c.Name
+}
+
+func saveContexts(cfg *config.CLI, changed bool) error {
+	if !changed {
+		return nil
+	}
+
+	err := cfg.Save()
+	if err != nil {
+		return fmt.Errorf("save vcluster config: %w", err)
+	}
+	return nil
+}
+
+// registerContext records the kube context of the virtual cluster, so vcluster kubeconfig clean can remove it once the
+// virtual cluster is gone. The command doesn't fail if the context couldn't be recorded.
+func registerContext(cfg *config.CLI, contextName string, kubeContext config.KubeContext, log log.Logger) {
+	cfg.RegisterContext(contextName, kubeContext)
+	err := cfg.Save()
+	if err != nil {
+		log.Debugf("record kube context %s: %v", contextName, err)
+	}
+}
+
+// unregisterContext removes the record of a kube context that was deleted
+func unregisterContext(cfg *config.CLI, contextName string, log log.Logger) {
+	if !cfg.UnregisterContext(contextName) {
+		return
+	}
+
+	err := cfg.Save()
+	if err != nil {
+		log.Debugf("remove record of kube context %s: %v", contextName, err)
+	}
+}
@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"context"
+	"testing"
+
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/vcluster/pkg/cli/config"
+	"gotest.tools/v3/assert"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+func testKubeConfig(servers map[string]string) *clientcmdapi.Config {
+	kubeConfig := clientcmdapi.NewConfig()
+	for name, server := range servers {
+		kubeConfig.Clusters[name] = &clientcmdapi.Cluster{Server: server}
+		kubeConfig.Contexts[name] = &clientcmdapi.Context{Cluster: name, AuthInfo: name}
+	}
+	return kubeConfig
+}
+
+func TestVClusterContexts(t *testing.T) {
+	kubeConfig := testKubeConfig(map[string]string{
+		"kind-kind":                              "https://127.0.0.1:6443",
+		"vcluster_my-vcluster_team-x_kind-kind":  "https://127.0.0.1:10443",
+		"vcluster-platform_dev_team-a_kind-kind": "https://platform.example.com/kubernetes/project/team-a/virtualcluster/dev",
+		"vcluster-platform-vcluster_prod_team-b": "https://platform.example.com/kubernetes/project/team-b/virtualcluster/prod",
+		"vcluster-platform_team-a_cluster-1":     "https://platform.example.com/kubernetes/cluster/cluster-1",
+		"custom":                                 "https://10.0.0.1",
+	})
+	registered := map[string]config.KubeContext{
+		"custom": {Driver: config.HelmDriver, Name: "custom", Namespace: "team-y", ParentContext: "kind-kind"},
+	}
+
+	vClusterContexts := vClusterContexts(kubeConfig, registered)
+	assert.DeepEqual(t, vClusterContexts, []vClusterContext{
+		{Context: "custom", KubeContext: registered["custom"]},
+		{Context: "vcluster-platform-vcluster_prod_team-b", KubeContext: config.KubeContext{Driver: config.PlatformDriver, Name: "prod", Project: "team-b"}},
+		{Context: "vcluster-platform_dev_team-a_kind-kind", KubeContext: config.KubeContext{Driver: config.PlatformDriver, Name: "dev", Project: "team-a", ParentContext: "kind-kind"}},
+		{Context: "vcluster_my-vcluster_team-x_kind-kind", KubeContext: config.KubeContext{Driver: config.HelmDriver, Name: "my-vcluster", Namespace: "team-x", ParentContext: "kind-kind"}},
+	})
+}
+
+func TestPruneContexts(t *testing.T) {
+	kubeConfig := testKubeConfig(map[string]string{"vcluster_a_ns_kind-kind": "https://127.0.0.1:10443"})
+	cfg := &config.CLI{Contexts: map[string]config.KubeContext{
+		"vcluster_a_ns_kind-kind": {Driver: config.HelmDriver, Name: "a"},
+		"vcluster_b_ns_kind-kind": {Driver: config.HelmDriver, Name: "b"},
+	}}
+
+	assert.Assert(t, pruneContexts(cfg, kubeConfig))
+	assert.DeepEqual(t, cfg.Contexts, map[string]config.KubeContext{"vcluster_a_ns_kind-kind": {Driver: config.HelmDriver, Name: "a"}})
+	assert.Assert(t, !pruneContexts(cfg, kubeConfig))
+}
+
+func TestIsContextOfPlatform(t *testing.T) {
+	kubeConfig := testKubeConfig(map[string]string{
+		"vcluster-platform-vcluster_a_p": "https://platform.example.com/kubernetes/project/p/virtualcluster/a",
+		"vcluster-platform-vcluster_b_p": "https://other.example.com/kubernetes/project/p/virtualcluster/b",
+	})
+
+	assert.Assert(t, isContextOfPlatform(kubeConfig, vClusterContext{Context: "vcluster-platform-vcluster_a_p"}, "https://platform.example.com"))
+	assert.Assert(t, !isContextOfPlatform(kubeConfig, vClusterContext{Context: "vcluster-platform-vcluster_b_p"}, "https://platform.example.com"))
+	assert.Assert(t, !isContextOfPlatform(kubeConfig, vClusterContext{Context: "vcluster-platform-vcluster_a_p"}, ""))
+
+	// recorded contexts are matched by their host, e.g. if they use a direct access point
+	recorded := vClusterContext{Context: "vcluster-platform-vcluster_b_p", KubeContext: config.KubeContext{Host: "https://platform.example.com"}}
+	assert.Assert(t, isContextOfPlatform(kubeConfig, recorded, "https://platform.example.com"))
+}
+
+func TestStaleHelmContextsMissingHost(t *testing.T) {
+	kubeConfig := testKubeConfig(map[string]string{"vcluster_a_ns_gone": "https://127.0.0.1:10443"})
+	vClusterContexts := vClusterContexts(kubeConfig, nil)
+
+	stale := staleHelmContexts(context.Background(), kubeConfig, vClusterContexts, false, log.Discard)
+	assert.Equal(t, len(stale), 1)
+	assert.Equal(t, stale[0].Context, "vcluster_a_ns_gone")
+	assert.Equal(t, stale[0].Reason, "host context gone doesn't exist anymore")
+}
@@ -0,0 +1,127 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/vcluster/config"
+	pkgconfig "github.com/loft-sh/vcluster/pkg/config"
+	"github.com/sirupsen/logrus"
+)
+
+type LintOptions struct {
+	Values    []string
+	SetValues []string
+
+	// Strict fails on warnings as well
+	Strict bool
+
+	// Output is the output format, either text or json
+	Output string
+
+	// ExpandEnv expands environment variables and templates in the values files
+	ExpandEnv bool
+
+	// Policy is validated in addition, violated rules are reported as errors
+	Policy string
+
+	// PolicyOverrideTokens exempt the values from policy rules
+	PolicyOverrideTokens []string
+}
+
+// Lint lints the given values merged with the default values of this version and fails if there are errors, or
+// warnings in strict mode
+func Lint(ctx context.Context, options *LintOptions, log log.Logger) error {
+	if options.Output != "text" && options.Output != "json" {
+		return fmt.Errorf("unsupported output format %s, please use text or json", options.Output)
+	}
+
+	values, cleanupValues, err := resolveValuesFiles(ctx, options.Values, options.ExpandEnv)
+	if err != nil {
+		return err
+	}
+	defer cleanupValues()
+	options.Values = values
+
+	results, err := LintValues(options.Values, options.SetValues)
+	if err != nil {
+		return err
+	}
+	policyResults, err := lintPolicy(ctx, options)
+	if err != nil {
+		return err
+	}
+	results = append(results, policyResults...)
+
+	errors, warnings := 0, 0
+	for _, result := range results {
+		if result.Severity == pkgconfig.LintSeverityError {
+			errors++
+		} else {
+			warnings++
+		}
+	}
+
+	if options.Output == "json" {
+		out, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		log.WriteString(logrus.InfoLevel, string(out)+"\n")
+	} else {
+		for _, result := range results {
+			if result.Severity == pkgconfig.LintSeverityError {
+				log.Errorf("%s", result)
+			} else {
+				log.Warnf("%s", result)
+			}
+		}
+	}
+
+	if errors > 0 || (options.Strict && warnings > 0) {
+		return fmt.Errorf("found %d error(s) and %d warning(s)", errors, warnings)
+	} else if options.Output == "text" {
+		log.Donef("Found %d error(s) and %d warning(s)", errors, warnings)
+	}
+
+	return nil
+}
+
+// LintValues merges the given values with the default values of this version and lints them
+func LintValues(values, setValues []string) ([]pkgconfig.LintResult, error) {
+	finalValues, err := mergeAllValues(setValues, values, config.Values)
+	if err != nil {
+		return nil, fmt.Errorf("merge values: %w", err)
+	}
+
+	vClusterConfig := &config.Config{}
+	err = vClusterConfig.UnmarshalYAMLStrict([]byte(finalValues))
+	if err != nil {
+		return []pkgconfig.LintResult{{Severity: pkgconfig.LintSeverityError, Message: fmt.Sprintf("parse config: %v", err)}}, nil
+	}
+
+	return pkgconfig.Lint(vClusterConfig), nil
+}
+
+// lintPolicy reports the violated rules of the policy as errors. Policies stored in a configmap are not supported, as
+// lint does not access the host cluster.
+func lintPolicy(ctx context.Context, options *LintOptions) ([]pkgconfig.LintResult, error) {
+	finalValues, err := mergeAllValues(options.SetValues, options.Values, config.Values)
+	if err != nil {
+		return nil, fmt.Errorf("merge values: %w", err)
+	}
+
+	violations, _, err := evaluatePolicy(ctx, options.Policy, options.PolicyOverrideTokens, finalValues, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]pkgconfig.LintResult, 0, len(violations))
+	for _, violation := range violations {
+		results = append(results, pkgconfig.LintResult{Severity: pkgconfig.LintSeverityError, Path: violation.Path, Message: fmt.Sprintf("%s (policy rule %s)", violation.Message, violation.Rule)})
+	}
+	return results, nil
+}
@@ -0,0 +1,87 @@
+package cli
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ListSortColumns are the columns the virtual clusters can be sorted by
+var ListSortColumns = []string{"name", "namespace", "project", "cluster", "template", "status", "version", "age"}
+
+func validateListOptions(options *ListOptions, platformDriver bool) error {
+	if options.Sleeping && options.Awake {
+		return errors.New("--sleeping and --awake cannot be used together")
+	}
+	if options.SortBy != "" && !slices.Contains(ListSortColumns, strings.ToLower(options.SortBy)) {
+		return fmt.Errorf("unknown sort column %q, please use one of: %s", options.SortBy, strings.Join(ListSortColumns, ", "))
+	}
+	if !platformDriver {
+		for flag, value := range map[string]string{"project": options.Project, "cluster": options.Cluster, "template": options.Template} {
+			if value != "" {
+				return fmt.Errorf("--%s is only supported by the platform driver, please use --driver platform", flag)
+			}
+		}
+	}
+
+	return nil
+}
+
+// filterVClusters returns the virtual clusters matching all filters of the options
+func filterVClusters(vClusters []ListVCluster, options *ListOptions) []ListVCluster {
+	filtered := []ListVCluster{}
+	for _, vCluster := range vClusters {
+		switch {
+		case len(options.Status) > 0 && !slices.ContainsFunc(options.Status, func(status string) bool {
+			return strings.EqualFold(status, vCluster.Status)
+		}):
+		case options.Sleeping && !vCluster.Sleeping:
+		case options.Awake && vCluster.Sleeping:
+		case options.Project != "" && vCluster.Project != options.Project:
+		case options.Cluster != "" && vCluster.Cluster != options.Cluster:
+		case options.Template != "" && vCluster.Template != options.Template:
+		default:
+			filtered = append(filtered, vCluster)
+		}
+	}
+
+	return filtered
+}
+
+// sortVClusters sorts the virtual clusters by the column and then by namespace and name, so the order is stable
+// between runs. The virtual clusters keep their order if no column is given.
+func sortVClusters(vClusters []ListVCluster, column string) ([]ListVCluster, error) {
+	if column == "" {
+		return vClusters, nil
+	}
+
+	var compare func(a, b ListVCluster) int
+	switch strings.ToLower(column) {
+	case "name":
+		compare = func(a, b ListVCluster) int { return cmp.Compare(a.Name, b.Name) }
+	case "namespace":
+		compare = func(a, b ListVCluster) int { return cmp.Compare(a.Namespace, b.Namespace) }
+	case "project":
+		compare = func(a, b ListVCluster) int { return cmp.Compare(a.Project, b.Project) }
+	case "cluster":
+		compare = func(a, b ListVCluster) int { return cmp.Compare(a.Cluster, b.Cluster) }
+	case "template":
+		compare = func(a, b ListVCluster) int { return cmp.Compare(a.Template, b.Template) }
+	case "status":
+		compare = func(a, b ListVCluster) int { return cmp.Compare(a.Status, b.Status) }
+	case "version":
+		compare = func(a, b ListVCluster) int { return cmp.Compare(a.Version, b.Version) }
+	case "age":
+		// the youngest virtual clusters come first, like the age column in ascending order
+		compare = func(a, b ListVCluster) int { return b.Created.Compare(a.Created) }
+	default:
+		return nil, fmt.Errorf("unknown sort column %q, please use one of: %s", column, strings.Join(ListSortColumns, ", "))
+	}
+
+	slices.SortStableFunc(vClusters, func(a, b ListVCluster) int {
+		return cmp.Or(compare(a, b), cmp.Compare(a.Namespace, b.Namespace), cmp.Compare(a.Name, b.Name))
+	})
+	return vClusters, nil
+}
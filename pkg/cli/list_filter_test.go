@@ -0,0 +1,66 @@
+package cli
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func listNames(vClusters []ListVCluster) []string {
+	names := []string{}
+	for _, vCluster := range vClusters {
+		names = append(names, vCluster.Namespace+"/"+vCluster.Name)
+	}
+	return names
+}
+
+func TestFilterVClusters(t *testing.T) {
+	vClusters := []ListVCluster{
+		{Name: "a", Namespace: "p-team-a", Project: "team-a", Cluster: "loft-cluster", Template: "small", Status: "Ready"},
+		{Name: "b", Namespace: "p-team-a", Project: "team-a", Cluster: "edge", Status: "Sleeping", Sleeping: true},
+		{Name: "c", Namespace: "p-team-b", Project: "team-b", Cluster: "loft-cluster", Template: "large", Status: "Pending"},
+	}
+
+	assert.DeepEqual(t, listNames(filterVClusters(vClusters, &ListOptions{})), []string{"p-team-a/a", "p-team-a/b", "p-team-b/c"})
+	assert.DeepEqual(t, listNames(filterVClusters(vClusters, &ListOptions{Status: []string{"ready", "pending"}})), []string{"p-team-a/a", "p-team-b/c"})
+	assert.DeepEqual(t, listNames(filterVClusters(vClusters, &ListOptions{Sleeping: true})), []string{"p-team-a/b"})
+	assert.DeepEqual(t, listNames(filterVClusters(vClusters, &ListOptions{Awake: true, Cluster: "loft-cluster"})), []string{"p-team-a/a", "p-team-b/c"})
+	assert.DeepEqual(t, listNames(filterVClusters(vClusters, &ListOptions{Project: "team-b", Template: "large"})), []string{"p-team-b/c"})
+	assert.DeepEqual(t, listNames(filterVClusters(vClusters, &ListOptions{Template: "medium"})), []string{})
+}
+
+func TestSortVClusters(t *testing.T) {
+	now := time.Now()
+	vClusters := []ListVCluster{
+		{Name: "b", Namespace: "ns-1", Status: "Running", Created: now.Add(-time.Hour)},
+		{Name: "a", Namespace: "ns-2", Status: "Paused", Created: now},
+		{Name: "c", Namespace: "ns-1", Status: "Running", Created: now.Add(-2 * time.Hour)},
+	}
+
+	sorted, err := sortVClusters(vClusters, "name")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, listNames(sorted), []string{"ns-2/a", "ns-1/b", "ns-1/c"})
+
+	sorted, err = sortVClusters(vClusters, "Status")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, listNames(sorted), []string{"ns-2/a", "ns-1/b", "ns-1/c"})
+
+	sorted, err = sortVClusters(vClusters, "age")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, listNames(sorted), []string{"ns-2/a", "ns-1/b", "ns-1/c"})
+
+	sorted, err = sortVClusters(vClusters, "namespace")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, listNames(sorted), []string{"ns-1/b", "ns-1/c", "ns-2/a"})
+
+	_, err = sortVClusters(vClusters, "size")
+	assert.ErrorContains(t, err, "unknown sort column")
+}
+
+func TestValidateListOptions(t *testing.T) {
+	assert.NilError(t, validateListOptions(&ListOptions{Project: "team-a", SortBy: "project"}, true))
+	assert.ErrorContains(t, validateListOptions(&ListOptions{Project: "team-a"}, false), "--project is only supported by the platform driver")
+	assert.ErrorContains(t, validateListOptions(&ListOptions{Sleeping: true, Awake: true}, true), "cannot be used together")
+	assert.ErrorContains(t, validateListOptions(&ListOptions{SortBy: "size"}, false), "unknown sort column")
+}
@@ -26,15 +26,37 @@ type ListVCluster struct {
 	Status     string
 	AgeSeconds int
 	Connected  bool
+	Sleeping   bool `json:",omitempty"`
+
+	// Project, Cluster and Template are only set by the platform driver
+	Project  string `json:",omitempty"`
+	Cluster  string `json:",omitempty"`
+	Template string `json:",omitempty"`
 }
 
 type ListOptions struct {
 	Driver string
 
 	Output string
+
+	// Status, Sleeping and Awake filter the virtual clusters of both drivers
+	Status   []string
+	Sleeping bool
+	Awake    bool
+	SortBy   string
+
+	// Project, Cluster and Template filter the virtual clusters of the platform driver
+	Project  string
+	Cluster  string
+	Template string
 }
 
 func ListHelm(ctx context.Context, options *ListOptions, globalFlags *flags.GlobalFlags, log log.Logger) error {
+	err := validateListOptions(options, false)
+	if err != nil {
+		return err
+	}
+
 	rawConfig, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(clientcmd.NewDefaultClientConfigLoadingRules(), &clientcmd.ConfigOverrides{}).RawConfig()
 	if err != nil {
 		return err
@@ -55,7 +77,11 @@ func ListHelm(ctx context.Context, options *ListOptions, globalFlags *flags.Glob
 		return err
 	}
 
-	err = printVClusters(ctx, options, ossToVClusters(vClusters, currentContext), globalFlags, true, log)
+	output, err := sortVClusters(filterVClusters(ossToVClusters(vClusters, currentContext), options), options.SortBy)
+	if err != nil {
+		return err
+	}
+	err = printVClusters(ctx, options, output, globalFlags, true, log)
 	if err != nil {
 		return err
 	}
@@ -73,7 +99,10 @@ func printVClusters(ctx context.Context, options *ListOptions, output []ListVClu
 		logger.WriteString(logrus.InfoLevel, string(bytes)+"\n")
 	} else {
 		header := []string{"NAME", "NAMESPACE", "STATUS", "VERSION", "CONNECTED", "AGE"}
-		values := toValues(output)
+		if !showPlatform {
+			header = []string{"NAME", "NAMESPACE", "PROJECT", "CLUSTER", "TEMPLATE", "STATUS", "VERSION", "CONNECTED", "AGE"}
+		}
+		values := toValues(output, !showPlatform)
 		table.PrintTable(logger, header, values)
 
 		// show use driver command
@@ -119,6 +148,7 @@ func ossToVClusters(vClusters []find.VCluster, currentContext string) []ListVClu
 			Version:    vCluster.Version,
 			AgeSeconds: int(time.Since(vCluster.Created.Time).Round(time.Second).Seconds()),
 			Status:     string(vCluster.Status),
+			Sleeping:   vCluster.Status == find.StatusPaused,
 		}
 		vClusterOutput.Connected = currentContext == find.VClusterContextName(
 			vCluster.Name,
@@ -130,7 +160,7 @@ func ossToVClusters(vClusters []find.VCluster, currentContext string) []ListVClu
 	return output
 }
 
-func toValues(vClusters []ListVCluster) [][]string {
+func toValues(vClusters []ListVCluster, platformColumns bool) [][]string {
 	var values [][]string
 	for _, vCluster := range vClusters {
 		isConnected := ""
@@ -138,14 +168,16 @@ func toValues(vClusters []ListVCluster) [][]string {
 			isConnected = "True"
 		}
 
-		values = append(values, []string{
-			vCluster.Name,
-			vCluster.Namespace,
+		row := []string{vCluster.Name, vCluster.Namespace}
+		if platformColumns {
+			row = append(row, vCluster.Project, vCluster.Cluster, vCluster.Template)
+		}
+		values = append(values, append(row,
 			vCluster.Status,
 			vCluster.Version,
 			isConnected,
-			time.Since(vCluster.Created).Round(1 * time.Second).String(),
-		})
+			time.Since(vCluster.Created).Round(1*time.Second).String(),
+		))
 	}
 	return values
 }
@@ -5,6 +5,7 @@ import (
 	"strings"
 	"time"
 
+	storagev1 "github.com/loft-sh/api/v4/pkg/apis/storage/v1"
 	"github.com/loft-sh/log"
 	"github.com/loft-sh/vcluster/pkg/cli/flags"
 	"github.com/loft-sh/vcluster/pkg/platform"
@@ -12,6 +13,11 @@ import (
 )
 
 func ListPlatform(ctx context.Context, options *ListOptions, globalFlags *flags.GlobalFlags, logger log.Logger) error {
+	err := validateListOptions(options, true)
+	if err != nil {
+		return err
+	}
+
 	rawConfig, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(clientcmd.NewDefaultClientConfigLoadingRules(), &clientcmd.ConfigOverrides{}).RawConfig()
 	if err != nil {
 		return err
@@ -27,12 +33,17 @@ func ListPlatform(ctx context.Context, options *ListOptions, globalFlags *flags.
 		return err
 	}
 
-	proVClusters, err := platform.ListVClusters(ctx, platformClient, "", "")
+	// the project is filtered by the platform, the other filters are applied to the listed virtual clusters
+	proVClusters, err := platform.ListVClusters(ctx, platformClient, "", options.Project)
 	if err != nil {
 		return err
 	}
 
-	err = printVClusters(ctx, options, proToVClusters(proVClusters, currentContext), globalFlags, false, logger)
+	output, err := sortVClusters(filterVClusters(proToVClusters(proVClusters, currentContext), options), options.SortBy)
+	if err != nil {
+		return err
+	}
+	err = printVClusters(ctx, options, output, globalFlags, false, logger)
 	if err != nil {
 		return err
 	}
@@ -62,6 +73,11 @@ func proToVClusters(vClusters []*platform.VirtualClusterInstanceProject, current
 			name = vCluster.VirtualCluster.Name
 		}
 
+		template := ""
+		if vCluster.VirtualCluster.Spec.TemplateRef != nil {
+			template = vCluster.VirtualCluster.Spec.TemplateRef.Name
+		}
+
 		connected := strings.HasPrefix(currentContext, "vcluster-platform_"+vCluster.VirtualCluster.Name+"_"+vCluster.Project.Name)
 		vClusterOutput := ListVCluster{
 			Name:       name,
@@ -71,6 +87,10 @@ func proToVClusters(vClusters []*platform.VirtualClusterInstanceProject, current
 			AgeSeconds: int(time.Since(vCluster.VirtualCluster.CreationTimestamp.Time).Round(time.Second).Seconds()),
 			Status:     status,
 			Version:    version,
+			Sleeping:   vCluster.VirtualCluster.Status.Phase == storagev1.InstanceSleeping,
+			Project:    vCluster.Project.Name,
+			Cluster:    vCluster.VirtualCluster.Spec.ClusterRef.Cluster,
+			Template:   template,
 		}
 		output = append(output, vClusterOutput)
 	}
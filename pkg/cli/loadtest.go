@@ -0,0 +1,552 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/log/table"
+	"github.com/loft-sh/vcluster/pkg/cli/find"
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/loft-sh/vcluster/pkg/metrics"
+	"github.com/loft-sh/vcluster/pkg/util/random"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+const (
+	// DefaultLoadTestImage is the default image of the load test pods, the pods only need to exist on the host
+	DefaultLoadTestImage = "registry.k8s.io/pause:3.9"
+
+	loadTestLabel = "vcluster.loft.sh/loadtest"
+
+	// host api server counter of all handled requests
+	hostRequestsMetric = "apiserver_request_total"
+)
+
+// resources created by the load test, the pods mount the secrets so the secrets are synced as well
+const (
+	LoadTestResourcePods     = "pods"
+	LoadTestResourceServices = "services"
+	LoadTestResourceSecrets  = "secrets"
+)
+
+var loadTestResources = []string{LoadTestResourceSecrets, LoadTestResourcePods, LoadTestResourceServices}
+
+type LoadTestOptions struct {
+	Output string
+
+	// Namespaces is the number of namespaces created in the virtual cluster
+	Namespaces int
+
+	// Objects is the number of pods, services and secrets created in each namespace
+	Objects int
+
+	// Concurrency is the number of namespaces that are filled in parallel
+	Concurrency int
+
+	// QPS and Burst limit the requests against the virtual cluster
+	QPS   float32
+	Burst int
+
+	// Image is the image of the created pods
+	Image string
+
+	// Timeout is the time the syncer may take to sync all objects to the host cluster
+	Timeout time.Duration
+
+	// SkipCleanup keeps the created namespaces after the test
+	SkipCleanup bool
+}
+
+// LoadTestReport is the result of a load test against a virtual cluster
+type LoadTestReport struct {
+	Namespaces int `json:"namespaces"`
+	Objects    int `json:"objectsPerNamespace"`
+
+	// Duration is the time from the first created object until all objects were synced or the timeout was reached
+	Duration time.Duration `json:"duration"`
+
+	// HostRequests and HostQPS are the requests the host api server handled during the test, they include the
+	// requests of all other clients of the host cluster. They are empty if the host metrics are not accessible.
+	HostRequests *int64   `json:"hostRequests,omitempty"`
+	HostQPS      *float64 `json:"hostQPS,omitempty"`
+
+	Resources []LoadTestResourceReport `json:"resources"`
+}
+
+// LoadTestResourceReport holds the sync latencies of a single resource, the latency of an object is the time from its
+// creation in the virtual cluster until the host object was observed
+type LoadTestResourceReport struct {
+	Resource string `json:"resource"`
+	Created  int    `json:"created"`
+	Synced   int    `json:"synced"`
+
+	P50 time.Duration `json:"p50"`
+	P90 time.Duration `json:"p90"`
+	P99 time.Duration `json:"p99"`
+	Max time.Duration `json:"max"`
+}
+
+// LoadTest creates namespaces with pods, services and secrets in the virtual cluster, measures how long the syncer
+// takes to create their host objects and how many requests the host api server handled in that time. The namespaces
+// are deleted afterwards.
+func LoadTest(ctx context.Context, options *LoadTestOptions, globalFlags *flags.GlobalFlags, vClusterName string, log log.Logger) error {
+	err := validateLoadTestOptions(options)
+	if err != nil {
+		return err
+	}
+
+	vCluster, err := find.GetVCluster(ctx, globalFlags.Context, vClusterName, globalFlags.Namespace, log)
+	if err != nil {
+		return err
+	}
+
+	connectCmd := &connectHelm{
+		GlobalFlags:    globalFlags,
+		ConnectOptions: &ConnectOptions{},
+		Log:            log,
+	}
+	err = connectCmd.prepare(ctx, vCluster)
+	if err != nil {
+		return err
+	}
+
+	kubeConfig, err := connectCmd.getVClusterKubeConfig(ctx, vCluster.Name, []string{"vcluster", "loadtest"})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		close(connectCmd.interruptChan)
+		<-connectCmd.errorChan
+	}()
+	err = connectCmd.waitForVCluster(ctx, *kubeConfig, connectCmd.errorChan)
+	if err != nil {
+		return err
+	}
+
+	// the default client rate limit would throttle the load test instead of the syncer
+	vRestConfig, err := clientcmd.NewDefaultClientConfig(getLocalVClusterConfig(*kubeConfig, connectCmd.ConnectOptions), &clientcmd.ConfigOverrides{}).ClientConfig()
+	if err != nil {
+		return fmt.Errorf("create virtual rest config: %w", err)
+	}
+	vRestConfig.QPS = options.QPS
+	vRestConfig.Burst = options.Burst
+	vKubeClient, err := kubernetes.NewForConfig(vRestConfig)
+	if err != nil {
+		return fmt.Errorf("create virtual kube client: %w", err)
+	}
+
+	prefix := "vcluster-loadtest-" + random.String(6)
+	if !options.SkipCleanup {
+		defer deleteLoadTestNamespaces(vKubeClient, prefix, log)
+	}
+
+	report, err := runLoadTest(ctx, vKubeClient, connectCmd.kubeClient, vCluster.Namespace, vCluster.Name, prefix, options, log)
+	if err != nil {
+		return err
+	}
+
+	if options.Output == "json" {
+		out, err := json.MarshalIndent(report, "", "    ")
+		if err != nil {
+			return fmt.Errorf("json marshal load test report: %w", err)
+		}
+
+		log.WriteString(logrus.InfoLevel, string(out)+"\n")
+	} else {
+		values := [][]string{}
+		for _, resource := range report.Resources {
+			values = append(values, []string{
+				resource.Resource,
+				fmt.Sprintf("%d/%d", resource.Synced, resource.Created),
+				resource.P50.Round(time.Millisecond).String(),
+				resource.P90.Round(time.Millisecond).String(),
+				resource.P99.Round(time.Millisecond).String(),
+				resource.Max.Round(time.Millisecond).String(),
+			})
+		}
+		table.PrintTable(log, []string{"RESOURCE", "SYNCED", "P50", "P90", "P99", "MAX"}, values)
+
+		hostLoad := "unknown (host api server metrics are not accessible)"
+		if report.HostQPS != nil {
+			hostLoad = fmt.Sprintf("%d requests, %.1f qps", *report.HostRequests, *report.HostQPS)
+		}
+		log.Infof("Synced %d namespaces in %s, host api server load: %s", report.Namespaces, report.Duration.Round(time.Millisecond), hostLoad)
+	}
+
+	for _, resource := range report.Resources {
+		if resource.Synced < resource.Created {
+			return fmt.Errorf("%d of %d %s were not synced within %s", resource.Created-resource.Synced, resource.Created, resource.Resource, options.Timeout)
+		}
+	}
+
+	return nil
+}
+
+func validateLoadTestOptions(options *LoadTestOptions) error {
+	if options.Namespaces < 1 {
+		return fmt.Errorf("--namespaces must be at least 1")
+	}
+	if options.Objects < 1 {
+		return fmt.Errorf("--objects must be at least 1")
+	}
+	if options.Concurrency < 1 {
+		return fmt.Errorf("--concurrency must be at least 1")
+	}
+	if options.Output != "table" && options.Output != "json" {
+		return fmt.Errorf("invalid output %q, must be one of: table, json", options.Output)
+	}
+
+	return nil
+}
+
+// runLoadTest creates the objects in the virtual cluster and watches the host namespace for their host objects
+func runLoadTest(ctx context.Context, vKubeClient, hostKubeClient kubernetes.Interface, hostNamespace, vClusterName, prefix string, options *LoadTestOptions, log log.Logger) (*LoadTestReport, error) {
+	tracker := newLoadTestTracker(prefix)
+	watchCtx, cancelWatch := context.WithCancel(ctx)
+	defer cancelWatch()
+	for _, resource := range loadTestResources {
+		err := watchHostObjects(watchCtx, hostKubeClient, hostNamespace, vClusterName, resource, tracker)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	requestsBefore, err := hostRequestCount(ctx, hostKubeClient)
+	if err != nil {
+		log.Warnf("Cannot read the host api server metrics, the host api server load will not be reported: %v", err)
+	}
+
+	log.Infof("Creating %d namespaces with %d pods, services and secrets each...", options.Namespaces, options.Objects)
+	start := time.Now()
+	namespaces := make(chan int)
+	errs := make(chan error, options.Concurrency)
+	wg := sync.WaitGroup{}
+	for range options.Concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range namespaces {
+				err := createLoadTestNamespace(ctx, vKubeClient, fmt.Sprintf("%s-%d", prefix, i), options, tracker)
+				if err != nil {
+					errs <- err
+					return
+				}
+			}
+		}()
+	}
+	for i := range options.Namespaces {
+		select {
+		case namespaces <- i:
+		case err := <-errs:
+			close(namespaces)
+			wg.Wait()
+			return nil, err
+		}
+	}
+	close(namespaces)
+	wg.Wait()
+	select {
+	case err := <-errs:
+		return nil, err
+	default:
+	}
+
+	log.Infof("Created all objects after %s, waiting for the syncer...", time.Since(start).Round(time.Millisecond))
+	_ = wait.PollUntilContextTimeout(ctx, time.Second, options.Timeout, true, func(context.Context) (bool, error) {
+		return tracker.done(), nil
+	})
+	duration := time.Since(start)
+
+	report := &LoadTestReport{
+		Namespaces: options.Namespaces,
+		Objects:    options.Objects,
+		Duration:   duration,
+		Resources:  tracker.report(),
+	}
+	if requestsBefore >= 0 {
+		requestsAfter, err := hostRequestCount(ctx, hostKubeClient)
+		if err != nil {
+			log.Warnf("Cannot read the host api server metrics: %v", err)
+		} else {
+			requests := requestsAfter - requestsBefore
+			qps := float64(requests) / duration.Seconds()
+			report.HostRequests = &requests
+			report.HostQPS = &qps
+		}
+	}
+
+	return report, nil
+}
+
+// createLoadTestNamespace creates the namespace and its objects, each pod mounts a secret and is selected by a service
+func createLoadTestNamespace(ctx context.Context, vKubeClient kubernetes.Interface, namespace string, options *LoadTestOptions, tracker *loadTestTracker) error {
+	_, err := vKubeClient.CoreV1().Namespaces().Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:   namespace,
+		Labels: map[string]string{loadTestLabel: "true"},
+	}}, metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("create namespace %s: %w", namespace, err)
+	}
+
+	for i := range options.Objects {
+		name := fmt.Sprintf("loadtest-%d", i)
+		labels := map[string]string{loadTestLabel: name}
+		_, err = vKubeClient.CoreV1().Secrets(namespace).Create(ctx, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: labels},
+			StringData: map[string]string{"key": name},
+		}, metav1.CreateOptions{})
+		if err != nil {
+			return fmt.Errorf("create secret %s/%s: %w", namespace, name, err)
+		}
+		tracker.created(LoadTestResourceSecrets, namespace, name)
+
+		_, err = vKubeClient.CoreV1().Pods(namespace).Create(ctx, &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: labels},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{
+					Name:         "pause",
+					Image:        options.Image,
+					VolumeMounts: []corev1.VolumeMount{{Name: "secret", MountPath: "/secret"}},
+				}},
+				Volumes: []corev1.Volume{{
+					Name:         "secret",
+					VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: name}},
+				}},
+			},
+		}, metav1.CreateOptions{})
+		if err != nil {
+			return fmt.Errorf("create pod %s/%s: %w", namespace, name, err)
+		}
+		tracker.created(LoadTestResourcePods, namespace, name)
+
+		_, err = vKubeClient.CoreV1().Services(namespace).Create(ctx, &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: labels},
+			Spec: corev1.ServiceSpec{
+				Selector: labels,
+				Ports:    []corev1.ServicePort{{Port: 80, TargetPort: intstr.FromInt32(80)}},
+			},
+		}, metav1.CreateOptions{})
+		if err != nil {
+			return fmt.Errorf("create service %s/%s: %w", namespace, name, err)
+		}
+		tracker.created(LoadTestResourceServices, namespace, name)
+	}
+
+	return nil
+}
+
+// watchHostObjects reports the host objects of the resource to the tracker as soon as they are observed
+func watchHostObjects(ctx context.Context, hostKubeClient kubernetes.Interface, hostNamespace, vClusterName, resource string, tracker *loadTestTracker) error {
+	listOptions := metav1.ListOptions{LabelSelector: translate.MarkerLabel + "=" + vClusterName}
+	var (
+		watcher watch.Interface
+		err     error
+	)
+	switch resource {
+	case LoadTestResourcePods:
+		watcher, err = hostKubeClient.CoreV1().Pods(hostNamespace).Watch(ctx, listOptions)
+	case LoadTestResourceServices:
+		watcher, err = hostKubeClient.CoreV1().Services(hostNamespace).Watch(ctx, listOptions)
+	case LoadTestResourceSecrets:
+		watcher, err = hostKubeClient.CoreV1().Secrets(hostNamespace).Watch(ctx, listOptions)
+	default:
+		return fmt.Errorf("unsupported resource %s", resource)
+	}
+	if err != nil {
+		return fmt.Errorf("watch host %s: %w", resource, err)
+	}
+
+	go func() {
+		defer watcher.Stop()
+		for event := range watcher.ResultChan() {
+			if event.Type != watch.Added && event.Type != watch.Modified {
+				continue
+			}
+
+			obj, ok := event.Object.(metav1.Object)
+			if !ok {
+				continue
+			}
+
+			tracker.synced(resource, obj.GetAnnotations()[translate.NamespaceAnnotation], obj.GetAnnotations()[translate.NameAnnotation])
+		}
+	}()
+
+	return nil
+}
+
+// hostRequestCount returns the number of requests the host api server handled since it was started
+func hostRequestCount(ctx context.Context, hostKubeClient kubernetes.Interface) (int64, error) {
+	raw, err := hostKubeClient.CoreV1().RESTClient().Get().AbsPath("/metrics").DoRaw(ctx)
+	if err != nil {
+		return -1, fmt.Errorf("get host api server metrics: %w", err)
+	}
+
+	return sumRequestCount(raw)
+}
+
+// sumRequestCount sums up the request counter of the api server metrics over all labels
+func sumRequestCount(raw []byte) (int64, error) {
+	metricFamilies, err := metrics.Decode(raw)
+	if err != nil {
+		return -1, err
+	}
+
+	for _, metricFamily := range metricFamilies {
+		if metricFamily.GetName() != hostRequestsMetric {
+			continue
+		}
+
+		total := 0.0
+		for _, metric := range metricFamily.Metric {
+			total += metric.GetCounter().GetValue()
+		}
+
+		return int64(total), nil
+	}
+
+	return -1, fmt.Errorf("metric %s not found", hostRequestsMetric)
+}
+
+func deleteLoadTestNamespaces(vKubeClient kubernetes.Interface, prefix string, log log.Logger) {
+	namespaces, err := vKubeClient.CoreV1().Namespaces().List(context.Background(), metav1.ListOptions{LabelSelector: loadTestLabel + "=true"})
+	if err != nil {
+		log.Warnf("Error listing load test namespaces: %v", err)
+		return
+	}
+
+	log.Infof("Deleting load test namespaces...")
+	for _, namespace := range namespaces.Items {
+		if !strings.HasPrefix(namespace.Name, prefix+"-") {
+			continue
+		}
+
+		err = vKubeClient.CoreV1().Namespaces().Delete(context.Background(), namespace.Name, metav1.DeleteOptions{})
+		if err != nil && !kerrors.IsNotFound(err) {
+			log.Warnf("Error deleting load test namespace %s: %v", namespace.Name, err)
+		}
+	}
+}
+
+// loadTestTracker records when the virtual objects were created and when their host objects were observed. Host
+// objects can be observed before the create request of the virtual object returned, those count as synced
+// immediately.
+type loadTestTracker struct {
+	m sync.Mutex
+
+	prefix    string
+	createdAt map[string]map[string]time.Time
+	syncedAt  map[string]map[string]time.Time
+}
+
+func newLoadTestTracker(prefix string) *loadTestTracker {
+	tracker := &loadTestTracker{
+		prefix:    prefix,
+		createdAt: map[string]map[string]time.Time{},
+		syncedAt:  map[string]map[string]time.Time{},
+	}
+	for _, resource := range loadTestResources {
+		tracker.createdAt[resource] = map[string]time.Time{}
+		tracker.syncedAt[resource] = map[string]time.Time{}
+	}
+
+	return tracker
+}
+
+func (t *loadTestTracker) created(resource, namespace, name string) {
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	t.createdAt[resource][namespace+"/"+name] = time.Now()
+}
+
+func (t *loadTestTracker) synced(resource, namespace, name string) {
+	if !strings.HasPrefix(namespace, t.prefix+"-") {
+		return
+	}
+
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	key := namespace + "/" + name
+	if _, ok := t.syncedAt[resource][key]; !ok {
+		t.syncedAt[resource][key] = time.Now()
+	}
+}
+
+func (t *loadTestTracker) done() bool {
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	for _, resource := range loadTestResources {
+		for key := range t.createdAt[resource] {
+			if _, ok := t.syncedAt[resource][key]; !ok {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+func (t *loadTestTracker) report() []LoadTestResourceReport {
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	reports := []LoadTestResourceReport{}
+	for _, resource := range loadTestResources {
+		latencies := []time.Duration{}
+		for key, createdAt := range t.createdAt[resource] {
+			syncedAt, ok := t.syncedAt[resource][key]
+			if !ok {
+				continue
+			}
+
+			latencies = append(latencies, max(syncedAt.Sub(createdAt), 0))
+		}
+		sort.Slice(latencies, func(i, j int) bool {
+			return latencies[i] < latencies[j]
+		})
+
+		report := LoadTestResourceReport{
+			Resource: resource,
+			Created:  len(t.createdAt[resource]),
+			Synced:   len(latencies),
+			P50:      percentile(latencies, 50),
+			P90:      percentile(latencies, 90),
+			P99:      percentile(latencies, 99),
+		}
+		if len(latencies) > 0 {
+			report.Max = latencies[len(latencies)-1]
+		}
+		reports = append(reports, report)
+	}
+
+	return reports
+}
+
+// percentile returns the nearest-rank percentile of the sorted durations
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	return sorted[min(max(rank, 1), len(sorted))-1]
+}
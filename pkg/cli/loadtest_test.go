@@ -0,0 +1,86 @@
+package cli
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestPercentile(t *testing.T) {
+	assert.Equal(t, percentile(nil, 50), time.Duration(0))
+
+	sorted := []time.Duration{}
+	for i := 1; i <= 10; i++ {
+		sorted = append(sorted, time.Duration(i)*time.Millisecond)
+	}
+	assert.Equal(t, percentile(sorted, 0), time.Millisecond)
+	assert.Equal(t, percentile(sorted, 50), 5*time.Millisecond)
+	assert.Equal(t, percentile(sorted, 90), 9*time.Millisecond)
+	assert.Equal(t, percentile(sorted, 99), 10*time.Millisecond)
+	assert.Equal(t, percentile(sorted, 100), 10*time.Millisecond)
+}
+
+func TestSumRequestCount(t *testing.T) {
+	raw := `# HELP apiserver_request_total [STABLE] Counter of apiserver requests
+# TYPE apiserver_request_total counter
+apiserver_request_total{code="200",resource="pods",verb="LIST"} 120
+apiserver_request_total{code="201",resource="pods",verb="POST"} 30
+# HELP apiserver_current_inflight_requests [STABLE] Maximal number of currently used inflight request limit
+# TYPE apiserver_current_inflight_requests gauge
+apiserver_current_inflight_requests{request_kind="mutating"} 2
+`
+	count, err := sumRequestCount([]byte(raw))
+	assert.NilError(t, err)
+	assert.Equal(t, count, int64(150))
+
+	_, err = sumRequestCount([]byte("# TYPE other counter\nother 1\n"))
+	assert.ErrorContains(t, err, "metric apiserver_request_total not found")
+}
+
+func TestLoadTestTracker(t *testing.T) {
+	tracker := newLoadTestTracker("vcluster-loadtest-abc")
+	tracker.created(LoadTestResourcePods, "vcluster-loadtest-abc-0", "loadtest-0")
+	tracker.created(LoadTestResourcePods, "vcluster-loadtest-abc-0", "loadtest-1")
+	assert.Assert(t, !tracker.done())
+
+	// host objects of other namespaces are ignored
+	tracker.synced(LoadTestResourcePods, "default", "loadtest-0")
+	tracker.synced(LoadTestResourcePods, "vcluster-loadtest-abc-0", "loadtest-0")
+	assert.Assert(t, !tracker.done())
+
+	report := tracker.report()
+	assert.Equal(t, len(report), len(loadTestResources))
+	for _, resource := range report {
+		if resource.Resource == LoadTestResourcePods {
+			assert.Equal(t, resource.Created, 2)
+			assert.Equal(t, resource.Synced, 1)
+			assert.Equal(t, resource.Max, resource.P99)
+		} else {
+			assert.Equal(t, resource.Created, 0)
+		}
+	}
+
+	tracker.synced(LoadTestResourcePods, "vcluster-loadtest-abc-0", "loadtest-1")
+	assert.Assert(t, tracker.done())
+}
+
+func TestCreateLoadTestNamespace(t *testing.T) {
+	vKubeClient := fake.NewSimpleClientset()
+	tracker := newLoadTestTracker("vcluster-loadtest-abc")
+	err := createLoadTestNamespace(context.Background(), vKubeClient, "vcluster-loadtest-abc-0", &LoadTestOptions{Objects: 2, Image: DefaultLoadTestImage}, tracker)
+	assert.NilError(t, err)
+
+	pods, err := vKubeClient.CoreV1().Pods("vcluster-loadtest-abc-0").List(context.Background(), metav1.ListOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, len(pods.Items), 2)
+
+	// the pods mount the secrets, so the syncer syncs them to the host cluster
+	assert.Equal(t, pods.Items[0].Spec.Volumes[0].Secret.SecretName, pods.Items[0].Name)
+	for _, resource := range tracker.report() {
+		assert.Equal(t, resource.Created, 2)
+	}
+}
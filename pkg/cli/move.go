@@ -0,0 +1,403 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/vcluster/config"
+	cliconfig "github.com/loft-sh/vcluster/pkg/cli/config"
+	"github.com/loft-sh/vcluster/pkg/cli/find"
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/loft-sh/vcluster/pkg/platform/backup"
+	"github.com/loft-sh/vcluster/pkg/util/clihelper"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+type MoveOptions struct {
+	// TargetContext is the kube context of the cluster the virtual cluster is moved to, defaults to the current context
+	TargetContext string
+
+	// TargetNamespace is the namespace the virtual cluster is moved to, defaults to the namespace of the virtual cluster
+	TargetNamespace string
+
+	// KeepSource keeps the paused source virtual cluster instead of deleting it
+	KeepSource bool
+
+	// ForceDeleteSource deletes the source even if it has synced persistent volume claims, whose data is not moved
+	ForceDeleteSource bool
+
+	// SnapshotLocation is a local path or presigned url the snapshot of the source is saved to, only used for
+	// virtual clusters without an external database
+	SnapshotLocation string
+
+	// ChartRepo is the repo of the vcluster chart the target is deployed from
+	ChartRepo string
+
+	RollbackTimeout time.Duration
+}
+
+// Move moves the virtual cluster into another namespace or cluster. The virtual cluster is deployed with the same
+// chart version and config into the target. Virtual clusters with an external database, which is shared
+// with the target, are paused before the target is started with the same data. For all other backing stores
+// the objects of the source are snapshotted right before it is paused and restored into the target. Finally the
+// kube context of the source is replaced by one of the target.
+//
+// The data of persistent volumes is not moved, so the source is kept if it has synced persistent volume claims unless
+// ForceDeleteSource is set. Load balancer services and ingresses get new addresses in the target, DNS records that
+// point to the addresses of the source have to be switched over by the user.
+func Move(ctx context.Context, globalFlags *flags.GlobalFlags, vClusterName string, options *MoveOptions, log log.Logger) error {
+	source, err := find.GetVCluster(ctx, globalFlags.Context, vClusterName, globalFlags.Namespace, log)
+	if err != nil {
+		return err
+	}
+	targetContext, targetNamespace, err := moveTarget(source, options)
+	if err != nil {
+		return err
+	}
+	release, err := vClusterRelease(ctx, source, vClusterName)
+	if err != nil {
+		return err
+	}
+	if isLegacyVCluster(release.Chart.Metadata.Version) {
+		return fmt.Errorf("vcluster %s uses chart version %s, please upgrade it to v0.20 or newer before moving it", vClusterName, release.Chart.Metadata.Version)
+	}
+
+	values := release.Config
+	if values == nil {
+		values = map[string]interface{}{}
+	}
+	vConfig, err := releaseVClusterConfig(values)
+	if err != nil {
+		return err
+	}
+
+	// make sure we don't overwrite another virtual cluster in the target
+	_, err = find.GetVCluster(ctx, targetContext, vClusterName, targetNamespace, log)
+	if err == nil {
+		return fmt.Errorf("vcluster %s already exists in namespace %s of context %s", vClusterName, targetNamespace, targetContext)
+	}
+	var errorNotFound *find.VClusterNotFoundError
+	if !errors.As(err, &errorNotFound) {
+		return err
+	}
+
+	sourceClient, err := vClusterHostClient(source)
+	if err != nil {
+		return err
+	}
+	volumeClaims, err := syncedVolumeClaims(ctx, sourceClient, source.Namespace, vClusterName)
+	if err != nil {
+		return err
+	}
+	endpoints, err := syncedEndpoints(ctx, sourceClient, source.Namespace, vClusterName)
+	if err != nil {
+		return err
+	}
+	keepSource := options.KeepSource
+	if len(volumeClaims) > 0 {
+		log.Warnf("The data of the persistent volume claims %s of vcluster %s is not moved, the target starts with empty volumes", strings.Join(volumeClaims, ", "), vClusterName)
+		if !keepSource && !options.ForceDeleteSource {
+			log.Warnf("The source is kept so no data is lost, copy the data into the volumes of the target and delete the source afterwards or use --force-delete-source")
+			keepSource = true
+		}
+	}
+
+	sourceFlags := *globalFlags
+	sourceFlags.Context = source.Context
+	sourceFlags.Namespace = source.Namespace
+	targetFlags := *globalFlags
+	targetFlags.Context = targetContext
+	targetFlags.Namespace = targetNamespace
+
+	if sharedBackingStore(vConfig.BackingStoreType()) {
+		err = moveSharedBackingStore(ctx, &sourceFlags, &targetFlags, vClusterName, release.Chart.Metadata.Version, values, vConfig, options, log)
+	} else {
+		err = moveSnapshot(ctx, &sourceFlags, &targetFlags, source, vClusterName, release.Chart.Metadata.Version, values, options, log)
+	}
+	if err != nil {
+		return err
+	}
+
+	err = switchKubeContext(ctx, &targetFlags, source, vClusterName, log)
+	if err != nil {
+		return fmt.Errorf("switch kube context: %w", err)
+	}
+
+	if len(endpoints) > 0 {
+		log.Warnf("The load balancer services and ingresses %s of vcluster %s get new addresses in the target, please update DNS records and clients that point to the addresses of the source", strings.Join(endpoints, ", "), vClusterName)
+	}
+
+	if keepSource {
+		log.Infof("Keeping the paused source vcluster %s in namespace %s of context %s", vClusterName, source.Namespace, source.Context)
+	} else {
+		err = DeleteHelm(ctx, &DeleteOptions{
+			Wait:                true,
+			AutoDeleteNamespace: true,
+			ForceCleanup:        true,
+		}, &sourceFlags, vClusterName, log)
+		if err != nil {
+			return fmt.Errorf("delete source: %w", err)
+		}
+	}
+
+	log.Donef("Successfully moved vcluster %s into namespace %s of context %s", vClusterName, targetNamespace, targetContext)
+	return nil
+}
+
+// sharedBackingStore returns true if the target uses the same data as the source with the same config. A deployed
+// etcd runs next to the control plane, so it is not shared.
+func sharedBackingStore(storeType config.StoreType) bool {
+	return storeType == config.StoreTypeExternalDatabase
+}
+
+// moveSharedBackingStore deploys the target scaled to zero and starts it once the source is paused, because both
+// virtual clusters would write to the same backing store otherwise
+func moveSharedBackingStore(ctx context.Context, sourceFlags, targetFlags *flags.GlobalFlags, vClusterName, chartVersion string, values map[string]interface{}, vConfig *config.Config, options *MoveOptions, log log.Logger) error {
+	standby, err := standbyValues(values, vConfig, strings.Join([]string{sourceFlags.Context, sourceFlags.Namespace, vClusterName}, "/"))
+	if err != nil {
+		return err
+	}
+	promoted, _, err := promotedValues(standby)
+	if err != nil {
+		return err
+	}
+
+	log.Infof("Deploy vcluster %s into namespace %s of context %s...", vClusterName, targetFlags.Namespace, targetFlags.Context)
+	err = deployReleaseValues(ctx, targetFlags, vClusterName, options.ChartRepo, chartVersion, standby, 0, log)
+	if err != nil {
+		return fmt.Errorf("deploy target: %w", err)
+	}
+
+	err = PauseHelm(ctx, sourceFlags, vClusterName, log)
+	if err != nil {
+		return fmt.Errorf("pause source: %w", err)
+	}
+
+	err = deployReleaseValues(ctx, targetFlags, vClusterName, options.ChartRepo, chartVersion, promoted, options.RollbackTimeout, log)
+	if err != nil {
+		return resumeSource(ctx, sourceFlags, vClusterName, fmt.Errorf("start target: %w", err), log)
+	}
+
+	return nil
+}
+
+// moveSnapshot deploys and starts the target with its own backing store, then takes a snapshot of the source, pauses
+// it and restores the snapshot into the target. Changes to the source between the snapshot and the pause are lost.
+func moveSnapshot(ctx context.Context, sourceFlags, targetFlags *flags.GlobalFlags, source *find.VCluster, vClusterName, chartVersion string, values map[string]interface{}, options *MoveOptions, log log.Logger) error {
+	log.Infof("Deploy vcluster %s into namespace %s of context %s...", vClusterName, targetFlags.Namespace, targetFlags.Context)
+	err := deployReleaseValues(ctx, targetFlags, vClusterName, options.ChartRepo, chartVersion, values, options.RollbackTimeout, log)
+	if err != nil {
+		return fmt.Errorf("deploy target: %w", err)
+	}
+
+	log.Infof("Snapshot vcluster %s in namespace %s of context %s...", vClusterName, source.Namespace, source.Context)
+	archive, err := snapshotVClusterWithConnect(ctx, sourceFlags, source, nil, log)
+	if err != nil {
+		return deleteTarget(ctx, targetFlags, vClusterName, fmt.Errorf("snapshot source: %w", err), log)
+	}
+	if options.SnapshotLocation != "" {
+		err = backup.Upload(ctx, options.SnapshotLocation, archive)
+		if err != nil {
+			return deleteTarget(ctx, targetFlags, vClusterName, fmt.Errorf("save snapshot: %w", err), log)
+		}
+		log.Infof("Saved snapshot to %s", options.SnapshotLocation)
+	}
+
+	err = PauseHelm(ctx, sourceFlags, vClusterName, log)
+	if err != nil {
+		return deleteTarget(ctx, targetFlags, vClusterName, fmt.Errorf("pause source: %w", err), log)
+	}
+
+	log.Infof("Restore snapshot into vcluster %s in namespace %s of context %s...", vClusterName, targetFlags.Namespace, targetFlags.Context)
+	err = restoreVClusterSnapshotWithConnect(ctx, targetFlags, vClusterName, archive, log)
+	if err != nil {
+		err = deleteTarget(ctx, targetFlags, vClusterName, fmt.Errorf("restore target: %w", err), log)
+		return resumeSource(ctx, sourceFlags, vClusterName, err, log)
+	}
+
+	return nil
+}
+
+func snapshotVClusterWithConnect(ctx context.Context, globalFlags *flags.GlobalFlags, vCluster *find.VCluster, labels map[string]string, log log.Logger) ([]byte, error) {
+	vRestConfig, stop, err := vClusterRestConfig(ctx, globalFlags, vCluster, "snapshot", log)
+	if err != nil {
+		return nil, err
+	}
+	defer stop()
+
+	return snapshotVCluster(ctx, vRestConfig, labels, log)
+}
+
+func restoreVClusterSnapshotWithConnect(ctx context.Context, globalFlags *flags.GlobalFlags, vClusterName string, archive []byte, log log.Logger) error {
+	vCluster, err := find.GetVCluster(ctx, globalFlags.Context, vClusterName, globalFlags.Namespace, log)
+	if err != nil {
+		return err
+	}
+	vRestConfig, stop, err := vClusterRestConfig(ctx, globalFlags, vCluster, "restore", log)
+	if err != nil {
+		return err
+	}
+	defer stop()
+
+	return restoreVClusterSnapshot(ctx, vRestConfig, archive, log)
+}
+
+// resumeSource resumes the paused source after the move failed and returns the failure
+func resumeSource(ctx context.Context, sourceFlags *flags.GlobalFlags, vClusterName string, moveErr error, log log.Logger) error {
+	log.Warnf("Moving failed, resuming vcluster %s in namespace %s of context %s", vClusterName, sourceFlags.Namespace, sourceFlags.Context)
+	err := ResumeHelm(ctx, sourceFlags, vClusterName, log)
+	if err != nil {
+		return fmt.Errorf("%w, resume source: %w", moveErr, err)
+	}
+
+	return moveErr
+}
+
+// deleteTarget deletes the target that was deployed by the failed move and returns the failure
+func deleteTarget(ctx context.Context, targetFlags *flags.GlobalFlags, vClusterName string, moveErr error, log log.Logger) error {
+	log.Warnf("Moving failed, deleting vcluster %s in namespace %s of context %s", vClusterName, targetFlags.Namespace, targetFlags.Context)
+	err := DeleteHelm(ctx, &DeleteOptions{
+		Wait:                true,
+		AutoDeleteNamespace: true,
+		ForceCleanup:        true,
+		CleanupPVCs:         true,
+	}, targetFlags, vClusterName, log)
+	if err != nil {
+		return fmt.Errorf("%w, delete target: %w", moveErr, err)
+	}
+
+	return moveErr
+}
+
+// switchKubeContext replaces the kube context of the source virtual cluster with a context of the target, so clients
+// using the context reach the new endpoint. The target context is only written if the target is reachable without
+// port-forwarding, for example if it is exposed via a load balancer or through the background proxy.
+func switchKubeContext(ctx context.Context, targetFlags *flags.GlobalFlags, source *find.VCluster, vClusterName string, log log.Logger) error {
+	rawConfig, err := source.ClientFactory.RawConfig()
+	if err != nil {
+		return err
+	}
+	sourceContext := find.VClusterContextName(vClusterName, source.Namespace, source.Context)
+	_, hasContext := rawConfig.Contexts[sourceContext]
+	if !hasContext {
+		return nil
+	}
+	wasCurrent := rawConfig.CurrentContext == sourceContext
+	err = deleteContext(&rawConfig, sourceContext, source.Context)
+	if err != nil {
+		return err
+	}
+	unregisterContext(targetFlags.LoadedConfig(log), sourceContext, log)
+
+	target, err := find.GetVCluster(ctx, targetFlags.Context, vClusterName, targetFlags.Namespace, log)
+	if err != nil {
+		return err
+	}
+	connectCmd := &connectHelm{
+		GlobalFlags:    targetFlags,
+		ConnectOptions: &ConnectOptions{BackgroundProxy: true},
+		Log:            log,
+	}
+	err = connectCmd.prepare(ctx, target)
+	if err != nil {
+		return err
+	}
+	kubeConfig, err := connectCmd.getVClusterKubeConfig(ctx, vClusterName, nil)
+	if err != nil {
+		return err
+	}
+	if connectCmd.portForwarding {
+		close(connectCmd.interruptChan)
+		<-connectCmd.errorChan
+		log.Warnf("Removed kube context %s, vcluster %s is only reachable via port-forwarding, run `vcluster connect %s --context %s -n %s` to connect to it", sourceContext, vClusterName, vClusterName, targetFlags.Context, targetFlags.Namespace)
+		return nil
+	}
+
+	targetContext := connectCmd.KubeConfigContextName
+	err = clihelper.UpdateKubeConfig(targetContext, kubeConfig.Clusters[targetContext], kubeConfig.AuthInfos[targetContext], wasCurrent)
+	if err != nil {
+		return err
+	}
+	registerContext(targetFlags.LoadedConfig(log), targetContext, cliconfig.KubeContext{
+		Driver:        cliconfig.HelmDriver,
+		Name:          vClusterName,
+		Namespace:     target.Namespace,
+		ParentContext: target.Context,
+	}, log)
+
+	log.Donef("Replaced kube context %s with %s", sourceContext, targetContext)
+	return nil
+}
+
+// vClusterHostClient returns a client for the host cluster of the virtual cluster
+func vClusterHostClient(vCluster *find.VCluster) (kubernetes.Interface, error) {
+	restConfig, err := vCluster.ClientFactory.ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	return kubernetes.NewForConfig(restConfig)
+}
+
+// syncedVolumeClaims returns the names of the persistent volume claims the virtual cluster synced into its namespace
+func syncedVolumeClaims(ctx context.Context, kubeClient kubernetes.Interface, namespace, vClusterName string) ([]string, error) {
+	pvcs, err := kubeClient.CoreV1().PersistentVolumeClaims(namespace).List(ctx, metav1.ListOptions{LabelSelector: translate.MarkerLabel + "=" + vClusterName})
+	if err != nil {
+		return nil, fmt.Errorf("list persistent volume claims: %w", err)
+	}
+
+	names := []string{}
+	for _, pvc := range pvcs.Items {
+		names = append(names, pvc.Name)
+	}
+	return names, nil
+}
+
+// syncedEndpoints returns the load balancer services and ingresses the virtual cluster synced into its namespace,
+// which are reachable under a different address once the virtual cluster is moved
+func syncedEndpoints(ctx context.Context, kubeClient kubernetes.Interface, namespace, vClusterName string) ([]string, error) {
+	listOptions := metav1.ListOptions{LabelSelector: translate.MarkerLabel + "=" + vClusterName}
+	services, err := kubeClient.CoreV1().Services(namespace).List(ctx, listOptions)
+	if err != nil {
+		return nil, fmt.Errorf("list services: %w", err)
+	}
+	ingresses, err := kubeClient.NetworkingV1().Ingresses(namespace).List(ctx, listOptions)
+	if err != nil {
+		return nil, fmt.Errorf("list ingresses: %w", err)
+	}
+
+	names := []string{}
+	for _, service := range services.Items {
+		if service.Spec.Type == corev1.ServiceTypeLoadBalancer {
+			names = append(names, "service/"+service.Name)
+		}
+	}
+	for _, ingress := range ingresses.Items {
+		names = append(names, "ingress/"+ingress.Name)
+	}
+	return names, nil
+}
+
+// moveTarget returns the context and namespace the virtual cluster is moved to
+func moveTarget(source *find.VCluster, options *MoveOptions) (string, string, error) {
+	targetContext := options.TargetContext
+	if targetContext == "" {
+		targetContext = source.Context
+	}
+	targetNamespace := options.TargetNamespace
+	if targetNamespace == "" {
+		targetNamespace = source.Namespace
+	}
+	if targetContext == source.Context && targetNamespace == source.Namespace {
+		return "", "", fmt.Errorf("vcluster %s is already in namespace %s of context %s, please specify a different --target-context or --target-namespace", source.Name, source.Namespace, source.Context)
+	}
+
+	return targetContext, targetNamespace, nil
+}
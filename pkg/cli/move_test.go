@@ -0,0 +1,55 @@
+package cli
+
+import (
+	"context"
+	"testing"
+
+	"github.com/loft-sh/vcluster/pkg/cli/find"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestMoveTarget(t *testing.T) {
+	source := &find.VCluster{Name: "my-vcluster", Namespace: "vcluster-ns", Context: "source"}
+
+	targetContext, targetNamespace, err := moveTarget(source, &MoveOptions{TargetNamespace: "other-ns"})
+	assert.NilError(t, err)
+	assert.Equal(t, targetContext, "source")
+	assert.Equal(t, targetNamespace, "other-ns")
+
+	targetContext, targetNamespace, err = moveTarget(source, &MoveOptions{TargetContext: "target"})
+	assert.NilError(t, err)
+	assert.Equal(t, targetContext, "target")
+	assert.Equal(t, targetNamespace, "vcluster-ns")
+
+	_, _, err = moveTarget(source, &MoveOptions{})
+	assert.ErrorContains(t, err, "is already in namespace vcluster-ns")
+
+	_, _, err = moveTarget(source, &MoveOptions{TargetContext: "source", TargetNamespace: "vcluster-ns"})
+	assert.ErrorContains(t, err, "is already in namespace vcluster-ns")
+}
+
+func TestSyncedHostObjects(t *testing.T) {
+	synced := func(name string) metav1.ObjectMeta {
+		return metav1.ObjectMeta{Name: name, Namespace: "vcluster-ns", Labels: map[string]string{translate.MarkerLabel: "my-vcluster"}}
+	}
+	kubeClient := fake.NewSimpleClientset(
+		&corev1.PersistentVolumeClaim{ObjectMeta: synced("data-x-default-x-my-vcluster")},
+		&corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: "data-my-vcluster-0", Namespace: "vcluster-ns"}},
+		&corev1.Service{ObjectMeta: synced("web-x-default-x-my-vcluster"), Spec: corev1.ServiceSpec{Type: corev1.ServiceTypeLoadBalancer}},
+		&corev1.Service{ObjectMeta: synced("db-x-default-x-my-vcluster"), Spec: corev1.ServiceSpec{Type: corev1.ServiceTypeClusterIP}},
+		&networkingv1.Ingress{ObjectMeta: synced("web-x-default-x-my-vcluster")},
+	)
+
+	volumeClaims, err := syncedVolumeClaims(context.Background(), kubeClient, "vcluster-ns", "my-vcluster")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, volumeClaims, []string{"data-x-default-x-my-vcluster"})
+
+	endpoints, err := syncedEndpoints(context.Background(), kubeClient, "vcluster-ns", "my-vcluster")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, endpoints, []string{"service/web-x-default-x-my-vcluster", "ingress/web-x-default-x-my-vcluster"})
+}
@@ -0,0 +1,125 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/vcluster/config"
+	pkgconfig "github.com/loft-sh/vcluster/pkg/config"
+	"github.com/loft-sh/vcluster/pkg/strvals"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// PolicyEnv is used as policy if --policy is not set, so platform teams can enforce a policy for all users of a machine
+	// or ci runner
+	PolicyEnv = "VCLUSTER_POLICY"
+
+	// PolicyConfigMapKey is the key of the policy within a configmap://<namespace>/<name> policy
+	PolicyConfigMapKey = "policy.yaml"
+
+	policyConfigMapPrefix = "configmap://"
+)
+
+// loadPolicy reads the policy from a local file, an https:// or oci:// reference or a configmap://<namespace>/<name> in the
+// host cluster. The kube client is only needed for configmap policies.
+func loadPolicy(ctx context.Context, source string, kubeClient kubernetes.Interface) (*pkgconfig.Policy, error) {
+	var rawPolicy []byte
+	var err error
+	switch {
+	case strings.HasPrefix(source, policyConfigMapPrefix):
+		namespace, name, found := strings.Cut(strings.TrimPrefix(source, policyConfigMapPrefix), "/")
+		if !found || namespace == "" || name == "" {
+			return nil, fmt.Errorf("invalid policy %s, expected configmap://<namespace>/<name>", source)
+		} else if kubeClient == nil {
+			return nil, fmt.Errorf("policy %s requires access to the host cluster", source)
+		}
+
+		configMap, err := kubeClient.CoreV1().ConfigMaps(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("get policy configmap: %w", err)
+		} else if configMap.Data[PolicyConfigMapKey] == "" {
+			return nil, fmt.Errorf("policy configmap %s/%s has no %s", namespace, name, PolicyConfigMapKey)
+		}
+
+		rawPolicy = []byte(configMap.Data[PolicyConfigMapKey])
+	case isRemoteValues(source):
+		rawPolicy, err = fetchRemoteValues(ctx, source, http.DefaultClient, dockerCredentials)
+	default:
+		rawPolicy, err = os.ReadFile(source)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read policy %s: %w", source, err)
+	}
+
+	return pkgconfig.ParsePolicy(rawPolicy)
+}
+
+// enforcePolicy validates the merged values against the policy and fails if a rule is violated that is not exempted by
+// one of the override tokens
+func enforcePolicy(ctx context.Context, source string, overrideTokens []string, finalValues string, kubeClient kubernetes.Interface, log log.Logger) error {
+	violations, overridden, err := evaluatePolicy(ctx, source, overrideTokens, finalValues, kubeClient)
+	if err != nil {
+		return err
+	}
+
+	for _, violation := range overridden {
+		log.Warnf("Policy violation exempted by override token: %s", violation)
+	}
+	if len(violations) == 0 {
+		return nil
+	}
+
+	messages := make([]string, 0, len(violations))
+	for _, violation := range violations {
+		messages = append(messages, "- "+violation.String())
+	}
+	return fmt.Errorf("values violate the policy:\n%s", strings.Join(messages, "\n"))
+}
+
+// evaluatePolicy returns the violated and the exempted rules of the policy. The policy is taken from $VCLUSTER_POLICY
+// if source is empty, without any policy nothing is violated.
+func evaluatePolicy(ctx context.Context, source string, overrideTokens []string, finalValues string, kubeClient kubernetes.Interface) ([]pkgconfig.PolicyViolation, []pkgconfig.PolicyViolation, error) {
+	if source == "" {
+		source = os.Getenv(PolicyEnv)
+		if source == "" {
+			return nil, nil, nil
+		}
+	}
+
+	policy, err := loadPolicy(ctx, source, kubeClient)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	values, err := policyValues(finalValues)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	violations, overridden := policy.Evaluate(values, overrideTokens)
+	return violations, overridden, nil
+}
+
+// policyValues merges the values into the defaults, so rules also apply to values that are not set explicitly
+func policyValues(finalValues string) (map[string]interface{}, error) {
+	defaults := map[string]interface{}{}
+	err := yaml.Unmarshal([]byte(config.Values), &defaults)
+	if err != nil {
+		return nil, fmt.Errorf("parse default values: %w", err)
+	}
+
+	values := map[string]interface{}{}
+	err = yaml.Unmarshal([]byte(finalValues), &values)
+	if err != nil {
+		return nil, fmt.Errorf("parse values: %w", err)
+	}
+
+	return strvals.MergeMaps(defaults, values), nil
+}
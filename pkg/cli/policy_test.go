@@ -0,0 +1,40 @@
+package cli
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/loft-sh/log"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+const telemetryPolicy = "rules:\n- name: telemetry\n  path: telemetry.enabled\n  equals: true\n"
+
+func TestEnforcePolicy(t *testing.T) {
+	t.Setenv(PolicyEnv, "")
+	policyFile := filepath.Join(t.TempDir(), "policy.yaml")
+	assert.NilError(t, os.WriteFile(policyFile, []byte(telemetryPolicy), 0o600))
+	kubeClient := fake.NewSimpleClientset(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "policy", Namespace: "platform"},
+		Data:       map[string]string{PolicyConfigMapKey: telemetryPolicy},
+	})
+
+	// telemetry is enabled by default
+	assert.NilError(t, enforcePolicy(context.Background(), policyFile, nil, "", nil, log.Discard))
+	assert.NilError(t, enforcePolicy(context.Background(), "configmap://platform/policy", nil, "", kubeClient, log.Discard))
+	assert.ErrorContains(t, enforcePolicy(context.Background(), policyFile, nil, "telemetry:\n  enabled: false\n", nil, log.Discard), "telemetry.enabled must be true, but is false (rule telemetry)")
+
+	// the environment variable is used without --policy
+	assert.NilError(t, enforcePolicy(context.Background(), "", nil, "telemetry:\n  enabled: false\n", nil, log.Discard))
+	t.Setenv(PolicyEnv, policyFile)
+	assert.ErrorContains(t, enforcePolicy(context.Background(), "", nil, "telemetry:\n  enabled: false\n", nil, log.Discard), "values violate the policy")
+
+	assert.ErrorContains(t, enforcePolicy(context.Background(), "configmap://platform/other", nil, "", kubeClient, log.Discard), "not found")
+	assert.ErrorContains(t, enforcePolicy(context.Background(), "configmap://platform/policy", nil, "", nil, log.Discard), "requires access to the host cluster")
+	assert.ErrorContains(t, enforcePolicy(context.Background(), "configmap://policy", nil, "", kubeClient, log.Discard), "invalid policy")
+}
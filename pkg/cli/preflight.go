@@ -0,0 +1,525 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/log/table"
+	"github.com/loft-sh/vcluster/config"
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/sirupsen/logrus"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+const (
+	PreflightPassed  = "passed"
+	PreflightWarning = "warning"
+	PreflightFailed  = "failed"
+
+	podSecurityEnforceLabel  = "pod-security.kubernetes.io/enforce"
+	defaultStorageClassLabel = "storageclass.kubernetes.io/is-default-class"
+)
+
+type PreflightOptions struct {
+	Values    []string
+	SetValues []string
+	ExpandEnv bool
+
+	// Output is the output format, either table or json
+	Output string
+}
+
+// PreflightResult is the result of a single preflight check
+type PreflightResult struct {
+	Check   string `json:"check"`
+	Status  string `json:"status"`
+	Message string `json:"message"`
+}
+
+// preflightRequirement is an api group version the virtual cluster needs with the given config
+type preflightRequirement struct {
+	groupVersion string
+	resource     string
+	reason       string
+	required     func(c *config.Config) bool
+}
+
+var preflightRequirements = []preflightRequirement{
+	{groupVersion: "apps/v1", resource: "statefulsets", reason: "the control plane", required: func(*config.Config) bool { return true }},
+	{groupVersion: "rbac.authorization.k8s.io/v1", resource: "roles", reason: "the control plane", required: func(*config.Config) bool { return true }},
+	{groupVersion: "networking.k8s.io/v1", resource: "networkpolicies", reason: "policies.networkPolicy, policies.networkIsolation and sync.toHost.networkPolicies", required: func(c *config.Config) bool {
+		return c.Policies.NetworkPolicy.Enabled || c.Policies.NetworkIsolation.Enabled || c.Sync.ToHost.NetworkPolicies.Enabled
+	}},
+	{groupVersion: "networking.k8s.io/v1", resource: "ingresses", reason: "sync.toHost.ingresses", required: func(c *config.Config) bool { return c.Sync.ToHost.Ingresses.Enabled }},
+	{groupVersion: "scheduling.k8s.io/v1", resource: "priorityclasses", reason: "sync.toHost.priorityClasses", required: func(c *config.Config) bool { return c.Sync.ToHost.PriorityClasses.Enabled }},
+	{groupVersion: "snapshot.storage.k8s.io/v1", resource: "volumesnapshots", reason: "sync.toHost.volumeSnapshots", required: func(c *config.Config) bool { return c.Sync.ToHost.VolumeSnapshots.Enabled }},
+	{groupVersion: "networking.istio.io/v1beta1", resource: "virtualservices", reason: "integrations.istio", required: func(c *config.Config) bool { return c.Integrations.Istio.Enabled }},
+	{groupVersion: "cert-manager.io/v1", resource: "certificates", reason: "integrations.certManager", required: func(c *config.Config) bool { return c.Integrations.CertManager.Enabled }},
+	{groupVersion: "external-secrets.io/v1beta1", resource: "externalsecrets", reason: "integrations.externalSecrets", required: func(c *config.Config) bool { return c.Integrations.ExternalSecrets.Enabled }},
+	{groupVersion: "metrics.k8s.io/v1beta1", resource: "pods", reason: "observability.metrics.proxy", required: func(c *config.Config) bool {
+		return c.Observability.Metrics.Proxy.Nodes || c.Observability.Metrics.Proxy.Pods
+	}},
+}
+
+// Preflight checks if the host cluster is ready for a virtual cluster with the given values before it is created
+func Preflight(ctx context.Context, vClusterName string, options *PreflightOptions, globalFlags *flags.GlobalFlags, log log.Logger) error {
+	if options.Output != "table" && options.Output != "json" {
+		return fmt.Errorf("unsupported output format %s, please use table or json", options.Output)
+	}
+
+	values, cleanupValues, err := resolveValuesFiles(ctx, options.Values, options.ExpandEnv)
+	if err != nil {
+		return err
+	}
+	defer cleanupValues()
+	options.Values = values
+
+	finalValues, err := mergeAllValues(options.SetValues, options.Values, config.Values)
+	if err != nil {
+		return fmt.Errorf("merge values: %w", err)
+	}
+	vClusterConfig := &config.Config{}
+	err = vClusterConfig.UnmarshalYAMLStrict([]byte(finalValues))
+	if err != nil {
+		return err
+	}
+
+	kubeClientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(clientcmd.NewDefaultClientConfigLoadingRules(), &clientcmd.ConfigOverrides{
+		CurrentContext: globalFlags.Context,
+	})
+	restConfig, err := kubeClientConfig.ClientConfig()
+	if err != nil {
+		return err
+	}
+	kubeClient, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+
+	// the namespace is chosen the same way vcluster create does
+	namespace := globalFlags.Namespace
+	if namespace == "" {
+		namespace, _, err = kubeClientConfig.Namespace()
+		if err != nil {
+			return err
+		} else if namespace == "" || namespace == "default" {
+			namespace = "vcluster-" + vClusterName
+		}
+	}
+
+	log.Infof("Running preflight checks for virtual cluster %s in namespace %s...", vClusterName, namespace)
+	results := runPreflightChecks(ctx, kubeClient, vClusterConfig, vClusterName, namespace)
+	if options.Output == "json" {
+		out, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		log.WriteString(logrus.InfoLevel, string(out)+"\n")
+	} else {
+		rows := make([][]string, 0, len(results))
+		for _, result := range results {
+			rows = append(rows, []string{result.Check, strings.ToUpper(result.Status), result.Message})
+		}
+		table.PrintTable(log, []string{"CHECK", "RESULT", "MESSAGE"}, rows)
+	}
+
+	failed := 0
+	for _, result := range results {
+		if result.Status == PreflightFailed {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d preflight checks failed", failed, len(results))
+	} else if options.Output == "table" {
+		log.Donef("All preflight checks passed, run `vcluster create %s -n %s` to create the virtual cluster", vClusterName, namespace)
+	}
+
+	return nil
+}
+
+func runPreflightChecks(ctx context.Context, kubeClient kubernetes.Interface, vClusterConfig *config.Config, vClusterName, namespace string) []PreflightResult {
+	namespaceObj, err := kubeClient.CoreV1().Namespaces().Get(ctx, namespace, metav1.GetOptions{})
+	if err != nil {
+		namespaceObj = nil
+	}
+
+	checks := []struct {
+		name  string
+		check func() (string, string)
+	}{
+		{name: "namespace", check: func() (string, string) { return checkPreflightNamespace(namespaceObj, err, namespace) }},
+		{name: "rbac", check: func() (string, string) {
+			return checkPreflightRBAC(ctx, kubeClient, vClusterConfig, namespace, kerrors.IsNotFound(err))
+		}},
+		{name: "pod-security", check: func() (string, string) {
+			return checkPreflightPodSecurity(kubeClient, vClusterConfig, namespaceObj)
+		}},
+		{name: "storage", check: func() (string, string) { return checkPreflightStorage(ctx, kubeClient, vClusterConfig) }},
+		{name: "node-resources", check: func() (string, string) { return checkPreflightNodeResources(ctx, kubeClient, vClusterConfig) }},
+		{name: "api-groups", check: func() (string, string) { return checkPreflightAPIGroups(kubeClient, vClusterConfig) }},
+		{name: "network-policy-egress", check: func() (string, string) {
+			return checkPreflightEgress(ctx, kubeClient, vClusterConfig, vClusterName, namespace)
+		}},
+	}
+
+	results := make([]PreflightResult, 0, len(checks))
+	for _, c := range checks {
+		status, message := c.check()
+		results = append(results, PreflightResult{Check: c.name, Status: status, Message: message})
+	}
+
+	return results
+}
+
+func checkPreflightNamespace(namespaceObj *corev1.Namespace, err error, namespace string) (string, string) {
+	if kerrors.IsNotFound(err) {
+		return PreflightPassed, fmt.Sprintf("namespace %s will be created", namespace)
+	} else if kerrors.IsForbidden(err) {
+		return PreflightWarning, fmt.Sprintf("not allowed to get namespace %s, make sure it exists", namespace)
+	} else if err != nil {
+		return PreflightFailed, fmt.Sprintf("get namespace %s: %v", namespace, err)
+	} else if namespaceObj.DeletionTimestamp != nil {
+		return PreflightFailed, fmt.Sprintf("namespace %s is terminating", namespace)
+	}
+
+	return PreflightPassed, fmt.Sprintf("namespace %s exists", namespace)
+}
+
+// checkPreflightRBAC checks that the current user may create the resources of the chart
+func checkPreflightRBAC(ctx context.Context, kubeClient kubernetes.Interface, vClusterConfig *config.Config, namespace string, createNamespace bool) (string, string) {
+	attributes := []authorizationv1.ResourceAttributes{
+		{Verb: "create", Group: "apps", Resource: "statefulsets"},
+		{Verb: "create", Resource: "services"},
+		{Verb: "create", Resource: "secrets"},
+		{Verb: "create", Resource: "configmaps"},
+		{Verb: "create", Resource: "serviceaccounts"},
+		{Verb: "create", Group: "rbac.authorization.k8s.io", Resource: "roles"},
+		{Verb: "create", Group: "rbac.authorization.k8s.io", Resource: "rolebindings"},
+	}
+	if vClusterConfig.Policies.NetworkPolicy.Enabled || vClusterConfig.Policies.NetworkIsolation.Enabled {
+		attributes = append(attributes, authorizationv1.ResourceAttributes{Verb: "create", Group: "networking.k8s.io", Resource: "networkpolicies"})
+	}
+	if vClusterConfig.Policies.ResourceQuota.Enabled {
+		attributes = append(attributes, authorizationv1.ResourceAttributes{Verb: "create", Resource: "resourcequotas"})
+	}
+	if vClusterConfig.Policies.LimitRange.Enabled {
+		attributes = append(attributes, authorizationv1.ResourceAttributes{Verb: "create", Resource: "limitranges"})
+	}
+	for i := range attributes {
+		attributes[i].Namespace = namespace
+	}
+	if createNamespace {
+		attributes = append(attributes, authorizationv1.ResourceAttributes{Verb: "create", Resource: "namespaces"})
+	}
+	if requiresClusterRole(vClusterConfig) {
+		attributes = append(attributes,
+			authorizationv1.ResourceAttributes{Verb: "create", Group: "rbac.authorization.k8s.io", Resource: "clusterroles"},
+			authorizationv1.ResourceAttributes{Verb: "create", Group: "rbac.authorization.k8s.io", Resource: "clusterrolebindings"},
+		)
+	}
+
+	denied := []string{}
+	for _, attribute := range attributes {
+		review, err := kubeClient.AuthorizationV1().SelfSubjectAccessReviews().Create(ctx, &authorizationv1.SelfSubjectAccessReview{
+			Spec: authorizationv1.SelfSubjectAccessReviewSpec{ResourceAttributes: &attribute},
+		}, metav1.CreateOptions{})
+		if err != nil {
+			return PreflightWarning, fmt.Sprintf("cannot review permissions: %v", err)
+		} else if !review.Status.Allowed {
+			resource := attribute.Resource
+			if attribute.Group != "" {
+				resource += "." + attribute.Group
+			}
+			denied = append(denied, attribute.Verb+" "+resource)
+		}
+	}
+	if len(denied) > 0 {
+		return PreflightFailed, fmt.Sprintf("missing permissions: %s", strings.Join(denied, ", "))
+	}
+
+	return PreflightPassed, fmt.Sprintf("allowed to create all %d required resources", len(attributes))
+}
+
+// requiresClusterRole mirrors the most common conditions of vcluster.createClusterRole of the chart
+func requiresClusterRole(c *config.Config) bool {
+	switch c.RBAC.ClusterRole.Enabled {
+	case "true":
+		return true
+	case "auto":
+	default:
+		return false
+	}
+
+	return len(c.RBAC.ClusterRole.OverwriteRules) > 0 ||
+		len(c.RBAC.ClusterRole.ExtraRules) > 0 ||
+		len(c.Networking.ReplicateServices.FromHost) > 0 ||
+		c.Sync.ToHost.StorageClasses.Enabled ||
+		c.Sync.ToHost.PersistentVolumes.Enabled ||
+		c.Sync.ToHost.PriorityClasses.Enabled ||
+		c.Sync.ToHost.VolumeSnapshots.Enabled ||
+		c.Sync.FromHost.IngressClasses.Enabled ||
+		c.Sync.FromHost.Nodes.Enabled ||
+		c.Sync.FromHost.Nodes.FakeNodeExtendedResources ||
+		c.Integrations.Istio.Enabled ||
+		c.Integrations.CertManager.Enabled ||
+		c.Integrations.ExternalSecrets.Enabled ||
+		c.Sync.FromHost.StorageClasses.Enabled == "true" ||
+		c.Sync.FromHost.CSINodes.Enabled == "true" ||
+		c.Sync.FromHost.CSIDrivers.Enabled == "true" ||
+		c.Sync.FromHost.CSIStorageCapacities.Enabled == "true" ||
+		c.ControlPlane.Advanced.VirtualScheduler.Enabled ||
+		c.Observability.Metrics.Proxy.Nodes ||
+		c.Experimental.IsolatedControlPlane.Enabled ||
+		c.Experimental.MultiNamespaceMode.Enabled ||
+		slices.ContainsFunc(c.Sync.ToHost.Pods.ImagePullSecrets, func(imagePullSecret config.SyncPodsImagePullSecret) bool {
+			return imagePullSecret.FromSecret.Name != ""
+		})
+}
+
+// checkPreflightPodSecurity checks the pod security admission level of the namespace against the control plane and
+// the workloads of the virtual cluster
+func checkPreflightPodSecurity(kubeClient kubernetes.Interface, vClusterConfig *config.Config, namespaceObj *corev1.Namespace) (string, string) {
+	// pod security policies were removed in kubernetes 1.25, but might still be enforced by older clusters
+	if resources, err := kubeClient.Discovery().ServerResourcesForGroupVersion("policy/v1beta1"); err == nil {
+		for _, apiResource := range resources.APIResources {
+			if apiResource.Name != "podsecuritypolicies" {
+				continue
+			}
+
+			return PreflightWarning, "the host cluster serves pod security policies, make sure the service account of the virtual cluster may use a policy that allows its pods"
+		}
+	}
+
+	level := ""
+	if namespaceObj != nil {
+		level = namespaceObj.Labels[podSecurityEnforceLabel]
+	}
+	switch level {
+	case "restricted":
+		securityContext := vClusterConfig.ControlPlane.StatefulSet.Security.ContainerSecurityContext
+		if runAsNonRoot, _ := securityContext["runAsNonRoot"].(bool); !runAsNonRoot {
+			return PreflightFailed, "the namespace enforces the restricted pod security standard, but the control plane runs as root, set controlPlane.statefulSet.security.containerSecurityContext to run as non root or use another namespace"
+		}
+
+		return PreflightWarning, "the namespace enforces the restricted pod security standard, make sure the control plane and all workloads of the virtual cluster satisfy it"
+	case "baseline":
+		if vClusterConfig.Policies.PodSecurityStandard.Level == "" || vClusterConfig.Policies.PodSecurityStandard.Level == "privileged" {
+			return PreflightWarning, "the namespace enforces the baseline pod security standard, workloads of the virtual cluster violating it will not start, set policies.podSecurityStandard=baseline to reject them in the virtual cluster already"
+		}
+
+		return PreflightPassed, "the namespace enforces the baseline pod security standard"
+	case "", "privileged":
+		return PreflightPassed, "the namespace does not restrict pods"
+	}
+
+	return PreflightWarning, fmt.Sprintf("the namespace enforces the unknown pod security level %s", level)
+}
+
+// checkPreflightStorage checks that the persistent volume claim of the backing store can be provisioned
+func checkPreflightStorage(ctx context.Context, kubeClient kubernetes.Interface, vClusterConfig *config.Config) (string, string) {
+	persistence := vClusterConfig.ControlPlane.StatefulSet.Persistence
+	storeType := vClusterConfig.BackingStoreType()
+	required := len(persistence.VolumeClaimTemplates) > 0 || persistence.VolumeClaim.Enabled == "true" ||
+		(persistence.VolumeClaim.Enabled == "auto" && (storeType == config.StoreTypeEmbeddedDatabase || storeType == config.StoreTypeEmbeddedEtcd))
+	if !required {
+		return PreflightPassed, fmt.Sprintf("the %s backing store does not need a persistent volume", storeType)
+	}
+
+	storageClasses, err := kubeClient.StorageV1().StorageClasses().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return PreflightWarning, fmt.Sprintf("cannot list storage classes: %v", err)
+	}
+
+	if persistence.VolumeClaim.StorageClass != "" {
+		for _, storageClass := range storageClasses.Items {
+			if storageClass.Name == persistence.VolumeClaim.StorageClass {
+				return PreflightPassed, fmt.Sprintf("storage class %s exists", storageClass.Name)
+			}
+		}
+
+		return PreflightFailed, fmt.Sprintf("storage class %s of controlPlane.statefulSet.persistence.volumeClaim.storageClass does not exist", persistence.VolumeClaim.StorageClass)
+	}
+
+	for _, storageClass := range storageClasses.Items {
+		if storageClass.Annotations[defaultStorageClassLabel] == "true" {
+			return PreflightPassed, fmt.Sprintf("default storage class %s exists", storageClass.Name)
+		}
+	}
+
+	return PreflightFailed, fmt.Sprintf("the %s backing store needs a persistent volume, but there is no default storage class, set controlPlane.statefulSet.persistence.volumeClaim.storageClass", storeType)
+}
+
+// checkPreflightNodeResources checks that at least one node has enough unrequested resources for a control plane replica
+func checkPreflightNodeResources(ctx context.Context, kubeClient kubernetes.Interface, vClusterConfig *config.Config) (string, string) {
+	requests := corev1.ResourceList{}
+	for name, value := range vClusterConfig.ControlPlane.StatefulSet.Resources.Requests {
+		quantity, err := resource.ParseQuantity(fmt.Sprint(value))
+		if err != nil {
+			return PreflightWarning, fmt.Sprintf("cannot parse controlPlane.statefulSet.resources.requests.%s: %v", name, err)
+		}
+
+		requests[corev1.ResourceName(name)] = quantity
+	}
+
+	nodes, err := kubeClient.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return PreflightWarning, fmt.Sprintf("cannot list nodes: %v", err)
+	}
+	pods, err := kubeClient.CoreV1().Pods("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return PreflightWarning, fmt.Sprintf("cannot list pods: %v", err)
+	}
+
+	requested := map[string]corev1.ResourceList{}
+	for _, pod := range pods.Items {
+		if pod.Spec.NodeName == "" || pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		if requested[pod.Spec.NodeName] == nil {
+			requested[pod.Spec.NodeName] = corev1.ResourceList{}
+		}
+		for _, container := range pod.Spec.Containers {
+			for name, quantity := range container.Resources.Requests {
+				sum := requested[pod.Spec.NodeName][name]
+				sum.Add(quantity)
+				requested[pod.Spec.NodeName][name] = sum
+			}
+		}
+	}
+
+	fitting := 0
+	for _, node := range nodes.Items {
+		if node.Spec.Unschedulable || !isNodeReady(&node) {
+			continue
+		}
+
+		fits := true
+		for name, quantity := range requests {
+			free := node.Status.Allocatable[name].DeepCopy()
+			free.Sub(requested[node.Name][name])
+			if free.Cmp(quantity) < 0 {
+				fits = false
+				break
+			}
+		}
+		if fits {
+			fitting++
+		}
+	}
+
+	replicas := int(vClusterConfig.ControlPlane.StatefulSet.HighAvailability.Replicas)
+	if replicas < 1 {
+		replicas = 1
+	}
+	if fitting == 0 {
+		return PreflightFailed, fmt.Sprintf("no ready node has enough unrequested resources for the control plane requests %s", formatResourceList(requests))
+	} else if fitting < replicas {
+		return PreflightWarning, fmt.Sprintf("only %d ready node(s) have enough unrequested resources for the %d control plane replicas", fitting, replicas)
+	}
+
+	return PreflightPassed, fmt.Sprintf("%d ready node(s) have enough unrequested resources for the control plane", fitting)
+}
+
+func isNodeReady(node *corev1.Node) bool {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+
+	return false
+}
+
+func formatResourceList(resources corev1.ResourceList) string {
+	parts := []string{}
+	for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory, corev1.ResourceEphemeralStorage} {
+		if quantity, ok := resources[name]; ok {
+			parts = append(parts, fmt.Sprintf("%s=%s", name, quantity.String()))
+		}
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+// checkPreflightAPIGroups checks that the host cluster serves the apis the enabled features need
+func checkPreflightAPIGroups(kubeClient kubernetes.Interface, vClusterConfig *config.Config) (string, string) {
+	missing := []string{}
+	for _, requirement := range preflightRequirements {
+		if !requirement.required(vClusterConfig) {
+			continue
+		}
+
+		served := false
+		resources, err := kubeClient.Discovery().ServerResourcesForGroupVersion(requirement.groupVersion)
+		if err != nil && !kerrors.IsNotFound(err) {
+			return PreflightWarning, fmt.Sprintf("cannot discover %s: %v", requirement.groupVersion, err)
+		} else if err == nil {
+			for _, apiResource := range resources.APIResources {
+				if apiResource.Name == requirement.resource {
+					served = true
+					break
+				}
+			}
+		}
+		if !served {
+			missing = append(missing, fmt.Sprintf("%s %s (needed by %s)", requirement.groupVersion, requirement.resource, requirement.reason))
+		}
+	}
+	if len(missing) > 0 {
+		return PreflightFailed, fmt.Sprintf("the host cluster does not serve %s", strings.Join(missing, ", "))
+	}
+
+	return PreflightPassed, "the host cluster serves all required apis"
+}
+
+// checkPreflightEgress checks that no network policy of the namespace restricts the egress of the control plane, as
+// it needs to reach the host api server and dns
+func checkPreflightEgress(ctx context.Context, kubeClient kubernetes.Interface, vClusterConfig *config.Config, vClusterName, namespace string) (string, string) {
+	networkPolicies, err := kubeClient.NetworkingV1().NetworkPolicies(namespace).List(ctx, metav1.ListOptions{})
+	if kerrors.IsNotFound(err) {
+		return PreflightPassed, "no network policies in the namespace"
+	} else if err != nil {
+		return PreflightWarning, fmt.Sprintf("cannot list network policies: %v", err)
+	}
+
+	controlPlaneLabels := labels.Set{"app": "vcluster", "release": vClusterName}
+	restricting := []string{}
+	for _, networkPolicy := range networkPolicies.Items {
+		restrictsEgress := false
+		for _, policyType := range networkPolicy.Spec.PolicyTypes {
+			if policyType == "Egress" {
+				restrictsEgress = true
+			}
+		}
+		if !restrictsEgress {
+			continue
+		}
+
+		selector, err := metav1.LabelSelectorAsSelector(&networkPolicy.Spec.PodSelector)
+		if err == nil && selector.Matches(controlPlaneLabels) {
+			restricting = append(restricting, networkPolicy.Name)
+		}
+	}
+	if len(restricting) > 0 {
+		return PreflightWarning, fmt.Sprintf("network policies %s restrict the egress of the control plane, make sure it can still reach the host api server and dns", strings.Join(restricting, ", "))
+	} else if vClusterConfig.Policies.NetworkPolicy.Enabled {
+		return PreflightPassed, "no network policy restricts the egress of the control plane, policies.networkPolicy isolates the workloads"
+	} else if vClusterConfig.Policies.NetworkIsolation.Enabled {
+		return PreflightPassed, "no network policy restricts the egress of the control plane, policies.networkIsolation isolates the workloads"
+	}
+
+	return PreflightPassed, "no network policy restricts the egress of the control plane"
+}
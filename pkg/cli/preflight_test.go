@@ -0,0 +1,96 @@
+package cli
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/loft-sh/vcluster/config"
+	"gotest.tools/v3/assert"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+func TestPreflightChecks(t *testing.T) {
+	vClusterConfig, err := config.NewDefaultConfig()
+	assert.NilError(t, err)
+
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node"},
+		Status: corev1.NodeStatus{
+			Allocatable: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1"), corev1.ResourceMemory: resource.MustParse("4Gi"), corev1.ResourceEphemeralStorage: resource.MustParse("10Gi")},
+			Conditions:  []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}},
+		},
+	}
+	busyPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "busy", Namespace: "other"},
+		Spec: corev1.PodSpec{NodeName: "node", Containers: []corev1.Container{{Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("900m")},
+		}}}},
+	}
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "test", Labels: map[string]string{podSecurityEnforceLabel: "restricted"}}}
+	egressPolicy := &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "deny-egress", Namespace: "test"},
+		Spec:       networkingv1.NetworkPolicySpec{PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeEgress}},
+	}
+	storageClass := &storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: "standard", Annotations: map[string]string{defaultStorageClassLabel: "true"}}}
+
+	newClient := func(objects ...runtime.Object) *fake.Clientset {
+		kubeClient := fake.NewSimpleClientset(objects...)
+		kubeClient.Discovery().(*fakediscovery.FakeDiscovery).Resources = []*metav1.APIResourceList{
+			{GroupVersion: "apps/v1", APIResources: []metav1.APIResource{{Name: "statefulsets"}}},
+			{GroupVersion: "rbac.authorization.k8s.io/v1", APIResources: []metav1.APIResource{{Name: "roles"}}},
+		}
+		kubeClient.PrependReactor("create", "selfsubjectaccessreviews", func(action clienttesting.Action) (bool, runtime.Object, error) {
+			review := action.(clienttesting.CreateAction).GetObject().(*authorizationv1.SelfSubjectAccessReview)
+			review.Status.Allowed = review.Spec.ResourceAttributes.Resource != "secrets" && review.Spec.ResourceAttributes.Resource != "clusterroles"
+			return true, review, nil
+		})
+		return kubeClient
+	}
+	statuses := func(results []PreflightResult) map[string]string {
+		out := map[string]string{}
+		for _, result := range results {
+			out[result.Check] = result.Status
+		}
+		return out
+	}
+
+	// a cluster that satisfies all checks except for a missing permission
+	results := runPreflightChecks(context.Background(), newClient(node, storageClass), vClusterConfig, "my-vcluster", "test")
+	assert.DeepEqual(t, statuses(results), map[string]string{
+		"namespace":             PreflightPassed,
+		"rbac":                  PreflightFailed,
+		"pod-security":          PreflightPassed,
+		"storage":               PreflightPassed,
+		"node-resources":        PreflightPassed,
+		"api-groups":            PreflightPassed,
+		"network-policy-egress": PreflightPassed,
+	})
+	assert.Equal(t, results[1].Message, "missing permissions: create secrets")
+	assert.Assert(t, strings.Contains(results[0].Message, "will be created"), results[0].Message)
+
+	// a cluster that fails or warns on all checks
+	vClusterConfig.Sync.ToHost.VolumeSnapshots.Enabled = true
+	results = runPreflightChecks(context.Background(), newClient(node, busyPod, namespace, egressPolicy), vClusterConfig, "my-vcluster", "test")
+	assert.DeepEqual(t, statuses(results), map[string]string{
+		"namespace":             PreflightPassed,
+		"rbac":                  PreflightFailed,
+		"pod-security":          PreflightFailed,
+		"storage":               PreflightFailed,
+		"node-resources":        PreflightFailed,
+		"api-groups":            PreflightFailed,
+		"network-policy-egress": PreflightWarning,
+	})
+	assert.Assert(t, strings.Contains(results[1].Message, "create clusterroles.rbac.authorization.k8s.io"), results[1].Message)
+	assert.Assert(t, strings.Contains(results[5].Message, "snapshot.storage.k8s.io/v1 volumesnapshots"), results[5].Message)
+	assert.Assert(t, strings.Contains(results[6].Message, "deny-egress"), results[6].Message)
+}
@@ -0,0 +1,34 @@
+package presets
+
+import (
+	"embed"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+//go:embed *.yaml
+var presets embed.FS
+
+// Names returns the names of all presets
+func Names() []string {
+	entries, _ := presets.ReadDir(".")
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, strings.TrimSuffix(entry.Name(), path.Ext(entry.Name())))
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+// Get returns the vcluster.yaml fragment of the preset
+func Get(name string) ([]byte, error) {
+	values, err := presets.ReadFile(name + ".yaml")
+	if err != nil {
+		return nil, fmt.Errorf("unknown preset %q, please use one of: %s", name, strings.Join(Names(), ", "))
+	}
+
+	return values, nil
+}
@@ -0,0 +1,254 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	managementv1 "github.com/loft-sh/api/v4/pkg/apis/management/v1"
+	storagev1 "github.com/loft-sh/api/v4/pkg/apis/storage/v1"
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/log/table"
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/loft-sh/vcluster/pkg/platform"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+)
+
+const (
+	// QuotaVirtualClusters is the quota of the number of virtual clusters
+	QuotaVirtualClusters = "count/virtualclusterinstances"
+	// QuotaCPU is the quota of the requested cpu of all workloads
+	QuotaCPU = "requests.cpu"
+	// QuotaMemory is the quota of the requested memory of all workloads
+	QuotaMemory = "requests.memory"
+
+	quotaScopeProject = "project"
+	quotaScopeUser    = "per user"
+)
+
+type QuotaOptions struct {
+	Project string
+	Output  string
+
+	// PerUser sets the quotas every user or team has in the project instead of the quotas of the whole project
+	PerUser bool
+
+	VirtualClusters string
+	CPU             string
+	Memory          string
+}
+
+// ProjectQuota is a quota of a project and its usage
+type ProjectQuota struct {
+	// Scope is project, per user, or the user or team the usage belongs to
+	Scope    string `json:"scope"`
+	Resource string `json:"resource"`
+	Limit    string `json:"limit,omitempty"`
+	Used     string `json:"used,omitempty"`
+}
+
+// GetQuotaPlatform prints the quotas of the project and how much of them is used
+func GetQuotaPlatform(ctx context.Context, options *QuotaOptions, globalFlags *flags.GlobalFlags, log log.Logger) error {
+	if options.Project == "" {
+		return errors.New("please specify a project with --project")
+	}
+
+	platformClient, err := platform.InitClientFromConfig(ctx, globalFlags.LoadedConfig(log))
+	if err != nil {
+		return err
+	}
+	managementClient, err := platformClient.Management()
+	if err != nil {
+		return err
+	}
+
+	project, err := managementClient.Loft().ManagementV1().Projects().Get(ctx, options.Project, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("get project %s: %w", options.Project, err)
+	}
+
+	quotas := projectQuotas(project)
+	switch options.Output {
+	case "json", "yaml":
+		out, err := json.MarshalIndent(quotas, "", "  ")
+		if err != nil {
+			return fmt.Errorf("json marshal: %w", err)
+		}
+		if options.Output == "yaml" {
+			out, err = yaml.JSONToYAML(out)
+			if err != nil {
+				return fmt.Errorf("yaml marshal: %w", err)
+			}
+		}
+
+		_, err = os.Stdout.Write(append(out, '\n'))
+		return err
+	case "table", "":
+		if len(quotas) == 0 {
+			log.Infof("Project %s has no quotas", options.Project)
+			return nil
+		}
+
+		rows := [][]string{}
+		for _, quota := range quotas {
+			rows = append(rows, []string{quota.Scope, quota.Resource, quota.Limit, quota.Used})
+		}
+		table.PrintTable(log, []string{"SCOPE", "RESOURCE", "LIMIT", "USED"}, rows)
+		return nil
+	default:
+		return fmt.Errorf("unknown output format %s, please use one of: table, json, yaml", options.Output)
+	}
+}
+
+// SetQuotaPlatform sets or removes quotas of the project. The changes are given as RESOURCE=VALUE, an empty value or
+// RESOURCE- removes the quota.
+func SetQuotaPlatform(ctx context.Context, options *QuotaOptions, globalFlags *flags.GlobalFlags, changes []string, log log.Logger) error {
+	if options.Project == "" {
+		return errors.New("please specify a project with --project")
+	}
+	changes = append(changes, options.flagChanges()...)
+	if len(changes) == 0 {
+		return errors.New("please specify at least one quota to set, e.g. --virtual-clusters 10 or requests.cpu=20")
+	}
+
+	platformClient, err := platform.InitClientFromConfig(ctx, globalFlags.LoadedConfig(log))
+	if err != nil {
+		return err
+	}
+	managementClient, err := platformClient.Management()
+	if err != nil {
+		return err
+	}
+
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		project, err := managementClient.Loft().ManagementV1().Projects().Get(ctx, options.Project, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("get project %s: %w", options.Project, err)
+		}
+
+		if options.PerUser {
+			project.Spec.Quotas.User, err = applyQuotaChanges(project.Spec.Quotas.User, changes)
+		} else {
+			project.Spec.Quotas.Project, err = applyQuotaChanges(project.Spec.Quotas.Project, changes)
+		}
+		if err != nil {
+			return err
+		}
+
+		_, err = managementClient.Loft().ManagementV1().Projects().Update(ctx, project, metav1.UpdateOptions{})
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	scope := quotaScopeProject
+	if options.PerUser {
+		scope = quotaScopeUser
+	}
+	log.Donef("Successfully updated the %s quotas of project %s", scope, options.Project)
+	return nil
+}
+
+func (o *QuotaOptions) flagChanges() []string {
+	changes := []string{}
+	for key, value := range map[string]string{QuotaVirtualClusters: o.VirtualClusters, QuotaCPU: o.CPU, QuotaMemory: o.Memory} {
+		if value != "" {
+			changes = append(changes, key+"="+value)
+		}
+	}
+
+	sort.Strings(changes)
+	return changes
+}
+
+// applyQuotaChanges returns the quotas with the changes applied, the given quotas are not modified
+func applyQuotaChanges(quotas map[string]string, changes []string) (map[string]string, error) {
+	newQuotas := map[string]string{}
+	for key, value := range quotas {
+		newQuotas[key] = value
+	}
+
+	for _, change := range changes {
+		key, value, found := strings.Cut(change, "=")
+		if !found {
+			if !strings.HasSuffix(change, "-") {
+				return nil, fmt.Errorf("invalid quota %q, please use RESOURCE=VALUE to set or RESOURCE- to remove a quota", change)
+			}
+
+			key = strings.TrimSuffix(change, "-")
+		}
+		if key == "" {
+			return nil, fmt.Errorf("invalid quota %q, the resource is empty", change)
+		} else if value == "" {
+			delete(newQuotas, key)
+			continue
+		}
+
+		_, err := resource.ParseQuantity(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value %q of quota %s: %w", value, key, err)
+		}
+		newQuotas[key] = value
+	}
+
+	if len(newQuotas) == 0 {
+		return nil, nil
+	}
+	return newQuotas, nil
+}
+
+// projectQuotas returns the project and per user quotas with their usage, followed by the usage of each user and team
+func projectQuotas(project *managementv1.Project) []ProjectQuota {
+	quotas := []ProjectQuota{}
+	var projectStatus *storagev1.QuotaStatusProject
+	var userStatus *storagev1.QuotaStatusUser
+	if project.Status.Quotas != nil {
+		projectStatus = project.Status.Quotas.Project
+		userStatus = project.Status.Quotas.User
+	}
+
+	for _, key := range sortedKeys(project.Spec.Quotas.Project) {
+		quota := ProjectQuota{Scope: quotaScopeProject, Resource: key, Limit: project.Spec.Quotas.Project[key]}
+		if projectStatus != nil {
+			quota.Used = projectStatus.Used[key]
+		}
+		quotas = append(quotas, quota)
+	}
+	for _, key := range sortedKeys(project.Spec.Quotas.User) {
+		quotas = append(quotas, ProjectQuota{Scope: quotaScopeUser, Resource: key, Limit: project.Spec.Quotas.User[key]})
+	}
+
+	if userStatus != nil {
+		usage := []struct {
+			kind string
+			used map[string]map[string]string
+		}{{"user", userStatus.Used.Users}, {"team", userStatus.Used.Teams}}
+		for _, u := range usage {
+			for _, name := range sortedKeys(u.used) {
+				for _, key := range sortedKeys(u.used[name]) {
+					quotas = append(quotas, ProjectQuota{Scope: u.kind + "/" + name, Resource: key, Limit: project.Spec.Quotas.User[key], Used: u.used[name][key]})
+				}
+			}
+		}
+	}
+
+	return quotas
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"testing"
+
+	managementv1 "github.com/loft-sh/api/v4/pkg/apis/management/v1"
+	storagev1 "github.com/loft-sh/api/v4/pkg/apis/storage/v1"
+	"gotest.tools/v3/assert"
+)
+
+func TestApplyQuotaChanges(t *testing.T) {
+	current := map[string]string{"requests.cpu": "10", "requests.memory": "32Gi"}
+
+	quotas, err := applyQuotaChanges(current, []string{"requests.cpu=20", "requests.memory-", "count/virtualclusterinstances=5"})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, quotas, map[string]string{"requests.cpu": "20", "count/virtualclusterinstances": "5"})
+	assert.Equal(t, current["requests.memory"], "32Gi")
+
+	quotas, err = applyQuotaChanges(current, []string{"requests.cpu=", "requests.memory-"})
+	assert.NilError(t, err)
+	assert.Assert(t, quotas == nil)
+
+	_, err = applyQuotaChanges(current, []string{"requests.cpu"})
+	assert.ErrorContains(t, err, "please use RESOURCE=VALUE")
+	_, err = applyQuotaChanges(current, []string{"requests.cpu=lots"})
+	assert.ErrorContains(t, err, "invalid value")
+	_, err = applyQuotaChanges(current, []string{"=10"})
+	assert.ErrorContains(t, err, "the resource is empty")
+}
+
+func TestQuotaFlagChanges(t *testing.T) {
+	options := &QuotaOptions{VirtualClusters: "10", Memory: "64Gi"}
+	assert.DeepEqual(t, options.flagChanges(), []string{QuotaVirtualClusters + "=10", QuotaMemory + "=64Gi"})
+}
+
+func TestProjectQuotas(t *testing.T) {
+	project := &managementv1.Project{
+		Spec: managementv1.ProjectSpec{
+			ProjectSpec: storagev1.ProjectSpec{
+				Quotas: storagev1.Quotas{
+					Project: map[string]string{"requests.cpu": "20", QuotaVirtualClusters: "10"},
+					User:    map[string]string{QuotaVirtualClusters: "2"},
+				},
+			},
+		},
+		Status: managementv1.ProjectStatus{
+			ProjectStatus: storagev1.ProjectStatus{
+				Quotas: &storagev1.QuotaStatus{
+					Project: &storagev1.QuotaStatusProject{Used: map[string]string{QuotaVirtualClusters: "3"}},
+					User: &storagev1.QuotaStatusUser{
+						Used: storagev1.QuotaStatusUserUsed{
+							Users: map[string]map[string]string{"admin": {QuotaVirtualClusters: "2"}},
+							Teams: map[string]map[string]string{"dev": {QuotaVirtualClusters: "1"}},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	assert.DeepEqual(t, projectQuotas(project), []ProjectQuota{
+		{Scope: "project", Resource: QuotaVirtualClusters, Limit: "10", Used: "3"},
+		{Scope: "project", Resource: "requests.cpu", Limit: "20"},
+		{Scope: "per user", Resource: QuotaVirtualClusters, Limit: "2"},
+		{Scope: "user/admin", Resource: QuotaVirtualClusters, Limit: "2", Used: "2"},
+		{Scope: "team/dev", Resource: QuotaVirtualClusters, Limit: "2", Used: "1"},
+	})
+}
@@ -0,0 +1,438 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/vcluster/config"
+	"github.com/loft-sh/vcluster/pkg/cli/find"
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/util/retry"
+)
+
+// renameTimeout is the time to wait for a persistent volume claim to be deleted during rename
+var renameTimeout = 2 * time.Minute
+
+type RenameOptions struct {
+	// ChartRepo is the repo of the vcluster chart the virtual cluster is deployed from
+	ChartRepo string
+}
+
+// Rename renames the virtual cluster within its namespace. The virtual cluster is paused, the persistent volumes of
+// the control plane and of the synced persistent volume claims are bound to claims with the new name and the virtual
+// cluster is deployed under the new name with the same chart version and config. The syncer then recreates the host
+// objects with names translated for the new name and the release of the old name is deleted.
+func Rename(ctx context.Context, globalFlags *flags.GlobalFlags, vClusterName, newName string, options *RenameOptions, log log.Logger) error {
+	if errs := validation.IsDNS1123Label(newName); len(errs) > 0 {
+		return fmt.Errorf("invalid name %s: %s", newName, strings.Join(errs, ", "))
+	} else if newName == vClusterName {
+		return fmt.Errorf("vcluster is already named %s", newName)
+	}
+
+	vCluster, err := find.GetVCluster(ctx, globalFlags.Context, vClusterName, globalFlags.Namespace, log)
+	if err != nil {
+		return err
+	}
+	release, err := vClusterRelease(ctx, vCluster, vClusterName)
+	if err != nil {
+		return err
+	}
+	if isLegacyVCluster(release.Chart.Metadata.Version) {
+		return fmt.Errorf("vcluster %s uses chart version %s, please upgrade it to v0.20 or newer before renaming it", vClusterName, release.Chart.Metadata.Version)
+	}
+
+	values := release.Config
+	if values == nil {
+		values = map[string]interface{}{}
+	}
+	vConfig, err := releaseVClusterConfig(values)
+	if err != nil {
+		return err
+	}
+	if vConfig.Experimental.MultiNamespaceMode.Enabled {
+		return fmt.Errorf("vcluster %s uses the multi namespace mode, which cannot be renamed", vClusterName)
+	}
+	// etcd members are named after the pods of the virtual cluster, so an etcd cluster won't start under a new name
+	if storeType := vConfig.BackingStoreType(); storeType == config.StoreTypeEmbeddedEtcd || storeType == config.StoreTypeExternalEtcd {
+		return fmt.Errorf("vcluster %s uses the %s backing store, which cannot be renamed", vClusterName, storeType)
+	}
+
+	_, err = find.GetVCluster(ctx, vCluster.Context, newName, vCluster.Namespace, log)
+	if err == nil {
+		return fmt.Errorf("vcluster %s already exists in namespace %s", newName, vCluster.Namespace)
+	}
+	var errorNotFound *find.VClusterNotFoundError
+	if !errors.As(err, &errorNotFound) {
+		return err
+	}
+
+	restConfig, err := vCluster.ClientFactory.ClientConfig()
+	if err != nil {
+		return err
+	}
+	kubeClient, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+
+	renameFlags := *globalFlags
+	renameFlags.Context = vCluster.Context
+	renameFlags.Namespace = vCluster.Namespace
+	err = PauseHelm(ctx, &renameFlags, vClusterName, log)
+	if err != nil {
+		return fmt.Errorf("pause vcluster: %w", err)
+	}
+
+	renamer := &renamer{
+		kubeClient:   kubeClient,
+		flags:        &renameFlags,
+		namespace:    vCluster.Namespace,
+		vClusterName: vClusterName,
+		newName:      newName,
+		log:          log,
+	}
+	err = renamer.rename(ctx, release.Chart.Metadata.Version, values, options)
+	if err != nil {
+		rollbackErr := renamer.rollback(ctx)
+		if rollbackErr != nil {
+			return fmt.Errorf("%w, rollback: %w", err, rollbackErr)
+		}
+
+		return err
+	}
+
+	// the context is renamed before the old release is deleted, which would delete it otherwise
+	err = renameKubeContext(&renameFlags, vCluster, vClusterName, newName, log)
+	if err != nil {
+		log.Warnf("Error renaming kube context: %v", err)
+	}
+
+	// the namespace is still used by the renamed virtual cluster, the host objects synced for the old name are
+	// recreated by the syncer of the new name
+	err = DeleteHelm(ctx, &DeleteOptions{
+		Wait:         true,
+		KeepPVC:      true,
+		ForceCleanup: true,
+	}, &renameFlags, vClusterName, log)
+	if err != nil {
+		return fmt.Errorf("delete vcluster %s: %w", vClusterName, err)
+	}
+
+	log.Donef("Successfully renamed vcluster %s to %s in namespace %s, run `vcluster connect %s -n %s` to connect to it", vClusterName, newName, vCluster.Namespace, newName, vCluster.Namespace)
+	return nil
+}
+
+// renamer moves the data of a paused virtual cluster to a new name and keeps track of the changes, so they can be
+// rolled back if the virtual cluster can't be deployed under the new name
+type renamer struct {
+	kubeClient   kubernetes.Interface
+	flags        *flags.GlobalFlags
+	namespace    string
+	vClusterName string
+	newName      string
+	log          log.Logger
+
+	copiedSecrets []string
+	renamedClaims []renamedClaim
+	deployed      bool
+}
+
+type renamedClaim struct {
+	original *corev1.PersistentVolumeClaim
+	renamed  *corev1.PersistentVolumeClaim
+}
+
+func (r *renamer) rename(ctx context.Context, chartVersion string, values map[string]interface{}, options *RenameOptions) error {
+	// the k3s token encrypts the bootstrap data in the backing store, the other distros keep the ca and service account
+	// keys in the certs secret, so existing tokens and kube configs stay valid
+	for _, secretName := range [][2]string{
+		{"vc-k3s-" + r.vClusterName, "vc-k3s-" + r.newName},
+		{r.vClusterName + "-certs", r.newName + "-certs"},
+	} {
+		copied, err := copySecret(ctx, r.kubeClient, r.namespace, secretName[0], secretName[1])
+		if err != nil {
+			return err
+		} else if copied {
+			r.copiedSecrets = append(r.copiedSecrets, secretName[1])
+		}
+	}
+
+	pvcs, err := r.kubeClient.CoreV1().PersistentVolumeClaims(r.namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("list persistent volume claims: %w", err)
+	}
+	for _, pvc := range pvcs.Items {
+		renamed, ok := renamedVolumeClaim(&pvc, r.vClusterName, r.newName)
+		if !ok {
+			continue
+		} else if pvc.Spec.VolumeName == "" {
+			r.log.Warnf("Persistent volume claim %s is not bound, skipping it", pvc.Name)
+			continue
+		}
+
+		renamed, err = rebindVolumeClaim(ctx, r.kubeClient, &pvc, renamed)
+		if err != nil {
+			return fmt.Errorf("rename persistent volume claim %s: %w", pvc.Name, err)
+		}
+		r.renamedClaims = append(r.renamedClaims, renamedClaim{original: pvc.DeepCopy(), renamed: renamed})
+		r.log.Donef("Renamed persistent volume claim %s to %s", pvc.Name, renamed.Name)
+	}
+
+	r.log.Infof("Deploy vcluster %s into namespace %s...", r.newName, r.namespace)
+	r.deployed = true
+	err = deployReleaseValues(ctx, r.flags, r.newName, options.ChartRepo, chartVersion, values, 0, r.log)
+	if err != nil {
+		return fmt.Errorf("deploy vcluster %s: %w", r.newName, err)
+	}
+
+	return nil
+}
+
+// rollback deletes the release of the new name, binds the persistent volumes to their original claims again and
+// resumes the virtual cluster under its old name
+func (r *renamer) rollback(ctx context.Context) error {
+	r.log.Infof("Roll back renaming vcluster %s to %s...", r.vClusterName, r.newName)
+	if r.deployed {
+		newFlags := *r.flags
+		err := DeleteHelm(ctx, &DeleteOptions{
+			Wait:           true,
+			KeepPVC:        true,
+			IgnoreNotFound: true,
+			ForceCleanup:   true,
+		}, &newFlags, r.newName, r.log)
+		if err != nil {
+			return fmt.Errorf("delete vcluster %s: %w", r.newName, err)
+		}
+	}
+
+	for i := len(r.renamedClaims) - 1; i >= 0; i-- {
+		claim := r.renamedClaims[i]
+		_, err := rebindVolumeClaim(ctx, r.kubeClient, claim.renamed, restoredVolumeClaim(claim.original))
+		if err != nil {
+			return fmt.Errorf("restore persistent volume claim %s: %w", claim.original.Name, err)
+		}
+	}
+
+	for _, secretName := range r.copiedSecrets {
+		err := r.kubeClient.CoreV1().Secrets(r.namespace).Delete(ctx, secretName, metav1.DeleteOptions{})
+		if err != nil && !kerrors.IsNotFound(err) {
+			return fmt.Errorf("delete secret %s: %w", secretName, err)
+		}
+	}
+
+	err := ResumeHelm(ctx, r.flags, r.vClusterName, r.log)
+	if err != nil {
+		return fmt.Errorf("resume vcluster %s: %w", r.vClusterName, err)
+	}
+
+	return nil
+}
+
+// renameKubeContext renames the kube context of the virtual cluster and its cluster and user entries to the context
+// name of the new name
+func renameKubeContext(globalFlags *flags.GlobalFlags, vCluster *find.VCluster, vClusterName, newName string, log log.Logger) error {
+	rawConfig, err := vCluster.ClientFactory.RawConfig()
+	if err != nil {
+		return err
+	}
+
+	oldContext := find.VClusterContextName(vClusterName, vCluster.Namespace, vCluster.Context)
+	newContext := find.VClusterContextName(newName, vCluster.Namespace, vCluster.Context)
+	kubeContext, ok := rawConfig.Contexts[oldContext]
+	if !ok {
+		return nil
+	}
+
+	delete(rawConfig.Contexts, oldContext)
+	rawConfig.Contexts[newContext] = kubeContext
+	if cluster, ok := rawConfig.Clusters[kubeContext.Cluster]; ok && kubeContext.Cluster == oldContext {
+		delete(rawConfig.Clusters, oldContext)
+		rawConfig.Clusters[newContext] = cluster
+		kubeContext.Cluster = newContext
+
+		// port-forwardings and background proxies of the old name are stopped with its release
+		if server, err := url.Parse(cluster.Server); err == nil && (server.Hostname() == "localhost" || server.Hostname() == "127.0.0.1") {
+			log.Warnf("Kube context %s points to %s, run `vcluster connect %s -n %s` to connect to the renamed vcluster", newContext, cluster.Server, newName, vCluster.Namespace)
+		}
+	}
+	if authInfo, ok := rawConfig.AuthInfos[kubeContext.AuthInfo]; ok && kubeContext.AuthInfo == oldContext {
+		delete(rawConfig.AuthInfos, oldContext)
+		rawConfig.AuthInfos[newContext] = authInfo
+		kubeContext.AuthInfo = newContext
+	}
+	if rawConfig.CurrentContext == oldContext {
+		rawConfig.CurrentContext = newContext
+	}
+
+	err = clientcmd.ModifyConfig(clientcmd.NewDefaultClientConfigLoadingRules(), rawConfig, false)
+	if err != nil {
+		return err
+	}
+
+	cfg := globalFlags.LoadedConfig(log)
+	if registered, ok := cfg.Contexts[oldContext]; ok {
+		cfg.UnregisterContext(oldContext)
+		registered.Name = newName
+		registerContext(cfg, newContext, registered, log)
+	}
+
+	log.Donef("Renamed kube context %s to %s", oldContext, newContext)
+	return nil
+}
+
+// renamedVolumeClaim returns a copy of the persistent volume claim of the control plane or synced by the virtual
+// cluster with the name and labels of the new virtual cluster name. Returns false if the claim doesn't belong to the
+// virtual cluster.
+func renamedVolumeClaim(pvc *corev1.PersistentVolumeClaim, vClusterName, newName string) (*corev1.PersistentVolumeClaim, bool) {
+	name := ""
+	if matches := regexp.MustCompile("^data-" + regexp.QuoteMeta(vClusterName) + `(-etcd)?-(\d+)$`).FindStringSubmatch(pvc.Name); matches != nil {
+		name = "data-" + newName + matches[1] + "-" + matches[2]
+	} else if pvc.Labels[translate.MarkerLabel] == vClusterName && pvc.Annotations[translate.NameAnnotation] != "" {
+		name = translate.SingleNamespacePhysicalName(pvc.Annotations[translate.NameAnnotation], pvc.Annotations[translate.NamespaceAnnotation], newName)
+	} else {
+		return nil, false
+	}
+
+	labels := map[string]string{}
+	for k, v := range pvc.Labels {
+		switch {
+		case k == translate.MarkerLabel:
+			labels[k] = newName
+		case k == "release" && v == vClusterName:
+			labels[k] = newName
+		case strings.HasPrefix(k, translate.LabelPrefix):
+			// translated label keys contain the name of the virtual cluster, the syncer adds them again
+		default:
+			labels[k] = v
+		}
+	}
+
+	return copyVolumeClaim(pvc, name, labels), true
+}
+
+// restoredVolumeClaim returns a copy of the persistent volume claim with its original name and labels, which is used
+// to bind the volume to the original claim again
+func restoredVolumeClaim(pvc *corev1.PersistentVolumeClaim) *corev1.PersistentVolumeClaim {
+	return copyVolumeClaim(pvc, pvc.Name, pvc.Labels)
+}
+
+func copyVolumeClaim(pvc *corev1.PersistentVolumeClaim, name string, labels map[string]string) *corev1.PersistentVolumeClaim {
+	annotations := map[string]string{}
+	for k, v := range pvc.Annotations {
+		if strings.HasPrefix(k, "pv.kubernetes.io/") || strings.HasPrefix(k, "volume.kubernetes.io/") {
+			continue
+		}
+		annotations[k] = v
+	}
+
+	return &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   pvc.Namespace,
+			Labels:      labels,
+			Annotations: annotations,
+		},
+		Spec: *pvc.Spec.DeepCopy(),
+	}
+}
+
+// rebindVolumeClaim binds the persistent volume of the old claim to the new claim and deletes the old claim. The
+// volume is retained in between, so its data survives the deletion of the old claim. Returns the created claim.
+func rebindVolumeClaim(ctx context.Context, kubeClient kubernetes.Interface, pvc, renamed *corev1.PersistentVolumeClaim) (*corev1.PersistentVolumeClaim, error) {
+	pv, err := kubeClient.CoreV1().PersistentVolumes().Get(ctx, pvc.Spec.VolumeName, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("get persistent volume %s: %w", pvc.Spec.VolumeName, err)
+	}
+	reclaimPolicy := pv.Spec.PersistentVolumeReclaimPolicy
+	if reclaimPolicy != corev1.PersistentVolumeReclaimRetain {
+		pv.Spec.PersistentVolumeReclaimPolicy = corev1.PersistentVolumeReclaimRetain
+		pv, err = kubeClient.CoreV1().PersistentVolumes().Update(ctx, pv, metav1.UpdateOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("retain persistent volume %s: %w", pv.Name, err)
+		}
+	}
+
+	renamed, err = kubeClient.CoreV1().PersistentVolumeClaims(renamed.Namespace).Create(ctx, renamed, metav1.CreateOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("create persistent volume claim %s: %w", renamed.Name, err)
+	}
+	err = kubeClient.CoreV1().PersistentVolumeClaims(pvc.Namespace).Delete(ctx, pvc.Name, metav1.DeleteOptions{})
+	if err != nil && !kerrors.IsNotFound(err) {
+		return nil, fmt.Errorf("delete persistent volume claim: %w", err)
+	}
+	err = wait.PollUntilContextTimeout(ctx, time.Second, renameTimeout, true, func(ctx context.Context) (bool, error) {
+		_, err := kubeClient.CoreV1().PersistentVolumeClaims(pvc.Namespace).Get(ctx, pvc.Name, metav1.GetOptions{})
+		if kerrors.IsNotFound(err) {
+			return true, nil
+		}
+
+		return false, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("wait for persistent volume claim to be deleted: %w", err)
+	}
+
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		pv, err := kubeClient.CoreV1().PersistentVolumes().Get(ctx, pv.Name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		pv.Spec.ClaimRef = &corev1.ObjectReference{
+			Kind:       "PersistentVolumeClaim",
+			APIVersion: "v1",
+			Namespace:  renamed.Namespace,
+			Name:       renamed.Name,
+			UID:        renamed.UID,
+		}
+		pv.Spec.PersistentVolumeReclaimPolicy = reclaimPolicy
+		_, err = kubeClient.CoreV1().PersistentVolumes().Update(ctx, pv, metav1.UpdateOptions{})
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("bind persistent volume %s: %w", pv.Name, err)
+	}
+
+	return renamed, nil
+}
+
+// copySecret copies the data of the secret to a secret with a new name if it exists. Returns true if the secret was
+// copied.
+func copySecret(ctx context.Context, kubeClient kubernetes.Interface, namespace, name, newName string) (bool, error) {
+	secret, err := kubeClient.CoreV1().Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if kerrors.IsNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, fmt.Errorf("get secret %s: %w", name, err)
+	}
+
+	_, err = kubeClient.CoreV1().Secrets(namespace).Create(ctx, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      newName,
+			Namespace: namespace,
+		},
+		Type: secret.Type,
+		Data: secret.Data,
+	}, metav1.CreateOptions{})
+	if kerrors.IsAlreadyExists(err) {
+		return false, nil
+	} else if err != nil {
+		return false, fmt.Errorf("create secret %s: %w", newName, err)
+	}
+
+	return true, nil
+}
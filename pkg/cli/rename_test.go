@@ -0,0 +1,123 @@
+package cli
+
+import (
+	"context"
+	"testing"
+
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestRenamedVolumeClaim(t *testing.T) {
+	renamed, ok := renamedVolumeClaim(&corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "data-my-vcluster-0",
+			Namespace: "vcluster-ns",
+			Labels:    map[string]string{"app": "vcluster", "release": "my-vcluster"},
+			Annotations: map[string]string{
+				"pv.kubernetes.io/bind-completed": "yes",
+			},
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{VolumeName: "pv-1"},
+	}, "my-vcluster", "new-vcluster")
+	assert.Assert(t, ok)
+	assert.Equal(t, renamed.Name, "data-new-vcluster-0")
+	assert.Equal(t, renamed.Labels["release"], "new-vcluster")
+	assert.Equal(t, renamed.Spec.VolumeName, "pv-1")
+	assert.Equal(t, len(renamed.Annotations), 0)
+
+	renamed, ok = renamedVolumeClaim(&corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "data-my-vcluster-etcd-1", Namespace: "vcluster-ns"},
+	}, "my-vcluster", "new-vcluster")
+	assert.Assert(t, ok)
+	assert.Equal(t, renamed.Name, "data-new-vcluster-etcd-1")
+
+	renamed, ok = renamedVolumeClaim(&corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      translate.SingleNamespacePhysicalName("www", "default", "my-vcluster"),
+			Namespace: "vcluster-ns",
+			Labels: map[string]string{
+				translate.MarkerLabel:                  "my-vcluster",
+				translate.LabelPrefix + "-my-vcluster": "value",
+				"other":                                "value",
+			},
+			Annotations: map[string]string{
+				translate.NameAnnotation:      "www",
+				translate.NamespaceAnnotation: "default",
+			},
+		},
+	}, "my-vcluster", "new-vcluster")
+	assert.Assert(t, ok)
+	assert.Equal(t, renamed.Name, translate.SingleNamespacePhysicalName("www", "default", "new-vcluster"))
+	assert.DeepEqual(t, renamed.Labels, map[string]string{translate.MarkerLabel: "new-vcluster", "other": "value"})
+	assert.Equal(t, renamed.Annotations[translate.NameAnnotation], "www")
+
+	// claims of other virtual clusters are not renamed
+	_, ok = renamedVolumeClaim(&corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "data-other-0", Labels: map[string]string{translate.MarkerLabel: "other"}},
+	}, "my-vcluster", "new-vcluster")
+	assert.Assert(t, !ok)
+}
+
+func TestRebindVolumeClaim(t *testing.T) {
+	pvc := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "data-my-vcluster-0", Namespace: "vcluster-ns"},
+		Spec:       corev1.PersistentVolumeClaimSpec{VolumeName: "pv-1"},
+	}
+	kubeClient := fake.NewSimpleClientset(pvc, &corev1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{Name: "pv-1"},
+		Spec: corev1.PersistentVolumeSpec{
+			PersistentVolumeReclaimPolicy: corev1.PersistentVolumeReclaimDelete,
+			ClaimRef:                      &corev1.ObjectReference{Namespace: "vcluster-ns", Name: "data-my-vcluster-0"},
+		},
+	})
+
+	renamed, ok := renamedVolumeClaim(pvc, "my-vcluster", "new-vcluster")
+	assert.Assert(t, ok)
+	renamed, err := rebindVolumeClaim(context.Background(), kubeClient, pvc, renamed)
+	assert.NilError(t, err)
+
+	_, err = kubeClient.CoreV1().PersistentVolumeClaims("vcluster-ns").Get(context.Background(), "data-my-vcluster-0", metav1.GetOptions{})
+	assert.ErrorContains(t, err, "not found")
+	_, err = kubeClient.CoreV1().PersistentVolumeClaims("vcluster-ns").Get(context.Background(), "data-new-vcluster-0", metav1.GetOptions{})
+	assert.NilError(t, err)
+
+	pv, err := kubeClient.CoreV1().PersistentVolumes().Get(context.Background(), "pv-1", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, pv.Spec.ClaimRef.Name, "data-new-vcluster-0")
+	assert.Equal(t, pv.Spec.PersistentVolumeReclaimPolicy, corev1.PersistentVolumeReclaimDelete)
+
+	// a failed rename binds the volume to the original claim again
+	_, err = rebindVolumeClaim(context.Background(), kubeClient, renamed, restoredVolumeClaim(pvc))
+	assert.NilError(t, err)
+	_, err = kubeClient.CoreV1().PersistentVolumeClaims("vcluster-ns").Get(context.Background(), "data-new-vcluster-0", metav1.GetOptions{})
+	assert.ErrorContains(t, err, "not found")
+	pv, err = kubeClient.CoreV1().PersistentVolumes().Get(context.Background(), "pv-1", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, pv.Spec.ClaimRef.Name, "data-my-vcluster-0")
+}
+
+func TestCopySecret(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-vcluster-certs", Namespace: "vcluster-ns"},
+		Data:       map[string][]byte{"ca.crt": []byte("ca")},
+	})
+
+	copied, err := copySecret(context.Background(), kubeClient, "vcluster-ns", "my-vcluster-certs", "new-vcluster-certs")
+	assert.NilError(t, err)
+	assert.Assert(t, copied)
+	secret, err := kubeClient.CoreV1().Secrets("vcluster-ns").Get(context.Background(), "new-vcluster-certs", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, string(secret.Data["ca.crt"]), "ca")
+
+	// existing and missing secrets are not copied
+	copied, err = copySecret(context.Background(), kubeClient, "vcluster-ns", "my-vcluster-certs", "new-vcluster-certs")
+	assert.NilError(t, err)
+	assert.Assert(t, !copied)
+	copied, err = copySecret(context.Background(), kubeClient, "vcluster-ns", "vc-k3s-my-vcluster", "vc-k3s-new-vcluster")
+	assert.NilError(t, err)
+	assert.Assert(t, !copied)
+}
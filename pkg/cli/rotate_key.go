@@ -0,0 +1,124 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/vcluster/pkg/cli/find"
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/loft-sh/vcluster/pkg/lifecycle"
+	"github.com/loft-sh/vcluster/pkg/platform"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+)
+
+type RotateKeyOptions struct {
+	// Interval rotates the access key repeatedly in this interval until the command is stopped
+	Interval time.Duration
+
+	// Timeout is the time to wait for the virtual cluster to become ready with the new access key
+	Timeout time.Duration
+}
+
+// RotateKey creates a new platform access key for the virtual cluster, stores it in the platform secret and restarts
+// the virtual cluster. After the virtual cluster is ready again, the previous access key is revoked if it was created
+// by a previous rotation.
+func RotateKey(ctx context.Context, options *RotateKeyOptions, globalFlags *flags.GlobalFlags, vClusterName string, log log.Logger) error {
+	for {
+		err := rotateKey(ctx, options, globalFlags, vClusterName, log)
+		if err != nil || options.Interval <= 0 {
+			return err
+		}
+
+		log.Infof("Next rotation of the access key of vcluster %s in %s", vClusterName, options.Interval.String())
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(options.Interval):
+		}
+	}
+}
+
+func rotateKey(ctx context.Context, options *RotateKeyOptions, globalFlags *flags.GlobalFlags, vClusterName string, log log.Logger) error {
+	vCluster, err := find.GetVCluster(ctx, globalFlags.Context, vClusterName, globalFlags.Namespace, log)
+	if err != nil {
+		return err
+	}
+	restConfig, err := vCluster.ClientFactory.ClientConfig()
+	if err != nil {
+		return err
+	}
+	kubeClient, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+
+	cliConfig := globalFlags.LoadedConfig(log)
+	rotated, err := platform.RotatePlatformSecret(ctx, cliConfig, kubeClient, vCluster.Name, vCluster.Namespace)
+	if err != nil {
+		return err
+	}
+	log.Donef("Stored new access key %s in platform secret of vCluster %s/%s", rotated.Name, vCluster.Namespace, vCluster.Name)
+
+	// the platform secret is only read on startup
+	restarted := time.Now()
+	labelSelector := "app=vcluster,release=" + vCluster.Name
+	err = lifecycle.DeletePods(ctx, kubeClient, labelSelector, vCluster.Namespace, log)
+	if err != nil {
+		return fmt.Errorf("delete vcluster workloads: %w", err)
+	}
+
+	log.Infof("Waiting for vCluster %s/%s to restart with the new access key...", vCluster.Namespace, vCluster.Name)
+	err = wait.PollUntilContextTimeout(ctx, 2*time.Second, options.Timeout, true, func(ctx context.Context) (bool, error) {
+		pods, err := kubeClient.CoreV1().Pods(vCluster.Namespace).List(ctx, metav1.ListOptions{LabelSelector: labelSelector})
+		if err != nil {
+			return false, err
+		}
+
+		return restartedPodsReady(pods.Items, restarted), nil
+	})
+	if err != nil {
+		return fmt.Errorf("vCluster %s/%s didn't become ready with the new access key, the previous access key was not revoked: %w", vCluster.Namespace, vCluster.Name, err)
+	}
+
+	if rotated.PreviousName != "" {
+		err = platform.RevokeAccessKey(ctx, cliConfig, rotated.PreviousName)
+		if err != nil {
+			log.Warnf("Error revoking previous access key %s: %v", rotated.PreviousName, err)
+		} else {
+			log.Donef("Revoked previous access key %s", rotated.PreviousName)
+		}
+	}
+
+	log.Donef("Successfully rotated access key of vCluster %s/%s", vCluster.Namespace, vCluster.Name)
+	return nil
+}
+
+// restartedPodsReady returns true if there are pods and all of them were created after the restart and are ready
+func restartedPodsReady(pods []corev1.Pod, restarted time.Time) bool {
+	if len(pods) == 0 {
+		return false
+	}
+
+	for _, pod := range pods {
+		if pod.CreationTimestamp.Time.Before(restarted.Truncate(time.Second)) || pod.DeletionTimestamp != nil {
+			return false
+		}
+
+		ready := false
+		for _, condition := range pod.Status.Conditions {
+			if condition.Type == corev1.PodReady && condition.Status == corev1.ConditionTrue {
+				ready = true
+			}
+		}
+		if !ready {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,32 @@
+package cli
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestRestartedPodsReady(t *testing.T) {
+	restarted := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	pod := func(created time.Time, ready corev1.ConditionStatus) corev1.Pod {
+		return corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(created)},
+			Status: corev1.PodStatus{
+				Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: ready}},
+			},
+		}
+	}
+
+	assert.Assert(t, !restartedPodsReady(nil, restarted))
+	assert.Assert(t, restartedPodsReady([]corev1.Pod{pod(restarted.Add(time.Second), corev1.ConditionTrue)}, restarted))
+	assert.Assert(t, !restartedPodsReady([]corev1.Pod{pod(restarted.Add(time.Second), corev1.ConditionFalse)}, restarted))
+
+	// pods that were running before the restart are still using the previous access key
+	assert.Assert(t, !restartedPodsReady([]corev1.Pod{
+		pod(restarted.Add(time.Second), corev1.ConditionTrue),
+		pod(restarted.Add(-time.Minute), corev1.ConditionTrue),
+	}, restarted))
+}
@@ -0,0 +1,366 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/vcluster/pkg/cli/find"
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/loft-sh/vcluster/pkg/platform/backup"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// snapshotSkippedResources are not part of a snapshot, because the target recreates them or they belong to the host
+// cluster the virtual cluster runs in
+var snapshotSkippedResources = []schema.GroupResource{
+	{Resource: "events"},
+	{Group: "events.k8s.io", Resource: "events"},
+	{Resource: "nodes"},
+	{Resource: "persistentvolumes"},
+	{Resource: "endpoints"},
+	{Group: "discovery.k8s.io", Resource: "endpointslices"},
+	{Group: "coordination.k8s.io", Resource: "leases"},
+	{Group: "storage.k8s.io", Resource: "csinodes"},
+	{Group: "storage.k8s.io", Resource: "volumeattachments"},
+	{Group: "certificates.k8s.io", Resource: "certificatesigningrequests"},
+	{Group: "metrics.k8s.io", Resource: "pods"},
+	{Group: "metrics.k8s.io", Resource: "nodes"},
+}
+
+// snapshotResourceOrder are restored before all other resources in this order, so pods find their service
+// accounts, secrets, config maps and claims
+var snapshotResourceOrder = []schema.GroupResource{
+	{Resource: "namespaces"},
+	{Group: "apiextensions.k8s.io", Resource: "customresourcedefinitions"},
+	{Resource: "serviceaccounts"},
+	{Resource: "secrets"},
+	{Resource: "configmaps"},
+	{Resource: "persistentvolumeclaims"},
+}
+
+var crdGroupKind = schema.GroupKind{Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"}
+
+// vClusterRestConfig connects to the given virtual cluster via port-forwarding and returns its rest config. The
+// returned function stops the port-forwarding.
+func vClusterRestConfig(ctx context.Context, globalFlags *flags.GlobalFlags, vCluster *find.VCluster, command string, log log.Logger) (*rest.Config, func(), error) {
+	connectFlags := *globalFlags
+	connectCmd := &connectHelm{
+		GlobalFlags:    &connectFlags,
+		ConnectOptions: &ConnectOptions{},
+		Log:            log,
+	}
+	err := connectCmd.prepare(ctx, vCluster)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	kubeConfig, err := connectCmd.getVClusterKubeConfig(ctx, vCluster.Name, []string{"vcluster", command})
+	if err != nil {
+		return nil, nil, err
+	}
+	stop := func() {
+		close(connectCmd.interruptChan)
+		<-connectCmd.errorChan
+	}
+	err = connectCmd.waitForVCluster(ctx, *kubeConfig, connectCmd.errorChan)
+	if err != nil {
+		stop()
+		return nil, nil, err
+	}
+
+	vRestConfig, err := clientcmd.NewDefaultClientConfig(getLocalVClusterConfig(*kubeConfig, connectCmd.ConnectOptions), &clientcmd.ConfigOverrides{}).ClientConfig()
+	if err != nil {
+		stop()
+		return nil, nil, fmt.Errorf("create virtual rest config: %w", err)
+	}
+
+	return vRestConfig, stop, nil
+}
+
+type SnapshotOptions struct {
+	// Labels are stored in the metadata of the snapshot archive, e.g. the purpose of the snapshot or a git ref
+	Labels map[string]string
+}
+
+// Snapshot writes the objects of the virtual cluster as archive to a local path or file:// url or uploads it to a
+// presigned http(s):// url. Snapshots in a local directory can be listed by their labels with backup.List.
+func Snapshot(ctx context.Context, globalFlags *flags.GlobalFlags, vClusterName, destination string, options *SnapshotOptions, log log.Logger) error {
+	vCluster, err := find.GetVCluster(ctx, globalFlags.Context, vClusterName, globalFlags.Namespace, log)
+	if err != nil {
+		return err
+	}
+
+	log.Infof("Snapshot vcluster %s in namespace %s of context %s...", vClusterName, vCluster.Namespace, vCluster.Context)
+	archive, err := snapshotVClusterWithConnect(ctx, globalFlags, vCluster, options.Labels, log)
+	if err != nil {
+		return err
+	}
+	err = backup.Upload(ctx, destination, archive)
+	if err != nil {
+		return fmt.Errorf("save snapshot: %w", err)
+	}
+
+	log.Donef("Saved snapshot of vcluster %s to %s", vClusterName, strings.SplitN(destination, "?", 2)[0])
+	return nil
+}
+
+// snapshotVCluster writes the objects of the virtual cluster as backup archive with the labels in its metadata.
+// Objects that are managed by a controller, such as the pods of a deployment, are left out as the controllers in the
+// target recreate them.
+func snapshotVCluster(ctx context.Context, vRestConfig *rest.Config, labels map[string]string, log log.Logger) ([]byte, error) {
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(vRestConfig)
+	if err != nil {
+		return nil, err
+	}
+	dynamicClient, err := dynamic.NewForConfig(vRestConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	resources, err := snapshotResources(discoveryClient)
+	if err != nil {
+		return nil, err
+	}
+
+	objects := []runtime.Object{}
+	for _, resource := range resources {
+		gvr := schema.GroupVersionResource{Group: resource.Group, Version: resource.Version, Resource: resource.Name}
+		list, err := dynamicClient.Resource(gvr).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			// skip resources that are gone or that we are not allowed to list
+			if kerrors.IsNotFound(err) || kerrors.IsForbidden(err) || kerrors.IsMethodNotSupported(err) {
+				log.Debugf("Skip %s in snapshot: %v", gvr.GroupResource().String(), err)
+				continue
+			}
+
+			return nil, fmt.Errorf("list %s: %w", gvr.GroupResource().String(), err)
+		}
+
+		for i := range list.Items {
+			obj := snapshotObject(&list.Items[i])
+			if obj != nil {
+				objects = append(objects, obj)
+			}
+		}
+	}
+
+	log.Infof("Snapshot contains %d objects", len(objects))
+	return backup.ToArchive(objects, labels)
+}
+
+// snapshotResources returns the resources of the snapshot ordered the way they are restored
+func snapshotResources(discoveryClient discovery.DiscoveryInterface) ([]metav1.APIResource, error) {
+	resourceLists, err := discoveryClient.ServerPreferredResources()
+	if err != nil && !discovery.IsGroupDiscoveryFailedError(err) {
+		return nil, fmt.Errorf("discover resources: %w", err)
+	}
+
+	resources := []metav1.APIResource{}
+	for _, resourceList := range resourceLists {
+		gv, err := schema.ParseGroupVersion(resourceList.GroupVersion)
+		if err != nil {
+			continue
+		}
+
+		for _, resource := range resourceList.APIResources {
+			if strings.Contains(resource.Name, "/") || !slices.Contains(resource.Verbs, "list") || !slices.Contains(resource.Verbs, "create") {
+				continue
+			} else if slices.Contains(snapshotSkippedResources, schema.GroupResource{Group: gv.Group, Resource: resource.Name}) {
+				continue
+			}
+
+			resource.Group = gv.Group
+			resource.Version = gv.Version
+			resources = append(resources, resource)
+		}
+	}
+
+	sort.SliceStable(resources, func(i, j int) bool {
+		return snapshotResourcePriority(resources[i]) < snapshotResourcePriority(resources[j])
+	})
+	return resources, nil
+}
+
+func snapshotResourcePriority(resource metav1.APIResource) int {
+	index := slices.Index(snapshotResourceOrder, schema.GroupResource{Group: resource.Group, Resource: resource.Name})
+	if index == -1 {
+		return len(snapshotResourceOrder)
+	}
+
+	return index
+}
+
+// snapshotObject returns the object without the fields the api server of the target sets, or nil if the object
+// is not part of the snapshot
+func snapshotObject(obj *unstructured.Unstructured) *unstructured.Unstructured {
+	if obj.GetDeletionTimestamp() != nil || metav1.GetControllerOfNoCopy(obj) != nil {
+		return nil
+	}
+
+	switch obj.GroupVersionKind().GroupKind() {
+	case schema.GroupKind{Kind: "ConfigMap"}:
+		// created by the root ca publisher in every namespace
+		if obj.GetName() == "kube-root-ca.crt" {
+			return nil
+		}
+	case schema.GroupKind{Kind: "Secret"}:
+		// service account tokens are signed by the source, the target creates its own
+		if secretType, _, _ := unstructured.NestedString(obj.Object, "type"); secretType == string(corev1.SecretTypeServiceAccountToken) {
+			return nil
+		}
+	case schema.GroupKind{Kind: "Service"}:
+		if obj.GetNamespace() == "default" && obj.GetName() == "kubernetes" {
+			return nil
+		}
+	}
+
+	obj = obj.DeepCopy()
+	obj.SetUID("")
+	obj.SetResourceVersion("")
+	obj.SetGeneration(0)
+	obj.SetCreationTimestamp(metav1.Time{})
+	obj.SetManagedFields(nil)
+	obj.SetOwnerReferences(nil)
+	unstructured.RemoveNestedField(obj.Object, "metadata", "selfLink")
+	unstructured.RemoveNestedField(obj.Object, "status")
+
+	switch obj.GroupVersionKind().GroupKind() {
+	case schema.GroupKind{Kind: "Pod"}:
+		// the nodes of the target differ
+		unstructured.RemoveNestedField(obj.Object, "spec", "nodeName")
+	case schema.GroupKind{Kind: "Service"}:
+		// the service cidr of the target might differ, headless services stay headless
+		if clusterIP, _, _ := unstructured.NestedString(obj.Object, "spec", "clusterIP"); clusterIP != corev1.ClusterIPNone {
+			unstructured.RemoveNestedField(obj.Object, "spec", "clusterIP")
+			unstructured.RemoveNestedField(obj.Object, "spec", "clusterIPs")
+		}
+		unstructured.RemoveNestedField(obj.Object, "spec", "healthCheckNodePort")
+		if ports, ok, _ := unstructured.NestedSlice(obj.Object, "spec", "ports"); ok {
+			for _, port := range ports {
+				if port, ok := port.(map[string]interface{}); ok {
+					delete(port, "nodePort")
+				}
+			}
+			_ = unstructured.SetNestedSlice(obj.Object, ports, "spec", "ports")
+		}
+	case schema.GroupKind{Kind: "PersistentVolumeClaim"}:
+		// the persistent volumes are not part of the snapshot, so the claims are bound again in the target
+		unstructured.RemoveNestedField(obj.Object, "spec", "volumeName")
+		annotations := obj.GetAnnotations()
+		delete(annotations, "pv.kubernetes.io/bind-completed")
+		delete(annotations, "pv.kubernetes.io/bound-by-controller")
+		delete(annotations, "volume.kubernetes.io/selected-node")
+		obj.SetAnnotations(annotations)
+	case schema.GroupKind{Group: "batch", Kind: "Job"}:
+		// the generated selector contains the uid of the job in the source
+		if manualSelector, _, _ := unstructured.NestedBool(obj.Object, "spec", "manualSelector"); !manualSelector {
+			unstructured.RemoveNestedField(obj.Object, "spec", "selector")
+			for _, label := range []string{"controller-uid", "batch.kubernetes.io/controller-uid"} {
+				unstructured.RemoveNestedField(obj.Object, "spec", "template", "metadata", "labels", label)
+			}
+		}
+	}
+
+	return obj
+}
+
+// restoreVClusterSnapshot creates the objects of a snapshot written by snapshotVCluster in the virtual cluster.
+// Objects that already exist in the target, such as the default namespaces, are skipped.
+func restoreVClusterSnapshot(ctx context.Context, vRestConfig *rest.Config, archive []byte, log log.Logger) error {
+	objects, err := backup.FromArchive(archive)
+	if err != nil {
+		return err
+	}
+
+	// custom resources can only be restored once their definitions are established
+	crds := []*unstructured.Unstructured{}
+	others := []*unstructured.Unstructured{}
+	for _, obj := range objects {
+		if obj.GroupVersionKind().GroupKind() == crdGroupKind {
+			crds = append(crds, obj)
+		} else {
+			others = append(others, obj)
+		}
+	}
+
+	infoFn := func(msg string) { log.Debug(msg) }
+	result := backup.RestoreResult{}
+	if len(crds) > 0 {
+		kubeClient, err := client.New(vRestConfig, client.Options{})
+		if err != nil {
+			return err
+		}
+
+		crdResult, restoreErrors := backup.Restore(ctx, kubeClient, crds, false, infoFn)
+		if len(restoreErrors) > 0 {
+			return fmt.Errorf("restore custom resource definitions: %w", errors.Join(restoreErrors...))
+		}
+		result.Created += crdResult.Created
+		result.Skipped += crdResult.Skipped
+
+		err = waitForEstablished(ctx, kubeClient, crds)
+		if err != nil {
+			return err
+		}
+	}
+
+	// a new client discovers the api groups of the restored definitions
+	kubeClient, err := client.New(vRestConfig, client.Options{})
+	if err != nil {
+		return err
+	}
+	othersResult, restoreErrors := backup.Restore(ctx, kubeClient, others, false, infoFn)
+	result.Created += othersResult.Created
+	result.Skipped += othersResult.Skipped
+	if len(restoreErrors) > 0 {
+		return fmt.Errorf("restore snapshot: %w", errors.Join(restoreErrors...))
+	}
+
+	log.Infof("Restored %d objects, skipped %d existing objects", result.Created, result.Skipped)
+	return nil
+}
+
+func waitForEstablished(ctx context.Context, kubeClient client.Client, crds []*unstructured.Unstructured) error {
+	for _, crd := range crds {
+		err := wait.PollUntilContextTimeout(ctx, time.Second, time.Minute, true, func(ctx context.Context) (bool, error) {
+			current := &unstructured.Unstructured{}
+			current.SetGroupVersionKind(crd.GroupVersionKind())
+			err := kubeClient.Get(ctx, client.ObjectKeyFromObject(crd), current)
+			if err != nil {
+				return false, err
+			}
+
+			conditions, _, _ := unstructured.NestedSlice(current.Object, "status", "conditions")
+			for _, condition := range conditions {
+				condition, ok := condition.(map[string]interface{})
+				if ok && condition["type"] == "Established" && condition["status"] == "True" {
+					return true, nil
+				}
+			}
+
+			return false, nil
+		})
+		if err != nil {
+			return fmt.Errorf("wait for custom resource definition %s: %w", crd.GetName(), err)
+		}
+	}
+
+	return nil
+}
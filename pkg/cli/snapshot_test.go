@@ -0,0 +1,132 @@
+package cli
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/ptr"
+)
+
+type preferredDiscovery struct {
+	*fakediscovery.FakeDiscovery
+}
+
+func (d *preferredDiscovery) ServerPreferredResources() ([]*metav1.APIResourceList, error) {
+	return d.Resources, nil
+}
+
+func TestSnapshotResources(t *testing.T) {
+	discoveryClient := &preferredDiscovery{FakeDiscovery: fake.NewSimpleClientset().Discovery().(*fakediscovery.FakeDiscovery)}
+	verbs := metav1.Verbs{"create", "list"}
+	discoveryClient.Resources = []*metav1.APIResourceList{
+		{GroupVersion: "apps/v1", APIResources: []metav1.APIResource{{Name: "deployments", Namespaced: true, Verbs: verbs}, {Name: "deployments/scale", Verbs: verbs}}},
+		{GroupVersion: "v1", APIResources: []metav1.APIResource{
+			{Name: "pods", Namespaced: true, Verbs: verbs},
+			{Name: "events", Namespaced: true, Verbs: verbs},
+			{Name: "nodes", Verbs: verbs},
+			{Name: "bindings", Namespaced: true, Verbs: metav1.Verbs{"create"}},
+			{Name: "secrets", Namespaced: true, Verbs: verbs},
+			{Name: "serviceaccounts", Namespaced: true, Verbs: verbs},
+			{Name: "namespaces", Verbs: verbs},
+		}},
+		{GroupVersion: "apiextensions.k8s.io/v1", APIResources: []metav1.APIResource{{Name: "customresourcedefinitions", Verbs: verbs}}},
+	}
+
+	resources, err := snapshotResources(discoveryClient)
+	assert.NilError(t, err)
+	names := []string{}
+	for _, resource := range resources {
+		names = append(names, resource.Group+"/"+resource.Version+"/"+resource.Name)
+	}
+	assert.DeepEqual(t, names, []string{
+		"/v1/namespaces",
+		"apiextensions.k8s.io/v1/customresourcedefinitions",
+		"/v1/serviceaccounts",
+		"/v1/secrets",
+		"apps/v1/deployments",
+		"/v1/pods",
+	})
+}
+
+func TestSnapshotObject(t *testing.T) {
+	toUnstructured := func(obj runtime.Object, apiVersion, kind string) *unstructured.Unstructured {
+		raw, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+		assert.NilError(t, err)
+		u := &unstructured.Unstructured{Object: raw}
+		u.SetAPIVersion(apiVersion)
+		u.SetKind(kind)
+		return u
+	}
+	meta := func(name string) metav1.ObjectMeta {
+		return metav1.ObjectMeta{Name: name, Namespace: "default", UID: types.UID(name), ResourceVersion: "10", CreationTimestamp: metav1.Now()}
+	}
+
+	// objects the target recreates are skipped
+	ownedPod := &corev1.Pod{ObjectMeta: meta("owned")}
+	ownedPod.OwnerReferences = []metav1.OwnerReference{{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "web", UID: "1", Controller: ptr.To(true)}}
+	assert.Assert(t, snapshotObject(toUnstructured(ownedPod, "v1", "Pod")) == nil)
+	assert.Assert(t, snapshotObject(toUnstructured(&corev1.ConfigMap{ObjectMeta: meta("kube-root-ca.crt")}, "v1", "ConfigMap")) == nil)
+	assert.Assert(t, snapshotObject(toUnstructured(&corev1.Secret{ObjectMeta: meta("token"), Type: corev1.SecretTypeServiceAccountToken}, "v1", "Secret")) == nil)
+	assert.Assert(t, snapshotObject(toUnstructured(&corev1.Service{ObjectMeta: meta("kubernetes")}, "v1", "Service")) == nil)
+
+	// fields set by the source are removed
+	pod := &corev1.Pod{ObjectMeta: meta("standalone"), Spec: corev1.PodSpec{NodeName: "node"}, Status: corev1.PodStatus{Phase: corev1.PodRunning}}
+	pod.OwnerReferences = []metav1.OwnerReference{{APIVersion: "v1", Kind: "ConfigMap", Name: "owner", UID: "2"}}
+	snapshot := snapshotObject(toUnstructured(pod, "v1", "Pod"))
+	assert.Assert(t, snapshot != nil)
+	assert.Equal(t, snapshot.GetUID(), types.UID(""))
+	assert.Equal(t, snapshot.GetResourceVersion(), "")
+	assert.Equal(t, len(snapshot.GetOwnerReferences()), 0)
+	_, found, _ := unstructured.NestedFieldNoCopy(snapshot.Object, "spec", "nodeName")
+	assert.Assert(t, !found)
+	_, found, _ = unstructured.NestedFieldNoCopy(snapshot.Object, "status")
+	assert.Assert(t, !found)
+
+	service := &corev1.Service{ObjectMeta: meta("web"), Spec: corev1.ServiceSpec{
+		ClusterIP:  "10.96.0.20",
+		ClusterIPs: []string{"10.96.0.20"},
+		Type:       corev1.ServiceTypeNodePort,
+		Ports:      []corev1.ServicePort{{Port: 80, NodePort: 30080}},
+	}}
+	snapshot = snapshotObject(toUnstructured(service, "v1", "Service"))
+	_, found, _ = unstructured.NestedFieldNoCopy(snapshot.Object, "spec", "clusterIP")
+	assert.Assert(t, !found)
+	ports, _, _ := unstructured.NestedSlice(snapshot.Object, "spec", "ports")
+	assert.DeepEqual(t, ports, []interface{}{map[string]interface{}{"port": int64(80), "targetPort": int64(0)}})
+	headless := &corev1.Service{ObjectMeta: meta("headless"), Spec: corev1.ServiceSpec{ClusterIP: corev1.ClusterIPNone}}
+	snapshot = snapshotObject(toUnstructured(headless, "v1", "Service"))
+	clusterIP, _, _ := unstructured.NestedString(snapshot.Object, "spec", "clusterIP")
+	assert.Equal(t, clusterIP, corev1.ClusterIPNone)
+
+	pvc := &corev1.PersistentVolumeClaim{ObjectMeta: meta("data"), Spec: corev1.PersistentVolumeClaimSpec{VolumeName: "pvc-1"}}
+	pvc.Annotations = map[string]string{"pv.kubernetes.io/bind-completed": "yes", "team": "a"}
+	snapshot = snapshotObject(toUnstructured(pvc, "v1", "PersistentVolumeClaim"))
+	_, found, _ = unstructured.NestedFieldNoCopy(snapshot.Object, "spec", "volumeName")
+	assert.Assert(t, !found)
+	assert.DeepEqual(t, snapshot.GetAnnotations(), map[string]string{"team": "a"})
+
+	job := &batchv1.Job{ObjectMeta: meta("migrate"), Spec: batchv1.JobSpec{
+		Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"batch.kubernetes.io/controller-uid": "migrate"}},
+		Template: corev1.PodTemplateSpec{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"batch.kubernetes.io/controller-uid": "migrate", "app": "migrate"}}},
+	}}
+	snapshot = snapshotObject(toUnstructured(job, "batch/v1", "Job"))
+	_, found, _ = unstructured.NestedFieldNoCopy(snapshot.Object, "spec", "selector")
+	assert.Assert(t, !found)
+	labels, _, _ := unstructured.NestedStringMap(snapshot.Object, "spec", "template", "metadata", "labels")
+	assert.DeepEqual(t, labels, map[string]string{"app": "migrate"})
+
+	// other objects are kept
+	deployment := &appsv1.Deployment{ObjectMeta: meta("web")}
+	snapshot = snapshotObject(toUnstructured(deployment, "apps/v1", "Deployment"))
+	assert.Equal(t, snapshot.GetName(), "web")
+	assert.Equal(t, snapshot.GetNamespace(), "default")
+}
@@ -0,0 +1,107 @@
+package start
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/loft-sh/api/v4/pkg/product"
+	"github.com/loft-sh/vcluster/pkg/platform/clihelper"
+)
+
+// prepareLicense seeds the license secret from the license file before Loft is installed. An offline license doesn't
+// need the loft.host tunnel, so it is disabled as well.
+func (l *LoftStarter) prepareLicense(ctx context.Context) error {
+	if l.LicenseFile == "" {
+		return nil
+	}
+
+	license, err := readOfflineLicense(l.LicenseFile)
+	if err != nil {
+		return err
+	}
+
+	err = clihelper.ApplyLicenseSecret(ctx, l.KubeClient, l.Namespace, license)
+	if err != nil {
+		return err
+	}
+
+	l.NoTunnel = true
+	l.Log.Donef("Stored offline license in secret %s/%s", l.Namespace, clihelper.LicenseSecretName)
+	return nil
+}
+
+// activateLicense passes the license secret to the Loft deployment after it was installed
+func (l *LoftStarter) activateLicense(ctx context.Context) error {
+	if l.LicenseFile == "" {
+		return nil
+	}
+
+	err := clihelper.EnsureLicenseEnv(ctx, l.KubeClient, l.Namespace)
+	if err != nil {
+		return err
+	}
+
+	l.Log.Done(product.Replace("Activated offline license for Loft"))
+	return nil
+}
+
+// Activate activates an offline license for an existing Loft instance
+func (l *LoftStarter) Activate(ctx context.Context) error {
+	license, err := readOfflineLicense(l.LicenseFile)
+	if err != nil {
+		return err
+	}
+
+	err = l.prepare()
+	if err != nil {
+		return err
+	}
+
+	isInstalled, err := clihelper.IsLoftAlreadyInstalled(ctx, l.KubeClient, l.Namespace)
+	if err != nil {
+		return err
+	} else if !isInstalled {
+		return fmt.Errorf("%s is not installed in namespace %s, please run `vcluster platform start --license-file %s` to install it with the license", product.DisplayName(), l.Namespace, l.LicenseFile)
+	}
+
+	err = clihelper.ApplyLicenseSecret(ctx, l.KubeClient, l.Namespace, license)
+	if err != nil {
+		return err
+	}
+	err = l.activateLicense(ctx)
+	if err != nil {
+		return err
+	}
+	if l.NoWait {
+		return nil
+	}
+
+	l.Log.Info(product.Replace("Waiting for Loft pod to be running..."))
+	_, err = clihelper.WaitForReadyLoftPod(ctx, l.KubeClient, l.Namespace, l.Log)
+	if err != nil {
+		return err
+	}
+
+	if license.Expiry != nil {
+		l.Log.Donef("Offline license is active and expires at %s", license.Expiry.Format(time.RFC3339))
+	} else {
+		l.Log.Done("Offline license is active")
+	}
+	return nil
+}
+
+func readOfflineLicense(licenseFile string) (*clihelper.OfflineLicense, error) {
+	out, err := os.ReadFile(licenseFile)
+	if err != nil {
+		return nil, fmt.Errorf("read license file: %w", err)
+	}
+
+	license, err := clihelper.ParseOfflineLicense(string(out), time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("license file %s: %w", licenseFile, err)
+	}
+
+	return license, nil
+}
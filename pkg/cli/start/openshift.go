@@ -0,0 +1,45 @@
+package start
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/loft-sh/api/v4/pkg/product"
+	"github.com/loft-sh/vcluster/pkg/platform/clihelper"
+	"k8s.io/client-go/dynamic"
+)
+
+// prepareOpenShift detects OpenShift clusters and allows the Loft service account to run the pods of the chart
+func (l *LoftStarter) prepareOpenShift(ctx context.Context) error {
+	isOpenShift, err := clihelper.IsOpenShift(l.KubeClient.Discovery())
+	if err != nil {
+		return err
+	}
+	l.OpenShift = isOpenShift
+	if !l.OpenShift {
+		if l.Route {
+			return fmt.Errorf("--route requires an OpenShift cluster")
+		}
+
+		return nil
+	} else if l.Route && l.Host == "" {
+		return fmt.Errorf("--route requires --host")
+	}
+
+	l.Log.Info(product.Replace("Detected OpenShift cluster, preparing Loft installation"))
+	return clihelper.EnsureOpenShiftSCC(ctx, l.KubeClient, l.Namespace, l.Log)
+}
+
+// remoteHost returns the host Loft is exposed with via the OpenShift route or the ingress
+func (l *LoftStarter) remoteHost(ctx context.Context) (string, error) {
+	if !l.Route {
+		return clihelper.GetLoftIngressHost(ctx, l.KubeClient, l.Namespace)
+	}
+
+	dynamicClient, err := dynamic.NewForConfig(l.RestConfig)
+	if err != nil {
+		return "", err
+	}
+
+	return clihelper.GetLoftRouteHost(ctx, dynamicClient, l.Namespace)
+}
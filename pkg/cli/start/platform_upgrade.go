@@ -0,0 +1,262 @@
+package start
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/blang/semver/v4"
+	"github.com/ghodss/yaml"
+	"github.com/loft-sh/api/v4/pkg/product"
+	"github.com/loft-sh/log/table"
+	"github.com/loft-sh/vcluster/pkg/helm"
+	"github.com/loft-sh/vcluster/pkg/platform"
+	"github.com/loft-sh/vcluster/pkg/platform/clihelper"
+	"github.com/loft-sh/vcluster/pkg/strvals"
+)
+
+// loftReleaseName is the name of the helm release of Loft
+const loftReleaseName = "loft"
+
+// UpgradePlan describes the changes of a Loft upgrade
+type UpgradePlan struct {
+	CurrentVersion string
+	TargetVersion  string
+	// Revision is the helm revision of the current release, it is rolled back to if the upgrade fails
+	Revision int
+	Changes  []ValueChange
+	Warnings []string
+}
+
+// ValueChange is a changed value of the helm release, Current or Target are empty if the value is added or removed
+type ValueChange struct {
+	Key     string
+	Current string
+	Target  string
+}
+
+// IsEmpty returns true if neither the version nor the values change
+func (p *UpgradePlan) IsEmpty() bool {
+	return p.CurrentVersion == p.TargetVersion && len(p.Changes) == 0
+}
+
+// RunUpgrade upgrades an existing Loft instance. It prints the plan with the current and target version and the
+// changed values, then upgrades the release and rolls it back to the previous revision if Loft doesn't become ready.
+func (l *LoftStarter) RunUpgrade(ctx context.Context) error {
+	err := l.prepare()
+	if err != nil {
+		return err
+	}
+
+	release, err := helm.NewSecrets(l.KubeClient).Get(ctx, loftReleaseName, l.Namespace)
+	if err != nil {
+		return fmt.Errorf("get helm release of %s in namespace %s: %w", product.DisplayName(), l.Namespace, err)
+	} else if release.Chart == nil || release.Chart.Metadata == nil {
+		return fmt.Errorf("helm release of %s has no chart metadata", product.DisplayName())
+	}
+
+	if l.Version == "" || l.Version == "latest" {
+		l.Version, err = platform.LatestCompatibleVersion(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	targetValues, err := upgradeValues(release.Config, l.ReuseValues, l.Values, l.SetValues)
+	if err != nil {
+		return err
+	}
+	plan, err := planUpgrade(release, l.Version, targetValues)
+	if err != nil {
+		return err
+	}
+	l.printPlan(plan)
+	if plan.IsEmpty() {
+		l.Log.Donef("%s is already up to date", product.DisplayName())
+		return nil
+	} else if l.DryRun {
+		return nil
+	}
+
+	extraArgs := []string{"--version", plan.TargetVersion}
+	if !l.ReuseValues {
+		extraArgs = append(extraArgs, "--reset-values")
+	}
+	args, err := valuesArgs(l.Values, l.SetValues)
+	if err != nil {
+		return err
+	}
+	extraArgs = append(extraArgs, args...)
+
+	chartName := l.ChartPath
+	chartRepo := ""
+	if chartName == "" {
+		chartName = l.ChartName
+		chartRepo = l.ChartRepo
+	}
+	err = clihelper.UpgradeLoft(chartName, chartRepo, l.Context, l.Namespace, extraArgs, l.Log)
+	if err != nil {
+		return l.rollbackUpgrade(plan, err)
+	}
+
+	l.Log.Info(product.Replace("Waiting for the upgraded Loft to be ready..."))
+	err = clihelper.WaitForLoftRollout(ctx, l.KubeClient, l.Namespace, l.UpgradeTimeout)
+	if err != nil {
+		return l.rollbackUpgrade(plan, err)
+	}
+	_, err = clihelper.WaitForReadyLoftPod(ctx, l.KubeClient, l.Namespace, l.Log)
+	if err != nil {
+		return l.rollbackUpgrade(plan, err)
+	}
+
+	l.Log.Donef("Successfully upgraded %s from %s to %s", product.DisplayName(), plan.CurrentVersion, plan.TargetVersion)
+	return nil
+}
+
+func (l *LoftStarter) rollbackUpgrade(plan *UpgradePlan, upgradeErr error) error {
+	l.Log.Warnf("Upgrade failed, rolling %s back to revision %d: %v", product.DisplayName(), plan.Revision, upgradeErr)
+	err := clihelper.RollbackLoft(l.Context, l.Namespace, plan.Revision, l.Log)
+	if err != nil {
+		return fmt.Errorf("upgrade: %w, rollback: %w", upgradeErr, err)
+	}
+
+	return fmt.Errorf("upgrade to %s was rolled back: %w", plan.TargetVersion, upgradeErr)
+}
+
+func (l *LoftStarter) printPlan(plan *UpgradePlan) {
+	l.Log.Infof("Current version: %s (revision %d)", plan.CurrentVersion, plan.Revision)
+	l.Log.Infof("Target version:  %s", plan.TargetVersion)
+	for _, warning := range plan.Warnings {
+		l.Log.Warn(warning)
+	}
+	if len(plan.Changes) == 0 {
+		l.Log.Info("No values change")
+		return
+	}
+
+	rows := [][]string{}
+	for _, change := range plan.Changes {
+		rows = append(rows, []string{change.Key, change.Current, change.Target})
+	}
+	table.PrintTable(l.Log, []string{"VALUE", "CURRENT", "TARGET"}, rows)
+}
+
+// planUpgrade validates the version skew between the current release and the target version and returns the plan
+func planUpgrade(release *helm.Release, targetVersion string, targetValues map[string]interface{}) (*UpgradePlan, error) {
+	current, err := semver.Parse(strings.TrimPrefix(release.Chart.Metadata.Version, "v"))
+	if err != nil {
+		return nil, fmt.Errorf("parse current version %s: %w", release.Chart.Metadata.Version, err)
+	}
+	target, err := semver.Parse(strings.TrimPrefix(targetVersion, "v"))
+	if err != nil {
+		return nil, fmt.Errorf("parse target version %s: %w", targetVersion, err)
+	}
+
+	switch {
+	case target.LT(platform.MinimumVersion):
+		return nil, fmt.Errorf("target version %s is older than the minimum supported version %s", targetVersion, platform.MinimumVersionTag)
+	case target.LT(current):
+		return nil, fmt.Errorf("target version %s is older than the current version %s, downgrades are not supported", targetVersion, release.Chart.Metadata.Version)
+	case target.Major != current.Major:
+		return nil, fmt.Errorf("upgrading from %s to %s changes the major version, please follow the migration guide of %s", release.Chart.Metadata.Version, targetVersion, product.DisplayName())
+	}
+
+	plan := &UpgradePlan{
+		CurrentVersion: current.String(),
+		TargetVersion:  target.String(),
+		Revision:       release.Version,
+		Changes:        diffValues(release.Config, targetValues),
+	}
+	if target.Minor > current.Minor+1 {
+		plan.Warnings = append(plan.Warnings, fmt.Sprintf("Upgrade skips %d minor versions, please check the release notes of the skipped versions", target.Minor-current.Minor-1))
+	}
+
+	return plan, nil
+}
+
+// upgradeValues returns the values the release will have after the upgrade. Helm starts with the values of the
+// current release if they are reused, merges the values files in order and applies the set values on top.
+func upgradeValues(current map[string]interface{}, reuseValues bool, values, setValues []string) (map[string]interface{}, error) {
+	target := map[string]interface{}{}
+	if reuseValues {
+		target = strvals.MergeMaps(target, current)
+	}
+	for _, valuesPath := range values {
+		out, err := os.ReadFile(valuesPath)
+		if err != nil {
+			return nil, fmt.Errorf("read values file: %w", err)
+		}
+
+		fileValues := map[string]interface{}{}
+		err = yaml.Unmarshal(out, &fileValues)
+		if err != nil {
+			return nil, fmt.Errorf("parse values file %s: %w", valuesPath, err)
+		}
+		target = strvals.MergeMaps(target, fileValues)
+	}
+	for _, setValue := range setValues {
+		err := strvals.ParseInto(setValue, target)
+		if err != nil {
+			return nil, fmt.Errorf("apply --set %s: %w", setValue, err)
+		}
+	}
+
+	return target, nil
+}
+
+// diffValues returns the changed leaf values of the current and target values sorted by key
+func diffValues(current, target map[string]interface{}) []ValueChange {
+	currentValues := map[string]string{}
+	flattenValues("", current, currentValues)
+	targetValues := map[string]string{}
+	flattenValues("", target, targetValues)
+
+	changes := []ValueChange{}
+	for key, value := range currentValues {
+		if targetValue, ok := targetValues[key]; !ok || targetValue != value {
+			changes = append(changes, ValueChange{Key: key, Current: value, Target: targetValue})
+		}
+	}
+	for key, value := range targetValues {
+		if _, ok := currentValues[key]; !ok {
+			changes = append(changes, ValueChange{Key: key, Target: value})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Key < changes[j].Key
+	})
+	return changes
+}
+
+func flattenValues(prefix string, values map[string]interface{}, out map[string]string) {
+	for key, value := range values {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+
+		if nested, ok := value.(map[string]interface{}); ok && len(nested) > 0 {
+			flattenValues(key, nested, out)
+			continue
+		}
+
+		out[key] = formatValue(value)
+	}
+}
+
+func formatValue(value interface{}) string {
+	if value == nil || reflect.TypeOf(value).Kind() == reflect.String {
+		return fmt.Sprint(value)
+	}
+
+	out, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+
+	return string(out)
+}
@@ -0,0 +1,70 @@
+package start
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/loft-sh/vcluster/pkg/helm"
+	"gotest.tools/v3/assert"
+)
+
+func newLoftRelease(version string, revision int, values map[string]interface{}) *helm.Release {
+	return &helm.Release{
+		Name:    loftReleaseName,
+		Version: revision,
+		Config:  values,
+		Chart: &helm.Chart{
+			Metadata: &helm.Metadata{Version: version},
+		},
+	}
+}
+
+func TestPlanUpgrade(t *testing.T) {
+	values := map[string]interface{}{"admin": map[string]interface{}{"password": "secret"}}
+	plan, err := planUpgrade(newLoftRelease("4.0.0", 3, values), "v4.0.0", values)
+	assert.NilError(t, err)
+	assert.Assert(t, plan.IsEmpty())
+	assert.Equal(t, plan.Revision, 3)
+
+	plan, err = planUpgrade(newLoftRelease("4.0.0", 3, values), "v4.3.1", values)
+	assert.NilError(t, err)
+	assert.Assert(t, !plan.IsEmpty())
+	assert.Equal(t, plan.TargetVersion, "4.3.1")
+	assert.Equal(t, len(plan.Warnings), 1)
+
+	_, err = planUpgrade(newLoftRelease("4.1.0", 3, values), "v4.0.0", values)
+	assert.ErrorContains(t, err, "downgrades are not supported")
+	_, err = planUpgrade(newLoftRelease("4.1.0", 3, values), "v5.0.0", values)
+	assert.ErrorContains(t, err, "major version")
+	_, err = planUpgrade(newLoftRelease("3.4.0", 3, values), "v3.5.0", values)
+	assert.ErrorContains(t, err, "minimum supported version")
+}
+
+func TestUpgradeValues(t *testing.T) {
+	valuesPath := filepath.Join(t.TempDir(), "values.yaml")
+	err := os.WriteFile(valuesPath, []byte("replicaCount: 2\ningress:\n  enabled: true\n"), 0600)
+	assert.NilError(t, err)
+
+	current := map[string]interface{}{
+		"admin":   map[string]interface{}{"password": "secret"},
+		"ingress": map[string]interface{}{"enabled": false, "host": "platform.example.com"},
+	}
+	target, err := upgradeValues(current, true, []string{valuesPath}, []string{"env.DISABLE_LOFT_ROUTER=true"})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, diffValues(current, target), []ValueChange{
+		{Key: "env.DISABLE_LOFT_ROUTER", Target: "true"},
+		{Key: "ingress.enabled", Current: "false", Target: "true"},
+		{Key: "replicaCount", Target: "2"},
+	})
+
+	// without reusing the values the current values are removed
+	target, err = upgradeValues(current, false, nil, []string{"replicaCount=2"})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, diffValues(current, target), []ValueChange{
+		{Key: "admin.password", Current: "secret"},
+		{Key: "ingress.enabled", Current: "false"},
+		{Key: "ingress.host", Current: "platform.example.com"},
+		{Key: "replicaCount", Target: "2"},
+	})
+}
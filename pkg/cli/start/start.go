@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"time"
 
 	"github.com/loft-sh/api/v4/pkg/product"
 	"github.com/loft-sh/log"
@@ -29,7 +30,8 @@ type Options struct {
 	Log              log.Logger
 	RestConfig       *rest.Config
 	Context          string
-	Values           string
+	Values           []string
+	SetValues        []string
 	LocalPort        string
 	Version          string
 	DockerImage      string
@@ -50,6 +52,16 @@ type Options struct {
 	Upgrade          bool
 	ReuseValues      bool
 	Docker           bool
+	// Route exposes Loft via an OpenShift route instead of an ingress
+	Route bool
+	// OpenShift is set if the cluster is an OpenShift cluster
+	OpenShift bool
+	// LicenseFile is the path to an offline license key that is activated on install
+	LicenseFile string
+	// DryRun only prints the upgrade plan without upgrading
+	DryRun bool
+	// UpgradeTimeout is the time to wait for an upgraded instance to become ready before it is rolled back
+	UpgradeTimeout time.Duration
 }
 
 func NewLoftStarter(options Options) *LoftStarter {
@@ -66,6 +78,10 @@ type LoftStarter struct {
 func (l *LoftStarter) Start(ctx context.Context) error {
 	// start in Docker?
 	if l.Docker {
+		if l.LicenseFile != "" {
+			return fmt.Errorf("--license-file is not supported with --docker")
+		}
+
 		return l.startDocker(ctx, "loft")
 	}
 
@@ -80,6 +96,11 @@ func (l *LoftStarter) Start(ctx context.Context) error {
 	}
 	l.Log.WriteString(logrus.InfoLevel, "\n")
 
+	err = l.prepareOpenShift(ctx)
+	if err != nil {
+		return err
+	}
+
 	// Uninstall already existing Loft instance
 	if l.Reset {
 		err = clihelper.UninstallLoft(ctx, l.KubeClient, l.RestConfig, l.Context, l.Namespace, l.Log)
@@ -104,6 +125,12 @@ func (l *LoftStarter) Start(ctx context.Context) error {
 		l.Password = defaultPassword
 	}
 
+	// seed the license secret, so it is in place before the Loft deployment references it
+	err = l.prepareLicense(ctx)
+	if err != nil {
+		return err
+	}
+
 	// Upgrade Loft if already installed
 	if isInstalled {
 		return l.handleAlreadyExistingInstallation(ctx)
@@ -119,7 +146,12 @@ func (l *LoftStarter) Start(ctx context.Context) error {
 		return err
 	}
 
-	err = l.upgradeLoft()
+	err = l.upgradeLoft(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = l.activateLicense(ctx)
 	if err != nil {
 		return err
 	}
@@ -284,7 +316,8 @@ func (l *LoftStarter) handleAlreadyExistingInstallation(ctx context.Context) err
 				l.Log.Info(product.Replace("Will enable Loft ingress with hostname: ") + l.Host)
 			}
 
-			if term.IsTerminal(os.Stdin) {
+			// OpenShift comes with a router that serves ingresses and routes
+			if term.IsTerminal(os.Stdin) && !l.OpenShift {
 				err := clihelper.EnsureIngressController(ctx, l.KubeClient, l.Context, l.Log)
 				if err != nil {
 					return errors.Wrap(err, "install ingress controller")
@@ -295,11 +328,16 @@ func (l *LoftStarter) handleAlreadyExistingInstallation(ctx context.Context) err
 
 	// Only upgrade if --upgrade flag is present or user decided to enable ingress
 	if l.Upgrade || enableIngress {
-		err := l.upgradeLoft()
+		err := l.upgradeLoft(ctx)
 		if err != nil {
 			return err
 		}
 	}
 
+	err := l.activateLicense(ctx)
+	if err != nil {
+		return err
+	}
+
 	return l.success(ctx)
 }
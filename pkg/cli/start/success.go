@@ -37,7 +37,7 @@ func (l *LoftStarter) success(ctx context.Context) error {
 	}
 
 	// check if Loft was installed locally
-	isLocal := clihelper.IsLoftInstalledLocally(ctx, l.KubeClient, l.Namespace)
+	isLocal := !l.Route && clihelper.IsLoftInstalledLocally(ctx, l.KubeClient, l.Namespace)
 	if isLocal {
 		// check if loft domain secret is there
 		if !l.NoTunnel {
@@ -61,7 +61,7 @@ func (l *LoftStarter) success(ctx context.Context) error {
 
 	// get login link
 	l.Log.Info("Checking Loft status...")
-	host, err := clihelper.GetLoftIngressHost(ctx, l.KubeClient, l.Namespace)
+	host, err := l.remoteHost(ctx)
 	if err != nil {
 		return err
 	}
@@ -2,6 +2,7 @@ package start
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
@@ -11,9 +12,10 @@ import (
 	"github.com/loft-sh/vcluster/pkg/platform/clihelper"
 	"github.com/mgutz/ansi"
 	"github.com/pkg/errors"
+	"k8s.io/client-go/dynamic"
 )
 
-func (l *LoftStarter) upgradeLoft() error {
+func (l *LoftStarter) upgradeLoft(ctx context.Context) error {
 	extraArgs := []string{}
 	if l.NoTunnel {
 		extraArgs = append(extraArgs, "--set-string", "env.DISABLE_LOFT_ROUTER=true")
@@ -21,7 +23,10 @@ func (l *LoftStarter) upgradeLoft() error {
 	if l.Password != "" {
 		extraArgs = append(extraArgs, "--set", "admin.password="+l.Password)
 	}
-	if l.Host != "" {
+	if l.Host != "" && l.Route {
+		// the route is created after the install
+		extraArgs = append(extraArgs, "--set", "ingress.enabled=false")
+	} else if l.Host != "" {
 		extraArgs = append(extraArgs, "--set", "ingress.enabled=true", "--set", "ingress.host="+l.Host)
 	}
 	if l.Version != "" {
@@ -36,13 +41,11 @@ func (l *LoftStarter) upgradeLoft() error {
 		extraArgs = append(extraArgs, "--reuse-values")
 	}
 
-	if l.Values != "" {
-		absValuesPath, err := filepath.Abs(l.Values)
-		if err != nil {
-			return err
-		}
-		extraArgs = append(extraArgs, "--values", absValuesPath)
+	args, err := valuesArgs(l.Values, l.SetValues)
+	if err != nil {
+		return err
 	}
+	extraArgs = append(extraArgs, args...)
 
 	chartName := l.ChartPath
 	chartRepo := ""
@@ -51,7 +54,7 @@ func (l *LoftStarter) upgradeLoft() error {
 		chartRepo = l.ChartRepo
 	}
 
-	err := clihelper.UpgradeLoft(chartName, chartRepo, l.Context, l.Namespace, extraArgs, l.Log)
+	err = clihelper.UpgradeLoft(chartName, chartRepo, l.Context, l.Namespace, extraArgs, l.Log)
 	if err != nil {
 		if !l.Reset {
 			return errors.New(err.Error() + product.Replace(fmt.Sprintf("\n\nIf want to purge and reinstall Loft, run: %s\n", ansi.Color("loft start --reset", "green+b"))))
@@ -84,5 +87,36 @@ func (l *LoftStarter) upgradeLoft() error {
 		}
 	}
 
+	if l.Host != "" && l.Route {
+		dynamicClient, err := dynamic.NewForConfig(l.RestConfig)
+		if err != nil {
+			return err
+		}
+
+		err = clihelper.EnsureLoftRoute(ctx, dynamicClient, l.Namespace, l.Host)
+		if err != nil {
+			return err
+		}
+		l.Log.Done(product.Replace("Exposed Loft via OpenShift route with hostname ") + l.Host)
+	}
+
 	return nil
 }
+
+// valuesArgs returns the helm arguments for the values files and set values. Helm merges the values files in the
+// given order and applies the set values on top, the same way the values of vcluster create are merged.
+func valuesArgs(values, setValues []string) ([]string, error) {
+	args := []string{}
+	for _, valuesPath := range values {
+		absValuesPath, err := filepath.Abs(valuesPath)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, "--values", absValuesPath)
+	}
+	for _, setValue := range setValues {
+		args = append(args, "--set", setValue)
+	}
+
+	return args, nil
+}
@@ -0,0 +1,26 @@
+package start
+
+import (
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestValuesArgs(t *testing.T) {
+	base, err := filepath.Abs("base.yaml")
+	assert.NilError(t, err)
+
+	args, err := valuesArgs([]string{"base.yaml", "/tmp/prod.yaml"}, []string{"replicaCount=2", "ingress.enabled=false"})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, args, []string{
+		"--values", base,
+		"--values", "/tmp/prod.yaml",
+		"--set", "replicaCount=2",
+		"--set", "ingress.enabled=false",
+	})
+
+	args, err = valuesArgs(nil, nil)
+	assert.NilError(t, err)
+	assert.Equal(t, len(args), 0)
+}
@@ -0,0 +1,314 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	storagev1 "github.com/loft-sh/api/v4/pkg/apis/storage/v1"
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/log/table"
+	"github.com/loft-sh/vcluster/pkg/cli/config"
+	"github.com/loft-sh/vcluster/pkg/cli/find"
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/loft-sh/vcluster/pkg/platform"
+	"github.com/loft-sh/vcluster/pkg/projectutil"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+const (
+	StateAwake    = "awake"
+	StateSleeping = "sleeping"
+	StateNotFound = "not found"
+	StateUnknown  = "unknown"
+)
+
+type StatusOptions struct {
+	// Short prints a single line for shell prompts
+	Short bool
+	// Watch prints the status again whenever it changes
+	Watch    bool
+	Interval time.Duration
+
+	// CacheTTL is how long the cached state of a context is used before it is checked again
+	CacheTTL time.Duration
+	// Timeout of checking the state, the cached or an unknown state is printed if it takes longer
+	Timeout time.Duration
+}
+
+// VClusterStatus is the state of the virtual cluster of a kube context
+type VClusterStatus struct {
+	Context   string            `json:"context"`
+	Driver    config.DriverType `json:"driver,omitempty"`
+	Name      string            `json:"name,omitempty"`
+	Namespace string            `json:"namespace,omitempty"`
+	Project   string            `json:"project,omitempty"`
+	State     string            `json:"state,omitempty"`
+	Checked   time.Time         `json:"checked"`
+}
+
+// StatusCache holds the last checked state by kube context
+type StatusCache map[string]VClusterStatus
+
+// Status prints the virtual cluster of the current kube context and whether it is awake or sleeping. The state is
+// cached by context, so printing it in a shell prompt doesn't call the cluster or the platform on every render.
+func Status(ctx context.Context, options *StatusOptions, globalFlags *flags.GlobalFlags, out io.Writer, log log.Logger) error {
+	if !options.Watch {
+		status, err := currentStatus(ctx, options, globalFlags, log)
+		if err != nil {
+			return err
+		}
+
+		return printStatus(out, status, options.Short, log)
+	}
+
+	interval := options.Interval
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+
+	// the state is checked on every interval, cached states from other shells are only used if they are newer
+	watchOptions := *options
+	watchOptions.CacheTTL = min(options.CacheTTL, interval)
+	options = &watchOptions
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var last *VClusterStatus
+	for {
+		status, err := currentStatus(ctx, options, globalFlags, log)
+		if err != nil {
+			return err
+		}
+		if last == nil || !sameStatus(*last, *status) {
+			err = printStatus(out, status, options.Short, log)
+			if err != nil {
+				return err
+			}
+			last = status
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// currentStatus returns the status of the current kube context from the cache or checks it if the cached status is
+// older than the cache ttl. The context is no virtual cluster context if the driver of the status is empty.
+func currentStatus(ctx context.Context, options *StatusOptions, globalFlags *flags.GlobalFlags, log log.Logger) (*VClusterStatus, error) {
+	rawConfig, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(clientcmd.NewDefaultClientConfigLoadingRules(), &clientcmd.ConfigOverrides{}).RawConfig()
+	if err != nil {
+		return nil, fmt.Errorf("load kube config: %w", err)
+	}
+	contextName := globalFlags.Context
+	if contextName == "" {
+		contextName = rawConfig.CurrentContext
+	}
+
+	cfg := globalFlags.LoadedConfig(log)
+	vClusterContext, ok := vClusterContextOf(contextName, cfg.Contexts)
+	if !ok {
+		return &VClusterStatus{Context: contextName}, nil
+	}
+
+	cachePath, err := cfg.StatusCachePath()
+	if err != nil {
+		return nil, err
+	}
+	cache := readStatusCache(cachePath, log)
+	cached, ok := cache[contextName]
+	if ok && cached.Name == vClusterContext.Name && time.Since(cached.Checked) < options.CacheTTL {
+		return &cached, nil
+	}
+
+	status := &VClusterStatus{
+		Context:   contextName,
+		Driver:    vClusterContext.Driver,
+		Name:      vClusterContext.Name,
+		Namespace: vClusterContext.Namespace,
+		Project:   vClusterContext.Project,
+		Checked:   time.Now(),
+	}
+	checkCtx, cancel := context.WithTimeout(ctx, options.Timeout)
+	defer cancel()
+	status.State, err = checkState(checkCtx, &rawConfig, cfg, vClusterContext)
+	if err != nil {
+		log.Debugf("check state of kube context %s: %v", contextName, err)
+
+		// an unknown state is cached as well, otherwise an unreachable cluster slows down every prompt render
+		status.State = StateUnknown
+		if ok && cached.Name == vClusterContext.Name {
+			status.State = cached.State
+		}
+	}
+
+	// contexts that were removed from the kube config are dropped from the cache
+	for cachedContext := range cache {
+		if _, ok := rawConfig.Contexts[cachedContext]; !ok {
+			delete(cache, cachedContext)
+		}
+	}
+	cache[contextName] = *status
+	err = writeStatusCache(cachePath, cache)
+	if err != nil {
+		log.Debugf("write status cache: %v", err)
+	}
+
+	return status, nil
+}
+
+// checkState returns the state of the virtual cluster from its host cluster or the platform
+func checkState(ctx context.Context, rawConfig *clientcmdapi.Config, cfg *config.CLI, vClusterContext vClusterContext) (string, error) {
+	if vClusterContext.Driver != config.PlatformDriver {
+		vClusters, err := find.ListOSSVClusters(ctx, vClusterContext.ParentContext, vClusterContext.Name, vClusterContext.Namespace)
+		if err != nil {
+			return "", err
+		}
+		for _, vCluster := range vClusters {
+			if vCluster.Namespace == vClusterContext.Namespace && vCluster.Context == vClusterContext.ParentContext {
+				return helmState(vCluster.Status), nil
+			}
+		}
+
+		return StateNotFound, nil
+	}
+
+	if !isContextOfPlatform(rawConfig, vClusterContext, cfg.Platform.Host) {
+		return "", errors.New("the context belongs to another platform than the one you are logged into")
+	}
+	platformClient, err := platform.InitClientFromConfig(ctx, cfg)
+	if err != nil {
+		return "", err
+	}
+	managementClient, err := platformClient.Management()
+	if err != nil {
+		return "", err
+	}
+
+	virtualClusterInstance, err := managementClient.Loft().ManagementV1().VirtualClusterInstances(projectutil.ProjectNamespace(vClusterContext.Project)).Get(ctx, vClusterContext.Name, metav1.GetOptions{})
+	if kerrors.IsNotFound(err) {
+		return StateNotFound, nil
+	} else if err != nil {
+		return "", err
+	}
+
+	return platformState(virtualClusterInstance.Status.Phase), nil
+}
+
+func helmState(status find.Status) string {
+	switch status {
+	case find.StatusRunning:
+		return StateAwake
+	case find.StatusPaused:
+		return StateSleeping
+	case "", find.StatusUnknown:
+		return StateUnknown
+	default:
+		return strings.ToLower(string(status))
+	}
+}
+
+func platformState(phase storagev1.InstancePhase) string {
+	switch phase {
+	case storagev1.InstanceReady:
+		return StateAwake
+	case storagev1.InstanceSleeping:
+		return StateSleeping
+	case "":
+		return StateUnknown
+	default:
+		return strings.ToLower(string(phase))
+	}
+}
+
+// printStatus prints the status, the short status is empty if the context is no virtual cluster context, so shell
+// prompts only show it while connected to a virtual cluster
+func printStatus(out io.Writer, status *VClusterStatus, short bool, log log.Logger) error {
+	if short {
+		if status.Driver == "" {
+			_, err := fmt.Fprintln(out)
+			return err
+		}
+
+		_, err := fmt.Fprintf(out, "%s (%s)\n", status.Name, status.State)
+		return err
+	}
+
+	if status.Driver == "" {
+		log.Infof("Kube context %s doesn't belong to a virtual cluster", status.Context)
+		return nil
+	}
+
+	location := status.Namespace
+	if status.Driver == config.PlatformDriver {
+		location = status.Project
+	}
+	table.PrintTable(log, []string{"CONTEXT", "VCLUSTER", "NAMESPACE / PROJECT", "DRIVER", "STATE", "CHECKED"}, [][]string{{
+		status.Context, status.Name, location, string(status.Driver), status.State, status.Checked.Format(time.RFC3339),
+	}})
+	return nil
+}
+
+func sameStatus(a, b VClusterStatus) bool {
+	return a.Context == b.Context && a.Name == b.Name && a.State == b.State
+}
+
+func readStatusCache(path string, log log.Logger) StatusCache {
+	cache := StatusCache{}
+	out, err := os.ReadFile(path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Debugf("read status cache: %v", err)
+		}
+
+		return cache
+	}
+
+	err = json.Unmarshal(out, &cache)
+	if err != nil {
+		log.Debugf("parse status cache: %v", err)
+		return StatusCache{}
+	}
+
+	return cache
+}
+
+// writeStatusCache replaces the cache atomically, because several shells might render their prompt at the same time
+func writeStatusCache(path string, cache StatusCache) error {
+	out, err := json.Marshal(cache)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return err
+	}
+	file, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	_, err = file.Write(out)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(file.Name(), path)
+}
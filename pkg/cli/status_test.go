@@ -0,0 +1,49 @@
+package cli
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+	"time"
+
+	storagev1 "github.com/loft-sh/api/v4/pkg/apis/storage/v1"
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/vcluster/pkg/cli/config"
+	"github.com/loft-sh/vcluster/pkg/cli/find"
+	"gotest.tools/v3/assert"
+)
+
+func TestStates(t *testing.T) {
+	assert.Equal(t, helmState(find.StatusRunning), StateAwake)
+	assert.Equal(t, helmState(find.StatusPaused), StateSleeping)
+	assert.Equal(t, helmState(""), StateUnknown)
+	assert.Equal(t, helmState("Pending"), "pending")
+
+	assert.Equal(t, platformState(storagev1.InstanceReady), StateAwake)
+	assert.Equal(t, platformState(storagev1.InstanceSleeping), StateSleeping)
+	assert.Equal(t, platformState(""), StateUnknown)
+}
+
+func TestPrintShortStatus(t *testing.T) {
+	out := &bytes.Buffer{}
+	assert.NilError(t, printStatus(out, &VClusterStatus{Context: "kind-kind"}, true, log.Discard))
+	assert.Equal(t, out.String(), "\n")
+
+	out.Reset()
+	assert.NilError(t, printStatus(out, &VClusterStatus{Context: "vcluster_a_ns_kind-kind", Driver: config.HelmDriver, Name: "a", State: StateSleeping}, true, log.Discard))
+	assert.Equal(t, out.String(), "a (sleeping)\n")
+}
+
+func TestStatusCache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "status-cache.json")
+	assert.DeepEqual(t, readStatusCache(path, log.Discard), StatusCache{})
+
+	checked := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	cache := StatusCache{"vcluster_a_ns_kind-kind": {Context: "vcluster_a_ns_kind-kind", Driver: config.HelmDriver, Name: "a", Namespace: "ns", State: StateAwake, Checked: checked}}
+	assert.NilError(t, writeStatusCache(path, cache))
+	assert.DeepEqual(t, readStatusCache(path, log.Discard), cache)
+
+	matches, err := filepath.Glob(path + ".*")
+	assert.NilError(t, err)
+	assert.Equal(t, len(matches), 0)
+}
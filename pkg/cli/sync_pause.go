@@ -0,0 +1,115 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/vcluster/pkg/cli/find"
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	pkgconfig "github.com/loft-sh/vcluster/pkg/config"
+	"github.com/loft-sh/vcluster/pkg/constants"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+type SyncPauseOptions struct {
+	// Resources are the resources whose syncers are paused or resumed, e.g. pods or services
+	Resources []string
+}
+
+// SyncPause pauses the syncers of the given resources at runtime. The syncer keeps the changes queued and syncs them
+// after the resources were resumed.
+func SyncPause(ctx context.Context, globalFlags *flags.GlobalFlags, vClusterName string, options *SyncPauseOptions, log log.Logger) error {
+	if len(options.Resources) == 0 {
+		return fmt.Errorf("please specify the resources to pause via --resource")
+	}
+
+	paused, err := updatePausedSyncers(ctx, globalFlags, vClusterName, func(paused []string) []string {
+		return pauseResources(paused, options.Resources)
+	}, log)
+	if err != nil {
+		return err
+	}
+
+	log.Donef("Paused syncing of %s in vcluster %s, the syncer applies the change within %s", strings.Join(paused, ", "), vClusterName, pkgconfig.ReloadInterval.String())
+	return nil
+}
+
+// SyncResume resumes the syncers of the given resources or all paused syncers if no resources are given
+func SyncResume(ctx context.Context, globalFlags *flags.GlobalFlags, vClusterName string, options *SyncPauseOptions, log log.Logger) error {
+	paused, err := updatePausedSyncers(ctx, globalFlags, vClusterName, func(paused []string) []string {
+		return resumeResources(paused, options.Resources)
+	}, log)
+	if err != nil {
+		return err
+	}
+
+	if len(paused) > 0 {
+		log.Donef("Resumed syncing in vcluster %s, %s are still paused", vClusterName, strings.Join(paused, ", "))
+		return nil
+	}
+
+	log.Donef("Resumed syncing of all resources in vcluster %s", vClusterName)
+	return nil
+}
+
+func updatePausedSyncers(ctx context.Context, globalFlags *flags.GlobalFlags, vClusterName string, update func(paused []string) []string, log log.Logger) ([]string, error) {
+	vCluster, err := find.GetVCluster(ctx, globalFlags.Context, vClusterName, globalFlags.Namespace, log)
+	if err != nil {
+		return nil, err
+	}
+	restConfig, err := vCluster.ClientFactory.ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+	kubeClient, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := kubeClient.CoreV1().Secrets(vCluster.Namespace).Get(ctx, "vc-config-"+vClusterName, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("get config secret of vcluster %s: %w", vClusterName, err)
+	}
+
+	paused := update(pkgconfig.ParsePausedSyncers(secret.Annotations[constants.PausedSyncersAnnotation]))
+	patch := fmt.Sprintf(`{"metadata":{"annotations":{%q:null}}}`, constants.PausedSyncersAnnotation)
+	if len(paused) > 0 {
+		patch = fmt.Sprintf(`{"metadata":{"annotations":{%q:%q}}}`, constants.PausedSyncersAnnotation, strings.Join(paused, ","))
+	}
+	_, err = kubeClient.CoreV1().Secrets(vCluster.Namespace).Patch(ctx, secret.Name, types.MergePatchType, []byte(patch), metav1.PatchOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("update paused syncers of vcluster %s: %w", vClusterName, err)
+	}
+
+	return paused, nil
+}
+
+func pauseResources(paused, resources []string) []string {
+	return pkgconfig.ParsePausedSyncers(strings.Join(append(paused, resources...), ","))
+}
+
+func resumeResources(paused, resources []string) []string {
+	if len(resources) == 0 {
+		return nil
+	}
+
+	remaining := []string{}
+	for _, pausedResource := range paused {
+		resumed := false
+		for _, resource := range resources {
+			if pkgconfig.SyncerMatchesResource(pausedResource, resource) || pkgconfig.SyncerMatchesResource(resource, pausedResource) {
+				resumed = true
+				break
+			}
+		}
+		if !resumed {
+			remaining = append(remaining, pausedResource)
+		}
+	}
+
+	return remaining
+}
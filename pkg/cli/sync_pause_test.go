@@ -0,0 +1,19 @@
+package cli
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestPauseResumeResources(t *testing.T) {
+	paused := pauseResources([]string{"services"}, []string{"Pods", "services", "ingresses"})
+	assert.DeepEqual(t, paused, []string{"ingresses", "pods", "services"})
+
+	// singular and plural refer to the same syncer
+	assert.DeepEqual(t, resumeResources(paused, []string{"pod", "ingresses"}), []string{"services"})
+	assert.DeepEqual(t, resumeResources(paused, []string{"configmaps"}), paused)
+
+	// resuming without resources resumes all
+	assert.Equal(t, len(resumeResources(paused, nil)), 0)
+}
@@ -0,0 +1,198 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/vcluster/config"
+	"github.com/loft-sh/vcluster/pkg/upgrade"
+	"github.com/santhosh-tekuri/jsonschema/v5"
+	"sigs.k8s.io/yaml"
+)
+
+// SchemaURL is the location of the published vcluster.yaml schema of a chart version
+var SchemaURL = "https://raw.githubusercontent.com/loft-sh/vcluster/%s/chart/values.schema.json"
+
+type ValidateOptions struct {
+	Values    []string
+	SetValues []string
+
+	// ChartVersion is the chart version to validate against. If empty, the schema embedded in this binary is used.
+	ChartVersion string
+
+	// ExpandEnv expands environment variables and templates in the values files
+	ExpandEnv bool
+}
+
+// SchemaViolation is a single value of the vcluster.yaml that does not match the schema
+type SchemaViolation struct {
+	Path    string
+	Message string
+}
+
+func (s SchemaViolation) String() string {
+	return s.Path + ": " + s.Message
+}
+
+// Validate validates the given values against the vcluster.yaml schema of the chart version without contacting any
+// cluster
+func Validate(ctx context.Context, options *ValidateOptions, log log.Logger) error {
+	if len(options.Values) == 0 && len(options.SetValues) == 0 {
+		return fmt.Errorf("please specify at least one values file via -f or a value via --set")
+	}
+
+	valuesFiles, cleanupValues, err := resolveValuesFiles(ctx, options.Values, options.ExpandEnv)
+	if err != nil {
+		return err
+	}
+	defer cleanupValues()
+	options.Values = valuesFiles
+
+	schema, err := chartSchema(ctx, options.ChartVersion)
+	if err != nil {
+		return err
+	}
+
+	values, err := mergeAllValues(options.SetValues, options.Values, "")
+	if err != nil {
+		return fmt.Errorf("merge values: %w", err)
+	}
+
+	violations, err := ValidateSchema(schema, []byte(values))
+	if err != nil {
+		return err
+	}
+
+	version := options.ChartVersion
+	if version == "" {
+		version = upgrade.GetVersion()
+	}
+	if len(violations) > 0 {
+		if isLegacyConfig([]byte(values)) {
+			return fmt.Errorf("it appears you are using a vCluster configuration using pre-v0.20 formatting. Please run %q to convert the values to the latest format", "vcluster convert config")
+		}
+
+		lines := []string{}
+		for _, violation := range violations {
+			lines = append(lines, "  - "+violation.String())
+		}
+		return fmt.Errorf("vcluster.yaml is not valid for chart version %s:\n%s", version, strings.Join(lines, "\n"))
+	}
+
+	log.Donef("vcluster.yaml is valid for chart version %s", version)
+	return nil
+}
+
+// ValidateSchema returns the values of the given vcluster.yaml that do not match the JSON schema, sorted by path.
+// Unknown fields are reported as violations as well.
+func ValidateSchema(rawSchema, rawValues []byte) ([]SchemaViolation, error) {
+	compiler := jsonschema.NewCompiler()
+	compiler.Draft = jsonschema.Draft2020
+	err := compiler.AddResource("values.schema.json", bytes.NewReader(rawSchema))
+	if err != nil {
+		return nil, fmt.Errorf("load schema: %w", err)
+	}
+	schema, err := compiler.Compile("values.schema.json")
+	if err != nil {
+		return nil, fmt.Errorf("compile schema: %w", err)
+	}
+
+	rawJSON, err := yaml.YAMLToJSON(rawValues)
+	if err != nil {
+		return nil, fmt.Errorf("parse values: %w", err)
+	}
+	var values interface{}
+	decoder := json.NewDecoder(bytes.NewReader(rawJSON))
+	decoder.UseNumber()
+	err = decoder.Decode(&values)
+	if err != nil {
+		return nil, fmt.Errorf("parse values: %w", err)
+	}
+	if values == nil {
+		values = map[string]interface{}{}
+	}
+
+	err = schema.Validate(values)
+	if err == nil {
+		return nil, nil
+	}
+
+	validationErr := &jsonschema.ValidationError{}
+	if !errors.As(err, &validationErr) {
+		return nil, err
+	}
+
+	violations := []SchemaViolation{}
+	seen := map[SchemaViolation]bool{}
+	collectViolations(validationErr, seen, &violations)
+	sort.SliceStable(violations, func(i, j int) bool {
+		return violations[i].Path < violations[j].Path
+	})
+	return violations, nil
+}
+
+func collectViolations(err *jsonschema.ValidationError, seen map[SchemaViolation]bool, violations *[]SchemaViolation) {
+	if len(err.Causes) > 0 {
+		for _, cause := range err.Causes {
+			collectViolations(cause, seen, violations)
+		}
+		return
+	}
+
+	violation := SchemaViolation{
+		Path:    instancePath(err.InstanceLocation),
+		Message: err.Message,
+	}
+	if !seen[violation] {
+		seen[violation] = true
+		*violations = append(*violations, violation)
+	}
+}
+
+// instancePath converts a JSON pointer such as /sync/toHost/pods to sync.toHost.pods
+func instancePath(pointer string) string {
+	path := strings.ReplaceAll(strings.TrimPrefix(pointer, "/"), "/", ".")
+	path = strings.ReplaceAll(path, "~1", "/")
+	path = strings.ReplaceAll(path, "~0", "~")
+	if path == "" {
+		return "<root>"
+	}
+
+	return path
+}
+
+// chartSchema returns the schema of the given chart version. The schema embedded in this binary is used for the
+// version of this binary, other versions are downloaded.
+func chartSchema(ctx context.Context, chartVersion string) ([]byte, error) {
+	if chartVersion == "" || strings.TrimPrefix(chartVersion, "v") == strings.TrimPrefix(upgrade.GetVersion(), "v") {
+		return []byte(config.Schema), nil
+	}
+
+	url := fmt.Sprintf(SchemaURL, "v"+strings.TrimPrefix(chartVersion, "v"))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("download schema of chart version %s: %w", chartVersion, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("no vcluster.yaml schema published for chart version %s, schemas are only available for v0.20 and newer", chartVersion)
+	} else if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("download schema of chart version %s: unexpected status code %d", chartVersion, resp.StatusCode)
+	}
+
+	return io.ReadAll(resp.Body)
+}
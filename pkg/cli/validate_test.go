@@ -0,0 +1,71 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/vcluster/config"
+	"gotest.tools/v3/assert"
+)
+
+func TestValidateSchema(t *testing.T) {
+	violations, err := ValidateSchema([]byte(config.Schema), []byte(config.Values))
+	assert.NilError(t, err)
+	assert.Equal(t, len(violations), 0)
+
+	violations, err = ValidateSchema([]byte(config.Schema), []byte(`
+sync:
+  toHost:
+    pods:
+      enabled: "yes"
+    unknown: true
+controlPlane:
+  statefulSet:
+    highAvailability:
+      replicas: two
+`))
+	assert.NilError(t, err)
+	paths := []string{}
+	for _, violation := range violations {
+		paths = append(paths, violation.Path)
+	}
+	assert.DeepEqual(t, paths, []string{"controlPlane.statefulSet.highAvailability.replicas", "sync.toHost", "sync.toHost.pods.enabled"})
+	assert.ErrorContains(t, fmt.Errorf("%s", violations[1]), "unknown")
+}
+
+func TestValidate(t *testing.T) {
+	ctx := context.Background()
+	valuesFile := filepath.Join(t.TempDir(), "vcluster.yaml")
+	assert.NilError(t, os.WriteFile(valuesFile, []byte("sync:\n  toHost:\n    ingresses:\n      enabled: true\n"), 0600))
+
+	err := Validate(ctx, &ValidateOptions{Values: []string{valuesFile}}, log.Discard)
+	assert.NilError(t, err)
+
+	err = Validate(ctx, &ValidateOptions{Values: []string{valuesFile}, SetValues: []string{"sync.toHost.unknown=true"}}, log.Discard)
+	assert.ErrorContains(t, err, "sync.toHost: additionalProperties 'unknown' not allowed")
+
+	// other chart versions are downloaded
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/v0.20.0" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"type":"object","properties":{"sync":{"type":"object","additionalProperties":false}}}`))
+	}))
+	defer server.Close()
+	defer func(schemaURL string) { SchemaURL = schemaURL }(SchemaURL)
+	SchemaURL = server.URL + "/%s"
+
+	err = Validate(ctx, &ValidateOptions{Values: []string{valuesFile}, ChartVersion: "0.20.0"}, log.Discard)
+	assert.ErrorContains(t, err, "not valid for chart version 0.20.0")
+
+	err = Validate(ctx, &ValidateOptions{Values: []string{valuesFile}, ChartVersion: "0.19.0"}, log.Discard)
+	assert.ErrorContains(t, err, "no vcluster.yaml schema published")
+}
@@ -0,0 +1,49 @@
+package cli
+
+import (
+	"context"
+	"net/http"
+	"os"
+
+	"github.com/loft-sh/vcluster/pkg/cli/cleanup"
+)
+
+// resolveValuesFiles prepares the values files passed via -f: values passed via -f - are read from stdin, values
+// passed as https:// or oci:// reference are downloaded and, if expandEnv is set, environment variables and templates
+// in the values files are expanded. The returned func removes the temp files that were created for this.
+func resolveValuesFiles(ctx context.Context, values []string, expandEnv bool) ([]string, func(), error) {
+	handles := []*cleanup.Handle{}
+	cleanupValues := func() {
+		for i := len(handles) - 1; i >= 0; i-- {
+			_ = handles[i].Run()
+		}
+	}
+
+	steps := []func([]string) ([]string, *cleanup.Handle, error){
+		func(values []string) ([]string, *cleanup.Handle, error) {
+			return stdinValuesFiles(values, os.Stdin)
+		},
+		func(values []string) ([]string, *cleanup.Handle, error) {
+			return remoteValuesFiles(ctx, values, http.DefaultClient, dockerCredentials)
+		},
+	}
+	if expandEnv {
+		steps = append(steps, func(values []string) ([]string, *cleanup.Handle, error) {
+			return expandValuesFiles(values, os.Environ())
+		})
+	}
+
+	for _, step := range steps {
+		newValues, handle, err := step(values)
+		if err != nil {
+			cleanupValues()
+			return nil, nil, err
+		} else if handle != nil {
+			handles = append(handles, handle)
+		}
+
+		values = newValues
+	}
+
+	return values, cleanupValues, nil
+}
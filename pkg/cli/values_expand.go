@@ -0,0 +1,190 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/loft-sh/vcluster/pkg/cli/cleanup"
+	"sigs.k8s.io/yaml"
+)
+
+// envVarRegEx matches ${VAR} and ${VAR:-default}, $${VAR} is an escaped literal ${VAR}
+var envVarRegEx = regexp.MustCompile(`\$?\$\{([A-Za-z_][A-Za-z0-9_]*)(:-([^}]*))?\}`)
+
+// expandValuesFiles replaces the values files with temp files in which environment variables are substituted and
+// templates are executed. Templates use [[ and ]] as delimiters, so they don't collide with the helm templates some
+// values such as experimental.deploy.vcluster.manifestsTemplate contain. It returns the cleanup of the temp files or
+// nil if nothing was expanded. environ is the environment in the form of os.Environ.
+func expandValuesFiles(values []string, environ []string) ([]string, *cleanup.Handle, error) {
+	if len(values) == 0 {
+		return values, nil, nil
+	}
+
+	tempFiles := []string{}
+	tempFilesCleanup := cleanup.Add("remove expanded values files", func() error {
+		for _, tempFile := range tempFiles {
+			err := os.Remove(tempFile)
+			if err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	newValues := make([]string, 0, len(values))
+	for _, valuesFile := range values {
+		rawValues, err := os.ReadFile(valuesFile)
+		if err != nil {
+			// values can also be passed base64 encoded instead of as a file
+			decoded, decodeErr := getBase64DecodedString(valuesFile)
+			if decodeErr != nil {
+				_ = tempFilesCleanup.Run()
+				return nil, nil, fmt.Errorf("reading values file %s: %w", valuesFile, err)
+			}
+
+			rawValues = []byte(decoded)
+		}
+
+		expanded, err := expandValues(string(rawValues), environ)
+		if err != nil {
+			_ = tempFilesCleanup.Run()
+			return nil, nil, fmt.Errorf("expand values file %s: %w", valuesFile, err)
+		}
+
+		tempFile, err := os.CreateTemp("", "vcluster-expanded-*.yaml")
+		if err != nil {
+			_ = tempFilesCleanup.Run()
+			return nil, nil, fmt.Errorf("create temp values file: %w", err)
+		}
+		tempFiles = append(tempFiles, tempFile.Name())
+
+		_, err = tempFile.WriteString(expanded)
+		_ = tempFile.Close()
+		if err != nil {
+			_ = tempFilesCleanup.Run()
+			return nil, nil, fmt.Errorf("write expanded values to temp values file: %w", err)
+		}
+
+		newValues = append(newValues, tempFile.Name())
+	}
+
+	return newValues, tempFilesCleanup, nil
+}
+
+// expandValues substitutes the environment variables and then executes the templates of the given values
+func expandValues(rawValues string, environ []string) (string, error) {
+	env := map[string]string{}
+	for _, kv := range environ {
+		key, value, _ := strings.Cut(kv, "=")
+		env[key] = value
+	}
+
+	var err error
+	rawValues = envVarRegEx.ReplaceAllStringFunc(rawValues, func(match string) string {
+		if strings.HasPrefix(match, "$$") {
+			return match[1:]
+		}
+
+		groups := envVarRegEx.FindStringSubmatch(match)
+		value, ok := env[groups[1]]
+		if ok {
+			return value
+		} else if groups[2] != "" {
+			return groups[3]
+		} else if err == nil {
+			err = fmt.Errorf("environment variable %s is not set, use ${%s:-default} to fall back to a default value", groups[1], groups[1])
+		}
+
+		return match
+	})
+	if err != nil {
+		return "", err
+	}
+
+	valuesTemplate, err := template.New("values").Delims("[[", "]]").Option("missingkey=error").Funcs(valuesTemplateFuncs(env)).Parse(rawValues)
+	if err != nil {
+		return "", fmt.Errorf("parse template: %w", err)
+	}
+
+	out := &bytes.Buffer{}
+	err = valuesTemplate.Execute(out, map[string]interface{}{"Env": env})
+	if err != nil {
+		return "", fmt.Errorf("execute template: %w", err)
+	}
+
+	return out.String(), nil
+}
+
+// valuesTemplateFuncs is the subset of the sprig functions that is available in values templates
+func valuesTemplateFuncs(env map[string]string) template.FuncMap {
+	return template.FuncMap{
+		"env": func(key string) string {
+			return env[key]
+		},
+		"default": func(defaultValue, value interface{}) interface{} {
+			if value == nil || value == "" || value == false || value == 0 {
+				return defaultValue
+			}
+
+			return value
+		},
+		"required": func(message string, value interface{}) (interface{}, error) {
+			if value == nil || value == "" {
+				return nil, fmt.Errorf("%s", message)
+			}
+
+			return value, nil
+		},
+		"ternary": func(trueValue, falseValue interface{}, condition bool) interface{} {
+			if condition {
+				return trueValue
+			}
+
+			return falseValue
+		},
+		"quote": func(value interface{}) string {
+			return fmt.Sprintf("%q", fmt.Sprint(value))
+		},
+		"squote": func(value interface{}) string {
+			return "'" + fmt.Sprint(value) + "'"
+		},
+		"upper":      strings.ToUpper,
+		"lower":      strings.ToLower,
+		"trim":       strings.TrimSpace,
+		"trimPrefix": func(prefix, s string) string { return strings.TrimPrefix(s, prefix) },
+		"trimSuffix": func(suffix, s string) string { return strings.TrimSuffix(s, suffix) },
+		"replace":    func(old, new, s string) string { return strings.ReplaceAll(s, old, new) },
+		"contains":   func(substr, s string) bool { return strings.Contains(s, substr) },
+		"hasPrefix":  func(prefix, s string) bool { return strings.HasPrefix(s, prefix) },
+		"hasSuffix":  func(suffix, s string) bool { return strings.HasSuffix(s, suffix) },
+		"split":      func(sep, s string) []string { return strings.Split(s, sep) },
+		"join": func(sep string, values []string) string {
+			return strings.Join(values, sep)
+		},
+		"b64enc": func(s string) string {
+			return base64.StdEncoding.EncodeToString([]byte(s))
+		},
+		"b64dec": func(s string) (string, error) {
+			decoded, err := base64.StdEncoding.DecodeString(s)
+			return string(decoded), err
+		},
+		"indent": func(spaces int, s string) string {
+			pad := strings.Repeat(" ", spaces)
+			return pad + strings.ReplaceAll(s, "\n", "\n"+pad)
+		},
+		"nindent": func(spaces int, s string) string {
+			pad := strings.Repeat(" ", spaces)
+			return "\n" + pad + strings.ReplaceAll(s, "\n", "\n"+pad)
+		},
+		"toYaml": func(value interface{}) (string, error) {
+			out, err := yaml.Marshal(value)
+			return strings.TrimSuffix(string(out), "\n"), err
+		},
+	}
+}
@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"encoding/base64"
+	"os"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestExpandValues(t *testing.T) {
+	environ := []string{"DOMAIN=dev.example.com", "REPLICAS=3", "EMPTY="}
+
+	tests := map[string]struct {
+		values   string
+		expected string
+		err      string
+	}{
+		"env var": {
+			values:   "host: ${DOMAIN}\n",
+			expected: "host: dev.example.com\n",
+		},
+		"default": {
+			values:   "host: ${UNSET:-localhost}\nempty: ${EMPTY:-unused}\n",
+			expected: "host: localhost\nempty: \n",
+		},
+		"escaped": {
+			values:   "command: echo $${HOME}\n",
+			expected: "command: echo ${HOME}\n",
+		},
+		"unset": {
+			values: "host: ${UNSET}\n",
+			err:    "environment variable UNSET is not set",
+		},
+		"template": {
+			values:   "replicas: [[ .Env.REPLICAS ]]\nhost: [[ env \"DOMAIN\" | upper | quote ]]\nname: [[ env \"UNSET\" | default \"vcluster\" ]]\n",
+			expected: "replicas: 3\nhost: \"DEV.EXAMPLE.COM\"\nname: vcluster\n",
+		},
+		"helm templates are kept": {
+			values:   "manifestsTemplate: |-\n  name: {{ .Release.Name }}\n",
+			expected: "manifestsTemplate: |-\n  name: {{ .Release.Name }}\n",
+		},
+		"missing key": {
+			values: "host: [[ .Env.UNSET ]]\n",
+			err:    "execute template",
+		},
+		"required": {
+			values: "host: [[ required \"UNSET is required\" (env \"UNSET\") ]]\n",
+			err:    "UNSET is required",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			expanded, err := expandValues(test.values, environ)
+			if test.err != "" {
+				assert.ErrorContains(t, err, test.err)
+				return
+			}
+
+			assert.NilError(t, err)
+			assert.Equal(t, expanded, test.expected)
+		})
+	}
+}
+
+func TestExpandValuesFiles(t *testing.T) {
+	valuesFile, err := os.CreateTemp("", "vcluster-values-*.yaml")
+	assert.NilError(t, err)
+	defer os.Remove(valuesFile.Name())
+	_, err = valuesFile.WriteString("host: ${DOMAIN}\n")
+	assert.NilError(t, err)
+	assert.NilError(t, valuesFile.Close())
+
+	encoded := base64.StdEncoding.EncodeToString([]byte("replicas: ${REPLICAS}\n"))
+	values, tempFilesCleanup, err := expandValuesFiles([]string{valuesFile.Name(), encoded}, []string{"DOMAIN=example.com", "REPLICAS=3"})
+	assert.NilError(t, err)
+	assert.Equal(t, len(values), 2)
+	for i, expected := range []string{"host: example.com\n", "replicas: 3\n"} {
+		content, err := os.ReadFile(values[i])
+		assert.NilError(t, err)
+		assert.Equal(t, string(content), expected)
+	}
+
+	assert.NilError(t, tempFilesCleanup.Run())
+	for _, value := range values {
+		_, err = os.Stat(value)
+		assert.Assert(t, os.IsNotExist(err))
+	}
+
+	_, _, err = expandValuesFiles([]string{"does-not-exist.yaml"}, nil)
+	assert.ErrorContains(t, err, "reading values file")
+}
@@ -0,0 +1,53 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/loft-sh/vcluster/pkg/cli/cleanup"
+	"github.com/loft-sh/vcluster/pkg/cli/presets"
+	"github.com/loft-sh/vcluster/pkg/strvals"
+	"sigs.k8s.io/yaml"
+)
+
+// presetValuesFiles merges the given presets in order into a temp file and puts it in front of the values files, so
+// the values of the user take precedence over the presets. It returns the cleanup of the temp file or nil if no
+// presets are used.
+func presetValuesFiles(presetNames, values []string) ([]string, *cleanup.Handle, error) {
+	if len(presetNames) == 0 {
+		return values, nil, nil
+	}
+
+	presetValues := map[string]interface{}{}
+	for _, name := range presetNames {
+		raw, err := presets.Get(name)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		parsed, err := parseString(string(raw))
+		if err != nil {
+			return nil, nil, fmt.Errorf("parse preset %s: %w", name, err)
+		}
+
+		presetValues = strvals.MergeMaps(presetValues, parsed)
+	}
+
+	rawValues, err := yaml.Marshal(presetValues)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tempFile, tempFileCleanup, err := cleanup.TempFile("vcluster-preset-*.yaml")
+	if err != nil {
+		return nil, nil, fmt.Errorf("create temp preset values file: %w", err)
+	}
+	defer tempFile.Close()
+
+	_, err = tempFile.Write(rawValues)
+	if err != nil {
+		_ = tempFileCleanup.Run()
+		return nil, nil, fmt.Errorf("write preset values to temp values file: %w", err)
+	}
+
+	return append([]string{tempFile.Name()}, values...), tempFileCleanup, nil
+}
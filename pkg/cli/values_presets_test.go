@@ -0,0 +1,66 @@
+package cli
+
+import (
+	"os"
+	"testing"
+
+	"github.com/loft-sh/vcluster/config"
+	"github.com/loft-sh/vcluster/pkg/cli/presets"
+	pkgconfig "github.com/loft-sh/vcluster/pkg/config"
+	"gotest.tools/v3/assert"
+	"sigs.k8s.io/yaml"
+)
+
+func TestPresetValuesFiles(t *testing.T) {
+	values, tempFileCleanup, err := presetValuesFiles(nil, []string{"a.yaml"})
+	assert.NilError(t, err)
+	assert.Assert(t, tempFileCleanup == nil)
+	assert.DeepEqual(t, values, []string{"a.yaml"})
+
+	userValues, err := os.CreateTemp("", "vcluster-values-*.yaml")
+	assert.NilError(t, err)
+	defer os.Remove(userValues.Name())
+	_, err = userValues.WriteString("controlPlane:\n  statefulSet:\n    highAvailability:\n      replicas: 5\n")
+	assert.NilError(t, err)
+	assert.NilError(t, userValues.Close())
+
+	values, tempFileCleanup, err = presetValuesFiles([]string{"ha", "isolated"}, []string{userValues.Name()})
+	assert.NilError(t, err)
+	assert.Equal(t, len(values), 2)
+	assert.Equal(t, values[1], userValues.Name())
+
+	merged, err := mergeAllValues(nil, values, "")
+	assert.NilError(t, err)
+	vConfig := &config.Config{}
+	assert.NilError(t, yaml.Unmarshal([]byte(merged), vConfig))
+	assert.Equal(t, vConfig.ControlPlane.StatefulSet.HighAvailability.Replicas, int32(5))
+	assert.Equal(t, vConfig.ControlPlane.BackingStore.Etcd.Deploy.Enabled, true)
+	assert.Equal(t, vConfig.Policies.NetworkPolicy.Enabled, true)
+
+	assert.NilError(t, tempFileCleanup.Run())
+	_, err = os.Stat(values[0])
+	assert.Assert(t, os.IsNotExist(err))
+
+	_, _, err = presetValuesFiles([]string{"unknown"}, nil)
+	assert.ErrorContains(t, err, "unknown preset")
+}
+
+func TestPresetsAreValid(t *testing.T) {
+	for _, name := range presets.Names() {
+		raw, err := presets.Get(name)
+		assert.NilError(t, err)
+
+		violations, err := ValidateSchema([]byte(config.Schema), raw)
+		assert.NilError(t, err)
+		assert.Equal(t, len(violations), 0, "preset %s: %v", name, violations)
+
+		merged, err := mergeAllValues(nil, nil, config.Values)
+		assert.NilError(t, err)
+		vConfig := &config.Config{}
+		assert.NilError(t, yaml.Unmarshal([]byte(merged), vConfig))
+		assert.NilError(t, yaml.Unmarshal(raw, vConfig))
+		for _, result := range pkgconfig.Lint(vConfig) {
+			assert.Assert(t, result.Severity != pkgconfig.LintSeverityError, "preset %s: %s", name, result)
+		}
+	}
+}
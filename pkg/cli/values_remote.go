@@ -0,0 +1,433 @@
+package cli
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/loft-sh/vcluster/pkg/cli/cleanup"
+	"github.com/loft-sh/vcluster/pkg/docker"
+)
+
+const (
+	// ValuesChecksumParam is the query parameter of a remote values file that holds the expected checksum of the
+	// content, e.g. https://example.com/vcluster.yaml?checksum=sha256:<hex>
+	ValuesChecksumParam = "checksum"
+
+	// maxRemoteValuesSize is the maximum size of a remote values file
+	maxRemoteValuesSize = 10 * 1024 * 1024
+
+	ociTitleAnnotation = "org.opencontainers.image.title"
+)
+
+var ociManifestMediaTypes = []string{
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+}
+
+// authChallengeParamRegEx matches the parameters of a WWW-Authenticate header such as realm="https://auth.docker.io/token"
+var authChallengeParamRegEx = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
+// registryCredentials returns the username and password for the given registry or empty strings for anonymous access
+type registryCredentials func(registry string) (string, string)
+
+// isRemoteValues returns true if the values file is an https:// or oci:// reference
+func isRemoteValues(valuesFile string) bool {
+	return strings.HasPrefix(valuesFile, "https://") || strings.HasPrefix(valuesFile, "oci://")
+}
+
+// remoteValuesFiles replaces the https:// and oci:// values files with temp files that hold the downloaded content,
+// as helm only reads local values files. It returns the cleanup of the temp files or nil if no values file is remote.
+func remoteValuesFiles(ctx context.Context, values []string, httpClient *http.Client, credentials registryCredentials) ([]string, *cleanup.Handle, error) {
+	if !slices.ContainsFunc(values, isRemoteValues) {
+		return values, nil, nil
+	}
+
+	tempFiles := []string{}
+	tempFilesCleanup := cleanup.Add("remove remote values files", func() error {
+		for _, tempFile := range tempFiles {
+			err := os.Remove(tempFile)
+			if err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	newValues := make([]string, 0, len(values))
+	for _, valuesFile := range values {
+		if !isRemoteValues(valuesFile) {
+			newValues = append(newValues, valuesFile)
+			continue
+		}
+
+		rawValues, err := fetchRemoteValues(ctx, valuesFile, httpClient, credentials)
+		if err != nil {
+			_ = tempFilesCleanup.Run()
+			return nil, nil, fmt.Errorf("fetch values file %s: %w", valuesFile, err)
+		}
+
+		tempFile, err := os.CreateTemp("", "vcluster-remote-*.yaml")
+		if err != nil {
+			_ = tempFilesCleanup.Run()
+			return nil, nil, fmt.Errorf("create temp values file: %w", err)
+		}
+		tempFiles = append(tempFiles, tempFile.Name())
+
+		_, err = tempFile.Write(rawValues)
+		_ = tempFile.Close()
+		if err != nil {
+			_ = tempFilesCleanup.Run()
+			return nil, nil, fmt.Errorf("write remote values to temp values file: %w", err)
+		}
+
+		newValues = append(newValues, tempFile.Name())
+	}
+
+	return newValues, tempFilesCleanup, nil
+}
+
+// fetchRemoteValues downloads the given https:// or oci:// values file and verifies its checksum if one is given
+func fetchRemoteValues(ctx context.Context, valuesFile string, httpClient *http.Client, credentials registryCredentials) ([]byte, error) {
+	u, err := url.Parse(valuesFile)
+	if err != nil {
+		return nil, err
+	}
+	query := u.Query()
+	checksum := query.Get(ValuesChecksumParam)
+	query.Del(ValuesChecksumParam)
+	u.RawQuery = query.Encode()
+
+	var rawValues []byte
+	if u.Scheme == "oci" {
+		rawValues, err = fetchOCIValues(ctx, strings.TrimPrefix(u.String(), "oci://"), httpClient, credentials)
+	} else {
+		rawValues, err = fetchHTTPValues(ctx, u.String(), httpClient)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if checksum != "" {
+		err = verifyDigest(rawValues, checksum)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return rawValues, nil
+}
+
+func fetchHTTPValues(ctx context.Context, valuesURL string, httpClient *http.Client) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, valuesURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	return readLimited(resp.Body)
+}
+
+// fetchOCIValues downloads the values file stored as the single layer, or the layer with a .yaml title, of the
+// artifact with the given reference, e.g. pushed via oras push registry/repo:tag vcluster.yaml
+func fetchOCIValues(ctx context.Context, reference string, httpClient *http.Client, credentials registryCredentials) ([]byte, error) {
+	registry, repository, tagOrDigest, err := parseOCIReference(reference)
+	if err != nil {
+		return nil, err
+	}
+
+	client := &ociClient{
+		httpClient:  httpClient,
+		registry:    registry,
+		credentials: credentials,
+	}
+
+	rawManifest, err := client.get(ctx, fmt.Sprintf("/v2/%s/manifests/%s", repository, tagOrDigest), strings.Join(ociManifestMediaTypes, ", "))
+	if err != nil {
+		return nil, fmt.Errorf("get manifest: %w", err)
+	}
+	if strings.Contains(tagOrDigest, ":") {
+		err = verifyDigest(rawManifest, tagOrDigest)
+		if err != nil {
+			return nil, fmt.Errorf("verify manifest: %w", err)
+		}
+	}
+
+	manifest := &ociManifest{}
+	err = json.Unmarshal(rawManifest, manifest)
+	if err != nil {
+		return nil, fmt.Errorf("parse manifest: %w", err)
+	}
+	if len(manifest.Layers) == 0 {
+		return nil, fmt.Errorf("artifact %s is an image index or has no layers, please push the values file as a single layer artifact", reference)
+	}
+
+	layer, err := manifest.valuesLayer()
+	if err != nil {
+		return nil, fmt.Errorf("artifact %s: %w", reference, err)
+	}
+
+	rawValues, err := client.get(ctx, fmt.Sprintf("/v2/%s/blobs/%s", repository, layer.Digest), "")
+	if err != nil {
+		return nil, fmt.Errorf("get layer %s: %w", layer.Digest, err)
+	}
+	err = verifyDigest(rawValues, layer.Digest)
+	if err != nil {
+		return nil, fmt.Errorf("verify layer: %w", err)
+	}
+
+	return rawValues, nil
+}
+
+type ociManifest struct {
+	Layers []ociDescriptor `json:"layers"`
+}
+
+type ociDescriptor struct {
+	MediaType   string            `json:"mediaType"`
+	Digest      string            `json:"digest"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+func (m *ociManifest) valuesLayer() (ociDescriptor, error) {
+	if len(m.Layers) == 1 {
+		return m.Layers[0], nil
+	}
+
+	for _, layer := range m.Layers {
+		title := layer.Annotations[ociTitleAnnotation]
+		if strings.HasSuffix(title, ".yaml") || strings.HasSuffix(title, ".yml") {
+			return layer, nil
+		}
+	}
+
+	return ociDescriptor{}, fmt.Errorf("found %d layers, but none with a .yaml or .yml %s annotation", len(m.Layers), ociTitleAnnotation)
+}
+
+// parseOCIReference splits registry/repository:tag or registry/repository@digest, the tag defaults to latest
+func parseOCIReference(reference string) (registry, repository, tagOrDigest string, err error) {
+	registry, repository, found := strings.Cut(reference, "/")
+	if !found || registry == "" || repository == "" {
+		return "", "", "", fmt.Errorf("invalid oci reference %s, expected oci://registry/repository:tag", reference)
+	}
+
+	if name, digest, found := strings.Cut(repository, "@"); found {
+		repository, tagOrDigest = name, digest
+	} else if i := strings.LastIndex(repository, ":"); i > strings.LastIndex(repository, "/") {
+		repository, tagOrDigest = repository[:i], repository[i+1:]
+	} else {
+		tagOrDigest = "latest"
+	}
+
+	// docker hub is served from another host and uses the library namespace for official images
+	if registry == "docker.io" {
+		registry = "registry-1.docker.io"
+		if !strings.Contains(repository, "/") {
+			repository = "library/" + repository
+		}
+	}
+
+	return registry, repository, tagOrDigest, nil
+}
+
+// ociClient is a minimal client of the OCI distribution API that supports anonymous, basic and bearer token auth
+type ociClient struct {
+	httpClient  *http.Client
+	registry    string
+	credentials registryCredentials
+
+	authorization string
+}
+
+func (c *ociClient) get(ctx context.Context, path, accept string) ([]byte, error) {
+	resp, err := c.do(ctx, path, accept)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusUnauthorized && c.authorization == "" {
+		challenge := resp.Header.Get("WWW-Authenticate")
+		_ = resp.Body.Close()
+		c.authorization, err = c.authorize(ctx, challenge)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err = c.do(ctx, path, accept)
+		if err != nil {
+			return nil, err
+		}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d from %s", resp.StatusCode, c.registry)
+	}
+
+	return readLimited(resp.Body)
+}
+
+func (c *ociClient) do(ctx context.Context, path, accept string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://"+c.registry+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	if c.authorization != "" {
+		req.Header.Set("Authorization", c.authorization)
+	}
+
+	return c.httpClient.Do(req)
+}
+
+// authorize answers the WWW-Authenticate challenge of the registry and returns the Authorization header to use
+func (c *ociClient) authorize(ctx context.Context, challenge string) (string, error) {
+	username, password := "", ""
+	if c.credentials != nil {
+		username, password = c.credentials(c.registry)
+	}
+
+	scheme, rawParams, _ := strings.Cut(challenge, " ")
+	switch strings.ToLower(scheme) {
+	case "basic":
+		if username == "" {
+			return "", fmt.Errorf("registry %s requires credentials, please log in via docker login %s", c.registry, c.registry)
+		}
+
+		return "Basic " + base64.StdEncoding.EncodeToString([]byte(username+":"+password)), nil
+	case "bearer":
+	default:
+		return "", fmt.Errorf("registry %s uses the unsupported auth challenge %q", c.registry, challenge)
+	}
+
+	params := map[string]string{}
+	for _, match := range authChallengeParamRegEx.FindAllStringSubmatch(rawParams, -1) {
+		params[match[1]] = match[2]
+	}
+	if params["realm"] == "" {
+		return "", fmt.Errorf("registry %s sent a bearer challenge without realm", c.registry)
+	}
+
+	tokenURL, err := url.Parse(params["realm"])
+	if err != nil {
+		return "", fmt.Errorf("parse token realm: %w", err)
+	}
+	query := tokenURL.Query()
+	for _, key := range []string{"service", "scope"} {
+		if params[key] != "" {
+			query.Set(key, params[key])
+		}
+	}
+	tokenURL.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, tokenURL.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	if username != "" {
+		req.SetBasicAuth(username, password)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("get registry token: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("get registry token: unexpected status code %d", resp.StatusCode)
+	}
+
+	token := struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}{}
+	err = json.NewDecoder(resp.Body).Decode(&token)
+	if err != nil {
+		return "", fmt.Errorf("parse registry token: %w", err)
+	}
+	if token.Token == "" {
+		token.Token = token.AccessToken
+	}
+
+	return "Bearer " + token.Token, nil
+}
+
+// verifyDigest checks the content against a digest in the form of sha256:<hex> or sha512:<hex>
+func verifyDigest(content []byte, digest string) error {
+	algorithm, expected, found := strings.Cut(digest, ":")
+	if !found {
+		return fmt.Errorf("invalid checksum %s, expected sha256:<hex> or sha512:<hex>", digest)
+	}
+
+	var hasher hash.Hash
+	switch algorithm {
+	case "sha256":
+		hasher = sha256.New()
+	case "sha512":
+		hasher = sha512.New()
+	default:
+		return fmt.Errorf("unsupported checksum algorithm %s, please use sha256 or sha512", algorithm)
+	}
+
+	_, _ = hasher.Write(content)
+	actual := hex.EncodeToString(hasher.Sum(nil))
+	if !strings.EqualFold(actual, expected) {
+		return fmt.Errorf("checksum mismatch: expected %s, got %s:%s", digest, algorithm, actual)
+	}
+
+	return nil
+}
+
+func readLimited(reader io.Reader) ([]byte, error) {
+	content, err := io.ReadAll(io.LimitReader(reader, maxRemoteValuesSize+1))
+	if err != nil {
+		return nil, err
+	} else if len(content) > maxRemoteValuesSize {
+		return nil, fmt.Errorf("content is larger than %d bytes", maxRemoteValuesSize)
+	}
+
+	return content, nil
+}
+
+// dockerCredentials returns the credentials of the registry from the docker config, e.g. stored via docker login
+func dockerCredentials(registry string) (string, string) {
+	dockerConfig, err := docker.NewDockerConfig()
+	if err != nil {
+		return "", ""
+	}
+
+	// docker stores the credentials of docker hub under its legacy index address
+	if registry == "registry-1.docker.io" {
+		registry = "https://index.docker.io/v1/"
+	}
+
+	authConfig, err := dockerConfig.AuthConfig(registry)
+	if err != nil {
+		return "", ""
+	}
+
+	return authConfig.Username, authConfig.Password
+}
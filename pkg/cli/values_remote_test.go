@@ -0,0 +1,183 @@
+package cli
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+const remoteTestValues = "controlPlane:\n  distro:\n    k8s:\n      enabled: true\n"
+
+func sha256Digest(content []byte) string {
+	sum := sha256.Sum256(content)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// newTestRegistry serves remoteTestValues as single layer artifact my-org/values:v1, requires a bearer token and returns
+// the digest of the manifest
+func newTestRegistry(t *testing.T) (*httptest.Server, string) {
+	layerDigest := sha256Digest([]byte(remoteTestValues))
+	manifest, err := json.Marshal(map[string]interface{}{
+		"schemaVersion": 2,
+		"mediaType":     "application/vnd.oci.image.manifest.v1+json",
+		"layers": []map[string]interface{}{{
+			"mediaType":   "application/vnd.oci.image.layer.v1.tar",
+			"digest":      layerDigest,
+			"annotations": map[string]string{ociTitleAnnotation: "vcluster.yaml"},
+		}},
+	})
+	assert.NilError(t, err)
+
+	var server *httptest.Server
+	server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/token":
+			assert.Equal(t, r.URL.Query().Get("scope"), "repository:my-org/values:pull")
+			_, _ = w.Write([]byte(`{"token":"secret"}`))
+		case r.Header.Get("Authorization") != "Bearer secret":
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="test",scope="repository:my-org/values:pull"`, server.URL))
+			w.WriteHeader(http.StatusUnauthorized)
+		case r.URL.Path == "/v2/my-org/values/manifests/v1" || r.URL.Path == "/v2/my-org/values/manifests/"+sha256Digest(manifest):
+			_, _ = w.Write(manifest)
+		case r.URL.Path == "/v2/my-org/values/blobs/"+layerDigest:
+			_, _ = w.Write([]byte(remoteTestValues))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+
+	return server, sha256Digest(manifest)
+}
+
+func TestFetchRemoteValues(t *testing.T) {
+	registry, manifestDigest := newTestRegistry(t)
+	defer registry.Close()
+	registryHost := strings.TrimPrefix(registry.URL, "https://")
+
+	web := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/vcluster.yaml" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		_, _ = w.Write([]byte(remoteTestValues))
+	}))
+	defer web.Close()
+
+	testCases := []struct {
+		name      string
+		values    string
+		client    *http.Client
+		expectErr string
+	}{
+		{
+			name:   "https",
+			values: web.URL + "/vcluster.yaml",
+			client: web.Client(),
+		},
+		{
+			name:   "https with checksum",
+			values: web.URL + "/vcluster.yaml?checksum=" + sha256Digest([]byte(remoteTestValues)),
+			client: web.Client(),
+		},
+		{
+			name:      "https with wrong checksum",
+			values:    web.URL + "/vcluster.yaml?checksum=" + sha256Digest([]byte("other")),
+			client:    web.Client(),
+			expectErr: "checksum mismatch",
+		},
+		{
+			name:      "https with unsupported checksum",
+			values:    web.URL + "/vcluster.yaml?checksum=md5:abc",
+			client:    web.Client(),
+			expectErr: "unsupported checksum algorithm md5",
+		},
+		{
+			name:      "https not found",
+			values:    web.URL + "/other.yaml",
+			client:    web.Client(),
+			expectErr: "unexpected status code 404",
+		},
+		{
+			name:   "oci tag",
+			values: "oci://" + registryHost + "/my-org/values:v1",
+			client: registry.Client(),
+		},
+		{
+			name:   "oci digest",
+			values: "oci://" + registryHost + "/my-org/values@" + manifestDigest,
+			client: registry.Client(),
+		},
+		{
+			name:      "oci missing tag",
+			values:    "oci://" + registryHost + "/my-org/values",
+			client:    registry.Client(),
+			expectErr: "unexpected status code 404",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rawValues, err := fetchRemoteValues(context.Background(), tc.values, tc.client, nil)
+			if tc.expectErr != "" {
+				assert.ErrorContains(t, err, tc.expectErr)
+				return
+			}
+
+			assert.NilError(t, err)
+			assert.Equal(t, string(rawValues), remoteTestValues)
+		})
+	}
+}
+
+func TestRemoteValuesFiles(t *testing.T) {
+	web := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(remoteTestValues))
+	}))
+	defer web.Close()
+
+	values, cleanup, err := remoteValuesFiles(context.Background(), []string{"local.yaml"}, web.Client(), nil)
+	assert.NilError(t, err)
+	assert.Assert(t, cleanup == nil)
+	assert.DeepEqual(t, values, []string{"local.yaml"})
+
+	values, cleanup, err = remoteValuesFiles(context.Background(), []string{"local.yaml", web.URL + "/vcluster.yaml"}, web.Client(), nil)
+	assert.NilError(t, err)
+	assert.Equal(t, len(values), 2)
+	assert.Equal(t, values[0], "local.yaml")
+
+	rawValues, err := os.ReadFile(values[1])
+	assert.NilError(t, err)
+	assert.Equal(t, string(rawValues), remoteTestValues)
+
+	assert.NilError(t, cleanup.Run())
+	_, err = os.Stat(values[1])
+	assert.Assert(t, os.IsNotExist(err))
+}
+
+func TestParseOCIReference(t *testing.T) {
+	testCases := map[string][3]string{
+		"ghcr.io/my-org/values:v1":                  {"ghcr.io", "my-org/values", "v1"},
+		"localhost:5000/values":                     {"localhost:5000", "values", "latest"},
+		"ghcr.io/my-org/values@sha256:abc":          {"ghcr.io", "my-org/values", "sha256:abc"},
+		"docker.io/values:v1":                       {"registry-1.docker.io", "library/values", "v1"},
+		"registry.example.com:443/a/b/values:1.0.0": {"registry.example.com:443", "a/b/values", "1.0.0"},
+	}
+	for reference, expected := range testCases {
+		registry, repository, tagOrDigest, err := parseOCIReference(reference)
+		assert.NilError(t, err, reference)
+		assert.DeepEqual(t, [3]string{registry, repository, tagOrDigest}, expected)
+	}
+
+	_, _, _, err := parseOCIReference("values")
+	assert.ErrorContains(t, err, "invalid oci reference")
+}
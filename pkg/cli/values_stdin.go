@@ -0,0 +1,57 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/loft-sh/vcluster/pkg/cli/cleanup"
+)
+
+// StdinValues is the values file that is read from stdin
+const StdinValues = "-"
+
+// stdinValuesFiles replaces the values file "-" with a temp file that holds the values read from stdin, as the values
+// files are read more than once. It returns the cleanup of the temp file or nil if stdin is not used.
+func stdinValuesFiles(values []string, stdin io.Reader) ([]string, *cleanup.Handle, error) {
+	found := false
+	for _, value := range values {
+		if value == StdinValues {
+			if found {
+				return nil, nil, fmt.Errorf("values can only be read once from stdin, please use -f %s only once", StdinValues)
+			}
+
+			found = true
+		}
+	}
+	if !found {
+		return values, nil, nil
+	}
+
+	rawValues, err := io.ReadAll(stdin)
+	if err != nil {
+		return nil, nil, fmt.Errorf("read values from stdin: %w", err)
+	}
+
+	tempFile, tempFileCleanup, err := cleanup.TempFile("vcluster-values-*.yaml")
+	if err != nil {
+		return nil, nil, fmt.Errorf("create temp values file: %w", err)
+	}
+	defer tempFile.Close()
+
+	_, err = tempFile.Write(rawValues)
+	if err != nil {
+		_ = tempFileCleanup.Run()
+		return nil, nil, fmt.Errorf("write stdin values to temp values file: %w", err)
+	}
+
+	newValues := make([]string, 0, len(values))
+	for _, value := range values {
+		if value == StdinValues {
+			value = tempFile.Name()
+		}
+
+		newValues = append(newValues, value)
+	}
+
+	return newValues, tempFileCleanup, nil
+}
@@ -0,0 +1,32 @@
+package cli
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestStdinValuesFiles(t *testing.T) {
+	values, tempFileCleanup, err := stdinValuesFiles([]string{"a.yaml"}, strings.NewReader("unused"))
+	assert.NilError(t, err)
+	assert.Assert(t, tempFileCleanup == nil)
+	assert.DeepEqual(t, values, []string{"a.yaml"})
+
+	values, tempFileCleanup, err = stdinValuesFiles([]string{"a.yaml", "-", "b.yaml"}, strings.NewReader("sync:\n  toHost: {}\n"))
+	assert.NilError(t, err)
+	assert.Equal(t, len(values), 3)
+	assert.Equal(t, values[0], "a.yaml")
+	assert.Equal(t, values[2], "b.yaml")
+	content, err := os.ReadFile(values[1])
+	assert.NilError(t, err)
+	assert.Equal(t, string(content), "sync:\n  toHost: {}\n")
+
+	assert.NilError(t, tempFileCleanup.Run())
+	_, err = os.Stat(values[1])
+	assert.Assert(t, os.IsNotExist(err))
+
+	_, _, err = stdinValuesFiles([]string{"-", "-"}, strings.NewReader(""))
+	assert.ErrorContains(t, err, "only once")
+}
@@ -0,0 +1,37 @@
+package cli
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestResolveValuesFiles(t *testing.T) {
+	t.Setenv("VCLUSTER_TEST_DOMAIN", "dev.example.com")
+	valuesFile := filepath.Join(t.TempDir(), "values.yaml")
+	assert.NilError(t, os.WriteFile(valuesFile, []byte("host: ${VCLUSTER_TEST_DOMAIN}\n"), 0600))
+
+	// local files are passed as they are without expansion
+	values, cleanupValues, err := resolveValuesFiles(context.Background(), []string{valuesFile}, false)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, values, []string{valuesFile})
+	cleanupValues()
+
+	// expanded values are written to a temp file that is removed by the cleanup
+	values, cleanupValues, err = resolveValuesFiles(context.Background(), []string{valuesFile}, true)
+	assert.NilError(t, err)
+	assert.Equal(t, len(values), 1)
+	assert.Assert(t, values[0] != valuesFile)
+	expanded, err := os.ReadFile(values[0])
+	assert.NilError(t, err)
+	assert.Equal(t, string(expanded), "host: dev.example.com\n")
+	cleanupValues()
+	_, err = os.Stat(values[0])
+	assert.Assert(t, os.IsNotExist(err))
+
+	_, _, err = resolveValuesFiles(context.Background(), []string{StdinValues, StdinValues}, false)
+	assert.ErrorContains(t, err, "values can only be read once from stdin")
+}
@@ -0,0 +1,326 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/log/table"
+	"github.com/loft-sh/vcluster/pkg/cli/find"
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/loft-sh/vcluster/pkg/util/random"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// DefaultVerifyServerImage is the default image of the probe server, needs to serve http on port 8080
+	DefaultVerifyServerImage = "nginxinc/nginx-unprivileged"
+
+	// DefaultVerifyClientImage is the default image of the probe clients, needs to provide nslookup and wget
+	DefaultVerifyClientImage = "busybox"
+
+	verifyLabel      = "vcluster.loft.sh/verify"
+	verifyServerPort = 8080
+)
+
+type VerifyOptions struct {
+	Output string
+
+	// Timeout is the time a single check may take
+	Timeout time.Duration
+
+	ServerImage string
+	ClientImage string
+}
+
+// VerifyResult is the result of a single smoke test check
+type VerifyResult struct {
+	Check    string        `json:"check"`
+	Passed   bool          `json:"passed"`
+	Message  string        `json:"message"`
+	Duration time.Duration `json:"duration"`
+}
+
+// Verify runs a smoke test against the virtual cluster: it checks that the api server responds, deploys a probe pod and
+// service into a temporary namespace and checks that the pod is scheduled on the host, that DNS resolves and that the
+// service is reachable. The namespace is deleted afterwards.
+func Verify(ctx context.Context, options *VerifyOptions, globalFlags *flags.GlobalFlags, vClusterName string, log log.Logger) error {
+	vCluster, err := find.GetVCluster(ctx, globalFlags.Context, vClusterName, globalFlags.Namespace, log)
+	if err != nil {
+		return err
+	}
+
+	connectCmd := &connectHelm{
+		GlobalFlags:    globalFlags,
+		ConnectOptions: &ConnectOptions{},
+		Log:            log,
+	}
+	err = connectCmd.prepare(ctx, vCluster)
+	if err != nil {
+		return err
+	}
+
+	// the checks run through a silenced port-forwarding like `vcluster connect -- command`, so they do not depend on how
+	// the virtual cluster is exposed
+	kubeConfig, err := connectCmd.getVClusterKubeConfig(ctx, vCluster.Name, []string{"vcluster", "verify"})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		close(connectCmd.interruptChan)
+		<-connectCmd.errorChan
+	}()
+
+	log.Infof("Verifying virtual cluster %s in namespace %s...", vCluster.Name, vCluster.Namespace)
+	start := time.Now()
+	err = connectCmd.waitForVCluster(ctx, *kubeConfig, connectCmd.errorChan)
+	if err != nil {
+		return err
+	}
+	log.Debugf("Virtual cluster became reachable after %s", time.Since(start).Round(time.Millisecond))
+
+	vKubeClient, err := getLocalVClusterClient(*kubeConfig, connectCmd.ConnectOptions)
+	if err != nil {
+		return err
+	}
+
+	results := runVerifyChecks(ctx, vKubeClient, connectCmd.kubeClient, vCluster.Namespace, options, log)
+	if options.Output == "json" {
+		out, err := json.MarshalIndent(results, "", "    ")
+		if err != nil {
+			return fmt.Errorf("json marshal verify results: %w", err)
+		}
+
+		log.WriteString(logrus.InfoLevel, string(out)+"\n")
+	} else {
+		values := [][]string{}
+		for _, result := range results {
+			status := "PASSED"
+			if !result.Passed {
+				status = "FAILED"
+			}
+
+			values = append(values, []string{result.Check, status, result.Duration.Round(time.Millisecond).String(), result.Message})
+		}
+		table.PrintTable(log, []string{"CHECK", "RESULT", "DURATION", "MESSAGE"}, values)
+	}
+
+	failed := 0
+	for _, result := range results {
+		if !result.Passed {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d checks of virtual cluster %s failed", failed, len(results), vCluster.Name)
+	}
+
+	log.Donef("Virtual cluster %s passed all checks", vCluster.Name)
+	return nil
+}
+
+// runVerifyChecks runs the checks within the virtual cluster, the host client is only used to look up the host pod of
+// the probe. Checks that depend on a failed check are reported as failed without running them.
+func runVerifyChecks(ctx context.Context, vKubeClient, hostKubeClient kubernetes.Interface, hostNamespace string, options *VerifyOptions, log log.Logger) []VerifyResult {
+	results := []VerifyResult{}
+	run := func(check string, fn func(ctx context.Context) (string, error)) bool {
+		log.Infof("Checking %s...", check)
+		start := time.Now()
+		message, err := fn(ctx)
+		result := VerifyResult{Check: check, Passed: err == nil, Message: message, Duration: time.Since(start)}
+		if err != nil {
+			result.Message = err.Error()
+		}
+
+		results = append(results, result)
+		return result.Passed
+	}
+	skip := func(checks ...string) []VerifyResult {
+		for _, check := range checks {
+			results = append(results, VerifyResult{Check: check, Message: "skipped because a previous check failed"})
+		}
+
+		return results
+	}
+
+	if !run("api-server", func(ctx context.Context) (string, error) {
+		return verifyAPIServer(ctx, vKubeClient)
+	}) {
+		return skip("pod-scheduling", "dns", "service-connectivity")
+	}
+
+	namespace := "vcluster-verify-" + random.String(6)
+	var serverAddress string
+	if !run("pod-scheduling", func(ctx context.Context) (string, error) {
+		_, err := vKubeClient.CoreV1().Namespaces().Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name:   namespace,
+			Labels: map[string]string{verifyLabel: "true"},
+		}}, metav1.CreateOptions{})
+		if err != nil {
+			return "", fmt.Errorf("create namespace: %w", err)
+		}
+
+		var message string
+		serverAddress, message, err = startVerifyServer(ctx, vKubeClient, hostKubeClient, namespace, hostNamespace, options)
+		return message, err
+	}) {
+		deleteVerifyNamespace(vKubeClient, namespace, log)
+		return skip("dns", "service-connectivity")
+	}
+	defer deleteVerifyNamespace(vKubeClient, namespace, log)
+
+	run("dns", func(ctx context.Context) (string, error) {
+		host := "server." + namespace + ".svc.cluster.local"
+		err := runVerifyProbe(ctx, vKubeClient, namespace, "dns", options.ClientImage, []string{"nslookup", host}, options.Timeout)
+		if err != nil {
+			return "", fmt.Errorf("resolve %s: %w", host, err)
+		}
+
+		return "resolved " + host, nil
+	})
+	run("service-connectivity", func(ctx context.Context) (string, error) {
+		// the cluster ip is used instead of the service name, so this check does not depend on DNS
+		err := runVerifyProbe(ctx, vKubeClient, namespace, "connectivity", options.ClientImage, []string{"wget", "-q", "-T", "3", "-O", "/dev/null", "http://" + serverAddress}, options.Timeout)
+		if err != nil {
+			return "", fmt.Errorf("connect to service %s: %w", serverAddress, err)
+		}
+
+		return "reached service " + serverAddress, nil
+	})
+
+	return results
+}
+
+// verifyAPIServer checks that the api server serves discovery and list requests
+func verifyAPIServer(ctx context.Context, vKubeClient kubernetes.Interface) (string, error) {
+	serverVersion, err := vKubeClient.Discovery().ServerVersion()
+	if err != nil {
+		return "", fmt.Errorf("get server version: %w", err)
+	}
+
+	start := time.Now()
+	_, err = vKubeClient.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("list namespaces: %w", err)
+	}
+
+	return fmt.Sprintf("kubernetes %s, listed namespaces in %s", serverVersion.GitVersion, time.Since(start).Round(time.Millisecond)), nil
+}
+
+// startVerifyServer creates the server pod and service and returns the address of the service once the pod is running
+// on a host node
+func startVerifyServer(ctx context.Context, vKubeClient, hostKubeClient kubernetes.Interface, namespace, hostNamespace string, options *VerifyOptions) (string, string, error) {
+	labels := map[string]string{verifyLabel: "server"}
+	pod, err := vKubeClient.CoreV1().Pods(namespace).Create(ctx, &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "server", Namespace: namespace, Labels: labels},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{
+				Name:  "server",
+				Image: options.ServerImage,
+				Ports: []corev1.ContainerPort{{ContainerPort: verifyServerPort}},
+			}},
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return "", "", fmt.Errorf("create server pod: %w", err)
+	}
+
+	service, err := vKubeClient.CoreV1().Services(namespace).Create(ctx, &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "server", Namespace: namespace},
+		Spec: corev1.ServiceSpec{
+			Selector: labels,
+			Ports:    []corev1.ServicePort{{Port: verifyServerPort, TargetPort: intstr.FromInt32(verifyServerPort)}},
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return "", "", fmt.Errorf("create server service: %w", err)
+	}
+
+	err = wait.PollUntilContextTimeout(ctx, time.Second, options.Timeout, true, func(ctx context.Context) (bool, error) {
+		pod, err = vKubeClient.CoreV1().Pods(namespace).Get(ctx, pod.Name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+
+		return pod.Status.Phase == corev1.PodRunning && pod.Spec.NodeName != "", nil
+	})
+	if err != nil {
+		return "", "", fmt.Errorf("wait for server pod to be running: %w%s", err, podWaitingReason(pod))
+	}
+
+	message := "scheduled on node " + pod.Spec.NodeName
+	hostPods, err := hostKubeClient.CoreV1().Pods(hostNamespace).List(ctx, metav1.ListOptions{
+		LabelSelector: translate.NamespaceLabel + "=" + namespace,
+	})
+	if err == nil && len(hostPods.Items) > 0 {
+		message += " as host pod " + hostNamespace + "/" + hostPods.Items[0].Name
+	}
+
+	return service.Spec.ClusterIP + ":" + strconv.Itoa(verifyServerPort), message, nil
+}
+
+// runVerifyProbe runs the command in a pod and returns an error if the pod did not succeed
+func runVerifyProbe(ctx context.Context, vKubeClient kubernetes.Interface, namespace, name, image string, command []string, timeout time.Duration) error {
+	pod, err := vKubeClient.CoreV1().Pods(namespace).Create(ctx, &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: map[string]string{verifyLabel: "client"}},
+		Spec: corev1.PodSpec{
+			RestartPolicy: corev1.RestartPolicyNever,
+			Containers: []corev1.Container{{
+				Name:    "client",
+				Image:   image,
+				Command: command,
+			}},
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("create probe pod: %w", err)
+	}
+
+	err = wait.PollUntilContextTimeout(ctx, time.Second, timeout, true, func(ctx context.Context) (bool, error) {
+		pod, err = vKubeClient.CoreV1().Pods(namespace).Get(ctx, pod.Name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+
+		return pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed, nil
+	})
+	if err != nil {
+		return fmt.Errorf("wait for probe pod %s to complete: %w%s", name, err, podWaitingReason(pod))
+	} else if pod.Status.Phase == corev1.PodFailed {
+		return fmt.Errorf("probe pod %s failed", name)
+	}
+
+	return nil
+}
+
+// podWaitingReason returns why the containers of the pod are not running yet, e.g. an image pull error
+func podWaitingReason(pod *corev1.Pod) string {
+	if pod == nil {
+		return ""
+	}
+
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.State.Waiting != nil && status.State.Waiting.Reason != "" {
+			return fmt.Sprintf(" (container %s is waiting: %s)", status.Name, status.State.Waiting.Reason)
+		}
+	}
+
+	return ""
+}
+
+func deleteVerifyNamespace(vKubeClient kubernetes.Interface, namespace string, log log.Logger) {
+	err := vKubeClient.CoreV1().Namespaces().Delete(context.Background(), namespace, metav1.DeleteOptions{})
+	if err != nil && !kerrors.IsNotFound(err) {
+		log.Warnf("Error deleting verify namespace %s: %v", namespace, err)
+	}
+}
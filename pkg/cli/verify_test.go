@@ -0,0 +1,64 @@
+package cli
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/loft-sh/log"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+func TestVerifyChecks(t *testing.T) {
+	options := &VerifyOptions{Timeout: 3 * time.Second, ServerImage: DefaultVerifyServerImage, ClientImage: DefaultVerifyClientImage}
+	newClient := func(failedProbe string) *fake.Clientset {
+		kubeClient := fake.NewSimpleClientset()
+		kubeClient.PrependReactor("create", "pods", func(action clienttesting.Action) (bool, runtime.Object, error) {
+			pod := action.(clienttesting.CreateAction).GetObject().(*corev1.Pod)
+			switch pod.Name {
+			case "server":
+				pod.Spec.NodeName = "node-1"
+				pod.Status.Phase = corev1.PodRunning
+			case failedProbe:
+				pod.Status.Phase = corev1.PodFailed
+			default:
+				pod.Status.Phase = corev1.PodSucceeded
+			}
+			return false, nil, nil
+		})
+		kubeClient.PrependReactor("create", "services", func(action clienttesting.Action) (bool, runtime.Object, error) {
+			action.(clienttesting.CreateAction).GetObject().(*corev1.Service).Spec.ClusterIP = "10.96.0.10"
+			return false, nil, nil
+		})
+		return kubeClient
+	}
+	checks := func(results []VerifyResult) map[string]bool {
+		out := map[string]bool{}
+		for _, result := range results {
+			out[result.Check] = result.Passed
+		}
+		return out
+	}
+
+	vKubeClient := newClient("")
+	results := runVerifyChecks(context.Background(), vKubeClient, fake.NewSimpleClientset(), "test", options, log.Discard)
+	assert.DeepEqual(t, checks(results), map[string]bool{"api-server": true, "pod-scheduling": true, "dns": true, "service-connectivity": true})
+	assert.Equal(t, results[1].Message, "scheduled on node node-1")
+	assert.Equal(t, results[3].Message, "reached service 10.96.0.10:8080")
+
+	// the temporary namespace is deleted afterwards
+	namespaces, err := vKubeClient.CoreV1().Namespaces().List(context.Background(), metav1.ListOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, len(namespaces.Items), 0)
+
+	// a failed probe fails its check only
+	results = runVerifyChecks(context.Background(), newClient("dns"), fake.NewSimpleClientset(), "test", options, log.Discard)
+	assert.DeepEqual(t, checks(results), map[string]bool{"api-server": true, "pod-scheduling": true, "dns": false, "service-connectivity": true})
+	assert.Assert(t, strings.Contains(results[2].Message, "probe pod dns failed"), results[2].Message)
+}
@@ -0,0 +1,580 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/vcluster/pkg/cli/find"
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	VolumesMigrateMethodCopy     = "copy"
+	VolumesMigrateMethodSnapshot = "snapshot"
+
+	// DefaultVolumesMigrateImage is the default image of the migration pod, needs to provide cp
+	DefaultVolumesMigrateImage = "busybox"
+
+	volumesMigrateLabel  = "vcluster.loft.sh/volumes-migrate"
+	volumesMigrateSuffix = "-migrate"
+)
+
+var volumeSnapshotGVR = schema.GroupVersionResource{Group: "snapshot.storage.k8s.io", Version: "v1", Resource: "volumesnapshots"}
+
+// annotations of a bound claim that must not be copied to the recreated claim
+var volumesMigrateBindAnnotations = []string{
+	"pv.kubernetes.io/bind-completed",
+	"pv.kubernetes.io/bound-by-controller",
+	"volume.beta.kubernetes.io/storage-provisioner",
+	"volume.kubernetes.io/storage-provisioner",
+	"volume.kubernetes.io/selected-node",
+	"volume.beta.kubernetes.io/storage-class",
+}
+
+type VolumesMigrateOptions struct {
+	FromClass string
+	ToClass   string
+
+	// PersistentVolumeClaims limits the migration to these claims in the format namespace/name
+	PersistentVolumeClaims []string
+
+	// Method is either copy, which copies the data with a pod, or snapshot, which restores a csi snapshot of the claim
+	Method        string
+	SnapshotClass string
+	Image         string
+
+	// Timeout is the time a single step of the migration of a claim may take
+	Timeout time.Duration
+
+	// DryRun only prints the claims that would be migrated
+	DryRun bool
+}
+
+// VolumesMigrate moves the data of the virtual persistent volume claims of the storage class FromClass to new volumes
+// of the storage class ToClass. The data is copied to a temporary claim, afterwards the virtual claim is recreated
+// with the same name and bound to the new volume and the old volume is deleted. Pods must not use the claims during
+// the migration.
+func VolumesMigrate(ctx context.Context, options *VolumesMigrateOptions, globalFlags *flags.GlobalFlags, vClusterName string, log log.Logger) error {
+	err := validateVolumesMigrateOptions(options)
+	if err != nil {
+		return err
+	}
+
+	vCluster, err := find.GetVCluster(ctx, globalFlags.Context, vClusterName, globalFlags.Namespace, log)
+	if err != nil {
+		return err
+	}
+
+	connectCmd := &connectHelm{
+		GlobalFlags:    globalFlags,
+		ConnectOptions: &ConnectOptions{},
+		Log:            log,
+	}
+	err = connectCmd.prepare(ctx, vCluster)
+	if err != nil {
+		return err
+	}
+
+	kubeConfig, err := connectCmd.getVClusterKubeConfig(ctx, vCluster.Name, []string{"vcluster", "volumes", "migrate"})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		close(connectCmd.interruptChan)
+		<-connectCmd.errorChan
+	}()
+	err = connectCmd.waitForVCluster(ctx, *kubeConfig, connectCmd.errorChan)
+	if err != nil {
+		return err
+	}
+
+	vRestConfig, err := clientcmd.NewDefaultClientConfig(getLocalVClusterConfig(*kubeConfig, connectCmd.ConnectOptions), &clientcmd.ConfigOverrides{}).ClientConfig()
+	if err != nil {
+		return fmt.Errorf("create virtual rest config: %w", err)
+	}
+	vKubeClient, err := kubernetes.NewForConfig(vRestConfig)
+	if err != nil {
+		return err
+	}
+	vDynamicClient, err := dynamic.NewForConfig(vRestConfig)
+	if err != nil {
+		return err
+	}
+
+	_, err = vKubeClient.StorageV1().StorageClasses().Get(ctx, options.ToClass, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("get storage class %s in virtual cluster: %w", options.ToClass, err)
+	}
+
+	claims, err := volumesMigrateClaims(ctx, vKubeClient, options)
+	if err != nil {
+		return err
+	} else if len(claims) == 0 {
+		log.Infof("No bound persistent volume claims of storage class %s found in vcluster %s", options.FromClass, vCluster.Name)
+		return nil
+	}
+
+	inUse, err := claimsInUse(ctx, vKubeClient, claims)
+	if err != nil {
+		return err
+	} else if len(inUse) > 0 {
+		return fmt.Errorf("persistent volume claims %s are used by pods, please scale down the workloads before migrating", strings.Join(inUse, ", "))
+	}
+
+	if options.DryRun {
+		for _, claim := range claims {
+			log.Infof("Would migrate persistent volume claim %s/%s from storage class %s to %s", claim.Namespace, claim.Name, options.FromClass, options.ToClass)
+		}
+		return nil
+	}
+
+	migrator := &volumeMigrator{
+		vKubeClient:    vKubeClient,
+		vDynamicClient: vDynamicClient,
+		hostKubeClient: connectCmd.kubeClient,
+		hostNamespace:  vCluster.Namespace,
+		vClusterName:   vCluster.Name,
+		options:        options,
+		log:            log,
+	}
+	for _, claim := range claims {
+		log.Infof("Migrating persistent volume claim %s/%s from storage class %s to %s...", claim.Namespace, claim.Name, options.FromClass, options.ToClass)
+		err = migrator.migrate(ctx, claim)
+		if err != nil {
+			return fmt.Errorf("migrate persistent volume claim %s/%s: %w", claim.Namespace, claim.Name, err)
+		}
+
+		log.Donef("Migrated persistent volume claim %s/%s to storage class %s", claim.Namespace, claim.Name, options.ToClass)
+	}
+
+	return nil
+}
+
+func validateVolumesMigrateOptions(options *VolumesMigrateOptions) error {
+	if options.FromClass == "" || options.ToClass == "" {
+		return fmt.Errorf("please specify --from-class and --to-class")
+	} else if options.FromClass == options.ToClass {
+		return fmt.Errorf("--from-class and --to-class must be different")
+	}
+	if options.Method != VolumesMigrateMethodCopy && options.Method != VolumesMigrateMethodSnapshot {
+		return fmt.Errorf("invalid method %q, must be one of: %s, %s", options.Method, VolumesMigrateMethodCopy, VolumesMigrateMethodSnapshot)
+	}
+	for _, claim := range options.PersistentVolumeClaims {
+		if namespace, name, ok := strings.Cut(claim, "/"); !ok || namespace == "" || name == "" {
+			return fmt.Errorf("invalid persistent volume claim %q, must be in the format namespace/name", claim)
+		}
+	}
+
+	return nil
+}
+
+// volumesMigrateClaims returns the bound virtual claims of the storage class that should be migrated
+func volumesMigrateClaims(ctx context.Context, vKubeClient kubernetes.Interface, options *VolumesMigrateOptions) ([]corev1.PersistentVolumeClaim, error) {
+	claimList, err := vKubeClient.CoreV1().PersistentVolumeClaims("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("list persistent volume claims: %w", err)
+	}
+
+	claims := []corev1.PersistentVolumeClaim{}
+	for _, claim := range claimList.Items {
+		if ptr.Deref(claim.Spec.StorageClassName, "") != options.FromClass || claim.Status.Phase != corev1.ClaimBound || claim.DeletionTimestamp != nil {
+			continue
+		} else if claim.Labels[volumesMigrateLabel] != "" {
+			// temporary claim of a previous migration
+			continue
+		} else if len(options.PersistentVolumeClaims) > 0 && !slices.Contains(options.PersistentVolumeClaims, claim.Namespace+"/"+claim.Name) {
+			continue
+		}
+
+		claims = append(claims, claim)
+	}
+
+	return claims, nil
+}
+
+// claimsInUse returns the claims that are mounted by pods that are not terminated
+func claimsInUse(ctx context.Context, vKubeClient kubernetes.Interface, claims []corev1.PersistentVolumeClaim) ([]string, error) {
+	inUse := []string{}
+	pods := map[string][]corev1.Pod{}
+	for _, claim := range claims {
+		if _, ok := pods[claim.Namespace]; !ok {
+			podList, err := vKubeClient.CoreV1().Pods(claim.Namespace).List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return nil, fmt.Errorf("list pods: %w", err)
+			}
+
+			pods[claim.Namespace] = podList.Items
+		}
+
+		for _, pod := range pods[claim.Namespace] {
+			if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+				continue
+			}
+			if slices.ContainsFunc(pod.Spec.Volumes, func(volume corev1.Volume) bool {
+				return volume.PersistentVolumeClaim != nil && volume.PersistentVolumeClaim.ClaimName == claim.Name
+			}) {
+				inUse = append(inUse, claim.Namespace+"/"+claim.Name)
+				break
+			}
+		}
+	}
+
+	return inUse, nil
+}
+
+type volumeMigrator struct {
+	vKubeClient    kubernetes.Interface
+	vDynamicClient dynamic.Interface
+	hostKubeClient kubernetes.Interface
+
+	hostNamespace string
+	vClusterName  string
+
+	options *VolumesMigrateOptions
+	log     log.Logger
+}
+
+func (m *volumeMigrator) migrate(ctx context.Context, claim corev1.PersistentVolumeClaim) (retErr error) {
+	targetName := claim.Name + volumesMigrateSuffix
+
+	// create the temporary claim of the new storage class with the data of the claim
+	var dataSource *corev1.TypedLocalObjectReference
+	if m.options.Method == VolumesMigrateMethodSnapshot {
+		err := m.createSnapshot(ctx, claim, targetName)
+		if err != nil {
+			return err
+		}
+		defer m.deleteSnapshot(claim.Namespace, targetName)
+
+		dataSource = &corev1.TypedLocalObjectReference{APIGroup: ptr.To(volumeSnapshotGVR.Group), Kind: "VolumeSnapshot", Name: targetName}
+	}
+	_, err := m.vKubeClient.CoreV1().PersistentVolumeClaims(claim.Namespace).Create(ctx, newMigrationClaim(claim, targetName, m.options.ToClass, dataSource), metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("create temporary persistent volume claim: %w", err)
+	}
+	claimsDeleted := false
+	defer func() {
+		// the claim is left untouched if the data couldn't be copied
+		if retErr != nil && !claimsDeleted {
+			err := m.vKubeClient.CoreV1().PersistentVolumeClaims(claim.Namespace).Delete(context.Background(), targetName, metav1.DeleteOptions{})
+			if err != nil && !kerrors.IsNotFound(err) {
+				m.log.Warnf("Error deleting temporary persistent volume claim %s/%s: %v", claim.Namespace, targetName, err)
+			}
+		}
+	}()
+
+	// the pod copies the data and makes sure the volume is provisioned for storage classes that wait for the first consumer
+	err = m.runMigrationPod(ctx, claim, targetName)
+	if err != nil {
+		return err
+	}
+	target, err := m.waitForClaimBound(ctx, claim.Namespace, targetName)
+	if err != nil {
+		return err
+	}
+
+	// keep the new volume when the temporary claim is deleted
+	hostTarget, err := m.hostClaim(ctx, target)
+	if err != nil {
+		return err
+	}
+	hostSource, err := m.hostClaim(ctx, &claim)
+	if err != nil {
+		return err
+	}
+	volume, err := m.hostKubeClient.CoreV1().PersistentVolumes().Get(ctx, hostTarget.Spec.VolumeName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("get host persistent volume: %w", err)
+	}
+	reclaimPolicy := volume.Spec.PersistentVolumeReclaimPolicy
+	err = m.patchHostVolume(ctx, volume.Name, map[string]interface{}{"persistentVolumeReclaimPolicy": corev1.PersistentVolumeReclaimRetain})
+	if err != nil {
+		return err
+	}
+
+	// delete the temporary and the old claim, the old volume is deleted depending on its reclaim policy
+	claimsDeleted = true
+	for _, name := range []string{targetName, claim.Name} {
+		err = m.vKubeClient.CoreV1().PersistentVolumeClaims(claim.Namespace).Delete(ctx, name, metav1.DeleteOptions{})
+		if err != nil && !kerrors.IsNotFound(err) {
+			return fmt.Errorf("delete persistent volume claim %s: %w", name, err)
+		}
+	}
+	for _, hostClaim := range []*corev1.PersistentVolumeClaim{hostTarget, hostSource} {
+		err = m.waitForHostClaimDeleted(ctx, hostClaim.Name)
+		if err != nil {
+			return err
+		}
+	}
+
+	// reserve the new volume for the host claim of the recreated virtual claim, which keeps its host name
+	err = m.patchHostVolume(ctx, volume.Name, map[string]interface{}{
+		"claimRef": map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "PersistentVolumeClaim",
+			"namespace":  m.hostNamespace,
+			"name":       hostSource.Name,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = m.vKubeClient.CoreV1().PersistentVolumeClaims(claim.Namespace).Create(ctx, newMigratedClaim(claim, m.options.ToClass), metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("recreate persistent volume claim, the data is retained in host persistent volume %s: %w", volume.Name, err)
+	}
+	_, err = m.waitForClaimBound(ctx, claim.Namespace, claim.Name)
+	if err != nil {
+		return err
+	}
+
+	return m.patchHostVolume(ctx, volume.Name, map[string]interface{}{"persistentVolumeReclaimPolicy": reclaimPolicy})
+}
+
+func (m *volumeMigrator) createSnapshot(ctx context.Context, claim corev1.PersistentVolumeClaim, name string) error {
+	spec := map[string]interface{}{
+		"source": map[string]interface{}{"persistentVolumeClaimName": claim.Name},
+	}
+	if m.options.SnapshotClass != "" {
+		spec["volumeSnapshotClassName"] = m.options.SnapshotClass
+	}
+
+	snapshot := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": volumeSnapshotGVR.GroupVersion().String(),
+		"kind":       "VolumeSnapshot",
+		"metadata": map[string]interface{}{
+			"name":      name,
+			"namespace": claim.Namespace,
+			"labels":    map[string]interface{}{volumesMigrateLabel: claim.Name},
+		},
+		"spec": spec,
+	}}
+	_, err := m.vDynamicClient.Resource(volumeSnapshotGVR).Namespace(claim.Namespace).Create(ctx, snapshot, metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("create volume snapshot: %w", err)
+	}
+
+	err = wait.PollUntilContextTimeout(ctx, 2*time.Second, m.options.Timeout, true, func(ctx context.Context) (bool, error) {
+		snapshot, err = m.vDynamicClient.Resource(volumeSnapshotGVR).Namespace(claim.Namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+
+		ready, _, _ := unstructured.NestedBool(snapshot.Object, "status", "readyToUse")
+		return ready, nil
+	})
+	if err != nil {
+		return fmt.Errorf("wait for volume snapshot %s to become ready: %w", name, err)
+	}
+
+	return nil
+}
+
+func (m *volumeMigrator) deleteSnapshot(namespace, name string) {
+	err := m.vDynamicClient.Resource(volumeSnapshotGVR).Namespace(namespace).Delete(context.Background(), name, metav1.DeleteOptions{})
+	if err != nil && !kerrors.IsNotFound(err) {
+		m.log.Warnf("Error deleting volume snapshot %s/%s: %v", namespace, name, err)
+	}
+}
+
+func (m *volumeMigrator) runMigrationPod(ctx context.Context, claim corev1.PersistentVolumeClaim, targetName string) error {
+	pod := newMigrationPod(claim, targetName, m.options.Method, m.options.Image)
+	_, err := m.vKubeClient.CoreV1().Pods(claim.Namespace).Create(ctx, pod, metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("create migration pod: %w", err)
+	}
+	defer func() {
+		err := m.vKubeClient.CoreV1().Pods(claim.Namespace).Delete(context.Background(), pod.Name, metav1.DeleteOptions{GracePeriodSeconds: ptr.To(int64(0))})
+		if err != nil && !kerrors.IsNotFound(err) {
+			m.log.Warnf("Error deleting migration pod %s/%s: %v", claim.Namespace, pod.Name, err)
+		}
+	}()
+
+	err = wait.PollUntilContextTimeout(ctx, 2*time.Second, m.options.Timeout, true, func(ctx context.Context) (bool, error) {
+		pod, err = m.vKubeClient.CoreV1().Pods(claim.Namespace).Get(ctx, pod.Name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+
+		return pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed, nil
+	})
+	if err != nil {
+		return fmt.Errorf("wait for migration pod %s to complete: %w%s", pod.Name, err, podWaitingReason(pod))
+	} else if pod.Status.Phase == corev1.PodFailed {
+		return fmt.Errorf("migration pod %s failed, the claim was not changed", pod.Name)
+	}
+
+	return nil
+}
+
+func (m *volumeMigrator) waitForClaimBound(ctx context.Context, namespace, name string) (*corev1.PersistentVolumeClaim, error) {
+	var claim *corev1.PersistentVolumeClaim
+	err := wait.PollUntilContextTimeout(ctx, 2*time.Second, m.options.Timeout, true, func(ctx context.Context) (bool, error) {
+		var err error
+		claim, err = m.vKubeClient.CoreV1().PersistentVolumeClaims(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+
+		return claim.Status.Phase == corev1.ClaimBound, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("wait for persistent volume claim %s/%s to become bound: %w", namespace, name, err)
+	}
+
+	return claim, nil
+}
+
+// hostClaim returns the host claim of the virtual claim, the host name is looked up through the annotations set by the
+// syncer, so it doesn't depend on the name translation of the virtual cluster
+func (m *volumeMigrator) hostClaim(ctx context.Context, vClaim *corev1.PersistentVolumeClaim) (*corev1.PersistentVolumeClaim, error) {
+	hostClaim, err := findHostClaim(ctx, m.hostKubeClient, m.hostNamespace, m.vClusterName, vClaim.Namespace, vClaim.Name)
+	if err != nil {
+		return nil, err
+	} else if hostClaim.Spec.VolumeName == "" {
+		return nil, fmt.Errorf("host persistent volume claim %s/%s is not bound", hostClaim.Namespace, hostClaim.Name)
+	}
+
+	return hostClaim, nil
+}
+
+func findHostClaim(ctx context.Context, hostKubeClient kubernetes.Interface, hostNamespace, vClusterName, namespace, name string) (*corev1.PersistentVolumeClaim, error) {
+	claimList, err := hostKubeClient.CoreV1().PersistentVolumeClaims(hostNamespace).List(ctx, metav1.ListOptions{LabelSelector: translate.MarkerLabel + "=" + vClusterName})
+	if err != nil {
+		return nil, fmt.Errorf("list host persistent volume claims: %w", err)
+	}
+
+	for _, claim := range claimList.Items {
+		if claim.Annotations[translate.NameAnnotation] == name && claim.Annotations[translate.NamespaceAnnotation] == namespace {
+			return &claim, nil
+		}
+	}
+
+	return nil, kerrors.NewNotFound(corev1.Resource("persistentvolumeclaims"), fmt.Sprintf("%s/%s in host namespace %s", namespace, name, hostNamespace))
+}
+
+func (m *volumeMigrator) waitForHostClaimDeleted(ctx context.Context, name string) error {
+	err := wait.PollUntilContextTimeout(ctx, 2*time.Second, m.options.Timeout, true, func(ctx context.Context) (bool, error) {
+		_, err := m.hostKubeClient.CoreV1().PersistentVolumeClaims(m.hostNamespace).Get(ctx, name, metav1.GetOptions{})
+		if kerrors.IsNotFound(err) {
+			return true, nil
+		}
+
+		return false, err
+	})
+	if err != nil {
+		return fmt.Errorf("wait for host persistent volume claim %s/%s to be deleted: %w", m.hostNamespace, name, err)
+	}
+
+	return nil
+}
+
+func (m *volumeMigrator) patchHostVolume(ctx context.Context, name string, spec map[string]interface{}) error {
+	patch, err := json.Marshal(map[string]interface{}{"spec": spec})
+	if err != nil {
+		return err
+	}
+
+	_, err = m.hostKubeClient.CoreV1().PersistentVolumes().Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		return fmt.Errorf("patch host persistent volume %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// newMigrationClaim returns the temporary claim of the new storage class that receives the data
+func newMigrationClaim(claim corev1.PersistentVolumeClaim, name, storageClass string, dataSource *corev1.TypedLocalObjectReference) *corev1.PersistentVolumeClaim {
+	resources := *claim.Spec.Resources.DeepCopy()
+	if capacity, ok := claim.Status.Capacity[corev1.ResourceStorage]; ok {
+		if resources.Requests == nil {
+			resources.Requests = corev1.ResourceList{}
+		}
+		if request := resources.Requests[corev1.ResourceStorage]; request.Cmp(capacity) < 0 {
+			resources.Requests[corev1.ResourceStorage] = capacity
+		}
+	}
+
+	return &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: claim.Namespace,
+			Labels:    map[string]string{volumesMigrateLabel: claim.Name},
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes:      claim.Spec.AccessModes,
+			Resources:        resources,
+			StorageClassName: ptr.To(storageClass),
+			VolumeMode:       claim.Spec.VolumeMode,
+			DataSource:       dataSource,
+		},
+	}
+}
+
+// newMigratedClaim returns the claim that replaces the migrated claim, it keeps the name, labels and annotations
+func newMigratedClaim(claim corev1.PersistentVolumeClaim, storageClass string) *corev1.PersistentVolumeClaim {
+	annotations := map[string]string{}
+	for k, v := range claim.Annotations {
+		if !slices.Contains(volumesMigrateBindAnnotations, k) {
+			annotations[k] = v
+		}
+	}
+
+	migrated := newMigrationClaim(claim, claim.Name, storageClass, nil)
+	migrated.Labels = claim.Labels
+	migrated.Annotations = annotations
+	return migrated
+}
+
+// newMigrationPod returns the pod that mounts the temporary claim and copies the data of the claim if needed
+func newMigrationPod(claim corev1.PersistentVolumeClaim, targetName, method, image string) *corev1.Pod {
+	volumes := []corev1.Volume{{
+		Name:         "target",
+		VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: targetName}},
+	}}
+	mounts := []corev1.VolumeMount{{Name: "target", MountPath: "/target"}}
+	command := []string{"true"}
+	if method == VolumesMigrateMethodCopy {
+		volumes = append(volumes, corev1.Volume{
+			Name:         "source",
+			VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: claim.Name, ReadOnly: true}},
+		})
+		mounts = append(mounts, corev1.VolumeMount{Name: "source", MountPath: "/source", ReadOnly: true})
+		command = []string{"cp", "-a", "/source/.", "/target/"}
+	}
+
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      targetName,
+			Namespace: claim.Namespace,
+			Labels:    map[string]string{volumesMigrateLabel: claim.Name},
+		},
+		Spec: corev1.PodSpec{
+			RestartPolicy: corev1.RestartPolicyNever,
+			Containers: []corev1.Container{{
+				Name:         "migrate",
+				Image:        image,
+				Command:      command,
+				VolumeMounts: mounts,
+			}},
+			Volumes: volumes,
+		},
+	}
+}
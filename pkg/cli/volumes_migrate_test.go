@@ -0,0 +1,150 @@
+package cli
+
+import (
+	"context"
+	"testing"
+
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/ptr"
+)
+
+func TestValidateVolumesMigrateOptions(t *testing.T) {
+	testCases := []struct {
+		name    string
+		options VolumesMigrateOptions
+		wantErr string
+	}{
+		{
+			name:    "valid",
+			options: VolumesMigrateOptions{FromClass: "gp2", ToClass: "gp3", Method: VolumesMigrateMethodCopy, PersistentVolumeClaims: []string{"default/data"}},
+		},
+		{
+			name:    "missing class",
+			options: VolumesMigrateOptions{FromClass: "gp2", Method: VolumesMigrateMethodCopy},
+			wantErr: "please specify --from-class and --to-class",
+		},
+		{
+			name:    "same class",
+			options: VolumesMigrateOptions{FromClass: "gp2", ToClass: "gp2", Method: VolumesMigrateMethodCopy},
+			wantErr: "--from-class and --to-class must be different",
+		},
+		{
+			name:    "invalid method",
+			options: VolumesMigrateOptions{FromClass: "gp2", ToClass: "gp3", Method: "rsync"},
+			wantErr: `invalid method "rsync", must be one of: copy, snapshot`,
+		},
+		{
+			name:    "invalid claim",
+			options: VolumesMigrateOptions{FromClass: "gp2", ToClass: "gp3", Method: VolumesMigrateMethodSnapshot, PersistentVolumeClaims: []string{"data"}},
+			wantErr: `invalid persistent volume claim "data", must be in the format namespace/name`,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateVolumesMigrateOptions(&tt.options)
+			if tt.wantErr == "" {
+				assert.NilError(t, err)
+			} else {
+				assert.Error(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestVolumesMigrateClaims(t *testing.T) {
+	vKubeClient := fake.NewSimpleClientset(
+		newTestClaim("default", "data", "gp2", corev1.ClaimBound),
+		newTestClaim("default", "logs", "gp2", corev1.ClaimBound),
+		newTestClaim("default", "pending", "gp2", corev1.ClaimPending),
+		newTestClaim("default", "other", "gp3", corev1.ClaimBound),
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+			Spec: corev1.PodSpec{Volumes: []corev1.Volume{{
+				Name:         "data",
+				VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "data"}},
+			}}},
+			Status: corev1.PodStatus{Phase: corev1.PodRunning},
+		},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "job", Namespace: "default"},
+			Spec: corev1.PodSpec{Volumes: []corev1.Volume{{
+				Name:         "logs",
+				VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "logs"}},
+			}}},
+			Status: corev1.PodStatus{Phase: corev1.PodSucceeded},
+		},
+	)
+
+	claims, err := volumesMigrateClaims(context.Background(), vKubeClient, &VolumesMigrateOptions{FromClass: "gp2"})
+	assert.NilError(t, err)
+	assert.Equal(t, len(claims), 2)
+
+	// completed pods don't block the migration
+	inUse, err := claimsInUse(context.Background(), vKubeClient, claims)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, inUse, []string{"default/data"})
+
+	claims, err = volumesMigrateClaims(context.Background(), vKubeClient, &VolumesMigrateOptions{FromClass: "gp2", PersistentVolumeClaims: []string{"default/logs"}})
+	assert.NilError(t, err)
+	assert.Equal(t, len(claims), 1)
+	assert.Equal(t, claims[0].Name, "logs")
+}
+
+func TestFindHostClaim(t *testing.T) {
+	hostClaim := newTestClaim("vcluster-ns", "data-x-default-x-my-vcluster", "gp2", corev1.ClaimBound)
+	hostClaim.Labels = map[string]string{translate.MarkerLabel: "my-vcluster"}
+	hostClaim.Annotations = map[string]string{translate.NameAnnotation: "data", translate.NamespaceAnnotation: "default"}
+	hostClaim.Spec.VolumeName = "pvc-123"
+	hostKubeClient := fake.NewSimpleClientset(hostClaim)
+
+	found, err := findHostClaim(context.Background(), hostKubeClient, "vcluster-ns", "my-vcluster", "default", "data")
+	assert.NilError(t, err)
+	assert.Equal(t, found.Name, "data-x-default-x-my-vcluster")
+
+	_, err = findHostClaim(context.Background(), hostKubeClient, "vcluster-ns", "my-vcluster", "default", "logs")
+	assert.Assert(t, kerrors.IsNotFound(err))
+}
+
+func TestNewMigratedClaim(t *testing.T) {
+	claim := newTestClaim("default", "data", "gp2", corev1.ClaimBound)
+	claim.Labels = map[string]string{"app": "postgres"}
+	claim.Annotations = map[string]string{"pv.kubernetes.io/bind-completed": "yes", "team": "db"}
+	claim.Spec.VolumeName = "pvc-123"
+	claim.Spec.Resources.Requests = corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("1Gi")}
+	claim.Status.Capacity = corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("2Gi")}
+
+	migrated := newMigratedClaim(*claim, "gp3")
+	assert.Equal(t, migrated.Name, "data")
+	assert.Equal(t, *migrated.Spec.StorageClassName, "gp3")
+	assert.Equal(t, migrated.Spec.VolumeName, "")
+	assert.DeepEqual(t, migrated.Labels, map[string]string{"app": "postgres"})
+	assert.DeepEqual(t, migrated.Annotations, map[string]string{"team": "db"})
+
+	// the new volume must fit the data of the old volume
+	request := migrated.Spec.Resources.Requests[corev1.ResourceStorage]
+	assert.Equal(t, request.String(), "2Gi")
+
+	pod := newMigrationPod(*claim, "data-migrate", VolumesMigrateMethodCopy, DefaultVolumesMigrateImage)
+	assert.Equal(t, len(pod.Spec.Volumes), 2)
+	assert.DeepEqual(t, pod.Spec.Containers[0].Command, []string{"cp", "-a", "/source/.", "/target/"})
+
+	pod = newMigrationPod(*claim, "data-migrate", VolumesMigrateMethodSnapshot, DefaultVolumesMigrateImage)
+	assert.Equal(t, len(pod.Spec.Volumes), 1)
+}
+
+func newTestClaim(namespace, name, storageClass string, phase corev1.PersistentVolumeClaimPhase) *corev1.PersistentVolumeClaim {
+	return &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+			StorageClassName: ptr.To(storageClass),
+		},
+		Status: corev1.PersistentVolumeClaimStatus{Phase: phase},
+	}
+}
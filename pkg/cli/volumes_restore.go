@@ -0,0 +1,336 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/vcluster/pkg/cli/find"
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/loft-sh/vcluster/pkg/controllers/resources/persistentvolumes"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/yaml"
+)
+
+type VolumesExportOptions struct {
+	// File is the file the volumes are written to, if empty they are printed
+	File string
+
+	// Retain changes the reclaim policy of the exported volumes to Retain, so their data isn't deleted together with
+	// the virtual cluster before they were imported
+	Retain bool
+}
+
+type VolumesImportOptions struct {
+	// File is the file written by vcluster volumes export
+	File string
+
+	// DryRun only prints the volumes that would be created
+	DryRun bool
+}
+
+// ExportedVolumes is the list of host volumes of a virtual cluster written by vcluster volumes export
+type ExportedVolumes struct {
+	Volumes []ExportedVolume `json:"volumes"`
+}
+
+// ExportedVolume is a host volume and the virtual claim it is bound to
+type ExportedVolume struct {
+	// Namespace and Claim are the namespace and name of the virtual claim
+	Namespace string `json:"namespace"`
+	Claim     string `json:"claim"`
+
+	// Volume is the host volume of the claim
+	Volume corev1.PersistentVolume `json:"volume"`
+}
+
+// VolumesExport writes the host volumes that are bound to the claims of the virtual cluster to a file, so they can be
+// recreated with VolumesImport after the virtual cluster was restored to another host cluster.
+func VolumesExport(ctx context.Context, options *VolumesExportOptions, globalFlags *flags.GlobalFlags, vClusterName string, log log.Logger) error {
+	if options.File == "" {
+		// the volumes are printed to stdout
+		log = log.ErrorStreamOnly()
+	}
+
+	vCluster, err := find.GetVCluster(ctx, globalFlags.Context, vClusterName, globalFlags.Namespace, log)
+	if err != nil {
+		return err
+	}
+	restConfig, err := vCluster.ClientFactory.ClientConfig()
+	if err != nil {
+		return err
+	}
+	kubeClient, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+
+	export, err := exportVolumes(ctx, kubeClient, vCluster.Namespace, vCluster.Name)
+	if err != nil {
+		return err
+	}
+	if options.Retain {
+		retained, err := retainVolumes(ctx, kubeClient, export)
+		for _, name := range retained {
+			log.Infof("Changed the reclaim policy of host persistent volume %s to %s", name, corev1.PersistentVolumeReclaimRetain)
+		}
+		if err != nil {
+			return err
+		}
+	} else {
+		for _, exported := range export.Volumes {
+			if exported.Volume.Spec.PersistentVolumeReclaimPolicy != corev1.PersistentVolumeReclaimRetain {
+				log.Warnf("Host persistent volume %s has the reclaim policy %s, its data is deleted together with the vcluster or the persistent volume claim %s/%s before it can be imported", exported.Volume.Name, exported.Volume.Spec.PersistentVolumeReclaimPolicy, exported.Namespace, exported.Claim)
+			}
+		}
+	}
+	if len(export.Volumes) > 0 {
+		log.Warnf("Stop the workloads of vcluster %s before importing the volumes, the same volume must not be attached by both host clusters at the same time", vCluster.Name)
+	}
+
+	out, err := yaml.Marshal(export)
+	if err != nil {
+		return fmt.Errorf("marshal volumes: %w", err)
+	}
+
+	if options.File == "" {
+		log.WriteString(logrus.InfoLevel, string(out))
+		return nil
+	}
+
+	err = os.WriteFile(options.File, out, 0600)
+	if err != nil {
+		return fmt.Errorf("write volumes: %w", err)
+	}
+
+	log.Donef("Exported %d volumes of vcluster %s to %s", len(export.Volumes), vCluster.Name, options.File)
+	return nil
+}
+
+// VolumesImport recreates the exported host volumes as static volumes for the claims of the restored virtual cluster,
+// so the stateful workloads reconnect to their data instead of provisioning new volumes. The volumes are reserved for
+// the host claims of the virtual cluster and created with the reclaim policy Retain.
+func VolumesImport(ctx context.Context, options *VolumesImportOptions, globalFlags *flags.GlobalFlags, vClusterName string, log log.Logger) error {
+	raw, err := os.ReadFile(options.File)
+	if err != nil {
+		return fmt.Errorf("read volumes: %w", err)
+	}
+	export := &ExportedVolumes{}
+	err = yaml.UnmarshalStrict(raw, export)
+	if err != nil {
+		return fmt.Errorf("parse volumes %s: %w", options.File, err)
+	}
+
+	vCluster, err := find.GetVCluster(ctx, globalFlags.Context, vClusterName, globalFlags.Namespace, log)
+	if err != nil {
+		return err
+	}
+
+	connectCmd := &connectHelm{
+		GlobalFlags:    globalFlags,
+		ConnectOptions: &ConnectOptions{},
+		Log:            log,
+	}
+	err = connectCmd.prepare(ctx, vCluster)
+	if err != nil {
+		return err
+	}
+
+	kubeConfig, err := connectCmd.getVClusterKubeConfig(ctx, vCluster.Name, []string{"vcluster", "volumes", "import"})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		close(connectCmd.interruptChan)
+		<-connectCmd.errorChan
+	}()
+	err = connectCmd.waitForVCluster(ctx, *kubeConfig, connectCmd.errorChan)
+	if err != nil {
+		return err
+	}
+
+	vKubeClient, err := getLocalVClusterClient(*kubeConfig, connectCmd.ConnectOptions)
+	if err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, exported := range export.Volumes {
+		volume, err := importVolume(ctx, vKubeClient, connectCmd.kubeClient, vCluster.Namespace, vCluster.Name, exported, options.DryRun)
+		if err != nil {
+			log.Errorf("Error importing volume of persistent volume claim %s/%s: %v", exported.Namespace, exported.Claim, err)
+			failed++
+		} else if volume == nil {
+			log.Infof("Skipped persistent volume claim %s/%s, it is already bound", exported.Namespace, exported.Claim)
+		} else if options.DryRun {
+			log.Infof("Would create host persistent volume %s for persistent volume claim %s/%s", volume.Name, exported.Namespace, exported.Claim)
+		} else {
+			log.Donef("Created host persistent volume %s for persistent volume claim %s/%s", volume.Name, exported.Namespace, exported.Claim)
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to import %d of %d volumes", failed, len(export.Volumes))
+	}
+
+	return nil
+}
+
+// exportVolumes returns the bound host volumes of the host claims of the virtual cluster
+func exportVolumes(ctx context.Context, hostKubeClient kubernetes.Interface, hostNamespace, vClusterName string) (*ExportedVolumes, error) {
+	claimList, err := hostKubeClient.CoreV1().PersistentVolumeClaims(hostNamespace).List(ctx, metav1.ListOptions{LabelSelector: translate.MarkerLabel + "=" + vClusterName})
+	if err != nil {
+		return nil, fmt.Errorf("list host persistent volume claims: %w", err)
+	}
+
+	export := &ExportedVolumes{Volumes: []ExportedVolume{}}
+	for _, claim := range claimList.Items {
+		if claim.Annotations[translate.NameAnnotation] == "" || claim.Spec.VolumeName == "" {
+			continue
+		}
+
+		volume, err := hostKubeClient.CoreV1().PersistentVolumes().Get(ctx, claim.Spec.VolumeName, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("get host persistent volume %s: %w", claim.Spec.VolumeName, err)
+		}
+
+		export.Volumes = append(export.Volumes, ExportedVolume{
+			Namespace: claim.Annotations[translate.NamespaceAnnotation],
+			Claim:     claim.Annotations[translate.NameAnnotation],
+			Volume: corev1.PersistentVolume{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        volume.Name,
+					Labels:      volume.Labels,
+					Annotations: volume.Annotations,
+				},
+				Spec: volume.Spec,
+			},
+		})
+	}
+
+	sort.SliceStable(export.Volumes, func(i, j int) bool {
+		return export.Volumes[i].Namespace+"/"+export.Volumes[i].Claim < export.Volumes[j].Namespace+"/"+export.Volumes[j].Claim
+	})
+	return export, nil
+}
+
+// retainVolumes changes the reclaim policy of the exported volumes to Retain and returns the names of the changed
+// volumes
+func retainVolumes(ctx context.Context, hostKubeClient kubernetes.Interface, export *ExportedVolumes) ([]string, error) {
+	retained := []string{}
+	patch := []byte(fmt.Sprintf(`{"spec":{"persistentVolumeReclaimPolicy":%q}}`, corev1.PersistentVolumeReclaimRetain))
+	for i := range export.Volumes {
+		volume := &export.Volumes[i].Volume
+		if volume.Spec.PersistentVolumeReclaimPolicy == corev1.PersistentVolumeReclaimRetain {
+			continue
+		}
+
+		_, err := hostKubeClient.CoreV1().PersistentVolumes().Patch(ctx, volume.Name, types.MergePatchType, patch, metav1.PatchOptions{})
+		if err != nil {
+			return retained, fmt.Errorf("change reclaim policy of host persistent volume %s: %w", volume.Name, err)
+		}
+
+		volume.Spec.PersistentVolumeReclaimPolicy = corev1.PersistentVolumeReclaimRetain
+		retained = append(retained, volume.Name)
+	}
+
+	return retained, nil
+}
+
+// importVolume creates the host volume of the exported volume for the host claim of the virtual claim. It returns nil
+// if the host claim is already bound.
+func importVolume(ctx context.Context, vKubeClient, hostKubeClient kubernetes.Interface, hostNamespace, vClusterName string, exported ExportedVolume, dryRun bool) (*corev1.PersistentVolume, error) {
+	vClaim, err := vKubeClient.CoreV1().PersistentVolumeClaims(exported.Namespace).Get(ctx, exported.Claim, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("get virtual persistent volume claim: %w", err)
+	} else if vClaim.Spec.VolumeName == "" {
+		return nil, fmt.Errorf("virtual persistent volume claim is not bound in the restored virtual cluster")
+	}
+
+	// the host claim is created by the syncer and waits for its volume
+	hostClaim, err := findHostClaim(ctx, hostKubeClient, hostNamespace, vClusterName, exported.Namespace, exported.Claim)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil, fmt.Errorf("host persistent volume claim doesn't exist yet, please retry after it was synced")
+		}
+
+		return nil, err
+	} else if hostClaim.Status.Phase == corev1.ClaimBound {
+		return nil, nil
+	}
+
+	volumeName, err := hostVolumeName(ctx, vKubeClient, vClaim.Spec.VolumeName)
+	if err != nil {
+		return nil, err
+	}
+	if hostClaim.Spec.VolumeName != "" && hostClaim.Spec.VolumeName != volumeName {
+		return nil, fmt.Errorf("host persistent volume claim %s waits for volume %s instead of %s", hostClaim.Name, hostClaim.Spec.VolumeName, volumeName)
+	}
+	volume := newImportedVolume(exported.Volume, volumeName, hostClaim)
+	if dryRun {
+		return volume, nil
+	}
+
+	_, err = hostKubeClient.CoreV1().PersistentVolumes().Create(ctx, volume, metav1.CreateOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("create host persistent volume %s: %w", volume.Name, err)
+	}
+
+	return volume, nil
+}
+
+// hostVolumeName returns the name of the host volume of the virtual volume. Virtual volumes synced from the host
+// cluster refer to their host volume by annotation, fake volumes have the name of the host volume.
+func hostVolumeName(ctx context.Context, vKubeClient kubernetes.Interface, vVolumeName string) (string, error) {
+	vVolume, err := vKubeClient.CoreV1().PersistentVolumes().Get(ctx, vVolumeName, metav1.GetOptions{})
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return vVolumeName, nil
+		}
+
+		return "", fmt.Errorf("get virtual persistent volume %s: %w", vVolumeName, err)
+	}
+	if vVolume.Annotations[persistentvolumes.HostClusterPersistentVolumeAnnotation] != "" {
+		return vVolume.Annotations[persistentvolumes.HostClusterPersistentVolumeAnnotation], nil
+	}
+
+	return vVolumeName, nil
+}
+
+// newImportedVolume returns the exported volume with the given name reserved for the host claim
+func newImportedVolume(exported corev1.PersistentVolume, name string, hostClaim *corev1.PersistentVolumeClaim) *corev1.PersistentVolume {
+	annotations := map[string]string{}
+	for k, v := range exported.Annotations {
+		if k != "pv.kubernetes.io/bound-by-controller" {
+			annotations[k] = v
+		}
+	}
+
+	volume := &corev1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Labels:      exported.Labels,
+			Annotations: annotations,
+		},
+		Spec: *exported.Spec.DeepCopy(),
+	}
+	volume.Spec.ClaimRef = &corev1.ObjectReference{
+		APIVersion: "v1",
+		Kind:       "PersistentVolumeClaim",
+		Namespace:  hostClaim.Namespace,
+		Name:       hostClaim.Name,
+	}
+	volume.Spec.PersistentVolumeReclaimPolicy = corev1.PersistentVolumeReclaimRetain
+	if hostClaim.Spec.StorageClassName != nil {
+		volume.Spec.StorageClassName = *hostClaim.Spec.StorageClassName
+	}
+
+	return volume
+}
@@ -0,0 +1,123 @@
+package cli
+
+import (
+	"context"
+	"testing"
+
+	"github.com/loft-sh/vcluster/pkg/controllers/resources/persistentvolumes"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestExportVolumes(t *testing.T) {
+	hostClaim := newTestHostClaim("data-x-default-x-my-vcluster", "default", "data", "pvc-123")
+	unboundClaim := newTestHostClaim("logs-x-default-x-my-vcluster", "default", "logs", "")
+	hostKubeClient := fake.NewSimpleClientset(hostClaim, unboundClaim, &corev1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{Name: "pvc-123", ResourceVersion: "42"},
+		Spec: corev1.PersistentVolumeSpec{
+			PersistentVolumeSource: corev1.PersistentVolumeSource{CSI: &corev1.CSIPersistentVolumeSource{Driver: "ebs.csi.aws.com", VolumeHandle: "vol-1"}},
+			ClaimRef:               &corev1.ObjectReference{Namespace: "vcluster-ns", Name: hostClaim.Name, UID: "uid"},
+		},
+		Status: corev1.PersistentVolumeStatus{Phase: corev1.VolumeBound},
+	})
+
+	export, err := exportVolumes(context.Background(), hostKubeClient, "vcluster-ns", "my-vcluster")
+	assert.NilError(t, err)
+	assert.Equal(t, len(export.Volumes), 1)
+	assert.Equal(t, export.Volumes[0].Namespace, "default")
+	assert.Equal(t, export.Volumes[0].Claim, "data")
+	assert.Equal(t, export.Volumes[0].Volume.Name, "pvc-123")
+	assert.Equal(t, export.Volumes[0].Volume.ResourceVersion, "")
+	assert.Equal(t, export.Volumes[0].Volume.Spec.CSI.VolumeHandle, "vol-1")
+}
+
+func TestRetainVolumes(t *testing.T) {
+	ctx := context.Background()
+	hostKubeClient := fake.NewSimpleClientset(
+		&corev1.PersistentVolume{ObjectMeta: metav1.ObjectMeta{Name: "pvc-delete"}, Spec: corev1.PersistentVolumeSpec{PersistentVolumeReclaimPolicy: corev1.PersistentVolumeReclaimDelete}},
+		&corev1.PersistentVolume{ObjectMeta: metav1.ObjectMeta{Name: "pvc-retain"}, Spec: corev1.PersistentVolumeSpec{PersistentVolumeReclaimPolicy: corev1.PersistentVolumeReclaimRetain}},
+	)
+	export := &ExportedVolumes{Volumes: []ExportedVolume{
+		{Namespace: "default", Claim: "data", Volume: corev1.PersistentVolume{ObjectMeta: metav1.ObjectMeta{Name: "pvc-delete"}, Spec: corev1.PersistentVolumeSpec{PersistentVolumeReclaimPolicy: corev1.PersistentVolumeReclaimDelete}}},
+		{Namespace: "default", Claim: "logs", Volume: corev1.PersistentVolume{ObjectMeta: metav1.ObjectMeta{Name: "pvc-retain"}, Spec: corev1.PersistentVolumeSpec{PersistentVolumeReclaimPolicy: corev1.PersistentVolumeReclaimRetain}}},
+	}}
+
+	retained, err := retainVolumes(ctx, hostKubeClient, export)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, retained, []string{"pvc-delete"})
+	assert.Equal(t, export.Volumes[0].Volume.Spec.PersistentVolumeReclaimPolicy, corev1.PersistentVolumeReclaimRetain)
+	volume, err := hostKubeClient.CoreV1().PersistentVolumes().Get(ctx, "pvc-delete", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, volume.Spec.PersistentVolumeReclaimPolicy, corev1.PersistentVolumeReclaimRetain)
+
+	// missing volumes fail the export
+	export.Volumes[0].Volume.Spec.PersistentVolumeReclaimPolicy = corev1.PersistentVolumeReclaimDelete
+	export.Volumes[0].Volume.Name = "pvc-missing"
+	_, err = retainVolumes(ctx, hostKubeClient, export)
+	assert.ErrorContains(t, err, "pvc-missing")
+}
+
+func TestImportVolume(t *testing.T) {
+	exported := ExportedVolume{
+		Namespace: "default",
+		Claim:     "data",
+		Volume: corev1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{Name: "pvc-123", Annotations: map[string]string{"pv.kubernetes.io/bound-by-controller": "yes"}},
+			Spec: corev1.PersistentVolumeSpec{
+				PersistentVolumeSource:        corev1.PersistentVolumeSource{CSI: &corev1.CSIPersistentVolumeSource{Driver: "ebs.csi.aws.com", VolumeHandle: "vol-1"}},
+				ClaimRef:                      &corev1.ObjectReference{Namespace: "old-ns", Name: "data-x-default-x-my-vcluster", UID: "uid"},
+				PersistentVolumeReclaimPolicy: corev1.PersistentVolumeReclaimDelete,
+				StorageClassName:              "gp2",
+			},
+		},
+	}
+	vClaim := newTestClaim("default", "data", "standard", corev1.ClaimBound)
+	vClaim.Spec.VolumeName = "pvc-123"
+
+	// the host claim of the restored virtual claim waits for the volume
+	hostClaim := newTestHostClaim("data-x-default-x-my-vcluster", "default", "data", "pvc-123")
+	hostClaim.Spec.StorageClassName = vClaim.Spec.StorageClassName
+	hostClaim.Status.Phase = corev1.ClaimPending
+	hostKubeClient := fake.NewSimpleClientset(hostClaim)
+
+	volume, err := importVolume(context.Background(), fake.NewSimpleClientset(vClaim), hostKubeClient, "vcluster-ns", "my-vcluster", exported, false)
+	assert.NilError(t, err)
+	assert.Equal(t, volume.Name, "pvc-123")
+
+	created, err := hostKubeClient.CoreV1().PersistentVolumes().Get(context.Background(), "pvc-123", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, created.Spec.ClaimRef, &corev1.ObjectReference{APIVersion: "v1", Kind: "PersistentVolumeClaim", Namespace: "vcluster-ns", Name: hostClaim.Name})
+	assert.Equal(t, created.Spec.PersistentVolumeReclaimPolicy, corev1.PersistentVolumeReclaimRetain)
+	assert.Equal(t, created.Spec.StorageClassName, "standard")
+	assert.Equal(t, created.Spec.CSI.VolumeHandle, "vol-1")
+	assert.DeepEqual(t, created.Annotations, map[string]string{})
+
+	// virtual volumes synced from the host refer to their host volume by annotation
+	vVolume := &corev1.PersistentVolume{ObjectMeta: metav1.ObjectMeta{
+		Name:        "pvc-123",
+		Annotations: map[string]string{persistentvolumes.HostClusterPersistentVolumeAnnotation: "pvc-456"},
+	}}
+	_, err = importVolume(context.Background(), fake.NewSimpleClientset(vClaim, vVolume), fake.NewSimpleClientset(hostClaim), "vcluster-ns", "my-vcluster", exported, true)
+	assert.ErrorContains(t, err, "waits for volume pvc-123 instead of pvc-456")
+
+	// bound host claims are skipped
+	hostClaim.Status.Phase = corev1.ClaimBound
+	volume, err = importVolume(context.Background(), fake.NewSimpleClientset(vClaim), fake.NewSimpleClientset(hostClaim), "vcluster-ns", "my-vcluster", exported, false)
+	assert.NilError(t, err)
+	assert.Assert(t, volume == nil)
+}
+
+func newTestHostClaim(name, vNamespace, vName, volumeName string) *corev1.PersistentVolumeClaim {
+	return &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   "vcluster-ns",
+			Labels:      map[string]string{translate.MarkerLabel: "my-vcluster"},
+			Annotations: map[string]string{translate.NameAnnotation: vName, translate.NamespaceAnnotation: vNamespace},
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{VolumeName: volumeName},
+	}
+}
@@ -51,6 +51,18 @@ type VirtualClusterConfig struct {
 
 	// ControlPlaneNamespace is the namespace where the vCluster control plane is running
 	ControlPlaneNamespace string `json:"controlPlaneNamespace,omitempty"`
+
+	// reloadHandlers are called when reloadable paths of the config change at runtime
+	reloadHandlers *reloadHandlers
+
+	// pausedSyncers are the resources whose syncers are paused at runtime
+	pausedSyncers *pausedSyncers
+
+	// shards is the shard assignment of this replica if sharding is enabled
+	shards *shards
+
+	// catchUp tracks the high priority syncers after a restart
+	catchUp *catchUp
 }
 
 func (v VirtualClusterConfig) EmbeddedDatabase() bool {
@@ -174,7 +186,7 @@ func (v VirtualClusterConfig) LegacyOptions() (*legacyconfig.LegacyVirtualCluste
 		RetryPeriod:                 v.ControlPlane.StatefulSet.HighAvailability.RetryPeriod,
 		Plugins:                     legacyPlugins,
 		DefaultImageRegistry:        v.ControlPlane.Advanced.DefaultImageRegistry,
-		EnforcePodSecurityStandard:  v.Policies.PodSecurityStandard,
+		EnforcePodSecurityStandard:  v.Policies.PodSecurityStandard.Level,
 		SyncLabels:                  v.Experimental.SyncSettings.SyncLabels,
 		MountPhysicalHostPaths:      false,
 		HostMetricsBindAddress:      "0",
@@ -0,0 +1,137 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/loft-sh/vcluster/config"
+)
+
+type LintSeverity string
+
+const (
+	// LintSeverityError means the virtual cluster will not start or not work as configured
+	LintSeverityError LintSeverity = "error"
+
+	// LintSeverityWarning means the config is valid, but risky or likely not intended
+	LintSeverityWarning LintSeverity = "warning"
+)
+
+// LintResult is a single finding of Lint
+type LintResult struct {
+	Severity LintSeverity `json:"severity"`
+	Path     string       `json:"path,omitempty"`
+	Message  string       `json:"message"`
+}
+
+func (l LintResult) String() string {
+	if l.Path == "" {
+		return l.Message
+	}
+
+	return l.Path + ": " + l.Message
+}
+
+// Lint checks the given vcluster.yaml for errors and risky settings. The config is not modified.
+func Lint(c *config.Config) []LintResult {
+	results := []LintResult{}
+	addError := func(path, format string, args ...interface{}) {
+		results = append(results, LintResult{Severity: LintSeverityError, Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+	addWarning := func(path, format string, args ...interface{}) {
+		results = append(results, LintResult{Severity: LintSeverityWarning, Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	// the validation sets defaults, so we validate a copy
+	copied, err := copyConfig(c)
+	if err != nil {
+		addError("", "copy config: %v", err)
+		return results
+	}
+	err = ValidateConfigAndSetDefaults(&VirtualClusterConfig{Config: *copied, Name: "vcluster"})
+	if err != nil {
+		addError("", "%v", err)
+	}
+
+	// backing store
+	replicas := c.ControlPlane.StatefulSet.HighAvailability.Replicas
+	storeType := c.BackingStoreType()
+	if replicas > 1 && storeType == config.StoreTypeEmbeddedDatabase {
+		addError("controlPlane.statefulSet.highAvailability.replicas", "%d replicas require etcd or an external database as backing store, the embedded database only supports a single replica", replicas)
+	}
+	if replicas > 1 && replicas%2 == 0 && storeType == config.StoreTypeEmbeddedEtcd {
+		addWarning("controlPlane.statefulSet.highAvailability.replicas", "embedded etcd with %d replicas tolerates as many failures as %d replicas, use an odd number of replicas", replicas, replicas-1)
+	}
+	etcdReplicas := c.ControlPlane.BackingStore.Etcd.Deploy.StatefulSet.HighAvailability.Replicas
+	if storeType == config.StoreTypeExternalEtcd && etcdReplicas > 1 && etcdReplicas%2 == 0 {
+		addWarning("controlPlane.backingStore.etcd.deploy.statefulSet.highAvailability.replicas", "etcd with %d replicas tolerates as many failures as %d replicas, use an odd number of replicas", etcdReplicas, etcdReplicas-1)
+	}
+	persistence := c.ControlPlane.StatefulSet.Persistence
+	if persistence.VolumeClaim.Enabled == "false" && len(persistence.VolumeClaimTemplates) == 0 && (storeType == config.StoreTypeEmbeddedDatabase || storeType == config.StoreTypeEmbeddedEtcd) {
+		addWarning("controlPlane.statefulSet.persistence.volumeClaim.enabled", "the %s backing store is not persisted, all data of the virtual cluster is lost when the control plane pod restarts", storeType)
+	}
+
+	// host access
+	if c.Sync.FromHost.Nodes.Enabled && c.Sync.FromHost.Nodes.SyncBackChanges && c.Sync.FromHost.Nodes.Selector.All {
+		addWarning("sync.fromHost.nodes.syncBackChanges", "users of the virtual cluster can change labels and taints of all host nodes")
+	}
+	if c.Policies.HostNodeAccess.AllowDebug {
+		addWarning("policies.hostNodeAccess.allowDebug", "users of the virtual cluster can create pods with access to the host node filesystem and namespaces")
+	}
+	if c.Policies.HostNodeAccess.AllowProxy {
+		addWarning("policies.hostNodeAccess.allowProxy", "users of the virtual cluster can access the kubelet of the host nodes")
+	}
+
+	// scheduling
+	scheduling := c.Policies.Scheduling
+	if c.ControlPlane.Advanced.VirtualScheduler.Enabled && (len(scheduling.NodeSelector) > 0 || len(scheduling.NodeAffinity) > 0) && len(c.Sync.FromHost.Nodes.Selector.Labels) == 0 {
+		addWarning("policies.scheduling", "the virtual scheduler can assign pods to nodes outside of the node pool, set sync.fromHost.nodes.selector.labels to only sync the nodes of the pool")
+	}
+
+	// storage classes, auto is resolved by the validation
+	storageClasses := copied.Sync.FromHost.StorageClasses
+	if (len(storageClasses.Mappings) > 0 || storageClasses.Default != "") && storageClasses.Enabled != "true" {
+		addWarning("sync.fromHost.storageClasses", "mappings and default only apply if storage classes are synced from the host cluster")
+	}
+
+	// network isolation
+	if c.Policies.NetworkIsolation.Enabled && len(c.Policies.NetworkIsolation.Egress.FQDNs) > 0 && c.ControlPlane.CoreDNS.Embedded {
+		addWarning("policies.networkIsolation.egress.fqdns", "the embedded CoreDNS resolves the domains from within the control plane, so the network policy provider cannot learn the addresses of the allowed domains")
+	}
+
+	// generic sync
+	for i, export := range c.Experimental.GenericSync.Exports {
+		if export != nil && strings.HasPrefix(export.APIVersion, "keda.sh/") {
+			addWarning(fmt.Sprintf("experimental.genericSync.export[%d]", i), "KEDA in the host cluster cannot scale the workloads of the virtual cluster, install KEDA inside the virtual cluster instead")
+		}
+	}
+
+	// features
+	if c.IsProFeatureEnabled() {
+		addWarning("", "pro features are enabled, which require the virtual cluster to be connected to vCluster Platform")
+	}
+	if c.Experimental.MultiNamespaceMode.Enabled {
+		addWarning("experimental.multiNamespaceMode.enabled", "multi namespace mode is experimental and cannot be disabled after the virtual cluster was created")
+		if c.Policies.NetworkIsolation.Enabled {
+			addWarning("policies.networkIsolation.enabled", "network isolation only applies to the namespace of the virtual cluster, workloads synced to other host namespaces are not isolated")
+		}
+	}
+
+	return results
+}
+
+func copyConfig(c *config.Config) (*config.Config, error) {
+	raw, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+
+	copied := &config.Config{}
+	err = json.Unmarshal(raw, copied)
+	if err != nil {
+		return nil, err
+	}
+
+	return copied, nil
+}
@@ -0,0 +1,185 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/loft-sh/vcluster/config"
+	"gotest.tools/v3/assert"
+)
+
+func TestLint(t *testing.T) {
+	type expectedResult struct {
+		Severity LintSeverity
+		Path     string
+		Contains string
+	}
+
+	testCases := []struct {
+		name     string
+		modify   func(c *config.Config)
+		expected []expectedResult
+	}{
+		{
+			name:   "defaults",
+			modify: func(_ *config.Config) {},
+		},
+		{
+			name: "multiple replicas with embedded database",
+			modify: func(c *config.Config) {
+				c.ControlPlane.StatefulSet.HighAvailability.Replicas = 3
+			},
+			expected: []expectedResult{
+				{Severity: LintSeverityError, Path: "controlPlane.statefulSet.highAvailability.replicas", Contains: "embedded database only supports a single replica"},
+			},
+		},
+		{
+			name: "even replicas with embedded etcd",
+			modify: func(c *config.Config) {
+				c.ControlPlane.BackingStore.Etcd.Embedded.Enabled = true
+				c.ControlPlane.StatefulSet.HighAvailability.Replicas = 4
+			},
+			expected: []expectedResult{
+				{Severity: LintSeverityWarning, Path: "controlPlane.statefulSet.highAvailability.replicas", Contains: "use an odd number of replicas"},
+				{Severity: LintSeverityWarning, Contains: "pro features are enabled"},
+			},
+		},
+		{
+			name: "even replicas with deployed etcd",
+			modify: func(c *config.Config) {
+				c.ControlPlane.BackingStore.Etcd.Deploy.Enabled = true
+				c.ControlPlane.BackingStore.Etcd.Deploy.StatefulSet.HighAvailability.Replicas = 2
+			},
+			expected: []expectedResult{
+				{Severity: LintSeverityWarning, Path: "controlPlane.backingStore.etcd.deploy.statefulSet.highAvailability.replicas", Contains: "use an odd number of replicas"},
+			},
+		},
+		{
+			name: "embedded database without persistence",
+			modify: func(c *config.Config) {
+				c.ControlPlane.StatefulSet.Persistence.VolumeClaim.Enabled = "false"
+			},
+			expected: []expectedResult{
+				{Severity: LintSeverityWarning, Path: "controlPlane.statefulSet.persistence.volumeClaim.enabled", Contains: "data of the virtual cluster is lost"},
+			},
+		},
+		{
+			name: "node changes synced back to all host nodes",
+			modify: func(c *config.Config) {
+				c.Sync.FromHost.Nodes.Enabled = true
+				c.Sync.FromHost.Nodes.SyncBackChanges = true
+				c.Sync.FromHost.Nodes.Selector.All = true
+			},
+			expected: []expectedResult{
+				{Severity: LintSeverityWarning, Path: "sync.fromHost.nodes.syncBackChanges"},
+			},
+		},
+		{
+			name: "host node access",
+			modify: func(c *config.Config) {
+				c.Policies.HostNodeAccess.AllowDebug = true
+				c.Policies.HostNodeAccess.AllowProxy = true
+			},
+			expected: []expectedResult{
+				{Severity: LintSeverityWarning, Path: "policies.hostNodeAccess.allowDebug"},
+				{Severity: LintSeverityWarning, Path: "policies.hostNodeAccess.allowProxy"},
+			},
+		},
+		{
+			name: "node pool with virtual scheduler and all nodes synced",
+			modify: func(c *config.Config) {
+				c.ControlPlane.Advanced.VirtualScheduler.Enabled = true
+				c.Sync.FromHost.Nodes.Enabled = true
+				c.Policies.Scheduling.NodeSelector = map[string]string{"pool": "team-a"}
+			},
+			expected: []expectedResult{
+				{Severity: LintSeverityWarning, Path: "policies.scheduling", Contains: "sync.fromHost.nodes.selector.labels"},
+			},
+		},
+		{
+			name: "node pool with virtual scheduler and pool nodes synced",
+			modify: func(c *config.Config) {
+				c.ControlPlane.Advanced.VirtualScheduler.Enabled = true
+				c.Sync.FromHost.Nodes.Enabled = true
+				c.Sync.FromHost.Nodes.Selector.Labels = map[string]string{"pool": "team-a"}
+				c.Policies.Scheduling.NodeSelector = map[string]string{"pool": "team-a"}
+			},
+		},
+		{
+			name: "storage class mappings without synced storage classes",
+			modify: func(c *config.Config) {
+				c.Sync.FromHost.StorageClasses.Enabled = "false"
+				c.Sync.FromHost.StorageClasses.Mappings = []config.StorageClassMapping{{From: "gp3", To: "standard"}}
+			},
+			expected: []expectedResult{
+				{Severity: LintSeverityWarning, Path: "sync.fromHost.storageClasses"},
+			},
+		},
+		{
+			name: "storage class mappings with synced storage classes",
+			modify: func(c *config.Config) {
+				c.Sync.FromHost.StorageClasses.Enabled = "true"
+				c.Sync.FromHost.StorageClasses.Mappings = []config.StorageClassMapping{{From: "gp3", To: "standard"}}
+			},
+		},
+		{
+			name: "fqdn egress rules with embedded coredns",
+			modify: func(c *config.Config) {
+				c.Policies.NetworkIsolation.Enabled = true
+				c.Policies.NetworkIsolation.Egress.FQDNs = []string{"api.github.com"}
+				c.ControlPlane.CoreDNS.Embedded = true
+			},
+			expected: []expectedResult{
+				{Severity: LintSeverityWarning, Path: "policies.networkIsolation.egress.fqdns", Contains: "embedded CoreDNS"},
+				{Severity: LintSeverityWarning, Contains: "pro features are enabled"},
+			},
+		},
+		{
+			name: "network isolation with network policy",
+			modify: func(c *config.Config) {
+				c.Policies.NetworkIsolation.Enabled = true
+				c.Policies.NetworkPolicy.Enabled = true
+			},
+			expected: []expectedResult{
+				{Severity: LintSeverityError, Contains: "policies.networkIsolation and policies.networkPolicy cannot be enabled at the same time"},
+			},
+		},
+		{
+			name: "keda resources exported to the host",
+			modify: func(c *config.Config) {
+				c.Experimental.GenericSync.Exports = []*config.Export{{SyncBase: config.SyncBase{TypeInformation: config.TypeInformation{APIVersion: "keda.sh/v1alpha1", Kind: "ScaledObject"}}}}
+			},
+			expected: []expectedResult{
+				{Severity: LintSeverityWarning, Path: "experimental.genericSync.export[0]", Contains: "install KEDA inside the virtual cluster"},
+			},
+		},
+		{
+			name: "multi namespace mode with network isolation",
+			modify: func(c *config.Config) {
+				c.Experimental.MultiNamespaceMode.Enabled = true
+				c.Policies.NetworkIsolation.Enabled = true
+			},
+			expected: []expectedResult{
+				{Severity: LintSeverityWarning, Path: "experimental.multiNamespaceMode.enabled"},
+				{Severity: LintSeverityWarning, Path: "policies.networkIsolation.enabled", Contains: "workloads synced to other host namespaces are not isolated"},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			c, err := config.NewDefaultConfig()
+			assert.NilError(t, err)
+			testCase.modify(c)
+
+			results := Lint(c)
+			assert.Equal(t, len(results), len(testCase.expected), "results: %v", results)
+			for i, expected := range testCase.expected {
+				assert.Equal(t, results[i].Severity, expected.Severity, results[i].String())
+				assert.Equal(t, results[i].Path, expected.Path, results[i].String())
+				assert.Assert(t, results[i].Message != "")
+				assert.Assert(t, strings.Contains(results[i].Message, expected.Contains), results[i].String())
+			}
+		})
+	}
+}
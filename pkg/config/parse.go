@@ -41,6 +41,10 @@ func ParseConfig(path, name string, setValues []string) (*VirtualClusterConfig,
 		Config:              *rawConfig,
 		Name:                name,
 		ControlPlaneService: name,
+		reloadHandlers:      &reloadHandlers{},
+		pausedSyncers:       &pausedSyncers{},
+		shards:              &shards{index: -1},
+		catchUp:             &catchUp{},
 	}
 	if name == "" {
 		return nil, fmt.Errorf("environment variable VCLUSTER_NAME is not defined")
@@ -0,0 +1,94 @@
+package config
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+type pausedSyncers struct {
+	m         sync.RWMutex
+	resources []string
+}
+
+// IsSyncerPaused returns true if the syncer with the given name was paused through the PausedSyncersAnnotation
+func (v *VirtualClusterConfig) IsSyncerPaused(syncerName string) bool {
+	if v.pausedSyncers == nil {
+		return false
+	}
+
+	v.pausedSyncers.m.RLock()
+	defer v.pausedSyncers.m.RUnlock()
+	for _, resource := range v.pausedSyncers.resources {
+		if SyncerMatchesResource(syncerName, resource) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// PausedSyncers returns the resources whose syncers are currently paused
+func (v *VirtualClusterConfig) PausedSyncers() []string {
+	if v.pausedSyncers == nil {
+		return nil
+	}
+
+	v.pausedSyncers.m.RLock()
+	defer v.pausedSyncers.m.RUnlock()
+	return append([]string{}, v.pausedSyncers.resources...)
+}
+
+// SetPausedSyncers replaces the paused resources and returns true if they changed
+func (v *VirtualClusterConfig) SetPausedSyncers(resources []string) bool {
+	if v.pausedSyncers == nil {
+		return false
+	}
+
+	v.pausedSyncers.m.Lock()
+	defer v.pausedSyncers.m.Unlock()
+	if strings.Join(v.pausedSyncers.resources, ",") == strings.Join(resources, ",") {
+		return false
+	}
+
+	v.pausedSyncers.resources = append([]string{}, resources...)
+	return true
+}
+
+// ParsePausedSyncers returns the sorted and deduplicated resources of the PausedSyncersAnnotation value
+func ParsePausedSyncers(value string) []string {
+	resources := []string{}
+	for _, resource := range strings.Split(value, ",") {
+		resource = strings.ToLower(strings.TrimSpace(resource))
+		if resource == "" {
+			continue
+		}
+
+		found := false
+		for _, existing := range resources {
+			if existing == resource {
+				found = true
+				break
+			}
+		}
+		if !found {
+			resources = append(resources, resource)
+		}
+	}
+
+	sort.Strings(resources)
+	return resources
+}
+
+// SyncerMatchesResource returns true if the given resource refers to the syncer with the given name. Syncers are
+// named after the singular of their resource, so pods matches the pod syncer and persistentvolumeclaims matches the
+// persistent-volume-claim syncer.
+func SyncerMatchesResource(syncerName, resource string) bool {
+	name := normalizeSyncerName(syncerName)
+	resource = normalizeSyncerName(resource)
+	return name == resource || name+"s" == resource || name+"es" == resource
+}
+
+func normalizeSyncerName(name string) string {
+	return strings.ReplaceAll(strings.ToLower(strings.TrimSpace(name)), "-", "")
+}
@@ -0,0 +1,247 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/yaml"
+)
+
+// PolicyOverrideAll can be used instead of a rule name in the override tokens to exempt all rules
+const PolicyOverrideAll = "*"
+
+// Policy holds organization wide constraints on the vcluster.yaml, e.g. that the backing store must be embedded etcd or
+// that telemetry must stay enabled. The CLI validates the merged values against the policy before deploying.
+type Policy struct {
+	// Rules are the constraints the values have to satisfy
+	Rules []PolicyRule `json:"rules,omitempty"`
+
+	// OverrideTokens map the sha256 checksum of a token, e.g. sha256:<hex>, to the names of the rules the token exempts.
+	// Only the checksum is stored, so the policy can be shared without leaking the tokens.
+	OverrideTokens map[string][]string `json:"overrideTokens,omitempty"`
+}
+
+// PolicyRule constrains the value at a single path of the vcluster.yaml. All set constraints have to be satisfied.
+type PolicyRule struct {
+	// Name identifies the rule in violations and override tokens
+	Name string `json:"name"`
+
+	// Path is the dotted path of the value, e.g. controlPlane.backingStore.etcd.embedded.enabled
+	Path string `json:"path"`
+
+	// Message is shown in addition to the violation, e.g. to explain the rule or how to request an exception
+	Message string `json:"message,omitempty"`
+
+	// Forbidden means the value must not be set to a non-empty value
+	Forbidden bool `json:"forbidden,omitempty"`
+
+	// Equals is the only allowed value
+	Equals interface{} `json:"equals,omitempty"`
+
+	// OneOf are the allowed values
+	OneOf []interface{} `json:"oneOf,omitempty"`
+
+	// NotOneOf are the forbidden values
+	NotOneOf []interface{} `json:"notOneOf,omitempty"`
+
+	// Min is the minimum of a number or quantity such as 10Gi
+	Min *resource.Quantity `json:"min,omitempty"`
+
+	// Max is the maximum of a number or quantity such as 10Gi
+	Max *resource.Quantity `json:"max,omitempty"`
+}
+
+// PolicyViolation is a single rule the values do not satisfy
+type PolicyViolation struct {
+	Rule    string `json:"rule"`
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+func (p PolicyViolation) String() string {
+	return fmt.Sprintf("%s %s (rule %s)", p.Path, p.Message, p.Rule)
+}
+
+// ParsePolicy parses and validates the given policy
+func ParsePolicy(raw []byte) (*Policy, error) {
+	policy := &Policy{}
+	err := yaml.UnmarshalStrict(raw, policy)
+	if err != nil {
+		return nil, fmt.Errorf("parse policy: %w", err)
+	}
+
+	names := map[string]bool{}
+	for i, rule := range policy.Rules {
+		if rule.Name == "" {
+			return nil, fmt.Errorf("rules[%d].name is required", i)
+		} else if names[rule.Name] {
+			return nil, fmt.Errorf("rule %s is defined more than once", rule.Name)
+		} else if rule.Path == "" {
+			return nil, fmt.Errorf("rule %s: path is required", rule.Name)
+		} else if !rule.Forbidden && rule.Equals == nil && len(rule.OneOf) == 0 && len(rule.NotOneOf) == 0 && rule.Min == nil && rule.Max == nil {
+			return nil, fmt.Errorf("rule %s: at least one of forbidden, equals, oneOf, notOneOf, min or max is required", rule.Name)
+		}
+
+		names[rule.Name] = true
+	}
+	for checksum, rules := range policy.OverrideTokens {
+		if !strings.HasPrefix(checksum, "sha256:") {
+			return nil, fmt.Errorf("override token %s: expected the sha256 checksum of the token, e.g. sha256:<hex>", checksum)
+		}
+		for _, rule := range rules {
+			if rule != PolicyOverrideAll && !names[rule] {
+				return nil, fmt.Errorf("override token %s: rule %s does not exist", checksum, rule)
+			}
+		}
+	}
+
+	return policy, nil
+}
+
+// PolicyOverrideTokenChecksum returns the checksum of the token as referenced in the override tokens of a policy
+func PolicyOverrideTokenChecksum(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// Evaluate checks the given values, which need to include the defaults, against the policy. It returns the violations
+// as well as the violations that are exempted by one of the given override tokens.
+func (p *Policy) Evaluate(values map[string]interface{}, overrideTokens []string) ([]PolicyViolation, []PolicyViolation) {
+	exempted := map[string]bool{}
+	for _, token := range overrideTokens {
+		for _, rule := range p.OverrideTokens[PolicyOverrideTokenChecksum(token)] {
+			exempted[rule] = true
+		}
+	}
+
+	violations := []PolicyViolation{}
+	overridden := []PolicyViolation{}
+	for _, rule := range p.Rules {
+		message := rule.evaluate(lookupPath(values, rule.Path))
+		if message == "" {
+			continue
+		}
+		if rule.Message != "" {
+			message += ": " + rule.Message
+		}
+
+		violation := PolicyViolation{Rule: rule.Name, Path: rule.Path, Message: message}
+		if exempted[rule.Name] || exempted[PolicyOverrideAll] {
+			overridden = append(overridden, violation)
+		} else {
+			violations = append(violations, violation)
+		}
+	}
+
+	return violations, overridden
+}
+
+// evaluate returns why the value does not satisfy the rule or an empty string
+func (r PolicyRule) evaluate(value interface{}) string {
+	if r.Forbidden && !isEmptyValue(value) {
+		return "must not be set"
+	}
+	if r.Equals != nil && !equalValues(value, r.Equals) {
+		return fmt.Sprintf("must be %s, but is %s", formatValue(r.Equals), formatValue(value))
+	}
+	if len(r.OneOf) > 0 && !slices.ContainsFunc(r.OneOf, func(allowed interface{}) bool { return equalValues(value, allowed) }) {
+		return fmt.Sprintf("must be one of %s, but is %s", formatValue(r.OneOf), formatValue(value))
+	}
+	if slices.ContainsFunc(r.NotOneOf, func(forbidden interface{}) bool { return equalValues(value, forbidden) }) {
+		return fmt.Sprintf("must not be %s", formatValue(value))
+	}
+	if r.Min != nil || r.Max != nil {
+		quantity, err := toQuantity(value)
+		if err != nil {
+			return fmt.Sprintf("must be a number or quantity, but is %s", formatValue(value))
+		} else if r.Min != nil && quantity.Cmp(*r.Min) < 0 {
+			return fmt.Sprintf("must be at least %s, but is %s", r.Min.String(), formatValue(value))
+		} else if r.Max != nil && quantity.Cmp(*r.Max) > 0 {
+			return fmt.Sprintf("must be at most %s, but is %s", r.Max.String(), formatValue(value))
+		}
+	}
+
+	return ""
+}
+
+func lookupPath(values map[string]interface{}, path string) interface{} {
+	var current interface{} = values
+	for _, key := range strings.Split(path, ".") {
+		currentMap, ok := current.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+
+		current = currentMap[key]
+	}
+
+	return current
+}
+
+// equalValues compares the values as json, so 1 and 1.0 from different yaml files are equal
+func equalValues(a, b interface{}) bool {
+	normalizedA, errA := normalizeValue(a)
+	normalizedB, errB := normalizeValue(b)
+	if errA != nil || errB != nil {
+		return reflect.DeepEqual(a, b)
+	}
+
+	return reflect.DeepEqual(normalizedA, normalizedB)
+}
+
+func normalizeValue(value interface{}) (interface{}, error) {
+	raw, err := yaml.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var normalized interface{}
+	err = yaml.Unmarshal(raw, &normalized)
+	return normalized, err
+}
+
+func isEmptyValue(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	}
+
+	return false
+}
+
+func toQuantity(value interface{}) (resource.Quantity, error) {
+	switch v := value.(type) {
+	case string:
+		return resource.ParseQuantity(v)
+	case float64, int, int64:
+		return resource.ParseQuantity(fmt.Sprint(v))
+	}
+
+	return resource.Quantity{}, fmt.Errorf("unsupported type %T", value)
+}
+
+func formatValue(value interface{}) string {
+	if value == nil {
+		return "not set"
+	}
+
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+
+	return string(raw)
+}
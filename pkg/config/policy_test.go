@@ -0,0 +1,113 @@
+package config
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+	"sigs.k8s.io/yaml"
+)
+
+const testPolicy = `
+rules:
+- name: embedded-etcd
+  path: controlPlane.backingStore.etcd.embedded.enabled
+  equals: true
+  message: contact the platform team for exceptions
+- name: telemetry
+  path: telemetry.enabled
+  equals: true
+- name: distro
+  path: controlPlane.distro
+  notOneOf: [k3s]
+- name: replicas
+  path: controlPlane.statefulSet.highAvailability.replicas
+  min: 1
+  max: 3
+- name: storage
+  path: controlPlane.statefulSet.persistence.volumeClaim.size
+  max: 10Gi
+- name: plugins
+  path: plugins
+  forbidden: true
+overrideTokens:
+  sha256:9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08: [telemetry]
+`
+
+func TestPolicy(t *testing.T) {
+	policy, err := ParsePolicy([]byte(testPolicy))
+	assert.NilError(t, err)
+
+	parseValues := func(raw string) map[string]interface{} {
+		values := map[string]interface{}{}
+		assert.NilError(t, yaml.Unmarshal([]byte(raw), &values))
+		return values
+	}
+	compliant := `
+controlPlane:
+  backingStore:
+    etcd:
+      embedded:
+        enabled: true
+  distro: k8s
+  statefulSet:
+    highAvailability:
+      replicas: 3
+    persistence:
+      volumeClaim:
+        size: 5Gi
+telemetry:
+  enabled: true
+plugins: {}
+`
+	violations, overridden := policy.Evaluate(parseValues(compliant), nil)
+	assert.Equal(t, len(violations), 0)
+	assert.Equal(t, len(overridden), 0)
+
+	violating := `
+controlPlane:
+  distro: k3s
+  statefulSet:
+    highAvailability:
+      replicas: 5
+    persistence:
+      volumeClaim:
+        size: 20Gi
+telemetry:
+  enabled: false
+plugins:
+  my-plugin:
+    image: my-plugin
+`
+	violations, overridden = policy.Evaluate(parseValues(violating), nil)
+	assert.Equal(t, len(overridden), 0)
+	assert.DeepEqual(t, violations, []PolicyViolation{
+		{Rule: "embedded-etcd", Path: "controlPlane.backingStore.etcd.embedded.enabled", Message: "must be true, but is not set: contact the platform team for exceptions"},
+		{Rule: "telemetry", Path: "telemetry.enabled", Message: "must be true, but is false"},
+		{Rule: "distro", Path: "controlPlane.distro", Message: `must not be "k3s"`},
+		{Rule: "replicas", Path: "controlPlane.statefulSet.highAvailability.replicas", Message: "must be at most 3, but is 5"},
+		{Rule: "storage", Path: "controlPlane.statefulSet.persistence.volumeClaim.size", Message: `must be at most 10Gi, but is "20Gi"`},
+		{Rule: "plugins", Path: "plugins", Message: "must not be set"},
+	})
+
+	// the token "test" only exempts the telemetry rule
+	violations, overridden = policy.Evaluate(parseValues(violating), []string{"test", "other"})
+	assert.Equal(t, len(violations), 5)
+	assert.DeepEqual(t, overridden, []PolicyViolation{{Rule: "telemetry", Path: "telemetry.enabled", Message: "must be true, but is false"}})
+	assert.Equal(t, PolicyOverrideTokenChecksum("test"), "sha256:9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08")
+}
+
+func TestParsePolicy(t *testing.T) {
+	testCases := map[string]string{
+		"rules:\n- path: telemetry.enabled\n  equals: true\n":                                        "rules[0].name is required",
+		"rules:\n- name: telemetry\n  equals: true\n":                                                "rule telemetry: path is required",
+		"rules:\n- name: telemetry\n  path: telemetry.enabled\n":                                     "at least one of",
+		"rules:\n- name: telemetry\n  path: telemetry.enabled\n  unknown: true\n":                    "unknown field",
+		"rules: []\noverrideTokens:\n  abc: [telemetry]\n":                                           "expected the sha256 checksum",
+		"rules: []\noverrideTokens:\n  sha256:abc: [telemetry]\n":                                    "rule telemetry does not exist",
+		"rules:\n- name: a\n  path: a\n  forbidden: true\n- name: a\n  path: b\n  forbidden: true\n": "defined more than once",
+	}
+	for rawPolicy, expectedErr := range testCases {
+		_, err := ParsePolicy([]byte(rawPolicy))
+		assert.ErrorContains(t, err, expectedErr, rawPolicy)
+	}
+}
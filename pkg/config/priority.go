@@ -0,0 +1,116 @@
+package config
+
+import (
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+const (
+	// SyncPriorityHigh syncers are synced first after a restart
+	SyncPriorityHigh = "high"
+
+	// SyncPriorityLow syncers only create missing host objects after a restart until the high priority syncers caught up
+	SyncPriorityLow = "low"
+)
+
+// CatchUpQueue is the work queue of a high priority syncer
+type CatchUpQueue interface {
+	// Len returns the number of queued requests
+	Len() int
+
+	// Started returns true once the syncer started to work off its queue, which happens after its caches were synced
+	Started() bool
+}
+
+type catchUp struct {
+	m sync.Mutex
+
+	// expected is the number of high priority syncers, queues are added once the syncers were started
+	expected int
+	queues   []CatchUpQueue
+
+	started time.Time
+	done    bool
+}
+
+// SyncPriority returns the catch up priority of the syncer with the given name, or an empty string if the syncer is
+// not prioritized
+func (v *VirtualClusterConfig) SyncPriority(syncerName string) string {
+	if v.catchUp == nil || !v.Experimental.SyncSettings.CatchUp.Enabled {
+		return ""
+	}
+
+	for _, resource := range v.Experimental.SyncSettings.CatchUp.HighPriority {
+		if SyncerMatchesResource(syncerName, resource) {
+			return SyncPriorityHigh
+		}
+	}
+	for _, resource := range v.Experimental.SyncSettings.CatchUp.LowPriority {
+		if SyncerMatchesResource(syncerName, resource) {
+			return SyncPriorityLow
+		}
+	}
+
+	return ""
+}
+
+// ExpectCatchUpQueue registers a high priority syncer whose queue is added with AddCatchUpQueue once it was started
+func (v *VirtualClusterConfig) ExpectCatchUpQueue() {
+	if v.catchUp == nil {
+		return
+	}
+
+	v.catchUp.m.Lock()
+	defer v.catchUp.m.Unlock()
+	if v.catchUp.started.IsZero() {
+		v.catchUp.started = time.Now()
+	}
+	v.catchUp.expected++
+}
+
+// AddCatchUpQueue adds the queue of a started high priority syncer
+func (v *VirtualClusterConfig) AddCatchUpQueue(queue CatchUpQueue) {
+	if v.catchUp == nil {
+		return
+	}
+
+	v.catchUp.m.Lock()
+	defer v.catchUp.m.Unlock()
+	v.catchUp.queues = append(v.catchUp.queues, queue)
+}
+
+// CatchingUp returns true while the high priority syncers still work off the objects that changed while vCluster was
+// not running. Once all of their queues were empty at the same time or the timeout was reached, catching up is done.
+func (v *VirtualClusterConfig) CatchingUp() bool {
+	if v.catchUp == nil || !v.Experimental.SyncSettings.CatchUp.Enabled {
+		return false
+	}
+
+	v.catchUp.m.Lock()
+	defer v.catchUp.m.Unlock()
+	if v.catchUp.done || v.catchUp.expected == 0 {
+		return false
+	}
+
+	timeout := time.Duration(v.Experimental.SyncSettings.CatchUp.Timeout) * time.Second
+	if time.Since(v.catchUp.started) > timeout {
+		klog.Infof("High priority syncers did not catch up within %s, syncing all resources", timeout)
+		v.catchUp.done = true
+		return false
+	}
+
+	if len(v.catchUp.queues) < v.catchUp.expected {
+		return true
+	}
+	for _, queue := range v.catchUp.queues {
+		if !queue.Started() || queue.Len() > 0 {
+			return true
+		}
+	}
+
+	klog.Infof("High priority syncers caught up after %s, syncing all resources", time.Since(v.catchUp.started).Round(time.Millisecond))
+	v.catchUp.done = true
+	return false
+}
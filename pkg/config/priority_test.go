@@ -0,0 +1,64 @@
+package config
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+type fakeCatchUpQueue struct {
+	len     int
+	started bool
+}
+
+func (q *fakeCatchUpQueue) Len() int {
+	return q.len
+}
+
+func (q *fakeCatchUpQueue) Started() bool {
+	return q.started
+}
+
+func TestCatchingUp(t *testing.T) {
+	vConfig := &VirtualClusterConfig{catchUp: &catchUp{}}
+	vConfig.Experimental.SyncSettings.CatchUp.Enabled = true
+	vConfig.Experimental.SyncSettings.CatchUp.HighPriority = []string{"pods", "endpoints"}
+	vConfig.Experimental.SyncSettings.CatchUp.LowPriority = []string{"configmaps"}
+	vConfig.Experimental.SyncSettings.CatchUp.Timeout = 300
+	assert.Equal(t, vConfig.SyncPriority("pod"), SyncPriorityHigh)
+	assert.Equal(t, vConfig.SyncPriority("endpoints"), SyncPriorityHigh)
+	assert.Equal(t, vConfig.SyncPriority("configmap"), SyncPriorityLow)
+	assert.Equal(t, vConfig.SyncPriority("secret"), "")
+
+	// without high priority syncers there is nothing to catch up
+	assert.Assert(t, !vConfig.CatchingUp())
+
+	// catching up until all high priority syncers were started and worked off their queues
+	vConfig.ExpectCatchUpQueue()
+	vConfig.ExpectCatchUpQueue()
+	assert.Assert(t, vConfig.CatchingUp())
+
+	pods := &fakeCatchUpQueue{len: 10, started: true}
+	endpoints := &fakeCatchUpQueue{}
+	vConfig.AddCatchUpQueue(pods)
+	vConfig.AddCatchUpQueue(endpoints)
+	assert.Assert(t, vConfig.CatchingUp())
+
+	endpoints.started = true
+	assert.Assert(t, vConfig.CatchingUp())
+
+	pods.len = 0
+	assert.Assert(t, !vConfig.CatchingUp())
+
+	// new requests after catching up don't hold back the low priority syncers again
+	pods.len = 5
+	assert.Assert(t, !vConfig.CatchingUp())
+}
+
+func TestCatchingUpTimeout(t *testing.T) {
+	vConfig := &VirtualClusterConfig{catchUp: &catchUp{}}
+	vConfig.Experimental.SyncSettings.CatchUp.Enabled = true
+	vConfig.Experimental.SyncSettings.CatchUp.Timeout = 0
+	vConfig.ExpectCatchUpQueue()
+	assert.Assert(t, !vConfig.CatchingUp())
+}
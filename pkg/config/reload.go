@@ -0,0 +1,319 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/loft-sh/vcluster/config"
+	"github.com/loft-sh/vcluster/pkg/constants"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/yaml"
+)
+
+// AnnotationPendingRestart is set on the vc-config secret and lists the changed config paths that are only applied
+// after vCluster was restarted
+const AnnotationPendingRestart = "vcluster.loft.sh/pending-restart"
+
+// ReloadInterval is the interval the vc-config secret is checked for changes
+var ReloadInterval = 10 * time.Second
+
+// ReloadablePaths are the config paths that are applied at runtime when the vc-config secret changes. Changes to all
+// other paths are deferred until vCluster is restarted. Paused syncers are not part of the config, they are read from
+// the annotation of the vc-config secret on every check.
+var ReloadablePaths = []string{
+	"sync.fromHost.nodes.selector",
+	"sync.toHost.pods.patches",
+	"sync.toHost.secrets.all",
+	"sync.toHost.secrets.opaqueOnly",
+	"sync.toHost.secrets.excludeTypes",
+	"sync.toHost.secrets.stripKeys",
+}
+
+// ReloadHandler is called with the new config after reloadable paths changed. Handlers only read the reloadable
+// paths they are interested in and need to take care of synchronizing with their own readers.
+type ReloadHandler func(newConfig *VirtualClusterConfig)
+
+type reloadHandlers struct {
+	m        sync.Mutex
+	handlers []ReloadHandler
+}
+
+// OnReload registers a handler that is called after reloadable paths of the config changed
+func (v *VirtualClusterConfig) OnReload(handler ReloadHandler) {
+	if v.reloadHandlers == nil {
+		return
+	}
+
+	v.reloadHandlers.m.Lock()
+	defer v.reloadHandlers.m.Unlock()
+	v.reloadHandlers.handlers = append(v.reloadHandlers.handlers, handler)
+}
+
+// Reload calls the registered reload handlers with the new config
+func (v *VirtualClusterConfig) Reload(newConfig *VirtualClusterConfig) {
+	if v.reloadHandlers == nil {
+		return
+	}
+
+	v.reloadHandlers.m.Lock()
+	handlers := append([]ReloadHandler{}, v.reloadHandlers.handlers...)
+	v.reloadHandlers.m.Unlock()
+	for _, handler := range handlers {
+		handler(newConfig)
+	}
+}
+
+// IsReloadable returns true if the given config path is applied at runtime
+func IsReloadable(path string) bool {
+	for _, reloadablePath := range ReloadablePaths {
+		if path == reloadablePath || strings.HasPrefix(path, reloadablePath+".") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ChangedPaths returns the sorted paths of all values that differ between the two configs. Lists are compared as a
+// whole.
+func ChangedPaths(oldConfig, newConfig *config.Config) ([]string, error) {
+	oldValues, err := toValues(oldConfig)
+	if err != nil {
+		return nil, err
+	}
+	newValues, err := toValues(newConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := changedPaths("", oldValues, newValues)
+	sort.Strings(paths)
+	return paths, nil
+}
+
+func changedPaths(prefix string, oldValue, newValue interface{}) []string {
+	oldMap, oldIsMap := oldValue.(map[string]interface{})
+	newMap, newIsMap := newValue.(map[string]interface{})
+	if !oldIsMap || !newIsMap {
+		if reflect.DeepEqual(oldValue, newValue) {
+			return nil
+		}
+
+		return []string{prefix}
+	}
+
+	paths := []string{}
+	for key, value := range oldMap {
+		paths = append(paths, changedPaths(joinPath(prefix, key), value, newMap[key])...)
+	}
+	for key, value := range newMap {
+		if _, ok := oldMap[key]; !ok {
+			paths = append(paths, changedPaths(joinPath(prefix, key), nil, value)...)
+		}
+	}
+
+	return paths
+}
+
+func joinPath(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+
+	return prefix + "." + key
+}
+
+func toValues(c *config.Config) (map[string]interface{}, error) {
+	raw, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+
+	values := map[string]interface{}{}
+	err = json.Unmarshal(raw, &values)
+	if err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
+
+// Reloader watches the vc-config secret of the virtual cluster and applies changes to ReloadablePaths at runtime.
+// Changes to other paths are flagged through the AnnotationPendingRestart annotation on the secret.
+type Reloader struct {
+	vConfig   *VirtualClusterConfig
+	setValues []string
+
+	// startup is the config vCluster was started with
+	startup *config.Config
+
+	// applied is the last applied raw config
+	applied       []byte
+	appliedConfig *config.Config
+}
+
+// NewReloader creates a reloader for the config vCluster was started with
+func NewReloader(vConfig *VirtualClusterConfig, path string, setValues []string) (*Reloader, error) {
+	rawFile, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	rawFile, err = applySetValues(rawFile, setValues)
+	if err != nil {
+		return nil, fmt.Errorf("apply set values: %w", err)
+	}
+
+	startup, err := parseRawConfig(rawFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Reloader{
+		vConfig:       vConfig,
+		setValues:     setValues,
+		startup:       startup,
+		applied:       rawFile,
+		appliedConfig: startup,
+	}, nil
+}
+
+// Start checks the vc-config secret for changes until the context is done
+func (r *Reloader) Start(ctx context.Context) {
+	go wait.UntilWithContext(ctx, func(ctx context.Context) {
+		err := r.check(ctx)
+		if err != nil {
+			klog.FromContext(ctx).Error(err, "reload vCluster config")
+		}
+	}, ReloadInterval)
+}
+
+func (r *Reloader) check(ctx context.Context) error {
+	secret, err := r.vConfig.ControlPlaneClient.CoreV1().Secrets(r.vConfig.ControlPlaneNamespace).Get(ctx, "vc-config-"+r.vConfig.Name, metav1.GetOptions{})
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil
+		}
+
+		return fmt.Errorf("get config secret: %w", err)
+	}
+
+	// paused syncers are applied independently of the config
+	pausedSyncers := ParsePausedSyncers(secret.Annotations[constants.PausedSyncersAnnotation])
+	if r.vConfig.SetPausedSyncers(pausedSyncers) {
+		klog.FromContext(ctx).Info("Changed paused syncers", "resources", pausedSyncers)
+	}
+
+	rawConfig, ok := secret.Data["config.yaml"]
+	if !ok {
+		return nil
+	}
+
+	rawConfig, err = applySetValues(rawConfig, r.setValues)
+	if err != nil {
+		return fmt.Errorf("apply set values: %w", err)
+	}
+	if bytes.Equal(rawConfig, r.applied) {
+		return nil
+	}
+
+	newConfig, err := parseRawConfig(rawConfig)
+	if err != nil {
+		return err
+	}
+
+	// flag the changes that need a restart
+	changed, err := ChangedPaths(r.startup, newConfig)
+	if err != nil {
+		return err
+	}
+	pending := []string{}
+	for _, path := range changed {
+		if !IsReloadable(path) {
+			pending = append(pending, path)
+		}
+	}
+	if secret.Annotations[AnnotationPendingRestart] != strings.Join(pending, ",") {
+		err = r.flagPendingRestart(ctx, pending)
+		if err != nil {
+			return err
+		}
+	}
+
+	// apply the reloadable changes
+	changed, err = ChangedPaths(r.appliedConfig, newConfig)
+	if err != nil {
+		return err
+	}
+	reloaded := []string{}
+	for _, path := range changed {
+		if IsReloadable(path) {
+			reloaded = append(reloaded, path)
+		}
+	}
+	if len(reloaded) > 0 {
+		newVConfig := &VirtualClusterConfig{
+			Config:                  *newConfig,
+			WorkloadConfig:          r.vConfig.WorkloadConfig,
+			WorkloadClient:          r.vConfig.WorkloadClient,
+			ControlPlaneConfig:      r.vConfig.ControlPlaneConfig,
+			ControlPlaneClient:      r.vConfig.ControlPlaneClient,
+			Name:                    r.vConfig.Name,
+			WorkloadService:         r.vConfig.WorkloadService,
+			WorkloadNamespace:       r.vConfig.WorkloadNamespace,
+			WorkloadTargetNamespace: r.vConfig.WorkloadTargetNamespace,
+			ControlPlaneService:     r.vConfig.ControlPlaneService,
+			ControlPlaneNamespace:   r.vConfig.ControlPlaneNamespace,
+		}
+		err = ValidateConfigAndSetDefaults(newVConfig)
+		if err != nil {
+			return fmt.Errorf("validate changed config: %w", err)
+		}
+
+		r.vConfig.Reload(newVConfig)
+		klog.FromContext(ctx).Info("Reloaded vCluster config", "paths", reloaded)
+	}
+	if len(pending) > 0 {
+		klog.FromContext(ctx).Info("Changed vCluster config requires a restart to be applied", "paths", pending)
+	}
+
+	r.applied = rawConfig
+	r.appliedConfig = newConfig
+	return nil
+}
+
+func (r *Reloader) flagPendingRestart(ctx context.Context, pending []string) error {
+	patch := []byte(fmt.Sprintf(`{"metadata":{"annotations":{%q:null}}}`, AnnotationPendingRestart))
+	if len(pending) > 0 {
+		patch = []byte(fmt.Sprintf(`{"metadata":{"annotations":{%q:%q}}}`, AnnotationPendingRestart, strings.Join(pending, ",")))
+	}
+
+	_, err := r.vConfig.ControlPlaneClient.CoreV1().Secrets(r.vConfig.ControlPlaneNamespace).Patch(ctx, "vc-config-"+r.vConfig.Name, types.MergePatchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		return fmt.Errorf("flag pending restart on config secret: %w", err)
+	}
+
+	return nil
+}
+
+func parseRawConfig(rawConfig []byte) (*config.Config, error) {
+	parsed := &config.Config{}
+	err := yaml.UnmarshalStrict(rawConfig, parsed)
+	if err != nil {
+		return nil, fmt.Errorf("parse config: %w", err)
+	}
+
+	return parsed, nil
+}
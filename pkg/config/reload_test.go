@@ -0,0 +1,143 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/loft-sh/vcluster/config"
+	"github.com/loft-sh/vcluster/pkg/constants"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestChangedPaths(t *testing.T) {
+	oldConfig := &config.Config{}
+	oldConfig.Sync.FromHost.Nodes.Selector.Labels = map[string]string{"a": "b"}
+	newConfig := &config.Config{}
+	newConfig.Sync.FromHost.Nodes.Selector.Labels = map[string]string{"a": "c"}
+	newConfig.Sync.ToHost.Ingresses.Enabled = true
+	newConfig.Sync.ToHost.Secrets.ExcludeTypes = []string{"kubernetes.io/tls"}
+
+	changed, err := ChangedPaths(oldConfig, newConfig)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, changed, []string{
+		"sync.fromHost.nodes.selector.labels.a",
+		"sync.toHost.ingresses.enabled",
+		"sync.toHost.secrets.excludeTypes",
+	})
+
+	assert.Assert(t, IsReloadable("sync.fromHost.nodes.selector.labels.a"))
+	assert.Assert(t, IsReloadable("sync.toHost.secrets.excludeTypes"))
+	assert.Assert(t, IsReloadable("sync.toHost.pods.patches"))
+	assert.Assert(t, !IsReloadable("sync.toHost.ingresses.enabled"))
+	assert.Assert(t, !IsReloadable("sync.toHost.secrets.allowed"))
+}
+
+func TestReloader(t *testing.T) {
+	ctx := context.Background()
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	assert.NilError(t, os.WriteFile(configPath, []byte(config.Values), 0600))
+
+	vConfig, err := ParseConfig(configPath, "my-vcluster", nil)
+	assert.NilError(t, err)
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "vc-config-my-vcluster", Namespace: "vcluster"},
+		Data:       map[string][]byte{"config.yaml": []byte(config.Values)},
+	}
+	kubeClient := fake.NewSimpleClientset(secret)
+	vConfig.ControlPlaneClient = kubeClient
+	vConfig.ControlPlaneNamespace = "vcluster"
+
+	reloaded := []*VirtualClusterConfig{}
+	vConfig.OnReload(func(newConfig *VirtualClusterConfig) {
+		reloaded = append(reloaded, newConfig)
+	})
+
+	reloader, err := NewReloader(vConfig, configPath, nil)
+	assert.NilError(t, err)
+
+	// nothing changed
+	assert.NilError(t, reloader.check(ctx))
+	assert.Equal(t, len(reloaded), 0)
+
+	// reloadable and unsafe changes
+	setSecretConfig(ctx, t, kubeClient, "sync.fromHost.nodes.selector.labels.team=a", "sync.toHost.ingresses.enabled=true")
+	assert.NilError(t, reloader.check(ctx))
+	assert.Equal(t, len(reloaded), 1)
+	assert.DeepEqual(t, reloaded[0].Sync.FromHost.Nodes.Selector.Labels, map[string]string{"team": "a"})
+	assert.Equal(t, reloaded[0].Name, "my-vcluster")
+	assert.Equal(t, getSecret(ctx, t, kubeClient).Annotations[AnnotationPendingRestart], "sync.toHost.ingresses.enabled")
+
+	// unsafe changes are reverted
+	setSecretConfig(ctx, t, kubeClient, "sync.fromHost.nodes.selector.labels.team=a")
+	assert.NilError(t, reloader.check(ctx))
+	assert.Equal(t, len(reloaded), 1)
+	_, ok := getSecret(ctx, t, kubeClient).Annotations[AnnotationPendingRestart]
+	assert.Assert(t, !ok)
+
+	// invalid configs are not applied
+	setSecretConfig(ctx, t, kubeClient, "sync.toHost.secrets.stripKeys[0]=[")
+	assert.ErrorContains(t, reloader.check(ctx), "validate changed config")
+	assert.Equal(t, len(reloaded), 1)
+}
+
+func TestPausedSyncers(t *testing.T) {
+	ctx := context.Background()
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	assert.NilError(t, os.WriteFile(configPath, []byte(config.Values), 0600))
+
+	vConfig, err := ParseConfig(configPath, "my-vcluster", nil)
+	assert.NilError(t, err)
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "vc-config-my-vcluster", Namespace: "vcluster"},
+		Data:       map[string][]byte{"config.yaml": []byte(config.Values)},
+	}
+	kubeClient := fake.NewSimpleClientset(secret)
+	vConfig.ControlPlaneClient = kubeClient
+	vConfig.ControlPlaneNamespace = "vcluster"
+
+	reloader, err := NewReloader(vConfig, configPath, nil)
+	assert.NilError(t, err)
+	assert.NilError(t, reloader.check(ctx))
+	assert.Assert(t, !vConfig.IsSyncerPaused("pod"))
+
+	// paused syncers are applied even if the config did not change
+	secret = getSecret(ctx, t, kubeClient)
+	secret.Annotations = map[string]string{constants.PausedSyncersAnnotation: "Pods, persistentvolumeclaims,ingresses"}
+	_, err = kubeClient.CoreV1().Secrets(secret.Namespace).Update(ctx, secret, metav1.UpdateOptions{})
+	assert.NilError(t, err)
+	assert.NilError(t, reloader.check(ctx))
+	assert.DeepEqual(t, vConfig.PausedSyncers(), []string{"ingresses", "persistentvolumeclaims", "pods"})
+	assert.Assert(t, vConfig.IsSyncerPaused("pod"))
+	assert.Assert(t, vConfig.IsSyncerPaused("persistent-volume-claim"))
+	assert.Assert(t, vConfig.IsSyncerPaused("ingress"))
+	assert.Assert(t, !vConfig.IsSyncerPaused("service"))
+
+	// resume
+	secret = getSecret(ctx, t, kubeClient)
+	delete(secret.Annotations, constants.PausedSyncersAnnotation)
+	_, err = kubeClient.CoreV1().Secrets(secret.Namespace).Update(ctx, secret, metav1.UpdateOptions{})
+	assert.NilError(t, err)
+	assert.NilError(t, reloader.check(ctx))
+	assert.Assert(t, !vConfig.IsSyncerPaused("pod"))
+}
+
+func setSecretConfig(ctx context.Context, t *testing.T, kubeClient *fake.Clientset, setValues ...string) {
+	rawConfig, err := applySetValues([]byte(config.Values), setValues)
+	assert.NilError(t, err)
+
+	secret := getSecret(ctx, t, kubeClient)
+	secret.Data["config.yaml"] = rawConfig
+	_, err = kubeClient.CoreV1().Secrets(secret.Namespace).Update(ctx, secret, metav1.UpdateOptions{})
+	assert.NilError(t, err)
+}
+
+func getSecret(ctx context.Context, t *testing.T, kubeClient *fake.Clientset) *corev1.Secret {
+	secret, err := kubeClient.CoreV1().Secrets("vcluster").Get(ctx, "vc-config-my-vcluster", metav1.GetOptions{})
+	assert.NilError(t, err)
+	return secret
+}
@@ -0,0 +1,113 @@
+package config
+
+import (
+	"hash/fnv"
+	"sync"
+)
+
+const (
+	// ShardByNamespace assigns all objects of a virtual namespace to the same shard
+	ShardByNamespace = "namespace"
+
+	// ShardByResource assigns all objects of a syncer to the same shard
+	ShardByResource = "resource"
+)
+
+type shards struct {
+	m sync.RWMutex
+
+	// index is the shard of this replica and total the number of shards, an index of -1 means no shard is assigned
+	index int
+	total int
+
+	handlers []func()
+
+	// reconciles is held for reading by the running reconciles of owned objects, so a new assignment is only applied
+	// once they finished
+	reconciles sync.RWMutex
+}
+
+// OwnsShard returns true if this replica syncs the objects of the syncer in the given virtual namespace. Without
+// sharding all objects are owned, with sharding nothing is owned until the leader assigned a shard to this replica.
+func (v *VirtualClusterConfig) OwnsShard(syncerName, namespace string) bool {
+	if v.shards == nil || !v.Experimental.SyncSettings.Sharding.Enabled {
+		return true
+	}
+
+	key := namespace
+	if v.Experimental.SyncSettings.Sharding.By == ShardByResource {
+		key = syncerName
+	}
+
+	v.shards.m.RLock()
+	defer v.shards.m.RUnlock()
+	return v.shards.index >= 0 && ShardForKey(key, v.shards.total) == v.shards.index
+}
+
+// AcquireShard returns true if this replica owns the shard of the object like OwnsShard. If the shard is owned, the
+// returned func has to be called once the object was synced, SetShardAssignment waits for this before it changes the
+// assignment.
+func (v *VirtualClusterConfig) AcquireShard(syncerName, namespace string) (func(), bool) {
+	if v.shards == nil || !v.Experimental.SyncSettings.Sharding.Enabled {
+		return func() {}, true
+	}
+
+	v.shards.reconciles.RLock()
+	if !v.OwnsShard(syncerName, namespace) {
+		v.shards.reconciles.RUnlock()
+		return nil, false
+	}
+
+	return v.shards.reconciles.RUnlock, true
+}
+
+// SetShardAssignment sets the shard of this replica and calls the registered handlers if the assignment changed. The
+// assignment is only changed once the acquired objects are synced, so a dropped shard isn't synced anymore afterwards.
+func (v *VirtualClusterConfig) SetShardAssignment(index, total int) bool {
+	if v.shards == nil {
+		return false
+	}
+
+	v.shards.m.RLock()
+	changed := v.shards.index != index || v.shards.total != total
+	v.shards.m.RUnlock()
+	if !changed {
+		return false
+	}
+
+	// wait for the running reconciles before the assignment is changed
+	v.shards.reconciles.Lock()
+	v.shards.m.Lock()
+	v.shards.index = index
+	v.shards.total = total
+	handlers := append([]func(){}, v.shards.handlers...)
+	v.shards.m.Unlock()
+	v.shards.reconciles.Unlock()
+
+	for _, handler := range handlers {
+		handler()
+	}
+	return true
+}
+
+// OnShardAssignment registers a handler that is called after the shard of this replica changed
+func (v *VirtualClusterConfig) OnShardAssignment(handler func()) {
+	if v.shards == nil {
+		return
+	}
+
+	v.shards.m.Lock()
+	defer v.shards.m.Unlock()
+	v.shards.handlers = append(v.shards.handlers, handler)
+}
+
+// ShardForKey returns the shard of the given key
+func ShardForKey(key string, total int) int {
+	if total <= 0 {
+		return -1
+	}
+
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+	return int(h.Sum32() % uint32(total))
+}
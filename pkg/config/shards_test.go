@@ -0,0 +1,70 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestOwnsShard(t *testing.T) {
+	vConfig := &VirtualClusterConfig{shards: &shards{index: -1}}
+	assert.Assert(t, vConfig.OwnsShard("pod", "default"))
+
+	// nothing is owned until a shard was assigned
+	vConfig.Experimental.SyncSettings.Sharding.Enabled = true
+	vConfig.Experimental.SyncSettings.Sharding.By = ShardByNamespace
+	assert.Assert(t, !vConfig.OwnsShard("pod", "default"))
+
+	changed := 0
+	vConfig.OnShardAssignment(func() { changed++ })
+	assert.Assert(t, vConfig.SetShardAssignment(ShardForKey("default", 3), 3))
+	assert.Assert(t, !vConfig.SetShardAssignment(ShardForKey("default", 3), 3))
+	assert.Equal(t, changed, 1)
+
+	// all syncers of the namespace belong to the same shard
+	assert.Assert(t, vConfig.OwnsShard("pod", "default"))
+	assert.Assert(t, vConfig.OwnsShard("configmap", "default"))
+
+	// every key belongs to exactly one shard
+	vConfig.Experimental.SyncSettings.Sharding.By = ShardByResource
+	for _, syncerName := range []string{"pod", "service", "configmap", "secret"} {
+		owners := 0
+		for index := range 3 {
+			vConfig.SetShardAssignment(index, 3)
+			if vConfig.OwnsShard(syncerName, "default") {
+				owners++
+			}
+		}
+		assert.Equal(t, owners, 1, syncerName)
+	}
+}
+
+func TestAcquireShard(t *testing.T) {
+	vConfig := &VirtualClusterConfig{shards: &shards{index: -1}}
+	vConfig.Experimental.SyncSettings.Sharding.Enabled = true
+	vConfig.Experimental.SyncSettings.Sharding.By = ShardByNamespace
+	_, owned := vConfig.AcquireShard("pod", "default")
+	assert.Assert(t, !owned)
+
+	vConfig.SetShardAssignment(ShardForKey("default", 1), 1)
+	release, owned := vConfig.AcquireShard("pod", "default")
+	assert.Assert(t, owned)
+
+	// the shard is only dropped once the running reconcile released it
+	dropped := make(chan struct{})
+	go func() {
+		vConfig.SetShardAssignment(-1, 0)
+		close(dropped)
+	}()
+	select {
+	case <-dropped:
+		t.Fatal("shard dropped while the object was synced")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	release()
+	<-dropped
+	_, owned = vConfig.AcquireShard("pod", "default")
+	assert.Assert(t, !owned)
+}
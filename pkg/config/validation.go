@@ -4,14 +4,27 @@ import (
 	"crypto/x509"
 	"errors"
 	"fmt"
+	"net"
 	"net/url"
+	"path"
+	"regexp"
 	"slices"
+	"strconv"
+	"strings"
 
 	"github.com/ghodss/yaml"
 	"github.com/loft-sh/vcluster/config"
+	"github.com/loft-sh/vcluster/pkg/integrations/certmanager"
+	"github.com/loft-sh/vcluster/pkg/integrations/externalsecrets"
+	"github.com/loft-sh/vcluster/pkg/integrations/istio"
+	"github.com/loft-sh/vcluster/pkg/util/resources"
+	"github.com/loft-sh/vcluster/pkg/util/servicecidr"
 	"github.com/loft-sh/vcluster/pkg/util/toleration"
 	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/api/validation"
+	utilvalidation "k8s.io/apimachinery/pkg/util/validation"
 )
 
 var allowedPodSecurityStandards = map[string]bool{
@@ -22,12 +35,15 @@ var allowedPodSecurityStandards = map[string]bool{
 
 var (
 	verbs = []string{"get", "list", "create", "update", "patch", "watch", "delete", "deletecollection"}
+
+	// WasmHookTypes are the client operations wasm hooks can be invoked for
+	WasmHookTypes = []string{"GetPhysical", "CreatePhysical", "UpdatePhysical", "DeletePhysical", "GetVirtual", "CreateVirtual", "UpdateVirtual", "DeleteVirtual"}
 )
 
 func ValidateConfigAndSetDefaults(config *VirtualClusterConfig) error {
 	// check the value of pod security standard
-	if config.Policies.PodSecurityStandard != "" && !allowedPodSecurityStandards[config.Policies.PodSecurityStandard] {
-		return fmt.Errorf("invalid argument enforce-pod-security-standard=%s, must be one of: privileged, baseline, restricted", config.Policies.PodSecurityStandard)
+	if config.Policies.PodSecurityStandard.Level != "" && !allowedPodSecurityStandards[config.Policies.PodSecurityStandard.Level] {
+		return fmt.Errorf("invalid argument enforce-pod-security-standard=%s, must be one of: privileged, baseline, restricted", config.Policies.PodSecurityStandard.Level)
 	}
 
 	// parse tolerations
@@ -69,8 +85,14 @@ func ValidateConfigAndSetDefaults(config *VirtualClusterConfig) error {
 		return fmt.Errorf("you cannot enable both sync.fromHost.storageClasses.enabled and sync.toHost.storageClasses.enabled at the same time. Choose only one of them")
 	}
 
+	// check host storage class mappings
+	err := validateHostStorageClasses(config.Sync.FromHost.StorageClasses)
+	if err != nil {
+		return err
+	}
+
 	// validate central admission control
-	err := validateCentralAdmissionControl(config)
+	err = validateCentralAdmissionControl(config)
 	if err != nil {
 		return err
 	}
@@ -92,6 +114,17 @@ func ValidateConfigAndSetDefaults(config *VirtualClusterConfig) error {
 		return err
 	}
 
+	// check isolated control plane
+	err = validateIsolatedControlPlane(config)
+	if err != nil {
+		return err
+	}
+
+	err = validateDensity(config.ControlPlane.Advanced.Density)
+	if err != nil {
+		return err
+	}
+
 	// check deny proxy requests
 	for _, c := range config.Experimental.DenyProxyRequests {
 		err := validateCheck(c)
@@ -106,6 +139,117 @@ func ValidateConfigAndSetDefaults(config *VirtualClusterConfig) error {
 		return err
 	}
 
+	// check auto sleep
+	if config.Experimental.AutoSleep.Enabled && config.Experimental.AutoSleep.AfterInactivity <= 0 {
+		return fmt.Errorf("experimental.autoSleep.afterInactivity must be greater than 0")
+	}
+
+	if config.ControlPlane.Proxy.FromHostCache.TTL < 0 {
+		return fmt.Errorf("controlPlane.proxy.fromHostCache.ttl must not be negative")
+	}
+
+	// check service cidr
+	err = validateServiceCIDR(config)
+	if err != nil {
+		return err
+	}
+
+	// check shared dns
+	err = validateSharedDNS(config)
+	if err != nil {
+		return err
+	}
+
+	// check coredns customizations
+	err = validateCoreDNS(config)
+	if err != nil {
+		return err
+	}
+
+	// check service account issuer
+	err = validateServiceAccountIssuer(config)
+	if err != nil {
+		return err
+	}
+
+	// check network isolation
+	err = validateNetworkIsolation(config.Policies)
+	if err != nil {
+		return err
+	}
+
+	// check pod restrictions
+	err = validatePodRestrictions(config.Policies.PodRestrictions)
+	if err != nil {
+		return err
+	}
+
+	// check pod security admission
+	err = validatePodSecurityStandard(config.Policies.PodSecurityStandard)
+	if err != nil {
+		return err
+	}
+
+	// check platform dns
+	err = validatePlatformDNS(config)
+	if err != nil {
+		return err
+	}
+
+	// check multi cluster
+	err = validateMultiCluster(config)
+	if err != nil {
+		return err
+	}
+
+	// check sharding
+	err = validateSharding(config)
+	if err != nil {
+		return err
+	}
+
+	// check catch up priorities
+	err = validateCatchUp(config.Experimental.SyncSettings.CatchUp)
+	if err != nil {
+		return err
+	}
+
+	// check wasm hooks
+	err = validateWasmHooks(config.Experimental.WasmHooks)
+	if err != nil {
+		return err
+	}
+
+	// check secret sync patterns
+	err = validateSyncSecrets(config.Sync.ToHost.Secrets)
+	if err != nil {
+		return err
+	}
+
+	// check image pull secrets
+	err = validateImagePullSecrets(config.Sync.ToHost.Pods.ImagePullSecrets)
+	if err != nil {
+		return err
+	}
+
+	// check scheduling policy
+	err = validateSchedulingPolicy(config.Policies.Scheduling)
+	if err != nil {
+		return err
+	}
+
+	// check extended resource limits
+	err = validateExtendedResourceLimits(config.Policies.ExtendedResources.Limits)
+	if err != nil {
+		return err
+	}
+
+	// check integrations
+	err = validateIntegrations(config.Integrations, config.Experimental.GenericSync.Exports)
+	if err != nil {
+		return err
+	}
+
 	// set service name
 	if config.ControlPlane.Advanced.WorkloadServiceAccount.Name == "" {
 		config.ControlPlane.Advanced.WorkloadServiceAccount.Name = "vc-workload-" + config.Name
@@ -450,6 +594,553 @@ func validateWildcardOrAny(values []string) error {
 	return nil
 }
 
+func validateIsolatedControlPlane(c *VirtualClusterConfig) error {
+	err := config.ValidateIsolatedControlPlane(c.Experimental.IsolatedControlPlane)
+	if err != nil {
+		return err
+	}
+
+	// k0s runs its own konnectivity setup that expects k0s workers, k3s keeps its certificates in other paths and
+	// configures the egress selector of the api server itself
+	if c.Experimental.IsolatedControlPlane.Enabled && c.Experimental.IsolatedControlPlane.Konnectivity.Enabled && c.Distro() != config.K8SDistro {
+		return fmt.Errorf("experimental.isolatedControlPlane.konnectivity.enabled is only supported with the k8s distro")
+	}
+
+	return nil
+}
+
+func validateDensity(density config.ControlPlaneDensity) error {
+	if !density.Enabled {
+		return nil
+	}
+	if density.ProbePeriodSeconds < 1 {
+		return fmt.Errorf("controlPlane.advanced.density.probePeriodSeconds must be greater than 0")
+	}
+
+	return nil
+}
+
+func validateSharedDNS(c *VirtualClusterConfig) error {
+	sharedDNS := c.Networking.Advanced.SharedDNS
+	if !sharedDNS.Enabled {
+		return nil
+	}
+	if sharedDNS.Namespace == "" {
+		return fmt.Errorf("networking.advanced.sharedDNS.namespace is required if shared dns is enabled")
+	}
+	if c.ControlPlane.CoreDNS.Embedded {
+		return fmt.Errorf("networking.advanced.sharedDNS.enabled cannot be used together with controlPlane.coredns.embedded")
+	}
+	if sharedDNS.Cache.TTL < 0 || sharedDNS.Cache.Size < 0 {
+		return fmt.Errorf("networking.advanced.sharedDNS.cache.ttl and networking.advanced.sharedDNS.cache.size must not be negative")
+	}
+
+	return nil
+}
+
+func validateServiceAccountIssuer(c *VirtualClusterConfig) error {
+	issuer := c.ControlPlane.Advanced.ServiceAccountIssuer
+	if issuer.URL != "" {
+		issuerURL, err := url.Parse(issuer.URL)
+		if err != nil || issuerURL.Scheme != "https" || issuerURL.Host == "" || issuerURL.RawQuery != "" || issuerURL.Fragment != "" {
+			return fmt.Errorf("controlPlane.advanced.serviceAccountIssuer.url: expected an https url without query or fragment, but got %q", issuer.URL)
+		}
+		if c.Distro() == config.K0SDistro {
+			return fmt.Errorf("controlPlane.advanced.serviceAccountIssuer.url is not supported with the k0s distro")
+		}
+	}
+	if issuer.Ingress.Enabled && (!issuer.Enabled || issuer.URL == "") {
+		return fmt.Errorf("controlPlane.advanced.serviceAccountIssuer.ingress.enabled requires controlPlane.advanced.serviceAccountIssuer.enabled and controlPlane.advanced.serviceAccountIssuer.url")
+	}
+
+	return nil
+}
+
+func validateNetworkIsolation(policies config.Policies) error {
+	networkIsolation := policies.NetworkIsolation
+	if !networkIsolation.Enabled {
+		return nil
+	}
+
+	// network policies are additive, so the egress and ingress policies.networkPolicy allows would bypass the isolation
+	if policies.NetworkPolicy.Enabled {
+		return fmt.Errorf("policies.networkIsolation and policies.networkPolicy cannot be enabled at the same time, as the traffic allowed by policies.networkPolicy bypasses the isolation")
+	}
+
+	err := validateIPBlocks("policies.networkIsolation.egress.ipBlocks", networkIsolation.Egress.IPBlocks)
+	if err != nil {
+		return err
+	}
+	err = validateIPBlocks("policies.networkIsolation.ingress.ipBlocks", networkIsolation.Ingress.IPBlocks)
+	if err != nil {
+		return err
+	}
+	for _, namespace := range networkIsolation.Ingress.Namespaces {
+		if errs := validation.ValidateNamespaceName(namespace, false); len(errs) > 0 {
+			return fmt.Errorf("policies.networkIsolation.ingress.namespaces: invalid namespace %q: %s", namespace, strings.Join(errs, ", "))
+		}
+	}
+	for _, fqdn := range networkIsolation.Egress.FQDNs {
+		if !isValidFQDNRule(fqdn) {
+			return fmt.Errorf("policies.networkIsolation.egress.fqdns: invalid domain %q, expected a domain name like api.github.com or *.github.com", fqdn)
+		}
+	}
+	switch networkIsolation.Egress.FQDNProvider {
+	case "", "cilium", "calico":
+	default:
+		return fmt.Errorf("policies.networkIsolation.egress.fqdnProvider: invalid provider %q, must be one of: cilium, calico", networkIsolation.Egress.FQDNProvider)
+	}
+
+	return nil
+}
+
+func validateIPBlocks(path string, ipBlocks []config.IPBlock) error {
+	for i, ipBlock := range ipBlocks {
+		_, cidr, err := net.ParseCIDR(ipBlock.CIDR)
+		if err != nil {
+			return fmt.Errorf("%s[%d].cidr: invalid cidr %q", path, i, ipBlock.CIDR)
+		}
+		for _, except := range ipBlock.Except {
+			exceptIP, _, err := net.ParseCIDR(except)
+			if err != nil {
+				return fmt.Errorf("%s[%d].except: invalid cidr %q", path, i, except)
+			} else if !cidr.Contains(exceptIP) {
+				return fmt.Errorf("%s[%d].except: %s is outside of %s", path, i, except, ipBlock.CIDR)
+			}
+		}
+	}
+
+	return nil
+}
+
+// isValidFQDNRule checks that the given rule is a domain name, where wildcards are only allowed as whole labels,
+// e.g. *.github.com
+func isValidFQDNRule(fqdn string) bool {
+	labels := strings.Split(fqdn, ".")
+	for i, label := range labels {
+		if label == "*" {
+			labels[i] = "wildcard"
+		} else if strings.Contains(label, "*") {
+			return false
+		}
+	}
+
+	return len(utilvalidation.IsDNS1123Subdomain(strings.Join(labels, "."))) == 0
+}
+
+func validateHostStorageClasses(storageClasses config.SyncFromHostStorageClasses) error {
+	from := map[string]bool{}
+	to := map[string]bool{}
+	for i, mapping := range storageClasses.Mappings {
+		if mapping.From == "" || mapping.To == "" {
+			return fmt.Errorf("sync.fromHost.storageClasses.mappings[%d]: from and to are required", i)
+		}
+		if from[mapping.From] {
+			return fmt.Errorf("sync.fromHost.storageClasses.mappings[%d]: host storage class %q is mapped more than once", i, mapping.From)
+		}
+		if to[mapping.To] {
+			return fmt.Errorf("sync.fromHost.storageClasses.mappings[%d]: virtual storage class %q is mapped more than once", i, mapping.To)
+		}
+
+		from[mapping.From] = true
+		to[mapping.To] = true
+	}
+	if storageClasses.Default != "" && len(storageClasses.Mappings) > 0 && !to[storageClasses.Default] {
+		return fmt.Errorf("sync.fromHost.storageClasses.default: storage class %q is not synced, it must be the target of one of the mappings", storageClasses.Default)
+	}
+
+	return nil
+}
+
+func validateSharding(c *VirtualClusterConfig) error {
+	sharding := &c.Experimental.SyncSettings.Sharding
+	if sharding.By == "" {
+		sharding.By = ShardByNamespace
+	}
+	if sharding.By != ShardByNamespace && sharding.By != ShardByResource {
+		return fmt.Errorf("experimental.syncSettings.sharding.by %q is invalid, must be one of: %s, %s", sharding.By, ShardByNamespace, ShardByResource)
+	}
+	if !sharding.Enabled {
+		return nil
+	}
+	if c.ControlPlane.StatefulSet.HighAvailability.Replicas < 2 {
+		return fmt.Errorf("experimental.syncSettings.sharding.enabled requires controlPlane.statefulSet.highAvailability.replicas to be greater than 1")
+	}
+	if c.Experimental.MultiCluster.Satellite.Enabled {
+		return fmt.Errorf("experimental.syncSettings.sharding.enabled cannot be used together with experimental.multiCluster.satellite.enabled")
+	}
+	if c.Experimental.SyncSettings.DisableSync {
+		return fmt.Errorf("experimental.syncSettings.sharding.enabled cannot be used together with experimental.syncSettings.disableSync")
+	}
+
+	return nil
+}
+
+func validateCatchUp(catchUp config.ExperimentalCatchUp) error {
+	if !catchUp.Enabled {
+		return nil
+	}
+	if catchUp.Timeout <= 0 {
+		return fmt.Errorf("experimental.syncSettings.catchUp.timeout must be greater than 0")
+	}
+	for _, high := range catchUp.HighPriority {
+		for _, low := range catchUp.LowPriority {
+			if SyncerMatchesResource(high, low) {
+				return fmt.Errorf("experimental.syncSettings.catchUp: resource %q cannot be high and low priority", low)
+			}
+		}
+	}
+
+	return nil
+}
+
+func validatePodRestrictions(podRestrictions config.PodRestrictions) error {
+	for _, rule := range []struct {
+		path string
+		rule config.PodRestrictionRule
+	}{
+		{path: "policies.podRestrictions.hostPath", rule: podRestrictions.HostPath.PodRestrictionRule},
+		{path: "policies.podRestrictions.hostNamespaces", rule: podRestrictions.HostNamespaces},
+		{path: "policies.podRestrictions.privileged", rule: podRestrictions.Privileged},
+	} {
+		switch rule.rule.Action {
+		case "", "deny", "rewrite":
+		default:
+			return fmt.Errorf("%s.action: invalid action %q, must be one of: deny, rewrite", rule.path, rule.rule.Action)
+		}
+	}
+	for _, allowedPath := range podRestrictions.HostPath.AllowedPaths {
+		if !path.IsAbs(allowedPath) {
+			return fmt.Errorf("policies.podRestrictions.hostPath.allowedPaths: %q is not an absolute path", allowedPath)
+		}
+	}
+
+	return nil
+}
+
+var podSecurityVersionRegEx = regexp.MustCompile(`^(latest|v1\.[0-9]+)$`)
+
+func validatePodSecurityStandard(podSecurity config.PodSecurityStandard) error {
+	if !podSecurity.Enabled {
+		return nil
+	}
+
+	defaults := podSecurity.Defaults
+	for _, mode := range []struct{ name, level, version string }{
+		{name: "enforce", level: defaults.Enforce, version: defaults.EnforceVersion},
+		{name: "audit", level: defaults.Audit, version: defaults.AuditVersion},
+		{name: "warn", level: defaults.Warn, version: defaults.WarnVersion},
+	} {
+		if mode.level != "" && !allowedPodSecurityStandards[mode.level] {
+			return fmt.Errorf("policies.podSecurityStandard.defaults.%s: invalid level %q, must be one of: privileged, baseline, restricted", mode.name, mode.level)
+		}
+		if mode.version != "" && !podSecurityVersionRegEx.MatchString(mode.version) {
+			return fmt.Errorf("policies.podSecurityStandard.defaults.%sVersion: invalid version %q, must be latest or of the form v1.x", mode.name, mode.version)
+		}
+	}
+	for _, namespace := range podSecurity.Exemptions.Namespaces {
+		if errs := validation.ValidateNamespaceName(namespace, false); len(errs) > 0 {
+			return fmt.Errorf("policies.podSecurityStandard.exemptions.namespaces: invalid namespace %q: %s", namespace, strings.Join(errs, ", "))
+		}
+	}
+
+	return nil
+}
+
+func validateCoreDNS(c *VirtualClusterConfig) error {
+	coreDNS := c.Networking.Advanced.CoreDNS
+	customized := len(coreDNS.Upstream) > 0 || len(coreDNS.StubDomains) > 0 || coreDNS.Plugins != "" || coreDNS.ServerBlocks != "" || coreDNS.NodeLocalDNS.Enabled
+	if !customized {
+		return nil
+	}
+	if c.ControlPlane.CoreDNS.OverwriteConfig != "" {
+		return fmt.Errorf("networking.advanced.coreDNS cannot be used together with controlPlane.coredns.overwriteConfig")
+	}
+	if c.Networking.Advanced.SharedDNS.Enabled {
+		return fmt.Errorf("networking.advanced.coreDNS cannot be used together with networking.advanced.sharedDNS.enabled")
+	}
+	if c.Networking.Advanced.FallbackHostCluster && (len(coreDNS.Upstream) > 0 || coreDNS.NodeLocalDNS.Enabled) {
+		return fmt.Errorf("networking.advanced.coreDNS.upstream and networking.advanced.coreDNS.nodeLocalDNS cannot be used together with networking.advanced.fallbackHostCluster")
+	}
+	if len(coreDNS.Upstream) > 0 && coreDNS.NodeLocalDNS.Enabled {
+		return fmt.Errorf("networking.advanced.coreDNS.upstream cannot be used together with networking.advanced.coreDNS.nodeLocalDNS.enabled")
+	}
+
+	for i, upstream := range coreDNS.Upstream {
+		if err := validateResolver(upstream); err != nil {
+			return fmt.Errorf("networking.advanced.coreDNS.upstream[%d]: %w", i, err)
+		}
+	}
+
+	domains := map[string]bool{}
+	for i, stubDomain := range coreDNS.StubDomains {
+		domain := strings.TrimSuffix(stubDomain.Domain, ".")
+		if errs := utilvalidation.IsDNS1123Subdomain(domain); len(errs) > 0 {
+			return fmt.Errorf("networking.advanced.coreDNS.stubDomains[%d].domain: invalid domain %q: %s", i, stubDomain.Domain, strings.Join(errs, ", "))
+		}
+		if domains[domain] {
+			return fmt.Errorf("networking.advanced.coreDNS.stubDomains has duplicate domain %s", domain)
+		}
+		domains[domain] = true
+		if len(stubDomain.Resolvers) == 0 {
+			return fmt.Errorf("networking.advanced.coreDNS.stubDomains[%d].resolvers is required", i)
+		}
+		for j, resolver := range stubDomain.Resolvers {
+			if err := validateResolver(resolver); err != nil {
+				return fmt.Errorf("networking.advanced.coreDNS.stubDomains[%d].resolvers[%d]: %w", i, j, err)
+			}
+		}
+	}
+
+	if err := validateCorefileSnippet(coreDNS.Plugins); err != nil {
+		return fmt.Errorf("networking.advanced.coreDNS.plugins: %w", err)
+	}
+	if err := validateCorefileSnippet(coreDNS.ServerBlocks); err != nil {
+		return fmt.Errorf("networking.advanced.coreDNS.serverBlocks: %w", err)
+	}
+
+	if coreDNS.NodeLocalDNS.Enabled && net.ParseIP(coreDNS.NodeLocalDNS.IP) == nil {
+		return fmt.Errorf("networking.advanced.coreDNS.nodeLocalDNS.ip: expected an ip, but got %q", coreDNS.NodeLocalDNS.IP)
+	}
+
+	return nil
+}
+
+func validateServiceCIDR(c *VirtualClusterConfig) error {
+	serviceCIDR := c.Networking.Advanced.ServiceCIDR
+	if serviceCIDR == "" {
+		return nil
+	}
+	if c.ServiceCIDR != "" && c.ServiceCIDR != serviceCIDR {
+		return fmt.Errorf("serviceCIDR %q and networking.advanced.serviceCIDR %q are different, please only use networking.advanced.serviceCIDR", c.ServiceCIDR, serviceCIDR)
+	}
+	if _, err := servicecidr.ParseServiceCIDR(serviceCIDR); err != nil {
+		return fmt.Errorf("networking.advanced.serviceCIDR: invalid cidr %q: %w", serviceCIDR, err)
+	}
+
+	return nil
+}
+
+func validatePlatformDNS(c *VirtualClusterConfig) error {
+	platformDNS := c.Networking.Advanced.PlatformDNS
+	if !platformDNS.Enabled {
+		return nil
+	}
+	if platformDNS.Namespace == "" {
+		return fmt.Errorf("networking.advanced.platformDNS.namespace is required if platform dns is enabled")
+	}
+	if errs := utilvalidation.IsDNS1123Subdomain(strings.TrimSuffix(platformDNS.Domain, ".")); len(errs) > 0 {
+		return fmt.Errorf("networking.advanced.platformDNS.domain: invalid domain %q: %s", platformDNS.Domain, strings.Join(errs, ", "))
+	}
+	if c.ControlPlane.CoreDNS.Embedded {
+		return fmt.Errorf("networking.advanced.platformDNS.enabled cannot be used together with controlPlane.coredns.embedded")
+	}
+	if c.Networking.Advanced.SharedDNS.Enabled {
+		return fmt.Errorf("networking.advanced.platformDNS.enabled cannot be used together with networking.advanced.sharedDNS.enabled")
+	}
+
+	return nil
+}
+
+// validateResolver checks that the resolver is something the coredns forward plugin understands
+func validateResolver(resolver string) error {
+	if strings.HasPrefix(resolver, "/") {
+		// resolv.conf style file
+		return nil
+	}
+
+	address := strings.TrimPrefix(strings.TrimPrefix(resolver, "dns://"), "tls://")
+	if net.ParseIP(address) != nil {
+		return nil
+	}
+
+	host, port, err := net.SplitHostPort(address)
+	if err == nil && net.ParseIP(host) != nil {
+		if portNumber, err := strconv.Atoi(port); err == nil && portNumber > 0 && portNumber < 65536 {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("expected an ip, ip:port or file path, but got %q", resolver)
+}
+
+// validateCorefileSnippet makes sure a snippet doesn't close blocks it hasn't opened
+func validateCorefileSnippet(snippet string) error {
+	depth := 0
+	for _, line := range strings.Split(snippet, "\n") {
+		// strip comments
+		line, _, _ = strings.Cut(line, "#")
+		for _, r := range line {
+			switch r {
+			case '{':
+				depth++
+			case '}':
+				depth--
+				if depth < 0 {
+					return fmt.Errorf("unexpected }")
+				}
+			}
+		}
+	}
+	if depth != 0 {
+		return fmt.Errorf("unclosed {")
+	}
+
+	return nil
+}
+
+func validateMultiCluster(c *VirtualClusterConfig) error {
+	multiCluster := c.Experimental.MultiCluster
+	names := map[string]bool{}
+	for _, satellite := range multiCluster.Satellites {
+		if satellite.Name == "" {
+			return fmt.Errorf("experimental.multiCluster.satellites[*].name is required")
+		}
+		if names[satellite.Name] {
+			return fmt.Errorf("experimental.multiCluster.satellites has duplicate satellite %s", satellite.Name)
+		}
+		if len(satellite.NodeSelector) == 0 {
+			return fmt.Errorf("experimental.multiCluster.satellites[%s].nodeSelector is required", satellite.Name)
+		}
+
+		names[satellite.Name] = true
+	}
+	if len(multiCluster.Satellites) > 0 && c.Sync.FromHost.Nodes.Enabled {
+		return fmt.Errorf("experimental.multiCluster.satellites cannot be used together with sync.fromHost.nodes.enabled, as each syncer would remove the nodes of the other host clusters")
+	}
+
+	if !multiCluster.Satellite.Enabled {
+		return nil
+	}
+	if !names[multiCluster.Satellite.Name] {
+		return fmt.Errorf("experimental.multiCluster.satellite.name %q must be one of experimental.multiCluster.satellites", multiCluster.Satellite.Name)
+	}
+	if c.Experimental.VirtualClusterKubeConfig.KubeConfig == "" {
+		return fmt.Errorf("experimental.virtualClusterKubeConfig.kubeConfig is required to connect the satellite to the virtual cluster")
+	}
+	if multiCluster.Satellite.KubernetesService == "" || multiCluster.Satellite.DNSService == "" {
+		return fmt.Errorf("experimental.multiCluster.satellite.kubernetesService and experimental.multiCluster.satellite.dnsService are required, as the cluster ips of the primary host cluster are not reachable from the satellite")
+	}
+
+	return nil
+}
+
+func validateWasmHooks(hooks []config.WasmHook) error {
+	names := map[string]bool{}
+	for idx, hook := range hooks {
+		if hook.Name == "" {
+			return fmt.Errorf("experimental.wasmHooks[%d].name is required", idx)
+		}
+		if names[hook.Name] {
+			return fmt.Errorf("experimental.wasmHooks has duplicate hook %s", hook.Name)
+		}
+		names[hook.Name] = true
+
+		if hook.APIVersion == "" || hook.Kind == "" {
+			return fmt.Errorf("experimental.wasmHooks[%s].apiVersion and kind are required", hook.Name)
+		}
+		if (hook.Module.Secret.Name == "") == (hook.Module.OCI == "") {
+			return fmt.Errorf("experimental.wasmHooks[%s].module needs exactly one of secret.name or oci", hook.Name)
+		}
+		if hook.Limits.Memory < 0 || hook.Limits.Timeout < 0 {
+			return fmt.Errorf("experimental.wasmHooks[%s].limits must not be negative", hook.Name)
+		}
+		for _, hookType := range hook.Types {
+			if !slices.Contains(WasmHookTypes, hookType) {
+				return fmt.Errorf("invalid experimental.wasmHooks[%s].types %q, expected one of %q", hook.Name, hookType, WasmHookTypes)
+			}
+		}
+	}
+
+	return nil
+}
+
+func validateSyncSecrets(secrets config.SyncSecrets) error {
+	for _, pattern := range secrets.ExcludeTypes {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid sync.toHost.secrets.excludeTypes pattern %q: %w", pattern, err)
+		}
+	}
+	for _, pattern := range secrets.StripKeys {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid sync.toHost.secrets.stripKeys pattern %q: %w", pattern, err)
+		}
+	}
+
+	return nil
+}
+
+func validateImagePullSecrets(imagePullSecrets []config.SyncPodsImagePullSecret) error {
+	for i, imagePullSecret := range imagePullSecrets {
+		if imagePullSecret.Name == "" {
+			return fmt.Errorf("sync.toHost.pods.imagePullSecrets[%d].name is required", i)
+		}
+		if (imagePullSecret.FromSecret.Name == "") != (imagePullSecret.FromSecret.Namespace == "") {
+			return fmt.Errorf("sync.toHost.pods.imagePullSecrets[%d].fromSecret needs both name and namespace", i)
+		}
+	}
+
+	return nil
+}
+
+func validateSchedulingPolicy(scheduling config.SchedulingPolicy) error {
+	for i, requirement := range scheduling.NodeAffinity {
+		if requirement.Key == "" {
+			return fmt.Errorf("policies.scheduling.nodeAffinity[%d].key is required", i)
+		}
+
+		switch requirement.Operator {
+		case "In", "NotIn", "Gt", "Lt":
+			if len(requirement.Values) == 0 {
+				return fmt.Errorf("policies.scheduling.nodeAffinity[%d].values is required for operator %s", i, requirement.Operator)
+			}
+		case "Exists", "DoesNotExist":
+			if len(requirement.Values) > 0 {
+				return fmt.Errorf("policies.scheduling.nodeAffinity[%d].values must be empty for operator %s", i, requirement.Operator)
+			}
+		default:
+			return fmt.Errorf("policies.scheduling.nodeAffinity[%d].operator %q is invalid, must be one of In, NotIn, Exists, DoesNotExist, Gt or Lt", i, requirement.Operator)
+		}
+	}
+
+	return nil
+}
+
+func validateExtendedResourceLimits(limits map[string]interface{}) error {
+	for name, limit := range limits {
+		if !resources.IsExtendedResourceName(corev1.ResourceName(name)) {
+			return fmt.Errorf("policies.extendedResources.limits.%s: %s is not an extended resource, use policies.resourceQuota to limit native resources", name, name)
+		}
+
+		_, err := resource.ParseQuantity(fmt.Sprint(limit))
+		if err != nil {
+			return fmt.Errorf("policies.extendedResources.limits.%s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+func validateIntegrations(integrations config.Integrations, exports []*config.Export) error {
+	for idx, exp := range exports {
+		if exp == nil {
+			continue
+		}
+
+		if istio.IsExported(integrations.Istio, exp.APIVersion, exp.Kind) {
+			return fmt.Errorf("experimental.genericSync.export[%d]: %s is already synced by integrations.istio, disable integrations.istio.sync.toHost to sync it yourself", idx, exp.Kind)
+		}
+		if certmanager.IsExported(integrations.CertManager, exp.APIVersion, exp.Kind) {
+			return fmt.Errorf("experimental.genericSync.export[%d]: %s is already synced by integrations.certManager, disable integrations.certManager.sync.toHost to sync it yourself", idx, exp.Kind)
+		}
+		if externalsecrets.IsExported(integrations.ExternalSecrets, exp.APIVersion, exp.Kind) {
+			return fmt.Errorf("experimental.genericSync.export[%d]: %s is already synced by integrations.externalSecrets, disable integrations.externalSecrets.sync.toHost to sync it yourself", idx, exp.Kind)
+		}
+	}
+
+	return nil
+}
+
 func validateK0sAndNoExperimentalKubeconfig(c *VirtualClusterConfig) error {
 	if c.Distro() != config.K0SDistro {
 		return nil
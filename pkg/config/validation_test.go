@@ -1,6 +1,7 @@
 package config
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/loft-sh/vcluster/config"
@@ -102,6 +103,473 @@ HYMfRsCbvUOZ58SWLs5fyQ==
 	}
 }
 
+func TestValidateCoreDNS(t *testing.T) {
+	testCases := []struct {
+		name    string
+		coreDNS config.NetworkingCoreDNS
+		modify  func(c *VirtualClusterConfig)
+		wantErr string
+	}{
+		{
+			name: "no customizations",
+		},
+		{
+			name: "valid customizations",
+			coreDNS: config.NetworkingCoreDNS{
+				Upstream:     []string{"1.1.1.1", "10.0.0.10:5353", "tls://9.9.9.9", "/etc/resolv.conf"},
+				StubDomains:  []config.CoreDNSStubDomain{{Domain: "corp.example.com.", Resolvers: []string{"10.0.0.10"}}},
+				Plugins:      "rewrite name exact a.internal a.default.svc.cluster.local",
+				ServerBlocks: "example.org:1053 {\n  whoami # say hi }\n}",
+			},
+		},
+		{
+			name:    "invalid upstream",
+			coreDNS: config.NetworkingCoreDNS{Upstream: []string{"dns.example.com"}},
+			wantErr: `networking.advanced.coreDNS.upstream[0]: expected an ip, ip:port or file path, but got "dns.example.com"`,
+		},
+		{
+			name:    "stub domain without resolvers",
+			coreDNS: config.NetworkingCoreDNS{StubDomains: []config.CoreDNSStubDomain{{Domain: "corp.example.com"}}},
+			wantErr: "networking.advanced.coreDNS.stubDomains[0].resolvers is required",
+		},
+		{
+			name: "duplicate stub domain",
+			coreDNS: config.NetworkingCoreDNS{StubDomains: []config.CoreDNSStubDomain{
+				{Domain: "corp.example.com", Resolvers: []string{"10.0.0.10"}},
+				{Domain: "corp.example.com.", Resolvers: []string{"10.0.0.11"}},
+			}},
+			wantErr: "networking.advanced.coreDNS.stubDomains has duplicate domain corp.example.com",
+		},
+		{
+			name:    "unbalanced server blocks",
+			coreDNS: config.NetworkingCoreDNS{ServerBlocks: "example.org:1053 {\n  whoami\n"},
+			wantErr: "networking.advanced.coreDNS.serverBlocks: unclosed {",
+		},
+		{
+			name:    "unbalanced plugins",
+			coreDNS: config.NetworkingCoreDNS{Plugins: "}"},
+			wantErr: "networking.advanced.coreDNS.plugins: unexpected }",
+		},
+		{
+			name:    "upstream and node local dns",
+			coreDNS: config.NetworkingCoreDNS{Upstream: []string{"1.1.1.1"}, NodeLocalDNS: config.NodeLocalDNS{Enabled: true, IP: "169.254.20.10"}},
+			wantErr: "networking.advanced.coreDNS.upstream cannot be used together with networking.advanced.coreDNS.nodeLocalDNS.enabled",
+		},
+		{
+			name:    "invalid node local dns ip",
+			coreDNS: config.NetworkingCoreDNS{NodeLocalDNS: config.NodeLocalDNS{Enabled: true}},
+			wantErr: `networking.advanced.coreDNS.nodeLocalDNS.ip: expected an ip, but got ""`,
+		},
+		{
+			name:    "overwritten config",
+			coreDNS: config.NetworkingCoreDNS{Upstream: []string{"1.1.1.1"}},
+			modify: func(c *VirtualClusterConfig) {
+				c.ControlPlane.CoreDNS.OverwriteConfig = ".:1053 {}"
+			},
+			wantErr: "networking.advanced.coreDNS cannot be used together with controlPlane.coredns.overwriteConfig",
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &VirtualClusterConfig{}
+			c.Networking.Advanced.CoreDNS = tt.coreDNS
+			if tt.modify != nil {
+				tt.modify(c)
+			}
+
+			err := validateCoreDNS(c)
+			if err != nil && (tt.wantErr == "" || tt.wantErr != err.Error()) {
+				t.Errorf("wanted err to be %s but got %s", tt.wantErr, err.Error())
+			} else if err == nil && tt.wantErr != "" {
+				t.Errorf("wanted err to be %s but got nil", tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateServiceAccountIssuer(t *testing.T) {
+	testCases := []struct {
+		name    string
+		issuer  config.ControlPlaneServiceAccountIssuer
+		distro  string
+		wantErr string
+	}{
+		{
+			name: "disabled",
+		},
+		{
+			name: "valid issuer with ingress",
+			issuer: config.ControlPlaneServiceAccountIssuer{
+				Enabled: true,
+				URL:     "https://oidc.example.com/my-vcluster",
+				Ingress: config.ServiceAccountIssuerIngress{Enabled: true},
+			},
+		},
+		{
+			name:    "http issuer",
+			issuer:  config.ControlPlaneServiceAccountIssuer{Enabled: true, URL: "http://oidc.example.com"},
+			wantErr: `controlPlane.advanced.serviceAccountIssuer.url: expected an https url without query or fragment, but got "http://oidc.example.com"`,
+		},
+		{
+			name:    "issuer with query",
+			issuer:  config.ControlPlaneServiceAccountIssuer{Enabled: true, URL: "https://oidc.example.com?a=b"},
+			wantErr: `controlPlane.advanced.serviceAccountIssuer.url: expected an https url without query or fragment, but got "https://oidc.example.com?a=b"`,
+		},
+		{
+			name:    "k0s",
+			issuer:  config.ControlPlaneServiceAccountIssuer{Enabled: true, URL: "https://oidc.example.com"},
+			distro:  config.K0SDistro,
+			wantErr: "controlPlane.advanced.serviceAccountIssuer.url is not supported with the k0s distro",
+		},
+		{
+			name:    "ingress without url",
+			issuer:  config.ControlPlaneServiceAccountIssuer{Enabled: true, Ingress: config.ServiceAccountIssuerIngress{Enabled: true}},
+			wantErr: "controlPlane.advanced.serviceAccountIssuer.ingress.enabled requires controlPlane.advanced.serviceAccountIssuer.enabled and controlPlane.advanced.serviceAccountIssuer.url",
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &VirtualClusterConfig{}
+			c.ControlPlane.Advanced.ServiceAccountIssuer = tt.issuer
+			if tt.distro == config.K0SDistro {
+				c.ControlPlane.Distro.K0S.Enabled = true
+			}
+
+			err := validateServiceAccountIssuer(c)
+			if err != nil && (tt.wantErr == "" || tt.wantErr != err.Error()) {
+				t.Errorf("wanted err to be %s but got %s", tt.wantErr, err.Error())
+			} else if err == nil && tt.wantErr != "" {
+				t.Errorf("wanted err to be %s but got nil", tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateNetworkIsolation(t *testing.T) {
+	testCases := []struct {
+		name             string
+		networkIsolation config.NetworkIsolation
+		networkPolicy    bool
+		wantErr          string
+	}{
+		{
+			name:             "disabled with invalid cidr",
+			networkIsolation: config.NetworkIsolation{Egress: config.NetworkIsolationEgress{IPBlocks: []config.IPBlock{{CIDR: "invalid"}}}},
+		},
+		{
+			name: "valid",
+			networkIsolation: config.NetworkIsolation{
+				Enabled: true,
+				Egress:  config.NetworkIsolationEgress{IPBlocks: []config.IPBlock{{CIDR: "0.0.0.0/0", Except: []string{"10.0.0.0/8"}}}, FQDNs: []string{"api.github.com", "*.github.com"}, FQDNProvider: "cilium"},
+				Ingress: config.NetworkIsolationIngress{Namespaces: []string{"ingress-nginx"}, IPBlocks: []config.IPBlock{{CIDR: "2001:db8::/64"}}},
+			},
+		},
+		{
+			name:             "invalid cidr",
+			networkIsolation: config.NetworkIsolation{Enabled: true, Egress: config.NetworkIsolationEgress{IPBlocks: []config.IPBlock{{CIDR: "10.0.0.0"}}}},
+			wantErr:          `policies.networkIsolation.egress.ipBlocks[0].cidr: invalid cidr "10.0.0.0"`,
+		},
+		{
+			name:             "except outside of cidr",
+			networkIsolation: config.NetworkIsolation{Enabled: true, Ingress: config.NetworkIsolationIngress{IPBlocks: []config.IPBlock{{CIDR: "10.0.0.0/8", Except: []string{"192.168.0.0/16"}}}}},
+			wantErr:          "policies.networkIsolation.ingress.ipBlocks[0].except: 192.168.0.0/16 is outside of 10.0.0.0/8",
+		},
+		{
+			name:             "invalid namespace",
+			networkIsolation: config.NetworkIsolation{Enabled: true, Ingress: config.NetworkIsolationIngress{Namespaces: []string{"Ingress"}}},
+			wantErr:          `policies.networkIsolation.ingress.namespaces: invalid namespace "Ingress"`,
+		},
+		{
+			name:             "invalid fqdn",
+			networkIsolation: config.NetworkIsolation{Enabled: true, Egress: config.NetworkIsolationEgress{FQDNs: []string{"git*.com"}}},
+			wantErr:          `policies.networkIsolation.egress.fqdns: invalid domain "git*.com", expected a domain name like api.github.com or *.github.com`,
+		},
+		{
+			name:             "network policy enabled",
+			networkIsolation: config.NetworkIsolation{Enabled: true},
+			networkPolicy:    true,
+			wantErr:          "policies.networkIsolation and policies.networkPolicy cannot be enabled at the same time",
+		},
+		{
+			name:             "disabled with network policy",
+			networkIsolation: config.NetworkIsolation{},
+			networkPolicy:    true,
+		},
+		{
+			name:             "invalid fqdn provider",
+			networkIsolation: config.NetworkIsolation{Enabled: true, Egress: config.NetworkIsolationEgress{FQDNProvider: "antrea"}},
+			wantErr:          `policies.networkIsolation.egress.fqdnProvider: invalid provider "antrea", must be one of: cilium, calico`,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateNetworkIsolation(config.Policies{NetworkIsolation: tt.networkIsolation, NetworkPolicy: config.NetworkPolicy{Enabled: tt.networkPolicy}})
+			if err != nil && (tt.wantErr == "" || !strings.HasPrefix(err.Error(), tt.wantErr)) {
+				t.Errorf("wanted err to be %s but got %s", tt.wantErr, err.Error())
+			} else if err == nil && tt.wantErr != "" {
+				t.Errorf("wanted err to be %s but got nil", tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateHostStorageClasses(t *testing.T) {
+	testCases := []struct {
+		name           string
+		storageClasses config.SyncFromHostStorageClasses
+		wantErr        string
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name:           "default without mappings",
+			storageClasses: config.SyncFromHostStorageClasses{Default: "standard"},
+		},
+		{
+			name: "valid",
+			storageClasses: config.SyncFromHostStorageClasses{
+				Mappings: []config.StorageClassMapping{{From: "gp3-encrypted", To: "standard"}, {From: "io2", To: "fast"}},
+				Default:  "fast",
+			},
+		},
+		{
+			name:           "missing to",
+			storageClasses: config.SyncFromHostStorageClasses{Mappings: []config.StorageClassMapping{{From: "gp3"}}},
+			wantErr:        "sync.fromHost.storageClasses.mappings[0]: from and to are required",
+		},
+		{
+			name:           "duplicate from",
+			storageClasses: config.SyncFromHostStorageClasses{Mappings: []config.StorageClassMapping{{From: "gp3", To: "a"}, {From: "gp3", To: "b"}}},
+			wantErr:        `sync.fromHost.storageClasses.mappings[1]: host storage class "gp3" is mapped more than once`,
+		},
+		{
+			name:           "duplicate to",
+			storageClasses: config.SyncFromHostStorageClasses{Mappings: []config.StorageClassMapping{{From: "gp2", To: "a"}, {From: "gp3", To: "a"}}},
+			wantErr:        `sync.fromHost.storageClasses.mappings[1]: virtual storage class "a" is mapped more than once`,
+		},
+		{
+			name: "default not mapped",
+			storageClasses: config.SyncFromHostStorageClasses{
+				Mappings: []config.StorageClassMapping{{From: "gp3", To: "standard"}},
+				Default:  "gp3",
+			},
+			wantErr: `sync.fromHost.storageClasses.default: storage class "gp3" is not synced, it must be the target of one of the mappings`,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateHostStorageClasses(tt.storageClasses)
+			if err != nil && (tt.wantErr == "" || tt.wantErr != err.Error()) {
+				t.Errorf("wanted err to be %s but got %s", tt.wantErr, err.Error())
+			} else if err == nil && tt.wantErr != "" {
+				t.Errorf("wanted err to be %s but got nil", tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateSharding(t *testing.T) {
+	testCases := []struct {
+		name     string
+		sharding config.ExperimentalSharding
+		replicas int32
+		wantBy   string
+		wantErr  string
+	}{
+		{
+			name:     "disabled",
+			replicas: 1,
+			wantBy:   ShardByNamespace,
+		},
+		{
+			name:     "enabled",
+			sharding: config.ExperimentalSharding{Enabled: true, By: ShardByResource},
+			replicas: 3,
+			wantBy:   ShardByResource,
+		},
+		{
+			name:     "invalid by",
+			sharding: config.ExperimentalSharding{By: "hash"},
+			replicas: 3,
+			wantErr:  `experimental.syncSettings.sharding.by "hash" is invalid, must be one of: namespace, resource`,
+		},
+		{
+			name:     "single replica",
+			sharding: config.ExperimentalSharding{Enabled: true},
+			replicas: 1,
+			wantErr:  "experimental.syncSettings.sharding.enabled requires controlPlane.statefulSet.highAvailability.replicas to be greater than 1",
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			vConfig := &VirtualClusterConfig{}
+			vConfig.Experimental.SyncSettings.Sharding = tt.sharding
+			vConfig.ControlPlane.StatefulSet.HighAvailability.Replicas = tt.replicas
+			err := validateSharding(vConfig)
+			if err != nil && (tt.wantErr == "" || tt.wantErr != err.Error()) {
+				t.Errorf("wanted err to be %s but got %s", tt.wantErr, err.Error())
+			} else if err == nil && tt.wantErr != "" {
+				t.Errorf("wanted err to be %s but got nil", tt.wantErr)
+			} else if err == nil && vConfig.Experimental.SyncSettings.Sharding.By != tt.wantBy {
+				t.Errorf("wanted by to be %s but got %s", tt.wantBy, vConfig.Experimental.SyncSettings.Sharding.By)
+			}
+		})
+	}
+}
+
+func TestValidateDensity(t *testing.T) {
+	testCases := []struct {
+		name    string
+		density config.ControlPlaneDensity
+		wantErr string
+	}{
+		{
+			name: "disabled",
+		},
+		{
+			name:    "valid",
+			density: config.ControlPlaneDensity{Enabled: true, ProbePeriodSeconds: 10},
+		},
+		{
+			name:    "zero probe period",
+			density: config.ControlPlaneDensity{Enabled: true},
+			wantErr: "controlPlane.advanced.density.probePeriodSeconds must be greater than 0",
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateDensity(tt.density)
+			if err != nil && (tt.wantErr == "" || tt.wantErr != err.Error()) {
+				t.Errorf("wanted err to be %s but got %s", tt.wantErr, err.Error())
+			} else if err == nil && tt.wantErr != "" {
+				t.Errorf("wanted err to be %s but got nil", tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateCatchUp(t *testing.T) {
+	testCases := []struct {
+		name    string
+		catchUp config.ExperimentalCatchUp
+		wantErr string
+	}{
+		{
+			name: "disabled",
+		},
+		{
+			name:    "valid",
+			catchUp: config.ExperimentalCatchUp{Enabled: true, HighPriority: []string{"pods"}, LowPriority: []string{"events"}, Timeout: 300},
+		},
+		{
+			name:    "missing timeout",
+			catchUp: config.ExperimentalCatchUp{Enabled: true, HighPriority: []string{"pods"}},
+			wantErr: "experimental.syncSettings.catchUp.timeout must be greater than 0",
+		},
+		{
+			name:    "high and low",
+			catchUp: config.ExperimentalCatchUp{Enabled: true, HighPriority: []string{"pods", "configmaps"}, LowPriority: []string{"ConfigMaps"}, Timeout: 300},
+			wantErr: `experimental.syncSettings.catchUp: resource "ConfigMaps" cannot be high and low priority`,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateCatchUp(tt.catchUp)
+			if err != nil && (tt.wantErr == "" || tt.wantErr != err.Error()) {
+				t.Errorf("wanted err to be %s but got %s", tt.wantErr, err.Error())
+			} else if err == nil && tt.wantErr != "" {
+				t.Errorf("wanted err to be %s but got nil", tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidatePodRestrictions(t *testing.T) {
+	testCases := []struct {
+		name            string
+		podRestrictions config.PodRestrictions
+		wantErr         string
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name: "valid",
+			podRestrictions: config.PodRestrictions{
+				HostPath:   config.PodRestrictionHostPath{PodRestrictionRule: config.PodRestrictionRule{Action: "rewrite"}, AllowedPaths: []string{"/var/log"}},
+				Privileged: config.PodRestrictionRule{Action: "deny", AllowedNamespaces: []string{"kube-system"}},
+			},
+		},
+		{
+			name:            "invalid action",
+			podRestrictions: config.PodRestrictions{HostNamespaces: config.PodRestrictionRule{Action: "drop"}},
+			wantErr:         `policies.podRestrictions.hostNamespaces.action: invalid action "drop", must be one of: deny, rewrite`,
+		},
+		{
+			name:            "relative path",
+			podRestrictions: config.PodRestrictions{HostPath: config.PodRestrictionHostPath{AllowedPaths: []string{"var/log"}}},
+			wantErr:         `policies.podRestrictions.hostPath.allowedPaths: "var/log" is not an absolute path`,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validatePodRestrictions(tt.podRestrictions)
+			if err != nil && (tt.wantErr == "" || tt.wantErr != err.Error()) {
+				t.Errorf("wanted err to be %s but got %s", tt.wantErr, err.Error())
+			} else if err == nil && tt.wantErr != "" {
+				t.Errorf("wanted err to be %s but got nil", tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidatePodSecurityStandard(t *testing.T) {
+	testCases := []struct {
+		name        string
+		podSecurity config.PodSecurityStandard
+		wantErr     string
+	}{
+		{
+			name:        "disabled with invalid level",
+			podSecurity: config.PodSecurityStandard{Defaults: config.PodSecurityStandardDefaults{Enforce: "strict"}},
+		},
+		{
+			name: "valid",
+			podSecurity: config.PodSecurityStandard{
+				Enabled:    true,
+				Defaults:   config.PodSecurityStandardDefaults{Enforce: "baseline", EnforceVersion: "v1.30", Warn: "restricted", WarnVersion: "latest"},
+				Exemptions: config.PodSecurityStandardExemptions{Namespaces: []string{"kube-system"}},
+			},
+		},
+		{
+			name:        "invalid level",
+			podSecurity: config.PodSecurityStandard{Enabled: true, Defaults: config.PodSecurityStandardDefaults{Audit: "strict"}},
+			wantErr:     `policies.podSecurityStandard.defaults.audit: invalid level "strict", must be one of: privileged, baseline, restricted`,
+		},
+		{
+			name:        "invalid version",
+			podSecurity: config.PodSecurityStandard{Enabled: true, Defaults: config.PodSecurityStandardDefaults{WarnVersion: "1.30"}},
+			wantErr:     `policies.podSecurityStandard.defaults.warnVersion: invalid version "1.30", must be latest or of the form v1.x`,
+		},
+		{
+			name:        "invalid namespace",
+			podSecurity: config.PodSecurityStandard{Enabled: true, Exemptions: config.PodSecurityStandardExemptions{Namespaces: []string{"Kube_System"}}},
+			wantErr:     `policies.podSecurityStandard.exemptions.namespaces: invalid namespace "Kube_System"`,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validatePodSecurityStandard(tt.podSecurity)
+			if err != nil && (tt.wantErr == "" || !strings.HasPrefix(err.Error(), tt.wantErr)) {
+				t.Errorf("wanted err to be %s but got %s", tt.wantErr, err.Error())
+			} else if err == nil && tt.wantErr != "" {
+				t.Errorf("wanted err to be %s but got nil", tt.wantErr)
+			}
+		})
+	}
+}
+
 func valHook(clientCfg config.ValidatingWebhookClientConfig) config.ValidatingWebhookConfiguration {
 	hook := config.ValidatingWebhookConfiguration{}
 	hook.APIVersion = "v1"
@@ -125,3 +593,41 @@ func mutHook(clientCfg config.ValidatingWebhookClientConfig) config.MutatingWebh
 	}
 	return hook
 }
+
+func TestValidateIsolatedControlPlaneKonnectivity(t *testing.T) {
+	testCases := []struct {
+		name    string
+		distro  func(*VirtualClusterConfig)
+		wantErr string
+	}{
+		{
+			name:   "k8s",
+			distro: func(c *VirtualClusterConfig) { c.ControlPlane.Distro.K8S.Enabled = true },
+		},
+		{
+			name:    "k3s",
+			distro:  func(c *VirtualClusterConfig) { c.ControlPlane.Distro.K3S.Enabled = true },
+			wantErr: "experimental.isolatedControlPlane.konnectivity.enabled is only supported with the k8s distro",
+		},
+		{
+			name:    "k0s",
+			distro:  func(c *VirtualClusterConfig) { c.ControlPlane.Distro.K0S.Enabled = true },
+			wantErr: "experimental.isolatedControlPlane.konnectivity.enabled is only supported with the k8s distro",
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			vConfig := &VirtualClusterConfig{}
+			vConfig.Experimental.IsolatedControlPlane.Enabled = true
+			vConfig.Experimental.IsolatedControlPlane.KubeConfig = "/data/workload/kubeconfig.yaml"
+			vConfig.Experimental.IsolatedControlPlane.Konnectivity.Enabled = true
+			tt.distro(vConfig)
+			err := validateIsolatedControlPlane(vConfig)
+			if err != nil && (tt.wantErr == "" || tt.wantErr != err.Error()) {
+				t.Errorf("wanted err to be %s but got %s", tt.wantErr, err.Error())
+			} else if err == nil && tt.wantErr != "" {
+				t.Errorf("wanted err to be %s but got nil", tt.wantErr)
+			}
+		})
+	}
+}
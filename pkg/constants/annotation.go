@@ -4,6 +4,12 @@ const (
 	SkipTranslationAnnotation = "vcluster.loft.sh/skip-translate"
 	SyncResourceAnnotation    = "vcluster.loft.sh/force-sync"
 
+	// ValuesChecksumAnnotation holds the checksum of the helm values last deployed by the vcluster CLI
+	ValuesChecksumAnnotation = "vcluster.loft.sh/values-checksum"
+
+	// PausedSyncersAnnotation is set on the vc-config secret and lists the resources whose syncers are paused at runtime
+	PausedSyncersAnnotation = "vcluster.loft.sh/paused-syncers"
+
 	PausedAnnotation         = "loft.sh/paused"
 	PausedReplicasAnnotation = "loft.sh/paused-replicas"
 	PausedDateAnnotation     = "loft.sh/paused-date"
@@ -0,0 +1,14 @@
+package constants
+
+const (
+	// HostCleanupFinalizer is added to virtual objects that were synced to the host cluster and is removed after the
+	// host object is deleted
+	HostCleanupFinalizer = "vcluster.loft.sh/host-cleanup"
+
+	// VirtualCleanupFinalizer is added to host objects that were imported into the virtual cluster and is removed after
+	// the virtual object is deleted
+	VirtualCleanupFinalizer = "vcluster.loft.sh/virtual-cleanup"
+)
+
+// SyncFinalizers are the finalizers vCluster adds to order the deletion of virtual and host objects
+var SyncFinalizers = []string{HostCleanupFinalizer, VirtualCleanupFinalizer}
@@ -0,0 +1,245 @@
+package coredns
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/loft-sh/vcluster/pkg/constants"
+	"github.com/loft-sh/vcluster/pkg/util/loghelper"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	PlatformHostsKey = "PlatformHosts"
+
+	// PlatformDNSLabel marks the registrations of the virtual clusters within the platform dns namespace
+	PlatformDNSLabel = "vcluster.loft.sh/platform-dns"
+
+	// PlatformDNSRenewAnnotation holds the last time a virtual cluster renewed its registration
+	PlatformDNSRenewAnnotation = "vcluster.loft.sh/platform-dns-renew-time"
+
+	// PlatformDNSNameAnnotation and PlatformDNSNamespaceAnnotation identify the virtual cluster that owns a registration
+	PlatformDNSNameAnnotation      = "vcluster.loft.sh/platform-dns-name"
+	PlatformDNSNamespaceAnnotation = "vcluster.loft.sh/platform-dns-namespace"
+
+	platformDNSHostsKey = "hosts"
+
+	// registrations that were not renewed for this long belong to deleted virtual clusters and are ignored
+	platformDNSRegistrationTimeout = 5 * time.Minute
+	platformDNSRenewInterval       = time.Minute
+	platformDNSResyncInterval      = 15 * time.Second
+)
+
+// PlatformHostsReconciler registers the services of the virtual cluster within the platform dns namespace and keeps
+// the PlatformHosts of the CoreDNS config up to date with the services of all registered virtual clusters.
+type PlatformHostsReconciler struct {
+	client.Client
+
+	// HostClient is used to read and write the registrations within the platform dns namespace
+	HostClient client.Client
+
+	// Name and Namespace identify the virtual cluster within the host cluster
+	Name      string
+	Namespace string
+
+	// Domain is the shared domain the services are registered under
+	Domain string
+
+	// RegistryNamespace is the host namespace that holds the registrations
+	RegistryNamespace string
+
+	Log loghelper.Logger
+}
+
+func (r *PlatformHostsReconciler) Reconcile(ctx context.Context, _ ctrl.Request) (ctrl.Result, error) {
+	services := &corev1.ServiceList{}
+	err := r.Client.List(ctx, services)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	err = r.register(ctx, PlatformHostEntries(services.Items, r.Name, r.Namespace, r.Domain))
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("register at platform dns: %w", err)
+	}
+
+	registrations := &corev1.ConfigMapList{}
+	err = r.HostClient.List(ctx, registrations, client.InNamespace(r.RegistryNamespace), client.MatchingLabels{PlatformDNSLabel: "true"})
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("list platform dns registrations: %w", err)
+	}
+
+	// update the hosts of the coredns configmap preserving other data keys
+	configmap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+		Namespace: Namespace,
+		Name:      ConfigMapName,
+	}}
+	err = r.Client.Get(ctx, client.ObjectKeyFromObject(configmap), configmap)
+	if kerrors.IsNotFound(err) {
+		r.Log.Debugf("%s/%s Configmap not found, CoreDNS is not fully configured", ConfigMapName, Namespace)
+		return ctrl.Result{RequeueAfter: time.Second}, nil
+	} else if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	platformHosts := MergePlatformHosts(registrations.Items, r.Domain, time.Now())
+	if configmap.Data[PlatformHostsKey] != platformHosts {
+		beforeChanges := configmap.DeepCopy()
+		if configmap.Data == nil {
+			configmap.Data = map[string]string{}
+		}
+		configmap.Data[PlatformHostsKey] = platformHosts
+		err = r.Client.Patch(ctx, configmap, client.MergeFrom(beforeChanges))
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	// registrations of other virtual clusters are not watched, so check them regularly
+	return ctrl.Result{RequeueAfter: platformDNSResyncInterval}, nil
+}
+
+func (r *PlatformHostsReconciler) register(ctx context.Context, hosts string) error {
+	registration := &corev1.ConfigMap{}
+	err := r.HostClient.Get(ctx, types.NamespacedName{Namespace: r.RegistryNamespace, Name: PlatformDNSRegistrationName(r.Name, r.Namespace)}, registration)
+	if err != nil && !kerrors.IsNotFound(err) {
+		return err
+	} else if kerrors.IsNotFound(err) {
+		r.Log.Infof("register virtual cluster at platform dns namespace %s", r.RegistryNamespace)
+		return r.HostClient.Create(ctx, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: r.RegistryNamespace,
+				Name:      PlatformDNSRegistrationName(r.Name, r.Namespace),
+				Labels:    map[string]string{PlatformDNSLabel: "true"},
+				Annotations: map[string]string{
+					PlatformDNSRenewAnnotation:     time.Now().UTC().Format(time.RFC3339),
+					PlatformDNSNameAnnotation:      r.Name,
+					PlatformDNSNamespaceAnnotation: r.Namespace,
+				},
+			},
+			Data: map[string]string{platformDNSHostsKey: hosts},
+		})
+	}
+
+	renewTime, err := time.Parse(time.RFC3339, registration.Annotations[PlatformDNSRenewAnnotation])
+	if registration.Data[platformDNSHostsKey] == hosts && err == nil && time.Since(renewTime) < platformDNSRenewInterval && registration.Annotations[PlatformDNSNameAnnotation] == r.Name && registration.Annotations[PlatformDNSNamespaceAnnotation] == r.Namespace {
+		return nil
+	}
+
+	beforeChanges := registration.DeepCopy()
+	if registration.Annotations == nil {
+		registration.Annotations = map[string]string{}
+	}
+	if registration.Data == nil {
+		registration.Data = map[string]string{}
+	}
+	registration.Annotations[PlatformDNSRenewAnnotation] = time.Now().UTC().Format(time.RFC3339)
+	registration.Annotations[PlatformDNSNameAnnotation] = r.Name
+	registration.Annotations[PlatformDNSNamespaceAnnotation] = r.Namespace
+	registration.Data[platformDNSHostsKey] = hosts
+	return r.HostClient.Patch(ctx, registration, client.MergeFrom(beforeChanges))
+}
+
+// PlatformDNSRegistrationName returns the name of the registration of a virtual cluster within the platform dns namespace
+func PlatformDNSRegistrationName(name, namespace string) string {
+	return translate.SafeConcatName("vc", "dns", name, "x", namespace)
+}
+
+// PlatformHostEntries returns the hosts entries of the services of a virtual cluster. The cluster ips of the virtual
+// services are the cluster ips of the synced host services, so they are reachable from the other virtual clusters.
+func PlatformHostEntries(services []corev1.Service, vClusterName, vClusterNamespace, domain string) string {
+	entries := []string{}
+	for _, service := range services {
+		if service.Spec.ClusterIP == "" || service.Spec.ClusterIP == corev1.ClusterIPNone {
+			continue
+		}
+
+		entries = append(entries, fmt.Sprintf("%s %s.%s%s", service.Spec.ClusterIP, service.Name, service.Namespace, platformHostSuffix(vClusterName, vClusterNamespace, domain)))
+	}
+
+	sort.Strings(entries)
+	return strings.Join(entries, "\n")
+}
+
+// MergePlatformHosts merges the hosts entries of all registrations that were renewed recently. A registration may only
+// contain entries of the virtual cluster that owns it, all other entries are dropped.
+func MergePlatformHosts(registrations []corev1.ConfigMap, domain string, now time.Time) string {
+	entries := []string{}
+	for _, registration := range registrations {
+		renewTime, err := time.Parse(time.RFC3339, registration.Annotations[PlatformDNSRenewAnnotation])
+		if err != nil || now.Sub(renewTime) > platformDNSRegistrationTimeout {
+			continue
+		}
+
+		// the owner is only trusted if the registration has the name the owner would use, which only the owner can update
+		name, namespace := registration.Annotations[PlatformDNSNameAnnotation], registration.Annotations[PlatformDNSNamespaceAnnotation]
+		if name == "" || namespace == "" || registration.Name != PlatformDNSRegistrationName(name, namespace) {
+			continue
+		}
+
+		suffix := platformHostSuffix(name, namespace, domain)
+		for _, entry := range strings.Split(registration.Data[platformDNSHostsKey], "\n") {
+			fields := strings.Fields(entry)
+			if len(fields) != 2 || net.ParseIP(fields[0]) == nil {
+				continue
+			}
+
+			// only <service>.<namespace> may precede the suffix of the owner
+			host, ok := strings.CutSuffix(fields[1], suffix)
+			if !ok || strings.Count(host, ".") != 1 {
+				continue
+			}
+
+			entries = append(entries, fields[0]+" "+fields[1])
+		}
+	}
+
+	sort.Strings(entries)
+	return strings.Join(entries, "\n")
+}
+
+// platformHostSuffix returns the suffix of the host names of a virtual cluster, which contains the host namespace, so
+// virtual clusters with the same name in different host namespaces don't collide
+func platformHostSuffix(vClusterName, vClusterNamespace, domain string) string {
+	return fmt.Sprintf(".%s.%s.%s", vClusterName, vClusterNamespace, strings.TrimSuffix(domain, "."))
+}
+
+// SetupWithManager adds the controller to the manager
+func (r *PlatformHostsReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	// creating a predicate to receive reconcile requests for coredns ConfigMap only
+	p := func(object client.Object) bool {
+		return object.GetNamespace() == Namespace && object.GetName() == ConfigMapName
+	}
+	funcs := predicate.NewPredicateFuncs(p)
+
+	// use modified handler to avoid triggering reconcile for each Service
+	eventHandler := handler.EnqueueRequestsFromMapFunc(func(_ context.Context, _ client.Object) []reconcile.Request {
+		return []reconcile.Request{{
+			NamespacedName: types.NamespacedName{Namespace: Namespace, Name: ConfigMapName},
+		}}
+	})
+
+	return ctrl.NewControllerManagedBy(mgr).
+		WithOptions(controller.Options{
+			CacheSyncTimeout: constants.DefaultCacheSyncTimeout,
+		}).
+		Named("coredns_platformhosts").
+		For(&corev1.ConfigMap{}, builder.WithPredicates(funcs, predicate.ResourceVersionChangedPredicate{})).
+		Watches(&corev1.Service{}, eventHandler).
+		Complete(r)
+}
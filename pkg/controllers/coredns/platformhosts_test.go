@@ -0,0 +1,62 @@
+package coredns
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPlatformHostEntries(t *testing.T) {
+	services := []corev1.Service{
+		{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop"}, Spec: corev1.ServiceSpec{ClusterIP: "10.96.0.20"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "shop"}, Spec: corev1.ServiceSpec{ClusterIP: "10.96.0.10"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "headless", Namespace: "shop"}, Spec: corev1.ServiceSpec{ClusterIP: corev1.ClusterIPNone}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "external", Namespace: "shop"}, Spec: corev1.ServiceSpec{Type: corev1.ServiceTypeExternalName}},
+	}
+
+	assert.Equal(t, PlatformHostEntries(services, "frontend", "team-a", "svc.platform.local."), "10.96.0.10 db.shop.frontend.team-a.svc.platform.local\n10.96.0.20 web.shop.frontend.team-a.svc.platform.local")
+}
+
+func TestMergePlatformHosts(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	registration := func(name, namespace string, renewTime time.Time, hosts string) corev1.ConfigMap {
+		return corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: PlatformDNSRegistrationName(name, namespace),
+				Annotations: map[string]string{
+					PlatformDNSRenewAnnotation:     renewTime.Format(time.RFC3339),
+					PlatformDNSNameAnnotation:      name,
+					PlatformDNSNamespaceAnnotation: namespace,
+				},
+			},
+			Data: map[string]string{platformDNSHostsKey: hosts},
+		}
+	}
+
+	spoofed := registration("frontend", "team-a", now, "10.96.0.50 web.shop.frontend.team-a.svc.platform.local")
+	spoofed.Name = PlatformDNSRegistrationName("attacker", "team-c")
+
+	registrations := []corev1.ConfigMap{
+		registration("frontend", "team-a", now.Add(-time.Minute), "10.96.0.20 web.shop.frontend.team-a.svc.platform.local"),
+		registration("backend", "team-b", now.Add(-2*time.Minute), "10.96.0.10 db.shop.backend.team-b.svc.platform.local\n10.96.0.11 cache.shop.backend.team-b.svc.platform.local"),
+		registration("deleted", "team-a", now.Add(-time.Hour), "10.96.0.30 old.shop.deleted.team-a.svc.platform.local"),
+		{Data: map[string]string{platformDNSHostsKey: "10.96.0.40 broken.shop.unknown.team-a.svc.platform.local"}},
+		// entries outside of the own suffix, with additional names or without an ip are dropped
+		registration("attacker", "team-c", now, strings.Join([]string{
+			"10.96.0.60 web.shop.frontend.team-a.svc.platform.local",
+			"10.96.0.61 api.shop.attacker.team-c.svc.platform.local web.shop.frontend.team-a.svc.platform.local",
+			"10.96.0.62 a.b.shop.attacker.team-c.svc.platform.local",
+			"invalid api.shop.attacker.team-c.svc.platform.local",
+			"10.96.0.63 api.shop.attacker.team-c.svc.platform.local",
+		}, "\n")),
+		// registrations that claim another owner than their name are ignored
+		spoofed,
+	}
+
+	assert.Equal(t, MergePlatformHosts(registrations, "svc.platform.local", now), "10.96.0.10 db.shop.backend.team-b.svc.platform.local\n10.96.0.11 cache.shop.backend.team-b.svc.platform.local\n10.96.0.20 web.shop.frontend.team-a.svc.platform.local\n10.96.0.63 api.shop.attacker.team-c.svc.platform.local")
+	assert.Equal(t, MergePlatformHosts(nil, "svc.platform.local", now), "")
+}
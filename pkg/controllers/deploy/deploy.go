@@ -569,7 +569,7 @@ func (r *Deployer) rollbackOrUninstall(ctx context.Context, chartName, namespace
 	}
 
 	r.Log.Infof("rolling back release %s", chartName)
-	err = r.HelmClient.Rollback(ctx, chartName, namespace)
+	err = r.HelmClient.Rollback(ctx, chartName, namespace, 0)
 	if err != nil {
 		r.Log.Errorf("error rolling back release %s", chartName)
 		return err
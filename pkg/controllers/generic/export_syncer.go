@@ -8,6 +8,9 @@ import (
 	"time"
 
 	"github.com/loft-sh/vcluster/pkg/config"
+	"github.com/loft-sh/vcluster/pkg/integrations/certmanager"
+	"github.com/loft-sh/vcluster/pkg/integrations/externalsecrets"
+	"github.com/loft-sh/vcluster/pkg/integrations/istio"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/klog/v2"
 
@@ -33,15 +36,17 @@ import (
 )
 
 func CreateExporters(ctx *config.ControllerContext) error {
-	exporterConfig := ctx.Config.Experimental.GenericSync
-	if len(exporterConfig.Exports) == 0 {
+	exports := append(istio.Exports(ctx.Config.Integrations.Istio), certmanager.Exports(ctx.Config.Integrations.CertManager)...)
+	exports = append(exports, externalsecrets.Exports(ctx.Config.Integrations.ExternalSecrets)...)
+	exports = append(exports, ctx.Config.Experimental.GenericSync.Exports...)
+	if len(exports) == 0 {
 		return nil
 	}
 
 	scheme := ctx.LocalManager.GetScheme()
 	registerCtx := util.ToRegisterContext(ctx)
 
-	for _, exportConfig := range exporterConfig.Exports {
+	for _, exportConfig := range exports {
 		gvk := schema.FromAPIVersionAndKind(exportConfig.APIVersion, exportConfig.Kind)
 		if !scheme.Recognizes(gvk) {
 			_, _, err := translate.EnsureCRDFromPhysicalCluster(
@@ -119,10 +124,30 @@ func createExporter(ctx *synccontext.RegisterContext, config *vclusterconfig.Exp
 		gvk:      gvk,
 		config:   config,
 		selector: selector,
+		validate: exportValidator(ctx, gvk),
 		name:     controllerID,
 	}, nil
 }
 
+// exportValidator returns the check of the integration that exports the given kind, objects that fail the check are
+// not synced to the host cluster
+func exportValidator(ctx *synccontext.RegisterContext, gvk schema.GroupVersionKind) func(*unstructured.Unstructured) error {
+	if ctx.Config == nil {
+		return nil
+	}
+
+	if certmanager.IsExported(ctx.Config.Integrations.CertManager, gvk.GroupVersion().String(), gvk.Kind) && gvk.Kind == certmanager.IssuerKind {
+		return certmanager.Validate
+	}
+	if externalsecrets.IsExported(ctx.Config.Integrations.ExternalSecrets, gvk.GroupVersion().String(), gvk.Kind) {
+		return func(obj *unstructured.Unstructured) error {
+			return externalsecrets.Validate(ctx.Config.Integrations.ExternalSecrets, obj)
+		}
+	}
+
+	return nil
+}
+
 type exporter struct {
 	translator.NamespacedTranslator
 
@@ -130,6 +155,7 @@ type exporter struct {
 	gvk      schema.GroupVersionKind
 	config   *vclusterconfig.Export
 	selector labels.Selector
+	validate func(*unstructured.Unstructured) error
 	name     string
 }
 
@@ -137,6 +163,9 @@ func (f *exporter) SyncToHost(ctx *synccontext.SyncContext, vObj client.Object)
 	// check if selector matches
 	if !f.objectMatches(vObj) {
 		return ctrl.Result{}, nil
+	} else if err := f.validateObject(vObj); err != nil {
+		f.EventRecorder().Eventf(vObj, "Warning", "SyncError", "Not syncing to physical cluster: %v", err)
+		return ctrl.Result{}, nil
 	}
 
 	// apply object to physical cluster
@@ -188,6 +217,10 @@ func (f *exporter) Sync(ctx *synccontext.SyncContext, pObj client.Object, vObj c
 		}
 
 		return ctrl.Result{}, nil
+	} else if err := f.validateObject(vObj); err != nil {
+		f.EventRecorder().Eventf(vObj, "Warning", "SyncError", "Not syncing to physical cluster: %v", err)
+		ctx.Log.Infof("delete physical %s %s/%s, because the virtual object is not valid anymore: %v", f.config.Kind, pObj.GetNamespace(), pObj.GetName(), err)
+		return ctrl.Result{}, ctx.PhysicalClient.Delete(ctx.Context, pObj)
 	}
 
 	// check if either object is getting deleted
@@ -299,6 +332,15 @@ func (f *exporter) objectMatches(obj client.Object) bool {
 	return f.selector == nil || f.selector.Matches(labels.Set(obj.GetLabels()))
 }
 
+func (f *exporter) validateObject(obj client.Object) error {
+	unstructuredObj, ok := obj.(*unstructured.Unstructured)
+	if f.validate == nil || !ok {
+		return nil
+	}
+
+	return f.validate(unstructuredObj)
+}
+
 type virtualToHostNameResolver struct {
 	namespace       string
 	targetNamespace string
@@ -317,7 +359,7 @@ func (r *virtualToHostNameResolver) TranslateNameWithNamespace(name string, name
 			}
 
 			return types.NamespacedName{
-				Namespace: translate.Default.PhysicalNamespace(namespace),
+				Namespace: translate.Default.PhysicalNamespace(ns),
 				Name:      translate.Default.PhysicalName(name, ns),
 			}
 		}), nil
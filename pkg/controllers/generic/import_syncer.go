@@ -91,6 +91,7 @@ func createImporter(ctx *synccontext.RegisterContext, config *vclusterconfig.Imp
 
 	syncerOptions := &syncertypes.Options{
 		DisableUIDDeletion: true,
+		ObjectsFromHost:    true,
 	}
 
 	if scopeAndSubresource, ok := gvkRegister[gvk]; ok {
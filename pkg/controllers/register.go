@@ -15,6 +15,7 @@ import (
 	"github.com/loft-sh/vcluster/pkg/controllers/resources/csistoragecapacities"
 	"github.com/loft-sh/vcluster/pkg/controllers/resources/endpoints"
 	"github.com/loft-sh/vcluster/pkg/controllers/resources/events"
+	"github.com/loft-sh/vcluster/pkg/controllers/resources/hostmirrors"
 	"github.com/loft-sh/vcluster/pkg/controllers/resources/ingressclasses"
 	"github.com/loft-sh/vcluster/pkg/controllers/resources/ingresses"
 	"github.com/loft-sh/vcluster/pkg/controllers/resources/namespaces"
@@ -25,6 +26,7 @@ import (
 	"github.com/loft-sh/vcluster/pkg/controllers/resources/poddisruptionbudgets"
 	"github.com/loft-sh/vcluster/pkg/controllers/resources/pods"
 	"github.com/loft-sh/vcluster/pkg/controllers/resources/priorityclasses"
+	"github.com/loft-sh/vcluster/pkg/controllers/resources/runtimeclasses"
 	"github.com/loft-sh/vcluster/pkg/controllers/resources/secrets"
 	"github.com/loft-sh/vcluster/pkg/controllers/resources/serviceaccounts"
 	"github.com/loft-sh/vcluster/pkg/controllers/resources/storageclasses"
@@ -33,12 +35,16 @@ import (
 	"github.com/loft-sh/vcluster/pkg/controllers/resources/volumesnapshots/volumesnapshots"
 	"github.com/loft-sh/vcluster/pkg/controllers/servicesync"
 	"github.com/loft-sh/vcluster/pkg/controllers/syncer"
+	"github.com/loft-sh/vcluster/pkg/integrations/certmanager"
+	"github.com/loft-sh/vcluster/pkg/integrations/externalsecrets"
 	"github.com/loft-sh/vcluster/pkg/util/blockingcacheclient"
 	util "github.com/loft-sh/vcluster/pkg/util/context"
 	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/rest"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 
 	"github.com/loft-sh/vcluster/pkg/controllers/coredns"
@@ -65,6 +71,7 @@ func getSyncers(ctx *config.ControllerContext) []initFunction {
 		isEnabled(ctx.Config.Sync.ToHost.PersistentVolumeClaims.Enabled, persistentvolumeclaims.New),
 		isEnabled(ctx.Config.Sync.ToHost.Ingresses.Enabled, ingresses.New),
 		isEnabled(ctx.Config.Sync.FromHost.IngressClasses.Enabled, ingressclasses.New),
+		isEnabled(ctx.Config.Sync.FromHost.RuntimeClasses.Enabled, runtimeclasses.New),
 		isEnabled(ctx.Config.Sync.ToHost.StorageClasses.Enabled, storageclasses.New),
 		isEnabled(ctx.Config.Sync.FromHost.StorageClasses.Enabled == "true", storageclasses.NewHostStorageClassSyncer),
 		isEnabled(ctx.Config.Sync.ToHost.PriorityClasses.Enabled, priorityclasses.New),
@@ -74,6 +81,10 @@ func getSyncers(ctx *config.ControllerContext) []initFunction {
 		isEnabled(ctx.Config.Sync.ToHost.VolumeSnapshots.Enabled, volumesnapshots.New),
 		isEnabled(ctx.Config.Sync.ToHost.VolumeSnapshots.Enabled, volumesnapshotcontents.New),
 		isEnabled(ctx.Config.Sync.ToHost.ServiceAccounts.Enabled, serviceaccounts.New),
+		isEnabled(ctx.Config.Integrations.CertManager.Enabled && ctx.Config.Integrations.CertManager.Sync.ToHost.Certificates.Enabled, secrets.NewCertManagerSyncer),
+		isEnabled(ctx.Config.Integrations.CertManager.Enabled && ctx.Config.Integrations.CertManager.Sync.FromHost.ClusterIssuers.Enabled, hostmirrors.New("clusterissuer", schema.FromAPIVersionAndKind(certmanager.APIVersion, certmanager.ClusterIssuerKind), nil)),
+		isEnabled(ctx.Config.Integrations.ExternalSecrets.Enabled && ctx.Config.Integrations.ExternalSecrets.Sync.ToHost.ExternalSecrets.Enabled, secrets.NewExternalSecretsSyncer),
+		isEnabled(ctx.Config.Integrations.ExternalSecrets.Enabled && ctx.Config.Integrations.ExternalSecrets.Sync.FromHost.ClusterSecretStores.Enabled, hostmirrors.New("clustersecretstore", schema.FromAPIVersionAndKind(externalsecrets.APIVersion, externalsecrets.ClusterSecretStoreKind), externalsecrets.MirrorAllowed(ctx.Config.Integrations.ExternalSecrets))),
 		isEnabled(ctx.Config.Sync.FromHost.CSINodes.Enabled == "true", csinodes.New),
 		isEnabled(ctx.Config.Sync.FromHost.CSIDrivers.Enabled == "true", csidrivers.New),
 		isEnabled(ctx.Config.Sync.FromHost.CSIStorageCapacities.Enabled == "true", csistoragecapacities.New),
@@ -83,6 +94,17 @@ func getSyncers(ctx *config.ControllerContext) []initFunction {
 	}
 }
 
+// getSatelliteSyncers returns the syncers a satellite needs to run the pods selected for its host cluster. The nodes
+// are only synced by the primary syncer, which creates the fake nodes for the pods of all host clusters.
+func getSatelliteSyncers(ctx *config.ControllerContext) []initFunction {
+	return []initFunction{
+		isEnabled(ctx.Config.Sync.ToHost.ConfigMaps.Enabled, configmaps.New),
+		isEnabled(ctx.Config.Sync.ToHost.Secrets.Enabled, secrets.New),
+		isEnabled(ctx.Config.Sync.ToHost.ServiceAccounts.Enabled, serviceaccounts.New),
+		isEnabled(ctx.Config.Sync.ToHost.Pods.Enabled, pods.New),
+	}
+}
+
 func isEnabled(enabled bool, fn initFunction) initFunction {
 	if enabled {
 		return fn
@@ -91,11 +113,20 @@ func isEnabled(enabled bool, fn initFunction) initFunction {
 }
 
 func Create(ctx *config.ControllerContext) ([]syncertypes.Object, error) {
+	return createSyncers(ctx, getSyncers(ctx))
+}
+
+// CreateSatellite creates the syncers of a satellite syncer
+func CreateSatellite(ctx *config.ControllerContext) ([]syncertypes.Object, error) {
+	return createSyncers(ctx, getSatelliteSyncers(ctx))
+}
+
+func createSyncers(ctx *config.ControllerContext, initFunctions []initFunction) ([]syncertypes.Object, error) {
 	registerContext := util.ToRegisterContext(ctx)
 
 	// register controllers for resource synchronization
 	syncers := []syncertypes.Object{}
-	for _, newSyncer := range getSyncers(ctx) {
+	for _, newSyncer := range initFunctions {
 		if newSyncer == nil {
 			continue
 		}
@@ -151,15 +182,13 @@ func RegisterIndices(ctx *config.ControllerContext, syncers []syncertypes.Object
 }
 
 func RegisterControllers(ctx *config.ControllerContext, syncers []syncertypes.Object) error {
-	registerContext := util.ToRegisterContext(ctx)
-
 	err := k8sdefaultendpoint.Register(ctx)
 	if err != nil {
 		return err
 	}
 
 	// register controller that maintains pod security standard check
-	if ctx.Config.Policies.PodSecurityStandard != "" {
+	if ctx.Config.Policies.PodSecurityStandard.Level != "" {
 		err := RegisterPodSecurityController(ctx)
 		if err != nil {
 			return err
@@ -172,6 +201,14 @@ func RegisterControllers(ctx *config.ControllerContext, syncers []syncertypes.Ob
 		return err
 	}
 
+	// register controller that registers the services at the platform dns and resolves the services of other virtual clusters
+	if ctx.Config.Networking.Advanced.PlatformDNS.Enabled {
+		err = RegisterPlatformDNSController(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
 	// register init manifests configmap watcher controller
 	err = deploy.RegisterInitManifestsController(ctx)
 	if err != nil {
@@ -190,11 +227,17 @@ func RegisterControllers(ctx *config.ControllerContext, syncers []syncertypes.Ob
 	}
 
 	// register controllers for resource synchronization
+	return RegisterSyncers(ctx, syncers)
+}
+
+// RegisterSyncers registers the controllers of the given syncers
+func RegisterSyncers(ctx *config.ControllerContext, syncers []syncertypes.Object) error {
+	registerContext := util.ToRegisterContext(ctx)
 	for _, v := range syncers {
 		// fake syncer?
 		fakeSyncer, ok := v.(syncertypes.FakeSyncer)
 		if ok {
-			err = syncer.RegisterFakeSyncer(registerContext, fakeSyncer)
+			err := syncer.RegisterFakeSyncer(registerContext, fakeSyncer)
 			if err != nil {
 				return errors.Wrapf(err, "start %s syncer", v.Name())
 			}
@@ -202,7 +245,7 @@ func RegisterControllers(ctx *config.ControllerContext, syncers []syncertypes.Ob
 			// real syncer?
 			realSyncer, ok := v.(syncertypes.Syncer)
 			if ok {
-				err = syncer.RegisterSyncer(registerContext, realSyncer)
+				err := syncer.RegisterSyncer(registerContext, realSyncer)
 				if err != nil {
 					return errors.Wrapf(err, "start %s syncer", v.Name())
 				}
@@ -365,10 +408,36 @@ func RegisterCoreDNSController(ctx *config.ControllerContext) error {
 	return nil
 }
 
+func RegisterPlatformDNSController(ctx *config.ControllerContext) error {
+	// the registrations are in a different host namespace than the virtual cluster, so we don't use the cache here
+	uncachedHostClient, err := client.New(ctx.LocalManager.GetConfig(), client.Options{
+		Scheme: ctx.LocalManager.GetScheme(),
+		Mapper: ctx.LocalManager.GetRESTMapper(),
+	})
+	if err != nil {
+		return err
+	}
+
+	controller := &coredns.PlatformHostsReconciler{
+		Client:            ctx.VirtualManager.GetClient(),
+		HostClient:        uncachedHostClient,
+		Name:              ctx.Config.Name,
+		Namespace:         ctx.Config.WorkloadNamespace,
+		Domain:            ctx.Config.Networking.Advanced.PlatformDNS.Domain,
+		RegistryNamespace: ctx.Config.Networking.Advanced.PlatformDNS.Namespace,
+		Log:               loghelper.New("corednsplatformhosts-controller"),
+	}
+	err = controller.SetupWithManager(ctx.VirtualManager)
+	if err != nil {
+		return fmt.Errorf("unable to setup CoreDNS PlatformHosts controller: %w", err)
+	}
+	return nil
+}
+
 func RegisterPodSecurityController(ctx *config.ControllerContext) error {
 	controller := &podsecurity.Reconciler{
 		Client:              ctx.VirtualManager.GetClient(),
-		PodSecurityStandard: ctx.Config.Policies.PodSecurityStandard,
+		PodSecurityStandard: ctx.Config.Policies.PodSecurityStandard.Level,
 		Log:                 loghelper.New("podSecurity-controller"),
 	}
 	err := controller.SetupWithManager(ctx.VirtualManager)
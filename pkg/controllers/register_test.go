@@ -0,0 +1,74 @@
+package controllers
+
+import (
+	"slices"
+	"testing"
+
+	vclusterconfig "github.com/loft-sh/vcluster/config"
+	"github.com/loft-sh/vcluster/pkg/config"
+	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
+	generictesting "github.com/loft-sh/vcluster/pkg/controllers/syncer/testing"
+	syncertypes "github.com/loft-sh/vcluster/pkg/types"
+	testingutil "github.com/loft-sh/vcluster/pkg/util/testing"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestSatelliteSyncers(t *testing.T) {
+	scheme := testingutil.NewScheme()
+	vClient := testingutil.NewFakeClient(scheme,
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+		&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "kubernetes", Namespace: "default"}, Spec: corev1.ServiceSpec{ClusterIP: "10.96.0.1"}},
+		&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "kube-dns", Namespace: "kube-system"}, Spec: corev1.ServiceSpec{ClusterIP: "10.96.0.10"}},
+	)
+	pClient := testingutil.NewFakeClient(scheme,
+		&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "vcluster-api", Namespace: generictesting.DefaultTestCurrentNamespace}, Spec: corev1.ServiceSpec{ClusterIP: "172.20.0.1"}},
+		&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "vcluster-dns", Namespace: generictesting.DefaultTestCurrentNamespace}, Spec: corev1.ServiceSpec{ClusterIP: "172.20.0.10"}},
+	)
+	registerContext := generictesting.NewFakeRegisterContext(pClient, vClient)
+	registerContext.Config.Experimental.MultiCluster.Satellites = []vclusterconfig.SatelliteCluster{
+		{Name: "eu", NodeSelector: map[string]string{"topology.kubernetes.io/region": "eu-west-1"}},
+	}
+	registerContext.Config.Experimental.MultiCluster.Satellite = vclusterconfig.ExperimentalSatellite{Enabled: true, Name: "eu", KubernetesService: "vcluster-api", DNSService: "vcluster-dns"}
+
+	// the secrets syncer discovers the ingress api of the host cluster, which isn't available here
+	registerContext.Config.Sync.ToHost.Secrets.Enabled = false
+
+	// the cluster ips of the virtual services belong to the primary host cluster, so the pods use the services of the
+	// satellite host cluster. The nodes are only synced by the primary syncer.
+	var podSyncer syncertypes.Syncer
+	var podSyncContext *synccontext.SyncContext
+	for _, newSyncer := range getSatelliteSyncers(&config.ControllerContext{Config: registerContext.Config}) {
+		if newSyncer == nil {
+			continue
+		}
+
+		syncContext, syncer := generictesting.FakeStartSyncer(t, registerContext, newSyncer)
+		assert.Assert(t, syncer.Name() != "node" && syncer.Name() != "fake-node")
+		if syncer.Name() == "pod" {
+			podSyncer, podSyncContext = syncer.(syncertypes.Syncer), syncContext
+		}
+	}
+	assert.Assert(t, podSyncer != nil)
+
+	vPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Spec: corev1.PodSpec{
+			NodeSelector: map[string]string{"topology.kubernetes.io/region": "eu-west-1"},
+			DNSPolicy:    corev1.DNSClusterFirst,
+			Containers:   []corev1.Container{{Name: "web", Image: "nginx"}},
+		},
+	}
+	assert.NilError(t, vClient.Create(podSyncContext.Context, vPod))
+	_, err := podSyncer.SyncToHost(podSyncContext, vPod)
+	assert.NilError(t, err)
+
+	pPod := &corev1.Pod{}
+	assert.NilError(t, pClient.Get(podSyncContext.Context, types.NamespacedName{Namespace: generictesting.DefaultTestTargetNamespace, Name: translate.Default.PhysicalName("web", "default")}, pPod))
+	assert.Assert(t, pPod.Spec.DNSConfig != nil)
+	assert.DeepEqual(t, pPod.Spec.DNSConfig.Nameservers, []string{"172.20.0.10"})
+	assert.Assert(t, slices.Contains(pPod.Spec.Containers[0].Env, corev1.EnvVar{Name: "KUBERNETES_SERVICE_HOST", Value: "172.20.0.1"}))
+}
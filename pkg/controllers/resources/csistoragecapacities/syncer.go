@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/loft-sh/vcluster/pkg/controllers/resources/storageclasses"
 	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
 	"github.com/loft-sh/vcluster/pkg/controllers/syncer/translator"
 	syncertypes "github.com/loft-sh/vcluster/pkg/types"
@@ -24,6 +25,7 @@ func New(ctx *synccontext.RegisterContext) (syncertypes.Object, error) {
 	return &csistoragecapacitySyncer{
 		storageClassSyncEnabled:     ctx.Config.Sync.ToHost.StorageClasses.Enabled,
 		hostStorageClassSyncEnabled: ctx.Config.Sync.FromHost.StorageClasses.Enabled == "true",
+		hostStorageClassMapping:     storageclasses.NewMapping(ctx.Config.Sync.FromHost.StorageClasses),
 		physicalClient:              ctx.PhysicalManager.GetClient(),
 	}, nil
 }
@@ -31,6 +33,7 @@ func New(ctx *synccontext.RegisterContext) (syncertypes.Object, error) {
 type csistoragecapacitySyncer struct {
 	storageClassSyncEnabled     bool
 	hostStorageClassSyncEnabled bool
+	hostStorageClassMapping     *storageclasses.Mapping
 	physicalClient              client.Client
 }
 
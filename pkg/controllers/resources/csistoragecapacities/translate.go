@@ -26,6 +26,11 @@ func (s *csistoragecapacitySyncer) fetchVirtualStorageClass(ctx *synccontext.Syn
 		}
 		return sc.Name, false, nil
 	}
+	if s.hostStorageClassSyncEnabled {
+		// the storage class might be synced under another name or not at all
+		virtualName := s.hostStorageClassMapping.HostToVirtual(physName)
+		return virtualName, virtualName == "", nil
+	}
 	return physName, false, nil
 }
 
@@ -8,6 +8,7 @@ import (
 	"github.com/loft-sh/vcluster/pkg/constants"
 	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
 	"github.com/loft-sh/vcluster/pkg/controllers/syncer/translator"
+	"github.com/loft-sh/vcluster/pkg/integrations/istio"
 	syncer "github.com/loft-sh/vcluster/pkg/types"
 	"github.com/loft-sh/vcluster/pkg/util/clienthelper"
 	"github.com/loft-sh/vcluster/pkg/util/translate"
@@ -35,12 +36,16 @@ func New(ctx *synccontext.RegisterContext) (syncer.Object, error) {
 	return &eventSyncer{
 		virtualClient: ctx.VirtualManager.GetClient(),
 		hostClient:    ctx.PhysicalManager.GetClient(),
+
+		skipSidecarEvents: ctx.Config.Integrations.Istio.Enabled,
 	}, nil
 }
 
 type eventSyncer struct {
 	virtualClient client.Client
 	hostClient    client.Client
+
+	skipSidecarEvents bool
 }
 
 func (s *eventSyncer) Resource() client.Object {
@@ -210,6 +215,11 @@ func (s *eventSyncer) getInvolvedObject(ctx context.Context, pObj client.Object)
 		return nil, nil
 	}
 
+	// the injected mesh sidecars don't exist in the virtual pod
+	if s.skipSidecarEvents && istio.IsSidecarEvent(pEvent) {
+		return nil, nil
+	}
+
 	// create new virtual object
 	vInvolvedObj, err := s.virtualClient.Scheme().New(gvk)
 	if err != nil {
@@ -0,0 +1,83 @@
+package hostmirrors
+
+import (
+	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
+	"github.com/loft-sh/vcluster/pkg/controllers/syncer/translator"
+	syncer "github.com/loft-sh/vcluster/pkg/types"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// New returns a syncer that mirrors the cluster scoped custom resources of the given kind from the host cluster into
+// the virtual cluster. The mirrored resources are read-only within the virtual cluster. If allowed is set, only the
+// host resources it allows are mirrored.
+func New(name string, gvk schema.GroupVersionKind, allowed func(pObj client.Object) bool) func(*synccontext.RegisterContext) (syncer.Object, error) {
+	return func(*synccontext.RegisterContext) (syncer.Object, error) {
+		obj := &unstructured.Unstructured{}
+		obj.SetGroupVersionKind(gvk)
+
+		return &hostMirrorSyncer{
+			Translator: translator.NewMirrorPhysicalTranslator(name, obj),
+			gvk:        gvk,
+			allowed:    allowed,
+		}, nil
+	}
+}
+
+type hostMirrorSyncer struct {
+	translator.Translator
+
+	gvk     schema.GroupVersionKind
+	allowed func(pObj client.Object) bool
+}
+
+var _ syncer.Initializer = &hostMirrorSyncer{}
+
+func (s *hostMirrorSyncer) Init(registerContext *synccontext.RegisterContext) error {
+	_, _, err := translate.EnsureCRDFromPhysicalCluster(registerContext.Context, registerContext.PhysicalManager.GetConfig(), registerContext.VirtualManager.GetConfig(), s.gvk)
+	return err
+}
+
+var _ syncer.ToVirtualSyncer = &hostMirrorSyncer{}
+
+func (s *hostMirrorSyncer) SyncToVirtual(ctx *synccontext.SyncContext, pObj client.Object) (ctrl.Result, error) {
+	if !s.isAllowed(pObj) {
+		return ctrl.Result{}, nil
+	}
+
+	vObj := s.translateBackwards(ctx.Context, pObj.(*unstructured.Unstructured))
+	ctx.Log.Infof("create %s %s, because it does not exist in the virtual cluster", s.gvk.Kind, vObj.GetName())
+	return ctrl.Result{}, ctx.VirtualClient.Create(ctx.Context, vObj)
+}
+
+var _ syncer.Syncer = &hostMirrorSyncer{}
+
+func (s *hostMirrorSyncer) SyncToHost(ctx *synccontext.SyncContext, vObj client.Object) (ctrl.Result, error) {
+	// the mirrored resources are read-only within the virtual cluster, so a resource without a host object
+	// was either deleted in the host cluster or created in the virtual cluster and gets removed
+	ctx.Log.Infof("delete %s %s, because it does not exist in the host cluster", s.gvk.Kind, vObj.GetName())
+	return ctrl.Result{}, ctx.VirtualClient.Delete(ctx.Context, vObj)
+}
+
+func (s *hostMirrorSyncer) Sync(ctx *synccontext.SyncContext, pObj client.Object, vObj client.Object) (ctrl.Result, error) {
+	if !s.isAllowed(pObj) {
+		ctx.Log.Infof("delete %s %s, because the host object is not allowed to be mirrored", s.gvk.Kind, vObj.GetName())
+		return ctrl.Result{}, ctx.VirtualClient.Delete(ctx.Context, vObj)
+	}
+
+	updated := s.translateUpdateBackwards(ctx.Context, pObj.(*unstructured.Unstructured), vObj.(*unstructured.Unstructured))
+	if updated != nil {
+		ctx.Log.Infof("update %s %s, because it differs from the host object", s.gvk.Kind, updated.GetName())
+		translator.PrintChanges(vObj, updated, ctx.Log)
+		return ctrl.Result{}, ctx.VirtualClient.Update(ctx.Context, updated)
+	}
+
+	return ctrl.Result{}, nil
+}
+
+func (s *hostMirrorSyncer) isAllowed(pObj client.Object) bool {
+	return s.allowed == nil || s.allowed(pObj)
+}
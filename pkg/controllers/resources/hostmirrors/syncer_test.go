@@ -0,0 +1,51 @@
+package hostmirrors
+
+import (
+	"context"
+	"testing"
+
+	generictesting "github.com/loft-sh/vcluster/pkg/controllers/syncer/testing"
+	"github.com/loft-sh/vcluster/pkg/integrations/certmanager"
+	testingutil "github.com/loft-sh/vcluster/pkg/util/testing"
+	"gotest.tools/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestTranslate(t *testing.T) {
+	pClient := testingutil.NewFakeClient(testingutil.NewScheme())
+	vClient := testingutil.NewFakeClient(testingutil.NewScheme())
+	ctx := generictesting.NewFakeRegisterContext(pClient, vClient)
+	gvk := schema.FromAPIVersionAndKind(certmanager.APIVersion, certmanager.ClusterIssuerKind)
+	obj, err := New("clusterissuer", gvk, nil)(ctx)
+	assert.NilError(t, err)
+	s := obj.(*hostMirrorSyncer)
+
+	pClusterIssuer := &unstructured.Unstructured{}
+	pClusterIssuer.SetGroupVersionKind(gvk)
+	pClusterIssuer.SetName("letsencrypt")
+	pClusterIssuer.SetResourceVersion("123")
+	pClusterIssuer.SetLabels(map[string]string{"team": "platform"})
+	pClusterIssuer.Object["spec"] = map[string]interface{}{
+		"acme": map[string]interface{}{"server": "https://acme-v02.api.letsencrypt.org/directory"},
+	}
+	pClusterIssuer.Object["status"] = map[string]interface{}{"conditions": []interface{}{}}
+
+	vClusterIssuer := s.translateBackwards(context.Background(), pClusterIssuer)
+	assert.Equal(t, vClusterIssuer.GetName(), "letsencrypt")
+	assert.Equal(t, vClusterIssuer.GetResourceVersion(), "")
+	_, found, _ := unstructured.NestedFieldNoCopy(vClusterIssuer.Object, "status")
+	assert.Assert(t, !found)
+	assert.Assert(t, s.translateUpdateBackwards(context.Background(), pClusterIssuer, vClusterIssuer) == nil)
+
+	// changes within the virtual cluster are reverted
+	changed := vClusterIssuer.DeepCopy()
+	changed.Object["spec"] = map[string]interface{}{
+		"acme": map[string]interface{}{"server": "https://example.com"},
+	}
+	changed.SetLabels(nil)
+	updated := s.translateUpdateBackwards(context.Background(), pClusterIssuer, changed)
+	assert.Assert(t, updated != nil)
+	assert.DeepEqual(t, updated.Object["spec"], pClusterIssuer.Object["spec"])
+	assert.DeepEqual(t, updated.GetLabels(), map[string]string{"team": "platform"})
+}
@@ -0,0 +1,37 @@
+package hostmirrors
+
+import (
+	"context"
+
+	"github.com/loft-sh/vcluster/pkg/controllers/syncer/translator"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func (s *hostMirrorSyncer) translateBackwards(ctx context.Context, pObj *unstructured.Unstructured) *unstructured.Unstructured {
+	vObj := s.TranslateMetadata(ctx, pObj).(*unstructured.Unstructured)
+
+	// the status is owned by the host controller
+	unstructured.RemoveNestedField(vObj.Object, "status")
+	return vObj
+}
+
+func (s *hostMirrorSyncer) translateUpdateBackwards(ctx context.Context, pObj, vObj *unstructured.Unstructured) *unstructured.Unstructured {
+	var updated *unstructured.Unstructured
+
+	changed, updatedAnnotations, updatedLabels := s.TranslateMetadataUpdate(ctx, vObj, pObj)
+	if changed {
+		updated = translator.NewIfNil(updated, vObj)
+		updated.SetAnnotations(updatedAnnotations)
+		updated.SetLabels(updatedLabels)
+	}
+
+	pSpec, _, _ := unstructured.NestedFieldNoCopy(pObj.Object, "spec")
+	vSpec, _, _ := unstructured.NestedFieldNoCopy(vObj.Object, "spec")
+	if !equality.Semantic.DeepEqual(pSpec, vSpec) {
+		updated = translator.NewIfNil(updated, vObj)
+		updated.Object["spec"] = pObj.DeepCopy().Object["spec"]
+	}
+
+	return updated
+}
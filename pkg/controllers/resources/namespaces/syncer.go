@@ -4,6 +4,7 @@ import (
 	"github.com/loft-sh/vcluster/pkg/constants"
 	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
 	"github.com/loft-sh/vcluster/pkg/controllers/syncer/translator"
+	"github.com/loft-sh/vcluster/pkg/integrations/istio"
 	syncertypes "github.com/loft-sh/vcluster/pkg/types"
 	"github.com/loft-sh/vcluster/pkg/util/translate"
 	corev1 "k8s.io/api/core/v1"
@@ -30,6 +31,11 @@ func New(ctx *synccontext.RegisterContext) (syncertypes.Object, error) {
 	for k, v := range ctx.Config.Experimental.MultiNamespaceMode.NamespaceLabels {
 		namespaceLabels[k] = v
 	}
+	if ctx.Config.Integrations.Istio.Enabled {
+		for k, v := range istio.NamespaceLabels(ctx.Config.Integrations.Istio) {
+			namespaceLabels[k] = v
+		}
+	}
 	namespaceLabels[VClusterNameAnnotation] = ctx.Config.Name
 	namespaceLabels[VClusterNamespaceAnnotation] = ctx.CurrentNamespace
 
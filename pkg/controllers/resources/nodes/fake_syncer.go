@@ -9,6 +9,7 @@ import (
 	"github.com/loft-sh/vcluster/pkg/constants"
 	"github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/api/equality"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 
 	"github.com/loft-sh/vcluster/pkg/controllers/resources/nodes/nodeservice"
 	podtranslate "github.com/loft-sh/vcluster/pkg/controllers/resources/pods/translate"
@@ -16,6 +17,7 @@ import (
 	"github.com/loft-sh/vcluster/pkg/controllers/syncer/translator"
 	syncer "github.com/loft-sh/vcluster/pkg/types"
 	"github.com/loft-sh/vcluster/pkg/util/random"
+	"github.com/loft-sh/vcluster/pkg/util/resources"
 	"github.com/loft-sh/vcluster/pkg/util/translate"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -33,16 +35,18 @@ var (
 
 func NewFakeSyncer(ctx *synccontext.RegisterContext, nodeService nodeservice.Provider) (syncer.Object, error) {
 	return &fakeNodeSyncer{
-		nodeServiceProvider:  nodeService,
-		fakeKubeletIPs:       ctx.Config.Networking.Advanced.ProxyKubelets.ByIP,
-		fakeKubeletHostnames: ctx.Config.Networking.Advanced.ProxyKubelets.ByHostname,
+		nodeServiceProvider:   nodeService,
+		fakeKubeletIPs:        ctx.Config.Networking.Advanced.ProxyKubelets.ByIP,
+		fakeKubeletHostnames:  ctx.Config.Networking.Advanced.ProxyKubelets.ByHostname,
+		copyExtendedResources: ctx.Config.Sync.FromHost.Nodes.FakeNodeExtendedResources,
 	}, nil
 }
 
 type fakeNodeSyncer struct {
-	nodeServiceProvider  nodeservice.Provider
-	fakeKubeletIPs       bool
-	fakeKubeletHostnames bool
+	nodeServiceProvider   nodeservice.Provider
+	fakeKubeletIPs        bool
+	fakeKubeletHostnames  bool
+	copyExtendedResources bool
 }
 
 func (r *fakeNodeSyncer) Resource() client.Object {
@@ -75,8 +79,13 @@ func (r *fakeNodeSyncer) FakeSyncToVirtual(ctx *synccontext.SyncContext, name ty
 		return ctrl.Result{}, nil
 	}
 
+	capacity, allocatable, err := r.extendedResources(ctx.Context, ctx.PhysicalClient, name.Name)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
 	ctx.Log.Infof("Create fake node %s", name.Name)
-	return ctrl.Result{}, createFakeNode(ctx.Context, r.fakeKubeletIPs, r.fakeKubeletHostnames, r.nodeServiceProvider, ctx.VirtualClient, name.Name)
+	return ctrl.Result{}, createFakeNode(ctx.Context, r.fakeKubeletIPs, r.fakeKubeletHostnames, r.nodeServiceProvider, ctx.VirtualClient, name.Name, capacity, allocatable)
 }
 
 func (r *fakeNodeSyncer) FakeSync(ctx *synccontext.SyncContext, vObj client.Object) (ctrl.Result, error) {
@@ -95,6 +104,16 @@ func (r *fakeNodeSyncer) FakeSync(ctx *synccontext.SyncContext, vObj client.Obje
 
 	// check if we need to update node ips
 	updated := r.updateIfNeeded(ctx, node, node.Name)
+
+	// check if we need to update the extended resources
+	if r.copyExtendedResources {
+		capacity, allocatable, err := r.extendedResources(ctx.Context, ctx.PhysicalClient, node.Name)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+
+		updated = updateExtendedResources(updated, node, capacity, allocatable)
+	}
 	if updated != nil {
 		ctx.Log.Infof("Update fake node %s", node.Name)
 		err := ctx.VirtualClient.Status().Update(ctx.Context, updated)
@@ -136,6 +155,50 @@ func (r *fakeNodeSyncer) updateIfNeeded(ctx *synccontext.SyncContext, node *core
 	return updated
 }
 
+// extendedResources returns the extended resources of the host node from the cache, if copying them is enabled
+func (r *fakeNodeSyncer) extendedResources(ctx context.Context, physicalClient client.Client, name string) (corev1.ResourceList, corev1.ResourceList, error) {
+	if !r.copyExtendedResources {
+		return nil, nil, nil
+	}
+
+	pNode := &corev1.Node{}
+	err := physicalClient.Get(ctx, types.NamespacedName{Name: name}, pNode)
+	if kerrors.IsNotFound(err) {
+		return nil, nil, nil
+	} else if err != nil {
+		return nil, nil, errors.Wrap(err, "get host node")
+	}
+
+	return resources.ExtendedResources(pNode.Status.Capacity), resources.ExtendedResources(pNode.Status.Allocatable), nil
+}
+
+// updateExtendedResources replaces the extended resources of the fake node with the ones of the host node
+func updateExtendedResources(updated, node *corev1.Node, capacity, allocatable corev1.ResourceList) *corev1.Node {
+	if equality.Semantic.DeepEqual(resources.ExtendedResources(node.Status.Capacity), capacity) &&
+		equality.Semantic.DeepEqual(resources.ExtendedResources(node.Status.Allocatable), allocatable) {
+		return updated
+	}
+
+	updated = translator.NewIfNil(updated, node)
+	updated.Status.Capacity = withExtendedResources(updated.Status.Capacity, capacity)
+	updated.Status.Allocatable = withExtendedResources(updated.Status.Allocatable, allocatable)
+	return updated
+}
+
+func withExtendedResources(list corev1.ResourceList, extended corev1.ResourceList) corev1.ResourceList {
+	merged := corev1.ResourceList{}
+	for name, quantity := range list {
+		if !resources.IsExtendedResourceName(name) {
+			merged[name] = quantity
+		}
+	}
+	for name, quantity := range extended {
+		merged[name] = quantity
+	}
+
+	return merged
+}
+
 func (r *fakeNodeSyncer) nodeNeeded(ctx *synccontext.SyncContext, nodeName string) (bool, error) {
 	return isNodeNeededByPod(ctx.Context, ctx.VirtualClient, ctx.PhysicalClient, nodeName)
 }
@@ -152,6 +215,8 @@ func createFakeNode(
 	nodeServiceProvider nodeservice.Provider,
 	virtualClient client.Client,
 	name string,
+	extendedCapacity corev1.ResourceList,
+	extendedAllocatable corev1.ResourceList,
 ) error {
 	nodeServiceProvider.Lock()
 	defer nodeServiceProvider.Unlock()
@@ -251,6 +316,8 @@ func createFakeNode(
 		},
 		Images: []corev1.ContainerImage{},
 	}
+	node.Status.Capacity = withExtendedResources(node.Status.Capacity, extendedCapacity)
+	node.Status.Allocatable = withExtendedResources(node.Status.Allocatable, extendedAllocatable)
 
 	if fakeKubeletHostnames {
 		node.Status.Addresses = append(node.Status.Addresses, corev1.NodeAddress{
@@ -198,3 +198,31 @@ func TestFakeSync(t *testing.T) {
 		},
 	})
 }
+
+func TestUpdateExtendedResources(t *testing.T) {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "mynode"},
+		Status: corev1.NodeStatus{
+			Capacity: corev1.ResourceList{
+				corev1.ResourceCPU:   resource.MustParse("16"),
+				"example.com/dongle": resource.MustParse("1"),
+			},
+			Allocatable: corev1.ResourceList{
+				corev1.ResourceCPU:   resource.MustParse("16"),
+				"example.com/dongle": resource.MustParse("1"),
+			},
+		},
+	}
+
+	gpus := corev1.ResourceList{"nvidia.com/gpu": resource.MustParse("8")}
+	updated := updateExtendedResources(nil, node, gpus, gpus)
+	assert.Assert(t, updated != nil)
+	assert.Assert(t, apiequality.Semantic.DeepEqual(updated.Status.Capacity, corev1.ResourceList{
+		corev1.ResourceCPU: resource.MustParse("16"),
+		"nvidia.com/gpu":   resource.MustParse("8"),
+	}))
+	assert.Assert(t, apiequality.Semantic.DeepEqual(updated.Status.Allocatable, updated.Status.Capacity))
+
+	// unchanged extended resources don't update the node
+	assert.Assert(t, updateExtendedResources(nil, updated, gpus, gpus) == nil)
+}
@@ -3,12 +3,15 @@ package nodes
 import (
 	"context"
 	"fmt"
+	"sync"
 
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 
+	vclusterconfig "github.com/loft-sh/vcluster/config"
+	"github.com/loft-sh/vcluster/pkg/config"
 	"github.com/loft-sh/vcluster/pkg/constants"
 	"github.com/loft-sh/vcluster/pkg/controllers/resources/nodes/nodeservice"
 	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
@@ -31,13 +34,6 @@ import (
 )
 
 func NewSyncer(ctx *synccontext.RegisterContext, nodeServiceProvider nodeservice.Provider) (syncertypes.Object, error) {
-	var nodeSelector labels.Selector
-	if ctx.Config.Sync.FromHost.Nodes.Selector.All {
-		nodeSelector = labels.Everything()
-	} else if len(ctx.Config.Sync.FromHost.Nodes.Selector.Labels) > 0 {
-		nodeSelector = labels.Set(ctx.Config.Sync.FromHost.Nodes.Selector.Labels).AsSelector()
-	}
-
 	// parse tolerations
 	var tolerations []*corev1.Toleration
 	if len(ctx.Config.Sync.ToHost.Pods.EnforceTolerations) > 0 {
@@ -49,11 +45,11 @@ func NewSyncer(ctx *synccontext.RegisterContext, nodeServiceProvider nodeservice
 		}
 	}
 
-	return &nodeSyncer{
+	s := &nodeSyncer{
 		enableScheduler: ctx.Config.ControlPlane.Advanced.VirtualScheduler.Enabled,
 
 		enforceNodeSelector:  true,
-		nodeSelector:         nodeSelector,
+		nodeSelector:         parseNodeSelector(ctx.Config.Sync.FromHost.Nodes.Selector),
 		clearImages:          ctx.Config.Sync.FromHost.Nodes.ClearImageStatus,
 		useFakeKubelets:      ctx.Config.Networking.Advanced.ProxyKubelets.ByHostname || ctx.Config.Networking.Advanced.ProxyKubelets.ByIP,
 		fakeKubeletIPs:       ctx.Config.Networking.Advanced.ProxyKubelets.ByIP,
@@ -63,10 +59,32 @@ func NewSyncer(ctx *synccontext.RegisterContext, nodeServiceProvider nodeservice
 		virtualClient:       ctx.VirtualManager.GetClient(),
 		nodeServiceProvider: nodeServiceProvider,
 		enforcedTolerations: tolerations,
-	}, nil
+	}
+
+	return s, nil
+}
+
+var _ syncertypes.ConfigReloader = &nodeSyncer{}
+
+// ReloadConfig applies the node selector, which can change at runtime
+func (s *nodeSyncer) ReloadConfig(newConfig *config.VirtualClusterConfig) {
+	s.nodeSelectorMutex.Lock()
+	defer s.nodeSelectorMutex.Unlock()
+	s.nodeSelector = parseNodeSelector(newConfig.Sync.FromHost.Nodes.Selector)
+}
+
+func parseNodeSelector(selector vclusterconfig.SyncNodeSelector) labels.Selector {
+	if selector.All {
+		return labels.Everything()
+	} else if len(selector.Labels) > 0 {
+		return labels.Set(selector.Labels).AsSelector()
+	}
+
+	return nil
 }
 
 type nodeSyncer struct {
+	nodeSelectorMutex    sync.RWMutex
 	nodeSelector         labels.Selector
 	physicalClient       client.Client
 	virtualClient        client.Client
@@ -330,13 +348,16 @@ func (s *nodeSyncer) SyncToVirtual(ctx *synccontext.SyncContext, pObj client.Obj
 }
 
 func (s *nodeSyncer) shouldSync(ctx context.Context, pObj *corev1.Node) (bool, error) {
-	if s.nodeSelector != nil {
+	s.nodeSelectorMutex.RLock()
+	nodeSelector := s.nodeSelector
+	s.nodeSelectorMutex.RUnlock()
+	if nodeSelector != nil {
 		ls := labels.Set(pObj.Labels)
 		if ls == nil {
 			ls = labels.Set{}
 		}
 
-		matched := s.nodeSelector.Matches(ls)
+		matched := nodeSelector.Matches(ls)
 		if !matched && !s.enforceNodeSelector {
 			return isNodeNeededByPod(ctx, s.virtualClient, s.physicalClient, pObj.Name)
 		}
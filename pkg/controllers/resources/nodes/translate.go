@@ -11,6 +11,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/resource"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/loft-sh/vcluster/pkg/util/resources"
 	"github.com/loft-sh/vcluster/pkg/util/stringutil"
 	"github.com/loft-sh/vcluster/pkg/util/translate"
 	corev1 "k8s.io/api/core/v1"
@@ -180,6 +181,7 @@ func (s *nodeSyncer) translateUpdateStatus(ctx *synccontext.SyncContext, pNode *
 			memory := translatedStatus.Allocatable.Memory().Value()
 			storageEphemeral := translatedStatus.Allocatable.StorageEphemeral().Value()
 			pods := translatedStatus.Allocatable.Pods().Value()
+			extended := resources.ExtendedResources(translatedStatus.Allocatable)
 
 			var nonVClusterPods int64
 			podList := &corev1.PodList{}
@@ -202,6 +204,12 @@ func (s *nodeSyncer) translateUpdateStatus(ctx *synccontext.SyncContext, pNode *
 						memory -= container.Resources.Requests.Memory().Value()
 						storageEphemeral -= container.Resources.Requests.StorageEphemeral().Value()
 					}
+					for name, quantity := range resources.PodExtendedResourceRequests(&pod) {
+						if available, ok := extended[name]; ok {
+							available.Sub(quantity)
+							extended[name] = available
+						}
+					}
 				}
 			}
 
@@ -218,6 +226,11 @@ func (s *nodeSyncer) translateUpdateStatus(ctx *synccontext.SyncContext, pNode *
 			if storageEphemeral > 0 {
 				translatedStatus.Allocatable[corev1.ResourceEphemeralStorage] = *resource.NewQuantity(storageEphemeral, resource.BinarySI)
 			}
+			for name, available := range extended {
+				if available.Sign() >= 0 {
+					translatedStatus.Allocatable[name] = available
+				}
+			}
 		}
 
 		// calculate what's in capacity & allocatable
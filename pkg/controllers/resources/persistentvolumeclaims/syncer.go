@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/loft-sh/vcluster/pkg/controllers/resources/persistentvolumes"
+	"github.com/loft-sh/vcluster/pkg/controllers/resources/storageclasses"
 	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
 	"github.com/loft-sh/vcluster/pkg/controllers/syncer/translator"
 	"github.com/pkg/errors"
@@ -41,15 +42,26 @@ func New(ctx *synccontext.RegisterContext) (syncer.Object, error) {
 		NamespacedTranslator: translator.NewNamespacedTranslator(ctx, "persistent-volume-claim", &corev1.PersistentVolumeClaim{}, excludedAnnotations...),
 
 		storageClassesEnabled:    storageClassesEnabled,
+		storageClassMapping:      hostStorageClassMapping(ctx),
 		schedulerEnabled:         ctx.Config.ControlPlane.Advanced.VirtualScheduler.Enabled,
 		useFakePersistentVolumes: !ctx.Config.Sync.ToHost.PersistentVolumes.Enabled,
 	}, nil
 }
 
+// hostStorageClassMapping returns the mapping of the storage classes synced from the host cluster, if any
+func hostStorageClassMapping(ctx *synccontext.RegisterContext) *storageclasses.Mapping {
+	if ctx.Config.Sync.FromHost.StorageClasses.Enabled != "true" {
+		return nil
+	}
+
+	return storageclasses.NewMapping(ctx.Config.Sync.FromHost.StorageClasses)
+}
+
 type persistentVolumeClaimSyncer struct {
 	translator.NamespacedTranslator
 
 	storageClassesEnabled    bool
+	storageClassMapping      *storageclasses.Mapping
 	schedulerEnabled         bool
 	useFakePersistentVolumes bool
 }
@@ -57,7 +69,7 @@ type persistentVolumeClaimSyncer struct {
 var _ syncer.OptionsProvider = &persistentVolumeClaimSyncer{}
 
 func (s *persistentVolumeClaimSyncer) WithOptions() *syncer.Options {
-	return &syncer.Options{DisableUIDDeletion: true}
+	return &syncer.Options{DisableUIDDeletion: true, DisableOrderedDeletion: true}
 }
 
 var _ syncer.Syncer = &persistentVolumeClaimSyncer{}
@@ -4,6 +4,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/loft-sh/vcluster/config"
 	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
 	testingutil "github.com/loft-sh/vcluster/pkg/util/testing"
 	"gotest.tools/assert"
@@ -13,10 +14,12 @@ import (
 	"github.com/loft-sh/vcluster/pkg/util/translate"
 
 	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/ptr"
 )
 
 func TestSync(t *testing.T) {
@@ -115,6 +118,18 @@ func TestSync(t *testing.T) {
 			},
 		},
 	}
+	mappedStorageClassPvc := &corev1.PersistentVolumeClaim{
+		ObjectMeta: vObjectMeta,
+		Spec: corev1.PersistentVolumeClaimSpec{
+			StorageClassName: ptr.To("standard"),
+		},
+	}
+	createdMappedStorageClassPvc := &corev1.PersistentVolumeClaim{
+		ObjectMeta: pObjectMeta,
+		Spec: corev1.PersistentVolumeClaimSpec{
+			StorageClassName: ptr.To("gp3"),
+		},
+	}
 	backwardUpdateStatusPvc := &corev1.PersistentVolumeClaim{
 		ObjectMeta: pObjectMeta,
 		Spec: corev1.PersistentVolumeClaimSpec{
@@ -150,6 +165,24 @@ func TestSync(t *testing.T) {
 				assert.NilError(t, err)
 			},
 		},
+		{
+			Name:                 "Create forward with mapped host storage class",
+			InitialVirtualState:  []runtime.Object{mappedStorageClassPvc},
+			InitialPhysicalState: []runtime.Object{&storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: "gp3"}}},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				corev1.SchemeGroupVersion.WithKind("PersistentVolumeClaim"): {mappedStorageClassPvc},
+			},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{
+				corev1.SchemeGroupVersion.WithKind("PersistentVolumeClaim"): {createdMappedStorageClassPvc},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				ctx.Config.Sync.FromHost.StorageClasses.Enabled = "true"
+				ctx.Config.Sync.FromHost.StorageClasses.Mappings = []config.StorageClassMapping{{From: "gp3", To: "standard"}}
+				syncCtx, syncer := generictesting.FakeStartSyncer(t, ctx, New)
+				_, err := syncer.(*persistentVolumeClaimSyncer).SyncToHost(syncCtx, mappedStorageClassPvc)
+				assert.NilError(t, err)
+			},
+		},
 		{
 			Name:                 "Delete forward with create function",
 			InitialVirtualState:  []runtime.Object{basePvc},
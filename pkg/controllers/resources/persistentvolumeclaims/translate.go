@@ -48,6 +48,16 @@ func (s *persistentVolumeClaimSyncer) translateSelector(ctx *synccontext.SyncCon
 		storageClassName = vPvc.Annotations[deprecatedStorageClassAnnotation]
 	}
 
+	// translate storage class if it is synced from the host cluster under another name
+	if s.storageClassMapping != nil && storageClassName != "" {
+		hostName := s.storageClassMapping.VirtualToHost(storageClassName)
+		if hostName != storageClassName {
+			storageClassName = hostName
+			delete(vPvc.Annotations, deprecatedStorageClassAnnotation)
+			vPvc.Spec.StorageClassName = &hostName
+		}
+	}
+
 	// translate storage class if we manage those in vcluster
 	if s.storageClassesEnabled && storageClassName != "" {
 		translated := translate.Default.PhysicalNameClusterScoped(storageClassName)
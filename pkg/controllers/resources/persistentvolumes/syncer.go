@@ -214,7 +214,7 @@ func (s *persistentVolumeSyncer) Sync(ctx *synccontext.SyncContext, pObj client.
 var _ syncertypes.OptionsProvider = &persistentVolumeSyncer{}
 
 func (s *persistentVolumeSyncer) WithOptions() *syncertypes.Options {
-	return &syncertypes.Options{DisableUIDDeletion: true}
+	return &syncertypes.Options{DisableUIDDeletion: true, DisableOrderedDeletion: true}
 }
 
 var _ syncertypes.ToVirtualSyncer = &persistentVolumeSyncer{}
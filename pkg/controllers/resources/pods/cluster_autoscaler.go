@@ -0,0 +1,64 @@
+package pods
+
+import (
+	"context"
+	"fmt"
+
+	vclusterconfig "github.com/loft-sh/vcluster/config"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// isUnschedulable returns true if the virtual scheduler couldn't find a node for the pod
+func isUnschedulable(vPod *corev1.Pod) bool {
+	for _, condition := range vPod.Status.Conditions {
+		if condition.Type == corev1.PodScheduled {
+			return condition.Status == corev1.ConditionFalse && condition.Reason == corev1.PodReasonUnschedulable
+		}
+	}
+
+	return false
+}
+
+// applyClusterAutoscaler marks a host pod that is synced without a node for the cluster-autoscaler of the host cluster
+func applyClusterAutoscaler(clusterAutoscaler vclusterconfig.SyncPodsClusterAutoscaler, pPod *corev1.Pod) {
+	if !clusterAutoscaler.Enabled || pPod.Spec.NodeName != "" {
+		return
+	}
+
+	if len(clusterAutoscaler.Annotations) > 0 {
+		if pPod.Annotations == nil {
+			pPod.Annotations = map[string]string{}
+		}
+		for k, v := range clusterAutoscaler.Annotations {
+			pPod.Annotations[k] = v
+		}
+	}
+	if clusterAutoscaler.PriorityClassName != "" {
+		// the priority is resolved by the host cluster from the priority class
+		pPod.Spec.PriorityClassName = clusterAutoscaler.PriorityClassName
+		pPod.Spec.Priority = nil
+	}
+}
+
+// bindHostPod binds a host pod that waits for a scale up to the node the virtual scheduler found in the meantime, otherwise
+// the host scheduler could choose a different node
+func bindHostPod(ctx context.Context, hostClient kubernetes.Interface, pPod *corev1.Pod, nodeName string) error {
+	err := hostClient.CoreV1().Pods(pPod.Namespace).Bind(ctx, &corev1.Binding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      pPod.Name,
+			Namespace: pPod.Namespace,
+		},
+		Target: corev1.ObjectReference{
+			Kind:       "Node",
+			Name:       nodeName,
+			APIVersion: "v1",
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("bind host pod %s/%s to node %s: %w", pPod.Namespace, pPod.Name, nodeName, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,65 @@
+package pods
+
+import (
+	"context"
+	"testing"
+
+	vclusterconfig "github.com/loft-sh/vcluster/config"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+	"k8s.io/utils/ptr"
+)
+
+func TestIsUnschedulable(t *testing.T) {
+	assert.Assert(t, !isUnschedulable(&corev1.Pod{}))
+	assert.Assert(t, isUnschedulable(&corev1.Pod{Status: corev1.PodStatus{Conditions: []corev1.PodCondition{
+		{Type: corev1.PodScheduled, Status: corev1.ConditionFalse, Reason: corev1.PodReasonUnschedulable},
+	}}}))
+	assert.Assert(t, !isUnschedulable(&corev1.Pod{Status: corev1.PodStatus{Conditions: []corev1.PodCondition{
+		{Type: corev1.PodScheduled, Status: corev1.ConditionFalse, Reason: corev1.PodReasonSchedulingGated},
+	}}}))
+}
+
+func TestApplyClusterAutoscaler(t *testing.T) {
+	clusterAutoscaler := vclusterconfig.SyncPodsClusterAutoscaler{
+		Enabled:           true,
+		Annotations:       map[string]string{"example.com/scale-up": "true"},
+		PriorityClassName: "scale-up",
+	}
+
+	pending := &corev1.Pod{Spec: corev1.PodSpec{Priority: ptr.To(int32(-100))}}
+	applyClusterAutoscaler(clusterAutoscaler, pending)
+	assert.DeepEqual(t, pending.Annotations, map[string]string{"example.com/scale-up": "true"})
+	assert.Equal(t, pending.Spec.PriorityClassName, "scale-up")
+	assert.Assert(t, pending.Spec.Priority == nil)
+
+	// pods with a node don't wait for a scale up
+	scheduled := &corev1.Pod{Spec: corev1.PodSpec{NodeName: "node-1"}}
+	applyClusterAutoscaler(clusterAutoscaler, scheduled)
+	assert.Assert(t, scheduled.Annotations == nil)
+	assert.Equal(t, scheduled.Spec.PriorityClassName, "")
+}
+
+func TestBindHostPod(t *testing.T) {
+	hostClient := fake.NewSimpleClientset()
+	var binding *corev1.Binding
+	hostClient.PrependReactor("create", "pods", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() != "binding" {
+			return false, nil, nil
+		}
+
+		binding = action.(clienttesting.CreateAction).GetObject().(*corev1.Binding)
+		return true, binding, nil
+	})
+
+	pPod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "test-x-test-x-vcluster", Namespace: "vcluster"}}
+	err := bindHostPod(context.Background(), hostClient, pPod, "node-1")
+	assert.NilError(t, err)
+	assert.Equal(t, binding.Name, "test-x-test-x-vcluster")
+	assert.Equal(t, binding.Namespace, "vcluster")
+	assert.Equal(t, binding.Target.Name, "node-1")
+}
@@ -15,52 +15,113 @@ import (
 	"k8s.io/client-go/kubernetes"
 )
 
-// AddEphemeralContainer runs an EphemeralContainer in the target Pod for use as a debug container
-func AddEphemeralContainer(ctx *synccontext.SyncContext, physicalClusterClient kubernetes.Interface, physicalPod *corev1.Pod, virtualPod *corev1.Pod) error {
-	if len(virtualPod.Spec.EphemeralContainers) > 0 {
-		podJS, err := json.Marshal(physicalPod)
-		if err != nil {
-			return fmt.Errorf("error creating JSON for physicalPod: %w", err)
-		}
-		debugPod, debugContainer, err := getEphemeralContainer(physicalPod, virtualPod)
-		if err != nil {
-			return err
-		}
-		ctx.Log.Debugf("new ephemeral container: %#v", debugContainer)
+// AddEphemeralContainers runs the given EphemeralContainers in the target Pod for use as debug containers
+func AddEphemeralContainers(ctx *synccontext.SyncContext, physicalClusterClient kubernetes.Interface, physicalPod *corev1.Pod, ephemeralContainers []corev1.EphemeralContainer) error {
+	if len(ephemeralContainers) == 0 {
+		return nil
+	}
 
-		debugJS, err := json.Marshal(debugPod)
-		if err != nil {
-			return fmt.Errorf("error creating JSON for debug container: %w", err)
-		}
+	podJS, err := json.Marshal(physicalPod)
+	if err != nil {
+		return fmt.Errorf("error creating JSON for physicalPod: %w", err)
+	}
+	debugPod := physicalPod.DeepCopy()
+	debugPod.Spec.EphemeralContainers = append(debugPod.Spec.EphemeralContainers, ephemeralContainers...)
+	for i := range ephemeralContainers {
+		ctx.Log.Debugf("new ephemeral container: %#v", ephemeralContainers[i])
+	}
 
-		patch, err := strategicpatch.CreateTwoWayMergePatch(podJS, debugJS, physicalPod)
-		if err != nil {
-			return fmt.Errorf("error creating patch to add debug container: %w", err)
+	debugJS, err := json.Marshal(debugPod)
+	if err != nil {
+		return fmt.Errorf("error creating JSON for debug container: %w", err)
+	}
+
+	patch, err := strategicpatch.CreateTwoWayMergePatch(podJS, debugJS, physicalPod)
+	if err != nil {
+		return fmt.Errorf("error creating patch to add debug container: %w", err)
+	}
+	ctx.Log.Debugf("generated strategic merge patch for debug container: %s", patch)
+
+	pods := physicalClusterClient.CoreV1().Pods(physicalPod.Namespace)
+	_, err = pods.Patch(ctx.Context, physicalPod.Name, types.StrategicMergePatchType, patch, metav1.PatchOptions{}, "ephemeralcontainers")
+	if err != nil {
+		// The apiserver will return a 404 when the EphemeralContainers feature is disabled because the `/ephemeralcontainers` subresource
+		// is missing. Unlike the 404 returned by a missing physicalPod, the status details will be empty.
+		if serr, ok := lo.ErrorsAs[*kerrors.StatusError](err); ok && serr.Status().Reason == metav1.StatusReasonNotFound && serr.ErrStatus.Details.Name == "" {
+			return fmt.Errorf("ephemeral containers are disabled for this cluster (error from server: %w)", err)
 		}
-		ctx.Log.Debugf("generated strategic merge patch for debug container: %s", patch)
-
-		pods := physicalClusterClient.CoreV1().Pods(physicalPod.Namespace)
-		_, err = pods.Patch(ctx.Context, physicalPod.Name, types.StrategicMergePatchType, patch, metav1.PatchOptions{}, "ephemeralcontainers")
-		if err != nil {
-			// The apiserver will return a 404 when the EphemeralContainers feature is disabled because the `/ephemeralcontainers` subresource
-			// is missing. Unlike the 404 returned by a missing physicalPod, the status details will be empty.
-			if serr, ok := lo.ErrorsAs[*kerrors.StatusError](err); ok && serr.Status().Reason == metav1.StatusReasonNotFound && serr.ErrStatus.Details.Name == "" {
-				return fmt.Errorf("ephemeral containers are disabled for this cluster (error from server: %w)", err)
-			}
-			// The Kind used for the /ephemeralcontainers subresource changed in 1.22. When presented with an unexpected
-			// Kind the api server will respond with a not-registered error. When this happens we can optimistically try
-			// using the old API.
-			if runtime.IsNotRegisteredError(err) {
-				ctx.Log.Infof("Falling back to legacy API because server returned error: %v", err)
-				return addEphemeralContainerLegacy(ctx, physicalClusterClient, physicalPod, debugContainer)
+		// The Kind used for the /ephemeralcontainers subresource changed in 1.22. When presented with an unexpected
+		// Kind the api server will respond with a not-registered error. When this happens we can optimistically try
+		// using the old API.
+		if runtime.IsNotRegisteredError(err) {
+			ctx.Log.Infof("Falling back to legacy API because server returned error: %v", err)
+			for i := range ephemeralContainers {
+				err = addEphemeralContainerLegacy(ctx, physicalClusterClient, physicalPod, &ephemeralContainers[i])
+				if err != nil {
+					return err
+				}
 			}
-			return err
+			return nil
 		}
+		return err
 	}
 
 	return nil
 }
 
+// BackSyncEphemeralContainers adds the ephemeral containers that were started in the host pod, e.g. by a
+// host cluster admin running kubectl debug, to the virtual pod so they show up within the vcluster as well.
+func BackSyncEphemeralContainers(ctx *synccontext.SyncContext, virtualPod *corev1.Pod, ephemeralContainers []corev1.EphemeralContainer) error {
+	if len(ephemeralContainers) == 0 {
+		return nil
+	}
+
+	newPod := virtualPod.DeepCopy()
+	for i := range ephemeralContainers {
+		newPod.Spec.EphemeralContainers = append(newPod.Spec.EphemeralContainers, translateHostEphemeralContainer(virtualPod, ephemeralContainers[i]))
+	}
+
+	ctx.Log.Infof("update virtual pod %s/%s, because host pod has new ephemeral containers", virtualPod.Namespace, virtualPod.Name)
+	return ctx.VirtualClient.SubResource("ephemeralcontainers").Update(ctx.Context, newPod)
+}
+
+// translateHostEphemeralContainer strips everything from a host ephemeral container that references
+// host objects, which do not exist within the virtual cluster.
+func translateHostEphemeralContainer(virtualPod *corev1.Pod, ephemeralContainer corev1.EphemeralContainer) corev1.EphemeralContainer {
+	translated := *ephemeralContainer.DeepCopy()
+	translated.EnvFrom = nil
+	translated.Env = lo.Filter(translated.Env, func(env corev1.EnvVar, _ int) bool {
+		return env.ValueFrom == nil
+	})
+	translated.VolumeMounts = lo.Filter(translated.VolumeMounts, func(volumeMount corev1.VolumeMount, _ int) bool {
+		return lo.ContainsBy(virtualPod.Spec.Volumes, func(volume corev1.Volume) bool {
+			return volume.Name == volumeMount.Name
+		})
+	})
+	if translated.TargetContainerName != "" && !lo.ContainsBy(virtualPod.Spec.Containers, func(container corev1.Container) bool {
+		return container.Name == translated.TargetContainerName
+	}) {
+		translated.TargetContainerName = ""
+	}
+
+	return translated
+}
+
+// missingEphemeralContainers returns the ephemeral containers of the from pod that are not part of the to pod yet.
+// Ephemeral containers can neither be changed nor removed once added, so comparing their names is sufficient.
+func missingEphemeralContainers(from *corev1.Pod, to *corev1.Pod) []corev1.EphemeralContainer {
+	var missing []corev1.EphemeralContainer
+	for _, ephemeralContainer := range from.Spec.EphemeralContainers {
+		if !lo.ContainsBy(to.Spec.EphemeralContainers, func(existing corev1.EphemeralContainer) bool {
+			return existing.Name == ephemeralContainer.Name
+		}) {
+			missing = append(missing, ephemeralContainer)
+		}
+	}
+
+	return missing
+}
+
 // addEphemeralContainerLegacy adds an ephemeral container using the pre-1.22 /ephemeralcontainers API
 // This may be removed when we no longer wish to support releases prior to 1.22.
 func addEphemeralContainerLegacy(ctx *synccontext.SyncContext, physicalClusterClient kubernetes.Interface, physicalPod *corev1.Pod, debugContainer *corev1.EphemeralContainer) error {
@@ -92,13 +153,3 @@ func addEphemeralContainerLegacy(ctx *synccontext.SyncContext, physicalClusterCl
 	}
 	return nil
 }
-
-// getEphemeralContainer returns a debugging pod and an EphemeralContainer suitable for use as a debug container
-// in the given pod.
-func getEphemeralContainer(physicalPod *corev1.Pod, virtualPod *corev1.Pod) (*corev1.Pod, *corev1.EphemeralContainer, error) {
-	ephemeralContainer := virtualPod.Spec.EphemeralContainers[len(virtualPod.Spec.EphemeralContainers)-1]
-	copied := physicalPod.DeepCopy()
-	ephemeralContainer.TargetContainerName = ""
-	copied.Spec.EphemeralContainers = append(copied.Spec.EphemeralContainers, ephemeralContainer)
-	return copied, &ephemeralContainer, nil
-}
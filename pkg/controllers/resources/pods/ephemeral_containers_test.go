@@ -0,0 +1,111 @@
+package pods
+
+import (
+	"context"
+	"testing"
+
+	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
+	"github.com/loft-sh/vcluster/pkg/util/loghelper"
+	testingutil "github.com/loft-sh/vcluster/pkg/util/testing"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestMissingEphemeralContainers(t *testing.T) {
+	ephemeralContainer := func(name string) corev1.EphemeralContainer {
+		return corev1.EphemeralContainer{EphemeralContainerCommon: corev1.EphemeralContainerCommon{Name: name, Image: "busybox"}}
+	}
+	podWith := func(names ...string) *corev1.Pod {
+		pod := &corev1.Pod{}
+		for _, name := range names {
+			pod.Spec.EphemeralContainers = append(pod.Spec.EphemeralContainers, ephemeralContainer(name))
+		}
+		return pod
+	}
+
+	testCases := []struct {
+		name     string
+		from     *corev1.Pod
+		to       *corev1.Pod
+		expected []string
+	}{
+		{
+			name: "none",
+			from: podWith(),
+			to:   podWith("debugger"),
+		},
+		{
+			name: "in sync",
+			from: podWith("debugger"),
+			to:   podWith("debugger"),
+		},
+		{
+			name:     "new containers",
+			from:     podWith("debugger-1", "debugger-2", "debugger-3"),
+			to:       podWith("debugger-2"),
+			expected: []string{"debugger-1", "debugger-3"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var names []string
+			for _, missing := range missingEphemeralContainers(testCase.from, testCase.to) {
+				names = append(names, missing.Name)
+			}
+			assert.DeepEqual(t, names, testCase.expected)
+		})
+	}
+}
+
+func TestTranslateHostEphemeralContainer(t *testing.T) {
+	virtualPod := &corev1.Pod{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "app"}},
+			Volumes:    []corev1.Volume{{Name: "data"}},
+		},
+	}
+
+	translated := translateHostEphemeralContainer(virtualPod, corev1.EphemeralContainer{
+		EphemeralContainerCommon: corev1.EphemeralContainerCommon{
+			Name:    "debugger",
+			Image:   "busybox",
+			EnvFrom: []corev1.EnvFromSource{{SecretRef: &corev1.SecretEnvSource{}}},
+			Env: []corev1.EnvVar{
+				{Name: "PLAIN", Value: "value"},
+				{Name: "FROM_SECRET", ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{}}},
+			},
+			VolumeMounts: []corev1.VolumeMount{{Name: "data"}, {Name: "host-only"}},
+		},
+		TargetContainerName: "host-sidecar",
+	})
+
+	assert.Equal(t, translated.Name, "debugger")
+	assert.Assert(t, translated.EnvFrom == nil)
+	assert.DeepEqual(t, translated.Env, []corev1.EnvVar{{Name: "PLAIN", Value: "value"}})
+	assert.DeepEqual(t, translated.VolumeMounts, []corev1.VolumeMount{{Name: "data"}})
+	assert.Equal(t, translated.TargetContainerName, "")
+}
+
+func TestSyncEphemeralContainersBackSyncDisabled(t *testing.T) {
+	vPod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"}}
+	pPod := vPod.DeepCopy()
+	pPod.Spec.EphemeralContainers = []corev1.EphemeralContainer{{EphemeralContainerCommon: corev1.EphemeralContainerCommon{Name: "debugger", Image: "busybox"}}}
+
+	vClient := testingutil.NewFakeClient(testingutil.NewScheme(), vPod.DeepCopy())
+	s := &podSyncer{}
+	changed, err := s.syncEphemeralContainers(&synccontext.SyncContext{
+		Context:       context.Background(),
+		Log:           loghelper.New("test"),
+		VirtualClient: vClient,
+	}, vPod, pPod)
+	assert.NilError(t, err)
+	assert.Assert(t, !changed)
+
+	// host ephemeral containers are not added to the virtual pod unless back syncing them is enabled
+	updated := &corev1.Pod{}
+	assert.NilError(t, vClient.Get(context.Background(), types.NamespacedName{Name: "test", Namespace: "default"}, updated))
+	assert.Equal(t, len(updated.Spec.EphemeralContainers), 0)
+}
@@ -0,0 +1,149 @@
+package pods
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/loft-sh/vcluster/pkg/util/resources"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func parseExtendedResourceLimits(limits map[string]interface{}) (corev1.ResourceList, error) {
+	if len(limits) == 0 {
+		return nil, nil
+	}
+
+	parsed := corev1.ResourceList{}
+	for name, limit := range limits {
+		quantity, err := resource.ParseQuantity(fmt.Sprint(limit))
+		if err != nil {
+			return nil, fmt.Errorf("policies.extendedResources.limits.%s: %w", name, err)
+		}
+
+		parsed[corev1.ResourceName(name)] = quantity
+	}
+
+	return parsed, nil
+}
+
+// extendedResourceReservationTimeout is how long a created host pod is counted towards the limits while it is not in
+// the cache yet
+const extendedResourceReservationTimeout = time.Minute
+
+// extendedResourceLimiter caps the extended resources of the host pods. Pods are checked and reserved while holding the
+// lock, so pods synced at the same time can't exceed the limits together. Reservations are kept until the host pod
+// shows up in the cache.
+type extendedResourceLimiter struct {
+	limits corev1.ResourceList
+
+	m        sync.Mutex
+	reserved map[types.NamespacedName]extendedResourceReservation
+}
+
+type extendedResourceReservation struct {
+	requests corev1.ResourceList
+	created  time.Time
+}
+
+func newExtendedResourceLimiter(limits corev1.ResourceList) *extendedResourceLimiter {
+	return &extendedResourceLimiter{
+		limits:   limits,
+		reserved: map[types.NamespacedName]extendedResourceReservation{},
+	}
+}
+
+// reserve returns why syncing the virtual pod would exceed the extended resource limits or an empty string. If the pod
+// fits, its requests are reserved for the host pod with the given name until the pod is in the cache or the
+// reservation is released. The host pods of the virtual cluster that didn't terminate yet count towards the limits.
+func (l *extendedResourceLimiter) reserve(ctx context.Context, hostClient client.Client, vPod *corev1.Pod, pName types.NamespacedName) (string, error) {
+	if l == nil || len(l.limits) == 0 {
+		return "", nil
+	}
+
+	requests := resources.PodExtendedResourceRequests(vPod)
+	names := []string{}
+	for name := range requests {
+		if _, ok := l.limits[name]; ok {
+			names = append(names, string(name))
+		}
+	}
+	if len(names) == 0 {
+		return "", nil
+	}
+	sort.Strings(names)
+
+	l.m.Lock()
+	defer l.m.Unlock()
+
+	podList := &corev1.PodList{}
+	err := hostClient.List(ctx, podList)
+	if err != nil {
+		return "", fmt.Errorf("list host pods: %w", err)
+	}
+
+	used := corev1.ResourceList{}
+	cached := map[types.NamespacedName]bool{}
+	for i := range podList.Items {
+		pPod := &podList.Items[i]
+		if !translate.Default.IsManaged(pPod) {
+			continue
+		}
+
+		cached[types.NamespacedName{Namespace: pPod.Namespace, Name: pPod.Name}] = true
+		if pPod.Status.Phase == corev1.PodSucceeded || pPod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+
+		addResources(used, resources.PodExtendedResourceRequests(pPod))
+	}
+
+	// count the created host pods that are not in the cache yet
+	for key, reservation := range l.reserved {
+		if cached[key] || time.Since(reservation.created) > extendedResourceReservationTimeout {
+			delete(l.reserved, key)
+			continue
+		}
+
+		addResources(used, reservation.requests)
+	}
+
+	for _, name := range names {
+		resourceName := corev1.ResourceName(name)
+		total := used[resourceName]
+		total.Add(requests[resourceName])
+		if limit := l.limits[resourceName]; total.Cmp(limit) > 0 {
+			usedQuantity := used[resourceName]
+			requested := requests[resourceName]
+			return fmt.Sprintf("requested %s %s, used %s, limited to %s", requested.String(), name, usedQuantity.String(), limit.String()), nil
+		}
+	}
+
+	l.reserved[pName] = extendedResourceReservation{requests: requests, created: time.Now()}
+	return "", nil
+}
+
+// release removes the reservation of a host pod that wasn't created
+func (l *extendedResourceLimiter) release(pName types.NamespacedName) {
+	if l == nil {
+		return
+	}
+
+	l.m.Lock()
+	defer l.m.Unlock()
+	delete(l.reserved, pName)
+}
+
+func addResources(list corev1.ResourceList, add corev1.ResourceList) {
+	for name, quantity := range add {
+		sum := list[name]
+		sum.Add(quantity)
+		list[name] = sum
+	}
+}
@@ -0,0 +1,107 @@
+package pods
+
+import (
+	"context"
+	"testing"
+
+	generictesting "github.com/loft-sh/vcluster/pkg/controllers/syncer/testing"
+	testingutil "github.com/loft-sh/vcluster/pkg/util/testing"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestExtendedResourceLimiter(t *testing.T) {
+	translate.Default = translate.NewSingleNamespaceTranslator(generictesting.DefaultTestTargetNamespace)
+
+	limits, err := parseExtendedResourceLimits(map[string]interface{}{"nvidia.com/gpu": 4})
+	assert.NilError(t, err)
+
+	gpuPod := func(name string, gpus string, phase corev1.PodPhase) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        translate.Default.PhysicalName(name, "test"),
+				Namespace:   generictesting.DefaultTestTargetNamespace,
+				Labels:      map[string]string{translate.MarkerLabel: translate.VClusterName},
+				Annotations: map[string]string{translate.NameAnnotation: name, translate.NamespaceAnnotation: "test"},
+			},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{
+					Name: "cuda",
+					Resources: corev1.ResourceRequirements{
+						Limits: corev1.ResourceList{"nvidia.com/gpu": resource.MustParse(gpus)},
+					},
+				}},
+			},
+			Status: corev1.PodStatus{Phase: phase},
+		}
+	}
+	unmanaged := gpuPod("unmanaged", "4", corev1.PodRunning)
+	unmanaged.Labels = nil
+
+	testCases := []struct {
+		name     string
+		hostPods []runtime.Object
+		vPod     *corev1.Pod
+
+		expected string
+	}{
+		{
+			name:     "fits",
+			hostPods: []runtime.Object{gpuPod("a", "2", corev1.PodRunning), gpuPod("b", "4", corev1.PodSucceeded), unmanaged},
+			vPod:     gpuPod("c", "2", ""),
+		},
+		{
+			name:     "exceeds",
+			hostPods: []runtime.Object{gpuPod("a", "2", corev1.PodRunning), gpuPod("b", "1", corev1.PodPending)},
+			vPod:     gpuPod("c", "2", ""),
+
+			expected: "requested 2 nvidia.com/gpu, used 3, limited to 4",
+		},
+		{
+			name:     "no extended resources",
+			hostPods: []runtime.Object{gpuPod("a", "4", corev1.PodRunning)},
+			vPod:     &corev1.Pod{Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "app"}}}},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			hostClient := testingutil.NewFakeClient(testingutil.NewScheme(), testCase.hostPods...)
+			exceeded, err := newExtendedResourceLimiter(limits).reserve(context.Background(), hostClient, testCase.vPod, types.NamespacedName{Namespace: generictesting.DefaultTestTargetNamespace, Name: "c"})
+			assert.NilError(t, err)
+			assert.Equal(t, exceeded, testCase.expected)
+		})
+	}
+
+	// pods that were created but are not in the cache yet count towards the limits
+	hostClient := testingutil.NewFakeClient(testingutil.NewScheme(), gpuPod("a", "2", corev1.PodRunning))
+	limiter := newExtendedResourceLimiter(limits)
+	bName := types.NamespacedName{Namespace: generictesting.DefaultTestTargetNamespace, Name: translate.Default.PhysicalName("b", "test")}
+	exceeded, err := limiter.reserve(context.Background(), hostClient, gpuPod("b", "2", ""), bName)
+	assert.NilError(t, err)
+	assert.Equal(t, exceeded, "")
+	exceeded, err = limiter.reserve(context.Background(), hostClient, gpuPod("c", "1", ""), types.NamespacedName{Namespace: generictesting.DefaultTestTargetNamespace, Name: "c"})
+	assert.NilError(t, err)
+	assert.Equal(t, exceeded, "requested 1 nvidia.com/gpu, used 4, limited to 4")
+
+	// released reservations don't count anymore
+	limiter.release(bName)
+	exceeded, err = limiter.reserve(context.Background(), hostClient, gpuPod("c", "1", ""), types.NamespacedName{Namespace: generictesting.DefaultTestTargetNamespace, Name: "c"})
+	assert.NilError(t, err)
+	assert.Equal(t, exceeded, "")
+
+	// reservations are dropped once the pod is in the cache, so it isn't counted twice
+	_, err = limiter.reserve(context.Background(), hostClient, gpuPod("b", "2", ""), bName)
+	assert.NilError(t, err)
+	assert.NilError(t, hostClient.Create(context.Background(), gpuPod("b", "2", corev1.PodRunning)))
+	exceeded, err = limiter.reserve(context.Background(), hostClient, gpuPod("d", "1", ""), types.NamespacedName{Namespace: generictesting.DefaultTestTargetNamespace, Name: "d"})
+	assert.NilError(t, err)
+	assert.Equal(t, exceeded, "requested 1 nvidia.com/gpu, used 5, limited to 4")
+	_, ok := limiter.reserved[bName]
+	assert.Assert(t, !ok)
+}
@@ -0,0 +1,93 @@
+package pods
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+
+	vclusterconfig "github.com/loft-sh/vcluster/config"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// ImagePullSecretSourceAnnotation marks image pull secrets vCluster copied from sync.toHost.pods.imagePullSecrets[].fromSecret,
+// only these secrets are updated when the source changes. The copies also carry the marker label and the vCluster owner,
+// so they are removed together with the vCluster. They have no virtual name annotation, so the syncer doesn't mistake
+// them for synced secrets without virtual counterpart.
+const ImagePullSecretSourceAnnotation = "vcluster.loft.sh/image-pull-secret-source"
+
+// ensureImagePullSecrets adds the configured image pull secrets to the host pod and copies the secrets that have a source
+// into the host namespace of the pod. The host client is not cached, as the source secrets are usually in a namespace
+// the syncer doesn't watch.
+func ensureImagePullSecrets(ctx context.Context, hostClient kubernetes.Interface, imagePullSecrets []vclusterconfig.SyncPodsImagePullSecret, pPod *corev1.Pod) error {
+	for _, imagePullSecret := range imagePullSecrets {
+		if imagePullSecret.FromSecret.Name != "" {
+			err := copyImagePullSecret(ctx, hostClient, imagePullSecret, pPod.Namespace)
+			if err != nil {
+				return err
+			}
+		}
+
+		if !slices.ContainsFunc(pPod.Spec.ImagePullSecrets, func(ref corev1.LocalObjectReference) bool { return ref.Name == imagePullSecret.Name }) {
+			pPod.Spec.ImagePullSecrets = append(pPod.Spec.ImagePullSecrets, corev1.LocalObjectReference{Name: imagePullSecret.Name})
+		}
+	}
+
+	return nil
+}
+
+func copyImagePullSecret(ctx context.Context, hostClient kubernetes.Interface, imagePullSecret vclusterconfig.SyncPodsImagePullSecret, namespace string) error {
+	source, err := hostClient.CoreV1().Secrets(imagePullSecret.FromSecret.Namespace).Get(ctx, imagePullSecret.FromSecret.Name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("get image pull secret %s/%s: %w", imagePullSecret.FromSecret.Namespace, imagePullSecret.FromSecret.Name, err)
+	}
+	sourceName := source.Namespace + "/" + source.Name
+
+	existing, err := hostClient.CoreV1().Secrets(namespace).Get(ctx, imagePullSecret.Name, metav1.GetOptions{})
+	if kerrors.IsNotFound(err) {
+		_, err = hostClient.CoreV1().Secrets(namespace).Create(ctx, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            imagePullSecret.Name,
+				Namespace:       namespace,
+				Labels:          map[string]string{translate.MarkerLabel: translate.VClusterName},
+				Annotations:     map[string]string{ImagePullSecretSourceAnnotation: sourceName},
+				OwnerReferences: translate.GetOwnerReference(nil),
+			},
+			Type: source.Type,
+			Data: source.Data,
+		}, metav1.CreateOptions{})
+		if err != nil && !kerrors.IsAlreadyExists(err) {
+			return fmt.Errorf("create image pull secret %s/%s: %w", namespace, imagePullSecret.Name, err)
+		}
+
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("get image pull secret %s/%s: %w", namespace, imagePullSecret.Name, err)
+	}
+
+	// secrets that weren't copied by vCluster are used as they are
+	if existing.Annotations[ImagePullSecretSourceAnnotation] != sourceName {
+		return nil
+	} else if reflect.DeepEqual(existing.Data, source.Data) && existing.Labels[translate.MarkerLabel] == translate.VClusterName {
+		return nil
+	}
+
+	if existing.Labels == nil {
+		existing.Labels = map[string]string{}
+	}
+	existing.Labels[translate.MarkerLabel] = translate.VClusterName
+	if len(existing.OwnerReferences) == 0 {
+		existing.OwnerReferences = translate.GetOwnerReference(nil)
+	}
+	existing.Data = source.Data
+	_, err = hostClient.CoreV1().Secrets(namespace).Update(ctx, existing, metav1.UpdateOptions{})
+	if err != nil {
+		return fmt.Errorf("update image pull secret %s/%s: %w", namespace, imagePullSecret.Name, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,137 @@
+package pods
+
+import (
+	"context"
+	"testing"
+
+	vclusterconfig "github.com/loft-sh/vcluster/config"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestEnsureImagePullSecrets(t *testing.T) {
+	translate.VClusterName = "my-vcluster"
+	translate.Owner = &corev1.Service{
+		TypeMeta:   metav1.TypeMeta{Kind: "Service", APIVersion: "v1"},
+		ObjectMeta: metav1.ObjectMeta{Name: "my-vcluster", Namespace: "test", UID: types.UID("owner")},
+	}
+	defer func() { translate.Owner = nil }()
+
+	source := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "registry-credentials", Namespace: "platform"},
+		Type:       corev1.SecretTypeDockerConfigJson,
+		Data:       map[string][]byte{corev1.DockerConfigJsonKey: []byte(`{"auths":{}}`)},
+	}
+	imagePullSecrets := []vclusterconfig.SyncPodsImagePullSecret{
+		{
+			Name: "platform-registry",
+			FromSecret: vclusterconfig.SyncPodsImagePullSecretSource{
+				Namespace: "platform",
+				Name:      "registry-credentials",
+			},
+		},
+		{
+			Name: "existing",
+		},
+	}
+
+	testCases := []struct {
+		name string
+
+		existing       []*corev1.Secret
+		podPullSecrets []corev1.LocalObjectReference
+
+		expectedPullSecrets []corev1.LocalObjectReference
+		expectedData        string
+		expectedAnnotation  string
+		expectedMarker      string
+	}{
+		{
+			name: "copy secret",
+
+			expectedPullSecrets: []corev1.LocalObjectReference{{Name: "platform-registry"}, {Name: "existing"}},
+			expectedData:        `{"auths":{}}`,
+			expectedAnnotation:  "platform/registry-credentials",
+			expectedMarker:      "my-vcluster",
+		},
+		{
+			name:           "keep existing pull secrets",
+			podPullSecrets: []corev1.LocalObjectReference{{Name: "tenant"}, {Name: "existing"}},
+
+			expectedPullSecrets: []corev1.LocalObjectReference{{Name: "tenant"}, {Name: "existing"}, {Name: "platform-registry"}},
+			expectedData:        `{"auths":{}}`,
+			expectedAnnotation:  "platform/registry-credentials",
+			expectedMarker:      "my-vcluster",
+		},
+		{
+			name: "update copied secret",
+			existing: []*corev1.Secret{{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "platform-registry",
+					Namespace:   "test",
+					Annotations: map[string]string{ImagePullSecretSourceAnnotation: "platform/registry-credentials"},
+				},
+				Data: map[string][]byte{corev1.DockerConfigJsonKey: []byte("old")},
+			}},
+
+			expectedPullSecrets: []corev1.LocalObjectReference{{Name: "platform-registry"}, {Name: "existing"}},
+			expectedData:        `{"auths":{}}`,
+			expectedAnnotation:  "platform/registry-credentials",
+			expectedMarker:      "my-vcluster",
+		},
+		{
+			name: "keep foreign secret",
+			existing: []*corev1.Secret{{
+				ObjectMeta: metav1.ObjectMeta{Name: "platform-registry", Namespace: "test"},
+				Data:       map[string][]byte{corev1.DockerConfigJsonKey: []byte("foreign")},
+			}},
+
+			expectedPullSecrets: []corev1.LocalObjectReference{{Name: "platform-registry"}, {Name: "existing"}},
+			expectedData:        "foreign",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			hostClient := fake.NewSimpleClientset(source.DeepCopy())
+			for _, secret := range testCase.existing {
+				_, err := hostClient.CoreV1().Secrets(secret.Namespace).Create(context.Background(), secret, metav1.CreateOptions{})
+				assert.NilError(t, err)
+			}
+
+			pPod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test"},
+				Spec:       corev1.PodSpec{ImagePullSecrets: testCase.podPullSecrets},
+			}
+			err := ensureImagePullSecrets(context.Background(), hostClient, imagePullSecrets, pPod)
+			assert.NilError(t, err)
+			assert.DeepEqual(t, pPod.Spec.ImagePullSecrets, testCase.expectedPullSecrets)
+
+			copied, err := hostClient.CoreV1().Secrets("test").Get(context.Background(), "platform-registry", metav1.GetOptions{})
+			assert.NilError(t, err)
+			assert.Equal(t, string(copied.Data[corev1.DockerConfigJsonKey]), testCase.expectedData)
+			assert.Equal(t, copied.Annotations[ImagePullSecretSourceAnnotation], testCase.expectedAnnotation)
+			assert.Equal(t, copied.Labels[translate.MarkerLabel], testCase.expectedMarker)
+			if testCase.expectedMarker != "" {
+				assert.Equal(t, len(copied.OwnerReferences), 1)
+				assert.Equal(t, copied.OwnerReferences[0].UID, types.UID("owner"))
+			}
+		})
+	}
+}
+
+func TestEnsureImagePullSecretsMissingSource(t *testing.T) {
+	imagePullSecrets := []vclusterconfig.SyncPodsImagePullSecret{{
+		Name:       "platform-registry",
+		FromSecret: vclusterconfig.SyncPodsImagePullSecretSource{Namespace: "platform", Name: "registry-credentials"},
+	}}
+
+	pPod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test"}}
+	err := ensureImagePullSecrets(context.Background(), fake.NewSimpleClientset(), imagePullSecrets, pPod)
+	assert.ErrorContains(t, err, "get image pull secret platform/registry-credentials")
+	assert.Equal(t, len(pPod.Spec.ImagePullSecrets), 0)
+}
@@ -0,0 +1,116 @@
+package pods
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	vclusterconfig "github.com/loft-sh/vcluster/config"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// podPatch is the parsed form of a sync.toHost.pods.patches entry
+type podPatch struct {
+	selector labels.Selector
+
+	env                       []corev1.EnvVar
+	tolerations               []corev1.Toleration
+	nodeSelector              map[string]string
+	runtimeClassName          string
+	topologySpreadConstraints []corev1.TopologySpreadConstraint
+	priorityClassName         string
+}
+
+func parsePodPatches(patches []vclusterconfig.SyncPodsPatch) ([]podPatch, error) {
+	parsed := make([]podPatch, 0, len(patches))
+	for i, patch := range patches {
+		parsedPatch := podPatch{
+			selector:          labels.Everything(),
+			nodeSelector:      patch.NodeSelector,
+			runtimeClassName:  patch.RuntimeClassName,
+			priorityClassName: patch.PriorityClassName,
+		}
+		if patch.Selector != nil && len(patch.Selector.LabelSelector) > 0 {
+			parsedPatch.selector = labels.SelectorFromSet(patch.Selector.LabelSelector)
+		}
+
+		err := convertPatchField(patch.Env, &parsedPatch.env)
+		if err != nil {
+			return nil, fmt.Errorf("sync.toHost.pods.patches[%d].env: %w", i, err)
+		}
+		err = convertPatchField(patch.Tolerations, &parsedPatch.tolerations)
+		if err != nil {
+			return nil, fmt.Errorf("sync.toHost.pods.patches[%d].tolerations: %w", i, err)
+		}
+		err = convertPatchField(patch.TopologySpreadConstraints, &parsedPatch.topologySpreadConstraints)
+		if err != nil {
+			return nil, fmt.Errorf("sync.toHost.pods.patches[%d].topologySpreadConstraints: %w", i, err)
+		}
+
+		parsed = append(parsed, parsedPatch)
+	}
+
+	return parsed, nil
+}
+
+func convertPatchField(from []map[string]interface{}, to interface{}) error {
+	if len(from) == 0 {
+		return nil
+	}
+
+	raw, err := json.Marshal(from)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(raw, to)
+}
+
+// applyPodPatches applies the patches that select the virtual pod to the host pod
+func applyPodPatches(patches []podPatch, vPod, pPod *corev1.Pod) {
+	for _, patch := range patches {
+		if !patch.selector.Matches(labels.Set(vPod.Labels)) {
+			continue
+		}
+
+		if len(patch.env) > 0 {
+			for i := range pPod.Spec.InitContainers {
+				pPod.Spec.InitContainers[i].Env = mergeEnv(pPod.Spec.InitContainers[i].Env, patch.env)
+			}
+			for i := range pPod.Spec.Containers {
+				pPod.Spec.Containers[i].Env = mergeEnv(pPod.Spec.Containers[i].Env, patch.env)
+			}
+		}
+
+		pPod.Spec.Tolerations = append(pPod.Spec.Tolerations, patch.tolerations...)
+		if len(patch.nodeSelector) > 0 {
+			if pPod.Spec.NodeSelector == nil {
+				pPod.Spec.NodeSelector = map[string]string{}
+			}
+			for k, v := range patch.nodeSelector {
+				pPod.Spec.NodeSelector[k] = v
+			}
+		}
+		if patch.runtimeClassName != "" {
+			runtimeClassName := patch.runtimeClassName
+			pPod.Spec.RuntimeClassName = &runtimeClassName
+		}
+		pPod.Spec.TopologySpreadConstraints = append(pPod.Spec.TopologySpreadConstraints, patch.topologySpreadConstraints...)
+		if patch.priorityClassName != "" {
+			// the priority is resolved by the host cluster from the priority class
+			pPod.Spec.PriorityClassName = patch.priorityClassName
+			pPod.Spec.Priority = nil
+		}
+	}
+}
+
+func mergeEnv(env []corev1.EnvVar, patchEnv []corev1.EnvVar) []corev1.EnvVar {
+	for _, envVar := range patchEnv {
+		if !slices.ContainsFunc(env, func(existing corev1.EnvVar) bool { return existing.Name == envVar.Name }) {
+			env = append(env, envVar)
+		}
+	}
+
+	return env
+}
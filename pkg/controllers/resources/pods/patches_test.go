@@ -0,0 +1,95 @@
+package pods
+
+import (
+	"testing"
+
+	vclusterconfig "github.com/loft-sh/vcluster/config"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+func TestApplyPodPatches(t *testing.T) {
+	patches, err := parsePodPatches([]vclusterconfig.SyncPodsPatch{
+		{
+			Env: []map[string]interface{}{
+				{"name": "HTTP_PROXY", "value": "http://proxy:3128"},
+			},
+			Tolerations: []map[string]interface{}{
+				{"key": "tenant", "operator": "Equal", "value": "team-a", "effect": "NoSchedule"},
+			},
+			NodeSelector: map[string]string{"pool": "tenants"},
+		},
+		{
+			Selector:         &vclusterconfig.Selector{LabelSelector: map[string]string{"app": "gpu"}},
+			RuntimeClassName: "nvidia",
+			TopologySpreadConstraints: []map[string]interface{}{
+				{"maxSkew": 1, "topologyKey": "topology.kubernetes.io/zone", "whenUnsatisfiable": "ScheduleAnyway"},
+			},
+			PriorityClassName: "gpu-workloads",
+		},
+	})
+	assert.NilError(t, err)
+
+	testCases := []struct {
+		name   string
+		labels map[string]string
+
+		expectedSpec corev1.PodSpec
+	}{
+		{
+			name: "patch all pods",
+
+			expectedSpec: corev1.PodSpec{
+				InitContainers: []corev1.Container{{Name: "init", Env: []corev1.EnvVar{{Name: "HTTP_PROXY", Value: "http://proxy:3128"}}}},
+				Containers:     []corev1.Container{{Name: "app", Env: []corev1.EnvVar{{Name: "HTTP_PROXY", Value: "custom"}}}},
+				Tolerations:    []corev1.Toleration{{Key: "tenant", Operator: corev1.TolerationOpEqual, Value: "team-a", Effect: corev1.TaintEffectNoSchedule}},
+				NodeSelector:   map[string]string{"disk": "ssd", "pool": "tenants"},
+				Priority:       ptr.To(int32(100)),
+			},
+		},
+		{
+			name:   "patch selected pods",
+			labels: map[string]string{"app": "gpu"},
+
+			expectedSpec: corev1.PodSpec{
+				InitContainers:   []corev1.Container{{Name: "init", Env: []corev1.EnvVar{{Name: "HTTP_PROXY", Value: "http://proxy:3128"}}}},
+				Containers:       []corev1.Container{{Name: "app", Env: []corev1.EnvVar{{Name: "HTTP_PROXY", Value: "custom"}}}},
+				Tolerations:      []corev1.Toleration{{Key: "tenant", Operator: corev1.TolerationOpEqual, Value: "team-a", Effect: corev1.TaintEffectNoSchedule}},
+				NodeSelector:     map[string]string{"disk": "ssd", "pool": "tenants"},
+				RuntimeClassName: ptr.To("nvidia"),
+				TopologySpreadConstraints: []corev1.TopologySpreadConstraint{
+					{MaxSkew: 1, TopologyKey: "topology.kubernetes.io/zone", WhenUnsatisfiable: corev1.ScheduleAnyway},
+				},
+				PriorityClassName: "gpu-workloads",
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			vPod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test", Labels: testCase.labels}}
+			pPod := &corev1.Pod{
+				Spec: corev1.PodSpec{
+					InitContainers: []corev1.Container{{Name: "init"}},
+					Containers:     []corev1.Container{{Name: "app", Env: []corev1.EnvVar{{Name: "HTTP_PROXY", Value: "custom"}}}},
+					NodeSelector:   map[string]string{"disk": "ssd"},
+					Priority:       ptr.To(int32(100)),
+				},
+			}
+
+			applyPodPatches(patches, vPod, pPod)
+			assert.DeepEqual(t, pPod.Spec, testCase.expectedSpec)
+		})
+	}
+}
+
+func TestParsePodPatchesInvalid(t *testing.T) {
+	_, err := parsePodPatches([]vclusterconfig.SyncPodsPatch{
+		{
+			Tolerations: []map[string]interface{}{{"key": 1}},
+		},
+	})
+	assert.ErrorContains(t, err, "sync.toHost.pods.patches[0].tolerations")
+}
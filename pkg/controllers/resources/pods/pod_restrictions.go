@@ -0,0 +1,204 @@
+package pods
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	vclusterconfig "github.com/loft-sh/vcluster/config"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	PodRestrictionActionDeny    = "deny"
+	PodRestrictionActionRewrite = "rewrite"
+)
+
+// podRestrictions is the parsed form of policies.podRestrictions
+type podRestrictions struct {
+	hostPath       vclusterconfig.PodRestrictionHostPath
+	hostNamespaces vclusterconfig.PodRestrictionRule
+	privileged     vclusterconfig.PodRestrictionRule
+}
+
+func parsePodRestrictions(policy vclusterconfig.PodRestrictions) *podRestrictions {
+	if policy.HostPath.Action == "" && policy.HostNamespaces.Action == "" && policy.Privileged.Action == "" {
+		return nil
+	}
+
+	return &podRestrictions{
+		hostPath:       policy.HostPath,
+		hostNamespaces: policy.HostNamespaces,
+		privileged:     policy.Privileged,
+	}
+}
+
+// violation returns the restricted settings of the virtual pod that are denied or an empty string if there are none
+func (p *podRestrictions) violation(vPod *corev1.Pod) string {
+	if p == nil {
+		return ""
+	}
+
+	violations := []string{}
+	if restricted(p.hostPath.PodRestrictionRule, PodRestrictionActionDeny, vPod) {
+		for _, volume := range p.deniedHostPathVolumes(vPod) {
+			violations = append(violations, fmt.Sprintf("hostPath volume %s (%s)", volume.Name, volume.HostPath.Path))
+		}
+	}
+	if restricted(p.hostNamespaces, PodRestrictionActionDeny, vPod) {
+		violations = append(violations, hostNamespaces(vPod)...)
+	}
+	if restricted(p.privileged, PodRestrictionActionDeny, vPod) {
+		for _, name := range privilegedContainers(vPod) {
+			violations = append(violations, "privileged container "+name)
+		}
+	}
+
+	return strings.Join(violations, ", ")
+}
+
+// rewrite removes the restricted settings of the virtual pod from the host pod and returns what was removed
+func (p *podRestrictions) rewrite(vPod, pPod *corev1.Pod) []string {
+	if p == nil {
+		return nil
+	}
+
+	rewritten := []string{}
+	if restricted(p.hostPath.PodRestrictionRule, PodRestrictionActionRewrite, vPod) {
+		// the translator keeps the volume names, volumes added by the translator itself are left untouched
+		for _, volume := range p.deniedHostPathVolumes(vPod) {
+			for i := range pPod.Spec.Volumes {
+				if pPod.Spec.Volumes[i].Name == volume.Name {
+					pPod.Spec.Volumes[i].VolumeSource = corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}
+					rewritten = append(rewritten, fmt.Sprintf("hostPath volume %s (%s)", volume.Name, volume.HostPath.Path))
+				}
+			}
+		}
+	}
+	if restricted(p.hostNamespaces, PodRestrictionActionRewrite, vPod) {
+		rewritten = append(rewritten, hostNamespaces(vPod)...)
+		pPod.Spec.HostNetwork = false
+		pPod.Spec.HostPID = false
+		pPod.Spec.HostIPC = false
+	}
+	if restricted(p.privileged, PodRestrictionActionRewrite, vPod) {
+		for _, name := range privilegedContainers(vPod) {
+			rewritten = append(rewritten, "privileged container "+name)
+		}
+		for i := range pPod.Spec.InitContainers {
+			pPod.Spec.InitContainers[i].SecurityContext = unprivileged(pPod.Spec.InitContainers[i].SecurityContext)
+		}
+		for i := range pPod.Spec.Containers {
+			pPod.Spec.Containers[i].SecurityContext = unprivileged(pPod.Spec.Containers[i].SecurityContext)
+		}
+		for i := range pPod.Spec.EphemeralContainers {
+			pPod.Spec.EphemeralContainers[i].SecurityContext = unprivileged(pPod.Spec.EphemeralContainers[i].SecurityContext)
+		}
+	}
+
+	return rewritten
+}
+
+// filterEphemeralContainers applies the privileged restriction to ephemeral containers that are added to an already
+// synced pod. It returns the containers that may be added and the denied or rewritten containers.
+func (p *podRestrictions) filterEphemeralContainers(vPod *corev1.Pod, containers []corev1.EphemeralContainer) ([]corev1.EphemeralContainer, []string, []string) {
+	if p == nil || p.privileged.Action == "" || !restricted(p.privileged, p.privileged.Action, vPod) {
+		return containers, nil, nil
+	}
+
+	allowed := []corev1.EphemeralContainer{}
+	denied := []string{}
+	rewritten := []string{}
+	for _, container := range containers {
+		if !isPrivileged(container.SecurityContext) {
+			allowed = append(allowed, container)
+		} else if p.privileged.Action == PodRestrictionActionDeny {
+			denied = append(denied, "privileged container "+container.Name)
+		} else {
+			container.SecurityContext = unprivileged(container.SecurityContext)
+			allowed = append(allowed, container)
+			rewritten = append(rewritten, "privileged container "+container.Name)
+		}
+	}
+
+	return allowed, denied, rewritten
+}
+
+func (p *podRestrictions) deniedHostPathVolumes(vPod *corev1.Pod) []corev1.Volume {
+	volumes := []corev1.Volume{}
+	for _, volume := range vPod.Spec.Volumes {
+		if volume.HostPath != nil && !hostPathAllowed(p.hostPath.AllowedPaths, volume.HostPath.Path) {
+			volumes = append(volumes, volume)
+		}
+	}
+
+	return volumes
+}
+
+func hostPathAllowed(allowedPaths []string, hostPath string) bool {
+	hostPath = path.Clean(hostPath)
+	for _, allowedPath := range allowedPaths {
+		allowedPath = path.Clean(allowedPath)
+		if hostPath == allowedPath || strings.HasPrefix(hostPath, strings.TrimSuffix(allowedPath, "/")+"/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+func restricted(rule vclusterconfig.PodRestrictionRule, action string, vPod *corev1.Pod) bool {
+	return rule.Action == action && !slices.Contains(rule.AllowedNamespaces, vPod.Namespace)
+}
+
+func hostNamespaces(vPod *corev1.Pod) []string {
+	used := []string{}
+	if vPod.Spec.HostNetwork {
+		used = append(used, "hostNetwork")
+	}
+	if vPod.Spec.HostPID {
+		used = append(used, "hostPID")
+	}
+	if vPod.Spec.HostIPC {
+		used = append(used, "hostIPC")
+	}
+
+	return used
+}
+
+func privilegedContainers(vPod *corev1.Pod) []string {
+	names := []string{}
+	for _, container := range vPod.Spec.InitContainers {
+		if isPrivileged(container.SecurityContext) {
+			names = append(names, container.Name)
+		}
+	}
+	for _, container := range vPod.Spec.Containers {
+		if isPrivileged(container.SecurityContext) {
+			names = append(names, container.Name)
+		}
+	}
+	for _, container := range vPod.Spec.EphemeralContainers {
+		if isPrivileged(container.SecurityContext) {
+			names = append(names, container.Name)
+		}
+	}
+
+	return names
+}
+
+func isPrivileged(securityContext *corev1.SecurityContext) bool {
+	return securityContext != nil && securityContext.Privileged != nil && *securityContext.Privileged
+}
+
+// unprivileged returns a copy of the security context without privileged, so the virtual pod isn't changed
+func unprivileged(securityContext *corev1.SecurityContext) *corev1.SecurityContext {
+	if !isPrivileged(securityContext) {
+		return securityContext
+	}
+
+	securityContext = securityContext.DeepCopy()
+	securityContext.Privileged = nil
+	return securityContext
+}
@@ -0,0 +1,98 @@
+package pods
+
+import (
+	"testing"
+
+	vclusterconfig "github.com/loft-sh/vcluster/config"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+func TestPodRestrictions(t *testing.T) {
+	assert.Assert(t, parsePodRestrictions(vclusterconfig.PodRestrictions{}) == nil)
+
+	restrictions := parsePodRestrictions(vclusterconfig.PodRestrictions{
+		HostPath: vclusterconfig.PodRestrictionHostPath{
+			PodRestrictionRule: vclusterconfig.PodRestrictionRule{Action: PodRestrictionActionRewrite},
+			AllowedPaths:       []string{"/var/log/"},
+		},
+		HostNamespaces: vclusterconfig.PodRestrictionRule{Action: PodRestrictionActionDeny, AllowedNamespaces: []string{"monitoring"}},
+		Privileged:     vclusterconfig.PodRestrictionRule{Action: PodRestrictionActionRewrite},
+	})
+
+	privileged := &corev1.SecurityContext{Privileged: ptr.To(true)}
+	hostPathVolume := func(name, path string) corev1.Volume {
+		return corev1.Volume{Name: name, VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: path}}}
+	}
+	testCases := []struct {
+		name      string
+		namespace string
+		spec      corev1.PodSpec
+
+		expectedViolation string
+		expectedRewritten []string
+		expectedSpec      corev1.PodSpec
+	}{
+		{
+			name:              "unrestricted pod",
+			spec:              corev1.PodSpec{Containers: []corev1.Container{{Name: "app"}}},
+			expectedRewritten: []string{},
+			expectedSpec:      corev1.PodSpec{Containers: []corev1.Container{{Name: "app"}}},
+		},
+		{
+			name:              "deny host network",
+			spec:              corev1.PodSpec{HostNetwork: true, HostPID: true},
+			expectedViolation: "hostNetwork, hostPID",
+		},
+		{
+			name:              "allowed namespace",
+			namespace:         "monitoring",
+			spec:              corev1.PodSpec{HostNetwork: true},
+			expectedRewritten: []string{},
+			expectedSpec:      corev1.PodSpec{HostNetwork: true},
+		},
+		{
+			name: "rewrite host paths and privileged containers",
+			spec: corev1.PodSpec{
+				Volumes:    []corev1.Volume{hostPathVolume("logs", "/var/log/app"), hostPathVolume("root", "/"), hostPathVolume("logs-sibling", "/var/logs")},
+				Containers: []corev1.Container{{Name: "app", SecurityContext: privileged}},
+			},
+			expectedRewritten: []string{"hostPath volume root (/)", "hostPath volume logs-sibling (/var/logs)", "privileged container app"},
+			expectedSpec: corev1.PodSpec{
+				Volumes: []corev1.Volume{
+					hostPathVolume("logs", "/var/log/app"),
+					{Name: "root", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+					{Name: "logs-sibling", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+				},
+				Containers: []corev1.Container{{Name: "app", SecurityContext: &corev1.SecurityContext{}}},
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			vPod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod", Namespace: testCase.namespace}, Spec: testCase.spec}
+			assert.Equal(t, restrictions.violation(vPod), testCase.expectedViolation)
+			if testCase.expectedViolation != "" {
+				return
+			}
+
+			pPod := vPod.DeepCopy()
+			assert.DeepEqual(t, restrictions.rewrite(vPod, pPod), testCase.expectedRewritten)
+			assert.DeepEqual(t, pPod.Spec, testCase.expectedSpec)
+		})
+	}
+
+	// the virtual pod keeps its security context
+	assert.Equal(t, *privileged.Privileged, true)
+
+	// new privileged ephemeral containers are rewritten
+	allowed, denied, rewritten := restrictions.filterEphemeralContainers(&corev1.Pod{}, []corev1.EphemeralContainer{
+		{EphemeralContainerCommon: corev1.EphemeralContainerCommon{Name: "debug", SecurityContext: privileged}},
+	})
+	assert.Equal(t, len(allowed), 1)
+	assert.Assert(t, allowed[0].SecurityContext.Privileged == nil)
+	assert.Equal(t, len(denied), 0)
+	assert.DeepEqual(t, rewritten, []string{"privileged container debug"})
+}
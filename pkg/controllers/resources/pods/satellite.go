@@ -0,0 +1,54 @@
+package pods
+
+import (
+	vclusterconfig "github.com/loft-sh/vcluster/config"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// satelliteForPod returns the name of the satellite that should sync the pod or an empty string
+// if the pod belongs to the primary syncer. A pod is selected by a satellite if either its node selector
+// or one of its required node affinity terms pins it to the node labels of the satellite.
+func satelliteForPod(satellites []vclusterconfig.SatelliteCluster, pod *corev1.Pod) string {
+	for _, satellite := range satellites {
+		if len(satellite.NodeSelector) == 0 {
+			continue
+		}
+
+		if matchesNodeSelector(satellite.NodeSelector, pod.Spec.NodeSelector) || matchesNodeAffinity(satellite.NodeSelector, pod.Spec.Affinity) {
+			return satellite.Name
+		}
+	}
+
+	return ""
+}
+
+func matchesNodeSelector(selector, nodeSelector map[string]string) bool {
+	for k, v := range selector {
+		if nodeSelector[k] != v {
+			return false
+		}
+	}
+
+	return true
+}
+
+func matchesNodeAffinity(selector map[string]string, affinity *corev1.Affinity) bool {
+	if affinity == nil || affinity.NodeAffinity == nil || affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		return false
+	}
+
+	for _, term := range affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms {
+		labels := map[string]string{}
+		for _, expression := range term.MatchExpressions {
+			if expression.Operator == corev1.NodeSelectorOpIn && len(expression.Values) == 1 {
+				labels[expression.Key] = expression.Values[0]
+			}
+		}
+
+		if matchesNodeSelector(selector, labels) {
+			return true
+		}
+	}
+
+	return false
+}
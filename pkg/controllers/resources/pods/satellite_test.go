@@ -0,0 +1,90 @@
+package pods
+
+import (
+	"testing"
+
+	vclusterconfig "github.com/loft-sh/vcluster/config"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestSatelliteForPod(t *testing.T) {
+	satellites := []vclusterconfig.SatelliteCluster{
+		{
+			Name:         "eu",
+			NodeSelector: map[string]string{"topology.kubernetes.io/region": "eu-west-1"},
+		},
+		{
+			Name: "empty",
+		},
+	}
+
+	testCases := []struct {
+		name string
+
+		pod corev1.PodSpec
+
+		expectedSatellite string
+	}{
+		{
+			name: "no selector",
+		},
+		{
+			name: "node selector",
+			pod: corev1.PodSpec{
+				NodeSelector: map[string]string{"topology.kubernetes.io/region": "eu-west-1", "disk": "ssd"},
+			},
+			expectedSatellite: "eu",
+		},
+		{
+			name: "other region",
+			pod: corev1.PodSpec{
+				NodeSelector: map[string]string{"topology.kubernetes.io/region": "us-east-1"},
+			},
+		},
+		{
+			name: "node affinity",
+			pod: corev1.PodSpec{
+				Affinity: &corev1.Affinity{
+					NodeAffinity: &corev1.NodeAffinity{
+						RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+							NodeSelectorTerms: []corev1.NodeSelectorTerm{
+								{
+									MatchExpressions: []corev1.NodeSelectorRequirement{
+										{Key: "topology.kubernetes.io/region", Operator: corev1.NodeSelectorOpIn, Values: []string{"eu-west-1"}},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			expectedSatellite: "eu",
+		},
+		{
+			name: "node affinity with multiple values",
+			pod: corev1.PodSpec{
+				Affinity: &corev1.Affinity{
+					NodeAffinity: &corev1.NodeAffinity{
+						RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+							NodeSelectorTerms: []corev1.NodeSelectorTerm{
+								{
+									MatchExpressions: []corev1.NodeSelectorRequirement{
+										{Key: "topology.kubernetes.io/region", Operator: corev1.NodeSelectorOpIn, Values: []string{"eu-west-1", "us-east-1"}},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			satellite := satelliteForPod(satellites, &corev1.Pod{Spec: testCase.pod})
+			assert.Equal(t, satellite, testCase.expectedSatellite)
+		})
+	}
+}
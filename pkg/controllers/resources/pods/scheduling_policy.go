@@ -0,0 +1,104 @@
+package pods
+
+import (
+	"encoding/json"
+	"fmt"
+
+	vclusterconfig "github.com/loft-sh/vcluster/config"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// schedulingPolicy is the parsed form of policies.scheduling
+type schedulingPolicy struct {
+	nodeSelector map[string]string
+	tolerations  []corev1.Toleration
+	nodeAffinity []corev1.NodeSelectorRequirement
+	enforce      bool
+
+	enableScheduler bool
+}
+
+func parseSchedulingPolicy(policy vclusterconfig.SchedulingPolicy, enableScheduler bool) (*schedulingPolicy, error) {
+	if len(policy.NodeSelector) == 0 && len(policy.Tolerations) == 0 && len(policy.NodeAffinity) == 0 {
+		return nil, nil
+	}
+
+	parsed := &schedulingPolicy{
+		nodeSelector:    policy.NodeSelector,
+		enforce:         policy.Enforce,
+		enableScheduler: enableScheduler,
+	}
+	err := convertPatchField(policy.Tolerations, &parsed.tolerations)
+	if err != nil {
+		return nil, fmt.Errorf("policies.scheduling.tolerations: %w", err)
+	}
+
+	raw, err := json.Marshal(policy.NodeAffinity)
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(raw, &parsed.nodeAffinity)
+	if err != nil {
+		return nil, fmt.Errorf("policies.scheduling.nodeAffinity: %w", err)
+	}
+
+	return parsed, nil
+}
+
+// violation returns why the virtual pod conflicts with the policy or an empty string if it doesn't or the policy isn't
+// enforced
+func (p *schedulingPolicy) violation(vPod *corev1.Pod) string {
+	if p == nil || !p.enforce {
+		return ""
+	}
+
+	if vPod.Spec.NodeName != "" && !p.enableScheduler {
+		return fmt.Sprintf("spec.nodeName %s bypasses the node pool of the virtual cluster", vPod.Spec.NodeName)
+	}
+	for key, value := range p.nodeSelector {
+		if tenantValue, ok := vPod.Spec.NodeSelector[key]; ok && tenantValue != value {
+			return fmt.Sprintf("node selector %s=%s conflicts with %s=%s", key, tenantValue, key, value)
+		}
+	}
+
+	return ""
+}
+
+// apply pins the host pod to the nodes selected by the policy
+func (p *schedulingPolicy) apply(pPod *corev1.Pod) {
+	if p == nil {
+		return
+	}
+
+	if len(p.nodeSelector) > 0 {
+		if pPod.Spec.NodeSelector == nil {
+			pPod.Spec.NodeSelector = map[string]string{}
+		}
+		for k, v := range p.nodeSelector {
+			pPod.Spec.NodeSelector[k] = v
+		}
+	}
+
+	pPod.Spec.Tolerations = append(pPod.Spec.Tolerations, p.tolerations...)
+
+	// node selector terms are ORed, so the requirements need to be part of every term
+	if len(p.nodeAffinity) > 0 {
+		if pPod.Spec.Affinity == nil {
+			pPod.Spec.Affinity = &corev1.Affinity{}
+		}
+		if pPod.Spec.Affinity.NodeAffinity == nil {
+			pPod.Spec.Affinity.NodeAffinity = &corev1.NodeAffinity{}
+		}
+		if pPod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+			pPod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution = &corev1.NodeSelector{}
+		}
+
+		required := pPod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+		if len(required.NodeSelectorTerms) == 0 {
+			required.NodeSelectorTerms = []corev1.NodeSelectorTerm{{}}
+		}
+		for i := range required.NodeSelectorTerms {
+			required.NodeSelectorTerms[i].MatchExpressions = append(required.NodeSelectorTerms[i].MatchExpressions, p.nodeAffinity...)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package pods
+
+import (
+	"testing"
+
+	vclusterconfig "github.com/loft-sh/vcluster/config"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestSchedulingPolicy(t *testing.T) {
+	policy, err := parseSchedulingPolicy(vclusterconfig.SchedulingPolicy{
+		NodeSelector: map[string]string{"pool": "team-a"},
+		Tolerations: []map[string]interface{}{
+			{"key": "pool", "operator": "Equal", "value": "team-a", "effect": "NoSchedule"},
+		},
+		NodeAffinity: []vclusterconfig.LabelSelectorRequirement{
+			{Key: "topology.kubernetes.io/zone", Operator: "In", Values: []string{"eu-1a", "eu-1b"}},
+		},
+		Enforce: true,
+	}, false)
+	assert.NilError(t, err)
+
+	zoneRequirement := corev1.NodeSelectorRequirement{Key: "topology.kubernetes.io/zone", Operator: corev1.NodeSelectorOpIn, Values: []string{"eu-1a", "eu-1b"}}
+	testCases := []struct {
+		name string
+		spec corev1.PodSpec
+
+		expectedViolation string
+		expectedSpec      corev1.PodSpec
+	}{
+		{
+			name: "pin pod",
+			spec: corev1.PodSpec{NodeSelector: map[string]string{"disk": "ssd"}},
+
+			expectedSpec: corev1.PodSpec{
+				NodeSelector: map[string]string{"disk": "ssd", "pool": "team-a"},
+				Tolerations:  []corev1.Toleration{{Key: "pool", Operator: corev1.TolerationOpEqual, Value: "team-a", Effect: corev1.TaintEffectNoSchedule}},
+				Affinity: &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+					NodeSelectorTerms: []corev1.NodeSelectorTerm{{MatchExpressions: []corev1.NodeSelectorRequirement{zoneRequirement}}},
+				}}},
+			},
+		},
+		{
+			name: "add requirements to every term",
+			spec: corev1.PodSpec{
+				Affinity: &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+					NodeSelectorTerms: []corev1.NodeSelectorTerm{
+						{MatchExpressions: []corev1.NodeSelectorRequirement{{Key: "gpu", Operator: corev1.NodeSelectorOpExists}}},
+						{MatchFields: []corev1.NodeSelectorRequirement{{Key: "metadata.name", Operator: corev1.NodeSelectorOpIn, Values: []string{"node-1"}}}},
+					},
+				}}},
+			},
+
+			expectedSpec: corev1.PodSpec{
+				NodeSelector: map[string]string{"pool": "team-a"},
+				Tolerations:  []corev1.Toleration{{Key: "pool", Operator: corev1.TolerationOpEqual, Value: "team-a", Effect: corev1.TaintEffectNoSchedule}},
+				Affinity: &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+					NodeSelectorTerms: []corev1.NodeSelectorTerm{
+						{MatchExpressions: []corev1.NodeSelectorRequirement{{Key: "gpu", Operator: corev1.NodeSelectorOpExists}, zoneRequirement}},
+						{
+							MatchExpressions: []corev1.NodeSelectorRequirement{zoneRequirement},
+							MatchFields:      []corev1.NodeSelectorRequirement{{Key: "metadata.name", Operator: corev1.NodeSelectorOpIn, Values: []string{"node-1"}}},
+						},
+					},
+				}}},
+			},
+		},
+		{
+			name: "reject conflicting node selector",
+			spec: corev1.PodSpec{NodeSelector: map[string]string{"pool": "team-b"}},
+
+			expectedViolation: "node selector pool=team-b conflicts with pool=team-a",
+		},
+		{
+			name: "reject node name",
+			spec: corev1.PodSpec{NodeName: "node-1"},
+
+			expectedViolation: "spec.nodeName node-1 bypasses the node pool of the virtual cluster",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			pod := &corev1.Pod{Spec: testCase.spec}
+			assert.Equal(t, policy.violation(pod), testCase.expectedViolation)
+			if testCase.expectedViolation != "" {
+				return
+			}
+
+			policy.apply(pod)
+			assert.DeepEqual(t, pod.Spec, testCase.expectedSpec)
+		})
+	}
+}
+
+func TestSchedulingPolicyEmpty(t *testing.T) {
+	policy, err := parseSchedulingPolicy(vclusterconfig.SchedulingPolicy{Enforce: true}, false)
+	assert.NilError(t, err)
+
+	pod := &corev1.Pod{Spec: corev1.PodSpec{NodeName: "node-1"}}
+	assert.Equal(t, policy.violation(pod), "")
+	policy.apply(pod)
+	assert.DeepEqual(t, pod.Spec, corev1.PodSpec{NodeName: "node-1"})
+}
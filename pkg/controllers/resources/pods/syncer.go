@@ -3,17 +3,24 @@ package pods
 import (
 	"context"
 	"reflect"
+	"strings"
+	"sync"
 	"time"
 
 	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/util/wait"
 
+	vclusterconfig "github.com/loft-sh/vcluster/config"
+	"github.com/loft-sh/vcluster/pkg/config"
 	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
 	"github.com/loft-sh/vcluster/pkg/controllers/syncer/translator"
 	syncer "github.com/loft-sh/vcluster/pkg/types"
 
 	translatepods "github.com/loft-sh/vcluster/pkg/controllers/resources/pods/translate"
+	"github.com/loft-sh/vcluster/pkg/coredns"
+	"github.com/loft-sh/vcluster/pkg/integrations/istio"
 	"github.com/loft-sh/vcluster/pkg/util/loghelper"
+	"github.com/loft-sh/vcluster/pkg/util/podhelper"
 	"github.com/loft-sh/vcluster/pkg/util/toleration"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
@@ -47,14 +54,6 @@ func New(ctx *synccontext.RegisterContext) (syncer.Object, error) {
 		return nil, err
 	}
 
-	// parse node selector
-	var nodeSelector *metav1.LabelSelector
-	if len(ctx.Config.Sync.FromHost.Nodes.Selector.Labels) > 0 {
-		nodeSelector = &metav1.LabelSelector{
-			MatchLabels: ctx.Config.Sync.FromHost.Nodes.Selector.Labels,
-		}
-	}
-
 	// parse tolerations
 	var tolerations []*corev1.Toleration
 	if len(ctx.Config.Sync.ToHost.Pods.EnforceTolerations) > 0 {
@@ -66,6 +65,25 @@ func New(ctx *synccontext.RegisterContext) (syncer.Object, error) {
 		}
 	}
 
+	// parse pod patches
+	podPatches, err := parsePodPatches(ctx.Config.Sync.ToHost.Pods.Patches)
+	if err != nil {
+		return nil, err
+	}
+
+	// parse scheduling policy
+	podRestrictions := parsePodRestrictions(ctx.Config.Policies.PodRestrictions)
+	schedulingPolicy, err := parseSchedulingPolicy(ctx.Config.Policies.Scheduling, ctx.Config.ControlPlane.Advanced.VirtualScheduler.Enabled)
+	if err != nil {
+		return nil, err
+	}
+
+	// parse extended resource limits
+	extendedResourceLimits, err := parseExtendedResourceLimits(ctx.Config.Policies.ExtendedResources.Limits)
+	if err != nil {
+		return nil, err
+	}
+
 	// create new namespaced translator
 	namespacedTranslator := translator.NewNamespacedTranslator(ctx, "pod", &corev1.Pod{})
 
@@ -75,9 +93,19 @@ func New(ctx *synccontext.RegisterContext) (syncer.Object, error) {
 		return nil, errors.Wrap(err, "create pod translator")
 	}
 
-	return &podSyncer{
+	// use the tenant service of the shared coredns if enabled
+	var sharedDNSTenant *coredns.SharedDNSTenant
+	if ctx.Config.Networking.Advanced.SharedDNS.Enabled {
+		tenant := coredns.NewSharedDNSTenant(ctx.Config)
+		sharedDNSTenant = &tenant
+	}
+
+	s := &podSyncer{
 		NamespacedTranslator: namespacedTranslator,
 
+		sharedDNSNamespace: ctx.Config.Networking.Advanced.SharedDNS.Namespace,
+		sharedDNSTenant:    sharedDNSTenant,
+
 		serviceName:     ctx.Config.WorkloadService,
 		enableScheduler: ctx.Config.ControlPlane.Advanced.VirtualScheduler.Enabled,
 
@@ -85,11 +113,54 @@ func New(ctx *synccontext.RegisterContext) (syncer.Object, error) {
 		physicalClusterClient: physicalClusterClient,
 		physicalClusterConfig: ctx.PhysicalManager.GetConfig(),
 		podTranslator:         podTranslator,
-		nodeSelector:          nodeSelector,
+		nodeSelector:          parseNodeSelector(ctx.Config.Sync.FromHost.Nodes.Selector),
 		tolerations:           tolerations,
+		imagePullSecrets:      ctx.Config.Sync.ToHost.Pods.ImagePullSecrets,
+		podPatches:            podPatches,
+		schedulingPolicy:      schedulingPolicy,
+		podRestrictions:       podRestrictions,
+		allowNodeDebug:        ctx.Config.Policies.HostNodeAccess.AllowDebug,
+
+		extendedResources: newExtendedResourceLimiter(extendedResourceLimits),
+		clusterAutoscaler: ctx.Config.Sync.ToHost.Pods.ClusterAutoscaler,
+		istio:             ctx.Config.Integrations.Istio,
+
+		backSyncEphemeralContainers: ctx.Config.Sync.ToHost.Pods.BackSyncEphemeralContainers,
+
+		podSecurityStandard: ctx.Config.Policies.PodSecurityStandard.Level,
+
+		satellite:                  satelliteName(ctx),
+		satellites:                 ctx.Config.Experimental.MultiCluster.Satellites,
+		satelliteKubernetesService: ctx.Config.Experimental.MultiCluster.Satellite.KubernetesService,
+		satelliteDNSService:        ctx.Config.Experimental.MultiCluster.Satellite.DNSService,
+	}
+
+	// the node selector and patches can change at runtime. They are only applied when a host pod is created, so existing
+	// pods are not synced again.
+	ctx.Config.OnReload(func(newConfig *config.VirtualClusterConfig) {
+		podPatches, err := parsePodPatches(newConfig.Sync.ToHost.Pods.Patches)
+		if err != nil {
+			loghelper.New("pods-syncer").Errorf("error reloading sync.toHost.pods.patches: %v", err)
+			return
+		}
 
-		podSecurityStandard: ctx.Config.Policies.PodSecurityStandard,
-	}, nil
+		s.reloadableMutex.Lock()
+		defer s.reloadableMutex.Unlock()
+		s.nodeSelector = parseNodeSelector(newConfig.Sync.FromHost.Nodes.Selector)
+		s.podPatches = podPatches
+	})
+
+	return s, nil
+}
+
+func parseNodeSelector(selector vclusterconfig.SyncNodeSelector) *metav1.LabelSelector {
+	if len(selector.Labels) == 0 {
+		return nil
+	}
+
+	return &metav1.LabelSelector{
+		MatchLabels: selector.Labels,
+	}
 }
 
 type podSyncer struct {
@@ -98,14 +169,44 @@ type podSyncer struct {
 	serviceName     string
 	enableScheduler bool
 
+	sharedDNSNamespace string
+	sharedDNSTenant    *coredns.SharedDNSTenant
+	sharedDNSMutex     sync.Mutex
+	sharedDNSIP        string
+
 	podTranslator         translatepods.Translator
 	virtualClusterClient  kubernetes.Interface
 	physicalClusterClient kubernetes.Interface
 	physicalClusterConfig *rest.Config
+	reloadableMutex       sync.RWMutex
 	nodeSelector          *metav1.LabelSelector
 	tolerations           []*corev1.Toleration
+	imagePullSecrets      []vclusterconfig.SyncPodsImagePullSecret
+	podPatches            []podPatch
+	schedulingPolicy      *schedulingPolicy
+	podRestrictions       *podRestrictions
+	allowNodeDebug        bool
+
+	extendedResources *extendedResourceLimiter
+	clusterAutoscaler vclusterconfig.SyncPodsClusterAutoscaler
+	istio             vclusterconfig.Istio
+
+	backSyncEphemeralContainers bool
 
 	podSecurityStandard string
+
+	satellite                  string
+	satellites                 []vclusterconfig.SatelliteCluster
+	satelliteKubernetesService string
+	satelliteDNSService        string
+}
+
+func satelliteName(ctx *synccontext.RegisterContext) string {
+	if !ctx.Config.Experimental.MultiCluster.Satellite.Enabled {
+		return ""
+	}
+
+	return ctx.Config.Experimental.MultiCluster.Satellite.Name
 }
 
 var _ syncer.IndicesRegisterer = &podSyncer{}
@@ -144,10 +245,21 @@ func (s *podSyncer) ModifyController(ctx *synccontext.RegisterContext, builder *
 	return builder.Watches(&corev1.Namespace{}, eventHandler), nil
 }
 
+var _ syncer.OptionsProvider = &podSyncer{}
+
+func (s *podSyncer) WithOptions() *syncer.Options {
+	return &syncer.Options{DisableOrderedDeletion: true}
+}
+
 var _ syncer.Syncer = &podSyncer{}
 
 func (s *podSyncer) SyncToHost(ctx *synccontext.SyncContext, vObj client.Object) (ctrl.Result, error) {
 	vPod := vObj.(*corev1.Pod)
+	// pods selected for another host cluster are synced by the syncer in that host cluster
+	if satelliteForPod(s.satellites, vPod) != s.satellite {
+		return ctrl.Result{}, nil
+	}
+
 	// in some scenarios it is possible that the pod was already started and the physical pod
 	// was deleted without vcluster's knowledge. In this case we are deleting the virtual pod
 	// as well, to avoid conflicts with nodes if we would resync the same pod to the host cluster again.
@@ -173,6 +285,28 @@ func (s *podSyncer) SyncToHost(ctx *synccontext.SyncContext, vObj client.Object)
 		}
 	}
 
+	// reject pods that conflict with the scheduling policy
+	if violation := s.schedulingPolicy.violation(vPod); violation != "" {
+		ctx.Log.Errorf("%s pod creation not allowed: %s", vPod.Name, violation)
+		s.EventRecorder().Eventf(vPod, "Warning", "SyncError", `Pod %s is forbidden by policies.scheduling: %s`, vPod.Name, violation)
+		return ctrl.Result{}, nil
+	}
+
+	// reject pods that use host access denied by the pod restrictions
+	if violation := s.podRestrictions.violation(vPod); violation != "" {
+		ctx.Log.Errorf("%s pod creation not allowed: %s", vPod.Name, violation)
+		s.EventRecorder().Eventf(vPod, "Warning", "SyncError", `Pod %s is forbidden by policies.podRestrictions: %s`, vPod.Name, violation)
+		return ctrl.Result{}, nil
+	}
+
+	// reject node debugging pods, no matter if a user or a controller created them. Pods bound by the virtual scheduler
+	// target a node, because they were scheduled there and not because they were created that way.
+	if !s.allowNodeDebug && podhelper.IsNodeDebugPod(vPod) && !(s.enableScheduler && podhelper.IsBoundByScheduler(vPod)) {
+		ctx.Log.Errorf("%s pod creation not allowed: pod targets node %s and uses host namespaces or hostPath volumes", vPod.Name, vPod.Spec.NodeName)
+		s.EventRecorder().Eventf(vPod, "Warning", "SyncError", "Pod %s is forbidden by policies.hostNodeAccess.allowDebug: pods that target node %s and use host namespaces or hostPath volumes are not allowed", vPod.Name, vPod.Spec.NodeName)
+		return ctrl.Result{}, nil
+	}
+
 	// translate the pod
 	pPod, err := s.translate(ctx, vPod)
 	if err != nil {
@@ -184,8 +318,32 @@ func (s *podSyncer) SyncToHost(ctx *synccontext.SyncContext, vObj client.Object)
 		pPod.Spec.Tolerations = append(pPod.Spec.Tolerations, *tol)
 	}
 
+	// apply pod patches
+	s.reloadableMutex.RLock()
+	podPatches := s.podPatches
+	s.reloadableMutex.RUnlock()
+	applyPodPatches(podPatches, vPod, pPod)
+
+	// apply scheduling policy
+	s.schedulingPolicy.apply(pPod)
+
+	// remove host access rewritten by the pod restrictions
+	if rewritten := s.podRestrictions.rewrite(vPod, pPod); len(rewritten) > 0 {
+		ctx.Log.Infof("sync pod %s/%s without %s, because of policies.podRestrictions", vPod.Namespace, vPod.Name, strings.Join(rewritten, ", "))
+		s.EventRecorder().Eventf(vPod, "Warning", "SyncWarning", "Pod %s is synced without %s because of policies.podRestrictions", vPod.Name, strings.Join(rewritten, ", "))
+	}
+
+	// ensure image pull secrets
+	err = ensureImagePullSecrets(ctx.Context, s.physicalClusterClient, s.imagePullSecrets, pPod)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
 	// ensure node selector
-	if s.nodeSelector != nil {
+	s.reloadableMutex.RLock()
+	nodeSelector := s.nodeSelector
+	s.reloadableMutex.RUnlock()
+	if nodeSelector != nil {
 		// 2 cases:
 		// 1. Pod already has a nodeName -> then we check if the node exists in the virtual cluster
 		// 2. Pod has no nodeName -> then we set the nodeSelector
@@ -193,7 +351,7 @@ func (s *podSyncer) SyncToHost(ctx *synccontext.SyncContext, vObj client.Object)
 			if pPod.Spec.NodeSelector == nil {
 				pPod.Spec.NodeSelector = map[string]string{}
 			}
-			for k, v := range s.nodeSelector.MatchLabels {
+			for k, v := range nodeSelector.MatchLabels {
 				pPod.Spec.NodeSelector[k] = v
 			}
 		} else {
@@ -210,12 +368,29 @@ func (s *podSyncer) SyncToHost(ctx *synccontext.SyncContext, vObj client.Object)
 		}
 	}
 
-	// if scheduler is enabled we only sync if the pod has a node name
-	if s.enableScheduler && pPod.Spec.NodeName == "" {
+	// if scheduler is enabled we only sync if the pod has a node name, unless the host cluster should scale up for it
+	if s.enableScheduler && pPod.Spec.NodeName == "" && (!s.clusterAutoscaler.Enabled || !isUnschedulable(vPod)) {
 		return ctrl.Result{}, nil
 	}
+	applyClusterAutoscaler(s.clusterAutoscaler, pPod)
+	istio.TranslatePod(s.istio, vPod, pPod)
 
-	return s.SyncToHostCreate(ctx, vPod, pPod)
+	// wait until the pod fits into the extended resource limits
+	pName := types.NamespacedName{Namespace: pPod.Namespace, Name: pPod.Name}
+	exceeded, err := s.extendedResources.reserve(ctx.Context, ctx.PhysicalClient, vPod, pName)
+	if err != nil {
+		return ctrl.Result{}, err
+	} else if exceeded != "" {
+		ctx.Log.Infof("delay syncing pod %s/%s, because it exceeds the extended resource limits: %s", vPod.Namespace, vPod.Name, exceeded)
+		s.EventRecorder().Eventf(vPod, "Warning", "SyncWarning", "Pod %s exceeds policies.extendedResources.limits: %s", vPod.Name, exceeded)
+		return ctrl.Result{RequeueAfter: time.Second * 30}, nil
+	}
+
+	result, err := s.SyncToHostCreate(ctx, vPod, pPod)
+	if err != nil || !result.IsZero() {
+		s.extendedResources.release(pName)
+	}
+	return result, err
 }
 
 func (s *podSyncer) Sync(ctx *synccontext.SyncContext, pObj client.Object, vObj client.Object) (ctrl.Result, error) {
@@ -254,6 +429,17 @@ func (s *podSyncer) Sync(ctx *synccontext.SyncContext, pObj client.Object, vObj
 		return ctrl.Result{}, err
 	}
 
+	// the virtual scheduler found a node before the host cluster scaled up
+	if s.enableScheduler && s.clusterAutoscaler.Enabled && pPod.Spec.NodeName == "" && vPod.Spec.NodeName != "" {
+		ctx.Log.Infof("bind physical pod %s/%s to node %s, because the virtual pod was scheduled", pPod.Namespace, pPod.Name, vPod.Spec.NodeName)
+		err := bindHostPod(ctx.Context, s.physicalClusterClient, pPod, vPod.Spec.NodeName)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{Requeue: true}, nil
+	}
+
 	// make sure node exists for pod
 	if pPod.Spec.NodeName != "" {
 		requeue, err := s.ensureNode(ctx, pPod, vPod)
@@ -303,26 +489,16 @@ func (s *podSyncer) Sync(ctx *synccontext.SyncContext, pObj client.Object, vObj
 		return ctrl.Result{}, nil
 	}
 
-	// sync ephemeral containers
-	if syncEphemeralContainers(vPod, strippedPod) {
-		kubeIP, _, ptrServiceList, err := s.getK8sIPDNSIPServiceList(ctx, vPod)
-		if err != nil {
-			return ctrl.Result{}, err
-		}
-
-		// translate services to environment variables
-		serviceEnv := translatepods.ServicesToEnvironmentVariables(vPod.Spec.EnableServiceLinks, ptrServiceList, kubeIP)
-		for i := range vPod.Spec.EphemeralContainers {
-			envVar, envFrom := s.podTranslator.TranslateContainerEnv(vPod.Spec.EphemeralContainers[i].Env, vPod.Spec.EphemeralContainers[i].EnvFrom, vPod, serviceEnv)
-			vPod.Spec.EphemeralContainers[i].Env = envVar
-			vPod.Spec.EphemeralContainers[i].EnvFrom = envFrom
-		}
-
-		// add ephemeralContainers subresource to physical pod
-		err = AddEphemeralContainer(ctx, s.physicalClusterClient, pPod, vPod)
-		if err != nil {
-			return ctrl.Result{}, err
-		}
+	// sync ephemeral containers virtual <-> physical, failures are only reported, as they shouldn't block syncing the
+	// rest of the pod
+	requeue, err := s.syncEphemeralContainers(ctx, vPod, strippedPod)
+	if kerrors.IsConflict(err) {
+		return ctrl.Result{Requeue: true}, nil
+	} else if err != nil {
+		ctx.Log.Infof("error syncing ephemeral containers of pod %s/%s: %v", vPod.Namespace, vPod.Name, err)
+		s.EventRecorder().Eventf(vObj, "Warning", "SyncError", "Error syncing ephemeral containers: %v", err)
+	} else if requeue {
+		return ctrl.Result{Requeue: true}, nil
 	}
 
 	// validate virtual pod before syncing it to the host cluster
@@ -339,29 +515,75 @@ func (s *podSyncer) Sync(ctx *synccontext.SyncContext, pObj client.Object, vObj
 	updatedPod, err := s.translateUpdate(ctx.Context, ctx.PhysicalClient, pPod, vPod)
 	if err != nil {
 		return ctrl.Result{}, err
-	} else if updatedPod != nil {
+	} else if updatedPod != nil && s.istio.Enabled {
+		istio.KeepHostLabels(pPod, updatedPod)
+		if equality.Semantic.DeepEqual(pPod, updatedPod) {
+			updatedPod = nil
+		}
+	}
+	if updatedPod != nil {
 		translator.PrintChanges(pPod, updatedPod, ctx.Log)
 	}
 
-	return s.SyncToHostUpdate(ctx, vPod, updatedPod)
-}
-
-func syncEphemeralContainers(vPod *corev1.Pod, pPod *corev1.Pod) bool {
-	if vPod.Spec.EphemeralContainers == nil {
-		return false
+	result, err := s.SyncToHostUpdate(ctx, vPod, updatedPod)
+	if err != nil || !result.IsZero() {
+		return result, err
 	}
-	if len(vPod.Spec.EphemeralContainers) != len(pPod.Spec.EphemeralContainers) {
-		return true
+
+	// make sure we come back in time to refresh the projected service account tokens
+	if refreshAfter, ok := translatepods.TokenRefreshAfter(pPod); ok {
+		result.RequeueAfter = max(refreshAfter, time.Second)
 	}
-	for i := range vPod.Spec.EphemeralContainers {
-		if vPod.Spec.EphemeralContainers[i].Image != pPod.Spec.EphemeralContainers[i].Image {
-			return true
+
+	return result, nil
+}
+
+// syncEphemeralContainers adds ephemeral containers of the virtual pod to the physical pod, e.g. when
+// kubectl debug is used within the vcluster. If enabled, it also adds ephemeral containers that were only
+// started in the physical pod to the virtual pod. It returns true if any pod was changed.
+func (s *podSyncer) syncEphemeralContainers(ctx *synccontext.SyncContext, vPod *corev1.Pod, pPod *corev1.Pod) (bool, error) {
+	toHost := missingEphemeralContainers(vPod, pPod)
+	if len(toHost) > 0 {
+		kubeIP, _, ptrServiceList, err := s.getK8sIPDNSIPServiceList(ctx, vPod)
+		if err != nil {
+			return false, err
+		}
+
+		// translate services to environment variables
+		serviceEnv := translatepods.ServicesToEnvironmentVariables(vPod.Spec.EnableServiceLinks, ptrServiceList, kubeIP)
+		for i := range toHost {
+			toHost[i] = s.podTranslator.TranslateEphemeralContainer(toHost[i], vPod, pPod, serviceEnv)
 		}
-		if vPod.Spec.EphemeralContainers[i].Name != pPod.Spec.EphemeralContainers[i].Name {
-			return true
+
+		// apply the pod restrictions to the new containers
+		var denied, rewritten []string
+		toHost, denied, rewritten = s.podRestrictions.filterEphemeralContainers(vPod, toHost)
+		if len(denied) > 0 {
+			s.EventRecorder().Eventf(vPod, "Warning", "SyncError", "Pod %s is forbidden by policies.podRestrictions: %s", vPod.Name, strings.Join(denied, ", "))
 		}
+		if len(rewritten) > 0 {
+			s.EventRecorder().Eventf(vPod, "Warning", "SyncWarning", "Pod %s is synced without %s because of policies.podRestrictions", vPod.Name, strings.Join(rewritten, ", "))
+		}
+		if len(toHost) == 0 {
+			return false, nil
+		}
+
+		// add ephemeralContainers subresource to physical pod
+		ctx.Log.Infof("update physical pod %s/%s, because virtual pod has new ephemeral containers", pPod.Namespace, pPod.Name)
+		return true, AddEphemeralContainers(ctx, s.physicalClusterClient, pPod, toHost)
+	}
+
+	if !s.backSyncEphemeralContainers {
+		return false, nil
 	}
-	return false
+
+	// add ephemeralContainers subresource to virtual pod
+	toVirtual := missingEphemeralContainers(pPod, vPod)
+	if len(toVirtual) > 0 {
+		return true, BackSyncEphemeralContainers(ctx, vPod, toVirtual)
+	}
+
+	return false, nil
 }
 
 func (s *podSyncer) ensureNode(ctx *synccontext.SyncContext, pObj *corev1.Pod, vObj *corev1.Pod) (bool, error) {
@@ -149,6 +149,15 @@ func TestSync(t *testing.T) {
 		"otherLabel": "abc",
 	}
 
+	// node debugging pod, e.g. created by a job that targets a node
+	vPodNodeDebug := &corev1.Pod{
+		ObjectMeta: vObjectMeta,
+		Spec: corev1.PodSpec{
+			NodeName: "test123",
+			HostPID:  true,
+		},
+	}
+
 	// pod security standards test objects
 	vPodPSS := &corev1.Pod{
 		ObjectMeta: vObjectMeta,
@@ -462,6 +471,22 @@ func TestSync(t *testing.T) {
 				assert.NilError(t, err)
 			},
 		},
+		{
+			Name:                 "Deny node debugging pods",
+			InitialVirtualState:  []runtime.Object{vPodNodeDebug.DeepCopy(), vNamespace.DeepCopy()},
+			InitialPhysicalState: []runtime.Object{pVclusterService.DeepCopy(), pDNSService.DeepCopy()},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				corev1.SchemeGroupVersion.WithKind("Pod"): {vPodNodeDebug.DeepCopy()},
+			},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{
+				corev1.SchemeGroupVersion.WithKind("Pod"): {},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				syncCtx, syncer := generictesting.FakeStartSyncer(t, ctx, New)
+				_, err := syncer.(*podSyncer).SyncToHost(syncCtx, vPodNodeDebug.DeepCopy())
+				assert.NilError(t, err)
+			},
+		},
 		{
 			Name:                 "SyncDown pods without any pod security standards",
 			InitialVirtualState:  []runtime.Object{vPodPSS.DeepCopy(), vNamespace.DeepCopy()},
@@ -489,7 +514,7 @@ func TestSync(t *testing.T) {
 				corev1.SchemeGroupVersion.WithKind("Pod"): {pPodPss.DeepCopy()},
 			},
 			Sync: func(ctx *synccontext.RegisterContext) {
-				ctx.Config.Policies.PodSecurityStandard = string(api.LevelPrivileged)
+				ctx.Config.Policies.PodSecurityStandard.Level = string(api.LevelPrivileged)
 				syncCtx, syncer := generictesting.FakeStartSyncer(t, ctx, New)
 				_, err := syncer.(*podSyncer).SyncToHost(syncCtx, vPodPSS.DeepCopy())
 				assert.NilError(t, err)
@@ -506,7 +531,7 @@ func TestSync(t *testing.T) {
 				corev1.SchemeGroupVersion.WithKind("Pod"): {},
 			},
 			Sync: func(ctx *synccontext.RegisterContext) {
-				ctx.Config.Policies.PodSecurityStandard = string(api.LevelRestricted)
+				ctx.Config.Policies.PodSecurityStandard.Level = string(api.LevelRestricted)
 				syncCtx, syncer := generictesting.FakeStartSyncer(t, ctx, New)
 				_, err := syncer.(*podSyncer).SyncToHost(syncCtx, vPodPSSR.DeepCopy())
 				assert.NilError(t, err)
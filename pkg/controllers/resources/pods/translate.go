@@ -6,6 +6,7 @@ import (
 
 	podtranslate "github.com/loft-sh/vcluster/pkg/controllers/resources/pods/translate"
 	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
+	"github.com/loft-sh/vcluster/pkg/coredns"
 	"github.com/loft-sh/vcluster/pkg/specialservices"
 	"github.com/loft-sh/vcluster/pkg/util/translate"
 	corev1 "k8s.io/api/core/v1"
@@ -69,6 +70,10 @@ func (s *podSyncer) translateUpdate(ctx context.Context, pClient client.Client,
 }
 
 func (s *podSyncer) findKubernetesIP(ctx *synccontext.SyncContext) (string, error) {
+	if s.satellite != "" {
+		return s.findSatelliteServiceIP(ctx, s.satelliteKubernetesService)
+	}
+
 	pService := &corev1.Service{}
 	err := ctx.CurrentNamespaceClient.Get(ctx.Context, types.NamespacedName{
 		Name:      s.serviceName,
@@ -82,6 +87,27 @@ func (s *podSyncer) findKubernetesIP(ctx *synccontext.SyncContext) (string, erro
 }
 
 func (s *podSyncer) findKubernetesDNSIP(ctx *synccontext.SyncContext) (string, error) {
+	if s.sharedDNSTenant != nil {
+		// the cluster ip of the tenant service doesn't change, so it's only read once
+		s.sharedDNSMutex.Lock()
+		defer s.sharedDNSMutex.Unlock()
+		if s.sharedDNSIP != "" {
+			return s.sharedDNSIP, nil
+		}
+
+		ip, err := coredns.SharedDNSServiceIP(ctx.Context, s.physicalClusterClient, s.sharedDNSNamespace, *s.sharedDNSTenant)
+		if err != nil || ip == "" {
+			return "", fmt.Errorf("waiting for shared DNS service IP")
+		}
+
+		s.sharedDNSIP = ip
+		return ip, nil
+	}
+
+	if s.satellite != "" {
+		return s.findSatelliteServiceIP(ctx, s.satelliteDNSService)
+	}
+
 	pClient, namespace := specialservices.Default.DNSNamespace(ctx)
 
 	// first try to find the actual synced service, then fallback to a different if we have a suffix (only in the case of integrated coredns)
@@ -98,6 +124,21 @@ func (s *podSyncer) findKubernetesDNSIP(ctx *synccontext.SyncContext) (string, e
 	return ip, nil
 }
 
+// findSatelliteServiceIP returns the cluster ip of a service in the host namespace of the satellite. The cluster ips
+// the primary syncer synced to the virtual services belong to the primary host cluster and are not reachable from the
+// satellite, so the kubernetes and dns services of the satellite pods are configured per host cluster.
+func (s *podSyncer) findSatelliteServiceIP(ctx *synccontext.SyncContext, name string) (string, error) {
+	pService := &corev1.Service{}
+	err := ctx.CurrentNamespaceClient.Get(ctx.Context, types.NamespacedName{Namespace: ctx.CurrentNamespace, Name: name}, pService)
+	if err != nil {
+		return "", fmt.Errorf("get satellite service %s/%s: %w", ctx.CurrentNamespace, name, err)
+	} else if pService.Spec.ClusterIP == "" || pService.Spec.ClusterIP == corev1.ClusterIPNone {
+		return "", fmt.Errorf("waiting for cluster ip of satellite service %s/%s", ctx.CurrentNamespace, name)
+	}
+
+	return pService.Spec.ClusterIP, nil
+}
+
 func (s *podSyncer) translateAndFindService(ctx *synccontext.SyncContext, kubeClient client.Client, namespace, name string) string {
 	pService := &corev1.Service{}
 	err := kubeClient.Get(ctx.Context, types.NamespacedName{
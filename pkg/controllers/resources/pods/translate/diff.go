@@ -3,7 +3,6 @@ package translate
 import (
 	"context"
 	"encoding/json"
-	"strings"
 
 	"github.com/loft-sh/vcluster/pkg/util/translate"
 	appsv1 "k8s.io/api/apps/v1"
@@ -53,6 +52,12 @@ func (t *translator) Diff(ctx context.Context, vPod, pPod *corev1.Pod) (*corev1.
 		delete(updatedAnnotations, OwnerSetKind)
 	}
 
+	// refresh projected service account tokens
+	err = t.refreshServiceAccountTokens(ctx, vPod, pPod, updatedAnnotations)
+	if err != nil {
+		return nil, err
+	}
+
 	if !equality.Semantic.DeepEqual(updatedAnnotations, pPod.Annotations) {
 		if updatedPod == nil {
 			updatedPod = pPod.DeepCopy()
@@ -75,21 +80,15 @@ func (t *translator) Diff(ctx context.Context, vPod, pPod *corev1.Pod) (*corev1.
 }
 
 func getExcludedAnnotations(pPod *corev1.Pod) []string {
-	annotations := []string{ClusterAutoScalerAnnotation, OwnerReferences, OwnerSetKind, NamespaceAnnotation, NameAnnotation, UIDAnnotation, ServiceAccountNameAnnotation, HostsRewrittenAnnotation, VClusterLabelsAnnotation}
+	annotations := []string{ClusterAutoScalerAnnotation, OwnerReferences, OwnerSetKind, NamespaceAnnotation, NameAnnotation, UIDAnnotation, ServiceAccountNameAnnotation, ServiceAccountTokenRefreshAnnotation, HostsRewrittenAnnotation, VClusterLabelsAnnotation}
 	if pPod != nil {
 		for _, v := range pPod.Spec.Volumes {
 			if v.Projected != nil {
 				for _, source := range v.Projected.Sources {
 					if source.DownwardAPI != nil {
 						for _, item := range source.DownwardAPI.Items {
-							if item.FieldRef != nil {
-								// check if its a label we have to rewrite
-								annotationsMatch := FieldPathAnnotationRegEx.FindStringSubmatch(item.FieldRef.FieldPath)
-								if len(annotationsMatch) == 2 {
-									if strings.HasPrefix(annotationsMatch[1], ServiceAccountTokenAnnotation) {
-										annotations = append(annotations, annotationsMatch[1])
-									}
-								}
+							if annotation := tokenAnnotationFromFieldRef(item.FieldRef); annotation != "" {
+								annotations = append(annotations, annotation)
 							}
 						}
 					}
@@ -1,8 +1,7 @@
 package translate
 
 import (
-	"strings"
-
+	"github.com/loft-sh/vcluster/config"
 	"github.com/loft-sh/vcluster/pkg/coredns"
 	corev1 "k8s.io/api/core/v1"
 )
@@ -27,10 +26,7 @@ var (
 
 func (t *translator) rewritePodHostnameFQDN(pPod *corev1.Pod, fromHost, toHostname, toHostnameFQDN string) {
 	if pPod.Annotations == nil || pPod.Annotations[DisableSubdomainRewriteAnnotation] != "true" || pPod.Annotations[HostsRewrittenAnnotation] != "true" {
-		image := t.overrideHostsImage
-		if t.defaultImageRegistry != "" {
-			image = strings.TrimSuffix(t.defaultImageRegistry, "/") + "/" + image
-		}
+		image := config.WithDefaultImageRegistry(t.defaultImageRegistry, t.overrideHostsImage)
 
 		userID := coredns.GetUserID()
 		groupID := coredns.GetGroupID()
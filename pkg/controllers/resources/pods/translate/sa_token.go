@@ -0,0 +1,175 @@
+package translate
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// maxTokenRefreshInterval is the maximum time after which a projected token is refreshed, this
+// mirrors the behaviour of the kubelet.
+const maxTokenRefreshInterval = 24 * time.Hour
+
+// requestServiceAccountToken requests a token for the given projection from the virtual api server.
+// Audience and expiration are taken from the projection, so the token is issued for the virtual
+// api server's issuer and defaults to its api audiences, the same way the kubelet would request it.
+func (t *translator) requestServiceAccountToken(ctx context.Context, vPod *corev1.Pod, projection *corev1.ServiceAccountTokenProjection) (*authenticationv1.TokenRequest, error) {
+	serviceAccountName := "default"
+	if vPod.Spec.ServiceAccountName != "" {
+		serviceAccountName = vPod.Spec.ServiceAccountName
+	} else if vPod.Spec.DeprecatedServiceAccount != "" {
+		serviceAccountName = vPod.Spec.DeprecatedServiceAccount
+	}
+
+	// create new client
+	vClient, err := kubernetes.NewForConfig(t.vClientConfig)
+	if err != nil {
+		return nil, errors.Wrap(err, "create client")
+	}
+
+	var audiences []string
+	if projection.Audience != "" {
+		audiences = []string{projection.Audience}
+	}
+
+	token, err := vClient.CoreV1().ServiceAccounts(vPod.Namespace).CreateToken(ctx, serviceAccountName, &authenticationv1.TokenRequest{
+		Spec: authenticationv1.TokenRequestSpec{
+			Audiences: audiences,
+			BoundObjectRef: &authenticationv1.BoundObjectReference{
+				APIVersion: corev1.SchemeGroupVersion.String(),
+				Kind:       "Pod",
+				Name:       vPod.Name,
+				UID:        vPod.UID,
+			},
+			ExpirationSeconds: projection.ExpirationSeconds,
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "create token")
+	} else if token.Status.Token == "" {
+		return nil, errors.New("received empty token")
+	}
+
+	return token, nil
+}
+
+// refreshServiceAccountTokens requests new tokens for all projected service account tokens of the
+// virtual pod if the refresh time stored on the physical pod has passed. Tokens that are stored
+// as annotations are written to the given annotations, tokens stored in a secret are updated directly.
+func (t *translator) refreshServiceAccountTokens(ctx context.Context, vPod, pPod *corev1.Pod, annotations map[string]string) error {
+	refreshAfter, ok := TokenRefreshAfter(pPod)
+	if !ok || refreshAfter > 0 {
+		return nil
+	}
+
+	delete(annotations, ServiceAccountTokenRefreshAnnotation)
+	tokenSecrets := map[string]string{}
+	for _, vVolume := range vPod.Spec.Volumes {
+		if vVolume.Projected == nil {
+			continue
+		}
+
+		// the translated volume has the same name and keeps the order of the sources
+		pVolume := findVolume(pPod, vVolume.Name)
+		if pVolume == nil || pVolume.Projected == nil || len(pVolume.Projected.Sources) != len(vVolume.Projected.Sources) {
+			continue
+		}
+
+		for i, source := range vVolume.Projected.Sources {
+			if source.ServiceAccountToken == nil {
+				continue
+			}
+
+			token, err := t.requestServiceAccountToken(ctx, vPod, source.ServiceAccountToken)
+			if err != nil {
+				return err
+			}
+			setTokenRefresh(annotations, token)
+
+			pSource := pVolume.Projected.Sources[i]
+			if pSource.Secret != nil {
+				tokenSecrets[vVolume.Name] = token.Status.Token
+			} else if pSource.DownwardAPI != nil {
+				for _, item := range pSource.DownwardAPI.Items {
+					annotation := tokenAnnotationFromFieldRef(item.FieldRef)
+					if annotation != "" {
+						annotations[annotation] = token.Status.Token
+					}
+				}
+			}
+		}
+	}
+
+	// update the service account token holder secret if necessary
+	if len(tokenSecrets) > 0 {
+		err := UpdateSATokenSecret(ctx, t.pClient, vPod, tokenSecrets)
+		if err != nil {
+			return errors.Wrap(err, "update sa token secret")
+		}
+	}
+
+	return nil
+}
+
+// TokenRefreshAfter returns the duration after which the projected service account tokens of the
+// physical pod need to be refreshed. Returns false if the pod has no refreshable tokens.
+func TokenRefreshAfter(pPod *corev1.Pod) (time.Duration, bool) {
+	if pPod.Annotations == nil || pPod.Annotations[ServiceAccountTokenRefreshAnnotation] == "" {
+		return 0, false
+	}
+
+	refreshTime, err := time.Parse(time.RFC3339, pPod.Annotations[ServiceAccountTokenRefreshAnnotation])
+	if err != nil {
+		// refresh right away if the annotation is malformed
+		return 0, true
+	}
+
+	return time.Until(refreshTime), true
+}
+
+// setTokenRefresh stores the time the given token needs to be refreshed in the annotations, if it is
+// earlier than the currently stored time. Tokens are refreshed after 80% of their lifetime or after
+// 24 hours, whichever comes first.
+func setTokenRefresh(annotations map[string]string, token *authenticationv1.TokenRequest) {
+	now := time.Now()
+	refreshInterval := token.Status.ExpirationTimestamp.Sub(now) * 8 / 10
+	if refreshInterval > maxTokenRefreshInterval {
+		refreshInterval = maxTokenRefreshInterval
+	}
+
+	refreshTime := now.Add(refreshInterval)
+	if existing, err := time.Parse(time.RFC3339, annotations[ServiceAccountTokenRefreshAnnotation]); err == nil && existing.Before(refreshTime) {
+		return
+	}
+
+	annotations[ServiceAccountTokenRefreshAnnotation] = refreshTime.UTC().Format(time.RFC3339)
+}
+
+func tokenAnnotationFromFieldRef(fieldRef *corev1.ObjectFieldSelector) string {
+	if fieldRef == nil {
+		return ""
+	}
+
+	annotationsMatch := FieldPathAnnotationRegEx.FindStringSubmatch(fieldRef.FieldPath)
+	if len(annotationsMatch) == 2 && strings.HasPrefix(annotationsMatch[1], ServiceAccountTokenAnnotation) {
+		return annotationsMatch[1]
+	}
+
+	return ""
+}
+
+func findVolume(pod *corev1.Pod, name string) *corev1.Volume {
+	for i := range pod.Spec.Volumes {
+		if pod.Spec.Volumes[i].Name == name {
+			return &pod.Spec.Volumes[i]
+		}
+	}
+
+	return nil
+}
@@ -121,3 +121,23 @@ func SetPodAsOwner(ctx context.Context, pPod *corev1.Pod, pClient client.Client,
 
 	return pClient.Update(ctx, secret)
 }
+
+// UpdateSATokenSecret updates the given tokens within the existing service account token secret of the pod
+// in place, so the kubelet picks up the new tokens without the mounted secret disappearing in between.
+func UpdateSATokenSecret(ctx context.Context, pClient client.Client, vPod *corev1.Pod, tokens map[string]string) error {
+	existingSecret, err := GetSecretIfExists(ctx, pClient, vPod.Name, vPod.Namespace)
+	if err != nil {
+		return err
+	} else if existingSecret == nil {
+		return SATokenSecret(ctx, pClient, vPod, tokens)
+	}
+
+	if existingSecret.Data == nil {
+		existingSecret.Data = map[string][]byte{}
+	}
+	for key, token := range tokens {
+		existingSecret.Data[key] = []byte(token)
+	}
+
+	return pClient.Update(ctx, existingSecret)
+}
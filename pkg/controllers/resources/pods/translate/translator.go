@@ -16,13 +16,10 @@ import (
 	"github.com/loft-sh/vcluster/pkg/util/loghelper"
 	"github.com/loft-sh/vcluster/pkg/util/random"
 	"github.com/loft-sh/vcluster/pkg/util/translate"
-	"github.com/pkg/errors"
 	appsv1 "k8s.io/api/apps/v1"
-	authenticationv1 "k8s.io/api/authentication/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/component-helpers/storage/ephemeral"
@@ -42,6 +39,7 @@ const (
 	ClusterAutoScalerDaemonSetAnnotation = "cluster-autoscaler.kubernetes.io/daemonset-pod"
 	ServiceAccountNameAnnotation         = "vcluster.loft.sh/service-account-name"
 	ServiceAccountTokenAnnotation        = "vcluster.loft.sh/token-"
+	ServiceAccountTokenRefreshAnnotation = "vcluster.loft.sh/sa-token-refresh"
 )
 
 var (
@@ -56,6 +54,7 @@ type Translator interface {
 	Diff(ctx context.Context, vPod, pPod *corev1.Pod) (*corev1.Pod, error)
 
 	TranslateContainerEnv(envVar []corev1.EnvVar, envFrom []corev1.EnvFromSource, vPod *corev1.Pod, serviceEnvMap map[string]string) ([]corev1.EnvVar, []corev1.EnvFromSource)
+	TranslateEphemeralContainer(ephemeralContainer corev1.EphemeralContainer, vPod, pPod *corev1.Pod, serviceEnvMap map[string]string) corev1.EphemeralContainer
 }
 
 func NewTranslator(ctx *synccontext.RegisterContext, eventRecorder record.EventRecorder) (Translator, error) {
@@ -307,10 +306,7 @@ func (t *translator) Translate(ctx context.Context, vPod *corev1.Pod, services [
 
 	// translate ephemeral containers
 	for i := range pPod.Spec.EphemeralContainers {
-		envVar, envFrom := t.TranslateContainerEnv(pPod.Spec.EphemeralContainers[i].Env, pPod.Spec.EphemeralContainers[i].EnvFrom, vPod, serviceEnv)
-		pPod.Spec.EphemeralContainers[i].Env = envVar
-		pPod.Spec.EphemeralContainers[i].EnvFrom = envFrom
-		pPod.Spec.EphemeralContainers[i].Image = t.imageTranslator.Translate(pPod.Spec.EphemeralContainers[i].Image)
+		pPod.Spec.EphemeralContainers[i] = t.TranslateEphemeralContainer(pPod.Spec.EphemeralContainers[i], vPod, pPod, serviceEnv)
 	}
 
 	// translate image pull secrets
@@ -324,8 +320,9 @@ func (t *translator) Translate(ctx context.Context, vPod *corev1.Pod, services [
 		return nil, err
 	}
 
-	// translate topology spread constraints
-	if t.enableScheduler {
+	// translate topology spread constraints, pods the virtual scheduler couldn't place are scheduled by the host cluster
+	// and keep their constraints
+	if t.enableScheduler && pPod.Spec.NodeName != "" {
 		pPod.Spec.TopologySpreadConstraints = nil
 		pPod.Spec.Affinity = nil
 		pPod.Spec.NodeSelector = nil
@@ -481,42 +478,16 @@ func (t *translator) translateProjectedVolume(
 			}
 		}
 		if projectedVolume.Sources[i].ServiceAccountToken != nil {
-			serviceAccountName := "default"
-			if vPod.Spec.ServiceAccountName != "" {
-				serviceAccountName = vPod.Spec.ServiceAccountName
-			} else if vPod.Spec.DeprecatedServiceAccount != "" {
-				serviceAccountName = vPod.Spec.DeprecatedServiceAccount
-			}
-
-			// create new client
-			vClient, err := kubernetes.NewForConfig(t.vClientConfig)
+			token, err := t.requestServiceAccountToken(ctx, vPod, projectedVolume.Sources[i].ServiceAccountToken)
 			if err != nil {
-				return errors.Wrap(err, "create client")
-			}
-
-			audiences := []string{"https://kubernetes.default.svc." + t.clusterDomain, "https://kubernetes.default.svc", "https://kubernetes.default"}
-			if projectedVolume.Sources[i].ServiceAccountToken.Audience != "" {
-				audiences = []string{projectedVolume.Sources[i].ServiceAccountToken.Audience}
+				return err
 			}
 
-			expirationSeconds := int64(10 * 365 * 24 * 60 * 60)
-			token, err := vClient.CoreV1().ServiceAccounts(vPod.Namespace).CreateToken(ctx, serviceAccountName, &authenticationv1.TokenRequest{
-				Spec: authenticationv1.TokenRequestSpec{
-					Audiences: audiences,
-					BoundObjectRef: &authenticationv1.BoundObjectReference{
-						APIVersion: corev1.SchemeGroupVersion.String(),
-						Kind:       "Pod",
-						Name:       vPod.Name,
-						UID:        vPod.UID,
-					},
-					ExpirationSeconds: &expirationSeconds,
-				},
-			}, metav1.CreateOptions{})
-			if err != nil {
-				return errors.Wrap(err, "create token")
-			} else if token.Status.Token == "" {
-				return errors.New("received empty token")
+			// remember when the token needs to be refreshed
+			if pPod.Annotations == nil {
+				pPod.Annotations = map[string]string{}
 			}
+			setTokenRefresh(pPod.Annotations, token)
 
 			// rewrite projected volume
 			allRights := int32(0644)
@@ -540,10 +511,6 @@ func (t *translator) translateProjectedVolume(
 				}
 			} else {
 				// set annotation on physical pod
-				if pPod.Annotations == nil {
-					pPod.Annotations = map[string]string{}
-				}
-
 				var annotation string
 
 				for {
@@ -650,6 +617,30 @@ func (t *translator) TranslateContainerEnv(envVar []corev1.EnvVar, envFrom []cor
 	return envVar, envFrom
 }
 
+// TranslateEphemeralContainer translates an ephemeral container of the virtual pod so it can be
+// added to the host pod through the ephemeralcontainers subresource.
+func (t *translator) TranslateEphemeralContainer(ephemeralContainer corev1.EphemeralContainer, vPod, pPod *corev1.Pod, serviceEnvMap map[string]string) corev1.EphemeralContainer {
+	translated := *ephemeralContainer.DeepCopy()
+	translated.Env, translated.EnvFrom = t.TranslateContainerEnv(translated.Env, translated.EnvFrom, vPod, serviceEnvMap)
+	translated.Image = t.imageTranslator.Translate(translated.Image)
+
+	// the target container has to exist in the host pod, otherwise the api server rejects the update
+	if translated.TargetContainerName != "" {
+		found := false
+		for _, container := range pPod.Spec.Containers {
+			if container.Name == translated.TargetContainerName {
+				found = true
+				break
+			}
+		}
+		if !found {
+			translated.TargetContainerName = ""
+		}
+	}
+
+	return translated
+}
+
 func translateDownwardAPI(env *corev1.EnvVar) {
 	if env.ValueFrom == nil {
 		return
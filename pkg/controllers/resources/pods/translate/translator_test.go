@@ -3,11 +3,13 @@ package translate
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/loft-sh/vcluster/pkg/util/loghelper"
 	"github.com/loft-sh/vcluster/pkg/util/translate"
 	"gotest.tools/v3/assert"
 	"gotest.tools/v3/assert/cmp"
+	authenticationv1 "k8s.io/api/authentication/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/tools/record"
@@ -230,6 +232,44 @@ type translatePodVolumesTestCase struct {
 	expectedVolumes []corev1.Volume
 }
 
+func TestServiceAccountTokenRefresh(t *testing.T) {
+	tokenExpiringIn := func(expiration time.Duration) *authenticationv1.TokenRequest {
+		return &authenticationv1.TokenRequest{
+			Status: authenticationv1.TokenRequestStatus{
+				Token:               "token",
+				ExpirationTimestamp: metav1.NewTime(time.Now().Add(expiration)),
+			},
+		}
+	}
+
+	pPod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{}}}
+	_, ok := TokenRefreshAfter(pPod)
+	assert.Assert(t, !ok, "pod without projected tokens should not be refreshed")
+
+	// refresh after 80% of the token lifetime
+	setTokenRefresh(pPod.Annotations, tokenExpiringIn(time.Hour))
+	refreshAfter, ok := TokenRefreshAfter(pPod)
+	assert.Assert(t, ok)
+	assert.Assert(t, refreshAfter > 47*time.Minute && refreshAfter <= 48*time.Minute, "unexpected refresh after %s", refreshAfter)
+
+	// a later refresh time does not override the earlier one
+	setTokenRefresh(pPod.Annotations, tokenExpiringIn(365*24*time.Hour))
+	refreshAfter, _ = TokenRefreshAfter(pPod)
+	assert.Assert(t, refreshAfter <= 48*time.Minute, "unexpected refresh after %s", refreshAfter)
+
+	// long lived tokens are refreshed at least once a day
+	delete(pPod.Annotations, ServiceAccountTokenRefreshAnnotation)
+	setTokenRefresh(pPod.Annotations, tokenExpiringIn(365*24*time.Hour))
+	refreshAfter, _ = TokenRefreshAfter(pPod)
+	assert.Assert(t, refreshAfter > 23*time.Hour && refreshAfter <= 24*time.Hour, "unexpected refresh after %s", refreshAfter)
+
+	// malformed annotations are refreshed right away
+	pPod.Annotations[ServiceAccountTokenRefreshAnnotation] = "invalid"
+	refreshAfter, ok = TokenRefreshAfter(pPod)
+	assert.Assert(t, ok)
+	assert.Equal(t, refreshAfter, time.Duration(0))
+}
+
 func appendNamespacesToMatchExpressions(source *metav1.LabelSelector, namespaces ...string) *metav1.LabelSelector {
 	ls := source.DeepCopy()
 	ls.MatchExpressions = append(ls.MatchExpressions, metav1.LabelSelectorRequirement{
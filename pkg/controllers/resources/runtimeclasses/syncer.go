@@ -0,0 +1,45 @@
+package runtimeclasses
+
+import (
+	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
+	"github.com/loft-sh/vcluster/pkg/controllers/syncer/translator"
+	syncer "github.com/loft-sh/vcluster/pkg/types"
+	nodev1 "k8s.io/api/node/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func New(*synccontext.RegisterContext) (syncer.Object, error) {
+	return &runtimeClassSyncer{
+		Translator: translator.NewMirrorPhysicalTranslator("runtimeclass", &nodev1.RuntimeClass{}),
+	}, nil
+}
+
+type runtimeClassSyncer struct {
+	translator.Translator
+}
+
+var _ syncer.ToVirtualSyncer = &runtimeClassSyncer{}
+var _ syncer.Syncer = &runtimeClassSyncer{}
+
+func (r *runtimeClassSyncer) SyncToVirtual(ctx *synccontext.SyncContext, pObj client.Object) (ctrl.Result, error) {
+	vObj := r.translateBackwards(ctx.Context, pObj.(*nodev1.RuntimeClass))
+	ctx.Log.Infof("create runtime class %s, because it does not exist in virtual cluster", vObj.Name)
+	return ctrl.Result{}, ctx.VirtualClient.Create(ctx.Context, vObj)
+}
+
+func (r *runtimeClassSyncer) Sync(ctx *synccontext.SyncContext, pObj, vObj client.Object) (ctrl.Result, error) {
+	updated := r.translateUpdateBackwards(ctx.Context, pObj.(*nodev1.RuntimeClass), vObj.(*nodev1.RuntimeClass))
+	if updated != nil {
+		ctx.Log.Infof("update runtime class %s", vObj.GetName())
+		translator.PrintChanges(pObj, updated, ctx.Log)
+		return ctrl.Result{}, ctx.VirtualClient.Update(ctx.Context, updated)
+	}
+
+	return ctrl.Result{}, nil
+}
+
+func (r *runtimeClassSyncer) SyncToHost(ctx *synccontext.SyncContext, vObj client.Object) (ctrl.Result, error) {
+	ctx.Log.Infof("delete virtual runtime class %s, because physical object is missing", vObj.GetName())
+	return ctrl.Result{}, ctx.VirtualClient.Delete(ctx.Context, vObj)
+}
@@ -0,0 +1,293 @@
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
+	"github.com/loft-sh/vcluster/pkg/controllers/syncer/translator"
+	"github.com/loft-sh/vcluster/pkg/integrations/certmanager"
+	"github.com/loft-sh/vcluster/pkg/integrations/externalsecrets"
+	syncer "github.com/loft-sh/vcluster/pkg/types"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// HostSecretAnnotation is set on the virtual secrets that are synced back from the host cluster
+const HostSecretAnnotation = "vcluster.loft.sh/host-secret"
+
+// secretIssuer is a controller of the host cluster that creates secrets for a resource synced from the virtual cluster
+type secretIssuer struct {
+	// controllerName marks the virtual secrets of the issuer, so the secrets syncer leaves them alone
+	controllerName string
+
+	// ownerGVK is the kind of the resource the secrets are issued for
+	ownerGVK schema.GroupVersionKind
+
+	// ownerName returns the name of the host resource the host secret was issued for
+	ownerName func(pSecret client.Object) string
+
+	// secretName returns the name of the issued secret in the virtual cluster
+	secretName func(vOwner *unstructured.Unstructured) string
+
+	// annotationPrefix selects the annotations of the host secret that are copied to the virtual secret
+	annotationPrefix string
+
+	// ownerAnnotation is an annotation of the host secret that holds the owner name and is rewritten to the virtual owner
+	ownerAnnotation string
+}
+
+var certManagerIssuer = secretIssuer{
+	controllerName: certmanager.ControllerName,
+	ownerGVK:       schema.FromAPIVersionAndKind(certmanager.APIVersion, "Certificate"),
+	ownerName: func(pSecret client.Object) string {
+		return pSecret.GetAnnotations()[certmanager.CertificateNameAnnotation]
+	},
+	secretName: func(vCertificate *unstructured.Unstructured) string {
+		secretName, _, _ := unstructured.NestedString(vCertificate.Object, "spec", "secretName")
+		return secretName
+	},
+	annotationPrefix: "cert-manager.io/",
+	ownerAnnotation:  certmanager.CertificateNameAnnotation,
+}
+
+var externalSecretsIssuer = secretIssuer{
+	controllerName: externalsecrets.ControllerName,
+	ownerGVK:       schema.FromAPIVersionAndKind(externalsecrets.APIVersion, "ExternalSecret"),
+	ownerName: func(pSecret client.Object) string {
+		for _, ownerReference := range pSecret.GetOwnerReferences() {
+			if ownerReference.Kind == "ExternalSecret" && strings.HasPrefix(ownerReference.APIVersion, externalsecrets.Group+"/") {
+				return ownerReference.Name
+			}
+		}
+
+		return ""
+	},
+	secretName: func(vExternalSecret *unstructured.Unstructured) string {
+		secretName, _, _ := unstructured.NestedString(vExternalSecret.Object, "spec", "target", "name")
+		if secretName == "" {
+			// the external secrets operator names the secret after the ExternalSecret by default
+			return vExternalSecret.GetName()
+		}
+
+		return secretName
+	},
+	annotationPrefix: "reconcile.external-secrets.io/",
+}
+
+// NewCertManagerSyncer creates a syncer that syncs the secrets, which the cert-manager of the host cluster issues for
+// the Certificates of the virtual cluster, back into the virtual cluster. The virtual secrets are read-only.
+func NewCertManagerSyncer(ctx *synccontext.RegisterContext) (syncer.Object, error) {
+	return newIssuedSecretSyncer(ctx, "cert-manager-secret", certManagerIssuer), nil
+}
+
+// NewExternalSecretsSyncer creates a syncer that syncs the secrets, which the external secrets operator of the host
+// cluster creates for the ExternalSecrets of the virtual cluster, back into the virtual cluster. The virtual secrets are read-only.
+func NewExternalSecretsSyncer(ctx *synccontext.RegisterContext) (syncer.Object, error) {
+	return newIssuedSecretSyncer(ctx, "external-secrets-secret", externalSecretsIssuer), nil
+}
+
+func newIssuedSecretSyncer(ctx *synccontext.RegisterContext, name string, issuer secretIssuer) *issuedSecretSyncer {
+	return &issuedSecretSyncer{
+		name:           name,
+		issuer:         issuer,
+		physicalClient: ctx.PhysicalManager.GetClient(),
+		virtualClient:  ctx.VirtualManager.GetClient(),
+	}
+}
+
+type issuedSecretSyncer struct {
+	name   string
+	issuer secretIssuer
+
+	physicalClient client.Client
+	virtualClient  client.Client
+}
+
+var _ syncer.Syncer = &issuedSecretSyncer{}
+
+func (s *issuedSecretSyncer) Name() string {
+	return s.name
+}
+
+func (s *issuedSecretSyncer) Resource() client.Object {
+	return &corev1.Secret{}
+}
+
+func (s *issuedSecretSyncer) IsManaged(ctx context.Context, pObj client.Object) (bool, error) {
+	pOwner, err := s.hostOwner(ctx, pObj)
+	return pOwner != nil, err
+}
+
+func (s *issuedSecretSyncer) VirtualToHost(_ context.Context, _ types.NamespacedName, vObj client.Object) types.NamespacedName {
+	if vObj == nil {
+		return types.NamespacedName{}
+	}
+
+	namespace, name, found := strings.Cut(vObj.GetAnnotations()[HostSecretAnnotation], "/")
+	if !found {
+		return types.NamespacedName{}
+	}
+
+	return types.NamespacedName{Namespace: namespace, Name: name}
+}
+
+func (s *issuedSecretSyncer) HostToVirtual(ctx context.Context, _ types.NamespacedName, pObj client.Object) types.NamespacedName {
+	if pObj == nil {
+		return types.NamespacedName{}
+	}
+
+	vOwner, err := s.virtualOwner(ctx, pObj)
+	if err != nil || vOwner == nil {
+		return types.NamespacedName{}
+	}
+
+	secretName := s.issuer.secretName(vOwner)
+	if secretName == "" {
+		return types.NamespacedName{}
+	}
+
+	return types.NamespacedName{Namespace: vOwner.GetNamespace(), Name: secretName}
+}
+
+var _ syncer.ObjectExcluder = &issuedSecretSyncer{}
+
+func (s *issuedSecretSyncer) ExcludeVirtual(vObj client.Object) bool {
+	// secrets created within the virtual cluster are never overwritten
+	return vObj.GetLabels()[translate.ControllerLabel] != s.issuer.controllerName
+}
+
+func (s *issuedSecretSyncer) ExcludePhysical(client.Object) bool {
+	return false
+}
+
+var _ syncer.ToVirtualSyncer = &issuedSecretSyncer{}
+
+func (s *issuedSecretSyncer) SyncToVirtual(ctx *synccontext.SyncContext, pObj client.Object) (ctrl.Result, error) {
+	vOwner, err := s.virtualOwner(ctx.Context, pObj)
+	if err != nil {
+		return ctrl.Result{}, err
+	} else if vOwner == nil {
+		return ctrl.Result{}, nil
+	}
+
+	vSecret := s.translateIssuedSecret(pObj.(*corev1.Secret), vOwner, s.HostToVirtual(ctx.Context, types.NamespacedName{}, pObj))
+	ctx.Log.Infof("create secret %s/%s, because it was issued in the host cluster for %s %s", vSecret.Namespace, vSecret.Name, s.issuer.ownerGVK.Kind, vOwner.GetName())
+	return ctrl.Result{}, ctx.VirtualClient.Create(ctx.Context, vSecret)
+}
+
+func (s *issuedSecretSyncer) SyncToHost(ctx *synccontext.SyncContext, vObj client.Object) (ctrl.Result, error) {
+	ctx.Log.Infof("delete virtual secret %s/%s, because the host secret was deleted", vObj.GetNamespace(), vObj.GetName())
+	return ctrl.Result{}, ctx.VirtualClient.Delete(ctx.Context, vObj)
+}
+
+func (s *issuedSecretSyncer) Sync(ctx *synccontext.SyncContext, pObj client.Object, vObj client.Object) (ctrl.Result, error) {
+	vOwner, err := s.virtualOwner(ctx.Context, pObj)
+	if err != nil {
+		return ctrl.Result{}, err
+	} else if vOwner == nil {
+		return ctrl.Result{}, nil
+	}
+
+	vSecret := vObj.(*corev1.Secret)
+	expected := s.translateIssuedSecret(pObj.(*corev1.Secret), vOwner, types.NamespacedName{Namespace: vSecret.Namespace, Name: vSecret.Name})
+	if equality.Semantic.DeepEqual(vSecret.Data, expected.Data) && vSecret.Type == expected.Type && equality.Semantic.DeepEqual(vSecret.Annotations, expected.Annotations) && equality.Semantic.DeepEqual(vSecret.Labels, expected.Labels) {
+		return ctrl.Result{}, nil
+	}
+
+	updated := vSecret.DeepCopy()
+	updated.Data = expected.Data
+	updated.Type = expected.Type
+	updated.Annotations = expected.Annotations
+	updated.Labels = expected.Labels
+	ctx.Log.Infof("update virtual secret %s/%s, because the host secret has changed", updated.Namespace, updated.Name)
+	translator.PrintChanges(vSecret, updated, ctx.Log)
+	return ctrl.Result{}, ctx.VirtualClient.Update(ctx.Context, updated)
+}
+
+// hostOwner returns the host resource the given host secret was issued for, if it was synced by the virtual cluster
+func (s *issuedSecretSyncer) hostOwner(ctx context.Context, pSecret client.Object) (*unstructured.Unstructured, error) {
+	ownerName := s.issuer.ownerName(pSecret)
+	if ownerName == "" {
+		return nil, nil
+	}
+
+	pOwner := s.newOwner()
+	err := s.physicalClient.Get(ctx, types.NamespacedName{Namespace: pSecret.GetNamespace(), Name: ownerName}, pOwner)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("get host %s %s/%s: %w", s.issuer.ownerGVK.Kind, pSecret.GetNamespace(), ownerName, err)
+	} else if !translate.Default.IsManaged(pOwner) {
+		return nil, nil
+	}
+
+	return pOwner, nil
+}
+
+// virtualOwner returns the virtual resource the given host secret was issued for
+func (s *issuedSecretSyncer) virtualOwner(ctx context.Context, pSecret client.Object) (*unstructured.Unstructured, error) {
+	pOwner, err := s.hostOwner(ctx, pSecret)
+	if err != nil || pOwner == nil {
+		return nil, err
+	}
+
+	vName := types.NamespacedName{
+		Namespace: pOwner.GetAnnotations()[translate.NamespaceAnnotation],
+		Name:      pOwner.GetAnnotations()[translate.NameAnnotation],
+	}
+	vOwner := s.newOwner()
+	err = s.virtualClient.Get(ctx, vName, vOwner)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("get virtual %s %s: %w", s.issuer.ownerGVK.Kind, vName.String(), err)
+	}
+
+	return vOwner, nil
+}
+
+func (s *issuedSecretSyncer) translateIssuedSecret(pSecret *corev1.Secret, vOwner *unstructured.Unstructured, vName types.NamespacedName) *corev1.Secret {
+	annotations := map[string]string{}
+	for k, v := range pSecret.Annotations {
+		if strings.HasPrefix(k, s.issuer.annotationPrefix) {
+			annotations[k] = v
+		}
+	}
+	if s.issuer.ownerAnnotation != "" {
+		annotations[s.issuer.ownerAnnotation] = vOwner.GetName()
+	}
+	annotations[HostSecretAnnotation] = pSecret.Namespace + "/" + pSecret.Name
+
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        vName.Name,
+			Namespace:   vName.Namespace,
+			Annotations: annotations,
+			Labels: map[string]string{
+				translate.ControllerLabel: s.issuer.controllerName,
+			},
+		},
+		Type: pSecret.Type,
+		Data: pSecret.Data,
+	}
+}
+
+func (s *issuedSecretSyncer) newOwner() *unstructured.Unstructured {
+	owner := &unstructured.Unstructured{}
+	owner.SetGroupVersionKind(s.issuer.ownerGVK)
+	return owner
+}
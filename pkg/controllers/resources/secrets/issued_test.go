@@ -0,0 +1,242 @@
+package secrets
+
+import (
+	"testing"
+
+	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
+	generictesting "github.com/loft-sh/vcluster/pkg/controllers/syncer/testing"
+	"github.com/loft-sh/vcluster/pkg/integrations/certmanager"
+	"github.com/loft-sh/vcluster/pkg/integrations/externalsecrets"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestCertManagerSync(t *testing.T) {
+	translate.Default = translate.NewSingleNamespaceTranslator(generictesting.DefaultTestTargetNamespace)
+
+	vCertificate := newUnstructured(certManagerIssuer.ownerGVK)
+	vCertificate.SetName("web")
+	vCertificate.SetNamespace("default")
+	vCertificate.Object["spec"] = map[string]interface{}{"secretName": "web-tls"}
+
+	pCertificate := newUnstructured(certManagerIssuer.ownerGVK)
+	pCertificate.SetName(translate.Default.PhysicalName("web", "default"))
+	pCertificate.SetNamespace(generictesting.DefaultTestTargetNamespace)
+	pCertificate.SetLabels(map[string]string{translate.MarkerLabel: translate.VClusterName})
+	pCertificate.SetAnnotations(map[string]string{
+		translate.NameAnnotation:      "web",
+		translate.NamespaceAnnotation: "default",
+	})
+	pCertificate.Object["spec"] = map[string]interface{}{"secretName": translate.Default.PhysicalName("web-tls", "default")}
+
+	unmanagedCertificate := pCertificate.DeepCopy()
+	unmanagedCertificate.SetLabels(nil)
+
+	pSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      translate.Default.PhysicalName("web-tls", "default"),
+			Namespace: generictesting.DefaultTestTargetNamespace,
+			Annotations: map[string]string{
+				certmanager.CertificateNameAnnotation: pCertificate.GetName(),
+				"cert-manager.io/issuer-name":         "letsencrypt",
+				"example.com/other":                   "other",
+			},
+		},
+		Type: corev1.SecretTypeTLS,
+		Data: map[string][]byte{
+			"tls.crt": []byte("crt"),
+			"tls.key": []byte("key"),
+		},
+	}
+	vSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "web-tls",
+			Namespace: "default",
+			Annotations: map[string]string{
+				certmanager.CertificateNameAnnotation: "web",
+				HostSecretAnnotation:                  pSecret.Namespace + "/" + pSecret.Name,
+				"cert-manager.io/issuer-name":         "letsencrypt",
+			},
+			Labels: map[string]string{
+				translate.ControllerLabel: certmanager.ControllerName,
+			},
+		},
+		Type: corev1.SecretTypeTLS,
+		Data: pSecret.Data,
+	}
+	staleSecret := vSecret.DeepCopy()
+	staleSecret.Data = map[string][]byte{"tls.crt": []byte("old")}
+
+	secretGVK := corev1.SchemeGroupVersion.WithKind("Secret")
+	generictesting.RunTests(t, []*generictesting.SyncTest{
+		{
+			Name:                 "Sync issued secret into the virtual cluster",
+			InitialVirtualState:  []runtime.Object{vCertificate.DeepCopy()},
+			InitialPhysicalState: []runtime.Object{pCertificate.DeepCopy(), pSecret.DeepCopy()},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				secretGVK: {vSecret.DeepCopy()},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				syncCtx, syncer := generictesting.FakeStartSyncer(t, ctx, NewCertManagerSyncer)
+				managed, err := syncer.(*issuedSecretSyncer).IsManaged(syncCtx.Context, pSecret.DeepCopy())
+				assert.NilError(t, err)
+				assert.Assert(t, managed)
+				assert.Equal(t, syncer.(*issuedSecretSyncer).HostToVirtual(syncCtx.Context, types.NamespacedName{}, pSecret.DeepCopy()), types.NamespacedName{Namespace: "default", Name: "web-tls"})
+				assert.Equal(t, syncer.(*issuedSecretSyncer).VirtualToHost(syncCtx.Context, types.NamespacedName{}, vSecret.DeepCopy()), types.NamespacedName{Namespace: pSecret.Namespace, Name: pSecret.Name})
+
+				_, err = syncer.(*issuedSecretSyncer).SyncToVirtual(syncCtx, pSecret.DeepCopy())
+				assert.NilError(t, err)
+			},
+		},
+		{
+			Name:                 "Update renewed secret",
+			InitialVirtualState:  []runtime.Object{vCertificate.DeepCopy(), staleSecret.DeepCopy()},
+			InitialPhysicalState: []runtime.Object{pCertificate.DeepCopy(), pSecret.DeepCopy()},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				secretGVK: {vSecret.DeepCopy()},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				syncCtx, syncer := generictesting.FakeStartSyncer(t, ctx, NewCertManagerSyncer)
+				_, err := syncer.(*issuedSecretSyncer).Sync(syncCtx, pSecret.DeepCopy(), staleSecret.DeepCopy())
+				assert.NilError(t, err)
+			},
+		},
+		{
+			Name:                 "Ignore secrets of host certificates",
+			InitialVirtualState:  []runtime.Object{vCertificate.DeepCopy()},
+			InitialPhysicalState: []runtime.Object{unmanagedCertificate.DeepCopy(), pSecret.DeepCopy()},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				secretGVK: {},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				syncCtx, syncer := generictesting.FakeStartSyncer(t, ctx, NewCertManagerSyncer)
+				managed, err := syncer.(*issuedSecretSyncer).IsManaged(syncCtx.Context, pSecret.DeepCopy())
+				assert.NilError(t, err)
+				assert.Assert(t, !managed)
+			},
+		},
+		{
+			Name:                "Delete virtual secret if the host secret is gone",
+			InitialVirtualState: []runtime.Object{vCertificate.DeepCopy(), vSecret.DeepCopy()},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				secretGVK: {},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				syncCtx, syncer := generictesting.FakeStartSyncer(t, ctx, NewCertManagerSyncer)
+				assert.Assert(t, syncer.(*issuedSecretSyncer).ExcludeVirtual(&corev1.Secret{}))
+				_, err := syncer.(*issuedSecretSyncer).SyncToHost(syncCtx, vSecret.DeepCopy())
+				assert.NilError(t, err)
+			},
+		},
+	})
+}
+
+func TestExternalSecretsSync(t *testing.T) {
+	translate.Default = translate.NewSingleNamespaceTranslator(generictesting.DefaultTestTargetNamespace)
+
+	vExternalSecret := newUnstructured(externalSecretsIssuer.ownerGVK)
+	vExternalSecret.SetName("database")
+	vExternalSecret.SetNamespace("default")
+	vExternalSecret.Object["spec"] = map[string]interface{}{
+		"secretStoreRef": map[string]interface{}{"name": "vault", "kind": "ClusterSecretStore"},
+	}
+
+	pExternalSecret := newUnstructured(externalSecretsIssuer.ownerGVK)
+	pExternalSecret.SetName(translate.Default.PhysicalName("database", "default"))
+	pExternalSecret.SetNamespace(generictesting.DefaultTestTargetNamespace)
+	pExternalSecret.SetLabels(map[string]string{translate.MarkerLabel: translate.VClusterName})
+	pExternalSecret.SetAnnotations(map[string]string{
+		translate.NameAnnotation:      "database",
+		translate.NamespaceAnnotation: "default",
+	})
+	pExternalSecret.Object["spec"] = map[string]interface{}{
+		"secretStoreRef": map[string]interface{}{"name": "vault", "kind": "ClusterSecretStore"},
+		"target":         map[string]interface{}{"name": translate.Default.PhysicalName("database", "default")},
+	}
+
+	pSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      translate.Default.PhysicalName("database", "default"),
+			Namespace: generictesting.DefaultTestTargetNamespace,
+			Annotations: map[string]string{
+				"reconcile.external-secrets.io/data-hash": "abc",
+			},
+			OwnerReferences: []metav1.OwnerReference{
+				{
+					APIVersion: externalsecrets.APIVersion,
+					Kind:       "ExternalSecret",
+					Name:       pExternalSecret.GetName(),
+				},
+			},
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{
+			"password": []byte("secret"),
+		},
+	}
+	vSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "database",
+			Namespace: "default",
+			Annotations: map[string]string{
+				HostSecretAnnotation:                      pSecret.Namespace + "/" + pSecret.Name,
+				"reconcile.external-secrets.io/data-hash": "abc",
+			},
+			Labels: map[string]string{
+				translate.ControllerLabel: externalsecrets.ControllerName,
+			},
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: pSecret.Data,
+	}
+
+	secretGVK := corev1.SchemeGroupVersion.WithKind("Secret")
+	generictesting.RunTests(t, []*generictesting.SyncTest{
+		{
+			Name:                 "Sync secret of an external secret into the virtual cluster",
+			InitialVirtualState:  []runtime.Object{vExternalSecret.DeepCopy()},
+			InitialPhysicalState: []runtime.Object{pExternalSecret.DeepCopy(), pSecret.DeepCopy()},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				secretGVK: {vSecret.DeepCopy()},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				syncCtx, syncer := generictesting.FakeStartSyncer(t, ctx, NewExternalSecretsSyncer)
+				assert.Equal(t, syncer.(*issuedSecretSyncer).HostToVirtual(syncCtx.Context, types.NamespacedName{}, pSecret.DeepCopy()), types.NamespacedName{Namespace: "default", Name: "database"})
+				assert.Assert(t, syncer.(*issuedSecretSyncer).ExcludeVirtual(&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{translate.ControllerLabel: certmanager.ControllerName}},
+				}))
+
+				_, err := syncer.(*issuedSecretSyncer).SyncToVirtual(syncCtx, pSecret.DeepCopy())
+				assert.NilError(t, err)
+			},
+		},
+		{
+			Name:                 "Ignore secrets without an external secret owner",
+			InitialVirtualState:  []runtime.Object{vExternalSecret.DeepCopy()},
+			InitialPhysicalState: []runtime.Object{pExternalSecret.DeepCopy()},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				secretGVK: {},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				syncCtx, syncer := generictesting.FakeStartSyncer(t, ctx, NewExternalSecretsSyncer)
+				unowned := pSecret.DeepCopy()
+				unowned.OwnerReferences = nil
+				managed, err := syncer.(*issuedSecretSyncer).IsManaged(syncCtx.Context, unowned)
+				assert.NilError(t, err)
+				assert.Assert(t, !managed)
+			},
+		},
+	})
+}
+
+func newUnstructured(gvk schema.GroupVersionKind) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(gvk)
+	return obj
+}
@@ -4,11 +4,14 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync"
 
 	"github.com/loft-sh/vcluster/pkg/controllers/syncer/translator"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 
+	vclusterconfig "github.com/loft-sh/vcluster/config"
+	"github.com/loft-sh/vcluster/pkg/config"
 	"github.com/loft-sh/vcluster/pkg/constants"
 	"github.com/loft-sh/vcluster/pkg/controllers/resources/ingresses"
 	"github.com/loft-sh/vcluster/pkg/controllers/resources/ingresses/legacy"
@@ -37,14 +40,16 @@ func New(ctx *synccontext.RegisterContext) (syncer.Object, error) {
 }
 
 func NewSyncer(ctx *synccontext.RegisterContext, useLegacy bool) (syncer.Object, error) {
-	return &secretSyncer{
+	s := &secretSyncer{
 		NamespacedTranslator: translator.NewNamespacedTranslator(ctx, "secret", &corev1.Secret{}),
 
 		useLegacyIngress: useLegacy,
 		includeIngresses: ctx.Config.Sync.ToHost.Ingresses.Enabled,
 
-		syncAllSecrets: ctx.Config.Sync.ToHost.Secrets.All,
-	}, nil
+		options: ctx.Config.Sync.ToHost.Secrets,
+	}
+
+	return s, nil
 }
 
 type secretSyncer struct {
@@ -53,7 +58,23 @@ type secretSyncer struct {
 	useLegacyIngress bool
 	includeIngresses bool
 
-	syncAllSecrets bool
+	optionsMutex sync.RWMutex
+	options      vclusterconfig.SyncSecrets
+}
+
+var _ syncer.ConfigReloader = &secretSyncer{}
+
+// ReloadConfig applies the secret filters, which can change at runtime
+func (s *secretSyncer) ReloadConfig(newConfig *config.VirtualClusterConfig) {
+	s.optionsMutex.Lock()
+	defer s.optionsMutex.Unlock()
+	s.options = newConfig.Sync.ToHost.Secrets
+}
+
+func (s *secretSyncer) getOptions() vclusterconfig.SyncSecrets {
+	s.optionsMutex.RLock()
+	defer s.optionsMutex.RUnlock()
+	return s.options
 }
 
 var _ syncer.IndicesRegisterer = &secretSyncer{}
@@ -139,6 +160,11 @@ func (s *secretSyncer) isSecretUsed(ctx *synccontext.SyncContext, vObj runtime.O
 	secret, ok := vObj.(*corev1.Secret)
 	if !ok || secret == nil {
 		return false, fmt.Errorf("%#v is not a secret", vObj)
+	}
+
+	options := s.getOptions()
+	if isExcluded(options, secret) {
+		return false, nil
 	} else if secret.Annotations != nil && secret.Annotations[constants.SyncResourceAnnotation] == "true" {
 		return true, nil
 	}
@@ -171,7 +197,7 @@ func (s *secretSyncer) isSecretUsed(ctx *synccontext.SyncContext, vObj runtime.O
 		}
 	}
 
-	if s.syncAllSecrets {
+	if options.All {
 		return true, nil
 	}
 
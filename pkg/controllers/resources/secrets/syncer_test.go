@@ -61,6 +61,19 @@ func TestSync(t *testing.T) {
 		ObjectMeta: syncedSecret.ObjectMeta,
 		Data:       updatedSecret.Data,
 	}
+	tlsSecret := &corev1.Secret{
+		ObjectMeta: baseSecret.ObjectMeta,
+		Type:       corev1.SecretTypeTLS,
+		Data: map[string][]byte{
+			"test":    []byte("test"),
+			"tls.key": []byte("key"),
+		},
+	}
+	strippedSyncedSecret := &corev1.Secret{
+		ObjectMeta: syncedSecret.ObjectMeta,
+		Type:       corev1.SecretTypeTLS,
+		Data:       updatedSecret.Data,
+	}
 	basePod := &corev1.Pod{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      "test",
@@ -151,6 +164,60 @@ func TestSync(t *testing.T) {
 				assert.NilError(t, err)
 			},
 		},
+		{
+			Name: "Excluded secret type",
+			InitialVirtualState: []runtime.Object{
+				tlsSecret,
+				basePod,
+			},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{
+				corev1.SchemeGroupVersion.WithKind("Secret"): {},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				ctx.Config.Sync.ToHost.Secrets.ExcludeTypes = []string{"kubernetes.io/*"}
+				syncContext, syncer := newFakeSyncer(t, ctx)
+				_, err := syncer.(*secretSyncer).SyncToHost(syncContext, tlsSecret)
+				assert.NilError(t, err)
+			},
+		},
+		{
+			Name: "Opaque only removes synced secret",
+			InitialVirtualState: []runtime.Object{
+				tlsSecret,
+				basePod,
+			},
+			InitialPhysicalState: []runtime.Object{
+				strippedSyncedSecret,
+			},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{
+				corev1.SchemeGroupVersion.WithKind("Secret"): {},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				ctx.Config.Sync.ToHost.Secrets.OpaqueOnly = true
+				syncContext, syncer := newFakeSyncer(t, ctx)
+				_, err := syncer.(*secretSyncer).Sync(syncContext, strippedSyncedSecret, tlsSecret)
+				assert.NilError(t, err)
+			},
+		},
+		{
+			Name: "Strip secret keys",
+			InitialVirtualState: []runtime.Object{
+				tlsSecret,
+				basePod,
+			},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{
+				corev1.SchemeGroupVersion.WithKind("Secret"): {
+					strippedSyncedSecret,
+				},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				ctx.Config.Experimental.SyncSettings.SyncLabels = []string{testLabel}
+				ctx.Config.Sync.ToHost.Secrets.StripKeys = []string{"*.key"}
+				syncContext, syncer := newFakeSyncer(t, ctx)
+				_, err := syncer.(*secretSyncer).SyncToHost(syncContext, tlsSecret)
+				assert.NilError(t, err)
+			},
+		},
 	})
 }
 
@@ -2,7 +2,9 @@ package secrets
 
 import (
 	"context"
+	"path"
 
+	vclusterconfig "github.com/loft-sh/vcluster/config"
 	"github.com/loft-sh/vcluster/pkg/controllers/syncer/translator"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
@@ -10,6 +12,7 @@ import (
 
 func (s *secretSyncer) translate(ctx context.Context, vObj *corev1.Secret) *corev1.Secret {
 	newSecret := s.TranslateMetadata(ctx, vObj).(*corev1.Secret)
+	newSecret.Data = translateData(s.getOptions(), vObj.Data)
 	if newSecret.Type == corev1.SecretTypeServiceAccountToken {
 		newSecret.Type = corev1.SecretTypeOpaque
 	}
@@ -21,9 +24,10 @@ func (s *secretSyncer) translateUpdate(ctx context.Context, pObj, vObj *corev1.S
 	var updated *corev1.Secret
 
 	// check data
-	if !equality.Semantic.DeepEqual(vObj.Data, pObj.Data) {
+	data := translateData(s.getOptions(), vObj.Data)
+	if !equality.Semantic.DeepEqual(data, pObj.Data) {
 		updated = translator.NewIfNil(updated, pObj)
-		updated.Data = vObj.Data
+		updated.Data = data
 	}
 
 	// check secret type
@@ -42,3 +46,42 @@ func (s *secretSyncer) translateUpdate(ctx context.Context, pObj, vObj *corev1.S
 
 	return updated
 }
+
+// isExcluded returns true if the secret type must not be synced to the host cluster
+func isExcluded(options vclusterconfig.SyncSecrets, secret *corev1.Secret) bool {
+	secretType := secret.Type
+	if secretType == "" {
+		secretType = corev1.SecretTypeOpaque
+	}
+	if options.OpaqueOnly && secretType != corev1.SecretTypeOpaque {
+		return true
+	}
+
+	return matchesAny(options.ExcludeTypes, string(secretType))
+}
+
+// translateData removes the keys that should be stripped from the secret data
+func translateData(options vclusterconfig.SyncSecrets, data map[string][]byte) map[string][]byte {
+	if len(options.StripKeys) == 0 || data == nil {
+		return data
+	}
+
+	newData := make(map[string][]byte, len(data))
+	for key, value := range data {
+		if !matchesAny(options.StripKeys, key) {
+			newData[key] = value
+		}
+	}
+
+	return newData
+}
+
+func matchesAny(patterns []string, value string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, value); matched {
+			return true
+		}
+	}
+
+	return false
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"time"
 
+	vclusterconfig "github.com/loft-sh/vcluster/config"
 	"github.com/loft-sh/vcluster/pkg/controllers/syncer"
 	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
 	"github.com/loft-sh/vcluster/pkg/controllers/syncer/translator"
@@ -29,6 +30,7 @@ func New(ctx *synccontext.RegisterContext) (syncertypes.Object, error) {
 		NamespacedTranslator: translator.NewNamespacedTranslator(ctx, "service", &corev1.Service{}, "field.cattle.io/publicEndpoints"),
 
 		serviceName: ctx.Config.WorkloadService,
+		externalDNS: ctx.Config.Integrations.ExternalDNS,
 	}, nil
 }
 
@@ -36,6 +38,7 @@ type serviceSyncer struct {
 	translator.NamespacedTranslator
 
 	serviceName string
+	externalDNS vclusterconfig.ExternalDNS
 }
 
 var _ syncertypes.OptionsProvider = &serviceSyncer{}
@@ -77,6 +80,12 @@ func (s *serviceSyncer) Sync(ctx *synccontext.SyncContext, pObj client.Object, v
 			if err != nil {
 				return ctrl.Result{}, err
 			}
+
+			// sync the status right away, so load balancer addresses assigned by the host cluster don't wait for the requeue
+			_, err = syncStatusBackwards(ctx, pService, newService)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
 		}
 
 		// we will requeue anyways
@@ -84,16 +93,10 @@ func (s *serviceSyncer) Sync(ctx *synccontext.SyncContext, pObj client.Object, v
 	}
 
 	// check if backwards status update is necessary
-	if !equality.Semantic.DeepEqual(vService.Status, pService.Status) {
-		newService := vService.DeepCopy()
-		newService.Status = pService.Status
-		ctx.Log.Infof("update virtual service %s/%s, because status is out of sync", vService.Namespace, vService.Name)
-		translator.PrintChanges(vService, newService, ctx.Log)
-		err := ctx.VirtualClient.Status().Update(ctx.Context, newService)
-		if err != nil {
-			return ctrl.Result{}, err
-		}
-
+	updated, err := syncStatusBackwards(ctx, pService, vService)
+	if err != nil {
+		return ctrl.Result{}, err
+	} else if updated {
 		return ctrl.Result{Requeue: true}, nil
 	}
 
@@ -106,6 +109,18 @@ func (s *serviceSyncer) Sync(ctx *synccontext.SyncContext, pObj client.Object, v
 	return s.SyncToHostUpdate(ctx, vObj, newService)
 }
 
+func syncStatusBackwards(ctx *synccontext.SyncContext, pService, vService *corev1.Service) (bool, error) {
+	if equality.Semantic.DeepEqual(vService.Status, pService.Status) {
+		return false, nil
+	}
+
+	newService := vService.DeepCopy()
+	newService.Status = pService.Status
+	ctx.Log.Infof("update virtual service %s/%s, because status is out of sync", vService.Namespace, vService.Name)
+	translator.PrintChanges(vService, newService, ctx.Log)
+	return true, ctx.VirtualClient.Status().Update(ctx.Context, newService)
+}
+
 func isSwitchingFromExternalName(pService *corev1.Service, vService *corev1.Service) bool {
 	return vService.Spec.Type == corev1.ServiceTypeExternalName && pService.Spec.Type != vService.Spec.Type && pService.Spec.ClusterIP != ""
 }
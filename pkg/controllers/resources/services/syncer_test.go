@@ -93,6 +93,30 @@ func TestSync(t *testing.T) {
 		},
 		Spec: updateForwardSpec,
 	}
+	externalDNSService := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      vObjectMeta.Name,
+			Namespace: vObjectMeta.Namespace,
+			Annotations: map[string]string{
+				"external-dns.alpha.kubernetes.io/hostname": "web.example.com",
+				"external-dns.alpha.kubernetes.io/access":   "private",
+			},
+		},
+	}
+	externalDNSSyncedService := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      pObjectMeta.Name,
+			Namespace: pObjectMeta.Namespace,
+			Annotations: map[string]string{
+				translate.NameAnnotation:                    vObjectMeta.Name,
+				translate.NamespaceAnnotation:               vObjectMeta.Namespace,
+				translate.UIDAnnotation:                     "",
+				translate.ManagedAnnotationsAnnotation:      "external-dns.alpha.kubernetes.io/hostname",
+				"external-dns.alpha.kubernetes.io/hostname": "web.example.com",
+			},
+			Labels: pObjectMeta.Labels,
+		},
+	}
 	updateBackwardSpec := corev1.ServiceSpec{
 		ExternalName:   "backwardExternal",
 		ExternalIPs:    []string{"123:221:123:221"},
@@ -366,6 +390,24 @@ func TestSync(t *testing.T) {
 				assert.NilError(t, err)
 			},
 		},
+		{
+			Name:                 "Update forward allowed external-dns annotations",
+			InitialVirtualState:  []runtime.Object{externalDNSService.DeepCopy()},
+			InitialPhysicalState: []runtime.Object{createdService.DeepCopy()},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				corev1.SchemeGroupVersion.WithKind("Service"): {externalDNSService.DeepCopy()},
+			},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{
+				corev1.SchemeGroupVersion.WithKind("Service"): {externalDNSSyncedService.DeepCopy()},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				ctx.Config.Integrations.ExternalDNS.Enabled = true
+				ctx.Config.Integrations.ExternalDNS.Annotations = []string{"external-dns.alpha.kubernetes.io/hostname"}
+				syncCtx, syncer := generictesting.FakeStartSyncer(t, ctx, New)
+				_, err := syncer.(*serviceSyncer).Sync(syncCtx, createdService.DeepCopy(), externalDNSService.DeepCopy())
+				assert.NilError(t, err)
+			},
+		},
 		{
 			Name:                 "Update forward not needed",
 			InitialVirtualState:  []runtime.Object{baseService.DeepCopy()},
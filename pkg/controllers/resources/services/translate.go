@@ -3,14 +3,16 @@ package services
 import (
 	"context"
 
+	vclusterconfig "github.com/loft-sh/vcluster/config"
 	"github.com/loft-sh/vcluster/pkg/controllers/syncer/translator"
+	"github.com/loft-sh/vcluster/pkg/integrations/externaldns"
 	"github.com/loft-sh/vcluster/pkg/util/translate"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
 )
 
 func (s *serviceSyncer) translate(ctx context.Context, vObj *corev1.Service) *corev1.Service {
-	newService := s.TranslateMetadata(ctx, vObj).(*corev1.Service)
+	newService := s.TranslateMetadata(ctx, WithSyncedAnnotations(s.externalDNS, vObj)).(*corev1.Service)
 	newService.Spec.Selector = translate.Default.TranslateLabels(vObj.Spec.Selector, vObj.Namespace, nil)
 	if newService.Spec.ClusterIP != "None" {
 		newService.Spec.ClusterIP = ""
@@ -31,6 +33,18 @@ func (s *serviceSyncer) translate(ctx context.Context, vObj *corev1.Service) *co
 	return newService
 }
 
+// WithSyncedAnnotations returns the virtual service without the external-dns annotations that are not synced to the
+// host cluster
+func WithSyncedAnnotations(externalDNS vclusterconfig.ExternalDNS, vObj *corev1.Service) *corev1.Service {
+	if !externalDNS.Enabled {
+		return vObj
+	}
+
+	vObj = vObj.DeepCopy()
+	vObj.Annotations = externaldns.FilterAnnotations(externalDNS, vObj.Annotations)
+	return vObj
+}
+
 func StripNodePorts(vObj *corev1.Service) {
 	for i := range vObj.Spec.Ports {
 		vObj.Spec.Ports[i].NodePort = 0
@@ -80,7 +94,7 @@ func (s *serviceSyncer) translateUpdate(ctx context.Context, pObj, vObj *corev1.
 	var updated *corev1.Service
 
 	// check annotations
-	_, updatedAnnotations, updatedLabels := s.TranslateMetadataUpdate(ctx, vObj, pObj)
+	_, updatedAnnotations, updatedLabels := s.TranslateMetadataUpdate(ctx, WithSyncedAnnotations(s.externalDNS, vObj), pObj)
 	// remove the ServiceBlockDeletion annotation if it's not needed
 	if vObj.Spec.ClusterIP == pObj.Spec.ClusterIP {
 		delete(updatedAnnotations, ServiceBlockDeletion)
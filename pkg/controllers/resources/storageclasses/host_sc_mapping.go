@@ -0,0 +1,70 @@
+package storageclasses
+
+import (
+	"context"
+
+	"github.com/loft-sh/vcluster/config"
+	"github.com/loft-sh/vcluster/pkg/controllers/syncer/translator"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Mapping maps the names of host storage classes to the names of the storage classes synced into the virtual cluster
+type Mapping struct {
+	hostToVirtual map[string]string
+	virtualToHost map[string]string
+}
+
+// NewMapping returns the storage class mapping of the given config or nil if no mappings are configured
+func NewMapping(storageClasses config.SyncFromHostStorageClasses) *Mapping {
+	if len(storageClasses.Mappings) == 0 {
+		return nil
+	}
+
+	mapping := &Mapping{
+		hostToVirtual: map[string]string{},
+		virtualToHost: map[string]string{},
+	}
+	for _, m := range storageClasses.Mappings {
+		mapping.hostToVirtual[m.From] = m.To
+		mapping.virtualToHost[m.To] = m.From
+	}
+
+	return mapping
+}
+
+// HostToVirtual returns the virtual name of the host storage class or an empty string if it is not synced
+func (m *Mapping) HostToVirtual(hostName string) string {
+	if m == nil {
+		return hostName
+	}
+
+	return m.hostToVirtual[hostName]
+}
+
+// VirtualToHost returns the host name of the virtual storage class, names that are not mapped are returned as is
+func (m *Mapping) VirtualToHost(virtualName string) string {
+	if m == nil {
+		return virtualName
+	}
+	if hostName, ok := m.virtualToHost[virtualName]; ok {
+		return hostName
+	}
+
+	return virtualName
+}
+
+// hostStorageClassTranslator mirrors host storage classes and renames them according to the mapping
+type hostStorageClassTranslator struct {
+	translator.Translator
+
+	mapping *Mapping
+}
+
+func (t *hostStorageClassTranslator) VirtualToHost(_ context.Context, req types.NamespacedName, _ client.Object) types.NamespacedName {
+	return types.NamespacedName{Name: t.mapping.VirtualToHost(req.Name)}
+}
+
+func (t *hostStorageClassTranslator) HostToVirtual(_ context.Context, req types.NamespacedName, _ client.Object) types.NamespacedName {
+	return types.NamespacedName{Name: t.mapping.HostToVirtual(req.Name)}
+}
@@ -5,18 +5,27 @@ import (
 	"github.com/loft-sh/vcluster/pkg/controllers/syncer/translator"
 	syncer "github.com/loft-sh/vcluster/pkg/types"
 	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-func NewHostStorageClassSyncer(*synccontext.RegisterContext) (syncer.Object, error) {
+func NewHostStorageClassSyncer(ctx *synccontext.RegisterContext) (syncer.Object, error) {
+	mapping := NewMapping(ctx.Config.Sync.FromHost.StorageClasses)
 	return &hostStorageClassSyncer{
-		Translator: translator.NewMirrorPhysicalTranslator("host-storageclass", &storagev1.StorageClass{}),
+		Translator: &hostStorageClassTranslator{
+			Translator: translator.NewMirrorPhysicalTranslator("host-storageclass", &storagev1.StorageClass{}),
+			mapping:    mapping,
+		},
+
+		defaultStorageClass: ctx.Config.Sync.FromHost.StorageClasses.Default,
 	}, nil
 }
 
 type hostStorageClassSyncer struct {
 	translator.Translator
+
+	defaultStorageClass string
 }
 
 var _ syncer.ToVirtualSyncer = &hostStorageClassSyncer{}
@@ -30,6 +39,12 @@ func (s *hostStorageClassSyncer) SyncToVirtual(ctx *synccontext.SyncContext, pOb
 var _ syncer.Syncer = &hostStorageClassSyncer{}
 
 func (s *hostStorageClassSyncer) Sync(ctx *synccontext.SyncContext, pObj client.Object, vObj client.Object) (ctrl.Result, error) {
+	// the host storage class is synced under another name or not at all anymore
+	if s.HostToVirtual(ctx.Context, types.NamespacedName{Name: pObj.GetName()}, pObj).Name != vObj.GetName() {
+		ctx.Log.Infof("delete virtual storage class %s, because it is not mapped to physical storage class %s anymore", vObj.GetName(), pObj.GetName())
+		return ctrl.Result{}, ctx.VirtualClient.Delete(ctx.Context, vObj)
+	}
+
 	// check if there is a change
 	updated := s.translateUpdateBackwards(ctx.Context, pObj.(*storagev1.StorageClass), vObj.(*storagev1.StorageClass))
 	if updated != nil {
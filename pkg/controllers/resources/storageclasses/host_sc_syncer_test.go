@@ -0,0 +1,122 @@
+package storageclasses
+
+import (
+	"testing"
+
+	"github.com/loft-sh/vcluster/config"
+	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
+	"gotest.tools/assert"
+
+	generictesting "github.com/loft-sh/vcluster/pkg/controllers/syncer/testing"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestSyncHostMappings(t *testing.T) {
+	storageClasses := config.SyncFromHostStorageClasses{
+		Enabled: "true",
+		Mappings: []config.StorageClassMapping{
+			{From: "gp3", To: "standard"},
+			{From: "io2", To: "fast"},
+		},
+		Default: "fast",
+	}
+
+	pGP3 := &storagev1.StorageClass{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "gp3",
+			Annotations: map[string]string{DefaultStorageClassAnnotation: "true"},
+		},
+		Provisioner: "ebs.csi.aws.com",
+	}
+	vStandard := &storagev1.StorageClass{
+		ObjectMeta:  metav1.ObjectMeta{Name: "standard"},
+		Provisioner: "ebs.csi.aws.com",
+	}
+	vGP3 := &storagev1.StorageClass{
+		ObjectMeta:  metav1.ObjectMeta{Name: "gp3"},
+		Provisioner: "ebs.csi.aws.com",
+	}
+	pIO2 := &storagev1.StorageClass{
+		ObjectMeta:  metav1.ObjectMeta{Name: "io2"},
+		Provisioner: "ebs.csi.aws.com",
+	}
+	vFast := &storagev1.StorageClass{
+		ObjectMeta:  metav1.ObjectMeta{Name: "fast"},
+		Provisioner: "ebs.csi.aws.com",
+	}
+	vFastDefault := &storagev1.StorageClass{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "fast",
+			Annotations: map[string]string{DefaultStorageClassAnnotation: "true"},
+		},
+		Provisioner: "ebs.csi.aws.com",
+	}
+
+	generictesting.RunTests(t, []*generictesting.SyncTest{
+		{
+			Name:                 "Sync Up mapped storage class",
+			InitialPhysicalState: []runtime.Object{pGP3},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				storagev1.SchemeGroupVersion.WithKind("StorageClass"): {vStandard},
+			},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{
+				storagev1.SchemeGroupVersion.WithKind("StorageClass"): {pGP3},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				ctx.Config.Sync.FromHost.StorageClasses = storageClasses
+				syncCtx, syncer := generictesting.FakeStartSyncer(t, ctx, NewHostStorageClassSyncer)
+				_, err := syncer.(*hostStorageClassSyncer).SyncToVirtual(syncCtx, pGP3)
+				assert.NilError(t, err)
+			},
+		},
+		{
+			Name:                 "Sync default storage class",
+			InitialVirtualState:  []runtime.Object{vFast},
+			InitialPhysicalState: []runtime.Object{pIO2},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				storagev1.SchemeGroupVersion.WithKind("StorageClass"): {vFastDefault},
+			},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{
+				storagev1.SchemeGroupVersion.WithKind("StorageClass"): {pIO2},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				ctx.Config.Sync.FromHost.StorageClasses = storageClasses
+				syncCtx, syncer := generictesting.FakeStartSyncer(t, ctx, NewHostStorageClassSyncer)
+				_, err := syncer.(*hostStorageClassSyncer).Sync(syncCtx, pIO2, vFast)
+				assert.NilError(t, err)
+			},
+		},
+		{
+			Name:                 "Delete storage class synced under the host name",
+			InitialVirtualState:  []runtime.Object{vGP3},
+			InitialPhysicalState: []runtime.Object{pGP3},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				storagev1.SchemeGroupVersion.WithKind("StorageClass"): {},
+			},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{
+				storagev1.SchemeGroupVersion.WithKind("StorageClass"): {pGP3},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				ctx.Config.Sync.FromHost.StorageClasses = storageClasses
+				syncCtx, syncer := generictesting.FakeStartSyncer(t, ctx, NewHostStorageClassSyncer)
+				_, err := syncer.(*hostStorageClassSyncer).Sync(syncCtx, pGP3, vGP3)
+				assert.NilError(t, err)
+			},
+		},
+	})
+}
+
+func TestMapping(t *testing.T) {
+	var noMapping *Mapping
+	assert.Equal(t, noMapping.HostToVirtual("gp3"), "gp3")
+	assert.Equal(t, noMapping.VirtualToHost("gp3"), "gp3")
+
+	mapping := NewMapping(config.SyncFromHostStorageClasses{Mappings: []config.StorageClassMapping{{From: "gp3", To: "standard"}}})
+	assert.Equal(t, mapping.HostToVirtual("gp3"), "standard")
+	assert.Equal(t, mapping.HostToVirtual("io2"), "")
+	assert.Equal(t, mapping.VirtualToHost("standard"), "gp3")
+	assert.Equal(t, mapping.VirtualToHost("other"), "other")
+}
@@ -6,17 +6,46 @@ import (
 	"github.com/loft-sh/vcluster/pkg/controllers/syncer/translator"
 	storagev1 "k8s.io/api/storage/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/types"
 )
 
+var betaDefaultStorageClassAnnotation = "storageclass.beta.kubernetes.io/is-default-class"
+
 func (s *hostStorageClassSyncer) translateBackwards(ctx context.Context, pStorageClass *storagev1.StorageClass) *storagev1.StorageClass {
-	return s.TranslateMetadata(ctx, pStorageClass).(*storagev1.StorageClass)
+	vStorageClass := s.TranslateMetadata(ctx, pStorageClass).(*storagev1.StorageClass)
+	vStorageClass.Name = s.HostToVirtual(ctx, types.NamespacedName{Name: pStorageClass.Name}, pStorageClass).Name
+	vStorageClass.Annotations = s.translateDefaultAnnotation(vStorageClass.Name, vStorageClass.Annotations)
+	return vStorageClass
+}
+
+// translateDefaultAnnotation marks the configured storage class as default instead of the default storage class of the host cluster
+func (s *hostStorageClassSyncer) translateDefaultAnnotation(vName string, annotations map[string]string) map[string]string {
+	if s.defaultStorageClass == "" {
+		return annotations
+	}
+
+	newAnnotations := map[string]string{}
+	for k, v := range annotations {
+		if k != DefaultStorageClassAnnotation && k != betaDefaultStorageClassAnnotation {
+			newAnnotations[k] = v
+		}
+	}
+	if vName == s.defaultStorageClass {
+		newAnnotations[DefaultStorageClassAnnotation] = "true"
+	}
+	if len(newAnnotations) == 0 {
+		return nil
+	}
+
+	return newAnnotations
 }
 
 func (s *hostStorageClassSyncer) translateUpdateBackwards(ctx context.Context, pObj, vObj *storagev1.StorageClass) *storagev1.StorageClass {
 	var updated *storagev1.StorageClass
 
-	changed, updatedAnnotations, updatedLabels := s.TranslateMetadataUpdate(ctx, vObj, pObj)
-	if changed {
+	_, updatedAnnotations, updatedLabels := s.TranslateMetadataUpdate(ctx, vObj, pObj)
+	updatedAnnotations = s.translateDefaultAnnotation(vObj.Name, updatedAnnotations)
+	if !equality.Semantic.DeepEqual(updatedAnnotations, vObj.Annotations) || !equality.Semantic.DeepEqual(updatedLabels, vObj.Labels) {
 		updated = translator.NewIfNil(updated, vObj)
 		updated.Labels = updatedLabels
 		updated.Annotations = updatedAnnotations
@@ -83,6 +83,12 @@ func (s *volumeSnapshotContentSyncer) SyncToVirtual(ctx *synccontext.SyncContext
 	return ctrl.Result{}, s.virtualClient.Create(ctx.Context, vVSC)
 }
 
+var _ syncer.OptionsProvider = &volumeSnapshotContentSyncer{}
+
+func (s *volumeSnapshotContentSyncer) WithOptions() *syncer.Options {
+	return &syncer.Options{DisableOrderedDeletion: true}
+}
+
 var _ syncer.Syncer = &volumeSnapshotContentSyncer{}
 
 func (s *volumeSnapshotContentSyncer) SyncToHost(ctx *synccontext.SyncContext, vObj client.Object) (ctrl.Result, error) {
@@ -41,6 +41,12 @@ func (s *volumeSnapshotSyncer) Init(registerContext *synccontext.RegisterContext
 	return util.EnsureCRD(registerContext.Context, registerContext.VirtualManager.GetConfig(), []byte(volumeSnapshotCRD), volumesnapshotv1.SchemeGroupVersion.WithKind("VolumeSnapshot"))
 }
 
+var _ syncer.OptionsProvider = &volumeSnapshotSyncer{}
+
+func (s *volumeSnapshotSyncer) WithOptions() *syncer.Options {
+	return &syncer.Options{DisableOrderedDeletion: true}
+}
+
 var _ syncer.Syncer = &volumeSnapshotSyncer{}
 
 func (s *volumeSnapshotSyncer) SyncToHost(ctx *synccontext.SyncContext, vObj client.Object) (ctrl.Result, error) {
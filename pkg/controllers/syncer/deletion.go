@@ -0,0 +1,97 @@
+package syncer
+
+import (
+	"fmt"
+
+	"github.com/loft-sh/vcluster/pkg/constants"
+	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// orderDeletion makes sure the host object of a virtual object is deleted before the virtual object is removed and,
+// for imported objects, the virtual object is deleted before the host object is removed. Finalizers that were added
+// before are always handled, so objects are not stuck after ordered deletion was disabled. If done is true, the
+// reconcile should return with the given result.
+func (r *SyncController) orderDeletion(ctx *synccontext.SyncContext, vObj, pObj client.Object) (done bool, result ctrl.Result, err error) {
+	if vObj != nil && controllerutil.ContainsFinalizer(vObj, constants.HostCleanupFinalizer) && vObj.GetDeletionTimestamp() != nil {
+		return true, ctrl.Result{}, r.cleanupHost(ctx, vObj, pObj)
+	}
+	if pObj != nil && controllerutil.ContainsFinalizer(pObj, constants.VirtualCleanupFinalizer) && pObj.GetDeletionTimestamp() != nil {
+		return true, ctrl.Result{}, r.cleanupVirtual(ctx, vObj, pObj)
+	}
+	if !r.orderedDeletion || r.options.DisableOrderedDeletion || vObj == nil || pObj == nil || vObj.GetDeletionTimestamp() != nil || pObj.GetDeletionTimestamp() != nil {
+		return false, ctrl.Result{}, nil
+	}
+
+	// add the finalizer to the object that depends on the other one, the resulting event reconciles again
+	if r.options.ObjectsFromHost {
+		if !controllerutil.ContainsFinalizer(pObj, constants.VirtualCleanupFinalizer) {
+			return true, ctrl.Result{}, addFinalizer(ctx, ctx.PhysicalClient, pObj, constants.VirtualCleanupFinalizer)
+		}
+	} else if translate.Default.IsManaged(pObj) && !controllerutil.ContainsFinalizer(vObj, constants.HostCleanupFinalizer) {
+		return true, ctrl.Result{}, addFinalizer(ctx, ctx.VirtualClient, vObj, constants.HostCleanupFinalizer)
+	}
+
+	return false, ctrl.Result{}, nil
+}
+
+// cleanupHost deletes the host object of the deleted virtual object and removes the finalizer of the virtual object
+// after the host object is gone
+func (r *SyncController) cleanupHost(ctx *synccontext.SyncContext, vObj, pObj client.Object) error {
+	if pObj == nil {
+		ctx.Log.Infof("remove finalizer %s from virtual object, because the physical object is deleted", constants.HostCleanupFinalizer)
+		return removeFinalizer(ctx, ctx.VirtualClient, vObj, constants.HostCleanupFinalizer)
+	} else if pObj.GetDeletionTimestamp() != nil {
+		// the delete event of the physical object reconciles again
+		return nil
+	}
+
+	_, err := DeleteObject(ctx, pObj, "virtual object is being deleted")
+	return err
+}
+
+// cleanupVirtual deletes the virtual object of the deleted imported host object and removes the finalizer of the host
+// object after the virtual object is gone
+func (r *SyncController) cleanupVirtual(ctx *synccontext.SyncContext, vObj, pObj client.Object) error {
+	if vObj == nil {
+		ctx.Log.Infof("remove finalizer %s from physical object, because the virtual object is deleted", constants.VirtualCleanupFinalizer)
+		return removeFinalizer(ctx, ctx.PhysicalClient, pObj, constants.VirtualCleanupFinalizer)
+	} else if vObj.GetDeletionTimestamp() != nil {
+		// the delete event of the virtual object reconciles again
+		return nil
+	}
+
+	ctx.Log.Infof("delete virtual %s, because physical object is being deleted", vObj.GetName())
+	err := ctx.VirtualClient.Delete(ctx.Context, vObj)
+	if err != nil && !kerrors.IsNotFound(err) {
+		return fmt.Errorf("delete virtual object: %w", err)
+	}
+
+	return nil
+}
+
+func addFinalizer(ctx *synccontext.SyncContext, kubeClient client.Client, obj client.Object, finalizer string) error {
+	patch := client.MergeFromWithOptions(obj.DeepCopyObject().(client.Object), client.MergeFromWithOptimisticLock{})
+	controllerutil.AddFinalizer(obj, finalizer)
+	err := kubeClient.Patch(ctx.Context, obj, patch)
+	if err != nil {
+		return fmt.Errorf("add finalizer %s: %w", finalizer, err)
+	}
+
+	return nil
+}
+
+func removeFinalizer(ctx *synccontext.SyncContext, kubeClient client.Client, obj client.Object, finalizer string) error {
+	patch := client.MergeFromWithOptions(obj.DeepCopyObject().(client.Object), client.MergeFromWithOptimisticLock{})
+	controllerutil.RemoveFinalizer(obj, finalizer)
+	err := kubeClient.Patch(ctx.Context, obj, patch)
+	if err != nil && !kerrors.IsNotFound(err) {
+		return fmt.Errorf("remove finalizer %s: %w", finalizer, err)
+	}
+
+	return nil
+}
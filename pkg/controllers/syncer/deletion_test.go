@@ -0,0 +1,216 @@
+package syncer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/loft-sh/vcluster/pkg/constants"
+	generictesting "github.com/loft-sh/vcluster/pkg/controllers/syncer/testing"
+	syncertypes "github.com/loft-sh/vcluster/pkg/types"
+	"github.com/loft-sh/vcluster/pkg/util/loghelper"
+	testingutil "github.com/loft-sh/vcluster/pkg/util/testing"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	"github.com/moby/locker"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+func TestOrderDeletion(t *testing.T) {
+	translate.Default = translate.NewSingleNamespaceTranslator(generictesting.DefaultTestTargetNamespace)
+	now := metav1.Now()
+	vName := types.NamespacedName{Name: "a", Namespace: namespaceInVclusterA}
+	vSecret := func(deletionTimestamp *metav1.Time, finalizers ...string) *corev1.Secret {
+		return &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+			Name:              vName.Name,
+			Namespace:         vName.Namespace,
+			UID:               "123",
+			DeletionTimestamp: deletionTimestamp,
+			Finalizers:        finalizers,
+		}}
+	}
+	pSecret := func(deletionTimestamp *metav1.Time, finalizers ...string) *corev1.Secret {
+		return &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+			Name:      translate.Default.PhysicalName(vName.Name, vName.Namespace),
+			Namespace: generictesting.DefaultTestTargetNamespace,
+			Annotations: map[string]string{
+				translate.NameAnnotation:      vName.Name,
+				translate.NamespaceAnnotation: vName.Namespace,
+				translate.UIDAnnotation:       "123",
+			},
+			Labels: map[string]string{
+				translate.MarkerLabel:    translate.VClusterName,
+				translate.NamespaceLabel: vName.Namespace,
+			},
+			DeletionTimestamp: deletionTimestamp,
+			Finalizers:        finalizers,
+		}}
+	}
+
+	testCases := []struct {
+		name            string
+		orderedDeletion bool
+		options         syncertypes.Options
+		virtual         []runtime.Object
+		physical        []runtime.Object
+		reconciles      int
+
+		expectVirtual           bool
+		expectVirtualFinalizer  string
+		expectPhysical          bool
+		expectPhysicalFinalizer string
+	}{
+		{
+			name:                   "add host cleanup finalizer",
+			orderedDeletion:        true,
+			virtual:                []runtime.Object{vSecret(nil)},
+			physical:               []runtime.Object{pSecret(nil)},
+			reconciles:             1,
+			expectVirtual:          true,
+			expectVirtualFinalizer: constants.HostCleanupFinalizer,
+			expectPhysical:         true,
+		},
+		{
+			name:           "no finalizer when disabled",
+			virtual:        []runtime.Object{vSecret(nil)},
+			physical:       []runtime.Object{pSecret(nil)},
+			reconciles:     1,
+			expectVirtual:  true,
+			expectPhysical: true,
+		},
+		{
+			name:            "no finalizer when syncer opts out",
+			orderedDeletion: true,
+			options:         syncertypes.Options{DisableOrderedDeletion: true},
+			virtual:         []runtime.Object{vSecret(nil)},
+			physical:        []runtime.Object{pSecret(nil)},
+			reconciles:      1,
+			expectVirtual:   true,
+			expectPhysical:  true,
+		},
+		{
+			name:                   "delete host object before virtual finalizer is removed",
+			orderedDeletion:        true,
+			virtual:                []runtime.Object{vSecret(&now, constants.HostCleanupFinalizer)},
+			physical:               []runtime.Object{pSecret(nil)},
+			reconciles:             1,
+			expectVirtual:          true,
+			expectVirtualFinalizer: constants.HostCleanupFinalizer,
+		},
+		{
+			name:            "remove virtual finalizer after host object is deleted",
+			orderedDeletion: true,
+			virtual:         []runtime.Object{vSecret(&now, constants.HostCleanupFinalizer)},
+			physical:        []runtime.Object{pSecret(nil)},
+			reconciles:      2,
+		},
+		{
+			name:                   "wait for deleting host object",
+			orderedDeletion:        true,
+			virtual:                []runtime.Object{vSecret(&now, constants.HostCleanupFinalizer)},
+			physical:               []runtime.Object{pSecret(&now, "test/finalizer")},
+			reconciles:             2,
+			expectVirtual:          true,
+			expectVirtualFinalizer: constants.HostCleanupFinalizer,
+			expectPhysical:         true,
+		},
+		{
+			name:       "remove leftover finalizer when disabled",
+			virtual:    []runtime.Object{vSecret(&now, constants.HostCleanupFinalizer)},
+			physical:   []runtime.Object{pSecret(nil)},
+			reconciles: 2,
+		},
+		{
+			name:                    "add virtual cleanup finalizer to imported object",
+			orderedDeletion:         true,
+			options:                 syncertypes.Options{ObjectsFromHost: true},
+			virtual:                 []runtime.Object{vSecret(nil)},
+			physical:                []runtime.Object{pSecret(nil)},
+			reconciles:              1,
+			expectVirtual:           true,
+			expectPhysical:          true,
+			expectPhysicalFinalizer: constants.VirtualCleanupFinalizer,
+		},
+		{
+			name:                    "delete virtual object before imported object finalizer is removed",
+			orderedDeletion:         true,
+			options:                 syncertypes.Options{ObjectsFromHost: true},
+			virtual:                 []runtime.Object{vSecret(nil)},
+			physical:                []runtime.Object{pSecret(&now, constants.VirtualCleanupFinalizer)},
+			reconciles:              1,
+			expectPhysical:          true,
+			expectPhysicalFinalizer: constants.VirtualCleanupFinalizer,
+		},
+		{
+			name:            "remove imported object finalizer after virtual object is deleted",
+			orderedDeletion: true,
+			options:         syncertypes.Options{ObjectsFromHost: true},
+			virtual:         []runtime.Object{vSecret(nil)},
+			physical:        []runtime.Object{pSecret(&now, constants.VirtualCleanupFinalizer)},
+			reconciles:      2,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			scheme := testingutil.NewScheme()
+			pClient := testingutil.NewFakeClient(scheme, tc.physical...)
+			vClient := testingutil.NewFakeClient(scheme, tc.virtual...)
+			fakeContext := generictesting.NewFakeRegisterContext(pClient, vClient)
+
+			syncerImpl, err := NewMockSyncer(fakeContext)
+			assert.NilError(t, err)
+			syncer := syncerImpl.(syncertypes.Syncer)
+			options := tc.options
+			controller := &SyncController{
+				syncer:         syncer,
+				log:            loghelper.New(syncer.Name()),
+				vEventRecorder: &testingutil.FakeEventRecorder{},
+				physicalClient: pClient,
+
+				currentNamespace:       fakeContext.CurrentNamespace,
+				currentNamespaceClient: fakeContext.CurrentNamespaceClient,
+
+				virtualClient:   vClient,
+				options:         &options,
+				orderedDeletion: tc.orderedDeletion,
+
+				locker: locker.New(),
+			}
+
+			for i := 0; i < tc.reconciles; i++ {
+				_, err = controller.Reconcile(context.Background(), ctrl.Request{NamespacedName: vName})
+				assert.NilError(t, err)
+			}
+
+			assertFinalizer(t, vClient, vName, tc.expectVirtual, tc.expectVirtualFinalizer)
+			assertFinalizer(t, pClient, types.NamespacedName{Name: pSecret(nil).Name, Namespace: pSecret(nil).Namespace}, tc.expectPhysical, tc.expectPhysicalFinalizer)
+		})
+	}
+}
+
+func assertFinalizer(t *testing.T, kubeClient client.Client, name types.NamespacedName, exists bool, finalizer string) {
+	t.Helper()
+
+	secret := &corev1.Secret{}
+	err := kubeClient.Get(context.Background(), name, secret)
+	if !exists {
+		assert.Assert(t, kerrors.IsNotFound(err), "expected %s to be deleted, got %v", name, err)
+		return
+	}
+
+	assert.NilError(t, err)
+	if finalizer != "" {
+		assert.Assert(t, controllerutil.ContainsFinalizer(secret, finalizer), "expected %s to have finalizer %s, got %v", name, finalizer, secret.Finalizers)
+	} else {
+		for _, syncFinalizer := range constants.SyncFinalizers {
+			assert.Assert(t, !controllerutil.ContainsFinalizer(secret, syncFinalizer), "expected %s to have no finalizer %s", name, syncFinalizer)
+		}
+	}
+}
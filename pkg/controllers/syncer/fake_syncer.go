@@ -3,6 +3,7 @@ package syncer
 import (
 	"context"
 
+	"github.com/loft-sh/vcluster/pkg/config"
 	"github.com/loft-sh/vcluster/pkg/constants"
 	"github.com/loft-sh/vcluster/pkg/util/translate"
 
@@ -13,6 +14,7 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	controller2 "sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 )
 
 func RegisterFakeSyncer(ctx *synccontext.RegisterContext, syncer syncertypes.FakeSyncer) error {
@@ -25,6 +27,7 @@ func RegisterFakeSyncer(ctx *synccontext.RegisterContext, syncer syncertypes.Fak
 		currentNamespaceClient: ctx.CurrentNamespaceClient,
 
 		virtualClient: ctx.VirtualManager.GetClient(),
+		vConfig:       ctx.Config,
 	}
 
 	return controller.Register(ctx)
@@ -40,9 +43,22 @@ type fakeSyncer struct {
 	currentNamespaceClient client.Client
 
 	virtualClient client.Client
+
+	// vConfig is used to check if this replica owns the shard of the object
+	vConfig *config.VirtualClusterConfig
 }
 
 func (r *fakeSyncer) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	// with sharding, the object is synced by the replica that owns its shard
+	// and the shard is only reassigned once the running reconciles are done
+	if r.vConfig != nil {
+		release, owned := r.vConfig.AcquireShard(r.syncer.Name(), req.Namespace)
+		if !owned {
+			return ctrl.Result{}, nil
+		}
+		defer release()
+	}
+
 	log := loghelper.NewFromExisting(r.log.Base(), req.Name)
 	syncContext := &synccontext.SyncContext{
 		Context:                ctx,
@@ -91,6 +107,8 @@ func (r *fakeSyncer) Register(ctx *synccontext.RegisterContext) error {
 		}).
 		Named(r.syncer.Name()).
 		For(r.syncer.Resource())
+	controller = watchShardAssignment(ctx, controller, r.syncer.Resource(), &handler.EnqueueRequestForObject{}, nil)
+	controller = watchReload(ctx, controller, r.syncer, &handler.EnqueueRequestForObject{}, nil)
 	var err error
 	modifier, ok := r.syncer.(syncertypes.ControllerModifier)
 	if ok {
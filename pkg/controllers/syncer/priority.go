@@ -0,0 +1,38 @@
+package syncer
+
+import (
+	"sync/atomic"
+	"time"
+
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/ratelimiter"
+)
+
+// requests of low priority syncers are requeued with this interval while the high priority syncers catch up
+const catchUpRequeueInterval = 2 * time.Second
+
+// catchUpQueue is the queue of a high priority syncer, it tracks when the syncer starts to work off its requests
+type catchUpQueue struct {
+	workqueue.RateLimitingInterface
+
+	started atomic.Bool
+}
+
+func newCatchUpQueue(controllerName string, rateLimiter ratelimiter.RateLimiter) *catchUpQueue {
+	return &catchUpQueue{
+		RateLimitingInterface: workqueue.NewRateLimitingQueueWithConfig(rateLimiter, workqueue.RateLimitingQueueConfig{
+			Name: controllerName,
+		}),
+	}
+}
+
+// Get is only called by the workers of the controller, which are started after the caches were synced. At that point
+// the queue holds all objects that existed before.
+func (q *catchUpQueue) Get() (interface{}, bool) {
+	q.started.Store(true)
+	return q.RateLimitingInterface.Get()
+}
+
+func (q *catchUpQueue) Started() bool {
+	return q.started.Load()
+}
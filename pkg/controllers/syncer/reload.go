@@ -0,0 +1,25 @@
+package syncer
+
+import (
+	"github.com/loft-sh/vcluster/pkg/config"
+	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
+	syncertypes "github.com/loft-sh/vcluster/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+)
+
+// watchReload passes the reloaded config to syncers that implement syncertypes.ConfigReloader and enqueues all of their
+// objects again afterwards, so the changed config also applies to objects that don't change otherwise
+func watchReload(ctx *synccontext.RegisterContext, controller *builder.Builder, syncer syncertypes.Object, virtualHandler, hostHandler handler.EventHandler) *builder.Builder {
+	reloader, ok := syncer.(syncertypes.ConfigReloader)
+	if !ok || ctx.Config == nil {
+		return controller
+	}
+
+	controller, resync := watchResync(ctx, controller, syncer.Resource(), virtualHandler, hostHandler)
+	ctx.Config.OnReload(func(newConfig *config.VirtualClusterConfig) {
+		reloader.ReloadConfig(newConfig)
+		resync()
+	})
+	return controller
+}
@@ -0,0 +1,57 @@
+package syncer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/loft-sh/vcluster/pkg/config"
+	generictesting "github.com/loft-sh/vcluster/pkg/controllers/syncer/testing"
+	testingutil "github.com/loft-sh/vcluster/pkg/util/testing"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+type reloadingSyncer struct {
+	mockSyncer
+
+	reloaded chan *config.VirtualClusterConfig
+}
+
+func (s *reloadingSyncer) ReloadConfig(newConfig *config.VirtualClusterConfig) {
+	s.reloaded <- newConfig
+}
+
+func TestWatchReload(t *testing.T) {
+	scheme := testingutil.NewScheme()
+	vClient := testingutil.NewFakeClient(scheme,
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "a", Namespace: "default"}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "b", Namespace: "default"}},
+	)
+	pClient := testingutil.NewFakeClient(scheme)
+	registerContext := generictesting.NewFakeRegisterContext(pClient, vClient)
+	ctx, cancel := context.WithCancel(registerContext.Context)
+	defer cancel()
+	registerContext.Context = ctx
+
+	object, err := NewMockSyncer(registerContext)
+	assert.NilError(t, err)
+	syncer := &reloadingSyncer{mockSyncer: *object.(*mockSyncer), reloaded: make(chan *config.VirtualClusterConfig, 1)}
+
+	// syncers without reloadable config are not reloaded
+	controller := ctrl.NewControllerManagedBy(registerContext.VirtualManager)
+	assert.Equal(t, watchReload(registerContext, controller, object, nil, nil), controller)
+
+	watchReload(registerContext, controller, syncer, nil, nil)
+	newConfig := &config.VirtualClusterConfig{}
+	registerContext.Config.Reload(newConfig)
+	assert.Equal(t, <-syncer.reloaded, newConfig)
+
+	// all objects of the syncer are enqueued again after the reload
+	events := make(chan event.GenericEvent)
+	go enqueueAll(ctx, registerContext.VirtualManager, &corev1.Secret{}, events)
+	names := []string{(<-events).Object.GetName(), (<-events).Object.GetName()}
+	assert.DeepEqual(t, names, []string{"a", "b"})
+}
@@ -0,0 +1,91 @@
+package syncer
+
+import (
+	"context"
+
+	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// watchShardAssignment enqueues all objects of the resource again after the shard of this replica changed, because
+// objects of a newly assigned shard are not synced until they change otherwise. The virtual objects are passed to the
+// virtual handler and the host objects to the host handler, the host handler is optional.
+func watchShardAssignment(ctx *synccontext.RegisterContext, controller *builder.Builder, obj client.Object, virtualHandler, hostHandler handler.EventHandler) *builder.Builder {
+	if ctx.Config == nil || !ctx.Config.Experimental.SyncSettings.Sharding.Enabled {
+		return controller
+	}
+
+	controller, resync := watchResync(ctx, controller, obj, virtualHandler, hostHandler)
+	ctx.Config.OnShardAssignment(resync)
+	return controller
+}
+
+// watchResync adds sources to the controller and returns a function that enqueues all objects of the resource through them
+func watchResync(ctx *synccontext.RegisterContext, controller *builder.Builder, obj client.Object, virtualHandler, hostHandler handler.EventHandler) (*builder.Builder, func()) {
+	virtualEvents := make(chan event.GenericEvent)
+	controller = controller.WatchesRawSource(source.Channel(virtualEvents, virtualHandler))
+	var hostEvents chan event.GenericEvent
+	if hostHandler != nil {
+		hostEvents = make(chan event.GenericEvent)
+		controller = controller.WatchesRawSource(source.Channel(hostEvents, hostHandler))
+	}
+
+	return controller, func() {
+		go enqueueAll(ctx.Context, ctx.VirtualManager, obj, virtualEvents)
+		if hostEvents != nil {
+			go enqueueAll(ctx.Context, ctx.PhysicalManager, obj, hostEvents)
+		}
+	}
+}
+
+// enqueueAll sends all objects of the resource to the channel, the client of the manager reads them from its cache
+func enqueueAll(ctx context.Context, manager ctrl.Manager, obj client.Object, events chan<- event.GenericEvent) {
+	gvk, err := apiutil.GVKForObject(obj, manager.GetScheme())
+	if err != nil {
+		klog.Errorf("Error resyncing objects: %v", err)
+		return
+	}
+
+	listObj, err := manager.GetScheme().New(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+	if err != nil {
+		klog.Errorf("Error resyncing %s: %v", gvk.Kind, err)
+		return
+	}
+	list, ok := listObj.(client.ObjectList)
+	if !ok {
+		klog.Errorf("Error resyncing %s: %T is not a list", gvk.Kind, listObj)
+		return
+	}
+
+	err = manager.GetClient().List(ctx, list)
+	if err != nil {
+		klog.Errorf("Error resyncing %s: %v", gvk.Kind, err)
+		return
+	}
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		klog.Errorf("Error resyncing %s: %v", gvk.Kind, err)
+		return
+	}
+
+	for _, item := range items {
+		itemObj, ok := item.(client.Object)
+		if !ok {
+			continue
+		}
+
+		select {
+		case events <- event.GenericEvent{Object: itemObj}:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
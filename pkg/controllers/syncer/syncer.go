@@ -6,6 +6,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/loft-sh/vcluster/pkg/config"
 	"github.com/loft-sh/vcluster/pkg/constants"
 	"github.com/loft-sh/vcluster/pkg/util/translate"
 	"github.com/moby/locker"
@@ -13,6 +14,7 @@ import (
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog/v2"
 	controller2 "sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/ratelimiter"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
@@ -28,6 +30,9 @@ import (
 
 const hostObjectRequestPrefix = "host#"
 
+// requests of paused syncers are requeued with this interval until the syncer is resumed
+const pausedRequeueInterval = 10 * time.Second
+
 func NewSyncController(ctx *synccontext.RegisterContext, syncer syncertypes.Syncer) *SyncController {
 	options := &syncertypes.Options{}
 	optionsProvider, ok := syncer.(syncertypes.OptionsProvider)
@@ -35,6 +40,11 @@ func NewSyncController(ctx *synccontext.RegisterContext, syncer syncertypes.Sync
 		options = optionsProvider.WithOptions()
 	}
 
+	priority := ""
+	if ctx.Config != nil {
+		priority = ctx.Config.SyncPriority(syncer.Name())
+	}
+
 	return &SyncController{
 		syncer:         syncer,
 		log:            loghelper.New(syncer.Name()),
@@ -47,6 +57,10 @@ func NewSyncController(ctx *synccontext.RegisterContext, syncer syncertypes.Sync
 		virtualClient: ctx.VirtualManager.GetClient(),
 		options:       options,
 
+		orderedDeletion: ctx.Config != nil && ctx.Config.Experimental.SyncSettings.OrderedDeletion,
+		vConfig:         ctx.Config,
+		priority:        priority,
+
 		locker: locker.New(),
 	}
 }
@@ -69,10 +83,24 @@ type SyncController struct {
 	virtualClient client.Client
 	options       *syncertypes.Options
 
+	// orderedDeletion adds finalizers that order the deletion of virtual and physical objects
+	orderedDeletion bool
+
+	// vConfig is used to check if the syncer was paused at runtime
+	vConfig *config.VirtualClusterConfig
+
+	// priority is the catch up priority of the syncer after a restart
+	priority string
+
 	locker *locker.Locker
 }
 
 func (r *SyncController) Reconcile(ctx context.Context, origReq ctrl.Request) (_ ctrl.Result, err error) {
+	// paused syncers keep their requests queued, so the changes are synced after the syncer was resumed
+	if r.vConfig != nil && r.vConfig.IsSyncerPaused(r.syncer.Name()) {
+		return ctrl.Result{RequeueAfter: pausedRequeueInterval}, nil
+	}
+
 	// if host request we need to find the virtual object
 	vReq, pReq, err := r.extractRequest(ctx, origReq)
 	if err != nil {
@@ -81,6 +109,16 @@ func (r *SyncController) Reconcile(ctx context.Context, origReq ctrl.Request) (_
 		return ctrl.Result{}, nil
 	}
 
+	// with sharding, the object is synced by the replica that owns its shard
+	// and the shard is only reassigned once the running reconciles are done
+	if r.vConfig != nil {
+		release, owned := r.vConfig.AcquireShard(r.syncer.Name(), vReq.Namespace)
+		if !owned {
+			return ctrl.Result{}, nil
+		}
+		defer release()
+	}
+
 	// block for virtual object here because we want to avoid
 	// reconciling on the same object in parallel as this could
 	// happen if a host event and virtual event are queued at the
@@ -117,6 +155,17 @@ func (r *SyncController) Reconcile(ctx context.Context, origReq ctrl.Request) (_
 		return ctrl.Result{}, err
 	}
 
+	// low priority syncers only create missing host objects until the high priority syncers caught up after a restart
+	if r.priority == config.SyncPriorityLow && (vObj == nil || pObj != nil) && r.vConfig.CatchingUp() {
+		return ctrl.Result{RequeueAfter: catchUpRequeueInterval}, nil
+	}
+
+	// make sure dependent objects are deleted first
+	done, result, err := r.orderDeletion(syncContext, vObj, pObj)
+	if done || err != nil {
+		return result, err
+	}
+
 	// check what function we should call
 	if vObj != nil && pObj == nil {
 		return r.syncer.SyncToHost(syncContext, vObj)
@@ -385,15 +434,29 @@ func (r *SyncController) enqueuePhysical(ctx context.Context, obj client.Object,
 }
 
 func (r *SyncController) Register(ctx *synccontext.RegisterContext) error {
+	options := controller2.Options{
+		MaxConcurrentReconciles: 10,
+		CacheSyncTimeout:        constants.DefaultCacheSyncTimeout,
+	}
+
+	// low priority syncers wait until the queues of the high priority syncers are empty after a restart
+	if r.priority == config.SyncPriorityHigh {
+		r.vConfig.ExpectCatchUpQueue()
+		options.NewQueue = func(controllerName string, rateLimiter ratelimiter.RateLimiter) workqueue.RateLimitingInterface {
+			queue := newCatchUpQueue(controllerName, rateLimiter)
+			r.vConfig.AddCatchUpQueue(queue)
+			return queue
+		}
+	}
+
 	// build the basic controller
 	controller := ctrl.NewControllerManagedBy(ctx.VirtualManager).
-		WithOptions(controller2.Options{
-			MaxConcurrentReconciles: 10,
-			CacheSyncTimeout:        constants.DefaultCacheSyncTimeout,
-		}).
+		WithOptions(options).
 		Named(r.syncer.Name()).
 		Watches(r.syncer.Resource(), newEventHandler(r.enqueueVirtual)).
 		WatchesRawSource(source.Kind(ctx.PhysicalManager.GetCache(), r.syncer.Resource(), newEventHandler(r.enqueuePhysical)))
+	controller = watchShardAssignment(ctx, controller, r.syncer.Resource(), newEventHandler(r.enqueueVirtual), newEventHandler(r.enqueuePhysical))
+	controller = watchReload(ctx, controller, r.syncer, newEventHandler(r.enqueueVirtual), newEventHandler(r.enqueuePhysical))
 
 	// should add extra stuff?
 	modifier, isControllerModifier := r.syncer.(syncertypes.ControllerModifier)
@@ -6,9 +6,9 @@ import (
 	"fmt"
 	"os"
 	"path"
-	"strings"
 	"text/template"
 
+	"github.com/loft-sh/vcluster/config"
 	"github.com/loft-sh/vcluster/pkg/constants"
 	"github.com/loft-sh/vcluster/pkg/util/applier"
 	"k8s.io/apimachinery/pkg/version"
@@ -69,9 +69,7 @@ func getManifestVariables(defaultImageRegistry string, serverVersion *version.In
 	if !found {
 		vars[VarImage] = DefaultImage
 	}
-	if defaultImageRegistry != "" {
-		vars[VarImage] = strings.TrimSuffix(defaultImageRegistry, "/") + "/" + vars[VarImage].(string)
-	}
+	vars[VarImage] = config.WithDefaultImageRegistry(defaultImageRegistry, vars[VarImage].(string))
 	vars[VarRunAsUser] = fmt.Sprintf("%v", GetUserID())
 	vars[VarRunAsGroup] = fmt.Sprintf("%v", GetGroupID())
 	if os.Getenv("DEBUG") == "true" {
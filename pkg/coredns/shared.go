@@ -0,0 +1,337 @@
+package coredns
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"strconv"
+
+	"github.com/loft-sh/vcluster/pkg/config"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	// SharedDNSName is the name of the shared CoreDNS deployment, its service account and role
+	SharedDNSName = "vcluster-shared-dns"
+
+	// SharedDNSAppLabel is the label used to select the shared CoreDNS pods
+	SharedDNSAppLabel = "vcluster-shared-dns"
+
+	// SharedDNSTenantLabel marks tenant services and secrets within the shared dns namespace. The shared CoreDNS picks
+	// up the secrets with this label.
+	SharedDNSTenantLabel = "vcluster.loft.sh/shared-dns-tenant"
+
+	// SharedDNSPortsConfigMap is the config map within the shared dns namespace that holds the port of every tenant
+	SharedDNSPortsConfigMap = "vcluster-shared-dns-ports"
+
+	// SharedDNSServiceAccount is the service account within the virtual cluster the shared CoreDNS uses to read its
+	// services, endpoints and namespaces
+	SharedDNSServiceAccount = "vc-shared-dns"
+
+	sharedDNSMinPort = 10000
+	sharedDNSMaxPort = 30000
+)
+
+// keys of the tenant secrets within the shared dns namespace
+const (
+	tenantNameKey          = "name"
+	tenantNamespaceKey     = "namespace"
+	tenantClusterDomainKey = "clusterDomain"
+	tenantPortKey          = "port"
+	tenantCacheTTLKey      = "cacheTTL"
+	tenantCacheSizeKey     = "cacheSize"
+	tenantServerKey        = "server"
+	tenantTokenKey         = corev1.ServiceAccountTokenKey
+	tenantCAKey            = corev1.ServiceAccountRootCAKey
+)
+
+// SharedDNSTenant describes a single virtual cluster served by the shared CoreDNS
+type SharedDNSTenant struct {
+	// Name is the name of the virtual cluster
+	Name string
+
+	// Namespace is the host namespace of the virtual cluster
+	Namespace string
+
+	// ClusterDomain is the cluster domain used within the virtual cluster
+	ClusterDomain string
+
+	// CacheTTL is the max ttl of the tenant cache partition
+	CacheTTL int
+
+	// CacheSize is the capacity of the tenant cache partition
+	CacheSize int
+}
+
+// SharedDNSCredentials are used by the shared CoreDNS to read the services, endpoints and namespaces of a tenant
+type SharedDNSCredentials struct {
+	// Server is the address of the virtual cluster api server that is reachable from the shared CoreDNS
+	Server string
+
+	// Token is the token of the shared dns service account within the virtual cluster
+	Token []byte
+
+	// CA is the certificate authority of the virtual cluster api server
+	CA []byte
+}
+
+// NewSharedDNSTenant creates the shared dns tenant for the given virtual cluster config
+func NewSharedDNSTenant(vConfig *config.VirtualClusterConfig) SharedDNSTenant {
+	return SharedDNSTenant{
+		Name:          vConfig.Name,
+		Namespace:     vConfig.WorkloadNamespace,
+		ClusterDomain: vConfig.Networking.Advanced.ClusterDomain,
+		CacheTTL:      vConfig.Networking.Advanced.SharedDNS.Cache.TTL,
+		CacheSize:     vConfig.Networking.Advanced.SharedDNS.Cache.Size,
+	}
+}
+
+// Key returns the unique key of the tenant that is used as name of the tenant service and secret
+func (t SharedDNSTenant) Key() string {
+	return translate.SafeConcatName("vc", "dns", t.Name, "x", t.Namespace)
+}
+
+// PreferredPort returns the port the tenant would like to use within the shared CoreDNS. If the port is
+// already taken by another tenant, the next free port is chosen.
+func (t SharedDNSTenant) PreferredPort() int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(t.Namespace + "/" + t.Name))
+	return sharedDNSMinPort + int(h.Sum32()%uint32(sharedDNSMaxPort-sharedDNSMinPort))
+}
+
+// EnsureSharedDNSServiceAccount creates the service account the shared CoreDNS uses within the virtual cluster and
+// returns its token. The service account is only allowed to read services, endpoints and namespaces, so the shared
+// CoreDNS never holds admin credentials of its tenants.
+func EnsureSharedDNSServiceAccount(ctx context.Context, virtualClient client.Client) (token, ca []byte, err error) {
+	objects := []client.Object{
+		&corev1.ServiceAccount{
+			ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceSystem, Name: SharedDNSServiceAccount},
+		},
+		&rbacv1.ClusterRole{
+			ObjectMeta: metav1.ObjectMeta{Name: SharedDNSServiceAccount},
+			Rules: []rbacv1.PolicyRule{
+				{
+					APIGroups: []string{""},
+					Resources: []string{"services", "endpoints", "namespaces"},
+					Verbs:     []string{"list", "watch"},
+				},
+				{
+					APIGroups: []string{"discovery.k8s.io"},
+					Resources: []string{"endpointslices"},
+					Verbs:     []string{"list", "watch"},
+				},
+			},
+		},
+		&rbacv1.ClusterRoleBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: SharedDNSServiceAccount},
+			RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: SharedDNSServiceAccount},
+			Subjects:   []rbacv1.Subject{{Kind: rbacv1.ServiceAccountKind, Namespace: metav1.NamespaceSystem, Name: SharedDNSServiceAccount}},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   metav1.NamespaceSystem,
+				Name:        SharedDNSServiceAccount,
+				Annotations: map[string]string{corev1.ServiceAccountNameKey: SharedDNSServiceAccount},
+			},
+			Type: corev1.SecretTypeServiceAccountToken,
+		},
+	}
+	for _, obj := range objects {
+		err = virtualClient.Create(ctx, obj)
+		if err != nil && !kerrors.IsAlreadyExists(err) {
+			return nil, nil, fmt.Errorf("create shared dns %T: %w", obj, err)
+		}
+	}
+
+	// the token is filled in by the token controller of the virtual cluster
+	secret := &corev1.Secret{}
+	err = virtualClient.Get(ctx, types.NamespacedName{Namespace: metav1.NamespaceSystem, Name: SharedDNSServiceAccount}, secret)
+	if err != nil {
+		return nil, nil, fmt.Errorf("get shared dns token: %w", err)
+	} else if len(secret.Data[corev1.ServiceAccountTokenKey]) == 0 {
+		return nil, nil, fmt.Errorf("shared dns token %s/%s was not issued yet", metav1.NamespaceSystem, SharedDNSServiceAccount)
+	}
+
+	return secret.Data[corev1.ServiceAccountTokenKey], secret.Data[corev1.ServiceAccountRootCAKey], nil
+}
+
+// RegisterSharedDNSTenant registers the tenant within the shared CoreDNS. A port is allocated for the tenant, the
+// settings and credentials of the tenant are stored in a labeled secret the shared CoreDNS picks up, and a tenant
+// service points to the allocated port of the shared CoreDNS. Tenants only have access to their own secret and
+// service, the shared CoreDNS itself is never changed by them.
+func RegisterSharedDNSTenant(ctx context.Context, hostClient client.Client, sharedNamespace string, tenant SharedDNSTenant, credentials SharedDNSCredentials) error {
+	port, err := allocatePort(ctx, hostClient, sharedNamespace, tenant)
+	if err != nil {
+		return fmt.Errorf("allocate shared dns port: %w", err)
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: sharedNamespace,
+			Name:      tenant.Key(),
+		},
+	}
+	_, err = controllerutil.CreateOrPatch(ctx, hostClient, secret, func() error {
+		if secret.Labels == nil {
+			secret.Labels = map[string]string{}
+		}
+		secret.Labels[SharedDNSTenantLabel] = "true"
+		secret.Data = map[string][]byte{
+			tenantNameKey:          []byte(tenant.Name),
+			tenantNamespaceKey:     []byte(tenant.Namespace),
+			tenantClusterDomainKey: []byte(tenant.ClusterDomain),
+			tenantPortKey:          []byte(strconv.Itoa(port)),
+			tenantCacheTTLKey:      []byte(strconv.Itoa(tenant.CacheTTL)),
+			tenantCacheSizeKey:     []byte(strconv.Itoa(tenant.CacheSize)),
+			tenantServerKey:        []byte(credentials.Server),
+			tenantTokenKey:         credentials.Token,
+			tenantCAKey:            credentials.CA,
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("ensure shared dns tenant secret: %w", err)
+	}
+
+	return ensureTenantService(ctx, hostClient, sharedNamespace, tenant, port)
+}
+
+// UnregisterSharedDNSTenant removes the tenant from the shared CoreDNS and releases its port
+func UnregisterSharedDNSTenant(ctx context.Context, hostClient client.Client, sharedNamespace string, tenant SharedDNSTenant) error {
+	err := hostClient.Delete(ctx, &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: sharedNamespace, Name: tenant.Key()}})
+	if client.IgnoreNotFound(err) != nil {
+		return fmt.Errorf("delete shared dns tenant secret: %w", err)
+	}
+
+	err = hostClient.Delete(ctx, &corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: sharedNamespace, Name: tenant.Key()}})
+	if client.IgnoreNotFound(err) != nil {
+		return fmt.Errorf("delete shared dns tenant service: %w", err)
+	}
+
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		ports := &corev1.ConfigMap{}
+		err := hostClient.Get(ctx, types.NamespacedName{Namespace: sharedNamespace, Name: SharedDNSPortsConfigMap}, ports)
+		if kerrors.IsNotFound(err) {
+			return nil
+		} else if err != nil {
+			return err
+		} else if _, ok := ports.Data[tenant.Key()]; !ok {
+			return nil
+		}
+
+		delete(ports.Data, tenant.Key())
+		return hostClient.Update(ctx, ports)
+	})
+	if err != nil {
+		return fmt.Errorf("release shared dns port: %w", err)
+	}
+
+	return nil
+}
+
+// SharedDNSServiceIP returns the cluster ip of the tenant service within the shared dns namespace. The shared namespace
+// is not cached, so the service is read from the api server.
+func SharedDNSServiceIP(ctx context.Context, kubeClient kubernetes.Interface, sharedNamespace string, tenant SharedDNSTenant) (string, error) {
+	service, err := kubeClient.CoreV1().Services(sharedNamespace).Get(ctx, tenant.Key(), metav1.GetOptions{})
+	if err != nil {
+		return "", err
+	}
+
+	return service.Spec.ClusterIP, nil
+}
+
+func ensureTenantService(ctx context.Context, hostClient client.Client, sharedNamespace string, tenant SharedDNSTenant, port int) error {
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: sharedNamespace,
+			Name:      tenant.Key(),
+		},
+	}
+
+	_, err := controllerutil.CreateOrPatch(ctx, hostClient, service, func() error {
+		if service.Labels == nil {
+			service.Labels = map[string]string{}
+		}
+		service.Labels[SharedDNSTenantLabel] = "true"
+		service.Spec.Selector = map[string]string{"k8s-app": SharedDNSAppLabel}
+		service.Spec.Ports = []corev1.ServicePort{
+			{
+				Name:       "dns",
+				Port:       53,
+				TargetPort: intstr.FromInt32(int32(port)),
+				Protocol:   corev1.ProtocolUDP,
+			},
+			{
+				Name:       "dns-tcp",
+				Port:       53,
+				TargetPort: intstr.FromInt32(int32(port)),
+				Protocol:   corev1.ProtocolTCP,
+			},
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("ensure shared dns tenant service: %w", err)
+	}
+
+	return nil
+}
+
+// allocatePort returns the port of the tenant if it was allocated before, otherwise the preferred port of the tenant
+// or the next free port. The ports of all tenants are stored in a single config map that is only updated with the
+// resource version it was read with, so tenants registering at the same time never get the same port.
+func allocatePort(ctx context.Context, hostClient client.Client, sharedNamespace string, tenant SharedDNSTenant) (int, error) {
+	port := 0
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		ports := &corev1.ConfigMap{}
+		err := hostClient.Get(ctx, types.NamespacedName{Namespace: sharedNamespace, Name: SharedDNSPortsConfigMap}, ports)
+		if kerrors.IsNotFound(err) {
+			return fmt.Errorf("config map %s/%s not found, make sure the shared CoreDNS is installed with vcluster shared-dns manifests", sharedNamespace, SharedDNSPortsConfigMap)
+		} else if err != nil {
+			return err
+		}
+
+		// reuse the existing port of the tenant
+		usedPorts := map[int]bool{}
+		for key, value := range ports.Data {
+			usedPort, err := strconv.Atoi(value)
+			if err != nil {
+				continue
+			} else if key == tenant.Key() {
+				port = usedPort
+				return nil
+			}
+
+			usedPorts[usedPort] = true
+		}
+
+		port = tenant.PreferredPort()
+		for usedPorts[port] {
+			port++
+			if port >= sharedDNSMaxPort {
+				port = sharedDNSMinPort
+			}
+		}
+
+		if ports.Data == nil {
+			ports.Data = map[string]string{}
+		}
+		ports.Data[tenant.Key()] = strconv.Itoa(port)
+		return hostClient.Update(ctx, ports)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return port, nil
+}
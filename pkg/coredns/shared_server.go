@@ -0,0 +1,458 @@
+package coredns
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/loft-sh/log"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+const (
+	// SharedDNSConfigPath is the directory the Corefile and the kube configs of the tenants are written to within the
+	// shared CoreDNS pods
+	SharedDNSConfigPath = "/etc/coredns"
+
+	corefileName        = "Corefile"
+	kubeConfigExtension = ".kubeconfig"
+)
+
+// SharedDNSManifestOptions configure the manifests that install the shared CoreDNS
+type SharedDNSManifestOptions struct {
+	// Namespace is the namespace the shared CoreDNS is installed into
+	Namespace string
+
+	// Image is the CoreDNS image
+	Image string
+
+	// CLIImage is an image that contains the vcluster cli, such as ghcr.io/loft-sh/vcluster-cli. It runs next to
+	// CoreDNS and writes the tenants into the Corefile.
+	CLIImage string
+
+	// Replicas of the shared CoreDNS
+	Replicas int
+}
+
+// SharedDNSManifests returns the manifests that install the shared CoreDNS. The shared CoreDNS is the only one that
+// can read the tenant secrets: a sidecar picks them up by label and writes the Corefile, which CoreDNS reloads. Tenants
+// only need access to their own secret and service and to the ports config map.
+func SharedDNSManifests(options *SharedDNSManifestOptions) ([]byte, error) {
+	image := options.Image
+	if image == "" {
+		image = DefaultImage
+	}
+	replicas := options.Replicas
+	if replicas <= 0 {
+		replicas = 1
+	}
+
+	manifestTemplate, err := template.New("shared-dns").Parse(sharedDNSManifestTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("parse shared dns manifest: %w", err)
+	}
+
+	buf := new(bytes.Buffer)
+	err = manifestTemplate.Execute(buf, map[string]interface{}{
+		"NAMESPACE":    options.Namespace,
+		"NAME":         SharedDNSName,
+		"APP":          SharedDNSAppLabel,
+		"PORTS":        SharedDNSPortsConfigMap,
+		"IMAGE":        image,
+		"CLI_IMAGE":    options.CLIImage,
+		"REPLICAS":     replicas,
+		"CONFIG_PATH":  SharedDNSConfigPath,
+		"RUN_AS_USER":  defaultUID,
+		"RUN_AS_GROUP": defaultGID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("execute shared dns manifest: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// TenantServerBlock returns the Corefile server block for the given tenant listening on the given port.
+// Every tenant gets its own cache so that cached entries of one virtual cluster are never returned to another.
+func TenantServerBlock(tenant SharedDNSTenant, port int) string {
+	clusterDomain := tenant.ClusterDomain
+	if clusterDomain == "" {
+		clusterDomain = "cluster.local"
+	}
+
+	return fmt.Sprintf(`.:%d {
+    errors
+    kubernetes %s in-addr.arpa ip6.arpa {
+        kubeconfig %s
+        pods insecure
+        fallthrough in-addr.arpa ip6.arpa
+    }
+    forward . /etc/resolv.conf
+    cache %d {
+        success %d
+        denial %d
+    }
+    loop
+    loadbalance
+}
+`, port, clusterDomain, filepath.Join(SharedDNSConfigPath, tenant.Key()+kubeConfigExtension), tenant.CacheTTL, tenant.CacheSize, tenant.CacheSize)
+}
+
+// RunSharedDNSTenants watches the tenant secrets and the ports config map within the given namespace and writes the
+// Corefile and the kube configs of the tenants into the given directory whenever they change. If once is true, the
+// files are written a single time, which is used to create the initial Corefile before CoreDNS starts.
+func RunSharedDNSTenants(ctx context.Context, kubeClient kubernetes.Interface, namespace, dir string, once bool, log log.Logger) error {
+	if once {
+		secretList, err := kubeClient.CoreV1().Secrets(namespace).List(ctx, metav1.ListOptions{LabelSelector: SharedDNSTenantLabel + "=true"})
+		if err != nil {
+			return fmt.Errorf("list shared dns tenants: %w", err)
+		}
+		ports, err := kubeClient.CoreV1().ConfigMaps(namespace).Get(ctx, SharedDNSPortsConfigMap, metav1.GetOptions{})
+		if err != nil && !kerrors.IsNotFound(err) {
+			return fmt.Errorf("get shared dns ports: %w", err)
+		}
+
+		secrets := make([]*corev1.Secret, 0, len(secretList.Items))
+		for i := range secretList.Items {
+			secrets = append(secrets, &secretList.Items[i])
+		}
+		return writeSharedDNSTenants(dir, secrets, ports, log)
+	}
+
+	secretFactory := informers.NewSharedInformerFactoryWithOptions(kubeClient, 0, informers.WithNamespace(namespace), informers.WithTweakListOptions(func(options *metav1.ListOptions) {
+		options.LabelSelector = SharedDNSTenantLabel + "=true"
+	}))
+	configMapFactory := informers.NewSharedInformerFactoryWithOptions(kubeClient, 0, informers.WithNamespace(namespace), informers.WithTweakListOptions(func(options *metav1.ListOptions) {
+		options.FieldSelector = fields.OneTermEqualSelector("metadata.name", SharedDNSPortsConfigMap).String()
+	}))
+	secrets := secretFactory.Core().V1().Secrets()
+	configMaps := configMapFactory.Core().V1().ConfigMaps()
+
+	changed := make(chan struct{}, 1)
+	notify := func() {
+		select {
+		case changed <- struct{}{}:
+		default:
+		}
+	}
+	handler := cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(interface{}) { notify() },
+		UpdateFunc: func(interface{}, interface{}) { notify() },
+		DeleteFunc: func(interface{}) { notify() },
+	}
+	for _, informer := range []cache.SharedIndexInformer{secrets.Informer(), configMaps.Informer()} {
+		_, err := informer.AddEventHandler(handler)
+		if err != nil {
+			return err
+		}
+	}
+
+	secretFactory.Start(ctx.Done())
+	configMapFactory.Start(ctx.Done())
+	secretFactory.WaitForCacheSync(ctx.Done())
+	configMapFactory.WaitForCacheSync(ctx.Done())
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-changed:
+		}
+
+		tenantSecrets, err := secrets.Lister().Secrets(namespace).List(labels.Everything())
+		if err != nil {
+			return err
+		}
+		ports, err := configMaps.Lister().ConfigMaps(namespace).Get(SharedDNSPortsConfigMap)
+		if err != nil && !kerrors.IsNotFound(err) {
+			return err
+		}
+
+		err = writeSharedDNSTenants(dir, tenantSecrets, ports, log)
+		if err != nil {
+			log.Errorf("Error writing shared dns tenants: %v", err)
+		}
+	}
+}
+
+// writeSharedDNSTenants renders the Corefile from the tenant secrets. Tenants only provide settings and credentials,
+// the server blocks and kube configs are created here, so a tenant can neither inject Corefile directives nor take
+// the port of another tenant.
+func writeSharedDNSTenants(dir string, secrets []*corev1.Secret, ports *corev1.ConfigMap, log log.Logger) error {
+	slices.SortFunc(secrets, func(a, b *corev1.Secret) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	corefile := []string{sharedDNSBaseServerBlock}
+	kubeConfigs := map[string][]byte{}
+	for _, secret := range secrets {
+		tenant, port, kubeConfig, err := parseTenantSecret(secret, ports)
+		if err != nil {
+			log.Warnf("Skip shared dns tenant %s: %v", secret.Name, err)
+			continue
+		}
+
+		corefile = append(corefile, TenantServerBlock(tenant, port))
+		kubeConfigs[tenant.Key()+kubeConfigExtension] = kubeConfig
+	}
+
+	// kube configs are written before the Corefile references them
+	for name, kubeConfig := range kubeConfigs {
+		err := writeFileIfChanged(filepath.Join(dir, name), kubeConfig)
+		if err != nil {
+			return err
+		}
+	}
+	err := writeFileIfChanged(filepath.Join(dir, corefileName), []byte(strings.Join(corefile, "\n")))
+	if err != nil {
+		return err
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if strings.HasSuffix(entry.Name(), kubeConfigExtension) && kubeConfigs[entry.Name()] == nil {
+			err = os.Remove(filepath.Join(dir, entry.Name()))
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func parseTenantSecret(secret *corev1.Secret, ports *corev1.ConfigMap) (SharedDNSTenant, int, []byte, error) {
+	tenant := SharedDNSTenant{
+		Name:          string(secret.Data[tenantNameKey]),
+		Namespace:     string(secret.Data[tenantNamespaceKey]),
+		ClusterDomain: string(secret.Data[tenantClusterDomainKey]),
+	}
+	if tenant.Key() != secret.Name {
+		return tenant, 0, nil, fmt.Errorf("secret name doesn't match tenant %s/%s", tenant.Namespace, tenant.Name)
+	}
+	if tenant.ClusterDomain != "" && len(validation.IsDNS1123Subdomain(tenant.ClusterDomain)) > 0 {
+		return tenant, 0, nil, fmt.Errorf("invalid cluster domain %q", tenant.ClusterDomain)
+	}
+
+	// only the port that was allocated for the tenant is used
+	port, err := strconv.Atoi(string(secret.Data[tenantPortKey]))
+	if err != nil || ports == nil || ports.Data[secret.Name] != strconv.Itoa(port) || port < sharedDNSMinPort || port >= sharedDNSMaxPort {
+		return tenant, 0, nil, fmt.Errorf("port %q was not allocated for the tenant", string(secret.Data[tenantPortKey]))
+	}
+
+	tenant.CacheTTL, err = strconv.Atoi(string(secret.Data[tenantCacheTTLKey]))
+	if err != nil || tenant.CacheTTL < 0 {
+		return tenant, 0, nil, fmt.Errorf("invalid cache ttl %q", string(secret.Data[tenantCacheTTLKey]))
+	}
+	tenant.CacheSize, err = strconv.Atoi(string(secret.Data[tenantCacheSizeKey]))
+	if err != nil || tenant.CacheSize < 0 {
+		return tenant, 0, nil, fmt.Errorf("invalid cache size %q", string(secret.Data[tenantCacheSizeKey]))
+	}
+
+	server, err := url.Parse(string(secret.Data[tenantServerKey]))
+	if err != nil || server.Scheme != "https" || server.Host == "" {
+		return tenant, 0, nil, fmt.Errorf("invalid server %q", string(secret.Data[tenantServerKey]))
+	}
+	if len(secret.Data[tenantTokenKey]) == 0 {
+		return tenant, 0, nil, fmt.Errorf("token is missing")
+	}
+
+	// the kube config only contains the token of the tenant, so no exec or auth plugins of tenants are ever run
+	kubeConfig, err := clientcmd.Write(clientcmdapi.Config{
+		Clusters: map[string]*clientcmdapi.Cluster{
+			"vcluster": {Server: server.String(), CertificateAuthorityData: secret.Data[tenantCAKey]},
+		},
+		AuthInfos: map[string]*clientcmdapi.AuthInfo{
+			SharedDNSServiceAccount: {Token: string(secret.Data[tenantTokenKey])},
+		},
+		Contexts: map[string]*clientcmdapi.Context{
+			"vcluster": {Cluster: "vcluster", AuthInfo: SharedDNSServiceAccount},
+		},
+		CurrentContext: "vcluster",
+	})
+	if err != nil {
+		return tenant, 0, nil, err
+	}
+
+	return tenant, port, kubeConfig, nil
+}
+
+func writeFileIfChanged(path string, content []byte) error {
+	existing, err := os.ReadFile(path)
+	if err == nil && bytes.Equal(existing, content) {
+		return nil
+	}
+
+	// files are replaced atomically, so CoreDNS never reads a partially written file
+	tempFile := path + ".tmp"
+	err = os.WriteFile(tempFile, content, 0600)
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tempFile, path)
+}
+
+const sharedDNSBaseServerBlock = `.:1053 {
+    errors
+    health
+    ready
+    prometheus :9153
+    reload
+}
+`
+
+const sharedDNSManifestTemplate = `apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: {{ .NAME }}
+  namespace: {{ .NAMESPACE }}
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: Role
+metadata:
+  name: {{ .NAME }}
+  namespace: {{ .NAMESPACE }}
+rules:
+  - apiGroups: [""]
+    resources: ["secrets", "configmaps"]
+    verbs: ["get", "list", "watch"]
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: RoleBinding
+metadata:
+  name: {{ .NAME }}
+  namespace: {{ .NAMESPACE }}
+subjects:
+  - kind: ServiceAccount
+    name: {{ .NAME }}
+    namespace: {{ .NAMESPACE }}
+roleRef:
+  kind: Role
+  name: {{ .NAME }}
+  apiGroup: rbac.authorization.k8s.io
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: {{ .PORTS }}
+  namespace: {{ .NAMESPACE }}
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: {{ .NAME }}
+  namespace: {{ .NAMESPACE }}
+  labels:
+    k8s-app: {{ .APP }}
+spec:
+  replicas: {{ .REPLICAS }}
+  selector:
+    matchLabels:
+      k8s-app: {{ .APP }}
+  template:
+    metadata:
+      labels:
+        k8s-app: {{ .APP }}
+    spec:
+      serviceAccountName: {{ .NAME }}
+      nodeSelector:
+        kubernetes.io/os: linux
+      topologySpreadConstraints:
+        - maxSkew: 1
+          topologyKey: kubernetes.io/hostname
+          whenUnsatisfiable: ScheduleAnyway
+          labelSelector:
+            matchLabels:
+              k8s-app: {{ .APP }}
+      securityContext:
+        runAsNonRoot: true
+        runAsUser: {{ .RUN_AS_USER }}
+        runAsGroup: {{ .RUN_AS_GROUP }}
+        fsGroup: {{ .RUN_AS_GROUP }}
+      initContainers:
+        - name: tenants-init
+          image: {{ .CLI_IMAGE }}
+          command: ["vcluster"]
+          args: ["shared-dns", "start", "--namespace", "{{ .NAMESPACE }}", "--config-dir", "{{ .CONFIG_PATH }}", "--once"]
+          volumeMounts:
+            - name: config
+              mountPath: {{ .CONFIG_PATH }}
+          securityContext:
+            allowPrivilegeEscalation: false
+            capabilities:
+              drop:
+                - ALL
+            readOnlyRootFilesystem: true
+      containers:
+        - name: coredns
+          image: {{ .IMAGE }}
+          imagePullPolicy: IfNotPresent
+          args: [ "-conf", "{{ .CONFIG_PATH }}/Corefile" ]
+          volumeMounts:
+            - name: config
+              mountPath: {{ .CONFIG_PATH }}
+              readOnly: true
+          securityContext:
+            allowPrivilegeEscalation: false
+            capabilities:
+              drop:
+                - ALL
+            readOnlyRootFilesystem: true
+          livenessProbe:
+            httpGet:
+              path: /health
+              port: 8080
+              scheme: HTTP
+            initialDelaySeconds: 60
+            timeoutSeconds: 5
+            successThreshold: 1
+            failureThreshold: 5
+          readinessProbe:
+            httpGet:
+              path: /ready
+              port: 8181
+              scheme: HTTP
+        - name: tenants
+          image: {{ .CLI_IMAGE }}
+          command: ["vcluster"]
+          args: ["shared-dns", "start", "--namespace", "{{ .NAMESPACE }}", "--config-dir", "{{ .CONFIG_PATH }}"]
+          volumeMounts:
+            - name: config
+              mountPath: {{ .CONFIG_PATH }}
+          securityContext:
+            allowPrivilegeEscalation: false
+            capabilities:
+              drop:
+                - ALL
+            readOnlyRootFilesystem: true
+          resources:
+            requests:
+              cpu: 10m
+              memory: 32Mi
+            limits:
+              memory: 128Mi
+      dnsPolicy: Default
+      volumes:
+        - name: config
+          emptyDir: {}
+`
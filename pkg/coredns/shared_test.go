@@ -0,0 +1,160 @@
+package coredns
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/loft-sh/log"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/yaml"
+)
+
+func TestSharedDNSTenants(t *testing.T) {
+	ctx := context.Background()
+	hostClient := fake.NewClientBuilder().WithScheme(clientgoscheme.Scheme).Build()
+	tenantA := SharedDNSTenant{Name: "a", Namespace: "team-a", CacheTTL: 30, CacheSize: 1000}
+	tenantB := SharedDNSTenant{Name: "a", Namespace: "team-b", CacheTTL: 30, CacheSize: 1000}
+	credentials := SharedDNSCredentials{Server: "https://a.team-a:443", Token: []byte("token-a"), CA: []byte("ca")}
+
+	// the ports config map is installed with the shared CoreDNS
+	err := RegisterSharedDNSTenant(ctx, hostClient, "shared-dns", tenantA, credentials)
+	assert.ErrorContains(t, err, "vcluster shared-dns manifests")
+	assert.NilError(t, hostClient.Create(ctx, &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "shared-dns", Name: SharedDNSPortsConfigMap}}))
+
+	assert.NilError(t, RegisterSharedDNSTenant(ctx, hostClient, "shared-dns", tenantA, credentials))
+	assert.NilError(t, RegisterSharedDNSTenant(ctx, hostClient, "shared-dns", tenantB, SharedDNSCredentials{Server: "https://a.team-b:443", Token: []byte("token-b")}))
+
+	// every tenant has its own secret
+	secretA := &corev1.Secret{}
+	assert.NilError(t, hostClient.Get(ctx, types.NamespacedName{Namespace: "shared-dns", Name: tenantA.Key()}, secretA))
+	assert.Equal(t, string(secretA.Data[tenantTokenKey]), "token-a")
+	assert.Equal(t, secretA.Labels[SharedDNSTenantLabel], "true")
+
+	// the ports of the tenants differ and are kept when registering again
+	portA := tenantServicePort(t, hostClient, tenantA)
+	portB := tenantServicePort(t, hostClient, tenantB)
+	assert.Assert(t, portA != portB)
+	assert.NilError(t, RegisterSharedDNSTenant(ctx, hostClient, "shared-dns", tenantA, credentials))
+	assert.Equal(t, tenantServicePort(t, hostClient, tenantA), portA)
+	assert.DeepEqual(t, allocatedPorts(t, hostClient), map[string]string{tenantA.Key(): strconv.Itoa(portA), tenantB.Key(): strconv.Itoa(portB)})
+
+	assert.NilError(t, UnregisterSharedDNSTenant(ctx, hostClient, "shared-dns", tenantA))
+	err = hostClient.Get(ctx, types.NamespacedName{Namespace: "shared-dns", Name: tenantA.Key()}, &corev1.Secret{})
+	assert.Assert(t, kerrors.IsNotFound(err))
+	err = hostClient.Get(ctx, types.NamespacedName{Namespace: "shared-dns", Name: tenantA.Key()}, &corev1.Service{})
+	assert.Assert(t, kerrors.IsNotFound(err))
+	assert.DeepEqual(t, allocatedPorts(t, hostClient), map[string]string{tenantB.Key(): strconv.Itoa(portB)})
+
+	// unregistering twice is fine
+	assert.NilError(t, UnregisterSharedDNSTenant(ctx, hostClient, "shared-dns", tenantA))
+}
+
+func TestAllocatePortConflict(t *testing.T) {
+	ctx := context.Background()
+	hostClient := fake.NewClientBuilder().WithScheme(clientgoscheme.Scheme).WithObjects(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "shared-dns", Name: SharedDNSPortsConfigMap}}).Build()
+	tenantA := SharedDNSTenant{Name: "a", Namespace: "team-a"}
+	tenantB := SharedDNSTenant{Name: "b", Namespace: "team-b"}
+
+	// another tenant allocated the preferred port after the config map was read, so the update is rejected and the
+	// next free port is chosen
+	stale := &corev1.ConfigMap{}
+	assert.NilError(t, hostClient.Get(ctx, types.NamespacedName{Namespace: "shared-dns", Name: SharedDNSPortsConfigMap}, stale))
+	current := stale.DeepCopy()
+	current.Data = map[string]string{tenantB.Key(): strconv.Itoa(tenantA.PreferredPort())}
+	assert.NilError(t, hostClient.Update(ctx, current))
+	stale.Data = map[string]string{tenantA.Key(): strconv.Itoa(tenantA.PreferredPort())}
+	assert.Assert(t, kerrors.IsConflict(hostClient.Update(ctx, stale)))
+
+	port, err := allocatePort(ctx, hostClient, "shared-dns", tenantA)
+	assert.NilError(t, err)
+	assert.Equal(t, port, tenantA.PreferredPort()+1)
+}
+
+func TestWriteSharedDNSTenants(t *testing.T) {
+	dir := t.TempDir()
+	tenantA := SharedDNSTenant{Name: "a", Namespace: "team-a", ClusterDomain: "cluster.local", CacheTTL: 30, CacheSize: 1000}
+	tenantB := SharedDNSTenant{Name: "b", Namespace: "team-b", CacheTTL: 30, CacheSize: 1000}
+	ports := &corev1.ConfigMap{Data: map[string]string{tenantA.Key(): "10001", tenantB.Key(): "10002"}}
+	secretA := tenantSecret(tenantA, 10001)
+	secretB := tenantSecret(tenantB, 10002)
+
+	// a tenant that claims a port that was not allocated for it is skipped
+	forged := tenantSecret(SharedDNSTenant{Name: "c", Namespace: "team-c"}, 10001)
+	assert.NilError(t, os.WriteFile(filepath.Join(dir, "stale"+kubeConfigExtension), []byte("stale"), 0600))
+	assert.NilError(t, writeSharedDNSTenants(dir, []*corev1.Secret{secretB, forged, secretA}, ports, log.Discard))
+
+	corefile, err := os.ReadFile(filepath.Join(dir, corefileName))
+	assert.NilError(t, err)
+	assert.Equal(t, string(corefile), strings.Join([]string{sharedDNSBaseServerBlock, TenantServerBlock(tenantA, 10001), TenantServerBlock(tenantB, 10002)}, "\n"))
+
+	kubeConfig, err := clientcmd.LoadFromFile(filepath.Join(dir, tenantA.Key()+kubeConfigExtension))
+	assert.NilError(t, err)
+	assert.Equal(t, kubeConfig.Clusters["vcluster"].Server, "https://a.team-a:443")
+	assert.Equal(t, kubeConfig.AuthInfos[SharedDNSServiceAccount].Token, "token")
+	assert.Assert(t, kubeConfig.AuthInfos[SharedDNSServiceAccount].Exec == nil)
+	_, err = os.Stat(filepath.Join(dir, "stale"+kubeConfigExtension))
+	assert.Assert(t, os.IsNotExist(err))
+
+	// removed tenants are removed from the Corefile
+	assert.NilError(t, writeSharedDNSTenants(dir, []*corev1.Secret{secretB}, ports, log.Discard))
+	corefile, err = os.ReadFile(filepath.Join(dir, corefileName))
+	assert.NilError(t, err)
+	assert.Assert(t, !strings.Contains(string(corefile), tenantA.Key()))
+	_, err = os.Stat(filepath.Join(dir, tenantA.Key()+kubeConfigExtension))
+	assert.Assert(t, os.IsNotExist(err))
+}
+
+func TestSharedDNSManifests(t *testing.T) {
+	manifests, err := SharedDNSManifests(&SharedDNSManifestOptions{Namespace: "shared-dns", CLIImage: "ghcr.io/loft-sh/vcluster-cli:0.21.0", Replicas: 2})
+	assert.NilError(t, err)
+
+	kinds := []string{}
+	for _, document := range strings.Split(string(manifests), "\n---\n") {
+		obj := &unstructured.Unstructured{}
+		assert.NilError(t, yaml.Unmarshal([]byte(document), &obj.Object))
+		assert.Equal(t, obj.GetNamespace(), "shared-dns")
+		kinds = append(kinds, obj.GetKind())
+	}
+	assert.DeepEqual(t, kinds, []string{"ServiceAccount", "Role", "RoleBinding", "ConfigMap", "Deployment"})
+}
+
+func tenantSecret(tenant SharedDNSTenant, port int) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: tenant.Key()},
+		Data: map[string][]byte{
+			tenantNameKey:          []byte(tenant.Name),
+			tenantNamespaceKey:     []byte(tenant.Namespace),
+			tenantClusterDomainKey: []byte(tenant.ClusterDomain),
+			tenantPortKey:          []byte(strconv.Itoa(port)),
+			tenantCacheTTLKey:      []byte(strconv.Itoa(tenant.CacheTTL)),
+			tenantCacheSizeKey:     []byte(strconv.Itoa(tenant.CacheSize)),
+			tenantServerKey:        []byte("https://" + tenant.Name + "." + tenant.Namespace + ":443"),
+			tenantTokenKey:         []byte("token"),
+		},
+	}
+}
+
+func tenantServicePort(t *testing.T, hostClient client.Client, tenant SharedDNSTenant) int {
+	service := &corev1.Service{}
+	assert.NilError(t, hostClient.Get(context.Background(), types.NamespacedName{Namespace: "shared-dns", Name: tenant.Key()}, service))
+	return service.Spec.Ports[0].TargetPort.IntValue()
+}
+
+func allocatedPorts(t *testing.T, hostClient client.Client) map[string]string {
+	ports := &corev1.ConfigMap{}
+	assert.NilError(t, hostClient.Get(context.Background(), types.NamespacedName{Namespace: "shared-dns", Name: SharedDNSPortsConfigMap}, ports))
+	return ports.Data
+}
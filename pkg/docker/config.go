@@ -21,6 +21,9 @@ type Config interface {
 
 	// Save persists the locally changed config file to file
 	Save() error
+
+	// AuthConfig returns the credentials stored for the given registry
+	AuthConfig(registry string) (types.AuthConfig, error)
 }
 
 // NewDockerConfig creates a new docker client
@@ -52,6 +55,15 @@ func (c *config) Store(registry string, authConfig types.AuthConfig) error {
 	return nil
 }
 
+func (c *config) AuthConfig(registry string) (types.AuthConfig, error) {
+	authConfig, err := c.DockerConfig.GetAuthConfig(registry)
+	if err != nil {
+		return types.AuthConfig{}, errors.Wrapf(err, "get credentials for registry %s", registry)
+	}
+
+	return authConfig, nil
+}
+
 func (c *config) Save() error {
 	return c.DockerConfig.Save()
 }
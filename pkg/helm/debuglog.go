@@ -0,0 +1,150 @@
+package helm
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"sigs.k8s.io/yaml"
+)
+
+const redacted = "REDACTED"
+
+var arrayIndexRegEx = regexp.MustCompile(`\[\d+\]`)
+
+// DebugLog records the helm command lines, values and output of a run into a file, so failed deployments can be
+// reproduced and shared. Values at the sensitive paths as well as registry passwords are redacted.
+type DebugLog struct {
+	// Path of the debug log file
+	Path string
+
+	sensitivePaths []string
+	m              sync.Mutex
+}
+
+// NewDebugLog creates a new debug log file within the temp dir. Sensitive paths are dotted value paths such as
+// controlPlane.distro.k3s.token, items of arrays are referenced as path[].
+func NewDebugLog(sensitivePaths []string) (*DebugLog, error) {
+	file, err := os.CreateTemp("", "vcluster-helm-debug-*.log")
+	if err != nil {
+		return nil, fmt.Errorf("create helm debug log: %w", err)
+	}
+	defer file.Close()
+
+	return &DebugLog{
+		Path:           file.Name(),
+		sensitivePaths: sensitivePaths,
+	}, nil
+}
+
+func (d *DebugLog) command(args []string) {
+	d.write("$ helm %s\n", strings.Join(d.redactArgs(args), " "))
+}
+
+func (d *DebugLog) values(source string, values []byte) {
+	d.write("--- values %s\n%s\n", source, d.redactValues(values))
+}
+
+func (d *DebugLog) output(output []byte) {
+	d.write("--- output\n%s\n", output)
+}
+
+// wrap references the debug log in the error
+func (d *DebugLog) wrap(err error) error {
+	if d == nil || err == nil {
+		return err
+	}
+
+	return fmt.Errorf("%w (helm debug log: %s)", err, d.Path)
+}
+
+func (d *DebugLog) write(format string, args ...interface{}) {
+	if d == nil {
+		return
+	}
+
+	d.m.Lock()
+	defer d.m.Unlock()
+
+	file, err := os.OpenFile(d.Path, os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+
+	_, _ = fmt.Fprintf(file, "[%s] "+format, append([]interface{}{time.Now().Format(time.RFC3339)}, args...)...)
+}
+
+// redactArgs redacts passwords and --set values at sensitive paths
+func (d *DebugLog) redactArgs(args []string) []string {
+	out := slices.Clone(args)
+	for i := 0; i < len(out)-1; i++ {
+		switch out[i] {
+		case "--password":
+			out[i+1] = redacted
+		case "--set", "--set-string":
+			pairs := strings.Split(out[i+1], ",")
+			for j, pair := range pairs {
+				key, _, found := strings.Cut(pair, "=")
+				if found && d.isSensitive(arrayIndexRegEx.ReplaceAllString(key, "[]")) {
+					pairs[j] = key + "=" + redacted
+				}
+			}
+			out[i+1] = strings.Join(pairs, ",")
+		}
+	}
+
+	return out
+}
+
+// redactValues replaces the values at sensitive paths of the yaml
+func (d *DebugLog) redactValues(values []byte) string {
+	var parsed interface{}
+	err := yaml.Unmarshal(values, &parsed)
+	if err != nil {
+		return "# values are not valid yaml and were omitted"
+	}
+
+	parsed = d.redactValue(parsed, "")
+	out, err := yaml.Marshal(parsed)
+	if err != nil {
+		return "# values couldn't be encoded and were omitted"
+	}
+
+	return string(out)
+}
+
+func (d *DebugLog) redactValue(value interface{}, path string) interface{} {
+	if path != "" && d.isSensitive(path) {
+		return redacted
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			itemPath := key
+			if path != "" {
+				itemPath = path + "." + key
+			}
+			v[key] = d.redactValue(item, itemPath)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = d.redactValue(item, path+"[]")
+		}
+	}
+
+	return value
+}
+
+func (d *DebugLog) isSensitive(path string) bool {
+	if d == nil {
+		return false
+	}
+
+	return slices.Contains(d.sensitivePaths, path)
+}
@@ -0,0 +1,47 @@
+package helm
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestDebugLog(t *testing.T) {
+	debugLog, err := NewDebugLog([]string{"controlPlane.distro.k3s.token", "experimental.deploy.vcluster.helm[].chart.password"})
+	assert.NilError(t, err)
+	defer os.Remove(debugLog.Path)
+
+	debugLog.command([]string{"registry", "login", "--password", "my-password", "--set", "controlPlane.distro.k3s.token=my-token,sync.toHost.pods.enabled=true"})
+	debugLog.values("vcluster.yaml", []byte(`controlPlane:
+  distro:
+    k3s:
+      enabled: true
+      token: my-token
+experimental:
+  deploy:
+    vcluster:
+      helm:
+      - chart:
+          name: my-chart
+          password: my-password
+`))
+	debugLog.output([]byte("Error: INSTALLATION FAILED"))
+
+	raw, err := os.ReadFile(debugLog.Path)
+	assert.NilError(t, err)
+	content := string(raw)
+	assert.Assert(t, !strings.Contains(content, "my-token") && !strings.Contains(content, "my-password"), content)
+	assert.Assert(t, strings.Contains(content, "--set controlPlane.distro.k3s.token=REDACTED,sync.toHost.pods.enabled=true"), content)
+	assert.Assert(t, strings.Contains(content, "name: my-chart"), content)
+	assert.Assert(t, strings.Contains(content, "INSTALLATION FAILED"), content)
+
+	assert.ErrorContains(t, debugLog.wrap(errors.New("error executing helm")), "helm debug log: "+debugLog.Path)
+	assert.NilError(t, debugLog.wrap(nil))
+
+	// without a debug log only passwords are redacted
+	var noDebugLog *DebugLog
+	assert.DeepEqual(t, noDebugLog.redactArgs([]string{"--password", "my-password", "--set", "a=b"}), []string{"--password", "REDACTED", "--set", "a=b"})
+}
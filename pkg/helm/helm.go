@@ -6,6 +6,7 @@ import (
 	"net/url"
 	"os"
 	"os/exec"
+	"strconv"
 	"strings"
 	"time"
 
@@ -29,9 +30,17 @@ type UpgradeOptions struct {
 
 	CreateNamespace bool
 
+	// KubeVersion is the kubernetes version the chart is rendered for by Template
+	KubeVersion string
+
+	// Username and Password authenticate against the chart repository or oci registry
 	Username string
 	Password string
-	WorkDir  string
+
+	// CaFile verifies the certificate of the chart repository
+	CaFile string
+
+	WorkDir string
 
 	Insecure bool
 	Atomic   bool
@@ -51,14 +60,16 @@ type Client interface {
 	Pull(ctx context.Context, name string, options UpgradeOptions) error
 	Delete(name, namespace string) error
 	Exists(name, namespace string) (bool, error)
-	Rollback(ctx context.Context, name, namespace string) error
+	Rollback(ctx context.Context, name, namespace string, revision int) error
 	Status(ctx context.Context, name, namespace string) ([]byte, error)
+	Template(ctx context.Context, name, namespace string, options UpgradeOptions) ([]byte, error)
 }
 
 type client struct {
 	config   *clientcmdapi.Config
 	log      log.Logger
 	helmPath string
+	debugLog *DebugLog
 }
 
 // NewClient creates a new helm client from the given config
@@ -70,6 +81,16 @@ func NewClient(config *clientcmdapi.Config, log log.Logger, commandPath string)
 	}
 }
 
+// NewDebugClient creates a new helm client that records all helm invocations into the debug log
+func NewDebugClient(config *clientcmdapi.Config, log log.Logger, commandPath string, debugLog *DebugLog) Client {
+	return &client{
+		config:   config,
+		log:      log,
+		helmPath: commandPath,
+		debugLog: debugLog,
+	}
+}
+
 func (c *client) Install(ctx context.Context, name, namespace string, options UpgradeOptions) error {
 	return c.run(ctx, name, namespace, options, "install", []string{"--repository-config=''"})
 }
@@ -82,8 +103,14 @@ func (c *client) Pull(ctx context.Context, name string, options UpgradeOptions)
 	return c.pull(ctx, name, options)
 }
 
-func (c *client) Rollback(ctx context.Context, name, namespace string) error {
-	return c.run(ctx, name, namespace, UpgradeOptions{}, "rollback", []string{})
+// Rollback rolls the release back to the given revision or to the previous one if revision is 0
+func (c *client) Rollback(ctx context.Context, name, namespace string, revision int) error {
+	extraArgs := []string{}
+	if revision > 0 {
+		extraArgs = append(extraArgs, strconv.Itoa(revision))
+	}
+
+	return c.run(ctx, name, namespace, UpgradeOptions{}, "rollback", extraArgs)
 }
 
 func (c *client) run(ctx context.Context, name, namespace string, options UpgradeOptions, command string, extraArgs []string) error {
@@ -94,20 +121,7 @@ func (c *client) run(ctx context.Context, name, namespace string, options Upgrad
 	defer os.Remove(kubeConfig)
 
 	args := []string{command, name}
-	if options.Path != "" {
-		args = append(args, options.Path)
-	} else {
-		if options.Chart != "" {
-			args = append(args, options.Chart)
-		}
-		if options.Repo != "" {
-			args = append(args, "--repo", options.Repo)
-		}
-		if options.Version != "" {
-			args = append(args, "--version", options.Version)
-		}
-	}
-
+	args = append(args, chartArgs(options)...)
 	if options.CreateNamespace {
 		args = append(args, "--create-namespace")
 	}
@@ -141,12 +155,18 @@ func (c *client) run(ctx context.Context, name, namespace string, options Upgrad
 		time.Sleep(time.Millisecond)
 
 		args = append(args, "--values", tempFile.Name())
+		c.debugLog.values(tempFile.Name(), []byte(options.Values))
 	}
 
 	// Values files
 	if len(options.ValuesFiles) > 0 {
 		for _, file := range options.ValuesFiles {
 			args = append(args, "--values", file)
+			if c.debugLog != nil {
+				if values, err := os.ReadFile(file); err == nil {
+					c.debugLog.values(file, values)
+				}
+			}
 		}
 	}
 
@@ -174,6 +194,38 @@ func (c *client) run(ctx context.Context, name, namespace string, options Upgrad
 	return c.execute(ctx, args, command, options.WorkDir)
 }
 
+// chartArgs returns the arguments that select the chart, either a local path or a chart of a repository together with
+// the credentials of the repository
+func chartArgs(options UpgradeOptions) []string {
+	if options.Path != "" {
+		return []string{options.Path}
+	}
+
+	args := []string{}
+	if strings.HasPrefix(options.Repo, "oci://") {
+		// helm doesn't support --repo for oci registries, the chart is referenced by its full url instead
+		args = append(args, strings.TrimSuffix(options.Repo, "/")+"/"+options.Chart)
+	} else {
+		if options.Chart != "" {
+			args = append(args, options.Chart)
+		}
+		if options.Repo != "" {
+			args = append(args, "--repo", options.Repo)
+		}
+	}
+	if options.Version != "" {
+		args = append(args, "--version", options.Version)
+	}
+	if options.Username != "" {
+		args = append(args, "--username", options.Username, "--password", options.Password)
+	}
+	if options.CaFile != "" {
+		args = append(args, "--ca-file", options.CaFile)
+	}
+
+	return args
+}
+
 func (c *client) pull(ctx context.Context, name string, options UpgradeOptions) error {
 	kubeConfig, err := WriteKubeConfig(c.config)
 	if err != nil {
@@ -208,6 +260,9 @@ func (c *client) pull(ctx context.Context, name string, options UpgradeOptions)
 		args = append(args, "--version", options.Version)
 	}
 
+	if options.CaFile != "" {
+		args = append(args, "--ca-file", options.CaFile)
+	}
 	if options.Insecure {
 		args = append(args, "--insecure-skip-tls-verify")
 	}
@@ -242,7 +297,9 @@ func (c *client) logout(ctx context.Context, options UpgradeOptions) {
 }
 
 func (c *client) execute(ctx context.Context, args []string, operation string, workdir string) error {
-	c.log.Info("execute command: helm " + strings.Join(args, " "))
+	redactedArgs := strings.Join(c.debugLog.redactArgs(args), " ")
+	c.log.Info("execute command: helm " + redactedArgs)
+	c.debugLog.command(args)
 	cmd := exec.CommandContext(ctx, c.helmPath, args...)
 
 	if workdir != "" {
@@ -250,12 +307,13 @@ func (c *client) execute(ctx context.Context, args []string, operation string, w
 	}
 
 	output, err := cmd.CombinedOutput()
+	c.debugLog.output(output)
 
 	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
-		return fmt.Errorf(errorTimeout, string(output), operation)
+		return c.debugLog.wrap(fmt.Errorf(errorTimeout, string(output), operation))
 	}
 	if err != nil {
-		return fmt.Errorf(errorExecutingHelm, strings.Join(args, " "), string(output))
+		return c.debugLog.wrap(fmt.Errorf(errorExecutingHelm, redactedArgs, string(output)))
 	}
 	return nil
 }
@@ -270,13 +328,15 @@ func (c *client) Delete(name, namespace string) error {
 	args := []string{"delete", name, "--namespace", namespace, "--kubeconfig", kubeConfig, "--repository-config=''"}
 
 	c.log.Debug("Delete helm chart with helm " + strings.Join(args, " "))
+	c.debugLog.command(args)
 	output, err := exec.Command(c.helmPath, args...).CombinedOutput()
+	c.debugLog.output(output)
 	if err != nil {
 		if strings.Contains(string(output), "release: not found") {
 			return fmt.Errorf("release '%s' was not found in namespace '%s'", name, namespace)
 		}
 
-		return fmt.Errorf("error executing helm delete: %s", string(output))
+		return c.debugLog.wrap(fmt.Errorf("error executing helm delete: %s", string(output)))
 	}
 
 	return nil
@@ -313,6 +373,37 @@ func (c *client) Status(ctx context.Context, name, namespace string) ([]byte, er
 	return exec.CommandContext(ctx, c.helmPath, args...).CombinedOutput()
 }
 
+// Template renders the chart locally without accessing the cluster and returns the manifests
+func (c *client) Template(ctx context.Context, name, namespace string, options UpgradeOptions) ([]byte, error) {
+	args := []string{"template", name}
+	args = append(args, chartArgs(options)...)
+	args = append(args, "--namespace", namespace)
+	if options.KubeVersion != "" {
+		args = append(args, "--kube-version", options.KubeVersion)
+	}
+	if options.Insecure {
+		args = append(args, "--insecure-skip-tls-verify")
+	}
+	for _, file := range options.ValuesFiles {
+		args = append(args, "--values", file)
+	}
+	for _, value := range options.SetValues {
+		args = append(args, "--set", value)
+	}
+
+	c.log.Debug("Render helm chart with helm " + strings.Join(c.debugLog.redactArgs(args), " "))
+	c.debugLog.command(args)
+	stderr := &strings.Builder{}
+	cmd := exec.CommandContext(ctx, c.helmPath, args...)
+	cmd.Stderr = stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, c.debugLog.wrap(fmt.Errorf("error executing helm template: %s", stderr.String()))
+	}
+
+	return output, nil
+}
+
 // WriteKubeConfig writes the kubeconfig to a file and returns the filename
 func WriteKubeConfig(configRaw *clientcmdapi.Config) (string, error) {
 	data, err := clientcmd.Write(*configRaw)
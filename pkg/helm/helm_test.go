@@ -0,0 +1,20 @@
+package helm
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestChartArgs(t *testing.T) {
+	assert.DeepEqual(t, chartArgs(UpgradeOptions{Path: "./chart", Repo: "https://charts.example.com", Username: "user", Password: "secret"}), []string{"./chart"})
+	assert.DeepEqual(t, chartArgs(UpgradeOptions{Chart: "vcluster", Repo: "https://charts.example.com", Version: "0.20.0"}), []string{
+		"vcluster", "--repo", "https://charts.example.com", "--version", "0.20.0",
+	})
+	assert.DeepEqual(t, chartArgs(UpgradeOptions{Chart: "vcluster", Repo: "https://charts.example.com", Username: "user", Password: "secret", CaFile: "/tmp/ca.crt"}), []string{
+		"vcluster", "--repo", "https://charts.example.com", "--username", "user", "--password", "secret", "--ca-file", "/tmp/ca.crt",
+	})
+	assert.DeepEqual(t, chartArgs(UpgradeOptions{Chart: "vcluster", Repo: "oci://ghcr.io/loft-sh/charts/", Version: "0.20.0"}), []string{
+		"oci://ghcr.io/loft-sh/charts/vcluster", "--version", "0.20.0",
+	})
+}
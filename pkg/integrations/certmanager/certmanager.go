@@ -0,0 +1,127 @@
+package certmanager
+
+import (
+	"fmt"
+	"strings"
+
+	vclusterconfig "github.com/loft-sh/vcluster/config"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	APIVersion = "cert-manager.io/v1"
+
+	IssuerKind        = "Issuer"
+	ClusterIssuerKind = "ClusterIssuer"
+
+	// CertificateNameAnnotation is set by cert-manager on the secrets it issues
+	CertificateNameAnnotation = "cert-manager.io/certificate-name"
+
+	// ControllerName marks the virtual secrets the integration manages, so the secrets syncer leaves them alone
+	ControllerName = "cert-manager"
+)
+
+// Exports returns the generic sync exports of the cert-manager resources that are synced to the host cluster
+func Exports(certManager vclusterconfig.CertManager) []*vclusterconfig.Export {
+	if !certManager.Enabled {
+		return nil
+	}
+
+	exports := []*vclusterconfig.Export{}
+	if certManager.Sync.ToHost.Certificates.Enabled {
+		exports = append(exports, newExport("Certificate", []*vclusterconfig.Patch{
+			rewriteName("spec.secretName"),
+			// ClusterIssuers are mirrored from the host cluster and keep their names
+			{
+				Operation: vclusterconfig.PatchTypeRewriteName,
+				Path:      "spec.issuerRef.name",
+				Conditions: []*vclusterconfig.PatchCondition{
+					{
+						Path:     "spec.issuerRef.kind",
+						NotEqual: ClusterIssuerKind,
+					},
+				},
+			},
+		}))
+	}
+	if certManager.Sync.ToHost.Issuers.Enabled {
+		exports = append(exports, newExport(IssuerKind, []*vclusterconfig.Patch{
+			rewriteName("spec.ca.secretName"),
+			rewriteName("spec.acme.privateKeySecretRef.name"),
+			rewriteName("spec.acme.externalAccountBinding.keySecretRef.name"),
+			rewriteName("spec.acme.solvers[*].dns01.acmeDNS.accountSecretRef.name"),
+			rewriteName("spec.acme.solvers[*].dns01.akamai.clientTokenSecretRef.name"),
+			rewriteName("spec.acme.solvers[*].dns01.akamai.clientSecretSecretRef.name"),
+			rewriteName("spec.acme.solvers[*].dns01.akamai.accessTokenSecretRef.name"),
+			rewriteName("spec.acme.solvers[*].dns01.azureDNS.clientSecretSecretRef.name"),
+			rewriteName("spec.acme.solvers[*].dns01.cloudDNS.serviceAccountSecretRef.name"),
+			rewriteName("spec.acme.solvers[*].dns01.cloudflare.apiKeySecretRef.name"),
+			rewriteName("spec.acme.solvers[*].dns01.cloudflare.apiTokenSecretRef.name"),
+			rewriteName("spec.acme.solvers[*].dns01.digitalocean.tokenSecretRef.name"),
+			rewriteName("spec.acme.solvers[*].dns01.rfc2136.tsigSecretSecretRef.name"),
+			rewriteName("spec.acme.solvers[*].dns01.route53.accessKeyIDSecretRef.name"),
+			rewriteName("spec.acme.solvers[*].dns01.route53.secretAccessKeySecretRef.name"),
+			rewriteName("spec.vault.caBundleSecretRef.name"),
+			rewriteName("spec.vault.clientCertSecretRef.name"),
+			rewriteName("spec.vault.clientKeySecretRef.name"),
+			rewriteName("spec.vault.auth.tokenSecretRef.name"),
+			rewriteName("spec.vault.auth.appRole.secretRef.name"),
+			rewriteName("spec.vault.auth.clientCertificate.secretName"),
+			rewriteName("spec.vault.auth.kubernetes.secretRef.name"),
+			rewriteName("spec.vault.auth.kubernetes.serviceAccountRef.name"),
+			rewriteName("spec.venafi.tpp.credentialsRef.name"),
+			rewriteName("spec.venafi.tpp.caBundleSecretRef.name"),
+			rewriteName("spec.venafi.cloud.apiTokenSecretRef.name"),
+		}))
+	}
+
+	return exports
+}
+
+// IsExported returns true if the integration already syncs the given kind
+func IsExported(certManager vclusterconfig.CertManager, apiVersion, kind string) bool {
+	for _, export := range Exports(certManager) {
+		if strings.SplitN(export.APIVersion, "/", 2)[0] == strings.SplitN(apiVersion, "/", 2)[0] && export.Kind == kind {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Validate returns an error if the Issuer uses a solver whose references can't be rewritten to the host cluster. The
+// config of dns01 webhook solvers is specific to the webhook, so it might reference any secret of the host cluster.
+func Validate(issuer *unstructured.Unstructured) error {
+	solvers, _, _ := unstructured.NestedSlice(issuer.Object, "spec", "acme", "solvers")
+	for idx, solver := range solvers {
+		solverMap, ok := solver.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		if _, ok, _ := unstructured.NestedMap(solverMap, "dns01", "webhook"); ok {
+			return fmt.Errorf("spec.acme.solvers[%d].dns01.webhook is not supported, use a ClusterIssuer of the host cluster instead", idx)
+		}
+	}
+
+	return nil
+}
+
+func newExport(kind string, patches []*vclusterconfig.Patch) *vclusterconfig.Export {
+	return &vclusterconfig.Export{
+		SyncBase: vclusterconfig.SyncBase{
+			TypeInformation: vclusterconfig.TypeInformation{
+				APIVersion: APIVersion,
+				Kind:       kind,
+			},
+			Patches: patches,
+		},
+	}
+}
+
+func rewriteName(path string) *vclusterconfig.Patch {
+	return &vclusterconfig.Patch{
+		Operation: vclusterconfig.PatchTypeRewriteName,
+		Path:      path,
+	}
+}
@@ -0,0 +1,144 @@
+package certmanager
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	vclusterconfig "github.com/loft-sh/vcluster/config"
+	"github.com/loft-sh/vcluster/pkg/patches"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	"gotest.tools/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestExports(t *testing.T) {
+	translate.Default = translate.NewSingleNamespaceTranslator("vcluster")
+	certManager := vclusterconfig.CertManager{Enabled: true}
+	certManager.Sync.ToHost.Certificates.Enabled = true
+
+	exports := Exports(certManager)
+	assert.Equal(t, len(exports), 1)
+	assert.Assert(t, IsExported(certManager, APIVersion, "Certificate"))
+	assert.Assert(t, !IsExported(certManager, APIVersion, "Issuer"))
+
+	certificate := newCertificate("Issuer")
+	applyExport(t, exports[0], certificate)
+	secretName, _, _ := unstructured.NestedString(certificate.Object, "spec", "secretName")
+	assert.Equal(t, secretName, translate.Default.PhysicalName("web-tls", "test"))
+	issuerName, _, _ := unstructured.NestedString(certificate.Object, "spec", "issuerRef", "name")
+	assert.Equal(t, issuerName, translate.Default.PhysicalName("letsencrypt", "test"))
+
+	// ClusterIssuers are mirrored from the host cluster
+	certificate = newCertificate("ClusterIssuer")
+	applyExport(t, exports[0], certificate)
+	issuerName, _, _ = unstructured.NestedString(certificate.Object, "spec", "issuerRef", "name")
+	assert.Equal(t, issuerName, "letsencrypt")
+
+	certManager.Enabled = false
+	assert.Equal(t, len(Exports(certManager)), 0)
+}
+
+func TestIssuerExport(t *testing.T) {
+	translate.Default = translate.NewSingleNamespaceTranslator("vcluster")
+	certManager := vclusterconfig.CertManager{Enabled: true}
+	certManager.Sync.ToHost.Issuers.Enabled = true
+
+	exports := Exports(certManager)
+	assert.Equal(t, len(exports), 1)
+	assert.Assert(t, IsExported(certManager, APIVersion, IssuerKind))
+
+	issuer := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": APIVersion,
+		"kind":       IssuerKind,
+		"metadata":   map[string]interface{}{"name": "letsencrypt", "namespace": "test"},
+		"spec": map[string]interface{}{
+			"acme": map[string]interface{}{
+				"privateKeySecretRef": map[string]interface{}{"name": "account-key"},
+				"solvers": []interface{}{
+					map[string]interface{}{"dns01": map[string]interface{}{
+						"cloudflare": map[string]interface{}{"apiTokenSecretRef": map[string]interface{}{"name": "cloudflare", "key": "token"}},
+					}},
+				},
+			},
+		},
+	}}
+	applyExport(t, exports[0], issuer)
+	solvers, _, _ := unstructured.NestedSlice(issuer.Object, "spec", "acme", "solvers")
+	tokenSecret, _, _ := unstructured.NestedString(solvers[0].(map[string]interface{}), "dns01", "cloudflare", "apiTokenSecretRef", "name")
+	assert.Equal(t, tokenSecret, translate.Default.PhysicalName("cloudflare", "test"))
+	assert.NilError(t, Validate(issuer))
+
+	vault := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": APIVersion,
+		"kind":       IssuerKind,
+		"metadata":   map[string]interface{}{"name": "vault", "namespace": "test"},
+		"spec": map[string]interface{}{
+			"vault": map[string]interface{}{
+				"caBundleSecretRef": map[string]interface{}{"name": "vault-ca"},
+				"auth": map[string]interface{}{
+					"kubernetes": map[string]interface{}{"secretRef": map[string]interface{}{"name": "vault-token"}},
+				},
+			},
+		},
+	}}
+	applyExport(t, exports[0], vault)
+	caBundle, _, _ := unstructured.NestedString(vault.Object, "spec", "vault", "caBundleSecretRef", "name")
+	assert.Equal(t, caBundle, translate.Default.PhysicalName("vault-ca", "test"))
+	token, _, _ := unstructured.NestedString(vault.Object, "spec", "vault", "auth", "kubernetes", "secretRef", "name")
+	assert.Equal(t, token, translate.Default.PhysicalName("vault-token", "test"))
+
+	// webhook solvers might reference any secret of the host cluster
+	solvers = append(solvers, map[string]interface{}{"dns01": map[string]interface{}{
+		"webhook": map[string]interface{}{"groupName": "acme.example.com", "solverName": "example"},
+	}})
+	assert.NilError(t, unstructured.SetNestedSlice(issuer.Object, solvers, "spec", "acme", "solvers"))
+	assert.Error(t, Validate(issuer), "spec.acme.solvers[1].dns01.webhook is not supported, use a ClusterIssuer of the host cluster instead")
+}
+
+func newCertificate(issuerKind string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": APIVersion,
+		"kind":       "Certificate",
+		"metadata":   map[string]interface{}{"name": "web", "namespace": "test"},
+		"spec": map[string]interface{}{
+			"secretName": "web-tls",
+			"dnsNames":   []interface{}{"web.example.com"},
+			"issuerRef":  map[string]interface{}{"name": "letsencrypt", "kind": issuerKind},
+		},
+	}}
+}
+
+func applyExport(t *testing.T, export *vclusterconfig.Export, obj *unstructured.Unstructured) {
+	err := patches.ApplyPatches(obj, nil, export.Patches, nil, &testNameResolver{namespace: obj.GetNamespace()})
+	assert.NilError(t, err)
+}
+
+type testNameResolver struct {
+	namespace string
+}
+
+func (r *testNameResolver) TranslateName(name string, regex *regexp.Regexp, _ string) (string, error) {
+	return r.TranslateNameWithNamespace(name, r.namespace, regex, "")
+}
+
+func (r *testNameResolver) TranslateNameWithNamespace(name string, namespace string, _ *regexp.Regexp, _ string) (string, error) {
+	return translate.Default.PhysicalName(name, namespace), nil
+}
+
+func (r *testNameResolver) TranslateLabelKey(string) (string, error) {
+	return "", fmt.Errorf("unsupported")
+}
+
+func (r *testNameResolver) TranslateLabelExpressionsSelector(*metav1.LabelSelector) (*metav1.LabelSelector, error) {
+	return nil, fmt.Errorf("unsupported")
+}
+
+func (r *testNameResolver) TranslateLabelSelector(map[string]string) (map[string]string, error) {
+	return nil, fmt.Errorf("unsupported")
+}
+
+func (r *testNameResolver) TranslateNamespaceRef(string) (string, error) {
+	return "", fmt.Errorf("unsupported")
+}
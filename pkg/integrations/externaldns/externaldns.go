@@ -0,0 +1,39 @@
+package externaldns
+
+import (
+	"strings"
+
+	vclusterconfig "github.com/loft-sh/vcluster/config"
+)
+
+// AnnotationPrefix is the prefix of the service annotations external-dns reads
+const AnnotationPrefix = "external-dns.alpha.kubernetes.io/"
+
+// FilterAnnotations returns the given annotations without the external-dns annotations that are not allowed to be
+// synced to the host cluster. Other annotations are kept as they are.
+func FilterAnnotations(externalDNS vclusterconfig.ExternalDNS, annotations map[string]string) map[string]string {
+	if !externalDNS.Enabled || annotations == nil {
+		return annotations
+	}
+
+	filtered := make(map[string]string, len(annotations))
+	for k, v := range annotations {
+		if strings.HasPrefix(k, AnnotationPrefix) && !isAllowed(externalDNS.Annotations, k) {
+			continue
+		}
+
+		filtered[k] = v
+	}
+
+	return filtered
+}
+
+func isAllowed(allowed []string, key string) bool {
+	for _, a := range allowed {
+		if a == key || (strings.HasSuffix(a, "/") && strings.HasPrefix(key, a)) {
+			return true
+		}
+	}
+
+	return false
+}
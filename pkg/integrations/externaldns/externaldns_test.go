@@ -0,0 +1,33 @@
+package externaldns
+
+import (
+	"testing"
+
+	vclusterconfig "github.com/loft-sh/vcluster/config"
+	"gotest.tools/assert"
+)
+
+func TestFilterAnnotations(t *testing.T) {
+	annotations := map[string]string{
+		"external-dns.alpha.kubernetes.io/hostname": "web.example.com",
+		"external-dns.alpha.kubernetes.io/access":   "private",
+		"external-dns.alpha.kubernetes.io/aws-zone": "zone",
+		"example.com/other":                         "other",
+	}
+
+	// disabled keeps all annotations
+	externalDNS := vclusterconfig.ExternalDNS{Annotations: []string{"external-dns.alpha.kubernetes.io/hostname"}}
+	assert.DeepEqual(t, FilterAnnotations(externalDNS, annotations), annotations)
+
+	externalDNS.Enabled = true
+	assert.DeepEqual(t, FilterAnnotations(externalDNS, annotations), map[string]string{
+		"external-dns.alpha.kubernetes.io/hostname": "web.example.com",
+		"example.com/other":                         "other",
+	})
+
+	// prefixes match all annotations below them
+	externalDNS.Annotations = []string{AnnotationPrefix}
+	assert.DeepEqual(t, FilterAnnotations(externalDNS, annotations), annotations)
+
+	assert.Assert(t, FilterAnnotations(externalDNS, nil) == nil)
+}
@@ -0,0 +1,120 @@
+package externalsecrets
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	vclusterconfig "github.com/loft-sh/vcluster/config"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	Group      = "external-secrets.io"
+	APIVersion = Group + "/v1beta1"
+
+	SecretStoreKind        = "SecretStore"
+	ClusterSecretStoreKind = "ClusterSecretStore"
+
+	// ControllerName marks the virtual secrets the integration manages, so the secrets syncer leaves them alone
+	ControllerName = "external-secrets"
+)
+
+// Exports returns the generic sync exports of the external secrets resources that are synced to the host cluster.
+// The store references are kept, so ExternalSecrets use the ClusterSecretStores of the host cluster and the
+// SecretStores within the host namespace of the virtual cluster. The config maps and secrets the target template is
+// rendered from are rewritten to their host names, so only synced objects of the virtual cluster can be referenced.
+func Exports(externalSecrets vclusterconfig.ExternalSecrets) []*vclusterconfig.Export {
+	if !externalSecrets.Enabled || !externalSecrets.Sync.ToHost.ExternalSecrets.Enabled {
+		return nil
+	}
+
+	return []*vclusterconfig.Export{
+		{
+			SyncBase: vclusterconfig.SyncBase{
+				TypeInformation: vclusterconfig.TypeInformation{
+					APIVersion: APIVersion,
+					Kind:       "ExternalSecret",
+				},
+				Patches: []*vclusterconfig.Patch{
+					{
+						Operation: vclusterconfig.PatchTypeRewriteName,
+						Path:      "spec.target.name",
+					},
+					{
+						Operation: vclusterconfig.PatchTypeRewriteName,
+						Path:      "spec.target.template.templateFrom[*].configMap.name",
+					},
+					{
+						Operation: vclusterconfig.PatchTypeRewriteName,
+						Path:      "spec.target.template.templateFrom[*].secret.name",
+					},
+				},
+			},
+		},
+	}
+}
+
+// IsExported returns true if the integration already syncs the given kind
+func IsExported(externalSecrets vclusterconfig.ExternalSecrets, apiVersion, kind string) bool {
+	for _, export := range Exports(externalSecrets) {
+		if strings.SplitN(export.APIVersion, "/", 2)[0] == strings.SplitN(apiVersion, "/", 2)[0] && export.Kind == kind {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Validate returns an error if the ExternalSecret references a secret store that is not allowed by
+// integrations.externalSecrets.allowedStores
+func Validate(externalSecrets vclusterconfig.ExternalSecrets, externalSecret *unstructured.Unstructured) error {
+	storeRefs := []map[string]interface{}{}
+	if storeRef, ok, _ := unstructured.NestedMap(externalSecret.Object, "spec", "secretStoreRef"); ok {
+		storeRefs = append(storeRefs, storeRef)
+	}
+	for _, field := range []string{"data", "dataFrom"} {
+		items, _, _ := unstructured.NestedSlice(externalSecret.Object, "spec", field)
+		for _, item := range items {
+			itemMap, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			if storeRef, ok, _ := unstructured.NestedMap(itemMap, "sourceRef", "storeRef"); ok {
+				storeRefs = append(storeRefs, storeRef)
+			}
+		}
+	}
+
+	for _, storeRef := range storeRefs {
+		name, _, _ := unstructured.NestedString(storeRef, "name")
+		kind, _, _ := unstructured.NestedString(storeRef, "kind")
+		allowed := externalSecrets.AllowedStores.SecretStores
+		if kind == ClusterSecretStoreKind {
+			allowed = externalSecrets.AllowedStores.ClusterSecretStores
+		} else if kind == "" {
+			kind = SecretStoreKind
+		}
+
+		if !isAllowed(allowed, name) {
+			return fmt.Errorf("%s %s is not allowed by integrations.externalSecrets.allowedStores", kind, name)
+		}
+	}
+
+	return nil
+}
+
+// MirrorAllowed returns a filter for the ClusterSecretStores that are mirrored into the virtual cluster. Only the stores
+// allowed by integrations.externalSecrets.allowedStores.clusterSecretStores are mirrored, so the virtual cluster
+// doesn't see the other stores of the host cluster.
+func MirrorAllowed(externalSecrets vclusterconfig.ExternalSecrets) func(pObj client.Object) bool {
+	return func(pObj client.Object) bool {
+		return isAllowed(externalSecrets.AllowedStores.ClusterSecretStores, pObj.GetName())
+	}
+}
+
+func isAllowed(allowed []string, name string) bool {
+	return slices.Contains(allowed, "*") || slices.Contains(allowed, name)
+}
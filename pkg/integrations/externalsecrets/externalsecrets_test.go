@@ -0,0 +1,128 @@
+package externalsecrets
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	vclusterconfig "github.com/loft-sh/vcluster/config"
+	"github.com/loft-sh/vcluster/pkg/patches"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	"gotest.tools/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestExports(t *testing.T) {
+	translate.Default = translate.NewSingleNamespaceTranslator("vcluster")
+	externalSecrets := vclusterconfig.ExternalSecrets{Enabled: true}
+	externalSecrets.Sync.ToHost.ExternalSecrets.Enabled = true
+
+	exports := Exports(externalSecrets)
+	assert.Equal(t, len(exports), 1)
+	assert.Assert(t, IsExported(externalSecrets, "external-secrets.io/v1alpha1", "ExternalSecret"))
+	assert.Assert(t, !IsExported(externalSecrets, APIVersion, "SecretStore"))
+
+	externalSecret := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": APIVersion,
+		"kind":       "ExternalSecret",
+		"metadata":   map[string]interface{}{"name": "database", "namespace": "test"},
+		"spec": map[string]interface{}{
+			"secretStoreRef": map[string]interface{}{"name": "vault", "kind": ClusterSecretStoreKind},
+			"target": map[string]interface{}{
+				"name": "database-credentials",
+				"template": map[string]interface{}{
+					"templateFrom": []interface{}{
+						map[string]interface{}{"configMap": map[string]interface{}{"name": "template"}},
+						map[string]interface{}{"secret": map[string]interface{}{"name": "ca"}},
+					},
+				},
+			},
+		},
+	}}
+	err := patches.ApplyPatches(externalSecret, nil, exports[0].Patches, nil, &testNameResolver{namespace: "test"})
+	assert.NilError(t, err)
+	targetName, _, _ := unstructured.NestedString(externalSecret.Object, "spec", "target", "name")
+	assert.Equal(t, targetName, translate.Default.PhysicalName("database-credentials", "test"))
+	storeName, _, _ := unstructured.NestedString(externalSecret.Object, "spec", "secretStoreRef", "name")
+	assert.Equal(t, storeName, "vault")
+
+	// the template sources are rewritten, so host objects outside of the virtual cluster can't be referenced
+	templateFrom, _, _ := unstructured.NestedSlice(externalSecret.Object, "spec", "target", "template", "templateFrom")
+	configMapName, _, _ := unstructured.NestedString(templateFrom[0].(map[string]interface{}), "configMap", "name")
+	assert.Equal(t, configMapName, translate.Default.PhysicalName("template", "test"))
+	secretName, _, _ := unstructured.NestedString(templateFrom[1].(map[string]interface{}), "secret", "name")
+	assert.Equal(t, secretName, translate.Default.PhysicalName("ca", "test"))
+
+	externalSecrets.Sync.ToHost.ExternalSecrets.Enabled = false
+	assert.Equal(t, len(Exports(externalSecrets)), 0)
+}
+
+func TestValidate(t *testing.T) {
+	externalSecrets := vclusterconfig.ExternalSecrets{Enabled: true}
+	externalSecrets.AllowedStores.SecretStores = []string{"*"}
+	externalSecrets.AllowedStores.ClusterSecretStores = []string{"vault"}
+
+	newExternalSecret := func(storeRef map[string]interface{}, dataStoreRef map[string]interface{}) *unstructured.Unstructured {
+		spec := map[string]interface{}{"secretStoreRef": storeRef}
+		if dataStoreRef != nil {
+			spec["data"] = []interface{}{
+				map[string]interface{}{"secretKey": "password", "sourceRef": map[string]interface{}{"storeRef": dataStoreRef}},
+			}
+		}
+		return &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+	}
+
+	assert.NilError(t, Validate(externalSecrets, newExternalSecret(map[string]interface{}{"name": "local"}, nil)))
+	assert.NilError(t, Validate(externalSecrets, newExternalSecret(map[string]interface{}{"name": "vault", "kind": ClusterSecretStoreKind}, nil)))
+	assert.Error(t, Validate(externalSecrets, newExternalSecret(map[string]interface{}{"name": "aws", "kind": ClusterSecretStoreKind}, nil)), "ClusterSecretStore aws is not allowed by integrations.externalSecrets.allowedStores")
+	assert.Error(t, Validate(externalSecrets, newExternalSecret(map[string]interface{}{"name": "local"}, map[string]interface{}{"name": "aws", "kind": ClusterSecretStoreKind})), "ClusterSecretStore aws is not allowed by integrations.externalSecrets.allowedStores")
+
+	// stores are only allowed if they are listed
+	externalSecrets.AllowedStores.SecretStores = nil
+	assert.Error(t, Validate(externalSecrets, newExternalSecret(map[string]interface{}{"name": "local"}, nil)), "SecretStore local is not allowed by integrations.externalSecrets.allowedStores")
+}
+
+func TestMirrorAllowed(t *testing.T) {
+	store := &unstructured.Unstructured{}
+	store.SetName("team-a-vault")
+
+	// no ClusterSecretStores are mirrored by default
+	assert.Assert(t, !MirrorAllowed(vclusterconfig.ExternalSecrets{})(store))
+
+	externalSecrets := vclusterconfig.ExternalSecrets{AllowedStores: vclusterconfig.ExternalSecretsAllowedStores{ClusterSecretStores: []string{"team-a-vault"}}}
+	assert.Assert(t, MirrorAllowed(externalSecrets)(store))
+	store.SetName("team-b-vault")
+	assert.Assert(t, !MirrorAllowed(externalSecrets)(store))
+
+	externalSecrets.AllowedStores.ClusterSecretStores = []string{"*"}
+	assert.Assert(t, MirrorAllowed(externalSecrets)(store))
+}
+
+type testNameResolver struct {
+	namespace string
+}
+
+func (r *testNameResolver) TranslateName(name string, regex *regexp.Regexp, _ string) (string, error) {
+	return r.TranslateNameWithNamespace(name, r.namespace, regex, "")
+}
+
+func (r *testNameResolver) TranslateNameWithNamespace(name string, namespace string, _ *regexp.Regexp, _ string) (string, error) {
+	return translate.Default.PhysicalName(name, namespace), nil
+}
+
+func (r *testNameResolver) TranslateLabelKey(string) (string, error) {
+	return "", fmt.Errorf("unsupported")
+}
+
+func (r *testNameResolver) TranslateLabelExpressionsSelector(*metav1.LabelSelector) (*metav1.LabelSelector, error) {
+	return nil, fmt.Errorf("unsupported")
+}
+
+func (r *testNameResolver) TranslateLabelSelector(map[string]string) (map[string]string, error) {
+	return nil, fmt.Errorf("unsupported")
+}
+
+func (r *testNameResolver) TranslateNamespaceRef(string) (string, error) {
+	return "", fmt.Errorf("unsupported")
+}
@@ -0,0 +1,157 @@
+package istio
+
+import (
+	"strings"
+
+	vclusterconfig "github.com/loft-sh/vcluster/config"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	InjectLabel          = "sidecar.istio.io/inject"
+	RevisionLabel        = "istio.io/rev"
+	NamespaceInjectLabel = "istio-injection"
+
+	NetworkingAPIVersion = "networking.istio.io/v1beta1"
+)
+
+// sidecarContainers are the containers the sidecar injector adds to a pod
+var sidecarContainers = map[string]bool{
+	"istio-proxy":      true,
+	"istio-init":       true,
+	"istio-validation": true,
+}
+
+// hostRegexes match the service hosts of the virtual cluster within Istio resources. Fully qualified hosts are
+// rewritten first, so the short host regex only matches hosts relative to the namespace of the resource.
+var hostRegexes = []string{
+	`^$NAME\.$NAMESPACE\.svc\.cluster\.local$`,
+	`^$NAME$`,
+}
+
+// PodLabels returns the labels that make the sidecar injector of the host cluster inject the proxy into a host pod
+func PodLabels(istio vclusterconfig.Istio) map[string]string {
+	if istio.Revision != "" {
+		return map[string]string{RevisionLabel: istio.Revision}
+	}
+
+	return map[string]string{InjectLabel: "true"}
+}
+
+// NamespaceLabels returns the labels that enable sidecar injection for a host namespace
+func NamespaceLabels(istio vclusterconfig.Istio) map[string]string {
+	if istio.Revision != "" {
+		return map[string]string{RevisionLabel: istio.Revision}
+	}
+
+	return map[string]string{NamespaceInjectLabel: "enabled"}
+}
+
+// TranslatePod labels the host pod for sidecar injection, unless the virtual pod opted out of the mesh
+func TranslatePod(istio vclusterconfig.Istio, vPod, pPod *corev1.Pod) {
+	if !istio.Enabled || vPod.Labels[InjectLabel] == "false" || vPod.Annotations[InjectLabel] == "false" {
+		return
+	}
+
+	if pPod.Labels == nil {
+		pPod.Labels = map[string]string{}
+	}
+	for k, v := range PodLabels(istio) {
+		pPod.Labels[k] = v
+	}
+}
+
+// KeepHostLabels copies the Istio labels of the host pod to the updated host pod. The sidecar injector adds labels such
+// as security.istio.io/tlsMode that are not part of the virtual pod and would be removed by the update otherwise.
+func KeepHostLabels(pPod, updated *corev1.Pod) {
+	for k, v := range pPod.Labels {
+		if !isIstioKey(k) {
+			continue
+		}
+
+		if updated.Labels == nil {
+			updated.Labels = map[string]string{}
+		}
+		if _, ok := updated.Labels[k]; !ok {
+			updated.Labels[k] = v
+		}
+	}
+}
+
+// IsSidecarEvent returns true if the host event belongs to one of the injected sidecar containers, which don't exist
+// in the virtual pod
+func IsSidecarEvent(event *corev1.Event) bool {
+	fieldPath := event.InvolvedObject.FieldPath
+	start := strings.Index(fieldPath, "{")
+	if start == -1 || !strings.HasSuffix(fieldPath, "}") {
+		return false
+	}
+
+	return sidecarContainers[fieldPath[start+1:len(fieldPath)-1]]
+}
+
+// Exports returns the generic sync exports of the Istio resources that are synced to the host cluster
+func Exports(istio vclusterconfig.Istio) []*vclusterconfig.Export {
+	if !istio.Enabled {
+		return nil
+	}
+
+	exports := []*vclusterconfig.Export{}
+	if istio.Sync.ToHost.VirtualServices.Enabled {
+		exports = append(exports, newExport("VirtualService",
+			"spec.hosts[*]",
+			"spec.http[*].route[*].destination.host",
+			"spec.http[*].mirror.host",
+			"spec.http[*].mirrors[*].destination.host",
+			"spec.tcp[*].route[*].destination.host",
+			"spec.tls[*].route[*].destination.host",
+		))
+	}
+	if istio.Sync.ToHost.DestinationRules.Enabled {
+		exports = append(exports, newExport("DestinationRule", "spec.host"))
+	}
+
+	return exports
+}
+
+// IsExported returns true if the integration already syncs the given kind
+func IsExported(istio vclusterconfig.Istio, apiVersion, kind string) bool {
+	for _, export := range Exports(istio) {
+		if strings.SplitN(export.APIVersion, "/", 2)[0] == strings.SplitN(apiVersion, "/", 2)[0] && export.Kind == kind {
+			return true
+		}
+	}
+
+	return false
+}
+
+func newExport(kind string, hostPaths ...string) *vclusterconfig.Export {
+	patches := []*vclusterconfig.Patch{}
+	for _, hostPath := range hostPaths {
+		for _, regex := range hostRegexes {
+			patches = append(patches, &vclusterconfig.Patch{
+				Operation: vclusterconfig.PatchTypeRewriteName,
+				Path:      hostPath,
+				Regex:     regex,
+			})
+		}
+	}
+
+	return &vclusterconfig.Export{
+		SyncBase: vclusterconfig.SyncBase{
+			TypeInformation: vclusterconfig.TypeInformation{
+				APIVersion: NetworkingAPIVersion,
+				Kind:       kind,
+			},
+			// the host cluster might not run Istio yet
+			Optional: true,
+			Patches:  patches,
+		},
+	}
+}
+
+// isIstioKey returns true for label keys of the istio.io domain and its subdomains
+func isIstioKey(key string) bool {
+	prefix, _, found := strings.Cut(key, "/")
+	return found && (prefix == "istio.io" || strings.HasSuffix(prefix, ".istio.io"))
+}
@@ -0,0 +1,173 @@
+package istio
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	vclusterconfig "github.com/loft-sh/vcluster/config"
+	"github.com/loft-sh/vcluster/pkg/patches"
+	patchesregex "github.com/loft-sh/vcluster/pkg/patches/regex"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestExports(t *testing.T) {
+	translate.Default = translate.NewSingleNamespaceTranslator("vcluster")
+	istio := vclusterconfig.Istio{Enabled: true}
+	istio.Sync.ToHost.VirtualServices.Enabled = true
+	istio.Sync.ToHost.DestinationRules.Enabled = true
+
+	exports := Exports(istio)
+	assert.Equal(t, len(exports), 2)
+	assert.Assert(t, IsExported(istio, "networking.istio.io/v1", "VirtualService"))
+	assert.Assert(t, !IsExported(istio, "networking.istio.io/v1beta1", "Gateway"))
+
+	virtualService := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": NetworkingAPIVersion,
+		"kind":       "VirtualService",
+		"metadata":   map[string]interface{}{"name": "reviews", "namespace": "test"},
+		"spec": map[string]interface{}{
+			"hosts":    []interface{}{"reviews", "bookinfo.example.com"},
+			"gateways": []interface{}{"istio-system/ingressgateway"},
+			"http": []interface{}{
+				map[string]interface{}{
+					"route": []interface{}{
+						map[string]interface{}{"destination": map[string]interface{}{"host": "reviews", "subset": "v1"}},
+						map[string]interface{}{"destination": map[string]interface{}{"host": "ratings.other.svc.cluster.local"}},
+					},
+					"mirror": map[string]interface{}{"host": "reviews-shadow"},
+				},
+			},
+		},
+	}}
+	applyExport(t, exports[0], virtualService)
+
+	physicalHost := func(name, namespace string) string {
+		return translate.Default.PhysicalName(name, namespace)
+	}
+	hosts, _, _ := unstructured.NestedStringSlice(virtualService.Object, "spec", "hosts")
+	assert.DeepEqual(t, hosts, []string{physicalHost("reviews", "test"), "bookinfo.example.com"})
+	gateways, _, _ := unstructured.NestedStringSlice(virtualService.Object, "spec", "gateways")
+	assert.DeepEqual(t, gateways, []string{"istio-system/ingressgateway"})
+	http, _, _ := unstructured.NestedSlice(virtualService.Object, "spec", "http")
+	routes := http[0].(map[string]interface{})["route"].([]interface{})
+	assert.Equal(t, routes[0].(map[string]interface{})["destination"].(map[string]interface{})["host"], physicalHost("reviews", "test"))
+	assert.Equal(t, routes[1].(map[string]interface{})["destination"].(map[string]interface{})["host"], physicalHost("ratings", "other")+".vcluster.svc.cluster.local")
+	assert.Equal(t, http[0].(map[string]interface{})["mirror"].(map[string]interface{})["host"], physicalHost("reviews-shadow", "test"))
+
+	destinationRule := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": NetworkingAPIVersion,
+		"kind":       "DestinationRule",
+		"metadata":   map[string]interface{}{"name": "reviews", "namespace": "test"},
+		"spec":       map[string]interface{}{"host": "reviews.test.svc.cluster.local"},
+	}}
+	applyExport(t, exports[1], destinationRule)
+
+	host, _, _ := unstructured.NestedString(destinationRule.Object, "spec", "host")
+	assert.Equal(t, host, translate.Default.PhysicalName("reviews", "test")+".vcluster.svc.cluster.local")
+}
+
+func TestExportsDisabled(t *testing.T) {
+	istio := vclusterconfig.Istio{Enabled: true}
+	istio.Sync.ToHost.DestinationRules.Enabled = true
+	exports := Exports(istio)
+	assert.Equal(t, len(exports), 1)
+	assert.Equal(t, exports[0].Kind, "DestinationRule")
+
+	istio.Enabled = false
+	assert.Equal(t, len(Exports(istio)), 0)
+}
+
+func TestTranslatePod(t *testing.T) {
+	istio := vclusterconfig.Istio{Enabled: true}
+	pPod := &corev1.Pod{}
+	TranslatePod(istio, &corev1.Pod{}, pPod)
+	assert.DeepEqual(t, pPod.Labels, map[string]string{InjectLabel: "true"})
+
+	istio.Revision = "canary"
+	pPod = &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "test"}}}
+	TranslatePod(istio, &corev1.Pod{}, pPod)
+	assert.DeepEqual(t, pPod.Labels, map[string]string{"app": "test", RevisionLabel: "canary"})
+
+	// pods can opt out of the mesh
+	pPod = &corev1.Pod{}
+	TranslatePod(istio, &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{InjectLabel: "false"}}}, pPod)
+	assert.Assert(t, pPod.Labels == nil)
+}
+
+func TestKeepHostLabels(t *testing.T) {
+	pPod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{
+		"app":                                   "old",
+		InjectLabel:                             "true",
+		"security.istio.io/tlsMode":             "istio",
+		"service.istio.io/canonical-name":       "reviews",
+		"example.com/istio.io":                  "other",
+		"vcluster.loft.sh/managed-by":           "vcluster",
+		"topology.kubernetes.io/istio.io-style": "other",
+	}}}
+	updated := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "new"}}}
+	KeepHostLabels(pPod, updated)
+	assert.DeepEqual(t, updated.Labels, map[string]string{
+		"app":                             "new",
+		InjectLabel:                       "true",
+		"security.istio.io/tlsMode":       "istio",
+		"service.istio.io/canonical-name": "reviews",
+	})
+}
+
+func TestIsSidecarEvent(t *testing.T) {
+	assert.Assert(t, IsSidecarEvent(&corev1.Event{InvolvedObject: corev1.ObjectReference{FieldPath: "spec.containers{istio-proxy}"}}))
+	assert.Assert(t, IsSidecarEvent(&corev1.Event{InvolvedObject: corev1.ObjectReference{FieldPath: "spec.initContainers{istio-init}"}}))
+	assert.Assert(t, !IsSidecarEvent(&corev1.Event{InvolvedObject: corev1.ObjectReference{FieldPath: "spec.containers{nginx}"}}))
+	assert.Assert(t, !IsSidecarEvent(&corev1.Event{}))
+}
+
+func applyExport(t *testing.T, export *vclusterconfig.Export, obj *unstructured.Unstructured) {
+	for _, patch := range export.Patches {
+		parsed, err := patchesregex.PrepareRegex(patch.Regex)
+		assert.NilError(t, err)
+		patch.ParsedRegex = parsed
+	}
+
+	err := patches.ApplyPatches(obj, nil, export.Patches, nil, &testNameResolver{namespace: obj.GetNamespace()})
+	assert.NilError(t, err)
+}
+
+type testNameResolver struct {
+	namespace string
+}
+
+func (r *testNameResolver) TranslateName(name string, regex *regexp.Regexp, _ string) (string, error) {
+	return r.TranslateNameWithNamespace(name, r.namespace, regex, "")
+}
+
+func (r *testNameResolver) TranslateNameWithNamespace(name string, namespace string, regex *regexp.Regexp, _ string) (string, error) {
+	return patchesregex.ProcessRegex(regex, name, func(name, ns string) types.NamespacedName {
+		if ns == "" {
+			ns = namespace
+		}
+
+		return types.NamespacedName{Namespace: translate.Default.PhysicalNamespace(ns), Name: translate.Default.PhysicalName(name, ns)}
+	}), nil
+}
+
+func (r *testNameResolver) TranslateLabelKey(string) (string, error) {
+	return "", fmt.Errorf("unsupported")
+}
+
+func (r *testNameResolver) TranslateLabelExpressionsSelector(*metav1.LabelSelector) (*metav1.LabelSelector, error) {
+	return nil, fmt.Errorf("unsupported")
+}
+
+func (r *testNameResolver) TranslateLabelSelector(map[string]string) (map[string]string, error) {
+	return nil, fmt.Errorf("unsupported")
+}
+
+func (r *testNameResolver) TranslateNamespaceRef(string) (string, error) {
+	return "", fmt.Errorf("unsupported")
+}
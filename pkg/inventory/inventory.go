@@ -0,0 +1,204 @@
+package inventory
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/vcluster/pkg/cli/find"
+)
+
+// DefaultCacheTTL is the default time the list of virtual clusters is served from the cache
+const DefaultCacheTTL = 30 * time.Second
+
+// Options configure the inventory server
+type Options struct {
+	// Address the server listens on
+	Address string
+
+	// Token clients need to send as bearer token
+	Token string
+
+	// CacheTTL is the time the list of virtual clusters is cached for
+	CacheTTL time.Duration
+}
+
+// VCluster is the inventory entry of a virtual cluster
+type VCluster struct {
+	Name      string    `json:"name"`
+	Namespace string    `json:"namespace"`
+	Context   string    `json:"context"`
+	Version   string    `json:"version"`
+	Status    string    `json:"status"`
+	Healthy   bool      `json:"healthy"`
+	Created   time.Time `json:"created"`
+}
+
+// Inventory is the response of the list endpoint
+type Inventory struct {
+	VClusters []VCluster `json:"vclusters"`
+
+	// Updated is the time the virtual clusters were last listed
+	Updated time.Time `json:"updated"`
+}
+
+// ListFunc lists all virtual clusters that should be served
+type ListFunc func(ctx context.Context) ([]find.VCluster, error)
+
+// Server serves a read-only JSON API of all virtual clusters the list func returns. Listing is expensive as it
+// queries the pods of every virtual cluster, so results are cached for the configured ttl.
+type Server struct {
+	list  ListFunc
+	token string
+	ttl   time.Duration
+	log   log.Logger
+
+	m         sync.Mutex
+	inventory *Inventory
+	now       func() time.Time
+}
+
+func NewServer(list ListFunc, options *Options, log log.Logger) *Server {
+	ttl := options.CacheTTL
+	if ttl <= 0 {
+		ttl = DefaultCacheTTL
+	}
+
+	return &Server{
+		list:  list,
+		token: options.Token,
+		ttl:   ttl,
+		log:   log,
+		now:   time.Now,
+	}
+}
+
+// Handler returns the http handler of the inventory api
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	})
+	mux.Handle("GET /api/v1/vclusters", s.authenticate(http.HandlerFunc(s.handleList)))
+	mux.Handle("GET /api/v1/vclusters/{namespace}/{name}", s.authenticate(http.HandlerFunc(s.handleGet)))
+	return mux
+}
+
+// Start serves the inventory api on the configured address until the context is done
+func Start(ctx context.Context, list ListFunc, options *Options, log log.Logger) error {
+	if options.Token == "" {
+		return fmt.Errorf("a token is required to serve the inventory")
+	}
+
+	server := &http.Server{
+		Addr:              options.Address,
+		Handler:           NewServer(list, options, log).Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	log.Infof("Serving vcluster inventory on %s", options.Address)
+	err := server.ListenAndServe()
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	return nil
+}
+
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) != 1 {
+			writeError(w, http.StatusUnauthorized, "unauthorized")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) handleList(w http.ResponseWriter, r *http.Request) {
+	inventory, err := s.getInventory(r.Context())
+	if err != nil {
+		s.log.Errorf("Error listing vclusters: %v", err)
+		writeError(w, http.StatusInternalServerError, "error listing vclusters")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, inventory)
+}
+
+func (s *Server) handleGet(w http.ResponseWriter, r *http.Request) {
+	inventory, err := s.getInventory(r.Context())
+	if err != nil {
+		s.log.Errorf("Error listing vclusters: %v", err)
+		writeError(w, http.StatusInternalServerError, "error listing vclusters")
+		return
+	}
+
+	for _, vCluster := range inventory.VClusters {
+		if vCluster.Namespace == r.PathValue("namespace") && vCluster.Name == r.PathValue("name") {
+			writeJSON(w, http.StatusOK, vCluster)
+			return
+		}
+	}
+
+	writeError(w, http.StatusNotFound, fmt.Sprintf("vcluster %s/%s not found", r.PathValue("namespace"), r.PathValue("name")))
+}
+
+// getInventory returns the cached inventory or lists the virtual clusters again if the cache expired
+func (s *Server) getInventory(ctx context.Context) (*Inventory, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if s.inventory != nil && s.now().Sub(s.inventory.Updated) < s.ttl {
+		return s.inventory, nil
+	}
+
+	vClusters, err := s.list(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	inventory := &Inventory{
+		VClusters: []VCluster{},
+		Updated:   s.now(),
+	}
+	for _, vCluster := range vClusters {
+		inventory.VClusters = append(inventory.VClusters, VCluster{
+			Name:      vCluster.Name,
+			Namespace: vCluster.Namespace,
+			Context:   vCluster.Context,
+			Version:   vCluster.Version,
+			Status:    string(vCluster.Status),
+			Healthy:   vCluster.Status == find.StatusRunning,
+			Created:   vCluster.Created.Time,
+		})
+	}
+
+	s.inventory = inventory
+	return inventory, nil
+}
+
+func writeJSON(w http.ResponseWriter, code int, obj interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(obj)
+}
+
+func writeError(w http.ResponseWriter, code int, message string) {
+	writeJSON(w, code, map[string]string{"error": message})
+}
@@ -0,0 +1,63 @@
+package inventory
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/vcluster/pkg/cli/find"
+	"gotest.tools/v3/assert"
+)
+
+func TestServer(t *testing.T) {
+	calls := 0
+	server := NewServer(func(_ context.Context) ([]find.VCluster, error) {
+		calls++
+		return []find.VCluster{
+			{Name: "a", Namespace: "team-a", Status: find.StatusRunning},
+			{Name: "b", Namespace: "team-b", Status: find.StatusPaused},
+		}, nil
+	}, &Options{Token: "secret", CacheTTL: time.Minute}, log.Discard)
+	now := time.Now()
+	server.now = func() time.Time { return now }
+	handler := server.Handler()
+
+	request := func(path, token string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, path, nil)
+		if token != "" {
+			r.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w
+	}
+
+	assert.Equal(t, request("/healthz", "").Code, http.StatusOK)
+	assert.Equal(t, request("/api/v1/vclusters", "").Code, http.StatusUnauthorized)
+	assert.Equal(t, request("/api/v1/vclusters", "wrong").Code, http.StatusUnauthorized)
+
+	response := request("/api/v1/vclusters", "secret")
+	assert.Equal(t, response.Code, http.StatusOK)
+	inventory := &Inventory{}
+	assert.NilError(t, json.Unmarshal(response.Body.Bytes(), inventory))
+	assert.Equal(t, len(inventory.VClusters), 2)
+	assert.Assert(t, inventory.VClusters[0].Healthy)
+	assert.Assert(t, !inventory.VClusters[1].Healthy)
+
+	response = request("/api/v1/vclusters/team-b/b", "secret")
+	assert.Equal(t, response.Code, http.StatusOK)
+	vCluster := &VCluster{}
+	assert.NilError(t, json.Unmarshal(response.Body.Bytes(), vCluster))
+	assert.Equal(t, vCluster.Status, string(find.StatusPaused))
+	assert.Equal(t, request("/api/v1/vclusters/team-b/a", "secret").Code, http.StatusNotFound)
+
+	// served from the cache until the ttl expires
+	assert.Equal(t, calls, 1)
+	now = now.Add(2 * time.Minute)
+	request("/api/v1/vclusters", "secret")
+	assert.Equal(t, calls, 2)
+}
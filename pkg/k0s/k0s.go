@@ -12,6 +12,7 @@ import (
 	"text/template"
 
 	vclusterconfig "github.com/loft-sh/vcluster/config"
+	"github.com/loft-sh/vcluster/pkg/admission"
 	"github.com/loft-sh/vcluster/pkg/config"
 	"github.com/loft-sh/vcluster/pkg/etcd"
 	"github.com/loft-sh/vcluster/pkg/util/commandwriter"
@@ -20,6 +21,7 @@ import (
 
 const runDir = "/run/k0s"
 const cidrPlaceholder = "CIDR_PLACEHOLDER"
+const admissionConfigPlaceholder = "ADMISSION_CONFIG_PLACEHOLDER"
 
 var k0sConfig = `apiVersion: k0s.k0sproject.io/v1beta1
 kind: Cluster
@@ -33,13 +35,12 @@ spec:
       bind-address: 127.0.0.1
       enable-admission-plugins: NodeRestriction
       endpoint-reconciler-type: none
+      {{- if .Values.policies.podSecurityStandard.enabled }}
+      admission-control-config-file: ADMISSION_CONFIG_PLACEHOLDER
+      {{- end }}
   network:
-    {{- if .Values.serviceCIDR }}
-    serviceCIDR: {{ .Values.serviceCIDR }}
-    {{- else }}
-    # Will be replaced automatically by the syncer container on first startup
+    # Will be replaced automatically by the syncer container on startup with the configured or detected service cidr
     serviceCIDR: CIDR_PLACEHOLDER
-    {{- end }}
     provider: custom
     {{- if .Values.networking.advanced.clusterDomain }}
     clusterDomain: {{ .Values.networking.advanced.clusterDomain }}
@@ -168,7 +169,15 @@ func WriteK0sConfig(
 	}
 
 	// apply changes
-	updatedConfig := []byte(strings.ReplaceAll(string(outBytes), cidrPlaceholder, serviceCIDR))
+	updatedConfig := []byte(strings.NewReplacer(cidrPlaceholder, serviceCIDR, admissionConfigPlaceholder, admission.ConfigPath).Replace(string(outBytes)))
+
+	// write the pod security admission config referenced by the k0s config
+	if admission.IsEnabled(vConfig.Policies) {
+		err = admission.WriteConfig(vConfig.Policies)
+		if err != nil {
+			return fmt.Errorf("write admission config: %w", err)
+		}
+	}
 
 	// write the config to file
 	err = os.WriteFile("/tmp/k0s-config.yaml", updatedConfig, 0640)
@@ -5,8 +5,10 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"strconv"
 	"strings"
 
+	"github.com/loft-sh/vcluster/pkg/admission"
 	"github.com/loft-sh/vcluster/pkg/config"
 	"github.com/loft-sh/vcluster/pkg/etcd"
 	"github.com/loft-sh/vcluster/pkg/util/commandwriter"
@@ -49,6 +51,23 @@ func StartK3S(ctx context.Context, vConfig *config.VirtualClusterConfig, service
 			args = append(args, "--kube-controller-manager-arg=controllers=*,-nodeipam,-nodelifecycle,-persistentvolume-binder,-attachdetach,-persistentvolume-expander,-cloud-node-lifecycle,-ttl")
 			args = append(args, "--kube-apiserver-arg=endpoint-reconciler-type=none")
 		}
+		if admission.IsEnabled(vConfig.Policies) {
+			err := admission.WriteConfig(vConfig.Policies)
+			if err != nil {
+				return fmt.Errorf("write admission config: %w", err)
+			}
+
+			args = append(args, "--kube-apiserver-arg=admission-control-config-file="+admission.ConfigPath)
+		}
+		if issuerURL := vConfig.ControlPlane.Advanced.ServiceAccountIssuer.URL; issuerURL != "" {
+			// the first issuer signs new tokens, tokens of the default issuer stay valid
+			args = append(args, "--kube-apiserver-arg=service-account-issuer="+issuerURL)
+			args = append(args, "--kube-apiserver-arg=service-account-issuer=https://kubernetes.default.svc."+vConfig.Networking.Advanced.ClusterDomain)
+			args = append(args, "--kube-apiserver-arg=service-account-jwks-uri="+strings.TrimSuffix(issuerURL, "/")+"/openid/v1/jwks")
+		}
+		if vConfig.ControlPlane.Advanced.Density.Enabled && vConfig.ControlPlane.Advanced.Density.CompactionInterval > 0 {
+			args = append(args, "--kube-apiserver-arg=etcd-compaction-interval="+strconv.Itoa(vConfig.ControlPlane.Advanced.Density.CompactionInterval)+"m")
+		}
 		if vConfig.ControlPlane.BackingStore.Etcd.Deploy.Enabled {
 			// wait until etcd is up and running
 			_, err := etcd.WaitForEtcdClient(ctx, &etcd.Certificates{
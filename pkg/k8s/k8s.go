@@ -5,14 +5,20 @@ import (
 	"crypto/tls"
 	"errors"
 	"fmt"
+	"net"
 	"net/http"
+	"os"
 	"os/exec"
+	"strconv"
 	"strings"
 	"time"
 
 	vclusterconfig "github.com/loft-sh/vcluster/config"
+	"github.com/loft-sh/vcluster/pkg/admission"
+	"github.com/loft-sh/vcluster/pkg/certs"
 	"github.com/loft-sh/vcluster/pkg/config"
 	"github.com/loft-sh/vcluster/pkg/etcd"
+	"github.com/loft-sh/vcluster/pkg/konnectivity"
 	"github.com/loft-sh/vcluster/pkg/pro"
 	"github.com/loft-sh/vcluster/pkg/util/commandwriter"
 	"golang.org/x/sync/errgroup"
@@ -42,6 +48,12 @@ func StartK8S(
 			dataSource = "sqlite:///data/state.db?_journal=WAL&cache=shared&_busy_timeout=30000"
 		}
 
+		// a crashed kine leaves its socket behind, which would prevent the new kine from listening
+		err := removeStaleSocket(strings.TrimPrefix(KineEndpoint, "unix://"))
+		if err != nil {
+			return fmt.Errorf("remove stale kine socket: %w", err)
+		}
+
 		// start embedded mode
 		go func() {
 			args := []string{}
@@ -113,8 +125,17 @@ func StartK8S(
 				args = append(args, "--requestheader-group-headers=X-Remote-Group")
 				args = append(args, "--requestheader-username-headers=X-Remote-User")
 				args = append(args, "--secure-port=6443")
+				// the first issuer signs new tokens, tokens of the default issuer stay valid
+				if issuerURL := vConfig.ControlPlane.Advanced.ServiceAccountIssuer.URL; issuerURL != "" {
+					args = append(args, "--service-account-issuer="+issuerURL)
+					args = append(args, "--service-account-jwks-uri="+strings.TrimSuffix(issuerURL, "/")+"/openid/v1/jwks")
+				}
 				args = append(args, "--service-account-issuer=https://kubernetes.default.svc.cluster.local")
 				args = append(args, "--service-account-key-file=/data/pki/sa.pub")
+				// keep accepting tokens signed with the key before the last rotation
+				if _, err := os.Stat("/data/pki/" + certs.ServiceAccountPreviousPublicKeyName); err == nil {
+					args = append(args, "--service-account-key-file=/data/pki/"+certs.ServiceAccountPreviousPublicKeyName)
+				}
 				args = append(args, "--service-account-signing-key-file=/data/pki/sa.key")
 				args = append(args, "--tls-cert-file=/data/pki/apiserver.crt")
 				args = append(args, "--tls-private-key-file=/data/pki/apiserver.key")
@@ -122,6 +143,31 @@ func StartK8S(
 				args = append(args, "--endpoint-reconciler-type=none")
 			}
 
+			// tunnel the traffic into the workload cluster through the konnectivity-server
+			if konnectivity.IsEnabled(vConfig.Experimental.IsolatedControlPlane) {
+				err := konnectivity.WriteEgressSelectorConfig()
+				if err != nil {
+					return fmt.Errorf("write egress selector config: %w", err)
+				}
+
+				args = append(args, "--egress-selector-config-file="+konnectivity.EgressSelectorConfigPath)
+			}
+
+			// configure the pod security admission defaults and exemptions
+			if admission.IsEnabled(vConfig.Policies) {
+				err := admission.WriteConfig(vConfig.Policies)
+				if err != nil {
+					return fmt.Errorf("write admission config: %w", err)
+				}
+
+				args = append(args, "--admission-control-config-file="+admission.ConfigPath)
+			}
+
+			// compact the backing store more often when running densely
+			if vConfig.ControlPlane.Advanced.Density.Enabled && vConfig.ControlPlane.Advanced.Density.CompactionInterval > 0 {
+				args = append(args, "--etcd-compaction-interval="+strconv.Itoa(vConfig.ControlPlane.Advanced.Density.CompactionInterval)+"m")
+			}
+
 			// add extra args
 			args = append(args, apiServer.ExtraArgs...)
 
@@ -243,6 +289,32 @@ func RunCommand(ctx context.Context, command []string, component string) error {
 	return err
 }
 
+// removeStaleSocket removes the unix socket at the given path if nobody is listening on it anymore
+func removeStaleSocket(path string) error {
+	_, err := os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+
+		return err
+	}
+
+	conn, err := net.DialTimeout("unix", path, time.Second)
+	if err == nil {
+		_ = conn.Close()
+		return nil
+	}
+
+	klog.Infof("Removing stale socket %s", path)
+	err = os.Remove(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
+
 // waits for the api to be up, ignoring certs and calling it
 // localhost
 func waitForAPI(ctx context.Context) bool {
@@ -0,0 +1,142 @@
+package konnectivity
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	vclusterconfig "github.com/loft-sh/vcluster/config"
+	"github.com/loft-sh/vcluster/pkg/certs"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	certutil "k8s.io/client-go/util/cert"
+	"k8s.io/client-go/util/keyutil"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// SocketPath is the unix socket the konnectivity-server listens on for the api server
+	SocketPath = "/run/konnectivity/konnectivity-server.socket"
+
+	// EgressSelectorConfigPath is the path of the egress selector configuration that is passed to the api server
+	EgressSelectorConfigPath = "/run/konnectivity/egress-selector-configuration.yaml"
+
+	// AgentCommonName is the common name of the client certificate the agents authenticate with
+	AgentCommonName = "system:konnectivity-agent"
+
+	// renew the agent certificate if it expires within this period
+	agentCertificateRenewPeriod = 30 * 24 * time.Hour
+)
+
+// the cluster egress covers webhooks, aggregated apis and kubelets, the control plane egress stays local
+const egressSelectorConfig = `apiVersion: apiserver.k8s.io/v1beta1
+kind: EgressSelectorConfiguration
+egressSelections:
+- name: cluster
+  connection:
+    proxyProtocol: GRPC
+    transport:
+      uds:
+        udsName: %s
+- name: controlplane
+  connection:
+    proxyProtocol: Direct
+- name: etcd
+  connection:
+    proxyProtocol: Direct
+`
+
+// IsEnabled returns true if the control plane should tunnel its traffic through the konnectivity-server
+func IsEnabled(isolatedControlPlane vclusterconfig.ExperimentalIsolatedControlPlane) bool {
+	return isolatedControlPlane.Enabled && isolatedControlPlane.Konnectivity.Enabled
+}
+
+// AgentSecretName returns the name of the secret within the workload cluster that holds the agent certificates
+func AgentSecretName(workloadService string) string {
+	return "vc-konnectivity-" + workloadService
+}
+
+// WriteEgressSelectorConfig writes the egress selector configuration for the api server
+func WriteEgressSelectorConfig() error {
+	err := os.MkdirAll(filepath.Dir(EgressSelectorConfigPath), 0755)
+	if err != nil {
+		return fmt.Errorf("create konnectivity dir: %w", err)
+	}
+
+	return os.WriteFile(EgressSelectorConfigPath, []byte(fmt.Sprintf(egressSelectorConfig, SocketPath)), 0640)
+}
+
+// EnsureAgentSecret makes sure the workload cluster has a secret with a client certificate signed by the virtual cluster
+// ca, which the agents use to connect to the konnectivity-server, and the ca to verify the server.
+func EnsureAgentSecret(ctx context.Context, workloadClient kubernetes.Interface, workloadNamespace, workloadService, pkiDir string) error {
+	caCert, caKey, err := certs.TryLoadCertAndKeyFromDisk(pkiDir, certs.CACertAndKeyBaseName)
+	if err != nil {
+		return fmt.Errorf("load ca: %w", err)
+	}
+	caCertPEM := certs.EncodeCertPEM(caCert)
+
+	secretName := AgentSecretName(workloadService)
+	secret, err := workloadClient.CoreV1().Secrets(workloadNamespace).Get(ctx, secretName, metav1.GetOptions{})
+	notFound := kerrors.IsNotFound(err)
+	if err != nil && !notFound {
+		return err
+	} else if err == nil && agentSecretValid(secret, caCertPEM) {
+		return nil
+	}
+
+	cert, key, err := certs.NewCertAndKey(caCert, caKey, &certs.CertConfig{
+		Config: certutil.Config{
+			CommonName: AgentCommonName,
+			Usages:     []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("create agent certificate: %w", err)
+	}
+	keyPEM, err := keyutil.MarshalPrivateKeyToPEM(key)
+	if err != nil {
+		return fmt.Errorf("encode agent key: %w", err)
+	}
+
+	data := map[string][]byte{
+		"ca.crt":                caCertPEM,
+		corev1.TLSCertKey:       certs.EncodeCertPEM(cert),
+		corev1.TLSPrivateKeyKey: keyPEM,
+	}
+	if notFound {
+		klog.Infof("Create konnectivity agent secret %s/%s", workloadNamespace, secretName)
+		_, err = workloadClient.CoreV1().Secrets(workloadNamespace).Create(ctx, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      secretName,
+				Namespace: workloadNamespace,
+			},
+			Type: corev1.SecretTypeTLS,
+			Data: data,
+		}, metav1.CreateOptions{})
+		return err
+	}
+
+	klog.Infof("Update konnectivity agent secret %s/%s", workloadNamespace, secretName)
+	secret.Data = data
+	_, err = workloadClient.CoreV1().Secrets(workloadNamespace).Update(ctx, secret, metav1.UpdateOptions{})
+	return err
+}
+
+func agentSecretValid(secret *corev1.Secret, caCertPEM []byte) bool {
+	if !bytes.Equal(secret.Data["ca.crt"], caCertPEM) {
+		return false
+	}
+
+	agentCerts, err := certutil.ParseCertsPEM(secret.Data[corev1.TLSCertKey])
+	if err != nil || len(agentCerts) == 0 {
+		return false
+	}
+
+	return time.Until(agentCerts[0].NotAfter) > agentCertificateRenewPeriod
+}
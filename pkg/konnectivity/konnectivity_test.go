@@ -0,0 +1,56 @@
+package konnectivity
+
+import (
+	"context"
+	"testing"
+
+	"github.com/loft-sh/vcluster/pkg/certs"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	certutil "k8s.io/client-go/util/cert"
+)
+
+func TestEnsureAgentSecret(t *testing.T) {
+	pkiDir := t.TempDir()
+	writeCA(t, pkiDir)
+
+	ctx := context.Background()
+	client := fake.NewSimpleClientset()
+	err := EnsureAgentSecret(ctx, client, "workload", "my-vcluster", pkiDir)
+	assert.NilError(t, err)
+
+	secret, err := client.CoreV1().Secrets("workload").Get(ctx, AgentSecretName("my-vcluster"), metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, secret.Type, corev1.SecretTypeTLS)
+
+	// the agent certificate is signed by the virtual cluster ca
+	caCert, err := certs.TryLoadCertFromDisk(pkiDir, certs.CACertAndKeyBaseName)
+	assert.NilError(t, err)
+	agentCerts, err := certutil.ParseCertsPEM(secret.Data[corev1.TLSCertKey])
+	assert.NilError(t, err)
+	assert.Equal(t, agentCerts[0].Subject.CommonName, AgentCommonName)
+	assert.NilError(t, certs.VerifyCertChain(agentCerts[0], nil, caCert))
+
+	// valid secrets are kept
+	err = EnsureAgentSecret(ctx, client, "workload", "my-vcluster", pkiDir)
+	assert.NilError(t, err)
+	unchanged, err := client.CoreV1().Secrets("workload").Get(ctx, AgentSecretName("my-vcluster"), metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, unchanged.Data, secret.Data)
+
+	// a new ca renews the secret
+	writeCA(t, pkiDir)
+	err = EnsureAgentSecret(ctx, client, "workload", "my-vcluster", pkiDir)
+	assert.NilError(t, err)
+	renewed, err := client.CoreV1().Secrets("workload").Get(ctx, AgentSecretName("my-vcluster"), metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Assert(t, string(renewed.Data["ca.crt"]) != string(secret.Data["ca.crt"]))
+}
+
+func writeCA(t *testing.T, pkiDir string) {
+	caCert, caKey, err := certs.NewCertificateAuthority(&certs.CertConfig{Config: certutil.Config{CommonName: "kubernetes"}})
+	assert.NilError(t, err)
+	assert.NilError(t, certs.WriteCertAndKey(pkiDir, certs.CACertAndKeyBaseName, caCert, caKey))
+}
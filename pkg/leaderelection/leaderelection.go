@@ -22,6 +22,7 @@ import (
 )
 
 func StartLeaderElection(ctx *config.ControllerContext, scheme *runtime.Scheme, run func() error) error {
+	startTime := time.Now()
 	localConfig := ctx.LocalManager.GetConfig()
 
 	// create the event recorder
@@ -46,15 +47,22 @@ func StartLeaderElection(ctx *config.ControllerContext, scheme *runtime.Scheme,
 		return err
 	}
 
+	// release leases of crashed instances, so we don't have to wait for them to expire
+	leaseName := translate.SafeConcatName("vcluster", translate.VClusterName, "controller")
+	_, err = CleanupStaleLease(ctx.Context, leaderElectionClient, ctx.Config.WorkloadNamespace, leaseName, id+identitySuffix)
+	if err != nil {
+		klog.Errorf("Error cleaning up stale leader election lease: %v", err)
+	}
+
 	// Lock required for leader election
 	rl, err := resourcelock.New(
 		resourcelock.LeasesResourceLock,
 		ctx.Config.WorkloadNamespace,
-		translate.SafeConcatName("vcluster", translate.VClusterName, "controller"),
+		leaseName,
 		leaderElectionClient.CoreV1(),
 		leaderElectionClient.CoordinationV1(),
 		resourcelock.ResourceLockConfig{
-			Identity:      id + "-external-vcluster-controller",
+			Identity:      id + identitySuffix,
 			EventRecorder: recorder,
 		},
 	)
@@ -70,7 +78,9 @@ func StartLeaderElection(ctx *config.ControllerContext, scheme *runtime.Scheme,
 		RetryPeriod:   time.Duration(ctx.Config.ControlPlane.StatefulSet.HighAvailability.RetryPeriod) * time.Second,
 		Callbacks: leaderelection.LeaderCallbacks{
 			OnStartedLeading: func(_ context.Context) {
-				klog.Info("Acquired leadership and run vcluster in leader mode")
+				recovery := time.Since(startTime)
+				RecoverySeconds.Set(recovery.Seconds())
+				klog.Infof("Acquired leadership after %s and run vcluster in leader mode", recovery.Round(time.Millisecond).String())
 
 				// start vcluster in leader mode
 				err = run()
@@ -0,0 +1,333 @@
+package leaderelection
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/loft-sh/vcluster/pkg/config"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	// shardMemberLabel marks the member leases of the replicas of a virtual cluster that take part in sharding
+	shardMemberLabel = "vcluster.loft.sh/shard-member"
+
+	// shardMembersKey is the key of the assignment config map that holds the identities of the members in shard order
+	shardMembersKey = "members"
+
+	// shardGenerationKey is the key of the assignment config map that holds the generation of the assignment
+	shardGenerationKey = "generation"
+
+	// shardGenerationAnnotation is set on the member leases to the generation of the assignment the member applied
+	shardGenerationAnnotation = "vcluster.loft.sh/shard-generation"
+)
+
+// StartShardMember renews the member lease of this replica and applies the shard assignment written by the leader. A
+// replica without a shard does not sync any objects until the leader assigned one to it. The member lease records
+// the generation of the applied assignment, so the leader knows when this replica dropped its previous shard. Once
+// the controller context is stopped, the member lease is released, so the leader reassigns the shard without waiting
+// for the lease to expire.
+func StartShardMember(ctx *config.ControllerContext) error {
+	kubeClient, err := kubernetes.NewForConfig(rest.AddUserAgent(ctx.LocalManager.GetConfig(), "shard-member"))
+	if err != nil {
+		return fmt.Errorf("create shard member client: %w", err)
+	}
+
+	id, err := os.Hostname()
+	if err != nil {
+		return err
+	}
+
+	member := &shardMember{
+		config:        ctx.Config,
+		kubeClient:    kubeClient,
+		namespace:     ctx.Config.WorkloadNamespace,
+		identity:      id + identitySuffix,
+		leaseDuration: int32(ctx.Config.ControlPlane.StatefulSet.HighAvailability.LeaseDuration),
+	}
+	go func() {
+		wait.Until(func() {
+			member.sync(ctx.Context)
+		}, time.Duration(ctx.Config.ControlPlane.StatefulSet.HighAvailability.RetryPeriod)*time.Second, ctx.StopChan)
+
+		releaseCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		err := member.release(releaseCtx)
+		if err != nil {
+			klog.Errorf("Error releasing shard member lease: %v", err)
+		}
+	}()
+
+	return nil
+}
+
+// shardMember applies the shard assignment of the leader to the config of this replica
+type shardMember struct {
+	config        *config.VirtualClusterConfig
+	kubeClient    kubernetes.Interface
+	namespace     string
+	identity      string
+	leaseDuration int32
+
+	// generation is the generation of the applied shard assignment
+	generation int64
+}
+
+// sync applies the current shard assignment and then renews the member lease with the applied generation. If the
+// lease cannot be renewed, the shard is dropped, because the leader reassigns it once the lease expired.
+func (m *shardMember) sync(ctx context.Context) {
+	assignment, err := readShardAssignment(ctx, m.kubeClient, m.namespace)
+	if err != nil {
+		klog.Errorf("Error reading shard assignment: %v", err)
+	} else {
+		index := slices.Index(assignment.Members, m.identity)
+		if m.config.SetShardAssignment(index, len(assignment.Members)) {
+			if index < 0 {
+				klog.Infof("No shard assigned to this replica, waiting for the leader")
+			} else {
+				klog.Infof("Assigned shard %d of %d to this replica", index, len(assignment.Members))
+			}
+		}
+		m.generation = assignment.Generation
+	}
+
+	err = renewMemberLease(ctx, m.kubeClient, m.namespace, m.identity, m.leaseDuration, m.generation)
+	if err != nil {
+		klog.Errorf("Error renewing shard member lease: %v", err)
+		if m.config.SetShardAssignment(-1, 0) {
+			klog.Infof("Dropped the shard of this replica until the member lease is renewed")
+		}
+	}
+}
+
+// release drops the shard of this replica and deletes its member lease
+func (m *shardMember) release(ctx context.Context) error {
+	m.config.SetShardAssignment(-1, 0)
+	err := m.kubeClient.CoordinationV1().Leases(m.namespace).Delete(ctx, memberLeaseName(m.identity), metav1.DeleteOptions{})
+	if err != nil && !kerrors.IsNotFound(err) {
+		return err
+	}
+
+	return nil
+}
+
+// AssignShards is run by the leader and assigns one shard to each replica with a live member lease, so the number of
+// shards follows the number of running replicas. Shards are reassigned in two steps, so an object is never synced by
+// two replicas: first all shards are revoked, then the new assignment is written once every live replica confirmed
+// it dropped its previous shard. Replicas whose member lease expired are not waited for.
+func AssignShards(ctx *config.ControllerContext) {
+	kubeClient, err := kubernetes.NewForConfig(rest.AddUserAgent(ctx.LocalManager.GetConfig(), "shard-leader"))
+	if err != nil {
+		klog.Errorf("Error creating shard leader client: %v", err)
+		return
+	}
+
+	namespace := ctx.Config.WorkloadNamespace
+	wait.Until(func() {
+		err := assignShards(ctx.Context, kubeClient, namespace, time.Now())
+		if err != nil {
+			klog.Errorf("Error assigning shards: %v", err)
+		}
+	}, time.Duration(ctx.Config.ControlPlane.StatefulSet.HighAvailability.RetryPeriod)*time.Second, ctx.StopChan)
+}
+
+func assignShards(ctx context.Context, kubeClient kubernetes.Interface, namespace string, now time.Time) error {
+	members, err := liveMembers(ctx, kubeClient, namespace, now)
+	if err != nil {
+		return fmt.Errorf("list shard members: %w", err)
+	}
+	current, err := readShardAssignment(ctx, kubeClient, namespace)
+	if err != nil {
+		return fmt.Errorf("read shard assignment: %w", err)
+	}
+
+	next, changed := nextShardAssignment(current, members)
+	if !changed {
+		return nil
+	}
+	err = writeShardAssignment(ctx, kubeClient, namespace, next)
+	if err != nil {
+		return fmt.Errorf("write shard assignment: %w", err)
+	}
+
+	if len(next.Members) == 0 {
+		klog.Infof("Revoked all shards to reassign them to replicas %s", strings.Join(memberIdentities(members), ", "))
+	} else {
+		klog.Infof("Assigned %d shards to replicas %s", len(next.Members), strings.Join(next.Members, ", "))
+	}
+	return nil
+}
+
+// shardAssignment holds the identities of the members in shard order. The generation increases with every change.
+type shardAssignment struct {
+	Members    []string
+	Generation int64
+
+	// found is true if the assignment was read from the config map with the resource version
+	found           bool
+	resourceVersion string
+}
+
+// liveShardMember is a member whose lease did not expire yet
+type liveShardMember struct {
+	Identity string
+
+	// Generation is the generation of the shard assignment the member applied
+	Generation int64
+}
+
+// nextShardAssignment returns the assignment that follows the current one for the given live members and true if it
+// differs. An assignment that doesn't match the live members is revoked first. The new assignment is only returned
+// once all live members applied the revocation.
+func nextShardAssignment(current shardAssignment, members []liveShardMember) (shardAssignment, bool) {
+	identities := memberIdentities(members)
+	if slices.Equal(current.Members, identities) {
+		return current, false
+	} else if len(current.Members) > 0 {
+		return shardAssignment{Generation: current.Generation + 1, found: current.found, resourceVersion: current.resourceVersion}, true
+	}
+
+	for _, member := range members {
+		if member.Generation < current.Generation {
+			return current, false
+		}
+	}
+
+	return shardAssignment{Members: identities, Generation: current.Generation + 1, found: current.found, resourceVersion: current.resourceVersion}, true
+}
+
+func memberIdentities(members []liveShardMember) []string {
+	identities := []string{}
+	for _, member := range members {
+		identities = append(identities, member.Identity)
+	}
+
+	return identities
+}
+
+func memberLeaseName(identity string) string {
+	return translate.SafeConcatName("vcluster", translate.VClusterName, "shard", strings.TrimSuffix(identity, identitySuffix))
+}
+
+func assignmentName() string {
+	return translate.SafeConcatName("vcluster", translate.VClusterName, "shards")
+}
+
+func renewMemberLease(ctx context.Context, kubeClient kubernetes.Interface, namespace, identity string, leaseDuration int32, generation int64) error {
+	now := metav1.NewMicroTime(time.Now())
+	annotations := map[string]string{shardGenerationAnnotation: strconv.FormatInt(generation, 10)}
+	lease, err := kubeClient.CoordinationV1().Leases(namespace).Get(ctx, memberLeaseName(identity), metav1.GetOptions{})
+	if kerrors.IsNotFound(err) {
+		_, err = kubeClient.CoordinationV1().Leases(namespace).Create(ctx, &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        memberLeaseName(identity),
+				Namespace:   namespace,
+				Labels:      map[string]string{shardMemberLabel: translate.VClusterName},
+				Annotations: annotations,
+			},
+			Spec: coordinationv1.LeaseSpec{
+				HolderIdentity:       ptr.To(identity),
+				LeaseDurationSeconds: ptr.To(leaseDuration),
+				AcquireTime:          &now,
+				RenewTime:            &now,
+			},
+		}, metav1.CreateOptions{})
+		return err
+	} else if err != nil {
+		return err
+	}
+
+	if lease.Annotations == nil {
+		lease.Annotations = map[string]string{}
+	}
+	lease.Annotations[shardGenerationAnnotation] = annotations[shardGenerationAnnotation]
+	lease.Spec.HolderIdentity = ptr.To(identity)
+	lease.Spec.LeaseDurationSeconds = ptr.To(leaseDuration)
+	lease.Spec.RenewTime = &now
+	_, err = kubeClient.CoordinationV1().Leases(namespace).Update(ctx, lease, metav1.UpdateOptions{})
+	return err
+}
+
+// liveMembers returns the members whose lease did not expire yet sorted by their identity
+func liveMembers(ctx context.Context, kubeClient kubernetes.Interface, namespace string, now time.Time) ([]liveShardMember, error) {
+	leaseList, err := kubeClient.CoordinationV1().Leases(namespace).List(ctx, metav1.ListOptions{LabelSelector: shardMemberLabel + "=" + translate.VClusterName})
+	if err != nil {
+		return nil, err
+	}
+
+	members := []liveShardMember{}
+	for _, lease := range leaseList.Items {
+		if lease.Spec.HolderIdentity == nil || lease.Spec.RenewTime == nil || lease.Spec.LeaseDurationSeconds == nil {
+			continue
+		}
+
+		expiry := lease.Spec.RenewTime.Add(time.Duration(*lease.Spec.LeaseDurationSeconds) * time.Second)
+		if !expiry.After(now) || slices.ContainsFunc(members, func(member liveShardMember) bool { return member.Identity == *lease.Spec.HolderIdentity }) {
+			continue
+		}
+
+		generation, _ := strconv.ParseInt(lease.Annotations[shardGenerationAnnotation], 10, 64)
+		members = append(members, liveShardMember{Identity: *lease.Spec.HolderIdentity, Generation: generation})
+	}
+
+	slices.SortFunc(members, func(a, b liveShardMember) int {
+		return strings.Compare(a.Identity, b.Identity)
+	})
+	return members, nil
+}
+
+// readShardAssignment reads the assignment config map, a missing config map is an empty assignment
+func readShardAssignment(ctx context.Context, kubeClient kubernetes.Interface, namespace string) (shardAssignment, error) {
+	configMap, err := kubeClient.CoreV1().ConfigMaps(namespace).Get(ctx, assignmentName(), metav1.GetOptions{})
+	if kerrors.IsNotFound(err) {
+		return shardAssignment{}, nil
+	} else if err != nil {
+		return shardAssignment{}, err
+	}
+
+	assignment := shardAssignment{Members: []string{}, found: true, resourceVersion: configMap.ResourceVersion}
+	if configMap.Data[shardMembersKey] != "" {
+		assignment.Members = strings.Split(configMap.Data[shardMembersKey], ",")
+	}
+	if configMap.Data[shardGenerationKey] != "" {
+		assignment.Generation, err = strconv.ParseInt(configMap.Data[shardGenerationKey], 10, 64)
+		if err != nil {
+			return shardAssignment{}, fmt.Errorf("parse %s: %w", shardGenerationKey, err)
+		}
+	}
+
+	return assignment, nil
+}
+
+// writeShardAssignment writes the assignment to the config map. The config map is updated with the resource version it
+// was read with, so a previous leader can't overwrite a newer assignment.
+func writeShardAssignment(ctx context.Context, kubeClient kubernetes.Interface, namespace string, assignment shardAssignment) error {
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: assignmentName(), Namespace: namespace, ResourceVersion: assignment.resourceVersion},
+		Data: map[string]string{
+			shardMembersKey:    strings.Join(assignment.Members, ","),
+			shardGenerationKey: strconv.FormatInt(assignment.Generation, 10),
+		},
+	}
+	if !assignment.found {
+		_, err := kubeClient.CoreV1().ConfigMaps(namespace).Create(ctx, configMap, metav1.CreateOptions{})
+		return err
+	}
+
+	_, err := kubeClient.CoreV1().ConfigMaps(namespace).Update(ctx, configMap, metav1.UpdateOptions{})
+	return err
+}
@@ -0,0 +1,133 @@
+package leaderelection
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	vclusterconfig "github.com/loft-sh/vcluster/config"
+	"github.com/loft-sh/vcluster/pkg/config"
+	"gotest.tools/v3/assert"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestShardAssignment(t *testing.T) {
+	ctx := context.Background()
+	kubeClient := fake.NewSimpleClientset()
+
+	// without assignment no member owns a shard
+	assignment, err := readShardAssignment(ctx, kubeClient, "test")
+	assert.NilError(t, err)
+	assert.Equal(t, len(assignment.Members), 0)
+	assert.Equal(t, assignment.Generation, int64(0))
+
+	for _, identity := range []string{"vcluster-1", "vcluster-0", "vcluster-2"} {
+		assert.NilError(t, renewMemberLease(ctx, kubeClient, "test", identity+identitySuffix, 60, 0))
+	}
+	assert.NilError(t, renewMemberLease(ctx, kubeClient, "test", "vcluster-0"+identitySuffix, 60, 3))
+
+	members, err := liveMembers(ctx, kubeClient, "test", time.Now())
+	assert.NilError(t, err)
+	assert.DeepEqual(t, members, []liveShardMember{
+		{Identity: "vcluster-0" + identitySuffix, Generation: 3},
+		{Identity: "vcluster-1" + identitySuffix},
+		{Identity: "vcluster-2" + identitySuffix},
+	})
+
+	// a new assignment is written directly if no shard was assigned before
+	next, changed := nextShardAssignment(assignment, members)
+	assert.Assert(t, changed)
+	assert.NilError(t, writeShardAssignment(ctx, kubeClient, "test", next))
+	assignment, err = readShardAssignment(ctx, kubeClient, "test")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, assignment.Members, []string{"vcluster-0" + identitySuffix, "vcluster-1" + identitySuffix, "vcluster-2" + identitySuffix})
+	assert.Equal(t, assignment.Generation, int64(1))
+	_, changed = nextShardAssignment(assignment, members)
+	assert.Assert(t, !changed)
+
+	// members whose lease expired lose their shard
+	members, err = liveMembers(ctx, kubeClient, "test", time.Now().Add(2*time.Minute))
+	assert.NilError(t, err)
+	assert.Equal(t, len(members), 0)
+}
+
+func TestShardReassignment(t *testing.T) {
+	ctx := context.Background()
+	kubeClient := fake.NewSimpleClientset()
+	namespaces := []string{}
+	for i := range 20 {
+		namespaces = append(namespaces, fmt.Sprintf("ns-%d", i))
+	}
+
+	newMember := func(identity string) *shardMember {
+		configPath := filepath.Join(t.TempDir(), "config.yaml")
+		assert.NilError(t, os.WriteFile(configPath, []byte(vclusterconfig.Values), 0600))
+		vConfig, err := config.ParseConfig(configPath, "my-vcluster", []string{"experimental.syncSettings.sharding.enabled=true", "controlPlane.statefulSet.highAvailability.replicas=2"})
+		assert.NilError(t, err)
+
+		return &shardMember{config: vConfig, kubeClient: kubeClient, namespace: "test", identity: identity + identitySuffix, leaseDuration: 60}
+	}
+	vcluster0 := newMember("vcluster-0")
+	vcluster1 := newMember("vcluster-1")
+
+	// owners returns the number of members that own each namespace and fails if a namespace is owned twice
+	owners := func(members ...*shardMember) map[string]int {
+		owned := map[string]int{}
+		for _, member := range members {
+			for _, namespace := range namespaces {
+				if member.config.OwnsShard("pod", namespace) {
+					owned[namespace]++
+				}
+			}
+		}
+		for namespace, count := range owned {
+			assert.Assert(t, count == 1, "namespace %s is owned by %d members", namespace, count)
+		}
+
+		return owned
+	}
+	assign := func() {
+		assert.NilError(t, assignShards(ctx, kubeClient, "test", time.Now()))
+		owners(vcluster0, vcluster1)
+	}
+	sync := func(member *shardMember) {
+		member.sync(ctx)
+		owners(vcluster0, vcluster1)
+	}
+
+	// the first member owns all namespaces
+	sync(vcluster0)
+	assign()
+	sync(vcluster0)
+	assert.Equal(t, len(owners(vcluster0)), len(namespaces))
+
+	// a joining member only gets a shard after the previous owner dropped its shard
+	sync(vcluster1)
+	assign()
+	assert.Equal(t, len(owners(vcluster0)), len(namespaces))
+	sync(vcluster0)
+	assert.Equal(t, len(owners(vcluster0)), 0)
+	assign()
+	assignment, err := readShardAssignment(ctx, kubeClient, "test")
+	assert.NilError(t, err)
+	assert.Equal(t, len(assignment.Members), 0, "assigned before vcluster-1 applied the revocation")
+	sync(vcluster1)
+	assign()
+	sync(vcluster1)
+	sync(vcluster0)
+	assert.Equal(t, len(owners(vcluster0, vcluster1)), len(namespaces))
+	assert.Assert(t, len(owners(vcluster0)) > 0)
+	assert.Assert(t, len(owners(vcluster1)) > 0)
+
+	// a released member drops its shard right away and its shard is reassigned without waiting for the lease
+	assert.NilError(t, vcluster0.release(ctx))
+	assert.Equal(t, len(owners(vcluster0)), 0)
+	assign()
+	sync(vcluster1)
+	assign()
+	sync(vcluster1)
+	assert.Equal(t, len(owners(vcluster1)), len(namespaces))
+}
@@ -0,0 +1,99 @@
+package leaderelection
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const identitySuffix = "-external-vcluster-controller"
+
+var (
+	// StaleLeasesCleared counts the leases that were released because their holder was gone
+	StaleLeasesCleared = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "vcluster_leader_election_stale_leases_cleared_total",
+		Help: "Number of leader election leases released because the holding pod no longer existed.",
+	})
+
+	// RecoverySeconds is the time it took this instance from starting the leader election until it acquired leadership
+	RecoverySeconds = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "vcluster_leader_election_recovery_seconds",
+		Help: "Seconds between starting the leader election and acquiring leadership.",
+	})
+)
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(StaleLeasesCleared, RecoverySeconds)
+}
+
+// CleanupStaleLease releases the lease if it is held by a pod of a previous instance that does not exist anymore
+// or has already stopped. This avoids waiting for the full lease duration after a control plane crash. The lease
+// is updated with its observed resource version, so a concurrent renewal by a live holder always wins.
+func CleanupStaleLease(ctx context.Context, kubeClient kubernetes.Interface, namespace, name, identity string) (bool, error) {
+	lease, err := kubeClient.CoordinationV1().Leases(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return false, nil
+		}
+
+		return false, fmt.Errorf("get lease: %w", err)
+	}
+
+	holder := ""
+	if lease.Spec.HolderIdentity != nil {
+		holder = *lease.Spec.HolderIdentity
+	}
+	if holder == "" || holder == identity || !strings.HasSuffix(holder, identitySuffix) {
+		// we either hold the lease already, nobody holds it or it's not managed by us
+		return false, nil
+	}
+
+	stale, err := isHolderGone(ctx, kubeClient, namespace, strings.TrimSuffix(holder, identitySuffix))
+	if err != nil || !stale {
+		return false, err
+	}
+
+	klog.Infof("Releasing stale leader election lease %s/%s held by %s", namespace, name, holder)
+	releaseLease(lease)
+	_, err = kubeClient.CoordinationV1().Leases(namespace).Update(ctx, lease, metav1.UpdateOptions{})
+	if err != nil {
+		if kerrors.IsConflict(err) {
+			// somebody else updated the lease in the meantime, so it's not stale
+			return false, nil
+		}
+
+		return false, fmt.Errorf("release lease: %w", err)
+	}
+
+	StaleLeasesCleared.Inc()
+	return true, nil
+}
+
+func isHolderGone(ctx context.Context, kubeClient kubernetes.Interface, namespace, podName string) (bool, error) {
+	pod, err := kubeClient.CoreV1().Pods(namespace).Get(ctx, podName, metav1.GetOptions{})
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return true, nil
+		}
+
+		return false, fmt.Errorf("get lease holder pod: %w", err)
+	}
+
+	// a terminating pod might still be leading, so we only consider pods that are gone or stopped
+	return pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed, nil
+}
+
+func releaseLease(lease *coordinationv1.Lease) {
+	lease.Spec.HolderIdentity = nil
+	lease.Spec.AcquireTime = nil
+	lease.Spec.RenewTime = nil
+}
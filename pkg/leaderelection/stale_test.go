@@ -0,0 +1,77 @@
+package leaderelection
+
+import (
+	"context"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/ptr"
+)
+
+func TestCleanupStaleLease(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		holder string
+		pods   []runtime.Object
+
+		expectedCleared bool
+	}{
+		{
+			name:            "holder pod gone",
+			holder:          "vcluster-1" + identitySuffix,
+			expectedCleared: true,
+		},
+		{
+			name:   "holder pod running",
+			holder: "vcluster-1" + identitySuffix,
+			pods: []runtime.Object{&corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "vcluster-1", Namespace: "test"},
+				Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+			}},
+		},
+		{
+			name:   "holder pod failed",
+			holder: "vcluster-1" + identitySuffix,
+			pods: []runtime.Object{&corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "vcluster-1", Namespace: "test"},
+				Status:     corev1.PodStatus{Phase: corev1.PodFailed},
+			}},
+			expectedCleared: true,
+		},
+		{
+			name:   "own lease",
+			holder: "vcluster-0" + identitySuffix,
+		},
+		{
+			name:   "foreign lease",
+			holder: "other-holder",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			lease := &coordinationv1.Lease{
+				ObjectMeta: metav1.ObjectMeta{Name: "lease", Namespace: "test"},
+				Spec: coordinationv1.LeaseSpec{
+					HolderIdentity: ptr.To(testCase.holder),
+					RenewTime:      &metav1.MicroTime{},
+				},
+			}
+			kubeClient := fake.NewSimpleClientset(append(testCase.pods, lease)...)
+
+			cleared, err := CleanupStaleLease(context.Background(), kubeClient, "test", "lease", "vcluster-0"+identitySuffix)
+			assert.NilError(t, err)
+			assert.Equal(t, cleared, testCase.expectedCleared)
+
+			lease, err = kubeClient.CoordinationV1().Leases("test").Get(context.Background(), "lease", metav1.GetOptions{})
+			assert.NilError(t, err)
+			assert.Equal(t, lease.Spec.HolderIdentity == nil, testCase.expectedCleared)
+		})
+	}
+}
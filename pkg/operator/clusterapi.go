@@ -0,0 +1,279 @@
+package operator
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/loft-sh/vcluster/pkg/util/kubeconfig"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// In ClusterAPI mode the VCluster object acts as infrastructure and control plane provider of a ClusterAPI Cluster.
+// A VCluster that is referenced by a Cluster consumes its lifecycle, e.g. pausing, and a VCluster without a Cluster
+// emits one, so it shows up in ClusterAPI based fleets.
+const (
+	ClusterAPIGroup = "cluster.x-k8s.io"
+
+	// ClusterNameLabel is set by ClusterAPI on all objects that belong to a Cluster
+	ClusterNameLabel = "cluster.x-k8s.io/cluster-name"
+
+	// ClusterPausedAnnotation pauses the reconciliation of all objects of a Cluster
+	ClusterPausedAnnotation = "cluster.x-k8s.io/paused"
+
+	// ClusterSecretType is the type of secrets ClusterAPI reads the kubeconfig of a Cluster from
+	ClusterSecretType corev1.SecretType = "cluster.x-k8s.io/secret"
+
+	// controlPlanePort is the port of the vcluster service
+	controlPlanePort = 443
+)
+
+// ClusterGroupVersionKind of the ClusterAPI Cluster
+var ClusterGroupVersionKind = schema.GroupVersionKind{Group: ClusterAPIGroup, Version: "v1beta1", Kind: "Cluster"}
+
+// watchClusters requeues the VCluster objects referenced by a changed ClusterAPI Cluster
+func watchClusters(builder *ctrl.Builder) *ctrl.Builder {
+	cluster := &unstructured.Unstructured{}
+	cluster.SetGroupVersionKind(ClusterGroupVersionKind)
+	return builder.Watches(cluster, handler.EnqueueRequestsFromMapFunc(func(_ context.Context, obj client.Object) []reconcile.Request {
+		cluster, ok := obj.(*unstructured.Unstructured)
+		if !ok {
+			return nil
+		}
+
+		requests := []reconcile.Request{}
+		for _, field := range []string{"infrastructureRef", "controlPlaneRef"} {
+			ref, _, _ := unstructured.NestedStringMap(cluster.Object, "spec", field)
+			if isVClusterRef(ref) {
+				namespace := ref["namespace"]
+				if namespace == "" {
+					namespace = cluster.GetNamespace()
+				}
+
+				requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: namespace, Name: ref["name"]}})
+			}
+		}
+
+		return requests
+	}))
+}
+
+func isVClusterRef(ref map[string]string) bool {
+	return ref["kind"] == Kind && ref["apiVersion"] == GroupVersionKind.GroupVersion().String() && ref["name"] != ""
+}
+
+// clusterAPIPaused returns true if the Cluster owning the VCluster is paused
+func (r *Reconciler) clusterAPIPaused(ctx context.Context, vCluster *unstructured.Unstructured) (bool, error) {
+	if _, ok := vCluster.GetAnnotations()[ClusterPausedAnnotation]; ok {
+		return true, nil
+	}
+
+	cluster, err := r.owningCluster(ctx, vCluster)
+	if err != nil || cluster == nil {
+		return false, err
+	}
+
+	paused, _, _ := unstructured.NestedBool(cluster.Object, "spec", "paused")
+	return paused, nil
+}
+
+// owningCluster returns the ClusterAPI Cluster that owns the VCluster or nil if there is none yet
+func (r *Reconciler) owningCluster(ctx context.Context, vCluster *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	for _, owner := range vCluster.GetOwnerReferences() {
+		groupVersion, err := schema.ParseGroupVersion(owner.APIVersion)
+		if err != nil || groupVersion.Group != ClusterAPIGroup || owner.Kind != ClusterGroupVersionKind.Kind {
+			continue
+		}
+
+		cluster := &unstructured.Unstructured{}
+		cluster.SetGroupVersionKind(ClusterGroupVersionKind)
+		err = r.Client.Get(ctx, types.NamespacedName{Namespace: vCluster.GetNamespace(), Name: owner.Name}, cluster)
+		if err != nil {
+			if kerrors.IsNotFound(err) {
+				return nil, nil
+			}
+
+			return nil, fmt.Errorf("get cluster: %w", err)
+		}
+
+		return cluster, nil
+	}
+
+	return nil, nil
+}
+
+// reconcileClusterAPI fulfills the ClusterAPI provider contract for a deployed VCluster. It publishes the control
+// plane endpoint and the kubeconfig secret of the Cluster and marks the VCluster as ready once the virtual cluster
+// kubeconfig is available.
+func (r *Reconciler) reconcileClusterAPI(ctx context.Context, vCluster *unstructured.Unstructured) (ctrl.Result, error) {
+	cluster, err := r.owningCluster(ctx, vCluster)
+	if err != nil {
+		return ctrl.Result{}, err
+	} else if cluster == nil {
+		// ClusterAPI sets the owner reference after the Cluster was created, so we only emit a Cluster once
+		err = r.ensureCluster(ctx, vCluster)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{RequeueAfter: 5 * time.Second}, nil
+	}
+
+	host := fmt.Sprintf("%s.%s.svc", vCluster.GetName(), vCluster.GetNamespace())
+	endpointHost, _, _ := unstructured.NestedString(vCluster.Object, "spec", "controlPlaneEndpoint", "host")
+	if endpointHost == "" {
+		err = unstructured.SetNestedMap(vCluster.Object, map[string]interface{}{
+			"host": host,
+			"port": int64(controlPlanePort),
+		}, "spec", "controlPlaneEndpoint")
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+
+		err = r.Client.Update(ctx, vCluster)
+		if err != nil {
+			return ctrl.Result{}, fmt.Errorf("set control plane endpoint: %w", err)
+		}
+	}
+
+	// the kubeconfig secret is written by the virtual cluster once its control plane is up
+	vClusterSecret := &corev1.Secret{}
+	err = r.Client.Get(ctx, types.NamespacedName{Namespace: vCluster.GetNamespace(), Name: kubeconfig.GetDefaultSecretName(vCluster.GetName())}, vClusterSecret)
+	if kerrors.IsNotFound(err) || (err == nil && len(vClusterSecret.Data[kubeconfig.KubeconfigSecretKey]) == 0) {
+		return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+	} else if err != nil {
+		return ctrl.Result{}, fmt.Errorf("get vcluster kubeconfig: %w", err)
+	}
+
+	clusterKubeConfig, err := clusterAPIKubeConfig(vClusterSecret.Data[kubeconfig.KubeconfigSecretKey], cluster.GetName(), "https://"+host+fmt.Sprintf(":%d", controlPlanePort))
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	err = r.ensureKubeConfigSecret(ctx, cluster, clusterKubeConfig)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	ready, _, _ := unstructured.NestedBool(vCluster.Object, "status", "ready")
+	if !ready {
+		phase, _, _ := unstructured.NestedString(vCluster.Object, "status", "phase")
+		err = r.setStatus(ctx, vCluster, Phase(phase), "", map[string]interface{}{
+			"ready":       true,
+			"initialized": true,
+		})
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// ensureCluster creates a ClusterAPI Cluster that uses the VCluster as infrastructure and control plane provider
+func (r *Reconciler) ensureCluster(ctx context.Context, vCluster *unstructured.Unstructured) error {
+	ref := map[string]interface{}{
+		"apiVersion": GroupVersionKind.GroupVersion().String(),
+		"kind":       Kind,
+		"name":       vCluster.GetName(),
+		"namespace":  vCluster.GetNamespace(),
+	}
+
+	cluster := &unstructured.Unstructured{}
+	cluster.SetGroupVersionKind(ClusterGroupVersionKind)
+	err := r.Client.Get(ctx, client.ObjectKeyFromObject(vCluster), cluster)
+	if err == nil {
+		infrastructureRef, _, _ := unstructured.NestedStringMap(cluster.Object, "spec", "infrastructureRef")
+		controlPlaneRef, _, _ := unstructured.NestedStringMap(cluster.Object, "spec", "controlPlaneRef")
+		if !isVClusterRef(infrastructureRef) && !isVClusterRef(controlPlaneRef) {
+			return fmt.Errorf("cluster %s/%s already exists and doesn't reference vcluster %s", cluster.GetNamespace(), cluster.GetName(), vCluster.GetName())
+		}
+
+		return nil
+	} else if !kerrors.IsNotFound(err) {
+		return fmt.Errorf("get cluster: %w", err)
+	}
+
+	cluster.SetName(vCluster.GetName())
+	cluster.SetNamespace(vCluster.GetNamespace())
+	err = unstructured.SetNestedMap(cluster.Object, map[string]interface{}{
+		"infrastructureRef": ref,
+		"controlPlaneRef":   ref,
+	}, "spec")
+	if err != nil {
+		return err
+	}
+
+	r.Log.Infof("Creating ClusterAPI cluster for vcluster %s/%s", vCluster.GetNamespace(), vCluster.GetName())
+	err = r.Client.Create(ctx, cluster)
+	if err != nil {
+		return fmt.Errorf("create cluster: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Reconciler) ensureKubeConfigSecret(ctx context.Context, cluster *unstructured.Unstructured, kubeConfig []byte) error {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      cluster.GetName() + "-kubeconfig",
+			Namespace: cluster.GetNamespace(),
+		},
+	}
+	_, err := controllerutil.CreateOrPatch(ctx, r.Client, secret, func() error {
+		if secret.Labels == nil {
+			secret.Labels = map[string]string{}
+		}
+		secret.Labels[ClusterNameLabel] = cluster.GetName()
+		secret.Type = ClusterSecretType
+		secret.Data = map[string][]byte{"value": kubeConfig}
+		secret.OwnerReferences = []metav1.OwnerReference{{
+			APIVersion: cluster.GetAPIVersion(),
+			Kind:       cluster.GetKind(),
+			Name:       cluster.GetName(),
+			UID:        cluster.GetUID(),
+			Controller: ptr.To(true),
+		}}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("ensure cluster kubeconfig secret: %w", err)
+	}
+
+	return nil
+}
+
+// clusterAPIKubeConfig rewrites the virtual cluster kubeconfig to use the in-cluster service endpoint
+func clusterAPIKubeConfig(raw []byte, clusterName, server string) ([]byte, error) {
+	config, err := clientcmd.Load(raw)
+	if err != nil {
+		return nil, fmt.Errorf("parse vcluster kubeconfig: %w", err)
+	}
+
+	for _, cluster := range config.Clusters {
+		cluster.Server = server
+	}
+
+	// ClusterAPI expects the context to be named after the Cluster
+	currentContext, ok := config.Contexts[config.CurrentContext]
+	if !ok {
+		return nil, fmt.Errorf("vcluster kubeconfig has no current context")
+	}
+	delete(config.Contexts, config.CurrentContext)
+	config.Contexts[clusterName] = currentContext
+	config.CurrentContext = clusterName
+
+	return clientcmd.Write(*config)
+}
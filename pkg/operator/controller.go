@@ -0,0 +1,237 @@
+package operator
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/loft-sh/vcluster/config"
+	"github.com/loft-sh/vcluster/pkg/constants"
+	"github.com/loft-sh/vcluster/pkg/helm"
+	"github.com/loft-sh/vcluster/pkg/upgrade"
+	"github.com/loft-sh/vcluster/pkg/util/loghelper"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+type Phase string
+
+const (
+	PhaseDeploying Phase = "Deploying"
+	PhaseDeployed  Phase = "Deployed"
+	PhaseFailed    Phase = "Failed"
+	PhaseDeleting  Phase = "Deleting"
+
+	retryInterval = time.Minute
+)
+
+// Reconciler deploys, upgrades and deletes the helm release of each VCluster object
+type Reconciler struct {
+	Client     client.Client
+	HelmClient helm.Client
+
+	// ClusterAPI enables the ClusterAPI provider mode, see clusterapi.go
+	ClusterAPI bool
+
+	Log loghelper.Logger
+}
+
+// SetupWithManager adds the controller to the manager
+func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(GroupVersionKind)
+
+	// status updates shouldn't trigger another helm upgrade
+	controllerBuilder := ctrl.NewControllerManagedBy(mgr).
+		Named("vcluster-operator").
+		For(obj, builder.WithPredicates(predicate.Or(predicate.GenerationChangedPredicate{}, predicate.AnnotationChangedPredicate{})))
+	if r.ClusterAPI {
+		controllerBuilder = watchClusters(controllerBuilder)
+	}
+
+	return controllerBuilder.Complete(r)
+}
+
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	vCluster := &unstructured.Unstructured{}
+	vCluster.SetGroupVersionKind(GroupVersionKind)
+	err := r.Client.Get(ctx, req.NamespacedName, vCluster)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+
+		return ctrl.Result{}, err
+	}
+
+	if vCluster.GetDeletionTimestamp() != nil {
+		return ctrl.Result{}, r.delete(ctx, vCluster)
+	}
+
+	if !controllerutil.ContainsFinalizer(vCluster, Finalizer) {
+		controllerutil.AddFinalizer(vCluster, Finalizer)
+		err = r.Client.Update(ctx, vCluster)
+		if err != nil {
+			return ctrl.Result{}, fmt.Errorf("add finalizer: %w", err)
+		}
+	}
+
+	if r.ClusterAPI {
+		paused, err := r.clusterAPIPaused(ctx, vCluster)
+		if err != nil {
+			return ctrl.Result{}, err
+		} else if paused {
+			r.Log.Debugf("Cluster of vcluster %s/%s is paused, skipping reconcile", vCluster.GetNamespace(), vCluster.GetName())
+			return ctrl.Result{}, nil
+		}
+	}
+
+	options, err := chartOptions(vCluster)
+	if err != nil {
+		// retrying won't help until the spec changes
+		return ctrl.Result{}, r.setStatus(ctx, vCluster, PhaseFailed, err.Error(), nil)
+	}
+
+	// only deploy if the chart or values changed since the last successful deployment, other spec changes such as
+	// the control plane endpoint don't require a helm upgrade
+	hash, err := optionsHash(options)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	appliedHash, _, _ := unstructured.NestedString(vCluster.Object, "status", "appliedHash")
+	phase, _, _ := unstructured.NestedString(vCluster.Object, "status", "phase")
+	if appliedHash != hash || phase != string(PhaseDeployed) {
+		err = r.setStatus(ctx, vCluster, PhaseDeploying, "", nil)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+
+		r.Log.Infof("Deploying vcluster %s/%s with chart %s version %s", vCluster.GetNamespace(), vCluster.GetName(), options.Chart, options.Version)
+		err = r.HelmClient.Upgrade(ctx, vCluster.GetName(), vCluster.GetNamespace(), *options)
+		if err != nil {
+			r.Log.Errorf("Error deploying vcluster %s/%s: %v", vCluster.GetNamespace(), vCluster.GetName(), err)
+			return ctrl.Result{RequeueAfter: retryInterval}, r.setStatus(ctx, vCluster, PhaseFailed, err.Error(), nil)
+		}
+
+		err = r.setStatus(ctx, vCluster, PhaseDeployed, "", map[string]interface{}{
+			"chartVersion": options.Version,
+			"appliedHash":  hash,
+		})
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	if r.ClusterAPI {
+		return r.reconcileClusterAPI(ctx, vCluster)
+	}
+
+	return ctrl.Result{}, nil
+}
+
+func (r *Reconciler) delete(ctx context.Context, vCluster *unstructured.Unstructured) error {
+	if !controllerutil.ContainsFinalizer(vCluster, Finalizer) {
+		return nil
+	}
+
+	err := r.setStatus(ctx, vCluster, PhaseDeleting, "", nil)
+	if err != nil {
+		return err
+	}
+
+	exists, err := r.HelmClient.Exists(vCluster.GetName(), vCluster.GetNamespace())
+	if err != nil {
+		return fmt.Errorf("check helm release: %w", err)
+	} else if exists {
+		r.Log.Infof("Deleting vcluster %s/%s", vCluster.GetNamespace(), vCluster.GetName())
+		err = r.HelmClient.Delete(vCluster.GetName(), vCluster.GetNamespace())
+		if err != nil {
+			return fmt.Errorf("delete helm release: %w", err)
+		}
+	}
+
+	controllerutil.RemoveFinalizer(vCluster, Finalizer)
+	return r.Client.Update(ctx, vCluster)
+}
+
+// setStatus updates the phase and the given fields of the status, other status fields are kept
+func (r *Reconciler) setStatus(ctx context.Context, vCluster *unstructured.Unstructured, phase Phase, message string, fields map[string]interface{}) error {
+	status, _, _ := unstructured.NestedMap(vCluster.Object, "status")
+	if status == nil {
+		status = map[string]interface{}{}
+	}
+	status["phase"] = string(phase)
+	status["observedGeneration"] = vCluster.GetGeneration()
+	if message != "" {
+		status["message"] = message
+	} else {
+		delete(status, "message")
+	}
+	for key, value := range fields {
+		status[key] = value
+	}
+
+	err := unstructured.SetNestedMap(vCluster.Object, status, "status")
+	if err != nil {
+		return err
+	}
+
+	err = r.Client.Status().Update(ctx, vCluster)
+	if err != nil {
+		return fmt.Errorf("update status: %w", err)
+	}
+
+	return nil
+}
+
+// optionsHash returns a hash of everything that requires a helm upgrade if changed
+func optionsHash(options *helm.UpgradeOptions) (string, error) {
+	out, err := json.Marshal(options)
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.Sum256(out)
+	return hex.EncodeToString(hash[:]), nil
+}
+
+// chartOptions builds the helm options from the spec and validates the vcluster.yaml
+func chartOptions(vCluster *unstructured.Unstructured) (*helm.UpgradeOptions, error) {
+	options := &helm.UpgradeOptions{
+		Chart:   "vcluster",
+		Repo:    constants.LoftChartRepo,
+		Version: upgrade.GetVersion(),
+	}
+	if name, _, _ := unstructured.NestedString(vCluster.Object, "spec", "chart", "name"); name != "" {
+		options.Chart = name
+	}
+	if repo, _, _ := unstructured.NestedString(vCluster.Object, "spec", "chart", "repo"); repo != "" {
+		options.Repo = repo
+	}
+	if version, _, _ := unstructured.NestedString(vCluster.Object, "spec", "chart", "version"); version != "" {
+		options.Version = version
+	}
+	if options.Version == upgrade.DevelopmentVersion {
+		options.Version = ""
+	}
+
+	values, _, _ := unstructured.NestedString(vCluster.Object, "spec", "values")
+	if values != "" {
+		vClusterConfig := &config.Config{}
+		err := vClusterConfig.UnmarshalYAMLStrict([]byte(values))
+		if err != nil {
+			return nil, fmt.Errorf("invalid spec.values: %w", err)
+		}
+	}
+	options.Values = values
+
+	return options, nil
+}
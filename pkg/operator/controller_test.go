@@ -0,0 +1,168 @@
+package operator
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/loft-sh/vcluster/pkg/helm"
+	"github.com/loft-sh/vcluster/pkg/util/loghelper"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+type fakeHelmClient struct {
+	helm.Client
+
+	releases map[string]helm.UpgradeOptions
+}
+
+func (f *fakeHelmClient) Upgrade(_ context.Context, name, namespace string, options helm.UpgradeOptions) error {
+	f.releases[namespace+"/"+name] = options
+	return nil
+}
+
+func (f *fakeHelmClient) Exists(name, namespace string) (bool, error) {
+	_, ok := f.releases[namespace+"/"+name]
+	return ok, nil
+}
+
+func (f *fakeHelmClient) Delete(name, namespace string) error {
+	delete(f.releases, namespace+"/"+name)
+	return nil
+}
+
+func newVCluster(values string) *unstructured.Unstructured {
+	vCluster := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"chart":  map[string]interface{}{"version": "0.20.0"},
+			"values": values,
+		},
+	}}
+	vCluster.SetGroupVersionKind(GroupVersionKind)
+	vCluster.SetName("my-vcluster")
+	vCluster.SetNamespace("team-a")
+	vCluster.SetGeneration(1)
+	return vCluster
+}
+
+func TestReconcile(t *testing.T) {
+	ctx := context.Background()
+	vCluster := newVCluster("sync:\n  toHost:\n    ingresses:\n      enabled: true\n")
+	kubeClient := fake.NewClientBuilder().WithObjects(vCluster).WithStatusSubresource(vCluster).Build()
+	helmClient := &fakeHelmClient{releases: map[string]helm.UpgradeOptions{}}
+	reconciler := &Reconciler{Client: kubeClient, HelmClient: helmClient, Log: loghelper.New("test")}
+	request := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "team-a", Name: "my-vcluster"}}
+
+	_, err := reconciler.Reconcile(ctx, request)
+	assert.NilError(t, err)
+	assert.Equal(t, helmClient.releases["team-a/my-vcluster"].Version, "0.20.0")
+	assert.Equal(t, helmClient.releases["team-a/my-vcluster"].Chart, "vcluster")
+
+	current := newVCluster("")
+	assert.NilError(t, kubeClient.Get(ctx, request.NamespacedName, current))
+	phase, _, _ := unstructured.NestedString(current.Object, "status", "phase")
+	assert.Equal(t, phase, string(PhaseDeployed))
+	assert.DeepEqual(t, current.GetFinalizers(), []string{Finalizer})
+
+	// deleting the object removes the release and the finalizer
+	assert.NilError(t, kubeClient.Delete(ctx, current))
+	_, err = reconciler.Reconcile(ctx, request)
+	assert.NilError(t, err)
+	_, ok := helmClient.releases["team-a/my-vcluster"]
+	assert.Assert(t, !ok)
+	err = kubeClient.Get(ctx, request.NamespacedName, newVCluster(""))
+	assert.ErrorContains(t, err, "not found")
+}
+
+func TestReconcileInvalidValues(t *testing.T) {
+	ctx := context.Background()
+	vCluster := newVCluster("sync:\n  unknown: true\n")
+	kubeClient := fake.NewClientBuilder().WithObjects(vCluster).WithStatusSubresource(vCluster).Build()
+	helmClient := &fakeHelmClient{releases: map[string]helm.UpgradeOptions{}}
+	reconciler := &Reconciler{Client: kubeClient, HelmClient: helmClient, Log: loghelper.New("test")}
+
+	_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "team-a", Name: "my-vcluster"}})
+	assert.NilError(t, err)
+	assert.Equal(t, len(helmClient.releases), 0)
+
+	current := newVCluster("")
+	assert.NilError(t, kubeClient.Get(ctx, types.NamespacedName{Namespace: "team-a", Name: "my-vcluster"}, current))
+	phase, _, _ := unstructured.NestedString(current.Object, "status", "phase")
+	assert.Equal(t, phase, string(PhaseFailed))
+	message, _, _ := unstructured.NestedString(current.Object, "status", "message")
+	assert.Assert(t, strings.HasPrefix(message, "invalid spec.values"), message)
+}
+
+func TestReconcileClusterAPI(t *testing.T) {
+	ctx := context.Background()
+	vCluster := newVCluster("")
+	kubeClient := fake.NewClientBuilder().WithObjects(vCluster).WithStatusSubresource(vCluster).Build()
+	helmClient := &fakeHelmClient{releases: map[string]helm.UpgradeOptions{}}
+	reconciler := &Reconciler{Client: kubeClient, HelmClient: helmClient, ClusterAPI: true, Log: loghelper.New("test")}
+	request := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "team-a", Name: "my-vcluster"}}
+
+	// a vcluster without a Cluster emits one
+	_, err := reconciler.Reconcile(ctx, request)
+	assert.NilError(t, err)
+	cluster := &unstructured.Unstructured{}
+	cluster.SetGroupVersionKind(ClusterGroupVersionKind)
+	assert.NilError(t, kubeClient.Get(ctx, request.NamespacedName, cluster))
+	infrastructureRef, _, _ := unstructured.NestedStringMap(cluster.Object, "spec", "infrastructureRef")
+	assert.Equal(t, infrastructureRef["name"], "my-vcluster")
+	assert.Equal(t, infrastructureRef["kind"], Kind)
+
+	// ClusterAPI adopts the vcluster and the kubeconfig becomes available
+	current := newVCluster("")
+	assert.NilError(t, kubeClient.Get(ctx, request.NamespacedName, current))
+	current.SetOwnerReferences([]metav1.OwnerReference{{APIVersion: ClusterGroupVersionKind.GroupVersion().String(), Kind: "Cluster", Name: "my-vcluster"}})
+	assert.NilError(t, kubeClient.Update(ctx, current))
+	assert.NilError(t, kubeClient.Create(ctx, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "vc-my-vcluster", Namespace: "team-a"},
+		Data:       map[string][]byte{"config": []byte(testKubeConfig)},
+	}))
+
+	_, err = reconciler.Reconcile(ctx, request)
+	assert.NilError(t, err)
+	secret := &corev1.Secret{}
+	assert.NilError(t, kubeClient.Get(ctx, types.NamespacedName{Namespace: "team-a", Name: "my-vcluster-kubeconfig"}, secret))
+	assert.Equal(t, secret.Type, ClusterSecretType)
+	assert.Equal(t, secret.Labels[ClusterNameLabel], "my-vcluster")
+	assert.Assert(t, strings.Contains(string(secret.Data["value"]), "https://my-vcluster.team-a.svc:443"))
+
+	assert.NilError(t, kubeClient.Get(ctx, request.NamespacedName, current))
+	ready, _, _ := unstructured.NestedBool(current.Object, "status", "ready")
+	assert.Assert(t, ready)
+	host, _, _ := unstructured.NestedString(current.Object, "spec", "controlPlaneEndpoint", "host")
+	assert.Equal(t, host, "my-vcluster.team-a.svc")
+
+	// a paused Cluster stops the reconciliation
+	assert.NilError(t, unstructured.SetNestedField(cluster.Object, true, "spec", "paused"))
+	assert.NilError(t, kubeClient.Update(ctx, cluster))
+	paused, err := reconciler.clusterAPIPaused(ctx, current)
+	assert.NilError(t, err)
+	assert.Assert(t, paused)
+}
+
+const testKubeConfig = `apiVersion: v1
+kind: Config
+clusters:
+- cluster:
+    server: https://localhost:8443
+  name: my-vcluster
+contexts:
+- context:
+    cluster: my-vcluster
+    user: my-vcluster
+  name: my-vcluster
+current-context: my-vcluster
+users:
+- name: my-vcluster
+  user:
+    token: test
+`
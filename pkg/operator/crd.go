@@ -0,0 +1,129 @@
+package operator
+
+import (
+	"context"
+	"fmt"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	Group   = "operator.vcluster.loft.sh"
+	Version = "v1alpha1"
+	Kind    = "VCluster"
+
+	// Finalizer makes sure the helm release is deleted before the VCluster object is gone
+	Finalizer = "operator.vcluster.loft.sh/helm-release"
+)
+
+// GroupVersionKind of the VCluster custom resource
+var GroupVersionKind = schema.GroupVersionKind{Group: Group, Version: Version, Kind: Kind}
+
+// CRD returns the definition of the VCluster custom resource. A VCluster object is reconciled into a helm release with
+// the same name and namespace.
+func CRD() *apiextensionsv1.CustomResourceDefinition {
+	stringProperty := func(description string) apiextensionsv1.JSONSchemaProps {
+		return apiextensionsv1.JSONSchemaProps{Type: "string", Description: description}
+	}
+
+	return &apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "vclusters." + Group,
+			// tells ClusterAPI which version to use for references to VCluster objects
+			Labels: map[string]string{
+				ClusterAPIGroup + "/" + ClusterGroupVersionKind.Version: Version,
+			},
+		},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Group: Group,
+			Names: apiextensionsv1.CustomResourceDefinitionNames{
+				Plural:   "vclusters",
+				Singular: "vcluster",
+				Kind:     Kind,
+				ListKind: Kind + "List",
+			},
+			Scope: apiextensionsv1.NamespaceScoped,
+			Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{
+				Name:    Version,
+				Served:  true,
+				Storage: true,
+				Subresources: &apiextensionsv1.CustomResourceSubresources{
+					Status: &apiextensionsv1.CustomResourceSubresourceStatus{},
+				},
+				AdditionalPrinterColumns: []apiextensionsv1.CustomResourceColumnDefinition{
+					{Name: "Phase", Type: "string", JSONPath: ".status.phase"},
+					{Name: "Chart Version", Type: "string", JSONPath: ".status.chartVersion"},
+					{Name: "Age", Type: "date", JSONPath: ".metadata.creationTimestamp"},
+				},
+				Schema: &apiextensionsv1.CustomResourceValidation{
+					OpenAPIV3Schema: &apiextensionsv1.JSONSchemaProps{
+						Type: "object",
+						Properties: map[string]apiextensionsv1.JSONSchemaProps{
+							"apiVersion": {Type: "string"},
+							"kind":       {Type: "string"},
+							"metadata":   {Type: "object"},
+							"spec": {
+								Type: "object",
+								Properties: map[string]apiextensionsv1.JSONSchemaProps{
+									"chart": {
+										Type: "object",
+										Properties: map[string]apiextensionsv1.JSONSchemaProps{
+											"name":    stringProperty("Name of the vcluster chart, defaults to vcluster."),
+											"repo":    stringProperty("Repo of the vcluster chart, defaults to the vcluster chart repository."),
+											"version": stringProperty("Version of the vcluster chart, defaults to the version of the operator."),
+										},
+									},
+									"values": stringProperty("Values is the vcluster.yaml of the virtual cluster."),
+									"controlPlaneEndpoint": {
+										Type:        "object",
+										Description: "ControlPlaneEndpoint is set by the operator in ClusterAPI mode.",
+										Properties: map[string]apiextensionsv1.JSONSchemaProps{
+											"host": {Type: "string"},
+											"port": {Type: "integer"},
+										},
+									},
+								},
+							},
+							"status": {
+								Type:                   "object",
+								XPreserveUnknownFields: ptr.To(true),
+							},
+						},
+					},
+				},
+			}},
+		},
+	}
+}
+
+// EnsureCRD creates or updates the VCluster custom resource definition
+func EnsureCRD(ctx context.Context, kubeClient client.Client) error {
+	crd := CRD()
+	existing := &apiextensionsv1.CustomResourceDefinition{}
+	err := kubeClient.Get(ctx, client.ObjectKeyFromObject(crd), existing)
+	if err != nil {
+		if !kerrors.IsNotFound(err) {
+			return fmt.Errorf("get crd: %w", err)
+		}
+
+		err = kubeClient.Create(ctx, crd)
+		if err != nil {
+			return fmt.Errorf("create crd: %w", err)
+		}
+
+		return nil
+	}
+
+	existing.Spec = crd.Spec
+	err = kubeClient.Update(ctx, existing)
+	if err != nil {
+		return fmt.Errorf("update crd: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,99 @@
+package operator
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+)
+
+// Name is the name of the operator deployment and its service account and cluster role binding
+const Name = "vcluster-operator"
+
+// ManifestOptions configure the manifests that install the operator
+type ManifestOptions struct {
+	// Namespace is the namespace the operator is installed into
+	Namespace string
+
+	// Image is an image that contains the vcluster cli, such as ghcr.io/loft-sh/vcluster-cli
+	Image string
+
+	// Replicas of the operator, leader election is enabled if there is more than one replica
+	Replicas int32
+
+	// WatchNamespace restricts the operator to VCluster objects within this namespace, all namespaces are watched if empty
+	WatchNamespace string
+
+	ClusterAPI bool
+}
+
+// Manifests returns the objects that install the operator. The operator deploys the vcluster chart, which creates
+// roles, cluster roles and bindings of its own, so its service account is bound to cluster-admin. Kubernetes only lets
+// a service account grant permissions it holds itself.
+func Manifests(options *ManifestOptions) []runtime.Object {
+	labels := map[string]string{"app": Name}
+	args := []string{"operator", "start", "--metrics-bind-address", "0"}
+	if options.Replicas > 1 {
+		args = append(args, "--leader-elect")
+	}
+	if options.WatchNamespace != "" {
+		args = append(args, "--namespace", options.WatchNamespace)
+	}
+	if options.ClusterAPI {
+		args = append(args, "--cluster-api")
+	}
+
+	return []runtime.Object{
+		&corev1.Namespace{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Namespace"},
+			ObjectMeta: metav1.ObjectMeta{Name: options.Namespace},
+		},
+		&corev1.ServiceAccount{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ServiceAccount"},
+			ObjectMeta: metav1.ObjectMeta{Name: Name, Namespace: options.Namespace, Labels: labels},
+		},
+		&rbacv1.ClusterRoleBinding{
+			TypeMeta:   metav1.TypeMeta{APIVersion: rbacv1.SchemeGroupVersion.String(), Kind: "ClusterRoleBinding"},
+			ObjectMeta: metav1.ObjectMeta{Name: Name, Labels: labels},
+			RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: "cluster-admin"},
+			Subjects:   []rbacv1.Subject{{Kind: rbacv1.ServiceAccountKind, Name: Name, Namespace: options.Namespace}},
+		},
+		&appsv1.Deployment{
+			TypeMeta:   metav1.TypeMeta{APIVersion: appsv1.SchemeGroupVersion.String(), Kind: "Deployment"},
+			ObjectMeta: metav1.ObjectMeta{Name: Name, Namespace: options.Namespace, Labels: labels},
+			Spec: appsv1.DeploymentSpec{
+				Replicas: ptr.To(options.Replicas),
+				Selector: &metav1.LabelSelector{MatchLabels: labels},
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{Labels: labels},
+					Spec: corev1.PodSpec{
+						ServiceAccountName: Name,
+						Containers: []corev1.Container{{
+							Name:    "operator",
+							Image:   options.Image,
+							Command: []string{"vcluster"},
+							Args:    args,
+							// the leader election lease is created in the namespace of the pod
+							Env: []corev1.EnvVar{{
+								Name:      "NAMESPACE",
+								ValueFrom: &corev1.EnvVarSource{FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.namespace"}},
+							}},
+							Resources: corev1.ResourceRequirements{
+								Requests: corev1.ResourceList{
+									corev1.ResourceCPU:    resource.MustParse("20m"),
+									corev1.ResourceMemory: resource.MustParse("128Mi"),
+								},
+								Limits: corev1.ResourceList{
+									corev1.ResourceMemory: resource.MustParse("512Mi"),
+								},
+							},
+						}},
+					},
+				},
+			},
+		},
+	}
+}
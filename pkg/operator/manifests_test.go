@@ -0,0 +1,53 @@
+package operator
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+)
+
+func TestManifests(t *testing.T) {
+	objects := Manifests(&ManifestOptions{Namespace: "operator", Image: "ghcr.io/loft-sh/vcluster-cli:0.21.0", Replicas: 2, WatchNamespace: "team-a"})
+	assert.Equal(t, len(objects), 4)
+
+	binding, ok := objects[2].(*rbacv1.ClusterRoleBinding)
+	assert.Assert(t, ok)
+	assert.DeepEqual(t, binding.Subjects, []rbacv1.Subject{{Kind: rbacv1.ServiceAccountKind, Name: Name, Namespace: "operator"}})
+
+	deployment, ok := objects[3].(*appsv1.Deployment)
+	assert.Assert(t, ok)
+	assert.Equal(t, deployment.Namespace, "operator")
+	assert.Equal(t, deployment.Spec.Template.Spec.ServiceAccountName, Name)
+	container := deployment.Spec.Template.Spec.Containers[0]
+	assert.Equal(t, container.Image, "ghcr.io/loft-sh/vcluster-cli:0.21.0")
+	assert.DeepEqual(t, container.Args, []string{"operator", "start", "--metrics-bind-address", "0", "--leader-elect", "--namespace", "team-a"})
+	assert.Equal(t, container.Env[0].ValueFrom.FieldRef.FieldPath, "metadata.namespace")
+
+	// a single replica doesn't need leader election
+	deployment = Manifests(&ManifestOptions{Namespace: "operator", Replicas: 1})[3].(*appsv1.Deployment)
+	assert.DeepEqual(t, deployment.Spec.Template.Spec.Containers[0].Args, []string{"operator", "start", "--metrics-bind-address", "0"})
+}
+
+func TestLeaderElectionNamespace(t *testing.T) {
+	t.Setenv("NAMESPACE", "")
+
+	namespace, err := leaderElectionNamespace(&Options{})
+	assert.NilError(t, err)
+	assert.Equal(t, namespace, "")
+
+	namespace, err = leaderElectionNamespace(&Options{LeaderElection: true, Namespace: "team-a", LeaderElectionNamespace: "operator"})
+	assert.NilError(t, err)
+	assert.Equal(t, namespace, "operator")
+
+	namespace, err = leaderElectionNamespace(&Options{LeaderElection: true, Namespace: "team-a"})
+	assert.NilError(t, err)
+	assert.Equal(t, namespace, "team-a")
+
+	// all namespaces are watched, so the lease is created in the namespace of the pod
+	t.Setenv("NAMESPACE", "operator")
+	namespace, err = leaderElectionNamespace(&Options{LeaderElection: true})
+	assert.NilError(t, err)
+	assert.Equal(t, namespace, "operator")
+}
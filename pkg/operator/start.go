@@ -0,0 +1,119 @@
+package operator
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/vcluster/pkg/helm"
+	"github.com/loft-sh/vcluster/pkg/util/clienthelper"
+	"github.com/loft-sh/vcluster/pkg/util/helmdownloader"
+	"github.com/loft-sh/vcluster/pkg/util/kubeconfig"
+	"github.com/loft-sh/vcluster/pkg/util/loghelper"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+)
+
+// Options configure the operator
+type Options struct {
+	// Namespace restricts the operator to VCluster objects within this namespace, all namespaces are watched if empty
+	Namespace string
+
+	MetricsBindAddress string
+	LeaderElection     bool
+
+	// LeaderElectionNamespace is the namespace of the leader election lease. Defaults to Namespace or the namespace of the
+	// pod the operator runs in.
+	LeaderElectionNamespace string
+
+	// ClusterAPI makes VCluster objects act as ClusterAPI infrastructure and control plane provider
+	ClusterAPI bool
+}
+
+// Start installs the VCluster custom resource definition and reconciles VCluster objects until the context is done
+func Start(ctx context.Context, restConfig *rest.Config, options *Options, log log.Logger) error {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = apiextensionsv1.AddToScheme(scheme)
+
+	kubeClient, err := client.New(restConfig, client.Options{Scheme: scheme})
+	if err != nil {
+		return err
+	}
+	err = EnsureCRD(ctx, kubeClient)
+	if err != nil {
+		return err
+	}
+
+	leaderElectionNamespace, err := leaderElectionNamespace(options)
+	if err != nil {
+		return err
+	}
+
+	managerOptions := ctrl.Options{
+		Scheme:                  scheme,
+		Metrics:                 metricsserver.Options{BindAddress: options.MetricsBindAddress},
+		LeaderElection:          options.LeaderElection,
+		LeaderElectionID:        Name,
+		LeaderElectionNamespace: leaderElectionNamespace,
+	}
+	if options.Namespace != "" {
+		managerOptions.Cache = cache.Options{DefaultNamespaces: map[string]cache.Config{options.Namespace: {}}}
+	}
+	mgr, err := ctrl.NewManager(restConfig, managerOptions)
+	if err != nil {
+		return fmt.Errorf("create manager: %w", err)
+	}
+
+	// the helm releases are deployed with the same credentials the operator uses
+	clientConfig, err := kubeconfig.ConvertRestConfigToClientConfig(restConfig)
+	if err != nil {
+		return err
+	}
+	rawConfig, err := clientConfig.RawConfig()
+	if err != nil {
+		return err
+	}
+	helmBinaryPath, err := helmdownloader.GetHelmBinaryPath(ctx, log)
+	if err != nil {
+		return err
+	}
+
+	err = (&Reconciler{
+		Client:     mgr.GetClient(),
+		HelmClient: helm.NewClient(&rawConfig, log, helmBinaryPath),
+		ClusterAPI: options.ClusterAPI,
+		Log:        loghelper.New("vcluster-operator"),
+	}).SetupWithManager(mgr)
+	if err != nil {
+		return fmt.Errorf("setup vcluster operator: %w", err)
+	}
+
+	log.Infof("Starting vcluster operator")
+	return mgr.Start(ctx)
+}
+
+// leaderElectionNamespace returns the namespace of the leader election lease, the operator watches all namespaces by
+// default, so the lease is created in the namespace of its pod then
+func leaderElectionNamespace(options *Options) (string, error) {
+	if !options.LeaderElection {
+		return "", nil
+	} else if options.LeaderElectionNamespace != "" {
+		return options.LeaderElectionNamespace, nil
+	} else if options.Namespace != "" {
+		return options.Namespace, nil
+	}
+
+	namespace, err := clienthelper.CurrentNamespace()
+	if err != nil {
+		return "", fmt.Errorf("leader election needs a namespace for its lease, please specify --leader-election-namespace or --namespace if the operator doesn't run in a pod: %w", err)
+	}
+
+	return namespace, nil
+}
@@ -0,0 +1,323 @@
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientpkg "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ManagerConfigSecret is the secret that holds the settings of the platform
+const ManagerConfigSecret = "loft-manager-config"
+
+// archiveObjectsFile is the file in the archive that holds the objects of the backup
+const archiveObjectsFile = "backup.yaml"
+
+// archiveMetadataFile is the file in the archive that holds the metadata of the backup
+const archiveMetadataFile = "metadata.yaml"
+
+// Metadata describes an archive written by ToArchive
+type Metadata struct {
+	// Labels tag the archive, e.g. with the purpose of the backup or the git ref it was taken at
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Created is the time the archive was written
+	Created time.Time `json:"created,omitempty"`
+}
+
+// Settings returns the secret with the settings of the platform installed in the given namespace
+func Settings(ctx context.Context, client clientpkg.Client, namespace string) ([]runtime.Object, error) {
+	secret, err := getSecret(ctx, client, namespace, ManagerConfigSecret)
+	if err != nil {
+		return nil, err
+	} else if secret == nil || secret.Name == "" {
+		return []runtime.Object{}, nil
+	}
+
+	return []runtime.Object{secret}, nil
+}
+
+// ToArchive writes the objects as a gzip compressed tar archive with the labels in its metadata
+func ToArchive(objects []runtime.Object, labels map[string]string) ([]byte, error) {
+	out, err := ToYAML(objects)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	metadata, err := yaml.Marshal(&Metadata{Labels: labels, Created: now.UTC().Truncate(time.Second)})
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal metadata")
+	}
+
+	buffer := &bytes.Buffer{}
+	gzipWriter := gzip.NewWriter(buffer)
+	tarWriter := tar.NewWriter(gzipWriter)
+	for _, file := range []struct {
+		name string
+		data []byte
+	}{{name: archiveMetadataFile, data: metadata}, {name: archiveObjectsFile, data: out}} {
+		err = tarWriter.WriteHeader(&tar.Header{
+			Name:    file.name,
+			Mode:    0600,
+			Size:    int64(len(file.data)),
+			ModTime: now,
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "write archive header")
+		}
+		_, err = tarWriter.Write(file.data)
+		if err != nil {
+			return nil, errors.Wrap(err, "write archive")
+		}
+	}
+	err = tarWriter.Close()
+	if err != nil {
+		return nil, errors.Wrap(err, "close archive")
+	}
+	err = gzipWriter.Close()
+	if err != nil {
+		return nil, errors.Wrap(err, "close archive")
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// FromArchive reads the objects of an archive written by ToArchive. Plain yaml backups written by earlier versions
+// are read as well.
+func FromArchive(data []byte) ([]*unstructured.Unstructured, error) {
+	if !isArchive(data) {
+		return FromYAML(data)
+	}
+
+	out, err := readArchiveFile(data, archiveObjectsFile)
+	if err != nil {
+		return nil, err
+	} else if out == nil {
+		return nil, fmt.Errorf("archive doesn't contain %s", archiveObjectsFile)
+	}
+
+	return FromYAML(out)
+}
+
+// ReadMetadata reads the metadata of an archive written by ToArchive. Plain yaml backups and archives written by
+// earlier versions have no metadata, so empty metadata is returned for them.
+func ReadMetadata(data []byte) (*Metadata, error) {
+	metadata := &Metadata{}
+	if !isArchive(data) {
+		return metadata, nil
+	}
+
+	out, err := readArchiveFile(data, archiveMetadataFile)
+	if err != nil {
+		return nil, err
+	} else if out == nil {
+		return metadata, nil
+	}
+
+	err = yaml.Unmarshal(out, metadata)
+	if err != nil {
+		return nil, errors.Wrap(err, "unmarshal metadata")
+	}
+
+	return metadata, nil
+}
+
+// isArchive returns true if data is gzip compressed
+func isArchive(data []byte) bool {
+	return len(data) > 1 && data[0] == 0x1f && data[1] == 0x8b
+}
+
+// readArchiveFile returns the contents of the file in the archive or nil if the archive doesn't contain it
+func readArchiveFile(data []byte, name string) ([]byte, error) {
+	gzipReader, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.Wrap(err, "read archive")
+	}
+	defer gzipReader.Close()
+
+	tarReader := tar.NewReader(gzipReader)
+	for {
+		header, err := tarReader.Next()
+		if errors.Is(err, io.EOF) {
+			return nil, nil
+		} else if err != nil {
+			return nil, errors.Wrap(err, "read archive")
+		} else if header.Name != name {
+			continue
+		}
+
+		out, err := io.ReadAll(tarReader)
+		if err != nil {
+			return nil, errors.Wrap(err, "read archive")
+		}
+
+		return out, nil
+	}
+}
+
+// FromYAML parses the objects of a backup written by ToYAML
+func FromYAML(data []byte) ([]*unstructured.Unstructured, error) {
+	objects := []*unstructured.Unstructured{}
+	for _, document := range strings.Split(string(data), "\n---\n") {
+		if strings.TrimSpace(document) == "" {
+			continue
+		}
+
+		obj := &unstructured.Unstructured{}
+		err := yaml.Unmarshal([]byte(document), &obj.Object)
+		if err != nil {
+			return nil, errors.Wrap(err, "unmarshal object")
+		} else if obj.GetKind() == "" || obj.GetName() == "" {
+			continue
+		}
+
+		objects = append(objects, obj)
+	}
+
+	return objects, nil
+}
+
+// Entry is an archive found by List
+type Entry struct {
+	Metadata
+
+	// Location is the path of the archive
+	Location string
+}
+
+// List returns the archives in a local directory or file:// url with labels that match the selector, the oldest
+// archive first. Presigned urls only grant access to a single object, so object stores cannot be listed.
+func List(location string, selector labels.Selector) ([]Entry, error) {
+	dir, err := localPath(location)
+	if err != nil {
+		return nil, err
+	}
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := []Entry{}
+	for _, file := range files {
+		if !file.Type().IsRegular() {
+			continue
+		}
+
+		path := filepath.Join(dir, file.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		} else if !isArchive(data) {
+			continue
+		}
+
+		metadata, err := ReadMetadata(data)
+		if err != nil {
+			return nil, fmt.Errorf("read %s: %w", path, err)
+		} else if !selector.Matches(labels.Set(metadata.Labels)) {
+			continue
+		}
+
+		entries = append(entries, Entry{Metadata: *metadata, Location: path})
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Created.Before(entries[j].Created)
+	})
+
+	return entries, nil
+}
+
+// Upload writes the archive to a local path or file:// url or uploads it with a http PUT request to a http(s):// url.
+// Object stores such as S3, GCS or Azure Blob Storage accept uploads to presigned urls, so no credentials are needed.
+func Upload(ctx context.Context, location string, data []byte) error {
+	parsed, err := url.Parse(location)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		path, err := localPath(location)
+		if err != nil {
+			return err
+		}
+		err = os.MkdirAll(filepath.Dir(path), 0755)
+		if err != nil {
+			return err
+		}
+
+		return os.WriteFile(path, data, 0600)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, location, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.ContentLength = int64(len(data))
+	req.Header.Set("Content-Type", "application/gzip")
+	if strings.HasSuffix(parsed.Hostname(), ".blob.core.windows.net") {
+		req.Header.Set("x-ms-blob-type", "BlockBlob")
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "upload backup")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("upload backup: unexpected status code %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	return nil
+}
+
+// Download reads the archive from a local path or file:// url or downloads it from a http(s):// url
+func Download(ctx context.Context, location string) ([]byte, error) {
+	parsed, err := url.Parse(location)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		path, err := localPath(location)
+		if err != nil {
+			return nil, err
+		}
+
+		return os.ReadFile(path)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, location, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "download backup")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("download backup: unexpected status code %d", resp.StatusCode)
+	}
+
+	return io.ReadAll(resp.Body)
+}
+
+func localPath(location string) (string, error) {
+	if strings.HasPrefix(location, "file://") {
+		return strings.TrimPrefix(location, "file://"), nil
+	} else if strings.Contains(location, "://") {
+		return "", fmt.Errorf("unsupported backup location %s, please use a local path, a file:// or a presigned http(s):// url", location)
+	}
+
+	return location, nil
+}
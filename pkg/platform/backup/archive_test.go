@@ -0,0 +1,138 @@
+package backup
+
+import (
+	"context"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+
+	storagev1 "github.com/loft-sh/api/v4/pkg/apis/storage/v1"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	clientpkg "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestArchive(t *testing.T) {
+	objects := []runtime.Object{
+		&storagev1.Project{
+			TypeMeta:   metav1.TypeMeta{Kind: "Project", APIVersion: storagev1.SchemeGroupVersion.String()},
+			ObjectMeta: metav1.ObjectMeta{Name: "default"},
+		},
+		&corev1.Secret{
+			TypeMeta:   metav1.TypeMeta{Kind: "Secret", APIVersion: "v1"},
+			ObjectMeta: metav1.ObjectMeta{Name: ManagerConfigSecret, Namespace: "loft"},
+			Data:       map[string][]byte{"config": []byte("audit: {}")},
+		},
+	}
+
+	archive, err := ToArchive(objects, map[string]string{"purpose": "pre-upgrade"})
+	assert.NilError(t, err)
+	location := filepath.Join(t.TempDir(), "backups", "backup.tar.gz")
+	err = Upload(context.Background(), "file://"+location, archive)
+	assert.NilError(t, err)
+	data, err := Download(context.Background(), location)
+	assert.NilError(t, err)
+
+	restored, err := FromArchive(data)
+	assert.NilError(t, err)
+	assert.Equal(t, len(restored), 2)
+	assert.Equal(t, restored[0].GetKind(), "Project")
+	assert.Equal(t, restored[1].GetNamespace(), "loft")
+	metadata, err := ReadMetadata(data)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, metadata.Labels, map[string]string{"purpose": "pre-upgrade"})
+	assert.Assert(t, !metadata.Created.IsZero())
+
+	// plain yaml backups can be restored as well
+	out, err := ToYAML(objects)
+	assert.NilError(t, err)
+	restored, err = FromArchive(out)
+	assert.NilError(t, err)
+	assert.Equal(t, len(restored), 2)
+	metadata, err = ReadMetadata(out)
+	assert.NilError(t, err)
+	assert.Equal(t, len(metadata.Labels), 0)
+
+	_, err = Download(context.Background(), "s3://bucket/backup.tar.gz")
+	assert.ErrorContains(t, err, "unsupported backup location")
+}
+
+func TestList(t *testing.T) {
+	dir := t.TempDir()
+	for name, archiveLabels := range map[string]map[string]string{
+		"pre-upgrade.tar.gz": {"purpose": "pre-upgrade", "git": "abc123"},
+		"nightly.tar.gz":     {"purpose": "nightly"},
+		"unlabeled.tar.gz":   nil,
+	} {
+		archive, err := ToArchive([]runtime.Object{}, archiveLabels)
+		assert.NilError(t, err)
+		assert.NilError(t, Upload(context.Background(), filepath.Join(dir, name), archive))
+	}
+	// plain yaml backups are not listed
+	assert.NilError(t, os.WriteFile(filepath.Join(dir, "backup.yaml"), []byte("kind: Secret"), 0600))
+
+	entries, err := List(dir, labels.Everything())
+	assert.NilError(t, err)
+	assert.Equal(t, len(entries), 3)
+
+	selector, err := labels.Parse("purpose=pre-upgrade")
+	assert.NilError(t, err)
+	entries, err = List("file://"+dir, selector)
+	assert.NilError(t, err)
+	assert.Equal(t, len(entries), 1)
+	assert.Equal(t, entries[0].Location, filepath.Join(dir, "pre-upgrade.tar.gz"))
+	assert.DeepEqual(t, entries[0].Labels, map[string]string{"purpose": "pre-upgrade", "git": "abc123"})
+
+	selector, err = labels.Parse("purpose")
+	assert.NilError(t, err)
+	entries, err = List(dir, selector)
+	assert.NilError(t, err)
+	assert.Equal(t, len(entries), 2)
+
+	_, err = List("https://my-bucket.s3.amazonaws.com/backups", labels.Everything())
+	assert.ErrorContains(t, err, "unsupported backup location")
+}
+
+func TestRestore(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewClientBuilder().WithScheme(clientgoscheme.Scheme).WithObjects(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "existing", Namespace: "loft"},
+		Data:       map[string][]byte{"key": []byte("old")},
+	}).Build()
+
+	objects := []*unstructured.Unstructured{
+		newSecret("existing", "new"),
+		newSecret("restored", "new"),
+	}
+	result, errs := Restore(ctx, client, objects, false, func(string) {})
+	assert.Equal(t, len(errs), 0)
+	assert.DeepEqual(t, result, RestoreResult{Created: 1, Skipped: 1})
+
+	result, errs = Restore(ctx, client, objects, true, func(string) {})
+	assert.Equal(t, len(errs), 0)
+	assert.DeepEqual(t, result, RestoreResult{Updated: 2})
+
+	secret := &corev1.Secret{}
+	err := client.Get(ctx, clientpkg.ObjectKey{Namespace: "loft", Name: "existing"}, secret)
+	assert.NilError(t, err)
+	assert.Equal(t, string(secret.Data["key"]), "new")
+	err = client.Get(ctx, clientpkg.ObjectKey{Name: "loft"}, &corev1.Namespace{})
+	assert.NilError(t, err)
+}
+
+func newSecret(name, value string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion("v1")
+	obj.SetKind("Secret")
+	obj.SetName(name)
+	obj.SetNamespace("loft")
+	_ = unstructured.SetNestedField(obj.Object, map[string]interface{}{"key": base64.StdEncoding.EncodeToString([]byte(value))}, "data")
+	return obj
+}
@@ -0,0 +1,40 @@
+package backup
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// ParseLabels parses archive labels in the form key=value
+func ParseLabels(labelArgs []string) (map[string]string, error) {
+	archiveLabels := map[string]string{}
+	for _, label := range labelArgs {
+		key, value, found := strings.Cut(label, "=")
+		if !found {
+			return nil, fmt.Errorf("invalid label %q, expected key=value", label)
+		}
+		errs := validation.IsQualifiedName(key)
+		errs = append(errs, validation.IsValidLabelValue(value)...)
+		if len(errs) > 0 {
+			return nil, fmt.Errorf("invalid label %q: %s", label, strings.Join(errs, ", "))
+		}
+
+		archiveLabels[key] = value
+	}
+
+	return archiveLabels, nil
+}
+
+// FormatLabels returns the archive labels sorted by their key in the form key=value
+func FormatLabels(archiveLabels map[string]string) string {
+	formatted := []string{}
+	for key, value := range archiveLabels {
+		formatted = append(formatted, key+"="+value)
+	}
+	sort.Strings(formatted)
+
+	return strings.Join(formatted, ",")
+}
@@ -0,0 +1,19 @@
+package backup
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestParseLabels(t *testing.T) {
+	archiveLabels, err := ParseLabels([]string{"purpose=pre-upgrade", "git=abc123", "empty="})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, archiveLabels, map[string]string{"purpose": "pre-upgrade", "git": "abc123", "empty": ""})
+	assert.Equal(t, FormatLabels(archiveLabels), "empty=,git=abc123,purpose=pre-upgrade")
+
+	_, err = ParseLabels([]string{"purpose"})
+	assert.ErrorContains(t, err, "expected key=value")
+	_, err = ParseLabels([]string{"purpose=pre upgrade"})
+	assert.ErrorContains(t, err, "invalid label")
+}
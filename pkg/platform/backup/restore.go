@@ -0,0 +1,95 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	clientpkg "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// RestoreResult counts the objects of a restore
+type RestoreResult struct {
+	Created int
+	Updated int
+	Skipped int
+}
+
+// Restore creates the objects of a backup. Cluster scoped objects such as projects, users and templates are
+// restored before the namespaced objects that belong to them. Existing objects are only updated with overwrite.
+func Restore(ctx context.Context, client clientpkg.Client, objects []*unstructured.Unstructured, overwrite bool, infoFn LogFn) (RestoreResult, []error) {
+	sorted := append([]*unstructured.Unstructured{}, objects...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].GetNamespace() == "" && sorted[j].GetNamespace() != ""
+	})
+
+	result := RestoreResult{}
+	restoreErrors := []error{}
+	namespaces := map[string]bool{}
+	for _, obj := range sorted {
+		name := obj.GetName()
+		if obj.GetNamespace() != "" {
+			name = obj.GetNamespace() + "/" + name
+		}
+
+		if obj.GetNamespace() != "" && !namespaces[obj.GetNamespace()] {
+			err := ensureNamespace(ctx, client, obj.GetNamespace())
+			if err != nil {
+				restoreErrors = append(restoreErrors, err)
+				continue
+			}
+			namespaces[obj.GetNamespace()] = true
+		}
+
+		updated, err := restoreObject(ctx, client, obj, overwrite)
+		switch {
+		case kerrors.IsAlreadyExists(err):
+			infoFn(fmt.Sprintf("Skipped existing %s %s", obj.GetKind(), name))
+			result.Skipped++
+		case err != nil:
+			restoreErrors = append(restoreErrors, errors.Wrapf(err, "restore %s %s", obj.GetKind(), name))
+		case updated:
+			infoFn(fmt.Sprintf("Updated %s %s", obj.GetKind(), name))
+			result.Updated++
+		default:
+			infoFn(fmt.Sprintf("Restored %s %s", obj.GetKind(), name))
+			result.Created++
+		}
+	}
+
+	return result, restoreErrors
+}
+
+// restoreObject creates the object or updates an existing object with overwrite. Returns true if the object was
+// updated.
+func restoreObject(ctx context.Context, client clientpkg.Client, obj *unstructured.Unstructured, overwrite bool) (bool, error) {
+	err := client.Create(ctx, obj.DeepCopy())
+	if err == nil || !kerrors.IsAlreadyExists(err) || !overwrite {
+		return false, err
+	}
+
+	existing := &unstructured.Unstructured{}
+	existing.SetGroupVersionKind(obj.GroupVersionKind())
+	err = client.Get(ctx, clientpkg.ObjectKeyFromObject(obj), existing)
+	if err != nil {
+		return false, err
+	}
+
+	update := obj.DeepCopy()
+	update.SetResourceVersion(existing.GetResourceVersion())
+	return true, client.Update(ctx, update)
+}
+
+func ensureNamespace(ctx context.Context, client clientpkg.Client, namespace string) error {
+	err := client.Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace}})
+	if err != nil && !kerrors.IsAlreadyExists(err) {
+		return errors.Wrapf(err, "create namespace %s", namespace)
+	}
+
+	return nil
+}
@@ -69,13 +69,19 @@ type Client interface {
 type LoginClient interface {
 	Login(host string, insecure bool, log log.Logger) error
 	LoginWithAccessKey(host, accessKey string, insecure bool) error
+	LoginWithOIDC(ctx context.Context, host string, oidcLogin config.OIDCLogin, insecure bool) error
 }
 
 // InitClientFromConfig returns a client with the client identity initialized through the selves api.
 // Use this by default, unless performing actions that don't require a log in (like login itself).
 func InitClientFromConfig(ctx context.Context, config *config.CLI) (Client, error) {
-	c := NewClientFromConfig(config)
+	c := &client{
+		config: config,
+	}
 
+	if err := c.refreshOIDCLogin(ctx); err != nil {
+		return nil, err
+	}
 	if err := c.RefreshSelf(ctx); err != nil {
 		return nil, err
 	}
@@ -328,6 +334,7 @@ func (c *client) LoginWithAccessKey(host, accessKey string, insecure bool) error
 	platformConfig.Host = host
 	platformConfig.Insecure = insecure
 	platformConfig.AccessKey = accessKey
+	platformConfig.OIDC = nil
 	c.Config().Platform = platformConfig
 
 	// verify the connection works
@@ -0,0 +1,157 @@
+package clihelper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/loft-sh/admin-apis/pkg/licenseapi"
+	"gopkg.in/square/go-jose.v2/jwt"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
+)
+
+const (
+	// LicenseSecretName is the name of the secret that holds the offline license of Loft
+	LicenseSecretName = "loft-license"
+
+	licenseSecretKey = "license"
+	licenseEnvName   = "LICENSE_KEY"
+)
+
+// OfflineLicense is an offline license key and the claims it contains
+type OfflineLicense struct {
+	Token   string
+	License *licenseapi.License
+	Expiry  *time.Time
+}
+
+// ParseOfflineLicense parses the claims of an offline license key. The signature is verified by Loft itself, this only
+// makes sure the key is an offline license and not expired before it is handed to Loft.
+func ParseOfflineLicense(token string, now time.Time) (*OfflineLicense, error) {
+	token = strings.TrimSpace(token)
+	signed, err := jwt.ParseSigned(token)
+	if err != nil {
+		return nil, fmt.Errorf("parse license key: %w", err)
+	}
+
+	claims := jwt.Claims{}
+	licenseClaims := licenseapi.OfflineLicenseKeyClaims{}
+	err = signed.UnsafeClaimsWithoutVerification(&claims, &licenseClaims)
+	if err != nil {
+		return nil, fmt.Errorf("parse license key claims: %w", err)
+	} else if licenseClaims.License == nil || !licenseClaims.License.IsOffline {
+		return nil, errors.New("license key is not an offline license")
+	}
+
+	license := &OfflineLicense{
+		Token:   token,
+		License: licenseClaims.License,
+	}
+	if claims.Expiry != nil {
+		expiry := claims.Expiry.Time()
+		if now.After(expiry) {
+			return nil, fmt.Errorf("license expired at %s", expiry.Format(time.RFC3339))
+		}
+		license.Expiry = &expiry
+	}
+
+	return license, nil
+}
+
+// ApplyLicenseSecret creates or updates the license secret in the Loft namespace. The namespace is created if it
+// doesn't exist yet, so the secret can be seeded before Loft is installed.
+func ApplyLicenseSecret(ctx context.Context, kubeClient kubernetes.Interface, namespace string, license *OfflineLicense) error {
+	_, err := kubeClient.CoreV1().Namespaces().Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace}}, metav1.CreateOptions{})
+	if err != nil && !kerrors.IsAlreadyExists(err) {
+		return fmt.Errorf("create namespace %s: %w", namespace, err)
+	}
+
+	secret, err := kubeClient.CoreV1().Secrets(namespace).Get(ctx, LicenseSecretName, metav1.GetOptions{})
+	if kerrors.IsNotFound(err) {
+		_, err = kubeClient.CoreV1().Secrets(namespace).Create(ctx, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      LicenseSecretName,
+				Namespace: namespace,
+			},
+			Data: map[string][]byte{
+				licenseSecretKey: []byte(license.Token),
+			},
+		}, metav1.CreateOptions{})
+		if err != nil {
+			return fmt.Errorf("create license secret: %w", err)
+		}
+
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("get license secret: %w", err)
+	}
+
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+	secret.Data[licenseSecretKey] = []byte(license.Token)
+	_, err = kubeClient.CoreV1().Secrets(namespace).Update(ctx, secret, metav1.UpdateOptions{})
+	if err != nil {
+		return fmt.Errorf("update license secret: %w", err)
+	}
+
+	return nil
+}
+
+// EnsureLicenseEnv passes the license secret to the manager container of the Loft deployment and restarts it, so
+// Loft picks up the license. Helm keeps the env variable on upgrades as it is not part of the chart.
+func EnsureLicenseEnv(ctx context.Context, kubeClient kubernetes.Interface, namespace string) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		deployment, err := kubeClient.AppsV1().Deployments(namespace).Get(ctx, defaultDeploymentName, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("get loft deployment: %w", err)
+		}
+
+		found := false
+		for i, container := range deployment.Spec.Template.Spec.Containers {
+			if container.Name != "manager" {
+				continue
+			}
+
+			deployment.Spec.Template.Spec.Containers[i].Env = licenseEnv(container.Env)
+			found = true
+		}
+		if !found {
+			return fmt.Errorf("loft deployment %s/%s has no manager container", namespace, defaultDeploymentName)
+		}
+
+		// the env variable doesn't change if the license is replaced, so the pods are restarted explicitly
+		if deployment.Spec.Template.Annotations == nil {
+			deployment.Spec.Template.Annotations = map[string]string{}
+		}
+		deployment.Spec.Template.Annotations["kubectl.kubernetes.io/restartedAt"] = time.Now().Format(time.RFC3339)
+		_, err = kubeClient.AppsV1().Deployments(namespace).Update(ctx, deployment, metav1.UpdateOptions{})
+		return err
+	})
+}
+
+func licenseEnv(env []corev1.EnvVar) []corev1.EnvVar {
+	licenseVar := corev1.EnvVar{
+		Name: licenseEnvName,
+		ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: LicenseSecretName},
+				Key:                  licenseSecretKey,
+			},
+		},
+	}
+	for i := range env {
+		if env[i].Name == licenseEnvName {
+			env[i] = licenseVar
+			return env
+		}
+	}
+
+	return append(env, licenseVar)
+}
@@ -0,0 +1,92 @@
+package clihelper
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/loft-sh/admin-apis/pkg/licenseapi"
+	jose "gopkg.in/square/go-jose.v2"
+	"gopkg.in/square/go-jose.v2/jwt"
+	"gotest.tools/v3/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func newTestLicense(t *testing.T, license *licenseapi.License, expiry time.Time) string {
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.HS256, Key: []byte("test-signing-key-of-32-bytes-len")}, nil)
+	assert.NilError(t, err)
+
+	token, err := jwt.Signed(signer).Claims(jwt.Claims{Expiry: jwt.NewNumericDate(expiry)}).Claims(licenseapi.OfflineLicenseKeyClaims{License: license}).CompactSerialize()
+	assert.NilError(t, err)
+	return token
+}
+
+func TestParseOfflineLicense(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	expiry := now.Add(24 * time.Hour)
+
+	token := newTestLicense(t, &licenseapi.License{InstanceID: "instance", IsOffline: true}, expiry)
+	license, err := ParseOfflineLicense(token+"\n", now)
+	assert.NilError(t, err)
+	assert.Equal(t, license.Token, token)
+	assert.Equal(t, license.License.InstanceID, "instance")
+	assert.Assert(t, license.Expiry.Equal(expiry))
+
+	_, err = ParseOfflineLicense(newTestLicense(t, &licenseapi.License{InstanceID: "instance"}, expiry), now)
+	assert.ErrorContains(t, err, "not an offline license")
+
+	_, err = ParseOfflineLicense(token, expiry.Add(time.Second))
+	assert.ErrorContains(t, err, "license expired")
+
+	_, err = ParseOfflineLicense("not-a-license", now)
+	assert.ErrorContains(t, err, "parse license key")
+}
+
+func TestApplyLicenseSecret(t *testing.T) {
+	ctx := context.Background()
+	kubeClient := fake.NewSimpleClientset()
+
+	err := ApplyLicenseSecret(ctx, kubeClient, "vcluster-platform", &OfflineLicense{Token: "first"})
+	assert.NilError(t, err)
+	err = ApplyLicenseSecret(ctx, kubeClient, "vcluster-platform", &OfflineLicense{Token: "second"})
+	assert.NilError(t, err)
+
+	_, err = kubeClient.CoreV1().Namespaces().Get(ctx, "vcluster-platform", metav1.GetOptions{})
+	assert.NilError(t, err)
+	secret, err := kubeClient.CoreV1().Secrets("vcluster-platform").Get(ctx, LicenseSecretName, metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, string(secret.Data[licenseSecretKey]), "second")
+}
+
+func TestEnsureLicenseEnv(t *testing.T) {
+	ctx := context.Background()
+	kubeClient := fake.NewSimpleClientset(&appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "loft", Namespace: "vcluster-platform"},
+		Spec: appsv1.DeploymentSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{Name: "manager", Env: []corev1.EnvVar{{Name: "DISABLE_LOFT_ROUTER", Value: "true"}}},
+					},
+				},
+			},
+		},
+	})
+
+	// applying the license twice must not duplicate the env variable
+	for i := 0; i < 2; i++ {
+		err := EnsureLicenseEnv(ctx, kubeClient, "vcluster-platform")
+		assert.NilError(t, err)
+	}
+
+	deployment, err := kubeClient.AppsV1().Deployments("vcluster-platform").Get(ctx, "loft", metav1.GetOptions{})
+	assert.NilError(t, err)
+	env := deployment.Spec.Template.Spec.Containers[0].Env
+	assert.Equal(t, len(env), 2)
+	assert.Equal(t, env[1].Name, licenseEnvName)
+	assert.Equal(t, env[1].ValueFrom.SecretKeyRef.Name, LicenseSecretName)
+	assert.Assert(t, deployment.Spec.Template.Annotations["kubectl.kubernetes.io/restartedAt"] != "")
+}
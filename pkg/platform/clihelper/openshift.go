@@ -0,0 +1,146 @@
+package clihelper
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/loft-sh/log"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+)
+
+// LoftRouteName is the name of the OpenShift route that exposes Loft instead of the ingress
+const LoftRouteName = "loft-route"
+
+// openShiftSCCClusterRole allows the Loft service account to run with the user ids set by the chart
+const openShiftSCCClusterRole = "system:openshift:scc:anyuid"
+
+var routesResource = schema.GroupVersionResource{Group: "route.openshift.io", Version: "v1", Resource: "routes"}
+
+// IsOpenShift returns true if the cluster serves the OpenShift route and security APIs
+func IsOpenShift(discoveryClient discovery.DiscoveryInterface) (bool, error) {
+	groups, err := discoveryClient.ServerGroups()
+	if err != nil {
+		return false, fmt.Errorf("discover api groups: %w", err)
+	}
+
+	route, security := false, false
+	for _, group := range groups.Groups {
+		switch group.Name {
+		case routesResource.Group:
+			route = true
+		case "security.openshift.io":
+			security = true
+		}
+	}
+
+	return route && security, nil
+}
+
+// EnsureOpenShiftSCC allows the Loft service account to use the anyuid security context constraints. OpenShift
+// rejects the pods of the chart with the default restricted security context constraints otherwise.
+func EnsureOpenShiftSCC(ctx context.Context, kubeClient kubernetes.Interface, namespace string, log log.Logger) error {
+	_, err := kubeClient.CoreV1().Namespaces().Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace}}, metav1.CreateOptions{})
+	if err != nil && !kerrors.IsAlreadyExists(err) {
+		return fmt.Errorf("create namespace %s: %w", namespace, err)
+	}
+
+	_, err = kubeClient.RbacV1().RoleBindings(namespace).Create(ctx, &rbacv1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      defaultReleaseName + "-scc-anyuid",
+			Namespace: namespace,
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "ClusterRole",
+			Name:     openShiftSCCClusterRole,
+		},
+		Subjects: []rbacv1.Subject{
+			{
+				Kind:      rbacv1.ServiceAccountKind,
+				Name:      defaultReleaseName,
+				Namespace: namespace,
+			},
+		},
+	}, metav1.CreateOptions{})
+	if kerrors.IsAlreadyExists(err) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("create security context constraints role binding: %w", err)
+	}
+
+	log.Donef("Allowed service account %s/%s to use the anyuid security context constraints", namespace, defaultReleaseName)
+	return nil
+}
+
+// EnsureLoftRoute creates or updates the OpenShift route that exposes Loft with the given host
+func EnsureLoftRoute(ctx context.Context, dynamicClient dynamic.Interface, namespace, host string) error {
+	route := loftRoute(namespace, host)
+	existing, err := dynamicClient.Resource(routesResource).Namespace(namespace).Get(ctx, LoftRouteName, metav1.GetOptions{})
+	if kerrors.IsNotFound(err) {
+		_, err = dynamicClient.Resource(routesResource).Namespace(namespace).Create(ctx, route, metav1.CreateOptions{})
+		if err != nil {
+			return fmt.Errorf("create route: %w", err)
+		}
+
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("get route: %w", err)
+	}
+
+	existing.Object["spec"] = route.Object["spec"]
+	_, err = dynamicClient.Resource(routesResource).Namespace(namespace).Update(ctx, existing, metav1.UpdateOptions{})
+	if err != nil {
+		return fmt.Errorf("update route: %w", err)
+	}
+
+	return nil
+}
+
+// GetLoftRouteHost returns the host of the OpenShift route of Loft
+func GetLoftRouteHost(ctx context.Context, dynamicClient dynamic.Interface, namespace string) (string, error) {
+	route, err := dynamicClient.Resource(routesResource).Namespace(namespace).Get(ctx, LoftRouteName, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("get route %s/%s: %w", namespace, LoftRouteName, err)
+	}
+
+	host, _, _ := unstructured.NestedString(route.Object, "spec", "host")
+	if host == "" {
+		return "", fmt.Errorf("couldn't find any host in loft route '%s/%s', please make sure you have not changed any deployed resources", namespace, LoftRouteName)
+	}
+
+	return host, nil
+}
+
+// loftRoute passes the TLS connection through to Loft, which serves its own certificate
+func loftRoute(namespace, host string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": routesResource.GroupVersion().String(),
+		"kind":       "Route",
+		"metadata": map[string]interface{}{
+			"name":      LoftRouteName,
+			"namespace": namespace,
+		},
+		"spec": map[string]interface{}{
+			"host": host,
+			"to": map[string]interface{}{
+				"kind": "Service",
+				"name": defaultReleaseName,
+			},
+			"port": map[string]interface{}{
+				"targetPort": "https",
+			},
+			"tls": map[string]interface{}{
+				"termination":                   "passthrough",
+				"insecureEdgeTerminationPolicy": "Redirect",
+			},
+		},
+	}}
+}
@@ -0,0 +1,38 @@
+package clihelper
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestIsOpenShift(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset()
+	isOpenShift, err := IsOpenShift(kubeClient.Discovery())
+	assert.NilError(t, err)
+	assert.Assert(t, !isOpenShift)
+
+	kubeClient.Resources = []*metav1.APIResourceList{
+		{GroupVersion: "route.openshift.io/v1", APIResources: []metav1.APIResource{{Name: "routes", Namespaced: true, Kind: "Route"}}},
+		{GroupVersion: "security.openshift.io/v1", APIResources: []metav1.APIResource{{Name: "securitycontextconstraints", Kind: "SecurityContextConstraints"}}},
+	}
+	isOpenShift, err = IsOpenShift(kubeClient.Discovery())
+	assert.NilError(t, err)
+	assert.Assert(t, isOpenShift)
+}
+
+func TestLoftRoute(t *testing.T) {
+	route := loftRoute("vcluster-platform", "platform.example.com")
+	assert.Equal(t, route.GetName(), LoftRouteName)
+	assert.Equal(t, route.GetNamespace(), "vcluster-platform")
+
+	host, _, _ := unstructured.NestedString(route.Object, "spec", "host")
+	assert.Equal(t, host, "platform.example.com")
+	termination, _, _ := unstructured.NestedString(route.Object, "spec", "tls", "termination")
+	assert.Equal(t, termination, "passthrough")
+	service, _, _ := unstructured.NestedString(route.Object, "spec", "to", "name")
+	assert.Equal(t, service, "loft")
+}
@@ -0,0 +1,75 @@
+package clihelper
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/loft-sh/api/v4/pkg/product"
+	"github.com/loft-sh/log"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+)
+
+// WaitForLoftRollout waits until all replicas of the Loft deployment run the current revision and are available
+func WaitForLoftRollout(ctx context.Context, kubeClient kubernetes.Interface, namespace string, timeout time.Duration) error {
+	var deployment *appsv1.Deployment
+	err := wait.PollUntilContextTimeout(ctx, 2*time.Second, timeout, true, func(ctx context.Context) (bool, error) {
+		var err error
+		deployment, err = kubeClient.AppsV1().Deployments(namespace).Get(ctx, defaultDeploymentName, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+
+		return isRolledOut(deployment), nil
+	})
+	if err != nil {
+		if deployment != nil {
+			return fmt.Errorf("wait for %s rollout (%d of %d replicas updated and available): %w", product.DisplayName(), deployment.Status.UpdatedReplicas, deployment.Status.Replicas, err)
+		}
+
+		return fmt.Errorf("wait for %s rollout: %w", product.DisplayName(), err)
+	}
+
+	return nil
+}
+
+func isRolledOut(deployment *appsv1.Deployment) bool {
+	replicas := int32(1)
+	if deployment.Spec.Replicas != nil {
+		replicas = *deployment.Spec.Replicas
+	}
+
+	return deployment.Status.ObservedGeneration >= deployment.Generation &&
+		deployment.Status.UpdatedReplicas == replicas &&
+		deployment.Status.Replicas == replicas &&
+		deployment.Status.AvailableReplicas == replicas
+}
+
+// RollbackLoft rolls the Loft release back to the given revision
+func RollbackLoft(kubeContext, namespace string, revision int, log log.Logger) error {
+	args := []string{
+		"rollback",
+		defaultReleaseName,
+		strconv.Itoa(revision),
+		"--wait",
+		"--kube-context",
+		kubeContext,
+		"--namespace",
+		namespace,
+	}
+
+	log.Infof("Executing command: helm %s", strings.Join(args, " "))
+	output, err := exec.Command("helm", args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("error during helm command: %s (%w)", string(output), err)
+	}
+
+	log.Donef("Rolled %s back to revision %d", product.DisplayName(), revision)
+	return nil
+}
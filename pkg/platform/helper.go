@@ -1040,10 +1040,12 @@ func ListVClusters(ctx context.Context, client Client, virtualClusterName, proje
 
 			virtualClusters = append(virtualClusters, virtualClusterInstance)
 		} else {
-			virtualClusters, err = getProjectVirtualClusterInstances(ctx, managementClient, p)
+			projectVirtualClusters, err := getProjectVirtualClusterInstances(ctx, managementClient, p)
 			if err != nil {
 				continue
 			}
+
+			virtualClusters = append(virtualClusters, projectVirtualClusters...)
 		}
 	}
 
@@ -43,6 +43,17 @@ func virtualClusterInstanceProjectAndNameFromContextName(contextName string) (st
 	return strings.Split(contextName, "_")[2], strings.Split(contextName, "_")[1]
 }
 
+// VirtualClusterInstanceFromContextName returns the project and name of the virtual cluster instance of a context
+// created by VirtualClusterInstanceContextName or empty strings if the context wasn't created by it
+func VirtualClusterInstanceFromContextName(contextName string) (string, string) {
+	splitted := strings.Split(contextName, "_")
+	if len(splitted) != 3 || splitted[0] != "vcluster-platform-vcluster" {
+		return "", ""
+	}
+
+	return splitted[2], splitted[1]
+}
+
 func SpaceContextName(clusterName, namespaceName string) string {
 	contextName := "vcluster-platform_"
 	if namespaceName != "" {
@@ -0,0 +1,507 @@
+package mock
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	clusterv1 "github.com/loft-sh/agentapi/v4/pkg/apis/loft/cluster/v1"
+	managementv1 "github.com/loft-sh/api/v4/pkg/apis/management/v1"
+	storagev1 "github.com/loft-sh/api/v4/pkg/apis/storage/v1"
+	"github.com/loft-sh/api/v4/pkg/auth"
+	"github.com/loft-sh/api/v4/pkg/clientset/versioned/scheme"
+	"github.com/loft-sh/log"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	clienttesting "k8s.io/client-go/testing"
+	certutil "k8s.io/client-go/util/cert"
+)
+
+const (
+	// ManagementPath is the path the management api is served under
+	ManagementPath = "/kubernetes/management/apis/management.loft.sh/v1/"
+
+	// ProjectNamespacePrefix is the project namespace prefix the mock server reports through the selves api
+	ProjectNamespacePrefix = "p-"
+
+	// Version is the platform version the mock server reports
+	Version = "v4.0.0-mock"
+
+	// User is the name of the user all access keys belong to
+	User = "admin"
+)
+
+// resources are the management resources the mock server stores
+var resources = map[string]string{
+	"projects":                "Project",
+	"clusters":                "Cluster",
+	"virtualclusterinstances": "VirtualClusterInstance",
+	"ownedaccesskeys":         "OwnedAccessKey",
+}
+
+var codec = scheme.Codecs.LegacyCodec(managementv1.SchemeGroupVersion)
+
+// Server is an in-memory fake of the platform management api. It serves projects, clusters, virtual cluster
+// instances, owned access keys and the selves api, which is enough to run the platform code paths of the cli such as
+// connect, import or activate without a real platform.
+type Server struct {
+	// AccessKey is the access key clients have to authenticate with
+	AccessKey string
+
+	tracker clienttesting.ObjectTracker
+}
+
+// NewServer creates a mock server accepting the given access key
+func NewServer(accessKey string) *Server {
+	return &Server{
+		AccessKey: accessKey,
+		tracker:   clienttesting.NewObjectTracker(scheme.Scheme, scheme.Codecs.UniversalDecoder()),
+	}
+}
+
+// Add stores the given management objects
+func (s *Server) Add(objs ...runtime.Object) error {
+	for _, obj := range objs {
+		err := s.tracker.Add(obj)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// AddProject stores a project which is allowed to use the given clusters and creates the clusters if needed
+func (s *Server) AddProject(name string, clusters ...string) error {
+	project := &managementv1.Project{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+	}
+	for _, cluster := range clusters {
+		project.Spec.AllowedClusters = append(project.Spec.AllowedClusters, storagev1.AllowedCluster{Name: cluster})
+
+		_, err := s.tracker.Get(gvr("clusters"), "", cluster)
+		if kerrors.IsNotFound(err) {
+			err = s.tracker.Add(&managementv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: cluster}})
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return s.tracker.Add(project)
+}
+
+// Get returns the stored object of the given management resource
+func (s *Server) Get(resource, namespace, name string) (runtime.Object, error) {
+	return s.tracker.Get(gvr(resource), namespace, name)
+}
+
+// Handler returns the http handler of the mock server
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/version", s.version)
+	mux.HandleFunc(ManagementPath, s.authenticated(s.management))
+	return mux
+}
+
+func (s *Server) version(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, &auth.Version{Version: Version})
+}
+
+func (s *Server) authenticated(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(token), []byte(s.AccessKey)) != 1 {
+			writeError(w, kerrors.NewUnauthorized("invalid access key"))
+			return
+		}
+
+		next(w, r)
+	}
+}
+
+// request is a parsed management api request
+type request struct {
+	resource    string
+	namespace   string
+	name        string
+	subresource string
+}
+
+func parseRequest(path string) (request, error) {
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(path, ManagementPath), "/"), "/")
+	req := request{}
+	if len(parts) >= 2 && parts[0] == "namespaces" {
+		req.namespace = parts[1]
+		parts = parts[2:]
+	}
+	if len(parts) == 0 || len(parts) > 3 || parts[0] == "" {
+		return req, fmt.Errorf("unsupported path %s", path)
+	}
+
+	req.resource = parts[0]
+	if len(parts) > 1 {
+		req.name = parts[1]
+	}
+	if len(parts) > 2 {
+		req.subresource = parts[2]
+	}
+
+	return req, nil
+}
+
+func (s *Server) management(w http.ResponseWriter, r *http.Request) {
+	req, err := parseRequest(r.URL.Path)
+	if err != nil {
+		writeError(w, kerrors.NewNotFound(schema.GroupResource{}, r.URL.Path))
+		return
+	}
+
+	var obj runtime.Object
+	switch {
+	case req.resource == "selves" && r.Method == http.MethodPost:
+		obj = s.self()
+	case req.resource == "projects" && req.subresource == "clusters" && r.Method == http.MethodGet:
+		obj, err = s.projectClusters(req.name)
+	case resources[req.resource] == "":
+		err = kerrors.NewNotFound(schema.GroupResource{Group: managementv1.SchemeGroupVersion.Group, Resource: req.resource}, req.name)
+	case req.subresource != "":
+		err = kerrors.NewMethodNotSupported(schema.GroupResource{Group: managementv1.SchemeGroupVersion.Group, Resource: req.resource + "/" + req.subresource}, r.Method)
+	case r.Method == http.MethodGet && req.name == "":
+		obj, err = s.list(req, r.URL.Query().Get("labelSelector"))
+	case r.Method == http.MethodGet:
+		obj, err = s.tracker.Get(gvr(req.resource), req.namespace, req.name)
+	case r.Method == http.MethodPost:
+		obj, err = s.create(req, r.Body)
+	case r.Method == http.MethodPut:
+		obj, err = s.update(req, r.Body)
+	case r.Method == http.MethodPatch:
+		obj, err = s.patch(req, r.Header.Get("Content-Type"), r.Body)
+	case r.Method == http.MethodDelete:
+		obj, err = s.delete(req)
+	default:
+		err = kerrors.NewMethodNotSupported(schema.GroupResource{Group: managementv1.SchemeGroupVersion.Group, Resource: req.resource}, r.Method)
+	}
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	status := http.StatusOK
+	if r.Method == http.MethodPost && req.resource != "selves" {
+		status = http.StatusCreated
+	}
+	writeObject(w, status, obj)
+}
+
+func (s *Server) self() *managementv1.Self {
+	return &managementv1.Self{
+		Status: managementv1.SelfStatus{
+			User: &managementv1.UserInfo{
+				EntityInfo: storagev1.EntityInfo{Name: User, Username: User},
+			},
+			Subject:                User,
+			AccessKey:              "mock-access-key",
+			AccessKeyType:          storagev1.AccessKeyTypeUser,
+			ProjectNamespacePrefix: &[]string{ProjectNamespacePrefix}[0],
+		},
+	}
+}
+
+func (s *Server) projectClusters(project string) (runtime.Object, error) {
+	obj, err := s.tracker.Get(gvr("projects"), "", project)
+	if err != nil {
+		return nil, err
+	}
+
+	projectClusters := &managementv1.ProjectClusters{}
+	for _, allowed := range obj.(*managementv1.Project).Spec.AllowedClusters {
+		cluster, err := s.tracker.Get(gvr("clusters"), "", allowed.Name)
+		if err != nil {
+			if kerrors.IsNotFound(err) {
+				continue
+			}
+
+			return nil, err
+		}
+
+		projectClusters.Clusters = append(projectClusters.Clusters, *cluster.(*managementv1.Cluster))
+	}
+
+	return projectClusters, nil
+}
+
+func (s *Server) list(req request, labelSelector string) (runtime.Object, error) {
+	selector, err := labels.Parse(labelSelector)
+	if err != nil {
+		return nil, kerrors.NewBadRequest(err.Error())
+	}
+
+	list, err := s.tracker.List(gvr(req.resource), managementv1.SchemeGroupVersion.WithKind(resources[req.resource]), req.namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := []runtime.Object{}
+	for _, item := range items {
+		accessor, err := meta.Accessor(item)
+		if err != nil {
+			return nil, err
+		}
+
+		if selector.Matches(labels.Set(accessor.GetLabels())) {
+			filtered = append(filtered, item)
+		}
+	}
+
+	return list, meta.SetList(list, filtered)
+}
+
+func (s *Server) create(req request, body io.Reader) (runtime.Object, error) {
+	obj, err := decode(body)
+	if err != nil {
+		return nil, err
+	}
+
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return nil, err
+	}
+	if accessor.GetName() == "" && accessor.GetGenerateName() != "" {
+		accessor.SetName(accessor.GetGenerateName() + strings.ToLower(string(uuid.NewUUID())[:5]))
+	}
+	accessor.SetNamespace(req.namespace)
+	accessor.SetUID(uuid.NewUUID())
+	accessor.SetCreationTimestamp(metav1.Now())
+
+	// virtual cluster instances are ready right away
+	if virtualClusterInstance, ok := obj.(*managementv1.VirtualClusterInstance); ok && virtualClusterInstance.Status.Phase == "" {
+		virtualClusterInstance.Status.Phase = storagev1.InstanceReady
+		virtualClusterInstance.Status.CanUse = true
+		virtualClusterInstance.Status.CanUpdate = true
+	}
+
+	err = s.tracker.Create(gvr(req.resource), obj, req.namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.tracker.Get(gvr(req.resource), req.namespace, accessor.GetName())
+}
+
+func (s *Server) update(req request, body io.Reader) (runtime.Object, error) {
+	obj, err := decode(body)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.store(req, obj)
+}
+
+func (s *Server) patch(req request, contentType string, body io.Reader) (runtime.Object, error) {
+	if contentType != string(types.MergePatchType) && contentType != string(types.JSONPatchType) {
+		return nil, kerrors.NewBadRequest(fmt.Sprintf("unsupported patch type %s", contentType))
+	}
+
+	existing, err := s.tracker.Get(gvr(req.resource), req.namespace, req.name)
+	if err != nil {
+		return nil, err
+	}
+
+	original, err := runtime.Encode(codec, existing)
+	if err != nil {
+		return nil, err
+	}
+
+	patchData, err := io.ReadAll(body)
+	if err != nil {
+		return nil, err
+	}
+
+	var patched []byte
+	if contentType == string(types.MergePatchType) {
+		patched, err = jsonpatch.MergePatch(original, patchData)
+	} else {
+		var jsonPatch jsonpatch.Patch
+		jsonPatch, err = jsonpatch.DecodePatch(patchData)
+		if err == nil {
+			patched, err = jsonPatch.Apply(original)
+		}
+	}
+	if err != nil {
+		return nil, kerrors.NewBadRequest(err.Error())
+	}
+
+	obj, err := decode(bytes.NewReader(patched))
+	if err != nil {
+		return nil, err
+	}
+
+	return s.store(req, obj)
+}
+
+func (s *Server) store(req request, obj runtime.Object) (runtime.Object, error) {
+	// waking up a sleeping virtual cluster instance is done by removing the sleep annotations
+	if virtualClusterInstance, ok := obj.(*managementv1.VirtualClusterInstance); ok && virtualClusterInstance.Status.Phase == storagev1.InstanceSleeping {
+		if virtualClusterInstance.Annotations[clusterv1.SleepModeForceAnnotation] == "" {
+			virtualClusterInstance.Status.Phase = storagev1.InstanceReady
+		}
+	}
+
+	err := s.tracker.Update(gvr(req.resource), obj, req.namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.tracker.Get(gvr(req.resource), req.namespace, req.name)
+}
+
+func (s *Server) delete(req request) (runtime.Object, error) {
+	err := s.tracker.Delete(gvr(req.resource), req.namespace, req.name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &metav1.Status{Status: metav1.StatusSuccess}, nil
+}
+
+// ProjectNamespace returns the namespace of the given project on the mock server
+func ProjectNamespace(project string) string {
+	return ProjectNamespacePrefix + project
+}
+
+func gvr(resource string) schema.GroupVersionResource {
+	return managementv1.SchemeGroupVersion.WithResource(resource)
+}
+
+func decode(body io.Reader) (runtime.Object, error) {
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, err
+	}
+
+	obj, _, err := scheme.Codecs.UniversalDecoder(managementv1.SchemeGroupVersion).Decode(data, nil, nil)
+	if err != nil {
+		return nil, kerrors.NewBadRequest(err.Error())
+	}
+
+	return obj, nil
+}
+
+func writeObject(w http.ResponseWriter, status int, obj runtime.Object) {
+	if _, ok := obj.(*metav1.Status); ok {
+		writeJSON(w, status, obj)
+		return
+	}
+
+	data, err := runtime.Encode(codec, obj)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write(data)
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	var apiStatus kerrors.APIStatus
+	if !errors.As(err, &apiStatus) {
+		apiStatus = kerrors.NewInternalError(err)
+	}
+
+	status := apiStatus.Status()
+	status.TypeMeta = metav1.TypeMeta{Kind: "Status", APIVersion: "v1"}
+	writeJSON(w, int(status.Code), &status)
+}
+
+func writeJSON(w http.ResponseWriter, status int, obj interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(obj)
+}
+
+// Options configure the mock server started by Start
+type Options struct {
+	Address   string
+	AccessKey string
+
+	// Project and Cluster are created on start, so virtual clusters can be created right away
+	Project string
+	Cluster string
+}
+
+// Start serves the mock server with a self-signed certificate until the context is done
+func Start(ctx context.Context, options *Options, log log.Logger) error {
+	if options.AccessKey == "" {
+		return fmt.Errorf("an access key is required to serve the mock platform")
+	}
+
+	mockServer := NewServer(options.AccessKey)
+	if options.Project != "" {
+		err := mockServer.AddProject(options.Project, options.Cluster)
+		if err != nil {
+			return fmt.Errorf("add project %s: %w", options.Project, err)
+		}
+	}
+
+	host, port, err := net.SplitHostPort(options.Address)
+	if err != nil {
+		return fmt.Errorf("parse address %s: %w", options.Address, err)
+	}
+	if host == "" {
+		host = "localhost"
+	}
+
+	certPEM, keyPEM, err := certutil.GenerateSelfSignedCertKey(host, nil, nil)
+	if err != nil {
+		return fmt.Errorf("generate certificate: %w", err)
+	}
+	certificate, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return err
+	}
+
+	server := &http.Server{
+		Addr:              options.Address,
+		Handler:           mockServer.Handler(),
+		TLSConfig:         &tls.Config{Certificates: []tls.Certificate{certificate}, MinVersion: tls.VersionTLS12},
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	log.Infof("Serving mock platform on https://%s", net.JoinHostPort(host, port))
+	err = server.ListenAndServeTLS("", "")
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,93 @@
+package mock
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	clusterv1 "github.com/loft-sh/agentapi/v4/pkg/apis/loft/cluster/v1"
+	managementv1 "github.com/loft-sh/api/v4/pkg/apis/management/v1"
+	storagev1 "github.com/loft-sh/api/v4/pkg/apis/storage/v1"
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/vcluster/pkg/cli/config"
+	"github.com/loft-sh/vcluster/pkg/platform"
+	"gotest.tools/v3/assert"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestServer(t *testing.T) {
+	ctx := context.Background()
+	mockServer := NewServer("test-key")
+	assert.NilError(t, mockServer.AddProject("default", "loft-cluster"))
+	assert.NilError(t, mockServer.Add(&managementv1.VirtualClusterInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "sleeping",
+			Namespace:   ProjectNamespace("default"),
+			Annotations: map[string]string{clusterv1.SleepModeForceAnnotation: "true"},
+		},
+		Status: managementv1.VirtualClusterInstanceStatus{
+			VirtualClusterInstanceStatus: storagev1.VirtualClusterInstanceStatus{Phase: storagev1.InstanceSleeping},
+			CanUse:                       true,
+		},
+	}))
+
+	server := httptest.NewTLSServer(mockServer.Handler())
+	defer server.Close()
+
+	cliConfig := config.New()
+	cliConfig.Platform.Host = server.URL
+	cliConfig.Platform.Insecure = true
+
+	// wrong access keys are rejected
+	cliConfig.Platform.AccessKey = "wrong-key"
+	_, err := platform.InitClientFromConfig(ctx, cliConfig)
+	assert.Assert(t, kerrors.IsUnauthorized(err), err)
+
+	cliConfig.Platform.AccessKey = "test-key"
+	platformClient, err := platform.InitClientFromConfig(ctx, cliConfig)
+	assert.NilError(t, err)
+	assert.Equal(t, platformClient.Self().Status.User.Name, User)
+
+	version, err := platformClient.Version()
+	assert.NilError(t, err)
+	assert.Equal(t, version.Version, Version)
+
+	// create a virtual cluster instance and list it
+	managementClient, err := platformClient.Management()
+	assert.NilError(t, err)
+	_, err = managementClient.Loft().ManagementV1().VirtualClusterInstances(ProjectNamespace("default")).Create(ctx, &managementv1.VirtualClusterInstance{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-vcluster"},
+		Spec: managementv1.VirtualClusterInstanceSpec{
+			VirtualClusterInstanceSpec: storagev1.VirtualClusterInstanceSpec{
+				ClusterRef: storagev1.VirtualClusterClusterRef{ClusterRef: storagev1.ClusterRef{Cluster: "loft-cluster"}},
+			},
+		},
+	}, metav1.CreateOptions{})
+	assert.NilError(t, err)
+
+	vClusters, err := platform.ListVClusters(ctx, platformClient, "", "")
+	assert.NilError(t, err)
+	assert.Equal(t, len(vClusters), 2)
+
+	vClusters, err = platform.ListVClusters(ctx, platformClient, "my-vcluster", "default")
+	assert.NilError(t, err)
+	assert.Equal(t, len(vClusters), 1)
+	assert.Equal(t, vClusters[0].VirtualCluster.Status.Phase, storagev1.InstanceReady)
+
+	contextOptions, err := platform.CreateVirtualClusterInstanceOptions(ctx, platformClient, "", "default", vClusters[0].VirtualCluster, false)
+	assert.NilError(t, err)
+	assert.Equal(t, contextOptions.Server, server.URL+"/kubernetes/project/default/virtualcluster/my-vcluster")
+
+	// sleeping virtual clusters are woken up by patching away the sleep annotations
+	_, err = platform.WaitForVirtualClusterInstance(ctx, managementClient, ProjectNamespace("default"), "sleeping", true, log.Discard)
+	assert.NilError(t, err)
+	obj, err := mockServer.Get("virtualclusterinstances", ProjectNamespace("default"), "sleeping")
+	assert.NilError(t, err)
+	assert.Equal(t, obj.(*managementv1.VirtualClusterInstance).Status.Phase, storagev1.InstanceReady)
+
+	err = managementClient.Loft().ManagementV1().VirtualClusterInstances(ProjectNamespace("default")).Delete(ctx, "sleeping", metav1.DeleteOptions{})
+	assert.NilError(t, err)
+	_, err = mockServer.Get("virtualclusterinstances", ProjectNamespace("default"), "sleeping")
+	assert.Assert(t, kerrors.IsNotFound(err))
+}
@@ -0,0 +1,217 @@
+package platform
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/loft-sh/api/v4/pkg/auth"
+	"github.com/loft-sh/vcluster/pkg/cli/config"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// OIDCClientSecretEnv holds the client secret of the OIDC client credentials login if no secret file is used
+	OIDCClientSecretEnv = "VCLUSTER_OIDC_CLIENT_SECRET"
+
+	OIDCTokenPath     = "%s/auth/oidc/token"
+	oidcDiscoveryPath = "/.well-known/openid-configuration"
+)
+
+// OIDCToken is a token issued by an OIDC provider for the client credentials of a client
+type OIDCToken struct {
+	IDToken     string
+	AccessToken string
+	Expiry      *time.Time
+}
+
+// LoginWithOIDC requests a token for the client credentials from the OIDC provider, exchanges it for an access key
+// of the platform and logs in with it. Nothing is prompted and no browser is opened, so this works in pipelines.
+func (c *client) LoginWithOIDC(ctx context.Context, host string, oidcLogin config.OIDCLogin, insecure bool) error {
+	err := verifyHost(host)
+	if err != nil {
+		return err
+	}
+
+	clientSecret, err := oidcClientSecret(oidcLogin)
+	if err != nil {
+		return err
+	}
+	token, err := RequestOIDCToken(ctx, http.DefaultClient, oidcLogin, clientSecret)
+	if err != nil {
+		return err
+	}
+	accessKey, err := ExchangeOIDCToken(ctx, host, insecure, token)
+	if err != nil {
+		return err
+	}
+
+	err = c.LoginWithAccessKey(host, accessKey, insecure)
+	if err != nil {
+		return err
+	}
+
+	oidcLogin.Expiry = nil
+	if token.Expiry != nil {
+		oidcLogin.Expiry = &metav1.Time{Time: *token.Expiry}
+	}
+	c.Config().Platform.OIDC = &oidcLogin
+	return c.Save()
+}
+
+// refreshOIDCLogin logs in again if the access key was issued for an OIDC token that is about to expire. If the login
+// can't be refreshed, e.g. because the client secret isn't available anymore, the current access key is used until
+// the token is expired.
+func (c *client) refreshOIDCLogin(ctx context.Context) error {
+	platformConfig := c.Config().Platform
+	oidcLogin := platformConfig.OIDC
+	if oidcLogin == nil || oidcLogin.Expiry == nil || time.Now().Add(RefreshToken).Before(oidcLogin.Expiry.Time) {
+		return nil
+	}
+
+	err := c.LoginWithOIDC(ctx, platformConfig.Host, *oidcLogin, platformConfig.Insecure)
+	if err != nil && time.Now().After(oidcLogin.Expiry.Time) {
+		return fmt.Errorf("refresh expired oidc login: %w", err)
+	}
+
+	return nil
+}
+
+func oidcClientSecret(oidcLogin config.OIDCLogin) (string, error) {
+	if oidcLogin.ClientSecretFile == "" {
+		clientSecret := os.Getenv(OIDCClientSecretEnv)
+		if clientSecret == "" {
+			return "", fmt.Errorf("please specify the oidc client secret via a file or the %s environment variable", OIDCClientSecretEnv)
+		}
+
+		return clientSecret, nil
+	}
+
+	out, err := os.ReadFile(oidcLogin.ClientSecretFile)
+	if err != nil {
+		return "", fmt.Errorf("read oidc client secret: %w", err)
+	}
+
+	return strings.TrimSpace(string(out)), nil
+}
+
+// RequestOIDCToken requests a token for the client credentials from the token endpoint of the OIDC provider
+func RequestOIDCToken(ctx context.Context, httpClient *http.Client, oidcLogin config.OIDCLogin, clientSecret string) (*OIDCToken, error) {
+	tokenEndpoint, err := discoverTokenEndpoint(ctx, httpClient, oidcLogin.Issuer)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "client_credentials")
+	form.Set("scope", strings.Join(append([]string{"openid"}, oidcLogin.Scopes...), " "))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.SetBasicAuth(url.QueryEscape(oidcLogin.ClientID), url.QueryEscape(clientSecret))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	tokenResponse := struct {
+		IDToken     string `json:"id_token"`
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}{}
+	err = doJSON(httpClient, req, &tokenResponse)
+	if err != nil {
+		return nil, fmt.Errorf("request oidc token: %w", err)
+	} else if tokenResponse.IDToken == "" && tokenResponse.AccessToken == "" {
+		return nil, errors.New("request oidc token: response contains no token")
+	}
+
+	token := &OIDCToken{
+		IDToken:     tokenResponse.IDToken,
+		AccessToken: tokenResponse.AccessToken,
+	}
+	if tokenResponse.ExpiresIn > 0 {
+		expiry := time.Now().Add(time.Duration(tokenResponse.ExpiresIn) * time.Second)
+		token.Expiry = &expiry
+	}
+
+	return token, nil
+}
+
+// ExchangeOIDCToken exchanges the OIDC token for an access key of the platform
+func ExchangeOIDCToken(ctx context.Context, host string, insecure bool, token *OIDCToken) (string, error) {
+	body, err := json.Marshal(&auth.OIDCTokenRequest{
+		Token:       token.IDToken,
+		AccessToken: token.AccessToken,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf(OIDCTokenPath, host), bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	httpClient := http.DefaultClient
+	if insecure {
+		httpClient = &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}}
+	}
+	accessKey := &auth.AccessKey{}
+	err = doJSON(httpClient, req, accessKey)
+	if err != nil {
+		return "", fmt.Errorf("exchange oidc token: %w", err)
+	} else if accessKey.AccessKey == "" {
+		return "", errors.New("exchange oidc token: response contains no access key")
+	}
+
+	return accessKey.AccessKey, nil
+}
+
+func discoverTokenEndpoint(ctx context.Context, httpClient *http.Client, issuer string) (string, error) {
+	if issuer == "" {
+		return "", errors.New("please specify the oidc issuer")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(issuer, "/")+oidcDiscoveryPath, nil)
+	if err != nil {
+		return "", err
+	}
+
+	discovery := struct {
+		TokenEndpoint string `json:"token_endpoint"`
+	}{}
+	err = doJSON(httpClient, req, &discovery)
+	if err != nil {
+		return "", fmt.Errorf("discover oidc provider %s: %w", issuer, err)
+	} else if discovery.TokenEndpoint == "" {
+		return "", fmt.Errorf("discover oidc provider %s: no token endpoint", issuer)
+	}
+
+	return discovery.TokenEndpoint, nil
+}
+
+func doJSON(httpClient *http.Client, req *http.Request, into interface{}) error {
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	} else if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	return json.Unmarshal(body, into)
+}
@@ -0,0 +1,77 @@
+package platform
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/loft-sh/api/v4/pkg/auth"
+	"github.com/loft-sh/vcluster/pkg/cli/config"
+	"gotest.tools/v3/assert"
+)
+
+func TestRequestOIDCToken(t *testing.T) {
+	var issuer *httptest.Server
+	issuer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case oidcDiscoveryPath:
+			_ = json.NewEncoder(w).Encode(map[string]string{"token_endpoint": issuer.URL + "/token"})
+		case "/token":
+			clientID, clientSecret, _ := r.BasicAuth()
+			if clientID != "ci" || clientSecret != "secret" || r.FormValue("grant_type") != "client_credentials" || r.FormValue("scope") != "openid groups" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"id_token": "id", "access_token": "access", "expires_in": 3600})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer issuer.Close()
+
+	oidcLogin := config.OIDCLogin{Issuer: issuer.URL + "/", ClientID: "ci", Scopes: []string{"groups"}}
+	token, err := RequestOIDCToken(context.Background(), issuer.Client(), oidcLogin, "secret")
+	assert.NilError(t, err)
+	assert.Equal(t, token.IDToken, "id")
+	assert.Equal(t, token.AccessToken, "access")
+	assert.Assert(t, token.Expiry != nil && token.Expiry.After(time.Now().Add(59*time.Minute)))
+
+	_, err = RequestOIDCToken(context.Background(), issuer.Client(), oidcLogin, "wrong")
+	assert.ErrorContains(t, err, "unexpected status code 401")
+}
+
+func TestExchangeOIDCToken(t *testing.T) {
+	platform := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request := &auth.OIDCTokenRequest{}
+		_ = json.NewDecoder(r.Body).Decode(request)
+		if r.URL.Path != "/auth/oidc/token" || request.Token != "id" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		_ = json.NewEncoder(w).Encode(&auth.AccessKey{AccessKey: "platform-key"})
+	}))
+	defer platform.Close()
+
+	accessKey, err := ExchangeOIDCToken(context.Background(), platform.URL, true, &OIDCToken{IDToken: "id"})
+	assert.NilError(t, err)
+	assert.Equal(t, accessKey, "platform-key")
+
+	_, err = ExchangeOIDCToken(context.Background(), platform.URL, true, &OIDCToken{IDToken: "other"})
+	assert.ErrorContains(t, err, "exchange oidc token")
+}
+
+func TestOIDCClientSecret(t *testing.T) {
+	t.Setenv(OIDCClientSecretEnv, "")
+	_, err := oidcClientSecret(config.OIDCLogin{})
+	assert.ErrorContains(t, err, OIDCClientSecretEnv)
+
+	t.Setenv(OIDCClientSecretEnv, "from-env")
+	clientSecret, err := oidcClientSecret(config.OIDCLogin{})
+	assert.NilError(t, err)
+	assert.Equal(t, clientSecret, "from-env")
+}
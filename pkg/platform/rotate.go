@@ -0,0 +1,110 @@
+package platform
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	managementv1 "github.com/loft-sh/api/v4/pkg/apis/management/v1"
+	"github.com/loft-sh/vcluster/pkg/cli/config"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// RotatedAccessKey describes an access key rotation of a platform secret
+type RotatedAccessKey struct {
+	// Name is the name of the new access key
+	Name string
+
+	// PreviousName is the name of the replaced access key if it was created by a previous rotation. Access keys that
+	// were not created by a rotation might be shared with other virtual clusters and are not returned.
+	PreviousName string
+}
+
+// RotatePlatformSecret creates a new access key for the virtual cluster and stores it in its platform secret
+func RotatePlatformSecret(ctx context.Context, config *config.CLI, kubeClient kubernetes.Interface, vClusterName, namespace string) (*RotatedAccessKey, error) {
+	keySecret, err := kubeClient.CoreV1().Secrets(namespace).Get(ctx, DefaultPlatformSecretName, metav1.GetOptions{})
+	if kerrors.IsNotFound(err) {
+		return nil, fmt.Errorf("platform secret %s/%s not found, please add the virtual cluster to the platform via `vcluster platform add vcluster` first", namespace, DefaultPlatformSecretName)
+	} else if err != nil {
+		return nil, fmt.Errorf("error getting platform secret %s/%s: %w", namespace, DefaultPlatformSecretName, err)
+	}
+
+	platformClient, err := InitClientFromConfig(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	accessKey, err := createVirtualClusterAccessKey(ctx, platformClient, fmt.Sprintf("vCluster %s/%s Access Key", namespace, vClusterName))
+	if err != nil {
+		return nil, err
+	}
+	err = validateAccessKey(ctx, platformClient, accessKey.Spec.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	rotated := &RotatedAccessKey{
+		Name:         accessKey.Name,
+		PreviousName: keySecret.Annotations[AccessKeyNameAnnotation],
+	}
+
+	patch := ctrlclient.MergeFrom(keySecret.DeepCopy())
+	if keySecret.Annotations == nil {
+		keySecret.Annotations = map[string]string{}
+	}
+	keySecret.Annotations[AccessKeyNameAnnotation] = accessKey.Name
+	keySecret.Annotations[AccessKeyRotatedAnnotation] = time.Now().UTC().Format(time.RFC3339)
+	keySecret.Data["accessKey"] = []byte(accessKey.Spec.Key)
+	patchBytes, err := patch.Data(keySecret)
+	if err != nil {
+		return nil, fmt.Errorf("error creating patch for platform secret %s/%s: %w", namespace, DefaultPlatformSecretName, err)
+	}
+	_, err = kubeClient.CoreV1().Secrets(namespace).Patch(ctx, keySecret.Name, patch.Type(), patchBytes, metav1.PatchOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error patching platform secret %s/%s: %w", namespace, DefaultPlatformSecretName, err)
+	}
+
+	return rotated, nil
+}
+
+// RevokeAccessKey deletes the access key with the given name
+func RevokeAccessKey(ctx context.Context, config *config.CLI, name string) error {
+	platformClient, err := InitClientFromConfig(ctx, config)
+	if err != nil {
+		return err
+	}
+	managementClient, err := platformClient.Management()
+	if err != nil {
+		return fmt.Errorf("create management client: %w", err)
+	}
+
+	err = managementClient.Loft().ManagementV1().OwnedAccessKeys().Delete(ctx, name, metav1.DeleteOptions{})
+	if err != nil && !kerrors.IsNotFound(err) {
+		return fmt.Errorf("delete access key %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// validateAccessKey returns an error if the platform doesn't accept the access key
+func validateAccessKey(ctx context.Context, platformClient Client, accessKey string) error {
+	managementClient, err := platformClient.Management()
+	if err != nil {
+		return fmt.Errorf("create management client: %w", err)
+	}
+
+	selfCtx, cancel := context.WithTimeout(ctx, 1*time.Minute)
+	defer cancel()
+	_, err = managementClient.Loft().ManagementV1().Selves().Create(selfCtx, &managementv1.Self{
+		Spec: managementv1.SelfSpec{
+			AccessKey: accessKey,
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("validate access key: %w", err)
+	}
+
+	return nil
+}
@@ -20,6 +20,17 @@ import (
 
 const DefaultPlatformSecretName = "vcluster-platform-api-key"
 
+const (
+	// AccessKeyNameAnnotation holds the name of the access key created for the virtual cluster by RotatePlatformSecret
+	AccessKeyNameAnnotation = "vcluster.loft.sh/access-key-name"
+
+	// AccessKeyRotatedAnnotation holds the time the access key of the platform secret was rotated last
+	AccessKeyRotatedAnnotation = "vcluster.loft.sh/access-key-rotated"
+)
+
+// ApplyPlatformSecret creates or updates the platform secret of the virtual cluster and returns true if it changed.
+// If no access key is given, an access key created by RotatePlatformSecret is kept, so adding the virtual cluster
+// again doesn't revert a rotation.
 func ApplyPlatformSecret(
 	ctx context.Context,
 	config *config.CLI,
@@ -30,11 +41,19 @@ func ApplyPlatformSecret(
 	accessKey,
 	host string,
 	insecure bool,
-) error {
-	var err error
+) (bool, error) {
+	// check if secret already exists
+	keySecret, err := kubeClient.CoreV1().Secrets(namespace).Get(ctx, DefaultPlatformSecretName, metav1.GetOptions{})
+	exists := err == nil
+	if err != nil && !kerrors.IsNotFound(err) {
+		return false, fmt.Errorf("error getting platform secret %s/%s: %w", namespace, DefaultPlatformSecretName, err)
+	} else if exists && accessKey == "" && keySecret.Annotations[AccessKeyNameAnnotation] != "" {
+		accessKey = string(keySecret.Data["accessKey"])
+	}
+
 	accessKey, host, insecure, err = getAccessKeyAndHost(ctx, config, accessKey, host, insecure)
 	if err != nil {
-		return fmt.Errorf("get access key and host: %w", err)
+		return false, fmt.Errorf("get access key and host: %w", err)
 	}
 
 	// build secret payload
@@ -50,11 +69,7 @@ func ApplyPlatformSecret(
 		payload["name"] = []byte(importName)
 	}
 
-	// check if secret already exists
-	keySecret, err := kubeClient.CoreV1().Secrets(namespace).Get(ctx, DefaultPlatformSecretName, metav1.GetOptions{})
-	if err != nil && !kerrors.IsNotFound(err) {
-		return fmt.Errorf("error getting platform secret %s/%s: %w", namespace, DefaultPlatformSecretName, err)
-	} else if kerrors.IsNotFound(err) {
+	if !exists {
 		_, err = kubeClient.CoreV1().Secrets(namespace).Create(ctx, &corev1.Secret{
 			ObjectMeta: metav1.ObjectMeta{
 				Name:      DefaultPlatformSecretName,
@@ -63,13 +78,13 @@ func ApplyPlatformSecret(
 			Data: payload,
 		}, metav1.CreateOptions{})
 		if err != nil {
-			return fmt.Errorf("error creating platform secret %s/%s: %w", namespace, DefaultPlatformSecretName, err)
+			return false, fmt.Errorf("error creating platform secret %s/%s: %w", namespace, DefaultPlatformSecretName, err)
 		}
 
-		return nil
+		return true, nil
 	} else if reflect.DeepEqual(keySecret.Data, payload) {
 		// no update needed, just return
-		return nil
+		return false, nil
 	}
 
 	// create the patch
@@ -77,16 +92,23 @@ func ApplyPlatformSecret(
 	keySecret.Data = payload
 	patchBytes, err := patch.Data(keySecret)
 	if err != nil {
-		return fmt.Errorf("error creating patch for platform secret %s/%s: %w", namespace, DefaultPlatformSecretName, err)
+		return false, fmt.Errorf("error creating patch for platform secret %s/%s: %w", namespace, DefaultPlatformSecretName, err)
 	}
 
 	// patch the secret
 	_, err = kubeClient.CoreV1().Secrets(namespace).Patch(ctx, keySecret.Name, patch.Type(), patchBytes, metav1.PatchOptions{})
 	if err != nil {
-		return fmt.Errorf("error patching platform secret %s/%s: %w", namespace, DefaultPlatformSecretName, err)
+		return false, fmt.Errorf("error patching platform secret %s/%s: %w", namespace, DefaultPlatformSecretName, err)
 	}
 
-	return nil
+	return true, nil
+}
+
+// EnsureVirtualClusterAccessKey creates the access key the CLI shares between the virtual clusters it adds to the
+// platform if it doesn't exist yet. Adding virtual clusters concurrently would create one access key each otherwise.
+func EnsureVirtualClusterAccessKey(ctx context.Context, config *config.CLI) error {
+	_, _, _, err := getAccessKeyAndHost(ctx, config, "", "", false)
+	return err
 }
 
 func getAccessKeyAndHost(ctx context.Context, config *config.CLI, accessKey, host string, insecure bool) (string, string, bool, error) {
@@ -132,33 +154,9 @@ func getAccessKeyAndHost(ctx context.Context, config *config.CLI, accessKey, hos
 
 	// check if we need to create a virtual cluster access key
 	if platformConfig.VirtualClusterAccessKey == "" {
-		user := ""
-		team := ""
-		if platformClient.Self().Status.User != nil {
-			user = platformClient.Self().Status.User.Name
-		}
-		if platformClient.Self().Status.Team != nil {
-			team = platformClient.Self().Status.Team.Name
-		}
-
-		accessKey, err := managementClient.Loft().ManagementV1().OwnedAccessKeys().Create(ctx, &managementv1.OwnedAccessKey{
-			Spec: managementv1.OwnedAccessKeySpec{
-				AccessKeySpec: storagev1.AccessKeySpec{
-					DisplayName: "vCluster CLI Activation Key",
-					User:        user,
-					Team:        team,
-					Scope: &storagev1.AccessKeyScope{
-						Roles: []storagev1.AccessKeyScopeRole{
-							{
-								Role: storagev1.AccessKeyScopeRoleVCluster,
-							},
-						},
-					},
-				},
-			},
-		}, metav1.CreateOptions{})
+		accessKey, err := createVirtualClusterAccessKey(ctx, platformClient, "vCluster CLI Activation Key")
 		if err != nil {
-			return "", "", false, fmt.Errorf("create owned access key: %w", err)
+			return "", "", false, err
 		}
 
 		platformConfig.VirtualClusterAccessKey = accessKey.Spec.Key
@@ -170,3 +168,42 @@ func getAccessKeyAndHost(ctx context.Context, config *config.CLI, accessKey, hos
 
 	return platformConfig.VirtualClusterAccessKey, host, insecure, nil
 }
+
+// createVirtualClusterAccessKey creates an access key with the vCluster scope for the user or team of the platform client
+func createVirtualClusterAccessKey(ctx context.Context, platformClient Client, displayName string) (*managementv1.OwnedAccessKey, error) {
+	managementClient, err := platformClient.Management()
+	if err != nil {
+		return nil, fmt.Errorf("create management client: %w", err)
+	}
+
+	user := ""
+	team := ""
+	if platformClient.Self().Status.User != nil {
+		user = platformClient.Self().Status.User.Name
+	}
+	if platformClient.Self().Status.Team != nil {
+		team = platformClient.Self().Status.Team.Name
+	}
+
+	accessKey, err := managementClient.Loft().ManagementV1().OwnedAccessKeys().Create(ctx, &managementv1.OwnedAccessKey{
+		Spec: managementv1.OwnedAccessKeySpec{
+			AccessKeySpec: storagev1.AccessKeySpec{
+				DisplayName: displayName,
+				User:        user,
+				Team:        team,
+				Scope: &storagev1.AccessKeyScope{
+					Roles: []storagev1.AccessKeyScopeRole{
+						{
+							Role: storagev1.AccessKeyScopeRoleVCluster,
+						},
+					},
+				},
+			},
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("create owned access key: %w", err)
+	}
+
+	return accessKey, nil
+}
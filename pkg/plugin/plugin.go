@@ -9,6 +9,7 @@ import (
 	plugintypes "github.com/loft-sh/vcluster/pkg/plugin/types"
 	pluginv1 "github.com/loft-sh/vcluster/pkg/plugin/v1"
 	pluginv2 "github.com/loft-sh/vcluster/pkg/plugin/v2"
+	"github.com/loft-sh/vcluster/pkg/plugin/wasm"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/rest"
 	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
@@ -24,12 +25,14 @@ func newManager() plugintypes.Manager {
 	return &manager{
 		legacyManager: pluginv1.NewManager(),
 		pluginManager: pluginv2.NewManager(),
+		wasmManager:   wasm.NewManager(),
 	}
 }
 
 type manager struct {
 	legacyManager *pluginv1.Manager
 	pluginManager *pluginv2.Manager
+	wasmManager   *wasm.Manager
 }
 
 func (m *manager) Start(
@@ -53,6 +56,11 @@ func (m *manager) Start(
 		return fmt.Errorf("start plugins: %w", err)
 	}
 
+	err = m.wasmManager.Start(ctx, vConfig)
+	if err != nil {
+		return fmt.Errorf("start wasm hooks: %w", err)
+	}
+
 	return nil
 }
 
@@ -72,19 +80,24 @@ func (m *manager) MutateObject(ctx context.Context, obj client.Object, hookType
 		return err
 	}
 
+	err = m.wasmManager.MutateObject(ctx, obj, hookType, scheme)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
 func (m *manager) HasClientHooks() bool {
-	return m.legacyManager.HasClientHooks() || m.pluginManager.HasClientHooks()
+	return m.legacyManager.HasClientHooks() || m.pluginManager.HasClientHooks() || m.wasmManager.HasClientHooks()
 }
 
 func (m *manager) HasClientHooksForType(versionKindType plugintypes.VersionKindType) bool {
-	return m.legacyManager.HasClientHooksForType(versionKindType) || m.pluginManager.HasClientHooksForType(versionKindType)
+	return m.legacyManager.HasClientHooksForType(versionKindType) || m.pluginManager.HasClientHooksForType(versionKindType) || m.wasmManager.HasClientHooksForType(versionKindType)
 }
 
 func (m *manager) HasPlugins() bool {
-	return m.legacyManager.HasPlugins() || m.pluginManager.HasPlugins()
+	return m.legacyManager.HasPlugins() || m.pluginManager.HasPlugins() || m.wasmManager.HasClientHooks()
 }
 
 func (m *manager) SetProFeatures(proFeatures map[string]bool) {
@@ -13,6 +13,8 @@ import (
 	"slices"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/ghodss/yaml"
@@ -22,6 +24,7 @@ import (
 	plugintypes "github.com/loft-sh/vcluster/pkg/plugin/types"
 	"github.com/loft-sh/vcluster/pkg/plugin/v2/pluginv2"
 	"github.com/loft-sh/vcluster/pkg/util/kubeconfig"
+	"google.golang.org/grpc"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apiserver/pkg/endpoints/request"
 	"k8s.io/client-go/tools/clientcmd"
@@ -64,6 +67,14 @@ type Manager struct {
 	NonResourceInterceptorsPorts map[string]map[string]portHandlerName
 	// ProFeatures are pro features to hand-over to the plugin
 	ProFeatures map[string]bool
+
+	// m guards the plugins, client hooks and interceptors, which change when plugins are reloaded
+	m sync.RWMutex
+
+	syncerConfig *clientcmdapi.Config
+	vConfig      *config.VirtualClusterConfig
+	isLeader     bool
+	nextPort     int
 }
 
 type portHandlerName struct {
@@ -75,11 +86,29 @@ type vClusterPlugin struct {
 	// Path is the path where the plugin was loaded from
 	Path string
 
+	// Address is the grpc address of a plugin that runs in its own container, such plugins have no path
+	Address string
+
+	// Port is the port the plugin serves its interceptors on
+	Port int
+
 	// Client is the plugin client
 	Client *plugin.Client
 
 	// GRPCClient is the direct grpc client
 	GRPCClient pluginv2.PluginClient
+
+	conn         *grpc.ClientConn
+	modTime      time.Time
+	disconnected atomic.Bool
+}
+
+func (p *vClusterPlugin) String() string {
+	if p.Address != "" {
+		return p.Address
+	}
+
+	return p.Path
 }
 
 func (m *Manager) Start(
@@ -87,72 +116,112 @@ func (m *Manager) Start(
 	syncerConfig *clientcmdapi.Config,
 	vConfig *config.VirtualClusterConfig,
 ) error {
+	m.syncerConfig = syncerConfig
+	m.vConfig = vConfig
+	m.nextPort = 13370
+
 	// try to search for plugins
 	plugins, err := m.findPlugins(ctx)
 	if err != nil {
 		return fmt.Errorf("find plugins: %w", err)
-	} else if len(plugins) == 0 {
-		return nil
 	}
 
 	// loop over plugins and load them
 	for _, pluginPath := range plugins {
-		err = m.loadPlugin(pluginPath, vConfig)
+		vClusterPlugin, err := m.loadPlugin(pluginPath, vConfig)
 		if err != nil {
 			return fmt.Errorf("start plugin %s: %w", pluginPath, err)
 		}
+
+		m.Plugins = append(m.Plugins, vClusterPlugin)
+	}
+
+	// connect to the plugins that run in their own container
+	for _, name := range externalPlugins(vConfig) {
+		vClusterPlugin, err := dialPlugin(vConfig.Plugins[name].Address)
+		if err != nil {
+			return fmt.Errorf("start plugin %s: %w", name, err)
+		}
+
+		m.Plugins = append(m.Plugins, vClusterPlugin)
+	}
+	if len(m.Plugins) == 0 {
+		return nil
 	}
 
-	port := 13370
 	// after loading all plugins we start them
 	for _, vClusterPlugin := range m.Plugins {
-		// build the start request
-		initRequest, err := m.buildInitRequest(filepath.Dir(vClusterPlugin.Path), syncerConfig, vConfig, port)
+		vClusterPlugin.Port = m.nextPort
+		m.nextPort++
 
+		err = m.initializePlugin(ctx, vClusterPlugin)
 		if err != nil {
-			return fmt.Errorf("build start request: %w", err)
+			return err
 		}
+	}
 
-		// start the plugin
-		_, err = vClusterPlugin.GRPCClient.Initialize(ctx, initRequest)
-		if err != nil {
-			return fmt.Errorf("error starting plugin %s: %w", vClusterPlugin.Path, err)
-		}
+	// health check the plugins and reload them if the plugin folder changes
+	go m.supervise(ctx)
+	return nil
+}
 
-		// get plugin config
-		pluginConfigResponse, err := vClusterPlugin.GRPCClient.GetPluginConfig(ctx, &pluginv2.GetPluginConfig_Request{})
-		if err != nil {
-			return fmt.Errorf("error retrieving client hooks for plugin %s: %w", vClusterPlugin.Path, err)
-		}
+// initializePlugin starts the plugin and registers the client hooks and interceptors declared in its plugin config
+func (m *Manager) initializePlugin(ctx context.Context, vClusterPlugin *vClusterPlugin) error {
+	workingDir := ""
+	if vClusterPlugin.Path != "" {
+		workingDir = filepath.Dir(vClusterPlugin.Path)
+	}
 
-		// parse plugin config
-		pluginConfig, err := parsePluginConfig(pluginConfigResponse.Config)
-		if err != nil {
-			return fmt.Errorf("error parsing plugin config: %w", err)
-		}
+	// build the start request
+	initRequest, err := m.buildInitRequest(workingDir, m.syncerConfig, m.vConfig, vClusterPlugin.Port)
+	if err != nil {
+		return fmt.Errorf("build start request: %w", err)
+	}
 
-		// register client hooks
-		err = m.registerClientHooks(vClusterPlugin, pluginConfig.ClientHooks)
-		if err != nil {
-			return fmt.Errorf("error adding client hook for plugin %s: %w", vClusterPlugin.Path, err)
-		}
+	// start the plugin, plugins in their own container might not be up yet
+	err = initialize(ctx, vClusterPlugin, initRequest)
+	if err != nil {
+		return fmt.Errorf("error starting plugin %s: %w", vClusterPlugin, err)
+	}
 
-		// register Interceptors
-		err = m.registerInterceptors(pluginConfig.Interceptors, port)
-		if err != nil {
-			return fmt.Errorf("error adding interceptor for plugin %s: %w", vClusterPlugin.Path, err)
-		}
+	// get plugin config
+	pluginConfigResponse, err := vClusterPlugin.GRPCClient.GetPluginConfig(ctx, &pluginv2.GetPluginConfig_Request{})
+	if err != nil {
+		return fmt.Errorf("error retrieving client hooks for plugin %s: %w", vClusterPlugin, err)
+	}
 
-		klog.FromContext(ctx).Info("Successfully loaded plugin", "plugin", vClusterPlugin.Path)
+	// parse plugin config
+	pluginConfig, err := parsePluginConfig(pluginConfigResponse.Config)
+	if err != nil {
+		return fmt.Errorf("error parsing plugin config: %w", err)
+	}
+
+	m.m.Lock()
+	defer m.m.Unlock()
+
+	// register client hooks
+	err = m.registerClientHooks(vClusterPlugin, pluginConfig.ClientHooks)
+	if err != nil {
+		m.unregister(vClusterPlugin)
+		return fmt.Errorf("error adding client hook for plugin %s: %w", vClusterPlugin, err)
+	}
 
-		port++
+	// register Interceptors
+	err = m.registerInterceptors(pluginConfig.Interceptors, vClusterPlugin.Port)
+	if err != nil {
+		m.unregister(vClusterPlugin)
+		return fmt.Errorf("error adding interceptor for plugin %s: %w", vClusterPlugin, err)
 	}
 
+	klog.FromContext(ctx).Info("Successfully loaded plugin", "plugin", vClusterPlugin.String())
 	return nil
 }
 
 // interceptorPortForResource returns the port and handler name for the given group, resource and verb
 func (m *Manager) interceptorPortForResource(group, resource, verb, resourceName string) (bool, int, string) {
+	m.m.RLock()
+	defer m.m.RUnlock()
+
 	groups := m.ResourceInterceptorsPorts
 	if resourcesMap, ok := groups[group]; ok {
 		portHandlerName, ok := portForResource(resourcesMap, resource, verb, resourceName)
@@ -216,6 +285,9 @@ func portForResourceName(resourceNames map[string]portHandlerName, resourceName
 
 // InterceptorPortForNonResourceURL returns the port and handler name for the given nonResourceUrl and verb
 func (m *Manager) InterceptorPortForNonResourceURL(path, verb string) (bool, int, string) {
+	m.m.RLock()
+	defer m.m.RUnlock()
+
 	// matchedPath will contain either the original path or the wildcard path that matched
 	matchedPath := ""
 	ok := false
@@ -262,7 +334,9 @@ func (m *Manager) MutateObject(ctx context.Context, obj client.Object, hookType
 		Kind:       kind,
 		Type:       hookType,
 	}
-	clientHooks := m.ClientHooks[versionKindType]
+	m.m.RLock()
+	clientHooks := slices.Clone(m.ClientHooks[versionKindType])
+	m.m.RUnlock()
 	if len(clientHooks) == 0 {
 		return nil
 	}
@@ -291,7 +365,7 @@ func (m *Manager) mutateObject(ctx context.Context, versionKindType plugintypes.
 	ctx, cancel := context.WithTimeout(ctx, time.Second*10)
 	defer cancel()
 
-	klog.FromContext(ctx).V(1).Info("calling plugin to mutate object", "plugin", plugin.Path, "apiVersion", versionKindType.APIVersion, "kind", versionKindType.Kind)
+	klog.FromContext(ctx).V(1).Info("calling plugin to mutate object", "plugin", plugin.String(), "apiVersion", versionKindType.APIVersion, "kind", versionKindType.Kind)
 	mutateResult, err := plugin.GRPCClient.Mutate(ctx, &pluginv2.Mutate_Request{
 		ApiVersion: versionKindType.APIVersion,
 		Kind:       versionKindType.Kind,
@@ -299,7 +373,7 @@ func (m *Manager) mutateObject(ctx context.Context, versionKindType plugintypes.
 		Type:       versionKindType.Type,
 	})
 	if err != nil {
-		return nil, fmt.Errorf("call plugin mutate %s: %w", plugin.Path, err)
+		return nil, fmt.Errorf("call plugin mutate %s: %w", plugin, err)
 	}
 
 	if mutateResult.Mutated {
@@ -309,10 +383,15 @@ func (m *Manager) mutateObject(ctx context.Context, versionKindType plugintypes.
 }
 
 func (m *Manager) SetLeader(ctx context.Context) error {
-	for _, vClusterPlugin := range m.Plugins {
+	m.m.Lock()
+	m.isLeader = true
+	plugins := slices.Clone(m.Plugins)
+	m.m.Unlock()
+
+	for _, vClusterPlugin := range plugins {
 		_, err := vClusterPlugin.GRPCClient.SetLeader(ctx, &pluginv2.SetLeader_Request{})
 		if err != nil {
-			return fmt.Errorf("error setting leader in plugin %s: %w", vClusterPlugin.Path, err)
+			return fmt.Errorf("error setting leader in plugin %s: %w", vClusterPlugin, err)
 		}
 	}
 
@@ -320,14 +399,23 @@ func (m *Manager) SetLeader(ctx context.Context) error {
 }
 
 func (m *Manager) HasClientHooksForType(versionKindType plugintypes.VersionKindType) bool {
+	m.m.RLock()
+	defer m.m.RUnlock()
+
 	return len(m.ClientHooks[versionKindType]) > 0
 }
 
 func (m *Manager) HasClientHooks() bool {
+	m.m.RLock()
+	defer m.m.RUnlock()
+
 	return len(m.ClientHooks) > 0
 }
 
 func (m *Manager) HasPlugins() bool {
+	m.m.RLock()
+	defer m.m.RUnlock()
+
 	return len(m.Plugins) > 0
 }
 
@@ -585,9 +673,9 @@ func (m *Manager) registerNonResourceURL(port int, interceptorsInfos Interceptor
 func (m *Manager) registerClientHooks(vClusterPlugin *vClusterPlugin, clientHooks []*ClientHook) error {
 	for _, clientHookInfo := range clientHooks {
 		if clientHookInfo.APIVersion == "" {
-			return fmt.Errorf("api version is empty in plugin %s hook", vClusterPlugin.Path)
+			return fmt.Errorf("api version is empty in plugin %s hook", vClusterPlugin)
 		} else if clientHookInfo.Kind == "" {
-			return fmt.Errorf("kind is empty in plugin %s hook", vClusterPlugin.Path)
+			return fmt.Errorf("kind is empty in plugin %s hook", vClusterPlugin)
 		}
 
 		for _, t := range clientHookInfo.Types {
@@ -604,7 +692,7 @@ func (m *Manager) registerClientHooks(vClusterPlugin *vClusterPlugin, clientHook
 			m.ClientHooks[versionKindType] = append(m.ClientHooks[versionKindType], vClusterPlugin)
 		}
 
-		klog.Infof("Register client hook for %s %s in plugin %s", clientHookInfo.APIVersion, clientHookInfo.Kind, vClusterPlugin.Path)
+		klog.Infof("Register client hook for %s %s in plugin %s", clientHookInfo.APIVersion, clientHookInfo.Kind, vClusterPlugin)
 	}
 
 	return nil
@@ -695,7 +783,7 @@ func (m *Manager) buildInitRequest(
 	}, nil
 }
 
-func (m *Manager) loadPlugin(pluginPath string, vConfig *config.VirtualClusterConfig) error {
+func (m *Manager) loadPlugin(pluginPath string, vConfig *config.VirtualClusterConfig) (*vClusterPlugin, error) {
 	// Create an hclog.Logger
 	logger := hclog.New(&hclog.LoggerOptions{
 		Name:   "plugin",
@@ -706,7 +794,11 @@ func (m *Manager) loadPlugin(pluginPath string, vConfig *config.VirtualClusterCo
 	// build command
 	cmd, err := buildCommand(pluginPath, vConfig)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	stat, err := os.Stat(pluginPath)
+	if err != nil {
+		return nil, err
 	}
 
 	// connect to plugin
@@ -727,24 +819,22 @@ func (m *Manager) loadPlugin(pluginPath string, vConfig *config.VirtualClusterCo
 	rpcClient, err := pluginClient.Client()
 	if err != nil {
 		pluginClient.Kill()
-		return err
+		return nil, err
 	}
 
 	// Request the plugin
 	raw, err := rpcClient.Dispense("plugin")
 	if err != nil {
 		pluginClient.Kill()
-		return err
+		return nil, err
 	}
 
-	// add to loaded plugins
-	m.Plugins = append(m.Plugins, &vClusterPlugin{
+	return &vClusterPlugin{
 		Path:       pluginPath,
 		Client:     pluginClient,
 		GRPCClient: raw.(pluginv2.PluginClient),
-	})
-
-	return nil
+		modTime:    stat.ModTime(),
+	}, nil
 }
 
 func (m *Manager) findPlugins(ctx context.Context) ([]string, error) {
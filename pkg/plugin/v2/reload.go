@@ -0,0 +1,317 @@
+package v2
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/loft-sh/vcluster/pkg/config"
+	"github.com/loft-sh/vcluster/pkg/plugin/v2/pluginv2"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/credentials/insecure"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+)
+
+var (
+	// HealthCheckInterval is the interval in which plugins are health checked and the plugin folder is checked for changes
+	HealthCheckInterval = 10 * time.Second
+
+	// HealthCheckTimeout is the time a plugin has to answer a health check
+	HealthCheckTimeout = 5 * time.Second
+
+	// ExternalPluginStartTimeout is the time plugins that run in their own container have to come up
+	ExternalPluginStartTimeout = 2 * time.Minute
+)
+
+// externalPlugins returns the sorted names of the plugins that run in their own container
+func externalPlugins(vConfig *config.VirtualClusterConfig) []string {
+	names := []string{}
+	for name, plugin := range vConfig.Plugins {
+		if plugin.Address != "" {
+			names = append(names, name)
+		}
+	}
+
+	slices.Sort(names)
+	return names
+}
+
+// dialPlugin connects to a plugin that serves the plugin grpc api in its own container
+func dialPlugin(address string) (*vClusterPlugin, error) {
+	conn, err := grpc.NewClient(address, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, err
+	}
+
+	vClusterPlugin := &vClusterPlugin{
+		Address:    address,
+		GRPCClient: pluginv2.NewPluginClient(conn),
+		conn:       conn,
+	}
+	go vClusterPlugin.watchConnection()
+	return vClusterPlugin, nil
+}
+
+// watchConnection marks the plugin as disconnected once an established connection is lost. grpc reconnects on its
+// own, but a restarted plugin container has lost its state and needs to be initialized again.
+func (p *vClusterPlugin) watchConnection() {
+	wasReady := false
+	state := p.conn.GetState()
+	for state != connectivity.Shutdown {
+		if state == connectivity.Ready {
+			wasReady = true
+		} else if wasReady {
+			p.disconnected.Store(true)
+			return
+		}
+
+		p.conn.WaitForStateChange(context.Background(), state)
+		state = p.conn.GetState()
+	}
+}
+
+// initialize sends the init request, plugins that run in their own container are retried until they are up
+func initialize(ctx context.Context, vClusterPlugin *vClusterPlugin, initRequest *pluginv2.Initialize_Request) error {
+	if vClusterPlugin.Address == "" {
+		_, err := vClusterPlugin.GRPCClient.Initialize(ctx, initRequest)
+		return err
+	}
+
+	var lastErr error
+	err := wait.PollUntilContextTimeout(ctx, time.Second, ExternalPluginStartTimeout, true, func(ctx context.Context) (bool, error) {
+		_, lastErr = vClusterPlugin.GRPCClient.Initialize(ctx, initRequest)
+		return lastErr == nil, nil
+	})
+	if err != nil && lastErr != nil {
+		return lastErr
+	}
+
+	return err
+}
+
+func (p *vClusterPlugin) close() {
+	if p.Client != nil {
+		p.Client.Kill()
+	}
+	if p.conn != nil {
+		_ = p.conn.Close()
+	}
+}
+
+// healthy checks if the plugin process is still running and answers requests
+func (p *vClusterPlugin) healthy(ctx context.Context) bool {
+	if p.disconnected.Load() || p.Client != nil && p.Client.Exited() {
+		return false
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, HealthCheckTimeout)
+	defer cancel()
+
+	_, err := p.GRPCClient.GetPluginConfig(ctx, &pluginv2.GetPluginConfig_Request{})
+	if err != nil {
+		p.disconnected.Store(true)
+		return false
+	}
+
+	return true
+}
+
+// supervise restarts unhealthy plugins and plugins whose binary changed, loads plugins added to the plugin folder and
+// removes plugins removed from it until the context is done
+func (m *Manager) supervise(ctx context.Context) {
+	wait.UntilWithContext(ctx, m.reconcilePlugins, HealthCheckInterval)
+}
+
+func (m *Manager) reconcilePlugins(ctx context.Context) {
+	pluginPaths, err := m.findPlugins(ctx)
+	if err != nil {
+		klog.FromContext(ctx).Error(err, "find plugins")
+		return
+	}
+
+	m.m.RLock()
+	plugins := slices.Clone(m.Plugins)
+	m.m.RUnlock()
+
+	loaded := map[string]bool{}
+	for _, vClusterPlugin := range plugins {
+		if vClusterPlugin.Path != "" {
+			loaded[vClusterPlugin.Path] = true
+			if !slices.Contains(pluginPaths, vClusterPlugin.Path) {
+				klog.FromContext(ctx).Info("Plugin was removed from the plugin folder, unloading it", "plugin", vClusterPlugin.String())
+				m.removePlugin(vClusterPlugin)
+				continue
+			}
+
+			stat, err := os.Stat(vClusterPlugin.Path)
+			if err == nil && !stat.ModTime().Equal(vClusterPlugin.modTime) {
+				klog.FromContext(ctx).Info("Plugin binary changed, reloading it", "plugin", vClusterPlugin.String())
+				m.restartPlugin(ctx, vClusterPlugin)
+				continue
+			}
+		}
+
+		if !vClusterPlugin.healthy(ctx) {
+			klog.FromContext(ctx).Info("Plugin is unhealthy, restarting it", "plugin", vClusterPlugin.String())
+			m.restartPlugin(ctx, vClusterPlugin)
+		}
+	}
+
+	for _, pluginPath := range pluginPaths {
+		if loaded[pluginPath] {
+			continue
+		}
+
+		klog.FromContext(ctx).Info("Plugin was added to the plugin folder, loading it", "plugin", pluginPath)
+		err = m.addPlugin(ctx, pluginPath)
+		if err != nil {
+			klog.FromContext(ctx).Error(err, "load plugin", "plugin", pluginPath)
+		}
+	}
+}
+
+func (m *Manager) addPlugin(ctx context.Context, pluginPath string) error {
+	vClusterPlugin, err := m.loadPlugin(pluginPath, m.vConfig)
+	if err != nil {
+		return err
+	}
+
+	m.m.Lock()
+	vClusterPlugin.Port = m.nextPort
+	m.nextPort++
+	m.m.Unlock()
+
+	err = m.startPlugin(ctx, vClusterPlugin)
+	if err != nil {
+		vClusterPlugin.close()
+		return err
+	}
+
+	m.m.Lock()
+	m.Plugins = append(m.Plugins, vClusterPlugin)
+	m.m.Unlock()
+	return nil
+}
+
+// restartPlugin replaces the plugin with a new instance, if that fails the old instance stays unhealthy and is
+// restarted again with the next health check
+func (m *Manager) restartPlugin(ctx context.Context, old *vClusterPlugin) {
+	m.m.Lock()
+	m.unregister(old)
+	m.m.Unlock()
+	old.close()
+
+	var (
+		vClusterPlugin *vClusterPlugin
+		err            error
+	)
+	if old.Address != "" {
+		vClusterPlugin, err = dialPlugin(old.Address)
+	} else {
+		vClusterPlugin, err = m.loadPlugin(old.Path, m.vConfig)
+	}
+	if err != nil {
+		klog.FromContext(ctx).Error(err, "restart plugin", "plugin", old.String())
+		return
+	}
+
+	vClusterPlugin.Port = old.Port
+	err = m.startPlugin(ctx, vClusterPlugin)
+	if err != nil {
+		vClusterPlugin.close()
+		klog.FromContext(ctx).Error(err, "restart plugin", "plugin", old.String())
+		return
+	}
+
+	m.m.Lock()
+	defer m.m.Unlock()
+	for i, p := range m.Plugins {
+		if p == old {
+			m.Plugins[i] = vClusterPlugin
+		}
+	}
+}
+
+// startPlugin initializes the plugin and signals leadership if the syncer is already leading
+func (m *Manager) startPlugin(ctx context.Context, vClusterPlugin *vClusterPlugin) error {
+	err := m.initializePlugin(ctx, vClusterPlugin)
+	if err != nil {
+		return err
+	}
+
+	m.m.RLock()
+	isLeader := m.isLeader
+	m.m.RUnlock()
+	if isLeader {
+		_, err = vClusterPlugin.GRPCClient.SetLeader(ctx, &pluginv2.SetLeader_Request{})
+		if err != nil {
+			m.m.Lock()
+			m.unregister(vClusterPlugin)
+			m.m.Unlock()
+			return fmt.Errorf("error setting leader in plugin %s: %w", vClusterPlugin, err)
+		}
+	}
+
+	return nil
+}
+
+func (m *Manager) removePlugin(target *vClusterPlugin) {
+	m.m.Lock()
+	m.unregister(target)
+	m.Plugins = slices.DeleteFunc(m.Plugins, func(p *vClusterPlugin) bool {
+		return p == target
+	})
+	m.m.Unlock()
+
+	target.close()
+}
+
+// unregister removes the client hooks and interceptors of the plugin, the caller needs to hold the lock
+func (m *Manager) unregister(target *vClusterPlugin) {
+	for versionKindType, plugins := range m.ClientHooks {
+		plugins = slices.DeleteFunc(slices.Clone(plugins), func(p *vClusterPlugin) bool {
+			return p == target
+		})
+		if len(plugins) == 0 {
+			delete(m.ClientHooks, versionKindType)
+		} else {
+			m.ClientHooks[versionKindType] = plugins
+		}
+	}
+
+	for group, resources := range m.ResourceInterceptorsPorts {
+		for resource, verbs := range resources {
+			for verb, resourceNames := range verbs {
+				for resourceName, handler := range resourceNames {
+					if handler.port == target.Port {
+						delete(resourceNames, resourceName)
+					}
+				}
+				if len(resourceNames) == 0 {
+					delete(verbs, verb)
+				}
+			}
+			if len(verbs) == 0 {
+				delete(resources, resource)
+			}
+		}
+		if len(resources) == 0 {
+			delete(m.ResourceInterceptorsPorts, group)
+		}
+	}
+
+	for url, verbs := range m.NonResourceInterceptorsPorts {
+		for verb, handler := range verbs {
+			if handler.port == target.Port {
+				delete(verbs, verb)
+			}
+		}
+		if len(verbs) == 0 {
+			delete(m.NonResourceInterceptorsPorts, url)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package v2
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+
+	vclusterconfig "github.com/loft-sh/vcluster/config"
+	"github.com/loft-sh/vcluster/pkg/config"
+	plugintypes "github.com/loft-sh/vcluster/pkg/plugin/types"
+	"github.com/loft-sh/vcluster/pkg/plugin/v2/pluginv2"
+	"google.golang.org/grpc"
+	"gotest.tools/v3/assert"
+	"k8s.io/client-go/rest"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+type testPluginServer struct {
+	pluginv2.UnimplementedPluginServer
+
+	m           sync.Mutex
+	initialized int
+	leader      int
+}
+
+func (s *testPluginServer) Initialize(context.Context, *pluginv2.Initialize_Request) (*pluginv2.Initialize_Response, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	s.initialized++
+	return &pluginv2.Initialize_Response{}, nil
+}
+
+func (s *testPluginServer) SetLeader(context.Context, *pluginv2.SetLeader_Request) (*pluginv2.SetLeader_Response, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	s.leader++
+	return &pluginv2.SetLeader_Response{}, nil
+}
+
+func (s *testPluginServer) GetPluginConfig(context.Context, *pluginv2.GetPluginConfig_Request) (*pluginv2.GetPluginConfig_Response, error) {
+	return &pluginv2.GetPluginConfig_Response{
+		Config: `{"clientHooks":[{"apiVersion":"v1","kind":"Pod","types":["CreatePhysical"]}],"interceptors":{"test":[{"apiGroups":["*"],"resources":["pods"],"verbs":["get"]}]}}`,
+	}, nil
+}
+
+func serveTestPlugin(t *testing.T, address string) (*testPluginServer, string, func()) {
+	listener, err := net.Listen("tcp", address)
+	assert.NilError(t, err)
+
+	server := grpc.NewServer()
+	pluginServer := &testPluginServer{}
+	pluginv2.RegisterPluginServer(server, pluginServer)
+	go func() {
+		_ = server.Serve(listener)
+	}()
+
+	return pluginServer, listener.Addr().String(), server.Stop
+}
+
+func TestExternalPluginRestart(t *testing.T) {
+	ctx := context.Background()
+	_, address, stop := serveTestPlugin(t, "127.0.0.1:0")
+	defer func() {
+		stop()
+	}()
+
+	vConfig := &config.VirtualClusterConfig{
+		Config:             vclusterconfig.Config{},
+		WorkloadConfig:     &rest.Config{Host: "https://localhost:6443"},
+		ControlPlaneConfig: &rest.Config{Host: "https://localhost:6443"},
+	}
+	manager := NewManager()
+	manager.PluginFolder = t.TempDir()
+	manager.syncerConfig = &clientcmdapi.Config{}
+	manager.vConfig = vConfig
+
+	vClusterPlugin, err := dialPlugin(address)
+	assert.NilError(t, err)
+	vClusterPlugin.Port = 13370
+	manager.Plugins = append(manager.Plugins, vClusterPlugin)
+	assert.NilError(t, manager.initializePlugin(ctx, vClusterPlugin))
+	assert.NilError(t, manager.SetLeader(ctx))
+
+	podHook := plugintypes.VersionKindType{APIVersion: "v1", Kind: "Pod", Type: "CreatePhysical"}
+	assert.Assert(t, manager.HasClientHooksForType(podHook))
+	ok, port, _ := manager.interceptorPortForResource("", "pods", "get", "test")
+	assert.Assert(t, ok)
+	assert.Equal(t, port, 13370)
+
+	// the healthy plugin is kept
+	manager.reconcilePlugins(ctx)
+	assert.Equal(t, manager.Plugins[0], vClusterPlugin)
+
+	// a crashed plugin is restarted once it's back and gets the same port
+	stop()
+	assert.Assert(t, !vClusterPlugin.healthy(ctx))
+	restartedServer, _, restartedStop := serveTestPlugin(t, address)
+	stop = restartedStop
+	manager.reconcilePlugins(ctx)
+	assert.Assert(t, manager.Plugins[0] != vClusterPlugin)
+	assert.Equal(t, restartedServer.initialized, 1)
+	assert.Equal(t, restartedServer.leader, 1)
+	assert.Equal(t, len(manager.ClientHooks[podHook]), 1)
+	assert.Equal(t, manager.ClientHooks[podHook][0], manager.Plugins[0])
+	ok, port, _ = manager.interceptorPortForResource("", "pods", "get", "test")
+	assert.Assert(t, ok)
+	assert.Equal(t, port, 13370)
+
+	// removing the plugin removes its hooks and interceptors
+	manager.removePlugin(manager.Plugins[0])
+	assert.Assert(t, !manager.HasPlugins())
+	assert.Assert(t, !manager.HasClientHooks())
+	ok, _, _ = manager.interceptorPortForResource("", "pods", "get", "test")
+	assert.Assert(t, !ok)
+}
+
+func TestExternalPlugins(t *testing.T) {
+	vConfig := &config.VirtualClusterConfig{}
+	vConfig.Plugins = map[string]vclusterconfig.Plugins{
+		"b":      {Address: "localhost:10001"},
+		"a":      {Address: "localhost:10000"},
+		"binary": {Image: "my-plugin"},
+	}
+
+	assert.DeepEqual(t, externalPlugins(vConfig), []string{"a", "b"})
+}
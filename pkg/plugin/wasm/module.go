@@ -0,0 +1,233 @@
+package wasm
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	vclusterconfig "github.com/loft-sh/vcluster/config"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// DefaultSecretKey is the key of the module in a secret if none is configured
+	DefaultSecretKey = "module.wasm"
+
+	// maxModuleSize limits the size of modules pulled from OCI registries
+	maxModuleSize = 32 * 1024 * 1024
+
+	// pullTimeout limits how long pulling a module from an OCI registry may take, so an unreachable registry does not
+	// block the start of vCluster
+	pullTimeout = 2 * time.Minute
+)
+
+var manifestMediaTypes = []string{
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+}
+
+type ociManifest struct {
+	Layers []ociDescriptor `json:"layers"`
+}
+
+type ociDescriptor struct {
+	MediaType string `json:"mediaType"`
+	Digest    string `json:"digest"`
+}
+
+// loadModule returns the WebAssembly module from the secret or OCI artifact of the config
+func loadModule(ctx context.Context, kubeClient kubernetes.Interface, namespace string, module vclusterconfig.WasmHookModule) ([]byte, error) {
+	if module.OCI != "" {
+		return pullModule(ctx, &http.Client{Timeout: pullTimeout}, module.OCI)
+	}
+
+	key := module.Secret.Key
+	if key == "" {
+		key = DefaultSecretKey
+	}
+
+	secret, err := kubeClient.CoreV1().Secrets(namespace).Get(ctx, module.Secret.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("get secret %s/%s: %w", namespace, module.Secret.Name, err)
+	} else if len(secret.Data[key]) == 0 {
+		return nil, fmt.Errorf("secret %s/%s has no key %s", namespace, module.Secret.Name, key)
+	}
+
+	return secret.Data[key], nil
+}
+
+// pullModule pulls the first layer of the OCI artifact, layers with a wasm media type take precedence
+func pullModule(ctx context.Context, httpClient *http.Client, reference string) ([]byte, error) {
+	registry, repository, tag, err := parseReference(reference)
+	if err != nil {
+		return nil, err
+	}
+
+	rawManifest, err := registryGet(ctx, httpClient, registry, repository, "manifests/"+tag, strings.Join(manifestMediaTypes, ","))
+	if err != nil {
+		return nil, fmt.Errorf("get manifest of %s: %w", reference, err)
+	}
+	manifest := &ociManifest{}
+	err = json.Unmarshal(rawManifest, manifest)
+	if err != nil {
+		return nil, fmt.Errorf("parse manifest of %s: %w", reference, err)
+	} else if len(manifest.Layers) == 0 {
+		return nil, fmt.Errorf("artifact %s has no layers", reference)
+	}
+
+	layer := manifest.Layers[0]
+	for _, l := range manifest.Layers {
+		if strings.Contains(l.MediaType, "wasm") {
+			layer = l
+			break
+		}
+	}
+
+	module, err := registryGet(ctx, httpClient, registry, repository, "blobs/"+layer.Digest, "")
+	if err != nil {
+		return nil, fmt.Errorf("get module of %s: %w", reference, err)
+	}
+
+	hash := sha256.Sum256(module)
+	if "sha256:"+hex.EncodeToString(hash[:]) != layer.Digest {
+		return nil, fmt.Errorf("digest of module %s doesn't match %s", reference, layer.Digest)
+	}
+
+	return module, nil
+}
+
+// parseReference splits a reference such as ghcr.io/my-org/my-hook:v1 into the registry url, repository and tag or digest
+func parseReference(reference string) (string, string, string, error) {
+	name, tag := reference, "latest"
+	if idx := strings.Index(name, "@"); idx >= 0 {
+		name, tag = name[:idx], name[idx+1:]
+	} else if idx := strings.LastIndex(name, ":"); idx > strings.LastIndex(name, "/") {
+		name, tag = name[:idx], name[idx+1:]
+	}
+
+	registry, repository, found := strings.Cut(name, "/")
+	if !found || !strings.ContainsAny(registry, ".:") && registry != "localhost" {
+		registry, repository = "registry-1.docker.io", name
+		if !strings.Contains(repository, "/") {
+			repository = "library/" + repository
+		}
+	}
+	if repository == "" || tag == "" {
+		return "", "", "", fmt.Errorf("invalid oci reference %s", reference)
+	}
+
+	scheme := "https"
+	if strings.HasPrefix(registry, "localhost") || strings.HasPrefix(registry, "127.0.0.1") {
+		scheme = "http"
+	}
+
+	return scheme + "://" + registry, repository, tag, nil
+}
+
+// registryGet gets the given path of the repository and retrieves an anonymous token if the registry asks for one
+func registryGet(ctx context.Context, httpClient *http.Client, registry, repository, path, accept string) ([]byte, error) {
+	target := registry + "/v2/" + repository + "/" + path
+	response, err := doGet(ctx, httpClient, target, accept, "")
+	if err != nil {
+		return nil, err
+	}
+
+	if response.StatusCode == http.StatusUnauthorized {
+		challenge := response.Header.Get("WWW-Authenticate")
+		_ = response.Body.Close()
+
+		token, err := fetchToken(ctx, httpClient, challenge)
+		if err != nil {
+			return nil, err
+		}
+
+		response, err = doGet(ctx, httpClient, target, accept, token)
+		if err != nil {
+			return nil, err
+		}
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d from %s", response.StatusCode, target)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(response.Body, maxModuleSize+1))
+	if err != nil {
+		return nil, err
+	} else if len(body) > maxModuleSize {
+		return nil, fmt.Errorf("%s exceeds the maximum size of %d bytes", target, maxModuleSize)
+	}
+
+	return body, nil
+}
+
+func doGet(ctx context.Context, httpClient *http.Client, target, accept, token string) (*http.Response, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, err
+	}
+	if accept != "" {
+		request.Header.Set("Accept", accept)
+	}
+	if token != "" {
+		request.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	return httpClient.Do(request)
+}
+
+// fetchToken retrieves an anonymous token for a challenge like Bearer realm="https://ghcr.io/token",service="ghcr.io",scope="repository:my-org/my-hook:pull"
+func fetchToken(ctx context.Context, httpClient *http.Client, challenge string) (string, error) {
+	params, found := strings.CutPrefix(challenge, "Bearer ")
+	if !found {
+		return "", fmt.Errorf("unsupported registry authentication %q, only anonymous pulls are supported", challenge)
+	}
+
+	values := map[string]string{}
+	for _, param := range strings.Split(params, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+		values[key] = strings.Trim(value, `"`)
+	}
+	if values["realm"] == "" {
+		return "", fmt.Errorf("registry authentication %q has no realm", challenge)
+	}
+
+	query := url.Values{}
+	for _, key := range []string{"service", "scope"} {
+		if values[key] != "" {
+			query.Set(key, values[key])
+		}
+	}
+
+	response, err := doGet(ctx, httpClient, values["realm"]+"?"+query.Encode(), "", "")
+	if err != nil {
+		return "", err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status code %d from %s", response.StatusCode, values["realm"])
+	}
+
+	token := struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}{}
+	err = json.NewDecoder(response.Body).Decode(&token)
+	if err != nil {
+		return "", fmt.Errorf("decode token: %w", err)
+	}
+	if token.Token != "" {
+		return token.Token, nil
+	}
+
+	return token.AccessToken, nil
+}
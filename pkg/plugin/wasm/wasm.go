@@ -0,0 +1,251 @@
+package wasm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	vclusterconfig "github.com/loft-sh/vcluster/config"
+	"github.com/loft-sh/vcluster/pkg/config"
+	plugintypes "github.com/loft-sh/vcluster/pkg/plugin/types"
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/imports/wasi_snapshot_preview1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+const (
+	// DefaultMemory is the default memory limit of a hook in MiB
+	DefaultMemory = 16
+
+	// DefaultTimeout is the default timeout of a single hook invocation
+	DefaultTimeout = time.Second
+
+	// pagesPerMiB is the number of 64KiB WebAssembly memory pages per MiB
+	pagesPerMiB = 16
+)
+
+// DefaultHookTypes are used if a hook doesn't specify any types
+var DefaultHookTypes = []string{"CreatePhysical", "UpdatePhysical"}
+
+// Request is passed as JSON to the mutate function of a module
+type Request struct {
+	APIVersion string          `json:"apiVersion"`
+	Kind       string          `json:"kind"`
+	Type       string          `json:"type"`
+	Object     json.RawMessage `json:"object"`
+}
+
+// Manager runs the WebAssembly hooks of experimental.wasmHooks.
+//
+// A module needs to export its memory, an alloc(size i32) i32 function that returns a buffer of the given size and a
+// mutate(ptr i32, len i32) i64 function. The syncer writes the JSON encoded Request into the buffer returned by alloc
+// and calls mutate with it. mutate returns the pointer to the JSON encoded mutated object in the upper and its length in
+// the lower 32 bits, a length of 0 leaves the object unchanged. Modules have no access to the network or the file
+// system, each invocation runs in a fresh instance that is restricted by the configured limits.
+type Manager struct {
+	hooks map[plugintypes.VersionKindType][]*hook
+}
+
+type hook struct {
+	name string
+
+	runtime wazero.Runtime
+	module  wazero.CompiledModule
+	timeout time.Duration
+}
+
+func NewManager() *Manager {
+	return &Manager{
+		hooks: map[plugintypes.VersionKindType][]*hook{},
+	}
+}
+
+// Start loads and compiles the modules of all configured hooks
+func (m *Manager) Start(ctx context.Context, vConfig *config.VirtualClusterConfig) error {
+	for _, hookConfig := range vConfig.Experimental.WasmHooks {
+		module, err := loadModule(ctx, vConfig.ControlPlaneClient, vConfig.ControlPlaneNamespace, hookConfig.Module)
+		if err != nil {
+			return fmt.Errorf("load wasm hook %s: %w", hookConfig.Name, err)
+		}
+
+		err = m.Register(ctx, hookConfig, module)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Register compiles the given module and registers it for the version kind types of the hook config
+func (m *Manager) Register(ctx context.Context, hookConfig vclusterconfig.WasmHook, module []byte) error {
+	h, err := newHook(ctx, hookConfig, module)
+	if err != nil {
+		return fmt.Errorf("compile wasm hook %s: %w", hookConfig.Name, err)
+	}
+
+	hookTypes := hookConfig.Types
+	if len(hookTypes) == 0 {
+		hookTypes = DefaultHookTypes
+	}
+	for _, hookType := range hookTypes {
+		versionKindType := plugintypes.VersionKindType{
+			APIVersion: hookConfig.APIVersion,
+			Kind:       hookConfig.Kind,
+			Type:       hookType,
+		}
+
+		m.hooks[versionKindType] = append(m.hooks[versionKindType], h)
+	}
+
+	klog.Infof("Register wasm hook %s for %s %s", hookConfig.Name, hookConfig.APIVersion, hookConfig.Kind)
+	return nil
+}
+
+func (m *Manager) HasClientHooks() bool {
+	return len(m.hooks) > 0
+}
+
+func (m *Manager) HasClientHooksForType(versionKindType plugintypes.VersionKindType) bool {
+	return len(m.hooks[versionKindType]) > 0
+}
+
+func (m *Manager) MutateObject(ctx context.Context, obj client.Object, hookType string, scheme *runtime.Scheme) error {
+	gvk, err := apiutil.GVKForObject(obj, scheme)
+	if err != nil {
+		return err
+	}
+
+	apiVersion, kind := gvk.ToAPIVersionAndKind()
+	versionKindType := plugintypes.VersionKindType{
+		APIVersion: apiVersion,
+		Kind:       kind,
+		Type:       hookType,
+	}
+	hooks := m.hooks[versionKindType]
+	if len(hooks) == 0 {
+		return nil
+	}
+
+	encodedObj, err := json.Marshal(obj)
+	if err != nil {
+		return fmt.Errorf("encode object: %w", err)
+	}
+
+	for _, h := range hooks {
+		request, err := json.Marshal(&Request{
+			APIVersion: apiVersion,
+			Kind:       kind,
+			Type:       hookType,
+			Object:     encodedObj,
+		})
+		if err != nil {
+			return fmt.Errorf("encode request: %w", err)
+		}
+
+		klog.FromContext(ctx).V(1).Info("calling wasm hook to mutate object", "hook", h.name, "apiVersion", apiVersion, "kind", kind)
+		mutated, err := h.mutate(ctx, request)
+		if err != nil {
+			return fmt.Errorf("call wasm hook %s: %w", h.name, err)
+		} else if mutated != nil {
+			encodedObj = mutated
+		}
+	}
+
+	err = json.Unmarshal(encodedObj, obj)
+	if err != nil {
+		return fmt.Errorf("decode object: %w", err)
+	}
+
+	return nil
+}
+
+func newHook(ctx context.Context, hookConfig vclusterconfig.WasmHook, module []byte) (*hook, error) {
+	memory := hookConfig.Limits.Memory
+	if memory == 0 {
+		memory = DefaultMemory
+	}
+	timeout := DefaultTimeout
+	if hookConfig.Limits.Timeout > 0 {
+		timeout = time.Duration(hookConfig.Limits.Timeout) * time.Millisecond
+	}
+
+	// the memory limit applies to the whole runtime, so every hook gets its own
+	r := wazero.NewRuntimeWithConfig(ctx, wazero.NewRuntimeConfig().
+		WithMemoryLimitPages(uint32(memory*pagesPerMiB)).
+		WithCloseOnContextDone(true))
+
+	// modules built with TinyGo or Rust usually import WASI, which is sandboxed as no file system is mounted
+	_, err := wasi_snapshot_preview1.Instantiate(ctx, r)
+	if err != nil {
+		_ = r.Close(ctx)
+		return nil, err
+	}
+
+	compiled, err := r.CompileModule(ctx, module)
+	if err != nil {
+		_ = r.Close(ctx)
+		return nil, err
+	}
+	for _, name := range []string{"alloc", "mutate"} {
+		if _, ok := compiled.ExportedFunctions()[name]; !ok {
+			_ = r.Close(ctx)
+			return nil, fmt.Errorf("module doesn't export function %s", name)
+		}
+	}
+
+	return &hook{
+		name:    hookConfig.Name,
+		runtime: r,
+		module:  compiled,
+		timeout: timeout,
+	}, nil
+}
+
+// mutate calls the module with the request and returns the mutated object or nil if it wasn't changed
+func (h *hook) mutate(ctx context.Context, request []byte) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+
+	// modules without a name can be instantiated concurrently
+	instance, err := h.runtime.InstantiateModule(ctx, h.module, wazero.NewModuleConfig().WithName("").WithStartFunctions("_initialize"))
+	if err != nil {
+		return nil, fmt.Errorf("instantiate module: %w", err)
+	}
+	defer instance.Close(ctx)
+
+	memory := instance.Memory()
+	if memory == nil {
+		return nil, fmt.Errorf("module doesn't export its memory")
+	}
+
+	results, err := instance.ExportedFunction("alloc").Call(ctx, uint64(len(request)))
+	if err != nil {
+		return nil, fmt.Errorf("alloc: %w", err)
+	}
+	ptr := uint32(results[0])
+	if !memory.Write(ptr, request) {
+		return nil, fmt.Errorf("alloc returned out of range buffer %d", ptr)
+	}
+
+	results, err = instance.ExportedFunction("mutate").Call(ctx, uint64(ptr), uint64(len(request)))
+	if err != nil {
+		return nil, fmt.Errorf("mutate: %w", err)
+	}
+	resultPtr, resultLen := uint32(results[0]>>32), uint32(results[0])
+	if resultLen == 0 {
+		return nil, nil
+	}
+
+	result, ok := memory.Read(resultPtr, resultLen)
+	if !ok {
+		return nil, fmt.Errorf("mutate returned out of range result %d with length %d", resultPtr, resultLen)
+	}
+
+	// the memory is gone once the instance is closed
+	return append([]byte{}, result...), nil
+}
@@ -0,0 +1,162 @@
+package wasm
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	vclusterconfig "github.com/loft-sh/vcluster/config"
+	plugintypes "github.com/loft-sh/vcluster/pkg/plugin/types"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+// testModule assembles a module exporting memory, alloc which always returns 0 and mutate with the given body. The
+// data is placed at offset 1024.
+func testModule(memoryPages byte, mutateBody []byte, data []byte) []byte {
+	section := func(id byte, content ...byte) []byte {
+		return append([]byte{id, byte(len(content))}, content...)
+	}
+	name := func(n string) []byte {
+		return append([]byte{byte(len(n))}, n...)
+	}
+
+	module := []byte{0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00}
+	// types: (i32) -> i32 and (i32, i32) -> i64
+	module = append(module, section(0x01, 0x02, 0x60, 0x01, 0x7f, 0x01, 0x7f, 0x60, 0x02, 0x7f, 0x7f, 0x01, 0x7e)...)
+	module = append(module, section(0x03, 0x02, 0x00, 0x01)...)
+	module = append(module, section(0x05, 0x01, 0x00, memoryPages)...)
+
+	exports := []byte{0x03}
+	exports = append(append(exports, name("memory")...), 0x02, 0x00)
+	exports = append(append(exports, name("alloc")...), 0x00, 0x00)
+	exports = append(append(exports, name("mutate")...), 0x00, 0x01)
+	module = append(module, section(0x07, exports...)...)
+
+	alloc := []byte{0x00, 0x41, 0x00, 0x0b}
+	mutate := append([]byte{0x00}, mutateBody...)
+	code := append([]byte{0x02, byte(len(alloc))}, alloc...)
+	code = append(append(code, byte(len(mutate))), mutate...)
+	module = append(module, section(0x0a, code...)...)
+
+	if len(data) > 0 {
+		segment := append([]byte{0x01, 0x00, 0x41, 0x80, 0x08, 0x0b, byte(len(data))}, data...)
+		module = append(module, section(0x0b, segment...)...)
+	}
+
+	return module
+}
+
+// returnData is a mutate body returning the data at offset 1024
+func returnData(length int) []byte {
+	return append(append([]byte{0x42}, sleb128(int64(1024)<<32|int64(length))...), 0x0b)
+}
+
+func sleb128(value int64) []byte {
+	out := []byte{}
+	for {
+		b := byte(value & 0x7f)
+		value >>= 7
+		if (value == 0 && b&0x40 == 0) || (value == -1 && b&0x40 != 0) {
+			return append(out, b)
+		}
+		out = append(out, b|0x80)
+	}
+}
+
+func TestMutateObject(t *testing.T) {
+	ctx := context.Background()
+	mutated := `{"metadata":{"name":"test","labels":{"mutated":"true"}}}`
+
+	manager := NewManager()
+	err := manager.Register(ctx, vclusterconfig.WasmHook{Name: "label", APIVersion: "v1", Kind: "Pod"}, testModule(1, returnData(len(mutated)), []byte(mutated)))
+	assert.NilError(t, err)
+	err = manager.Register(ctx, vclusterconfig.WasmHook{Name: "noop", APIVersion: "v1", Kind: "Pod", Types: []string{"CreatePhysical"}}, testModule(1, []byte{0x42, 0x00, 0x0b}, nil))
+	assert.NilError(t, err)
+
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "test"}}
+	err = manager.MutateObject(ctx, pod, "CreatePhysical", scheme.Scheme)
+	assert.NilError(t, err)
+	assert.Equal(t, pod.Labels["mutated"], "true")
+
+	// other kinds and types are not mutated
+	configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "test"}}
+	err = manager.MutateObject(ctx, configMap, "CreatePhysical", scheme.Scheme)
+	assert.NilError(t, err)
+	assert.Assert(t, configMap.Labels == nil)
+	assert.Assert(t, !manager.HasClientHooksForType(plugintypes.VersionKindType{APIVersion: "v1", Kind: "Pod", Type: "GetPhysical"}))
+}
+
+func TestLimits(t *testing.T) {
+	ctx := context.Background()
+
+	// loop forever
+	manager := NewManager()
+	err := manager.Register(ctx, vclusterconfig.WasmHook{Name: "loop", APIVersion: "v1", Kind: "Pod", Limits: vclusterconfig.WasmHookLimits{Timeout: 50}}, testModule(1, []byte{0x03, 0x40, 0x0c, 0x00, 0x0b, 0x42, 0x00, 0x0b}, nil))
+	assert.NilError(t, err)
+	err = manager.MutateObject(ctx, &corev1.Pod{}, "CreatePhysical", scheme.Scheme)
+	assert.ErrorContains(t, err, "call wasm hook loop")
+
+	// ask for more memory than allowed
+	err = manager.Register(ctx, vclusterconfig.WasmHook{Name: "memory", APIVersion: "v1", Kind: "Pod", Limits: vclusterconfig.WasmHookLimits{Memory: 1}}, testModule(32, []byte{0x42, 0x00, 0x0b}, nil))
+	assert.ErrorContains(t, err, "compile wasm hook memory")
+}
+
+func TestPullModule(t *testing.T) {
+	module := testModule(1, []byte{0x42, 0x00, 0x0b}, nil)
+	hash := sha256.Sum256(module)
+	digest := "sha256:" + hex.EncodeToString(hash[:])
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/token":
+			assert.Equal(t, r.URL.Query().Get("scope"), "repository:my-org/my-hook:pull")
+			_, _ = w.Write([]byte(`{"token":"anonymous"}`))
+		case r.Header.Get("Authorization") != "Bearer anonymous":
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="test",scope="repository:my-org/my-hook:pull"`, server.URL))
+			w.WriteHeader(http.StatusUnauthorized)
+		case r.URL.Path == "/v2/my-org/my-hook/manifests/v1":
+			_, _ = w.Write([]byte(fmt.Sprintf(`{"layers":[{"mediaType":"application/vnd.oci.empty.v1+json","digest":"sha256:0"},{"mediaType":"application/vnd.module.wasm.content.layer.v1+wasm","digest":"%s"}]}`, digest)))
+		case r.URL.Path == "/v2/my-org/my-hook/blobs/"+digest:
+			_, _ = w.Write(module)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	pulled, err := pullModule(context.Background(), server.Client(), strings.TrimPrefix(server.URL, "http://")+"/my-org/my-hook:v1")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, pulled, module)
+}
+
+func TestParseReference(t *testing.T) {
+	testCases := []struct {
+		reference  string
+		registry   string
+		repository string
+		tag        string
+	}{
+		{reference: "ghcr.io/my-org/my-hook:v1", registry: "https://ghcr.io", repository: "my-org/my-hook", tag: "v1"},
+		{reference: "ghcr.io/my-org/my-hook@sha256:abc", registry: "https://ghcr.io", repository: "my-org/my-hook", tag: "sha256:abc"},
+		{reference: "localhost:5000/my-hook", registry: "http://localhost:5000", repository: "my-hook", tag: "latest"},
+		{reference: "my-hook:v1", registry: "https://registry-1.docker.io", repository: "library/my-hook", tag: "v1"},
+		{reference: "my-org/my-hook", registry: "https://registry-1.docker.io", repository: "my-org/my-hook", tag: "latest"},
+	}
+
+	for _, testCase := range testCases {
+		registry, repository, tag, err := parseReference(testCase.reference)
+		assert.NilError(t, err, testCase.reference)
+		assert.Equal(t, registry, testCase.registry, testCase.reference)
+		assert.Equal(t, repository, testCase.repository, testCase.reference)
+		assert.Equal(t, tag, testCase.tag, testCase.reference)
+	}
+}
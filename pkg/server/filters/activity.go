@@ -0,0 +1,55 @@
+package filters
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/loft-sh/vcluster/pkg/autosleep"
+	requestpkg "github.com/loft-sh/vcluster/pkg/util/request"
+	"k8s.io/apiserver/pkg/endpoints/request"
+)
+
+// WithActivityTracking records incoming requests as virtual cluster activity. Activity wakes up
+// sleeping workloads, so the proxy acts as the wake up proxy for the auto sleep controller. Requests
+// that reach the workloads through the service proxy wait until the workloads are awake, as they
+// would fail without endpoints otherwise.
+func WithActivityTracking(h http.Handler, tracker *autosleep.ActivityTracker) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if isServiceProxy(req) && tracker.Sleeping() {
+			err := tracker.WaitAwake(req.Context(), autosleep.WakeUpTimeout)
+			if err != nil {
+				w.Header().Set("Retry-After", "10")
+				requestpkg.FailWithStatus(w, req, http.StatusServiceUnavailable, err)
+				return
+			}
+		} else if isActivity(req) {
+			tracker.RecordActivity()
+		}
+
+		h.ServeHTTP(w, req)
+	})
+}
+
+func isServiceProxy(req *http.Request) bool {
+	info, ok := request.RequestInfoFrom(req.Context())
+	return ok && info.IsResourceRequest && info.Resource == "services" && info.Subresource == "proxy"
+}
+
+func isActivity(req *http.Request) bool {
+	// watches are long-running and are re-established by controllers all the time
+	info, ok := request.RequestInfoFrom(req.Context())
+	if !ok || (info.IsResourceRequest && info.Verb == "watch") {
+		return false
+	}
+	if !info.IsResourceRequest && (strings.HasPrefix(info.Path, "/healthz") || strings.HasPrefix(info.Path, "/readyz") || strings.HasPrefix(info.Path, "/livez")) {
+		return false
+	}
+
+	// ignore system components such as coredns that constantly talk to the api server
+	user, ok := request.UserFrom(req.Context())
+	if ok && (strings.HasPrefix(user.GetName(), "system:serviceaccount:kube-system:") || strings.HasPrefix(user.GetName(), "system:node:")) {
+		return false
+	}
+
+	return true
+}
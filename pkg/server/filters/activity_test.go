@@ -0,0 +1,71 @@
+package filters
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/loft-sh/vcluster/pkg/autosleep"
+	"gotest.tools/v3/assert"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apiserver/pkg/endpoints/request"
+)
+
+func TestActivityTracking(t *testing.T) {
+	backendRequests := 0
+	backend := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		backendRequests++
+		w.WriteHeader(http.StatusOK)
+	})
+
+	resolver := &request.RequestInfoFactory{APIPrefixes: sets.NewString("api", "apis"), GrouplessAPIPrefixes: sets.NewString("api")}
+	tracker := autosleep.NewActivityTracker()
+	handler := WithActivityTracking(backend, tracker)
+	doRequest := func(url string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, url, nil)
+		info, err := resolver.NewRequestInfo(req)
+		assert.NilError(t, err)
+		req = req.WithContext(request.WithRequestInfo(req.Context(), info))
+
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, req)
+		return recorder
+	}
+	wakeUpRequested := func() bool {
+		select {
+		case <-tracker.WakeUpRequests():
+			return true
+		default:
+			return false
+		}
+	}
+
+	// watches and health checks are no activity
+	doRequest("/api/v1/pods?watch=true")
+	doRequest("/healthz")
+	assert.Assert(t, !wakeUpRequested())
+	doRequest("/api/v1/namespaces/default/pods")
+	assert.Assert(t, wakeUpRequested())
+
+	// service proxy requests wait until the sleeping workloads are awake
+	tracker.SetSleeping(true)
+	done := make(chan *httptest.ResponseRecorder)
+	go func() {
+		done <- doRequest("/api/v1/namespaces/default/services/web:80/proxy/")
+	}()
+	select {
+	case <-tracker.WakeUpRequests():
+	case <-time.After(10 * time.Second):
+		t.Fatal("expected wake up request")
+	}
+	select {
+	case <-done:
+		t.Fatal("request was proxied before the workloads were awake")
+	case <-time.After(50 * time.Millisecond):
+	}
+	tracker.SetSleeping(false)
+	recorder := <-done
+	assert.Equal(t, recorder.Code, http.StatusOK)
+	assert.Equal(t, backendRequests, 4)
+}
@@ -0,0 +1,184 @@
+package filters
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/loft-sh/vcluster/pkg/util/encoding"
+	"github.com/loft-sh/vcluster/pkg/util/random"
+	requestpkg "github.com/loft-sh/vcluster/pkg/util/request"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metainternalversionscheme "k8s.io/apimachinery/pkg/apis/meta/internalversion/scheme"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apiserver/pkg/endpoints/handlers/responsewriters"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// hostDryRunTranslators translate the virtual objects of the resources that are checked against the host cluster or
+// return nil if the object cannot be translated. The translation only needs to be close enough for the host admission,
+// the object is never persisted.
+var hostDryRunTranslators = map[string]func(vObj client.Object, syncedLabels []string) client.Object{
+	"pods": func(vObj client.Object, syncedLabels []string) client.Object {
+		if _, ok := vObj.(*corev1.Pod); !ok {
+			return nil
+		}
+
+		pPod, ok := translate.Default.ApplyMetadata(vObj, syncedLabels).(*corev1.Pod)
+		if !ok {
+			return nil
+		}
+		pPod.Spec.NodeName = ""
+		pPod.Spec.ServiceAccountName = ""
+		pPod.Spec.DeprecatedServiceAccount = ""
+		return pPod
+	},
+	"services": func(vObj client.Object, syncedLabels []string) client.Object {
+		if _, ok := vObj.(*corev1.Service); !ok {
+			return nil
+		}
+
+		pService, ok := translate.Default.ApplyMetadata(vObj, syncedLabels).(*corev1.Service)
+		if !ok {
+			return nil
+		}
+		pService.Spec.Selector = translate.Default.TranslateLabels(pService.Spec.Selector, vObj.GetNamespace(), nil)
+		pService.Spec.ClusterIP = ""
+		pService.Spec.ClusterIPs = nil
+		for i := range pService.Spec.Ports {
+			pService.Spec.Ports[i].NodePort = 0
+		}
+		return pService
+	},
+	"configmaps": func(vObj client.Object, syncedLabels []string) client.Object {
+		if _, ok := vObj.(*corev1.ConfigMap); !ok {
+			return nil
+		}
+
+		return translate.Default.ApplyMetadata(vObj, syncedLabels)
+	},
+	"secrets": func(vObj client.Object, syncedLabels []string) client.Object {
+		if _, ok := vObj.(*corev1.Secret); !ok {
+			return nil
+		}
+
+		return translate.Default.ApplyMetadata(vObj, syncedLabels)
+	},
+}
+
+// WithHostDryRun sends server side dry-run creates of the given synced core resources to the host cluster as dry-run as
+// well and returns denials of the host admission, e.g. by webhooks, pod security or resource quotas, as the real create
+// would be denied by the host cluster as soon as the object is synced. Dry-run requests never reach the syncer, so no
+// host object is created.
+func WithHostDryRun(h http.Handler, uncachedLocalClient client.Client, resources []string, syncedLabels []string) http.Handler {
+	decoder := encoding.NewDecoder(uncachedLocalClient.Scheme(), false)
+	s := serializer.NewCodecFactory(uncachedLocalClient.Scheme())
+	translators := map[string]func(vObj client.Object, syncedLabels []string) client.Object{}
+	for _, resource := range resources {
+		if translator, ok := hostDryRunTranslators[resource]; ok {
+			translators[resource] = translator
+		}
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		info, ok := request.RequestInfoFrom(req.Context())
+		if !ok {
+			requestpkg.FailWithStatus(w, req, http.StatusInternalServerError, fmt.Errorf("request info is missing"))
+			return
+		}
+
+		translator, ok := translators[info.Resource]
+		if !ok || info.Verb != "create" || info.Subresource != "" || info.Namespace == "" || info.APIGroup != corev1.SchemeGroupVersion.Group || info.APIVersion != corev1.SchemeGroupVersion.Version {
+			h.ServeHTTP(w, req)
+			return
+		}
+
+		options := &metav1.CreateOptions{}
+		if err := metainternalversionscheme.ParameterCodec.DecodeParameters(req.URL.Query(), metav1.SchemeGroupVersion, options); err != nil {
+			responsewriters.ErrorNegotiated(err, s, corev1.SchemeGroupVersion, w, req)
+			return
+		} else if len(options.DryRun) == 0 {
+			h.ServeHTTP(w, req)
+			return
+		}
+
+		rawObj, err := io.ReadAll(req.Body)
+		if err != nil {
+			responsewriters.ErrorNegotiated(err, s, corev1.SchemeGroupVersion, w, req)
+			return
+		}
+		req.Body = io.NopCloser(bytes.NewReader(rawObj))
+
+		err = hostDryRunCreate(req, decoder, uncachedLocalClient, translator, rawObj, info.Namespace, syncedLabels)
+		if err != nil {
+			responsewriters.ErrorNegotiated(err, s, corev1.SchemeGroupVersion, w, req)
+			return
+		}
+
+		h.ServeHTTP(w, req)
+	})
+}
+
+// hostDryRunCreate creates the translated object as dry-run in the host cluster and returns an error if the host
+// admission denied it. All other errors are ignored, as they are most likely caused by the approximate translation and
+// the virtual cluster validates the object anyway.
+func hostDryRunCreate(req *http.Request, decoder encoding.Decoder, localClient client.Client, translator func(vObj client.Object, syncedLabels []string) client.Object, rawObj []byte, namespace string, syncedLabels []string) error {
+	obj, err := decoder.Decode(rawObj, nil)
+	if err != nil {
+		// the virtual cluster will return a proper error for this
+		return nil
+	}
+	vObj, ok := obj.(client.Object)
+	if !ok {
+		return nil
+	}
+
+	vObj.SetNamespace(namespace)
+	if vObj.GetName() == "" && vObj.GetGenerateName() != "" {
+		vObj.SetName(vObj.GetGenerateName() + random.String(5))
+	}
+	if vObj.GetName() == "" {
+		return nil
+	}
+
+	pObj := translator(vObj, syncedLabels)
+	if pObj == nil {
+		return nil
+	}
+
+	err = localClient.Create(req.Context(), pObj, client.DryRunAll)
+	if err == nil || !isHostAdmissionDenial(err) {
+		if err != nil {
+			klog.V(1).Infof("Ignore error of host dry-run for %s/%s: %v", namespace, vObj.GetName(), err)
+		}
+
+		return nil
+	}
+
+	statusErr := &kerrors.StatusError{}
+	if !errors.As(err, &statusErr) {
+		return err
+	}
+
+	// the host names mean nothing to the user of the virtual cluster
+	status := statusErr.Status()
+	status.Message = "host cluster denied the request: " + strings.ReplaceAll(status.Message, pObj.GetName(), vObj.GetName())
+	if status.Details != nil {
+		status.Details.Name = vObj.GetName()
+	}
+	return &kerrors.StatusError{ErrStatus: status}
+}
+
+// isHostAdmissionDenial returns true if the error is caused by the admission of the host cluster and not by the
+// translation of the object
+func isHostAdmissionDenial(err error) bool {
+	return kerrors.IsForbidden(err) || ((kerrors.IsInvalid(err) || kerrors.IsBadRequest(err)) && strings.Contains(err.Error(), "admission webhook"))
+}
@@ -0,0 +1,121 @@
+package filters
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	testingutil "github.com/loft-sh/vcluster/pkg/util/testing"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func TestHostDryRun(t *testing.T) {
+	translate.Default = translate.NewSingleNamespaceTranslator("test")
+
+	hostCreates := []client.Object{}
+	hostClient := fake.NewClientBuilder().WithScheme(testingutil.NewScheme()).WithInterceptorFuncs(interceptor.Funcs{
+		Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+			createOptions := &client.CreateOptions{}
+			createOptions.ApplyOptions(opts)
+			assert.DeepEqual(t, createOptions.DryRun, []string{metav1.DryRunAll})
+			hostCreates = append(hostCreates, obj)
+
+			pod, ok := obj.(*corev1.Pod)
+			if ok && len(pod.Spec.Containers) > 0 && pod.Spec.Containers[0].SecurityContext != nil {
+				return kerrors.NewForbidden(corev1.Resource("pods"), pod.Name, fmt.Errorf("violates PodSecurity \"baseline:latest\": privileged (container \"nginx\")"))
+			} else if ok && pod.Spec.Containers[0].Image == "missing" {
+				return kerrors.NewNotFound(corev1.Resource("serviceaccounts"), "default")
+			}
+
+			return c.Create(ctx, obj, opts...)
+		},
+	}).Build()
+
+	backendRequests := 0
+	backend := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		backendRequests++
+		w.WriteHeader(http.StatusCreated)
+	})
+	handler := WithHostDryRun(backend, hostClient, []string{"pods"}, nil)
+
+	resolver := &request.RequestInfoFactory{APIPrefixes: sets.NewString("api", "apis"), GrouplessAPIPrefixes: sets.NewString("api")}
+	doRequest := func(url string, pod *corev1.Pod) *httptest.ResponseRecorder {
+		backendRequests = 0
+		hostCreates = []client.Object{}
+		rawPod, err := json.Marshal(pod)
+		assert.NilError(t, err)
+		req := httptest.NewRequest(http.MethodPost, url, strings.NewReader(string(rawPod)))
+		req.Header.Set("Content-Type", "application/json")
+		info, err := resolver.NewRequestInfo(req)
+		assert.NilError(t, err)
+		req = req.WithContext(request.WithRequestInfo(req.Context(), info))
+
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, req)
+		return recorder
+	}
+	newPod := func(image string, privileged bool) *corev1.Pod {
+		pod := &corev1.Pod{
+			TypeMeta:   metav1.TypeMeta{Kind: "Pod", APIVersion: "v1"},
+			ObjectMeta: metav1.ObjectMeta{Name: "nginx"},
+			Spec: corev1.PodSpec{
+				ServiceAccountName: "my-sa",
+				Containers:         []corev1.Container{{Name: "nginx", Image: image}},
+			},
+		}
+		if privileged {
+			pod.Spec.Containers[0].SecurityContext = &corev1.SecurityContext{Privileged: &[]bool{true}[0]}
+		}
+
+		return pod
+	}
+
+	// real creates are not checked against the host
+	recorder := doRequest("/api/v1/namespaces/default/pods", newPod("nginx", true))
+	assert.Equal(t, recorder.Code, http.StatusCreated)
+	assert.Equal(t, backendRequests, 1)
+	assert.Equal(t, len(hostCreates), 0)
+
+	// allowed dry-runs are checked against the host and passed to the virtual cluster
+	recorder = doRequest("/api/v1/namespaces/default/pods?dryRun=All", newPod("nginx", false))
+	assert.Equal(t, recorder.Code, http.StatusCreated)
+	assert.Equal(t, backendRequests, 1)
+	assert.Equal(t, len(hostCreates), 1)
+	hostPod := hostCreates[0].(*corev1.Pod)
+	assert.Equal(t, hostPod.Name, translate.Default.PhysicalName("nginx", "default"))
+	assert.Equal(t, hostPod.Namespace, "test")
+	assert.Equal(t, hostPod.Spec.ServiceAccountName, "")
+	assert.Assert(t, kerrors.IsNotFound(hostClient.Get(context.Background(), client.ObjectKeyFromObject(hostPod), &corev1.Pod{})))
+
+	// host admission denials are returned with the virtual name
+	recorder = doRequest("/api/v1/namespaces/default/pods?dryRun=All", newPod("nginx", true))
+	assert.Equal(t, recorder.Code, http.StatusForbidden)
+	assert.Equal(t, backendRequests, 0)
+	status := &metav1.Status{}
+	assert.NilError(t, json.Unmarshal(recorder.Body.Bytes(), status))
+	assert.Assert(t, strings.HasPrefix(status.Message, "host cluster denied the request: "), status.Message)
+	assert.Assert(t, strings.Contains(status.Message, `pods "nginx" is forbidden`), status.Message)
+
+	// other host errors are ignored
+	recorder = doRequest("/api/v1/namespaces/default/pods?dryRun=All", newPod("missing", false))
+	assert.Equal(t, recorder.Code, http.StatusCreated)
+	assert.Equal(t, backendRequests, 1)
+
+	// resources that are not synced are not checked
+	recorder = doRequest("/api/v1/namespaces/default/configmaps?dryRun=All", newPod("nginx", true))
+	assert.Equal(t, recorder.Code, http.StatusCreated)
+	assert.Equal(t, len(hostCreates), 0)
+}
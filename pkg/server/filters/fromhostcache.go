@@ -0,0 +1,235 @@
+package filters
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	requestpkg "github.com/loft-sh/vcluster/pkg/util/request"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apiserver/pkg/endpoints/handlers/responsewriters"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// the response cache is dropped completely once it holds this many entries
+const maxFromHostCacheEntries = 1000
+
+type cachedResponse struct {
+	body    []byte
+	expires time.Time
+}
+
+type fromHostCache struct {
+	m         sync.Mutex
+	responses map[string]cachedResponse
+}
+
+// WithFromHostCache serves get and list requests of the given cluster scoped resources from the informer cache of the
+// virtual cluster instead of passing them to the virtual api server. These resources are synced from the host cluster
+// and read by many workloads, e.g. nodes or storage classes, so this takes load off the virtual api server and its
+// backing store. Encoded responses are reused for identical requests for ttl. Requests the cache can't answer
+// exactly, such as watches, paginated lists, lists with field selectors or a resource version other than 0, are passed
+// through.
+func WithFromHostCache(h http.Handler, cachedVirtualClient client.Client, informers cache.Informers, resources []schema.GroupVersionResource, ttl time.Duration) http.Handler {
+	s := serializer.NewCodecFactory(cachedVirtualClient.Scheme())
+	responseCache := &fromHostCache{responses: map[string]cachedResponse{}}
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		info, ok := request.RequestInfoFrom(req.Context())
+		if !ok {
+			requestpkg.FailWithStatus(w, req, http.StatusInternalServerError, fmt.Errorf("request info is missing"))
+			return
+		}
+
+		gvr, ok := isCacheableFromHostRequest(info, req, resources)
+		if !ok {
+			h.ServeHTTP(w, req)
+			return
+		}
+
+		key := req.URL.Path + "?" + req.URL.Query().Get("labelSelector")
+		if body, ok := responseCache.get(key); ok {
+			writeJSON(w, body)
+			return
+		}
+
+		body, err := serveFromCache(req, info, gvr, cachedVirtualClient, informers)
+		if err != nil {
+			if kerrors.IsNotFound(err) || kerrors.IsBadRequest(err) {
+				responsewriters.ErrorNegotiated(err, s, gvr.GroupVersion(), w, req)
+				return
+			}
+
+			// let the api server answer if the cache can't
+			klog.V(1).Infof("Error serving %s from cache: %v", req.URL.Path, err)
+			h.ServeHTTP(w, req)
+			return
+		}
+
+		if ttl > 0 {
+			responseCache.set(key, body, ttl)
+		}
+		writeJSON(w, body)
+	})
+}
+
+func isCacheableFromHostRequest(info *request.RequestInfo, req *http.Request, resources []schema.GroupVersionResource) (schema.GroupVersionResource, bool) {
+	if !info.IsResourceRequest || info.Subresource != "" || info.Namespace != "" || (info.Verb != "get" && info.Verb != "list") {
+		return schema.GroupVersionResource{}, false
+	}
+
+	// only consistent reads without resource version constraints, pagination and field selectors are served from cache
+	query := req.URL.Query()
+	if query.Get("limit") != "" || query.Get("continue") != "" || query.Get("fieldSelector") != "" || query.Get("resourceVersionMatch") != "" || query.Get("watch") != "" {
+		return schema.GroupVersionResource{}, false
+	}
+	if resourceVersion := query.Get("resourceVersion"); resourceVersion != "" && resourceVersion != "0" {
+		return schema.GroupVersionResource{}, false
+	}
+
+	// only plain json is served, tables and protobuf are passed through
+	for _, accept := range strings.Split(req.Header.Get("Accept"), ",") {
+		mediaType := strings.TrimSpace(strings.Split(accept, ";")[0])
+		if strings.Contains(accept, "as=") || (mediaType != "" && mediaType != "*/*" && mediaType != "application/json") {
+			return schema.GroupVersionResource{}, false
+		}
+	}
+
+	for _, gvr := range resources {
+		if gvr.Group == info.APIGroup && gvr.Version == info.APIVersion && gvr.Resource == info.Resource {
+			return gvr, true
+		}
+	}
+
+	return schema.GroupVersionResource{}, false
+}
+
+func serveFromCache(req *http.Request, info *request.RequestInfo, gvr schema.GroupVersionResource, cachedVirtualClient client.Client, informers cache.Informers) ([]byte, error) {
+	gvk, err := cachedVirtualClient.RESTMapper().KindFor(gvr)
+	if err != nil {
+		return nil, err
+	}
+
+	if info.Verb == "get" {
+		obj, err := newObject(cachedVirtualClient.Scheme(), gvk)
+		if err != nil {
+			return nil, err
+		}
+		err = cachedVirtualClient.Get(req.Context(), client.ObjectKey{Name: info.Name}, obj)
+		if err != nil {
+			return nil, err
+		}
+
+		obj.GetObjectKind().SetGroupVersionKind(gvk)
+		return json.Marshal(obj)
+	}
+
+	listOptions := []client.ListOption{}
+	if labelSelector := req.URL.Query().Get("labelSelector"); labelSelector != "" {
+		selector, err := labels.Parse(labelSelector)
+		if err != nil {
+			return nil, kerrors.NewBadRequest(fmt.Sprintf("invalid label selector %q: %v", labelSelector, err))
+		}
+		listOptions = append(listOptions, client.MatchingLabelsSelector{Selector: selector})
+	}
+
+	// clients watch from the resource version of the list, so it has to be the resource version the informer has
+	// synced, the resource versions of the items can be long compacted. It is read before listing, so a watch replays
+	// changes the list already contains instead of missing them.
+	resourceVersion, err := lastSyncResourceVersion(req.Context(), cachedVirtualClient.Scheme(), informers, gvk)
+	if err != nil {
+		return nil, err
+	}
+
+	listGVK := gvk.GroupVersion().WithKind(gvk.Kind + "List")
+	listObj, err := cachedVirtualClient.Scheme().New(listGVK)
+	if err != nil {
+		return nil, err
+	}
+	list, ok := listObj.(client.ObjectList)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a list", listGVK.String())
+	}
+	err = cachedVirtualClient.List(req.Context(), list, listOptions...)
+	if err != nil {
+		return nil, err
+	}
+
+	list.SetResourceVersion(resourceVersion)
+	list.GetObjectKind().SetGroupVersionKind(listGVK)
+	return json.Marshal(list)
+}
+
+func newObject(scheme *runtime.Scheme, gvk schema.GroupVersionKind) (client.Object, error) {
+	obj, err := scheme.New(gvk)
+	if err != nil {
+		return nil, err
+	}
+
+	clientObj, ok := obj.(client.Object)
+	if !ok {
+		return nil, fmt.Errorf("%s is not an object", gvk.String())
+	}
+
+	return clientObj, nil
+}
+
+func lastSyncResourceVersion(ctx context.Context, scheme *runtime.Scheme, informers cache.Informers, gvk schema.GroupVersionKind) (string, error) {
+	obj, err := newObject(scheme, gvk)
+	if err != nil {
+		return "", err
+	}
+	informer, err := informers.GetInformer(ctx, obj, cache.BlockUntilSynced(false))
+	if err != nil {
+		return "", err
+	}
+
+	resourceVersioner, ok := informer.(interface{ LastSyncResourceVersion() string })
+	if !ok {
+		return "", fmt.Errorf("informer of %s has no resource version", gvk.String())
+	}
+	resourceVersion := resourceVersioner.LastSyncResourceVersion()
+	if resourceVersion == "" {
+		return "", fmt.Errorf("informer of %s has not synced yet", gvk.String())
+	}
+
+	return resourceVersion, nil
+}
+
+func writeJSON(w http.ResponseWriter, body []byte) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(body)
+}
+
+func (c *fromHostCache) get(key string) ([]byte, bool) {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	response, ok := c.responses[key]
+	if !ok || time.Now().After(response.expires) {
+		return nil, false
+	}
+
+	return response.body, true
+}
+
+func (c *fromHostCache) set(key string, body []byte, ttl time.Duration) {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	if len(c.responses) >= maxFromHostCacheEntries {
+		c.responses = map[string]cachedResponse{}
+	}
+	c.responses[key] = cachedResponse{body: body, expires: time.Now().Add(ttl)}
+}
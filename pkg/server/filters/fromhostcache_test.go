@@ -0,0 +1,133 @@
+package filters
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	testingutil "github.com/loft-sh/vcluster/pkg/util/testing"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type fakeMapperClient struct {
+	client.Client
+
+	mapper meta.RESTMapper
+}
+
+func (c *fakeMapperClient) RESTMapper() meta.RESTMapper {
+	return c.mapper
+}
+
+type fakeInformers struct {
+	cache.Informers
+
+	resourceVersion string
+}
+
+func (f *fakeInformers) GetInformer(context.Context, client.Object, ...cache.InformerGetOption) (cache.Informer, error) {
+	return &fakeInformer{resourceVersion: f.resourceVersion}, nil
+}
+
+type fakeInformer struct {
+	cache.Informer
+
+	resourceVersion string
+}
+
+func (f *fakeInformer) LastSyncResourceVersion() string {
+	return f.resourceVersion
+}
+
+func TestFromHostCache(t *testing.T) {
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(corev1.SchemeGroupVersion.WithKind("Node"), meta.RESTScopeRoot)
+	vClient := &fakeMapperClient{
+		Client: testingutil.NewFakeClient(testingutil.NewScheme(),
+			&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1", ResourceVersion: "9", Labels: map[string]string{"zone": "a"}}},
+			&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-2", ResourceVersion: "12", Labels: map[string]string{"zone": "b"}}},
+		),
+		mapper: mapper,
+	}
+
+	requests := 0
+	backend := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusTeapot)
+	})
+	informers := &fakeInformers{resourceVersion: "42"}
+	h := WithFromHostCache(backend, vClient, informers, []schema.GroupVersionResource{corev1.SchemeGroupVersion.WithResource("nodes")}, time.Minute)
+
+	resolver := &request.RequestInfoFactory{APIPrefixes: sets.NewString("api", "apis"), GrouplessAPIPrefixes: sets.NewString("api")}
+	doRequest := func(url, accept string) *httptest.ResponseRecorder {
+		requests = 0
+		req := httptest.NewRequest(http.MethodGet, url, nil)
+		req.Header.Set("Accept", accept)
+		info, err := resolver.NewRequestInfo(req)
+		assert.NilError(t, err)
+		req = req.WithContext(request.WithRequestInfo(req.Context(), info))
+
+		recorder := httptest.NewRecorder()
+		h.ServeHTTP(recorder, req)
+		return recorder
+	}
+
+	// lists are served from cache with the resource version the informer has synced
+	recorder := doRequest("/api/v1/nodes", "application/json")
+	assert.Equal(t, recorder.Code, http.StatusOK)
+	assert.Equal(t, requests, 0)
+	list := &corev1.NodeList{}
+	assert.NilError(t, json.Unmarshal(recorder.Body.Bytes(), list))
+	assert.Equal(t, list.Kind, "NodeList")
+	assert.Equal(t, list.ResourceVersion, "42")
+	assert.Equal(t, len(list.Items), 2)
+
+	// label selectors are applied
+	recorder = doRequest("/api/v1/nodes?labelSelector=zone%3Db", "")
+	list = &corev1.NodeList{}
+	assert.NilError(t, json.Unmarshal(recorder.Body.Bytes(), list))
+	assert.Equal(t, requests, 0)
+	assert.Equal(t, len(list.Items), 1)
+	assert.Equal(t, list.Items[0].Name, "node-2")
+
+	// gets are served from cache
+	recorder = doRequest("/api/v1/nodes/node-1", "application/json")
+	node := &corev1.Node{}
+	assert.NilError(t, json.Unmarshal(recorder.Body.Bytes(), node))
+	assert.Equal(t, requests, 0)
+	assert.Equal(t, node.Kind, "Node")
+	assert.Equal(t, node.Name, "node-1")
+
+	// missing objects are not found
+	recorder = doRequest("/api/v1/nodes/node-3", "application/json")
+	assert.Equal(t, requests, 0)
+	assert.Equal(t, recorder.Code, http.StatusNotFound)
+
+	// watches, paginated lists, field selectors, tables and other resources are passed through
+	for _, url := range []string{"/api/v1/nodes?watch=true", "/api/v1/nodes?limit=1", "/api/v1/nodes?fieldSelector=metadata.name%3Dnode-1", "/api/v1/nodes?resourceVersion=5", "/api/v1/nodes/node-1/status", "/api/v1/namespaces"} {
+		recorder = doRequest(url, "application/json")
+		assert.Equal(t, requests, 1, url)
+		assert.Equal(t, recorder.Code, http.StatusTeapot, url)
+	}
+	doRequest("/api/v1/nodes", "application/json;as=Table;v=v1;g=meta.k8s.io")
+	assert.Equal(t, requests, 1)
+	doRequest("/api/v1/nodes", "application/vnd.kubernetes.protobuf")
+	assert.Equal(t, requests, 1)
+
+	// lists are passed through as long as the informer has not synced
+	informers.resourceVersion = ""
+	recorder = doRequest("/api/v1/nodes?labelSelector=zone%3Da", "application/json")
+	assert.Equal(t, requests, 1)
+	assert.Equal(t, recorder.Code, http.StatusTeapot)
+}
@@ -0,0 +1,162 @@
+package filters
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	requestpkg "github.com/loft-sh/vcluster/pkg/util/request"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/klog/v2"
+)
+
+type listPage struct {
+	Kind       string            `json:"kind,omitempty"`
+	APIVersion string            `json:"apiVersion,omitempty"`
+	Metadata   metav1.ListMeta   `json:"metadata,omitempty"`
+	Items      []json.RawMessage `json:"items"`
+}
+
+// WithListStreaming splits list requests without a limit into pages of pageSize items and streams the items of
+// each page to the client as soon as they are received. Clients still receive a single list, but only one page
+// at a time is held in memory instead of the whole list.
+func WithListStreaming(h http.Handler, pageSize int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		info, ok := request.RequestInfoFrom(req.Context())
+		if !ok {
+			requestpkg.FailWithStatus(w, req, http.StatusInternalServerError, fmt.Errorf("request info is missing"))
+			return
+		}
+
+		if pageSize <= 0 || !isStreamableList(info, req) {
+			h.ServeHTTP(w, req)
+			return
+		}
+
+		streamList(w, req, h, pageSize)
+	})
+}
+
+func isStreamableList(info *request.RequestInfo, req *http.Request) bool {
+	if !info.IsResourceRequest || info.Verb != "list" || info.Subresource != "" {
+		return false
+	}
+
+	// requests that already paginate are passed through
+	query := req.URL.Query()
+	if query.Get("limit") != "" || query.Get("continue") != "" {
+		return false
+	}
+
+	// only plain json lists are streamed, tables and protobuf are passed through
+	for _, accept := range strings.Split(req.Header.Get("Accept"), ",") {
+		mediaType := strings.TrimSpace(strings.Split(accept, ";")[0])
+		if strings.Contains(accept, "as=") || (mediaType != "" && mediaType != "*/*" && mediaType != "application/json") {
+			return false
+		}
+	}
+
+	return true
+}
+
+func streamList(w http.ResponseWriter, req *http.Request, h http.Handler, pageSize int) {
+	flusher, _ := w.(http.Flusher)
+	continueToken := ""
+	started := false
+	written := 0
+	for {
+		pageRequest := req.Clone(req.Context())
+		query := pageRequest.URL.Query()
+		query.Set("limit", strconv.Itoa(pageSize))
+		if continueToken != "" {
+			query.Set("continue", continueToken)
+		}
+		pageRequest.URL.RawQuery = query.Encode()
+		pageRequest.Header.Set("Accept", "application/json")
+
+		code, header, body, err := executeRequest(pageRequest, h)
+		if err != nil {
+			if !started {
+				requestpkg.FailWithStatus(w, req, http.StatusInternalServerError, err)
+				return
+			}
+
+			klog.Errorf("error streaming list %s: %v", req.URL.Path, err)
+			return
+		} else if code != http.StatusOK {
+			if !started {
+				// the response has not started yet, so we just pass the error through
+				for k, v := range header {
+					w.Header()[k] = v
+				}
+				w.WriteHeader(code)
+				_, _ = w.Write(body)
+				return
+			}
+
+			// the status code was already sent, so the only thing left is to abort the response
+			klog.Errorf("error streaming list %s: unexpected status code %d: %s", req.URL.Path, code, string(body))
+			return
+		}
+
+		page := &listPage{}
+		err = json.Unmarshal(body, page)
+		if err != nil {
+			if !started {
+				requestpkg.FailWithStatus(w, req, http.StatusInternalServerError, err)
+				return
+			}
+
+			klog.Errorf("error streaming list %s: %v", req.URL.Path, err)
+			return
+		}
+
+		// write the list header with the metadata of the first page
+		if !started {
+			listMeta := page.Metadata
+			listMeta.Continue = ""
+			listMeta.RemainingItemCount = nil
+			rawListMeta, err := json.Marshal(listMeta)
+			if err != nil {
+				requestpkg.FailWithStatus(w, req, http.StatusInternalServerError, err)
+				return
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			_, err = fmt.Fprintf(w, `{"kind":%q,"apiVersion":%q,"metadata":%s,"items":[`, page.Kind, page.APIVersion, string(rawListMeta))
+			if err != nil {
+				return
+			}
+			started = true
+		}
+
+		for _, item := range page.Items {
+			if written > 0 {
+				_, err = w.Write([]byte(","))
+				if err != nil {
+					return
+				}
+			}
+
+			_, err = w.Write(item)
+			if err != nil {
+				return
+			}
+			written++
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+
+		continueToken = page.Metadata.Continue
+		if continueToken == "" {
+			break
+		}
+	}
+
+	_, _ = w.Write([]byte("]}\n"))
+}
@@ -0,0 +1,81 @@
+package filters
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apiserver/pkg/endpoints/request"
+)
+
+func TestListStreaming(t *testing.T) {
+	pods := []corev1.Pod{}
+	for i := 0; i < 5; i++ {
+		pods = append(pods, corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("pod-%d", i), Namespace: "default"}})
+	}
+
+	requests := 0
+	backend := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		requests++
+		list := &corev1.PodList{TypeMeta: metav1.TypeMeta{Kind: "PodList", APIVersion: "v1"}, ListMeta: metav1.ListMeta{ResourceVersion: "10"}}
+		limit, _ := strconv.Atoi(req.URL.Query().Get("limit"))
+		start, _ := strconv.Atoi(req.URL.Query().Get("continue"))
+		end := len(pods)
+		if limit > 0 && start+limit < end {
+			end = start + limit
+			list.Continue = strconv.Itoa(end)
+		}
+		list.Items = pods[start:end]
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(list)
+	})
+
+	resolver := &request.RequestInfoFactory{APIPrefixes: sets.NewString("api", "apis"), GrouplessAPIPrefixes: sets.NewString("api")}
+	doRequest := func(pageSize int, url, accept string) (*httptest.ResponseRecorder, *corev1.PodList) {
+		requests = 0
+		req := httptest.NewRequest(http.MethodGet, url, nil)
+		req.Header.Set("Accept", accept)
+		info, err := resolver.NewRequestInfo(req)
+		assert.NilError(t, err)
+		req = req.WithContext(request.WithRequestInfo(req.Context(), info))
+
+		recorder := httptest.NewRecorder()
+		WithListStreaming(backend, pageSize).ServeHTTP(recorder, req)
+		list := &corev1.PodList{}
+		assert.NilError(t, json.Unmarshal(recorder.Body.Bytes(), list), recorder.Body.String())
+		return recorder, list
+	}
+
+	// lists without a limit are split into pages
+	recorder, list := doRequest(2, "/api/v1/pods", "application/json")
+	assert.Equal(t, recorder.Code, http.StatusOK)
+	assert.Equal(t, requests, 3)
+	assert.Equal(t, list.Kind, "PodList")
+	assert.Equal(t, list.ResourceVersion, "10")
+	assert.Equal(t, list.Continue, "")
+	assert.DeepEqual(t, list.Items, pods)
+
+	// requests that paginate themselves are passed through
+	_, list = doRequest(2, "/api/v1/pods?limit=4", "application/json")
+	assert.Equal(t, requests, 1)
+	assert.Equal(t, len(list.Items), 4)
+	assert.Equal(t, list.Continue, "4")
+
+	// tables are passed through
+	_, list = doRequest(2, "/api/v1/pods", "application/json;as=Table;v=v1;g=meta.k8s.io")
+	assert.Equal(t, requests, 1)
+	assert.Equal(t, len(list.Items), 5)
+
+	// streaming can be disabled
+	_, list = doRequest(0, "/api/v1/pods", "application/json")
+	assert.Equal(t, requests, 1)
+	assert.Equal(t, len(list.Items), 5)
+}
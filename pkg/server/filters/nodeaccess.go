@@ -0,0 +1,102 @@
+package filters
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/loft-sh/vcluster/pkg/util/encoding"
+	"github.com/loft-sh/vcluster/pkg/util/podhelper"
+	requestpkg "github.com/loft-sh/vcluster/pkg/util/request"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apiserver/pkg/endpoints/handlers/responsewriters"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+)
+
+// WithHostNodeAccess denies node debugging pods and nodes/proxy requests, which would give users of the virtual
+// cluster direct access to the host nodes. Kubelet metrics and stats requests are still allowed, because
+// WithMetricsProxy rewrites them to only contain the workloads of the virtual cluster. Denied requests are recorded as
+// warning events within the virtual cluster.
+func WithHostNodeAccess(h http.Handler, scheme *runtime.Scheme, recorder record.EventRecorder, allowDebug, allowProxy bool) http.Handler {
+	decoder := encoding.NewDecoder(scheme, false)
+	s := serializer.NewCodecFactory(scheme)
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		info, ok := request.RequestInfoFrom(req.Context())
+		if !ok {
+			requestpkg.FailWithStatus(w, req, http.StatusInternalServerError, fmt.Errorf("request info is missing"))
+			return
+		}
+
+		userName := ""
+		if userInfo, ok := request.UserFrom(req.Context()); ok {
+			userName = userInfo.GetName()
+		}
+
+		if !allowProxy && isNodesProxy(info) && !isKubeletMetricsRequest(info, req) {
+			node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: info.Name}}
+			klog.Infof("Denied nodes/proxy request of user %s for node %s", userName, info.Name)
+			recorder.Eventf(node, corev1.EventTypeWarning, "HostNodeAccessDenied", "Denied nodes/proxy request of user %s, because policies.hostNodeAccess.allowProxy is disabled", userName)
+			responsewriters.ErrorNegotiated(kerrors.NewForbidden(corev1.Resource("nodes/proxy"), info.Name, fmt.Errorf("access to the host node is not allowed in this virtual cluster")), s, corev1.SchemeGroupVersion, w, req)
+			return
+		}
+
+		if !allowDebug && isPodCreate(info) {
+			rawObj, err := io.ReadAll(req.Body)
+			if err != nil {
+				responsewriters.ErrorNegotiated(err, s, corev1.SchemeGroupVersion, w, req)
+				return
+			}
+			req.Body = io.NopCloser(bytes.NewReader(rawObj))
+
+			podGVK := corev1.SchemeGroupVersion.WithKind("Pod")
+			obj, err := decoder.Decode(rawObj, &podGVK)
+			if err != nil {
+				// let the api server return the proper error
+				h.ServeHTTP(w, req)
+				return
+			}
+
+			pod, ok := obj.(*corev1.Pod)
+			if ok && podhelper.IsNodeDebugPod(pod) {
+				if pod.Namespace == "" {
+					pod.Namespace = info.Namespace
+				}
+
+				klog.Infof("Denied node debugging pod %s/%s of user %s for node %s", pod.Namespace, pod.Name, userName, pod.Spec.NodeName)
+				recorder.Eventf(pod, corev1.EventTypeWarning, "HostNodeAccessDenied", "Denied node debugging pod for node %s of user %s, because policies.hostNodeAccess.allowDebug is disabled", pod.Spec.NodeName, userName)
+				responsewriters.ErrorNegotiated(kerrors.NewForbidden(corev1.Resource("pods"), pod.Name, fmt.Errorf("pods that target node %s and use host namespaces or hostPath volumes are not allowed in this virtual cluster", pod.Spec.NodeName)), s, corev1.SchemeGroupVersion, w, req)
+				return
+			}
+		}
+
+		h.ServeHTTP(w, req)
+	})
+}
+
+// isKubeletMetricsRequest returns true if the nodes/proxy request only reads kubelet metrics or stats
+func isKubeletMetricsRequest(r *request.RequestInfo, req *http.Request) bool {
+	if r.Verb != "get" {
+		return false
+	}
+
+	return IsKubeletMetrics(req.URL.Path) || IsKubeletStats(req.URL.Path)
+}
+
+func isPodCreate(r *request.RequestInfo) bool {
+	if !r.IsResourceRequest {
+		return false
+	}
+
+	return r.APIGroup == corev1.SchemeGroupVersion.Group &&
+		r.APIVersion == corev1.SchemeGroupVersion.Version &&
+		r.Resource == "pods" &&
+		r.Subresource == "" &&
+		r.Verb == "create"
+}
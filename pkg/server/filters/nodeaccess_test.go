@@ -0,0 +1,143 @@
+package filters
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	testingutil "github.com/loft-sh/vcluster/pkg/util/testing"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestHostNodeAccess(t *testing.T) {
+	backendRequests := 0
+	backend := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		backendRequests++
+		w.WriteHeader(http.StatusOK)
+	})
+
+	resolver := &request.RequestInfoFactory{APIPrefixes: sets.NewString("api", "apis"), GrouplessAPIPrefixes: sets.NewString("api")}
+	doRequest := func(handler http.Handler, method, url string, body string) *httptest.ResponseRecorder {
+		backendRequests = 0
+		req := httptest.NewRequest(method, url, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		info, err := resolver.NewRequestInfo(req)
+		assert.NilError(t, err)
+		req = req.WithContext(request.WithRequestInfo(req.Context(), info))
+
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, req)
+		return recorder
+	}
+
+	debugPod := &corev1.Pod{
+		TypeMeta:   metav1.TypeMeta{Kind: "Pod", APIVersion: "v1"},
+		ObjectMeta: metav1.ObjectMeta{Name: "node-debugger"},
+		Spec: corev1.PodSpec{
+			NodeName:   "node-1",
+			HostPID:    true,
+			Containers: []corev1.Container{{Name: "debugger", Image: "busybox"}},
+		},
+	}
+	rawDebugPod, err := json.Marshal(debugPod)
+	assert.NilError(t, err)
+	rawPod, err := json.Marshal(&corev1.Pod{
+		TypeMeta:   metav1.TypeMeta{Kind: "Pod", APIVersion: "v1"},
+		ObjectMeta: metav1.ObjectMeta{Name: "nginx"},
+		Spec:       corev1.PodSpec{NodeName: "node-1", Containers: []corev1.Container{{Name: "nginx", Image: "nginx"}}},
+	})
+	assert.NilError(t, err)
+
+	testCases := []struct {
+		name       string
+		allowDebug bool
+		allowProxy bool
+		method     string
+		url        string
+		body       string
+		wantCode   int
+	}{
+		{
+			name:     "deny nodes proxy",
+			method:   http.MethodGet,
+			url:      "/api/v1/nodes/node-1/proxy/pods",
+			wantCode: http.StatusForbidden,
+		},
+		{
+			name:     "deny nodes proxy exec",
+			method:   http.MethodPost,
+			url:      "/api/v1/nodes/node-1/proxy/run/default/nginx/nginx",
+			wantCode: http.StatusForbidden,
+		},
+		{
+			name:     "allow kubelet metrics",
+			method:   http.MethodGet,
+			url:      "/api/v1/nodes/node-1/proxy/metrics",
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "allow kubelet cadvisor metrics",
+			method:   http.MethodGet,
+			url:      "/api/v1/nodes/node-1:10250/proxy/metrics/cadvisor",
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "allow kubelet stats",
+			method:   http.MethodGet,
+			url:      "/api/v1/nodes/node-1/proxy/stats/summary",
+			wantCode: http.StatusOK,
+		},
+		{
+			name:       "allow nodes proxy",
+			allowProxy: true,
+			method:     http.MethodGet,
+			url:        "/api/v1/nodes/node-1/proxy/pods",
+			wantCode:   http.StatusOK,
+		},
+		{
+			name:     "deny node debugging pod",
+			method:   http.MethodPost,
+			url:      "/api/v1/namespaces/default/pods",
+			body:     string(rawDebugPod),
+			wantCode: http.StatusForbidden,
+		},
+		{
+			name:       "allow node debugging pod",
+			allowDebug: true,
+			method:     http.MethodPost,
+			url:        "/api/v1/namespaces/default/pods",
+			body:       string(rawDebugPod),
+			wantCode:   http.StatusOK,
+		},
+		{
+			name:     "allow pod with node name",
+			method:   http.MethodPost,
+			url:      "/api/v1/namespaces/default/pods",
+			body:     string(rawPod),
+			wantCode: http.StatusOK,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := record.NewFakeRecorder(10)
+			handler := WithHostNodeAccess(backend, testingutil.NewScheme(), recorder, tt.allowDebug, tt.allowProxy)
+
+			response := doRequest(handler, tt.method, tt.url, tt.body)
+			assert.Equal(t, response.Code, tt.wantCode, response.Body.String())
+			if tt.wantCode == http.StatusOK {
+				assert.Equal(t, backendRequests, 1)
+				assert.Equal(t, len(recorder.Events), 0)
+			} else {
+				assert.Equal(t, backendRequests, 0)
+				assert.Equal(t, len(recorder.Events), 1)
+			}
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"io"
 	"net/http"
 
+	vclusterconfig "github.com/loft-sh/vcluster/config"
 	"github.com/loft-sh/vcluster/pkg/controllers/resources/services"
 	"github.com/loft-sh/vcluster/pkg/util/clienthelper"
 	"github.com/loft-sh/vcluster/pkg/util/encoding"
@@ -26,7 +27,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-func WithServiceCreateRedirect(handler http.Handler, uncachedLocalClient, uncachedVirtualClient client.Client, virtualConfig *rest.Config, syncedLabels []string) http.Handler {
+func WithServiceCreateRedirect(handler http.Handler, uncachedLocalClient, uncachedVirtualClient client.Client, virtualConfig *rest.Config, syncedLabels []string, externalDNS vclusterconfig.ExternalDNS) http.Handler {
 	decoder := encoding.NewDecoder(uncachedLocalClient.Scheme(), false)
 	s := serializer.NewCodecFactory(uncachedVirtualClient.Scheme())
 	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
@@ -57,7 +58,7 @@ func WithServiceCreateRedirect(handler http.Handler, uncachedLocalClient, uncach
 						return
 					}
 
-					svc, err := createService(req, decoder, uncachedLocalClient, uncachedVirtualImpersonatingClient, info.Namespace, syncedLabels)
+					svc, err := createService(req, decoder, uncachedLocalClient, uncachedVirtualImpersonatingClient, info.Namespace, syncedLabels, externalDNS)
 					if err != nil {
 						responsewriters.ErrorNegotiated(err, s, corev1.SchemeGroupVersion, w, req)
 						return
@@ -177,7 +178,7 @@ func updateService(req *http.Request, decoder encoding.Decoder, localClient clie
 	return newVService, nil
 }
 
-func createService(req *http.Request, decoder encoding.Decoder, localClient client.Client, virtualClient client.Client, fromNamespace string, syncedLabels []string) (runtime.Object, error) {
+func createService(req *http.Request, decoder encoding.Decoder, localClient client.Client, virtualClient client.Client, fromNamespace string, syncedLabels []string, externalDNS vclusterconfig.ExternalDNS) (runtime.Object, error) {
 	// we use a background context from now on as this is a critical operation
 	ctx := context.Background()
 
@@ -206,7 +207,7 @@ func createService(req *http.Request, decoder encoding.Decoder, localClient clie
 		vService.Name = vService.GenerateName + random.String(5)
 	}
 
-	newService := translate.Default.ApplyMetadata(vService, syncedLabels).(*corev1.Service)
+	newService := translate.Default.ApplyMetadata(services.WithSyncedAnnotations(externalDNS, vService), syncedLabels).(*corev1.Service)
 	if newService.Annotations == nil {
 		newService.Annotations = map[string]string{}
 	}
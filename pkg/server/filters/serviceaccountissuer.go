@@ -0,0 +1,57 @@
+package filters
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	requestpkg "github.com/loft-sh/vcluster/pkg/util/request"
+	"github.com/pkg/errors"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	OpenIDConfigurationPath = "/.well-known/openid-configuration"
+	JWKSPath                = "/openid/v1/jwks"
+)
+
+// WithServiceAccountIssuerDiscovery serves the service account issuer discovery document and the JWKS of the virtual
+// cluster without authentication, so identity providers can validate the service account tokens of the virtual cluster.
+// If an issuer url with a path is given, the endpoints are additionally served below that path, so they can be exposed
+// at the issuer url through an ingress.
+func WithServiceAccountIssuerDiscovery(h http.Handler, virtualClient kubernetes.Interface, issuerURL string) http.Handler {
+	prefix := ""
+	if parsed, err := url.Parse(issuerURL); err == nil {
+		prefix = strings.TrimSuffix(parsed.Path, "/")
+	}
+
+	paths := map[string]string{
+		OpenIDConfigurationPath:          OpenIDConfigurationPath,
+		JWKSPath:                         JWKSPath,
+		prefix + OpenIDConfigurationPath: OpenIDConfigurationPath,
+		prefix + JWKSPath:                JWKSPath,
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		path, ok := paths[req.URL.Path]
+		if !ok || req.Method != http.MethodGet {
+			h.ServeHTTP(w, req)
+			return
+		}
+
+		// the api server only serves these to authenticated users by default, so request them as vcluster admin
+		body, err := virtualClient.Discovery().RESTClient().Get().AbsPath(path).DoRaw(req.Context())
+		if err != nil {
+			requestpkg.FailWithStatus(w, req, http.StatusBadGateway, errors.Wrap(err, "get "+path))
+			return
+		}
+
+		contentType := "application/json"
+		if path == JWKSPath {
+			contentType = "application/jwk-set+json"
+		}
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("Cache-Control", "public, max-age=3600")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(body)
+	})
+}
@@ -0,0 +1,56 @@
+package filters
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+func TestServiceAccountIssuerDiscovery(t *testing.T) {
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case OpenIDConfigurationPath:
+			_, _ = w.Write([]byte(`{"issuer":"https://oidc.example.com/my-vcluster"}`))
+		case JWKSPath:
+			_, _ = w.Write([]byte(`{"keys":[]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer apiServer.Close()
+
+	virtualClient, err := kubernetes.NewForConfig(&rest.Config{Host: apiServer.URL})
+	assert.NilError(t, err)
+
+	backend := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+	handler := WithServiceAccountIssuerDiscovery(backend, virtualClient, "https://oidc.example.com/my-vcluster/")
+
+	testCases := []struct {
+		method      string
+		path        string
+		code        int
+		contentType string
+		body        string
+	}{
+		{method: http.MethodGet, path: "/.well-known/openid-configuration", code: http.StatusOK, contentType: "application/json", body: `{"issuer":"https://oidc.example.com/my-vcluster"}`},
+		{method: http.MethodGet, path: "/my-vcluster/.well-known/openid-configuration", code: http.StatusOK, contentType: "application/json", body: `{"issuer":"https://oidc.example.com/my-vcluster"}`},
+		{method: http.MethodGet, path: "/my-vcluster/openid/v1/jwks", code: http.StatusOK, contentType: "application/jwk-set+json", body: `{"keys":[]}`},
+		{method: http.MethodPost, path: "/openid/v1/jwks", code: http.StatusUnauthorized},
+		{method: http.MethodGet, path: "/api/v1/pods", code: http.StatusUnauthorized},
+	}
+	for _, testCase := range testCases {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(testCase.method, testCase.path, nil))
+		assert.Equal(t, recorder.Code, testCase.code, testCase.path)
+		if testCase.code == http.StatusOK {
+			assert.Equal(t, recorder.Header().Get("Content-Type"), testCase.contentType, testCase.path)
+			assert.Equal(t, recorder.Body.String(), testCase.body, testCase.path)
+		}
+	}
+}
@@ -1,13 +1,10 @@
 package handler
 
 import (
-	"net"
 	"net/http"
 	"net/url"
 	"strings"
-	"time"
 
-	utilnet "k8s.io/apimachinery/pkg/util/net"
 	"k8s.io/apimachinery/pkg/util/proxy"
 	"k8s.io/apiserver/pkg/endpoints/request"
 	"k8s.io/client-go/rest"
@@ -78,12 +75,11 @@ func Handler(prefix string, cfg *rest.Config, transport http.RoundTripper) (http
 	proxy.UpgradeTransport = upgradeTransport
 	proxy.UseRequestLocation = true
 
-	handler := http.Handler(proxy)
 	if len(prefix) > 0 {
-		handler = StripLeaveSlash(prefix, handler)
+		return StripLeaveSlash(prefix, proxy), nil
 	}
 
-	return handler, nil
+	return proxy, nil
 }
 
 // like http.StripPrefix, but always leaves an initial slash. (so that our
@@ -110,20 +106,18 @@ func makeUpgradeTransport(config *rest.Config) (proxy.UpgradeRequestRoundTripper
 	if err != nil {
 		return nil, err
 	}
-	tlsConfig, err := transport.TLSConfigFor(transportConfig)
+	rt, pooled, err := upgradeTransportFor(transportConfig)
 	if err != nil {
 		return nil, err
 	}
-	rt := utilnet.SetOldTransportDefaults(&http.Transport{
-		TLSClientConfig: tlsConfig,
-		DialContext: (&net.Dialer{
-			// Timeout:   30 * time.Second,
-			KeepAlive: 120 * time.Second,
-		}).DialContext,
-	})
 	upgrader, err := transport.HTTPWrappersForConfig(transportConfig, proxy.MirrorRequest)
 	if err != nil {
 		return nil, err
 	}
-	return proxy.NewUpgradeRequestRoundTripper(rt, upgrader), nil
+	upgradeRoundTripper := proxy.NewUpgradeRequestRoundTripper(rt, upgrader)
+	if pooled {
+		return &pooledUpgradeRoundTripper{UpgradeRequestRoundTripper: upgradeRoundTripper, transport: rt}, nil
+	}
+
+	return upgradeRoundTripper, nil
 }
@@ -0,0 +1,285 @@
+package handler
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	utilnet "k8s.io/apimachinery/pkg/util/net"
+	"k8s.io/apimachinery/pkg/util/proxy"
+	"k8s.io/apimachinery/third_party/forked/golang/netutil"
+	"k8s.io/client-go/transport"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	// number of established connections kept ready per host
+	poolSize = 4
+
+	// kube-apiserver closes connections without a request after 32 seconds, so pooled connections need to be used before
+	poolMaxIdle = 20 * time.Second
+)
+
+var (
+	// PoolConnections counts the connections handed out for upgrade requests by whether they came from the pool
+	PoolConnections = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "vcluster_proxy_pool_connections_total",
+		Help: "Number of connections used for exec, attach and port-forward requests by source (pooled or dialed).",
+	}, []string{"source"})
+
+	// PoolDialDuration is the time it took to establish a connection to the host api server
+	PoolDialDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "vcluster_proxy_pool_dial_duration_seconds",
+		Help:    "Seconds it took to establish a connection to the host api server, including the tls handshake.",
+		Buckets: prometheus.ExponentialBuckets(0.005, 2, 12),
+	})
+)
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(PoolConnections, PoolDialDuration)
+}
+
+var (
+	upgradeTransportsMutex sync.Mutex
+	upgradeTransports      = map[string]*http.Transport{}
+)
+
+// upgradeTransportFor returns a shared transport for upgrade requests and whether its connections come from the pool.
+// Upgraded connections are hijacked and can't be reused, so the transport dials through a pool that keeps established
+// connections to the host ready. The pool does the tls handshake ahead of time, so DialContext and DialTLSContext both
+// hand out connections that are ready to use. proxy.DialURL does the tls handshake itself for https urls, so upgrade
+// requests through a pooled transport need to be dialed as http, see pooledUpgradeRoundTripper.
+func upgradeTransportFor(transportConfig *transport.Config) (*http.Transport, bool, error) {
+	tlsConfig, err := transport.TLSConfigFor(transportConfig)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// custom dialers, proxies and certificate callbacks can't be compared, so don't share those transports
+	key, cacheable := upgradeTransportKey(transportConfig)
+	if !cacheable {
+		return newUpgradeTransport(tlsConfig), false, nil
+	}
+
+	upgradeTransportsMutex.Lock()
+	defer upgradeTransportsMutex.Unlock()
+	if rt, ok := upgradeTransports[key]; ok {
+		return rt, true, nil
+	}
+
+	rt := newUpgradeTransport(tlsConfig)
+	dial := rt.DialContext
+	if tlsConfig != nil {
+		tlsConfig.ClientSessionCache = tls.NewLRUClientSessionCache(poolSize * 4)
+		dial = dialTLS(dial, tlsConfig)
+	}
+	pool := newConnectionPool(dial)
+	rt.DialContext = pool.DialContext
+	if tlsConfig != nil {
+		rt.DialTLSContext = pool.DialContext
+	}
+
+	upgradeTransports[key] = rt
+	return rt, true, nil
+}
+
+// dialTLS returns a dial func that does the tls handshake like proxy.DialURL. Upgrade requests are written as
+// http/1.1, so http2 is never negotiated.
+func dialTLS(dial func(ctx context.Context, network, addr string) (net.Conn, error), tlsConfig *tls.Config) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		conn, err := dial(ctx, network, addr)
+		if err != nil {
+			return nil, err
+		}
+
+		config := tlsConfig.Clone()
+		if config.ServerName == "" && !config.InsecureSkipVerify {
+			config.ServerName = addr
+			if host, _, err := net.SplitHostPort(addr); err == nil {
+				config.ServerName = host
+			}
+		}
+		config.NextProtos = []string{"http/1.1"}
+
+		tlsConn := tls.Client(conn, config)
+		err = tlsConn.HandshakeContext(ctx)
+		if err != nil {
+			_ = conn.Close()
+			return nil, err
+		}
+
+		return tlsConn, nil
+	}
+}
+
+// pooledUpgradeRoundTripper dials upgrade requests as http, so proxy.DialURL uses the established tls connections of
+// the pool as they are instead of doing another tls handshake on top of them.
+type pooledUpgradeRoundTripper struct {
+	proxy.UpgradeRequestRoundTripper
+
+	transport *http.Transport
+}
+
+// WrappedRoundTripper returns the pooled transport, which proxy.DialURL dials through.
+func (rt *pooledUpgradeRoundTripper) WrappedRoundTripper() http.RoundTripper {
+	return rt.transport
+}
+
+func (rt *pooledUpgradeRoundTripper) WrapRequest(req *http.Request) (*http.Request, error) {
+	req, err := rt.UpgradeRequestRoundTripper.WrapRequest(req)
+	if err != nil || req.URL.Scheme != "https" {
+		return req, err
+	}
+
+	location := *req.URL
+	location.Scheme = "http"
+	location.Host = netutil.CanonicalAddr(req.URL)
+	updated := req.WithContext(req.Context())
+	updated.URL = &location
+	if updated.Host == "" {
+		updated.Host = req.URL.Host
+	}
+	return updated, nil
+}
+
+func newUpgradeTransport(tlsConfig *tls.Config) *http.Transport {
+	return utilnet.SetOldTransportDefaults(&http.Transport{
+		TLSClientConfig: tlsConfig,
+		DialContext: (&net.Dialer{
+			// Timeout:   30 * time.Second,
+			KeepAlive: 120 * time.Second,
+		}).DialContext,
+	})
+}
+
+func upgradeTransportKey(c *transport.Config) (string, bool) {
+	if c.HasCertCallback() || c.DialHolder != nil || c.Proxy != nil {
+		return "", false
+	}
+
+	hash := sha256.New()
+	_, _ = fmt.Fprintf(hash, "%t/%s/%s/%s/%s/%v/", c.TLS.Insecure, c.TLS.ServerName, c.TLS.CAFile, c.TLS.CertFile, c.TLS.KeyFile, c.TLS.NextProtos)
+	_, _ = hash.Write(c.TLS.CAData)
+	_, _ = hash.Write([]byte("/"))
+	_, _ = hash.Write(c.TLS.CertData)
+	_, _ = hash.Write([]byte("/"))
+	_, _ = hash.Write(c.TLS.KeyData)
+	return hex.EncodeToString(hash.Sum(nil)), true
+}
+
+type pooledConn struct {
+	net.Conn
+	timer *time.Timer
+}
+
+type connectionPool struct {
+	dial func(ctx context.Context, network, addr string) (net.Conn, error)
+
+	m    sync.Mutex
+	idle map[string][]*pooledConn
+	// number of connections that are currently dialed to refill the pool
+	dialing map[string]int
+}
+
+func newConnectionPool(dial func(ctx context.Context, network, addr string) (net.Conn, error)) *connectionPool {
+	return &connectionPool{
+		dial:    dial,
+		idle:    map[string][]*pooledConn{},
+		dialing: map[string]int{},
+	}
+}
+
+// DialContext hands out a pooled connection if there is one and refills the pool in the background. The pool is only
+// filled after it was used, so idle virtual clusters don't keep connections open.
+func (p *connectionPool) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	conn := p.get(addr)
+	go p.refill(network, addr)
+	if conn != nil {
+		PoolConnections.WithLabelValues("pooled").Inc()
+		return conn, nil
+	}
+
+	PoolConnections.WithLabelValues("dialed").Inc()
+	return p.dialConn(ctx, network, addr)
+}
+
+func (p *connectionPool) get(addr string) net.Conn {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	for len(p.idle[addr]) > 0 {
+		conn := p.idle[addr][0]
+		p.idle[addr] = p.idle[addr][1:]
+
+		// the timer already closed the connection if it couldn't be stopped
+		if conn.timer.Stop() {
+			return conn.Conn
+		}
+	}
+
+	return nil
+}
+
+func (p *connectionPool) refill(network, addr string) {
+	p.m.Lock()
+	missing := poolSize - len(p.idle[addr]) - p.dialing[addr]
+	if missing <= 0 {
+		p.m.Unlock()
+		return
+	}
+	p.dialing[addr] += missing
+	p.m.Unlock()
+
+	for i := 0; i < missing; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		conn, err := p.dialConn(ctx, network, addr)
+		cancel()
+
+		p.m.Lock()
+		p.dialing[addr]--
+		if err == nil {
+			p.put(addr, conn)
+		}
+		p.m.Unlock()
+		if err != nil {
+			return
+		}
+	}
+}
+
+// put needs to be called with the lock held
+func (p *connectionPool) put(addr string, conn net.Conn) {
+	pooled := &pooledConn{Conn: conn}
+	pooled.timer = time.AfterFunc(poolMaxIdle, func() {
+		p.m.Lock()
+		defer p.m.Unlock()
+
+		for i, other := range p.idle[addr] {
+			if other == pooled {
+				p.idle[addr] = append(p.idle[addr][:i], p.idle[addr][i+1:]...)
+				break
+			}
+		}
+		_ = conn.Close()
+	})
+
+	p.idle[addr] = append(p.idle[addr], pooled)
+}
+
+func (p *connectionPool) dialConn(ctx context.Context, network, addr string) (net.Conn, error) {
+	start := time.Now()
+	conn, err := p.dial(ctx, network, addr)
+	if err != nil {
+		return nil, err
+	}
+
+	PoolDialDuration.Observe(time.Since(start).Seconds())
+	return conn, nil
+}
@@ -0,0 +1,163 @@
+package handler
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	"gotest.tools/assert"
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	"k8s.io/client-go/rest"
+)
+
+func TestConnectionPool(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NilError(t, err)
+	defer listener.Close()
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+
+	pool := newConnectionPool((&net.Dialer{}).DialContext)
+	addr := listener.Addr().String()
+
+	// the first connection is dialed and fills the pool
+	conn, err := pool.DialContext(context.Background(), "tcp", addr)
+	assert.NilError(t, err)
+	defer conn.Close()
+	waitFor(t, func() bool {
+		pool.m.Lock()
+		defer pool.m.Unlock()
+		return len(pool.idle[addr]) == poolSize
+	})
+
+	// the next connections come from the pool without dialing
+	for i := 0; i < poolSize; i++ {
+		assert.Assert(t, pool.get(addr) != nil)
+	}
+	assert.Assert(t, pool.get(addr) == nil)
+}
+
+func TestUpgradeTransportIsShared(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	defer server.Close()
+
+	cfg := &rest.Config{Host: server.URL, TLSClientConfig: rest.TLSClientConfig{Insecure: true}}
+	transportConfig, err := cfg.TransportConfig()
+	assert.NilError(t, err)
+
+	first, pooled, err := upgradeTransportFor(transportConfig)
+	assert.NilError(t, err)
+	assert.Assert(t, pooled)
+	second, _, err := upgradeTransportFor(transportConfig)
+	assert.NilError(t, err)
+	assert.Assert(t, first == second)
+
+	// impersonation is applied by the wrappers and shares the transport
+	impersonated := rest.CopyConfig(cfg)
+	impersonated.Impersonate.UserName = "test"
+	transportConfig, err = impersonated.TransportConfig()
+	assert.NilError(t, err)
+	third, _, err := upgradeTransportFor(transportConfig)
+	assert.NilError(t, err)
+	assert.Assert(t, first == third)
+
+	// the pooled connections work
+	resp, err := first.RoundTrip(httptest.NewRequest(http.MethodGet, server.URL, nil))
+	assert.NilError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, resp.StatusCode, http.StatusOK)
+}
+
+func TestUpgradeUsesPool(t *testing.T) {
+	accepted := atomic.Int32{}
+	handshakes := atomic.Int32{}
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if !httpstream.IsUpgradeRequest(req) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		conn, buf, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		_, _ = buf.WriteString("HTTP/1.1 101 Switching Protocols\r\nConnection: Upgrade\r\nUpgrade: SPDY/3.1\r\n\r\n")
+		_ = buf.Flush()
+	}))
+	server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			accepted.Add(1)
+		}
+	}
+	server.TLS = &tls.Config{GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+		handshakes.Add(1)
+		return nil, nil
+	}}
+	server.StartTLS()
+	defer server.Close()
+
+	handler, err := Handler("", &rest.Config{Host: server.URL, TLSClientConfig: rest.TLSClientConfig{Insecure: true}}, nil)
+	assert.NilError(t, err)
+	proxyServer := httptest.NewServer(handler)
+	defer proxyServer.Close()
+
+	upgrade := func() {
+		conn, err := net.Dial("tcp", proxyServer.Listener.Addr().String())
+		assert.NilError(t, err)
+		defer conn.Close()
+
+		_, err = conn.Write([]byte("POST /api/v1/namespaces/default/pods/test/exec HTTP/1.1\r\nHost: vcluster\r\nConnection: Upgrade\r\nUpgrade: SPDY/3.1\r\n\r\n"))
+		assert.NilError(t, err)
+		resp, err := http.ReadResponse(bufio.NewReader(conn), nil)
+		assert.NilError(t, err)
+		_ = resp.Body.Close()
+		assert.Equal(t, resp.StatusCode, http.StatusSwitchingProtocols)
+	}
+
+	// the first upgrade dials and fills the pool
+	dialed, pooled := counterValue(t, "dialed"), counterValue(t, "pooled")
+	upgrade()
+	assert.Equal(t, counterValue(t, "dialed"), dialed+1)
+	waitFor(t, func() bool { return accepted.Load() == 1+poolSize })
+
+	// the pooled connections are ready to use, the tls handshake was done ahead of time
+	waitFor(t, func() bool { return handshakes.Load() == 1+poolSize })
+
+	// the next upgrade uses a pooled connection
+	upgrade()
+	assert.Equal(t, counterValue(t, "pooled"), pooled+1)
+	assert.Equal(t, counterValue(t, "dialed"), dialed+1)
+}
+
+func counterValue(t *testing.T, source string) float64 {
+	metric := &dto.Metric{}
+	assert.NilError(t, PoolConnections.WithLabelValues(source).Write(metric))
+	return metric.GetCounter().GetValue()
+}
+
+func waitFor(t *testing.T, condition func() bool) {
+	for i := 0; i < 100; i++ {
+		if condition() {
+			return
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	t.Fatal("timed out waiting for condition")
+}
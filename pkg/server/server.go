@@ -2,6 +2,8 @@ package server
 
 import (
 	"context"
+	"crypto/tls"
+	"fmt"
 	"io"
 	"net"
 	"net/http"
@@ -14,6 +16,7 @@ import (
 	"github.com/loft-sh/vcluster/pkg/authorization/delegatingauthorizer"
 	"github.com/loft-sh/vcluster/pkg/authorization/impersonationauthorizer"
 	"github.com/loft-sh/vcluster/pkg/authorization/kubeletauthorizer"
+	"github.com/loft-sh/vcluster/pkg/autosleep"
 	"github.com/loft-sh/vcluster/pkg/config"
 	"github.com/loft-sh/vcluster/pkg/constants"
 	"github.com/loft-sh/vcluster/pkg/controllers/resources/nodes"
@@ -24,13 +27,18 @@ import (
 	"github.com/loft-sh/vcluster/pkg/server/handler"
 	servertypes "github.com/loft-sh/vcluster/pkg/server/types"
 	"github.com/loft-sh/vcluster/pkg/util/blockingcacheclient"
+	"github.com/loft-sh/vcluster/pkg/util/crdconversion"
 	"github.com/loft-sh/vcluster/pkg/util/pluginhookclient"
 	"github.com/loft-sh/vcluster/pkg/util/serverhelper"
 	"github.com/loft-sh/vcluster/pkg/util/translate"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	nodev1 "k8s.io/api/node/v1"
+	storagev1 "k8s.io/api/storage/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apiserver/pkg/admission"
@@ -70,7 +78,13 @@ type Server struct {
 	requestHeaderCaFile    string
 	clientCaFile           string
 	redirectResources      []delegatingauthorizer.GroupVersionResourceVerb
-	fakeKubeletIPs         bool
+	// cachedResources are served by the proxy itself, so they are authorized against the virtual cluster
+	cachedResources []delegatingauthorizer.GroupVersionResourceVerb
+	fakeKubeletIPs  bool
+
+	// serviceAccountIssuerClient is used to serve the service account issuer discovery endpoints if enabled
+	serviceAccountIssuerClient kubernetes.Interface
+	serviceAccountIssuerURL    string
 }
 
 // NewServer creates and installs a new Server.
@@ -93,7 +107,7 @@ func NewServer(ctx *config.ControllerContext, requestHeaderCaFile, clientCaFile
 		return nil, err
 	}
 
-	cachedLocalClient, err := createCachedClient(ctx.Context, localConfig, ctx.Config.WorkloadNamespace, uncachedLocalClient.RESTMapper(), uncachedLocalClient.Scheme(), func(cache cache.Cache) error {
+	cachedLocalClient, _, err := createCachedClient(ctx.Context, localConfig, ctx.Config.WorkloadNamespace, uncachedLocalClient.RESTMapper(), uncachedLocalClient.Scheme(), func(cache cache.Cache) error {
 		if ctx.Config.Networking.Advanced.ProxyKubelets.ByIP {
 			err := cache.IndexField(ctx.Context, &corev1.Service{}, constants.IndexByClusterIP, func(object client.Object) []string {
 				svc := object.(*corev1.Service)
@@ -113,7 +127,7 @@ func NewServer(ctx *config.ControllerContext, requestHeaderCaFile, clientCaFile
 	if err != nil {
 		return nil, err
 	}
-	cachedVirtualClient, err := createCachedClient(ctx.Context, virtualConfig, corev1.NamespaceAll, uncachedVirtualClient.RESTMapper(), uncachedVirtualClient.Scheme(), func(cache cache.Cache) error {
+	cachedVirtualClient, virtualCache, err := createCachedClient(ctx.Context, virtualConfig, corev1.NamespaceAll, uncachedVirtualClient.RESTMapper(), uncachedVirtualClient.Scheme(), func(cache cache.Cache) error {
 		err := cache.IndexField(ctx.Context, &corev1.PersistentVolumeClaim{}, constants.IndexByPhysicalName, func(rawObj client.Object) []string {
 			return []string{translate.Default.PhysicalNamespace(rawObj.GetNamespace()) + "/" + translate.Default.PhysicalName(rawObj.GetName(), rawObj.GetNamespace())}
 		})
@@ -151,6 +165,14 @@ func NewServer(ctx *config.ControllerContext, requestHeaderCaFile, clientCaFile
 		return nil, errors.Wrap(err, "create cert syncer")
 	}
 
+	var serviceAccountIssuerClient kubernetes.Interface
+	if ctx.Config.ControlPlane.Advanced.ServiceAccountIssuer.Enabled {
+		serviceAccountIssuerClient, err = kubernetes.NewForConfig(virtualConfig)
+		if err != nil {
+			return nil, errors.Wrap(err, "create service account issuer client")
+		}
+	}
+
 	s := &Server{
 		uncachedVirtualClient: uncachedVirtualClient,
 		cachedVirtualClient:   cachedVirtualClient,
@@ -159,6 +181,9 @@ func NewServer(ctx *config.ControllerContext, requestHeaderCaFile, clientCaFile
 
 		fakeKubeletIPs: ctx.Config.Networking.Advanced.ProxyKubelets.ByIP,
 
+		serviceAccountIssuerClient: serviceAccountIssuerClient,
+		serviceAccountIssuerURL:    ctx.Config.ControlPlane.Advanced.ServiceAccountIssuer.URL,
+
 		currentNamespace:       ctx.Config.WorkloadNamespace,
 		currentNamespaceClient: cachedLocalClient,
 
@@ -200,10 +225,28 @@ func NewServer(ctx *config.ControllerContext, requestHeaderCaFile, clientCaFile
 	}
 
 	h := handler.ImpersonatingHandler("", virtualConfig)
-	h = filters.WithServiceCreateRedirect(h, uncachedLocalClient, uncachedVirtualClient, virtualConfig, ctx.Config.Experimental.SyncSettings.SyncLabels)
+	h = filters.WithListStreaming(h, ctx.Config.ControlPlane.Proxy.ListPageSize)
+	if ctx.Config.ControlPlane.Proxy.FromHostCache.Enabled {
+		resources := fromHostCacheResources(ctx.Config)
+		for _, resource := range resources {
+			for _, verb := range []string{"get", "list"} {
+				s.cachedResources = append(s.cachedResources, delegatingauthorizer.GroupVersionResourceVerb{GroupVersionResource: resource, Verb: verb})
+			}
+		}
+		h = filters.WithFromHostCache(h, cachedVirtualClient, virtualCache, resources, time.Duration(ctx.Config.ControlPlane.Proxy.FromHostCache.TTL)*time.Second)
+	}
+	if ctx.Config.ControlPlane.Proxy.HostDryRun {
+		h = filters.WithHostDryRun(h, uncachedLocalClient, hostDryRunResources(ctx.Config), ctx.Config.Experimental.SyncSettings.SyncLabels)
+	}
+	h = filters.WithServiceCreateRedirect(h, uncachedLocalClient, uncachedVirtualClient, virtualConfig, ctx.Config.Experimental.SyncSettings.SyncLabels, ctx.Config.Integrations.ExternalDNS)
 	h = filters.WithRedirect(h, localConfig, uncachedLocalClient.Scheme(), uncachedVirtualClient, admissionHandler, s.redirectResources)
 	h = filters.WithMetricsProxy(h, localConfig, cachedVirtualClient)
 
+	// deny direct access to the host nodes
+	if !ctx.Config.Policies.HostNodeAccess.AllowDebug || !ctx.Config.Policies.HostNodeAccess.AllowProxy {
+		h = filters.WithHostNodeAccess(h, uncachedVirtualClient.Scheme(), ctx.VirtualManager.GetEventRecorderFor("vcluster-host-node-access"), ctx.Config.Policies.HostNodeAccess.AllowDebug, ctx.Config.Policies.HostNodeAccess.AllowProxy)
+	}
+
 	// is metrics proxy enabled?
 	if ctx.Config.Observability.Metrics.Proxy.Nodes || ctx.Config.Observability.Metrics.Proxy.Pods {
 		h = filters.WithMetricsServerProxy(
@@ -223,6 +266,11 @@ func NewServer(ctx *config.ControllerContext, requestHeaderCaFile, clientCaFile
 	h = filters.WithFakeKubelet(h, localConfig, cachedVirtualClient)
 	h = filters.WithK3sConnect(h)
 
+	// track activity for auto sleep
+	if ctx.Config.Experimental.AutoSleep.Enabled {
+		h = filters.WithActivityTracking(h, autosleep.DefaultTracker)
+	}
+
 	if os.Getenv("DEBUG") == "true" {
 		h = filters.WithPprof(h)
 	}
@@ -240,6 +288,44 @@ func NewServer(ctx *config.ControllerContext, requestHeaderCaFile, clientCaFile
 	return s, nil
 }
 
+// hostDryRunResources returns the resources that are synced to the host cluster and checked by the host dry-run
+func hostDryRunResources(vConfig *config.VirtualClusterConfig) []string {
+	resources := []string{"services"}
+	if vConfig.Sync.ToHost.Pods.Enabled {
+		resources = append(resources, "pods")
+	}
+	if vConfig.Sync.ToHost.ConfigMaps.Enabled {
+		resources = append(resources, "configmaps")
+	}
+	if vConfig.Sync.ToHost.Secrets.Enabled {
+		resources = append(resources, "secrets")
+	}
+
+	return resources
+}
+
+// fromHostCacheResources returns the cluster scoped resources that are synced from the host cluster and served from cache
+func fromHostCacheResources(vConfig *config.VirtualClusterConfig) []schema.GroupVersionResource {
+	resources := []schema.GroupVersionResource{corev1.SchemeGroupVersion.WithResource("nodes")}
+	if vConfig.Sync.FromHost.StorageClasses.Enabled == "true" {
+		resources = append(resources, storagev1.SchemeGroupVersion.WithResource("storageclasses"))
+	}
+	if vConfig.Sync.FromHost.IngressClasses.Enabled {
+		resources = append(resources, networkingv1.SchemeGroupVersion.WithResource("ingressclasses"))
+	}
+	if vConfig.Sync.FromHost.RuntimeClasses.Enabled {
+		resources = append(resources, nodev1.SchemeGroupVersion.WithResource("runtimeclasses"))
+	}
+	if vConfig.Sync.FromHost.CSINodes.Enabled == "true" {
+		resources = append(resources, storagev1.SchemeGroupVersion.WithResource("csinodes"))
+	}
+	if vConfig.Sync.FromHost.CSIDrivers.Enabled == "true" {
+		resources = append(resources, storagev1.SchemeGroupVersion.WithResource("csidrivers"))
+	}
+
+	return resources
+}
+
 // ServeOnListenerTLS starts the server using given listener with TLS, loops forever until an error occurs
 func (s *Server) ServeOnListenerTLS(address string, port int, stopChan <-chan struct{}) error {
 	// kubernetes build handler configuration
@@ -261,6 +347,7 @@ func (s *Server) ServeOnListenerTLS(address string, port int, stopChan <-chan st
 		},
 	}
 	redirectAuthResources = append(redirectAuthResources, s.redirectResources...)
+	redirectAuthResources = append(redirectAuthResources, s.cachedResources...)
 	serverConfig.Authorization.Authorizer = union.New(
 		kubeletauthorizer.New(s.uncachedVirtualClient),
 		delegatingauthorizer.New(s.uncachedVirtualClient, redirectAuthResources, nil),
@@ -302,7 +389,7 @@ func (s *Server) ServeOnListenerTLS(address string, port int, stopChan <-chan st
 	return nil
 }
 
-func createCachedClient(ctx context.Context, config *rest.Config, namespace string, restMapper meta.RESTMapper, scheme *runtime.Scheme, registerIndices func(cache cache.Cache) error) (client.Client, error) {
+func createCachedClient(ctx context.Context, config *rest.Config, namespace string, restMapper meta.RESTMapper, scheme *runtime.Scheme, registerIndices func(cache cache.Cache) error) (client.Client, cache.Cache, error) {
 	// create cache options
 	cacheOptions := cache.Options{
 		Scheme: scheme,
@@ -315,14 +402,14 @@ func createCachedClient(ctx context.Context, config *rest.Config, namespace stri
 	// create the new cache
 	clientCache, err := cache.New(config, cacheOptions)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	// register indices
 	if registerIndices != nil {
 		err = registerIndices(clientCache)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 	}
 
@@ -344,15 +431,54 @@ func createCachedClient(ctx context.Context, config *rest.Config, namespace stri
 		},
 	})
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+
+	return cachedVirtualClient, clientCache, nil
+}
+
+// ServeCRDConversion serves the conversion reviews of the virtual api server for CRDs synced from the host cluster on
+// a dedicated loopback listener. The virtual api server calls webhooks without credentials, so the reviews can't be
+// served by the proxy, which users reach through port-forwarding. The listener uses the serving certificate of the
+// proxy. Loops forever until an error occurs or the stop channel is closed.
+func (s *Server) ServeCRDConversion(router *crdconversion.Router, port int, stopChan <-chan struct{}) error {
+	conversionServer := &http.Server{
+		Addr:              net.JoinHostPort("127.0.0.1", strconv.Itoa(port)),
+		Handler:           router,
+		ReadHeaderTimeout: 30 * time.Second,
+		TLSConfig: &tls.Config{
+			MinVersion: tls.VersionTLS12,
+			GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+				cert, err := tls.X509KeyPair(s.certSyncer.CurrentCertKeyContent())
+				if err != nil {
+					return nil, fmt.Errorf("load serving certificate: %w", err)
+				}
+
+				return &cert, nil
+			},
+		},
 	}
+	go func() {
+		<-stopChan
+		_ = conversionServer.Close()
+	}()
 
-	return cachedVirtualClient, nil
+	klog.Infof("Starting crd conversion server at %s", conversionServer.Addr)
+	err := conversionServer.ListenAndServeTLS("", "")
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	return nil
 }
 
 func (s *Server) buildHandlerChain(serverConfig *server.Config) http.Handler {
 	defaultHandler := DefaultBuildHandlerChain(s.handler, serverConfig)
 	defaultHandler = filters.WithNodeName(defaultHandler, s.currentNamespace, s.fakeKubeletIPs, s.cachedVirtualClient, s.currentNamespaceClient)
+	if s.serviceAccountIssuerClient != nil {
+		// discovery endpoints are public, so they are served before authentication
+		defaultHandler = filters.WithServiceAccountIssuerDiscovery(defaultHandler, s.serviceAccountIssuerClient, s.serviceAccountIssuerURL)
+	}
 	return defaultHandler
 }
 
@@ -80,6 +80,7 @@ func NewControllerContext(ctx context.Context, options *config.VirtualClusterCon
 		Scheme:         scheme.Scheme,
 		Metrics:        metricsserver.Options{BindAddress: virtualManagerMetrics},
 		LeaderElection: false,
+		Cache:          cache.Options{SyncPeriod: getResyncPeriod(options)},
 		NewClient:      pro.NewVirtualClient(options),
 	})
 	if err != nil {
@@ -101,6 +102,16 @@ func NewControllerContext(ctx context.Context, options *config.VirtualClusterCon
 	return controllerContext, nil
 }
 
+// getResyncPeriod returns the resync period of the density profile or nil to use the controller-runtime default
+func getResyncPeriod(options *config.VirtualClusterConfig) *time.Duration {
+	if !options.ControlPlane.Advanced.Density.Enabled || options.ControlPlane.Advanced.Density.ResyncPeriod <= 0 {
+		return nil
+	}
+
+	resyncPeriod := time.Duration(options.ControlPlane.Advanced.Density.ResyncPeriod) * time.Minute
+	return &resyncPeriod
+}
+
 func getLocalCacheOptions(options *config.VirtualClusterConfig) cache.Options {
 	// is multi namespace mode?
 	defaultNamespaces := make(map[string]cache.Config)
@@ -115,9 +126,9 @@ func getLocalCacheOptions(options *config.VirtualClusterConfig) cache.Options {
 	}
 
 	if len(defaultNamespaces) == 0 {
-		return cache.Options{DefaultNamespaces: nil}
+		return cache.Options{DefaultNamespaces: nil, SyncPeriod: getResyncPeriod(options)}
 	}
-	return cache.Options{DefaultNamespaces: defaultNamespaces}
+	return cache.Options{DefaultNamespaces: defaultNamespaces, SyncPeriod: getResyncPeriod(options)}
 }
 
 func startPlugins(ctx context.Context, virtualConfig *rest.Config, virtualRawConfig *clientcmdapi.Config, options *config.VirtualClusterConfig) error {
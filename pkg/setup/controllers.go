@@ -4,16 +4,21 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"path/filepath"
 	"time"
 
+	"github.com/loft-sh/vcluster/pkg/autosleep"
 	"github.com/loft-sh/vcluster/pkg/config"
 	"github.com/loft-sh/vcluster/pkg/controllers"
 	"github.com/loft-sh/vcluster/pkg/controllers/resources/services"
 	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
 	"github.com/loft-sh/vcluster/pkg/coredns"
+	"github.com/loft-sh/vcluster/pkg/konnectivity"
+	"github.com/loft-sh/vcluster/pkg/leaderelection"
 	"github.com/loft-sh/vcluster/pkg/metricsapiservice"
 	"github.com/loft-sh/vcluster/pkg/plugin"
 	"github.com/loft-sh/vcluster/pkg/pro"
+	"github.com/loft-sh/vcluster/pkg/scheme"
 	"github.com/loft-sh/vcluster/pkg/specialservices"
 	syncertypes "github.com/loft-sh/vcluster/pkg/types"
 	"github.com/loft-sh/vcluster/pkg/util/kubeconfig"
@@ -40,26 +45,7 @@ func StartControllers(controllerContext *config.ControllerContext) error {
 	// start coredns & create syncers
 	var syncers []syncertypes.Object
 	if !controllerContext.Config.Experimental.SyncSettings.DisableSync {
-		// setup CoreDNS according to the manifest file
-		// skip this if both integrated and dedicated coredns
-		// deployments are explicitly disabled
-		go func() {
-			// apply coredns
-			ApplyCoreDNS(controllerContext)
-
-			// delete coredns deployment if integrated core dns
-			if controllerContext.Config.ControlPlane.CoreDNS.Embedded {
-				err := controllerContext.VirtualManager.GetClient().Delete(controllerContext.Context, &appsv1.Deployment{
-					ObjectMeta: metav1.ObjectMeta{
-						Name:      "coredns",
-						Namespace: "kube-system",
-					},
-				})
-				if err != nil && !kerrors.IsNotFound(err) {
-					klog.Errorf("Error deleting coredns deployment: %v", err)
-				}
-			}
-		}()
+		go DeployCoreDNS(controllerContext)
 
 		// init syncers
 		syncers, err = controllers.Create(controllerContext)
@@ -74,6 +60,73 @@ func StartControllers(controllerContext *config.ControllerContext) error {
 		return err
 	}
 
+	return startLeaderControllers(controllerContext, controlPlaneClient, syncers)
+}
+
+// StartShardedControllers starts the syncers on every replica, each replica only syncs the objects of the shard the
+// leader assigned to it. All other controllers only run on the leader, which also assigns the shards.
+func StartShardedControllers(controllerContext *config.ControllerContext) error {
+	syncers, err := controllers.Create(controllerContext)
+	if err != nil {
+		return errors.Wrap(err, "instantiate controllers")
+	}
+
+	// start managers
+	err = StartManagers(controllerContext, syncers)
+	if err != nil {
+		return err
+	}
+
+	// register controllers for resource synchronization, they wait for a shard assignment
+	err = controllers.RegisterSyncers(controllerContext, syncers)
+	if err != nil {
+		return err
+	}
+	err = leaderelection.StartShardMember(controllerContext)
+	if err != nil {
+		return errors.Wrap(err, "start shard member")
+	}
+
+	return leaderelection.StartLeaderElection(controllerContext, scheme.Scheme, func() error {
+		controlPlaneClient, err := pro.ExchangeControlPlaneClient(controllerContext)
+		if err != nil {
+			return err
+		}
+
+		go DeployCoreDNS(controllerContext)
+		go leaderelection.AssignShards(controllerContext)
+		return startLeaderControllers(controllerContext, controlPlaneClient, nil)
+	})
+}
+
+// DeployCoreDNS sets up CoreDNS according to the manifest file or registers at the shared CoreDNS
+func DeployCoreDNS(controllerContext *config.ControllerContext) {
+	// register at the shared coredns instead of deploying a dedicated one
+	if controllerContext.Config.Networking.Advanced.SharedDNS.Enabled {
+		ApplySharedDNS(controllerContext)
+		return
+	}
+
+	// apply coredns
+	ApplyCoreDNS(controllerContext)
+
+	// delete coredns deployment if integrated core dns
+	if controllerContext.Config.ControlPlane.CoreDNS.Embedded {
+		err := controllerContext.VirtualManager.GetClient().Delete(controllerContext.Context, &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "coredns",
+				Namespace: "kube-system",
+			},
+		})
+		if err != nil && !kerrors.IsNotFound(err) {
+			klog.Errorf("Error deleting coredns deployment: %v", err)
+		}
+	}
+}
+
+// startLeaderControllers starts the controllers that only run on the leader after the managers were started. The
+// given syncers are registered as well.
+func startLeaderControllers(controllerContext *config.ControllerContext, controlPlaneClient client.Client, syncers []syncertypes.Object) error {
 	// sync remote Endpoints
 	if controllerContext.Config.Experimental.IsolatedControlPlane.KubeConfig != "" {
 		err := pro.SyncRemoteEndpoints(
@@ -94,6 +147,20 @@ func StartControllers(controllerContext *config.ControllerContext) error {
 		}
 	}
 
+	// publish the konnectivity agent certificates to the workload cluster
+	if konnectivity.IsEnabled(controllerContext.Config.Experimental.IsolatedControlPlane) {
+		err := konnectivity.EnsureAgentSecret(
+			controllerContext.Context,
+			controllerContext.Config.WorkloadClient,
+			controllerContext.Config.WorkloadNamespace,
+			controllerContext.Config.WorkloadService,
+			filepath.Dir(controllerContext.Config.VirtualClusterKubeConfig().ServerCACert),
+		)
+		if err != nil {
+			return errors.Wrap(err, "ensure konnectivity agent secret")
+		}
+	}
+
 	// sync endpoints for noop syncer
 	if controllerContext.Config.Experimental.SyncSettings.DisableSync && controllerContext.Config.Experimental.SyncSettings.RewriteKubernetesService {
 		err := pro.SyncNoopSyncerEndpoints(
@@ -117,7 +184,7 @@ func StartControllers(controllerContext *config.ControllerContext) error {
 	// if not noop syncer
 	if !controllerContext.Config.Experimental.SyncSettings.DisableSync {
 		// make sure the kubernetes service is synced
-		err = SyncKubernetesService(controllerContext)
+		err := SyncKubernetesService(controllerContext)
 		if err != nil {
 			return errors.Wrap(err, "sync kubernetes service")
 		}
@@ -134,6 +201,17 @@ func StartControllers(controllerContext *config.ControllerContext) error {
 		return fmt.Errorf("register pro controllers: %w", err)
 	}
 
+	// start auto sleep controller
+	if controllerContext.Config.Experimental.AutoSleep.Enabled {
+		go autosleep.NewController(
+			controllerContext.VirtualManager.GetClient(),
+			autosleep.DefaultTracker,
+			autosleep.DefaultSharedActivity,
+			time.Duration(controllerContext.Config.Experimental.AutoSleep.AfterInactivity)*time.Minute,
+			controllerContext.Config.Experimental.AutoSleep.ExcludeNamespaces,
+		).Start(controllerContext.Context)
+	}
+
 	// write the kube config to secret
 	go func() {
 		wait.Until(func() {
@@ -145,7 +223,7 @@ func StartControllers(controllerContext *config.ControllerContext) error {
 	}()
 
 	// set leader
-	err = plugin.DefaultManager.SetLeader(controllerContext.Context)
+	err := plugin.DefaultManager.SetLeader(controllerContext.Context)
 	if err != nil {
 		return fmt.Errorf("plugin set leader: %w", err)
 	}
@@ -169,6 +247,42 @@ func ApplyCoreDNS(controllerContext *config.ControllerContext) {
 	})
 }
 
+func ApplySharedDNS(controllerContext *config.ControllerContext) {
+	sharedDNS := controllerContext.Config.Networking.Advanced.SharedDNS
+	tenant := coredns.NewSharedDNSTenant(controllerContext.Config)
+	_ = wait.ExponentialBackoffWithContext(controllerContext.Context, wait.Backoff{Duration: time.Second, Factor: 1.5, Cap: time.Minute, Steps: math.MaxInt32}, func(ctx context.Context) (bool, error) {
+		// the shared namespace is not cached, the virtual cluster only has access to its own objects within it
+		hostClient, err := client.New(controllerContext.LocalManager.GetConfig(), client.Options{
+			Scheme: controllerContext.LocalManager.GetScheme(),
+			Mapper: controllerContext.LocalManager.GetRESTMapper(),
+		})
+		if err != nil {
+			klog.Infof("Failed to create shared CoreDNS client: %v", err)
+			return false, nil
+		}
+
+		// the shared CoreDNS only gets a token that can read services, endpoints and namespaces
+		token, ca, err := coredns.EnsureSharedDNSServiceAccount(ctx, controllerContext.VirtualManager.GetClient())
+		if err != nil {
+			klog.Infof("Failed to create shared CoreDNS service account: %v", err)
+			return false, nil
+		}
+
+		err = coredns.RegisterSharedDNSTenant(ctx, hostClient, sharedDNS.Namespace, tenant, coredns.SharedDNSCredentials{
+			Server: fmt.Sprintf("https://%s.%s:443", controllerContext.Config.WorkloadService, controllerContext.Config.WorkloadNamespace),
+			Token:  token,
+			CA:     ca,
+		})
+		if err != nil {
+			klog.Infof("Failed to register at shared CoreDNS: %v", err)
+			return false, nil
+		}
+
+		klog.Infof("Registered virtual cluster at shared CoreDNS in namespace %s", sharedDNS.Namespace)
+		return true, nil
+	})
+}
+
 func SyncKubernetesService(ctx *config.ControllerContext) error {
 	err := specialservices.SyncKubernetesService(
 		&synccontext.SyncContext{
@@ -233,8 +347,10 @@ func StartManagers(controllerContext *config.ControllerContext, syncers []syncer
 	controllerContext.VirtualManager.GetCache().WaitForCacheSync(controllerContext.Context)
 	klog.Infof("Successfully started local & virtual manager")
 
-	// register APIService
-	go RegisterOrDeregisterAPIService(controllerContext)
+	// register APIService, this is done by the primary syncer for satellites
+	if !controllerContext.Config.Experimental.MultiCluster.Satellite.Enabled {
+		go RegisterOrDeregisterAPIService(controllerContext)
+	}
 
 	return nil
 }
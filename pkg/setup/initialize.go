@@ -31,6 +31,11 @@ import (
 
 // Initialize creates the required secrets and configmaps for the control plane to start
 func Initialize(ctx context.Context, options *config.VirtualClusterConfig) error {
+	// a satellite connects to an existing control plane, so there is nothing to initialize
+	if options.Experimental.MultiCluster.Satellite.Enabled {
+		return nil
+	}
+
 	// Ensure that service CIDR range is written into the expected location
 	err := wait.PollUntilContextTimeout(ctx, 5*time.Second, 2*time.Minute, true, func(waitCtx context.Context) (bool, error) {
 		err := initialize(
@@ -65,13 +70,9 @@ func initialize(ctx context.Context, parentCtx context.Context, options *config.
 	}
 
 	// retrieve service cidr
-	serviceCIDR := options.ServiceCIDR
-	if serviceCIDR == "" {
-		var warning string
-		serviceCIDR, warning = servicecidr.GetServiceCIDR(ctx, options.WorkloadClient, options.WorkloadNamespace)
-		if warning != "" {
-			klog.Warning(warning)
-		}
+	serviceCIDR, err := ensureServiceCIDR(ctx, options)
+	if err != nil {
+		return err
 	}
 
 	// check what distro are we running
@@ -233,6 +234,38 @@ func initialize(ctx context.Context, parentCtx context.Context, options *config.
 	return nil
 }
 
+// ensureServiceCIDR returns the configured service cidr after validating it against the host service cidr or detects
+// the host service cidr if none is configured
+func ensureServiceCIDR(ctx context.Context, options *config.VirtualClusterConfig) (string, error) {
+	serviceCIDR := options.Networking.Advanced.ServiceCIDR
+	if serviceCIDR == "" {
+		serviceCIDR = options.ServiceCIDR
+	}
+	if serviceCIDR == "" {
+		hostServiceCIDR, warning := servicecidr.GetServiceCIDR(ctx, options.WorkloadClient, options.WorkloadNamespace)
+		if warning != "" {
+			klog.Warning(warning)
+		}
+
+		return hostServiceCIDR, nil
+	}
+
+	hostServiceCIDR, warning, err := servicecidr.DetectServiceCIDR(ctx, options.WorkloadClient, options.WorkloadNamespace)
+	if err != nil {
+		klog.Warningf("failed to detect host service CIDR, skipping validation of service CIDR %s: %v", serviceCIDR, err)
+		return serviceCIDR, nil
+	} else if warning != "" {
+		klog.Warning(warning)
+	}
+
+	err = servicecidr.ValidateServiceCIDR(serviceCIDR, hostServiceCIDR)
+	if err != nil {
+		return "", fmt.Errorf("validate service cidr: %w", err)
+	}
+
+	return serviceCIDR, nil
+}
+
 func GenerateCerts(ctx context.Context, currentNamespaceClient kubernetes.Interface, vClusterName, currentNamespace, serviceCIDR, certificatesDir string, options *config.VirtualClusterConfig) error {
 	clusterDomain := options.Networking.Advanced.ClusterDomain
 	// generate etcd server and peer sans
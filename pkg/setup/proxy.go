@@ -1,9 +1,17 @@
 package setup
 
 import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/loft-sh/vcluster/pkg/autosleep"
 	"github.com/loft-sh/vcluster/pkg/config"
 	"github.com/loft-sh/vcluster/pkg/pro"
 	"github.com/loft-sh/vcluster/pkg/server"
+	"github.com/loft-sh/vcluster/pkg/util/crdconversion"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/klog/v2"
 )
 
@@ -16,6 +24,28 @@ func StartProxy(ctx *config.ControllerContext) error {
 		}
 	}
 
+	// route conversion reviews of CRDs synced from the host cluster through the proxy
+	caBundle, err := os.ReadFile(ctx.Config.VirtualClusterKubeConfig().ServerCACert)
+	if err != nil {
+		klog.Warningf("Error reading server ca, conversion webhooks of host CRDs will not be available in the virtual cluster: %v", err)
+	} else {
+		crdconversion.Default, err = crdconversion.NewRouter("https://127.0.0.1:"+strconv.Itoa(crdconversion.Port), caBundle)
+		if err != nil {
+			return fmt.Errorf("create crd conversion router: %w", err)
+		}
+	}
+
+	// with multiple replicas each replica only sees part of the activity, so it is shared through a lease
+	if ctx.Config.Experimental.AutoSleep.Enabled && ctx.Config.ControlPlane.StatefulSet.HighAvailability.Replicas > 1 {
+		kubeClient, err := kubernetes.NewForConfig(ctx.LocalManager.GetConfig())
+		if err != nil {
+			return fmt.Errorf("create auto sleep client: %w", err)
+		}
+
+		autosleep.DefaultSharedActivity = autosleep.NewSharedActivity(kubeClient, ctx.Config.WorkloadNamespace, translate.SafeConcatName("vcluster", translate.VClusterName, "activity"), autosleep.DefaultTracker)
+		go autosleep.DefaultSharedActivity.Start(ctx.Context)
+	}
+
 	// start the proxy
 	proxyServer, err := server.NewServer(ctx, ctx.Config.VirtualClusterKubeConfig().RequestHeaderCACert, ctx.Config.VirtualClusterKubeConfig().ClientCACert)
 	if err != nil {
@@ -30,5 +60,15 @@ func StartProxy(ctx *config.ControllerContext) error {
 		}
 	}()
 
+	// serve the conversion reviews of the virtual api server on a dedicated loopback listener
+	if crdconversion.Default != nil {
+		go func() {
+			err := proxyServer.ServeCRDConversion(crdconversion.Default, crdconversion.Port, ctx.StopChan)
+			if err != nil {
+				klog.Fatalf("Error serving crd conversion: %v", err)
+			}
+		}()
+	}
+
 	return nil
 }
@@ -0,0 +1,33 @@
+package setup
+
+import (
+	"github.com/loft-sh/vcluster/pkg/config"
+	"github.com/loft-sh/vcluster/pkg/controllers"
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+)
+
+// StartSatellite starts the syncers of a satellite. In contrast to StartControllers, a satellite
+// only syncs the pods selected for its host cluster together with the resources they depend on,
+// while everything else is handled by the primary syncer next to the virtual control plane.
+func StartSatellite(controllerContext *config.ControllerContext) error {
+	klog.Infof("Starting satellite %s", controllerContext.Config.Experimental.MultiCluster.Satellite.Name)
+	syncers, err := controllers.CreateSatellite(controllerContext)
+	if err != nil {
+		return errors.Wrap(err, "instantiate controllers")
+	}
+
+	// start managers
+	err = StartManagers(controllerContext, syncers)
+	if err != nil {
+		return err
+	}
+
+	// register controllers
+	err = controllers.RegisterSyncers(controllerContext, syncers)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -1,6 +1,7 @@
 package types
 
 import (
+	"github.com/loft-sh/vcluster/pkg/config"
 	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
 	"github.com/loft-sh/vcluster/pkg/controllers/syncer/translator"
 	"k8s.io/apimachinery/pkg/types"
@@ -66,6 +67,13 @@ type ControllerStarter interface {
 	Register(ctx *synccontext.RegisterContext) error
 }
 
+// ConfigReloader is a syncer that applies reloadable config paths at runtime
+type ConfigReloader interface {
+	// ReloadConfig is called with the new config after reloadable paths changed. All objects of the syncer are synced
+	// again afterwards, so the changes also apply to existing objects.
+	ReloadConfig(newConfig *config.VirtualClusterConfig)
+}
+
 // Initializer is used to create and update the prerequisites of the syncer before the controller is started
 type Initializer interface {
 	Init(registerContext *synccontext.RegisterContext) error
@@ -78,6 +86,14 @@ type Options struct {
 
 	IsClusterScopedCRD   bool
 	HasStatusSubresource bool
+
+	// DisableOrderedDeletion disables the finalizers that order the deletion of virtual and physical objects, for
+	// syncers that already sync finalizers or deletion between both objects.
+	DisableOrderedDeletion bool
+
+	// ObjectsFromHost marks syncers that import physical objects into the virtual cluster, in which case the physical
+	// object is only removed after the virtual object is deleted.
+	ObjectsFromHost bool
 }
 
 type OptionsProvider interface {
@@ -0,0 +1,304 @@
+package crdconversion
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/klog/v2"
+)
+
+// PathPrefix is the path the virtual api server sends conversion reviews to
+const PathPrefix = "/convert/"
+
+// Port is the port of the dedicated loopback listener the virtual api server sends conversion reviews to. The
+// virtual api server calls webhooks without credentials, so conversion reviews are not served by the proxy, which
+// users reach through port-forwarding as well.
+const Port = 8445
+
+// Default is the router used for CRDs synced from the host cluster. It is nil if conversion routing is not available.
+var Default *Router
+
+// Router routes conversion reviews of the virtual api server to the conversion webhooks of the host cluster.
+// As the virtual api server cannot reach services in the host cluster, the virtual CRDs point to the syncer,
+// which forwards the objects to the host webhook and caches the converted objects by uid and resource version.
+type Router struct {
+	serverURL string
+	caBundle  []byte
+
+	webhooksLock sync.RWMutex
+	webhooks     map[string]*webhook
+
+	cache *lru.Cache[string, runtime.RawExtension]
+}
+
+// NewRouter creates a new router, serverURL is the url the virtual api server reaches the syncer at and caBundle
+// the ca that signed the serving certificate of the syncer.
+func NewRouter(serverURL string, caBundle []byte) (*Router, error) {
+	cache, err := lru.New[string, runtime.RawExtension](1024)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Router{
+		serverURL: strings.TrimSuffix(serverURL, "/"),
+		caBundle:  caBundle,
+		webhooks:  map[string]*webhook{},
+		cache:     cache,
+	}, nil
+}
+
+// VirtualConversion registers the webhook of the host CRD and returns the conversion the virtual CRD should use.
+// If the host CRD doesn't use a conversion webhook, nil is returned.
+func (r *Router) VirtualConversion(crdName string, hostConversion *apiextensionsv1.CustomResourceConversion) (*apiextensionsv1.CustomResourceConversion, error) {
+	if hostConversion == nil || hostConversion.Strategy != apiextensionsv1.WebhookConverter || hostConversion.Webhook == nil || hostConversion.Webhook.ClientConfig == nil {
+		return nil, nil
+	}
+
+	httpClient, err := webhookClient(hostConversion.Webhook.ClientConfig.CABundle)
+	if err != nil {
+		return nil, fmt.Errorf("create client for conversion webhook of %s: %w", crdName, err)
+	}
+
+	r.webhooksLock.Lock()
+	r.webhooks[crdName] = &webhook{
+		clientConfig: hostConversion.Webhook.ClientConfig.DeepCopy(),
+		httpClient:   httpClient,
+	}
+	r.webhooksLock.Unlock()
+
+	url := r.serverURL + PathPrefix + crdName
+	return &apiextensionsv1.CustomResourceConversion{
+		Strategy: apiextensionsv1.WebhookConverter,
+		Webhook: &apiextensionsv1.WebhookConversion{
+			ClientConfig: &apiextensionsv1.WebhookClientConfig{
+				URL:      &url,
+				CABundle: r.caBundle,
+			},
+			ConversionReviewVersions: []string{"v1"},
+		},
+	}, nil
+}
+
+// IsRouted returns true if the given conversion of a virtual CRD points to a router. Earlier versions served the
+// conversion reviews on the port of the proxy, so any loopback url is recognized.
+func (r *Router) IsRouted(conversion *apiextensionsv1.CustomResourceConversion) bool {
+	if conversion == nil || conversion.Webhook == nil || conversion.Webhook.ClientConfig == nil || conversion.Webhook.ClientConfig.URL == nil {
+		return false
+	}
+
+	routedURL, err := url.Parse(*conversion.Webhook.ClientConfig.URL)
+	if err != nil {
+		return false
+	}
+	ip := net.ParseIP(routedURL.Hostname())
+	return routedURL.Scheme == "https" && ip != nil && ip.IsLoopback() && strings.HasPrefix(routedURL.Path, PathPrefix)
+}
+
+// IsCurrent returns true if the given conversion of a virtual CRD points to this router
+func (r *Router) IsCurrent(conversion *apiextensionsv1.CustomResourceConversion) bool {
+	if conversion == nil || conversion.Webhook == nil || conversion.Webhook.ClientConfig == nil || conversion.Webhook.ClientConfig.URL == nil {
+		return false
+	}
+
+	return strings.HasPrefix(*conversion.Webhook.ClientConfig.URL, r.serverURL+PathPrefix) && bytes.Equal(conversion.Webhook.ClientConfig.CABundle, r.caBundle)
+}
+
+func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost || !strings.HasPrefix(req.URL.Path, PathPrefix) {
+		http.NotFound(w, req)
+		return
+	}
+
+	crdName := strings.TrimPrefix(req.URL.Path, PathPrefix)
+	r.webhooksLock.RLock()
+	hostWebhook := r.webhooks[crdName]
+	r.webhooksLock.RUnlock()
+	if hostWebhook == nil {
+		http.Error(w, fmt.Sprintf("no conversion webhook registered for %s", crdName), http.StatusNotFound)
+		return
+	}
+
+	review := &apiextensionsv1.ConversionReview{}
+	err := json.NewDecoder(req.Body).Decode(review)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("decode conversion review: %v", err), http.StatusBadRequest)
+		return
+	} else if review.Request == nil {
+		http.Error(w, "conversion review request is missing", http.StatusBadRequest)
+		return
+	}
+
+	review.Response = r.convert(req.Context(), hostWebhook, review.Request)
+	review.Request = nil
+	w.Header().Set("Content-Type", "application/json")
+	err = json.NewEncoder(w).Encode(review)
+	if err != nil {
+		klog.Errorf("Error writing conversion response for %s: %v", crdName, err)
+	}
+}
+
+func (r *Router) convert(ctx context.Context, hostWebhook *webhook, request *apiextensionsv1.ConversionRequest) *apiextensionsv1.ConversionResponse {
+	converted := make([]runtime.RawExtension, len(request.Objects))
+	keys := make([]string, len(request.Objects))
+	missing := []runtime.RawExtension{}
+	missingIndices := []int{}
+	for i, obj := range request.Objects {
+		keys[i] = cacheKey(obj.Raw, request.DesiredAPIVersion)
+		if keys[i] != "" {
+			if cached, ok := r.cache.Get(keys[i]); ok {
+				converted[i] = cached
+				continue
+			}
+		}
+
+		missing = append(missing, obj)
+		missingIndices = append(missingIndices, i)
+	}
+
+	if len(missing) > 0 {
+		response, err := hostWebhook.call(ctx, &apiextensionsv1.ConversionRequest{
+			UID:               request.UID,
+			DesiredAPIVersion: request.DesiredAPIVersion,
+			Objects:           missing,
+		})
+		if err != nil {
+			return failedResponse(request, fmt.Errorf("call host conversion webhook: %w", err))
+		} else if response.Result.Status != metav1.StatusSuccess {
+			response.UID = request.UID
+			return response
+		} else if len(response.ConvertedObjects) != len(missing) {
+			return failedResponse(request, fmt.Errorf("host conversion webhook returned %d objects, expected %d", len(response.ConvertedObjects), len(missing)))
+		}
+
+		for j, i := range missingIndices {
+			converted[i] = response.ConvertedObjects[j]
+			if keys[i] != "" {
+				r.cache.Add(keys[i], converted[i])
+			}
+		}
+	}
+
+	return &apiextensionsv1.ConversionResponse{
+		UID:              request.UID,
+		ConvertedObjects: converted,
+		Result:           metav1.Status{Status: metav1.StatusSuccess},
+	}
+}
+
+// cacheKey returns the key of the converted object, objects without uid or resource version are not cached
+func cacheKey(raw []byte, desiredAPIVersion string) string {
+	obj := &metav1.PartialObjectMetadata{}
+	err := json.Unmarshal(raw, obj)
+	if err != nil || obj.UID == "" || obj.ResourceVersion == "" {
+		return ""
+	}
+
+	return strings.Join([]string{string(obj.UID), obj.ResourceVersion, obj.APIVersion, desiredAPIVersion}, "/")
+}
+
+type webhook struct {
+	clientConfig *apiextensionsv1.WebhookClientConfig
+	httpClient   *http.Client
+}
+
+func (w *webhook) call(ctx context.Context, request *apiextensionsv1.ConversionRequest) (*apiextensionsv1.ConversionResponse, error) {
+	url, err := webhookURL(w.clientConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := json.Marshal(&apiextensionsv1.ConversionReview{
+		TypeMeta: metav1.TypeMeta{APIVersion: apiextensionsv1.SchemeGroupVersion.String(), Kind: "ConversionReview"},
+		Request:  request,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := w.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		out, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(out))
+	}
+
+	review := &apiextensionsv1.ConversionReview{}
+	err = json.NewDecoder(resp.Body).Decode(review)
+	if err != nil {
+		return nil, fmt.Errorf("decode conversion review: %w", err)
+	} else if review.Response == nil {
+		return nil, fmt.Errorf("conversion review response is missing")
+	}
+
+	return review.Response, nil
+}
+
+func webhookURL(clientConfig *apiextensionsv1.WebhookClientConfig) (string, error) {
+	if clientConfig.URL != nil {
+		return *clientConfig.URL, nil
+	} else if clientConfig.Service == nil {
+		return "", fmt.Errorf("webhook has neither url nor service")
+	}
+
+	port := int32(443)
+	if clientConfig.Service.Port != nil {
+		port = *clientConfig.Service.Port
+	}
+	path := ""
+	if clientConfig.Service.Path != nil {
+		path = *clientConfig.Service.Path
+	}
+
+	return fmt.Sprintf("https://%s.%s.svc:%d%s", clientConfig.Service.Name, clientConfig.Service.Namespace, port, path), nil
+}
+
+func webhookClient(caBundle []byte) (*http.Client, error) {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if len(caBundle) > 0 {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caBundle) {
+			return nil, fmt.Errorf("invalid ca bundle")
+		}
+
+		tlsConfig.RootCAs = pool
+	}
+
+	return &http.Client{
+		Timeout:   10 * time.Second,
+		Transport: &http.Transport{TLSClientConfig: tlsConfig},
+	}, nil
+}
+
+func failedResponse(request *apiextensionsv1.ConversionRequest, err error) *apiextensionsv1.ConversionResponse {
+	return &apiextensionsv1.ConversionResponse{
+		UID: request.UID,
+		Result: metav1.Status{
+			Status:  metav1.StatusFailure,
+			Message: err.Error(),
+		},
+	}
+}
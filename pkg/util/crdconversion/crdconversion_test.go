@@ -0,0 +1,119 @@
+package crdconversion
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestRouter(t *testing.T) {
+	calls := 0
+	hostWebhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		calls++
+		review := &apiextensionsv1.ConversionReview{}
+		assert.NilError(t, json.NewDecoder(req.Body).Decode(review))
+
+		converted := []runtime.RawExtension{}
+		for _, obj := range review.Request.Objects {
+			partial := &metav1.PartialObjectMetadata{}
+			assert.NilError(t, json.Unmarshal(obj.Raw, partial))
+			partial.APIVersion = review.Request.DesiredAPIVersion
+			raw, err := json.Marshal(partial)
+			assert.NilError(t, err)
+			converted = append(converted, runtime.RawExtension{Raw: raw})
+		}
+
+		review.Response = &apiextensionsv1.ConversionResponse{
+			UID:              review.Request.UID,
+			ConvertedObjects: converted,
+			Result:           metav1.Status{Status: metav1.StatusSuccess},
+		}
+		assert.NilError(t, json.NewEncoder(w).Encode(review))
+	}))
+	defer hostWebhook.Close()
+
+	router, err := NewRouter("https://127.0.0.1:8445", []byte("ca"))
+	assert.NilError(t, err)
+
+	// crds without conversion webhook are not routed
+	conversion, err := router.VirtualConversion("tests.example.com", &apiextensionsv1.CustomResourceConversion{Strategy: apiextensionsv1.NoneConverter})
+	assert.NilError(t, err)
+	assert.Assert(t, conversion == nil)
+
+	conversion, err = router.VirtualConversion("tests.example.com", &apiextensionsv1.CustomResourceConversion{
+		Strategy: apiextensionsv1.WebhookConverter,
+		Webhook: &apiextensionsv1.WebhookConversion{
+			ClientConfig: &apiextensionsv1.WebhookClientConfig{URL: &hostWebhook.URL},
+		},
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, *conversion.Webhook.ClientConfig.URL, "https://127.0.0.1:8445/convert/tests.example.com")
+	assert.Assert(t, router.IsRouted(conversion))
+	assert.Assert(t, router.IsCurrent(conversion))
+
+	// crds that still point to the proxy port of earlier versions are routed, but have to be updated
+	legacyURL := "https://127.0.0.1:8443/convert/tests.example.com"
+	legacy := &apiextensionsv1.CustomResourceConversion{
+		Strategy: apiextensionsv1.WebhookConverter,
+		Webhook: &apiextensionsv1.WebhookConversion{
+			ClientConfig: &apiextensionsv1.WebhookClientConfig{URL: &legacyURL, CABundle: []byte("ca")},
+		},
+	}
+	assert.Assert(t, router.IsRouted(legacy))
+	assert.Assert(t, !router.IsCurrent(legacy))
+	assert.Assert(t, !router.IsRouted(&apiextensionsv1.CustomResourceConversion{
+		Strategy: apiextensionsv1.WebhookConverter,
+		Webhook: &apiextensionsv1.WebhookConversion{
+			ClientConfig: &apiextensionsv1.WebhookClientConfig{URL: &hostWebhook.URL},
+		},
+	}))
+
+	convert := func() *apiextensionsv1.ConversionResponse {
+		body, err := json.Marshal(&apiextensionsv1.ConversionReview{
+			Request: &apiextensionsv1.ConversionRequest{
+				UID:               "review",
+				DesiredAPIVersion: "example.com/v2",
+				Objects: []runtime.RawExtension{
+					{Raw: []byte(`{"apiVersion":"example.com/v1","kind":"Test","metadata":{"name":"test","uid":"123","resourceVersion":"1"}}`)},
+				},
+			},
+		})
+		assert.NilError(t, err)
+
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, PathPrefix+"tests.example.com", bytes.NewReader(body)))
+		assert.Equal(t, recorder.Code, http.StatusOK)
+
+		review := &apiextensionsv1.ConversionReview{}
+		assert.NilError(t, json.NewDecoder(recorder.Body).Decode(review))
+		return review.Response
+	}
+
+	response := convert()
+	assert.Equal(t, response.Result.Status, metav1.StatusSuccess)
+	assert.Equal(t, len(response.ConvertedObjects), 1)
+	assert.Assert(t, bytes.Contains(response.ConvertedObjects[0].Raw, []byte(`"apiVersion":"example.com/v2"`)))
+
+	// the second conversion is served from the cache
+	response = convert()
+	assert.Equal(t, response.Result.Status, metav1.StatusSuccess)
+	assert.Equal(t, calls, 1)
+
+	// only conversion reviews are served
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, PathPrefix+"tests.example.com", nil))
+	assert.Equal(t, recorder.Code, http.StatusNotFound)
+	assert.Equal(t, calls, 1)
+
+	// unknown crds are rejected
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, PathPrefix+"unknown.example.com", bytes.NewReader([]byte("{}"))))
+	assert.Equal(t, recorder.Code, http.StatusNotFound)
+}
@@ -0,0 +1,33 @@
+package podhelper
+
+import corev1 "k8s.io/api/core/v1"
+
+// IsNodeDebugPod returns true if the pod targets a specific node and uses host namespaces or hostPath volumes, which
+// is what kubectl debug node/... creates
+func IsNodeDebugPod(pod *corev1.Pod) bool {
+	if pod.Spec.NodeName == "" {
+		return false
+	}
+	if pod.Spec.HostPID || pod.Spec.HostIPC || pod.Spec.HostNetwork {
+		return true
+	}
+	for _, volume := range pod.Spec.Volumes {
+		if volume.HostPath != nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsBoundByScheduler returns true if the node name of the pod was set through the binding subresource, which marks the
+// pod as scheduled, instead of being part of the pod spec the pod was created with
+func IsBoundByScheduler(pod *corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodScheduled && condition.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,72 @@
+package resources
+
+import (
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// IsExtendedResourceName returns true if the resource is provided by a device plugin or registered by a cluster
+// operator, e.g. nvidia.com/gpu, instead of being a native kubernetes resource
+func IsExtendedResourceName(name corev1.ResourceName) bool {
+	if !strings.Contains(string(name), "/") || strings.Contains(string(name), corev1.ResourceDefaultNamespacePrefix) {
+		return false
+	}
+
+	// quota resources are prefixed with requests.
+	return !strings.HasPrefix(string(name), corev1.DefaultResourceRequestsPrefix)
+}
+
+// ExtendedResources returns the extended resources of the list or nil if there are none
+func ExtendedResources(list corev1.ResourceList) corev1.ResourceList {
+	var extended corev1.ResourceList
+	for name, quantity := range list {
+		if !IsExtendedResourceName(name) {
+			continue
+		}
+		if extended == nil {
+			extended = corev1.ResourceList{}
+		}
+
+		extended[name] = quantity
+	}
+
+	return extended
+}
+
+// PodExtendedResourceRequests returns the extended resources the pod requests. Init containers run one after another,
+// so the pod requests the maximum of the init containers or the sum of the containers.
+func PodExtendedResourceRequests(pod *corev1.Pod) corev1.ResourceList {
+	requests := corev1.ResourceList{}
+	for _, container := range pod.Spec.Containers {
+		for name, quantity := range containerExtendedResourceRequests(container) {
+			sum := requests[name]
+			sum.Add(quantity)
+			requests[name] = sum
+		}
+	}
+	for _, container := range pod.Spec.InitContainers {
+		for name, quantity := range containerExtendedResourceRequests(container) {
+			if current, ok := requests[name]; !ok || quantity.Cmp(current) > 0 {
+				requests[name] = quantity
+			}
+		}
+	}
+
+	return requests
+}
+
+// containerExtendedResourceRequests falls back to the limits, as extended resources can't be overcommitted and
+// requests default to the limits
+func containerExtendedResourceRequests(container corev1.Container) corev1.ResourceList {
+	requests := ExtendedResources(container.Resources.Limits)
+	for name, quantity := range ExtendedResources(container.Resources.Requests) {
+		if requests == nil {
+			requests = corev1.ResourceList{}
+		}
+
+		requests[name] = quantity
+	}
+
+	return requests
+}
@@ -0,0 +1,53 @@
+package resources
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestIsExtendedResourceName(t *testing.T) {
+	assert.Assert(t, IsExtendedResourceName("nvidia.com/gpu"))
+	assert.Assert(t, IsExtendedResourceName("example.com/dongle"))
+	assert.Assert(t, !IsExtendedResourceName(corev1.ResourceCPU))
+	assert.Assert(t, !IsExtendedResourceName("hugepages-2Mi"))
+	assert.Assert(t, !IsExtendedResourceName("kubernetes.io/batch-cpu"))
+	assert.Assert(t, !IsExtendedResourceName("requests.nvidia.com/gpu"))
+}
+
+func TestPodExtendedResourceRequests(t *testing.T) {
+	pod := &corev1.Pod{
+		Spec: corev1.PodSpec{
+			InitContainers: []corev1.Container{{
+				Name: "init",
+				Resources: corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{"nvidia.com/gpu": resource.MustParse("3"), "example.com/dongle": resource.MustParse("1")},
+				},
+			}},
+			Containers: []corev1.Container{
+				{
+					Name: "a",
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1"), "nvidia.com/gpu": resource.MustParse("1")},
+						Limits:   corev1.ResourceList{"nvidia.com/gpu": resource.MustParse("1")},
+					},
+				},
+				{
+					Name: "b",
+					Resources: corev1.ResourceRequirements{
+						Limits: corev1.ResourceList{"nvidia.com/gpu": resource.MustParse("1")},
+					},
+				},
+			},
+		},
+	}
+
+	requests := PodExtendedResourceRequests(pod)
+	assert.Equal(t, len(requests), 2)
+	gpus := requests["nvidia.com/gpu"]
+	assert.Equal(t, gpus.String(), "3")
+	dongles := requests["example.com/dongle"]
+	assert.Equal(t, dongles.String(), "1")
+}
@@ -2,6 +2,7 @@ package servicecidr
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net"
 	"strings"
@@ -9,24 +10,157 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
 )
 
 const (
 	ErrorMessageFind = "The range of valid IPs is "
 	FallbackCIDR     = "10.96.0.0/12"
+
+	// KubernetesServiceCIDRName is the name of the ServiceCIDR object that holds the service cidrs kube-apiserver was started with
+	KubernetesServiceCIDRName = "kubernetes"
+
+	// the largest service cidrs kube-apiserver accepts, used when deriving the cidr from the kubernetes service
+	maxIPv4MaskSize = 12
+	maxIPv6MaskSize = 108
 )
 
+var serviceCIDRAPIVersions = []string{"networking.k8s.io/v1", "networking.k8s.io/v1beta1"}
+
+// GetServiceCIDR returns the service cidr of the host cluster and falls back to FallbackCIDR if it couldn't be detected.
+// The second return value is a warning that should be shown to the user.
 func GetServiceCIDR(ctx context.Context, client kubernetes.Interface, namespace string) (string, string) {
+	cidr, warning, err := DetectServiceCIDR(ctx, client, namespace)
+	if err != nil {
+		return FallbackCIDR, fmt.Sprintf("failed to detect service CIDR, will fallback to %s, however this is probably wrong, please make sure the host cluster service cidr and virtual cluster service cidr match or set networking.advanced.serviceCIDR. Error details: %v", FallbackCIDR, err)
+	}
+
+	return cidr, warning
+}
+
+// DetectServiceCIDR detects the service cidr of the host cluster. It reads the ServiceCIDR api first, then creates test
+// services with invalid cluster ips and if that fails as well, e.g. because quotas or admission policies within the namespace
+// reject the test services, it derives the cidr from the cluster ip of the default kubernetes service.
+func DetectServiceCIDR(ctx context.Context, client kubernetes.Interface, namespace string) (string, string, error) {
+	cidr, err := getServiceCIDRFromAPI(ctx, client)
+	if err == nil {
+		return cidr, "", nil
+	}
+
+	cidr, warning, testServiceErr := getServiceCIDRFromTestServices(ctx, client, namespace)
+	if testServiceErr == nil {
+		return cidr, warning, nil
+	}
+
+	cidr, err = getServiceCIDRFromKubernetesService(ctx, client)
+	if err != nil {
+		return "", "", fmt.Errorf("%w ; failed to derive service CIDR from the kubernetes service: %w", testServiceErr, err)
+	}
+
+	return cidr, fmt.Sprintf("failed to detect service CIDR through test services, derived %s from the default kubernetes service instead, please set networking.advanced.serviceCIDR if this is wrong. Error details: %v", cidr, testServiceErr), nil
+}
+
+// ParseServiceCIDR parses a comma separated list of cidrs with at most one cidr per ip family
+func ParseServiceCIDR(serviceCIDR string) ([]*net.IPNet, error) {
+	cidrs := []*net.IPNet{}
+	for _, cidr := range strings.Split(serviceCIDR, ",") {
+		_, ipNet, err := net.ParseCIDR(strings.TrimSpace(cidr))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, other := range cidrs {
+			if isIPv4(other.IP) == isIPv4(ipNet.IP) {
+				return nil, fmt.Errorf("only a single cidr per ip family is allowed, but got %s and %s", other.String(), ipNet.String())
+			}
+		}
+
+		cidrs = append(cidrs, ipNet)
+	}
+
+	return cidrs, nil
+}
+
+// ValidateServiceCIDR makes sure the service cidr of the virtual cluster includes the host service cidr of the same ip
+// family. Services are synced with the cluster ips the host cluster assigned, so the virtual cluster needs to accept them.
+func ValidateServiceCIDR(serviceCIDR, hostServiceCIDR string) error {
+	cidrs, err := ParseServiceCIDR(serviceCIDR)
+	if err != nil {
+		return fmt.Errorf("parse service cidr %s: %w", serviceCIDR, err)
+	}
+	hostCIDRs, err := ParseServiceCIDR(hostServiceCIDR)
+	if err != nil {
+		return fmt.Errorf("parse host service cidr %s: %w", hostServiceCIDR, err)
+	}
+
+	for _, cidr := range cidrs {
+		for _, hostCIDR := range hostCIDRs {
+			if isIPv4(cidr.IP) != isIPv4(hostCIDR.IP) {
+				continue
+			}
+
+			cidrOnes, _ := cidr.Mask.Size()
+			hostOnes, _ := hostCIDR.Mask.Size()
+			if !cidr.Contains(hostCIDR.IP) || cidrOnes > hostOnes {
+				return fmt.Errorf("service cidr %s doesn't include the host service cidr %s, the cluster ips of synced services would be rejected by the virtual cluster", cidr.String(), hostCIDR.String())
+			}
+		}
+	}
+
+	return nil
+}
+
+func getServiceCIDRFromAPI(ctx context.Context, client kubernetes.Interface) (string, error) {
+	// fake clients don't have a rest client
+	restClient, ok := client.NetworkingV1().RESTClient().(*rest.RESTClient)
+	if !ok || restClient == nil {
+		return "", fmt.Errorf("rest client unavailable")
+	}
+
+	var lastErr error
+	for _, apiVersion := range serviceCIDRAPIVersions {
+		raw, err := restClient.Get().AbsPath("/apis", apiVersion, "servicecidrs", KubernetesServiceCIDRName).DoRaw(ctx)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		serviceCIDR := &struct {
+			Spec struct {
+				CIDRs []string `json:"cidrs,omitempty"`
+			} `json:"spec,omitempty"`
+		}{}
+		err = json.Unmarshal(raw, serviceCIDR)
+		if err != nil {
+			return "", err
+		} else if len(serviceCIDR.Spec.CIDRs) == 0 {
+			return "", fmt.Errorf("service cidr %s has no cidrs", KubernetesServiceCIDRName)
+		}
+
+		// the primary cidr is always the first one
+		cidr := strings.Join(serviceCIDR.Spec.CIDRs, ",")
+		_, err = ParseServiceCIDR(cidr)
+		if err != nil {
+			return "", err
+		}
+
+		return cidr, nil
+	}
+
+	return "", lastErr
+}
+
+func getServiceCIDRFromTestServices(ctx context.Context, client kubernetes.Interface, namespace string) (string, string, error) {
 	ipv4CIDR, ipv4Err := getServiceCIDR(ctx, client, namespace, false)
 	ipv6CIDR, ipv6Err := getServiceCIDR(ctx, client, namespace, true)
 	if ipv4Err != nil && ipv6Err != nil {
-		return FallbackCIDR, fmt.Sprintf("failed to detect service CIDR, will fallback to %s, however this is probably wrong, please make sure the host cluster service cidr and virtual cluster service cidr match. Error details: failed to find IPv4 service CIDR: %v ; or IPv6 service CIDR: %v", FallbackCIDR, ipv4Err, ipv6Err)
+		return "", "", fmt.Errorf("failed to find IPv4 service CIDR: %w ; or IPv6 service CIDR: %w", ipv4Err, ipv6Err)
 	}
 	if ipv4Err != nil {
-		return ipv6CIDR, fmt.Sprintf("failed to find IPv4 service CIDR, will use IPv6 service CIDR. Error details: %v", ipv4Err)
+		return ipv6CIDR, fmt.Sprintf("failed to find IPv4 service CIDR, will use IPv6 service CIDR. Error details: %v", ipv4Err), nil
 	}
 	if ipv6Err != nil {
-		return ipv4CIDR, fmt.Sprintf("failed to find IPv6 service CIDR, will use IPv4 service CIDR. Error details: %v", ipv6Err)
+		return ipv4CIDR, fmt.Sprintf("failed to find IPv6 service CIDR, will use IPv4 service CIDR. Error details: %v", ipv6Err), nil
 	}
 
 	// Both IPv4 and IPv6 are configured, we need to find out which one is the default
@@ -54,17 +188,53 @@ func GetServiceCIDR(ctx context.Context, client kubernetes.Interface, namespace
 		if len(testService.Spec.IPFamilies) > 0 {
 			if testService.Spec.IPFamilies[0] == corev1.IPv4Protocol {
 				// IPv4 is the default
-				return fmt.Sprintf("%s,%s", ipv4CIDR, ipv6CIDR), ""
+				return fmt.Sprintf("%s,%s", ipv4CIDR, ipv6CIDR), "", nil
 			}
 
 			// IPv6 is the default
-			return fmt.Sprintf("%s,%s", ipv6CIDR, ipv4CIDR), ""
+			return fmt.Sprintf("%s,%s", ipv6CIDR, ipv4CIDR), "", nil
 		}
 
-		return ipv4CIDR, fmt.Sprintf("unexpected number of entries in .Spec.IPFamilies - %d, defaulting to IPv4 CIDR only", len(testService.Spec.IPFamilies))
+		return ipv4CIDR, fmt.Sprintf("unexpected number of entries in .Spec.IPFamilies - %d, defaulting to IPv4 CIDR only", len(testService.Spec.IPFamilies)), nil
 	}
 
-	return fmt.Sprintf("%s,%s", ipv4CIDR, ipv6CIDR), "failed to find host cluster default Service IP family, defaulting to IPv4 family"
+	return fmt.Sprintf("%s,%s", ipv4CIDR, ipv6CIDR), "failed to find host cluster default Service IP family, defaulting to IPv4 family", nil
+}
+
+// getServiceCIDRFromKubernetesService derives the service cidr from the default kubernetes service, which always gets the
+// first ip of the service cidr. As the mask is unknown, the largest cidr kube-apiserver accepts is used, which includes any
+// smaller host service cidr.
+func getServiceCIDRFromKubernetesService(ctx context.Context, client kubernetes.Interface) (string, error) {
+	kubernetesService, err := client.CoreV1().Services("default").Get(ctx, "kubernetes", metav1.GetOptions{})
+	if err != nil {
+		return "", err
+	}
+
+	clusterIPs := kubernetesService.Spec.ClusterIPs
+	if len(clusterIPs) == 0 && kubernetesService.Spec.ClusterIP != "" {
+		clusterIPs = []string{kubernetesService.Spec.ClusterIP}
+	}
+
+	cidrs := []string{}
+	for _, clusterIP := range clusterIPs {
+		ip := net.ParseIP(clusterIP)
+		if ip == nil {
+			return "", fmt.Errorf("invalid cluster ip %s of the kubernetes service", clusterIP)
+		}
+
+		mask := net.CIDRMask(maxIPv6MaskSize, net.IPv6len*8)
+		if isIPv4(ip) {
+			mask = net.CIDRMask(maxIPv4MaskSize, net.IPv4len*8)
+			ip = ip.To4()
+		}
+
+		cidrs = append(cidrs, (&net.IPNet{IP: ip.Mask(mask), Mask: mask}).String())
+	}
+	if len(cidrs) == 0 {
+		return "", fmt.Errorf("kubernetes service has no cluster ip")
+	}
+
+	return strings.Join(cidrs, ","), nil
 }
 
 func getServiceCIDR(ctx context.Context, client kubernetes.Interface, namespace string, ipv6 bool) (string, error) {
@@ -111,3 +281,7 @@ func getServiceCIDR(ctx context.Context, client kubernetes.Interface, namespace
 	}
 	return cidr, nil
 }
+
+func isIPv4(ip net.IP) bool {
+	return ip.To4() != nil
+}
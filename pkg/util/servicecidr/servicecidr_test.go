@@ -0,0 +1,108 @@
+package servicecidr
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+func TestDetectServiceCIDR(t *testing.T) {
+	kubernetesService := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "kubernetes", Namespace: "default"},
+		Spec:       corev1.ServiceSpec{ClusterIP: "10.43.0.1", ClusterIPs: []string{"10.43.0.1"}},
+	}
+
+	testCases := []struct {
+		name string
+
+		objects          []runtime.Object
+		createServiceErr func(service *corev1.Service) error
+		expectedCIDR     string
+		expectedWarning  bool
+		expectedErr      bool
+	}{
+		{
+			name: "test services",
+			createServiceErr: func(service *corev1.Service) error {
+				if service.Spec.ClusterIP == "4.4.4.4" {
+					return fmt.Errorf("Service \"test\" is invalid: spec.clusterIPs: Invalid value: []string{\"4.4.4.4\"}: failed to allocate IP 4.4.4.4: provided IP is not in the valid range. %s10.43.0.0/16", ErrorMessageFind)
+				}
+
+				return fmt.Errorf("IPv6 is not configured on this cluster")
+			},
+			expectedCIDR:    "10.43.0.0/16",
+			expectedWarning: true,
+		},
+		{
+			name:    "restricted namespace",
+			objects: []runtime.Object{kubernetesService},
+			createServiceErr: func(*corev1.Service) error {
+				return kerrors.NewForbidden(schema.GroupResource{Resource: "services"}, "test", fmt.Errorf("exceeded quota"))
+			},
+			expectedCIDR:    "10.32.0.0/12",
+			expectedWarning: true,
+		},
+		{
+			name: "nothing works",
+			createServiceErr: func(*corev1.Service) error {
+				return kerrors.NewForbidden(schema.GroupResource{Resource: "services"}, "test", fmt.Errorf("exceeded quota"))
+			},
+			expectedErr: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			client := fake.NewSimpleClientset(testCase.objects...)
+			client.PrependReactor("create", "services", func(action clienttesting.Action) (bool, runtime.Object, error) {
+				return true, nil, testCase.createServiceErr(action.(clienttesting.CreateAction).GetObject().(*corev1.Service))
+			})
+
+			cidr, warning, err := DetectServiceCIDR(context.Background(), client, "test")
+			if testCase.expectedErr {
+				assert.Assert(t, err != nil)
+				return
+			}
+
+			assert.NilError(t, err)
+			assert.Equal(t, cidr, testCase.expectedCIDR)
+			assert.Equal(t, warning != "", testCase.expectedWarning)
+		})
+	}
+
+	// fallback is only used if nothing else works
+	cidr, warning := GetServiceCIDR(context.Background(), fake.NewSimpleClientset(), "test")
+	assert.Equal(t, cidr, FallbackCIDR)
+	assert.Assert(t, warning != "")
+}
+
+func TestValidateServiceCIDR(t *testing.T) {
+	testCases := []struct {
+		serviceCIDR     string
+		hostServiceCIDR string
+		expectedErr     bool
+	}{
+		{serviceCIDR: "10.96.0.0/12", hostServiceCIDR: "10.96.0.0/12"},
+		{serviceCIDR: "10.96.0.0/12", hostServiceCIDR: "10.96.0.0/16"},
+		{serviceCIDR: "10.96.0.0/16", hostServiceCIDR: "10.96.0.0/12", expectedErr: true},
+		{serviceCIDR: "10.100.0.0/16", hostServiceCIDR: "10.96.0.0/16", expectedErr: true},
+		{serviceCIDR: "10.96.0.0/12,fd00::/108", hostServiceCIDR: "fd00::/112,10.96.0.0/12"},
+		{serviceCIDR: "10.96.0.0/12,fd01::/108", hostServiceCIDR: "10.96.0.0/12,fd00::/108", expectedErr: true},
+		{serviceCIDR: "10.96.0.0/12,10.100.0.0/16", hostServiceCIDR: "10.96.0.0/12", expectedErr: true},
+		{serviceCIDR: "10.96.0.0", hostServiceCIDR: "10.96.0.0/12", expectedErr: true},
+	}
+
+	for _, testCase := range testCases {
+		err := ValidateServiceCIDR(testCase.serviceCIDR, testCase.hostServiceCIDR)
+		assert.Equal(t, err != nil, testCase.expectedErr, "%s in %s: %v", testCase.hostServiceCIDR, testCase.serviceCIDR, err)
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/loft-sh/vcluster/pkg/log"
+	"github.com/loft-sh/vcluster/pkg/util/crdconversion"
 	"github.com/pkg/errors"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	apiextensionsv1clientset "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
@@ -240,6 +241,14 @@ func EnsureCRDFromPhysicalCluster(ctx context.Context, pConfig *rest.Config, vCo
 			return isClusterScoped, hasStatusSubresource, nil
 		}
 
+		// make sure conversion reviews of the virtual cluster reach the host webhook again after a restart
+		if crdconversion.Default != nil && crdconversion.Default.IsRouted(crdDefinition.Spec.Conversion) {
+			err = registerHostConversion(ctx, pConfig, vClient, crdDefinition)
+			if err != nil {
+				return isClusterScoped, hasStatusSubresource, err
+			}
+		}
+
 		for _, version := range crdDefinition.Spec.Versions {
 			if version.Name == groupVersionKind.Version {
 				if version.Subresources != nil && version.Subresources.Status != nil {
@@ -281,20 +290,33 @@ func EnsureCRDFromPhysicalCluster(ctx context.Context, pConfig *rest.Config, vCo
 	crdDefinition.OwnerReferences = nil
 	crdDefinition.Status = apiextensionsv1.CustomResourceDefinitionStatus{}
 	crdDefinition.Spec.PreserveUnknownFields = false
-	crdDefinition.Spec.Conversion = nil
 
-	// make sure we only store the version we care about
+	// route conversion reviews to the webhook in the host cluster
+	var conversion *apiextensionsv1.CustomResourceConversion
+	if crdconversion.Default != nil {
+		conversion, err = crdconversion.Default.VirtualConversion(crdDefinition.Name, crdDefinition.Spec.Conversion)
+		if err != nil {
+			return isClusterScoped, hasStatusSubresource, err
+		}
+	}
+	crdDefinition.Spec.Conversion = conversion
+
+	// without a conversion webhook we only store the version we care about, otherwise we keep all served versions
+	// with the storage version of the host cluster, so objects are converted the same way in both clusters
 	newVersions := []apiextensionsv1.CustomResourceDefinitionVersion{}
 	for _, version := range crdDefinition.Spec.Versions {
 		if version.Name == groupVersionKind.Version {
 			version.Served = true
-			version.Storage = true
+			if conversion == nil {
+				version.Storage = true
+			}
 			newVersions = append(newVersions, version)
 
 			if version.Subresources != nil && version.Subresources.Status != nil {
 				hasStatusSubresource = true
 			}
-			break
+		} else if conversion != nil && version.Served {
+			newVersions = append(newVersions, version)
 		}
 	}
 	crdDefinition.Spec.Versions = newVersions
@@ -303,7 +325,13 @@ func EnsureCRDFromPhysicalCluster(ctx context.Context, pConfig *rest.Config, vCo
 
 	log.NewWithoutName().Infof("Create crd %s in virtual cluster", groupVersionKind.String())
 	_, err = vClient.ApiextensionsV1().CustomResourceDefinitions().Create(ctx, crdDefinition, metav1.CreateOptions{})
-	if err != nil {
+	if kerrors.IsAlreadyExists(err) {
+		// the crd exists with another version, so we add the missing one
+		err = addVirtualCRDVersion(ctx, vClient, crdDefinition, groupVersionKind.Version)
+		if err != nil {
+			return isClusterScoped, hasStatusSubresource, errors.Wrap(err, "update crd in virtual cluster")
+		}
+	} else if err != nil {
 		return isClusterScoped, hasStatusSubresource, errors.Wrap(err, "create crd in virtual cluster")
 	}
 
@@ -333,6 +361,63 @@ func EnsureCRDFromPhysicalCluster(ctx context.Context, pConfig *rest.Config, vCo
 	return isClusterScoped, hasStatusSubresource, nil
 }
 
+// addVirtualCRDVersion adds the given version of the host CRD to the existing virtual CRD. The storage version
+// of the virtual CRD stays the same, as the stored objects would otherwise be interpreted in the wrong version.
+func addVirtualCRDVersion(ctx context.Context, vClient apiextensionsv1clientset.Interface, hostCRD *apiextensionsv1.CustomResourceDefinition, version string) error {
+	virtualCRD, err := vClient.ApiextensionsV1().CustomResourceDefinitions().Get(ctx, hostCRD.Name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	for _, virtualVersion := range virtualCRD.Spec.Versions {
+		if virtualVersion.Name == version {
+			return nil
+		}
+	}
+	if hostCRD.Spec.Conversion == nil {
+		return fmt.Errorf("crd %s exists in the virtual cluster without version %s, but the host cluster has no conversion webhook to convert between the versions", hostCRD.Name, version)
+	}
+
+	for _, hostVersion := range hostCRD.Spec.Versions {
+		if hostVersion.Name == version {
+			hostVersion.Storage = false
+			virtualCRD.Spec.Versions = append(virtualCRD.Spec.Versions, hostVersion)
+			break
+		}
+	}
+	virtualCRD.Spec.Conversion = hostCRD.Spec.Conversion
+	_, err = vClient.ApiextensionsV1().CustomResourceDefinitions().Update(ctx, virtualCRD, metav1.UpdateOptions{})
+	return err
+}
+
+// registerHostConversion registers the conversion webhook of the host CRD at the conversion router and points the
+// virtual CRD to the router again if it still uses the url of an earlier version
+func registerHostConversion(ctx context.Context, pConfig *rest.Config, vClient apiextensionsv1clientset.Interface, virtualCRD *apiextensionsv1.CustomResourceDefinition) error {
+	pClient, err := apiextensionsv1clientset.NewForConfig(pConfig)
+	if err != nil {
+		return err
+	}
+
+	hostCRD, err := pClient.ApiextensionsV1().CustomResourceDefinitions().Get(ctx, virtualCRD.Name, metav1.GetOptions{})
+	if err != nil {
+		return errors.Wrap(err, "retrieve crd in host cluster")
+	}
+
+	conversion, err := crdconversion.Default.VirtualConversion(virtualCRD.Name, hostCRD.Spec.Conversion)
+	if err != nil || conversion == nil || crdconversion.Default.IsCurrent(virtualCRD.Spec.Conversion) {
+		return err
+	}
+
+	virtualCRD = virtualCRD.DeepCopy()
+	virtualCRD.Spec.Conversion = conversion
+	_, err = vClient.ApiextensionsV1().CustomResourceDefinitions().Update(ctx, virtualCRD, metav1.UpdateOptions{})
+	if err != nil {
+		return fmt.Errorf("update conversion of crd %s in virtual cluster: %w", virtualCRD.Name, err)
+	}
+
+	return nil
+}
+
 func ConvertKindToResource(config *rest.Config, groupVersionKind schema.GroupVersionKind) (schema.GroupVersionResource, error) {
 	discoveryClient, err := discovery.NewDiscoveryClientForConfig(config)
 	if err != nil {
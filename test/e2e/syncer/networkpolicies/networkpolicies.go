@@ -170,6 +170,100 @@ var _ = ginkgo.Describe("NetworkPolicies are created as expected", func() {
 		framework.DefaultFramework.TestServiceIsEventuallyUnreachable(curlPod, nginxService)
 	})
 
+	ginkgo.It("Test Ingress NetworkPolicy works as expected", func() {
+		// no NetworkPolicy yet - verify communication to test service
+		framework.DefaultFramework.TestServiceIsEventuallyReachable(curlPod, nginxService)
+
+		f.Log.Info("deny all Ingress to the Namespace that hosts nginx pod")
+		networkPolicy, err := f.VclusterClient.NetworkingV1().NetworkPolicies(nsB.GetName()).Create(f.Context, &networkingv1.NetworkPolicy{
+			ObjectMeta: metav1.ObjectMeta{Namespace: nsB.GetName(), Name: "my-ingress-policy"},
+			Spec: networkingv1.NetworkPolicySpec{
+				PodSelector: metav1.LabelSelector{},
+				PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+			},
+		}, metav1.CreateOptions{})
+		framework.ExpectNoError(err)
+		// sleep to reduce the rate of pod/exec calls made when checking if service is reacheable
+		time.Sleep(time.Second * 10)
+		framework.DefaultFramework.TestServiceIsEventuallyUnreachable(curlPod, nginxService)
+
+		f.Log.Info("allow Ingress from the curl pod Namespace")
+		err = updateNetworkPolicyWithRetryOnConflict(f, networkPolicy, func(np *networkingv1.NetworkPolicy) {
+			np.Spec.Ingress = []networkingv1.NetworkPolicyIngressRule{
+				{
+					From: []networkingv1.NetworkPolicyPeer{
+						{
+							NamespaceSelector: &metav1.LabelSelector{
+								MatchLabels: nsA.GetLabels(),
+							},
+						},
+					},
+				},
+			}
+		})
+		framework.ExpectNoError(err)
+		// sleep to reduce the rate of pod/exec calls made when checking if service is reacheable
+		time.Sleep(time.Second * 10)
+		framework.DefaultFramework.TestServiceIsEventuallyReachable(curlPod, nginxService)
+
+		f.Log.Info("deny Ingress by using a namespace selector expression that does not match the curl pod Namespace")
+		err = updateNetworkPolicyWithRetryOnConflict(f, networkPolicy, func(np *networkingv1.NetworkPolicy) {
+			np.Spec.Ingress = []networkingv1.NetworkPolicyIngressRule{
+				{
+					From: []networkingv1.NetworkPolicyPeer{
+						{
+							NamespaceSelector: &metav1.LabelSelector{
+								MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "key-a", Operator: metav1.LabelSelectorOpDoesNotExist}},
+							},
+						},
+					},
+				},
+			}
+		})
+		framework.ExpectNoError(err)
+		// sleep to reduce the rate of pod/exec calls made when checking if service is reacheable
+		time.Sleep(time.Second * 10)
+		framework.DefaultFramework.TestServiceIsEventuallyUnreachable(curlPod, nginxService)
+
+		f.Log.Info("allow Ingress by using a namespace selector expression that matches the curl pod Namespace")
+		err = updateNetworkPolicyWithRetryOnConflict(f, networkPolicy, func(np *networkingv1.NetworkPolicy) {
+			np.Spec.Ingress = []networkingv1.NetworkPolicyIngressRule{
+				{
+					From: []networkingv1.NetworkPolicyPeer{
+						{
+							NamespaceSelector: &metav1.LabelSelector{
+								MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "key-a", Operator: metav1.LabelSelectorOpExists}},
+							},
+						},
+					},
+				},
+			}
+		})
+		framework.ExpectNoError(err)
+		// sleep to reduce the rate of pod/exec calls made when checking if service is reacheable
+		time.Sleep(time.Second * 10)
+		framework.DefaultFramework.TestServiceIsEventuallyReachable(curlPod, nginxService)
+
+		f.Log.Info("deny Ingress on the nginx port (by allowing Ingress only on a different port)")
+		err = updateNetworkPolicyWithRetryOnConflict(f, networkPolicy, func(np *networkingv1.NetworkPolicy) {
+			np.Spec.Ingress = []networkingv1.NetworkPolicyIngressRule{
+				{
+					Ports: []networkingv1.NetworkPolicyPort{{Port: &intstr.IntOrString{Type: intstr.Int, IntVal: 1}}},
+					From: []networkingv1.NetworkPolicyPeer{
+						{
+							NamespaceSelector: &metav1.LabelSelector{
+								MatchLabels: nsA.GetLabels(),
+							},
+						},
+					},
+				},
+			}
+		})
+		framework.ExpectNoError(err)
+		// sleep to reduce the rate of pod/exec calls made when checking if service is reacheable
+		time.Sleep(time.Second * 10)
+		framework.DefaultFramework.TestServiceIsEventuallyUnreachable(curlPod, nginxService)
+	})
 })
 
 func updateNetworkPolicyWithRetryOnConflict(f *framework.Framework, networkPolicy *networkingv1.NetworkPolicy, mutator func(np *networkingv1.NetworkPolicy)) error {
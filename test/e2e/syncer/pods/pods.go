@@ -13,6 +13,7 @@ import (
 	"github.com/loft-sh/vcluster/pkg/util/translate"
 	"github.com/loft-sh/vcluster/test/framework"
 	"github.com/onsi/ginkgo/v2"
+	authenticationv1 "k8s.io/api/authentication/v1"
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -541,4 +542,66 @@ var _ = ginkgo.Describe("Pods are running in the host cluster", func() {
 			}
 		}
 	})
+
+	ginkgo.It("Test if projected service account tokens are issued by the virtual cluster", func() {
+		podName := "test-kubectl"
+
+		pod, err := f.VclusterClient.CoreV1().Pods(ns).Create(f.Context, &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      podName,
+				Namespace: ns,
+			},
+			Spec: corev1.PodSpec{
+				RestartPolicy: corev1.RestartPolicyNever,
+				Containers: []corev1.Container{
+					{
+						Name:            podName,
+						Image:           "bitnami/kubectl",
+						Command:         []string{"kubectl", "get", "pods", "-n", ns},
+						ImagePullPolicy: corev1.PullIfNotPresent,
+						SecurityContext: f.GetDefaultSecurityContext(),
+					},
+				},
+			},
+		}, metav1.CreateOptions{})
+		framework.ExpectNoError(err)
+
+		// the in-cluster client within the pod has to be able to talk to the virtual api server
+		err = wait.PollUntilContextTimeout(f.Context, time.Second*5, framework.PollTimeout, true, func(ctx context.Context) (bool, error) {
+			vPod, err := f.VclusterClient.CoreV1().Pods(ns).Get(ctx, podName, metav1.GetOptions{})
+			if err != nil {
+				return false, err
+			}
+
+			switch vPod.Status.Phase {
+			case corev1.PodSucceeded:
+				return true, nil
+			case corev1.PodFailed:
+				return false, fmt.Errorf("pod %s/%s failed to access the virtual api server", ns, podName)
+			}
+			return false, nil
+		})
+		framework.ExpectNoError(err)
+
+		// get current physical Pod resource
+		pPod, err := f.HostClient.CoreV1().Pods(translate.Default.PhysicalNamespace(ns)).Get(f.Context, translate.Default.PhysicalName(pod.Name, pod.Namespace), metav1.GetOptions{})
+		framework.ExpectNoError(err)
+
+		// make sure the tokens are refreshed
+		_, ok := podtranslate.TokenRefreshAfter(pPod)
+		framework.ExpectEqual(ok, true, "service account token refresh annotation should be present")
+
+		// make sure the mounted tokens are accepted by the virtual api server
+		secret, err := f.HostClient.CoreV1().Secrets(translate.Default.PhysicalNamespace(ns)).Get(f.Context, podtranslate.SecretNameFromPodName(pod.Name, ns), metav1.GetOptions{})
+		framework.ExpectNoError(err)
+		framework.ExpectNotEmpty(secret.Data)
+		for _, token := range secret.Data {
+			review, err := f.VclusterClient.AuthenticationV1().TokenReviews().Create(f.Context, &authenticationv1.TokenReview{
+				Spec: authenticationv1.TokenReviewSpec{Token: string(token)},
+			}, metav1.CreateOptions{})
+			framework.ExpectNoError(err)
+			framework.ExpectEqual(review.Status.Authenticated, true, "service account token should be valid in the virtual cluster")
+			framework.ExpectEqual(review.Status.User.Username, "system:serviceaccount:"+ns+":default")
+		}
+	})
 })
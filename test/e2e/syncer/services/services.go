@@ -17,6 +17,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	utilrand "k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/tools/cache"
@@ -325,4 +326,57 @@ var _ = ginkgo.Describe("Services are created as expected", func() {
 		framework.ExpectNoError(err, "failed to delete Service %v in namespace %v", testService.ObjectMeta.Name, ns)
 		f.Log.Infof("Service %s deleted", testSvcName)
 	})
+
+	ginkgo.It("Test LoadBalancer external-dns annotations & status", func() {
+		service := &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "myservice-external-dns",
+				Namespace: ns,
+				Annotations: map[string]string{
+					"external-dns.alpha.kubernetes.io/hostname": "web.example.com",
+					"external-dns.alpha.kubernetes.io/ttl":      "60",
+					"external-dns.alpha.kubernetes.io/access":   "private",
+				},
+			},
+			Spec: corev1.ServiceSpec{
+				Type:     "LoadBalancer",
+				Selector: map[string]string{"doesnt": "matter"},
+				Ports: []corev1.ServicePort{
+					{
+						Port: 80,
+					},
+				},
+			},
+		}
+
+		vService, err := f.VclusterClient.CoreV1().Services(ns).Create(f.Context, service, metav1.CreateOptions{})
+		framework.ExpectNoError(err)
+		err = f.WaitForService(vService.Name, vService.Namespace)
+		framework.ExpectNoError(err)
+
+		// only the allowed external-dns annotations are synced to the host service
+		pServiceName := translate.Default.PhysicalName(vService.Name, vService.Namespace)
+		pService, err := f.HostClient.CoreV1().Services(translate.Default.PhysicalNamespace(ns)).Get(f.Context, pServiceName, metav1.GetOptions{})
+		framework.ExpectNoError(err)
+		framework.ExpectEqual(pService.Annotations["external-dns.alpha.kubernetes.io/hostname"], "web.example.com")
+		framework.ExpectEqual(pService.Annotations["external-dns.alpha.kubernetes.io/ttl"], "60")
+		_, found := pService.Annotations["external-dns.alpha.kubernetes.io/access"]
+		framework.ExpectEqual(found, false)
+
+		// load balancer addresses of the host cluster are synced back
+		pService.Status.LoadBalancer = corev1.LoadBalancerStatus{
+			Ingress: []corev1.LoadBalancerIngress{{IP: "203.0.113.10", Hostname: "lb.example.com"}},
+		}
+		_, err = f.HostClient.CoreV1().Services(pService.Namespace).UpdateStatus(f.Context, pService, metav1.UpdateOptions{})
+		framework.ExpectNoError(err)
+		err = wait.PollUntilContextTimeout(f.Context, time.Second, time.Second*10, true, func(ctx context.Context) (bool, error) {
+			vService, err := f.VclusterClient.CoreV1().Services(ns).Get(ctx, vService.Name, metav1.GetOptions{})
+			if err != nil {
+				return false, err
+			}
+
+			return len(vService.Status.LoadBalancer.Ingress) == 1 && vService.Status.LoadBalancer.Ingress[0].Hostname == "lb.example.com", nil
+		})
+		framework.ExpectNoError(err)
+	})
 })
@@ -0,0 +1,113 @@
+package autoscaler
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	"github.com/loft-sh/vcluster/test/framework"
+	"github.com/onsi/ginkgo/v2"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+const (
+	poolLabel         = "e2e.vcluster.loft.sh/autoscaler-pool"
+	scaleUpAnnotation = "e2e.vcluster.loft.sh/scale-up"
+)
+
+// The kind host cluster has no cluster-autoscaler, so the test simulates the scale up by labeling a host node, which
+// makes new capacity available for the pending host pod the same way a new node of the node pool would.
+var _ = ginkgo.Describe("Cluster autoscaler integration", func() {
+	f := framework.DefaultFramework
+	ginkgo.It("Sync unschedulable pods to the host cluster and schedule them once capacity arrives", func() {
+		nsName := "default"
+		podName := "autoscaler"
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: podName,
+			},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{
+					{
+						Name:  "nginx",
+						Image: "nginxinc/nginx-unprivileged",
+					},
+				},
+				NodeSelector: map[string]string{
+					poolLabel: "true",
+				},
+			},
+		}
+
+		ginkgo.By("Create a pod that fits no node")
+		_, err := f.VclusterClient.CoreV1().Pods(nsName).Create(f.Context, pod, metav1.CreateOptions{})
+		framework.ExpectNoError(err)
+
+		ginkgo.By("The pending host pod is visible to the cluster-autoscaler")
+		pPodNamespace := translate.Default.PhysicalNamespace(nsName)
+		pPodName := translate.Default.PhysicalName(podName, nsName)
+		err = wait.PollUntilContextTimeout(f.Context, time.Second, time.Minute*2, false, func(ctx context.Context) (bool, error) {
+			pPod, err := f.HostClient.CoreV1().Pods(pPodNamespace).Get(ctx, pPodName, metav1.GetOptions{})
+			if kerrors.IsNotFound(err) {
+				return false, nil
+			} else if err != nil {
+				return false, err
+			}
+
+			return pPod.Spec.NodeName == "" && pPod.Annotations[scaleUpAnnotation] == "true" && pPod.Spec.NodeSelector[poolLabel] == "true", nil
+		})
+		framework.ExpectNoError(err)
+
+		ginkgo.By("Add capacity by labeling a host node")
+		hostNodes, err := f.HostClient.CoreV1().Nodes().List(f.Context, metav1.ListOptions{})
+		framework.ExpectNoError(err)
+		framework.ExpectEqual(len(hostNodes.Items) > 0, true)
+		nodeName := hostNodes.Items[0].Name
+		patchNodeLabel(f, nodeName, "true")
+		defer patchNodeLabel(f, nodeName, "")
+
+		ginkgo.By("The pod runs and its node appears in the virtual cluster")
+		err = wait.PollUntilContextTimeout(f.Context, time.Second, time.Minute*3, false, func(ctx context.Context) (bool, error) {
+			vPod, err := f.VclusterClient.CoreV1().Pods(nsName).Get(ctx, podName, metav1.GetOptions{})
+			if err != nil {
+				return false, err
+			}
+
+			return vPod.Status.Phase == corev1.PodRunning && vPod.Spec.NodeName == nodeName, nil
+		})
+		framework.ExpectNoError(err)
+
+		vNode, err := f.VclusterClient.CoreV1().Nodes().Get(f.Context, nodeName, metav1.GetOptions{})
+		framework.ExpectNoError(err)
+		framework.ExpectEqual(vNode.Labels[poolLabel], "true")
+
+		ginkgo.By("delete pod from vcluster")
+		err = f.VclusterClient.CoreV1().Pods(nsName).Delete(f.Context, podName, metav1.DeleteOptions{})
+		framework.ExpectNoError(err)
+	})
+})
+
+// patchNodeLabel sets the pool label on the host node or removes it if the value is empty
+func patchNodeLabel(f *framework.Framework, nodeName string, value string) {
+	var labelValue interface{}
+	if value != "" {
+		labelValue = value
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"labels": map[string]interface{}{
+				poolLabel: labelValue,
+			},
+		},
+	})
+	framework.ExpectNoError(err)
+
+	_, err = f.HostClient.CoreV1().Nodes().Patch(f.Context, nodeName, types.MergePatchType, patch, metav1.PatchOptions{})
+	framework.ExpectNoError(err)
+}
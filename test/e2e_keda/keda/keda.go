@@ -0,0 +1,144 @@
+package keda
+
+import (
+	"context"
+	"time"
+
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	"github.com/loft-sh/vcluster/test/framework"
+	"github.com/onsi/ginkgo/v2"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	nsName     = "keda-test"
+	sourceName = "keda-source"
+	targetName = "keda-target"
+)
+
+// KEDA runs inside the virtual cluster, so the ScaledObject, the scaled deployment and the HPA created by KEDA are
+// all virtual objects and only the resulting pods are synced to the host cluster.
+var _ = ginkgo.Describe("KEDA inside the virtual cluster", func() {
+	f := framework.DefaultFramework
+	ginkgo.It("Scale a deployment with a ScaledObject", func() {
+		ginkgo.By("Create the test namespace")
+		_, err := f.VclusterClient.CoreV1().Namespaces().Create(f.Context, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: nsName}}, metav1.CreateOptions{})
+		framework.ExpectNoError(err)
+		defer func() {
+			framework.ExpectNoError(f.DeleteTestNamespace(nsName, false))
+		}()
+
+		ginkgo.By("Create the source and the target deployment")
+		_, err = f.VclusterClient.AppsV1().Deployments(nsName).Create(f.Context, newDeployment(sourceName, 2), metav1.CreateOptions{})
+		framework.ExpectNoError(err)
+		_, err = f.VclusterClient.AppsV1().Deployments(nsName).Create(f.Context, newDeployment(targetName, 0), metav1.CreateOptions{})
+		framework.ExpectNoError(err)
+
+		ginkgo.By("Create a ScaledObject that scales the target to the number of source pods")
+		scaledObject := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "keda.sh/v1alpha1",
+			"kind":       "ScaledObject",
+			"metadata": map[string]interface{}{
+				"name":      targetName,
+				"namespace": nsName,
+			},
+			"spec": map[string]interface{}{
+				"scaleTargetRef": map[string]interface{}{
+					"name": targetName,
+				},
+				"pollingInterval": int64(5),
+				"cooldownPeriod":  int64(10),
+				"minReplicaCount": int64(0),
+				"maxReplicaCount": int64(3),
+				"triggers": []interface{}{
+					map[string]interface{}{
+						"type": "kubernetes-workload",
+						"metadata": map[string]interface{}{
+							"podSelector": "app=" + sourceName,
+							"value":       "1",
+						},
+					},
+				},
+			},
+		}}
+
+		// the KEDA crds and webhooks are installed by the init chart and might not be ready yet
+		err = wait.PollUntilContextTimeout(f.Context, time.Second*5, time.Minute*3, true, func(ctx context.Context) (bool, error) {
+			err := f.VclusterCRClient.Create(ctx, scaledObject)
+			if err != nil {
+				f.Log.Infof("Waiting for KEDA: %v", err)
+				return false, nil
+			}
+
+			return true, nil
+		})
+		framework.ExpectNoError(err)
+
+		ginkgo.By("The target is scaled up and its pods run in the host cluster")
+		err = wait.PollUntilContextTimeout(f.Context, time.Second*5, time.Minute*3, false, func(ctx context.Context) (bool, error) {
+			target, err := f.VclusterClient.AppsV1().Deployments(nsName).Get(ctx, targetName, metav1.GetOptions{})
+			if err != nil {
+				return false, err
+			}
+
+			return target.Status.ReadyReplicas == 2, nil
+		})
+		framework.ExpectNoError(err)
+
+		vPods, err := f.VclusterClient.CoreV1().Pods(nsName).List(f.Context, metav1.ListOptions{LabelSelector: labels.SelectorFromSet(map[string]string{"app": targetName}).String()})
+		framework.ExpectNoError(err)
+		for _, vPod := range vPods.Items {
+			pPodName := translate.Default.PhysicalName(vPod.Name, vPod.Namespace)
+			pPod, err := f.HostClient.CoreV1().Pods(translate.Default.PhysicalNamespace(vPod.Namespace)).Get(f.Context, pPodName, metav1.GetOptions{})
+			framework.ExpectNoError(err)
+			framework.ExpectEqual(pPod.Status.Phase, corev1.PodRunning)
+		}
+
+		ginkgo.By("The target is scaled down with the source")
+		_, err = f.VclusterClient.AppsV1().Deployments(nsName).Patch(f.Context, sourceName, types.MergePatchType, []byte(`{"spec":{"replicas":0}}`), metav1.PatchOptions{})
+		framework.ExpectNoError(err)
+		err = wait.PollUntilContextTimeout(f.Context, time.Second*5, time.Minute*5, false, func(ctx context.Context) (bool, error) {
+			target, err := f.VclusterClient.AppsV1().Deployments(nsName).Get(ctx, targetName, metav1.GetOptions{})
+			if err != nil {
+				return false, err
+			}
+
+			return ptr.Deref(target.Spec.Replicas, 1) == 0, nil
+		})
+		framework.ExpectNoError(err)
+	})
+})
+
+func newDeployment(name string, replicas int32) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: ptr.To(replicas),
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"app": name},
+			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{"app": name},
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name:  "nginx",
+							Image: "nginxinc/nginx-unprivileged",
+						},
+					},
+				},
+			},
+		},
+	}
+}